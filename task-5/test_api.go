@@ -3,14 +3,21 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"time"
 )
 
 const baseURL = "http://localhost:8080/api/v1"
 
+const healthURL = "http://localhost:8080/health"
+
+// exitServerNotReady is the exit code used when the API never answered its health check
+const exitServerNotReady = 2
+
 type TaskResponse struct {
 	Success bool        `json:"success"`
 	Message string      `json:"message"`
@@ -28,20 +35,30 @@ type Task struct {
 }
 
 func main() {
+	wait := flag.Duration("wait", 60*time.Second, "how long to wait for the server to become ready")
+	flag.Parse()
+
 	fmt.Println("Testing Task Management API with MongoDB...")
-	
+
+	// Wait for the server before running any test
+	fmt.Println("\nWaiting for the server to become ready...")
+	if err := waitForReady(healthURL, *wait); err != nil {
+		fmt.Printf("Error: server never became ready: %v\n", err)
+		os.Exit(exitServerNotReady)
+	}
+
 	// Test 1: Health check
 	fmt.Println("\n1. Testing health check...")
-	resp, err := http.Get("http://localhost:8080/health")
+	resp, err := http.Get(healthURL)
 	if err != nil {
 		fmt.Printf("Error: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ := io.ReadAll(resp.Body)
 	fmt.Printf("Health check response: %s\n", string(body))
-	
+
 	// Test 2: Create a task
 	fmt.Println("\n2. Creating a new task...")
 	taskData := map[string]interface{}{
@@ -50,7 +67,7 @@ func main() {
 		"due_date":    "2024-12-31",
 		"status":      "pending",
 	}
-	
+
 	jsonData, _ := json.Marshal(taskData)
 	resp, err = http.Post(baseURL+"/tasks", "application/json", bytes.NewBuffer(jsonData))
 	if err != nil {
@@ -58,14 +75,14 @@ func main() {
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Create task response: %s\n", string(body))
-	
+
 	// Parse the response to get the task ID
 	var createResponse TaskResponse
 	json.Unmarshal(body, &createResponse)
-	
+
 	var taskID string
 	if createResponse.Success {
 		if taskData, ok := createResponse.Data.(map[string]interface{}); ok {
@@ -75,12 +92,12 @@ func main() {
 			}
 		}
 	}
-	
+
 	if taskID == "" {
 		fmt.Println("Failed to get task ID from create response")
 		return
 	}
-	
+
 	// Test 3: Get all tasks
 	fmt.Println("\n3. Getting all tasks...")
 	resp, err = http.Get(baseURL + "/tasks")
@@ -89,10 +106,10 @@ func main() {
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Get all tasks response: %s\n", string(body))
-	
+
 	// Test 4: Get task by ID
 	fmt.Printf("\n4. Getting task by ID (%s)...\n", taskID)
 	resp, err = http.Get(baseURL + "/tasks/" + taskID)
@@ -101,10 +118,10 @@ func main() {
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Get task by ID response: %s\n", string(body))
-	
+
 	// Test 5: Update task
 	fmt.Printf("\n5. Updating task (%s)...\n", taskID)
 	updateData := map[string]interface{}{
@@ -113,25 +130,25 @@ func main() {
 		"due_date":    "2024-12-31",
 		"status":      "in_progress",
 	}
-	
+
 	jsonData, _ = json.Marshal(updateData)
 	client := &http.Client{}
 	req, _ := http.NewRequest("PUT", baseURL+"/tasks/"+taskID, bytes.NewBuffer(jsonData))
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	resp, err = client.Do(req)
 	if err != nil {
 		fmt.Printf("Error updating task: %v\n", err)
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Update task response: %s\n", string(body))
-	
+
 	// Wait a moment before deletion
 	time.Sleep(1 * time.Second)
-	
+
 	// Test 6: Delete task
 	fmt.Printf("\n6. Deleting task (%s)...\n", taskID)
 	req, _ = http.NewRequest("DELETE", baseURL+"/tasks/"+taskID, nil)
@@ -141,10 +158,10 @@ func main() {
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Delete task response: %s\n", string(body))
-	
+
 	// Test 7: Try to get deleted task (should return 404)
 	fmt.Printf("\n7. Trying to get deleted task (%s)...\n", taskID)
 	resp, err = http.Get(baseURL + "/tasks/" + taskID)
@@ -153,10 +170,10 @@ func main() {
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Get deleted task response: %s\n", string(body))
-	
+
 	// Test 8: Test invalid ObjectID format
 	fmt.Println("\n8. Testing invalid ObjectID format...")
 	resp, err = http.Get(baseURL + "/tasks/invalid-id")
@@ -165,9 +182,49 @@ func main() {
 		return
 	}
 	defer resp.Body.Close()
-	
+
 	body, _ = io.ReadAll(resp.Body)
 	fmt.Printf("Invalid ID response: %s\n", string(body))
-	
+
 	fmt.Println("\nMongoDB API testing completed!")
-}
\ No newline at end of file
+}
+
+// waitForReady polls the health endpoint with exponential backoff until it
+// answers 200 OK or the timeout elapses
+func waitForReady(url string, timeout time.Duration) error {
+	client := &http.Client{Timeout: 2 * time.Second}
+	deadline := time.Now().Add(timeout)
+	backoff := 250 * time.Millisecond
+	const maxBackoff = 5 * time.Second
+
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		resp, err := client.Get(url)
+		if err == nil {
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+			if resp.StatusCode == http.StatusOK {
+				fmt.Printf("Server is ready (attempt %d)\n", attempt)
+				return nil
+			}
+			err = fmt.Errorf("health check returned status %d", resp.StatusCode)
+		}
+		lastErr = err
+
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return fmt.Errorf("gave up after %s and %d attempts: %v", timeout, attempt, lastErr)
+		}
+		if backoff > remaining {
+			backoff = remaining
+		}
+
+		fmt.Printf("  attempt %d: not ready (%v), retrying in %s\n", attempt, lastErr, backoff.Round(time.Millisecond))
+		time.Sleep(backoff)
+
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+}
@@ -2,15 +2,48 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 )
 
 const baseURL = "http://localhost:8080"
 
+// Admin bootstrap modes
+const (
+	bootstrapRegister = "register" // register a fresh admin, relying on the first registered user becoming admin
+	bootstrapExisting = "existing" // log in with pre-provisioned admin credentials
+)
+
+// credentials is a username/password pair the tester signs in with
+type credentials struct {
+	Username string
+	Password string
+}
+
+// testConfig holds the identities used by a run. Unless supplied, usernames are
+// generated per run so reruns never collide with users left behind earlier.
+type testConfig struct {
+	bootstrap string
+	prefix    string
+	admin     credentials
+	regular   credentials
+	test      credentials
+}
+
 type User struct {
 	ID       string `json:"id,omitempty"`
 	Username string `json:"username"`
@@ -48,8 +81,30 @@ type APIResponse struct {
 }
 
 func main() {
+	load := flag.Bool("load", false, "run the load test instead of the functional test suite")
+	users := flag.Int("users", 20, "number of virtual users for the load test")
+	duration := flag.Duration("duration", 30*time.Second, "how long the load test hammers the API")
+	prefix := flag.String("user-prefix", getEnvOrDefault("TASK_API_USER_PREFIX", "apitest"), "prefix for generated usernames (env TASK_API_USER_PREFIX)")
+	adminUser := flag.String("admin-user", os.Getenv("TASK_API_ADMIN_USERNAME"), "username of a pre-provisioned admin (env TASK_API_ADMIN_USERNAME)")
+	adminPassword := flag.String("admin-password", os.Getenv("TASK_API_ADMIN_PASSWORD"), "password of a pre-provisioned admin (env TASK_API_ADMIN_PASSWORD)")
+	bootstrap := flag.String("admin-bootstrap", os.Getenv("TASK_API_ADMIN_BOOTSTRAP"),
+		"how to obtain an admin: register (first registered user becomes admin) or existing (use -admin-user); defaults to existing when admin credentials are supplied (env TASK_API_ADMIN_BOOTSTRAP)")
+	flag.Parse()
+
+	cfg, err := newTestConfig(*prefix, *bootstrap, credentials{Username: *adminUser, Password: *adminPassword})
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		os.Exit(2)
+	}
+
+	if *load {
+		runLoadTest(cfg, *users, *duration)
+		return
+	}
+
 	fmt.Println("🚀 Starting comprehensive Task Management API testing...")
 	fmt.Println(strings.Repeat("=", 60))
+	printIdentities(cfg)
 
 	// Test 1: Health Check
 	fmt.Println("\n🏥 Test 1: Health Check")
@@ -57,11 +112,11 @@ func main() {
 
 	// Test 2: User Registration
 	fmt.Println("\n📝 Test 2: User Registration")
-	testUserRegistration()
+	testUserRegistration(cfg)
 
 	// Test 3: User Login
 	fmt.Println("\n🔐 Test 3: User Login")
-	adminToken := testUserLogin()
+	adminToken := testUserLogin(cfg)
 
 	// Test 4: Protected Route Access
 	fmt.Println("\n🛡️ Test 4: Protected Route Access")
@@ -73,11 +128,11 @@ func main() {
 
 	// Test 6: User Role Testing
 	fmt.Println("\n👥 Test 6: User Role Testing")
-	testUserRoles(adminToken)
+	testUserRoles(cfg, adminToken)
 
 	// Test 7: Authorization Tests
 	fmt.Println("\n🔒 Test 7: Authorization Tests")
-	testAuthorization()
+	testAuthorization(cfg)
 
 	// Test 8: Invalid Token Tests
 	fmt.Println("\n❌ Test 8: Invalid Token Tests")
@@ -85,11 +140,77 @@ func main() {
 
 	fmt.Println("\n✅ All tests completed!")
 	fmt.Println(strings.Repeat("=", 60))
+	printIdentities(cfg)
+}
+
+// newTestConfig resolves the admin bootstrap mode and generates the per-run users
+func newTestConfig(prefix, bootstrap string, admin credentials) (*testConfig, error) {
+	if bootstrap == "" {
+		bootstrap = bootstrapRegister
+		if admin.Username != "" {
+			bootstrap = bootstrapExisting
+		}
+	}
+
+	cfg := &testConfig{bootstrap: bootstrap, prefix: prefix}
+	switch bootstrap {
+	case bootstrapExisting:
+		if admin.Username == "" || admin.Password == "" {
+			return nil, errors.New("-admin-bootstrap=existing requires -admin-user and -admin-password (or TASK_API_ADMIN_USERNAME and TASK_API_ADMIN_PASSWORD)")
+		}
+		cfg.admin = admin
+	case bootstrapRegister:
+		if admin.Username != "" {
+			return nil, errors.New("-admin-user cannot be combined with -admin-bootstrap=register")
+		}
+		cfg.admin = generateCredentials(prefix, "admin")
+	default:
+		return nil, fmt.Errorf("unknown admin bootstrap mode %q (expected %s or %s)", bootstrap, bootstrapRegister, bootstrapExisting)
+	}
+
+	cfg.regular = generateCredentials(prefix, "regular")
+	cfg.test = generateCredentials(prefix, "test")
+	return cfg, nil
+}
+
+// generateCredentials returns a username of the form <prefix>_<role>_<timestamp>_<random>
+// together with a random password
+func generateCredentials(prefix, role string) credentials {
+	return credentials{
+		Username: fmt.Sprintf("%s_%s_%s_%s", prefix, role, time.Now().Format("20060102T150405"), randomHex(3)),
+		Password: "pw_" + randomHex(8),
+	}
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		// Fall back to the clock; uniqueness matters here, not secrecy
+		return fmt.Sprintf("%x", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(b)
+}
+
+// printIdentities lists the accounts a run used so failures can be traced server-side.
+// Passwords are not printed.
+func printIdentities(cfg *testConfig) {
+	fmt.Println("\n🪪 Identities used in this run")
+	fmt.Printf("  Admin bootstrap: %s\n", cfg.bootstrap)
+	fmt.Printf("  Admin user:      %s\n", cfg.admin.Username)
+	fmt.Printf("  Regular user:    %s\n", cfg.regular.Username)
+	fmt.Printf("  Test user:       %s\n", cfg.test.Username)
+}
+
+func getEnvOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
 }
 
 func testHealthCheck() {
 	fmt.Println("  Testing health endpoint...")
-	
+
 	resp, err := http.Get(baseURL + "/health")
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -99,7 +220,7 @@ func testHealthCheck() {
 
 	body, _ := io.ReadAll(resp.Body)
 	fmt.Printf("    Status: %d, Response: %s\n", resp.StatusCode, string(body))
-	
+
 	if resp.StatusCode == 200 {
 		fmt.Printf("    ✅ Health check successful\n")
 	} else {
@@ -107,19 +228,22 @@ func testHealthCheck() {
 	}
 }
 
-func testUserRegistration() {
-	users := []User{
-		{Username: "admin_user", Password: "admin123"},
-		{Username: "regular_user", Password: "user123"},
-		{Username: "test_user", Password: "test123"},
+func testUserRegistration(cfg *testConfig) {
+	var users []User
+	if cfg.bootstrap == bootstrapRegister {
+		users = append(users, User{Username: cfg.admin.Username, Password: cfg.admin.Password})
 	}
+	users = append(users,
+		User{Username: cfg.regular.Username, Password: cfg.regular.Password},
+		User{Username: cfg.test.Username, Password: cfg.test.Password},
+	)
 
 	for i, user := range users {
 		fmt.Printf("  Registering user %d: %s\n", i+1, user.Username)
-		
+
 		jsonData, _ := json.Marshal(user)
 		resp, err := http.Post(baseURL+"/api/v1/register", "application/json", bytes.NewBuffer(jsonData))
-		
+
 		if err != nil {
 			fmt.Printf("    ❌ Error: %v\n", err)
 			continue
@@ -128,7 +252,7 @@ func testUserRegistration() {
 
 		body, _ := io.ReadAll(resp.Body)
 		fmt.Printf("    Status: %d, Response: %s\n", resp.StatusCode, string(body))
-		
+
 		if resp.StatusCode == 201 {
 			fmt.Printf("    ✅ User %s registered successfully\n", user.Username)
 		} else if resp.StatusCode == 409 {
@@ -139,17 +263,17 @@ func testUserRegistration() {
 	}
 }
 
-func testUserLogin() string {
-	fmt.Println("  Testing login with admin user...")
-	
+func testUserLogin(cfg *testConfig) string {
+	fmt.Printf("  Testing login with admin user %s...\n", cfg.admin.Username)
+
 	loginReq := LoginRequest{
-		Username: "admin_user",
-		Password: "admin123",
+		Username: cfg.admin.Username,
+		Password: cfg.admin.Password,
 	}
 
 	jsonData, _ := json.Marshal(loginReq)
 	resp, err := http.Post(baseURL+"/api/v1/login", "application/json", bytes.NewBuffer(jsonData))
-	
+
 	if err != nil {
 		fmt.Printf("  ❌ Login error: %v\n", err)
 		return ""
@@ -164,6 +288,9 @@ func testUserLogin() string {
 		json.Unmarshal(body, &loginResp)
 		fmt.Printf("  ✅ Login successful, token received\n")
 		fmt.Printf("  User role: %s\n", loginResp.User.Role)
+		if loginResp.User.Role != "admin" {
+			fmt.Printf("  ⚠️ %s is not an admin; against a seeded database use -admin-user/-admin-password instead of registering one\n", cfg.admin.Username)
+		}
 		return loginResp.Token
 	}
 
@@ -179,11 +306,11 @@ func testProtectedRoutes(token string) {
 
 	// Test accessing protected user profile
 	fmt.Println("  Testing protected user profile access...")
-	
+
 	client := &http.Client{}
 	req, _ := http.NewRequest("GET", baseURL+"/api/v1/users/profile", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
-	
+
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -193,7 +320,7 @@ func testProtectedRoutes(token string) {
 
 	body, _ := io.ReadAll(resp.Body)
 	fmt.Printf("    Status: %d, Response: %s\n", resp.StatusCode, string(body))
-	
+
 	if resp.StatusCode == 200 {
 		fmt.Printf("    ✅ Protected route access successful\n")
 	} else {
@@ -217,12 +344,12 @@ func testTaskOperations(token string) {
 		Status:      "pending",
 		DueDate:     "2025-08-01",
 	}
-	
+
 	jsonData, _ := json.Marshal(task)
 	req, _ := http.NewRequest("POST", baseURL+"/api/v1/tasks", bytes.NewBuffer(jsonData))
 	req.Header.Set("Authorization", "Bearer "+token)
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Create task error: %v\n", err)
@@ -246,7 +373,7 @@ func testTaskOperations(token string) {
 	fmt.Println("  Fetching all tasks...")
 	req, _ = http.NewRequest("GET", baseURL+"/api/v1/tasks", nil)
 	req.Header.Set("Authorization", "Bearer "+token)
-	
+
 	resp, err = client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Get tasks error: %v\n", err)
@@ -266,12 +393,12 @@ func testTaskOperations(token string) {
 		fmt.Println("  Updating the task (Admin operation)...")
 		createdTask.Title = "Updated Test Task"
 		createdTask.Status = "in_progress"
-		
+
 		jsonData, _ = json.Marshal(createdTask)
 		req, _ = http.NewRequest("PUT", baseURL+"/api/v1/tasks/"+createdTask.ID, bytes.NewBuffer(jsonData))
 		req.Header.Set("Authorization", "Bearer "+token)
 		req.Header.Set("Content-Type", "application/json")
-		
+
 		resp, err = client.Do(req)
 		if err != nil {
 			fmt.Printf("    ❌ Update task error: %v\n", err)
@@ -290,7 +417,7 @@ func testTaskOperations(token string) {
 		fmt.Println("  Getting task by ID...")
 		req, _ = http.NewRequest("GET", baseURL+"/api/v1/tasks/"+createdTask.ID, nil)
 		req.Header.Set("Authorization", "Bearer "+token)
-		
+
 		resp, err = client.Do(req)
 		if err != nil {
 			fmt.Printf("    ❌ Get task by ID error: %v\n", err)
@@ -309,7 +436,7 @@ func testTaskOperations(token string) {
 		fmt.Println("  Deleting the task (Admin operation)...")
 		req, _ = http.NewRequest("DELETE", baseURL+"/api/v1/tasks/"+createdTask.ID, nil)
 		req.Header.Set("Authorization", "Bearer "+token)
-		
+
 		resp, err = client.Do(req)
 		if err != nil {
 			fmt.Printf("    ❌ Delete task error: %v\n", err)
@@ -326,7 +453,7 @@ func testTaskOperations(token string) {
 	}
 }
 
-func testUserRoles(adminToken string) {
+func testUserRoles(cfg *testConfig, adminToken string) {
 	if adminToken == "" {
 		fmt.Println("  ⚠️ No admin token available, skipping user role tests")
 		return
@@ -338,7 +465,7 @@ func testUserRoles(adminToken string) {
 	fmt.Println("  Testing get all users (Admin only)...")
 	req, _ := http.NewRequest("GET", baseURL+"/api/v1/users", nil)
 	req.Header.Set("Authorization", "Bearer "+adminToken)
-	
+
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -358,14 +485,14 @@ func testUserRoles(adminToken string) {
 	// Test promoting a user (Admin only)
 	fmt.Println("  Testing user promotion (Admin only)...")
 	promoteReq := map[string]string{
-		"username": "regular_user",
+		"username": cfg.regular.Username,
 	}
-	
+
 	jsonData, _ := json.Marshal(promoteReq)
 	req, _ = http.NewRequest("POST", baseURL+"/api/v1/users/promote", bytes.NewBuffer(jsonData))
 	req.Header.Set("Authorization", "Bearer "+adminToken)
 	req.Header.Set("Content-Type", "application/json")
-	
+
 	resp, err = client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -383,10 +510,10 @@ func testUserRoles(adminToken string) {
 	}
 }
 
-func testAuthorization() {
+func testAuthorization(cfg *testConfig) {
 	// Test accessing protected routes without token
 	fmt.Println("  Testing access without authentication token...")
-	
+
 	resp, err := http.Get(baseURL + "/api/v1/tasks")
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -412,7 +539,7 @@ func testAuthorization() {
 
 	jsonData, _ := json.Marshal(loginReq)
 	resp, err = http.Post(baseURL+"/api/v1/login", "application/json", bytes.NewBuffer(jsonData))
-	
+
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
 		return
@@ -430,16 +557,16 @@ func testAuthorization() {
 
 	// Test regular user trying to access admin endpoints
 	fmt.Println("  Testing regular user access to admin endpoints...")
-	
+
 	// First login as regular user
 	regularLoginReq := LoginRequest{
-		Username: "test_user",
-		Password: "test123",
+		Username: cfg.test.Username,
+		Password: cfg.test.Password,
 	}
 
 	jsonData, _ = json.Marshal(regularLoginReq)
 	resp, err = http.Post(baseURL+"/api/v1/login", "application/json", bytes.NewBuffer(jsonData))
-	
+
 	if err != nil {
 		fmt.Printf("    ❌ Error logging in regular user: %v\n", err)
 		return
@@ -447,11 +574,11 @@ func testAuthorization() {
 	defer resp.Body.Close()
 
 	body, _ = io.ReadAll(resp.Body)
-	
+
 	if resp.StatusCode == 200 {
 		var loginResp LoginResponse
 		json.Unmarshal(body, &loginResp)
-		
+
 		// Try to create a task (admin only operation)
 		client := &http.Client{}
 		task := Task{
@@ -459,12 +586,12 @@ func testAuthorization() {
 			Description: "This should fail",
 			Status:      "pending",
 		}
-		
+
 		jsonData, _ = json.Marshal(task)
 		req, _ := http.NewRequest("POST", baseURL+"/api/v1/tasks", bytes.NewBuffer(jsonData))
 		req.Header.Set("Authorization", "Bearer "+loginResp.Token)
 		req.Header.Set("Content-Type", "application/json")
-		
+
 		resp, err = client.Do(req)
 		if err != nil {
 			fmt.Printf("    ❌ Error: %v\n", err)
@@ -485,12 +612,12 @@ func testAuthorization() {
 
 func testInvalidTokens() {
 	client := &http.Client{}
-	
+
 	// Test with invalid token
 	fmt.Println("  Testing with invalid token...")
 	req, _ := http.NewRequest("GET", baseURL+"/api/v1/tasks", nil)
 	req.Header.Set("Authorization", "Bearer invalid_token_here")
-	
+
 	resp, err := client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -511,7 +638,7 @@ func testInvalidTokens() {
 	fmt.Println("  Testing with malformed Authorization header...")
 	req, _ = http.NewRequest("GET", baseURL+"/api/v1/tasks", nil)
 	req.Header.Set("Authorization", "InvalidFormat")
-	
+
 	resp, err = client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -531,7 +658,7 @@ func testInvalidTokens() {
 	// Test with no Authorization header
 	fmt.Println("  Testing with no Authorization header...")
 	req, _ = http.NewRequest("GET", baseURL+"/api/v1/users/profile", nil)
-	
+
 	resp, err = client.Do(req)
 	if err != nil {
 		fmt.Printf("    ❌ Error: %v\n", err)
@@ -547,4 +674,322 @@ func testInvalidTokens() {
 	} else {
 		fmt.Printf("    ❌ Should have rejected missing authorization header\n")
 	}
-}
\ No newline at end of file
+}
+
+// Load test
+
+// loadStats collects latencies and errors for a single operation
+type loadStats struct {
+	mu        sync.Mutex
+	latencies []time.Duration
+	errors    int
+}
+
+func (ls *loadStats) record(latency time.Duration, failed bool) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	ls.latencies = append(ls.latencies, latency)
+	if failed {
+		ls.errors++
+	}
+}
+
+// percentile returns the p-th percentile of the sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted))*p/100+0.5) - 1
+	if index < 0 {
+		index = 0
+	}
+	if index >= len(sorted) {
+		index = len(sorted) - 1
+	}
+	return sorted[index]
+}
+
+// virtualUser is a registered and logged-in load test participant
+type virtualUser struct {
+	username string
+	token    string
+}
+
+func newLoadClient(users int) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConns = users * 2
+	transport.MaxIdleConnsPerHost = users * 2
+	transport.MaxConnsPerHost = users * 2
+	transport.IdleConnTimeout = 90 * time.Second
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   10 * time.Second,
+	}
+}
+
+func runLoadTest(cfg *testConfig, users int, duration time.Duration) {
+	fmt.Println("🏋️ Starting Task Management API load test...")
+	fmt.Println(strings.Repeat("=", 60))
+	fmt.Printf("  Virtual users: %d, duration: %s\n", users, duration)
+
+	if users <= 0 {
+		fmt.Println("  ❌ -users must be greater than zero")
+		os.Exit(1)
+	}
+
+	// Ctrl-C stops the workers early but still prints the summary
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client := newLoadClient(users)
+
+	if cfg.bootstrap == bootstrapRegister {
+		if status, err := registerUser(ctx, client, cfg.admin); err != nil || status != 201 {
+			fmt.Printf("  ⚠️ Registering admin %s failed (status %d, error %v)\n", cfg.admin.Username, status, err)
+		}
+	}
+
+	adminToken := loginForToken(ctx, client, cfg.admin.Username, cfg.admin.Password)
+	if adminToken == "" {
+		fmt.Println("  ⚠️ Admin login failed, status updates will be skipped")
+	}
+
+	fmt.Println("\n📝 Registering and logging in virtual users...")
+	vusers := setupVirtualUsers(ctx, client, cfg.prefix, users)
+	if len(vusers) == 0 {
+		fmt.Println("  ❌ No virtual users could log in, aborting load test")
+		os.Exit(1)
+	}
+	fmt.Printf("  ✅ %d/%d virtual users ready\n", len(vusers), users)
+
+	taskID := ""
+	if adminToken != "" {
+		taskID = createLoadTask(ctx, client, adminToken)
+	}
+
+	stats := map[string]*loadStats{
+		"GET /api/v1/tasks":     {},
+		"PUT /api/v1/tasks/:id": {},
+	}
+
+	fmt.Printf("\n🔥 Hammering the API for %s (Ctrl-C to stop early)...\n", duration)
+	runCtx, cancel := context.WithTimeout(ctx, duration)
+	defer cancel()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	for i, vu := range vusers {
+		wg.Add(1)
+		go func(i int, vu virtualUser) {
+			defer wg.Done()
+			statuses := []string{"pending", "in_progress", "completed"}
+			for n := 0; runCtx.Err() == nil; n++ {
+				doLoadRequest(runCtx, client, stats["GET /api/v1/tasks"], "GET", "/api/v1/tasks", vu.token, nil)
+
+				// The update path is admin-only; virtual users stay regular users and
+				// send their updates with the one admin's token
+				if taskID != "" && adminToken != "" {
+					update := Task{
+						Title:       "Load Test Task",
+						Description: fmt.Sprintf("Updated on behalf of %s", vu.username),
+						Status:      statuses[(i+n)%len(statuses)],
+					}
+					doLoadRequest(runCtx, client, stats["PUT /api/v1/tasks/:id"], "PUT", "/api/v1/tasks/"+taskID, adminToken, update)
+				}
+			}
+		}(i, vu)
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	if ctx.Err() != nil {
+		fmt.Println("  ⚠️ Interrupted, printing partial results")
+	}
+
+	if taskID != "" {
+		deleteLoadTask(client, adminToken, taskID)
+	}
+
+	printLoadSummary(stats, elapsed)
+	printIdentities(cfg)
+	if len(vusers) > 0 {
+		fmt.Printf("  Virtual users:   %s ... %s\n", vusers[0].username, vusers[len(vusers)-1].username)
+	}
+}
+
+// registerUser registers creds and returns the response status code
+func registerUser(ctx context.Context, client *http.Client, creds credentials) (int, error) {
+	jsonData, _ := json.Marshal(User{Username: creds.Username, Password: creds.Password})
+	req, _ := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/v1/register", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	return resp.StatusCode, nil
+}
+
+func loginForToken(ctx context.Context, client *http.Client, username, password string) string {
+	jsonData, _ := json.Marshal(LoginRequest{Username: username, Password: password})
+	req, _ := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/v1/login", bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		io.Copy(io.Discard, resp.Body)
+		return ""
+	}
+
+	var loginResp LoginResponse
+	if err := json.NewDecoder(resp.Body).Decode(&loginResp); err != nil {
+		return ""
+	}
+	return loginResp.Token
+}
+
+// setupVirtualUsers registers and logs in the load test users. They are left as regular
+// users: the API cannot delete or demote users, so promoting them would leave admin
+// accounts behind on the target server.
+func setupVirtualUsers(ctx context.Context, client *http.Client, prefix string, count int) []virtualUser {
+	vusers := make([]virtualUser, 0, count)
+
+	for i := 0; i < count && ctx.Err() == nil; i++ {
+		creds := generateCredentials(prefix, fmt.Sprintf("load%d", i))
+		username := creds.Username
+
+		status, err := registerUser(ctx, client, creds)
+		if err != nil {
+			fmt.Printf("    ❌ Registering %s: %v\n", username, err)
+			continue
+		}
+		if status != 201 {
+			fmt.Printf("    ❌ Registering %s failed with status %d\n", username, status)
+			continue
+		}
+
+		token := loginForToken(ctx, client, username, creds.Password)
+		if token == "" {
+			fmt.Printf("    ❌ Login failed for %s\n", username)
+			continue
+		}
+		vusers = append(vusers, virtualUser{username: username, token: token})
+	}
+
+	return vusers
+}
+
+func createLoadTask(ctx context.Context, client *http.Client, token string) string {
+	task := Task{
+		Title:       "Load Test Task",
+		Description: "Target of concurrent status updates",
+		Status:      "pending",
+	}
+
+	jsonData, _ := json.Marshal(task)
+	req, _ := http.NewRequestWithContext(ctx, "POST", baseURL+"/api/v1/tasks", bytes.NewBuffer(jsonData))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		fmt.Printf("  ❌ Create load test task error: %v\n", err)
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 {
+		fmt.Printf("  ❌ Create load test task failed with status %d\n", resp.StatusCode)
+		return ""
+	}
+
+	var apiResp struct {
+		Data Task `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&apiResp); err != nil {
+		return ""
+	}
+	return apiResp.Data.ID
+}
+
+func deleteLoadTask(client *http.Client, token, taskID string) {
+	req, _ := http.NewRequest("DELETE", baseURL+"/api/v1/tasks/"+taskID, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if resp, err := client.Do(req); err == nil {
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+	}
+}
+
+// doLoadRequest issues one request and records its latency. Requests cut short by the
+// end of the run are not counted.
+func doLoadRequest(ctx context.Context, client *http.Client, stats *loadStats, method, path, token string, body interface{}) {
+	var reader io.Reader
+	if body != nil {
+		jsonData, _ := json.Marshal(body)
+		reader = bytes.NewReader(jsonData)
+	}
+
+	req, _ := http.NewRequestWithContext(ctx, method, baseURL+path, reader)
+	req.Header.Set("Authorization", "Bearer "+token)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return
+		}
+		stats.record(time.Since(start), true)
+		return
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	stats.record(time.Since(start), resp.StatusCode >= 400)
+}
+
+func printLoadSummary(stats map[string]*loadStats, elapsed time.Duration) {
+	operations := make([]string, 0, len(stats))
+	for op := range stats {
+		operations = append(operations, op)
+	}
+	sort.Strings(operations)
+
+	fmt.Println("\n📊 Load test summary")
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("%-24s %10s %8s %12s %12s %12s %12s\n", "Operation", "Requests", "Errors", "p50", "p95", "p99", "Req/s")
+	fmt.Println(strings.Repeat("-", 100))
+
+	for _, op := range operations {
+		ls := stats[op]
+		ls.mu.Lock()
+		sorted := append([]time.Duration(nil), ls.latencies...)
+		errorCount := ls.errors
+		ls.mu.Unlock()
+
+		sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+		throughput := float64(len(sorted)) / elapsed.Seconds()
+
+		fmt.Printf("%-24s %10d %8d %12s %12s %12s %12.1f\n",
+			op, len(sorted), errorCount,
+			percentile(sorted, 50).Round(time.Microsecond),
+			percentile(sorted, 95).Round(time.Microsecond),
+			percentile(sorted, 99).Round(time.Microsecond),
+			throughput)
+	}
+
+	fmt.Println(strings.Repeat("=", 100))
+	fmt.Printf("Elapsed: %s\n", elapsed.Round(time.Millisecond))
+}
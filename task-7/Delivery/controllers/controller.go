@@ -1,6 +1,7 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -38,19 +39,9 @@ func (ctrl *Controller) Register(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userUsecase.RegisterUser(userReq)
+	user, err := ctrl.userUsecase.RegisterUser(c.Request.Context(), userReq)
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		if err.Error() == "username already exists" {
-			statusCode = http.StatusConflict
-		}
-		
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to create user",
-			Error:   err.Error(),
-		}
-		c.JSON(statusCode, errorResponse)
+		respondError(c, "Failed to create user", err)
 		return
 	}
 
@@ -77,14 +68,9 @@ func (ctrl *Controller) Login(c *gin.Context) {
 		return
 	}
 
-	user, token, err := ctrl.userUsecase.LoginUser(loginReq)
+	user, token, err := ctrl.userUsecase.LoginUser(c.Request.Context(), loginReq)
 	if err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Authentication failed",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusUnauthorized, errorResponse)
+		respondError(c, "Authentication failed", err)
 		return
 	}
 
@@ -112,19 +98,9 @@ func (ctrl *Controller) PromoteUser(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userUsecase.PromoteUserToAdmin(promoteReq.Username)
+	user, err := ctrl.userUsecase.PromoteUserToAdmin(c.Request.Context(), promoteReq.Username)
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
-		}
-		
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to promote user",
-			Error:   err.Error(),
-		}
-		c.JSON(statusCode, errorResponse)
+		respondError(c, "Failed to promote user", err)
 		return
 	}
 
@@ -139,14 +115,9 @@ func (ctrl *Controller) PromoteUser(c *gin.Context) {
 
 // GetAllUsers handles GET /users (admin only)
 func (ctrl *Controller) GetAllUsers(c *gin.Context) {
-	users, err := ctrl.userUsecase.GetAllUsers()
+	users, err := ctrl.userUsecase.GetAllUsers(c.Request.Context())
 	if err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve users",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusInternalServerError, errorResponse)
+		respondError(c, "Failed to retrieve users", err)
 		return
 	}
 	
@@ -172,14 +143,9 @@ func (ctrl *Controller) GetProfile(c *gin.Context) {
 		return
 	}
 
-	user, err := ctrl.userUsecase.GetUserProfile(userID.(string))
+	user, err := ctrl.userUsecase.GetUserProfile(c.Request.Context(), userID.(string))
 	if err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve user profile",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusNotFound, errorResponse)
+		respondError(c, "Failed to retrieve user profile", err)
 		return
 	}
 
@@ -196,14 +162,9 @@ func (ctrl *Controller) GetProfile(c *gin.Context) {
 
 // GetAllTasks handles GET /tasks
 func (ctrl *Controller) GetAllTasks(c *gin.Context) {
-	tasks, err := ctrl.taskUsecase.GetAllTasks()
+	tasks, err := ctrl.taskUsecase.GetAllTasks(c.Request.Context())
 	if err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve tasks",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusInternalServerError, errorResponse)
+		respondError(c, "Failed to retrieve tasks", err)
 		return
 	}
 	
@@ -220,19 +181,9 @@ func (ctrl *Controller) GetAllTasks(c *gin.Context) {
 func (ctrl *Controller) GetTaskByID(c *gin.Context) {
 	id := c.Param("id")
 
-	task, err := ctrl.taskUsecase.GetTaskByID(id)
+	task, err := ctrl.taskUsecase.GetTaskByID(c.Request.Context(), id)
 	if err != nil {
-		statusCode := http.StatusNotFound
-		if err.Error() == "invalid task ID format" {
-			statusCode = http.StatusBadRequest
-		}
-		
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Task not found",
-			Error:   err.Error(),
-		}
-		c.JSON(statusCode, errorResponse)
+		respondError(c, "Task not found", err)
 		return
 	}
 
@@ -259,14 +210,9 @@ func (ctrl *Controller) CreateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := ctrl.taskUsecase.CreateTask(taskReq)
+	task, err := ctrl.taskUsecase.CreateTask(c.Request.Context(), taskReq)
 	if err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to create task",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+		respondError(c, "Failed to create task", err)
 		return
 	}
 
@@ -294,22 +240,9 @@ func (ctrl *Controller) UpdateTask(c *gin.Context) {
 		return
 	}
 
-	task, err := ctrl.taskUsecase.UpdateTask(id, taskReq)
+	task, err := ctrl.taskUsecase.UpdateTask(c.Request.Context(), id, taskReq)
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		if err.Error() == "task not found" {
-			statusCode = http.StatusNotFound
-		}
-		if err.Error() == "invalid task ID format" {
-			statusCode = http.StatusBadRequest
-		}
-		
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to update task",
-			Error:   err.Error(),
-		}
-		c.JSON(statusCode, errorResponse)
+		respondError(c, "Failed to update task", err)
 		return
 	}
 
@@ -326,19 +259,9 @@ func (ctrl *Controller) UpdateTask(c *gin.Context) {
 func (ctrl *Controller) DeleteTask(c *gin.Context) {
 	id := c.Param("id")
 
-	err := ctrl.taskUsecase.DeleteTask(id)
+	err := ctrl.taskUsecase.DeleteTask(c.Request.Context(), id)
 	if err != nil {
-		statusCode := http.StatusNotFound
-		if err.Error() == "invalid task ID format" {
-			statusCode = http.StatusBadRequest
-		}
-		
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to delete task",
-			Error:   err.Error(),
-		}
-		c.JSON(statusCode, errorResponse)
+		respondError(c, "Failed to delete task", err)
 		return
 	}
 
@@ -348,4 +271,29 @@ func (ctrl *Controller) DeleteTask(c *gin.Context) {
 	}
 	
 	c.JSON(http.StatusOK, response)
+}
+
+// respondError writes the error response for an error returned by a use case, choosing the status
+// code from the domain error it wraps. Errors the domain does not know about are server errors.
+func respondError(c *gin.Context, message string, err error) {
+	statusCode := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, Domain.ErrTaskNotFound), errors.Is(err, Domain.ErrUserNotFound):
+		statusCode = http.StatusNotFound
+	case errors.Is(err, Domain.ErrUsernameTaken):
+		statusCode = http.StatusConflict
+	case errors.Is(err, Domain.ErrInvalidCredentials):
+		statusCode = http.StatusUnauthorized
+	case errors.Is(err, Domain.ErrInvalidTaskID), errors.Is(err, Domain.ErrInvalidUserID),
+		errors.Is(err, Domain.ErrInvalidStatus), errors.Is(err, Domain.ErrInvalidDueDate),
+		errors.Is(err, Domain.ErrAlreadyAdmin):
+		statusCode = http.StatusBadRequest
+	}
+
+	errorResponse := Domain.ErrorResponse{
+		Success: false,
+		Message: message,
+		Error:   err.Error(),
+	}
+	c.JSON(statusCode, errorResponse)
 }
\ No newline at end of file
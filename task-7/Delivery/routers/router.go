@@ -3,7 +3,7 @@ package routers
 import (
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
-	
+
 	"task_manager/Delivery/controllers"
 	"task_manager/Infrastructure"
 	"task_manager/Repositories"
@@ -19,6 +19,16 @@ type DatabaseConfig struct {
 
 // SetupRouter initializes and configures the Gin router with Clean Architecture
 func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig) *gin.Engine {
+	// Initialize Repository layer
+	taskRepo := Repositories.NewTaskRepository(client, dbConfig.Database, dbConfig.Collection)
+	userRepo := Repositories.NewUserRepository(client, dbConfig.Database)
+
+	return SetupRouterWithRepositories(taskRepo, userRepo)
+}
+
+// SetupRouterWithRepositories configures the Gin router on top of the given repositories,
+// allowing alternative storage backends (e.g. in-memory ones) to be plugged in
+func SetupRouterWithRepositories(taskRepo Repositories.TaskRepositoryInterface, userRepo Repositories.UserRepositoryInterface) *gin.Engine {
 	router := gin.Default()
 
 	// Initialize Infrastructure layer
@@ -26,10 +36,6 @@ func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig) *gin.Engine {
 	jwtService := Infrastructure.NewJWTService()
 	authMiddleware := Infrastructure.NewAuthMiddleware(jwtService)
 
-	// Initialize Repository layer
-	taskRepo := Repositories.NewTaskRepository(client, dbConfig.Database, dbConfig.Collection)
-	userRepo := Repositories.NewUserRepository(client, dbConfig.Database)
-
 	// Initialize Usecase layer
 	taskUsecase := Usecases.NewTaskUsecase(taskRepo)
 	userUsecase := Usecases.NewUserUsecase(userRepo, passwordService, jwtService)
@@ -48,9 +54,9 @@ func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig) *gin.Engine {
 		userRoutes := v1.Group("/users")
 		userRoutes.Use(authMiddleware.AuthenticateToken())
 		{
-			userRoutes.GET("/profile", controller.GetProfile)                                          // GET /api/v1/users/profile
-			userRoutes.GET("", authMiddleware.RequireAdmin(), controller.GetAllUsers)                  // GET /api/v1/users (admin only)
-			userRoutes.POST("/promote", authMiddleware.RequireAdmin(), controller.PromoteUser)         // POST /api/v1/users/promote (admin only)
+			userRoutes.GET("/profile", controller.GetProfile)                                  // GET /api/v1/users/profile
+			userRoutes.GET("", authMiddleware.RequireAdmin(), controller.GetAllUsers)          // GET /api/v1/users (admin only)
+			userRoutes.POST("/promote", authMiddleware.RequireAdmin(), controller.PromoteUser) // POST /api/v1/users/promote (admin only)
 		}
 
 		// Protected task routes
@@ -58,9 +64,9 @@ func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig) *gin.Engine {
 		tasks.Use(authMiddleware.AuthenticateToken()) // All task routes require authentication
 		{
 			// Read operations - accessible by all authenticated users (admin and regular users)
-			tasks.GET("", authMiddleware.RequireUser(), controller.GetAllTasks)       // GET /api/v1/tasks
-			tasks.GET("/:id", authMiddleware.RequireUser(), controller.GetTaskByID)   // GET /api/v1/tasks/:id
-			
+			tasks.GET("", authMiddleware.RequireUser(), controller.GetAllTasks)     // GET /api/v1/tasks
+			tasks.GET("/:id", authMiddleware.RequireUser(), controller.GetTaskByID) // GET /api/v1/tasks/:id
+
 			// Write operations - accessible only by admins
 			tasks.POST("", authMiddleware.RequireAdmin(), controller.CreateTask)       // POST /api/v1/tasks (admin only)
 			tasks.PUT("/:id", authMiddleware.RequireAdmin(), controller.UpdateTask)    // PUT /api/v1/tasks/:id (admin only)
@@ -77,4 +83,4 @@ func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig) *gin.Engine {
 	})
 
 	return router
-}
\ No newline at end of file
+}
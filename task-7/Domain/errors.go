@@ -0,0 +1,17 @@
+package Domain
+
+import "errors"
+
+// Errors returned by the repositories and use cases. Callers compare against them with errors.Is,
+// and their messages are what API clients see in the error field of a response.
+var (
+	ErrTaskNotFound       = errors.New("task not found")
+	ErrUserNotFound       = errors.New("user not found")
+	ErrInvalidTaskID      = errors.New("invalid task ID format")
+	ErrInvalidUserID      = errors.New("invalid user ID format")
+	ErrUsernameTaken      = errors.New("username already exists")
+	ErrInvalidStatus      = errors.New("invalid status, must be one of: pending, in_progress, completed")
+	ErrInvalidDueDate     = errors.New("invalid due date format, use YYYY-MM-DD")
+	ErrAlreadyAdmin       = errors.New("user is already an admin")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
@@ -12,16 +12,18 @@ import (
 	"task_manager/Domain"
 )
 
-// TaskRepositoryInterface defines the contract for task data access
+// TaskRepositoryInterface defines the contract for task data access.
+// Every method runs under the caller's context, so a cancelled request stops its query.
 type TaskRepositoryInterface interface {
-	GetAll() ([]*Domain.Task, error)
-	GetByID(id string) (*Domain.Task, error)
-	Create(task *Domain.Task) error
-	Update(id string, task *Domain.Task) error
-	Delete(id string) error
+	GetAll(ctx context.Context) ([]*Domain.Task, error)
+	GetByID(ctx context.Context, id string) (*Domain.Task, error)
+	Create(ctx context.Context, task *Domain.Task) error
+	Update(ctx context.Context, id string, task *Domain.Task) error
+	Delete(ctx context.Context, id string) error
 }
 
-// TaskRepository implements TaskRepositoryInterface with MongoDB
+// TaskRepository implements TaskRepositoryInterface with MongoDB.
+// Each call is bounded by a 10 second timeout derived from the caller's context.
 type TaskRepository struct {
 	collection *mongo.Collection
 }
@@ -35,8 +37,8 @@ func NewTaskRepository(client *mongo.Client, dbName, collectionName string) Task
 }
 
 // GetAll returns all tasks from MongoDB
-func (tr *TaskRepository) GetAll() ([]*Domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) GetAll(ctx context.Context) ([]*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	cursor, err := tr.collection.Find(ctx, bson.M{})
@@ -54,20 +56,20 @@ func (tr *TaskRepository) GetAll() ([]*Domain.Task, error) {
 }
 
 // GetByID returns a task by its ObjectID from MongoDB
-func (tr *TaskRepository) GetByID(id string) (*Domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid task ID format")
+		return nil, Domain.ErrInvalidTaskID
 	}
 
 	var task Domain.Task
 	err = tr.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&task)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("task not found")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, Domain.ErrTaskNotFound
 		}
 		return nil, err
 	}
@@ -76,8 +78,8 @@ func (tr *TaskRepository) GetByID(id string) (*Domain.Task, error) {
 }
 
 // Create creates a new task in MongoDB
-func (tr *TaskRepository) Create(task *Domain.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) Create(ctx context.Context, task *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	task.ID = primitive.NewObjectID()
@@ -89,13 +91,13 @@ func (tr *TaskRepository) Create(task *Domain.Task) error {
 }
 
 // Update updates an existing task in MongoDB
-func (tr *TaskRepository) Update(id string, task *Domain.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid task ID format")
+		return Domain.ErrInvalidTaskID
 	}
 
 	task.UpdatedAt = time.Now()
@@ -116,20 +118,20 @@ func (tr *TaskRepository) Update(id string, task *Domain.Task) error {
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("task not found")
+		return Domain.ErrTaskNotFound
 	}
 
 	return nil
 }
 
 // Delete deletes a task by its ObjectID from MongoDB
-func (tr *TaskRepository) Delete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid task ID format")
+		return Domain.ErrInvalidTaskID
 	}
 
 	result, err := tr.collection.DeleteOne(ctx, bson.M{"_id": objectID})
@@ -138,7 +140,7 @@ func (tr *TaskRepository) Delete(id string) error {
 	}
 
 	if result.DeletedCount == 0 {
-		return errors.New("task not found")
+		return Domain.ErrTaskNotFound
 	}
 
 	return nil
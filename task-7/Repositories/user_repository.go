@@ -12,18 +12,20 @@ import (
 	"task_manager/Domain"
 )
 
-// UserRepositoryInterface defines the contract for user data access
+// UserRepositoryInterface defines the contract for user data access.
+// Every method runs under the caller's context, so a cancelled request stops its query.
 type UserRepositoryInterface interface {
-	GetAll() ([]*Domain.User, error)
-	GetByID(id string) (*Domain.User, error)
-	GetByUsername(username string) (*Domain.User, error)
-	Create(user *Domain.User) error
-	Update(id string, user *Domain.User) error
-	UpdateByUsername(username string, user *Domain.User) error
-	CountUsers() (int64, error)
+	GetAll(ctx context.Context) ([]*Domain.User, error)
+	GetByID(ctx context.Context, id string) (*Domain.User, error)
+	GetByUsername(ctx context.Context, username string) (*Domain.User, error)
+	Create(ctx context.Context, user *Domain.User) error
+	Update(ctx context.Context, id string, user *Domain.User) error
+	UpdateByUsername(ctx context.Context, username string, user *Domain.User) error
+	CountUsers(ctx context.Context) (int64, error)
 }
 
-// UserRepository implements UserRepositoryInterface with MongoDB
+// UserRepository implements UserRepositoryInterface with MongoDB.
+// Each call is bounded by a 10 second timeout derived from the caller's context.
 type UserRepository struct {
 	collection *mongo.Collection
 }
@@ -37,8 +39,8 @@ func NewUserRepository(client *mongo.Client, dbName string) UserRepositoryInterf
 }
 
 // GetAll returns all users from MongoDB
-func (ur *UserRepository) GetAll() ([]*Domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) GetAll(ctx context.Context) ([]*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	cursor, err := ur.collection.Find(ctx, bson.M{})
@@ -56,20 +58,20 @@ func (ur *UserRepository) GetAll() ([]*Domain.User, error) {
 }
 
 // GetByID retrieves a user by ID from MongoDB
-func (ur *UserRepository) GetByID(id string) (*Domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) GetByID(ctx context.Context, id string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid user ID format")
+		return nil, Domain.ErrInvalidUserID
 	}
 
 	var user Domain.User
 	err = ur.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, Domain.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -78,15 +80,15 @@ func (ur *UserRepository) GetByID(id string) (*Domain.User, error) {
 }
 
 // GetByUsername retrieves a user by username from MongoDB
-func (ur *UserRepository) GetByUsername(username string) (*Domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	var user Domain.User
 	err := ur.collection.FindOne(ctx, bson.M{"username": username}).Decode(&user)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, Domain.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -95,8 +97,8 @@ func (ur *UserRepository) GetByUsername(username string) (*Domain.User, error) {
 }
 
 // Create creates a new user in MongoDB
-func (ur *UserRepository) Create(user *Domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) Create(ctx context.Context, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	user.ID = primitive.NewObjectID()
@@ -108,13 +110,13 @@ func (ur *UserRepository) Create(user *Domain.User) error {
 }
 
 // Update updates an existing user in MongoDB
-func (ur *UserRepository) Update(id string, user *Domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) Update(ctx context.Context, id string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid user ID format")
+		return Domain.ErrInvalidUserID
 	}
 
 	user.UpdatedAt = time.Now()
@@ -134,15 +136,15 @@ func (ur *UserRepository) Update(id string, user *Domain.User) error {
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return Domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
 // UpdateByUsername updates an existing user by username in MongoDB
-func (ur *UserRepository) UpdateByUsername(username string, user *Domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	user.UpdatedAt = time.Now()
@@ -160,15 +162,15 @@ func (ur *UserRepository) UpdateByUsername(username string, user *Domain.User) e
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return Domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
 // CountUsers returns the total number of users in the database
-func (ur *UserRepository) CountUsers() (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	count, err := ur.collection.CountDocuments(ctx, bson.M{})
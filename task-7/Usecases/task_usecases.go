@@ -1,7 +1,7 @@
 package Usecases
 
 import (
-	"errors"
+	"context"
 	"time"
 
 	"task_manager/Domain"
@@ -10,11 +10,11 @@ import (
 
 // TaskUsecaseInterface defines the contract for task business logic
 type TaskUsecaseInterface interface {
-	GetAllTasks() ([]*Domain.Task, error)
-	GetTaskByID(id string) (*Domain.Task, error)
-	CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, error)
-	UpdateTask(id string, taskReq Domain.TaskRequest) (*Domain.Task, error)
-	DeleteTask(id string) error
+	GetAllTasks(ctx context.Context) ([]*Domain.Task, error)
+	GetTaskByID(ctx context.Context, id string) (*Domain.Task, error)
+	CreateTask(ctx context.Context, taskReq Domain.TaskRequest) (*Domain.Task, error)
+	UpdateTask(ctx context.Context, id string, taskReq Domain.TaskRequest) (*Domain.Task, error)
+	DeleteTask(ctx context.Context, id string) error
 }
 
 // TaskUsecase implements task business logic
@@ -30,20 +30,20 @@ func NewTaskUsecase(taskRepo Repositories.TaskRepositoryInterface) TaskUsecaseIn
 }
 
 // GetAllTasks returns all tasks
-func (tu *TaskUsecase) GetAllTasks() ([]*Domain.Task, error) {
-	return tu.taskRepo.GetAll()
+func (tu *TaskUsecase) GetAllTasks(ctx context.Context) ([]*Domain.Task, error) {
+	return tu.taskRepo.GetAll(ctx)
 }
 
 // GetTaskByID returns a task by its ID
-func (tu *TaskUsecase) GetTaskByID(id string) (*Domain.Task, error) {
-	return tu.taskRepo.GetByID(id)
+func (tu *TaskUsecase) GetTaskByID(ctx context.Context, id string) (*Domain.Task, error) {
+	return tu.taskRepo.GetByID(ctx, id)
 }
 
 // CreateTask creates a new task
-func (tu *TaskUsecase) CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, error) {
+func (tu *TaskUsecase) CreateTask(ctx context.Context, taskReq Domain.TaskRequest) (*Domain.Task, error) {
 	// Validate status
 	if !Domain.IsValidStatus(taskReq.Status) {
-		return nil, errors.New("invalid status, must be one of: pending, in_progress, completed")
+		return nil, Domain.ErrInvalidStatus
 	}
 
 	// Parse due date if provided
@@ -52,7 +52,7 @@ func (tu *TaskUsecase) CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, err
 	if taskReq.DueDate != "" {
 		dueDate, err = time.Parse("2006-01-02", taskReq.DueDate)
 		if err != nil {
-			return nil, errors.New("invalid due date format, use YYYY-MM-DD")
+			return nil, Domain.ErrInvalidDueDate
 		}
 	}
 
@@ -63,7 +63,7 @@ func (tu *TaskUsecase) CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, err
 		Status:      taskReq.Status,
 	}
 
-	err = tu.taskRepo.Create(task)
+	err = tu.taskRepo.Create(ctx, task)
 	if err != nil {
 		return nil, err
 	}
@@ -72,16 +72,16 @@ func (tu *TaskUsecase) CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, err
 }
 
 // UpdateTask updates an existing task
-func (tu *TaskUsecase) UpdateTask(id string, taskReq Domain.TaskRequest) (*Domain.Task, error) {
+func (tu *TaskUsecase) UpdateTask(ctx context.Context, id string, taskReq Domain.TaskRequest) (*Domain.Task, error) {
 	// Check if task exists
-	existingTask, err := tu.taskRepo.GetByID(id)
+	existingTask, err := tu.taskRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
 	// Validate status
 	if !Domain.IsValidStatus(taskReq.Status) {
-		return nil, errors.New("invalid status, must be one of: pending, in_progress, completed")
+		return nil, Domain.ErrInvalidStatus
 	}
 
 	// Parse due date if provided
@@ -89,7 +89,7 @@ func (tu *TaskUsecase) UpdateTask(id string, taskReq Domain.TaskRequest) (*Domai
 	if taskReq.DueDate != "" {
 		dueDate, err = time.Parse("2006-01-02", taskReq.DueDate)
 		if err != nil {
-			return nil, errors.New("invalid due date format, use YYYY-MM-DD")
+			return nil, Domain.ErrInvalidDueDate
 		}
 	}
 
@@ -99,16 +99,16 @@ func (tu *TaskUsecase) UpdateTask(id string, taskReq Domain.TaskRequest) (*Domai
 	existingTask.DueDate = dueDate
 	existingTask.Status = taskReq.Status
 
-	err = tu.taskRepo.Update(id, existingTask)
+	err = tu.taskRepo.Update(ctx, id, existingTask)
 	if err != nil {
 		return nil, err
 	}
 
 	// Return updated task
-	return tu.taskRepo.GetByID(id)
+	return tu.taskRepo.GetByID(ctx, id)
 }
 
 // DeleteTask deletes a task by its ID
-func (tu *TaskUsecase) DeleteTask(id string) error {
-	return tu.taskRepo.Delete(id)
+func (tu *TaskUsecase) DeleteTask(ctx context.Context, id string) error {
+	return tu.taskRepo.Delete(ctx, id)
 }
\ No newline at end of file
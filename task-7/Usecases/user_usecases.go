@@ -1,7 +1,9 @@
 package Usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
 
 	"task_manager/Domain"
 	"task_manager/Infrastructure"
@@ -10,11 +12,11 @@ import (
 
 // UserUsecaseInterface defines the contract for user business logic
 type UserUsecaseInterface interface {
-	RegisterUser(userReq Domain.UserRequest) (*Domain.User, error)
-	LoginUser(loginReq Domain.LoginRequest) (*Domain.User, string, error)
-	GetUserProfile(userID string) (*Domain.User, error)
-	GetAllUsers() ([]*Domain.User, error)
-	PromoteUserToAdmin(username string) (*Domain.User, error)
+	RegisterUser(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error)
+	LoginUser(ctx context.Context, loginReq Domain.LoginRequest) (*Domain.User, string, error)
+	GetUserProfile(ctx context.Context, userID string) (*Domain.User, error)
+	GetAllUsers(ctx context.Context) ([]*Domain.User, error)
+	PromoteUserToAdmin(ctx context.Context, username string) (*Domain.User, error)
 }
 
 // UserUsecase implements user business logic
@@ -38,21 +40,24 @@ func NewUserUsecase(
 }
 
 // RegisterUser creates a new user
-func (uu *UserUsecase) RegisterUser(userReq Domain.UserRequest) (*Domain.User, error) {
+func (uu *UserUsecase) RegisterUser(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error) {
 	// Check if username already exists
-	existingUser, _ := uu.userRepo.GetByUsername(userReq.Username)
+	existingUser, err := uu.userRepo.GetByUsername(ctx, userReq.Username)
+	if err != nil && !errors.Is(err, Domain.ErrUserNotFound) {
+		return nil, err
+	}
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, Domain.ErrUsernameTaken
 	}
 
 	// Hash the password
 	hashedPassword, err := uu.passwordService.HashPassword(userReq.Password)
 	if err != nil {
-		return nil, errors.New("failed to hash password")
+		return nil, fmt.Errorf("failed to hash password: %w", err)
 	}
 
 	// Check if this is the first user (make them admin)
-	userCount, err := uu.userRepo.CountUsers()
+	userCount, err := uu.userRepo.CountUsers(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +73,7 @@ func (uu *UserUsecase) RegisterUser(userReq Domain.UserRequest) (*Domain.User, e
 		Role:     role,
 	}
 
-	err = uu.userRepo.Create(user)
+	err = uu.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
@@ -77,54 +82,57 @@ func (uu *UserUsecase) RegisterUser(userReq Domain.UserRequest) (*Domain.User, e
 }
 
 // LoginUser authenticates a user and returns user info with JWT token
-func (uu *UserUsecase) LoginUser(loginReq Domain.LoginRequest) (*Domain.User, string, error) {
-	user, err := uu.userRepo.GetByUsername(loginReq.Username)
+func (uu *UserUsecase) LoginUser(ctx context.Context, loginReq Domain.LoginRequest) (*Domain.User, string, error) {
+	user, err := uu.userRepo.GetByUsername(ctx, loginReq.Username)
+	if errors.Is(err, Domain.ErrUserNotFound) {
+		return nil, "", Domain.ErrInvalidCredentials
+	}
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		return nil, "", err
 	}
 
 	// Compare password with hash
 	err = uu.passwordService.ComparePassword(user.Password, loginReq.Password)
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		return nil, "", Domain.ErrInvalidCredentials
 	}
 
 	// Generate JWT token
 	token, err := uu.jwtService.GenerateToken(user)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	return user, token, nil
 }
 
 // GetUserProfile returns user profile by ID
-func (uu *UserUsecase) GetUserProfile(userID string) (*Domain.User, error) {
-	return uu.userRepo.GetByID(userID)
+func (uu *UserUsecase) GetUserProfile(ctx context.Context, userID string) (*Domain.User, error) {
+	return uu.userRepo.GetByID(ctx, userID)
 }
 
 // GetAllUsers returns all users (admin only)
-func (uu *UserUsecase) GetAllUsers() ([]*Domain.User, error) {
-	return uu.userRepo.GetAll()
+func (uu *UserUsecase) GetAllUsers(ctx context.Context) ([]*Domain.User, error) {
+	return uu.userRepo.GetAll(ctx)
 }
 
 // PromoteUserToAdmin promotes a user to admin role
-func (uu *UserUsecase) PromoteUserToAdmin(username string) (*Domain.User, error) {
-	user, err := uu.userRepo.GetByUsername(username)
+func (uu *UserUsecase) PromoteUserToAdmin(ctx context.Context, username string) (*Domain.User, error) {
+	user, err := uu.userRepo.GetByUsername(ctx, username)
 	if err != nil {
 		return nil, err
 	}
 
 	if user.Role == Domain.RoleAdmin {
-		return nil, errors.New("user is already an admin")
+		return nil, Domain.ErrAlreadyAdmin
 	}
 
 	user.Role = Domain.RoleAdmin
-	err = uu.userRepo.UpdateByUsername(username, user)
+	err = uu.userRepo.UpdateByUsername(ctx, username, user)
 	if err != nil {
 		return nil, err
 	}
 
 	// Return updated user
-	return uu.userRepo.GetByUsername(username)
+	return uu.userRepo.GetByUsername(ctx, username)
 }
\ No newline at end of file
@@ -2,26 +2,86 @@ package main
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/joho/godotenv"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/mongodb"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"golang.org/x/net/context"
 
 	"task_manager/Delivery/routers"
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+	"task_manager/Usecases"
 )
 
+// Backends the architecture test can run against
+const (
+	backendLocal     = "local"     // MongoDB from MONGODB_URI, skipped when unreachable
+	backendEphemeral = "ephemeral" // throwaway MongoDB container, skipped without Docker
+	backendMemory    = "memory"    // in-memory repositories only
+)
+
+// mongoImage is the Docker image used for the ephemeral MongoDB
+const mongoImage = "mongo:7"
+
+// errDockerUnavailable is returned by startMongoContainer when no Docker daemon can be reached
+var errDockerUnavailable = errors.New("docker is not available")
+
+// testRun collects assertion failures for the whole run
+type testRun struct {
+	step     int
+	scenario string
+	failures []string
+}
+
+// begin starts the named scenario; failures recorded afterwards are attributed to it
+func (tr *testRun) begin(scenario string) {
+	tr.step++
+	tr.scenario = scenario
+	fmt.Printf("\n%d. %s:\n", tr.step, scenario)
+}
+
+// check records a failed assertion when ok is false
+func (tr *testRun) check(ok bool, format string, args ...interface{}) bool {
+	msg := fmt.Sprintf(format, args...)
+	if ok {
+		fmt.Printf("   ✓ %s\n", msg)
+		return true
+	}
+	fmt.Printf("   ✗ %s\n", msg)
+	tr.failures = append(tr.failures, fmt.Sprintf("[%s] %s", tr.scenario, msg))
+	return false
+}
+
+// expectStatus asserts the response status code, printing the body on mismatch
+func (tr *testRun) expectStatus(w *httptest.ResponseRecorder, expected int, step string) bool {
+	if !tr.check(w.Code == expected, "%s: expected status %d, got %d", step, expected, w.Code) {
+		fmt.Printf("   Response: %s\n", w.Body.String())
+		return false
+	}
+	return true
+}
+
 // TestCleanArchitecture demonstrates the Clean Architecture implementation
-func TestCleanArchitecture() {
+func TestCleanArchitecture(backend string) int {
 	// Load environment variables
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using defaults")
@@ -34,41 +94,105 @@ func TestCleanArchitecture() {
 		Collection: getEnvOrDefault("MONGODB_COLLECTION", "tasks_test"),
 	}
 
-	// Connect to MongoDB
-	client, err := connectToMongoDB(dbConfig)
-	if err != nil {
-		log.Printf("Warning: Could not connect to MongoDB: %v", err)
-		log.Println("Skipping database-dependent tests")
-		return
+	var router *gin.Engine
+	switch backend {
+	case backendLocal:
+		client, err := connectToMongoDB(dbConfig)
+		if err != nil {
+			log.Printf("Warning: Could not connect to MongoDB: %v", err)
+			log.Println("Skipping database-dependent tests (use -backend=ephemeral to provision one)")
+			return 0
+		}
+		defer disconnectFromMongoDB(client)
+		router = routers.SetupRouter(client, dbConfig)
+
+	case backendEphemeral:
+		uri, stop, err := startMongoContainer(context.Background())
+		if errors.Is(err, errDockerUnavailable) {
+			log.Printf("Warning: %v", err)
+			log.Println("SKIP: the ephemeral backend needs a running Docker daemon (use -backend=memory to run without one)")
+			return 0
+		}
+		if err != nil {
+			log.Printf("Error: could not start MongoDB container: %v", err)
+			return 1
+		}
+		defer stop()
+
+		dbConfig.URI = uri
+		client, err := connectToMongoDB(dbConfig)
+		if err != nil {
+			log.Printf("Error: ephemeral MongoDB is not reachable: %v", err)
+			return 1
+		}
+		defer disconnectFromMongoDB(client)
+		router = routers.SetupRouter(client, dbConfig)
+
+	case backendMemory:
+		router = setupInMemoryRouter()
+
+	default:
+		log.Printf("Error: unknown backend %q (expected %s, %s or %s)", backend, backendLocal, backendEphemeral, backendMemory)
+		return 2
 	}
-	defer disconnectFromMongoDB(client)
 
-	// Initialize router with Clean Architecture
-	router := routers.SetupRouter(client, dbConfig)
+	// Only a shared local database can already hold the test user
+	fresh := backend != backendLocal
+	tr := &testRun{}
 
 	// Test Clean Architecture layers
 	fmt.Println("=== Testing Clean Architecture Implementation ===")
-	
+
 	// Test 1: User Registration (Domain -> Usecase -> Repository -> Infrastructure)
-	fmt.Println("\n1. Testing User Registration Flow:")
-	testUserRegistration(router)
+	tr.begin("Testing User Registration Flow")
+	testUserRegistration(tr, router, fresh)
 
 	// Test 2: User Login (Authentication flow)
-	fmt.Println("\n2. Testing User Login Flow:")
-	token := testUserLogin(router)
+	tr.begin("Testing User Login Flow")
+	token := testUserLogin(tr, router)
 
 	// Test 3: Task Creation (Admin-only operation)
-	fmt.Println("\n3. Testing Task Creation Flow:")
-	testTaskCreation(router, token)
+	tr.begin("Testing Task Creation Flow")
+	taskID := testTaskCreation(tr, router, token)
 
 	// Test 4: Task Retrieval (Read operation)
-	fmt.Println("\n4. Testing Task Retrieval Flow:")
-	testTaskRetrieval(router, token)
+	tr.begin("Testing Task Retrieval Flow")
+	testTaskRetrieval(tr, router, token, taskID)
+
+	// Negative paths: every request below must be rejected
+	tr.begin("Testing Regular User Cannot Create Tasks")
+	userToken := testRegularUserCannotCreateTask(tr, router)
+
+	tr.begin("Testing Tampered Token Is Rejected")
+	testTamperedTokenRejected(tr, router, userToken)
+
+	tr.begin("Testing Expired Token Is Rejected")
+	testExpiredTokenRejected(tr, router)
+
+	tr.begin("Testing Invalid Task ID Is Rejected")
+	testInvalidTaskIDRejected(tr, router, token)
+
+	tr.begin("Testing Missing Task Is Not Found")
+	testMissingTaskNotFound(tr, router, token)
+
+	tr.begin("Testing Duplicate Registration Is Rejected")
+	testDuplicateRegistrationRejected(tr, router)
 
 	fmt.Println("\n=== Clean Architecture Test Complete ===")
+
+	if len(tr.failures) > 0 {
+		fmt.Printf("\nFAILED: %d assertion(s) failed:\n", len(tr.failures))
+		for _, failure := range tr.failures {
+			fmt.Printf("   - %s\n", failure)
+		}
+		return 1
+	}
+
+	fmt.Println("\nAll assertions passed")
+	return 0
 }
 
-func testUserRegistration(router *gin.Engine) {
+func testUserRegistration(tr *testRun, router *gin.Engine, fresh bool) {
 	userReq := Domain.UserRequest{
 		Username: "testuser",
 		Password: "password123",
@@ -82,15 +206,18 @@ func testUserRegistration(router *gin.Engine) {
 	router.ServeHTTP(w, req)
 
 	fmt.Printf("   Status: %d\n", w.Code)
-	if w.Code == http.StatusCreated || w.Code == http.StatusConflict {
-		fmt.Println("   ✓ User registration flow working correctly")
-	} else {
-		fmt.Printf("   ✗ Unexpected status code: %d\n", w.Code)
+	if fresh {
+		tr.expectStatus(w, http.StatusCreated, "register on empty database")
+		return
+	}
+
+	if !tr.check(w.Code == http.StatusCreated || w.Code == http.StatusConflict,
+		"register: expected status %d or %d, got %d", http.StatusCreated, http.StatusConflict, w.Code) {
 		fmt.Printf("   Response: %s\n", w.Body.String())
 	}
 }
 
-func testUserLogin(router *gin.Engine) string {
+func testUserLogin(tr *testRun, router *gin.Engine) string {
 	loginReq := Domain.LoginRequest{
 		Username: "testuser",
 		Password: "password123",
@@ -104,23 +231,20 @@ func testUserLogin(router *gin.Engine) string {
 	router.ServeHTTP(w, req)
 
 	fmt.Printf("   Status: %d\n", w.Code)
-	if w.Code == http.StatusOK {
-		var response Domain.LoginResponse
-		json.Unmarshal(w.Body.Bytes(), &response)
-		fmt.Println("   ✓ User login flow working correctly")
-		fmt.Printf("   Token received: %s...\n", response.Token[:20])
-		return response.Token
-	} else {
-		fmt.Printf("   ✗ Login failed with status: %d\n", w.Code)
-		fmt.Printf("   Response: %s\n", w.Body.String())
+	if !tr.expectStatus(w, http.StatusOK, "login") {
 		return ""
 	}
+
+	var response Domain.LoginResponse
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	tr.check(err == nil, "login: response decodes as LoginResponse")
+	tr.check(response.Token != "", "login: token is present")
+	return response.Token
 }
 
-func testTaskCreation(router *gin.Engine, token string) {
-	if token == "" {
-		fmt.Println("   ✗ No token available, skipping task creation test")
-		return
+func testTaskCreation(tr *testRun, router *gin.Engine, token string) string {
+	if !tr.check(token != "", "task creation: token available") {
+		return ""
 	}
 
 	taskReq := Domain.TaskRequest{
@@ -139,17 +263,23 @@ func testTaskCreation(router *gin.Engine, token string) {
 	router.ServeHTTP(w, req)
 
 	fmt.Printf("   Status: %d\n", w.Code)
-	if w.Code == http.StatusCreated {
-		fmt.Println("   ✓ Task creation flow working correctly")
-	} else {
-		fmt.Printf("   ✗ Task creation failed with status: %d\n", w.Code)
-		fmt.Printf("   Response: %s\n", w.Body.String())
+	if !tr.expectStatus(w, http.StatusCreated, "create task") {
+		return ""
 	}
+
+	var response struct {
+		Success bool        `json:"success"`
+		Data    Domain.Task `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	tr.check(err == nil && response.Success, "create task: response reports success")
+	tr.check(!response.Data.ID.IsZero(), "create task: task ID assigned")
+	tr.check(response.Data.Title == taskReq.Title, "create task: title persisted")
+	return response.Data.ID.Hex()
 }
 
-func testTaskRetrieval(router *gin.Engine, token string) {
-	if token == "" {
-		fmt.Println("   ✗ No token available, skipping task retrieval test")
+func testTaskRetrieval(tr *testRun, router *gin.Engine, token, taskID string) {
+	if !tr.check(token != "", "task retrieval: token available") {
 		return
 	}
 
@@ -160,12 +290,419 @@ func testTaskRetrieval(router *gin.Engine, token string) {
 	router.ServeHTTP(w, req)
 
 	fmt.Printf("   Status: %d\n", w.Code)
-	if w.Code == http.StatusOK {
-		fmt.Println("   ✓ Task retrieval flow working correctly")
-	} else {
-		fmt.Printf("   ✗ Task retrieval failed with status: %d\n", w.Code)
-		fmt.Printf("   Response: %s\n", w.Body.String())
+	if !tr.expectStatus(w, http.StatusOK, "list tasks") {
+		return
+	}
+
+	var response struct {
+		Success bool           `json:"success"`
+		Data    []*Domain.Task `json:"data"`
+	}
+	err := json.Unmarshal(w.Body.Bytes(), &response)
+	tr.check(err == nil && response.Success, "list tasks: response reports success")
+
+	if taskID == "" {
+		return
+	}
+	found := false
+	for _, task := range response.Data {
+		if task.ID.Hex() == taskID {
+			found = true
+			break
+		}
+	}
+	tr.check(found, "list tasks: created task %s is listed", taskID)
+}
+
+// testRegularUserCannotCreateTask registers a non-admin user and checks that the
+// admin-only task creation endpoint answers 403; it returns the user's token
+func testRegularUserCannotCreateTask(tr *testRun, router *gin.Engine) string {
+	// The first registered user is the admin, so any later one is a regular user
+	username := fmt.Sprintf("regularuser_%d", time.Now().UnixNano())
+
+	w := postJSON(router, "/api/v1/register", Domain.UserRequest{Username: username, Password: "password123"}, "")
+	if !tr.expectStatus(w, http.StatusCreated, "register regular user") {
+		return ""
 	}
+
+	w = postJSON(router, "/api/v1/login", Domain.LoginRequest{Username: username, Password: "password123"}, "")
+	if !tr.expectStatus(w, http.StatusOK, "login regular user") {
+		return ""
+	}
+
+	var login Domain.LoginResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &login); !tr.check(err == nil && login.Token != "", "login regular user: token is present") {
+		return ""
+	}
+	if tr.check(login.User != nil, "login regular user: user is present") {
+		tr.check(login.User.Role == Domain.RoleUser, "login regular user: role is %q, got %q", Domain.RoleUser, login.User.Role)
+	}
+
+	w = postJSON(router, "/api/v1/tasks", Domain.TaskRequest{Title: "Forbidden Task", Status: "pending"}, login.Token)
+	tr.expectStatus(w, http.StatusForbidden, "create task as regular user")
+	return login.Token
+}
+
+// testTamperedTokenRejected rewrites the role claim of a regular user's token to
+// admin without re-signing it and checks that the API answers 401
+func testTamperedTokenRejected(tr *testRun, router *gin.Engine, userToken string) {
+	if !tr.check(userToken != "", "tampered token: regular user token available") {
+		return
+	}
+
+	parts := strings.Split(userToken, ".")
+	if !tr.check(len(parts) == 3, "tampered token: token has three segments") {
+		return
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if !tr.check(err == nil, "tampered token: payload decodes") {
+		return
+	}
+	claims := map[string]interface{}{}
+	if !tr.check(json.Unmarshal(payload, &claims) == nil, "tampered token: claims decode") {
+		return
+	}
+	claims["role"] = Domain.RoleAdmin
+	payload, _ = json.Marshal(claims)
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	tampered := strings.Join(parts, ".")
+
+	w := postJSON(router, "/api/v1/tasks", Domain.TaskRequest{Title: "Tampered Task", Status: "pending"}, tampered)
+	tr.expectStatus(w, http.StatusUnauthorized, "create task with tampered token")
+
+	w = getWithToken(router, "/api/v1/tasks", tampered)
+	tr.expectStatus(w, http.StatusUnauthorized, "list tasks with tampered token")
+}
+
+// testExpiredTokenRejected signs a correctly keyed token that expired an hour ago
+// and checks that the API answers 401
+func testExpiredTokenRejected(tr *testRun, router *gin.Engine) {
+	claims := jwt.MapClaims{
+		"user_id":  primitive.NewObjectID().Hex(),
+		"username": "testuser",
+		"role":     Domain.RoleAdmin,
+		"exp":      time.Now().Add(-time.Hour).Unix(),
+		"iat":      time.Now().Add(-25 * time.Hour).Unix(),
+	}
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(Infrastructure.NewJWTService().GetJWTSecret())
+	if !tr.check(err == nil, "expired token: token signed") {
+		return
+	}
+
+	w := getWithToken(router, "/api/v1/tasks", expired)
+	tr.expectStatus(w, http.StatusUnauthorized, "list tasks with expired token")
+}
+
+// testInvalidTaskIDRejected checks that malformed ObjectIDs answer 400 rather than 404 or 500
+func testInvalidTaskIDRejected(tr *testRun, router *gin.Engine, token string) {
+	if !tr.check(token != "", "invalid task ID: admin token available") {
+		return
+	}
+
+	w := getWithToken(router, "/api/v1/tasks/not-an-object-id", token)
+	tr.expectStatus(w, http.StatusBadRequest, "get task with invalid ID")
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/tasks/not-an-object-id", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	tr.expectStatus(w, http.StatusBadRequest, "delete task with invalid ID")
+}
+
+// testMissingTaskNotFound checks that a well-formed ID of a task that does not exist answers 404
+func testMissingTaskNotFound(tr *testRun, router *gin.Engine, token string) {
+	if !tr.check(token != "", "missing task: admin token available") {
+		return
+	}
+
+	missingID := primitive.NewObjectID().Hex()
+	w := getWithToken(router, "/api/v1/tasks/"+missingID, token)
+	tr.expectStatus(w, http.StatusNotFound, "get missing task")
+
+	req, _ := http.NewRequest("DELETE", "/api/v1/tasks/"+missingID, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	tr.expectStatus(w, http.StatusNotFound, "delete missing task")
+}
+
+// testDuplicateRegistrationRejected registers the already existing test user again and expects 409
+func testDuplicateRegistrationRejected(tr *testRun, router *gin.Engine) {
+	w := postJSON(router, "/api/v1/register", Domain.UserRequest{Username: "testuser", Password: "password123"}, "")
+	tr.expectStatus(w, http.StatusConflict, "register existing username")
+}
+
+// postJSON sends body as JSON to path, authenticated when token is not empty
+func postJSON(router *gin.Engine, path string, body interface{}, token string) *httptest.ResponseRecorder {
+	jsonData, _ := json.Marshal(body)
+	req, _ := http.NewRequest("POST", path, bytes.NewBuffer(jsonData))
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// getWithToken sends an authenticated GET request to path
+func getWithToken(router *gin.Engine, path, token string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest("GET", path, nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+// startMongoContainer runs a throwaway MongoDB container through testcontainers and returns
+// its URI together with a function that removes the container again. It reports
+// errDockerUnavailable when there is no Docker daemon to run it on.
+func startMongoContainer(ctx context.Context) (string, func(), error) {
+	// testcontainers panics on some paths without a daemon, so check for one first
+	provider, err := testcontainers.ProviderDocker.GetProvider()
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %v", errDockerUnavailable, err)
+	}
+	defer provider.Close()
+	if err := provider.Health(ctx); err != nil {
+		return "", nil, fmt.Errorf("%w: %v", errDockerUnavailable, err)
+	}
+
+	// The module waits until mongod accepts connections before returning
+	container, err := mongodb.RunContainer(ctx, testcontainers.WithImage(mongoImage))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to start %s: %v", mongoImage, err)
+	}
+	stop := func() {
+		if err := container.Terminate(context.Background()); err != nil {
+			log.Printf("Warning: failed to remove MongoDB container %.12s: %v", container.GetContainerID(), err)
+			return
+		}
+		log.Println("Removed ephemeral MongoDB container")
+	}
+
+	uri, err := container.ConnectionString(ctx)
+	if err != nil {
+		stop()
+		return "", nil, fmt.Errorf("failed to resolve container address: %v", err)
+	}
+
+	log.Printf("Started ephemeral MongoDB container %.12s at %s", container.GetContainerID(), uri)
+	return uri, stop, nil
+}
+
+// setupInMemoryRouter wires the router on top of in-memory repositories
+func setupInMemoryRouter() *gin.Engine {
+	return routers.SetupRouterWithRepositories(newMemoryTaskRepository(), newMemoryUserRepository())
+}
+
+// The concrete repositories and usecases must keep satisfying the interfaces the layers depend on
+var (
+	_ Repositories.TaskRepositoryInterface = (*Repositories.TaskRepository)(nil)
+	_ Repositories.UserRepositoryInterface = (*Repositories.UserRepository)(nil)
+	_ Repositories.TaskRepositoryInterface = (*memoryTaskRepository)(nil)
+	_ Repositories.UserRepositoryInterface = (*memoryUserRepository)(nil)
+	_ Usecases.TaskUsecaseInterface        = (*Usecases.TaskUsecase)(nil)
+	_ Usecases.UserUsecaseInterface        = (*Usecases.UserUsecase)(nil)
+)
+
+// memoryTaskRepository implements Repositories.TaskRepositoryInterface in memory
+type memoryTaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[primitive.ObjectID]Domain.Task
+}
+
+func newMemoryTaskRepository() Repositories.TaskRepositoryInterface {
+	return &memoryTaskRepository{tasks: make(map[primitive.ObjectID]Domain.Task)}
+}
+
+func (mr *memoryTaskRepository) GetAll(ctx context.Context) ([]*Domain.Task, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	tasks := make([]*Domain.Task, 0, len(mr.tasks))
+	for _, task := range mr.tasks {
+		task := task
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+func (mr *memoryTaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	task, ok := mr.tasks[objectID]
+	if !ok {
+		return nil, Domain.ErrTaskNotFound
+	}
+	return &task, nil
+}
+
+func (mr *memoryTaskRepository) Create(ctx context.Context, task *Domain.Task) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	task.ID = primitive.NewObjectID()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+	mr.tasks[task.ID] = *task
+	return nil
+}
+
+func (mr *memoryTaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	existing, ok := mr.tasks[objectID]
+	if !ok {
+		return Domain.ErrTaskNotFound
+	}
+
+	task.UpdatedAt = time.Now()
+	existing.Title = task.Title
+	existing.Description = task.Description
+	existing.DueDate = task.DueDate
+	existing.Status = task.Status
+	existing.UpdatedAt = task.UpdatedAt
+	mr.tasks[objectID] = existing
+	return nil
+}
+
+func (mr *memoryTaskRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	if _, ok := mr.tasks[objectID]; !ok {
+		return Domain.ErrTaskNotFound
+	}
+	delete(mr.tasks, objectID)
+	return nil
+}
+
+// memoryUserRepository implements Repositories.UserRepositoryInterface in memory
+type memoryUserRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]Domain.User
+}
+
+func newMemoryUserRepository() Repositories.UserRepositoryInterface {
+	return &memoryUserRepository{users: make(map[primitive.ObjectID]Domain.User)}
+}
+
+func (mr *memoryUserRepository) GetAll(ctx context.Context) ([]*Domain.User, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	users := make([]*Domain.User, 0, len(mr.users))
+	for _, user := range mr.users {
+		user := user
+		users = append(users, &user)
+	}
+	return users, nil
+}
+
+func (mr *memoryUserRepository) GetByID(ctx context.Context, id string) (*Domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	user, ok := mr.users[objectID]
+	if !ok {
+		return nil, Domain.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (mr *memoryUserRepository) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	for _, user := range mr.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, Domain.ErrUserNotFound
+}
+
+func (mr *memoryUserRepository) Create(ctx context.Context, user *Domain.User) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	user.ID = primitive.NewObjectID()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+	mr.users[user.ID] = *user
+	return nil
+}
+
+func (mr *memoryUserRepository) Update(ctx context.Context, id string, user *Domain.User) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	existing, ok := mr.users[objectID]
+	if !ok {
+		return Domain.ErrUserNotFound
+	}
+
+	user.UpdatedAt = time.Now()
+	existing.Username = user.Username
+	existing.Password = user.Password
+	existing.Role = user.Role
+	existing.UpdatedAt = user.UpdatedAt
+	mr.users[objectID] = existing
+	return nil
+}
+
+func (mr *memoryUserRepository) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
+	mr.mu.Lock()
+	defer mr.mu.Unlock()
+
+	for id, existing := range mr.users {
+		if existing.Username == username {
+			user.UpdatedAt = time.Now()
+			existing.Role = user.Role
+			existing.UpdatedAt = user.UpdatedAt
+			mr.users[id] = existing
+			return nil
+		}
+	}
+	return Domain.ErrUserNotFound
+}
+
+func (mr *memoryUserRepository) CountUsers(ctx context.Context) (int64, error) {
+	mr.mu.RLock()
+	defer mr.mu.RUnlock()
+
+	return int64(len(mr.users)), nil
 }
 
 func getEnvOrDefault(key, defaultValue string) string {
@@ -188,6 +725,7 @@ func connectToMongoDB(config *routers.DatabaseConfig) (*mongo.Client, error) {
 	// Test the connection
 	err = client.Ping(ctx, nil)
 	if err != nil {
+		client.Disconnect(context.Background())
 		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
 	}
 
@@ -209,5 +747,10 @@ func disconnectFromMongoDB(client *mongo.Client) error {
 }
 
 func main() {
-	TestCleanArchitecture()
-}
\ No newline at end of file
+	backend := flag.String("backend", backendLocal,
+		"storage backend: local (MONGODB_URI), ephemeral (throwaway Docker MongoDB, skipped without Docker) or memory")
+	flag.Parse()
+
+	// os.Exit skips deferred calls, so the run has to finish (and tear down) first
+	os.Exit(TestCleanArchitecture(*backend))
+}
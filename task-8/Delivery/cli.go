@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"task_manager/Delivery/routers"
+	"task_manager/Domain"
+)
+
+// serveCommand runs the HTTP API; it is the command when none is given
+const serveCommand = "serve"
+
+// listUsersPageSize is how many users list-users fetches at a time
+const listUsersPageSize = 100
+
+// operatorCaller acts for whoever runs an admin command. It has no user ID, so audit
+// entries it causes have no actor.
+var operatorCaller = Domain.Caller{Role: Domain.RoleAdmin}
+
+// errUsage reports invalid command line flags that the flag package has already described
+var errUsage = errors.New("invalid usage")
+
+// adminAction runs an admin command against the configured database, writing its result to out
+type adminAction func(ctx context.Context, services *routers.Services, out io.Writer) error
+
+// adminCommand is a subcommand for operators. parse reads and checks its flags before anything
+// connects to the database and returns the action to run.
+type adminCommand struct {
+	summary string
+	parse   func(args []string, stderr io.Writer) (adminAction, error)
+}
+
+// adminCommands are the subcommands besides serve, by name
+var adminCommands = map[string]adminCommand{
+	"create-admin": {summary: "Create an admin account", parse: parseCreateAdmin},
+	"list-users":   {summary: "List user accounts", parse: parseListUsers},
+	"promote":      {summary: "Promote a user to admin or manager", parse: parsePromote},
+	"purge-tasks":  {summary: "Permanently remove tasks in a status that have not changed for a while", parse: parsePurgeTasks},
+}
+
+// splitCommand separates the subcommand from its arguments. Without one, or when the arguments
+// start with a flag as in `main --memory`, the command is serve.
+func splitCommand(args []string) (string, []string) {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return serveCommand, args
+	}
+	return args[0], args[1:]
+}
+
+// printUsage lists the commands
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: task_manager [command] [flags]\n\nCommands:\n")
+	fmt.Fprintf(w, "  %-14s %s\n", serveCommand, "Run the HTTP API (the default)")
+
+	names := make([]string, 0, len(adminCommands))
+	for name := range adminCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  %-14s %s\n", name, adminCommands[name].summary)
+	}
+	fmt.Fprintf(w, "\nRun task_manager <command> -h for the flags of a command.\n")
+}
+
+// runAdminCommand runs the named admin command and returns the process exit code: 0 on success,
+// 1 when the command failed and 2 when it was called wrongly. open connects to the database only
+// once the flags are known to be valid; the function it returns releases the connection.
+func runAdminCommand(ctx context.Context, name string, args []string, open func() (*routers.Services, func(), error), stdout, stderr io.Writer) int {
+	command, ok := adminCommands[name]
+	if !ok {
+		fmt.Fprintf(stderr, "unknown command %q\n\n", name)
+		printUsage(stderr)
+		return 2
+	}
+
+	action, err := command.parse(args, stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case err != nil:
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 2
+	}
+
+	services, closeServices, err := open()
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	defer closeServices()
+
+	if err := action(ctx, services, stdout); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", name, err)
+		return 1
+	}
+	return 0
+}
+
+// newFlagSet creates the flag set of a command, reporting problems to stderr
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	return flags
+}
+
+// parseFlags parses args, turning errors the flag package already printed into errUsage
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected argument %q", flags.Arg(0))
+	}
+	return nil
+}
+
+// parseCreateAdmin reads create-admin --username --password [--email]
+func parseCreateAdmin(args []string, stderr io.Writer) (adminAction, error) {
+	flags := newFlagSet("create-admin", stderr)
+	username := flags.String("username", "", "username of the new admin (required)")
+	password := flags.String("password", "", "password of the new admin (required)")
+	email := flags.String("email", "", "email address of the new admin")
+	if err := parseFlags(flags, args); err != nil {
+		return nil, err
+	}
+	if *username == "" || *password == "" {
+		return nil, errors.New("--username and --password are required")
+	}
+
+	return func(ctx context.Context, services *routers.Services, out io.Writer) error {
+		user, err := services.Users.CreateAdmin(ctx, Domain.UserRequest{Username: *username, Email: *email, Password: *password})
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "created admin %s (%s)\n", user.Username, user.ID.Hex())
+		return nil
+	}, nil
+}
+
+// parseListUsers reads list-users [--role] [--q]
+func parseListUsers(args []string, stderr io.Writer) (adminAction, error) {
+	flags := newFlagSet("list-users", stderr)
+	role := flags.String("role", "", "only list users with this role: user, manager or admin")
+	query := flags.String("q", "", "only list users whose username or email contains this text")
+	if err := parseFlags(flags, args); err != nil {
+		return nil, err
+	}
+	if *role != "" && !Domain.IsValidRole(*role) {
+		return nil, fmt.Errorf("invalid --role %q: must be one of user, manager, admin", *role)
+	}
+
+	return func(ctx context.Context, services *routers.Services, out io.Writer) error {
+		table := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(table, "ID\tUSERNAME\tEMAIL\tROLE\tACTIVE\tCREATED")
+
+		filter := Domain.UserFilter{Query: *query, Role: *role}
+		for offset := int64(0); ; offset += listUsersPageSize {
+			users, total, err := services.Users.GetAllUsers(ctx, filter, Domain.Pagination{Limit: listUsersPageSize, Offset: offset})
+			if err != nil {
+				return err
+			}
+			for _, user := range users {
+				fmt.Fprintf(table, "%s\t%s\t%s\t%s\t%t\t%s\n", user.ID.Hex(), user.Username, user.Email, user.Role, user.IsActive, user.CreatedAt.Format(time.RFC3339))
+			}
+			if len(users) == 0 || offset+int64(len(users)) >= total {
+				break
+			}
+		}
+		return table.Flush()
+	}, nil
+}
+
+// parsePromote reads promote --username [--role]
+func parsePromote(args []string, stderr io.Writer) (adminAction, error) {
+	flags := newFlagSet("promote", stderr)
+	username := flags.String("username", "", "username of the user to promote (required)")
+	role := flags.String("role", Domain.RoleAdmin, "role to grant: admin or manager")
+	if err := parseFlags(flags, args); err != nil {
+		return nil, err
+	}
+	if *username == "" {
+		return nil, errors.New("--username is required")
+	}
+
+	return func(ctx context.Context, services *routers.Services, out io.Writer) error {
+		user, err := services.Users.PromoteUser(ctx, operatorCaller, *username, *role)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "promoted %s to %s\n", user.Username, user.Role)
+		return nil
+	}, nil
+}
+
+// parsePurgeTasks reads purge-tasks --status --older-than
+func parsePurgeTasks(args []string, stderr io.Writer) (adminAction, error) {
+	flags := newFlagSet("purge-tasks", stderr)
+	status := flags.String("status", "", "status of the tasks to remove: pending, in_progress or completed (required)")
+	olderThan := flags.String("older-than", "", "only remove tasks not updated for this long, in days such as 90d or as a duration such as 12h (required)")
+	if err := parseFlags(flags, args); err != nil {
+		return nil, err
+	}
+	if *status == "" || *olderThan == "" {
+		return nil, errors.New("--status and --older-than are required")
+	}
+	if !Domain.IsValidStatus(*status) {
+		return nil, fmt.Errorf("invalid --status %q: must be one of pending, in_progress, completed", *status)
+	}
+	age, err := parseAge(*olderThan)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, services *routers.Services, out io.Writer) error {
+		purged, err := services.Tasks.PurgeTasksByStatus(ctx, operatorCaller, *status, age)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "purged %d %s tasks not updated for %s\n", purged, *status, *olderThan)
+		return nil
+	}, nil
+}
+
+// parseAge reads a positive age given in days, such as 90d, or as a Go duration such as 36h
+func parseAge(value string) (time.Duration, error) {
+	var age time.Duration
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q: must be a number of days such as 90d or a duration such as 12h", value)
+		}
+		age = time.Duration(n) * 24 * time.Hour
+	} else {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --older-than %q: must be a number of days such as 90d or a duration such as 12h", value)
+		}
+		age = parsed
+	}
+
+	if age <= 0 {
+		return 0, fmt.Errorf("invalid --older-than %q: must be positive", value)
+	}
+	return age, nil
+}
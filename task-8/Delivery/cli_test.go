@@ -0,0 +1,192 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task_manager/Delivery/routers"
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// openMemoryServices returns an open function that hands out the same in-memory services every time
+func openMemoryServices(t *testing.T) (*routers.Services, func() (*routers.Services, func(), error)) {
+	t.Helper()
+	services, err := routers.NewServices(nil, &routers.DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	require.NoError(t, err)
+	return services, func() (*routers.Services, func(), error) {
+		return services, func() {}, nil
+	}
+}
+
+// runCommand runs an admin command and returns its exit code, stdout and stderr
+func runCommand(open func() (*routers.Services, func(), error), args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := runAdminCommand(context.Background(), args[0], args[1:], open, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestSplitCommand(t *testing.T) {
+	testCases := []struct {
+		name            string
+		args            []string
+		expectedCommand string
+		expectedArgs    []string
+	}{
+		{"no arguments", nil, serveCommand, nil},
+		{"flags only", []string{"--memory"}, serveCommand, []string{"--memory"}},
+		{"command", []string{"serve", "--memory"}, serveCommand, []string{"--memory"}},
+		{"admin command", []string{"promote", "--username", "alice"}, "promote", []string{"--username", "alice"}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act
+			command, args := splitCommand(tc.args)
+
+			// Assert
+			assert.Equal(t, tc.expectedCommand, command)
+			assert.Equal(t, tc.expectedArgs, args)
+		})
+	}
+}
+
+func TestParseAge(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected time.Duration
+		err      string
+	}{
+		{value: "90d", expected: 90 * 24 * time.Hour},
+		{value: "12h", expected: 12 * time.Hour},
+		{value: "1d12h", err: `invalid --older-than "1d12h": must be a number of days such as 90d or a duration such as 12h`},
+		{value: "soon", err: `invalid --older-than "soon": must be a number of days such as 90d or a duration such as 12h`},
+		{value: "0d", err: `invalid --older-than "0d": must be positive`},
+		{value: "-5m", err: `invalid --older-than "-5m": must be positive`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			// Act
+			age, err := parseAge(tc.value)
+
+			// Assert
+			if tc.err != "" {
+				assert.EqualError(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, age)
+		})
+	}
+}
+
+func TestRunAdminCommand(t *testing.T) {
+	t.Run("Success - create an admin, list users and promote one", func(t *testing.T) {
+		// Arrange
+		services, open := openMemoryServices(t)
+
+		// Act
+		createCode, createOut, _ := runCommand(open, "create-admin", "--username", "root", "--password", "Root-Passw0rd!")
+		_, err := services.Users.RegisterUser(context.Background(), Domain.UserRequest{Username: "bob", Password: "Bob-Passw0rd!"})
+		require.NoError(t, err)
+		promoteCode, promoteOut, _ := runCommand(open, "promote", "--username", "bob", "--role", "manager")
+		listCode, listOut, _ := runCommand(open, "list-users")
+		adminsCode, adminsOut, _ := runCommand(open, "list-users", "--role", "admin")
+
+		// Assert
+		assert.Equal(t, 0, createCode)
+		assert.Contains(t, createOut, "created admin root (")
+		assert.Equal(t, 0, promoteCode)
+		assert.Equal(t, "promoted bob to manager\n", promoteOut)
+		assert.Equal(t, 0, listCode)
+		assert.Contains(t, listOut, "USERNAME")
+		assert.Regexp(t, `bob\s+manager`, listOut)
+		assert.Regexp(t, `root\s+admin`, listOut)
+		assert.Equal(t, 0, adminsCode)
+		assert.Contains(t, adminsOut, "root")
+		assert.NotContains(t, adminsOut, "bob")
+	})
+
+	t.Run("Success - purge tasks", func(t *testing.T) {
+		// Arrange
+		services, open := openMemoryServices(t)
+		_, err := services.Tasks.CreateTask(context.Background(), Domain.Caller{UserID: "507f1f77bcf86cd799439011", Role: Domain.RoleAdmin}, Domain.TaskRequest{Title: "Recent", Status: Domain.StatusPending, DueDate: "2030-01-01"}, nil)
+		require.NoError(t, err)
+
+		// Act
+		code, stdout, _ := runCommand(open, "purge-tasks", "--status", "pending", "--older-than", "90d")
+
+		// Assert: the task was updated just now, so it stays
+		assert.Equal(t, 0, code)
+		assert.Equal(t, "purged 0 pending tasks not updated for 90d\n", stdout)
+		count, err := services.Tasks.CountTasks(context.Background(), Domain.Caller{Role: Domain.RoleAdmin}, Domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Error - invalid usage exits with 2 without opening the database", func(t *testing.T) {
+		testCases := []struct {
+			name   string
+			args   []string
+			stderr string
+		}{
+			{"unknown command", []string{"frobnicate"}, `unknown command "frobnicate"`},
+			{"missing password", []string{"create-admin", "--username", "root"}, "create-admin: --username and --password are required"},
+			{"unknown flag", []string{"promote", "--user", "bob"}, "flag provided but not defined: -user"},
+			{"stray argument", []string{"list-users", "bob"}, `list-users: unexpected argument "bob"`},
+			{"invalid role", []string{"list-users", "--role", "owner"}, `list-users: invalid --role "owner"`},
+			{"invalid status", []string{"purge-tasks", "--status", "done", "--older-than", "90d"}, `purge-tasks: invalid --status "done"`},
+			{"invalid age", []string{"purge-tasks", "--status", "completed", "--older-than", "ninety"}, `purge-tasks: invalid --older-than "ninety"`},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Arrange
+				opened := false
+				open := func() (*routers.Services, func(), error) {
+					opened = true
+					return nil, nil, errors.New("should not be called")
+				}
+
+				// Act
+				code, _, stderr := runCommand(open, tc.args...)
+
+				// Assert
+				assert.Equal(t, 2, code)
+				assert.Contains(t, stderr, tc.stderr)
+				assert.False(t, opened)
+			})
+		}
+	})
+
+	t.Run("Error - database unavailable", func(t *testing.T) {
+		// Arrange
+		open := func() (*routers.Services, func(), error) {
+			return nil, nil, errors.New("failed to ping MongoDB: connection refused")
+		}
+
+		// Act
+		code, _, stderr := runCommand(open, "list-users")
+
+		// Assert
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "list-users: failed to ping MongoDB: connection refused\n", stderr)
+	})
+
+	t.Run("Error - command fails", func(t *testing.T) {
+		// Arrange
+		_, open := openMemoryServices(t)
+
+		// Act
+		code, stdout, stderr := runCommand(open, "promote", "--username", "nobody")
+
+		// Assert
+		assert.Equal(t, 1, code)
+		assert.Empty(t, stdout)
+		assert.Equal(t, "promote: user not found\n", stderr)
+	})
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"task_manager/Domain"
+)
+
+func init() {
+	// Report fields by their JSON names, which is what clients send
+	if validate, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		validate.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+// jsonFieldName returns the name a struct field has in JSON, or its Go name when it has no json tag
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	if name == "" {
+		return field.Name
+	}
+	return name
+}
+
+// bindJSON decodes the request body into obj and validates it. On failure it writes a 400 listing
+// what was wrong with each field and returns false. In strict mode fields obj does not have are
+// rejected instead of ignored.
+func (ctrl *Controller) bindJSON(c *gin.Context, obj any) bool {
+	var err error
+	if ctrl.strictJSON {
+		err = decodeStrictJSON(c.Request, obj)
+	} else {
+		err = c.ShouldBindJSON(obj)
+	}
+	if err == nil {
+		return true
+	}
+
+	errorResponse := Domain.ErrorResponse{
+		Success:   false,
+		MessageID: Domain.MsgInvalidRequestPayload,
+		Error:     err.Error(),
+	}
+	if fieldErrors := translateBindingError(err); len(fieldErrors) > 0 {
+		messages := make([]string, len(fieldErrors))
+		for i, fieldError := range fieldErrors {
+			messages[i] = fieldError.Message
+		}
+		errorResponse.Error = strings.Join(messages, "; ")
+		errorResponse.Errors = fieldErrors
+	}
+	ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+	return false
+}
+
+// decodeStrictJSON binds like gin's JSON binding but fails on fields obj does not have. When those
+// are top-level fields, the error is an *unknownFieldsError naming every one of them.
+func decodeStrictJSON(req *http.Request, obj any) error {
+	if req == nil || req.Body == nil {
+		return errors.New("invalid request")
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		if strings.HasPrefix(err.Error(), "json: unknown field ") {
+			if unknown := unknownFields(body, obj); len(unknown) > 0 {
+				return &unknownFieldsError{fields: unknown}
+			}
+		}
+		return err
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
+
+// unknownFieldsError names the fields of a request body that the request type does not have
+type unknownFieldsError struct {
+	fields []string
+}
+
+func (e *unknownFieldsError) Error() string {
+	quoted := make([]string, len(e.fields))
+	for i, field := range e.fields {
+		quoted[i] = fmt.Sprintf("%q", field)
+	}
+	return "unknown fields " + strings.Join(quoted, ", ")
+}
+
+// unknownFields returns, sorted, the top-level keys of a JSON object body that match no field of
+// obj. Like the JSON decoder, it matches field names regardless of case.
+func unknownFields(body []byte, obj any) []string {
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(body, &keys); err != nil {
+		return nil
+	}
+	t := reflect.TypeOf(obj)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	known := knownFields(t)
+	var unknown []string
+	for key := range keys {
+		found := false
+		for _, name := range known {
+			if strings.EqualFold(key, name) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// knownFields returns the JSON names of the fields of a struct type, including those of embedded structs
+func knownFields(t reflect.Type) []string {
+	var names []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Tag.Get("json") == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				names = append(names, knownFields(embedded)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name := jsonFieldName(field); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// translateBindingError describes each invalid field in a binding error.
+// It returns nil for errors that are not about particular fields, such as malformed JSON.
+func translateBindingError(err error) []Domain.FieldError {
+	var unknownErr *unknownFieldsError
+	if errors.As(err, &unknownErr) {
+		fieldErrors := make([]Domain.FieldError, len(unknownErr.fields))
+		for i, field := range unknownErr.fields {
+			fieldErrors[i] = Domain.FieldError{
+				Field:   field,
+				Rule:    "unknown",
+				Message: fmt.Sprintf("unknown field %q", field),
+			}
+		}
+		return fieldErrors
+	}
+
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		fieldErrors := make([]Domain.FieldError, 0, len(validationErrors))
+		for _, validationError := range validationErrors {
+			fieldErrors = append(fieldErrors, Domain.FieldError{
+				Field:   validationError.Field(),
+				Rule:    validationError.Tag(),
+				Message: validationMessage(validationError),
+			})
+		}
+		return fieldErrors
+	}
+
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) && typeError.Field != "" {
+		return []Domain.FieldError{{
+			Field:   typeError.Field,
+			Rule:    "type",
+			Message: fmt.Sprintf("%s must be %s", typeError.Field, jsonTypeName(typeError.Type)),
+		}}
+	}
+	return nil
+}
+
+// validationMessage phrases a failed validation rule for the client
+func validationMessage(fieldError validator.FieldError) string {
+	field := fieldError.Field()
+	switch fieldError.Tag() {
+	case "required":
+		return field + " is required"
+	case "email":
+		return field + " must be a valid email address"
+	case "oneof":
+		return fmt.Sprintf("%s must be one of: %s", field, strings.ReplaceAll(fieldError.Param(), " ", ", "))
+	case "min":
+		return fmt.Sprintf("%s must be at least %s characters", field, fieldError.Param())
+	case "max":
+		return fmt.Sprintf("%s must be at most %s characters", field, fieldError.Param())
+	default:
+		return fmt.Sprintf("%s failed the %s rule", field, fieldError.Tag())
+	}
+}
+
+// jsonTypeName names, with an article, the JSON type that decodes into a Go type
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Pointer:
+		return jsonTypeName(t.Elem())
+	default:
+		return "an object"
+	}
+}
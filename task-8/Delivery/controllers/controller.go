@@ -1,24 +1,89 @@
 package controllers
 
 import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"mime"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"task_manager/Domain"
 	"task_manager/Usecases"
 )
 
+// DefaultMaxPageLimit is the largest page size accepted when TASKS_MAX_PAGE_LIMIT is not set
+const DefaultMaxPageLimit int64 = 100
+
+// DefaultUserPageSize is the page size of GET /users when page_size is not given
+const DefaultUserPageSize int64 = 20
+
+// MaxUserSearchLength bounds the q parameter of GET /users
+const MaxUserSearchLength = 100
+
+// DefaultPurgeOlderThanDays is used when DELETE /tasks/trash is called without older_than_days
+const DefaultPurgeOlderThanDays = 30
+
+// StatusClientClosedRequest is the status logged for requests the client abandoned before a response
+const StatusClientClosedRequest = 499
+
 // Controller handles HTTP requests for both task and user operations
 type Controller struct {
-	taskUsecase Usecases.TaskUsecaseInterface
-	userUsecase Usecases.UserUsecaseInterface
+	taskUsecase          Usecases.TaskUsecaseInterface
+	userUsecase          Usecases.UserUsecaseInterface
+	commentUsecase       Usecases.CommentUsecaseInterface
+	attachmentUsecase    Usecases.AttachmentUsecaseInterface
+	workLogUsecase       Usecases.WorkLogUsecaseInterface
+	auditUsecase         Usecases.AuditUsecaseInterface
+	passwordResetUsecase Usecases.PasswordResetUsecaseInterface
+	apiKeyUsecase        Usecases.APIKeyUsecaseInterface
+	webhookUsecase       Usecases.WebhookUsecaseInterface
+	reportUsecase        Usecases.ReportUsecaseInterface
+	maxPageLimit         int64
+	strictJSON           bool // Reject request bodies with fields the request type does not have
+	defaultLocation      *time.Location
+	apiBasePath          string // Path clients reach /api/v1 under, for the Location of created resources
+	logger               *slog.Logger
 }
 
-// NewController creates a new instance of Controller
-func NewController(taskUsecase Usecases.TaskUsecaseInterface, userUsecase Usecases.UserUsecaseInterface) *Controller {
+// NewController creates a new instance of Controller. apiBasePath is the path clients reach the
+// /api/v1 routes under, such as /api/v1 or, behind a proxy serving the API under /tasks, /tasks/api/v1.
+func NewController(taskUsecase Usecases.TaskUsecaseInterface, userUsecase Usecases.UserUsecaseInterface, commentUsecase Usecases.CommentUsecaseInterface, attachmentUsecase Usecases.AttachmentUsecaseInterface, workLogUsecase Usecases.WorkLogUsecaseInterface, auditUsecase Usecases.AuditUsecaseInterface, passwordResetUsecase Usecases.PasswordResetUsecaseInterface, apiKeyUsecase Usecases.APIKeyUsecaseInterface, webhookUsecase Usecases.WebhookUsecaseInterface, reportUsecase Usecases.ReportUsecaseInterface, apiBasePath string, logger *slog.Logger) *Controller {
+	maxPageLimit := DefaultMaxPageLimit
+	if value := os.Getenv("TASKS_MAX_PAGE_LIMIT"); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			maxPageLimit = parsed
+		}
+	}
+	strictJSON, _ := strconv.ParseBool(os.Getenv("STRICT_JSON"))
+
 	return &Controller{
-		taskUsecase: taskUsecase,
-		userUsecase: userUsecase,
+		taskUsecase:          taskUsecase,
+		userUsecase:          userUsecase,
+		commentUsecase:       commentUsecase,
+		attachmentUsecase:    attachmentUsecase,
+		workLogUsecase:       workLogUsecase,
+		auditUsecase:         auditUsecase,
+		passwordResetUsecase: passwordResetUsecase,
+		apiKeyUsecase:        apiKeyUsecase,
+		webhookUsecase:       webhookUsecase,
+		reportUsecase:        reportUsecase,
+		maxPageLimit:         maxPageLimit,
+		strictJSON:           strictJSON,
+		defaultLocation:      Usecases.DefaultLocation(),
+		apiBasePath:          apiBasePath,
+		logger:               logger,
 	}
 }
 
@@ -27,325 +92,3220 @@ func NewController(taskUsecase Usecases.TaskUsecaseInterface, userUsecase Usecas
 // Register handles POST /register
 func (ctrl *Controller) Register(c *gin.Context) {
 	var userReq Domain.UserRequest
-	
-	if err := c.ShouldBindJSON(&userReq); err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Invalid request payload",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+
+	if !ctrl.bindJSON(c, &userReq) {
 		return
 	}
 
-	user, err := ctrl.userUsecase.RegisterUser(userReq)
+	user, err := ctrl.userUsecase.RegisterUser(c.Request.Context(), userReq)
 	if err != nil {
 		statusCode := http.StatusBadRequest
-		if err.Error() == "username already exists" {
+		if errors.Is(err, Domain.ErrUsernameTaken) || errors.Is(err, Domain.ErrEmailTaken) {
 			statusCode = http.StatusConflict
 		}
-		
+
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to create user",
-			Error:   err.Error(),
+			Success:    false,
+			MessageID:  Domain.MsgCreateUserFailed,
+			Error:      err.Error(),
+			Violations: passwordPolicyViolations(err),
 		}
-		c.JSON(statusCode, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
 		return
 	}
 
-	response := Domain.UserResponse{
-		Success: true,
-		Message: "User registered successfully",
-		Data:    user,
-	}
-	
+	// The user is copied so its links never reach the event subscribers sharing it
+	registered := *user
+	registered.Links = ctrl.setLocation(c, "users", user.ID.Hex())
+	response := Domain.NewResponse(localize(c, Domain.MsgUserRegistered), &registered)
+
 	c.JSON(http.StatusCreated, response)
 }
 
 // Login handles POST /login
 func (ctrl *Controller) Login(c *gin.Context) {
 	var loginReq Domain.LoginRequest
-	
-	if err := c.ShouldBindJSON(&loginReq); err != nil {
-		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Invalid request payload",
-			Error:   err.Error(),
-		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+
+	if !ctrl.bindJSON(c, &loginReq) {
 		return
 	}
 
-	user, token, err := ctrl.userUsecase.LoginUser(loginReq)
+	loginReq.IP = c.ClientIP()
+	loginReq.UserAgent = c.Request.UserAgent()
+
+	user, tokens, err := ctrl.userUsecase.LoginUser(c.Request.Context(), loginReq)
 	if err != nil {
+		statusCode := http.StatusUnauthorized
+		if errors.Is(err, Domain.ErrAccountDeactivated) {
+			statusCode = http.StatusForbidden
+		}
+
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Authentication failed",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgAuthenticationFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusUnauthorized, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
 		return
 	}
 
+	message := localize(c, Domain.MsgLoggedIn)
 	response := Domain.LoginResponse{
-		Success: true,
-		Message: "Login successful",
-		Token:   token,
-		User:    user,
+		Success:      true,
+		Message:      message.Text,
+		MessageID:    message.ID,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
 	}
-	
+
 	c.JSON(http.StatusOK, response)
 }
 
-// PromoteUser handles POST /promote (admin only)
-func (ctrl *Controller) PromoteUser(c *gin.Context) {
-	var promoteReq Domain.PromoteRequest
-	
-	if err := c.ShouldBindJSON(&promoteReq); err != nil {
+// RefreshToken handles POST /auth/refresh, exchanging a refresh token for a new token pair
+func (ctrl *Controller) RefreshToken(c *gin.Context) {
+	var refreshReq Domain.RefreshRequest
+
+	if err := c.ShouldBindJSON(&refreshReq); err != nil {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Invalid request payload",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
 		return
 	}
 
-	user, err := ctrl.userUsecase.PromoteUserToAdmin(promoteReq.Username)
+	user, tokens, err := ctrl.userUsecase.RefreshTokens(c.Request.Context(), refreshReq.RefreshToken)
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		if err.Error() == "user not found" {
-			statusCode = http.StatusNotFound
+		status := http.StatusInternalServerError
+		messageID := Domain.MsgRefreshTokenFailed
+		switch {
+		case errors.Is(err, Domain.ErrInvalidRefreshToken), errors.Is(err, Domain.ErrRefreshTokenExpired), errors.Is(err, Domain.ErrRefreshTokenReused):
+			status = http.StatusUnauthorized
+			messageID = Domain.MsgAuthenticationFailed
+		case errors.Is(err, Domain.ErrAccountDeactivated):
+			status = http.StatusForbidden
+			messageID = Domain.MsgAuthenticationFailed
 		}
-		
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to promote user",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: messageID,
+			Error:     err.Error(),
 		}
-		c.JSON(statusCode, errorResponse)
+		ctrl.respondError(c, status, errorResponse)
 		return
 	}
 
-	response := Domain.UserResponse{
-		Success: true,
-		Message: "User promoted to admin successfully",
-		Data:    user,
+	message := localize(c, Domain.MsgTokenRefreshed)
+	response := Domain.LoginResponse{
+		Success:      true,
+		Message:      message.Text,
+		MessageID:    message.ID,
+		Token:        tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		ExpiresIn:    tokens.ExpiresIn,
+		User:         user,
 	}
-	
+
 	c.JSON(http.StatusOK, response)
 }
 
-// GetAllUsers handles GET /users (admin only)
-func (ctrl *Controller) GetAllUsers(c *gin.Context) {
-	users, err := ctrl.userUsecase.GetAllUsers()
-	if err != nil {
+// ForgotPassword handles POST /auth/forgot-password. The response is the same whether
+// or not the username exists, so it cannot be used to discover accounts.
+func (ctrl *Controller) ForgotPassword(c *gin.Context) {
+	var forgotReq Domain.ForgotPasswordRequest
+
+	if err := c.ShouldBindJSON(&forgotReq); err != nil {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve users",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusInternalServerError, errorResponse)
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
 		return
 	}
-	
-	response := Domain.UserResponse{
-		Success: true,
-		Message: "Users retrieved successfully",
-		Data:    users,
+
+	if err := ctrl.passwordResetUsecase.RequestReset(c.Request.Context(), forgotReq.Username); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRequestPasswordResetFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgPasswordResetRequested))
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// ResetPassword handles POST /auth/reset-password. Expired and already used tokens
+// answer 410 Gone so clients can tell them apart from a mistyped token.
+func (ctrl *Controller) ResetPassword(c *gin.Context) {
+	var resetReq Domain.ResetPasswordRequest
+
+	if err := c.ShouldBindJSON(&resetReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	if err := ctrl.passwordResetUsecase.ResetPassword(c.Request.Context(), resetReq.Token, resetReq.NewPassword); err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrInvalidResetToken):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrResetTokenExpired), errors.Is(err, Domain.ErrResetTokenUsed):
+			statusCode = http.StatusGone
+		}
+		violations := passwordPolicyViolations(err)
+		if violations != nil {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:    false,
+			MessageID:  Domain.MsgResetPasswordFailed,
+			Error:      err.Error(),
+			Violations: violations,
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
 	}
-	
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgPasswordReset))
+
 	c.JSON(http.StatusOK, response)
 }
 
-// GetProfile handles GET /profile (authenticated users)
-func (ctrl *Controller) GetProfile(c *gin.Context) {
-	userID, exists := c.Get("user_id")
-	if !exists {
+// VerifyEmail handles POST /auth/verify-email. Expired tokens answer 410 Gone so clients
+// can offer to send a new one.
+func (ctrl *Controller) VerifyEmail(c *gin.Context) {
+	var verifyReq Domain.VerifyEmailRequest
+
+	if err := c.ShouldBindJSON(&verifyReq); err != nil {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "User ID not found in token",
-			Error:   "Authentication required",
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusUnauthorized, errorResponse)
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
 		return
 	}
 
-	user, err := ctrl.userUsecase.GetUserProfile(userID.(string))
+	user, err := ctrl.userUsecase.VerifyEmail(c.Request.Context(), verifyReq.Token)
 	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrInvalidVerificationToken):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrVerificationTokenExpired):
+			statusCode = http.StatusGone
+		}
+
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve user profile",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgVerifyEmailFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusNotFound, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
 		return
 	}
 
-	response := Domain.UserResponse{
-		Success: true,
-		Message: "Profile retrieved successfully",
-		Data:    user,
-	}
-	
+	response := Domain.NewResponse(localize(c, Domain.MsgEmailVerified), user)
+
 	c.JSON(http.StatusOK, response)
 }
 
-// Task-related handlers
+// Logout handles POST /auth/logout, revoking the caller's access token and, if sent, their refresh token
+func (ctrl *Controller) Logout(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
 
-// GetAllTasks handles GET /tasks
-func (ctrl *Controller) GetAllTasks(c *gin.Context) {
-	tasks, err := ctrl.taskUsecase.GetAllTasks()
-	if err != nil {
+	tokenID := c.GetString("token_id")
+	expiresAt := c.GetTime("token_expires_at")
+	if tokenID == "" || expiresAt.IsZero() {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to retrieve tasks",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgTokenNotRevocable,
+			Error:     "Token has no ID or expiry; log in again to get a revocable token",
 		}
-		c.JSON(http.StatusInternalServerError, errorResponse)
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
 		return
 	}
-	
-	response := Domain.TaskResponse{
-		Success: true,
-		Message: "Tasks retrieved successfully",
-		Data:    tasks,
+
+	// The body is optional; an empty one only revokes the access token
+	var logoutReq Domain.LogoutRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&logoutReq); err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidRequestPayload,
+				Error:     err.Error(),
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+	}
+
+	if err := ctrl.userUsecase.Logout(c.Request.Context(), caller, tokenID, expiresAt, logoutReq.RefreshToken); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgLogoutFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
 	}
-	
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgLoggedOut))
+
 	c.JSON(http.StatusOK, response)
 }
 
-// GetTaskByID handles GET /tasks/:id
-func (ctrl *Controller) GetTaskByID(c *gin.Context) {
-	id := c.Param("id")
+// GetLoginHistory handles GET /users/me/logins, listing the caller's most recent logins
+func (ctrl *Controller) GetLoginHistory(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
 
-	task, err := ctrl.taskUsecase.GetTaskByID(id)
+	events, err := ctrl.userUsecase.GetLoginHistory(c.Request.Context(), caller)
 	if err != nil {
-		statusCode := http.StatusNotFound
-		if err.Error() == "invalid task ID format" {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidUserID) {
 			statusCode = http.StatusBadRequest
 		}
-		
+
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Task not found",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgRetrieveLoginHistoryFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(statusCode, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgLoginHistoryRetrieved), events, Domain.PaginationMeta{Total: int64(len(events))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PromoteUser handles POST /promote (admin only)
+func (ctrl *Controller) PromoteUser(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
 		return
 	}
 
-	response := Domain.TaskResponse{
-		Success: true,
-		Message: "Task retrieved successfully",
-		Data:    task,
+	var promoteReq Domain.PromoteRequest
+
+	if !ctrl.bindJSON(c, &promoteReq) {
+		return
+	}
+
+	user, err := ctrl.userUsecase.PromoteUser(c.Request.Context(), caller, promoteReq.Username, promoteReq.Role)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, Domain.ErrUserNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgPromoteUserFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
 	}
-	
+
+	response := Domain.NewResponse(localize(c, Domain.MsgUserPromoted, user.Role), user)
+
 	c.JSON(http.StatusOK, response)
 }
 
-// CreateTask handles POST /tasks (admin only)
-func (ctrl *Controller) CreateTask(c *gin.Context) {
-	var taskReq Domain.TaskRequest
-	
-	if err := c.ShouldBindJSON(&taskReq); err != nil {
+// DemoteUser handles POST /demote (admin only)
+func (ctrl *Controller) DemoteUser(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var demoteReq Domain.DemoteRequest
+
+	if err := c.ShouldBindJSON(&demoteReq); err != nil {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Invalid request payload",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
 		return
 	}
 
-	task, err := ctrl.taskUsecase.CreateTask(taskReq)
+	user, err := ctrl.userUsecase.DemoteAdminToUser(c.Request.Context(), caller, demoteReq.Username)
 	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case errors.Is(err, Domain.ErrUserNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrSelfDemotion), errors.Is(err, Domain.ErrLastAdminDemotion):
+			statusCode = http.StatusConflict
+		}
+
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to create task",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgDemoteUserFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
 		return
 	}
 
-	response := Domain.TaskResponse{
-		Success: true,
-		Message: "Task created successfully",
-		Data:    task,
-	}
-	
-	c.JSON(http.StatusCreated, response)
+	response := Domain.NewResponse(localize(c, Domain.MsgUserDemoted), user)
+
+	c.JSON(http.StatusOK, response)
 }
 
-// UpdateTask handles PUT /tasks/:id (admin only)
-func (ctrl *Controller) UpdateTask(c *gin.Context) {
-	id := c.Param("id")
+// DeleteUser handles DELETE /users/:id (admin only). With ?anonymize=true the user's personal
+// data is scrubbed but the record is kept; otherwise the user is removed and their tasks reassigned.
+func (ctrl *Controller) DeleteUser(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	anonymize := false
+	if value := c.Query("anonymize"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidQueryParameters,
+				Error:     "invalid anonymize, must be true or false",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		anonymize = parsed
+	}
+
+	result, err := ctrl.userUsecase.DeleteUser(c.Request.Context(), caller, c.Param("id"), anonymize)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrInvalidUserID):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrUserNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrSelfDeletion), errors.Is(err, Domain.ErrLastAdminDeletion), errors.Is(err, Domain.ErrReassignToAPIKey):
+			statusCode = http.StatusConflict
+		}
 
-	var taskReq Domain.TaskRequest
-	if err := c.ShouldBindJSON(&taskReq); err != nil {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Invalid request payload",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgDeleteUserFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(http.StatusBadRequest, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	messageID := Domain.MsgUserDeleted
+	if result.Anonymized {
+		messageID = Domain.MsgUserAnonymized
+	}
+
+	response := Domain.NewResponse(localize(c, messageID), result)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeactivateUser handles POST /users/:id/deactivate (admin only)
+func (ctrl *Controller) DeactivateUser(c *gin.Context) {
+	ctrl.setUserActive(c, false)
+}
+
+// ActivateUser handles POST /users/:id/activate (admin only)
+func (ctrl *Controller) ActivateUser(c *gin.Context) {
+	ctrl.setUserActive(c, true)
+}
+
+// setUserActive serves DeactivateUser and ActivateUser, which differ only in the usecase they call
+func (ctrl *Controller) setUserActive(c *gin.Context, active bool) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
 		return
 	}
 
-	task, err := ctrl.taskUsecase.UpdateTask(id, taskReq)
+	update, failedID, doneID := ctrl.userUsecase.DeactivateUser, Domain.MsgDeactivateUserFailed, Domain.MsgUserDeactivated
+	if active {
+		update, failedID, doneID = ctrl.userUsecase.ActivateUser, Domain.MsgActivateUserFailed, Domain.MsgUserActivated
+	}
+
+	user, err := update(c.Request.Context(), caller, c.Param("id"))
 	if err != nil {
-		statusCode := http.StatusBadRequest
-		if err.Error() == "task not found" {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrInvalidUserID):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrUserNotFound):
 			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrSelfDeactivation):
+			statusCode = http.StatusConflict
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: failedID,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, doneID), user)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateAPIKey handles POST /api-keys (admin only). The key is in the response exactly once.
+func (ctrl *Controller) CreateAPIKey(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var keyReq Domain.APIKeyRequest
+	if err := c.ShouldBindJSON(&keyReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
 		}
-		if err.Error() == "invalid task ID format" {
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	created, err := ctrl.apiKeyUsecase.CreateAPIKey(c.Request.Context(), caller, keyReq)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidInput) {
 			statusCode = http.StatusBadRequest
 		}
-		
+
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to update task",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgCreateAPIKeyFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(statusCode, errorResponse)
+		ctrl.respondError(c, statusCode, errorResponse)
 		return
 	}
 
-	response := Domain.TaskResponse{
-		Success: true,
-		Message: "Task updated successfully",
-		Data:    task,
+	response := Domain.NewResponse(localize(c, Domain.MsgAPIKeyCreated), created)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetAPIKeys handles GET /api-keys (admin only)
+func (ctrl *Controller) GetAPIKeys(c *gin.Context) {
+	keys, err := ctrl.apiKeyUsecase.ListAPIKeys(c.Request.Context())
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveAPIKeysFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
 	}
-	
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgAPIKeysRetrieved), keys, Domain.PaginationMeta{Total: int64(len(keys))})
+
 	c.JSON(http.StatusOK, response)
 }
 
-// DeleteTask handles DELETE /tasks/:id (admin only)
-func (ctrl *Controller) DeleteTask(c *gin.Context) {
-	id := c.Param("id")
+// RevokeAPIKey handles DELETE /api-keys/:id (admin only)
+func (ctrl *Controller) RevokeAPIKey(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
 
-	err := ctrl.taskUsecase.DeleteTask(id)
+	key, err := ctrl.apiKeyUsecase.RevokeAPIKey(c.Request.Context(), caller, c.Param("id"))
 	if err != nil {
-		statusCode := http.StatusNotFound
-		if err.Error() == "invalid task ID format" {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrInvalidAPIKeyID):
 			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrAPIKeyNotFound):
+			statusCode = http.StatusNotFound
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRevokeAPIKeyFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgAPIKeyRevoked), key)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Webhook handlers
+
+// CreateWebhook handles POST /webhooks (admin only). The signing secret is in the response exactly once.
+func (ctrl *Controller) CreateWebhook(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var webhookReq Domain.WebhookRequest
+	if !ctrl.bindJSON(c, &webhookReq) {
+		return
+	}
+
+	created, err := ctrl.webhookUsecase.CreateWebhook(c.Request.Context(), caller, webhookReq)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgCreateWebhookFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, webhookErrorStatus(err), errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgWebhookCreated), created)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetWebhooks handles GET /webhooks (admin only)
+func (ctrl *Controller) GetWebhooks(c *gin.Context) {
+	webhooks, err := ctrl.webhookUsecase.ListWebhooks(c.Request.Context())
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveWebhooksFailed,
+			Error:     err.Error(),
 		}
-		
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgWebhooksRetrieved), webhooks, Domain.PaginationMeta{Total: int64(len(webhooks))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWebhook handles GET /webhooks/:id (admin only)
+func (ctrl *Controller) GetWebhook(c *gin.Context) {
+	webhook, err := ctrl.webhookUsecase.GetWebhook(c.Request.Context(), c.Param("id"))
+	if err != nil {
 		errorResponse := Domain.ErrorResponse{
-			Success: false,
-			Message: "Failed to delete task",
-			Error:   err.Error(),
+			Success:   false,
+			MessageID: Domain.MsgRetrieveWebhookFailed,
+			Error:     err.Error(),
 		}
-		c.JSON(statusCode, errorResponse)
+		ctrl.respondError(c, webhookErrorStatus(err), errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgWebhookRetrieved), webhook)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateWebhook handles PUT /webhooks/:id (admin only). An empty secret keeps the current one.
+func (ctrl *Controller) UpdateWebhook(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var webhookReq Domain.WebhookRequest
+	if !ctrl.bindJSON(c, &webhookReq) {
 		return
 	}
 
-	response := Domain.TaskResponse{
-		Success: true,
-		Message: "Task deleted successfully",
+	webhook, err := ctrl.webhookUsecase.UpdateWebhook(c.Request.Context(), caller, c.Param("id"), webhookReq)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUpdateWebhookFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, webhookErrorStatus(err), errorResponse)
+		return
 	}
-	
+
+	response := Domain.NewResponse(localize(c, Domain.MsgWebhookUpdated), webhook)
+
 	c.JSON(http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// DeleteWebhook handles DELETE /webhooks/:id (admin only)
+func (ctrl *Controller) DeleteWebhook(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	if err := ctrl.webhookUsecase.DeleteWebhook(c.Request.Context(), caller, c.Param("id")); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgDeleteWebhookFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, webhookErrorStatus(err), errorResponse)
+		return
+	}
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgWebhookDeleted))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWebhookDeliveries handles GET /webhooks/:id/deliveries?limit=&offset= (admin only), newest first
+func (ctrl *Controller) GetWebhookDeliveries(c *gin.Context) {
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	deliveries, total, err := ctrl.webhookUsecase.GetDeliveries(c.Request.Context(), c.Param("id"), pagination)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveWebhookDeliveriesFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, webhookErrorStatus(err), errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgWebhookDeliveriesRetrieved), deliveries, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// webhookErrorStatus maps a webhook usecase error to its HTTP status
+func webhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, Domain.ErrInvalidInput), errors.Is(err, Domain.ErrInvalidWebhookID), errors.Is(err, Domain.ErrInvalidPagination):
+		return http.StatusBadRequest
+	case errors.Is(err, Domain.ErrWebhookNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ChangePassword handles PUT /users/password
+func (ctrl *Controller) ChangePassword(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var changeReq Domain.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&changeReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	err := ctrl.userUsecase.ChangePassword(c.Request.Context(), caller, changeReq.CurrentPassword, changeReq.NewPassword)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrIncorrectPassword):
+			statusCode = http.StatusUnauthorized
+		case errors.Is(err, Domain.ErrUserNotFound):
+			statusCode = http.StatusNotFound
+		}
+		violations := passwordPolicyViolations(err)
+		if violations != nil {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:    false,
+			MessageID:  Domain.MsgChangePasswordFailed,
+			Error:      err.Error(),
+			Violations: violations,
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgPasswordChanged))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAllUsers handles GET /users?q=&role=&sort=&page=&page_size= (admin only)
+func (ctrl *Controller) GetAllUsers(c *gin.Context) {
+	filter, pagination, page, err := ctrl.parseUserListQuery(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidQueryParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	users, total, err := ctrl.userUsecase.GetAllUsers(c.Request.Context(), filter, pagination)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveUsersFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgUsersRetrieved), users, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+		Page:   page,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetUserDirectory handles GET /users/directory?q=&sort=&page=&page_size= (any role).
+// It lists only IDs and usernames, and q searches usernames alone so email addresses cannot be probed.
+func (ctrl *Controller) GetUserDirectory(c *gin.Context) {
+	filter, pagination, page, err := ctrl.parseUserListQuery(c)
+	if err == nil && filter.Role != "" {
+		err = errors.New("role is only accepted by the admin user list")
+	}
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidQueryParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	entries, total, err := ctrl.userUsecase.GetUserDirectory(c.Request.Context(), filter.Query, pagination)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveUsersFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgUsersRetrieved), entries, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+		Page:   page,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseUserListQuery reads the q, role, sort, page and page_size query parameters of GET /users.
+// Pages are numbered from 1; a page past the last one is valid and simply empty.
+func (ctrl *Controller) parseUserListQuery(c *gin.Context) (Domain.UserFilter, Domain.Pagination, int64, error) {
+	pagination := Domain.Pagination{Limit: DefaultUserPageSize}
+	page := int64(1)
+
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		return Domain.UserFilter{}, Domain.Pagination{}, 0, err
+	}
+
+	if sort, ok := c.GetQuery("sort"); ok {
+		if sort != Domain.SortCreatedAt && sort != Domain.SortUsername {
+			return Domain.UserFilter{}, Domain.Pagination{}, 0, errors.New("invalid sort, must be one of: created_at, username")
+		}
+		pagination.Sort = sort
+	}
+
+	if value, ok := c.GetQuery("page_size"); ok {
+		pageSize, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || pageSize <= 0 {
+			return Domain.UserFilter{}, Domain.Pagination{}, 0, errors.New("page_size must be a positive integer")
+		}
+		if pageSize > ctrl.maxPageLimit {
+			return Domain.UserFilter{}, Domain.Pagination{}, 0, fmt.Errorf("page_size must not exceed %d", ctrl.maxPageLimit)
+		}
+		pagination.Limit = pageSize
+	}
+
+	if value, ok := c.GetQuery("page"); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		// The second check keeps the offset from overflowing
+		if err != nil || parsed <= 0 || parsed-1 > math.MaxInt64/pagination.Limit {
+			return Domain.UserFilter{}, Domain.Pagination{}, 0, errors.New("page must be a positive integer")
+		}
+		page = parsed
+	}
+	pagination.Offset = (page - 1) * pagination.Limit
+
+	return filter, pagination, page, nil
+}
+
+// parseUserFilter reads the q and role query parameters shared by the user list and export
+func parseUserFilter(c *gin.Context) (Domain.UserFilter, error) {
+	var filter Domain.UserFilter
+
+	if value, ok := c.GetQuery("q"); ok {
+		filter.Query = strings.TrimSpace(value)
+		if utf8.RuneCountInString(filter.Query) > MaxUserSearchLength {
+			return Domain.UserFilter{}, fmt.Errorf("q must not exceed %d characters", MaxUserSearchLength)
+		}
+	}
+
+	if role, ok := c.GetQuery("role"); ok {
+		if !Domain.IsValidRole(role) {
+			return Domain.UserFilter{}, errors.New("invalid role, must be one of: user, manager, admin")
+		}
+		filter.Role = role
+	}
+
+	return filter, nil
+}
+
+// userExportColumns is the header row of the user CSV export. Password hashes are never exported.
+var userExportColumns = []string{"id", "username", "email", "role", "created_at", "last_login_at", "is_active"}
+
+// ExportUsers handles GET /users/export?q=&role= (admin only), streaming the matching users as CSV
+// under a filename dated today
+func (ctrl *Controller) ExportUsers(c *gin.Context) {
+	filter, err := parseUserFilter(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidQueryParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	started := false
+	csvWriter := csv.NewWriter(c.Writer)
+	start := func() error {
+		started = true
+		filename := "users-" + time.Now().In(ctrl.defaultLocation).Format("2006-01-02") + ".csv"
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		return csvWriter.Write(userExportColumns)
+	}
+
+	err = ctrl.userUsecase.ExportUsers(c.Request.Context(), filter, func(user *Domain.User) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+		return csvWriter.Write(userCSVRecord(user))
+	})
+	if err != nil {
+		if !started {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgExportUsersFailed,
+				Error:     err.Error(),
+			}
+			ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+			return
+		}
+		csvWriter.Flush()
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+
+	// An empty export still gets its header row
+	if !started {
+		if err := start(); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	csvWriter.Flush()
+}
+
+// userCSVRecord lays out a user in userExportColumns order; a user who never logged in has a blank last_login_at
+func userCSVRecord(user *Domain.User) []string {
+	lastLogin := ""
+	if user.LastLoginAt != nil {
+		lastLogin = formatExportTime(*user.LastLoginAt)
+	}
+	return []string{
+		user.ID.Hex(),
+		user.Username,
+		user.Email,
+		user.Role,
+		formatExportTime(user.CreatedAt),
+		lastLogin,
+		strconv.FormatBool(user.IsActive),
+	}
+}
+
+// GetProfile handles GET /profile (authenticated users)
+func (ctrl *Controller) GetProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgCallerNotFound,
+			Error:     "Authentication required",
+		}
+		ctrl.respondError(c, http.StatusUnauthorized, errorResponse)
+		return
+	}
+
+	user, err := ctrl.userUsecase.GetUserProfile(c.Request.Context(), userID.(string))
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveUserProfileFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusNotFound, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgProfileRetrieved), user)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateProfile handles PUT /users/profile, changing the caller's own username and email.
+// Role and password in the payload are ignored.
+func (ctrl *Controller) UpdateProfile(c *gin.Context) {
+	userID, exists := c.Get("user_id")
+	if !exists {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgCallerNotFound,
+			Error:     "Authentication required",
+		}
+		ctrl.respondError(c, http.StatusUnauthorized, errorResponse)
+		return
+	}
+
+	var profileReq Domain.ProfileUpdateRequest
+	if err := c.ShouldBindJSON(&profileReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	user, err := ctrl.userUsecase.UpdateProfile(c.Request.Context(), userID.(string), profileReq)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrUsernameTaken) || errors.Is(err, Domain.ErrEmailTaken):
+			statusCode = http.StatusConflict
+		case errors.Is(err, Domain.ErrUserNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidInput):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUpdateProfileFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgProfileUpdated), user)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// callerFromContext builds the caller identity from the values set by the auth middleware
+func callerFromContext(c *gin.Context) (Domain.Caller, bool) {
+	userID, ok := c.Get("user_id")
+	if !ok {
+		return Domain.Caller{}, false
+	}
+	userIDStr, ok := userID.(string)
+	if !ok || userIDStr == "" {
+		return Domain.Caller{}, false
+	}
+
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	_, viaAPIKey := c.Get("api_key_id")
+
+	return Domain.Caller{UserID: userIDStr, Role: roleStr, APIKey: viaAPIKey}, true
+}
+
+// isStatusTransitionError reports whether the usecase rejected a move between statuses
+func isStatusTransitionError(err error) bool {
+	return errors.Is(err, Domain.ErrInvalidStatusTransition)
+}
+
+// isVersionConflictError reports whether an update lost a race with another writer
+func isVersionConflictError(err error) bool {
+	return errors.Is(err, Domain.ErrVersionConflict)
+}
+
+// isOpenTaskLimitError reports whether a task was refused because its owner has too many open tasks
+func isOpenTaskLimitError(err error) bool {
+	return errors.Is(err, Domain.ErrOpenTaskLimit)
+}
+
+// isDueDateRangeError reports whether a due date was refused by the due date rules
+func isDueDateRangeError(err error) bool {
+	return errors.Is(err, Domain.ErrDueDateOutOfRange)
+}
+
+// ifMatchVersion reads the task version a client expects from the If-Match header.
+// It returns nil when the header is absent. Both a bare version and the task's ETag are accepted.
+func ifMatchVersion(c *gin.Context) (*int, error) {
+	value := c.GetHeader("If-Match")
+	if value == "" {
+		return nil, nil
+	}
+
+	value = strings.Trim(strings.TrimPrefix(value, "W/"), `"`)
+	value, _, _ = strings.Cut(value, "-")
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 0 {
+		return nil, errors.New("invalid If-Match header, must be the task version")
+	}
+	return &version, nil
+}
+
+// weakETag builds a weak ETag from a counter and a timestamp that both move whenever the resource changes
+func weakETag(counter int64, updatedAt time.Time) string {
+	return fmt.Sprintf(`W/"%d-%d"`, counter, updatedAt.UnixNano())
+}
+
+// taskETag tags a single task. Logging work does not change the task's version, so the
+// logged total, when loaded, is part of the tag too.
+func taskETag(task *Domain.Task) string {
+	if task.TotalLoggedMinutes == nil {
+		return weakETag(int64(task.Version), task.UpdatedAt)
+	}
+	return fmt.Sprintf(`W/"%d-%d-%d"`, task.Version, task.UpdatedAt.UnixNano(), *task.TotalLoggedMinutes)
+}
+
+// taskListETag keys a page of tasks on the total and the newest updated_at in the page,
+// so edits, additions and deletions all change it
+func taskListETag(tasks []*Domain.Task, total int64) string {
+	var newest time.Time
+	for _, task := range tasks {
+		if task.UpdatedAt.After(newest) {
+			newest = task.UpdatedAt
+		}
+	}
+	return weakETag(total, newest)
+}
+
+// etagMatches reports whether an If-None-Match header matches the ETag, using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	if strings.TrimSpace(ifNoneMatch) == "*" {
+		return true
+	}
+
+	target := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(candidate), "W/") == target {
+			return true
+		}
+	}
+	return false
+}
+
+// respondNotModified sets the ETag header and, when the client already has this version,
+// writes an empty 304. It reports whether the response is complete.
+func respondNotModified(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// isBulkValidationError reports whether a bulk request was rejected before reaching the database
+func isBulkValidationError(err error) bool {
+	return errors.Is(err, Domain.ErrInvalidInput)
+}
+
+// respondError writes an error response tagged with the request's ID, resolving its MessageID
+// in the request's language. Server errors are also logged, since the client only sees a generic message.
+// Once the client has gone away only StatusClientClosedRequest is recorded for the logs:
+// the failure is almost always the cancelled context itself, and no one is left to read a body.
+func (ctrl *Controller) respondError(c *gin.Context, status int, errorResponse Domain.ErrorResponse) {
+	if errors.Is(c.Request.Context().Err(), context.Canceled) {
+		ctrl.logger.InfoContext(c.Request.Context(), "client disconnected", "method", c.Request.Method, "path", c.Request.URL.Path)
+		c.Status(StatusClientClosedRequest)
+		c.Abort()
+		return
+	}
+	if status >= http.StatusInternalServerError {
+		// Logs stay in English whatever language the client reads
+		message := errorResponse.Message
+		if errorResponse.MessageID != "" {
+			message = Domain.Translate(Domain.DefaultLocale, errorResponse.MessageID)
+		}
+		ctrl.logger.ErrorContext(c.Request.Context(), message, "status", status, "error", errorResponse.Error)
+	}
+	if errorResponse.MessageID != "" {
+		errorResponse.Message = localize(c, errorResponse.MessageID).Text
+	}
+	errorResponse.RequestID = Domain.RequestIDFromContext(c.Request.Context())
+	c.JSON(status, errorResponse)
+}
+
+// localize resolves a catalog message in the language negotiated for the request
+func localize(c *gin.Context, id string, args ...interface{}) Domain.Message {
+	return Domain.LocalizeMessage(c.Request.Context(), id, args...)
+}
+
+// passwordPolicyViolations returns the failed rules when err is a password policy error, and nil otherwise
+func passwordPolicyViolations(err error) []Domain.PasswordRuleViolation {
+	var policyErr *Domain.PasswordPolicyError
+	if errors.As(err, &policyErr) {
+		return policyErr.Violations
+	}
+	return nil
+}
+
+// respondMissingCaller writes the 401 used when the auth middleware did not identify the caller
+func (ctrl *Controller) respondMissingCaller(c *gin.Context) {
+	errorResponse := Domain.ErrorResponse{
+		Success:   false,
+		MessageID: Domain.MsgCallerNotFound,
+		Error:     "Authentication required",
+	}
+	ctrl.respondError(c, http.StatusUnauthorized, errorResponse)
+}
+
+// setLocation points the Location header at a resource just created in collection and returns
+// the links to include with it
+func (ctrl *Controller) setLocation(c *gin.Context, collection, id string) *Domain.Links {
+	self := ctrl.apiBasePath + "/" + collection + "/" + id
+	c.Header("Location", self)
+	return &Domain.Links{Self: self}
+}
+
+// Task-related handlers
+
+// GetAllTasks handles GET /tasks?status=&priority=&tag=&due_before=&due_after=&include_deleted=&archived=&limit=&offset=&sort=&fields=
+func (ctrl *Controller) GetAllTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	filter, err := ctrl.parseTaskFilter(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidFilterParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Deleted tasks are only visible to those who manage the trash
+	if filter.IncludeDeleted && !caller.Can(Domain.PermissionTasksWrite) {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgAccessDenied,
+			Error:     "Permission " + Domain.PermissionTasksWrite + " required",
+		}
+		ctrl.respondError(c, http.StatusForbidden, errorResponse)
+		return
+	}
+
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	pagination.Fields, ok = ctrl.parseTaskFields(c)
+	if !ok {
+		return
+	}
+
+	tasks, total, err := ctrl.taskUsecase.GetAllTasks(c.Request.Context(), caller, filter, pagination)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	if respondNotModified(c, taskListETag(tasks, total)) {
+		return
+	}
+
+	meta := Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	}
+	if len(pagination.Fields) > 0 {
+		selected := make([]map[string]json.RawMessage, 0, len(tasks))
+		for _, task := range tasks {
+			fields, err := task.Select(pagination.Fields)
+			if err != nil {
+				ctrl.respondSelectFailed(c, err)
+				return
+			}
+			selected = append(selected, fields)
+		}
+		c.JSON(http.StatusOK, Domain.NewListResponse(localize(c, Domain.MsgTasksRetrieved), selected, meta))
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgTasksRetrieved), tasks, meta)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseTaskFields reads the fields query parameter, the comma-separated task fields a client
+// wants back. It returns nil when the parameter is absent, and responds 400 listing the valid
+// fields when it names an unknown one.
+func (ctrl *Controller) parseTaskFields(c *gin.Context) ([]string, bool) {
+	value, ok := c.GetQuery("fields")
+	if !ok {
+		return nil, true
+	}
+
+	fields, err := Domain.ParseTaskFields(value)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidFieldsParameter,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return nil, false
+	}
+	return fields, true
+}
+
+// respondSelectFailed responds 500 when a task could not be reduced to the selected fields
+func (ctrl *Controller) respondSelectFailed(c *gin.Context, err error) {
+	errorResponse := Domain.ErrorResponse{
+		Success:   false,
+		MessageID: Domain.MsgSelectTaskFieldsFailed,
+		Error:     err.Error(),
+	}
+	ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+}
+
+// parseTaskFilter reads the status, priority, tag, due_before, due_after, include_deleted and archived query parameters.
+// Dates use the same YYYY-MM-DD format as TaskRequest.DueDate and are read in the same default timezone:
+// due_after starts at the beginning of its day and due_before ends at the last second of its day, so
+// tasks due on either date are included.
+func (ctrl *Controller) parseTaskFilter(c *gin.Context) (Domain.TaskFilter, error) {
+	var filter Domain.TaskFilter
+
+	if status, ok := c.GetQuery("status"); ok {
+		if !Domain.IsValidStatus(status) {
+			return Domain.TaskFilter{}, Domain.ErrInvalidStatus
+		}
+		filter.Status = status
+	}
+
+	if value, ok := c.GetQuery("tag"); ok {
+		tag := strings.ToLower(strings.TrimSpace(value))
+		if tag == "" {
+			return Domain.TaskFilter{}, errors.New("tag cannot be empty")
+		}
+		filter.Tag = tag
+	}
+
+	if priority, ok := c.GetQuery("priority"); ok {
+		if !Domain.IsValidPriority(priority) {
+			return Domain.TaskFilter{}, Domain.ErrInvalidPriority
+		}
+		filter.Priority = priority
+	}
+
+	if value, ok := c.GetQuery("due_before"); ok {
+		dueBefore, err := time.ParseInLocation(Usecases.DueDateLayout, value, ctrl.defaultLocation)
+		if err != nil {
+			return Domain.TaskFilter{}, errors.New("invalid due_before format, use YYYY-MM-DD")
+		}
+		filter.DueBefore = dueBefore.AddDate(0, 0, 1).Add(-time.Second).UTC()
+	}
+
+	if value, ok := c.GetQuery("due_after"); ok {
+		dueAfter, err := time.ParseInLocation(Usecases.DueDateLayout, value, ctrl.defaultLocation)
+		if err != nil {
+			return Domain.TaskFilter{}, errors.New("invalid due_after format, use YYYY-MM-DD")
+		}
+		filter.DueAfter = dueAfter.UTC()
+	}
+
+	if !filter.DueBefore.IsZero() && !filter.DueAfter.IsZero() && filter.DueAfter.After(filter.DueBefore) {
+		return Domain.TaskFilter{}, errors.New("due_after must not be later than due_before")
+	}
+
+	if value, ok := c.GetQuery("include_deleted"); ok {
+		includeDeleted, err := strconv.ParseBool(value)
+		if err != nil {
+			return Domain.TaskFilter{}, errors.New("invalid include_deleted value, use true or false")
+		}
+		filter.IncludeDeleted = includeDeleted
+	}
+
+	if value, ok := c.GetQuery("archived"); ok {
+		archived, err := strconv.ParseBool(value)
+		if err != nil {
+			return Domain.TaskFilter{}, errors.New("invalid archived value, use true or false")
+		}
+		filter.OnlyArchived = archived
+	}
+
+	return filter, nil
+}
+
+// parsePagination reads the limit, offset and sort query parameters.
+// Omitting all of them returns the zero Pagination, which lists every task.
+func (ctrl *Controller) parsePagination(c *gin.Context) (Domain.Pagination, error) {
+	var pagination Domain.Pagination
+
+	if value, ok := c.GetQuery("limit"); ok {
+		limit, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || limit <= 0 {
+			return Domain.Pagination{}, errors.New("limit must be a positive integer")
+		}
+		if limit > ctrl.maxPageLimit {
+			return Domain.Pagination{}, fmt.Errorf("limit must not exceed %d", ctrl.maxPageLimit)
+		}
+		pagination.Limit = limit
+	}
+
+	if value, ok := c.GetQuery("offset"); ok {
+		offset, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || offset < 0 {
+			return Domain.Pagination{}, errors.New("offset must be a non-negative integer")
+		}
+		pagination.Offset = offset
+	}
+
+	if sort, ok := c.GetQuery("sort"); ok {
+		if sort != Domain.SortPriority {
+			return Domain.Pagination{}, errors.New("invalid sort, must be one of: priority")
+		}
+		pagination.Sort = sort
+	}
+
+	return pagination, nil
+}
+
+// SearchTasks handles GET /tasks/search?q=&limit=&offset=
+func (ctrl *Controller) SearchTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	tasks, total, err := ctrl.taskUsecase.SearchTasks(c.Request.Context(), caller, c.Query("q"), pagination)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidInput) {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgSearchTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgTasksSearched), tasks, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CountTasks handles GET /tasks/count?status=&priority=&tag=&due_before=&due_after=&include_deleted=
+func (ctrl *Controller) CountTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	filter, err := ctrl.parseTaskFilter(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidFilterParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Deleted tasks are only visible to those who manage the trash
+	if filter.IncludeDeleted && !caller.Can(Domain.PermissionTasksWrite) {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgAccessDenied,
+			Error:     "Permission " + Domain.PermissionTasksWrite + " required",
+		}
+		ctrl.respondError(c, http.StatusForbidden, errorResponse)
+		return
+	}
+
+	count, err := ctrl.taskUsecase.CountTasks(c.Request.Context(), caller, filter)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgCountTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	message := localize(c, Domain.MsgTasksCounted)
+	response := Domain.CountResponse{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Count:     count,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// exportColumns is the header row of CSV exports
+var exportColumns = []string{"id", "title", "description", "status", "due_date", "created_at", "updated_at"}
+
+// ExportTasks handles GET /tasks/export?format=csv|json plus the list filters.
+// Tasks are written to the response as they are read, so nothing is sent until the first task
+// arrives; a failure after that point can only cut the download short.
+func (ctrl *Controller) ExportTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "json" {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidExportParameters,
+			Error:     "invalid format, must be one of: csv, json",
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	filter, err := ctrl.parseTaskFilter(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidFilterParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Deleted tasks are only visible to those who manage the trash
+	if filter.IncludeDeleted && !caller.Can(Domain.PermissionTasksWrite) {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgAccessDenied,
+			Error:     "Permission " + Domain.PermissionTasksWrite + " required",
+		}
+		ctrl.respondError(c, http.StatusForbidden, errorResponse)
+		return
+	}
+
+	started := false
+	written := 0
+	csvWriter := csv.NewWriter(c.Writer)
+	start := func() error {
+		started = true
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="tasks.%s"`, format))
+		if format == "json" {
+			c.Header("Content-Type", "application/json")
+			c.Status(http.StatusOK)
+			_, err := c.Writer.WriteString("[")
+			return err
+		}
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Status(http.StatusOK)
+		return csvWriter.Write(exportColumns)
+	}
+
+	err = ctrl.taskUsecase.ExportTasks(c.Request.Context(), caller, filter, func(task *Domain.Task) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		if format == "csv" {
+			return csvWriter.Write(taskCSVRecord(task))
+		}
+
+		data, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		if written > 0 {
+			if _, err := c.Writer.WriteString(","); err != nil {
+				return err
+			}
+		}
+		written++
+		_, err = c.Writer.Write(data)
+		return err
+	})
+	if err != nil {
+		if !started {
+			statusCode := http.StatusInternalServerError
+			if errors.Is(err, Domain.ErrInvalidInput) {
+				statusCode = http.StatusBadRequest
+			}
+
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgExportTasksFailed,
+				Error:     err.Error(),
+			}
+			ctrl.respondError(c, statusCode, errorResponse)
+			return
+		}
+		csvWriter.Flush()
+		_ = c.Error(err)
+		c.Abort()
+		return
+	}
+
+	// An empty export still gets its header row or brackets
+	if !started {
+		if err := start(); err != nil {
+			_ = c.Error(err)
+			return
+		}
+	}
+	if format == "json" {
+		_, _ = c.Writer.WriteString("]")
+		return
+	}
+	csvWriter.Flush()
+}
+
+// taskCSVRecord lays out a task in exportColumns order; missing dates are left blank
+func taskCSVRecord(task *Domain.Task) []string {
+	return []string{
+		task.ID.Hex(),
+		task.Title,
+		task.Description,
+		task.Status,
+		formatExportTime(task.DueDate),
+		formatExportTime(task.CreatedAt),
+		formatExportTime(task.UpdatedAt),
+	}
+}
+
+// formatExportTime renders a timestamp as RFC3339 in UTC, or blank when unset
+func formatExportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// GetAssignedTasks handles GET /tasks/assigned-to-me?status=&priority=&tag=&due_before=&due_after=&limit=&offset=&sort=
+func (ctrl *Controller) GetAssignedTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	filter, err := ctrl.parseTaskFilter(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidFilterParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// Deleted tasks are only visible to those who manage the trash
+	if filter.IncludeDeleted && !caller.Can(Domain.PermissionTasksWrite) {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgAccessDenied,
+			Error:     "Permission " + Domain.PermissionTasksWrite + " required",
+		}
+		ctrl.respondError(c, http.StatusForbidden, errorResponse)
+		return
+	}
+
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	tasks, total, err := ctrl.taskUsecase.GetAssignedTasks(c.Request.Context(), caller, filter, pagination)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgAssignedTasksRetrieved), tasks, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTags handles GET /tasks/tags
+func (ctrl *Controller) GetTags(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	tags, err := ctrl.taskUsecase.GetTags(c.Request.Context(), caller)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveTagsFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgTagsRetrieved), tags, Domain.PaginationMeta{Total: int64(len(tags))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOverdueTasks handles GET /tasks/overdue?limit=&offset=
+func (ctrl *Controller) GetOverdueTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	tasks, total, err := ctrl.taskUsecase.GetOverdueTasks(c.Request.Context(), caller, pagination)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidInput) {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveOverdueTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgOverdueTasksRetrieved), tasks, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetStats handles GET /tasks/stats
+func (ctrl *Controller) GetStats(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	stats, err := ctrl.taskUsecase.GetStats(c.Request.Context(), caller)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveTaskStatisticsFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskStatisticsRetrieved), stats)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTaskByID handles GET /tasks/:id?include=subtasks&fields=
+func (ctrl *Controller) GetTaskByID(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	id := c.Param("id")
+
+	include := c.Query("include")
+	if include != "" && include != "subtasks" {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestParameters,
+			Error:     "invalid include, must be one of: subtasks",
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	fields, ok := ctrl.parseTaskFields(c)
+	if !ok {
+		return
+	}
+	if fields != nil && include != "" {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestParameters,
+			Error:     "fields cannot be combined with include",
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	var task *Domain.Task
+	var err error
+	switch {
+	case include == "subtasks":
+		task, err = ctrl.taskUsecase.GetTaskWithSubtasks(c.Request.Context(), caller, id)
+	case fields != nil:
+		task, err = ctrl.taskUsecase.GetTaskByIDWithFields(c.Request.Context(), caller, id, fields)
+	default:
+		task, err = ctrl.taskUsecase.GetTaskByID(c.Request.Context(), caller, id)
+	}
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if errors.Is(err, Domain.ErrInvalidTaskID) {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgTaskNotFound,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	// Embedded subtasks change independently of the parent, so only the plain task is tagged
+	if include == "" && respondNotModified(c, taskETag(task)) {
+		return
+	}
+
+	if fields != nil {
+		selected, err := task.Select(fields)
+		if err != nil {
+			ctrl.respondSelectFailed(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, Domain.NewResponse(localize(c, Domain.MsgTaskRetrieved), selected))
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskRetrieved), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CreateTask handles POST /tasks (admin only).
+// ?allow_duplicate=false refuses a title repeating one of the caller's open tasks, and true allows it,
+// whatever TASKS_ALLOW_DUPLICATES says.
+func (ctrl *Controller) CreateTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var allowDuplicate *bool
+	if value := c.Query("allow_duplicate"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidQueryParameters,
+				Error:     "invalid allow_duplicate, must be true or false",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		allowDuplicate = &parsed
+	}
+
+	var taskReq Domain.TaskRequest
+
+	if !ctrl.bindJSON(c, &taskReq) {
+		return
+	}
+
+	task, err := ctrl.taskUsecase.CreateTask(c.Request.Context(), caller, taskReq, allowDuplicate)
+	if err != nil {
+		var duplicateErr *Domain.DuplicateTaskError
+		statusCode := http.StatusBadRequest
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgCreateTaskFailed,
+			Error:     err.Error(),
+		}
+		switch {
+		case isOpenTaskLimitError(err), isDueDateRangeError(err):
+			statusCode = http.StatusUnprocessableEntity
+		case errors.As(err, &duplicateErr):
+			statusCode = http.StatusConflict
+			errorResponse.TaskID = duplicateErr.TaskID.Hex()
+		}
+
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	// The task is copied so its links never reach the event subscribers sharing it
+	created := *task
+	created.Links = ctrl.setLocation(c, "tasks", task.ID.Hex())
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskCreated), &created)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// CreateTasks handles POST /tasks/bulk (admin only).
+// The body is a JSON array of tasks; ?atomic=true refuses the whole batch if any item is invalid.
+func (ctrl *Controller) CreateTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	atomic := false
+	if value := c.Query("atomic"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidQueryParameters,
+				Error:     "invalid atomic, must be true or false",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		atomic = parsed
+	}
+
+	// Items are validated one by one in the usecase so failures can be reported per index
+	var taskReqs []Domain.TaskRequest
+	if err := json.NewDecoder(c.Request.Body).Decode(&taskReqs); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	result, err := ctrl.taskUsecase.CreateTasks(c.Request.Context(), caller, taskReqs, atomic)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgCreateTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	statusCode := http.StatusCreated
+	messageID := Domain.MsgTasksCreated
+	switch {
+	case len(result.Created) == 0:
+		statusCode = http.StatusUnprocessableEntity
+		messageID = Domain.MsgNoTasksCreated
+	case len(result.Errors) > 0:
+		statusCode = http.StatusMultiStatus
+		messageID = Domain.MsgTasksPartlyCreated
+	}
+
+	message := localize(c, messageID)
+	response := Domain.BulkCreateResponse{
+		Success:   len(result.Errors) == 0,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      result,
+	}
+
+	c.JSON(statusCode, response)
+}
+
+// ImportTasks handles POST /tasks/import?skip_duplicates= (admin only).
+// The JSON array of tasks is read from the "file" field of a multipart form, or from the raw body otherwise.
+func (ctrl *Controller) ImportTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	skipDuplicates := false
+	if value := c.Query("skip_duplicates"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidQueryParameters,
+				Error:     "invalid skip_duplicates, must be true or false",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		skipDuplicates = parsed
+	}
+
+	imports, err := readTaskImports(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidImportFile,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	result, err := ctrl.taskUsecase.ImportTasks(c.Request.Context(), caller, imports, skipDuplicates)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidInput) {
+			statusCode = http.StatusBadRequest
+		}
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgImportTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	message := localize(c, Domain.MsgTasksImported)
+	if result.Skipped > 0 {
+		message = localize(c, Domain.MsgTasksPartlyImported, result.Imported, result.Skipped)
+	}
+
+	response := Domain.ImportResponse{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// readTaskImports decodes the import array from a multipart "file" upload or from the request body
+func readTaskImports(c *gin.Context) ([]Domain.TaskImport, error) {
+	var imports []Domain.TaskImport
+
+	if strings.HasPrefix(c.ContentType(), "multipart/form-data") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, errors.New("missing import file, upload it in the \"file\" field")
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		if err := json.NewDecoder(file).Decode(&imports); err != nil {
+			return nil, err
+		}
+		return imports, nil
+	}
+
+	if err := json.NewDecoder(c.Request.Body).Decode(&imports); err != nil {
+		return nil, err
+	}
+	return imports, nil
+}
+
+// UpdateTask handles PUT /tasks/:id (admin only)
+func (ctrl *Controller) UpdateTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	id := c.Param("id")
+
+	var taskReq Domain.TaskRequest
+	if !ctrl.bindJSON(c, &taskReq) {
+		return
+	}
+
+	// An If-Match header takes precedence over a version in the body
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestHeaders,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+	if version != nil {
+		taskReq.Version = version
+	}
+
+	task, err := ctrl.taskUsecase.UpdateTask(c.Request.Context(), caller, id, taskReq)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, Domain.ErrTaskNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		if errors.Is(err, Domain.ErrInvalidTaskID) {
+			statusCode = http.StatusBadRequest
+		}
+		if isStatusTransitionError(err) || isDueDateRangeError(err) {
+			statusCode = http.StatusUnprocessableEntity
+		}
+		if isVersionConflictError(err) {
+			statusCode = http.StatusConflict
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUpdateTaskFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskUpdated), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTasksStatus handles POST /tasks/bulk-status (admin only)
+func (ctrl *Controller) UpdateTasksStatus(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	var bulkReq Domain.BulkStatusRequest
+	if err := c.ShouldBindJSON(&bulkReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	result, err := ctrl.taskUsecase.UpdateTasksStatus(c.Request.Context(), caller, bulkReq.IDs, bulkReq.Status)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if !isBulkValidationError(err) {
+			statusCode = http.StatusInternalServerError
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUpdateTaskStatusesFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	message := localize(c, Domain.MsgTaskStatusesUpdated)
+	response := Domain.BulkResponse{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTasksByStatus handles DELETE /tasks?status= (admin only)
+func (ctrl *Controller) DeleteTasksByStatus(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	status := c.Query("status")
+	if status == "" {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidQueryParameters,
+			Error:     "status is required",
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	result, err := ctrl.taskUsecase.DeleteTasksByStatus(c.Request.Context(), caller, status)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if !isBulkValidationError(err) {
+			statusCode = http.StatusInternalServerError
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgDeleteTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	message := localize(c, Domain.MsgTasksDeleted)
+	response := Domain.BulkResponse{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// PatchTask handles PATCH /tasks/:id (admin only)
+func (ctrl *Controller) PatchTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	id := c.Param("id")
+
+	var patchReq Domain.TaskPatchRequest
+	if err := c.ShouldBindJSON(&patchReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	// An If-Match header takes precedence over a version in the body
+	version, err := ifMatchVersion(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestHeaders,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+	if version != nil {
+		patchReq.Version = version
+	}
+
+	task, err := ctrl.taskUsecase.PatchTask(c.Request.Context(), caller, id, patchReq)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, Domain.ErrTaskNotFound) {
+			statusCode = http.StatusNotFound
+		}
+		if isStatusTransitionError(err) || isDueDateRangeError(err) {
+			statusCode = http.StatusUnprocessableEntity
+		}
+		if isVersionConflictError(err) {
+			statusCode = http.StatusConflict
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUpdateTaskFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskUpdated), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateTaskStatus handles PATCH /tasks/:id/status (assignee or admin)
+func (ctrl *Controller) UpdateTaskStatus(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	id := c.Param("id")
+
+	var statusReq Domain.TaskStatusRequest
+	if err := c.ShouldBindJSON(&statusReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	task, err := ctrl.taskUsecase.UpdateTaskStatus(c.Request.Context(), caller, id, statusReq.Status)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrNotAssignee):
+			statusCode = http.StatusForbidden
+		}
+		if isStatusTransitionError(err) {
+			statusCode = http.StatusUnprocessableEntity
+		}
+		if isVersionConflictError(err) {
+			statusCode = http.StatusConflict
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUpdateTaskStatusFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskStatusUpdated), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DeleteTask handles DELETE /tasks/:id (admin only)
+func (ctrl *Controller) DeleteTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	id := c.Param("id")
+
+	err := ctrl.taskUsecase.DeleteTask(c.Request.Context(), caller, id)
+	if err != nil {
+		statusCode := http.StatusNotFound
+		if errors.Is(err, Domain.ErrInvalidTaskID) {
+			statusCode = http.StatusBadRequest
+		}
+		if errors.Is(err, Domain.ErrTaskHasSubtasks) {
+			statusCode = http.StatusConflict
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgDeleteTaskFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgTaskDeleted))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetDeletedTasks handles GET /tasks/trash (admin only)
+func (ctrl *Controller) GetDeletedTasks(c *gin.Context) {
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	tasks, total, err := ctrl.taskUsecase.GetDeletedTasks(c.Request.Context(), pagination)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveDeletedTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgDeletedTasksRetrieved), tasks, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RestoreTask handles POST /tasks/:id/restore (admin only)
+func (ctrl *Controller) RestoreTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	id := c.Param("id")
+
+	task, err := ctrl.taskUsecase.RestoreTask(c.Request.Context(), caller, id)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidTaskID):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrTaskNotDeleted):
+			statusCode = http.StatusConflict
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRestoreTaskFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskRestored), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ArchiveTask handles POST /tasks/:id/archive?force= (admin only)
+func (ctrl *Controller) ArchiveTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	force := false
+	if value := c.Query("force"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidQueryParameters,
+				Error:     "invalid force, must be true or false",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		force = parsed
+	}
+
+	task, err := ctrl.taskUsecase.ArchiveTask(c.Request.Context(), caller, c.Param("id"), force)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgArchiveTaskFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, archiveStatusCode(err), errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskArchived), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UnarchiveTask handles POST /tasks/:id/unarchive (admin only)
+func (ctrl *Controller) UnarchiveTask(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	task, err := ctrl.taskUsecase.UnarchiveTask(c.Request.Context(), caller, c.Param("id"))
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUnarchiveTaskFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, archiveStatusCode(err), errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgTaskUnarchived), task)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// archiveStatusCode maps an error from archiving or unarchiving a task to its HTTP status
+func archiveStatusCode(err error) int {
+	switch {
+	case errors.Is(err, Domain.ErrTaskNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, Domain.ErrInvalidTaskID):
+		return http.StatusBadRequest
+	case errors.Is(err, Domain.ErrTaskArchived), errors.Is(err, Domain.ErrTaskNotArchived), errors.Is(err, Domain.ErrTaskNotCompleted):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ArchiveCompletedTasks handles POST /tasks/archive?completed_before= (admin only).
+// completed_before is a YYYY-MM-DD date, meaning the start of that day, or an RFC 3339 time.
+func (ctrl *Controller) ArchiveCompletedTasks(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	completedBefore, err := ctrl.parseCompletedBefore(c.Query("completed_before"))
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidQueryParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	result, err := ctrl.taskUsecase.ArchiveCompletedTasks(c.Request.Context(), caller, completedBefore)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgArchiveTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	message := localize(c, Domain.MsgTasksArchived)
+	response := Domain.BulkResponse{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      result,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// parseCompletedBefore reads a required completed_before value. Dates are read in the default timezone.
+func (ctrl *Controller) parseCompletedBefore(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("completed_before is required")
+	}
+	if completedBefore, err := time.ParseInLocation(Usecases.DueDateLayout, value, ctrl.defaultLocation); err == nil {
+		return completedBefore.UTC(), nil
+	}
+	completedBefore, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, errors.New("invalid completed_before format, use YYYY-MM-DD or RFC 3339")
+	}
+	return completedBefore.UTC(), nil
+}
+
+// PurgeDeletedTasks handles DELETE /tasks/trash?older_than_days= (admin only)
+func (ctrl *Controller) PurgeDeletedTasks(c *gin.Context) {
+	olderThanDays := DefaultPurgeOlderThanDays
+	if value, ok := c.GetQuery("older_than_days"); ok {
+		days, err := strconv.Atoi(value)
+		if err != nil || days < 0 {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidPurgeParameters,
+				Error:     "older_than_days must be a non-negative integer",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		olderThanDays = days
+	}
+
+	purged, err := ctrl.taskUsecase.PurgeDeletedTasks(c.Request.Context(), olderThanDays)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgPurgeDeletedTasksFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusInternalServerError, errorResponse)
+		return
+	}
+
+	message := localize(c, Domain.MsgDeletedTasksPurged)
+	response := Domain.PurgeResponse{
+		Success:       true,
+		Message:       message.Text,
+		MessageID:     message.ID,
+		PurgedCount:   purged,
+		OlderThanDays: olderThanDays,
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Comment-related handlers
+
+// AddComment handles POST /tasks/:id/comments
+func (ctrl *Controller) AddComment(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	taskID := c.Param("id")
+
+	var commentReq Domain.CommentRequest
+	if err := c.ShouldBindJSON(&commentReq); err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	comment, err := ctrl.commentUsecase.AddComment(c.Request.Context(), caller, taskID, commentReq)
+	if err != nil {
+		statusCode := http.StatusBadRequest
+		if errors.Is(err, Domain.ErrTaskNotFound) {
+			statusCode = http.StatusNotFound
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgAddCommentFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgCommentAdded), comment)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetComments handles GET /tasks/:id/comments
+func (ctrl *Controller) GetComments(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	taskID := c.Param("id")
+
+	comments, err := ctrl.commentUsecase.GetComments(c.Request.Context(), caller, taskID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidTaskID):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveCommentsFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgCommentsRetrieved), comments, Domain.PaginationMeta{Total: int64(len(comments))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// LogWork handles POST /tasks/:id/worklogs
+func (ctrl *Controller) LogWork(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	taskID := c.Param("id")
+
+	var workLogReq Domain.WorkLogRequest
+	if !ctrl.bindJSON(c, &workLogReq) {
+		return
+	}
+
+	workLog, err := ctrl.workLogUsecase.LogWork(c.Request.Context(), caller, taskID, workLogReq)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrDailyWorkLimit):
+			statusCode = http.StatusConflict
+		case errors.Is(err, Domain.ErrInvalidTaskID), errors.Is(err, Domain.ErrInvalidInput):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgLogWorkFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgWorkLogged), workLog)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// GetWorkLogs handles GET /tasks/:id/worklogs
+func (ctrl *Controller) GetWorkLogs(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	taskID := c.Param("id")
+
+	workLogs, err := ctrl.workLogUsecase.GetWorkLogs(c.Request.Context(), caller, taskID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidTaskID):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveWorkLogsFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgWorkLogsRetrieved), workLogs, Domain.PaginationMeta{Total: int64(len(workLogs))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetWorkLogSummary handles GET /worklogs/summary. The optional from and to query parameters
+// bound the days covered; managers and admins can pass user_id to see a single user.
+func (ctrl *Controller) GetWorkLogSummary(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	summaries, err := ctrl.workLogUsecase.GetSummary(c.Request.Context(), caller, c.Query("user_id"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidUserID) || errors.Is(err, Domain.ErrInvalidInput) {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveWorkLogSummaryFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgWorkLogSummaryRetrieved), summaries, Domain.PaginationMeta{Total: int64(len(summaries))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UploadAttachment handles POST /tasks/:id/attachments, a multipart/form-data request with the file
+// in the "file" field. The file is streamed to storage as it arrives rather than held in memory.
+func (ctrl *Controller) UploadAttachment(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	taskID := c.Param("id")
+
+	file, err := multipartFile(c, "file")
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidRequestPayload,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	attachment, err := ctrl.attachmentUsecase.AddAttachment(c.Request.Context(), caller, taskID, file.FileName(), file)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrAttachmentTooLarge), errors.As(err, &maxBytesErr):
+			statusCode = http.StatusRequestEntityTooLarge
+		case errors.Is(err, Domain.ErrUnsupportedAttachmentType):
+			statusCode = http.StatusUnsupportedMediaType
+		case errors.Is(err, Domain.ErrInvalidInput):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgUploadAttachmentFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewResponse(localize(c, Domain.MsgAttachmentUploaded), attachment)
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// multipartFile returns the file part of a multipart request with the given field name, positioned
+// at the start of its content. Parts before it are skipped.
+func multipartFile(c *gin.Context, field string) (*multipart.Part, error) {
+	reader, err := c.Request.MultipartReader()
+	if err != nil {
+		return nil, fmt.Errorf("the request must be multipart/form-data: %v", err)
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			return nil, fmt.Errorf("the request has no %q file field", field)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if part.FormName() == field && part.FileName() != "" {
+			return part, nil
+		}
+	}
+}
+
+// GetAttachments handles GET /tasks/:id/attachments
+func (ctrl *Controller) GetAttachments(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	taskID := c.Param("id")
+
+	attachments, err := ctrl.attachmentUsecase.GetAttachments(c.Request.Context(), caller, taskID)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidTaskID):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveAttachmentsFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgAttachmentsRetrieved), attachments, Domain.PaginationMeta{Total: int64(len(attachments))})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadAttachment handles GET /tasks/:id/attachments/:attachmentId. The file is streamed with
+// the content type detected at upload and a Content-Disposition naming the original file.
+func (ctrl *Controller) DownloadAttachment(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	attachment, content, err := ctrl.attachmentUsecase.OpenAttachment(c.Request.Context(), caller, c.Param("id"), c.Param("attachmentId"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound), errors.Is(err, Domain.ErrAttachmentNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidID):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgDownloadAttachmentFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+	defer content.Close()
+
+	// FormatMediaType encodes names that are not plain ASCII as RFC 2231 asks
+	disposition := mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename})
+	if disposition == "" {
+		disposition = "attachment"
+	}
+
+	c.DataFromReader(http.StatusOK, attachment.Size, attachment.ContentType, content, map[string]string{
+		"Content-Disposition":    disposition,
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DeleteAttachment handles DELETE /tasks/:id/attachments/:attachmentId
+func (ctrl *Controller) DeleteAttachment(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	err := ctrl.attachmentUsecase.DeleteAttachment(c.Request.Context(), caller, c.Param("id"), c.Param("attachmentId"))
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound), errors.Is(err, Domain.ErrAttachmentNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidID):
+			statusCode = http.StatusBadRequest
+		case errors.Is(err, Domain.ErrNotUploader):
+			statusCode = http.StatusForbidden
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgDeleteAttachmentFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewMessageResponse(localize(c, Domain.MsgAttachmentDeleted))
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetTaskRevisions handles GET /tasks/:id/revisions?limit=&offset=
+func (ctrl *Controller) GetTaskRevisions(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		ctrl.respondMissingCaller(c)
+		return
+	}
+
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	revisions, total, err := ctrl.taskUsecase.GetTaskRevisions(c.Request.Context(), caller, c.Param("id"), pagination)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			statusCode = http.StatusNotFound
+		case errors.Is(err, Domain.ErrInvalidInput):
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveTaskRevisionsFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgTaskRevisionsRetrieved), revisions, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Audit handlers
+
+// GetAuditLog handles GET /audit?actor_id=&entity=&limit=&offset= (admin only)
+func (ctrl *Controller) GetAuditLog(c *gin.Context) {
+	pagination, err := ctrl.parsePagination(c)
+	if err != nil {
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgInvalidPaginationParameters,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+		return
+	}
+
+	filter := Domain.AuditFilter{
+		ActorID: c.Query("actor_id"),
+		Entity:  c.Query("entity"),
+	}
+
+	entries, total, err := ctrl.auditUsecase.GetAuditLog(c.Request.Context(), filter, pagination)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidInput) {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgRetrieveAuditLogFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgAuditLogRetrieved), entries, Domain.PaginationMeta{
+		Total:  total,
+		Limit:  pagination.Limit,
+		Offset: pagination.Offset,
+	})
+
+	c.JSON(http.StatusOK, response)
+}
+
+// Report handlers
+
+// GetTasksByUserReport handles GET /reports/tasks-by-user?since= (admin only). since is a
+// YYYY-MM-DD day in the default timezone; only tasks created from its start are counted.
+func (ctrl *Controller) GetTasksByUserReport(c *gin.Context) {
+	var since time.Time
+	if value, ok := c.GetQuery("since"); ok {
+		parsed, err := time.ParseInLocation(Usecases.DueDateLayout, value, ctrl.defaultLocation)
+		if err != nil {
+			errorResponse := Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidRequestParameters,
+				Error:     "invalid since format, use YYYY-MM-DD",
+			}
+			ctrl.respondError(c, http.StatusBadRequest, errorResponse)
+			return
+		}
+		since = parsed.UTC()
+	}
+
+	reports, err := ctrl.reportUsecase.GetTasksByUser(c.Request.Context(), since)
+	if err != nil {
+		statusCode := http.StatusInternalServerError
+		if errors.Is(err, Domain.ErrInvalidInput) {
+			statusCode = http.StatusBadRequest
+		}
+
+		errorResponse := Domain.ErrorResponse{
+			Success:   false,
+			MessageID: Domain.MsgBuildReportFailed,
+			Error:     err.Error(),
+		}
+		ctrl.respondError(c, statusCode, errorResponse)
+		return
+	}
+
+	response := Domain.NewListResponse(localize(c, Domain.MsgReportBuilt), reports, Domain.PaginationMeta{Total: int64(len(reports))})
+
+	c.JSON(http.StatusOK, response)
+}
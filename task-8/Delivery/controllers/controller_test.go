@@ -2,11 +2,20 @@ package controllers
 
 import (
 	"bytes"
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -14,6 +23,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+	"task_manager/Usecases"
 )
 
 // Mock implementations for testing
@@ -21,248 +33,6152 @@ type MockTaskUsecase struct {
 	mock.Mock
 }
 
-func (m *MockTaskUsecase) GetAllTasks() ([]*Domain.Task, error) {
+func (m *MockTaskUsecase) GetAllTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(caller, filter, pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskUsecase) GetTaskByID(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	args := m.Called(caller, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetTaskByIDWithFields(ctx context.Context, caller Domain.Caller, id string, fields []string) (*Domain.Task, error) {
+	args := m.Called(caller, id, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) CreateTask(ctx context.Context, caller Domain.Caller, taskReq Domain.TaskRequest, allowDuplicate *bool) (*Domain.Task, error) {
+	args := m.Called(caller, taskReq, allowDuplicate)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) CreateTasks(ctx context.Context, caller Domain.Caller, taskReqs []Domain.TaskRequest, atomic bool) (*Domain.BulkCreateResult, error) {
+	args := m.Called(caller, taskReqs, atomic)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.BulkCreateResult), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetTaskRevisions(ctx context.Context, caller Domain.Caller, id string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error) {
+	args := m.Called(caller, id, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.TaskRevision), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskUsecase) ImportTasks(ctx context.Context, caller Domain.Caller, imports []Domain.TaskImport, skipDuplicates bool) (*Domain.ImportResult, error) {
+	args := m.Called(caller, imports, skipDuplicates)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.ImportResult), args.Error(1)
+}
+
+func (m *MockTaskUsecase) UpdateTasksStatus(ctx context.Context, caller Domain.Caller, ids []string, status string) (*Domain.BulkResult, error) {
+	args := m.Called(caller, ids, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.BulkResult), args.Error(1)
+}
+
+func (m *MockTaskUsecase) DeleteTasksByStatus(ctx context.Context, caller Domain.Caller, status string) (*Domain.BulkResult, error) {
+	args := m.Called(caller, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.BulkResult), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetStats(ctx context.Context, caller Domain.Caller) (*Domain.TaskStats, error) {
+	args := m.Called(caller)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.TaskStats), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetOverdueTasks(ctx context.Context, caller Domain.Caller, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(caller, pagination)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskUsecase) SearchTasks(ctx context.Context, caller Domain.Caller, query string, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(caller, query, pagination)
+	if args.Get(0) == nil {
+		return nil, args.Get(1).(int64), args.Error(2)
+	}
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskUsecase) CountTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter) (int64, error) {
+	args := m.Called(caller, filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskUsecase) ExportTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	args := m.Called(caller, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) UpdateTask(ctx context.Context, caller Domain.Caller, id string, taskReq Domain.TaskRequest) (*Domain.Task, error) {
+	args := m.Called(caller, id, taskReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) PatchTask(ctx context.Context, caller Domain.Caller, id string, patch Domain.TaskPatchRequest) (*Domain.Task, error) {
+	args := m.Called(caller, id, patch)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) DeleteTask(ctx context.Context, caller Domain.Caller, id string) error {
+	args := m.Called(caller, id)
+	return args.Error(0)
+}
+
+func (m *MockTaskUsecase) GetDeletedTasks(ctx context.Context, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskUsecase) RestoreTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	args := m.Called(caller, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) ArchiveTask(ctx context.Context, caller Domain.Caller, id string, force bool) (*Domain.Task, error) {
+	args := m.Called(caller, id, force)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) UnarchiveTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	args := m.Called(caller, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) ArchiveCompletedTasks(ctx context.Context, caller Domain.Caller, completedBefore time.Time) (*Domain.BulkResult, error) {
+	args := m.Called(caller, completedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.BulkResult), args.Error(1)
+}
+
+func (m *MockTaskUsecase) PurgeTasksByStatus(ctx context.Context, caller Domain.Caller, status string, olderThan time.Duration) (int64, error) {
+	args := m.Called(caller, status, olderThan)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskUsecase) PurgeDeletedTasks(ctx context.Context, olderThanDays int) (int64, error) {
+	args := m.Called(olderThanDays)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetTaskWithSubtasks(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	args := m.Called(caller, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetTags(ctx context.Context, caller Domain.Caller) ([]string, error) {
+	args := m.Called(caller)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTaskUsecase) GetAssignedTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(caller, filter, pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskUsecase) UpdateTaskStatus(ctx context.Context, caller Domain.Caller, id string, status string) (*Domain.Task, error) {
+	args := m.Called(caller, id, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+type MockCommentUsecase struct {
+	mock.Mock
+}
+
+func (m *MockCommentUsecase) AddComment(ctx context.Context, caller Domain.Caller, taskID string, commentReq Domain.CommentRequest) (*Domain.Comment, error) {
+	args := m.Called(caller, taskID, commentReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentUsecase) GetComments(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.Comment, error) {
+	args := m.Called(caller, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Comment), args.Error(1)
+}
+
+type MockWorkLogUsecase struct {
+	mock.Mock
+}
+
+func (m *MockWorkLogUsecase) LogWork(ctx context.Context, caller Domain.Caller, taskID string, workLogReq Domain.WorkLogRequest) (*Domain.WorkLog, error) {
+	args := m.Called(caller, taskID, workLogReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.WorkLog), args.Error(1)
+}
+
+func (m *MockWorkLogUsecase) GetWorkLogs(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.WorkLog, error) {
+	args := m.Called(caller, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.WorkLog), args.Error(1)
+}
+
+func (m *MockWorkLogUsecase) GetSummary(ctx context.Context, caller Domain.Caller, userID, from, to string) ([]*Domain.WorkLogSummary, error) {
+	args := m.Called(caller, userID, from, to)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.WorkLogSummary), args.Error(1)
+}
+
+type MockAttachmentUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentUsecase) AddAttachment(ctx context.Context, caller Domain.Caller, taskID, filename string, content io.Reader) (*Domain.Attachment, error) {
+	// The content is read here, as the usecase would, so tests can match on it
+	data, _ := io.ReadAll(content)
+	args := m.Called(caller, taskID, filename, string(data))
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentUsecase) GetAttachments(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.Attachment, error) {
+	args := m.Called(caller, taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentUsecase) OpenAttachment(ctx context.Context, caller Domain.Caller, taskID, attachmentID string) (*Domain.Attachment, io.ReadCloser, error) {
+	args := m.Called(caller, taskID, attachmentID)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*Domain.Attachment), args.Get(1).(io.ReadCloser), args.Error(2)
+}
+
+func (m *MockAttachmentUsecase) DeleteAttachment(ctx context.Context, caller Domain.Caller, taskID, attachmentID string) error {
+	args := m.Called(caller, taskID, attachmentID)
+	return args.Error(0)
+}
+
+type MockPasswordResetUsecase struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetUsecase) RequestReset(ctx context.Context, username string) error {
+	args := m.Called(username)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	args := m.Called(token, newPassword)
+	return args.Error(0)
+}
+
+type MockAPIKeyUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyUsecase) CreateAPIKey(ctx context.Context, caller Domain.Caller, req Domain.APIKeyRequest) (*Domain.CreatedAPIKey, error) {
+	args := m.Called(caller, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.CreatedAPIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyUsecase) ListAPIKeys(ctx context.Context) ([]*Domain.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyUsecase) RevokeAPIKey(ctx context.Context, caller Domain.Caller, id string) (*Domain.APIKey, error) {
+	args := m.Called(caller, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyUsecase) ValidateAPIKey(ctx context.Context, key string) (*Domain.APIKey, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+// MockWebhookUsecase is a mock implementation of WebhookUsecaseInterface
+type MockWebhookUsecase struct {
+	mock.Mock
+}
+
+func (m *MockWebhookUsecase) CreateWebhook(ctx context.Context, caller Domain.Caller, req Domain.WebhookRequest) (*Domain.CreatedWebhook, error) {
+	args := m.Called(caller, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.CreatedWebhook), args.Error(1)
+}
+
+func (m *MockWebhookUsecase) ListWebhooks(ctx context.Context) ([]*Domain.Webhook, error) {
 	args := m.Called()
-	return args.Get(0).([]*Domain.Task), args.Error(1)
+	return args.Get(0).([]*Domain.Webhook), args.Error(1)
 }
 
-func (m *MockTaskUsecase) GetTaskByID(id string) (*Domain.Task, error) {
+func (m *MockWebhookUsecase) GetWebhook(ctx context.Context, id string) (*Domain.Webhook, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
 	}
-	return args.Get(0).(*Domain.Task), args.Error(1)
+	return args.Get(0).(*Domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookUsecase) UpdateWebhook(ctx context.Context, caller Domain.Caller, id string, req Domain.WebhookRequest) (*Domain.Webhook, error) {
+	args := m.Called(caller, id, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookUsecase) DeleteWebhook(ctx context.Context, caller Domain.Caller, id string) error {
+	args := m.Called(caller, id)
+	return args.Error(0)
 }
 
-func (m *MockTaskUsecase) CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, error) {
-	args := m.Called(taskReq)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*Domain.Task), args.Error(1)
-}
+func (m *MockWebhookUsecase) GetDeliveries(ctx context.Context, id string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error) {
+	args := m.Called(id, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+type MockUserUsecase struct {
+	mock.Mock
+}
+
+func (m *MockUserUsecase) RegisterUser(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error) {
+	args := m.Called(userReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) LoginUser(ctx context.Context, loginReq Domain.LoginRequest) (*Domain.User, *Domain.AuthTokens, error) {
+	args := m.Called(loginReq)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*Domain.User), args.Get(1).(*Domain.AuthTokens), args.Error(2)
+}
+
+func (m *MockUserUsecase) Logout(ctx context.Context, caller Domain.Caller, tokenID string, expiresAt time.Time, refreshToken string) error {
+	args := m.Called(caller, tokenID, expiresAt, refreshToken)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) RefreshTokens(ctx context.Context, refreshToken string) (*Domain.User, *Domain.AuthTokens, error) {
+	args := m.Called(refreshToken)
+	if args.Get(0) == nil {
+		return nil, nil, args.Error(2)
+	}
+	return args.Get(0).(*Domain.User), args.Get(1).(*Domain.AuthTokens), args.Error(2)
+}
+
+func (m *MockUserUsecase) ChangePassword(ctx context.Context, caller Domain.Caller, currentPassword, newPassword string) error {
+	args := m.Called(caller, currentPassword, newPassword)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) GetUserProfile(ctx context.Context, userID string) (*Domain.User, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) UpdateProfile(ctx context.Context, userID string, req Domain.ProfileUpdateRequest) (*Domain.User, error) {
+	args := m.Called(userID, req)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) GetAllUsers(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).([]*Domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserUsecase) GetUserDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	args := m.Called(query, pagination)
+	return args.Get(0).([]*Domain.UserDirectoryEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserUsecase) ExportUsers(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockUserUsecase) GetLoginHistory(ctx context.Context, caller Domain.Caller) ([]*Domain.LoginEvent, error) {
+	args := m.Called(caller)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.LoginEvent), args.Error(1)
+}
+
+func (m *MockUserUsecase) PromoteUser(ctx context.Context, caller Domain.Caller, username, role string) (*Domain.User, error) {
+	args := m.Called(caller, username, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) DemoteAdminToUser(ctx context.Context, caller Domain.Caller, username string) (*Domain.User, error) {
+	args := m.Called(caller, username)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) DeleteUser(ctx context.Context, caller Domain.Caller, userID string, anonymize bool) (*Domain.UserDeletionResult, error) {
+	args := m.Called(caller, userID, anonymize)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.UserDeletionResult), args.Error(1)
+}
+
+func (m *MockUserUsecase) DeactivateUser(ctx context.Context, caller Domain.Caller, userID string) (*Domain.User, error) {
+	args := m.Called(caller, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) ActivateUser(ctx context.Context, caller Domain.Caller, userID string) (*Domain.User, error) {
+	args := m.Called(caller, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) VerifyEmail(ctx context.Context, token string) (*Domain.User, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) CreateAdmin(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error) {
+	args := m.Called(userReq)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserUsecase) SeedAdmin(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, bool, error) {
+	args := m.Called(userReq)
+	if args.Get(0) == nil {
+		return nil, args.Bool(1), args.Error(2)
+	}
+	return args.Get(0).(*Domain.User), args.Bool(1), args.Error(2)
+}
+
+// MockAuditUsecase is a mock implementation of AuditUsecaseInterface
+type MockAuditUsecase struct {
+	mock.Mock
+}
+
+func (m *MockAuditUsecase) GetAuditLog(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error) {
+	args := m.Called(filter, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.AuditEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type MockReportUsecase struct {
+	mock.Mock
+}
+
+func (m *MockReportUsecase) GetTasksByUser(ctx context.Context, since time.Time) ([]*Domain.UserTaskReport, error) {
+	args := m.Called(since)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.UserTaskReport), args.Error(1)
+}
+
+// weakPasswordError is what the usecases return for a password the policy rejects
+var weakPasswordError = &Domain.PasswordPolicyError{Violations: []Domain.PasswordRuleViolation{
+	{Rule: Domain.PasswordRuleMinLength, Message: "must be at least 8 characters long"},
+	{Rule: Domain.PasswordRuleCommon, Message: "must not be a commonly used password"},
+}}
+
+// assertPasswordViolations checks that the response lists the rules of weakPasswordError
+func assertPasswordViolations(t *testing.T, w *httptest.ResponseRecorder) {
+	var response Domain.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, weakPasswordError.Violations, response.Violations)
+}
+
+// assertFieldErrors checks that a response lists exactly the given invalid fields
+func assertFieldErrors(t *testing.T, w *httptest.ResponseRecorder, expected ...Domain.FieldError) {
+	var response Domain.ErrorResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, "Invalid request payload", response.Message)
+	assert.Equal(t, expected, response.Errors)
+}
+
+// Test setup helper
+func setupTestController() (*Controller, *MockTaskUsecase, *MockUserUsecase) {
+	mockTaskUsecase := new(MockTaskUsecase)
+	mockUserUsecase := new(MockUserUsecase)
+	controller := NewController(mockTaskUsecase, mockUserUsecase, new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockTaskUsecase, mockUserUsecase
+}
+
+func setupCommentTestController() (*Controller, *MockCommentUsecase) {
+	mockCommentUsecase := new(MockCommentUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), mockCommentUsecase, new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockCommentUsecase
+}
+
+func setupAttachmentTestController() (*Controller, *MockAttachmentUsecase) {
+	mockAttachmentUsecase := new(MockAttachmentUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), mockAttachmentUsecase, new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockAttachmentUsecase
+}
+
+func setupWorkLogTestController() (*Controller, *MockWorkLogUsecase) {
+	mockWorkLogUsecase := new(MockWorkLogUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), mockWorkLogUsecase, new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockWorkLogUsecase
+}
+
+func setupAuditTestController() (*Controller, *MockAuditUsecase) {
+	mockAuditUsecase := new(MockAuditUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), mockAuditUsecase, new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockAuditUsecase
+}
+
+func setupAPIKeyTestController() (*Controller, *MockAPIKeyUsecase) {
+	mockAPIKeyUsecase := new(MockAPIKeyUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), mockAPIKeyUsecase, new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockAPIKeyUsecase
+}
+
+func setupWebhookTestController() (*Controller, *MockWebhookUsecase) {
+	mockWebhookUsecase := new(MockWebhookUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), mockWebhookUsecase, new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockWebhookUsecase
+}
+
+func setupReportTestController() (*Controller, *MockReportUsecase) {
+	mockReportUsecase := new(MockReportUsecase)
+	controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), mockReportUsecase, "/api/v1", Infrastructure.NewNopLogger())
+	return controller, mockReportUsecase
+}
+
+func setupGinContext() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	return gin.New()
+}
+
+// setupAuthenticatedGinContext mimics the auth middleware by setting the caller identity
+func setupAuthenticatedGinContext(userID, role string) *gin.Engine {
+	router := setupGinContext()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", userID)
+		c.Set("role", role)
+		c.Next()
+	})
+	return router
+}
+
+const (
+	testAdminID = "507f1f77bcf86cd799439011"
+	testUserID  = "507f1f77bcf86cd799439022"
+)
+
+var (
+	adminCaller = Domain.Caller{UserID: testAdminID, Role: Domain.RoleAdmin}
+	userCaller  = Domain.Caller{UserID: testUserID, Role: Domain.RoleUser}
+)
+
+// User Controller Tests
+
+func TestController_Register(t *testing.T) {
+	t.Run("Success - register user", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		userReq := Domain.UserRequest{
+			Username: "testuser",
+			Password: "password123",
+		}
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     Domain.RoleUser,
+		}
+
+		mockUserUsecase.On("RegisterUser", userReq).Return(expectedUser, nil)
+
+		reqBody, _ := json.Marshal(userReq)
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response Domain.Response[*Domain.User]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "User registered successfully", response.Message)
+		assert.Equal(t, expectedUser.ID, response.Data.ID)
+		assert.Equal(t, "testuser", response.Data.Username)
+		location := "/api/v1/users/" + expectedUser.ID.Hex()
+		assert.Equal(t, location, w.Header().Get("Location"))
+		if assert.NotNil(t, response.Data.Links) {
+			assert.Equal(t, location, response.Data.Links.Self)
+		}
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - Location includes the base path the API is served under", func(t *testing.T) {
+		// Arrange
+		controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/tasks-service/api/v1", Infrastructure.NewNopLogger())
+		mockUserUsecase := controller.userUsecase.(*MockUserUsecase)
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		userReq := Domain.UserRequest{Username: "testuser", Password: "password123"}
+		expectedUser := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+		mockUserUsecase.On("RegisterUser", userReq).Return(expectedUser, nil)
+
+		reqBody, _ := json.Marshal(userReq)
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "/tasks-service/api/v1/users/"+expectedUser.ID.Hex(), w.Header().Get("Location"))
+	})
+
+	t.Run("Error - strict mode rejects an unknown key", func(t *testing.T) {
+		// Arrange
+		t.Setenv("STRICT_JSON", "true")
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		req := httptest.NewRequest("POST", "/register", bytes.NewBufferString(`{"username":"newuser","password":"Passw0rd!","e_mail":"new@example.com"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertFieldErrors(t, w, Domain.FieldError{Field: "e_mail", Rule: "unknown", Message: `unknown field "e_mail"`})
+		mockUserUsecase.AssertNotCalled(t, "RegisterUser", mock.Anything)
+	})
+
+	t.Run("Error - password fails the policy", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+		mockUserUsecase.On("RegisterUser", Domain.UserRequest{Username: "newuser", Password: "123"}).Return(nil, weakPasswordError)
+
+		req := httptest.NewRequest("POST", "/register", bytes.NewBufferString(`{"username":"newuser","password":"123"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertPasswordViolations(t, w)
+	})
+
+	t.Run("Error - invalid email", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		req := httptest.NewRequest("POST", "/register", bytes.NewBufferString(`{"username":"newuser","password":"password123","email":"not-an-email"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertFieldErrors(t, w, Domain.FieldError{Field: "email", Rule: "email", Message: "email must be a valid email address"})
+		mockUserUsecase.AssertNotCalled(t, "RegisterUser", mock.Anything)
+	})
+
+	t.Run("Error - missing fields", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		req := httptest.NewRequest("POST", "/register", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertFieldErrors(t, w,
+			Domain.FieldError{Field: "username", Rule: "required", Message: "username is required"},
+			Domain.FieldError{Field: "password", Rule: "required", Message: "password is required"},
+		)
+		assert.Contains(t, w.Body.String(), `"error":"username is required; password is required"`)
+		mockUserUsecase.AssertNotCalled(t, "RegisterUser", mock.Anything)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Invalid request payload", response.Message)
+	})
+
+	t.Run("Error - email already exists", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		userReq := Domain.UserRequest{
+			Username: "newuser",
+			Password: "password123",
+			Email:    "taken@example.com",
+		}
+
+		mockUserUsecase.On("RegisterUser", userReq).Return(nil, Domain.ErrEmailTaken)
+
+		reqBody, _ := json.Marshal(userReq)
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "email already exists")
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - username already exists", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		userReq := Domain.UserRequest{
+			Username: "existinguser",
+			Password: "password123",
+		}
+
+		mockUserUsecase.On("RegisterUser", userReq).Return(nil, Domain.ErrUsernameTaken)
+
+		reqBody, _ := json.Marshal(userReq)
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to create user", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - other registration error", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/register", controller.Register)
+
+		userReq := Domain.UserRequest{
+			Username: "testuser",
+			Password: "password123",
+		}
+
+		mockUserUsecase.On("RegisterUser", userReq).Return(nil, errors.New("database error"))
+
+		reqBody, _ := json.Marshal(userReq)
+		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to create user", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_Login(t *testing.T) {
+	t.Run("Success - login user", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/login", controller.Login)
+
+		// IP and UserAgent are not part of the body; the controller fills them in from the request
+		loginReq := Domain.LoginRequest{
+			Username:  "testuser",
+			Password:  "password123",
+			IP:        "192.0.2.1",
+			UserAgent: "task-cli/1.0",
+		}
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     Domain.RoleUser,
+		}
+		expectedTokens := &Domain.AuthTokens{AccessToken: "jwt.token.here", RefreshToken: "refresh-token", ExpiresIn: 900}
+
+		mockUserUsecase.On("LoginUser", loginReq).Return(expectedUser, expectedTokens, nil)
+
+		reqBody, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "task-cli/1.0")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.LoginResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Login successful", response.Message)
+		assert.Equal(t, "jwt.token.here", response.Token)
+		assert.Equal(t, "refresh-token", response.RefreshToken)
+		assert.Equal(t, int64(900), response.ExpiresIn)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupGinContext()
+		router.POST("/login", controller.Login)
+
+		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Invalid request payload", response.Message)
+	})
+
+	t.Run("Error - missing password", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/login", controller.Login)
+
+		req := httptest.NewRequest("POST", "/login", bytes.NewBufferString(`{"username":"testuser"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertFieldErrors(t, w, Domain.FieldError{Field: "password", Rule: "required", Message: "password is required"})
+		mockUserUsecase.AssertNotCalled(t, "LoginUser", mock.Anything)
+	})
+
+	t.Run("Error - authentication failed", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/login", controller.Login)
+
+		loginReq := Domain.LoginRequest{
+			Username: "testuser",
+			Password: "wrongpassword",
+			IP:       "192.0.2.1",
+		}
+
+		mockUserUsecase.On("LoginUser", loginReq).Return(nil, nil, Domain.ErrInvalidCredentials)
+
+		reqBody, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Authentication failed", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - deactivated account", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/login", controller.Login)
+
+		loginReq := Domain.LoginRequest{Username: "testuser", Password: "password123", IP: "192.0.2.1"}
+		mockUserUsecase.On("LoginUser", loginReq).Return(nil, nil, Domain.ErrAccountDeactivated)
+
+		reqBody, _ := json.Marshal(loginReq)
+		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_Logout(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	setup := func(tokenID string) (*gin.Engine, *MockUserUsecase) {
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.Use(func(c *gin.Context) {
+			if tokenID != "" {
+				c.Set("token_id", tokenID)
+				c.Set("token_expires_at", expiresAt)
+			}
+			c.Next()
+		})
+		router.POST("/auth/logout", controller.Logout)
+		return router, mockUserUsecase
+	}
+	caller := Domain.Caller{UserID: testUserID, Role: Domain.RoleUser}
+
+	t.Run("Success - revoke access token", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup("token-id")
+		mockUserUsecase.On("Logout", caller, "token-id", expiresAt, "").Return(nil)
+		req := httptest.NewRequest("POST", "/auth/logout", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Logged out successfully")
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - revoke refresh token too", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup("token-id")
+		mockUserUsecase.On("Logout", caller, "token-id", expiresAt, "refresh-token").Return(nil)
+		req := httptest.NewRequest("POST", "/auth/logout", bytes.NewBufferString(`{"refresh_token":"refresh-token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - token without an ID", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup("")
+		req := httptest.NewRequest("POST", "/auth/logout", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserUsecase.AssertNotCalled(t, "Logout", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		router, _ := setup("token-id")
+		req := httptest.NewRequest("POST", "/auth/logout", bytes.NewBufferString("invalid json"))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Error - blacklist failure", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup("token-id")
+		mockUserUsecase.On("Logout", caller, "token-id", expiresAt, "").Return(errors.New("database error"))
+		req := httptest.NewRequest("POST", "/auth/logout", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestController_ForgotPassword(t *testing.T) {
+	setup := func() (*gin.Engine, *MockPasswordResetUsecase) {
+		mockPasswordResetUsecase := new(MockPasswordResetUsecase)
+		controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), mockPasswordResetUsecase, new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+		router := setupGinContext()
+		router.POST("/auth/forgot-password", controller.ForgotPassword)
+		return router, mockPasswordResetUsecase
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/auth/forgot-password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("Success - same response for known and unknown users", func(t *testing.T) {
+		// Arrange
+		router, mockPasswordResetUsecase := setup()
+		mockPasswordResetUsecase.On("RequestReset", "testuser").Return(nil)
+		mockPasswordResetUsecase.On("RequestReset", "nobody").Return(nil)
+		known := httptest.NewRecorder()
+		unknown := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(known, newRequest(`{"username":"testuser"}`))
+		router.ServeHTTP(unknown, newRequest(`{"username":"nobody"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusAccepted, known.Code)
+		assert.Equal(t, known.Code, unknown.Code)
+		assert.Equal(t, known.Body.String(), unknown.Body.String())
+		mockPasswordResetUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing username", func(t *testing.T) {
+		// Arrange
+		router, mockPasswordResetUsecase := setup()
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockPasswordResetUsecase.AssertNotCalled(t, "RequestReset", mock.Anything)
+	})
+
+	t.Run("Error - database failure", func(t *testing.T) {
+		// Arrange
+		router, mockPasswordResetUsecase := setup()
+		mockPasswordResetUsecase.On("RequestReset", "testuser").Return(errors.New("database error"))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"username":"testuser"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestController_ResetPassword(t *testing.T) {
+	setup := func() (*gin.Engine, *MockPasswordResetUsecase) {
+		mockPasswordResetUsecase := new(MockPasswordResetUsecase)
+		controller := NewController(new(MockTaskUsecase), new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), mockPasswordResetUsecase, new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+		router := setupGinContext()
+		router.POST("/auth/reset-password", controller.ResetPassword)
+		return router, mockPasswordResetUsecase
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/auth/reset-password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+	validBody := `{"token":"reset-token","new_password":"newpassword"}`
+
+	t.Run("Success - password reset", func(t *testing.T) {
+		// Arrange
+		router, mockPasswordResetUsecase := setup()
+		mockPasswordResetUsecase.On("ResetPassword", "reset-token", "newpassword").Return(nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(validBody))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Password reset successfully")
+		mockPasswordResetUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - new password fails the policy", func(t *testing.T) {
+		// Arrange
+		router, mockPasswordResetUsecase := setup()
+		mockPasswordResetUsecase.On("ResetPassword", "reset-token", "123").Return(weakPasswordError)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"token":"reset-token","new_password":"123"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertPasswordViolations(t, w)
+	})
+
+	t.Run("Error - missing token", func(t *testing.T) {
+		// Arrange
+		router, mockPasswordResetUsecase := setup()
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"new_password":"newpassword"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockPasswordResetUsecase.AssertNotCalled(t, "ResetPassword", mock.Anything, mock.Anything)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Error - unknown token", Domain.ErrInvalidResetToken, http.StatusBadRequest},
+		{"Error - expired token", Domain.ErrResetTokenExpired, http.StatusGone},
+		{"Error - used token", Domain.ErrResetTokenUsed, http.StatusGone},
+		{"Error - database failure", errors.New("database error"), http.StatusInternalServerError},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			router, mockPasswordResetUsecase := setup()
+			mockPasswordResetUsecase.On("ResetPassword", "reset-token", "newpassword").Return(tc.err)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, newRequest(validBody))
+
+			// Assert
+			assert.Equal(t, tc.status, w.Code)
+			assert.Contains(t, w.Body.String(), tc.err.Error())
+		})
+	}
+}
+
+func TestController_VerifyEmail(t *testing.T) {
+	setup := func() (*gin.Engine, *MockUserUsecase) {
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/auth/verify-email", controller.VerifyEmail)
+		return router, mockUserUsecase
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("POST", "/auth/verify-email", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("Success - email verified", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Email: "user@example.com", EmailVerified: true, Role: Domain.RoleUser}
+		mockUserUsecase.On("VerifyEmail", "verification-token").Return(user, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"token":"verification-token"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Email verified successfully")
+		assert.Contains(t, w.Body.String(), `"email_verified":true`)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing token", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserUsecase.AssertNotCalled(t, "VerifyEmail", mock.Anything)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Error - invalid token", Domain.ErrInvalidVerificationToken, http.StatusBadRequest},
+		{"Error - expired token", Domain.ErrVerificationTokenExpired, http.StatusGone},
+		{"Error - database failure", errors.New("database error"), http.StatusInternalServerError},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			router, mockUserUsecase := setup()
+			mockUserUsecase.On("VerifyEmail", "verification-token").Return(nil, tc.err)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, newRequest(`{"token":"verification-token"}`))
+
+			// Assert
+			assert.Equal(t, tc.status, w.Code)
+			assert.Contains(t, w.Body.String(), tc.err.Error())
+		})
+	}
+}
+
+func TestController_RefreshToken(t *testing.T) {
+	t.Run("Success - refresh token pair", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/auth/refresh", controller.RefreshToken)
+
+		expectedUser := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+		expectedTokens := &Domain.AuthTokens{AccessToken: "new-access-token", RefreshToken: "new-refresh-token", ExpiresIn: 900}
+		mockUserUsecase.On("RefreshTokens", "old-refresh-token").Return(expectedUser, expectedTokens, nil)
+
+		req := httptest.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"old-refresh-token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.LoginResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "new-access-token", response.Token)
+		assert.Equal(t, "new-refresh-token", response.RefreshToken)
+		assert.Equal(t, int64(900), response.ExpiresIn)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing refresh token", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupGinContext()
+		router.POST("/auth/refresh", controller.RefreshToken)
+
+		req := httptest.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Error - rejected refresh tokens", func(t *testing.T) {
+		for _, reason := range []error{Domain.ErrInvalidRefreshToken, Domain.ErrRefreshTokenExpired, Domain.ErrRefreshTokenReused} {
+			// Arrange
+			controller, _, mockUserUsecase := setupTestController()
+			router := setupGinContext()
+			router.POST("/auth/refresh", controller.RefreshToken)
+			mockUserUsecase.On("RefreshTokens", "bad-token").Return(nil, nil, reason)
+
+			req := httptest.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"bad-token"}`))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusUnauthorized, w.Code, reason)
+
+			var response Domain.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Authentication failed", response.Message)
+			assert.Equal(t, reason.Error(), response.Error)
+		}
+	})
+
+	t.Run("Error - storage failure", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.POST("/auth/refresh", controller.RefreshToken)
+		mockUserUsecase.On("RefreshTokens", "old-refresh-token").Return(nil, nil, errors.New("database error"))
+
+		req := httptest.NewRequest("POST", "/auth/refresh", bytes.NewBufferString(`{"refresh_token":"old-refresh-token"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+func TestController_ChangePassword(t *testing.T) {
+	setup := func() (*gin.Engine, *MockUserUsecase) {
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.PUT("/users/password", controller.ChangePassword)
+		return router, mockUserUsecase
+	}
+	caller := Domain.Caller{UserID: testUserID, Role: Domain.RoleUser}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("PUT", "/users/password", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("Success - password changed", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		mockUserUsecase.On("ChangePassword", caller, "oldpassword", "newpassword").Return(nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"current_password":"oldpassword","new_password":"newpassword"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Password changed successfully")
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - wrong current password", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		mockUserUsecase.On("ChangePassword", caller, "wrongpassword", "newpassword").Return(Domain.ErrIncorrectPassword)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"current_password":"wrongpassword","new_password":"newpassword"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Contains(t, w.Body.String(), "current password is incorrect")
+	})
+
+	t.Run("Error - new password fails the policy", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		mockUserUsecase.On("ChangePassword", caller, "oldpassword", "123").Return(weakPasswordError)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"current_password":"oldpassword","new_password":"123"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertPasswordViolations(t, w)
+	})
+
+	t.Run("Error - missing current password", func(t *testing.T) {
+		// Arrange
+		router, _ := setup()
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"new_password":"newpassword"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		mockUserUsecase.On("ChangePassword", caller, "oldpassword", "newpassword").Return(Domain.ErrUserNotFound)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"current_password":"oldpassword","new_password":"newpassword"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Error - database failure", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup()
+		mockUserUsecase.On("ChangePassword", caller, "oldpassword", "newpassword").Return(errors.New("database error"))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"current_password":"oldpassword","new_password":"newpassword"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestController_PromoteUser(t *testing.T) {
+	t.Run("Success - promote user", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/promote", controller.PromoteUser)
+
+		promoteReq := Domain.PromoteRequest{
+			Username: "usertoPromote",
+		}
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "usertoPromote",
+			Role:     Domain.RoleAdmin,
+		}
+
+		mockUserUsecase.On("PromoteUser", adminCaller, promoteReq.Username, "").Return(expectedUser, nil)
+
+		reqBody, _ := json.Marshal(promoteReq)
+		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.User]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "User promoted to admin successfully", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - promote user to manager", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/promote", controller.PromoteUser)
+
+		promoteReq := Domain.PromoteRequest{
+			Username: "usertoPromote",
+			Role:     Domain.RoleManager,
+		}
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "usertoPromote",
+			Role:     Domain.RoleManager,
+		}
+
+		mockUserUsecase.On("PromoteUser", adminCaller, promoteReq.Username, Domain.RoleManager).Return(expectedUser, nil)
+
+		reqBody, _ := json.Marshal(promoteReq)
+		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.User]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "User promoted to manager successfully", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid role", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/promote", controller.PromoteUser)
+
+		promoteReq := Domain.PromoteRequest{
+			Username: "usertoPromote",
+			Role:     "superuser",
+		}
+
+		mockUserUsecase.On("PromoteUser", adminCaller, promoteReq.Username, "superuser").Return(nil, Domain.NewError(Domain.ErrInvalidInput, "invalid role, must be one of: manager, admin"))
+
+		reqBody, _ := json.Marshal(promoteReq)
+		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/promote", controller.PromoteUser)
+
+		promoteReq := Domain.PromoteRequest{
+			Username: "nonexistentuser",
+		}
+
+		mockUserUsecase.On("PromoteUser", adminCaller, promoteReq.Username, "").Return(nil, Domain.ErrUserNotFound)
+
+		reqBody, _ := json.Marshal(promoteReq)
+		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to promote user", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/promote", controller.PromoteUser)
+
+		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Invalid request payload", response.Message)
+	})
+
+	t.Run("Error - missing username", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/promote", controller.PromoteUser)
+
+		req := httptest.NewRequest("POST", "/promote", bytes.NewBufferString(`{"role":"manager"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertFieldErrors(t, w, Domain.FieldError{Field: "username", Rule: "required", Message: "username is required"})
+		mockUserUsecase.AssertNotCalled(t, "PromoteUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestController_GetLoginHistory(t *testing.T) {
+	t.Run("Success - list recent logins", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/users/me/logins", controller.GetLoginHistory)
+
+		events := []*Domain.LoginEvent{{ID: primitive.NewObjectID(), IP: "203.0.113.7", UserAgent: "task-cli/1.0", Timestamp: time.Now()}}
+		mockUserUsecase.On("GetLoginHistory", adminCaller).Return(events, nil)
+
+		req := httptest.NewRequest("GET", "/users/me/logins", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.LoginEvent]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, "203.0.113.7", response.Data[0].IP)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository failure", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/users/me/logins", controller.GetLoginHistory)
+
+		mockUserUsecase.On("GetLoginHistory", adminCaller).Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/users/me/logins", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_DemoteUser(t *testing.T) {
+	t.Run("Success - demote admin", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/demote", controller.DemoteUser)
+
+		demoteReq := Domain.DemoteRequest{Username: "admintoDemote"}
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "admintoDemote",
+			Role:     Domain.RoleUser,
+		}
+
+		mockUserUsecase.On("DemoteAdminToUser", adminCaller, demoteReq.Username).Return(expectedUser, nil)
+
+		reqBody, _ := json.Marshal(demoteReq)
+		req := httptest.NewRequest("POST", "/demote", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.User]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "User demoted successfully", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	errorCases := []struct {
+		name         string
+		err          error
+		expectedCode int
+	}{
+		{"Error - user not found", Domain.ErrUserNotFound, http.StatusNotFound},
+		{"Error - user is not an admin or manager", Domain.ErrNotPrivileged, http.StatusBadRequest},
+		{"Error - cannot demote yourself", Domain.ErrSelfDemotion, http.StatusConflict},
+		{"Error - cannot demote the last admin", Domain.ErrLastAdminDemotion, http.StatusConflict},
+	}
+
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, _, mockUserUsecase := setupTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.POST("/demote", controller.DemoteUser)
+
+			mockUserUsecase.On("DemoteAdminToUser", adminCaller, "someadmin").Return(nil, tc.err)
+
+			reqBody, _ := json.Marshal(Domain.DemoteRequest{Username: "someadmin"})
+			req := httptest.NewRequest("POST", "/demote", bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			var response Domain.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &response)
+			assert.NoError(t, err)
+			assert.Equal(t, "Failed to demote user", response.Message)
+			assert.Equal(t, tc.err.Error(), response.Error)
+
+			mockUserUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_DeleteUser(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+
+	t.Run("Success - hard delete", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/users/:id", controller.DeleteUser)
+
+		result := &Domain.UserDeletionResult{TasksReassigned: 2, TasksUnassigned: 1}
+		mockUserUsecase.On("DeleteUser", adminCaller, userID, false).Return(result, nil)
+
+		req := httptest.NewRequest("DELETE", "/users/"+userID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.Response[*Domain.UserDeletionResult]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Success)
+		assert.Equal(t, "User deleted successfully", response.Message)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - anonymize", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/users/:id", controller.DeleteUser)
+
+		mockUserUsecase.On("DeleteUser", adminCaller, userID, true).Return(&Domain.UserDeletionResult{Anonymized: true}, nil)
+
+		req := httptest.NewRequest("DELETE", "/users/"+userID+"?anonymize=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.Response[*Domain.UserDeletionResult]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "User anonymized successfully", response.Message)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - API key cannot take over the user's tasks", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.Use(func(c *gin.Context) {
+			c.Set("api_key_id", testAdminID)
+			c.Next()
+		})
+		router.DELETE("/users/:id", controller.DeleteUser)
+
+		keyCaller := Domain.Caller{UserID: testAdminID, Role: Domain.RoleAdmin, APIKey: true}
+		mockUserUsecase.On("DeleteUser", keyCaller, userID, false).Return(nil, Domain.ErrReassignToAPIKey)
+
+		req := httptest.NewRequest("DELETE", "/users/"+userID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid anonymize", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/users/:id", controller.DeleteUser)
+
+		req := httptest.NewRequest("DELETE", "/users/"+userID+"?anonymize=maybe", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserUsecase.AssertNotCalled(t, "DeleteUser", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	errorCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"deleting yourself", Domain.ErrSelfDeletion, http.StatusConflict},
+		{"deleting the last admin", Domain.ErrLastAdminDeletion, http.StatusConflict},
+		{"user not found", Domain.ErrUserNotFound, http.StatusNotFound},
+		{"invalid ID", Domain.ErrInvalidUserID, http.StatusBadRequest},
+		{"database error", errors.New("database connection failed"), http.StatusInternalServerError},
+	}
+	for _, tc := range errorCases {
+		t.Run("Error - "+tc.name, func(t *testing.T) {
+			// Arrange
+			controller, _, mockUserUsecase := setupTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.DELETE("/users/:id", controller.DeleteUser)
+
+			mockUserUsecase.On("DeleteUser", adminCaller, userID, false).Return(nil, tc.err)
+
+			req := httptest.NewRequest("DELETE", "/users/"+userID, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.statusCode, w.Code)
+			var response Domain.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Failed to delete user", response.Message)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
+}
+
+func TestController_GetAllUsers(t *testing.T) {
+	t.Run("Success - get all users", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.GET("/users", controller.GetAllUsers)
+
+		expectedUsers := []*Domain.User{
+			{
+				ID:       primitive.NewObjectID(),
+				Username: "user1",
+				Role:     Domain.RoleUser,
+			},
+			{
+				ID:       primitive.NewObjectID(),
+				Username: "admin1",
+				Role:     Domain.RoleAdmin,
+			},
+		}
+
+		mockUserUsecase.On("GetAllUsers", Domain.UserFilter{}, Domain.Pagination{Limit: DefaultUserPageSize}).Return(expectedUsers, int64(2), nil)
+
+		req := httptest.NewRequest("GET", "/users", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.User]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Users retrieved successfully", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.GET("/users", controller.GetAllUsers)
+
+		mockUserUsecase.On("GetAllUsers", Domain.UserFilter{}, Domain.Pagination{Limit: DefaultUserPageSize}).Return([]*Domain.User(nil), int64(0), errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/users", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to retrieve users", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - combined filters and paging", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.GET("/users", controller.GetAllUsers)
+
+		filter := Domain.UserFilter{Query: "ali", Role: Domain.RoleAdmin}
+		pagination := Domain.Pagination{Limit: 10, Offset: 20, Sort: Domain.SortUsername}
+		mockUserUsecase.On("GetAllUsers", filter, pagination).Return([]*Domain.User{{Username: "alice", Role: Domain.RoleAdmin}}, int64(21), nil)
+
+		req := httptest.NewRequest("GET", "/users?q=+ali+&role=admin&sort=username&page=3&page_size=10", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.ListResponse[*Domain.User]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, Domain.PaginationMeta{Total: 21, Limit: 10, Offset: 20, Page: 3}, response.Meta)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - page past the end returns an empty list", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.GET("/users", controller.GetAllUsers)
+
+		pagination := Domain.Pagination{Limit: DefaultUserPageSize, Offset: 99 * DefaultUserPageSize}
+		mockUserUsecase.On("GetAllUsers", Domain.UserFilter{}, pagination).Return([]*Domain.User{}, int64(3), nil)
+
+		req := httptest.NewRequest("GET", "/users?page=100", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.ListResponse[*Domain.User]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Empty(t, response.Data)
+		assert.Equal(t, int64(3), response.Meta.Total)
+		assert.Equal(t, int64(100), response.Meta.Page)
+	})
+
+	invalidQueries := []struct {
+		name  string
+		query string
+	}{
+		{"unknown role", "role=owner"},
+		{"unknown sort", "sort=email"},
+		{"zero page", "page=0"},
+		{"page too large", "page=9223372036854775807"},
+		{"non-numeric page size", "page_size=ten"},
+		{"page size above the limit", "page_size=101"},
+		{"search too long", "q=" + strings.Repeat("a", MaxUserSearchLength+1)},
+	}
+	for _, tc := range invalidQueries {
+		t.Run("Error - "+tc.name, func(t *testing.T) {
+			// Arrange
+			controller, _, mockUserUsecase := setupTestController()
+			router := setupGinContext()
+			router.GET("/users", controller.GetAllUsers)
+
+			req := httptest.NewRequest("GET", "/users?"+tc.query, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			mockUserUsecase.AssertNotCalled(t, "GetAllUsers", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestController_GetUserDirectory(t *testing.T) {
+	t.Run("Success - search and paging reach the usecase", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.GET("/users/directory", controller.GetUserDirectory)
+
+		entries := []*Domain.UserDirectoryEntry{{ID: primitive.NewObjectID(), Username: "alice"}}
+		mockUserUsecase.On("GetUserDirectory", "ali", Domain.Pagination{Limit: 10, Offset: 10, Sort: Domain.SortUsername}).Return(entries, int64(11), nil)
+
+		req := httptest.NewRequest("GET", "/users/directory?q=+ali+&sort=username&page=2&page_size=10", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.UserDirectoryEntry]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, entries, response.Data)
+		assert.Equal(t, Domain.PaginationMeta{Total: 11, Limit: 10, Offset: 10, Page: 2}, response.Meta)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - role filter is refused", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		router.GET("/users/directory", controller.GetUserDirectory)
+
+		req := httptest.NewRequest("GET", "/users/directory?role=admin", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "role is only accepted by the admin user list")
+		mockUserUsecase.AssertNotCalled(t, "GetUserDirectory", mock.Anything, mock.Anything)
+	})
+}
+
+func TestController_GetProfile(t *testing.T) {
+	t.Run("Success - get user profile", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+
+		// Middleware to set user_id in context
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "507f1f77bcf86cd799439011")
+			c.Next()
+		})
+		router.GET("/profile", controller.GetProfile)
+
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     Domain.RoleUser,
+		}
+
+		mockUserUsecase.On("GetUserProfile", "507f1f77bcf86cd799439011").Return(expectedUser, nil)
+
+		req := httptest.NewRequest("GET", "/profile", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.User]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Profile retrieved successfully", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - user ID not found in context", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupGinContext()
+		router.GET("/profile", controller.GetProfile)
+
+		req := httptest.NewRequest("GET", "/profile", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "User ID not found in token", response.Message)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+
+		// Middleware to set user_id in context
+		router.Use(func(c *gin.Context) {
+			c.Set("user_id", "507f1f77bcf86cd799439011")
+			c.Next()
+		})
+		router.GET("/profile", controller.GetProfile)
+
+		mockUserUsecase.On("GetUserProfile", "507f1f77bcf86cd799439011").Return(nil, Domain.ErrUserNotFound)
+
+		req := httptest.NewRequest("GET", "/profile", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to retrieve user profile", response.Message)
+
+		mockUserUsecase.AssertExpectations(t)
+	})
+}
+
+// Task Controller Tests
+
+func TestController_UpdateProfile(t *testing.T) {
+	const userID = "507f1f77bcf86cd799439011"
+	setup := func(authenticated bool) (*gin.Engine, *MockUserUsecase) {
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupGinContext()
+		if authenticated {
+			router.Use(func(c *gin.Context) {
+				c.Set("user_id", userID)
+				c.Next()
+			})
+		}
+		router.PUT("/profile", controller.UpdateProfile)
+		return router, mockUserUsecase
+	}
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest("PUT", "/profile", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	t.Run("Success - profile updated and password stays hidden", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup(true)
+		updatedUser := &Domain.User{ID: primitive.NewObjectID(), Username: "newname", Email: "new@example.com", Password: "hashed_password", Role: Domain.RoleUser}
+		mockUserUsecase.On("UpdateProfile", userID, Domain.ProfileUpdateRequest{Username: "newname", Email: "new@example.com"}).Return(updatedUser, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"username":"newname","email":"new@example.com"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Profile updated successfully")
+		assert.Contains(t, w.Body.String(), `"username":"newname"`)
+		assert.NotContains(t, w.Body.String(), "hashed_password")
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - role and password in the payload are ignored", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup(true)
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+		mockUserUsecase.On("UpdateProfile", userID, Domain.ProfileUpdateRequest{Username: "testuser"}).Return(user, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"username":"testuser","role":"admin","password":"hijacked"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"role":"user"`)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - user ID not found in context", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup(false)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"username":"newname"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		mockUserUsecase.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid email", func(t *testing.T) {
+		// Arrange
+		router, mockUserUsecase := setup(true)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, newRequest(`{"email":"not-an-email"}`))
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserUsecase.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+	})
+
+	errorCases := []struct {
+		name   string
+		err    error
+		status int
+	}{
+		{"Error - username taken", Domain.ErrUsernameTaken, http.StatusConflict},
+		{"Error - email taken", Domain.ErrEmailTaken, http.StatusConflict},
+		{"Error - invalid username", Domain.NewError(Domain.ErrInvalidInput, "username must be between 3 and 32 characters"), http.StatusBadRequest},
+		{"Error - user not found", Domain.ErrUserNotFound, http.StatusNotFound},
+		{"Error - database failure", errors.New("database error"), http.StatusInternalServerError},
+	}
+	for _, tc := range errorCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			router, mockUserUsecase := setup(true)
+			mockUserUsecase.On("UpdateProfile", userID, Domain.ProfileUpdateRequest{Username: "newname"}).Return(nil, tc.err)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, newRequest(`{"username":"newname"}`))
+
+			// Assert
+			assert.Equal(t, tc.status, w.Code)
+			assert.Contains(t, w.Body.String(), tc.err.Error())
+		})
+	}
+}
+
+func TestController_GetAllTasks(t *testing.T) {
+	t.Run("Success - get all tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		expectedTasks := []*Domain.Task{
+			{
+				ID:          primitive.NewObjectID(),
+				Title:       "Task 1",
+				Description: "Description 1",
+				Status:      Domain.StatusPending,
+			},
+			{
+				ID:          primitive.NewObjectID(),
+				Title:       "Task 2",
+				Description: "Description 2",
+				Status:      Domain.StatusCompleted,
+			},
+		}
+
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{}).Return(expectedTasks, int64(2), nil)
+
+		req := httptest.NewRequest("GET", "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Tasks retrieved successfully", response.Message)
+		assert.Equal(t, Domain.PaginationMeta{Total: 2, Limit: 0, Offset: 0}, response.Meta)
+		assert.Equal(t, "2", w.Header().Get("X-Total-Count"))
+		if assert.Len(t, response.Data, 2) {
+			assert.Equal(t, "Task 1", response.Data[0].Title)
+			assert.Equal(t, Domain.StatusCompleted, response.Data[1].Status)
+		}
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - an empty page is an empty array with meta", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{Limit: 10}).Return([]*Domain.Task(nil), int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?limit=10", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"success":true,"message":"Tasks retrieved successfully","message_id":"tasks_retrieved","data":[],"meta":{"total":0,"limit":10,"offset":0}}`, w.Body.String())
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - only the selected fields are returned", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task 1", Status: Domain.StatusPending, UpdatedAt: time.Now()}
+		fields := []string{"id", "title", "status", "due_date"}
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{Fields: fields}).Return([]*Domain.Task{task}, int64(1), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?fields=id,title,status,due_date", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Data, 1)
+		assert.Len(t, response.Data[0], 4)
+		assert.Equal(t, task.ID.Hex(), response.Data[0]["id"])
+		assert.Equal(t, "Task 1", response.Data[0]["title"])
+		assert.NotContains(t, response.Data[0], "description")
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - unknown field lists the valid ones", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		req := httptest.NewRequest("GET", "/tasks?fields=id,secret", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid fields parameter", response.Message)
+		assert.Equal(t, Domain.ErrInvalidTaskFields.Error(), response.Error)
+		mockTaskUsecase.AssertNotCalled(t, "GetAllTasks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - get page of tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		expectedTasks := []*Domain.Task{
+			{
+				ID:     primitive.NewObjectID(),
+				Title:  "Task 11",
+				Status: Domain.StatusPending,
+			},
+		}
+
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{Limit: 10, Offset: 10}).Return(expectedTasks, int64(11), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?limit=10&offset=10", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, Domain.PaginationMeta{Total: 11, Limit: 10, Offset: 10}, response.Meta)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid pagination parameters", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			query         string
+			expectedError string
+		}{
+			{name: "non-numeric limit", query: "limit=abc", expectedError: "limit must be a positive integer"},
+			{name: "negative limit", query: "limit=-5", expectedError: "limit must be a positive integer"},
+			{name: "zero limit", query: "limit=0", expectedError: "limit must be a positive integer"},
+			{name: "limit above maximum", query: "limit=101", expectedError: "limit must not exceed 100"},
+			{name: "non-numeric offset", query: "offset=abc", expectedError: "offset must be a non-negative integer"},
+			{name: "negative offset", query: "offset=-1", expectedError: "offset must be a non-negative integer"},
+			{name: "unknown sort", query: "sort=title", expectedError: "invalid sort, must be one of: priority"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.GET("/tasks", controller.GetAllTasks)
+
+				req := httptest.NewRequest("GET", "/tasks?"+tt.query, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.False(t, response.Success)
+				assert.Equal(t, "Invalid pagination parameters", response.Message)
+				assert.Equal(t, tt.expectedError, response.Error)
+
+				mockTaskUsecase.AssertNotCalled(t, "GetAllTasks", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Success - filters passed to usecase", func(t *testing.T) {
+		dueAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		dueBefore := time.Date(2025, 1, 31, 23, 59, 59, 0, time.UTC) // End of the day, so tasks due that day match
+
+		tests := []struct {
+			name           string
+			query          string
+			expectedFilter Domain.TaskFilter
+		}{
+			{
+				name:           "status only",
+				query:          "status=pending",
+				expectedFilter: Domain.TaskFilter{Status: Domain.StatusPending},
+			},
+			{
+				name:           "priority only",
+				query:          "priority=urgent",
+				expectedFilter: Domain.TaskFilter{Priority: Domain.PriorityUrgent},
+			},
+			{
+				name:           "tag is normalized",
+				query:          "tag=%20Backend",
+				expectedFilter: Domain.TaskFilter{Tag: "backend"},
+			},
+			{
+				name:           "due_before only",
+				query:          "due_before=2025-01-31",
+				expectedFilter: Domain.TaskFilter{DueBefore: dueBefore},
+			},
+			{
+				name:           "due_after only",
+				query:          "due_after=2025-01-01",
+				expectedFilter: Domain.TaskFilter{DueAfter: dueAfter},
+			},
+			{
+				name:  "all filters combined",
+				query: "status=in_progress&due_before=2025-01-31&due_after=2025-01-01",
+				expectedFilter: Domain.TaskFilter{
+					Status:    Domain.StatusInProgress,
+					DueBefore: dueBefore,
+					DueAfter:  dueAfter,
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.GET("/tasks", controller.GetAllTasks)
+
+				mockTaskUsecase.On("GetAllTasks", adminCaller, tt.expectedFilter, Domain.Pagination{}).Return([]*Domain.Task{}, int64(0), nil)
+
+				req := httptest.NewRequest("GET", "/tasks?"+tt.query, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, http.StatusOK, w.Code)
+				mockTaskUsecase.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("Error - invalid filter parameters", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			query         string
+			expectedError string
+		}{
+			{name: "unknown status", query: "status=done", expectedError: "invalid status, must be one of: pending, in_progress, completed"},
+			{name: "empty status", query: "status=", expectedError: "invalid status, must be one of: pending, in_progress, completed"},
+			{name: "empty tag", query: "tag=", expectedError: "tag cannot be empty"},
+			{name: "unknown priority", query: "priority=critical", expectedError: "invalid priority, must be one of: low, medium, high, urgent"},
+			{name: "malformed due_before", query: "due_before=31-01-2025", expectedError: "invalid due_before format, use YYYY-MM-DD"},
+			{name: "malformed due_after", query: "due_after=tomorrow", expectedError: "invalid due_after format, use YYYY-MM-DD"},
+			{name: "inverted range", query: "due_after=2025-02-01&due_before=2025-01-01", expectedError: "due_after must not be later than due_before"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.GET("/tasks", controller.GetAllTasks)
+
+				req := httptest.NewRequest("GET", "/tasks?"+tt.query, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.False(t, response.Success)
+				assert.Equal(t, "Invalid filter parameters", response.Message)
+				assert.Equal(t, tt.expectedError, response.Error)
+
+				mockTaskUsecase.AssertNotCalled(t, "GetAllTasks", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Success - due_before includes tasks due that day in the default timezone", func(t *testing.T) {
+		// Arrange: a task due "2025-01-31" in UTC+3 is stored as 2025-01-31T20:59:59Z
+		t.Setenv("TASKS_DEFAULT_TIMEZONE", "Africa/Addis_Ababa")
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		expectedFilter := Domain.TaskFilter{
+			DueAfter:  time.Date(2025, 1, 30, 21, 0, 0, 0, time.UTC),
+			DueBefore: time.Date(2025, 1, 31, 20, 59, 59, 0, time.UTC),
+		}
+		mockTaskUsecase.On("GetAllTasks", adminCaller, expectedFilter, Domain.Pagination{}).Return([]*Domain.Task{}, int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?due_after=2025-01-31&due_before=2025-01-31", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - sort by priority passed to usecase", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		pagination := Domain.Pagination{Limit: 5, Sort: Domain.SortPriority}
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, pagination).Return([]*Domain.Task{}, int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?sort=priority&limit=5", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - admin includes deleted tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{IncludeDeleted: true}, Domain.Pagination{}).Return([]*Domain.Task{}, int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?include_deleted=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - archived lists only archived tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		mockTaskUsecase.On("GetAllTasks", userCaller, Domain.TaskFilter{OnlyArchived: true}, Domain.Pagination{}).Return([]*Domain.Task{}, int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?archived=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - regular user cannot include deleted tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		req := httptest.NewRequest("GET", "/tasks?include_deleted=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Access denied", response.Message)
+		mockTaskUsecase.AssertNotCalled(t, "GetAllTasks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid include_deleted value", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		req := httptest.NewRequest("GET", "/tasks?include_deleted=maybe", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "invalid include_deleted value, use true or false", response.Error)
+		mockTaskUsecase.AssertNotCalled(t, "GetAllTasks", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - max page limit from environment", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TASKS_MAX_PAGE_LIMIT", "500")
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{Limit: 250}).Return([]*Domain.Task{}, int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks?limit=250", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{}).Return([]*Domain.Task(nil), int64(0), errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to retrieve tasks", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_GetTaskByID(t *testing.T) {
+	t.Run("Success - get task by ID", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedTask := &Domain.Task{
+			ID:          primitive.NewObjectID(),
+			Title:       "Test Task",
+			Description: "Test Description",
+			Status:      Domain.StatusInProgress,
+		}
+
+		mockTaskUsecase.On("GetTaskByID", adminCaller, taskID).Return(expectedTask, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task retrieved successfully", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - only the selected fields are returned", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		taskID := primitive.NewObjectID()
+		task := &Domain.Task{ID: taskID, Title: "Test Task", Status: Domain.StatusInProgress, Version: 2}
+		mockTaskUsecase.On("GetTaskByIDWithFields", adminCaller, taskID.Hex(), []string{"title", "status"}).Return(task, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID.Hex()+"?fields=title,status", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response struct {
+			Data json.RawMessage `json:"data"`
+		}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"title":"Test Task","status":"in_progress"}`, string(response.Data))
+		assert.NotEmpty(t, w.Header().Get("ETag"))
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - fields cannot be combined with include", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		req := httptest.NewRequest("GET", "/tasks/"+primitive.NewObjectID().Hex()+"?fields=title&include=subtasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTaskUsecase.AssertNotCalled(t, "GetTaskWithSubtasks", mock.Anything, mock.Anything)
+		mockTaskUsecase.AssertNotCalled(t, "GetTaskByIDWithFields", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		taskID := primitive.NewObjectID().Hex()
+
+		mockTaskUsecase.On("GetTaskByID", adminCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Task not found", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - message is in the request's language", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("GetTaskByID", adminCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+		req = req.WithContext(Domain.WithLocale(req.Context(), "am"))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "ተግባሩ አልተገኘም", response.Message)
+		assert.Equal(t, Domain.MsgTaskNotFound, response.MessageID)
+	})
+
+	t.Run("Error - response carries the request ID", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("GetTaskByID", adminCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+		req = req.WithContext(Domain.WithRequestID(req.Context(), "req-123"))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "req-123", response.RequestID)
+	})
+
+	t.Run("Error - invalid task ID format", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		invalidID := "invalid-id"
+
+		mockTaskUsecase.On("GetTaskByID", adminCaller, invalidID).Return(nil, Domain.ErrInvalidTaskID)
+
+		req := httptest.NewRequest("GET", "/tasks/"+invalidID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Task not found", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - include subtasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		parentID := primitive.NewObjectID()
+		expectedTask := &Domain.Task{
+			ID:       parentID,
+			Title:    "Parent",
+			Subtasks: []*Domain.Task{{ID: primitive.NewObjectID(), Title: "Subtask", ParentTaskID: &parentID}},
+		}
+		mockTaskUsecase.On("GetTaskWithSubtasks", adminCaller, parentID.Hex()).Return(expectedTask, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+parentID.Hex()+"?include=subtasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Data.Subtasks, 1)
+		assert.Equal(t, "Subtask", response.Data.Subtasks[0].Title)
+
+		mockTaskUsecase.AssertNotCalled(t, "GetTaskByID", mock.Anything, mock.Anything)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - unknown include", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		req := httptest.NewRequest("GET", "/tasks/507f1f77bcf86cd799439099?include=comments", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "invalid include, must be one of: subtasks", response.Error)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+}
+
+func TestController_CreateTask(t *testing.T) {
+	t.Run("Success - create task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		taskReq := Domain.TaskRequest{
+			Title:       "New Task",
+			Description: "New Description",
+			DueDate:     "2024-12-31",
+			Status:      Domain.StatusPending,
+		}
+		expectedTask := &Domain.Task{
+			ID:          primitive.NewObjectID(),
+			Title:       "New Task",
+			Description: "New Description",
+			Status:      Domain.StatusPending,
+		}
+
+		mockTaskUsecase.On("CreateTask", adminCaller, taskReq, (*bool)(nil)).Return(expectedTask, nil)
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task created successfully", response.Message)
+		location := "/api/v1/tasks/" + expectedTask.ID.Hex()
+		assert.Equal(t, location, w.Header().Get("Location"))
+		if assert.NotNil(t, response.Data.Links) {
+			assert.Equal(t, location, response.Data.Links.Self)
+		}
+		assert.Nil(t, expectedTask.Links, "the task shared with event subscribers is left untouched")
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Invalid request payload", response.Message)
+	})
+
+	t.Run("Error - invalid fields", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			body     string
+			expected []Domain.FieldError
+		}{
+			{
+				name: "missing title and status",
+				body: `{"description":"no title"}`,
+				expected: []Domain.FieldError{
+					{Field: "title", Rule: "required", Message: "title is required"},
+					{Field: "status", Rule: "required", Message: "status is required"},
+				},
+			},
+			{
+				name:     "wrong type",
+				body:     `{"title":"Task","status":"pending","tags":"urgent"}`,
+				expected: []Domain.FieldError{{Field: "tags", Rule: "type", Message: "tags must be an array"}},
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks", controller.CreateTask)
+
+				req := httptest.NewRequest("POST", "/tasks", bytes.NewBufferString(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assertFieldErrors(t, w, tt.expected...)
+				mockTaskUsecase.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Strict mode - unknown fields", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			strict         string
+			body           string
+			expectedStatus int
+			expected       []Domain.FieldError
+		}{
+			{name: "ignored by default", strict: "", body: `{"title":"New Task","status":"pending","dueDate":"2024-12-31"}`, expectedStatus: http.StatusCreated},
+			{name: "correctly cased payload passes when strict", strict: "true", body: `{"title":"New Task","status":"pending"}`, expectedStatus: http.StatusCreated},
+			{name: "unknown top-level keys are listed when strict", strict: "true", body: `{"title":"New Task","status":"pending","dueDate":"2024-12-31","assignee":"bob"}`, expectedStatus: http.StatusBadRequest, expected: []Domain.FieldError{
+				{Field: "assignee", Rule: "unknown", Message: `unknown field "assignee"`},
+				{Field: "dueDate", Rule: "unknown", Message: `unknown field "dueDate"`},
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				t.Setenv("STRICT_JSON", tt.strict)
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks", controller.CreateTask)
+
+				taskReq := Domain.TaskRequest{Title: "New Task", Status: Domain.StatusPending}
+				mockTaskUsecase.On("CreateTask", adminCaller, taskReq, (*bool)(nil)).Return(&Domain.Task{ID: primitive.NewObjectID(), Title: "New Task"}, nil).Maybe()
+
+				req := httptest.NewRequest("POST", "/tasks", bytes.NewBufferString(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				if tt.expectedStatus == http.StatusBadRequest {
+					assertFieldErrors(t, w, tt.expected...)
+					mockTaskUsecase.AssertNotCalled(t, "CreateTask", mock.Anything, mock.Anything, mock.Anything)
+				}
+			})
+		}
+	})
+
+	t.Run("Error - create task failed", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		taskReq := Domain.TaskRequest{
+			Title:  "New Task",
+			Status: Domain.StatusPending,
+		}
+
+		mockTaskUsecase.On("CreateTask", adminCaller, taskReq, (*bool)(nil)).Return(nil, errors.New("validation error"))
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to create task", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - open task limit reached", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		taskReq := Domain.TaskRequest{
+			Title:  "New Task",
+			Status: Domain.StatusPending,
+		}
+
+		mockTaskUsecase.On("CreateTask", adminCaller, taskReq, (*bool)(nil)).Return(nil, fmt.Errorf("%w: 5 of 5 open tasks", Domain.ErrOpenTaskLimit))
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "open task limit reached: 5 of 5 open tasks", response.Error)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - due date out of range", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		taskReq := Domain.TaskRequest{
+			Title:   "New Task",
+			DueDate: "1970-01-01",
+			Status:  Domain.StatusPending,
+		}
+
+		mockTaskUsecase.On("CreateTask", adminCaller, taskReq, (*bool)(nil)).Return(nil, Domain.ErrDueDateInPast)
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "due date must not be in the past", response.Error)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - allow_duplicate is passed to the usecase", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		taskReq := Domain.TaskRequest{Title: "Deploy release", Status: Domain.StatusPending}
+		mockTaskUsecase.On("CreateTask", adminCaller, taskReq, mock.MatchedBy(func(allowDuplicate *bool) bool {
+			return allowDuplicate != nil && *allowDuplicate
+		})).Return(&Domain.Task{ID: primitive.NewObjectID(), Title: "Deploy release"}, nil)
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("POST", "/tasks?allow_duplicate=true", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - duplicate task returns 409 with the existing task's ID", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		existingID := primitive.NewObjectID()
+		taskReq := Domain.TaskRequest{Title: "Deploy release", Status: Domain.StatusPending}
+		mockTaskUsecase.On("CreateTask", adminCaller, taskReq, mock.MatchedBy(func(allowDuplicate *bool) bool {
+			return allowDuplicate != nil && !*allowDuplicate
+		})).Return(nil, &Domain.DuplicateTaskError{TaskID: existingID})
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("POST", "/tasks?allow_duplicate=false", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, existingID.Hex(), response.TaskID)
+		assert.Equal(t, "an open task with the same title already exists: "+existingID.Hex(), response.Error)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid allow_duplicate", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks", controller.CreateTask)
+
+		req := httptest.NewRequest("POST", "/tasks?allow_duplicate=maybe", bytes.NewBufferString(`{"title":"Task","status":"pending"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid allow_duplicate, must be true or false")
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+}
+
+func TestController_UpdateTask(t *testing.T) {
+	t.Run("Success - update task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PUT("/tasks/:id", controller.UpdateTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		taskReq := Domain.TaskRequest{
+			Title:       "Updated Task",
+			Description: "Updated Description",
+			Status:      Domain.StatusCompleted,
+		}
+		expectedTask := &Domain.Task{
+			ID:          primitive.NewObjectID(),
+			Title:       "Updated Task",
+			Description: "Updated Description",
+			Status:      Domain.StatusCompleted,
+		}
+
+		mockTaskUsecase.On("UpdateTask", adminCaller, taskID, taskReq).Return(expectedTask, nil)
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task updated successfully", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PUT("/tasks/:id", controller.UpdateTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		taskReq := Domain.TaskRequest{
+			Title:  "Updated Task",
+			Status: Domain.StatusCompleted,
+		}
+
+		mockTaskUsecase.On("UpdateTask", adminCaller, taskID, taskReq).Return(nil, Domain.ErrTaskNotFound)
+
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to update task", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PUT("/tasks/:id", controller.UpdateTask)
+
+		taskID := primitive.NewObjectID().Hex()
+
+		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer([]byte("invalid json")))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Invalid request payload", response.Message)
+	})
+
+	t.Run("Error - missing status", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PUT("/tasks/:id", controller.UpdateTask)
+
+		req := httptest.NewRequest("PUT", "/tasks/"+primitive.NewObjectID().Hex(), bytes.NewBufferString(`{"title":"Updated"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assertFieldErrors(t, w, Domain.FieldError{Field: "status", Rule: "required", Message: "status is required"})
+		mockTaskUsecase.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestController_PatchTask(t *testing.T) {
+	t.Run("Success - patch task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		status := Domain.StatusInProgress
+		patch := Domain.TaskPatchRequest{Status: &status}
+		expectedTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Existing Task",
+			Status: Domain.StatusInProgress,
+		}
+
+		mockTaskUsecase.On("PatchTask", adminCaller, taskID, patch).Return(expectedTask, nil)
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID, bytes.NewBufferString(`{"status":"in_progress"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task updated successfully", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - validation failure", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		status := "invalid_status"
+		patch := Domain.TaskPatchRequest{Status: &status}
+
+		mockTaskUsecase.On("PatchTask", adminCaller, taskID, patch).Return(nil, Domain.ErrInvalidStatus)
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID, bytes.NewBufferString(`{"status":"invalid_status"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to update task", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - illegal status transition", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		status := Domain.StatusPending
+		patch := Domain.TaskPatchRequest{Status: &status}
+
+		mockTaskUsecase.On("PatchTask", adminCaller, taskID, patch).Return(nil, fmt.Errorf("%w from completed to pending", Domain.ErrInvalidStatusTransition))
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID, bytes.NewBufferString(`{"status":"pending"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - due date too far ahead", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		dueDate := "2999-01-01"
+		patch := Domain.TaskPatchRequest{DueDate: &dueDate}
+
+		mockTaskUsecase.On("PatchTask", adminCaller, taskID, patch).Return(nil, Domain.NewError(Domain.ErrDueDateTooFar, "due date must be at most 10 years from now"))
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID, bytes.NewBufferString(`{"due_date":"2999-01-01"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		title := "New Title"
+		patch := Domain.TaskPatchRequest{Title: &title}
+
+		mockTaskUsecase.On("PatchTask", adminCaller, taskID, patch).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID, bytes.NewBufferString(`{"title":"New Title"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid JSON", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+primitive.NewObjectID().Hex(), bytes.NewBufferString("invalid json"))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Invalid request payload", response.Message)
+		mockTaskUsecase.AssertNotCalled(t, "PatchTask", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestController_DeleteTask(t *testing.T) {
+	t.Run("Success - delete task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks/:id", controller.DeleteTask)
+
+		taskID := primitive.NewObjectID().Hex()
+
+		mockTaskUsecase.On("DeleteTask", adminCaller, taskID).Return(nil)
+
+		req := httptest.NewRequest("DELETE", "/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.MessageResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task deleted successfully", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks/:id", controller.DeleteTask)
+
+		taskID := primitive.NewObjectID().Hex()
+
+		mockTaskUsecase.On("DeleteTask", adminCaller, taskID).Return(Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("DELETE", "/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to delete task", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid task ID format", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks/:id", controller.DeleteTask)
+
+		invalidID := "invalid-id"
+
+		mockTaskUsecase.On("DeleteTask", adminCaller, invalidID).Return(Domain.ErrInvalidTaskID)
+
+		req := httptest.NewRequest("DELETE", "/tasks/"+invalidID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Failed to delete task", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - parent with subtasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks/:id", controller.DeleteTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("DeleteTask", adminCaller, taskID).Return(Domain.ErrTaskHasSubtasks)
+
+		req := httptest.NewRequest("DELETE", "/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "task has subtasks, delete them before deleting the parent task", response.Error)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+// Test constructor
+func TestNewController(t *testing.T) {
+	mockTaskUsecase := new(MockTaskUsecase)
+	mockUserUsecase := new(MockUserUsecase)
+	mockCommentUsecase := new(MockCommentUsecase)
+	mockAttachmentUsecase := new(MockAttachmentUsecase)
+	mockWorkLogUsecase := new(MockWorkLogUsecase)
+	mockAuditUsecase := new(MockAuditUsecase)
+	mockPasswordResetUsecase := new(MockPasswordResetUsecase)
+	mockAPIKeyUsecase := new(MockAPIKeyUsecase)
+	mockWebhookUsecase := new(MockWebhookUsecase)
+	mockReportUsecase := new(MockReportUsecase)
+
+	controller := NewController(mockTaskUsecase, mockUserUsecase, mockCommentUsecase, mockAttachmentUsecase, mockWorkLogUsecase, mockAuditUsecase, mockPasswordResetUsecase, mockAPIKeyUsecase, mockWebhookUsecase, mockReportUsecase, "/api/v1", Infrastructure.NewNopLogger())
+
+	assert.NotNil(t, controller)
+	assert.Equal(t, mockTaskUsecase, controller.taskUsecase)
+	assert.Equal(t, mockUserUsecase, controller.userUsecase)
+	assert.Equal(t, mockCommentUsecase, controller.commentUsecase)
+	assert.Equal(t, mockAttachmentUsecase, controller.attachmentUsecase)
+	assert.Equal(t, mockWorkLogUsecase, controller.workLogUsecase)
+	assert.Equal(t, mockAuditUsecase, controller.auditUsecase)
+	assert.Equal(t, mockPasswordResetUsecase, controller.passwordResetUsecase)
+	assert.Equal(t, mockAPIKeyUsecase, controller.apiKeyUsecase)
+	assert.Equal(t, mockWebhookUsecase, controller.webhookUsecase)
+	assert.Equal(t, mockReportUsecase, controller.reportUsecase)
+	assert.Equal(t, "/api/v1", controller.apiBasePath)
+}
+
+func TestController_GetDeletedTasks(t *testing.T) {
+	t.Run("Success - get deleted tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.GET("/tasks/trash", controller.GetDeletedTasks)
+
+		deletedAt := time.Now()
+		expectedTasks := []*Domain.Task{
+			{
+				ID:        primitive.NewObjectID(),
+				Title:     "Deleted Task",
+				Status:    Domain.StatusPending,
+				DeletedAt: &deletedAt,
+			},
+		}
+
+		mockTaskUsecase.On("GetDeletedTasks", Domain.Pagination{Limit: 10}).Return(expectedTasks, int64(1), nil)
+
+		req := httptest.NewRequest("GET", "/tasks/trash?limit=10", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Deleted tasks retrieved successfully", response.Message)
+		assert.Equal(t, Domain.PaginationMeta{Total: 1, Limit: 10, Offset: 0}, response.Meta)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.GET("/tasks/trash", controller.GetDeletedTasks)
+
+		mockTaskUsecase.On("GetDeletedTasks", Domain.Pagination{}).Return([]*Domain.Task(nil), int64(0), errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/tasks/trash", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Failed to retrieve deleted tasks", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_RestoreTask(t *testing.T) {
+	t.Run("Success - restore task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/:id/restore", controller.RestoreTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Restored Task",
+			Status: Domain.StatusPending,
+		}
+
+		mockTaskUsecase.On("RestoreTask", adminCaller, taskID).Return(expectedTask, nil)
+
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/restore", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task restored successfully", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - restore failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "not deleted", err: Domain.ErrTaskNotDeleted, expectedStatus: http.StatusConflict},
+			{name: "not found", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "invalid ID", err: Domain.ErrInvalidTaskID, expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks/:id/restore", controller.RestoreTask)
+
+				taskID := primitive.NewObjectID().Hex()
+				mockTaskUsecase.On("RestoreTask", adminCaller, taskID).Return(nil, tt.err)
+
+				req := httptest.NewRequest("POST", "/tasks/"+taskID+"/restore", nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.False(t, response.Success)
+				assert.Equal(t, "Failed to restore task", response.Message)
+				assert.Equal(t, tt.err.Error(), response.Error)
+
+				mockTaskUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestController_ArchiveTask(t *testing.T) {
+	t.Run("Success - archive task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/:id/archive", controller.ArchiveTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Done", Status: Domain.StatusCompleted, Archived: true}
+		mockTaskUsecase.On("ArchiveTask", adminCaller, taskID, false).Return(expectedTask, nil)
+
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/archive", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Task archived successfully", response.Message)
+		assert.True(t, response.Data.Archived)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - force is passed to the usecase", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/:id/archive", controller.ArchiveTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("ArchiveTask", adminCaller, taskID, true).Return(&Domain.Task{Archived: true}, nil)
+
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/archive?force=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid force value", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/:id/archive", controller.ArchiveTask)
+
+		req := httptest.NewRequest("POST", "/tasks/"+primitive.NewObjectID().Hex()+"/archive?force=maybe", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), "invalid force, must be true or false")
+		mockTaskUsecase.AssertNotCalled(t, "ArchiveTask", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - archive failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "not completed", err: Domain.ErrTaskNotCompleted, expectedStatus: http.StatusConflict},
+			{name: "already archived", err: Domain.ErrTaskArchived, expectedStatus: http.StatusConflict},
+			{name: "not found", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "invalid ID", err: Domain.ErrInvalidTaskID, expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks/:id/archive", controller.ArchiveTask)
+
+				taskID := primitive.NewObjectID().Hex()
+				mockTaskUsecase.On("ArchiveTask", adminCaller, taskID, false).Return(nil, tt.err)
+
+				req := httptest.NewRequest("POST", "/tasks/"+taskID+"/archive", nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Failed to archive task", response.Message)
+				assert.Equal(t, tt.err.Error(), response.Error)
+			})
+		}
+	})
+}
+
+func TestController_UnarchiveTask(t *testing.T) {
+	t.Run("Success - unarchive task", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/:id/unarchive", controller.UnarchiveTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("UnarchiveTask", adminCaller, taskID).Return(&Domain.Task{Title: "Done"}, nil)
+
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/unarchive", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), "Task unarchived successfully")
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - task is not archived", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/:id/unarchive", controller.UnarchiveTask)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("UnarchiveTask", adminCaller, taskID).Return(nil, Domain.ErrTaskNotArchived)
+
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/unarchive", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusConflict, w.Code)
+		assert.Contains(t, w.Body.String(), "Failed to unarchive task")
+	})
+}
+
+func TestController_ArchiveCompletedTasks(t *testing.T) {
+	t.Run("Success - a date archives tasks completed before that day", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/archive", controller.ArchiveCompletedTasks)
+
+		cutoff := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+		mockTaskUsecase.On("ArchiveCompletedTasks", adminCaller, cutoff).Return(&Domain.BulkResult{MatchedCount: 2, ModifiedCount: 2}, nil)
+
+		req := httptest.NewRequest("POST", "/tasks/archive?completed_before=2025-03-01", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.BulkResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Tasks archived successfully", response.Message)
+		assert.Equal(t, int64(2), response.Data.ModifiedCount)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - an RFC 3339 time is read as is", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/archive", controller.ArchiveCompletedTasks)
+
+		cutoff := time.Date(2025, 3, 1, 10, 30, 0, 0, time.UTC)
+		mockTaskUsecase.On("ArchiveCompletedTasks", adminCaller, cutoff).Return(&Domain.BulkResult{}, nil)
+
+		req := httptest.NewRequest("POST", "/tasks/archive?completed_before=2025-03-01T12:30:00%2B02:00", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - completed_before is missing or malformed", func(t *testing.T) {
+		for _, query := range []string{"", "?completed_before=yesterday"} {
+			// Arrange
+			controller, mockTaskUsecase, _ := setupTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.POST("/tasks/archive", controller.ArchiveCompletedTasks)
+
+			req := httptest.NewRequest("POST", "/tasks/archive"+query, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusBadRequest, w.Code, query)
+			mockTaskUsecase.AssertNotCalled(t, "ArchiveCompletedTasks", mock.Anything, mock.Anything)
+		}
+	})
+}
+
+func TestController_PurgeDeletedTasks(t *testing.T) {
+	t.Run("Success - purge with default age", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.DELETE("/tasks/trash", controller.PurgeDeletedTasks)
+
+		mockTaskUsecase.On("PurgeDeletedTasks", DefaultPurgeOlderThanDays).Return(int64(2), nil)
+
+		req := httptest.NewRequest("DELETE", "/tasks/trash", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.PurgeResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, int64(2), response.PurgedCount)
+		assert.Equal(t, DefaultPurgeOlderThanDays, response.OlderThanDays)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - purge with custom age", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.DELETE("/tasks/trash", controller.PurgeDeletedTasks)
+
+		mockTaskUsecase.On("PurgeDeletedTasks", 7).Return(int64(0), nil)
+
+		req := httptest.NewRequest("DELETE", "/tasks/trash?older_than_days=7", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid age", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.DELETE("/tasks/trash", controller.PurgeDeletedTasks)
+
+		req := httptest.NewRequest("DELETE", "/tasks/trash?older_than_days=-3", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "older_than_days must be a non-negative integer", response.Error)
+		mockTaskUsecase.AssertNotCalled(t, "PurgeDeletedTasks", mock.Anything)
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.DELETE("/tasks/trash", controller.PurgeDeletedTasks)
+
+		mockTaskUsecase.On("PurgeDeletedTasks", DefaultPurgeOlderThanDays).Return(int64(0), errors.New("database error"))
+
+		req := httptest.NewRequest("DELETE", "/tasks/trash", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_TaskOwnership(t *testing.T) {
+	t.Run("Success - regular user identity passed to usecase", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks", controller.GetAllTasks)
+
+		mockTaskUsecase.On("GetAllTasks", userCaller, Domain.TaskFilter{}, Domain.Pagination{}).Return([]*Domain.Task{}, int64(0), nil)
+
+		req := httptest.NewRequest("GET", "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - regular user reading another user's task gets 404", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id", controller.GetTaskByID)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskUsecase.On("GetTaskByID", userCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Task not found", response.Message)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing identity returns 401", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			method string
+			path   string
+			route  func(router *gin.Engine, controller *Controller)
+		}{
+			{
+				name:   "list tasks",
+				method: "GET",
+				path:   "/tasks",
+				route:  func(r *gin.Engine, ctrl *Controller) { r.GET("/tasks", ctrl.GetAllTasks) },
+			},
+			{
+				name:   "get task",
+				method: "GET",
+				path:   "/tasks/507f1f77bcf86cd799439099",
+				route:  func(r *gin.Engine, ctrl *Controller) { r.GET("/tasks/:id", ctrl.GetTaskByID) },
+			},
+			{
+				name:   "create task",
+				method: "POST",
+				path:   "/tasks",
+				route:  func(r *gin.Engine, ctrl *Controller) { r.POST("/tasks", ctrl.CreateTask) },
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupGinContext()
+				tt.route(router, controller)
+
+				req := httptest.NewRequest(tt.method, tt.path, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "User ID not found in token", response.Message)
+				assert.Empty(t, mockTaskUsecase.Calls)
+			})
+		}
+	})
+}
+
+func TestController_GetAssignedTasks(t *testing.T) {
+	t.Run("Success - get assigned tasks", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/assigned-to-me", controller.GetAssignedTasks)
+
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Assigned Task", Status: Domain.StatusPending},
+		}
+		filter := Domain.TaskFilter{Status: Domain.StatusPending}
+		pagination := Domain.Pagination{Limit: 10}
+
+		mockTaskUsecase.On("GetAssignedTasks", userCaller, filter, pagination).Return(expectedTasks, int64(1), nil)
+
+		req := httptest.NewRequest("GET", "/tasks/assigned-to-me?status=pending&limit=10", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Assigned tasks retrieved successfully", response.Message)
+		assert.Equal(t, int64(1), response.Meta.Total)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid filter", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/assigned-to-me", controller.GetAssignedTasks)
+
+		req := httptest.NewRequest("GET", "/tasks/assigned-to-me?status=unknown", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - missing identity returns 401", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupGinContext()
+		router.GET("/tasks/assigned-to-me", controller.GetAssignedTasks)
+
+		req := httptest.NewRequest("GET", "/tasks/assigned-to-me", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+}
+
+func TestController_CreateTasks(t *testing.T) {
+	taskReqs := []Domain.TaskRequest{
+		{Title: "First", Status: Domain.StatusPending},
+		{Title: "", Status: Domain.StatusPending},
+	}
+
+	t.Run("Bulk results map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name            string
+			query           string
+			atomic          bool
+			result          *Domain.BulkCreateResult
+			expectedStatus  int
+			expectedSuccess bool
+		}{
+			{
+				name:            "all created",
+				result:          &Domain.BulkCreateResult{Created: []string{"a", "b"}, Errors: []Domain.BulkItemError{}},
+				expectedStatus:  http.StatusCreated,
+				expectedSuccess: true,
+			},
+			{
+				name:           "partially created",
+				result:         &Domain.BulkCreateResult{Created: []string{"a"}, Errors: []Domain.BulkItemError{{Index: 1, Error: "title cannot be empty"}}},
+				expectedStatus: http.StatusMultiStatus,
+			},
+			{
+				name:           "atomic batch refused",
+				query:          "?atomic=true",
+				atomic:         true,
+				result:         &Domain.BulkCreateResult{Created: []string{}, Errors: []Domain.BulkItemError{{Index: 1, Error: "title cannot be empty"}}},
+				expectedStatus: http.StatusUnprocessableEntity,
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks/bulk", controller.CreateTasks)
+
+				mockTaskUsecase.On("CreateTasks", adminCaller, taskReqs, tt.atomic).Return(tt.result, nil)
+
+				reqBody, _ := json.Marshal(taskReqs)
+				req := httptest.NewRequest("POST", "/tasks/bulk"+tt.query, bytes.NewBuffer(reqBody))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.BulkCreateResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, tt.expectedSuccess, response.Success)
+				assert.Equal(t, tt.result, response.Data)
+				mockTaskUsecase.AssertExpectations(t)
+			})
+		}
+	})
+
+	t.Run("Error - body is not an array", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/bulk", controller.CreateTasks)
+
+		req := httptest.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(`{"title":"Task"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - invalid atomic flag", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/bulk", controller.CreateTasks)
+
+		req := httptest.NewRequest("POST", "/tasks/bulk?atomic=maybe", bytes.NewBufferString(`[]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - usecase rejects the batch", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/bulk", controller.CreateTasks)
+
+		mockTaskUsecase.On("CreateTasks", adminCaller, []Domain.TaskRequest{}, false).Return(nil, Domain.NewError(Domain.ErrInvalidInput, "no tasks provided"))
+
+		req := httptest.NewRequest("POST", "/tasks/bulk", bytes.NewBufferString(`[]`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_ImportTasks(t *testing.T) {
+	imports := []Domain.TaskImport{
+		{TaskRequest: Domain.TaskRequest{Title: "Old", Status: Domain.StatusPending}, CreatedAt: "2023-03-01T08:00:00Z"},
+		{TaskRequest: Domain.TaskRequest{Title: "", Status: Domain.StatusPending}},
+	}
+	result := &Domain.ImportResult{
+		Imported: 1,
+		Skipped:  1,
+		Created:  []string{"a"},
+		Errors:   []Domain.BulkItemError{{Index: 1, Error: "title cannot be empty"}},
+	}
+
+	t.Run("Success - raw JSON body", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/import", controller.ImportTasks)
+
+		mockTaskUsecase.On("ImportTasks", adminCaller, imports, true).Return(result, nil)
+
+		reqBody, _ := json.Marshal(imports)
+		req := httptest.NewRequest("POST", "/tasks/import?skip_duplicates=true", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ImportResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Imported 1 tasks, skipped 1", response.Message)
+		assert.Equal(t, result, response.Data)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - multipart file upload", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/import", controller.ImportTasks)
+
+		mockTaskUsecase.On("ImportTasks", adminCaller, imports, false).Return(result, nil)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		part, _ := writer.CreateFormFile("file", "tasks.json")
+		_ = json.NewEncoder(part).Encode(imports)
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/tasks/import", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			query         string
+			body          string
+			expectedError string
+		}{
+			{name: "invalid skip_duplicates", query: "?skip_duplicates=maybe", body: "[]", expectedError: "invalid skip_duplicates, must be true or false"},
+			{name: "body is not an array", body: `{"title":"x"}`},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks/import", controller.ImportTasks)
+
+				req := httptest.NewRequest("POST", "/tasks/import"+tt.query, bytes.NewBufferString(tt.body))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				if tt.expectedError != "" {
+					var response Domain.ErrorResponse
+					_ = json.Unmarshal(w.Body.Bytes(), &response)
+					assert.Equal(t, tt.expectedError, response.Error)
+				}
+				mockTaskUsecase.AssertNotCalled(t, "ImportTasks", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Error - multipart without file", func(t *testing.T) {
+		// Arrange
+		controller, _, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/import", controller.ImportTasks)
+
+		body := &bytes.Buffer{}
+		writer := multipart.NewWriter(body)
+		_ = writer.WriteField("other", "value")
+		writer.Close()
+
+		req := httptest.NewRequest("POST", "/tasks/import", body)
+		req.Header.Set("Content-Type", writer.FormDataContentType())
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Error - usecase failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "empty import", err: Domain.NewError(Domain.ErrInvalidInput, "no tasks provided"), expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks/import", controller.ImportTasks)
+
+				mockTaskUsecase.On("ImportTasks", adminCaller, mock.Anything, false).Return(nil, tt.err)
+
+				req := httptest.NewRequest("POST", "/tasks/import", bytes.NewBufferString("[]"))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				mockTaskUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestController_UpdateTasksStatus(t *testing.T) {
+	t.Run("Success - returns matched and modified counts", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/tasks/bulk-status", controller.UpdateTasksStatus)
+
+		ids := []string{primitive.NewObjectID().Hex(), primitive.NewObjectID().Hex()}
+		expected := &Domain.BulkResult{MatchedCount: 2, ModifiedCount: 1}
+		mockTaskUsecase.On("UpdateTasksStatus", adminCaller, ids, Domain.StatusCompleted).Return(expected, nil)
+
+		reqBody, _ := json.Marshal(Domain.BulkStatusRequest{IDs: ids, Status: Domain.StatusCompleted})
+		req := httptest.NewRequest("POST", "/tasks/bulk-status", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.BulkResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, expected, response.Data)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "empty id list", err: Domain.NewError(Domain.ErrInvalidInput, "no task IDs provided"), expectedStatus: http.StatusBadRequest},
+			{name: "malformed id", err: fmt.Errorf("%w: bad-id", Domain.ErrInvalidTaskID), expectedStatus: http.StatusBadRequest},
+			{name: "invalid status", err: Domain.ErrInvalidStatus, expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.POST("/tasks/bulk-status", controller.UpdateTasksStatus)
+
+				mockTaskUsecase.On("UpdateTasksStatus", adminCaller, []string{"bad-id"}, "completed").Return(nil, tt.err)
+
+				req := httptest.NewRequest("POST", "/tasks/bulk-status", bytes.NewBufferString(`{"ids":["bad-id"],"status":"completed"}`))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+			})
+		}
+	})
+}
+
+func TestController_DeleteTasksByStatus(t *testing.T) {
+	t.Run("Success - returns matched and modified counts", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks", controller.DeleteTasksByStatus)
+
+		expected := &Domain.BulkResult{MatchedCount: 3, ModifiedCount: 3}
+		mockTaskUsecase.On("DeleteTasksByStatus", adminCaller, Domain.StatusCompleted).Return(expected, nil)
+
+		req := httptest.NewRequest("DELETE", "/tasks?status=completed", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.BulkResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, response.Data)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing status", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks", controller.DeleteTasksByStatus)
+
+		req := httptest.NewRequest("DELETE", "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - invalid status", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.DELETE("/tasks", controller.DeleteTasksByStatus)
+
+		mockTaskUsecase.On("DeleteTasksByStatus", adminCaller, "done").Return(nil, Domain.ErrInvalidStatus)
+
+		req := httptest.NewRequest("DELETE", "/tasks?status=done", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+func TestController_CountTasks(t *testing.T) {
+	t.Run("Success - count matches the filtered list", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks", controller.GetAllTasks)
+		router.GET("/tasks/count", controller.CountTasks)
+
+		filter := Domain.TaskFilter{Status: Domain.StatusPending}
+		tasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "First", Status: Domain.StatusPending},
+			{ID: primitive.NewObjectID(), Title: "Second", Status: Domain.StatusPending},
+		}
+		mockTaskUsecase.On("GetAllTasks", userCaller, filter, Domain.Pagination{}).Return(tasks, int64(len(tasks)), nil)
+		mockTaskUsecase.On("CountTasks", userCaller, filter).Return(int64(len(tasks)), nil)
+
+		listRecorder := httptest.NewRecorder()
+		countRecorder := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(listRecorder, httptest.NewRequest("GET", "/tasks?status=pending", nil))
+		router.ServeHTTP(countRecorder, httptest.NewRequest("GET", "/tasks/count?status=pending", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, countRecorder.Code)
+
+		var listResponse Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(listRecorder.Body.Bytes(), &listResponse)
+		assert.NoError(t, err)
+
+		var countResponse Domain.CountResponse
+		err = json.Unmarshal(countRecorder.Body.Bytes(), &countResponse)
+		assert.NoError(t, err)
+		assert.True(t, countResponse.Success)
+		assert.Equal(t, int64(len(listResponse.Data)), countResponse.Count)
+		assert.Equal(t, strconv.Itoa(len(listResponse.Data)), listRecorder.Header().Get("X-Total-Count"))
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - deleted tasks require admin", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/count", controller.CountTasks)
+
+		req := httptest.NewRequest("GET", "/tasks/count?include_deleted=true", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusForbidden, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - invalid filter", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/count", controller.CountTasks)
+
+		req := httptest.NewRequest("GET", "/tasks/count?due_before=tomorrow", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+}
+
+func TestController_ExportUsers(t *testing.T) {
+	createdAt := time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC)
+	lastLogin := time.Date(2025, 1, 2, 8, 30, 0, 0, time.UTC)
+	users := []*Domain.User{
+		{ID: primitive.NewObjectID(), Username: "doe, jane", Email: "jane@corp.example", Role: Domain.RoleManager, CreatedAt: createdAt, LastLoginAt: &lastLogin, IsActive: true},
+		{ID: primitive.NewObjectID(), Username: "newcomer", Role: Domain.RoleUser, CreatedAt: createdAt},
+	}
+
+	// streamUsers makes the mocked usecase feed users to the export callback
+	streamUsers := func(args mock.Arguments) {
+		fn := args.Get(1).(func(user *Domain.User) error)
+		for _, user := range users {
+			if err := fn(user); err != nil {
+				return
+			}
+		}
+	}
+
+	t.Run("Success - CSV with escaped fields and a dated filename", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/users/export", controller.ExportUsers)
+
+		filter := Domain.UserFilter{Query: "corp", Role: Domain.RoleManager}
+		mockUserUsecase.On("ExportUsers", filter, mock.Anything).Run(streamUsers).Return(nil)
+
+		req := httptest.NewRequest("GET", "/users/export?q=corp&role=manager", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		today := time.Now().In(Usecases.DefaultLocation()).Format("2006-01-02")
+		assert.Equal(t, `attachment; filename="users-`+today+`.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Contains(t, w.Body.String(), `"doe, jane"`)
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "username", "email", "role", "created_at", "last_login_at", "is_active"},
+			{users[0].ID.Hex(), "doe, jane", "jane@corp.example", "manager", "2024-12-01T09:00:00Z", "2025-01-02T08:30:00Z", "true"},
+			{users[1].ID.Hex(), "newcomer", "", "user", "2024-12-01T09:00:00Z", "", "false"},
+		}, records)
+		mockUserUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - empty export still has the header row", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/users/export", controller.ExportUsers)
+
+		mockUserUsecase.On("ExportUsers", Domain.UserFilter{}, mock.Anything).Return(nil)
+
+		req := httptest.NewRequest("GET", "/users/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id,username,email,role,created_at,last_login_at,is_active\n", w.Body.String())
+	})
+
+	t.Run("Error - invalid role", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/users/export", controller.ExportUsers)
+
+		req := httptest.NewRequest("GET", "/users/export?role=owner", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockUserUsecase.AssertNotCalled(t, "ExportUsers", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - failure before the first row is a JSON error", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/users/export", controller.ExportUsers)
+
+		mockUserUsecase.On("ExportUsers", Domain.UserFilter{}, mock.Anything).Return(errors.New("database unavailable"))
+
+		req := httptest.NewRequest("GET", "/users/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.MsgExportUsersFailed, response.MessageID)
+	})
+}
+
+func TestController_ExportTasks(t *testing.T) {
+	createdAt := time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC)
+	tasks := []*Domain.Task{
+		{
+			ID:          primitive.NewObjectID(),
+			Title:       "Plan, then build",
+			Description: "Line one\nsaid \"hi\"",
+			Status:      Domain.StatusPending,
+			DueDate:     time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC),
+			CreatedAt:   createdAt,
+			UpdatedAt:   createdAt,
+		},
+		{ID: primitive.NewObjectID(), Title: "No due date", Status: Domain.StatusCompleted, CreatedAt: createdAt, UpdatedAt: createdAt},
+	}
+
+	// streamTasks makes the mocked usecase feed tasks to the export callback
+	streamTasks := func(args mock.Arguments) {
+		fn := args.Get(2).(func(task *Domain.Task) error)
+		for _, task := range tasks {
+			if err := fn(task); err != nil {
+				return
+			}
+		}
+	}
+
+	t.Run("Success - CSV with escaped fields", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/export", controller.ExportTasks)
+
+		filter := Domain.TaskFilter{Status: Domain.StatusPending}
+		mockTaskUsecase.On("ExportTasks", userCaller, filter, mock.Anything).Run(streamTasks).Return(nil)
+
+		req := httptest.NewRequest("GET", "/tasks/export?format=csv&status=pending", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="tasks.csv"`, w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+
+		records, err := csv.NewReader(w.Body).ReadAll()
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{
+			{"id", "title", "description", "status", "due_date", "created_at", "updated_at"},
+			{tasks[0].ID.Hex(), "Plan, then build", "Line one\nsaid \"hi\"", "pending", "2024-12-31T17:00:00Z", "2024-12-01T09:00:00Z", "2024-12-01T09:00:00Z"},
+			{tasks[1].ID.Hex(), "No due date", "", "completed", "", "2024-12-01T09:00:00Z", "2024-12-01T09:00:00Z"},
+		}, records)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - JSON download", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/export", controller.ExportTasks)
+
+		mockTaskUsecase.On("ExportTasks", adminCaller, Domain.TaskFilter{}, mock.Anything).Run(streamTasks).Return(nil)
+
+		req := httptest.NewRequest("GET", "/tasks/export?format=json", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, `attachment; filename="tasks.json"`, w.Header().Get("Content-Disposition"))
+
+		var exported []*Domain.Task
+		err := json.Unmarshal(w.Body.Bytes(), &exported)
+		assert.NoError(t, err)
+		assert.Len(t, exported, 2)
+		assert.Equal(t, tasks[0].Title, exported[0].Title)
+	})
+
+	t.Run("Success - empty export has only the header row", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/export", controller.ExportTasks)
+
+		mockTaskUsecase.On("ExportTasks", adminCaller, Domain.TaskFilter{}, mock.Anything).Return(nil)
+
+		req := httptest.NewRequest("GET", "/tasks/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "id,title,description,status,due_date,created_at,updated_at\n", w.Body.String())
+	})
+
+	t.Run("Error - unknown format", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/export", controller.ExportTasks)
+
+		req := httptest.NewRequest("GET", "/tasks/export?format=xlsx", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - failure before any task is a JSON error", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/export", controller.ExportTasks)
+
+		mockTaskUsecase.On("ExportTasks", adminCaller, Domain.TaskFilter{}, mock.Anything).Return(errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/tasks/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Failed to export tasks", response.Message)
+	})
+}
+
+func TestController_GetOverdueTasks(t *testing.T) {
+	t.Run("Success - paginated overdue tasks with days overdue", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/overdue", controller.GetOverdueTasks)
+
+		daysOverdue := 3
+		tasks := []*Domain.Task{{ID: primitive.NewObjectID(), Title: "Late", DaysOverdue: &daysOverdue}}
+		mockTaskUsecase.On("GetOverdueTasks", userCaller, Domain.Pagination{Limit: 5, Offset: 5}).Return(tasks, int64(6), nil)
+
+		req := httptest.NewRequest("GET", "/tasks/overdue?limit=5&offset=5", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"days_overdue":3`)
+
+		var response Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.PaginationMeta{Total: 6, Limit: 5, Offset: 5}, response.Meta)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid pagination", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/overdue", controller.GetOverdueTasks)
+
+		req := httptest.NewRequest("GET", "/tasks/overdue?limit=0", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - sort rejected by usecase", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/overdue", controller.GetOverdueTasks)
+
+		mockTaskUsecase.On("GetOverdueTasks", userCaller, Domain.Pagination{Sort: Domain.SortPriority}).Return(nil, int64(0), Domain.NewError(Domain.ErrInvalidInput, "invalid sort, overdue tasks are always sorted by due date"))
+
+		req := httptest.NewRequest("GET", "/tasks/overdue?sort=priority", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestController_SearchTasks(t *testing.T) {
+	t.Run("Success - paginated results with relevance scores", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/search", controller.SearchTasks)
+
+		score := 1.5
+		tasks := []*Domain.Task{{ID: primitive.NewObjectID(), Title: "Invoice", Score: &score}}
+		mockTaskUsecase.On("SearchTasks", userCaller, "invoice client", Domain.Pagination{Limit: 5, Offset: 5}).Return(tasks, int64(6), nil)
+
+		req := httptest.NewRequest("GET", "/tasks/search?q=invoice+client&limit=5&offset=5", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `"score":1.5`)
+
+		var response Domain.ListResponse[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.MsgTasksSearched, response.MessageID)
+		assert.Equal(t, Domain.PaginationMeta{Total: 6, Limit: 5, Offset: 5}, response.Meta)
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - empty query rejected by usecase", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/search", controller.SearchTasks)
+
+		mockTaskUsecase.On("SearchTasks", userCaller, "", Domain.Pagination{}).Return(nil, int64(0), Domain.NewError(Domain.ErrInvalidInput, "search query is required"))
+
+		req := httptest.NewRequest("GET", "/tasks/search", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Contains(t, w.Body.String(), Domain.MsgSearchTasksFailed)
+	})
+}
+
+func TestController_GetStats(t *testing.T) {
+	t.Run("Success - stats JSON shape", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/stats", controller.GetStats)
+
+		stats := &Domain.TaskStats{
+			ByStatus:      map[string]int64{Domain.StatusPending: 2, Domain.StatusInProgress: 1, Domain.StatusCompleted: 4},
+			Overdue:       1,
+			DueNext7Days:  2,
+			CreatedPerDay: []Domain.DailyCount{{Date: "2024-12-30", Count: 0}, {Date: "2024-12-31", Count: 3}},
+		}
+		mockTaskUsecase.On("GetStats", userCaller).Return(stats, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/stats", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{
+			"success": true,
+			"message": "Task statistics retrieved successfully",
+			"message_id": "task_statistics_retrieved",
+			"data": {
+				"by_status": {"pending": 2, "in_progress": 1, "completed": 4},
+				"overdue": 1,
+				"due_next_7_days": 2,
+				"created_per_day": [
+					{"date": "2024-12-30", "count": 0},
+					{"date": "2024-12-31", "count": 3}
+				]
+			}
+		}`, w.Body.String())
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository failure", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/stats", controller.GetStats)
+
+		mockTaskUsecase.On("GetStats", userCaller).Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/tasks/stats", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
+
+func TestController_UpdateTaskStatus(t *testing.T) {
+	t.Run("Success - assignee updates status", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.PATCH("/tasks/:id/status", controller.UpdateTaskStatus)
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Assigned Task",
+			Status: Domain.StatusCompleted,
+		}
+
+		mockTaskUsecase.On("UpdateTaskStatus", userCaller, taskID, Domain.StatusCompleted).Return(expectedTask, nil)
+
+		body := `{"status":"completed"}`
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID+"/status", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.Response[*Domain.Task]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Task status updated successfully", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing status", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.PATCH("/tasks/:id/status", controller.UpdateTaskStatus)
+
+		req := httptest.NewRequest("PATCH", "/tasks/507f1f77bcf86cd799439099/status", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockTaskUsecase.Calls)
+	})
+
+	t.Run("Error - status failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "not assignee", err: Domain.ErrNotAssignee, expectedStatus: http.StatusForbidden},
+			{name: "not found", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "invalid status", err: Domain.ErrInvalidStatus, expectedStatus: http.StatusBadRequest},
+			{name: "illegal transition", err: fmt.Errorf("%w from pending to completed", Domain.ErrInvalidStatusTransition), expectedStatus: http.StatusUnprocessableEntity},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.PATCH("/tasks/:id/status", controller.UpdateTaskStatus)
+
+				taskID := primitive.NewObjectID().Hex()
+				mockTaskUsecase.On("UpdateTaskStatus", userCaller, taskID, "completed").Return(nil, tt.err)
+
+				req := httptest.NewRequest("PATCH", "/tasks/"+taskID+"/status", bytes.NewBufferString(`{"status":"completed"}`))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Failed to update task status", response.Message)
+				assert.Equal(t, tt.err.Error(), response.Error)
+
+				mockTaskUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestController_GetTags(t *testing.T) {
+	t.Run("Success - get tags", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/tags", controller.GetTags)
+
+		mockTaskUsecase.On("GetTags", userCaller).Return([]string{"backend", "sprint-12"}, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/tags", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[string]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Tags retrieved successfully", response.Message)
+		assert.Equal(t, []string{"backend", "sprint-12"}, response.Data)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/tags", controller.GetTags)
+
+		mockTaskUsecase.On("GetTags", adminCaller).Return(nil, errors.New("database error"))
+
+		req := httptest.NewRequest("GET", "/tasks/tags", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Failed to retrieve tags", response.Message)
+
+		mockTaskUsecase.AssertExpectations(t)
+	})
+}
+
+// Comment Controller Tests
+func TestController_AddComment(t *testing.T) {
+	t.Run("Success - add comment", func(t *testing.T) {
+		// Arrange
+		controller, mockCommentUsecase := setupCommentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.POST("/tasks/:id/comments", controller.AddComment)
+
+		taskID := primitive.NewObjectID().Hex()
+		commentReq := Domain.CommentRequest{Body: "Looks good"}
+		expectedComment := &Domain.Comment{ID: primitive.NewObjectID(), Body: "Looks good"}
+		mockCommentUsecase.On("AddComment", userCaller, taskID, commentReq).Return(expectedComment, nil)
+
+		body, _ := json.Marshal(commentReq)
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/comments", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response Domain.Response[*Domain.Comment]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Comment added successfully", response.Message)
+
+		mockCommentUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Success - author in body is ignored", func(t *testing.T) {
+		// Arrange
+		controller, mockCommentUsecase := setupCommentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.POST("/tasks/:id/comments", controller.AddComment)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockCommentUsecase.On("AddComment", userCaller, taskID, Domain.CommentRequest{Body: "Hi"}).Return(&Domain.Comment{Body: "Hi"}, nil)
+
+		body := `{"body":"Hi","author_id":"` + testAdminID + `"}`
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/comments", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		mockCommentUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing body", func(t *testing.T) {
+		// Arrange
+		controller, mockCommentUsecase := setupCommentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.POST("/tasks/:id/comments", controller.AddComment)
+
+		req := httptest.NewRequest("POST", "/tasks/507f1f77bcf86cd799439099/comments", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Empty(t, mockCommentUsecase.Calls)
+	})
+
+	t.Run("Error - comment failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "task not found", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "body too long", err: Domain.NewError(Domain.ErrInvalidInput, "comment body must not exceed 2000 characters"), expectedStatus: http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockCommentUsecase := setupCommentTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.POST("/tasks/:id/comments", controller.AddComment)
+
+				taskID := primitive.NewObjectID().Hex()
+				mockCommentUsecase.On("AddComment", userCaller, taskID, Domain.CommentRequest{Body: "Hi"}).Return(nil, tt.err)
+
+				req := httptest.NewRequest("POST", "/tasks/"+taskID+"/comments", bytes.NewBufferString(`{"body":"Hi"}`))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Failed to add comment", response.Message)
+				assert.Equal(t, tt.err.Error(), response.Error)
+
+				mockCommentUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestController_GetComments(t *testing.T) {
+	t.Run("Success - get comments", func(t *testing.T) {
+		// Arrange
+		controller, mockCommentUsecase := setupCommentTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id/comments", controller.GetComments)
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedComments := []*Domain.Comment{{ID: primitive.NewObjectID(), Body: "First"}}
+		mockCommentUsecase.On("GetComments", adminCaller, taskID).Return(expectedComments, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/comments", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.Comment]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Comments retrieved successfully", response.Message)
+
+		mockCommentUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		controller, mockCommentUsecase := setupCommentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/comments", controller.GetComments)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockCommentUsecase.On("GetComments", userCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/comments", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockCommentUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - missing identity returns 401", func(t *testing.T) {
+		// Arrange
+		controller, mockCommentUsecase := setupCommentTestController()
+		router := setupGinContext()
+		router.GET("/tasks/:id/comments", controller.GetComments)
+
+		req := httptest.NewRequest("GET", "/tasks/507f1f77bcf86cd799439099/comments", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Empty(t, mockCommentUsecase.Calls)
+	})
+}
+
+// attachmentUploadBody builds a multipart body with one file part named "file"
+func attachmentUploadBody(filename, content string) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	_ = writer.WriteField("note", "ignored")
+	part, _ := writer.CreateFormFile("file", filename)
+	_, _ = part.Write([]byte(content))
+	writer.Close()
+	return body, writer.FormDataContentType()
+}
+
+func TestController_LogWork(t *testing.T) {
+	t.Run("Success - work logged", func(t *testing.T) {
+		// Arrange
+		controller, mockWorkLogUsecase := setupWorkLogTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.POST("/tasks/:id/worklogs", controller.LogWork)
+
+		taskID := primitive.NewObjectID().Hex()
+		workLogReq := Domain.WorkLogRequest{Minutes: 90, Note: "Review", Date: "2024-03-01"}
+		mockWorkLogUsecase.On("LogWork", userCaller, taskID, workLogReq).Return(&Domain.WorkLog{ID: primitive.NewObjectID(), Minutes: 90}, nil)
+
+		body, _ := json.Marshal(workLogReq)
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/worklogs", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+
+		var response Domain.Response[*Domain.WorkLog]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "Work logged successfully", response.Message)
+		assert.Equal(t, 90, response.Data.Minutes)
+		mockWorkLogUsecase.AssertExpectations(t)
+	})
+
+	t.Run("Error - usecase errors map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "invalid minutes", err: Domain.NewError(Domain.ErrInvalidInput, "minutes must be positive"), expectedStatus: http.StatusBadRequest},
+			{name: "hidden task", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "day already full", err: Domain.ErrDailyWorkLimit, expectedStatus: http.StatusConflict},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockWorkLogUsecase := setupWorkLogTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.POST("/tasks/:id/worklogs", controller.LogWork)
+
+				taskID := primitive.NewObjectID().Hex()
+				mockWorkLogUsecase.On("LogWork", userCaller, taskID, Domain.WorkLogRequest{Minutes: 30}).Return(nil, tt.err)
+
+				req := httptest.NewRequest("POST", "/tasks/"+taskID+"/worklogs", bytes.NewBufferString(`{"minutes": 30}`))
+				req.Header.Set("Content-Type", "application/json")
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, Domain.MsgLogWorkFailed, response.MessageID)
+			})
+		}
+	})
+
+	t.Run("Error - missing minutes", func(t *testing.T) {
+		// Arrange
+		controller, mockWorkLogUsecase := setupWorkLogTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.POST("/tasks/:id/worklogs", controller.LogWork)
+
+		req := httptest.NewRequest("POST", "/tasks/"+primitive.NewObjectID().Hex()+"/worklogs", bytes.NewBufferString(`{"note": "Forgot"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockWorkLogUsecase.AssertNotCalled(t, "LogWork", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestController_GetWorkLogs(t *testing.T) {
+	t.Run("Success - work logs listed", func(t *testing.T) {
+		// Arrange
+		controller, mockWorkLogUsecase := setupWorkLogTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/worklogs", controller.GetWorkLogs)
+
+		taskID := primitive.NewObjectID().Hex()
+		workLogs := []*Domain.WorkLog{{ID: primitive.NewObjectID(), Minutes: 30}, {ID: primitive.NewObjectID(), Minutes: 45}}
+		mockWorkLogUsecase.On("GetWorkLogs", userCaller, taskID).Return(workLogs, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/worklogs", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.WorkLog]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Len(t, response.Data, 2)
+		assert.Equal(t, int64(2), response.Meta.Total)
+	})
+
+	t.Run("Error - hidden task", func(t *testing.T) {
+		// Arrange
+		controller, mockWorkLogUsecase := setupWorkLogTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/worklogs", controller.GetWorkLogs)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockWorkLogUsecase.On("GetWorkLogs", userCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/worklogs", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestController_GetWorkLogSummary(t *testing.T) {
+	t.Run("Success - filters passed through", func(t *testing.T) {
+		// Arrange
+		controller, mockWorkLogUsecase := setupWorkLogTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/worklogs/summary", controller.GetWorkLogSummary)
+
+		summaries := []*Domain.WorkLogSummary{{UserID: primitive.NewObjectID(), TotalMinutes: 120, Entries: 2}}
+		mockWorkLogUsecase.On("GetSummary", adminCaller, testUserID, "2024-03-01", "2024-03-31").Return(summaries, nil)
+
+		req := httptest.NewRequest("GET", "/worklogs/summary?user_id="+testUserID+"&from=2024-03-01&to=2024-03-31", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.WorkLogSummary]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, summaries[0].TotalMinutes, response.Data[0].TotalMinutes)
+	})
+
+	t.Run("Error - invalid date", func(t *testing.T) {
+		// Arrange
+		controller, mockWorkLogUsecase := setupWorkLogTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/worklogs/summary", controller.GetWorkLogSummary)
+
+		mockWorkLogUsecase.On("GetSummary", adminCaller, "", "March", "").Return(nil, Domain.NewError(Domain.ErrInvalidInput, "dates must use the YYYY-MM-DD format"))
+
+		req := httptest.NewRequest("GET", "/worklogs/summary?from=March", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusBadRequest, w.Code)
 
-func (m *MockTaskUsecase) UpdateTask(id string, taskReq Domain.TaskRequest) (*Domain.Task, error) {
-	args := m.Called(id, taskReq)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*Domain.Task), args.Error(1)
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.MsgRetrieveWorkLogSummaryFailed, response.MessageID)
+	})
 }
 
-func (m *MockTaskUsecase) DeleteTask(id string) error {
-	args := m.Called(id)
-	return args.Error(0)
-}
+func TestController_UploadAttachment(t *testing.T) {
+	t.Run("Success - upload attachment", func(t *testing.T) {
+		// Arrange
+		controller, mockAttachmentUsecase := setupAttachmentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.POST("/tasks/:id/attachments", controller.UploadAttachment)
 
-type MockUserUsecase struct {
-	mock.Mock
-}
+		taskID := primitive.NewObjectID().Hex()
+		expectedAttachment := &Domain.Attachment{ID: primitive.NewObjectID(), Filename: "notes.txt", ContentType: "text/plain; charset=utf-8", Size: 5}
+		mockAttachmentUsecase.On("AddAttachment", userCaller, taskID, "notes.txt", "hello").Return(expectedAttachment, nil)
 
-func (m *MockUserUsecase) RegisterUser(userReq Domain.UserRequest) (*Domain.User, error) {
-	args := m.Called(userReq)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*Domain.User), args.Error(1)
-}
+		body, contentType := attachmentUploadBody("notes.txt", "hello")
+		req := httptest.NewRequest("POST", "/tasks/"+taskID+"/attachments", body)
+		req.Header.Set("Content-Type", contentType)
+		w := httptest.NewRecorder()
 
-func (m *MockUserUsecase) LoginUser(loginReq Domain.LoginRequest) (*Domain.User, string, error) {
-	args := m.Called(loginReq)
-	if args.Get(0) == nil {
-		return nil, args.String(1), args.Error(2)
-	}
-	return args.Get(0).(*Domain.User), args.String(1), args.Error(2)
-}
+		// Act
+		router.ServeHTTP(w, req)
 
-func (m *MockUserUsecase) GetUserProfile(userID string) (*Domain.User, error) {
-	args := m.Called(userID)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*Domain.User), args.Error(1)
-}
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
 
-func (m *MockUserUsecase) GetAllUsers() ([]*Domain.User, error) {
-	args := m.Called()
-	return args.Get(0).([]*Domain.User), args.Error(1)
-}
+		var response Domain.Response[*Domain.Attachment]
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.True(t, response.Success)
+		assert.Equal(t, "Attachment uploaded successfully", response.Message)
+		assert.Equal(t, "notes.txt", response.Data.Filename)
+		assert.NotContains(t, w.Body.String(), "storage_key")
 
-func (m *MockUserUsecase) PromoteUserToAdmin(username string) (*Domain.User, error) {
-	args := m.Called(username)
-	if args.Get(0) == nil {
-		return nil, args.Error(1)
-	}
-	return args.Get(0).(*Domain.User), args.Error(1)
-}
+		mockAttachmentUsecase.AssertExpectations(t)
+	})
 
-// Test setup helper
-func setupTestController() (*Controller, *MockTaskUsecase, *MockUserUsecase) {
-	mockTaskUsecase := new(MockTaskUsecase)
-	mockUserUsecase := new(MockUserUsecase)
-	controller := NewController(mockTaskUsecase, mockUserUsecase)
-	return controller, mockTaskUsecase, mockUserUsecase
-}
+	t.Run("Error - invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name        string
+			contentType string
+			body        string
+		}{
+			{name: "not multipart", contentType: "application/json", body: `{"file":"x"}`},
+			{name: "no file field", contentType: "multipart/form-data; boundary=b", body: "--b\r\nContent-Disposition: form-data; name=\"note\"\r\n\r\nhi\r\n--b--\r\n"},
+		}
 
-func setupGinContext() *gin.Engine {
-	gin.SetMode(gin.TestMode)
-	return gin.New()
-}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockAttachmentUsecase := setupAttachmentTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.POST("/tasks/:id/attachments", controller.UploadAttachment)
 
-// User Controller Tests
+				req := httptest.NewRequest("POST", "/tasks/507f1f77bcf86cd799439099/attachments", strings.NewReader(tt.body))
+				req.Header.Set("Content-Type", tt.contentType)
+				w := httptest.NewRecorder()
 
-func TestController_Register(t *testing.T) {
-	t.Run("Success - register user", func(t *testing.T) {
-		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/register", controller.Register)
+				// Act
+				router.ServeHTTP(w, req)
 
-		userReq := Domain.UserRequest{
-			Username: "testuser",
-			Password: "password123",
+				// Assert
+				assert.Equal(t, http.StatusBadRequest, w.Code)
+				assert.Empty(t, mockAttachmentUsecase.Calls)
+			})
 		}
-		expectedUser := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: "testuser",
-			Role:     Domain.RoleUser,
+	})
+
+	t.Run("Error - upload failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "task not found", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "file too large", err: Domain.NewError(Domain.ErrAttachmentTooLarge, "attachment must not exceed 64 bytes"), expectedStatus: http.StatusRequestEntityTooLarge},
+			{name: "request body too large", err: &http.MaxBytesError{Limit: 64}, expectedStatus: http.StatusRequestEntityTooLarge},
+			{name: "unsupported type", err: Domain.NewError(Domain.ErrUnsupportedAttachmentType, "attachment content type application/x-msdownload is not allowed"), expectedStatus: http.StatusUnsupportedMediaType},
+			{name: "empty file", err: Domain.NewError(Domain.ErrInvalidInput, "attachment is empty"), expectedStatus: http.StatusBadRequest},
+			{name: "storage failure", err: errors.New("disk full"), expectedStatus: http.StatusInternalServerError},
 		}
 
-		mockUserUsecase.On("RegisterUser", userReq).Return(expectedUser, nil)
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockAttachmentUsecase := setupAttachmentTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.POST("/tasks/:id/attachments", controller.UploadAttachment)
 
-		reqBody, _ := json.Marshal(userReq)
-		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+				taskID := primitive.NewObjectID().Hex()
+				mockAttachmentUsecase.On("AddAttachment", userCaller, taskID, "file.bin", "data").Return(nil, tt.err)
+
+				body, contentType := attachmentUploadBody("file.bin", "data")
+				req := httptest.NewRequest("POST", "/tasks/"+taskID+"/attachments", body)
+				req.Header.Set("Content-Type", contentType)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.Equal(t, "Failed to upload attachment", response.Message)
+
+				mockAttachmentUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestController_GetAttachments(t *testing.T) {
+	t.Run("Success - get attachments", func(t *testing.T) {
+		// Arrange
+		controller, mockAttachmentUsecase := setupAttachmentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/attachments", controller.GetAttachments)
+
+		taskID := primitive.NewObjectID().Hex()
+		attachments := []*Domain.Attachment{{ID: primitive.NewObjectID(), Filename: "a.png"}, {ID: primitive.NewObjectID(), Filename: "b.pdf"}}
+		mockAttachmentUsecase.On("GetAttachments", userCaller, taskID).Return(attachments, nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/attachments", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusCreated, w.Code)
-		
-		var response Domain.UserResponse
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.Attachment]
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "User registered successfully", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, "Attachments retrieved successfully", response.Message)
+		assert.Len(t, response.Data, 2)
+		assert.Equal(t, int64(2), response.Meta.Total)
+
+		mockAttachmentUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid JSON", func(t *testing.T) {
+	t.Run("Error - task not found", func(t *testing.T) {
 		// Arrange
-		controller, _, _ := setupTestController()
-		router := setupGinContext()
-		router.POST("/register", controller.Register)
+		controller, mockAttachmentUsecase := setupAttachmentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/attachments", controller.GetAttachments)
 
-		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer([]byte("invalid json")))
-		req.Header.Set("Content-Type", "application/json")
+		taskID := primitive.NewObjectID().Hex()
+		mockAttachmentUsecase.On("GetAttachments", userCaller, taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/attachments", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Invalid request payload", response.Message)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+		mockAttachmentUsecase.AssertExpectations(t)
 	})
+}
 
-	t.Run("Error - username already exists", func(t *testing.T) {
-		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/register", controller.Register)
+func TestController_DownloadAttachment(t *testing.T) {
+	taskID := primitive.NewObjectID().Hex()
+	attachmentID := primitive.NewObjectID().Hex()
 
-		userReq := Domain.UserRequest{
-			Username: "existinguser",
-			Password: "password123",
-		}
+	t.Run("Success - file is streamed with its name and type", func(t *testing.T) {
+		// Arrange
+		controller, mockAttachmentUsecase := setupAttachmentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/attachments/:attachmentId", controller.DownloadAttachment)
 
-		mockUserUsecase.On("RegisterUser", userReq).Return(nil, errors.New("username already exists"))
+		attachment := &Domain.Attachment{Filename: "résumé.pdf", ContentType: "application/pdf", Size: 8}
+		mockAttachmentUsecase.On("OpenAttachment", userCaller, taskID, attachmentID).Return(attachment, io.NopCloser(strings.NewReader("%PDF-1.7")), nil)
 
-		reqBody, _ := json.Marshal(userReq)
-		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/attachments/"+attachmentID, nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusConflict, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to create user", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+		assert.Equal(t, "8", w.Header().Get("Content-Length"))
+		assert.Equal(t, "attachment; filename*=utf-8''r%C3%A9sum%C3%A9.pdf", w.Header().Get("Content-Disposition"))
+		assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+		assert.Equal(t, "%PDF-1.7", w.Body.String())
+
+		mockAttachmentUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - other registration error", func(t *testing.T) {
+	t.Run("Error - attachment not found", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/register", controller.Register)
-
-		userReq := Domain.UserRequest{
-			Username: "testuser",
-			Password: "password123",
-		}
+		controller, mockAttachmentUsecase := setupAttachmentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/attachments/:attachmentId", controller.DownloadAttachment)
 
-		mockUserUsecase.On("RegisterUser", userReq).Return(nil, errors.New("database error"))
+		mockAttachmentUsecase.On("OpenAttachment", userCaller, taskID, attachmentID).Return(nil, nil, Domain.ErrAttachmentNotFound)
 
-		reqBody, _ := json.Marshal(userReq)
-		req := httptest.NewRequest("POST", "/register", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest("GET", "/tasks/"+taskID+"/attachments/"+attachmentID, nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to create user", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, "Failed to download attachment", response.Message)
+
+		mockAttachmentUsecase.AssertExpectations(t)
 	})
 }
 
-func TestController_Login(t *testing.T) {
-	t.Run("Success - login user", func(t *testing.T) {
-		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/login", controller.Login)
+func TestController_DeleteAttachment(t *testing.T) {
+	taskID := primitive.NewObjectID().Hex()
+	attachmentID := primitive.NewObjectID().Hex()
 
-		loginReq := Domain.LoginRequest{
-			Username: "testuser",
-			Password: "password123",
-		}
-		expectedUser := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: "testuser",
-			Role:     Domain.RoleUser,
-		}
-		expectedToken := "jwt.token.here"
+	t.Run("Success - delete attachment", func(t *testing.T) {
+		// Arrange
+		controller, mockAttachmentUsecase := setupAttachmentTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.DELETE("/tasks/:id/attachments/:attachmentId", controller.DeleteAttachment)
 
-		mockUserUsecase.On("LoginUser", loginReq).Return(expectedUser, expectedToken, nil)
+		mockAttachmentUsecase.On("DeleteAttachment", userCaller, taskID, attachmentID).Return(nil)
 
-		reqBody, _ := json.Marshal(loginReq)
-		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest("DELETE", "/tasks/"+taskID+"/attachments/"+attachmentID, nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -270,95 +6186,263 @@ func TestController_Login(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.LoginResponse
+
+		var response Domain.MessageResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "Login successful", response.Message)
-		assert.Equal(t, expectedToken, response.Token)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, "Attachment deleted successfully", response.Message)
+
+		mockAttachmentUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid JSON", func(t *testing.T) {
+	t.Run("Error - delete failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "not the uploader", err: Domain.ErrNotUploader, expectedStatus: http.StatusForbidden},
+			{name: "attachment not found", err: Domain.ErrAttachmentNotFound, expectedStatus: http.StatusNotFound},
+			{name: "invalid attachment ID", err: Domain.ErrInvalidAttachmentID, expectedStatus: http.StatusBadRequest},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockAttachmentUsecase := setupAttachmentTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.DELETE("/tasks/:id/attachments/:attachmentId", controller.DeleteAttachment)
+
+				mockAttachmentUsecase.On("DeleteAttachment", userCaller, taskID, attachmentID).Return(tt.err)
+
+				req := httptest.NewRequest("DELETE", "/tasks/"+taskID+"/attachments/"+attachmentID, nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				mockAttachmentUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestController_GetTaskRevisions(t *testing.T) {
+	taskID := primitive.NewObjectID()
+
+	t.Run("Success - paginated revisions", func(t *testing.T) {
 		// Arrange
-		controller, _, _ := setupTestController()
-		router := setupGinContext()
-		router.POST("/login", controller.Login)
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+		router.GET("/tasks/:id/revisions", controller.GetTaskRevisions)
 
-		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer([]byte("invalid json")))
-		req.Header.Set("Content-Type", "application/json")
+		pagination := Domain.Pagination{Limit: 5}
+		revisions := []*Domain.TaskRevision{
+			{ID: primitive.NewObjectID(), TaskID: taskID, EditorID: testAdminID, Previous: map[string]interface{}{"description": "Old text"}},
+		}
+		mockTaskUsecase.On("GetTaskRevisions", userCaller, taskID.Hex(), pagination).Return(revisions, int64(6), nil)
+
+		req := httptest.NewRequest("GET", "/tasks/"+taskID.Hex()+"/revisions?limit=5", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.ListResponse[*Domain.TaskRevision]
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Invalid request payload", response.Message)
+		assert.True(t, response.Success)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, "Old text", response.Data[0].Previous["description"])
+		assert.Equal(t, int64(6), response.Meta.Total)
+		mockTaskUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - authentication failed", func(t *testing.T) {
-		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/login", controller.Login)
+	t.Run("Error - failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "task not found", err: Domain.ErrTaskNotFound, expectedStatus: http.StatusNotFound},
+			{name: "invalid task ID", err: Domain.ErrInvalidTaskID, expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
 
-		loginReq := Domain.LoginRequest{
-			Username: "testuser",
-			Password: "wrongpassword",
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testUserID, Domain.RoleUser)
+				router.GET("/tasks/:id/revisions", controller.GetTaskRevisions)
+
+				mockTaskUsecase.On("GetTaskRevisions", userCaller, taskID.Hex(), Domain.Pagination{}).Return(nil, int64(0), tt.err)
+
+				req := httptest.NewRequest("GET", "/tasks/"+taskID.Hex()+"/revisions", nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				mockTaskUsecase.AssertExpectations(t)
+			})
+		}
+	})
+}
+
+func TestIfMatchVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected *int
+		wantErr  bool
+	}{
+		{name: "missing header", header: ""},
+		{name: "plain version", header: "3", expected: intPtr(3)},
+		{name: "quoted weak version", header: `W/"7"`, expected: intPtr(7)},
+		{name: "task ETag", header: `W/"5-1733043600000000000"`, expected: intPtr(5)},
+		{name: "not a number", header: `"abc"`, wantErr: true},
+		{name: "negative", header: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest("PUT", "/tasks/1", nil)
+			if tt.header != "" {
+				c.Request.Header.Set("If-Match", tt.header)
+			}
+
+			// Act
+			version, err := ifMatchVersion(c)
+
+			// Assert
+			if tt.wantErr {
+				assert.EqualError(t, err, "invalid If-Match header, must be the task version")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, version)
+		})
+	}
+}
+
+func intPtr(value int) *int {
+	return &value
+}
+
+func TestEtagMatches(t *testing.T) {
+	etag := `W/"3-100"`
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		expected    bool
+	}{
+		{name: "missing header", ifNoneMatch: "", expected: false},
+		{name: "exact match", ifNoneMatch: `W/"3-100"`, expected: true},
+		{name: "strong form of the same tag", ifNoneMatch: `"3-100"`, expected: true},
+		{name: "one of several", ifNoneMatch: `W/"2-90", W/"3-100"`, expected: true},
+		{name: "wildcard", ifNoneMatch: "*", expected: true},
+		{name: "stale tag", ifNoneMatch: `W/"2-90"`, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, etagMatches(tt.ifNoneMatch, etag))
+		})
+	}
+}
+
+func TestTaskListETag(t *testing.T) {
+	older := time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)
+	newer := older.Add(time.Hour)
+	tasks := []*Domain.Task{{UpdatedAt: newer}, {UpdatedAt: older}}
+
+	assert.Equal(t, weakETag(2, newer), taskListETag(tasks, 2))
+	assert.NotEqual(t, taskListETag(tasks, 2), taskListETag(tasks[1:], 1))
+}
+
+func TestController_ETags(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	task := &Domain.Task{ID: taskID, Title: "Task", Status: Domain.StatusPending, Version: 4, UpdatedAt: time.Date(2024, 12, 1, 9, 0, 0, 0, time.UTC)}
+	etag := weakETag(4, task.UpdatedAt)
+
+	t.Run("Single task", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			ifNoneMatch    string
+			expectedStatus int
+		}{
+			{name: "missing header returns the task", expectedStatus: http.StatusOK},
+			{name: "matching header is not modified", ifNoneMatch: etag, expectedStatus: http.StatusNotModified},
+			{name: "stale header returns the task", ifNoneMatch: weakETag(3, task.UpdatedAt), expectedStatus: http.StatusOK},
 		}
 
-		mockUserUsecase.On("LoginUser", loginReq).Return(nil, "", errors.New("invalid credentials"))
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockTaskUsecase, _ := setupTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.GET("/tasks/:id", controller.GetTaskByID)
+
+				mockTaskUsecase.On("GetTaskByID", adminCaller, taskID.Hex()).Return(task, nil)
+
+				req := httptest.NewRequest("GET", "/tasks/"+taskID.Hex(), nil)
+				if tt.ifNoneMatch != "" {
+					req.Header.Set("If-None-Match", tt.ifNoneMatch)
+				}
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+				if tt.expectedStatus == http.StatusNotModified {
+					assert.Empty(t, w.Body.String())
+				} else {
+					assert.NotEmpty(t, w.Body.String())
+				}
+			})
+		}
+	})
 
-		reqBody, _ := json.Marshal(loginReq)
-		req := httptest.NewRequest("POST", "/login", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
-		w := httptest.NewRecorder()
+	t.Run("Logged work changes the tag", func(t *testing.T) {
+		// Arrange
+		loggedMinutes := 30
+		logged := *task
+		logged.TotalLoggedMinutes = &loggedMinutes
 
 		// Act
-		router.ServeHTTP(w, req)
+		tagged := taskETag(&logged)
 
 		// Assert
-		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Authentication failed", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, etag, taskETag(task))
+		assert.NotEqual(t, etag, tagged)
+		assert.True(t, strings.HasPrefix(tagged, `W/"4-`))
 	})
-}
 
-func TestController_PromoteUser(t *testing.T) {
-	t.Run("Success - promote user", func(t *testing.T) {
+	t.Run("Task with subtasks is not tagged", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/promote", controller.PromoteUser)
-
-		promoteReq := Domain.PromoteRequest{
-			Username: "usertoPromote",
-		}
-		expectedUser := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: "usertoPromote",
-			Role:     Domain.RoleAdmin,
-		}
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks/:id", controller.GetTaskByID)
 
-		mockUserUsecase.On("PromoteUserToAdmin", promoteReq.Username).Return(expectedUser, nil)
+		mockTaskUsecase.On("GetTaskWithSubtasks", adminCaller, taskID.Hex()).Return(task, nil)
 
-		reqBody, _ := json.Marshal(promoteReq)
-		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest("GET", "/tasks/"+taskID.Hex()+"?include=subtasks", nil)
+		req.Header.Set("If-None-Match", etag)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -366,161 +6450,123 @@ func TestController_PromoteUser(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.UserResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "User promoted to admin successfully", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Empty(t, w.Header().Get("ETag"))
 	})
 
-	t.Run("Error - user not found", func(t *testing.T) {
+	t.Run("Task list", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.POST("/promote", controller.PromoteUser)
-
-		promoteReq := Domain.PromoteRequest{
-			Username: "nonexistentuser",
-		}
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/tasks", controller.GetAllTasks)
 
-		mockUserUsecase.On("PromoteUserToAdmin", promoteReq.Username).Return(nil, errors.New("user not found"))
+		tasks := []*Domain.Task{task}
+		mockTaskUsecase.On("GetAllTasks", adminCaller, Domain.TaskFilter{}, Domain.Pagination{}).Return(tasks, int64(1), nil)
 
-		reqBody, _ := json.Marshal(promoteReq)
-		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest("GET", "/tasks", nil)
+		req.Header.Set("If-None-Match", taskListETag(tasks, 1))
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to promote user", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Equal(t, taskListETag(tasks, 1), w.Header().Get("ETag"))
+		assert.Equal(t, "1", w.Header().Get("X-Total-Count"))
+		assert.Empty(t, w.Body.String())
 	})
+}
 
-	t.Run("Error - invalid JSON", func(t *testing.T) {
+func TestController_VersionConflicts(t *testing.T) {
+	conflict := Domain.ErrVersionConflict
+
+	t.Run("Error - stale If-Match on patch is a conflict", func(t *testing.T) {
 		// Arrange
-		controller, _, _ := setupTestController()
-		router := setupGinContext()
-		router.POST("/promote", controller.PromoteUser)
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PATCH("/tasks/:id", controller.PatchTask)
 
-		req := httptest.NewRequest("POST", "/promote", bytes.NewBuffer([]byte("invalid json")))
+		taskID := primitive.NewObjectID().Hex()
+		title := "Renamed"
+		patch := Domain.TaskPatchRequest{Title: &title, Version: intPtr(3)}
+		mockTaskUsecase.On("PatchTask", adminCaller, taskID, patch).Return(nil, conflict)
+
+		req := httptest.NewRequest("PATCH", "/tasks/"+taskID, bytes.NewBufferString(`{"title":"Renamed","version":1}`))
 		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", `"3"`)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
+		assert.Equal(t, http.StatusConflict, w.Code)
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Invalid request payload", response.Message)
+		assert.Equal(t, conflict.Error(), response.Error)
+		mockTaskUsecase.AssertExpectations(t)
 	})
-}
 
-func TestController_GetAllUsers(t *testing.T) {
-	t.Run("Success - get all users", func(t *testing.T) {
+	t.Run("Error - stale body version on update is a conflict", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.GET("/users", controller.GetAllUsers)
-
-		expectedUsers := []*Domain.User{
-			{
-				ID:       primitive.NewObjectID(),
-				Username: "user1",
-				Role:     Domain.RoleUser,
-			},
-			{
-				ID:       primitive.NewObjectID(),
-				Username: "admin1",
-				Role:     Domain.RoleAdmin,
-			},
-		}
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PUT("/tasks/:id", controller.UpdateTask)
 
-		mockUserUsecase.On("GetAllUsers").Return(expectedUsers, nil)
+		taskID := primitive.NewObjectID().Hex()
+		taskReq := Domain.TaskRequest{Title: "Task", Status: Domain.StatusPending, Version: intPtr(2)}
+		mockTaskUsecase.On("UpdateTask", adminCaller, taskID, taskReq).Return(nil, conflict)
 
-		req := httptest.NewRequest("GET", "/users", nil)
+		reqBody, _ := json.Marshal(taskReq)
+		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.UserResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "Users retrieved successfully", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusConflict, w.Code)
+		mockTaskUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - repository error", func(t *testing.T) {
+	t.Run("Error - invalid If-Match header", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		router.GET("/users", controller.GetAllUsers)
-
-		mockUserUsecase.On("GetAllUsers").Return([]*Domain.User(nil), errors.New("database error"))
+		controller, mockTaskUsecase, _ := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.PUT("/tasks/:id", controller.UpdateTask)
 
-		req := httptest.NewRequest("GET", "/users", nil)
+		req := httptest.NewRequest("PUT", "/tasks/"+primitive.NewObjectID().Hex(), bytes.NewBufferString(`{"title":"Task","status":"pending"}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", "latest")
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to retrieve users", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockTaskUsecase.AssertNotCalled(t, "UpdateTask", mock.Anything, mock.Anything, mock.Anything)
 	})
 }
 
-func TestController_GetProfile(t *testing.T) {
-	t.Run("Success - get user profile", func(t *testing.T) {
+func TestController_GetAuditLog(t *testing.T) {
+	t.Run("Success - filters and pagination are passed through", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		
-		// Middleware to set user_id in context
-		router.Use(func(c *gin.Context) {
-			c.Set("user_id", "507f1f77bcf86cd799439011")
-			c.Next()
-		})
-		router.GET("/profile", controller.GetProfile)
-
-		expectedUser := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: "testuser",
-			Role:     Domain.RoleUser,
+		controller, mockAuditUsecase := setupAuditTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/audit", controller.GetAuditLog)
+
+		filter := Domain.AuditFilter{ActorID: testAdminID, Entity: Domain.AuditEntityTask}
+		pagination := Domain.Pagination{Limit: 10, Offset: 20}
+		expectedEntries := []*Domain.AuditEntry{
+			{ID: primitive.NewObjectID(), ActorID: testAdminID, Action: Domain.AuditActionUpdate, Entity: Domain.AuditEntityTask, Diff: "title"},
 		}
+		mockAuditUsecase.On("GetAuditLog", filter, pagination).Return(expectedEntries, int64(21), nil)
 
-		mockUserUsecase.On("GetUserProfile", "507f1f77bcf86cd799439011").Return(expectedUser, nil)
-
-		req := httptest.NewRequest("GET", "/profile", nil)
+		req := httptest.NewRequest("GET", "/audit?actor_id="+testAdminID+"&entity=task&limit=10&offset=20", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -528,98 +6574,80 @@ func TestController_GetProfile(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.UserResponse
+
+		var response Domain.ListResponse[*Domain.AuditEntry]
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.True(t, response.Success)
-		assert.Equal(t, "Profile retrieved successfully", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, Domain.PaginationMeta{Total: 21, Limit: 10, Offset: 20}, response.Meta)
+		mockAuditUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - user ID not found in context", func(t *testing.T) {
-		// Arrange
-		controller, _, _ := setupTestController()
-		router := setupGinContext()
-		router.GET("/profile", controller.GetProfile)
+	t.Run("Error - failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "invalid entity", err: Domain.NewError(Domain.ErrInvalidInput, "invalid entity, must be one of: task, user, api_key, webhook, api"), expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
 
-		req := httptest.NewRequest("GET", "/profile", nil)
-		w := httptest.NewRecorder()
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockAuditUsecase := setupAuditTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.GET("/audit", controller.GetAuditLog)
 
-		// Act
-		router.ServeHTTP(w, req)
+				mockAuditUsecase.On("GetAuditLog", mock.Anything, Domain.Pagination{}).Return(nil, int64(0), tt.err)
 
-		// Assert
-		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "User ID not found in token", response.Message)
+				req := httptest.NewRequest("GET", "/audit", nil)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				mockAuditUsecase.AssertExpectations(t)
+			})
+		}
 	})
 
-	t.Run("Error - user not found", func(t *testing.T) {
+	t.Run("Error - invalid pagination", func(t *testing.T) {
 		// Arrange
-		controller, _, mockUserUsecase := setupTestController()
-		router := setupGinContext()
-		
-		// Middleware to set user_id in context
-		router.Use(func(c *gin.Context) {
-			c.Set("user_id", "507f1f77bcf86cd799439011")
-			c.Next()
-		})
-		router.GET("/profile", controller.GetProfile)
+		controller, mockAuditUsecase := setupAuditTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/audit", controller.GetAuditLog)
 
-		mockUserUsecase.On("GetUserProfile", "507f1f77bcf86cd799439011").Return(nil, errors.New("user not found"))
-
-		req := httptest.NewRequest("GET", "/profile", nil)
+		req := httptest.NewRequest("GET", "/audit?limit=0", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to retrieve user profile", response.Message)
-		
-		mockUserUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockAuditUsecase.AssertNotCalled(t, "GetAuditLog", mock.Anything, mock.Anything)
 	})
 }
 
-// Task Controller Tests
-
-func TestController_GetAllTasks(t *testing.T) {
-	t.Run("Success - get all tasks", func(t *testing.T) {
+func TestController_GetTasksByUserReport(t *testing.T) {
+	t.Run("Success - since is read as the start of the day", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.GET("/tasks", controller.GetAllTasks)
+		controller, mockReportUsecase := setupReportTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/reports/tasks-by-user", controller.GetTasksByUserReport)
 
-		expectedTasks := []*Domain.Task{
-			{
-				ID:          primitive.NewObjectID(),
-				Title:       "Task 1",
-				Description: "Description 1",
-				Status:      Domain.StatusPending,
-			},
-			{
-				ID:          primitive.NewObjectID(),
-				Title:       "Task 2",
-				Description: "Description 2",
-				Status:      Domain.StatusCompleted,
-			},
+		expectedReports := []*Domain.UserTaskReport{
+			{UserID: primitive.NewObjectID(), Username: "alice", Pending: 3, InProgress: 1, Overdue: 1, Open: 4},
+			{UserID: primitive.NewObjectID(), Username: "bob"},
 		}
+		mockReportUsecase.On("GetTasksByUser", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).Return(expectedReports, nil)
 
-		mockTaskUsecase.On("GetAllTasks").Return(expectedTasks, nil)
-
-		req := httptest.NewRequest("GET", "/tasks", nil)
+		req := httptest.NewRequest("GET", "/reports/tasks-by-user?since=2024-01-01", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -627,159 +6655,184 @@ func TestController_GetAllTasks(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.TaskResponse
+
+		var response Domain.ListResponse[*Domain.UserTaskReport]
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.True(t, response.Success)
-		assert.Equal(t, "Tasks retrieved successfully", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.Equal(t, expectedReports, response.Data)
+		mockReportUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - repository error", func(t *testing.T) {
-		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.GET("/tasks", controller.GetAllTasks)
-
-		mockTaskUsecase.On("GetAllTasks").Return([]*Domain.Task(nil), errors.New("database error"))
+	t.Run("Error - failures map to status codes", func(t *testing.T) {
+		tests := []struct {
+			name           string
+			err            error
+			expectedStatus int
+		}{
+			{name: "since in the future", err: Domain.NewError(Domain.ErrInvalidInput, "since must not be in the future"), expectedStatus: http.StatusBadRequest},
+			{name: "database error", err: errors.New("database error"), expectedStatus: http.StatusInternalServerError},
+		}
 
-		req := httptest.NewRequest("GET", "/tasks", nil)
-		w := httptest.NewRecorder()
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				controller, mockReportUsecase := setupReportTestController()
+				router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+				router.GET("/reports/tasks-by-user", controller.GetTasksByUserReport)
 
-		// Act
-		router.ServeHTTP(w, req)
+				mockReportUsecase.On("GetTasksByUser", time.Time{}).Return(nil, tt.err)
 
-		// Assert
-		assert.Equal(t, http.StatusInternalServerError, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to retrieve tasks", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
-	})
-}
+				req := httptest.NewRequest("GET", "/reports/tasks-by-user", nil)
+				w := httptest.NewRecorder()
 
-func TestController_GetTaskByID(t *testing.T) {
-	t.Run("Success - get task by ID", func(t *testing.T) {
-		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.GET("/tasks/:id", controller.GetTaskByID)
+				// Act
+				router.ServeHTTP(w, req)
 
-		taskID := primitive.NewObjectID().Hex()
-		expectedTask := &Domain.Task{
-			ID:          primitive.NewObjectID(),
-			Title:       "Test Task",
-			Description: "Test Description",
-			Status:      Domain.StatusInProgress,
+				// Assert
+				assert.Equal(t, tt.expectedStatus, w.Code)
+				mockReportUsecase.AssertExpectations(t)
+			})
 		}
+	})
 
-		mockTaskUsecase.On("GetTaskByID", taskID).Return(expectedTask, nil)
+	t.Run("Error - malformed since", func(t *testing.T) {
+		// Arrange
+		controller, mockReportUsecase := setupReportTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/reports/tasks-by-user", controller.GetTasksByUserReport)
 
-		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+		req := httptest.NewRequest("GET", "/reports/tasks-by-user?since=yesterday", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.TaskResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "Task retrieved successfully", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockReportUsecase.AssertNotCalled(t, "GetTasksByUser", mock.Anything)
 	})
+}
+
+func TestController_PromoteUser_MissingCaller(t *testing.T) {
+	// Arrange
+	controller, _, mockUserUsecase := setupTestController()
+	router := setupGinContext()
+	router.POST("/promote", controller.PromoteUser)
 
-	t.Run("Error - task not found", func(t *testing.T) {
-		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.GET("/tasks/:id", controller.GetTaskByID)
+	req := httptest.NewRequest("POST", "/promote", bytes.NewBufferString(`{"username":"someone"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
 
-		taskID := primitive.NewObjectID().Hex()
+	// Act
+	router.ServeHTTP(w, req)
 
-		mockTaskUsecase.On("GetTaskByID", taskID).Return(nil, errors.New("task not found"))
+	// Assert
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	mockUserUsecase.AssertNotCalled(t, "PromoteUser", mock.Anything, mock.Anything, mock.Anything)
+}
 
-		req := httptest.NewRequest("GET", "/tasks/"+taskID, nil)
+func TestController_DeactivateUser(t *testing.T) {
+	userID := primitive.NewObjectID().Hex()
+
+	t.Run("Success - deactivate", func(t *testing.T) {
+		// Arrange
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/users/:id/deactivate", controller.DeactivateUser)
+
+		user := &Domain.User{Username: "leaver", Role: Domain.RoleUser, IsActive: false}
+		mockUserUsecase.On("DeactivateUser", adminCaller, userID).Return(user, nil)
+
+		req := httptest.NewRequest("POST", "/users/"+userID+"/deactivate", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Task not found", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.Response[*Domain.User]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.True(t, response.Success)
+		assert.Equal(t, "User deactivated successfully", response.Message)
+		mockUserUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid task ID format", func(t *testing.T) {
+	t.Run("Success - activate", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.GET("/tasks/:id", controller.GetTaskByID)
-
-		invalidID := "invalid-id"
+		controller, _, mockUserUsecase := setupTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/users/:id/activate", controller.ActivateUser)
 
-		mockTaskUsecase.On("GetTaskByID", invalidID).Return(nil, errors.New("invalid task ID format"))
+		user := &Domain.User{Username: "returner", Role: Domain.RoleUser, IsActive: true}
+		mockUserUsecase.On("ActivateUser", adminCaller, userID).Return(user, nil)
 
-		req := httptest.NewRequest("GET", "/tasks/"+invalidID, nil)
+		req := httptest.NewRequest("POST", "/users/"+userID+"/activate", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Task not found", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.Response[*Domain.User]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "User activated successfully", response.Message)
+		mockUserUsecase.AssertExpectations(t)
 	})
+
+	errorCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"deactivating yourself", Domain.ErrSelfDeactivation, http.StatusConflict},
+		{"user not found", Domain.ErrUserNotFound, http.StatusNotFound},
+		{"invalid ID", Domain.ErrInvalidUserID, http.StatusBadRequest},
+		{"database error", errors.New("database connection failed"), http.StatusInternalServerError},
+	}
+	for _, tc := range errorCases {
+		t.Run("Error - "+tc.name, func(t *testing.T) {
+			// Arrange
+			controller, _, mockUserUsecase := setupTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.POST("/users/:id/deactivate", controller.DeactivateUser)
+
+			mockUserUsecase.On("DeactivateUser", adminCaller, userID).Return(nil, tc.err)
+
+			req := httptest.NewRequest("POST", "/users/"+userID+"/deactivate", nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.statusCode, w.Code)
+			var response Domain.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.Equal(t, "Failed to deactivate user", response.Message)
+			assert.Equal(t, tc.err.Error(), response.Error)
+		})
+	}
 }
 
-func TestController_CreateTask(t *testing.T) {
-	t.Run("Success - create task", func(t *testing.T) {
+func TestController_CreateAPIKey(t *testing.T) {
+	t.Run("Success - returns the key with 201", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.POST("/tasks", controller.CreateTask)
-
-		taskReq := Domain.TaskRequest{
-			Title:       "New Task",
-			Description: "New Description",
-			DueDate:     "2024-12-31",
-			Status:      Domain.StatusPending,
-		}
-		expectedTask := &Domain.Task{
-			ID:          primitive.NewObjectID(),
-			Title:       "New Task",
-			Description: "New Description",
-			Status:      Domain.StatusPending,
+		controller, mockAPIKeyUsecase := setupAPIKeyTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/api-keys", controller.CreateAPIKey)
+
+		keyReq := Domain.APIKeyRequest{Name: "nightly-report", Role: Domain.RoleManager}
+		created := &Domain.CreatedAPIKey{
+			APIKey: &Domain.APIKey{ID: primitive.NewObjectID(), Name: "nightly-report", Role: Domain.RoleManager, KeyHash: "hash"},
+			Key:    "tm_secret",
 		}
+		mockAPIKeyUsecase.On("CreateAPIKey", adminCaller, keyReq).Return(created, nil)
 
-		mockTaskUsecase.On("CreateTask", taskReq).Return(expectedTask, nil)
-
-		reqBody, _ := json.Marshal(taskReq)
-		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(reqBody))
+		reqBody, _ := json.Marshal(keyReq)
+		req := httptest.NewRequest("POST", "/api-keys", bytes.NewBuffer(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -788,23 +6841,23 @@ func TestController_CreateTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusCreated, w.Code)
-		
-		var response Domain.TaskResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "Task created successfully", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		var response struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "tm_secret", response.Data["key"])
+		assert.Equal(t, "nightly-report", response.Data["name"])
+		assert.NotContains(t, response.Data, "key_hash")
+		mockAPIKeyUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid JSON", func(t *testing.T) {
+	t.Run("Error - missing name", func(t *testing.T) {
 		// Arrange
-		controller, _, _ := setupTestController()
-		router := setupGinContext()
-		router.POST("/tasks", controller.CreateTask)
+		controller, mockAPIKeyUsecase := setupAPIKeyTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/api-keys", controller.CreateAPIKey)
 
-		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer([]byte("invalid json")))
+		req := httptest.NewRequest("POST", "/api-keys", bytes.NewBufferString(`{"role": "user"}`))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -813,29 +6866,20 @@ func TestController_CreateTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Invalid request payload", response.Message)
+		mockAPIKeyUsecase.AssertNotCalled(t, "CreateAPIKey", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - create task failed", func(t *testing.T) {
+	t.Run("Error - invalid role", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.POST("/tasks", controller.CreateTask)
-
-		taskReq := Domain.TaskRequest{
-			Title:  "New Task",
-			Status: Domain.StatusPending,
-		}
+		controller, mockAPIKeyUsecase := setupAPIKeyTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/api-keys", controller.CreateAPIKey)
 
-		mockTaskUsecase.On("CreateTask", taskReq).Return(nil, errors.New("validation error"))
+		keyReq := Domain.APIKeyRequest{Name: "cron", Role: "owner"}
+		mockAPIKeyUsecase.On("CreateAPIKey", adminCaller, keyReq).Return(nil, Domain.NewError(Domain.ErrInvalidInput, "invalid role, must be one of: user, manager, admin"))
 
-		reqBody, _ := json.Marshal(taskReq)
-		req := httptest.NewRequest("POST", "/tasks", bytes.NewBuffer(reqBody))
+		reqBody, _ := json.Marshal(keyReq)
+		req := httptest.NewRequest("POST", "/api-keys", bytes.NewBuffer(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -844,42 +6888,20 @@ func TestController_CreateTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to create task", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
 	})
 }
 
-func TestController_UpdateTask(t *testing.T) {
-	t.Run("Success - update task", func(t *testing.T) {
+func TestController_GetAPIKeys(t *testing.T) {
+	t.Run("Success - lists keys", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.PUT("/tasks/:id", controller.UpdateTask)
-
-		taskID := primitive.NewObjectID().Hex()
-		taskReq := Domain.TaskRequest{
-			Title:       "Updated Task",
-			Description: "Updated Description",
-			Status:      Domain.StatusCompleted,
-		}
-		expectedTask := &Domain.Task{
-			ID:          primitive.NewObjectID(),
-			Title:       "Updated Task",
-			Description: "Updated Description",
-			Status:      Domain.StatusCompleted,
-		}
+		controller, mockAPIKeyUsecase := setupAPIKeyTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/api-keys", controller.GetAPIKeys)
 
-		mockTaskUsecase.On("UpdateTask", taskID, taskReq).Return(expectedTask, nil)
+		keys := []*Domain.APIKey{{ID: primitive.NewObjectID(), Name: "cron", Role: Domain.RoleUser}}
+		mockAPIKeyUsecase.On("ListAPIKeys").Return(keys, nil)
 
-		reqBody, _ := json.Marshal(taskReq)
-		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(reqBody))
-		req.Header.Set("Content-Type", "application/json")
+		req := httptest.NewRequest("GET", "/api-keys", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -887,32 +6909,87 @@ func TestController_UpdateTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.TaskResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "Task updated successfully", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		mockAPIKeyUsecase.AssertExpectations(t)
 	})
+}
 
-	t.Run("Error - task not found", func(t *testing.T) {
+func TestController_RevokeAPIKey(t *testing.T) {
+	keyID := primitive.NewObjectID().Hex()
+
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"Success - revoked", nil, http.StatusOK},
+		{"Error - key not found", Domain.ErrAPIKeyNotFound, http.StatusNotFound},
+		{"Error - invalid ID", Domain.ErrInvalidAPIKeyID, http.StatusBadRequest},
+		{"Error - database error", errors.New("database connection failed"), http.StatusInternalServerError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, mockAPIKeyUsecase := setupAPIKeyTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.DELETE("/api-keys/:id", controller.RevokeAPIKey)
+
+			if tc.err != nil {
+				mockAPIKeyUsecase.On("RevokeAPIKey", adminCaller, keyID).Return(nil, tc.err)
+			} else {
+				mockAPIKeyUsecase.On("RevokeAPIKey", adminCaller, keyID).Return(&Domain.APIKey{Name: "cron", Revoked: true}, nil)
+			}
+
+			req := httptest.NewRequest("DELETE", "/api-keys/"+keyID, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.statusCode, w.Code)
+			mockAPIKeyUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_CreateWebhook(t *testing.T) {
+	t.Run("Success - returns the secret with 201", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.PUT("/tasks/:id", controller.UpdateTask)
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/webhooks", controller.CreateWebhook)
 
-		taskID := primitive.NewObjectID().Hex()
-		taskReq := Domain.TaskRequest{
-			Title:  "Updated Task",
-			Status: Domain.StatusCompleted,
+		webhookReq := Domain.WebhookRequest{URL: "https://ci.example.com/hook", Events: []string{Domain.EventTaskCreated}}
+		webhook := &Domain.Webhook{ID: primitive.NewObjectID(), URL: webhookReq.URL, Secret: "whsec_secret", Events: webhookReq.Events, Active: true}
+		created := &Domain.CreatedWebhook{Webhook: webhook, Secret: webhook.Secret}
+		mockWebhookUsecase.On("CreateWebhook", adminCaller, webhookReq).Return(created, nil)
+
+		reqBody, _ := json.Marshal(webhookReq)
+		req := httptest.NewRequest("POST", "/webhooks", bytes.NewBuffer(reqBody))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		var response struct {
+			Data map[string]interface{} `json:"data"`
 		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "whsec_secret", response.Data["secret"])
+		assert.Equal(t, "https://ci.example.com/hook", response.Data["url"])
+		mockWebhookUsecase.AssertExpectations(t)
+	})
 
-		mockTaskUsecase.On("UpdateTask", taskID, taskReq).Return(nil, errors.New("task not found"))
+	t.Run("Error - missing events", func(t *testing.T) {
+		// Arrange
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/webhooks", controller.CreateWebhook)
 
-		reqBody, _ := json.Marshal(taskReq)
-		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer(reqBody))
+		req := httptest.NewRequest("POST", "/webhooks", bytes.NewBufferString(`{"url": "https://ci.example.com/hook"}`))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -920,26 +6997,21 @@ func TestController_UpdateTask(t *testing.T) {
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to update task", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		mockWebhookUsecase.AssertNotCalled(t, "CreateWebhook", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - invalid JSON", func(t *testing.T) {
+	t.Run("Error - invalid event", func(t *testing.T) {
 		// Arrange
-		controller, _, _ := setupTestController()
-		router := setupGinContext()
-		router.PUT("/tasks/:id", controller.UpdateTask)
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.POST("/webhooks", controller.CreateWebhook)
 
-		taskID := primitive.NewObjectID().Hex()
+		webhookReq := Domain.WebhookRequest{URL: "https://ci.example.com/hook", Events: []string{"task.archived"}}
+		mockWebhookUsecase.On("CreateWebhook", adminCaller, webhookReq).Return(nil, Domain.NewError(Domain.ErrInvalidInput, `invalid event "task.archived"`))
 
-		req := httptest.NewRequest("PUT", "/tasks/"+taskID, bytes.NewBuffer([]byte("invalid json")))
+		reqBody, _ := json.Marshal(webhookReq)
+		req := httptest.NewRequest("POST", "/webhooks", bytes.NewBuffer(reqBody))
 		req.Header.Set("Content-Type", "application/json")
 		w := httptest.NewRecorder()
 
@@ -948,27 +7020,20 @@ func TestController_UpdateTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Invalid request payload", response.Message)
 	})
 }
 
-func TestController_DeleteTask(t *testing.T) {
-	t.Run("Success - delete task", func(t *testing.T) {
+func TestController_GetWebhooks(t *testing.T) {
+	t.Run("Success - lists webhooks without their secrets", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.DELETE("/tasks/:id", controller.DeleteTask)
-
-		taskID := primitive.NewObjectID().Hex()
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/webhooks", controller.GetWebhooks)
 
-		mockTaskUsecase.On("DeleteTask", taskID).Return(nil)
+		webhooks := []*Domain.Webhook{{ID: primitive.NewObjectID(), URL: "https://ci.example.com/hook", Secret: "whsec_secret", Events: []string{Domain.EventTaskCreated}}}
+		mockWebhookUsecase.On("ListWebhooks").Return(webhooks, nil)
 
-		req := httptest.NewRequest("DELETE", "/tasks/"+taskID, nil)
+		req := httptest.NewRequest("GET", "/webhooks", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -976,55 +7041,177 @@ func TestController_DeleteTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response Domain.TaskResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.True(t, response.Success)
-		assert.Equal(t, "Task deleted successfully", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.NotContains(t, w.Body.String(), "whsec_secret")
+		mockWebhookUsecase.AssertExpectations(t)
 	})
+}
 
-	t.Run("Error - task not found", func(t *testing.T) {
-		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.DELETE("/tasks/:id", controller.DeleteTask)
+func TestController_GetWebhook(t *testing.T) {
+	webhookID := primitive.NewObjectID().Hex()
+
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"Success - found", nil, http.StatusOK},
+		{"Error - webhook not found", Domain.ErrWebhookNotFound, http.StatusNotFound},
+		{"Error - invalid ID", Domain.ErrInvalidWebhookID, http.StatusBadRequest},
+		{"Error - database error", errors.New("database connection failed"), http.StatusInternalServerError},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, mockWebhookUsecase := setupWebhookTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.GET("/webhooks/:id", controller.GetWebhook)
+
+			if tc.err != nil {
+				mockWebhookUsecase.On("GetWebhook", webhookID).Return(nil, tc.err)
+			} else {
+				mockWebhookUsecase.On("GetWebhook", webhookID).Return(&Domain.Webhook{URL: "https://ci.example.com/hook"}, nil)
+			}
+
+			req := httptest.NewRequest("GET", "/webhooks/"+webhookID, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.statusCode, w.Code)
+			mockWebhookUsecase.AssertExpectations(t)
+		})
+	}
+}
 
-		taskID := primitive.NewObjectID().Hex()
+func TestController_UpdateWebhook(t *testing.T) {
+	webhookID := primitive.NewObjectID().Hex()
+	active := false
+	webhookReq := Domain.WebhookRequest{URL: "https://ci.example.com/hook", Events: []string{Domain.EventTaskUpdated}, Active: &active}
+
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"Success - updated", nil, http.StatusOK},
+		{"Error - webhook not found", Domain.ErrWebhookNotFound, http.StatusNotFound},
+		{"Error - invalid URL", Domain.NewError(Domain.ErrInvalidInput, "invalid url"), http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, mockWebhookUsecase := setupWebhookTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.PUT("/webhooks/:id", controller.UpdateWebhook)
+
+			if tc.err != nil {
+				mockWebhookUsecase.On("UpdateWebhook", adminCaller, webhookID, webhookReq).Return(nil, tc.err)
+			} else {
+				mockWebhookUsecase.On("UpdateWebhook", adminCaller, webhookID, webhookReq).Return(&Domain.Webhook{URL: webhookReq.URL}, nil)
+			}
+
+			reqBody, _ := json.Marshal(webhookReq)
+			req := httptest.NewRequest("PUT", "/webhooks/"+webhookID, bytes.NewBuffer(reqBody))
+			req.Header.Set("Content-Type", "application/json")
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.statusCode, w.Code)
+			mockWebhookUsecase.AssertExpectations(t)
+		})
+	}
+}
+
+func TestController_DeleteWebhook(t *testing.T) {
+	webhookID := primitive.NewObjectID().Hex()
+
+	testCases := []struct {
+		name       string
+		err        error
+		statusCode int
+	}{
+		{"Success - deleted", nil, http.StatusOK},
+		{"Error - webhook not found", Domain.ErrWebhookNotFound, http.StatusNotFound},
+		{"Error - invalid ID", Domain.ErrInvalidWebhookID, http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			controller, mockWebhookUsecase := setupWebhookTestController()
+			router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+			router.DELETE("/webhooks/:id", controller.DeleteWebhook)
+			mockWebhookUsecase.On("DeleteWebhook", adminCaller, webhookID).Return(tc.err)
+
+			req := httptest.NewRequest("DELETE", "/webhooks/"+webhookID, nil)
+			w := httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.statusCode, w.Code)
+			mockWebhookUsecase.AssertExpectations(t)
+		})
+	}
+}
 
-		mockTaskUsecase.On("DeleteTask", taskID).Return(errors.New("task not found"))
+func TestController_GetWebhookDeliveries(t *testing.T) {
+	webhookID := primitive.NewObjectID().Hex()
 
-		req := httptest.NewRequest("DELETE", "/tasks/"+taskID, nil)
+	t.Run("Success - one page with meta", func(t *testing.T) {
+		// Arrange
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/webhooks/:id/deliveries", controller.GetWebhookDeliveries)
+
+		deliveries := []*Domain.WebhookDelivery{{Event: Domain.EventTaskCreated, Attempts: 2, StatusCode: http.StatusOK, Delivered: true}}
+		mockWebhookUsecase.On("GetDeliveries", webhookID, Domain.Pagination{Limit: 1, Offset: 1}).Return(deliveries, int64(3), nil)
+
+		req := httptest.NewRequest("GET", "/webhooks/"+webhookID+"/deliveries?limit=1&offset=1", nil)
 		w := httptest.NewRecorder()
 
 		// Act
 		router.ServeHTTP(w, req)
 
 		// Assert
-		assert.Equal(t, http.StatusNotFound, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to delete task", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.ListResponse[*Domain.WebhookDelivery]
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, 2, response.Data[0].Attempts)
+		assert.Equal(t, int64(3), response.Meta.Total)
+		mockWebhookUsecase.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid task ID format", func(t *testing.T) {
+	t.Run("Error - webhook not found", func(t *testing.T) {
 		// Arrange
-		controller, mockTaskUsecase, _ := setupTestController()
-		router := setupGinContext()
-		router.DELETE("/tasks/:id", controller.DeleteTask)
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/webhooks/:id/deliveries", controller.GetWebhookDeliveries)
+		mockWebhookUsecase.On("GetDeliveries", webhookID, mock.Anything).Return(nil, int64(0), Domain.ErrWebhookNotFound)
 
-		invalidID := "invalid-id"
+		req := httptest.NewRequest("GET", "/webhooks/"+webhookID+"/deliveries", nil)
+		w := httptest.NewRecorder()
 
-		mockTaskUsecase.On("DeleteTask", invalidID).Return(errors.New("invalid task ID format"))
+		// Act
+		router.ServeHTTP(w, req)
 
-		req := httptest.NewRequest("DELETE", "/tasks/"+invalidID, nil)
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Error - invalid pagination", func(t *testing.T) {
+		// Arrange
+		controller, mockWebhookUsecase := setupWebhookTestController()
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.GET("/webhooks/:id/deliveries", controller.GetWebhookDeliveries)
+
+		req := httptest.NewRequest("GET", "/webhooks/"+webhookID+"/deliveries?limit=abc", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -1032,25 +7219,59 @@ func TestController_DeleteTask(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusBadRequest, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Failed to delete task", response.Message)
-		
-		mockTaskUsecase.AssertExpectations(t)
+		mockWebhookUsecase.AssertNotCalled(t, "GetDeliveries", mock.Anything, mock.Anything)
 	})
 }
 
-// Test constructor
-func TestNewController(t *testing.T) {
-	mockTaskUsecase := new(MockTaskUsecase)
-	mockUserUsecase := new(MockUserUsecase)
+// blockingTaskRepository holds GetAll open until the request context is cancelled, like a slow query
+type blockingTaskRepository struct {
+	Repositories.TaskRepositoryInterface
+	started chan struct{}
+}
 
-	controller := NewController(mockTaskUsecase, mockUserUsecase)
+func (r *blockingTaskRepository) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	close(r.started)
+	<-ctx.Done()
+	return nil, 0, ctx.Err()
+}
 
-	assert.NotNil(t, controller)
-	assert.Equal(t, mockTaskUsecase, controller.taskUsecase)
-	assert.Equal(t, mockUserUsecase, controller.userUsecase)
-}
\ No newline at end of file
+func TestController_ClientDisconnect(t *testing.T) {
+	t.Run("Success - a cancelled request stops the query and writes no response", func(t *testing.T) {
+		// Arrange
+		repo := &blockingTaskRepository{started: make(chan struct{})}
+		taskUsecase := Usecases.NewTaskUsecase(repo, nil, nil, nil, nil, nil, nil, nil, nil, Infrastructure.NewNopBusinessMetrics(), Usecases.DueDateRules{}, Infrastructure.NewNopLogger())
+		controller := NewController(taskUsecase, new(MockUserUsecase), new(MockCommentUsecase), new(MockAttachmentUsecase), new(MockWorkLogUsecase), new(MockAuditUsecase), new(MockPasswordResetUsecase), new(MockAPIKeyUsecase), new(MockWebhookUsecase), new(MockReportUsecase), "/api/v1", Infrastructure.NewNopLogger())
+		router := setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin)
+		router.Use(Infrastructure.NewTimeoutMiddleware(Infrastructure.NewNopLogger()).Timeout())
+		router.GET("/tasks", controller.GetAllTasks)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		req := httptest.NewRequest("GET", "/tasks", nil).WithContext(ctx)
+		w := httptest.NewRecorder()
+		goroutines := runtime.NumGoroutine()
+
+		// Act
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			router.ServeHTTP(w, req)
+		}()
+		<-repo.started
+		cancel()
+
+		// Assert
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("handler kept running after the request was cancelled")
+		}
+		assert.Equal(t, StatusClientClosedRequest, w.Code)
+		assert.Empty(t, w.Body.String())
+		// assert.Eventually polls from a goroutine of its own, so count by hand
+		for deadline := time.Now().Add(time.Second); runtime.NumGoroutine() > goroutines && time.Now().Before(deadline); {
+			time.Sleep(10 * time.Millisecond)
+		}
+		assert.LessOrEqual(t, runtime.NumGoroutine(), goroutines, "goroutines leaked")
+	})
+}
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// docsPage renders the spec with Redoc, loaded from its CDN so no assets ship with the server
+const docsPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Task Manager API</title>
+  <meta charset="utf-8">
+  <meta name="viewport" content="width=device-width, initial-scale=1">
+</head>
+<body>
+  <redoc spec-url="/openapi.json"></redoc>
+  <script src="https://cdn.redoc.ly/redoc/v2.1.3/bundles/redoc.standalone.js"></script>
+</body>
+</html>
+`
+
+// DocsController serves the OpenAPI specification and a page that renders it
+type DocsController struct {
+	spec []byte
+}
+
+// NewDocsController builds the specification once, from the routes and the Domain types
+func NewDocsController(version string) *DocsController {
+	spec, err := json.Marshal(buildOpenAPISpec(version))
+	if err != nil {
+		panic(fmt.Sprintf("failed to build the OpenAPI specification: %v", err))
+	}
+	return &DocsController{
+		spec: spec,
+	}
+}
+
+// GetSpec handles GET /openapi.json
+func (dc *DocsController) GetSpec(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", dc.spec)
+}
+
+// GetDocs handles GET /docs
+func (dc *DocsController) GetDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(docsPage))
+}
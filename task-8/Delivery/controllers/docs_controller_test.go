@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func TestDocsController_GetSpec(t *testing.T) {
+	t.Run("Success - serve the OpenAPI specification", func(t *testing.T) {
+		// Arrange
+		controller := NewDocsController("1.2.3")
+		router := setupGinContext()
+		router.GET("/openapi.json", controller.GetSpec)
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+		var spec openAPIDocument
+		err := json.Unmarshal(w.Body.Bytes(), &spec)
+		assert.NoError(t, err)
+		assert.Equal(t, "3.0.3", spec.OpenAPI)
+		assert.Equal(t, "1.2.3", spec.Info.Version)
+
+		assert.Contains(t, spec.Paths, "/api/v1/tasks")
+		assert.Contains(t, spec.Paths["/api/v1/tasks"], "get")
+		assert.Contains(t, spec.Paths["/api/v1/tasks"], "post")
+		assert.Contains(t, spec.Paths, "/api/v1/tasks/{id}")
+		assert.Contains(t, spec.Paths["/api/v1/tasks/{id}"], "put")
+		assert.Contains(t, spec.Paths["/api/v1/tasks/{id}"], "delete")
+
+		assert.Equal(t, "bearer", spec.Components.SecuritySchemes["bearerAuth"].Scheme)
+		assert.Contains(t, spec.Components.Schemas, "TaskResponse")
+		assert.Contains(t, spec.Components.Schemas, "TaskListResponse")
+		assert.Contains(t, spec.Components.Schemas, "StringListResponse")
+		assert.Equal(t, "#/components/schemas/Task", spec.Components.Schemas["TaskListResponse"].Properties["data"].Items.Ref)
+		assert.Contains(t, spec.Components.Schemas["TaskListResponse"].Required, "meta")
+		assert.Contains(t, spec.Components.Schemas, "ErrorResponse")
+		assert.ElementsMatch(t,
+			[]string{Domain.StatusPending, Domain.StatusInProgress, Domain.StatusCompleted},
+			spec.Components.Schemas["Task"].Properties["status"].Enum)
+		assert.ElementsMatch(t,
+			[]string{Domain.RoleUser, Domain.RoleManager, Domain.RoleAdmin},
+			spec.Components.Schemas["User"].Properties["role"].Enum)
+	})
+
+	t.Run("Success - public operations have no security requirement", func(t *testing.T) {
+		// Act
+		spec := buildOpenAPISpec("dev")
+
+		// Assert
+		assert.Empty(t, spec.Paths["/api/v1/login"]["post"].Security)
+		assert.NotEmpty(t, spec.Paths["/api/v1/tasks"]["get"].Security)
+	})
+}
+
+func TestDocsController_GetDocs(t *testing.T) {
+	t.Run("Success - serve the documentation page", func(t *testing.T) {
+		// Arrange
+		controller := NewDocsController("dev")
+		router := setupGinContext()
+		router.GET("/docs", controller.GetDocs)
+		req := httptest.NewRequest("GET", "/docs", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/html")
+		assert.Contains(t, w.Body.String(), `spec-url="/openapi.json"`)
+	})
+}
@@ -0,0 +1,109 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// DefaultEventStreamHeartbeat is how often an idle event stream sends a comment so proxies keep it open
+const DefaultEventStreamHeartbeat = 30 * time.Second
+
+// EventSubscriber delivers the events published from now until ctx ends; Infrastructure.EventBroker implements it
+type EventSubscriber interface {
+	Subscribe(ctx context.Context) <-chan Domain.Event
+}
+
+// EventStreamController streams task changes to clients as server-sent events
+type EventStreamController struct {
+	events    EventSubscriber
+	heartbeat time.Duration
+	logger    *slog.Logger
+}
+
+// NewEventStreamController creates a new instance of EventStreamController
+func NewEventStreamController(events EventSubscriber, logger *slog.Logger) *EventStreamController {
+	return &EventStreamController{
+		events:    events,
+		heartbeat: DefaultEventStreamHeartbeat,
+		logger:    logger,
+	}
+}
+
+// StreamTaskEvents handles GET /tasks/events. It holds the connection open and sends every task
+// created, updated or deleted that the caller can see, until the client goes away.
+func (ec *EventStreamController) StreamTaskEvents(c *gin.Context) {
+	caller, ok := callerFromContext(c)
+	if !ok {
+		message := localize(c, Domain.MsgCallerNotFound)
+		c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
+			Success:   false,
+			Message:   message.Text,
+			MessageID: message.ID,
+			Error:     "Authentication required",
+			RequestID: Domain.RequestIDFromContext(c.Request.Context()),
+		})
+		return
+	}
+
+	// The request context ends when the client disconnects, which also ends the subscription
+	ctx := c.Request.Context()
+	events := ec.events.Subscribe(ctx)
+
+	// A stream outlives the server's write timeout; writers that cannot lift it keep it
+	http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Stop nginx from buffering the stream
+	c.Status(http.StatusOK)
+	if err := ec.send(c, ": connected\n\n"); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(ec.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if !event.IsTaskEventVisibleTo(caller) {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				ec.logger.ErrorContext(ctx, "failed to encode task event", "event", event.Type, "event_id", event.ID, "error", err)
+				continue
+			}
+			if err := ec.send(c, fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := ec.send(c, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send writes one message of the stream and flushes it to the client
+func (ec *EventStreamController) send(c *gin.Context, message string) error {
+	if _, err := io.WriteString(c.Writer, message); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
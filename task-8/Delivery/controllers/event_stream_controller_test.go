@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// openEventStream serves the task event stream to the given caller and connects to it
+func openEventStream(t *testing.T, controller *EventStreamController, router *gin.Engine) (*http.Response, *bufio.Reader, context.CancelFunc) {
+	router.GET("/tasks/events", controller.StreamTaskEvents)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(ctx, "GET", server.URL+"/tasks/events", nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp, bufio.NewReader(resp.Body), cancel
+}
+
+// readMessage reads one server-sent event message, without its terminating blank line
+func readMessage(t *testing.T, reader *bufio.Reader) []string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			return lines
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestEventStreamController_StreamTaskEvents(t *testing.T) {
+	t.Run("Success - streams the caller's task events and skips the rest", func(t *testing.T) {
+		// Arrange
+		broker := Infrastructure.NewEventBroker(Infrastructure.NewNopLogger())
+		controller := NewEventStreamController(broker, Infrastructure.NewNopLogger())
+		resp, reader, cancel := openEventStream(t, controller, setupAuthenticatedGinContext(testUserID, Domain.RoleUser))
+		defer cancel()
+		assert.Equal(t, []string{": connected"}, readMessage(t, reader))
+
+		ownerID, _ := primitive.ObjectIDFromHex(testUserID)
+		ownTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Mine", CreatedBy: ownerID}
+		otherTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Theirs", CreatedBy: primitive.NewObjectID()}
+		hidden := Domain.NewEvent(Domain.EventTaskCreated, "", otherTask)
+		visible := Domain.NewEvent(Domain.EventTaskUpdated, testUserID, ownTask)
+
+		// Act
+		broker.Publish(context.Background(), hidden)
+		broker.Publish(context.Background(), Domain.NewEvent(Domain.EventUserPromoted, "", Domain.PromotedUserEvent{}))
+		broker.Publish(context.Background(), visible)
+		message := readMessage(t, reader)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		require.Len(t, message, 3)
+		assert.Equal(t, "id: "+visible.ID, message[0])
+		assert.Equal(t, "event: task.updated", message[1])
+		var payload struct {
+			Type string      `json:"type"`
+			Data Domain.Task `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(message[2], "data: ")), &payload))
+		assert.Equal(t, Domain.EventTaskUpdated, payload.Type)
+		assert.Equal(t, "Mine", payload.Data.Title)
+	})
+
+	t.Run("Success - idle streams get heartbeats", func(t *testing.T) {
+		// Arrange
+		broker := Infrastructure.NewEventBroker(Infrastructure.NewNopLogger())
+		controller := NewEventStreamController(broker, Infrastructure.NewNopLogger())
+		controller.heartbeat = 10 * time.Millisecond
+		_, reader, cancel := openEventStream(t, controller, setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin))
+		defer cancel()
+		readMessage(t, reader)
+
+		// Act
+		message := readMessage(t, reader)
+
+		// Assert
+		assert.Equal(t, []string{": heartbeat"}, message)
+	})
+
+	t.Run("Success - a disconnected client is unsubscribed", func(t *testing.T) {
+		// Arrange
+		broker := Infrastructure.NewEventBroker(Infrastructure.NewNopLogger())
+		controller := NewEventStreamController(broker, Infrastructure.NewNopLogger())
+		_, reader, cancel := openEventStream(t, controller, setupAuthenticatedGinContext(testAdminID, Domain.RoleAdmin))
+		readMessage(t, reader)
+		assert.Equal(t, 1, broker.Subscribers())
+
+		// Act
+		cancel()
+
+		// Assert
+		assert.Eventually(t, func() bool { return broker.Subscribers() == 0 }, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("Error - missing caller", func(t *testing.T) {
+		// Arrange
+		controller := NewEventStreamController(Infrastructure.NewEventBroker(Infrastructure.NewNopLogger()), Infrastructure.NewNopLogger())
+		router := setupGinContext()
+		router.GET("/tasks/events", controller.StreamTaskEvents)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/tasks/events", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+}
@@ -0,0 +1,95 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// FallbackController answers requests that match no route, in the same JSON shape as every other error
+type FallbackController struct {
+	routes gin.RoutesInfo
+}
+
+// NewFallbackController creates a new instance of FallbackController. routes must be every route
+// registered on the engine, so it is created once the routes are in place.
+func NewFallbackController(routes gin.RoutesInfo) *FallbackController {
+	return &FallbackController{
+		routes: routes,
+	}
+}
+
+// NotFound handles requests to paths that no route serves
+func (fc *FallbackController) NotFound(c *gin.Context) {
+	message := localize(c, Domain.MsgRouteNotFound)
+	c.JSON(http.StatusNotFound, Domain.ErrorResponse{
+		Success:   false,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Error:     fmt.Sprintf("No route for %s %s", c.Request.Method, c.Request.URL.Path),
+		RequestID: Domain.RequestIDFromContext(c.Request.Context()),
+	})
+}
+
+// MethodNotAllowed handles requests to a path that is served, but not for the request's method.
+// The Allow header lists the methods that are. OPTIONS is answered with 204 and the same header.
+func (fc *FallbackController) MethodNotAllowed(c *gin.Context) {
+	allowed := fc.allowedMethods(c.Request.URL.Path)
+	c.Header("Allow", strings.Join(append(allowed, http.MethodOptions), ", "))
+	if c.Request.Method == http.MethodOptions {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	message := localize(c, Domain.MsgMethodNotAllowed)
+	c.JSON(http.StatusMethodNotAllowed, Domain.ErrorResponse{
+		Success:   false,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Error:     fmt.Sprintf("%s is not allowed on %s", c.Request.Method, c.Request.URL.Path),
+		RequestID: Domain.RequestIDFromContext(c.Request.Context()),
+	})
+}
+
+// allowedMethods returns the methods of the routes matching path, sorted and without duplicates
+func (fc *FallbackController) allowedMethods(path string) []string {
+	seen := make(map[string]bool)
+	var methods []string
+	for _, route := range fc.routes {
+		if !seen[route.Method] && routeMatches(route.Path, path) {
+			seen[route.Method] = true
+			methods = append(methods, route.Method)
+		}
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// routeMatches reports whether path is served by a route pattern, where a :name segment matches
+// any one segment and a *name segment matches the rest of the path
+func routeMatches(pattern, path string) bool {
+	patternSegments := strings.Split(strings.Trim(pattern, "/"), "/")
+	pathSegments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "*") {
+			return true
+		}
+		if i >= len(pathSegments) {
+			return false
+		}
+		if strings.HasPrefix(segment, ":") {
+			if pathSegments[i] == "" {
+				return false
+			}
+			continue
+		}
+		if segment != pathSegments[i] {
+			return false
+		}
+	}
+	return len(patternSegments) == len(pathSegments)
+}
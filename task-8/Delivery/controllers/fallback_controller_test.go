@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+// setupFallbackRouter registers a few routes and the fallback handlers the way the real router does
+func setupFallbackRouter() *gin.Engine {
+	router := setupGinContext()
+	router.GET("/tasks/:id", func(c *gin.Context) {})
+	router.PUT("/tasks/:id", func(c *gin.Context) {})
+	router.GET("/files/*path", func(c *gin.Context) {})
+
+	controller := NewFallbackController(router.Routes())
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(controller.NotFound)
+	router.NoMethod(controller.MethodNotAllowed)
+	return router
+}
+
+func TestFallbackController(t *testing.T) {
+	t.Run("Success - OPTIONS lists the allowed methods", func(t *testing.T) {
+		// Arrange
+		router := setupFallbackRouter()
+		req := httptest.NewRequest("OPTIONS", "/tasks/1", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "GET, PUT, OPTIONS", w.Header().Get("Allow"))
+	})
+
+	t.Run("Error - wrong method is a 405 with an Allow header", func(t *testing.T) {
+		// Arrange
+		router := setupFallbackRouter()
+		req := httptest.NewRequest("DELETE", "/tasks/1", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, "GET, PUT, OPTIONS", w.Header().Get("Allow"))
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.MsgMethodNotAllowed, response.MessageID)
+		assert.Equal(t, "DELETE is not allowed on /tasks/1", response.Error)
+	})
+
+	t.Run("Error - unknown path is a JSON 404", func(t *testing.T) {
+		// Arrange
+		router := setupFallbackRouter()
+		req := httptest.NewRequest("GET", "/tasks/1/comments", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, Domain.MsgRouteNotFound, response.MessageID)
+	})
+}
+
+func TestRouteMatches(t *testing.T) {
+	testCases := []struct {
+		pattern  string
+		path     string
+		expected bool
+	}{
+		{"/tasks", "/tasks", true},
+		{"/tasks/:id", "/tasks/42", true},
+		{"/tasks/:id", "/tasks", false},
+		{"/tasks/:id", "/tasks/42/comments", false},
+		{"/tasks/:id/comments", "/tasks/42/comments", true},
+		{"/files/*path", "/files/a/b", true},
+		{"/files/*path", "/files", true},
+		{"/tasks/count", "/tasks/stats", false},
+	}
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, routeMatches(tc.pattern, tc.path), "%s against %s", tc.path, tc.pattern)
+	}
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"task_manager/Domain"
+)
+
+// ReadinessTimeout bounds the database ping done by the readiness check
+const ReadinessTimeout = 2 * time.Second
+
+// DatabasePinger checks that the database is reachable; *mongo.Client implements it
+type DatabasePinger interface {
+	Ping(ctx context.Context, rp *readpref.ReadPref) error
+}
+
+// HealthController serves the liveness, readiness and version endpoints
+type HealthController struct {
+	db        DatabasePinger
+	build     Domain.VersionInfo
+	startedAt time.Time
+}
+
+// NewHealthController creates a new instance of HealthController
+func NewHealthController(db DatabasePinger, build Domain.VersionInfo, startedAt time.Time) *HealthController {
+	return &HealthController{
+		db:        db,
+		build:     build,
+		startedAt: startedAt,
+	}
+}
+
+// Liveness handles GET /healthz. It always succeeds while the process is able to serve requests.
+func (hc *HealthController) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, hc.response(Domain.HealthStatusOK, "Task Management API is running"))
+}
+
+// Readiness handles GET /readyz. It pings the database and answers 503 with the reason when it is unreachable.
+func (hc *HealthController) Readiness(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ReadinessTimeout)
+	defer cancel()
+
+	if err := hc.db.Ping(ctx, readpref.Primary()); err != nil {
+		response := hc.response(Domain.HealthStatusUnavailable, "Database is unreachable")
+		response.Error = err.Error()
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, hc.response(Domain.HealthStatusOK, "Task Management API is ready"))
+}
+
+// Version handles GET /version. It reports which build is running, so deploy tooling can verify a rollout.
+func (hc *HealthController) Version(c *gin.Context) {
+	c.JSON(http.StatusOK, hc.build)
+}
+
+func (hc *HealthController) response(status, message string) Domain.HealthResponse {
+	uptime := time.Since(hc.startedAt)
+	return Domain.HealthResponse{
+		Status:        status,
+		Message:       message,
+		Version:       hc.build.Version,
+		Commit:        hc.build.Commit,
+		BuildTime:     hc.build.BuildTime,
+		Uptime:        uptime.Round(time.Second).String(),
+		UptimeSeconds: int64(uptime.Seconds()),
+	}
+}
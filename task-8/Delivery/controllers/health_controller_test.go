@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
+	"task_manager/Domain"
+)
+
+// MockDatabasePinger is a mock implementation of DatabasePinger
+type MockDatabasePinger struct {
+	mock.Mock
+}
+
+func (m *MockDatabasePinger) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestHealthController_Liveness(t *testing.T) {
+	// Arrange
+	mockDB := new(MockDatabasePinger)
+	controller := NewHealthController(mockDB, Domain.VersionInfo{Version: "1.2.3", Commit: "abc1234", BuildTime: "2024-05-01T10:00:00Z"}, time.Now().Add(-90*time.Second))
+	router := setupGinContext()
+	router.GET("/healthz", controller.Liveness)
+	req := httptest.NewRequest("GET", "/healthz", nil)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, req)
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response Domain.HealthResponse
+	assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+	assert.Equal(t, Domain.HealthStatusOK, response.Status)
+	assert.Equal(t, "1.2.3", response.Version)
+	assert.Equal(t, "abc1234", response.Commit)
+	assert.Equal(t, "2024-05-01T10:00:00Z", response.BuildTime)
+	assert.Equal(t, "1m30s", response.Uptime)
+	assert.Equal(t, int64(90), response.UptimeSeconds)
+	mockDB.AssertNotCalled(t, "Ping")
+}
+
+func TestHealthController_Readiness(t *testing.T) {
+	t.Run("Success - database reachable", func(t *testing.T) {
+		// Arrange
+		mockDB := new(MockDatabasePinger)
+		controller := NewHealthController(mockDB, Domain.VersionInfo{Version: "1.2.3", Commit: "abc1234", BuildTime: "2024-05-01T10:00:00Z"}, time.Now())
+		router := setupGinContext()
+		router.GET("/readyz", controller.Readiness)
+		mockDB.On("Ping").Return(nil)
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		var response Domain.HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, Domain.HealthStatusOK, response.Status)
+		assert.Empty(t, response.Error)
+		mockDB.AssertExpectations(t)
+	})
+
+	t.Run("Error - database unreachable", func(t *testing.T) {
+		// Arrange
+		mockDB := new(MockDatabasePinger)
+		controller := NewHealthController(mockDB, Domain.VersionInfo{Version: "1.2.3", Commit: "abc1234", BuildTime: "2024-05-01T10:00:00Z"}, time.Now())
+		router := setupGinContext()
+		router.GET("/readyz", controller.Readiness)
+		mockDB.On("Ping").Return(errors.New("server selection error: context deadline exceeded"))
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response Domain.HealthResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, Domain.HealthStatusUnavailable, response.Status)
+		assert.Equal(t, "Database is unreachable", response.Message)
+		assert.Equal(t, "server selection error: context deadline exceeded", response.Error)
+		assert.Equal(t, "1.2.3", response.Version)
+	})
+}
+
+func TestHealthController_Version(t *testing.T) {
+	// Arrange
+	build := Domain.VersionInfo{Version: "1.2.3", Commit: "abc1234", BuildTime: "2024-05-01T10:00:00Z"}
+	controller := NewHealthController(new(MockDatabasePinger), build, time.Now())
+	router := setupGinContext()
+	router.GET("/version", controller.Version)
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/version", nil))
+
+	// Assert
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"version":"1.2.3","commit":"abc1234","build_time":"2024-05-01T10:00:00Z"}`, w.Body.String())
+}
@@ -0,0 +1,44 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// KeySetProvider supplies the public keys that verify access tokens; Infrastructure.JWTService implements it
+type KeySetProvider interface {
+	JWKS() *Domain.JSONWebKeySet
+}
+
+// JWKSController serves the public key set so other services can verify tokens without the signing key
+type JWKSController struct {
+	keys KeySetProvider
+}
+
+// NewJWKSController creates a new instance of JWKSController
+func NewJWKSController(keys KeySetProvider) *JWKSController {
+	return &JWKSController{
+		keys: keys,
+	}
+}
+
+// GetJWKS handles GET /.well-known/jwks.json. It answers 404 when tokens are signed with a shared secret.
+func (jc *JWKSController) GetJWKS(c *gin.Context) {
+	keySet := jc.keys.JWKS()
+	if keySet == nil {
+		message := localize(c, Domain.MsgNoPublicKeys)
+		c.JSON(http.StatusNotFound, Domain.ErrorResponse{
+			Success:   false,
+			Message:   message.Text,
+			MessageID: message.ID,
+			Error:     "Tokens are signed with a shared secret",
+			RequestID: Domain.RequestIDFromContext(c.Request.Context()),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, keySet)
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+// stubKeySetProvider returns a fixed key set
+type stubKeySetProvider struct {
+	keySet *Domain.JSONWebKeySet
+}
+
+func (s stubKeySetProvider) JWKS() *Domain.JSONWebKeySet {
+	return s.keySet
+}
+
+func TestJWKSController_GetJWKS(t *testing.T) {
+	t.Run("Success - serve public keys", func(t *testing.T) {
+		// Arrange
+		keySet := &Domain.JSONWebKeySet{Keys: []Domain.JSONWebKey{{Kty: "RSA", Use: "sig", Alg: "RS256", Kid: "key-1", N: "modulus", E: "AQAB"}}}
+		controller := NewJWKSController(stubKeySetProvider{keySet: keySet})
+		router := setupGinContext()
+		router.GET("/.well-known/jwks.json", controller.GetJWKS)
+		req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "public, max-age=300", w.Header().Get("Cache-Control"))
+
+		var response Domain.JSONWebKeySet
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, *keySet, response)
+	})
+
+	t.Run("Error - no public keys with a shared secret", func(t *testing.T) {
+		// Arrange
+		controller := NewJWKSController(stubKeySetProvider{})
+		router := setupGinContext()
+		router.GET("/.well-known/jwks.json", controller.GetJWKS)
+		req := httptest.NewRequest("GET", "/.well-known/jwks.json", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+	})
+}
@@ -0,0 +1,506 @@
+package controllers
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"task_manager/Domain"
+)
+
+// openAPIDocument is the part of an OpenAPI 3.0 document this API uses
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+}
+
+type openAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type openAPIOperation struct {
+	Summary     string                     `json:"summary"`
+	OperationID string                     `json:"operationId"`
+	Tags        []string                   `json:"tags"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *openAPIRequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+	Security    []map[string][]string      `json:"security,omitempty"`
+}
+
+type openAPIParameter struct {
+	Name        string         `json:"name"`
+	In          string         `json:"in"`
+	Description string         `json:"description,omitempty"`
+	Required    bool           `json:"required,omitempty"`
+	Schema      *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type         string `json:"type"`
+	Scheme       string `json:"scheme,omitempty"`
+	BearerFormat string `json:"bearerFormat,omitempty"`
+	In           string `json:"in,omitempty"`
+	Name         string `json:"name,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Pattern              string                    `json:"pattern,omitempty"`
+	MaxLength            int                       `json:"maxLength,omitempty"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	AllOf                []*openAPISchema          `json:"allOf,omitempty"`
+}
+
+var (
+	taskStatuses   = []string{Domain.StatusPending, Domain.StatusInProgress, Domain.StatusCompleted}
+	taskPriorities = []string{Domain.PriorityLow, Domain.PriorityMedium, Domain.PriorityHigh, Domain.PriorityUrgent}
+	recurrences    = []string{Domain.RecurrenceNone, Domain.RecurrenceDaily, Domain.RecurrenceWeekly, Domain.RecurrenceMonthly}
+	roles          = []string{Domain.RoleUser, Domain.RoleManager, Domain.RoleAdmin}
+	auditEntities  = []string{Domain.AuditEntityTask, Domain.AuditEntityUser, Domain.AuditEntityAPIKey, Domain.AuditEntityWebhook, Domain.AuditEntityAPI}
+)
+
+// schemaEnums lists the allowed values of string fields, keyed by type name and JSON field name
+var schemaEnums = map[string][]string{
+	"Task.status":                 taskStatuses,
+	"Task.priority":               taskPriorities,
+	"Task.recurrence":             recurrences,
+	"TaskRequest.status":          taskStatuses,
+	"TaskRequest.priority":        taskPriorities,
+	"TaskRequest.recurrence":      recurrences,
+	"TaskPatchRequest.status":     taskStatuses,
+	"TaskPatchRequest.priority":   taskPriorities,
+	"TaskPatchRequest.recurrence": recurrences,
+	"TaskStatusRequest.status":    taskStatuses,
+	"BulkStatusRequest.status":    taskStatuses,
+	"User.role":                   roles,
+	"APIKey.role":                 roles,
+	"APIKeyRequest.role":          roles,
+	"PromoteRequest.role":         {Domain.RoleManager, Domain.RoleAdmin},
+	"AuditEntry.entity":           auditEntities,
+	"WebhookDelivery.event":       Domain.EventTypes,
+	"HealthResponse.status":       {Domain.HealthStatusOK, Domain.HealthStatusUnavailable},
+}
+
+// schemaBuilder turns Go types into OpenAPI schemas. Named structs become components that are
+// referenced by name, so the spec follows the Domain types as they change.
+type schemaBuilder struct {
+	components map[string]*openAPISchema
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	objectIDType = reflect.TypeOf(primitive.ObjectID{})
+)
+
+// schemaOf returns the schema of the type of value, which may be a nil pointer
+func (b *schemaBuilder) schemaOf(value any) *openAPISchema {
+	return b.schemaFor(reflect.TypeOf(value))
+}
+
+func (b *schemaBuilder) schemaFor(t reflect.Type) *openAPISchema {
+	if t == nil {
+		return &openAPISchema{}
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch {
+	case t == timeType:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	case t == objectIDType:
+		return &openAPISchema{Type: "string", Pattern: "^[0-9a-f]{24}$"}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &openAPISchema{Type: "string"}
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}
+	case reflect.Slice, reflect.Array:
+		return &openAPISchema{Type: "array", Items: b.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &openAPISchema{Type: "object", AdditionalProperties: b.schemaFor(t.Elem())}
+	case reflect.Struct:
+		name := componentName(t)
+		if _, ok := b.components[name]; !ok {
+			// Registered before the fields are walked so self-referencing types terminate
+			schema := &openAPISchema{Type: "object", Properties: map[string]*openAPISchema{}}
+			b.components[name] = schema
+			b.addFields(schema, t, name)
+		}
+		return &openAPISchema{Ref: "#/components/schemas/" + name}
+	default:
+		// interface{} fields hold different types depending on the endpoint
+		return &openAPISchema{}
+	}
+}
+
+// componentName names the component of a struct type. Instances of a generic type are named
+// after their type argument, so Domain.ListResponse[*Domain.Task] becomes TaskListResponse.
+func componentName(t reflect.Type) string {
+	base, argument, generic := strings.Cut(t.Name(), "[")
+	if !generic {
+		return base
+	}
+	argument = strings.TrimLeft(strings.TrimSuffix(argument, "]"), "*[]")
+	if i := strings.LastIndex(argument, "."); i >= 0 {
+		argument = argument[i+1:]
+	}
+	return strings.ToUpper(argument[:1]) + argument[1:] + base
+}
+
+// addFields adds the JSON fields of struct type t to schema. Fields of embedded structs are
+// inlined, as encoding/json does.
+func (b *schemaBuilder) addFields(schema *openAPISchema, t reflect.Type, typeName string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			b.addFields(schema, embedded, embedded.Name())
+			continue
+		}
+
+		tag := field.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if name == "" {
+			name = field.Name
+		}
+
+		fieldSchema := b.schemaFor(field.Type)
+		if enum, ok := schemaEnums[typeName+"."+name]; ok {
+			fieldSchema.Enum = enum
+		}
+		schema.Properties[name] = fieldSchema
+
+		// Requests require what binding requires; responses always include fields without omitempty
+		required := strings.Contains(field.Tag.Get("binding"), "required")
+		if isResponseType(typeName) && !strings.Contains(options, "omitempty") && field.Type.Kind() != reflect.Pointer {
+			required = true
+		}
+		if required {
+			schema.Required = append(schema.Required, name)
+		}
+	}
+}
+
+// isResponseType reports whether a type is only ever sent by the server
+func isResponseType(typeName string) bool {
+	return !strings.HasSuffix(typeName, "Request") && typeName != "TaskImport"
+}
+
+// openAPIRoute describes one operation of the API
+type openAPIRoute struct {
+	method     string
+	path       string // In OpenAPI form, /tasks/{id}
+	tag        string
+	summary    string
+	public     bool // Served without a token
+	parameters []openAPIParameter
+	body       any  // Request body type, nil when there is none
+	upload     bool // The body is a form whose file field holds an uploaded file
+	status     int  // Success status
+	response   *openAPISchema
+}
+
+// buildOpenAPISpec describes every route SetupRouter registers
+func buildOpenAPISpec(version string) *openAPIDocument {
+	b := &schemaBuilder{components: map[string]*openAPISchema{}}
+
+	message := b.schemaOf(Domain.MessageResponse{})
+	errorSchema := b.schemaOf(Domain.ErrorResponse{})
+
+	idParam := openAPIParameter{Name: "id", In: "path", Required: true, Schema: &openAPISchema{Type: "string", Pattern: "^[0-9a-f]{24}$"}}
+	attachmentIDParam := openAPIParameter{Name: "attachmentId", In: "path", Required: true, Schema: idParam.Schema}
+	query := func(name, description string, schema *openAPISchema) openAPIParameter {
+		return openAPIParameter{Name: name, In: "query", Description: description, Schema: schema}
+	}
+	str := func(enum ...string) *openAPISchema { return &openAPISchema{Type: "string", Enum: enum} }
+	integer := &openAPISchema{Type: "integer"}
+	boolean := &openAPISchema{Type: "boolean"}
+	date := &openAPISchema{Type: "string", Format: "date"}
+
+	limit := query("limit", "Page size", integer)
+	offset := query("offset", "Number of results to skip", integer)
+	sortByPriority := query("sort", "Sort order", str(Domain.SortPriority))
+	taskFields := query("fields", "Comma-separated task fields to return, any of: "+strings.Join(Domain.TaskFields, ", "), str())
+	taskFilter := []openAPIParameter{
+		query("status", "Only tasks with this status", str(taskStatuses...)),
+		query("tag", "Only tasks with this tag", str()),
+		query("priority", "Only tasks with this priority", str(taskPriorities...)),
+		query("due_before", "Only tasks due on or before this day", date),
+		query("due_after", "Only tasks due on or after this day", date),
+		query("include_deleted", "Include soft deleted tasks (admins only)", boolean),
+		query("archived", "List archived tasks instead of the others", boolean),
+	}
+	withParams := func(base []openAPIParameter, params ...openAPIParameter) []openAPIParameter {
+		return append(append([]openAPIParameter{}, base...), params...)
+	}
+
+	routes := []openAPIRoute{
+		{method: http.MethodPost, path: "/api/v1/register", tag: "auth", summary: "Register a user", public: true, body: Domain.UserRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+		{method: http.MethodPost, path: "/api/v1/login", tag: "auth", summary: "Log in", public: true, body: Domain.LoginRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.LoginResponse{})},
+		{method: http.MethodPost, path: "/api/v1/auth/refresh", tag: "auth", summary: "Exchange a refresh token for new tokens", public: true, body: Domain.RefreshRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.LoginResponse{})},
+		{method: http.MethodPost, path: "/api/v1/auth/logout", tag: "auth", summary: "Revoke the access token and optionally a refresh token", body: Domain.LogoutRequest{}, status: http.StatusOK, response: message},
+		{method: http.MethodPost, path: "/api/v1/auth/forgot-password", tag: "auth", summary: "Send a password reset token", public: true, body: Domain.ForgotPasswordRequest{}, status: http.StatusAccepted, response: message},
+		{method: http.MethodPost, path: "/api/v1/auth/reset-password", tag: "auth", summary: "Set a new password with a reset token", public: true, body: Domain.ResetPasswordRequest{}, status: http.StatusOK, response: message},
+		{method: http.MethodPost, path: "/api/v1/auth/verify-email", tag: "auth", summary: "Confirm an email address", public: true, body: Domain.VerifyEmailRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+
+		{method: http.MethodGet, path: "/api/v1/users/profile", tag: "users", summary: "Get the caller's profile", status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+		{method: http.MethodPut, path: "/api/v1/users/profile", tag: "users", summary: "Change the caller's username or email", body: Domain.ProfileUpdateRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+		{method: http.MethodPut, path: "/api/v1/users/password", tag: "users", summary: "Change the caller's password", body: Domain.ChangePasswordRequest{}, status: http.StatusOK, response: message},
+		{method: http.MethodGet, path: "/api/v1/users/me/logins", tag: "users", summary: "List the caller's recent logins", status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.LoginEvent]{})},
+		{method: http.MethodGet, path: "/api/v1/users", tag: "users", summary: "List users", parameters: []openAPIParameter{
+			query("q", "Search usernames and emails", str()),
+			query("role", "Only users with this role", str(roles...)),
+			query("sort", "Sort order", str(Domain.SortCreatedAt, Domain.SortUsername)),
+			query("page", "Page number, starting at 1", integer),
+			query("page_size", "Page size", integer),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.User]{})},
+		{method: http.MethodGet, path: "/api/v1/users/directory", tag: "users", summary: "List the IDs and usernames of users, for picking assignees", parameters: []openAPIParameter{
+			query("q", "Search usernames", str()),
+			query("sort", "Sort order", str(Domain.SortCreatedAt, Domain.SortUsername)),
+			query("page", "Page number, starting at 1", integer),
+			query("page_size", "Page size", integer),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.UserDirectoryEntry]{})},
+		{method: http.MethodGet, path: "/api/v1/users/export", tag: "users", summary: "Export users as CSV, without password hashes", parameters: []openAPIParameter{
+			query("q", "Search usernames and emails", str()),
+			query("role", "Only users with this role", str(roles...)),
+		}, status: http.StatusOK, response: &openAPISchema{Type: "string"}},
+		{method: http.MethodPost, path: "/api/v1/users/promote", tag: "users", summary: "Promote a user to manager or admin", body: Domain.PromoteRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+		{method: http.MethodPost, path: "/api/v1/users/demote", tag: "users", summary: "Demote an admin or manager to user", body: Domain.DemoteRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+		{method: http.MethodDelete, path: "/api/v1/users/{id}", tag: "users", summary: "Delete or anonymize a user", parameters: []openAPIParameter{
+			idParam, query("anonymize", "Scrub personal data but keep the record", boolean),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.UserDeletionResult]{})},
+		{method: http.MethodPost, path: "/api/v1/users/{id}/deactivate", tag: "users", summary: "Deactivate a user", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+		{method: http.MethodPost, path: "/api/v1/users/{id}/activate", tag: "users", summary: "Activate a user", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.User]{})},
+
+		{method: http.MethodGet, path: "/api/v1/tasks", tag: "tasks", summary: "List tasks", parameters: withParams(taskFilter, limit, offset, sortByPriority, taskFields), status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Task]{})},
+		{method: http.MethodPost, path: "/api/v1/tasks", tag: "tasks", summary: "Create a task", parameters: []openAPIParameter{
+			query("allow_duplicate", "Set to false to refuse a title repeating one of the caller's open tasks", boolean),
+		}, body: Domain.TaskRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodDelete, path: "/api/v1/tasks", tag: "tasks", summary: "Delete every task with a status", parameters: []openAPIParameter{
+			{Name: "status", In: "query", Required: true, Schema: str(taskStatuses...)},
+		}, status: http.StatusOK, response: b.schemaOf(Domain.BulkResponse{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/{id}", tag: "tasks", summary: "Get a task", parameters: []openAPIParameter{
+			idParam, query("include", "Set to subtasks to include the task's subtasks", str("subtasks")), taskFields,
+		}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodPut, path: "/api/v1/tasks/{id}", tag: "tasks", summary: "Replace a task", parameters: []openAPIParameter{idParam}, body: Domain.TaskRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodPatch, path: "/api/v1/tasks/{id}", tag: "tasks", summary: "Change some fields of a task", parameters: []openAPIParameter{idParam}, body: Domain.TaskPatchRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodDelete, path: "/api/v1/tasks/{id}", tag: "tasks", summary: "Move a task to the trash", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: message},
+		{method: http.MethodGet, path: "/api/v1/tasks/assigned-to-me", tag: "tasks", summary: "List tasks assigned to the caller", parameters: withParams(taskFilter, limit, offset, sortByPriority), status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Task]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/tags", tag: "tasks", summary: "List the tags in use", status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[string]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/count", tag: "tasks", summary: "Count tasks", parameters: taskFilter, status: http.StatusOK, response: b.schemaOf(Domain.CountResponse{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/search", tag: "tasks", summary: "Search task titles and descriptions, most relevant first", parameters: []openAPIParameter{
+			{Name: "q", In: "query", Description: "Words to search for; a task matches any of them", Required: true, Schema: &openAPISchema{Type: "string", MaxLength: Domain.MaxSearchQueryLength}},
+			limit, offset,
+		}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Task]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/export", tag: "tasks", summary: "Export tasks as CSV or JSON", parameters: withParams(taskFilter,
+			query("format", "Export format", str("csv", "json")),
+		), status: http.StatusOK, response: &openAPISchema{Type: "string"}},
+		{method: http.MethodGet, path: "/api/v1/tasks/stats", tag: "tasks", summary: "Summarize the caller's tasks", status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.TaskStats]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/overdue", tag: "tasks", summary: "List overdue tasks", parameters: []openAPIParameter{limit, offset}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Task]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/events", tag: "tasks", summary: "Stream the caller's task changes as server-sent events", status: http.StatusOK, response: &openAPISchema{Type: "string"}},
+		{method: http.MethodPost, path: "/api/v1/tasks/{id}/comments", tag: "comments", summary: "Comment on a task", parameters: []openAPIParameter{idParam}, body: Domain.CommentRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.Comment]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/{id}/comments", tag: "comments", summary: "List a task's comments", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Comment]{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/{id}/worklogs", tag: "worklogs", summary: "Log time spent on a task", parameters: []openAPIParameter{idParam}, body: Domain.WorkLogRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.WorkLog]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/{id}/worklogs", tag: "worklogs", summary: "List the time logged on a task, earliest day first", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.WorkLog]{})},
+		{method: http.MethodGet, path: "/api/v1/worklogs/summary", tag: "worklogs", summary: "Total the time each user logged, most first", parameters: []openAPIParameter{
+			query("user_id", "Only this user; regular users always get their own total", str()),
+			query("from", "Only work on or after this day", date),
+			query("to", "Only work on or before this day", date),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.WorkLogSummary]{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/{id}/attachments", tag: "attachments", summary: "Attach a file to a task", parameters: []openAPIParameter{idParam}, upload: true, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.Attachment]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/{id}/attachments", tag: "attachments", summary: "List a task's attachments", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Attachment]{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/{id}/attachments/{attachmentId}", tag: "attachments", summary: "Download an attachment", parameters: []openAPIParameter{idParam, attachmentIDParam}, status: http.StatusOK, response: &openAPISchema{Type: "string", Format: "binary"}},
+		{method: http.MethodDelete, path: "/api/v1/tasks/{id}/attachments/{attachmentId}", tag: "attachments", summary: "Delete an attachment and its file", parameters: []openAPIParameter{idParam, attachmentIDParam}, status: http.StatusOK, response: message},
+		{method: http.MethodGet, path: "/api/v1/tasks/{id}/revisions", tag: "tasks", summary: "List a task's previous versions", parameters: []openAPIParameter{idParam, limit, offset}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.TaskRevision]{})},
+		{method: http.MethodPatch, path: "/api/v1/tasks/{id}/status", tag: "tasks", summary: "Change a task's status", parameters: []openAPIParameter{idParam}, body: Domain.TaskStatusRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/bulk", tag: "tasks", summary: "Create several tasks", parameters: []openAPIParameter{
+			query("atomic", "Create all tasks or none", boolean),
+		}, body: []Domain.TaskRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.BulkCreateResponse{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/import", tag: "tasks", summary: "Import tasks from a JSON array, sent as the body or as the file field of a form", parameters: []openAPIParameter{
+			query("skip_duplicates", "Skip tasks whose title matches an existing task", boolean),
+		}, body: []Domain.TaskImport{}, status: http.StatusOK, response: b.schemaOf(Domain.ImportResponse{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/bulk-status", tag: "tasks", summary: "Change the status of several tasks", body: Domain.BulkStatusRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.BulkResponse{})},
+		{method: http.MethodGet, path: "/api/v1/tasks/trash", tag: "tasks", summary: "List soft deleted tasks", parameters: []openAPIParameter{limit, offset}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Task]{})},
+		{method: http.MethodDelete, path: "/api/v1/tasks/trash", tag: "tasks", summary: "Permanently remove old soft deleted tasks", parameters: []openAPIParameter{
+			query("older_than_days", "Only tasks deleted at least this many days ago", integer),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.PurgeResponse{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/{id}/restore", tag: "tasks", summary: "Restore a soft deleted task", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/archive", tag: "tasks", summary: "Archive every task completed before a date", parameters: []openAPIParameter{
+			{Name: "completed_before", In: "query", Description: "A date, meaning the start of that day, or an RFC 3339 time", Required: true, Schema: str()},
+		}, status: http.StatusOK, response: b.schemaOf(Domain.BulkResponse{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/{id}/archive", tag: "tasks", summary: "Archive a task", parameters: []openAPIParameter{
+			idParam,
+			query("force", "Archive a task that is not completed (always allowed for admins)", boolean),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+		{method: http.MethodPost, path: "/api/v1/tasks/{id}/unarchive", tag: "tasks", summary: "Unarchive a task", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Task]{})},
+
+		{method: http.MethodPost, path: "/api/v1/api-keys", tag: "api-keys", summary: "Create an API key", body: Domain.APIKeyRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.CreatedAPIKey]{})},
+		{method: http.MethodGet, path: "/api/v1/api-keys", tag: "api-keys", summary: "List API keys", status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.APIKey]{})},
+		{method: http.MethodDelete, path: "/api/v1/api-keys/{id}", tag: "api-keys", summary: "Revoke an API key", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.APIKey]{})},
+
+		{method: http.MethodPost, path: "/api/v1/webhooks", tag: "webhooks", summary: "Create a webhook", body: Domain.WebhookRequest{}, status: http.StatusCreated, response: b.schemaOf(Domain.Response[*Domain.CreatedWebhook]{})},
+		{method: http.MethodGet, path: "/api/v1/webhooks", tag: "webhooks", summary: "List webhooks", status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.Webhook]{})},
+		{method: http.MethodGet, path: "/api/v1/webhooks/{id}", tag: "webhooks", summary: "Get a webhook", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Webhook]{})},
+		{method: http.MethodPut, path: "/api/v1/webhooks/{id}", tag: "webhooks", summary: "Replace a webhook, keeping its secret unless a new one is given", parameters: []openAPIParameter{idParam}, body: Domain.WebhookRequest{}, status: http.StatusOK, response: b.schemaOf(Domain.Response[*Domain.Webhook]{})},
+		{method: http.MethodDelete, path: "/api/v1/webhooks/{id}", tag: "webhooks", summary: "Delete a webhook and its delivery log", parameters: []openAPIParameter{idParam}, status: http.StatusOK, response: message},
+		{method: http.MethodGet, path: "/api/v1/webhooks/{id}/deliveries", tag: "webhooks", summary: "List a webhook's recent deliveries, newest first", parameters: []openAPIParameter{idParam, limit, offset}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.WebhookDelivery]{})},
+
+		{method: http.MethodGet, path: "/api/v1/audit", tag: "audit", summary: "List audit log entries", parameters: []openAPIParameter{
+			query("actor_id", "Only changes by this user or API key", str()),
+			query("entity", "Only changes to this kind of record", str(auditEntities...)),
+			limit, offset,
+		}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.AuditEntry]{})},
+
+		{method: http.MethodGet, path: "/api/v1/reports/tasks-by-user", tag: "reports", summary: "Count every user's tasks by status, busiest first", parameters: []openAPIParameter{
+			query("since", "Only tasks created on or after this day", date),
+		}, status: http.StatusOK, response: b.schemaOf(Domain.ListResponse[*Domain.UserTaskReport]{})},
+
+		{method: http.MethodGet, path: "/healthz", tag: "health", summary: "Report that the process is up", public: true, status: http.StatusOK, response: b.schemaOf(Domain.HealthResponse{})},
+		{method: http.MethodGet, path: "/readyz", tag: "health", summary: "Report whether the database is reachable", public: true, status: http.StatusOK, response: b.schemaOf(Domain.HealthResponse{})},
+		{method: http.MethodGet, path: "/version", tag: "health", summary: "Report which build is running", public: true, status: http.StatusOK, response: b.schemaOf(Domain.VersionInfo{})},
+		{method: http.MethodGet, path: "/.well-known/jwks.json", tag: "health", summary: "Get the public keys that verify access tokens", public: true, status: http.StatusOK, response: b.schemaOf(Domain.JSONWebKeySet{})},
+		{method: http.MethodGet, path: "/metrics", tag: "health", summary: "Prometheus metrics", public: true, status: http.StatusOK, response: &openAPISchema{Type: "string"}},
+		{method: http.MethodGet, path: "/openapi.json", tag: "health", summary: "This document", public: true, status: http.StatusOK, response: &openAPISchema{Type: "object"}},
+		{method: http.MethodGet, path: "/docs", tag: "health", summary: "Interactive API documentation", public: true, status: http.StatusOK, response: &openAPISchema{Type: "string"}},
+	}
+
+	doc := &openAPIDocument{
+		OpenAPI: "3.0.3",
+		Info: openAPIInfo{
+			Title:       "Task Manager API",
+			Version:     version,
+			Description: "Tasks, users and their permissions. Errors use the ErrorResponse envelope.",
+		},
+		Paths: map[string]map[string]openAPIOperation{},
+		Components: openAPIComponents{
+			Schemas: b.components,
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"bearerAuth": {Type: "http", Scheme: "bearer", BearerFormat: "JWT", Description: "Access token from /api/v1/login"},
+				"apiKeyAuth": {Type: "apiKey", In: "header", Name: "X-API-Key", Description: "API key created by an admin"},
+			},
+		},
+	}
+
+	for _, route := range routes {
+		contentType := "application/json"
+		switch route.path {
+		case "/api/v1/tasks/export", "/api/v1/users/export":
+			contentType = "text/csv"
+		case "/api/v1/tasks/{id}/attachments/{attachmentId}":
+			if route.method == http.MethodGet {
+				contentType = "application/octet-stream"
+			}
+		case "/metrics":
+			contentType = "text/plain"
+		case "/docs":
+			contentType = "text/html"
+		}
+
+		operation := openAPIOperation{
+			Summary:     route.summary,
+			OperationID: operationID(route.method, route.path),
+			Tags:        []string{route.tag},
+			Parameters:  route.parameters,
+			Responses: map[string]openAPIResponse{
+				strconv.Itoa(route.status): {
+					Description: http.StatusText(route.status),
+					Content:     map[string]openAPIMediaType{contentType: {Schema: route.response}},
+				},
+				"default": {
+					Description: "Error",
+					Content:     map[string]openAPIMediaType{"application/json": {Schema: errorSchema}},
+				},
+			},
+		}
+		if route.body != nil {
+			operation.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"application/json": {Schema: b.schemaOf(route.body)}},
+			}
+		}
+		if route.upload {
+			form := &openAPISchema{
+				Type:       "object",
+				Properties: map[string]*openAPISchema{"file": {Type: "string", Format: "binary"}},
+				Required:   []string{"file"},
+			}
+			operation.RequestBody = &openAPIRequestBody{
+				Required: true,
+				Content:  map[string]openAPIMediaType{"multipart/form-data": {Schema: form}},
+			}
+		}
+		if !route.public {
+			operation.Security = []map[string][]string{{"bearerAuth": {}}, {"apiKeyAuth": {}}}
+		}
+
+		if doc.Paths[route.path] == nil {
+			doc.Paths[route.path] = map[string]openAPIOperation{}
+		}
+		doc.Paths[route.path][strings.ToLower(route.method)] = operation
+	}
+
+	return doc
+}
+
+// operationID derives a stable operation ID such as getApiV1TasksId from a method and path
+func operationID(method, path string) string {
+	var builder strings.Builder
+	builder.WriteString(strings.ToLower(method))
+	for _, part := range strings.FieldsFunc(path, func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '{' || r == '}'
+	}) {
+		builder.WriteString(strings.ToUpper(part[:1]) + part[1:])
+	}
+	return builder.String()
+}
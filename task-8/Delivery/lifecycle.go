@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"task_manager/Delivery/routers"
+)
+
+// Lifecycle starts the background components of the server under one root context and drains them
+// at shutdown, once the HTTP server has stopped handing them new work
+type Lifecycle struct {
+	logger     *slog.Logger
+	ctx        context.Context
+	cancel     context.CancelFunc
+	components []routers.Component
+	started    []routers.Component
+}
+
+// NewLifecycle creates a Lifecycle with no components
+func NewLifecycle(logger *slog.Logger) *Lifecycle {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Lifecycle{
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// Register adds components, which are started in the order they are registered
+func (l *Lifecycle) Register(components ...routers.Component) {
+	l.components = append(l.components, components...)
+}
+
+// Start starts every registered component under the root context. When one fails, the components
+// already started keep running and Stop drains them as usual.
+func (l *Lifecycle) Start() error {
+	for _, component := range l.components {
+		if err := component.Start(l.ctx); err != nil {
+			return fmt.Errorf("failed to start %s: %w", component.Name(), err)
+		}
+		l.started = append(l.started, component)
+		l.logger.Debug("started component", "component", component.Name())
+	}
+	return nil
+}
+
+// Stop cancels the root context and stops the started components in reverse order, waiting for
+// each to drain until ctx is done. A component still draining at the deadline is logged by name
+// and abandoned, and the components after it are told to stop without waiting.
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	l.cancel()
+
+	var errs []error
+	for i := len(l.started) - 1; i >= 0; i-- {
+		component := l.started[i]
+
+		stopped := make(chan error, 1)
+		go func() {
+			stopped <- component.Stop(ctx)
+		}()
+
+		var err error
+		select {
+		case err = <-stopped:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		switch {
+		case err == nil:
+			l.logger.Info("stopped component", "component", component.Name())
+		case errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled):
+			l.logger.Error("component did not drain before the shutdown deadline", "component", component.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", component.Name(), err))
+		default:
+			l.logger.Error("failed to stop component", "component", component.Name(), "error", err)
+			errs = append(errs, fmt.Errorf("%s: %w", component.Name(), err))
+		}
+	}
+	l.started = nil
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"task_manager/Infrastructure"
+)
+
+// fakeComponent records when it is started and stopped; a blocking one does not finish draining until blocking is closed
+type fakeComponent struct {
+	name     string
+	events   *[]string
+	mu       *sync.Mutex
+	startErr error
+	blocking chan struct{}
+	rootCtx  context.Context
+}
+
+func (f *fakeComponent) Name() string {
+	return f.name
+}
+
+func (f *fakeComponent) Start(ctx context.Context) error {
+	if f.startErr != nil {
+		return f.startErr
+	}
+	f.rootCtx = ctx
+	f.record("start " + f.name)
+	return nil
+}
+
+func (f *fakeComponent) Stop(ctx context.Context) error {
+	f.record("stop " + f.name)
+	if f.blocking != nil {
+		<-f.blocking
+	}
+	return nil
+}
+
+func (f *fakeComponent) record(event string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	*f.events = append(*f.events, event)
+}
+
+// newFakeComponents creates components named after names that record into one list of events
+func newFakeComponents(names ...string) ([]*fakeComponent, *[]string) {
+	events := &[]string{}
+	mu := &sync.Mutex{}
+	var components []*fakeComponent
+	for _, name := range names {
+		components = append(components, &fakeComponent{name: name, events: events, mu: mu})
+	}
+	return components, events
+}
+
+func TestLifecycle(t *testing.T) {
+	t.Run("Success - components stop in reverse order after the root context is cancelled", func(t *testing.T) {
+		// Arrange
+		fakes, events := newFakeComponents("dispatcher", "streams", "reminders")
+		lifecycle := NewLifecycle(Infrastructure.NewNopLogger())
+		for _, fake := range fakes {
+			lifecycle.Register(fake)
+		}
+
+		// Act
+		startErr := lifecycle.Start()
+		rootCtx := fakes[0].rootCtx
+		rootErrBeforeStop := rootCtx.Err()
+		stopErr := lifecycle.Stop(context.Background())
+
+		// Assert
+		assert.NoError(t, startErr)
+		assert.NoError(t, stopErr)
+		assert.NoError(t, rootErrBeforeStop)
+		assert.ErrorIs(t, rootCtx.Err(), context.Canceled)
+		assert.Equal(t, []string{
+			"start dispatcher", "start streams", "start reminders",
+			"stop reminders", "stop streams", "stop dispatcher",
+		}, *events)
+	})
+
+	t.Run("Error - a component that does not drain is named when the deadline hits", func(t *testing.T) {
+		// Arrange
+		fakes, _ := newFakeComponents("dispatcher", "streams")
+		fakes[1].blocking = make(chan struct{})
+		defer close(fakes[1].blocking)
+		var logs bytes.Buffer
+		lifecycle := NewLifecycle(slog.New(slog.NewTextHandler(&logs, nil)))
+		lifecycle.Register(fakes[0], fakes[1])
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// Act
+		assert.NoError(t, lifecycle.Start())
+		err := lifecycle.Stop(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Contains(t, err.Error(), "streams")
+		assert.Contains(t, logs.String(), `msg="component did not drain before the shutdown deadline" component=streams`)
+	})
+
+	t.Run("Error - components after one that fails to start are not started", func(t *testing.T) {
+		// Arrange
+		fakes, events := newFakeComponents("dispatcher", "streams", "reminders")
+		fakes[1].startErr = errors.New("port in use")
+		lifecycle := NewLifecycle(Infrastructure.NewNopLogger())
+		lifecycle.Register(fakes[0], fakes[1], fakes[2])
+
+		// Act
+		startErr := lifecycle.Start()
+		stopErr := lifecycle.Stop(context.Background())
+
+		// Assert
+		assert.EqualError(t, startErr, "failed to start streams: port in use")
+		assert.NoError(t, stopErr)
+		assert.Equal(t, []string{"start dispatcher", "stop dispatcher"}, *events)
+	})
+}
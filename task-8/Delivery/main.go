@@ -2,23 +2,147 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	"github.com/joho/godotenv"
+	"go.mongodb.org/mongo-driver/event"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
 
 	"task_manager/Delivery/routers"
+	"task_manager/Infrastructure"
+	"task_manager/Infrastructure/buildinfo"
 )
 
-// GetDatabaseConfig returns database configuration from environment variables or defaults
-func GetDatabaseConfig() *routers.DatabaseConfig {
+// Default MongoDB client settings, used when the matching environment variable is unset
+const (
+	DefaultMongoMaxPoolSize            = 100
+	DefaultMongoMinPoolSize            = 0
+	DefaultMongoConnectTimeout         = 10 * time.Second
+	DefaultMongoSocketTimeout          = 0
+	DefaultMongoServerSelectionTimeout = 10 * time.Second
+	DefaultMongoReadPreference         = "primary"
+)
+
+// Default HTTP server settings, used when the matching environment variable is unset.
+// Writes get a generous timeout because CSV exports stream for as long as they need.
+const (
+	DefaultServerPort              = 8080
+	DefaultServerReadTimeout       = 30 * time.Second
+	DefaultServerReadHeaderTimeout = 10 * time.Second
+	DefaultServerWriteTimeout      = 5 * time.Minute
+	DefaultServerIdleTimeout       = 2 * time.Minute
+	DefaultReminderInterval        = 5 * time.Minute
+)
+
+// ServerConfig holds the HTTP server configuration
+type ServerConfig struct {
+	Port int
+
+	// ReadTimeout bounds reading a whole request, body included; 0 means none
+	ReadTimeout time.Duration
+	// ReadHeaderTimeout bounds reading the request headers
+	ReadHeaderTimeout time.Duration
+	// WriteTimeout bounds writing the response; 0 means none
+	WriteTimeout time.Duration
+	// IdleTimeout bounds how long a keep-alive connection waits for the next request; 0 means ReadTimeout
+	IdleTimeout time.Duration
+
+	// TLSCertFile and TLSKeyFile switch the server to HTTPS when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+
+	// ReminderInterval is how often due-date reminders are sent; 0 turns them off
+	ReminderInterval time.Duration
+}
+
+// TLS reports whether the server is configured for HTTPS
+func (c *ServerConfig) TLS() bool {
+	return c.TLSCertFile != ""
+}
+
+// GetServerConfig returns the HTTP server configuration from environment variables or defaults.
+// Like GetDatabaseConfig it fails on a setting that cannot be parsed, and also on a TLS
+// certificate without its key (or the reverse) or a key pair that cannot be loaded.
+func GetServerConfig() (*ServerConfig, error) {
+	config := &ServerConfig{
+		Port:              DefaultServerPort,
+		ReadTimeout:       DefaultServerReadTimeout,
+		ReadHeaderTimeout: DefaultServerReadHeaderTimeout,
+		WriteTimeout:      DefaultServerWriteTimeout,
+		IdleTimeout:       DefaultServerIdleTimeout,
+		TLSCertFile:       os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:        os.Getenv("TLS_KEY_FILE"),
+		ReminderInterval:  DefaultReminderInterval,
+	}
+
+	if value := os.Getenv("PORT"); value != "" {
+		port, err := strconv.Atoi(value)
+		if err != nil || port < 1 || port > 65535 {
+			return nil, fmt.Errorf("invalid PORT %q: must be a number from 1 to 65535", value)
+		}
+		config.Port = port
+	}
+
+	var err error
+	if config.ReadTimeout, err = timeoutFromEnv("SERVER_READ_TIMEOUT", config.ReadTimeout, true); err != nil {
+		return nil, err
+	}
+	if config.ReadHeaderTimeout, err = timeoutFromEnv("SERVER_READ_HEADER_TIMEOUT", config.ReadHeaderTimeout, false); err != nil {
+		return nil, err
+	}
+	if config.WriteTimeout, err = timeoutFromEnv("SERVER_WRITE_TIMEOUT", config.WriteTimeout, true); err != nil {
+		return nil, err
+	}
+	if config.IdleTimeout, err = timeoutFromEnv("SERVER_IDLE_TIMEOUT", config.IdleTimeout, true); err != nil {
+		return nil, err
+	}
+	if config.ReminderInterval, err = timeoutFromEnv("REMINDER_INTERVAL", config.ReminderInterval, true); err != nil {
+		return nil, err
+	}
+
+	switch {
+	case config.TLSCertFile != "" && config.TLSKeyFile == "":
+		return nil, fmt.Errorf("TLS_CERT_FILE is set but TLS_KEY_FILE is not: both are needed for HTTPS")
+	case config.TLSCertFile == "" && config.TLSKeyFile != "":
+		return nil, fmt.Errorf("TLS_KEY_FILE is set but TLS_CERT_FILE is not: both are needed for HTTPS")
+	case config.TLS():
+		if _, err := tls.LoadX509KeyPair(config.TLSCertFile, config.TLSKeyFile); err != nil {
+			return nil, fmt.Errorf("invalid TLS_CERT_FILE %q or TLS_KEY_FILE %q: %v", config.TLSCertFile, config.TLSKeyFile, err)
+		}
+	}
+
+	return config, nil
+}
+
+// NewServer creates the HTTP server for a configuration
+func NewServer(config *ServerConfig, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              fmt.Sprintf(":%d", config.Port),
+		Handler:           handler,
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+}
+
+// GetDatabaseConfig returns database configuration from environment variables or defaults.
+// It fails on a client setting that cannot be parsed, so a typo stops startup instead of
+// silently falling back to a default.
+func GetDatabaseConfig() (*routers.DatabaseConfig, error) {
 	uri := os.Getenv("MONGODB_URI")
 	if uri == "" {
 		uri = "mongodb://localhost:27017"
@@ -34,19 +158,114 @@ func GetDatabaseConfig() *routers.DatabaseConfig {
 		collection = "tasks"
 	}
 
-	return &routers.DatabaseConfig{
-		URI:        uri,
-		Database:   database,
-		Collection: collection,
+	config := &routers.DatabaseConfig{
+		URI:                    uri,
+		Database:               database,
+		Collection:             collection,
+		MaxPoolSize:            DefaultMongoMaxPoolSize,
+		MinPoolSize:            DefaultMongoMinPoolSize,
+		ConnectTimeout:         DefaultMongoConnectTimeout,
+		SocketTimeout:          DefaultMongoSocketTimeout,
+		ServerSelectionTimeout: DefaultMongoServerSelectionTimeout,
+		ReadPreference:         DefaultMongoReadPreference,
+	}
+
+	var err error
+	if config.MaxPoolSize, err = poolSizeFromEnv("MONGODB_MAX_POOL_SIZE", config.MaxPoolSize); err != nil {
+		return nil, err
+	}
+	if config.MinPoolSize, err = poolSizeFromEnv("MONGODB_MIN_POOL_SIZE", config.MinPoolSize); err != nil {
+		return nil, err
+	}
+	if config.MaxPoolSize > 0 && config.MinPoolSize > config.MaxPoolSize {
+		return nil, fmt.Errorf("invalid MONGODB_MIN_POOL_SIZE %d: must not exceed MONGODB_MAX_POOL_SIZE %d", config.MinPoolSize, config.MaxPoolSize)
+	}
+	if config.ConnectTimeout, err = timeoutFromEnv("MONGODB_CONNECT_TIMEOUT", config.ConnectTimeout, false); err != nil {
+		return nil, err
+	}
+	if config.SocketTimeout, err = timeoutFromEnv("MONGODB_SOCKET_TIMEOUT", config.SocketTimeout, true); err != nil {
+		return nil, err
+	}
+	if config.ServerSelectionTimeout, err = timeoutFromEnv("MONGODB_SERVER_SELECTION_TIMEOUT", config.ServerSelectionTimeout, false); err != nil {
+		return nil, err
+	}
+
+	if value := os.Getenv("USE_MEMORY_DB"); value != "" {
+		inMemory, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid USE_MEMORY_DB %q: must be true or false", value)
+		}
+		config.InMemory = inMemory
+	}
+
+	if value := os.Getenv("MONGODB_READ_PREFERENCE"); value != "" {
+		if _, err := readpref.ModeFromString(value); err != nil {
+			return nil, fmt.Errorf("invalid MONGODB_READ_PREFERENCE %q: must be one of primary, primaryPreferred, secondary, secondaryPreferred, nearest", value)
+		}
+		config.ReadPreference = value
+	}
+
+	config.Driver = routers.DriverMongo
+	if value := os.Getenv("DB_DRIVER"); value != "" {
+		if value != routers.DriverMongo && value != routers.DriverPostgres {
+			return nil, fmt.Errorf("invalid DB_DRIVER %q: must be mongo or postgres", value)
+		}
+		config.Driver = value
+	}
+	if config.Driver == routers.DriverPostgres {
+		config.PostgresDSN = os.Getenv("POSTGRES_DSN")
+		if config.PostgresDSN == "" {
+			return nil, errors.New("invalid POSTGRES_DSN: must be set when DB_DRIVER is postgres")
+		}
+	}
+
+	return config, nil
+}
+
+// poolSizeFromEnv reads a connection count, returning fallback when the variable is unset
+func poolSizeFromEnv(name string, fallback uint64) (uint64, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	size, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: must be a non-negative integer", name, value)
+	}
+	return size, nil
+}
+
+// timeoutFromEnv reads a Go duration such as "5s", returning fallback when the variable is unset.
+// Zero is accepted only when allowZero is set, where it means no timeout.
+func timeoutFromEnv(name string, fallback time.Duration, allowZero bool) (time.Duration, error) {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback, nil
+	}
+
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout < 0 || (timeout == 0 && !allowZero) {
+		if allowZero {
+			return 0, fmt.Errorf("invalid %s %q: must be a non-negative duration such as 30s", name, value)
+		}
+		return 0, fmt.Errorf("invalid %s %q: must be a positive duration such as 30s", name, value)
 	}
+	return timeout, nil
 }
 
-// ConnectToMongoDB establishes a connection to MongoDB
-func ConnectToMongoDB(config *routers.DatabaseConfig) (*mongo.Client, error) {
+// ConnectToMongoDB establishes a connection to MongoDB.
+// The monitor, if any, observes every command the client runs.
+func ConnectToMongoDB(config *routers.DatabaseConfig, logger *slog.Logger, monitor *event.CommandMonitor) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	clientOptions := options.Client().ApplyURI(config.URI)
+	clientOptions, err := mongoClientOptions(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
+	}
+	clientOptions.SetMonitor(monitor)
+
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to MongoDB: %v", err)
@@ -58,12 +277,47 @@ func ConnectToMongoDB(config *routers.DatabaseConfig) (*mongo.Client, error) {
 		return nil, fmt.Errorf("failed to ping MongoDB: %v", err)
 	}
 
-	log.Printf("Successfully connected to MongoDB at %s", config.URI)
+	logger.Info("connected to MongoDB", "uri", config.URI, "max_pool_size", config.MaxPoolSize, "read_preference", config.ReadPreference)
 	return client, nil
 }
 
+// mongoClientOptions builds the client options for a configuration. Settings left at zero are
+// not applied, and options given in the URI itself take precedence over the configuration.
+func mongoClientOptions(config *routers.DatabaseConfig) (*options.ClientOptions, error) {
+	clientOptions := options.Client()
+
+	if config.MaxPoolSize > 0 {
+		clientOptions.SetMaxPoolSize(config.MaxPoolSize)
+	}
+	if config.MinPoolSize > 0 {
+		clientOptions.SetMinPoolSize(config.MinPoolSize)
+	}
+	if config.ConnectTimeout > 0 {
+		clientOptions.SetConnectTimeout(config.ConnectTimeout)
+	}
+	if config.SocketTimeout > 0 {
+		clientOptions.SetSocketTimeout(config.SocketTimeout)
+	}
+	if config.ServerSelectionTimeout > 0 {
+		clientOptions.SetServerSelectionTimeout(config.ServerSelectionTimeout)
+	}
+	if config.ReadPreference != "" {
+		mode, err := readpref.ModeFromString(config.ReadPreference)
+		if err != nil {
+			return nil, err
+		}
+		readPreference, err := readpref.New(mode)
+		if err != nil {
+			return nil, err
+		}
+		clientOptions.SetReadPreference(readPreference)
+	}
+
+	return clientOptions.ApplyURI(config.URI), nil
+}
+
 // DisconnectFromMongoDB closes the MongoDB connection
-func DisconnectFromMongoDB(client *mongo.Client) error {
+func DisconnectFromMongoDB(client *mongo.Client, logger *slog.Logger) error {
 	if client == nil {
 		return nil // Nothing to disconnect
 	}
@@ -76,48 +330,167 @@ func DisconnectFromMongoDB(client *mongo.Client) error {
 		return fmt.Errorf("failed to disconnect from MongoDB: %v", err)
 	}
 
-	log.Println("Disconnected from MongoDB")
+	logger.Info("disconnected from MongoDB")
 	return nil
 }
 
 func main() {
-	// Load environment variables from .env file
+	command, args := splitCommand(os.Args[1:])
+	if command == "help" {
+		printUsage(os.Stdout)
+		return
+	}
+
+	// Admin commands print their results to stdout, so their logs go to stderr
+	logOutput := io.Writer(os.Stdout)
+	if command != serveCommand {
+		logOutput = os.Stderr
+	}
+	logger := loadEnvironment(logOutput)
+
+	if command == serveCommand {
+		serve(args, logger)
+		return
+	}
+	os.Exit(runAdminCommand(context.Background(), command, args, func() (*routers.Services, func(), error) {
+		return openServices(logger)
+	}, os.Stdout, os.Stderr))
+}
+
+// loadEnvironment loads the .env file, if any, and creates the logger, which reads LOG_LEVEL and LOG_FORMAT
+func loadEnvironment(logOutput io.Writer) *slog.Logger {
+	envSource := "current directory"
 	if err := godotenv.Load(".env"); err != nil {
 		// Try loading from parent directory as fallback
+		envSource = "parent directory"
 		if err := godotenv.Load("../.env"); err != nil {
-			log.Println("No .env file found, using system environment variables")
-		} else {
-			log.Println("Loaded .env from parent directory")
+			envSource = ""
 		}
+	}
+
+	logger := Infrastructure.NewLoggerTo(logOutput)
+	if envSource == "" {
+		logger.Info("no .env file found, using system environment variables")
 	} else {
-		log.Println("Loaded .env from current directory")
+		logger.Info("loaded .env", "from", envSource)
+	}
+	return logger
+}
+
+// openServices connects to the configured MongoDB for an admin command. In-memory storage is
+// refused, since a command would only see an empty database of its own.
+func openServices(logger *slog.Logger) (*routers.Services, func(), error) {
+	dbConfig, err := GetDatabaseConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+	if dbConfig.InMemory {
+		return nil, nil, errors.New("admin commands need MongoDB, but USE_MEMORY_DB is set")
+	}
+
+	client, err := ConnectToMongoDB(dbConfig, logger, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	disconnect := func() {
+		if err := DisconnectFromMongoDB(client, logger); err != nil {
+			logger.Error("failed to disconnect from MongoDB", "error", err)
+		}
+	}
+
+	// Commands serve no /metrics, so there is nothing to count business metrics for
+	services, err := routers.NewServices(client, dbConfig, Infrastructure.NewNopBusinessMetrics(), logger)
+	if err != nil {
+		disconnect()
+		return nil, nil, err
+	}
+
+	// A command such as promote may have queued webhook deliveries, which need the database to record them
+	closeServices := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := services.Close(ctx); err != nil {
+			logger.Error("webhook deliveries did not finish", "error", err)
+		}
+		disconnect()
+	}
+	return services, closeServices, nil
+}
+
+// serve runs the HTTP API until it receives SIGINT or SIGTERM
+func serve(args []string, logger *slog.Logger) {
+	flags := flag.NewFlagSet(serveCommand, flag.ExitOnError)
+	useMemory := flags.Bool("memory", false, "keep data in memory instead of MongoDB, for demos (same as USE_MEMORY_DB=true)")
+	flags.Parse(args)
+
+	// Get server and database configuration
+	serverConfig, err := GetServerConfig()
+	if err != nil {
+		logger.Error("invalid server configuration", "error", err)
+		os.Exit(1)
 	}
 
-	// Get database configuration
-	dbConfig := GetDatabaseConfig()
-	log.Printf("Using MongoDB URI: %s", dbConfig.URI)
-	log.Printf("Using Database: %s", dbConfig.Database)
+	dbConfig, err := GetDatabaseConfig()
+	if err != nil {
+		logger.Error("invalid MongoDB configuration", "error", err)
+		os.Exit(1)
+	}
+	if *useMemory {
+		dbConfig.InMemory = true
+	}
 
-	// Connect to MongoDB
-	client, err := ConnectToMongoDB(dbConfig)
+	// Connect to MongoDB, counting failed commands in the metrics. In memory mode there is nothing to connect to.
+	metrics := Infrastructure.NewMetrics()
+	var client *mongo.Client
+	if dbConfig.InMemory {
+		logger.Info("using in-memory storage instead of MongoDB")
+	} else {
+		logger.Info("using MongoDB", "uri", dbConfig.URI, "database", dbConfig.Database)
+		client, err = ConnectToMongoDB(dbConfig, logger, metrics.MongoMonitor())
+		if err != nil {
+			logger.Error("failed to connect to MongoDB", "error", err)
+			os.Exit(1)
+		}
+	}
+
+	// Initialize the services and the router with Clean Architecture
+	services, err := routers.NewServices(client, dbConfig, metrics, logger)
 	if err != nil {
-		log.Fatal("Failed to connect to MongoDB:", err)
+		logger.Error("failed to initialize services", "error", err)
+		os.Exit(1)
 	}
+	r := routers.NewRouter(services, logger, metrics)
 
-	// Initialize the router with Clean Architecture
-	r := routers.SetupRouter(client, dbConfig)
+	// Create HTTP server; event streams never go idle, so they are ended when it shuts down
+	srv := NewServer(serverConfig, r)
+	srv.RegisterOnShutdown(services.EndStreams)
 
-	// Create HTTP server
-	srv := &http.Server{
-		Addr:    ":8080",
-		Handler: r,
+	// Start the background components under the lifecycle's root context; due-date reminders run
+	// until shutdown unless they are turned off
+	if serverConfig.ReminderInterval == 0 {
+		logger.Info("due-date reminders are turned off")
+	}
+	lifecycle := NewLifecycle(logger)
+	lifecycle.Register(services.Components(serverConfig.ReminderInterval)...)
+	if err := lifecycle.Start(); err != nil {
+		logger.Error("failed to start background components", "error", err)
+		os.Exit(1)
 	}
 
-	// Start server in a goroutine
+	// Start server in a goroutine, over HTTPS when a certificate is configured
 	go func() {
-		log.Println("Starting Task Management API server on :8080")
-		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			log.Fatal("Failed to start server:", err)
+		build := buildinfo.Get()
+		logger.Info("starting Task Management API server", "addr", srv.Addr, "tls", serverConfig.TLS(),
+			"version", build.Version, "commit", build.Commit, "build_time", build.BuildTime)
+		var err error
+		if serverConfig.TLS() {
+			err = srv.ListenAndServeTLS(serverConfig.TLSCertFile, serverConfig.TLSKeyFile)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error("failed to start server", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -125,21 +498,26 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	logger.Info("shutting down server")
 
 	// Graceful shutdown with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
-	// Shutdown HTTP server
+	// Stop accepting requests first, so the components are handed no new work while they drain
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatal("Server forced to shutdown:", err)
+		logger.Error("server forced to shutdown", "error", err)
+	}
+
+	// Drain the components within what is left of the timeout, naming any that blocks
+	if err := lifecycle.Stop(ctx); err != nil {
+		logger.Error("background components did not stop cleanly", "error", err)
 	}
 
 	// Disconnect from MongoDB
-	if err := DisconnectFromMongoDB(client); err != nil {
-		log.Printf("Error disconnecting from MongoDB: %v", err)
+	if err := DisconnectFromMongoDB(client, logger); err != nil {
+		logger.Error("failed to disconnect from MongoDB", "error", err)
 	}
 
-	log.Println("Server exited")
-}
\ No newline at end of file
+	logger.Info("server exited")
+}
@@ -0,0 +1,100 @@
+package routers
+
+import (
+	"context"
+	"time"
+)
+
+// Component is a part of the server that works in the background. Start launches its work, which
+// runs until ctx is done or Stop is called; Stop waits for work in flight to drain, giving up when
+// ctx is done.
+type Component interface {
+	Name() string
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
+
+// Components lists the background components of services in the order they are started. They are
+// stopped in reverse, so the reminders stop producing work before the queues that carry it are drained.
+// A zero reminderInterval leaves the reminders out.
+func (s *Services) Components(reminderInterval time.Duration) []Component {
+	components := []Component{
+		&funcComponent{name: "webhook_dispatcher", stop: s.webhooks.Close},
+		&funcComponent{name: "request_audit", stop: s.requestAudit.Close},
+		&funcComponent{name: "task_event_streams", stop: func(ctx context.Context) error {
+			s.EndStreams()
+			return nil
+		}},
+	}
+	// Registered first so that it stops last, once nothing else writes to PostgreSQL
+	if s.postgres != nil {
+		components = append([]Component{&funcComponent{name: "postgres", stop: s.closePostgres}}, components...)
+	}
+	if reminderInterval > 0 {
+		components = append(components, &loopComponent{name: "task_reminders", run: func(ctx context.Context) {
+			s.RunReminders(ctx, reminderInterval)
+		}})
+	}
+	return components
+}
+
+// funcComponent is a component whose work was started when it was built, so only stopping it does anything
+type funcComponent struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// Name implements Component
+func (fc *funcComponent) Name() string {
+	return fc.name
+}
+
+// Start implements Component; the work is already running
+func (fc *funcComponent) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop implements Component
+func (fc *funcComponent) Stop(ctx context.Context) error {
+	return fc.stop(ctx)
+}
+
+// loopComponent runs a function in its own goroutine until it is stopped
+type loopComponent struct {
+	name   string
+	run    func(ctx context.Context)
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Name implements Component
+func (lc *loopComponent) Name() string {
+	return lc.name
+}
+
+// Start implements Component
+func (lc *loopComponent) Start(ctx context.Context) error {
+	ctx, lc.cancel = context.WithCancel(ctx)
+	lc.done = make(chan struct{})
+	go func() {
+		defer close(lc.done)
+		lc.run(ctx)
+	}()
+	return nil
+}
+
+// Stop implements Component. The run in progress is cancelled and, like a reminder run giving up its lock,
+// gets until ctx is done to return.
+func (lc *loopComponent) Stop(ctx context.Context) error {
+	if lc.cancel == nil {
+		return nil
+	}
+	lc.cancel()
+
+	select {
+	case <-lc.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -1,80 +1,409 @@
 package routers
 
 import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"go.mongodb.org/mongo-driver/mongo"
-	
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+
 	"task_manager/Delivery/controllers"
+	"task_manager/Domain"
 	"task_manager/Infrastructure"
+	"task_manager/Infrastructure/buildinfo"
 	"task_manager/Repositories"
-	"task_manager/Usecases"
+	"task_manager/Repositories/memory"
+	"task_manager/Repositories/postgres"
+)
+
+// startedAt approximates the process start time for reporting uptime
+var startedAt = time.Now()
+
+// Routes of task attachments, which lift the request body limit and the request timeout for file transfers
+const (
+	attachmentsPath = "/api/v1/tasks/:id/attachments"
+	attachmentPath  = "/api/v1/tasks/:id/attachments/:attachmentId"
 )
 
-// DatabaseConfig holds database configuration
+// DatabaseConfig holds database configuration. Zero client settings leave the driver's defaults.
 type DatabaseConfig struct {
 	URI        string
 	Database   string
 	Collection string
+
+	// MaxPoolSize caps the connections to each server; 0 means no limit
+	MaxPoolSize uint64
+	// MinPoolSize is the number of connections to each server kept open while idle
+	MinPoolSize uint64
+	// ConnectTimeout bounds opening a connection
+	ConnectTimeout time.Duration
+	// SocketTimeout bounds each read or write on a connection; 0 means none
+	SocketTimeout time.Duration
+	// ServerSelectionTimeout bounds finding a server able to run an operation
+	ServerSelectionTimeout time.Duration
+	// ReadPreference is a read preference mode such as primary or secondaryPreferred
+	ReadPreference string
+
+	// InMemory keeps all data in process memory instead of MongoDB, for demos. The client is not used.
+	InMemory bool
+
+	// Driver selects where tasks, users and the reports over them are kept: DriverMongo, the
+	// default when empty, or DriverPostgres. Every other collection stays in MongoDB either way.
+	Driver string
+	// PostgresDSN is the connection string of the PostgreSQL database used with DriverPostgres
+	PostgresDSN string
 }
 
-// SetupRouter initializes and configures the Gin router with Clean Architecture
-func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig) *gin.Engine {
-	router := gin.Default()
+// Database drivers for tasks and users
+const (
+	DriverMongo    = "mongo"
+	DriverPostgres = "postgres"
+)
+
+// repositories holds the repositories the usecases are built from
+type repositories struct {
+	tasks             Repositories.TaskRepositoryInterface
+	users             Repositories.UserRepositoryInterface
+	comments          Repositories.CommentRepositoryInterface
+	attachments       Repositories.AttachmentRepositoryInterface
+	workLogs          Repositories.WorkLogRepositoryInterface
+	audit             Repositories.AuditRepositoryInterface
+	revisions         Repositories.RevisionRepositoryInterface
+	refreshTokens     Repositories.RefreshTokenRepositoryInterface
+	passwordResets    Repositories.PasswordResetRepositoryInterface
+	loginEvents       Repositories.LoginEventRepositoryInterface
+	tokenBlacklist    Repositories.TokenBlacklistRepositoryInterface
+	apiKeys           Repositories.APIKeyRepositoryInterface
+	webhooks          Repositories.WebhookRepositoryInterface
+	webhookDeliveries Repositories.WebhookDeliveryRepositoryInterface
+	locks             Repositories.LockRepositoryInterface
+	reports           Repositories.ReportRepositoryInterface
+}
+
+// newMongoRepositories creates the repositories backed by MongoDB
+func newMongoRepositories(client *mongo.Client, dbConfig *DatabaseConfig, retrier *Infrastructure.Retrier, txManager Infrastructure.TransactionManager) *repositories {
+	return &repositories{
+		tasks:             Repositories.NewTaskRepository(client, dbConfig.Database, dbConfig.Collection, retrier, txManager),
+		users:             Repositories.NewUserRepository(client, dbConfig.Database, retrier),
+		comments:          Repositories.NewCommentRepository(client, dbConfig.Database),
+		attachments:       Repositories.NewAttachmentRepository(client, dbConfig.Database),
+		workLogs:          Repositories.NewWorkLogRepository(client, dbConfig.Database),
+		audit:             Repositories.NewAuditRepository(client, dbConfig.Database),
+		revisions:         Repositories.NewRevisionRepository(client, dbConfig.Database),
+		refreshTokens:     Repositories.NewRefreshTokenRepository(client, dbConfig.Database),
+		passwordResets:    Repositories.NewPasswordResetRepository(client, dbConfig.Database),
+		loginEvents:       Repositories.NewLoginEventRepository(client, dbConfig.Database),
+		tokenBlacklist:    Repositories.NewTokenBlacklistRepository(client, dbConfig.Database),
+		apiKeys:           Repositories.NewAPIKeyRepository(client, dbConfig.Database),
+		webhooks:          Repositories.NewWebhookRepository(client, dbConfig.Database),
+		webhookDeliveries: Repositories.NewWebhookDeliveryRepository(client, dbConfig.Database),
+		locks:             Repositories.NewLockRepository(client, dbConfig.Database),
+		reports:           Repositories.NewReportRepository(client, dbConfig.Database, dbConfig.Collection, retrier),
+	}
+}
+
+// newMemoryRepositories creates the repositories that keep data in process memory
+func newMemoryRepositories() *repositories {
+	tasks, users := memory.NewTaskRepository(), memory.NewUserRepository()
+	return &repositories{
+		tasks:             tasks,
+		users:             users,
+		comments:          memory.NewCommentRepository(),
+		attachments:       memory.NewAttachmentRepository(),
+		workLogs:          memory.NewWorkLogRepository(),
+		audit:             memory.NewAuditRepository(),
+		revisions:         memory.NewRevisionRepository(),
+		refreshTokens:     memory.NewRefreshTokenRepository(),
+		passwordResets:    memory.NewPasswordResetRepository(),
+		loginEvents:       memory.NewLoginEventRepository(),
+		tokenBlacklist:    memory.NewTokenBlacklistRepository(),
+		apiKeys:           memory.NewAPIKeyRepository(),
+		webhooks:          memory.NewWebhookRepository(),
+		webhookDeliveries: memory.NewWebhookDeliveryRepository(),
+		locks:             memory.NewLockRepository(),
+		reports:           memory.NewReportRepository(tasks, users),
+	}
+}
+
+// usePostgres moves tasks, users and the reports over them to the PostgreSQL database db
+func (r *repositories) usePostgres(db *sql.DB) {
+	r.tasks = postgres.NewTaskRepository(db)
+	r.users = postgres.NewUserRepository(db)
+	r.reports = postgres.NewReportRepository(db)
+}
+
+// splitDatabase is the readiness check when tasks and users are kept in PostgreSQL and everything
+// else in MongoDB: both must be reachable
+type splitDatabase struct {
+	mongo    controllers.DatabasePinger
+	postgres *sql.DB
+}
+
+// Ping implements controllers.DatabasePinger
+func (d splitDatabase) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	if err := d.mongo.Ping(ctx, rp); err != nil {
+		return err
+	}
+	return d.postgres.PingContext(ctx)
+}
+
+// memoryDatabase stands in for MongoDB in the readiness check when data is kept in memory
+type memoryDatabase struct{}
+
+// Ping implements controllers.DatabasePinger; memory is always reachable
+func (memoryDatabase) Ping(ctx context.Context, rp *readpref.ReadPref) error {
+	return nil
+}
+
+// basePathFromEnv returns BASE_PATH, the path prefix a reverse proxy serves the API under, as a
+// path with a leading slash and no trailing one. It is empty when the API is served at the root.
+func basePathFromEnv() string {
+	basePath := strings.Trim(strings.TrimSpace(os.Getenv("BASE_PATH")), "/")
+	if basePath == "" {
+		return ""
+	}
+	return "/" + basePath
+}
+
+// SetupRouter initializes and configures the Gin router with Clean Architecture.
+// metrics must be the instance whose MongoMonitor was given to the client, so database errors are counted.
+// With dbConfig.InMemory set, client may be nil.
+func SetupRouter(client *mongo.Client, dbConfig *DatabaseConfig, logger *slog.Logger, metrics *Infrastructure.Metrics) *gin.Engine {
+	services, err := NewServices(client, dbConfig, metrics, logger)
+	if err != nil {
+		panic(err)
+	}
+
+	return NewRouter(services, logger, metrics)
+}
+
+// NewRouter configures the Gin router on services built by NewServices. The caller keeps services
+// so it can close them once the server has shut down.
+func NewRouter(services *Services, logger *slog.Logger, metrics *Infrastructure.Metrics) *gin.Engine {
+	// With ADMIN_USERNAME and ADMIN_PASSWORD set, a fresh database gets its admin here rather than from the first registration
+	if err := seedAdmin(context.Background(), services.Users, logger); err != nil {
+		panic(err)
+	}
+
+	if services.taskCache != nil {
+		metrics.ObserveCache("tasks", services.taskCache)
+	}
 
 	// Initialize Infrastructure layer
-	passwordService := Infrastructure.NewPasswordService()
-	jwtService := Infrastructure.NewJWTService()
-	authMiddleware := Infrastructure.NewAuthMiddleware(jwtService)
+	timeoutMiddleware := Infrastructure.NewTimeoutMiddleware(logger)
+	requestIDMiddleware := Infrastructure.NewRequestIDMiddleware()
+	localeMiddleware := Infrastructure.NewLocaleMiddleware()
+	clientIPs := Infrastructure.NewClientIPResolver()
+	requestLoggerMiddleware := Infrastructure.NewRequestLoggerMiddleware(logger, clientIPs)
+	rateLimitMiddleware := Infrastructure.NewRateLimitMiddleware(Infrastructure.NewMemoryRateLimitStore(), clientIPs)
+	bodyLimitMiddleware := Infrastructure.NewBodyLimitMiddleware()
+	compressionMiddleware := Infrastructure.NewCompressionMiddleware()
+	authRateLimit := Infrastructure.RateLimitFromEnv("RATE_LIMIT_AUTH", Infrastructure.DefaultAuthRateLimit)
+	apiRateLimit := Infrastructure.RateLimitFromEnv("RATE_LIMIT_API", Infrastructure.DefaultAPIRateLimit)
+
+	// Only TRUSTED_PROXIES may name the client in X-Forwarded-For or X-Real-IP, for the rate limiter,
+	// the request log and the login history alike
+	router := gin.New()
+	if err := clientIPs.Configure(router); err != nil {
+		panic(err)
+	}
+
+	// The request ID is assigned first so the request log and every handler can see it, and the
+	// locale next so every message, including the middleware's own errors, is in the client's language.
+	// Every response names the build that served it. Oversized request bodies are rejected with a 413
+	// and responses are gzipped for clients that accept it.
+	// Attachment uploads are allowed bodies as large as the largest attachment, plus room for the multipart headers.
+	router.Use(requestIDMiddleware.AssignRequestID(), buildinfo.SetVersionHeader(), localeMiddleware.NegotiateLocale(), requestLoggerMiddleware.LogRequest(), metrics.Instrument(), gin.Recovery(),
+		bodyLimitMiddleware.Limit(attachmentsPath), compressionMiddleware.Compress())
+	uploadLimit := bodyLimitMiddleware.LimitTo(services.attachmentLimits.MaxBytes + Infrastructure.DefaultMaxRequestBodyBytes)
+
+	// Either a Bearer token or an X-API-Key header authenticates a request
+	authMiddleware := Infrastructure.NewAuthMiddleware(services.jwtService, services.repos.tokenBlacklist, Infrastructure.NewCachedUserStatusChecker(services.repos.users), services.APIKeys)
 
-	// Initialize Repository layer
-	taskRepo := Repositories.NewTaskRepository(client, dbConfig.Database, dbConfig.Collection)
-	userRepo := Repositories.NewUserRepository(client, dbConfig.Database)
+	// Initialize Controller layer. Behind a reverse proxy that serves the API under BASE_PATH, the
+	// Location of created resources includes that prefix so clients can follow it.
+	controller := controllers.NewController(services.Tasks, services.Users, services.Comments, services.Attachments, services.WorkLogs, services.Audit, services.PasswordResets, services.APIKeys, services.Webhooks, services.Reports, basePathFromEnv()+"/api/v1", logger)
 
-	// Initialize Usecase layer
-	taskUsecase := Usecases.NewTaskUsecase(taskRepo)
-	userUsecase := Usecases.NewUserUsecase(userRepo, passwordService, jwtService)
+	// Routes are guarded by the permission they need; Domain maps each role to its permissions
+	readTasks := authMiddleware.RequirePermission(Domain.PermissionTasksRead)
+	writeTasks := authMiddleware.RequirePermission(Domain.PermissionTasksWrite)
+	manageUsers := authMiddleware.RequirePermission(Domain.PermissionUsersManage)
+	readAudit := authMiddleware.RequirePermission(Domain.PermissionAuditRead)
 
-	// Initialize Controller layer
-	controller := controllers.NewController(taskUsecase, userUsecase)
+	// Requests are traced before authentication so rejected attempts are recorded too
+	requestAudit := services.requestAudit
 
-	// API versioning group
+	// Task changes are pushed to dashboards as server-sent events
+	eventStreamController := controllers.NewEventStreamController(services.taskEvents, logger)
+
+	// API versioning group. The CSV exports, the event stream and attachment uploads and downloads run for as long
+	// as they take, so they have no request timeout.
 	v1 := router.Group("/api/v1")
+	v1.Use(rateLimitMiddleware.Limit("api", apiRateLimit), timeoutMiddleware.Timeout("/api/v1/tasks/export", "/api/v1/users/export", "/api/v1/tasks/events", attachmentsPath, attachmentPath))
 	{
-		// Public authentication routes (no middleware required)
-		v1.POST("/register", controller.Register) // POST /api/v1/register
-		v1.POST("/login", controller.Login)       // POST /api/v1/login
+		// Public authentication routes (no authentication, but a much tighter rate limit)
+		v1.POST("/register", rateLimitMiddleware.Limit("auth", authRateLimit), controller.Register) // POST /api/v1/register
+		v1.POST("/login", rateLimitMiddleware.Limit("auth", authRateLimit), controller.Login)       // POST /api/v1/login
 
-		// Protected user routes (authentication required)
+		// Token refresh is authenticated by the refresh token in the body; logout needs the access token it revokes.
+		// Password recovery is authenticated by the reset token sent to the user.
+		authRoutes := v1.Group("/auth")
+		authRoutes.Use(rateLimitMiddleware.Limit("auth", authRateLimit))
+		{
+			authRoutes.POST("/refresh", controller.RefreshToken)                              // POST /api/v1/auth/refresh
+			authRoutes.POST("/logout", authMiddleware.AuthenticateToken(), controller.Logout) // POST /api/v1/auth/logout
+			authRoutes.POST("/forgot-password", controller.ForgotPassword)                    // POST /api/v1/auth/forgot-password
+			authRoutes.POST("/reset-password", controller.ResetPassword)                      // POST /api/v1/auth/reset-password
+			authRoutes.POST("/verify-email", controller.VerifyEmail)                          // POST /api/v1/auth/verify-email
+		}
+
+		// Protected user routes (authentication required), traced in the audit log with AUDIT_REQUESTS
 		userRoutes := v1.Group("/users")
-		userRoutes.Use(authMiddleware.AuthenticateToken())
+		userRoutes.Use(requestAudit.Record(), authMiddleware.AuthenticateToken())
 		{
-			userRoutes.GET("/profile", controller.GetProfile)                                          // GET /api/v1/users/profile
-			userRoutes.GET("", authMiddleware.RequireAdmin(), controller.GetAllUsers)                  // GET /api/v1/users (admin only)
-			userRoutes.POST("/promote", authMiddleware.RequireAdmin(), controller.PromoteUser)         // POST /api/v1/users/promote (admin only)
+			userRoutes.GET("/profile", controller.GetProfile)                          // GET /api/v1/users/profile
+			userRoutes.PUT("/profile", controller.UpdateProfile)                       // PUT /api/v1/users/profile
+			userRoutes.PUT("/password", controller.ChangePassword)                     // PUT /api/v1/users/password
+			userRoutes.GET("/me/logins", controller.GetLoginHistory)                   // GET /api/v1/users/me/logins
+			userRoutes.GET("/directory", controller.GetUserDirectory)                  // GET /api/v1/users/directory
+			userRoutes.GET("", manageUsers, controller.GetAllUsers)                    // GET /api/v1/users
+			userRoutes.GET("/export", manageUsers, controller.ExportUsers)             // GET /api/v1/users/export
+			userRoutes.POST("/promote", manageUsers, controller.PromoteUser)           // POST /api/v1/users/promote
+			userRoutes.POST("/demote", manageUsers, controller.DemoteUser)             // POST /api/v1/users/demote
+			userRoutes.DELETE("/:id", manageUsers, controller.DeleteUser)              // DELETE /api/v1/users/:id
+			userRoutes.POST("/:id/deactivate", manageUsers, controller.DeactivateUser) // POST /api/v1/users/:id/deactivate
+			userRoutes.POST("/:id/activate", manageUsers, controller.ActivateUser)     // POST /api/v1/users/:id/activate
 		}
 
 		// Protected task routes
 		tasks := v1.Group("/tasks")
-		tasks.Use(authMiddleware.AuthenticateToken()) // All task routes require authentication
+		tasks.Use(requestAudit.RecordWrites(), authMiddleware.AuthenticateToken()) // All task routes require authentication; changes are traced with AUDIT_REQUESTS
 		{
-			// Read operations - accessible by all authenticated users (admin and regular users)
-			tasks.GET("", authMiddleware.RequireUser(), controller.GetAllTasks)       // GET /api/v1/tasks
-			tasks.GET("/:id", authMiddleware.RequireUser(), controller.GetTaskByID)   // GET /api/v1/tasks/:id
-			
-			// Write operations - accessible only by admins
-			tasks.POST("", authMiddleware.RequireAdmin(), controller.CreateTask)       // POST /api/v1/tasks (admin only)
-			tasks.PUT("/:id", authMiddleware.RequireAdmin(), controller.UpdateTask)    // PUT /api/v1/tasks/:id (admin only)
-			tasks.DELETE("/:id", authMiddleware.RequireAdmin(), controller.DeleteTask) // DELETE /api/v1/tasks/:id (admin only)
+			// Read operations - every role; regular users only see tasks they created or are assigned to
+			tasks.GET("", readTasks, controller.GetAllTasks)                        // GET /api/v1/tasks
+			tasks.GET("/:id", readTasks, controller.GetTaskByID)                    // GET /api/v1/tasks/:id
+			tasks.GET("/assigned-to-me", readTasks, controller.GetAssignedTasks)    // GET /api/v1/tasks/assigned-to-me
+			tasks.GET("/tags", readTasks, controller.GetTags)                       // GET /api/v1/tasks/tags
+			tasks.GET("/count", readTasks, controller.CountTasks)                   // GET /api/v1/tasks/count
+			tasks.GET("/search", readTasks, controller.SearchTasks)                 // GET /api/v1/tasks/search?q=
+			tasks.GET("/export", readTasks, controller.ExportTasks)                 // GET /api/v1/tasks/export
+			tasks.GET("/stats", readTasks, controller.GetStats)                     // GET /api/v1/tasks/stats
+			tasks.GET("/overdue", readTasks, controller.GetOverdueTasks)            // GET /api/v1/tasks/overdue
+			tasks.GET("/events", readTasks, eventStreamController.StreamTaskEvents) // GET /api/v1/tasks/events
+
+			// Comments - any user who can see the task
+			tasks.POST("/:id/comments", readTasks, controller.AddComment) // POST /api/v1/tasks/:id/comments
+			tasks.GET("/:id/comments", readTasks, controller.GetComments) // GET /api/v1/tasks/:id/comments
+
+			// Time tracking - any user who can see the task logs their own work on it
+			tasks.POST("/:id/worklogs", readTasks, controller.LogWork)    // POST /api/v1/tasks/:id/worklogs
+			tasks.GET("/:id/worklogs", readTasks, controller.GetWorkLogs) // GET /api/v1/tasks/:id/worklogs
+
+			// Attachments - any user who can see the task; the uploader or a manager deletes them
+			tasks.POST("/:id/attachments", readTasks, uploadLimit, controller.UploadAttachment)    // POST /api/v1/tasks/:id/attachments
+			tasks.GET("/:id/attachments", readTasks, controller.GetAttachments)                    // GET /api/v1/tasks/:id/attachments
+			tasks.GET("/:id/attachments/:attachmentId", readTasks, controller.DownloadAttachment)  // GET /api/v1/tasks/:id/attachments/:attachmentId
+			tasks.DELETE("/:id/attachments/:attachmentId", readTasks, controller.DeleteAttachment) // DELETE /api/v1/tasks/:id/attachments/:attachmentId
+
+			// Revision history - any user who can see the task
+			tasks.GET("/:id/revisions", readTasks, controller.GetTaskRevisions) // GET /api/v1/tasks/:id/revisions
+
+			// Status changes - the assignee, or anyone who can write tasks
+			tasks.PATCH("/:id/status", readTasks, controller.UpdateTaskStatus) // PATCH /api/v1/tasks/:id/status
+
+			// Write operations - managers and admins
+			tasks.POST("", writeTasks, controller.CreateTask)                    // POST /api/v1/tasks
+			tasks.POST("/bulk", writeTasks, controller.CreateTasks)              // POST /api/v1/tasks/bulk
+			tasks.POST("/import", writeTasks, controller.ImportTasks)            // POST /api/v1/tasks/import
+			tasks.POST("/bulk-status", writeTasks, controller.UpdateTasksStatus) // POST /api/v1/tasks/bulk-status
+			tasks.DELETE("", writeTasks, controller.DeleteTasksByStatus)         // DELETE /api/v1/tasks?status=
+			tasks.PUT("/:id", writeTasks, controller.UpdateTask)                 // PUT /api/v1/tasks/:id
+			tasks.PATCH("/:id", writeTasks, controller.PatchTask)                // PATCH /api/v1/tasks/:id
+			tasks.DELETE("/:id", writeTasks, controller.DeleteTask)              // DELETE /api/v1/tasks/:id
+
+			// Trash management - soft-deleted tasks, managers and admins
+			tasks.GET("/trash", writeTasks, controller.GetDeletedTasks)      // GET /api/v1/tasks/trash
+			tasks.DELETE("/trash", writeTasks, controller.PurgeDeletedTasks) // DELETE /api/v1/tasks/trash
+			tasks.POST("/:id/restore", writeTasks, controller.RestoreTask)   // POST /api/v1/tasks/:id/restore
+
+			// Archiving - hides tasks from listings without deleting them, managers and admins
+			tasks.POST("/archive", writeTasks, controller.ArchiveCompletedTasks) // POST /api/v1/tasks/archive?completed_before=
+			tasks.POST("/:id/archive", writeTasks, controller.ArchiveTask)       // POST /api/v1/tasks/:id/archive
+			tasks.POST("/:id/unarchive", writeTasks, controller.UnarchiveTask)   // POST /api/v1/tasks/:id/unarchive
+		}
+
+		// API keys for machine clients - admins
+		apiKeyRoutes := v1.Group("/api-keys")
+		apiKeyRoutes.Use(authMiddleware.AuthenticateToken(), manageUsers)
+		{
+			apiKeyRoutes.POST("", controller.CreateAPIKey)       // POST /api/v1/api-keys
+			apiKeyRoutes.GET("", controller.GetAPIKeys)          // GET /api/v1/api-keys
+			apiKeyRoutes.DELETE("/:id", controller.RevokeAPIKey) // DELETE /api/v1/api-keys/:id
+		}
+
+		// Outbound webhooks on task and user events - admins
+		webhookRoutes := v1.Group("/webhooks")
+		webhookRoutes.Use(authMiddleware.AuthenticateToken(), manageUsers)
+		{
+			webhookRoutes.POST("", controller.CreateWebhook)                      // POST /api/v1/webhooks
+			webhookRoutes.GET("", controller.GetWebhooks)                         // GET /api/v1/webhooks
+			webhookRoutes.GET("/:id", controller.GetWebhook)                      // GET /api/v1/webhooks/:id
+			webhookRoutes.PUT("/:id", controller.UpdateWebhook)                   // PUT /api/v1/webhooks/:id
+			webhookRoutes.DELETE("/:id", controller.DeleteWebhook)                // DELETE /api/v1/webhooks/:id
+			webhookRoutes.GET("/:id/deliveries", controller.GetWebhookDeliveries) // GET /api/v1/webhooks/:id/deliveries
+		}
+
+		// Logged time per user - regular users see their own, managers and admins everyone's
+		workLogRoutes := v1.Group("/worklogs")
+		workLogRoutes.Use(authMiddleware.AuthenticateToken(), readTasks)
+		{
+			workLogRoutes.GET("/summary", controller.GetWorkLogSummary) // GET /api/v1/worklogs/summary
+		}
+
+		// Protected audit routes - admins
+		auditRoutes := v1.Group("/audit")
+		auditRoutes.Use(authMiddleware.AuthenticateToken())
+		{
+			auditRoutes.GET("", readAudit, controller.GetAuditLog) // GET /api/v1/audit
+		}
+
+		// Protected report routes - admins
+		reportRoutes := v1.Group("/reports")
+		reportRoutes.Use(authMiddleware.AuthenticateToken(), manageUsers)
+		{
+			reportRoutes.GET("/tasks-by-user", controller.GetTasksByUserReport) // GET /api/v1/reports/tasks-by-user
 		}
 	}
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":  "OK",
-			"message": "Task Management API is running",
-		})
-	})
+	// Liveness and readiness endpoints, outside /api/v1 so they need no token and have no request timeout
+	healthController := controllers.NewHealthController(services.database, buildinfo.Get(), startedAt)
+	router.GET("/healthz", healthController.Liveness) // GET /healthz (process is up)
+	router.GET("/readyz", healthController.Readiness) // GET /readyz (MongoDB is reachable, always in memory mode)
+	router.GET("/version", healthController.Version)  // GET /version (which build is running)
+
+	// Public key set for verifying RS256 tokens (404 with HS256, whose secret must stay private)
+	jwksController := controllers.NewJWKSController(services.jwtService)
+	router.GET("/.well-known/jwks.json", jwksController.GetJWKS) // GET /.well-known/jwks.json
+
+	// Prometheus metrics endpoint, outside /api/v1 so it needs no JWT (basic auth when METRICS_USERNAME is set)
+	router.GET("/metrics", metrics.Handlers()...)
+
+	// API contract and its rendered documentation, public like the health endpoints
+	docsController := controllers.NewDocsController(buildinfo.Version)
+	router.GET("/openapi.json", docsController.GetSpec) // GET /openapi.json
+	router.GET("/docs", docsController.GetDocs)         // GET /docs
+
+	// Unknown paths get a JSON 404 and known paths a JSON 405 naming the allowed methods, so clients always get
+	// the error envelope. These run after the global middleware only, so a wrong method is never a 401.
+	fallbackController := controllers.NewFallbackController(router.Routes())
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(fallbackController.NotFound)
+	router.NoMethod(fallbackController.MethodNotAllowed)
 
 	return router
-}
\ No newline at end of file
+}
@@ -1,30 +1,44 @@
 package routers
 
 import (
+	"bufio"
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Infrastructure/buildinfo"
 )
 
 func setupTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
-	
+
 	// Create a mock MongoDB client for testing
 	client, _ := mongo.NewClient(options.Client().ApplyURI("mongodb://localhost:27017"))
-	
+
 	dbConfig := &DatabaseConfig{
 		URI:        "mongodb://localhost:27017",
 		Database:   "testdb",
 		Collection: "tasks",
 	}
-	
-	return SetupRouter(client, dbConfig)
+
+	return SetupRouter(client, dbConfig, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
 }
 
 func TestSetupRouter(t *testing.T) {
@@ -37,11 +51,11 @@ func TestSetupRouter(t *testing.T) {
 	})
 }
 
-func TestHealthEndpoint(t *testing.T) {
-	t.Run("Success - health check", func(t *testing.T) {
+func TestHealthEndpoints(t *testing.T) {
+	t.Run("Success - liveness check", func(t *testing.T) {
 		// Arrange
 		router := setupTestRouter()
-		req := httptest.NewRequest("GET", "/health", nil)
+		req := httptest.NewRequest("GET", "/healthz", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -49,12 +63,158 @@ func TestHealthEndpoint(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
+
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.Equal(t, "OK", response["status"])
 		assert.Equal(t, "Task Management API is running", response["message"])
+		assert.Equal(t, buildinfo.Version, response["version"])
+		assert.Equal(t, buildinfo.Commit, response["commit"])
+		assert.Equal(t, buildinfo.BuildTime, response["build_time"])
+		assert.Contains(t, response, "uptime")
+		assert.Equal(t, buildinfo.Version, w.Header().Get(buildinfo.VersionHeader))
+	})
+
+	t.Run("Success - version", func(t *testing.T) {
+		// Arrange
+		router := setupTestRouter()
+		req := httptest.NewRequest("GET", "/version", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var response Domain.VersionInfo
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, buildinfo.Get(), response)
+	})
+
+	t.Run("Error - readiness with a disconnected client", func(t *testing.T) {
+		// Arrange
+		router := setupTestRouter()
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response map[string]interface{}
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "unavailable", response["status"])
+		assert.NotEmpty(t, response["error"])
+	})
+
+	t.Run("Error - readiness with an unreachable URI", func(t *testing.T) {
+		// Arrange
+		clientOptions := options.Client().ApplyURI("mongodb://127.0.0.1:1").SetServerSelectionTimeout(100 * time.Millisecond)
+		client, err := mongo.Connect(context.Background(), clientOptions)
+		assert.NoError(t, err)
+		defer client.Disconnect(context.Background())
+
+		dbConfig := &DatabaseConfig{URI: "mongodb://127.0.0.1:1", Database: "testdb", Collection: "tasks"}
+		router := SetupRouter(client, dbConfig, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		req := httptest.NewRequest("GET", "/readyz", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var response map[string]interface{}
+		err = json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.Equal(t, "unavailable", response["status"])
+		assert.Equal(t, "Database is unreachable", response["message"])
+		assert.NotEmpty(t, response["error"])
+	})
+}
+
+func TestMetricsEndpoint(t *testing.T) {
+	t.Run("Success - served without a token", func(t *testing.T) {
+		// Arrange
+		router := setupTestRouter()
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/healthz", nil))
+		req := httptest.NewRequest("GET", "/metrics", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="/healthz",status="200"} 1`)
+	})
+
+	t.Run("Success - business metrics are served", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(`{"username": "admin", "password": "Demo-Passw0rd!"}`)))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(`{"username": "admin", "password": "Wrong-Passw0rd!"}`)))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Body.String(), `users_registered_total{role="admin"} 1`)
+		assert.Contains(t, w.Body.String(), `login_failures_total{reason="invalid_credentials"} 1`)
+	})
+
+	t.Run("Success - routers can be built repeatedly", func(t *testing.T) {
+		// Act & Assert
+		assert.NotPanics(t, func() {
+			setupTestRouter()
+			setupTestRouter()
+		})
+	})
+}
+
+func TestRateLimiting(t *testing.T) {
+	t.Run("Error - login is limited after the burst", func(t *testing.T) {
+		// Arrange
+		router := setupTestRouter()
+		for i := 0; i < Infrastructure.DefaultAuthRateLimit.Burst; i++ {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString("{")))
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		}
+		req := httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.NotEmpty(t, w.Header().Get("Retry-After"))
+		assert.Contains(t, w.Body.String(), `"request_id"`)
+	})
+
+	t.Run("Success - protected routes use the higher limit", func(t *testing.T) {
+		// Arrange
+		router := setupTestRouter()
+		for i := 0; i < Infrastructure.DefaultAuthRateLimit.Burst; i++ {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString("{")))
+		}
+		req := httptest.NewRequest("GET", "/api/v1/tasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 }
 
@@ -110,6 +270,13 @@ func TestRouterEndpoints(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized, // No auth header
 			description:    "Should require authentication",
 		},
+		{
+			name:           "Tasks by user report requires auth",
+			method:         "GET",
+			path:           "/api/v1/reports/tasks-by-user",
+			expectedStatus: http.StatusUnauthorized, // No auth header
+			description:    "Should require authentication",
+		},
 		{
 			name:           "Get task by ID requires auth",
 			method:         "GET",
@@ -131,6 +298,13 @@ func TestRouterEndpoints(t *testing.T) {
 			expectedStatus: http.StatusUnauthorized, // No auth header
 			description:    "Should require authentication",
 		},
+		{
+			name:           "Patch task requires auth",
+			method:         "PATCH",
+			path:           "/api/v1/tasks/507f1f77bcf86cd799439011",
+			expectedStatus: http.StatusUnauthorized, // No auth header
+			description:    "Should require authentication",
+		},
 		{
 			name:           "Delete task requires auth",
 			method:         "DELETE",
@@ -156,7 +330,7 @@ func TestRouterEndpoints(t *testing.T) {
 }
 
 func TestRouterNotFoundEndpoint(t *testing.T) {
-	t.Run("404 for non-existent endpoint", func(t *testing.T) {
+	t.Run("JSON 404 for non-existent endpoint", func(t *testing.T) {
 		// Arrange
 		router := setupTestRouter()
 		req := httptest.NewRequest("GET", "/non-existent", nil)
@@ -167,6 +341,30 @@ func TestRouterNotFoundEndpoint(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusNotFound, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.False(t, response.Success)
+		assert.Equal(t, Domain.MsgRouteNotFound, response.MessageID)
+		assert.Equal(t, "Route not found", response.Message)
+		assert.Equal(t, "No route for GET /non-existent", response.Error)
+		assert.NotEmpty(t, response.RequestID)
+	})
+
+	t.Run("Message follows Accept-Language", func(t *testing.T) {
+		// Arrange
+		router := setupTestRouter()
+		req := httptest.NewRequest("DELETE", "/api/v1/register", nil)
+		req.Header.Set("Accept-Language", "am")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Contains(t, w.Body.String(), Domain.Translate("am", Domain.MsgMethodNotAllowed))
 	})
 }
 
@@ -196,7 +394,14 @@ func TestRouterMiddlewareChain(t *testing.T) {
 		}{
 			{"POST", "/api/v1/register"},
 			{"POST", "/api/v1/login"},
-			{"GET", "/health"},
+			{"POST", "/api/v1/auth/refresh"},
+			{"POST", "/api/v1/auth/forgot-password"},
+			{"POST", "/api/v1/auth/reset-password"},
+			{"POST", "/api/v1/auth/verify-email"},
+			{"GET", "/healthz"},
+			{"GET", "/readyz"},
+			{"GET", "/version"},
+			{"GET", "/.well-known/jwks.json"},
 		}
 
 		for _, endpoint := range publicEndpoints {
@@ -207,7 +412,7 @@ func TestRouterMiddlewareChain(t *testing.T) {
 
 			// These endpoints should not return 401 (unauthorized)
 			// They might return 400 (bad request) due to missing body, but not 401
-			assert.NotEqual(t, http.StatusUnauthorized, w.Code, 
+			assert.NotEqual(t, http.StatusUnauthorized, w.Code,
 				"Endpoint %s %s should not require authentication", endpoint.method, endpoint.path)
 		}
 	})
@@ -219,14 +424,44 @@ func TestRouterMiddlewareChain(t *testing.T) {
 			method string
 			path   string
 		}{
+			{"POST", "/api/v1/auth/logout"},
 			{"GET", "/api/v1/users/profile"},
+			{"PUT", "/api/v1/users/profile"},
+			{"PUT", "/api/v1/users/password"},
 			{"GET", "/api/v1/users"},
+			{"GET", "/api/v1/users/export"},
 			{"POST", "/api/v1/users/promote"},
 			{"GET", "/api/v1/tasks"},
 			{"GET", "/api/v1/tasks/507f1f77bcf86cd799439011"},
 			{"POST", "/api/v1/tasks"},
+			{"POST", "/api/v1/tasks/bulk"},
+			{"POST", "/api/v1/tasks/import"},
+			{"POST", "/api/v1/tasks/bulk-status"},
+			{"DELETE", "/api/v1/tasks?status=completed"},
 			{"PUT", "/api/v1/tasks/507f1f77bcf86cd799439011"},
+			{"PATCH", "/api/v1/tasks/507f1f77bcf86cd799439011"},
 			{"DELETE", "/api/v1/tasks/507f1f77bcf86cd799439011"},
+			{"GET", "/api/v1/tasks/trash"},
+			{"DELETE", "/api/v1/tasks/trash"},
+			{"POST", "/api/v1/tasks/507f1f77bcf86cd799439011/restore"},
+			{"POST", "/api/v1/tasks/archive?completed_before=2025-01-01"},
+			{"POST", "/api/v1/tasks/507f1f77bcf86cd799439011/archive"},
+			{"POST", "/api/v1/tasks/507f1f77bcf86cd799439011/unarchive"},
+			{"GET", "/api/v1/tasks/assigned-to-me"},
+			{"GET", "/api/v1/tasks/tags"},
+			{"GET", "/api/v1/tasks/count"},
+			{"GET", "/api/v1/tasks/export"},
+			{"GET", "/api/v1/tasks/stats"},
+			{"GET", "/api/v1/tasks/overdue"},
+			{"GET", "/api/v1/tasks/search?q=x"},
+			{"POST", "/api/v1/tasks/507f1f77bcf86cd799439011/comments"},
+			{"GET", "/api/v1/tasks/507f1f77bcf86cd799439011/comments"},
+			{"POST", "/api/v1/tasks/507f1f77bcf86cd799439011/worklogs"},
+			{"GET", "/api/v1/tasks/507f1f77bcf86cd799439011/worklogs"},
+			{"GET", "/api/v1/worklogs/summary"},
+			{"PATCH", "/api/v1/tasks/507f1f77bcf86cd799439011/status"},
+			{"GET", "/api/v1/tasks/507f1f77bcf86cd799439011/revisions"},
+			{"GET", "/api/v1/audit"},
 		}
 
 		for _, endpoint := range protectedEndpoints {
@@ -236,7 +471,7 @@ func TestRouterMiddlewareChain(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			// These endpoints should return 401 (unauthorized) without auth header
-			assert.Equal(t, http.StatusUnauthorized, w.Code, 
+			assert.Equal(t, http.StatusUnauthorized, w.Code,
 				"Endpoint %s %s should require authentication", endpoint.method, endpoint.path)
 		}
 	})
@@ -254,6 +489,8 @@ func TestRouterAPIVersioning(t *testing.T) {
 			{"POST", "/api/v1/register"},
 			{"POST", "/api/v1/login"},
 			{"GET", "/api/v1/users/profile"},
+			{"PUT", "/api/v1/users/profile"},
+			{"PUT", "/api/v1/users/password"},
 			{"GET", "/api/v1/users"},
 			{"POST", "/api/v1/users/promote"},
 			{"GET", "/api/v1/tasks"},
@@ -267,7 +504,7 @@ func TestRouterAPIVersioning(t *testing.T) {
 
 			// Should not return 404 (not found) - endpoint exists
 			// May return 401 (unauthorized) or 400 (bad request) for protected/invalid endpoints
-			assert.NotEqual(t, http.StatusNotFound, w.Code, 
+			assert.NotEqual(t, http.StatusNotFound, w.Code,
 				"Endpoint %s %s should exist", endpoint.method, endpoint.path)
 		}
 	})
@@ -289,42 +526,56 @@ func TestRouterAPIVersioning(t *testing.T) {
 			router.ServeHTTP(w, req)
 
 			// Should return 404 (not found) - endpoint doesn't exist without versioning
-			assert.Equal(t, http.StatusNotFound, w.Code, 
+			assert.Equal(t, http.StatusNotFound, w.Code,
 				"Endpoint %s should not exist without versioning", endpoint)
 		}
 	})
 }
 
 func TestRouterHTTPMethods(t *testing.T) {
-	t.Run("Endpoints respond to correct HTTP methods", func(t *testing.T) {
+	t.Run("Wrong methods get a JSON 405 naming the allowed methods", func(t *testing.T) {
 		router := setupTestRouter()
 
 		methodTests := []struct {
-			correctMethod string
-			wrongMethod   string
-			path          string
+			method string
+			path   string
+			allow  string
 		}{
-			{"POST", "GET", "/api/v1/register"},
-			{"POST", "PUT", "/api/v1/login"},
-			{"GET", "POST", "/api/v1/users/profile"},
-			{"POST", "GET", "/api/v1/users/promote"},
-			{"GET", "POST", "/api/v1/tasks"},
-			{"POST", "GET", "/api/v1/tasks"},
-			{"PUT", "POST", "/api/v1/tasks/507f1f77bcf86cd799439011"},
-			{"DELETE", "GET", "/api/v1/tasks/507f1f77bcf86cd799439011"},
+			{"GET", "/api/v1/register", "POST, OPTIONS"},
+			{"PUT", "/api/v1/login", "POST, OPTIONS"},
+			// DELETE /users/:id serves these paths as well
+			{"POST", "/api/v1/users/profile", "DELETE, GET, PUT, OPTIONS"},
+			{"GET", "/api/v1/users/promote", "DELETE, POST, OPTIONS"},
+			{"POST", "/api/v1/tasks/507f1f77bcf86cd799439011", "DELETE, GET, PATCH, PUT, OPTIONS"},
+			{"DELETE", "/healthz", "GET, OPTIONS"},
 		}
 
 		for _, test := range methodTests {
-			// Test wrong method returns 405, 404, or 401 (for protected endpoints)
-			req := httptest.NewRequest(test.wrongMethod, test.path, nil)
+			// The auth middleware belongs to the route, so a protected path answers 405 rather than 401
+			req := httptest.NewRequest(test.method, test.path, nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 
-			// Should return method not allowed, not found, or unauthorized (for protected endpoints)
-			// Protected endpoints may return 401 because auth middleware runs before method check
-			assert.True(t, w.Code == http.StatusMethodNotAllowed || w.Code == http.StatusNotFound || w.Code == http.StatusUnauthorized,
-				"Wrong method %s for %s should return 405, 404, or 401, got %d", 
-				test.wrongMethod, test.path, w.Code)
+			assert.Equal(t, http.StatusMethodNotAllowed, w.Code, "%s %s", test.method, test.path)
+			assert.Equal(t, test.allow, w.Header().Get("Allow"), "%s %s", test.method, test.path)
+
+			var response Domain.ErrorResponse
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			assert.False(t, response.Success)
+			assert.Equal(t, Domain.MsgMethodNotAllowed, response.MessageID)
+			assert.NotEmpty(t, response.RequestID)
+		}
+	})
+
+	t.Run("Right methods on protected endpoints require authentication", func(t *testing.T) {
+		router := setupTestRouter()
+
+		for _, method := range []string{"GET", "PUT", "PATCH", "DELETE"} {
+			req := httptest.NewRequest(method, "/api/v1/tasks/507f1f77bcf86cd799439011", nil)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusUnauthorized, w.Code, method)
 		}
 	})
 }
@@ -346,7 +597,7 @@ func TestRouterContentType(t *testing.T) {
 }
 
 func TestRouterCORS(t *testing.T) {
-	t.Run("Router handles OPTIONS requests", func(t *testing.T) {
+	t.Run("OPTIONS lists the allowed methods", func(t *testing.T) {
 		router := setupTestRouter()
 
 		req := httptest.NewRequest("OPTIONS", "/api/v1/register", nil)
@@ -354,9 +605,9 @@ func TestRouterCORS(t *testing.T) {
 
 		router.ServeHTTP(w, req)
 
-		// OPTIONS requests should be handled (not return 405)
-		// Gin handles OPTIONS by default
-		assert.NotEqual(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Equal(t, "POST, OPTIONS", w.Header().Get("Allow"))
+		assert.Empty(t, w.Body.String())
 	})
 }
 
@@ -398,16 +649,771 @@ func TestSetupRouterWithDifferentConfigs(t *testing.T) {
 			client, _ := mongo.NewClient(options.Client().ApplyURI(tc.config.URI))
 
 			// Act
-			router := SetupRouter(client, tc.config)
+			router := SetupRouter(client, tc.config, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
 
 			// Assert
 			assert.NotNil(t, router)
 
 			// Test that health endpoint works
-			req := httptest.NewRequest("GET", "/health", nil)
+			req := httptest.NewRequest("GET", "/healthz", nil)
 			w := httptest.NewRecorder()
 			router.ServeHTTP(w, req)
 			assert.Equal(t, http.StatusOK, w.Code)
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestSetupRouter_InMemory(t *testing.T) {
+	t.Run("Success - register, log in and create a task without MongoDB", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		credentials := `{"username": "demo", "password": "Demo-Passw0rd!"}`
+
+		// Act
+		register := httptest.NewRecorder()
+		router.ServeHTTP(register, httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(login.Body.Bytes(), &loginResponse))
+
+		create := httptest.NewRecorder()
+		createReq := httptest.NewRequest("POST", "/api/v1/tasks", bytes.NewBufferString(`{"title": "Try the demo", "status": "pending", "due_date": "2030-01-01T00:00:00Z"}`))
+		createReq.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+		router.ServeHTTP(create, createReq)
+
+		list := httptest.NewRecorder()
+		listReq := httptest.NewRequest("GET", "/api/v1/tasks", nil)
+		listReq.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+		router.ServeHTTP(list, listReq)
+
+		ready := httptest.NewRecorder()
+		router.ServeHTTP(ready, httptest.NewRequest("GET", "/readyz", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, register.Code)
+		assert.Equal(t, http.StatusOK, login.Code)
+		assert.NotEmpty(t, loginResponse.Token)
+		assert.Equal(t, http.StatusCreated, create.Code, create.Body.String())
+		assert.Equal(t, http.StatusOK, list.Code)
+		assert.Contains(t, list.Body.String(), "Try the demo")
+		assert.Equal(t, http.StatusOK, ready.Code)
+	})
+}
+
+func TestWebhooks_InMemory(t *testing.T) {
+	t.Run("Success - a created task is delivered signed and logged", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		received := make(chan *http.Request, 1)
+		var receivedBody []byte
+		receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			receivedBody, _ = io.ReadAll(r.Body)
+			received <- r
+		}))
+		defer receiver.Close()
+
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		// The first user to register becomes the admin
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(login.Body.Bytes(), &loginResponse))
+		authorized := func(method, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Act
+		create := authorized("POST", "/api/v1/webhooks", `{"url": "`+receiver.URL+`", "secret": "0123456789abcdef", "events": ["task.created"]}`)
+		var createResponse struct {
+			Data struct {
+				ID     string `json:"id"`
+				Secret string `json:"secret"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(create.Body.Bytes(), &createResponse))
+		task := authorized("POST", "/api/v1/tasks", `{"title": "Ship it", "status": "pending"}`)
+		var request *http.Request
+		select {
+		case request = <-received:
+		case <-time.After(5 * time.Second):
+			t.Fatal("the webhook was not delivered")
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assert.NoError(t, services.Close(ctx))
+		deliveries := authorized("GET", "/api/v1/webhooks/"+createResponse.Data.ID+"/deliveries", "")
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, create.Code, create.Body.String())
+		assert.Equal(t, "0123456789abcdef", createResponse.Data.Secret)
+		assert.Equal(t, http.StatusCreated, task.Code, task.Body.String())
+		assert.Equal(t, "task.created", request.Header.Get("X-Webhook-Event"))
+		assert.Equal(t, Infrastructure.SignWebhookPayload("0123456789abcdef", receivedBody), request.Header.Get("X-Webhook-Signature"))
+		assert.Contains(t, string(receivedBody), "Ship it")
+		assert.Equal(t, http.StatusOK, deliveries.Code)
+		assert.Contains(t, deliveries.Body.String(), `"delivered":true`)
+	})
+}
+
+func TestTaskCache_InMemory(t *testing.T) {
+	t.Run("Success - cached reads see the latest write and are counted", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		t.Setenv("TASK_CACHE_TTL", "1m")
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(login.Body.Bytes(), &loginResponse))
+		authorized := func(method, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+		create := authorized("POST", "/api/v1/tasks", `{"title": "Before", "status": "pending"}`)
+		var createResponse struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(create.Body.Bytes(), &createResponse))
+		path := "/api/v1/tasks/" + createResponse.Data.ID
+
+		// Act
+		authorized("GET", path, "")
+		cached := authorized("GET", path, "")
+		patch := authorized("PATCH", path, `{"title": "After"}`)
+		afterWrite := authorized("GET", path, "")
+		metrics := httptest.NewRecorder()
+		router.ServeHTTP(metrics, httptest.NewRequest("GET", "/metrics", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, cached.Code)
+		assert.Contains(t, cached.Body.String(), "Before")
+		assert.Equal(t, http.StatusOK, patch.Code, patch.Body.String())
+		assert.Contains(t, afterWrite.Body.String(), "After")
+		assert.Regexp(t, `cache_hits_total\{cache="tasks"\} [1-9]`, metrics.Body.String())
+		assert.Contains(t, metrics.Body.String(), `cache_misses_total{cache="tasks"}`)
+	})
+}
+
+func TestArchive_InMemory(t *testing.T) {
+	t.Run("Success - archived tasks leave the cached listing and come back when unarchived", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		t.Setenv("TASK_CACHE_TTL", "1m")
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(login.Body.Bytes(), &loginResponse))
+		authorized := func(method, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+		var created struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		create := authorized("POST", "/api/v1/tasks", `{"title": "Shipped", "status": "in_progress"}`)
+		assert.NoError(t, json.Unmarshal(create.Body.Bytes(), &created))
+		authorized("PATCH", "/api/v1/tasks/"+created.Data.ID+"/status", `{"status": "completed"}`)
+		authorized("POST", "/api/v1/tasks", `{"title": "Open", "status": "pending"}`)
+		authorized("GET", "/api/v1/tasks", "")
+
+		// Act
+		archive := authorized("POST", "/api/v1/tasks/"+created.Data.ID+"/archive", "")
+		listed := authorized("GET", "/api/v1/tasks", "")
+		archived := authorized("GET", "/api/v1/tasks?archived=true", "")
+		unarchive := authorized("POST", "/api/v1/tasks/"+created.Data.ID+"/unarchive", "")
+		relisted := authorized("GET", "/api/v1/tasks", "")
+		bulk := authorized("POST", "/api/v1/tasks/archive?completed_before="+time.Now().Add(time.Minute).UTC().Format(time.RFC3339), "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, archive.Code, archive.Body.String())
+		assert.NotContains(t, listed.Body.String(), "Shipped")
+		assert.Contains(t, listed.Body.String(), "Open")
+		assert.Contains(t, archived.Body.String(), "Shipped")
+		assert.NotContains(t, archived.Body.String(), "Open")
+		assert.Equal(t, http.StatusOK, unarchive.Code, unarchive.Body.String())
+		assert.Contains(t, relisted.Body.String(), "Shipped")
+		assert.Equal(t, http.StatusOK, bulk.Code, bulk.Body.String())
+		assert.Contains(t, bulk.Body.String(), `"modified_count":1`)
+	})
+}
+
+func TestTaskFields_InMemory(t *testing.T) {
+	t.Run("Success - listing and reading a task return only the selected fields", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(login.Body.Bytes(), &loginResponse))
+		authorized := func(path string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+		create := httptest.NewRequest("POST", "/api/v1/tasks", bytes.NewBufferString(`{"title": "Report", "description": "A long description", "status": "pending"}`))
+		create.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+		created := httptest.NewRecorder()
+		router.ServeHTTP(created, create)
+		var createResponse struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(created.Body.Bytes(), &createResponse))
+
+		// Act
+		list := authorized("/api/v1/tasks?fields=id,title,status,due_date")
+		one := authorized("/api/v1/tasks/" + createResponse.Data.ID + "?fields=title")
+		unknown := authorized("/api/v1/tasks?fields=title,secret")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, list.Code)
+		var listResponse struct {
+			Data []map[string]interface{} `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal(list.Body.Bytes(), &listResponse))
+		assert.Equal(t, []map[string]interface{}{{
+			"id": createResponse.Data.ID, "title": "Report", "status": "pending", "due_date": "0001-01-01T00:00:00Z",
+		}}, listResponse.Data)
+		assert.Equal(t, http.StatusOK, one.Code)
+		assert.NotContains(t, one.Body.String(), "description")
+		assert.Contains(t, one.Body.String(), `"title":"Report"`)
+		assert.Equal(t, http.StatusBadRequest, unknown.Code)
+		assert.Contains(t, unknown.Body.String(), "must be any of: id, title")
+	})
+}
+
+func TestTasksByUserReport_InMemory(t *testing.T) {
+	t.Run("Success - admins see every user, regular users are refused", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		login := func(username string) string {
+			credentials := `{"username": "` + username + `", "password": "Demo-Passw0rd!"}`
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+			var response struct {
+				Token string `json:"token"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			return response.Token
+		}
+		adminToken, userToken := login("admin"), login("worker")
+		authorized := func(token, method, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+		authorized(adminToken, "POST", "/api/v1/tasks", `{"title": "Report", "status": "pending"}`)
+
+		// Act
+		report := authorized(adminToken, "GET", "/api/v1/reports/tasks-by-user", "")
+		refused := authorized(userToken, "GET", "/api/v1/reports/tasks-by-user", "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, report.Code, report.Body.String())
+		var response Domain.ListResponse[*Domain.UserTaskReport]
+		assert.NoError(t, json.Unmarshal(report.Body.Bytes(), &response))
+		if assert.Len(t, response.Data, 2) {
+			assert.Equal(t, "admin", response.Data[0].Username)
+			assert.Equal(t, int64(1), response.Data[0].Open)
+			assert.Equal(t, "worker", response.Data[1].Username)
+			assert.Zero(t, response.Data[1].Open)
+		}
+		assert.Equal(t, http.StatusForbidden, refused.Code)
+	})
+}
+
+func TestUserDirectory_InMemory(t *testing.T) {
+	t.Run("Success - regular users list IDs and usernames but not the full user list", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		login := func(username string) string {
+			credentials := `{"username": "` + username + `", "email": "` + username + `@example.com", "password": "Demo-Passw0rd!"}`
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+			var response struct {
+				Token string `json:"token"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			return response.Token
+		}
+		login("admin")
+		userToken := login("worker")
+		get := func(path string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest("GET", path, nil)
+			req.Header.Set("Authorization", "Bearer "+userToken)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Act
+		directory := get("/api/v1/users/directory?sort=username&page_size=1")
+		search := get("/api/v1/users/directory?q=WORK")
+		byEmail := get("/api/v1/users/directory?q=example.com")
+		fullList := get("/api/v1/users")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, directory.Code, directory.Body.String())
+		var response struct {
+			Data []map[string]interface{} `json:"data"`
+			Meta Domain.PaginationMeta    `json:"meta"`
+		}
+		assert.NoError(t, json.Unmarshal(directory.Body.Bytes(), &response))
+		if assert.Len(t, response.Data, 1) {
+			assert.Equal(t, "admin", response.Data[0]["username"])
+			assert.NotEmpty(t, response.Data[0]["id"])
+			assert.Len(t, response.Data[0], 2, "only the id and username are exposed")
+		}
+		assert.Equal(t, int64(2), response.Meta.Total)
+		assert.Contains(t, search.Body.String(), `"username":"worker"`)
+		assert.NotContains(t, search.Body.String(), `"username":"admin"`)
+		assert.Contains(t, byEmail.Body.String(), `"total":0`)
+		assert.Equal(t, http.StatusForbidden, fullList.Code)
+	})
+}
+
+func TestWorkLogs_InMemory(t *testing.T) {
+	t.Run("Success - logged work adds up on the task and in the summary, capped at a day", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResponse))
+		authorized := func(method, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		created := authorized("POST", "/api/v1/tasks", `{"title": "Estimated", "status": "pending", "estimate_minutes": 120}`)
+		var createResponse Domain.Response[*Domain.Task]
+		assert.NoError(t, json.Unmarshal(created.Body.Bytes(), &createResponse))
+		taskPath := "/api/v1/tasks/" + createResponse.Data.ID.Hex()
+
+		// Act
+		logged := authorized("POST", taskPath+"/worklogs", `{"minutes": 90, "note": "Design", "date": "2024-03-01"}`)
+		overLimit := authorized("POST", taskPath+"/worklogs", `{"minutes": 1400, "date": "2024-03-01"}`)
+		task := authorized("GET", taskPath, "")
+		summary := authorized("GET", "/api/v1/worklogs/summary?from=2024-03-01&to=2024-03-31", "")
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, logged.Code, logged.Body.String())
+		assert.Equal(t, http.StatusConflict, overLimit.Code, overLimit.Body.String())
+
+		var taskResponse Domain.Response[*Domain.Task]
+		assert.NoError(t, json.Unmarshal(task.Body.Bytes(), &taskResponse))
+		assert.Equal(t, 120, *taskResponse.Data.EstimateMinutes)
+		assert.Equal(t, 90, *taskResponse.Data.TotalLoggedMinutes)
+
+		var summaryResponse Domain.ListResponse[*Domain.WorkLogSummary]
+		assert.NoError(t, json.Unmarshal(summary.Body.Bytes(), &summaryResponse))
+		if assert.Len(t, summaryResponse.Data, 1) {
+			assert.Equal(t, int64(90), summaryResponse.Data[0].TotalMinutes)
+			assert.Equal(t, int64(1), summaryResponse.Data[0].Entries)
+		}
+	})
+}
+
+func TestTaskEventStream_InMemory(t *testing.T) {
+	t.Run("Success - a created task is streamed to a connected client", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		server := httptest.NewServer(NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics()))
+		defer server.Close()
+		defer services.EndStreams()
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		_, err = http.Post(server.URL+"/api/v1/register", "application/json", bytes.NewBufferString(credentials))
+		assert.NoError(t, err)
+		login, err := http.Post(server.URL+"/api/v1/login", "application/json", bytes.NewBufferString(credentials))
+		assert.NoError(t, err)
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.NewDecoder(login.Body).Decode(&loginResponse))
+		login.Body.Close()
+
+		streamReq, _ := http.NewRequest("GET", server.URL+"/api/v1/tasks/events", nil)
+		streamReq.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+		stream, err := http.DefaultClient.Do(streamReq)
+		assert.NoError(t, err)
+		defer stream.Body.Close()
+		reader := bufio.NewReader(stream.Body)
+		connected, _ := reader.ReadString('\n')
+		reader.ReadString('\n')
+
+		// Act
+		createReq, _ := http.NewRequest("POST", server.URL+"/api/v1/tasks", bytes.NewBufferString(`{"title": "Watch me", "status": "pending"}`))
+		createReq.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+		create, err := http.DefaultClient.Do(createReq)
+		assert.NoError(t, err)
+		create.Body.Close()
+		var message []string
+		for len(message) < 3 {
+			line, err := reader.ReadString('\n')
+			if !assert.NoError(t, err) {
+				break
+			}
+			message = append(message, strings.TrimSuffix(line, "\n"))
+		}
+
+		// Assert
+		assert.Equal(t, http.StatusOK, stream.StatusCode)
+		assert.Equal(t, ": connected\n", connected)
+		assert.Equal(t, http.StatusCreated, create.StatusCode)
+		assert.Equal(t, "event: task.created", message[1])
+		assert.Contains(t, message[2], "Watch me")
+	})
+}
+
+func TestAttachments_InMemory(t *testing.T) {
+	t.Run("Success - a file larger than the body limit is uploaded, downloaded and purged with its task", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		dir := t.TempDir()
+		t.Setenv("ATTACHMENTS_DIR", dir)
+		t.Setenv("ATTACHMENT_MAX_BYTES", strconv.Itoa(4<<20))
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		login := httptest.NewRecorder()
+		router.ServeHTTP(login, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(login.Body.Bytes(), &loginResponse))
+		authorized := func(method, path string, body io.Reader, contentType string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, body)
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			if contentType != "" {
+				req.Header.Set("Content-Type", contentType)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+		var created struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		task := authorized("POST", "/api/v1/tasks", strings.NewReader(`{"title": "Design", "status": "pending"}`), "")
+		assert.NoError(t, json.Unmarshal(task.Body.Bytes(), &created))
+		taskID := created.Data.ID
+
+		content := strings.Repeat("notes ", 2<<20/6)
+		form := &bytes.Buffer{}
+		writer := multipart.NewWriter(form)
+		part, _ := writer.CreateFormFile("file", "notes.txt")
+		_, _ = part.Write([]byte(content))
+		writer.Close()
+
+		// Act
+		upload := authorized("POST", "/api/v1/tasks/"+taskID+"/attachments", form, writer.FormDataContentType())
+		assert.NoError(t, json.Unmarshal(upload.Body.Bytes(), &created))
+		download := authorized("GET", "/api/v1/tasks/"+taskID+"/attachments/"+created.Data.ID, nil, "")
+		authorized("DELETE", "/api/v1/tasks/"+taskID, nil, "")
+		purge := authorized("DELETE", "/api/v1/tasks/trash?older_than_days=0", nil, "")
+		entries, _ := os.ReadDir(dir)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, upload.Code, upload.Body.String())
+		assert.Equal(t, http.StatusOK, download.Code)
+		assert.Equal(t, "text/plain; charset=utf-8", download.Header().Get("Content-Type"))
+		assert.Equal(t, `attachment; filename=notes.txt`, download.Header().Get("Content-Disposition"))
+		assert.Equal(t, content, download.Body.String())
+		assert.Equal(t, http.StatusOK, purge.Code)
+		assert.Empty(t, entries, "purging the task deletes its files")
+	})
+}
+
+// pathParam matches a gin path parameter such as :id, which OpenAPI writes as {id}
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+func TestOpenAPISpecCoversRoutes(t *testing.T) {
+	t.Run("Success - every registered route is documented", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/openapi.json", nil))
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		var spec struct {
+			Paths map[string]map[string]json.RawMessage `json:"paths"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &spec))
+		assert.Contains(t, spec.Paths, "/api/v1/tasks")
+
+		for _, route := range router.Routes() {
+			path := pathParam.ReplaceAllString(route.Path, "{$1}")
+			assert.Contains(t, spec.Paths[path], strings.ToLower(route.Method), "%s %s is not in the OpenAPI spec", route.Method, route.Path)
+		}
+	})
+}
+
+func TestRouterBodyLimitAndCompression(t *testing.T) {
+	t.Run("Error - oversized body is rejected with 413", func(t *testing.T) {
+		// Arrange
+		t.Setenv("MAX_REQUEST_BODY_BYTES", "64")
+		gin.SetMode(gin.TestMode)
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		body := `{"username": "` + strings.Repeat("a", 100) + `", "password": "Demo-Passw0rd!"}`
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(body)))
+
+		// Assert
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.Contains(t, w.Body.String(), "Request body too large")
+	})
+
+	t.Run("Success - large response is gzipped", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+		req := httptest.NewRequest("GET", "/openapi.json", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	})
+}
+
+func TestRequestAudit_InMemory(t *testing.T) {
+	t.Run("Success - user and task changes are traced with passwords redacted", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		t.Setenv("AUDIT_REQUESTS", "true")
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		router := NewRouter(services, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+
+		credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials)))
+		var loginResponse struct {
+			Token string `json:"token"`
+		}
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResponse))
+		authorized := func(method, path, body string) *httptest.ResponseRecorder {
+			req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			return w
+		}
+
+		// Act
+		changed := authorized("PUT", "/api/v1/users/password", `{"current_password": "Demo-Passw0rd!", "new_password": "Other-Passw0rd!"}`)
+		created := authorized("POST", "/api/v1/tasks", `{"title": "Traced", "status": "pending"}`)
+		authorized("GET", "/api/v1/tasks", "")
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		assert.NoError(t, services.Close(ctx))
+		entries, _, err := services.Audit.GetAuditLog(context.Background(), Domain.AuditFilter{Entity: Domain.AuditEntityAPI}, Domain.Pagination{})
+
+		// Assert
+		assert.Equal(t, http.StatusOK, changed.Code, changed.Body.String())
+		assert.Equal(t, http.StatusCreated, created.Code, created.Body.String())
+		assert.NoError(t, err)
+		if assert.Len(t, entries, 2) {
+			paths := []string{entries[0].Request.Path, entries[1].Request.Path}
+			assert.ElementsMatch(t, []string{"/api/v1/users/password", "/api/v1/tasks"}, paths)
+			for _, entry := range entries {
+				assert.NotEmpty(t, entry.ActorID)
+				assert.NotContains(t, entry.Request.Body, "Passw0rd")
+			}
+		}
+	})
+}
+
+func TestBasePathFromEnv(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"/", ""},
+		{"/tasks-service", "/tasks-service"},
+		{"tasks-service/", "/tasks-service"},
+		{" /edge/tasks/ ", "/edge/tasks"},
+	}
+	for _, tc := range testCases {
+		t.Setenv("BASE_PATH", tc.value)
+		assert.Equal(t, tc.expected, basePathFromEnv(), "BASE_PATH=%q", tc.value)
+	}
+}
+
+func TestServicesComponents_InMemory(t *testing.T) {
+	t.Run("Success - components start and drain in order", func(t *testing.T) {
+		// Arrange
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+		components := services.Components(time.Hour)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		// Act
+		var names []string
+		for _, component := range components {
+			names = append(names, component.Name())
+			assert.NoError(t, component.Start(ctx))
+		}
+		for i := len(components) - 1; i >= 0; i-- {
+			assert.NoError(t, components[i].Stop(ctx))
+		}
+
+		// Assert
+		assert.Equal(t, []string{"webhook_dispatcher", "request_audit", "task_event_streams", "task_reminders"}, names)
+	})
+
+	t.Run("Success - reminders are left out when turned off", func(t *testing.T) {
+		// Arrange
+		services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		assert.NoError(t, err)
+
+		// Act
+		components := services.Components(0)
+
+		// Assert
+		for _, component := range components {
+			assert.NotEqual(t, "task_reminders", component.Name())
+		}
+	})
+}
+
+func TestClientIP_InMemory(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    string
+		remoteAddr string
+		expected   string
+	}{
+		{name: "Error - an untrusted peer cannot spoof its address", remoteAddr: "192.0.2.1:1234", expected: "192.0.2.1"},
+		{name: "Success - a trusted proxy names the client", trusted: "10.0.0.0/8", remoteAddr: "10.0.0.1:1234", expected: "198.51.100.7"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			t.Setenv("TRUSTED_PROXIES", tt.trusted)
+			router := SetupRouter(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopLogger(), Infrastructure.NewMetrics())
+			credentials := `{"username": "admin", "password": "Demo-Passw0rd!"}`
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("POST", "/api/v1/register", bytes.NewBufferString(credentials)))
+
+			login := httptest.NewRequest("POST", "/api/v1/login", bytes.NewBufferString(credentials))
+			login.RemoteAddr = tt.remoteAddr
+			login.Header.Set("X-Forwarded-For", "198.51.100.7")
+			login.Header.Set("X-Real-IP", "198.51.100.8")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, login)
+			var loginResponse struct {
+				Token string `json:"token"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &loginResponse))
+
+			req := httptest.NewRequest("GET", "/api/v1/users/me/logins", nil)
+			req.Header.Set("Authorization", "Bearer "+loginResponse.Token)
+			w = httptest.NewRecorder()
+
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			var response struct {
+				Data []Domain.LoginEvent `json:"data"`
+			}
+			assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+			if assert.Len(t, response.Data, 1) {
+				assert.Equal(t, tt.expected, response.Data[0].IP)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package routers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"task_manager/Domain"
+	"task_manager/Usecases"
+)
+
+// seedAdmin creates the admin named by ADMIN_USERNAME and ADMIN_PASSWORD (and optionally
+// ADMIN_EMAIL) when no admin exists yet. Without them it does nothing and the first user to
+// register becomes the admin instead.
+func seedAdmin(ctx context.Context, userUsecase Usecases.UserUsecaseInterface, logger *slog.Logger) error {
+	username, password := os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD")
+	if username == "" && password == "" {
+		return nil
+	}
+	if username == "" || password == "" {
+		return errors.New("ADMIN_USERNAME and ADMIN_PASSWORD must be set together to seed an admin")
+	}
+
+	user, created, err := userUsecase.SeedAdmin(ctx, Domain.UserRequest{
+		Username: username,
+		Email:    os.Getenv("ADMIN_EMAIL"),
+		Password: password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to seed admin %q: %w", username, err)
+	}
+	if created {
+		logger.InfoContext(ctx, "seeded admin", "username", user.Username, "user_id", user.ID.Hex())
+	} else {
+		logger.InfoContext(ctx, "an admin already exists, skipping admin seeding", "username", username)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package routers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// newSeedTestServices builds the services on in-memory repositories
+func newSeedTestServices(t *testing.T) *Services {
+	services, err := NewServices(nil, &DatabaseConfig{Collection: "tasks", InMemory: true}, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	if err != nil {
+		t.Fatalf("failed to build services: %v", err)
+	}
+	return services
+}
+
+func TestSeedAdmin(t *testing.T) {
+	t.Run("Success - create the admin once across restarts", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADMIN_USERNAME", "root")
+		t.Setenv("ADMIN_PASSWORD", "Root-Passw0rd!")
+		services := newSeedTestServices(t)
+		repos := services.repos
+
+		// Act: the second call stands in for a restart against the same database
+		firstErr := seedAdmin(context.Background(), services.Users, Infrastructure.NewNopLogger())
+		secondErr := seedAdmin(context.Background(), services.Users, Infrastructure.NewNopLogger())
+
+		// Assert
+		assert.NoError(t, firstErr)
+		assert.NoError(t, secondErr)
+		admin, err := repos.users.GetByUsername(context.Background(), "root")
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleAdmin, admin.Role)
+		assert.NotEqual(t, "Root-Passw0rd!", admin.Password)
+		count, err := repos.users.CountUsers(context.Background())
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("Success - nothing to do without the variables", func(t *testing.T) {
+		// Arrange
+		services := newSeedTestServices(t)
+		repos := services.repos
+
+		// Act
+		err := seedAdmin(context.Background(), services.Users, Infrastructure.NewNopLogger())
+
+		// Assert
+		assert.NoError(t, err)
+		count, _ := repos.users.CountUsers(context.Background())
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("Error - username without password", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADMIN_USERNAME", "root")
+
+		// Act
+		err := seedAdmin(context.Background(), newSeedTestServices(t).Users, Infrastructure.NewNopLogger())
+
+		// Assert
+		assert.EqualError(t, err, "ADMIN_USERNAME and ADMIN_PASSWORD must be set together to seed an admin")
+	})
+
+	t.Run("Error - password fails the policy", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADMIN_USERNAME", "root")
+		t.Setenv("ADMIN_PASSWORD", "short")
+
+		// Act
+		err := seedAdmin(context.Background(), newSeedTestServices(t).Users, Infrastructure.NewNopLogger())
+
+		// Assert
+		var policyErr *Domain.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		assert.ErrorContains(t, err, `failed to seed admin "root"`)
+	})
+
+	t.Run("Success - first registration is not an admin once seeding is configured", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADMIN_USERNAME", "root")
+		t.Setenv("ADMIN_PASSWORD", "Root-Passw0rd!")
+		userUsecase := newSeedTestServices(t).Users
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: "early", Password: "Early-Passw0rd!"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleUser, user.Role)
+	})
+}
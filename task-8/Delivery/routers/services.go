@@ -0,0 +1,190 @@
+package routers
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"task_manager/Delivery/controllers"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+	"task_manager/Repositories/postgres"
+	"task_manager/Usecases"
+)
+
+// Services are the usecases built on one set of repositories, shared by the HTTP API and the admin commands
+type Services struct {
+	Tasks          Usecases.TaskUsecaseInterface
+	Users          Usecases.UserUsecaseInterface
+	Comments       Usecases.CommentUsecaseInterface
+	Attachments    Usecases.AttachmentUsecaseInterface
+	WorkLogs       Usecases.WorkLogUsecaseInterface
+	Audit          Usecases.AuditUsecaseInterface
+	PasswordResets Usecases.PasswordResetUsecaseInterface
+	APIKeys        Usecases.APIKeyUsecaseInterface
+	Webhooks       Usecases.WebhookUsecaseInterface
+	Reminders      Usecases.ReminderUsecaseInterface
+	Reports        Usecases.ReportUsecaseInterface
+
+	repos            *repositories
+	webhooks         *Infrastructure.WebhookDispatcher
+	requestAudit     *Infrastructure.RequestAuditMiddleware
+	taskEvents       *Infrastructure.EventBroker
+	taskCache        Infrastructure.Cache
+	attachmentLimits Usecases.AttachmentLimits
+	jwtService       Infrastructure.JWTServiceInterface
+	database         controllers.DatabasePinger
+	postgres         *sql.DB // Set when tasks and users are kept in PostgreSQL
+	logger           *slog.Logger
+}
+
+// NewServices creates the repositories, in memory for demos or on MongoDB, makes sure their indexes
+// exist and builds the usecases on them, which count business events in metrics. With dbConfig.InMemory
+// set, client may be nil. With dbConfig.Driver set to DriverPostgres, tasks and users are kept in
+// PostgreSQL instead, whose schema is migrated here.
+func NewServices(client *mongo.Client, dbConfig *DatabaseConfig, metrics Infrastructure.BusinessMetrics, logger *slog.Logger) (*Services, error) {
+	// Initialize Infrastructure layer
+	passwordService, err := Infrastructure.NewPasswordServiceForAlgorithm(os.Getenv("PASSWORD_HASH_ALGORITHM"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hashing configuration: %v", err)
+	}
+	passwordPolicy := Infrastructure.NewPasswordPolicy()
+	jwtService := Infrastructure.NewJWTService()
+
+	// Initialize Repository layer
+	var repos *repositories
+	var txManager Infrastructure.TransactionManager
+	var database controllers.DatabasePinger
+	if dbConfig.InMemory {
+		logger.Warn("keeping data in memory, everything is lost when the server stops")
+		repos = newMemoryRepositories()
+		txManager = Infrastructure.DirectTransactionManager{}
+		database = memoryDatabase{}
+	} else {
+		txManager = Infrastructure.NewMongoTransactionManager(client, logger)
+		repos = newMongoRepositories(client, dbConfig, Infrastructure.NewRetrier(logger), txManager)
+		database = client
+	}
+
+	// MongoDB transactions do not reach PostgreSQL, so the user writes of promotions and demotions
+	// are no longer atomic with their audit entries
+	var postgresDB *sql.DB
+	if !dbConfig.InMemory && dbConfig.Driver == DriverPostgres {
+		logger.Info("keeping tasks and users in PostgreSQL")
+		postgresDB, err = postgres.Open(context.Background(), dbConfig.PostgresDSN)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err != nil {
+				postgresDB.Close()
+			}
+		}()
+		repos.usePostgres(postgresDB)
+		database = splitDatabase{mongo: client, postgres: postgresDB}
+	}
+
+	// Indexes are created on every start; with STRICT_INDEXES a failure stops startup
+	err = Infrastructure.EnsureIndexes(context.Background(), logger, Infrastructure.StrictIndexes(), []Infrastructure.CollectionIndexes{
+		{Collection: "users", Ensurer: repos.users},
+		{Collection: dbConfig.Collection, Ensurer: repos.tasks},
+		{Collection: "audit_logs", Ensurer: repos.audit},
+		{Collection: "task_revisions", Ensurer: repos.revisions},
+		{Collection: "work_logs", Ensurer: repos.workLogs},
+		{Collection: "refresh_tokens", Ensurer: repos.refreshTokens},
+		{Collection: "revoked_tokens", Ensurer: repos.tokenBlacklist},
+		{Collection: "password_reset_tokens", Ensurer: repos.passwordResets},
+		{Collection: "login_events", Ensurer: repos.loginEvents},
+		{Collection: "api_keys", Ensurer: repos.apiKeys},
+		{Collection: "webhooks", Ensurer: repos.webhooks},
+		{Collection: "webhook_deliveries", Ensurer: repos.webhookDeliveries},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Task reads are cached only when TASK_CACHE_TTL is set
+	taskCache, err := Infrastructure.NewCacheFromEnv("TASK_CACHE")
+	if err != nil {
+		return nil, fmt.Errorf("invalid task cache configuration: %v", err)
+	}
+	if taskCache != nil {
+		repos.tasks = Repositories.NewCachedTaskRepository(repos.tasks, taskCache)
+	}
+
+	// Attachment files are kept on disk under ATTACHMENTS_DIR, in memory mode too
+	blobs := Infrastructure.NewBlobStoreFromEnv()
+	attachmentLimits := Usecases.AttachmentLimitsFromEnv()
+	dueDateRules := Usecases.DueDateRulesFromEnv()
+
+	notifier := Infrastructure.NewNotifier(logger)
+	webhooks := Infrastructure.NewWebhookDispatcher(repos.webhooks, repos.webhookDeliveries, logger)
+	taskEvents := Infrastructure.NewEventBroker(logger)
+
+	// With AUDIT_REQUESTS set, admin and task-changing API requests are traced in the audit log
+	requestAudit := Infrastructure.NewRequestAuditMiddleware(repos.audit, logger)
+	events := Infrastructure.EventBuses{webhooks, taskEvents}
+
+	// Initialize Usecase layer
+	return &Services{
+		Tasks:            Usecases.NewTaskUsecase(repos.tasks, repos.users, repos.comments, repos.attachments, blobs, repos.workLogs, repos.audit, repos.revisions, events, metrics, dueDateRules, logger),
+		Users:            Usecases.NewUserUsecase(repos.users, repos.tasks, repos.refreshTokens, repos.tokenBlacklist, passwordService, passwordPolicy, jwtService, notifier, repos.audit, repos.loginEvents, txManager, events, metrics, logger),
+		Comments:         Usecases.NewCommentUsecase(repos.comments, repos.tasks),
+		Attachments:      Usecases.NewAttachmentUsecase(repos.attachments, repos.tasks, blobs, attachmentLimits, logger),
+		WorkLogs:         Usecases.NewWorkLogUsecase(repos.workLogs, repos.tasks),
+		Audit:            Usecases.NewAuditUsecase(repos.audit),
+		PasswordResets:   Usecases.NewPasswordResetUsecase(repos.users, repos.passwordResets, repos.refreshTokens, passwordService, passwordPolicy, notifier, repos.audit, logger),
+		APIKeys:          Usecases.NewAPIKeyUsecase(repos.apiKeys, repos.audit, logger),
+		Webhooks:         Usecases.NewWebhookUsecase(repos.webhooks, repos.webhookDeliveries, repos.audit, logger),
+		Reminders:        Usecases.NewReminderUsecase(repos.tasks, repos.users, notifier, logger),
+		Reports:          Usecases.NewReportUsecase(repos.reports),
+		repos:            repos,
+		webhooks:         webhooks,
+		requestAudit:     requestAudit,
+		taskEvents:       taskEvents,
+		taskCache:        taskCache,
+		attachmentLimits: attachmentLimits,
+		jwtService:       jwtService,
+		database:         database,
+		postgres:         postgresDB,
+		logger:           logger,
+	}, nil
+}
+
+// Close waits for queued webhook deliveries and request audit entries to be written, giving up when ctx is done,
+// then closes the PostgreSQL connections
+func (s *Services) Close(ctx context.Context) error {
+	return errors.Join(s.webhooks.Close(ctx), s.requestAudit.Close(ctx), s.closePostgres(ctx))
+}
+
+// closePostgres closes the PostgreSQL connections, if tasks and users are kept there
+func (s *Services) closePostgres(ctx context.Context) error {
+	if s.postgres == nil {
+		return nil
+	}
+	return s.postgres.Close()
+}
+
+// EndStreams closes the open task event streams, which would otherwise keep the server from shutting down
+func (s *Services) EndStreams() {
+	s.taskEvents.Close()
+}
+
+// RunReminders sends due-date reminders every interval until ctx is done. When several instances
+// share a database, the lock makes sure only one of them sends reminders at a time.
+func (s *Services) RunReminders(ctx context.Context, interval time.Duration) {
+	scheduler := Infrastructure.NewScheduler("task_reminders", interval, s.repos.locks, func(ctx context.Context) error {
+		sent, err := s.Reminders.SendReminders(ctx, time.Now())
+		if sent > 0 {
+			s.logger.InfoContext(ctx, "sent task reminders", "count", sent)
+		}
+		return err
+	}, s.logger)
+	scheduler.Run(ctx)
+}
@@ -1,80 +1,1004 @@
 package Domain
 
 import (
+	"context"
+	"encoding/json"
+	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Task represents a task in the task management system
 type Task struct {
+	ID                 primitive.ObjectID  `json:"id" bson:"_id,omitempty"`
+	Title              string              `json:"title" bson:"title"`
+	Description        string              `json:"description" bson:"description"`
+	DueDate            time.Time           `json:"due_date" bson:"due_date"`
+	Status             string              `json:"status" bson:"status"`
+	Priority           string              `json:"priority" bson:"priority,omitempty"`
+	Tags               []string            `json:"tags,omitempty" bson:"tags,omitempty"`
+	EstimateMinutes    *int                `json:"estimate_minutes,omitempty" bson:"estimate_minutes,omitempty"`
+	CreatedAt          time.Time           `json:"created_at" bson:"created_at"`
+	UpdatedAt          time.Time           `json:"updated_at" bson:"updated_at"`
+	Version            int                 `json:"version" bson:"version"` // Incremented on every update; missing on old documents, which read as 0
+	CreatedBy          primitive.ObjectID  `json:"created_by" bson:"created_by,omitempty"`
+	AssigneeID         *primitive.ObjectID `json:"assignee_id,omitempty" bson:"assignee_id,omitempty"`
+	ParentTaskID       *primitive.ObjectID `json:"parent_task_id,omitempty" bson:"parent_task_id,omitempty"`
+	Recurrence         string              `json:"recurrence" bson:"recurrence,omitempty"`
+	NextOccurrenceID   *primitive.ObjectID `json:"next_occurrence_id,omitempty" bson:"next_occurrence_id,omitempty"` // Set once the next occurrence has been spawned
+	Subtasks           []*Task             `json:"subtasks,omitempty" bson:"-"`                                      // Only populated on request
+	DaysOverdue        *int                `json:"days_overdue,omitempty" bson:"-"`                                  // Only populated by the overdue listing
+	Score              *float64            `json:"score,omitempty" bson:"-"`                                         // Relevance to the query; only populated by search
+	TotalLoggedMinutes *int                `json:"total_logged_minutes,omitempty" bson:"-"`                          // Time logged on the task; only populated when a single task is fetched
+	Archived           bool                `json:"archived" bson:"archived,omitempty"`                               // Archived tasks are hidden from listings without being deleted
+	DeletedAt          *time.Time          `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`                 // Set when the task is soft deleted
+	LastNotifiedAt     *time.Time          `json:"last_notified_at,omitempty" bson:"last_notified_at,omitempty"`     // Set when a due-date reminder was sent
+	TitleKey           string              `json:"-" bson:"title_key,omitempty"`                                     // NormalizeTitle(Title), kept by the repository for duplicate checks
+	Links              *Links              `json:"links,omitempty" bson:"-"`                                         // Only populated on creation
+}
+
+// Links holds the URIs of a resource, relative to the server root
+type Links struct {
+	Self string `json:"self"`
+}
+
+// NormalizeTitle folds a title for duplicate checks: case is ignored and runs of whitespace,
+// including leading and trailing whitespace, count as a single space
+func NormalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
+
+// ChangedFields lists, by JSON name, the editable fields whose values differ from before
+func (t *Task) ChangedFields(before *Task) []string {
+	var fields []string
+	if t.Title != before.Title {
+		fields = append(fields, "title")
+	}
+	if t.Description != before.Description {
+		fields = append(fields, "description")
+	}
+	if !t.DueDate.Equal(before.DueDate) {
+		fields = append(fields, "due_date")
+	}
+	if t.Status != before.Status {
+		fields = append(fields, "status")
+	}
+	if t.Priority != before.Priority {
+		fields = append(fields, "priority")
+	}
+	if !sameObjectID(t.AssigneeID, before.AssigneeID) {
+		fields = append(fields, "assignee_id")
+	}
+	if !sameTags(t.Tags, before.Tags) {
+		fields = append(fields, "tags")
+	}
+	if t.Recurrence != before.Recurrence {
+		fields = append(fields, "recurrence")
+	}
+	if !sameInt(t.EstimateMinutes, before.EstimateMinutes) {
+		fields = append(fields, "estimate_minutes")
+	}
+	return fields
+}
+
+// PreviousValues returns before's values for the fields changed since, keyed by JSON name.
+// It returns nil when nothing changed.
+func (t *Task) PreviousValues(before *Task) map[string]interface{} {
+	fields := t.ChangedFields(before)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	values := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		switch field {
+		case "title":
+			values[field] = before.Title
+		case "description":
+			values[field] = before.Description
+		case "due_date":
+			values[field] = before.DueDate
+		case "status":
+			values[field] = before.Status
+		case "priority":
+			values[field] = before.Priority
+		case "assignee_id":
+			values[field] = before.AssigneeID
+		case "tags":
+			values[field] = before.Tags
+		case "recurrence":
+			values[field] = before.Recurrence
+		case "estimate_minutes":
+			values[field] = before.EstimateMinutes
+		}
+	}
+	return values
+}
+
+// sameObjectID compares optional ObjectIDs, treating two nils as equal
+func sameObjectID(a, b *primitive.ObjectID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameInt compares optional integers, treating two nils as equal
+func sameInt(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// sameTags compares tag lists in order, treating nil and empty as equal
+func sameTags(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// IsAssignedTo reports whether the task is assigned to the user with the given hex ID
+func (t *Task) IsAssignedTo(userID string) bool {
+	return t.AssigneeID != nil && t.AssigneeID.Hex() == userID
+}
+
+// OwnerID returns the user responsible for the task: its assignee, or its creator while unassigned
+func (t *Task) OwnerID() primitive.ObjectID {
+	if t.AssigneeID != nil {
+		return *t.AssigneeID
+	}
+	return t.CreatedBy
+}
+
+// ApplyDefaults fills in fields missing from documents stored before they existed
+// and normalizes the due date to UTC
+func (t *Task) ApplyDefaults() {
+	if t.Priority == "" {
+		t.Priority = PriorityMedium
+	}
+	if t.Recurrence == "" {
+		t.Recurrence = RecurrenceNone
+	}
+	// The driver decodes dates in local time; due dates are always echoed in UTC
+	t.DueDate = t.DueDate.UTC()
+}
+
+// Select returns the task as a JSON object holding only the named fields. A selected field
+// that is unset and normally omitted, such as assignee_id, is omitted here too.
+func (t *Task) Select(fields []string) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return nil, err
+	}
+
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	selected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		if value, ok := all[field]; ok {
+			selected[field] = value
+		}
+	}
+	return selected, nil
+}
+
+// IsRecurring reports whether completing the task spawns a new occurrence
+func (t *Task) IsRecurring() bool {
+	return t.Recurrence != "" && t.Recurrence != RecurrenceNone
+}
+
+// NextOccurrence returns a pending copy of the task due one recurrence interval later
+func (t *Task) NextOccurrence() *Task {
+	return &Task{
+		Title:           t.Title,
+		Description:     t.Description,
+		DueDate:         NextDueDate(t.DueDate, t.Recurrence),
+		Status:          StatusPending,
+		Priority:        t.Priority,
+		Tags:            t.Tags,
+		EstimateMinutes: t.EstimateMinutes,
+		CreatedBy:       t.CreatedBy,
+		AssigneeID:      t.AssigneeID,
+		ParentTaskID:    t.ParentTaskID,
+		Recurrence:      t.Recurrence,
+	}
+}
+
+// SetDaysOverdue records how many whole days past its due date the task is at now
+func (t *Task) SetDaysOverdue(now time.Time) {
+	days := int(now.Sub(t.DueDate).Hours() / 24)
+	t.DaysOverdue = &days
+}
+
+// IsSubtask reports whether the task belongs to a parent task
+func (t *Task) IsSubtask() bool {
+	return t.ParentTaskID != nil
+}
+
+// IsDeleted reports whether the task has been soft deleted
+func (t *Task) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
+
+// IsVisibleTo reports whether the caller may read the task.
+// Admins and managers see every task; regular users only tasks they created or are assigned to.
+func (t *Task) IsVisibleTo(caller Caller) bool {
+	return caller.Can(PermissionTasksReadAll) || t.CreatedBy.Hex() == caller.UserID || t.IsAssignedTo(caller.UserID)
+}
+
+// Comment represents a note left on a task
+type Comment struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID `json:"task_id" bson:"task_id"`
+	AuthorID  primitive.ObjectID `json:"author_id" bson:"author_id"`
+	Body      string             `json:"body" bson:"body"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	DeletedAt *time.Time         `json:"-" bson:"deleted_at,omitempty"` // Set while the parent task is soft deleted
+}
+
+// WorkLog records time a user spent on a task on one day
+type WorkLog struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID `json:"task_id" bson:"task_id"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Minutes   int                `json:"minutes" bson:"minutes"`
+	Note      string             `json:"note,omitempty" bson:"note,omitempty"`
+	Date      time.Time          `json:"date" bson:"date"` // Midnight UTC of the day the work was done
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	DeletedAt *time.Time         `json:"-" bson:"deleted_at,omitempty"` // Set while the parent task is soft deleted
+}
+
+// WorkLogSummary totals the time one user logged
+type WorkLogSummary struct {
+	UserID       primitive.ObjectID `json:"user_id" bson:"_id"`
+	TotalMinutes int64              `json:"total_minutes" bson:"total_minutes"`
+	Entries      int64              `json:"entries" bson:"entries"`
+}
+
+// WorkLogFilter narrows the work logs a summary covers; zero values do not filter
+type WorkLogFilter struct {
+	UserID primitive.ObjectID
+	From   time.Time // Earliest day included
+	To     time.Time // Latest day included
+}
+
+// Attachment describes a file uploaded to a task; the bytes live in blob storage under StorageKey
+type Attachment struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Title       string             `json:"title" bson:"title"`
-	Description string             `json:"description" bson:"description"`
-	DueDate     time.Time          `json:"due_date" bson:"due_date"`
-	Status      string             `json:"status" bson:"status"`
+	TaskID      primitive.ObjectID `json:"task_id" bson:"task_id"`
+	Filename    string             `json:"filename" bson:"filename"`
+	ContentType string             `json:"content_type" bson:"content_type"` // Detected from the content, not taken from the client
+	Size        int64              `json:"size" bson:"size"`                 // In bytes
+	StorageKey  string             `json:"-" bson:"storage_key"`
+	UploadedBy  primitive.ObjectID `json:"uploaded_by" bson:"uploaded_by"`
 	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+	DeletedAt   *time.Time         `json:"-" bson:"deleted_at,omitempty"` // Set while the parent task is soft deleted
 }
 
 // User represents a user in the task management system
 type User struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Username      string             `json:"username" bson:"username"`
+	UsernameLower string             `json:"-" bson:"username_lower,omitempty"`      // Canonical form used for lookups; missing on records not yet migrated
+	Email         string             `json:"email,omitempty" bson:"email,omitempty"` // Optional; where password reset links are sent
+	EmailVerified bool               `json:"email_verified" bson:"email_verified"`   // Set once the user confirms the current email address
+	Password      string             `json:"-" bson:"password"`                      // Hidden from JSON response
+	Role          string             `json:"role" bson:"role"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
+	AnonymizedAt  *time.Time         `json:"anonymized_at,omitempty" bson:"anonymized_at,omitempty"` // Set when an admin scrubbed the user's personal data
+	LastLoginAt   *time.Time         `json:"last_login_at,omitempty" bson:"last_login_at,omitempty"` // Missing until the user first logs in
+	IsActive      bool               `json:"is_active" bson:"is_active"`                             // Deactivated users cannot log in or use issued tokens
+	FirstAdmin    bool               `json:"-" bson:"first_admin,omitempty"`                         // Set on the one user who became admin by registering first
+	Links         *Links             `json:"links,omitempty" bson:"-"`                               // Only populated on registration
+}
+
+// UserDirectoryEntry is what every signed-in user may see of the other users, enough to pick an assignee
+type UserDirectoryEntry struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id"`
+	Username string             `json:"username" bson:"username"`
+}
+
+// UnmarshalBSON decodes a user, treating records stored before deactivation existed as active
+func (u *User) UnmarshalBSON(data []byte) error {
+	type plainUser User
+	decoded := plainUser{IsActive: true}
+	if err := bson.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*u = User(decoded)
+	return nil
+}
+
+// LoginEvent records one successful login for the user's login history
+type LoginEvent struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	IP        string             `json:"ip" bson:"ip"`
+	UserAgent string             `json:"user_agent,omitempty" bson:"user_agent,omitempty"`
+	Timestamp time.Time          `json:"timestamp" bson:"timestamp"`
+}
+
+// AuditEntry records who changed what. Entries are append-only.
+type AuditEntry struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ActorID   string             `json:"actor_id" bson:"actor_id"`
+	Action    string             `json:"action" bson:"action"`
+	Entity    string             `json:"entity" bson:"entity"`
+	EntityID  string             `json:"entity_id,omitempty" bson:"entity_id,omitempty"` // Empty for bulk actions
+	Timestamp time.Time          `json:"timestamp" bson:"timestamp"`
+	Diff      string             `json:"diff,omitempty" bson:"diff,omitempty"`       // Human-readable summary of the change
+	Request   *AuditRequest      `json:"request,omitempty" bson:"request,omitempty"` // Set on entries tracing an API request
+}
+
+// AuditRequest traces one API request. Sensitive body fields are redacted before it is stored.
+type AuditRequest struct {
+	Method    string `json:"method" bson:"method"`
+	Path      string `json:"path" bson:"path"`
+	Status    int    `json:"status" bson:"status"`
+	LatencyMs int64  `json:"latency_ms" bson:"latency_ms"`
+	RequestID string `json:"request_id,omitempty" bson:"request_id,omitempty"`
+	Body      string `json:"body,omitempty" bson:"body,omitempty"`
+}
+
+// TaskRevision keeps the previous values of the fields one task update changed
+type TaskRevision struct {
+	ID        primitive.ObjectID     `json:"id" bson:"_id,omitempty"`
+	TaskID    primitive.ObjectID     `json:"task_id" bson:"task_id"`
+	EditorID  string                 `json:"editor_id" bson:"editor_id"`
+	Timestamp time.Time              `json:"timestamp" bson:"timestamp"`
+	Previous  map[string]interface{} `json:"previous" bson:"previous"` // Keyed by the task's JSON field names
+}
+
+// RefreshToken is a stored refresh token. Only a hash of the token is kept; every token
+// rotated from the same login shares a FamilyID so a reused token can revoke them all.
+type RefreshToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	FamilyID  string             `json:"family_id" bson:"family_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	RevokedAt *time.Time         `json:"revoked_at,omitempty" bson:"revoked_at,omitempty"` // Set on rotation, logout or reuse
+}
+
+// PasswordResetToken is a stored single-use password reset token. Only a hash of the token is kept.
+type PasswordResetToken struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID    primitive.ObjectID `json:"user_id" bson:"user_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
+	ExpiresAt time.Time          `json:"expires_at" bson:"expires_at"`
+	UsedAt    *time.Time         `json:"used_at,omitempty" bson:"used_at,omitempty"` // Set once the password has been reset
+}
+
+// APIKey lets a machine client authenticate with the X-API-Key header instead of a user's
+// password. Only a hash of the key is kept; the key itself is shown once, at creation.
+type APIKey struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	Name       string             `json:"name" bson:"name"`
+	KeyHash    string             `json:"-" bson:"key_hash"`
+	Role       string             `json:"role" bson:"role"`
+	CreatedBy  primitive.ObjectID `json:"created_by" bson:"created_by"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+	LastUsedAt *time.Time         `json:"last_used_at,omitempty" bson:"last_used_at,omitempty"`
+	Revoked    bool               `json:"revoked" bson:"revoked"`
+}
+
+// APIKeyRequest represents the request payload for creating an API key
+type APIKeyRequest struct {
+	Name string `json:"name" binding:"required"`
+	Role string `json:"role"` // Defaults to user
+}
+
+// CreatedAPIKey is an API key together with its plaintext, which is only available at creation
+type CreatedAPIKey struct {
+	*APIKey
+	Key string `json:"key"`
+}
+
+// Webhook is an HTTP endpoint that is sent the events it subscribes to. Every delivery is signed
+// with the secret, which is never returned after creation.
+type Webhook struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	Username  string             `json:"username" bson:"username"`
-	Password  string             `json:"-" bson:"password"` // Hidden from JSON response
-	Role      string             `json:"role" bson:"role"`
+	URL       string             `json:"url" bson:"url"`
+	Secret    string             `json:"-" bson:"secret"`
+	Events    []string           `json:"events" bson:"events"`
+	Active    bool               `json:"active" bson:"active"`
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
-// TaskRequest represents the request payload for creating/updating tasks
+// WebhookRequest represents the request payload for creating or replacing a webhook.
+// An empty secret is generated on create and left unchanged on update.
+type WebhookRequest struct {
+	URL    string   `json:"url" binding:"required"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events" binding:"required"`
+	Active *bool    `json:"active"` // Defaults to true
+}
+
+// CreatedWebhook is a webhook together with its secret, which is only returned at creation
+type CreatedWebhook struct {
+	*Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookDelivery records the outcome of sending one event to one webhook, after any retries
+type WebhookDelivery struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	WebhookID  primitive.ObjectID `json:"webhook_id" bson:"webhook_id"`
+	EventID    string             `json:"event_id" bson:"event_id"`
+	Event      string             `json:"event" bson:"event"`
+	Attempts   int                `json:"attempts" bson:"attempts"`
+	StatusCode int                `json:"status_code,omitempty" bson:"status_code,omitempty"` // Of the last attempt; missing when no response arrived
+	Error      string             `json:"error,omitempty" bson:"error,omitempty"`             // Why the last attempt failed
+	Delivered  bool               `json:"delivered" bson:"delivered"`
+	CreatedAt  time.Time          `json:"created_at" bson:"created_at"`
+}
+
+// Event is something that happened to a task or user, published to webhook and event stream subscribers.
+// Data is the task or user as the API returns it.
+type Event struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	ActorID    string      `json:"actor_id,omitempty"` // Empty for changes made by the admin commands
+	Data       interface{} `json:"data"`
+}
+
+// NewEvent returns an event of the given type that happened now
+func NewEvent(eventType, actorID string, data interface{}) Event {
+	return Event{
+		ID:         primitive.NewObjectID().Hex(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		ActorID:    actorID,
+		Data:       data,
+	}
+}
+
+// IsTaskEventVisibleTo reports whether the event is about a task the caller can see. Deletions
+// whose owners are unknown, such as those by status, are visible only to callers who can read every task.
+func (e Event) IsTaskEventVisibleTo(caller Caller) bool {
+	switch data := e.Data.(type) {
+	case *Task:
+		return data.IsVisibleTo(caller)
+	case DeletedTaskEvent:
+		if data.Task == nil {
+			return caller.Can(PermissionTasksReadAll)
+		}
+		return data.Task.IsVisibleTo(caller)
+	default:
+		return false
+	}
+}
+
+// DeletedTaskEvent is the data of a task.deleted event. Task is the deleted task when it is known;
+// it is left out of the payload and only decides who may see the event.
+type DeletedTaskEvent struct {
+	ID   string `json:"id"`
+	Task *Task  `json:"-"`
+}
+
+// PromotedUserEvent is the data of a user.promoted event
+type PromotedUserEvent struct {
+	User         *User  `json:"user"`
+	PreviousRole string `json:"previous_role"`
+}
+
+// AuthTokens is the access and refresh token pair issued on login and refresh
+type AuthTokens struct {
+	AccessToken  string
+	RefreshToken string
+	ExpiresIn    int64 // Access token lifetime in seconds
+}
+
+// JSONWebKey is a public signing key in JWK format (RFC 7517)
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// JSONWebKeySet is the body of GET /.well-known/jwks.json
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+// TaskRequest represents the request payload for creating/updating tasks.
+// Omitting tags leaves them unchanged on update, while an empty array clears them.
+// Estimates are in minutes.
 type TaskRequest struct {
-	Title       string `json:"title" binding:"required"`
-	Description string `json:"description"`
-	DueDate     string `json:"due_date"`
-	Status      string `json:"status" binding:"required"`
+	Title           string   `json:"title" binding:"required"`
+	Description     string   `json:"description"`
+	DueDate         string   `json:"due_date"`
+	Status          string   `json:"status" binding:"required"`
+	Priority        string   `json:"priority"`    // Optional; defaults to medium
+	AssigneeID      string   `json:"assignee_id"` // Optional; empty leaves the task unassigned
+	Tags            []string `json:"tags"`
+	ParentTaskID    string   `json:"parent_task_id"`   // Optional; only honoured on create
+	Recurrence      string   `json:"recurrence"`       // Optional; defaults to none
+	EstimateMinutes *int     `json:"estimate_minutes"` // Optional; omitting it leaves the estimate unchanged on update, 0 clears it
+	Version         *int     `json:"version"`          // Optional on update; the version the client last fetched
+}
+
+// TaskPatchRequest represents the request payload for partially updating tasks.
+// Nil fields are left unchanged; an empty due_date, assignee_id or tags array, or a zero estimate_minutes, clears the value.
+type TaskPatchRequest struct {
+	Title           *string  `json:"title"`
+	Description     *string  `json:"description"`
+	DueDate         *string  `json:"due_date"`
+	EstimateMinutes *int     `json:"estimate_minutes"`
+	Status          *string  `json:"status"`
+	Priority        *string  `json:"priority"`
+	AssigneeID      *string  `json:"assignee_id"`
+	Tags            []string `json:"tags"`
+	Recurrence      *string  `json:"recurrence"`
+	Version         *int     `json:"version"` // Optional; the version the client last fetched
+}
+
+// TaskStatusRequest represents the request payload for changing only a task's status
+type TaskStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
+
+// WorkLogRequest represents the request payload for logging time spent on a task
+type WorkLogRequest struct {
+	Minutes int    `json:"minutes" binding:"required"`
+	Note    string `json:"note"`
+	Date    string `json:"date"` // Optional YYYY-MM-DD; defaults to today
+}
+
+// CommentRequest represents the request payload for commenting on a task
+type CommentRequest struct {
+	Body string `json:"body" binding:"required"`
 }
 
 // UserRequest represents the request payload for user registration
 type UserRequest struct {
 	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required,min=6"`
+	Password string `json:"password" binding:"required"`     // Checked against the password policy
+	Email    string `json:"email" binding:"omitempty,email"` // Optional; needed for password reset by email
 }
 
 // LoginRequest represents the request payload for user login
 type LoginRequest struct {
+	Username  string `json:"username" binding:"required"`
+	Password  string `json:"password" binding:"required"`
+	IP        string `json:"-"` // Filled in from the request for the login history
+	UserAgent string `json:"-"`
+}
+
+// RefreshRequest represents the request payload for exchanging a refresh token
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
+}
+
+// LogoutRequest represents the optional request payload for logging out
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"` // Optional; also revokes this login's refresh tokens
+}
+
+// ChangePasswordRequest represents the request payload for changing the caller's password
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required"` // Checked against the password policy
+}
+
+// ForgotPasswordRequest represents the request payload for requesting a password reset
+type ForgotPasswordRequest struct {
 	Username string `json:"username" binding:"required"`
-	Password string `json:"password" binding:"required"`
+}
+
+// ResetPasswordRequest represents the request payload for resetting a password with a reset token
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required"` // Checked against the password policy
+}
+
+// ProfileUpdateRequest represents the request payload for updating the caller's own profile.
+// Omitted fields are left unchanged; role and password cannot be changed here.
+type ProfileUpdateRequest struct {
+	Username string `json:"username"`
+	Email    string `json:"email" binding:"omitempty,email"`
+}
+
+// VerifyEmailRequest represents the request payload for confirming an email address
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
 }
 
 // PromoteRequest represents the request payload for promoting users
 type PromoteRequest struct {
 	Username string `json:"username" binding:"required"`
+	Role     string `json:"role"` // manager or admin; admin when omitted
+}
+
+// DemoteRequest represents the request payload for demoting admins
+type DemoteRequest struct {
+	Username string `json:"username" binding:"required"`
+}
+
+// Pagination represents the limit/offset window and ordering applied to task listings.
+// A zero Limit means no limit and an empty Sort orders by creation, so the zero
+// value returns every task oldest first.
+type Pagination struct {
+	Limit  int64
+	Offset int64
+	Sort   string
+	Fields []string // JSON names of the task fields to load; empty loads them all
+}
+
+// TaskFilter narrows task listings. Zero-valued fields are ignored and the
+// remaining conditions are combined with AND; due date bounds are inclusive.
+// Soft-deleted tasks are excluded unless IncludeDeleted or OnlyDeleted is set, and
+// archived tasks unless IncludeArchived or OnlyArchived is set.
+type TaskFilter struct {
+	Status          string
+	Priority        string
+	Tag             string
+	DueBefore       time.Time
+	DueAfter        time.Time
+	CreatedBy       primitive.ObjectID
+	AssigneeID      primitive.ObjectID
+	IncludeDeleted  bool
+	OnlyDeleted     bool
+	IncludeArchived bool
+	OnlyArchived    bool
+	OverdueAt       time.Time // Only tasks due before this time that are not completed
+	OpenOnly        bool      // Only tasks that are not completed
+}
+
+// AuditFilter narrows audit log listings; empty fields are ignored
+type AuditFilter struct {
+	ActorID string
+	Entity  string
+}
+
+// UserFilter narrows user listings; empty fields are ignored
+type UserFilter struct {
+	Query string // Case-insensitive substring of the username or email
+	Role  string
+}
+
+// PaginationMeta describes a paginated result so clients can render page controls.
+// Page is only set by listings that are paged by page number.
+type PaginationMeta struct {
+	Total  int64 `json:"total"`
+	Limit  int64 `json:"limit"`
+	Offset int64 `json:"offset"`
+	Page   int64 `json:"page,omitempty"`
+}
+
+// TaskImport is one entry of a task import: a create request that may keep its original creation time
+type TaskImport struct {
+	TaskRequest
+	CreatedAt string `json:"created_at"` // Optional RFC3339 timestamp; defaults to the import time
+}
+
+// ImportResult summarizes a task import. Every row that was not imported is counted
+// as skipped and listed in Errors with the reason.
+type ImportResult struct {
+	Imported int             `json:"imported"`
+	Skipped  int             `json:"skipped"`
+	Created  []string        `json:"created"`
+	Errors   []BulkItemError `json:"errors"`
+}
+
+// ImportResponse represents the result of a task import
+type ImportResponse struct {
+	Success   bool          `json:"success"`
+	Message   string        `json:"message"`
+	MessageID string        `json:"message_id"`
+	Data      *ImportResult `json:"data"`
+}
+
+// BulkItemError reports why the item at Index of a bulk request was rejected
+type BulkItemError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+// BulkCreateResult lists the ids of the created tasks and the rejected items
+type BulkCreateResult struct {
+	Created []string        `json:"created"`
+	Errors  []BulkItemError `json:"errors"`
+}
+
+// BulkCreateResponse represents the result of a bulk task creation
+type BulkCreateResponse struct {
+	Success   bool              `json:"success"`
+	Message   string            `json:"message"`
+	MessageID string            `json:"message_id"`
+	Data      *BulkCreateResult `json:"data"`
+}
+
+// BulkStatusRequest represents the request payload for changing the status of several tasks
+type BulkStatusRequest struct {
+	IDs    []string `json:"ids"`
+	Status string   `json:"status" binding:"required"`
+}
+
+// BulkResult reports how many tasks a bulk update matched and changed
+type BulkResult struct {
+	MatchedCount  int64 `json:"matched_count"`
+	ModifiedCount int64 `json:"modified_count"`
+}
+
+// TaskStatusChange is a task changed by a bulk status update, as saved, with the status it had before
+type TaskStatusChange struct {
+	Task       *Task
+	FromStatus string
+}
+
+// UserDeletionResult reports how a user was removed and what happened to their tasks
+type UserDeletionResult struct {
+	Anonymized      bool  `json:"anonymized"`
+	TasksReassigned int64 `json:"tasks_reassigned"` // Tasks the user created, now owned by the acting admin
+	TasksUnassigned int64 `json:"tasks_unassigned"` // Tasks that were assigned to the user and now have no assignee
+}
+
+// BulkResponse represents the result of a bulk status change or bulk delete
+type BulkResponse struct {
+	Success   bool        `json:"success"`
+	Message   string      `json:"message"`
+	MessageID string      `json:"message_id"`
+	Data      *BulkResult `json:"data"`
+}
+
+// StatsCreatedDays is the number of days covered by TaskStats.CreatedPerDay, ending today
+const StatsCreatedDays = 30
+
+// DailyCount is the number of tasks for a single UTC day
+type DailyCount struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int64  `json:"count"`
+}
+
+// TaskStats summarizes the tasks visible to a caller.
+// Overdue and due-soon counts leave out completed tasks and tasks without a due date.
+type TaskStats struct {
+	ByStatus      map[string]int64 `json:"by_status"`
+	Overdue       int64            `json:"overdue"`
+	DueNext7Days  int64            `json:"due_next_7_days"`
+	CreatedPerDay []DailyCount     `json:"created_per_day"`
+}
+
+// UserTaskReport counts one user's active tasks by status. A task belongs to its assignee, or to
+// its creator while unassigned. Overdue counts open tasks past their due date and Open is
+// Pending plus InProgress.
+type UserTaskReport struct {
+	UserID     primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Username   string             `json:"username" bson:"username"`
+	Pending    int64              `json:"pending" bson:"pending"`
+	InProgress int64              `json:"in_progress" bson:"in_progress"`
+	Completed  int64              `json:"completed" bson:"completed"`
+	Overdue    int64              `json:"overdue" bson:"overdue"`
+	Open       int64              `json:"open" bson:"open"`
+}
+
+// CountResponse represents the number of tasks matching a filter
+type CountResponse struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	MessageID string `json:"message_id"`
+	Count     int64  `json:"count"`
+}
+
+// Health statuses reported by the liveness and readiness endpoints
+const (
+	HealthStatusOK          = "OK"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// HealthResponse represents the result of a liveness or readiness check
+type HealthResponse struct {
+	Status        string `json:"status"`
+	Message       string `json:"message"`
+	Version       string `json:"version"`
+	Commit        string `json:"commit"`
+	BuildTime     string `json:"build_time"`
+	Uptime        string `json:"uptime"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	Error         string `json:"error,omitempty"`
+}
+
+// VersionInfo identifies the build of the server that is running
+type VersionInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+// PurgeResponse represents the result of permanently removing soft-deleted tasks
+type PurgeResponse struct {
+	Success       bool   `json:"success"`
+	Message       string `json:"message"`
+	MessageID     string `json:"message_id"`
+	PurgedCount   int64  `json:"purged_count"`
+	OlderThanDays int    `json:"older_than_days"`
 }
 
 // Response types
-type TaskResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+
+// Response is the envelope of a successful response carrying a single value
+type Response[T any] struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	MessageID string `json:"message_id"`
+	Data      T      `json:"data"`
+}
+
+// NewResponse wraps data in a successful Response
+func NewResponse[T any](message Message, data T) Response[T] {
+	return Response[T]{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      data,
+	}
+}
+
+// ListResponse is the envelope of every list endpoint. Data is always an array, never null,
+// and Meta always describes the page it holds; unpaged lists report only their total.
+type ListResponse[T any] struct {
+	Success   bool           `json:"success"`
+	Message   string         `json:"message"`
+	MessageID string         `json:"message_id"`
+	Data      []T            `json:"data"`
+	Meta      PaginationMeta `json:"meta"`
+}
+
+// NewListResponse wraps a page of items in a successful ListResponse
+func NewListResponse[T any](message Message, items []T, meta PaginationMeta) ListResponse[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return ListResponse[T]{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Data:      items,
+		Meta:      meta,
+	}
+}
+
+// MessageResponse is the envelope of a successful response that has no data, such as a deletion
+type MessageResponse struct {
+	Success   bool   `json:"success"`
+	Message   string `json:"message"`
+	MessageID string `json:"message_id"`
 }
 
-type UserResponse struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+// NewMessageResponse builds a successful MessageResponse
+func NewMessageResponse(message Message) MessageResponse {
+	return MessageResponse{
+		Success:   true,
+		Message:   message.Text,
+		MessageID: message.ID,
+	}
 }
 
 type LoginResponse struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-	Token   string `json:"token,omitempty"`
-	User    *User  `json:"user,omitempty"`
+	Success      bool   `json:"success"`
+	Message      string `json:"message"`
+	MessageID    string `json:"message_id"`
+	Token        string `json:"token,omitempty"` // Access token
+	RefreshToken string `json:"refresh_token,omitempty"`
+	ExpiresIn    int64  `json:"expires_in,omitempty"` // Access token lifetime in seconds
+	User         *User  `json:"user,omitempty"`
 }
 
 type ErrorResponse struct {
-	Success bool   `json:"success"`
+	Success    bool                    `json:"success"`
+	Message    string                  `json:"message"`
+	MessageID  string                  `json:"message_id,omitempty"` // Stable ID of Message, set when it comes from the catalog
+	Error      string                  `json:"error,omitempty"`
+	Violations []PasswordRuleViolation `json:"violations,omitempty"` // Failed password policy rules
+	Errors     []FieldError            `json:"errors,omitempty"`     // Invalid fields in the request body
+	TaskID     string                  `json:"task_id,omitempty"`    // The existing task a rejected create would duplicate
+	RequestID  string                  `json:"request_id,omitempty"`
+}
+
+// FieldError describes a request body field that failed validation, named as in JSON
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"` // The failed rule, such as required or email
+	Message string `json:"message"`
+}
+
+// Password policy rules
+const (
+	PasswordRuleMinLength = "min_length"
+	PasswordRuleLetter    = "letter"
+	PasswordRuleLowercase = "lowercase"
+	PasswordRuleUppercase = "uppercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRuleCommon    = "common"
+)
+
+// PasswordRuleViolation names a password policy rule a password failed, with a message for the user
+type PasswordRuleViolation struct {
+	Rule    string `json:"rule"`
 	Message string `json:"message"`
-	Error   string `json:"error,omitempty"`
+}
+
+// PasswordPolicyError is returned for a password that fails the password policy. It lists every failed rule.
+type PasswordPolicyError struct {
+	Violations []PasswordRuleViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Message
+	}
+	return "password " + strings.Join(messages, ", ")
+}
+
+// DuplicateTaskError is returned when a new task would repeat an open task of the same creator.
+// It matches ErrDuplicateTask with errors.Is.
+type DuplicateTaskError struct {
+	TaskID primitive.ObjectID // The existing task
+}
+
+func (e *DuplicateTaskError) Error() string {
+	return ErrDuplicateTask.Error() + ": " + e.TaskID.Hex()
+}
+
+func (e *DuplicateTaskError) Unwrap() error { return ErrDuplicateTask }
+
+// RequestIDHeader carries the request ID on both requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDContextKey struct{}
+
+// WithRequestID returns a copy of ctx that carries the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDContextKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if there is none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
+	return requestID
+}
+
+// Caller identifies the authenticated user on whose behalf a usecase runs. For requests made
+// with an API key, UserID is the key's ID and APIKey is set.
+type Caller struct {
+	UserID string
+	Role   string
+	APIKey bool
+}
+
+// Can reports whether the caller's role grants the permission
+func (c Caller) Can(permission string) bool {
+	return HasPermission(c.Role, permission)
+}
+
+// EmailVerificationClaims are the claims of a valid email verification token
+type EmailVerificationClaims struct {
+	UserID string
+	Email  string
 }
 
 // JWTClaims represents the JWT token claims
@@ -86,10 +1010,125 @@ type JWTClaims struct {
 
 // User roles constants
 const (
-	RoleAdmin = "admin"
-	RoleUser  = "user"
+	RoleAdmin   = "admin"
+	RoleManager = "manager"
+	RoleUser    = "user"
 )
 
+// Permissions granted by roles
+const (
+	PermissionTasksRead    = "tasks:read"     // read, comment on, attach files to and change the status of own and assigned tasks
+	PermissionTasksReadAll = "tasks:read_all" // read every task, not only own and assigned ones
+	PermissionTasksWrite   = "tasks:write"    // create, edit, archive, delete and restore tasks
+	PermissionUsersManage  = "users:manage"   // list, promote, demote, deactivate and delete users; manage API keys
+	PermissionAuditRead    = "audit:read"     // read the audit log
+)
+
+// rolePermissions maps each built-in role to the permissions it grants
+var rolePermissions = map[string][]string{
+	RoleUser:    {PermissionTasksRead},
+	RoleManager: {PermissionTasksRead, PermissionTasksReadAll, PermissionTasksWrite},
+	RoleAdmin:   {PermissionTasksRead, PermissionTasksReadAll, PermissionTasksWrite, PermissionUsersManage, PermissionAuditRead},
+}
+
+// HasPermission reports whether the role grants the permission.
+// Unknown roles, such as one removed since a token was issued, grant nothing.
+func HasPermission(role, permission string) bool {
+	for _, granted := range rolePermissions[role] {
+		if granted == permission {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidRole checks if the provided role is one of the built-in roles
+func IsValidRole(role string) bool {
+	_, ok := rolePermissions[role]
+	return ok
+}
+
+// Username length limits, counted in characters after trimming
+const (
+	MinUsernameLength = 3
+	MaxUsernameLength = 32
+)
+
+// AnonymizedUsername is the placeholder username an anonymized user keeps, unique through the user ID
+func AnonymizedUsername(userID string) string {
+	return "deleted-user-" + userID
+}
+
+// NormalizeEmail returns the canonical form email addresses are stored and compared in
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizeUsername returns the canonical form usernames are looked up and compared in,
+// so "Alice" and " alice" name the same account
+func NormalizeUsername(username string) string {
+	return strings.ToLower(strings.TrimSpace(username))
+}
+
+// Audit actions
+const (
+	AuditActionCreate         = "create"
+	AuditActionUpdate         = "update"
+	AuditActionDelete         = "delete"
+	AuditActionBulkUpdate     = "bulk_update"
+	AuditActionBulkDelete     = "bulk_delete"
+	AuditActionArchive        = "archive"
+	AuditActionUnarchive      = "unarchive"
+	AuditActionRestore        = "restore"
+	AuditActionBulkArchive    = "bulk_archive"
+	AuditActionPromote        = "promote"
+	AuditActionDemote         = "demote"
+	AuditActionChangePassword = "change_password"
+	AuditActionResetPassword  = "reset_password"
+	AuditActionUpdateProfile  = "update_profile"
+	AuditActionAnonymize      = "anonymize"
+	AuditActionDeactivate     = "deactivate"
+	AuditActionActivate       = "activate"
+	AuditActionRevoke         = "revoke"
+	AuditActionRequest        = "request"
+)
+
+// Audited entities
+const (
+	AuditEntityTask    = "task"
+	AuditEntityUser    = "user"
+	AuditEntityAPIKey  = "api_key"
+	AuditEntityWebhook = "webhook"
+	AuditEntityAPI     = "api"
+)
+
+// IsValidAuditEntity checks if the provided entity is one the audit log records
+func IsValidAuditEntity(entity string) bool {
+	return entity == AuditEntityTask || entity == AuditEntityUser || entity == AuditEntityAPIKey || entity == AuditEntityWebhook || entity == AuditEntityAPI
+}
+
+// Event types webhooks can subscribe to
+const (
+	EventTaskCreated    = "task.created"
+	EventTaskUpdated    = "task.updated"
+	EventTaskDeleted    = "task.deleted"
+	EventUserRegistered = "user.registered"
+	EventUserPromoted   = "user.promoted"
+)
+
+// EventTypes lists every event type, in the order they are documented
+var EventTypes = []string{EventTaskCreated, EventTaskUpdated, EventTaskDeleted, EventUserRegistered, EventUserPromoted}
+
+// IsValidEventType checks if the provided event type is one webhooks can subscribe to
+func IsValidEventType(eventType string) bool {
+	for _, validType := range EventTypes {
+		if eventType == validType {
+			return true
+		}
+	}
+	return false
+}
+
 // Task status constants
 const (
 	StatusPending    = "pending"
@@ -106,4 +1145,151 @@ func IsValidStatus(status string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// statusTransitions lists the statuses a task may move to from each status
+var statusTransitions = map[string][]string{
+	StatusPending:    {StatusInProgress},
+	StatusInProgress: {StatusCompleted, StatusPending},
+}
+
+// CanTransitionStatus reports whether a task may move from one status to another.
+// Keeping the current status is always allowed.
+func CanTransitionStatus(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range statusTransitions[from] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusesTransitioningTo returns the statuses from which a task may move to the given status,
+// including the status itself
+func StatusesTransitioningTo(to string) []string {
+	var statuses []string
+	for _, from := range []string{StatusPending, StatusInProgress, StatusCompleted} {
+		if CanTransitionStatus(from, to) {
+			statuses = append(statuses, from)
+		}
+	}
+	return statuses
+}
+
+// IsValidInitialStatus checks if a task may be created with the provided status
+func IsValidInitialStatus(status string) bool {
+	return status == StatusPending || status == StatusInProgress
+}
+
+// Task priority constants
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
+// IsValidPriority checks if the provided priority is valid
+func IsValidPriority(priority string) bool {
+	validPriorities := []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityUrgent}
+	for _, validPriority := range validPriorities {
+		if priority == validPriority {
+			return true
+		}
+	}
+	return false
+}
+
+// Task recurrence constants
+const (
+	RecurrenceNone    = "none"
+	RecurrenceDaily   = "daily"
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// IsValidRecurrence checks if the provided recurrence is valid
+func IsValidRecurrence(recurrence string) bool {
+	validRecurrences := []string{RecurrenceNone, RecurrenceDaily, RecurrenceWeekly, RecurrenceMonthly}
+	for _, validRecurrence := range validRecurrences {
+		if recurrence == validRecurrence {
+			return true
+		}
+	}
+	return false
+}
+
+// NextDueDate advances a due date by one recurrence interval.
+// Monthly recurrences clamp to the last day of a shorter month, so Jan 31 becomes Feb 28 (or 29).
+func NextDueDate(dueDate time.Time, recurrence string) time.Time {
+	switch recurrence {
+	case RecurrenceDaily:
+		return dueDate.AddDate(0, 0, 1)
+	case RecurrenceWeekly:
+		return dueDate.AddDate(0, 0, 7)
+	case RecurrenceMonthly:
+		year, month, day := dueDate.Date()
+		hour, minute, sec := dueDate.Clock()
+		// Day 0 of the month after next is the last day of next month
+		lastDay := time.Date(year, month+2, 0, 0, 0, 0, 0, dueDate.Location()).Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return time.Date(year, month+1, day, hour, minute, sec, dueDate.Nanosecond(), dueDate.Location())
+	}
+	return dueDate
+}
+
+// Task listing sort orders
+const (
+	SortPriority = "priority" // Most urgent first
+	SortDueDate  = "due_date" // Earliest due first; used by the overdue listing
+)
+
+// MaxSearchQueryLength is the longest task search query accepted, in characters
+const MaxSearchQueryLength = 200
+
+// TaskFields lists, by JSON name, the task fields a client may select with ?fields=.
+// Subtasks, days_overdue, score and total_logged_minutes are left out because they are never stored.
+var TaskFields = []string{
+	"id", "title", "description", "due_date", "status", "priority", "tags", "created_at", "updated_at",
+	"version", "created_by", "assignee_id", "parent_task_id", "recurrence", "next_occurrence_id",
+	"estimate_minutes", "archived", "deleted_at", "last_notified_at",
+}
+
+// IsValidTaskField reports whether name is one of TaskFields
+func IsValidTaskField(name string) bool {
+	for _, field := range TaskFields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTaskFields reads a comma-separated field selection such as "id,title,status".
+// Repeated names are kept once, in the order first given.
+func ParseTaskFields(value string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if !IsValidTaskField(name) {
+			return nil, ErrInvalidTaskFields
+		}
+		if !seen[name] {
+			seen[name] = true
+			fields = append(fields, name)
+		}
+	}
+	return fields, nil
+}
+
+// User listing sort orders
+const (
+	SortCreatedAt = "created_at" // Oldest account first; the default
+	SortUsername  = "username"   // Alphabetical, ignoring case
+)
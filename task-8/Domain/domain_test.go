@@ -1,13 +1,28 @@
 package Domain
 
 import (
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+func TestNormalizeUsername(t *testing.T) {
+	assert.Equal(t, "alice", NormalizeUsername("Alice"))
+	assert.Equal(t, "alice", NormalizeUsername("  ALICE\t"))
+	assert.Equal(t, "émile", NormalizeUsername("Émile"))
+	assert.Equal(t, "", NormalizeUsername("   "))
+}
+
+func TestNormalizeTitle(t *testing.T) {
+	assert.Equal(t, "deploy release", NormalizeTitle("Deploy release"))
+	assert.Equal(t, "deploy release", NormalizeTitle("  DEPLOY \t release\n"))
+	assert.Equal(t, "", NormalizeTitle("   "))
+}
+
 func TestIsValidStatus(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -54,6 +69,174 @@ func TestIsValidStatus(t *testing.T) {
 	}
 }
 
+func TestCanTransitionStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected bool
+	}{
+		{name: "Pending to in progress", from: StatusPending, to: StatusInProgress, expected: true},
+		{name: "In progress to completed", from: StatusInProgress, to: StatusCompleted, expected: true},
+		{name: "In progress back to pending", from: StatusInProgress, to: StatusPending, expected: true},
+		{name: "Unchanged status", from: StatusCompleted, to: StatusCompleted, expected: true},
+		{name: "Pending straight to completed", from: StatusPending, to: StatusCompleted, expected: false},
+		{name: "Completed back to pending", from: StatusCompleted, to: StatusPending, expected: false},
+		{name: "Completed back to in progress", from: StatusCompleted, to: StatusInProgress, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := CanTransitionStatus(tt.from, tt.to)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestStatusesTransitioningTo(t *testing.T) {
+	assert.Equal(t, []string{StatusPending, StatusInProgress}, StatusesTransitioningTo(StatusPending))
+	assert.Equal(t, []string{StatusPending, StatusInProgress}, StatusesTransitioningTo(StatusInProgress))
+	assert.Equal(t, []string{StatusInProgress, StatusCompleted}, StatusesTransitioningTo(StatusCompleted))
+}
+
+func TestIsValidInitialStatus(t *testing.T) {
+	assert.True(t, IsValidInitialStatus(StatusPending))
+	assert.True(t, IsValidInitialStatus(StatusInProgress))
+	assert.False(t, IsValidInitialStatus(StatusCompleted))
+}
+
+func TestIsValidPriority(t *testing.T) {
+	tests := []struct {
+		name     string
+		priority string
+		expected bool
+	}{
+		{name: "Valid priority - low", priority: PriorityLow, expected: true},
+		{name: "Valid priority - medium", priority: PriorityMedium, expected: true},
+		{name: "Valid priority - high", priority: PriorityHigh, expected: true},
+		{name: "Valid priority - urgent", priority: PriorityUrgent, expected: true},
+		{name: "Invalid priority - empty string", priority: "", expected: false},
+		{name: "Invalid priority - wrong case", priority: "HIGH", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsValidPriority(tt.priority)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestIsValidRecurrence(t *testing.T) {
+	tests := []struct {
+		name       string
+		recurrence string
+		expected   bool
+	}{
+		{name: "Valid recurrence - none", recurrence: RecurrenceNone, expected: true},
+		{name: "Valid recurrence - daily", recurrence: RecurrenceDaily, expected: true},
+		{name: "Valid recurrence - weekly", recurrence: RecurrenceWeekly, expected: true},
+		{name: "Valid recurrence - monthly", recurrence: RecurrenceMonthly, expected: true},
+		{name: "Invalid recurrence - empty string", recurrence: "", expected: false},
+		{name: "Invalid recurrence - yearly", recurrence: "yearly", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsValidRecurrence(tt.recurrence)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestNextDueDate(t *testing.T) {
+	tests := []struct {
+		name       string
+		dueDate    time.Time
+		recurrence string
+		expected   time.Time
+	}{
+		{name: "Daily adds one day", dueDate: time.Date(2024, 2, 28, 0, 0, 0, 0, time.UTC), recurrence: RecurrenceDaily, expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "Weekly adds seven days", dueDate: time.Date(2024, 12, 28, 0, 0, 0, 0, time.UTC), recurrence: RecurrenceWeekly, expected: time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "Monthly keeps the day", dueDate: time.Date(2024, 3, 15, 9, 30, 0, 0, time.UTC), recurrence: RecurrenceMonthly, expected: time.Date(2024, 4, 15, 9, 30, 0, 0, time.UTC)},
+		{name: "Monthly clamps to end of February", dueDate: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), recurrence: RecurrenceMonthly, expected: time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "Monthly clamps to leap day", dueDate: time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC), recurrence: RecurrenceMonthly, expected: time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "Monthly rolls over the year", dueDate: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), recurrence: RecurrenceMonthly, expected: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "None leaves the date unchanged", dueDate: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC), recurrence: RecurrenceNone, expected: time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := NextDueDate(tt.dueDate, tt.recurrence)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestTaskNextOccurrence(t *testing.T) {
+	t.Run("Copies the task as pending with the next due date", func(t *testing.T) {
+		assigneeID := primitive.NewObjectID()
+		task := Task{
+			ID:         primitive.NewObjectID(),
+			Title:      "Standup notes",
+			DueDate:    time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			Status:     StatusCompleted,
+			Priority:   PriorityHigh,
+			Tags:       []string{"team"},
+			CreatedBy:  primitive.NewObjectID(),
+			AssigneeID: &assigneeID,
+			Recurrence: RecurrenceWeekly,
+		}
+
+		next := task.NextOccurrence()
+
+		assert.True(t, task.IsRecurring())
+		assert.True(t, next.ID.IsZero())
+		assert.Equal(t, StatusPending, next.Status)
+		assert.Equal(t, time.Date(2024, 3, 22, 0, 0, 0, 0, time.UTC), next.DueDate)
+		assert.Equal(t, task.Title, next.Title)
+		assert.Equal(t, task.Priority, next.Priority)
+		assert.Equal(t, task.Tags, next.Tags)
+		assert.Equal(t, task.CreatedBy, next.CreatedBy)
+		assert.Equal(t, task.AssigneeID, next.AssigneeID)
+		assert.Equal(t, RecurrenceWeekly, next.Recurrence)
+		assert.Nil(t, next.NextOccurrenceID)
+	})
+
+	t.Run("Tasks without recurrence do not recur", func(t *testing.T) {
+		assert.False(t, (&Task{}).IsRecurring())
+		assert.False(t, (&Task{Recurrence: RecurrenceNone}).IsRecurring())
+	})
+}
+
+func TestTaskApplyDefaults(t *testing.T) {
+	t.Run("Missing priority reads back as medium", func(t *testing.T) {
+		task := Task{}
+		task.ApplyDefaults()
+		assert.Equal(t, PriorityMedium, task.Priority)
+	})
+
+	t.Run("Stored priority is kept", func(t *testing.T) {
+		task := Task{Priority: PriorityUrgent}
+		task.ApplyDefaults()
+		assert.Equal(t, PriorityUrgent, task.Priority)
+	})
+
+	t.Run("Missing recurrence reads back as none", func(t *testing.T) {
+		task := Task{}
+		task.ApplyDefaults()
+		assert.Equal(t, RecurrenceNone, task.Recurrence)
+	})
+
+	t.Run("Due date is normalized to UTC", func(t *testing.T) {
+		eat := time.FixedZone("EAT", 3*60*60)
+		task := Task{DueDate: time.Date(2024, 12, 31, 17, 0, 0, 0, eat)}
+		task.ApplyDefaults()
+		assert.Equal(t, time.UTC, task.DueDate.Location())
+		assert.Equal(t, time.Date(2024, 12, 31, 14, 0, 0, 0, time.UTC), task.DueDate)
+	})
+}
+
 func TestTaskStruct(t *testing.T) {
 	t.Run("Task creation with all fields", func(t *testing.T) {
 		id := primitive.NewObjectID()
@@ -188,27 +371,43 @@ func TestPromoteRequest(t *testing.T) {
 }
 
 func TestResponseStructs(t *testing.T) {
-	t.Run("TaskResponse", func(t *testing.T) {
-		response := TaskResponse{
-			Success: true,
-			Message: "Task created successfully",
-			Data:    "test data",
-		}
+	t.Run("Response", func(t *testing.T) {
+		task := &Task{Title: "Test Task"}
+		response := NewResponse(Message{ID: MsgTaskCreated, Text: "Task created successfully"}, task)
 
 		assert.True(t, response.Success)
 		assert.Equal(t, "Task created successfully", response.Message)
-		assert.Equal(t, "test data", response.Data)
+		assert.Equal(t, MsgTaskCreated, response.MessageID)
+		assert.Equal(t, task, response.Data)
 	})
 
-	t.Run("UserResponse", func(t *testing.T) {
-		response := UserResponse{
-			Success: false,
-			Message: "User creation failed",
-		}
+	t.Run("ListResponse with pagination meta", func(t *testing.T) {
+		response := NewListResponse(Message{ID: MsgTasksRetrieved, Text: "Tasks retrieved successfully"}, []*Task{{Title: "Test Task"}}, PaginationMeta{
+			Total:  42,
+			Limit:  10,
+			Offset: 20,
+		})
 
-		assert.False(t, response.Success)
-		assert.Equal(t, "User creation failed", response.Message)
-		assert.Nil(t, response.Data)
+		assert.True(t, response.Success)
+		assert.Len(t, response.Data, 1)
+		assert.Equal(t, int64(42), response.Meta.Total)
+		assert.Equal(t, int64(10), response.Meta.Limit)
+		assert.Equal(t, int64(20), response.Meta.Offset)
+	})
+
+	t.Run("ListResponse encodes an empty page as an array", func(t *testing.T) {
+		var tasks []*Task
+		body, err := json.Marshal(NewListResponse(Message{ID: MsgTasksRetrieved, Text: "Tasks retrieved successfully"}, tasks, PaginationMeta{}))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"success":true,"message":"Tasks retrieved successfully","message_id":"tasks_retrieved","data":[],"meta":{"total":0,"limit":0,"offset":0}}`, string(body))
+	})
+
+	t.Run("MessageResponse", func(t *testing.T) {
+		body, err := json.Marshal(NewMessageResponse(Message{ID: MsgTaskDeleted, Text: "Task deleted successfully"}))
+
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{"success":true,"message":"Task deleted successfully","message_id":"task_deleted"}`, string(body))
 	})
 
 	t.Run("LoginResponse", func(t *testing.T) {
@@ -239,6 +438,327 @@ func TestResponseStructs(t *testing.T) {
 	})
 }
 
+func TestPagination(t *testing.T) {
+	t.Run("Zero value means no limit", func(t *testing.T) {
+		var pagination Pagination
+
+		assert.Equal(t, int64(0), pagination.Limit)
+		assert.Equal(t, int64(0), pagination.Offset)
+	})
+
+	t.Run("Pagination with limit and offset", func(t *testing.T) {
+		pagination := Pagination{Limit: 25, Offset: 50}
+
+		assert.Equal(t, int64(25), pagination.Limit)
+		assert.Equal(t, int64(50), pagination.Offset)
+	})
+}
+
+func TestParseTaskFields(t *testing.T) {
+	t.Run("Success - known fields are kept once in order", func(t *testing.T) {
+		// Act
+		fields, err := ParseTaskFields("id, title,status,id,due_date")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"id", "title", "status", "due_date"}, fields)
+	})
+
+	t.Run("Error - unknown or empty field names", func(t *testing.T) {
+		for _, value := range []string{"id,secret", "", "id,,title", "subtasks"} {
+			// Act
+			fields, err := ParseTaskFields(value)
+
+			// Assert
+			assert.ErrorIs(t, err, ErrInvalidTaskFields, value)
+			assert.Nil(t, fields)
+		}
+		assert.Contains(t, ErrInvalidTaskFields.Error(), "id, title, description, due_date")
+	})
+}
+
+func TestTaskSelect(t *testing.T) {
+	// Arrange
+	task := &Task{ID: primitive.NewObjectID(), Title: "Report", Description: "A long description", Status: StatusPending}
+
+	// Act
+	selected, err := task.Select([]string{"id", "title", "assignee_id"})
+
+	// Assert
+	assert.NoError(t, err)
+	assert.Len(t, selected, 2)
+	assert.JSONEq(t, `"`+task.ID.Hex()+`"`, string(selected["id"]))
+	assert.JSONEq(t, `"Report"`, string(selected["title"]))
+}
+
+func TestTaskIsDeleted(t *testing.T) {
+	t.Run("Active task", func(t *testing.T) {
+		task := &Task{Title: "Active"}
+
+		assert.False(t, task.IsDeleted())
+	})
+
+	t.Run("Soft deleted task", func(t *testing.T) {
+		deletedAt := time.Now()
+		task := &Task{Title: "Deleted", DeletedAt: &deletedAt}
+
+		assert.True(t, task.IsDeleted())
+	})
+}
+
+func TestTaskIsAssignedTo(t *testing.T) {
+	assigneeID := primitive.NewObjectID()
+
+	t.Run("Unassigned task", func(t *testing.T) {
+		task := &Task{Title: "Unassigned"}
+
+		assert.False(t, task.IsAssignedTo(assigneeID.Hex()))
+	})
+
+	t.Run("Assigned to user", func(t *testing.T) {
+		task := &Task{Title: "Assigned", AssigneeID: &assigneeID}
+
+		assert.True(t, task.IsAssignedTo(assigneeID.Hex()))
+	})
+
+	t.Run("Assigned to someone else", func(t *testing.T) {
+		task := &Task{Title: "Assigned", AssigneeID: &assigneeID}
+
+		assert.False(t, task.IsAssignedTo(primitive.NewObjectID().Hex()))
+	})
+}
+
+func TestTaskOwnerID(t *testing.T) {
+	creator, assignee := primitive.NewObjectID(), primitive.NewObjectID()
+
+	assert.Equal(t, creator, (&Task{CreatedBy: creator}).OwnerID())
+	assert.Equal(t, assignee, (&Task{CreatedBy: creator, AssigneeID: &assignee}).OwnerID())
+}
+
+func TestTaskSetDaysOverdue(t *testing.T) {
+	now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+
+	t.Run("Counts whole days past the due date", func(t *testing.T) {
+		task := Task{DueDate: time.Date(2024, 12, 28, 18, 0, 0, 0, time.UTC)}
+		task.SetDaysOverdue(now)
+		assert.Equal(t, 2, *task.DaysOverdue)
+	})
+
+	t.Run("Less than a day late is zero days", func(t *testing.T) {
+		task := Task{DueDate: time.Date(2024, 12, 31, 9, 0, 0, 0, time.UTC)}
+		task.SetDaysOverdue(now)
+		assert.Equal(t, 0, *task.DaysOverdue)
+	})
+}
+
+func TestTaskIsSubtask(t *testing.T) {
+	parentID := primitive.NewObjectID()
+
+	assert.False(t, (&Task{Title: "Parent"}).IsSubtask())
+	assert.True(t, (&Task{Title: "Child", ParentTaskID: &parentID}).IsSubtask())
+}
+
+func TestTaskIsVisibleTo(t *testing.T) {
+	creatorID := primitive.NewObjectID()
+	assigneeID := primitive.NewObjectID()
+	task := &Task{Title: "Task", CreatedBy: creatorID, AssigneeID: &assigneeID}
+
+	tests := []struct {
+		name     string
+		caller   Caller
+		expected bool
+	}{
+		{name: "Admin", caller: Caller{UserID: primitive.NewObjectID().Hex(), Role: RoleAdmin}, expected: true},
+		{name: "Creator", caller: Caller{UserID: creatorID.Hex(), Role: RoleUser}, expected: true},
+		{name: "Assignee", caller: Caller{UserID: assigneeID.Hex(), Role: RoleUser}, expected: true},
+		{name: "Unrelated user", caller: Caller{UserID: primitive.NewObjectID().Hex(), Role: RoleUser}, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, task.IsVisibleTo(tt.caller))
+		})
+	}
+}
+
+func TestTaskChangedFields(t *testing.T) {
+	assigneeID := primitive.NewObjectID()
+	before := &Task{
+		Title:      "Task",
+		Status:     StatusPending,
+		Priority:   PriorityMedium,
+		AssigneeID: &assigneeID,
+		Tags:       []string{"backend"},
+		DueDate:    time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	t.Run("No changes", func(t *testing.T) {
+		after := *before
+		sameAssignee := assigneeID
+		after.AssigneeID = &sameAssignee
+		after.Tags = []string{"backend"}
+
+		assert.Empty(t, after.ChangedFields(before))
+	})
+
+	t.Run("Lists changed fields in order", func(t *testing.T) {
+		after := *before
+		after.Title = "Renamed"
+		after.Status = StatusInProgress
+		after.AssigneeID = nil
+		after.Tags = nil
+
+		assert.Equal(t, []string{"title", "status", "assignee_id", "tags"}, after.ChangedFields(before))
+	})
+
+	t.Run("Estimate compared by value", func(t *testing.T) {
+		estimate, same := 60, 60
+		withEstimate := *before
+		withEstimate.EstimateMinutes = &estimate
+		after := withEstimate
+		after.EstimateMinutes = &same
+
+		assert.Empty(t, after.ChangedFields(&withEstimate))
+		assert.Equal(t, []string{"estimate_minutes"}, withEstimate.ChangedFields(before))
+	})
+}
+
+func TestTaskPreviousValues(t *testing.T) {
+	before := &Task{Title: "Task", Description: "Old text", Status: StatusPending, Priority: PriorityMedium}
+
+	t.Run("No changes", func(t *testing.T) {
+		after := *before
+		assert.Nil(t, after.PreviousValues(before))
+	})
+
+	t.Run("Only changed fields keep their old values", func(t *testing.T) {
+		after := *before
+		after.Description = "New text"
+		after.Priority = PriorityHigh
+
+		assert.Equal(t, map[string]interface{}{"description": "Old text", "priority": PriorityMedium}, after.PreviousValues(before))
+	})
+}
+
+func TestIsValidAuditEntity(t *testing.T) {
+	assert.True(t, IsValidAuditEntity(AuditEntityTask))
+	assert.True(t, IsValidAuditEntity(AuditEntityUser))
+	assert.True(t, IsValidAuditEntity(AuditEntityAPIKey))
+	assert.True(t, IsValidAuditEntity(AuditEntityWebhook))
+	assert.True(t, IsValidAuditEntity(AuditEntityAPI))
+	assert.False(t, IsValidAuditEntity("comment"))
+}
+
+func TestIsValidEventType(t *testing.T) {
+	for _, eventType := range EventTypes {
+		assert.True(t, IsValidEventType(eventType), eventType)
+	}
+	assert.False(t, IsValidEventType("task.*"))
+	assert.False(t, IsValidEventType(""))
+}
+
+func TestEventIsTaskEventVisibleTo(t *testing.T) {
+	creatorID := primitive.NewObjectID()
+	task := &Task{Title: "Task", CreatedBy: creatorID}
+	creator := Caller{UserID: creatorID.Hex(), Role: RoleUser}
+	stranger := Caller{UserID: primitive.NewObjectID().Hex(), Role: RoleUser}
+	manager := Caller{UserID: primitive.NewObjectID().Hex(), Role: RoleManager}
+
+	tests := []struct {
+		name     string
+		event    Event
+		caller   Caller
+		expected bool
+	}{
+		{name: "Own task", event: NewEvent(EventTaskUpdated, "", task), caller: creator, expected: true},
+		{name: "Someone else's task", event: NewEvent(EventTaskUpdated, "", task), caller: stranger, expected: false},
+		{name: "Own deleted task", event: NewEvent(EventTaskDeleted, "", DeletedTaskEvent{ID: "1", Task: task}), caller: creator, expected: true},
+		{name: "Someone else's deleted task", event: NewEvent(EventTaskDeleted, "", DeletedTaskEvent{ID: "1", Task: task}), caller: stranger, expected: false},
+		{name: "Deletion with unknown owner", event: NewEvent(EventTaskDeleted, "", DeletedTaskEvent{ID: "1"}), caller: creator, expected: false},
+		{name: "Deletion with unknown owner to a manager", event: NewEvent(EventTaskDeleted, "", DeletedTaskEvent{ID: "1"}), caller: manager, expected: true},
+		{name: "Not a task event", event: NewEvent(EventUserPromoted, "", PromotedUserEvent{}), caller: manager, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.event.IsTaskEventVisibleTo(tt.caller))
+		})
+	}
+}
+
+func TestTaskFilter(t *testing.T) {
+	t.Run("Zero value filters nothing", func(t *testing.T) {
+		var filter TaskFilter
+
+		assert.Empty(t, filter.Status)
+		assert.True(t, filter.DueBefore.IsZero())
+		assert.True(t, filter.DueAfter.IsZero())
+		assert.False(t, filter.IncludeDeleted)
+		assert.False(t, filter.OnlyDeleted)
+	})
+
+	t.Run("Filter with status and due date range", func(t *testing.T) {
+		after := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		before := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+		filter := TaskFilter{
+			Status:    StatusPending,
+			DueBefore: before,
+			DueAfter:  after,
+		}
+
+		assert.Equal(t, StatusPending, filter.Status)
+		assert.Equal(t, before, filter.DueBefore)
+		assert.Equal(t, after, filter.DueAfter)
+	})
+}
+
+func TestCallerCan(t *testing.T) {
+	t.Run("Admin caller", func(t *testing.T) {
+		caller := Caller{UserID: "507f1f77bcf86cd799439011", Role: RoleAdmin}
+
+		assert.True(t, caller.Can(PermissionUsersManage))
+		assert.True(t, caller.Can(PermissionTasksWrite))
+		assert.True(t, caller.Can(PermissionAuditRead))
+	})
+
+	t.Run("Manager caller", func(t *testing.T) {
+		caller := Caller{UserID: "507f1f77bcf86cd799439011", Role: RoleManager}
+
+		assert.True(t, caller.Can(PermissionTasksWrite))
+		assert.True(t, caller.Can(PermissionTasksReadAll))
+		assert.False(t, caller.Can(PermissionUsersManage))
+		assert.False(t, caller.Can(PermissionAuditRead))
+	})
+
+	t.Run("Regular caller", func(t *testing.T) {
+		caller := Caller{UserID: "507f1f77bcf86cd799439011", Role: RoleUser}
+
+		assert.True(t, caller.Can(PermissionTasksRead))
+		assert.False(t, caller.Can(PermissionTasksReadAll))
+		assert.False(t, caller.Can(PermissionTasksWrite))
+	})
+
+	t.Run("Unknown role", func(t *testing.T) {
+		caller := Caller{UserID: "507f1f77bcf86cd799439011", Role: "superuser"}
+
+		assert.False(t, caller.Can(PermissionTasksRead))
+	})
+
+	t.Run("Empty caller", func(t *testing.T) {
+		var caller Caller
+
+		assert.False(t, caller.Can(PermissionTasksRead))
+	})
+}
+
+func TestIsValidRole(t *testing.T) {
+	assert.True(t, IsValidRole(RoleAdmin))
+	assert.True(t, IsValidRole(RoleManager))
+	assert.True(t, IsValidRole(RoleUser))
+	assert.False(t, IsValidRole("superuser"))
+	assert.False(t, IsValidRole(""))
+}
+
 func TestJWTClaims(t *testing.T) {
 	t.Run("JWT claims creation", func(t *testing.T) {
 		claims := JWTClaims{
@@ -264,4 +784,35 @@ func TestConstants(t *testing.T) {
 		assert.Equal(t, "in_progress", StatusInProgress)
 		assert.Equal(t, "completed", StatusCompleted)
 	})
-}
\ No newline at end of file
+}
+
+func TestUserUnmarshalBSON(t *testing.T) {
+	t.Run("Records without is_active are active", func(t *testing.T) {
+		// Arrange
+		data, err := bson.Marshal(bson.M{"username": "legacy", "role": RoleUser})
+		assert.NoError(t, err)
+
+		// Act
+		var user User
+		err = bson.Unmarshal(data, &user)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "legacy", user.Username)
+		assert.True(t, user.IsActive)
+	})
+
+	t.Run("Stored flag is kept", func(t *testing.T) {
+		// Arrange
+		data, err := bson.Marshal(User{Username: "inactive", Role: RoleUser, IsActive: false})
+		assert.NoError(t, err)
+
+		// Act
+		var user User
+		err = bson.Unmarshal(data, &user)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, user.IsActive)
+	})
+}
@@ -0,0 +1,119 @@
+package Domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors returned by repositories and usecases. Handlers choose a status code with errors.Is,
+// so a message can be reworded without changing the response status. An error with a more
+// specific message wraps one of these, either with %w or through NewError.
+
+// ErrInvalidInput matches every error about a malformed or out of range request value
+var ErrInvalidInput = errors.New("invalid input")
+
+// Invalid request values
+var (
+	ErrInvalidID           = NewError(ErrInvalidInput, "invalid ID format")
+	ErrInvalidTaskID       = NewError(ErrInvalidID, "invalid task ID format")
+	ErrInvalidUserID       = NewError(ErrInvalidID, "invalid user ID format")
+	ErrInvalidAPIKeyID     = NewError(ErrInvalidID, "invalid API key ID format")
+	ErrInvalidWebhookID    = NewError(ErrInvalidID, "invalid webhook ID format")
+	ErrInvalidAttachmentID = NewError(ErrInvalidID, "invalid attachment ID format")
+	ErrInvalidParentTaskID = NewError(ErrInvalidID, "invalid parent task ID format")
+	ErrInvalidAssigneeID   = NewError(ErrInvalidID, "invalid assignee ID format")
+	ErrInvalidStatus       = NewError(ErrInvalidInput, "invalid status, must be one of: pending, in_progress, completed")
+	ErrInvalidPriority     = NewError(ErrInvalidInput, "invalid priority, must be one of: low, medium, high, urgent")
+	ErrInvalidRecurrence   = NewError(ErrInvalidInput, "invalid recurrence, must be one of: none, daily, weekly, monthly")
+	ErrInvalidDueDate      = NewError(ErrInvalidInput, "invalid due date format, use YYYY-MM-DD or RFC3339 (e.g. 2024-12-31T17:00:00+03:00)")
+	ErrInvalidPagination   = NewError(ErrInvalidInput, "invalid pagination, limit and offset must not be negative")
+	ErrInvalidTaskFields   = NewError(ErrInvalidInput, "invalid fields, must be any of: "+strings.Join(TaskFields, ", "))
+)
+
+// Due dates refused by the configured due date rules
+var (
+	ErrDueDateOutOfRange = errors.New("due date is out of the allowed range")
+	ErrDueDateInPast     = NewError(ErrDueDateOutOfRange, "due date must not be in the past")
+	ErrDueDateTooFar     = NewError(ErrDueDateOutOfRange, "due date is too far in the future")
+)
+
+// Missing records
+var (
+	ErrTaskNotFound         = errors.New("task not found")
+	ErrParentTaskNotFound   = errors.New("parent task not found")
+	ErrUserNotFound         = errors.New("user not found")
+	ErrAssigneeNotFound     = errors.New("assignee not found")
+	ErrAPIKeyNotFound       = errors.New("API key not found")
+	ErrWebhookNotFound      = errors.New("webhook not found")
+	ErrAttachmentNotFound   = errors.New("attachment not found")
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrResetTokenNotFound   = errors.New("reset token not found")
+)
+
+// Requests that conflict with the current state of a record
+var (
+	ErrUsernameTaken           = errors.New("username already exists")
+	ErrEmailTaken              = errors.New("email already exists")
+	ErrAlreadyHasRole          = errors.New("user already has this role")
+	ErrAlreadyAdmin            = NewError(ErrAlreadyHasRole, "user is already an admin")
+	ErrNotPrivileged           = errors.New("user is not an admin or manager")
+	ErrSelfDemotion            = errors.New("cannot demote yourself")
+	ErrLastAdminDemotion       = errors.New("cannot demote the last admin")
+	ErrSelfDeletion            = errors.New("cannot delete yourself")
+	ErrLastAdminDeletion       = errors.New("cannot delete the last admin")
+	ErrReassignToAPIKey        = errors.New("an API key cannot take over a deleted user's tasks, anonymize the user instead")
+	ErrSelfDeactivation        = errors.New("cannot deactivate yourself")
+	ErrVersionConflict         = errors.New("task was modified by someone else, refetch it and try again")
+	ErrInvalidStatusTransition = errors.New("invalid status transition")
+	ErrOpenTaskLimit           = errors.New("open task limit reached")
+	ErrDuplicateTask           = errors.New("an open task with the same title already exists")
+	ErrTaskHasSubtasks         = errors.New("task has subtasks, delete them before deleting the parent task")
+	ErrTaskNotDeleted          = errors.New("task is not deleted")
+	ErrTaskArchived            = errors.New("task is already archived")
+	ErrTaskNotArchived         = errors.New("task is not archived")
+	ErrTaskNotCompleted        = errors.New("task is not completed, archive it with force=true")
+	ErrNotAssignee             = errors.New("only the assignee or an admin can change the task status")
+	ErrNotUploader             = errors.New("only the uploader or a manager can delete an attachment")
+	ErrDailyWorkLimit          = errors.New("no more than 24 hours can be logged on one day")
+)
+
+// Uploaded files that are refused
+var (
+	ErrAttachmentTooLarge        = errors.New("attachment is too large")
+	ErrUnsupportedAttachmentType = errors.New("attachment content type is not allowed")
+)
+
+// Failed authentication and unusable tokens
+var (
+	ErrInvalidCredentials       = errors.New("invalid credentials")
+	ErrAccountDeactivated       = errors.New("account is deactivated")
+	ErrIncorrectPassword        = errors.New("current password is incorrect")
+	ErrInvalidRefreshToken      = errors.New("invalid refresh token")
+	ErrRefreshTokenExpired      = errors.New("refresh token expired")
+	ErrRefreshTokenReused       = errors.New("refresh token reuse detected")
+	ErrRefreshTokenRevoked      = errors.New("refresh token already revoked")
+	ErrInvalidResetToken        = errors.New("invalid reset token")
+	ErrResetTokenExpired        = errors.New("reset token expired")
+	ErrResetTokenUsed           = errors.New("reset token already used")
+	ErrInvalidVerificationToken = errors.New("invalid verification token")
+	ErrVerificationTokenExpired = errors.New("verification token expired")
+	ErrInvalidAPIKey            = errors.New("invalid API key")
+	ErrAPIKeyRevoked            = errors.New("API key revoked")
+)
+
+// kindError carries its own message but matches the error it was created from with errors.Is
+type kindError struct {
+	kind    error
+	message string
+}
+
+func (e *kindError) Error() string { return e.message }
+
+func (e *kindError) Unwrap() error { return e.kind }
+
+// NewError returns an error with the formatted message that errors.Is matches against kind.
+// It is for messages that do not start with the message of kind, where %w would not read well.
+func NewError(kind error, format string, args ...any) error {
+	return &kindError{kind: kind, message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,42 @@
+package Domain
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrors(t *testing.T) {
+	t.Run("Specific invalid IDs match their kind and keep their message", func(t *testing.T) {
+		assert.ErrorIs(t, ErrInvalidTaskID, ErrInvalidID)
+		assert.ErrorIs(t, ErrInvalidTaskID, ErrInvalidInput)
+		assert.EqualError(t, ErrInvalidTaskID, "invalid task ID format")
+		assert.NotErrorIs(t, ErrInvalidTaskID, ErrInvalidUserID)
+	})
+
+	t.Run("NewError formats its message", func(t *testing.T) {
+		// Act
+		err := NewError(ErrAlreadyHasRole, "user is already a %s", RoleManager)
+
+		// Assert
+		assert.EqualError(t, err, "user is already a manager")
+		assert.ErrorIs(t, err, ErrAlreadyHasRole)
+		assert.NotErrorIs(t, err, ErrAlreadyAdmin)
+	})
+
+	t.Run("Wrapped errors still match", func(t *testing.T) {
+		// Act
+		err := fmt.Errorf("%w: %s", ErrInvalidTaskID, "bad-id")
+
+		// Assert
+		assert.EqualError(t, err, "invalid task ID format: bad-id")
+		assert.ErrorIs(t, err, ErrInvalidInput)
+	})
+
+	t.Run("Unrelated errors do not match", func(t *testing.T) {
+		assert.NotErrorIs(t, errors.New("invalid task ID format"), ErrInvalidTaskID)
+		assert.NotErrorIs(t, ErrTaskNotFound, ErrInvalidInput)
+	})
+}
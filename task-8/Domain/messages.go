@@ -0,0 +1,383 @@
+package Domain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Message IDs name every message the API sends to users. Responses carry the ID next to the
+// text, so clients can show their own wording instead of the server's translation.
+const (
+	MsgAccessDenied                     = "access_denied"
+	MsgAccountDeactivated               = "account_deactivated"
+	MsgActivateUserFailed               = "activate_user_failed"
+	MsgAddCommentFailed                 = "add_comment_failed"
+	MsgAPIKeyCreated                    = "api_key_created"
+	MsgAPIKeyRevoked                    = "api_key_revoked"
+	MsgAPIKeysRetrieved                 = "api_keys_retrieved"
+	MsgArchiveTaskFailed                = "archive_task_failed"
+	MsgArchiveTasksFailed               = "archive_tasks_failed"
+	MsgAssignedTasksRetrieved           = "assigned_tasks_retrieved"
+	MsgAttachmentDeleted                = "attachment_deleted"
+	MsgAttachmentUploaded               = "attachment_uploaded"
+	MsgAttachmentsRetrieved             = "attachments_retrieved"
+	MsgAuditLogRetrieved                = "audit_log_retrieved"
+	MsgAuthenticationFailed             = "authentication_failed"
+	MsgAuthorizationHeaderRequired      = "authorization_header_required"
+	MsgBuildReportFailed                = "build_report_failed"
+	MsgCallerNotFound                   = "caller_not_found"
+	MsgChangePasswordFailed             = "change_password_failed"
+	MsgCommentAdded                     = "comment_added"
+	MsgCommentsRetrieved                = "comments_retrieved"
+	MsgCountTasksFailed                 = "count_tasks_failed"
+	MsgCreateAPIKeyFailed               = "create_api_key_failed"
+	MsgCreateTaskFailed                 = "create_task_failed"
+	MsgCreateTasksFailed                = "create_tasks_failed"
+	MsgCreateUserFailed                 = "create_user_failed"
+	MsgCreateWebhookFailed              = "create_webhook_failed"
+	MsgDeactivateUserFailed             = "deactivate_user_failed"
+	MsgDeleteAttachmentFailed           = "delete_attachment_failed"
+	MsgDeleteTaskFailed                 = "delete_task_failed"
+	MsgDeleteTasksFailed                = "delete_tasks_failed"
+	MsgDeleteUserFailed                 = "delete_user_failed"
+	MsgDeleteWebhookFailed              = "delete_webhook_failed"
+	MsgDeletedTasksPurged               = "deleted_tasks_purged"
+	MsgDeletedTasksRetrieved            = "deleted_tasks_retrieved"
+	MsgDemoteUserFailed                 = "demote_user_failed"
+	MsgDownloadAttachmentFailed         = "download_attachment_failed"
+	MsgEmailVerified                    = "email_verified"
+	MsgExportTasksFailed                = "export_tasks_failed"
+	MsgExportUsersFailed                = "export_users_failed"
+	MsgImportTasksFailed                = "import_tasks_failed"
+	MsgInvalidAPIKey                    = "invalid_api_key"
+	MsgInvalidAuthorizationHeaderFormat = "invalid_authorization_header_format"
+	MsgInvalidExportParameters          = "invalid_export_parameters"
+	MsgInvalidFieldsParameter           = "invalid_fields_parameter"
+	MsgInvalidFilterParameters          = "invalid_filter_parameters"
+	MsgInvalidImportFile                = "invalid_import_file"
+	MsgInvalidOrExpiredToken            = "invalid_or_expired_token"
+	MsgInvalidPaginationParameters      = "invalid_pagination_parameters"
+	MsgInvalidPurgeParameters           = "invalid_purge_parameters"
+	MsgInvalidQueryParameters           = "invalid_query_parameters"
+	MsgInvalidRequestHeaders            = "invalid_request_headers"
+	MsgInvalidRequestParameters         = "invalid_request_parameters"
+	MsgInvalidRequestPayload            = "invalid_request_payload"
+	MsgInvalidTokenClaims               = "invalid_token_claims"
+	MsgLogWorkFailed                    = "log_work_failed"
+	MsgLoggedIn                         = "logged_in"
+	MsgLoggedOut                        = "logged_out"
+	MsgLoginHistoryRetrieved            = "login_history_retrieved"
+	MsgLogoutFailed                     = "logout_failed"
+	MsgMethodNotAllowed                 = "method_not_allowed"
+	MsgNoPublicKeys                     = "no_public_keys"
+	MsgNoTasksCreated                   = "no_tasks_created"
+	MsgOverdueTasksRetrieved            = "overdue_tasks_retrieved"
+	MsgPasswordChanged                  = "password_changed"
+	MsgPasswordReset                    = "password_reset"
+	MsgPasswordResetRequested           = "password_reset_requested"
+	MsgProfileRetrieved                 = "profile_retrieved"
+	MsgProfileUpdated                   = "profile_updated"
+	MsgPromoteUserFailed                = "promote_user_failed"
+	MsgPurgeDeletedTasksFailed          = "purge_deleted_tasks_failed"
+	MsgRefreshTokenFailed               = "refresh_token_failed"
+	MsgReportBuilt                      = "report_built"
+	MsgRequestBodyTooLarge              = "request_body_too_large"
+	MsgRequestPasswordResetFailed       = "request_password_reset_failed"
+	MsgRequestTimedOut                  = "request_timed_out"
+	MsgResetPasswordFailed              = "reset_password_failed"
+	MsgRestoreTaskFailed                = "restore_task_failed"
+	MsgRetrieveAPIKeysFailed            = "retrieve_api_keys_failed"
+	MsgRetrieveAttachmentsFailed        = "retrieve_attachments_failed"
+	MsgRetrieveAuditLogFailed           = "retrieve_audit_log_failed"
+	MsgRetrieveCommentsFailed           = "retrieve_comments_failed"
+	MsgRetrieveDeletedTasksFailed       = "retrieve_deleted_tasks_failed"
+	MsgRetrieveLoginHistoryFailed       = "retrieve_login_history_failed"
+	MsgRetrieveOverdueTasksFailed       = "retrieve_overdue_tasks_failed"
+	MsgRetrieveTagsFailed               = "retrieve_tags_failed"
+	MsgRetrieveTaskRevisionsFailed      = "retrieve_task_revisions_failed"
+	MsgRetrieveTaskStatisticsFailed     = "retrieve_task_statistics_failed"
+	MsgRetrieveTasksFailed              = "retrieve_tasks_failed"
+	MsgRetrieveUserProfileFailed        = "retrieve_user_profile_failed"
+	MsgRetrieveUsersFailed              = "retrieve_users_failed"
+	MsgRetrieveWebhookDeliveriesFailed  = "retrieve_webhook_deliveries_failed"
+	MsgRetrieveWebhookFailed            = "retrieve_webhook_failed"
+	MsgRetrieveWebhooksFailed           = "retrieve_webhooks_failed"
+	MsgRetrieveWorkLogSummaryFailed     = "retrieve_work_log_summary_failed"
+	MsgRetrieveWorkLogsFailed           = "retrieve_work_logs_failed"
+	MsgRevokeAPIKeyFailed               = "revoke_api_key_failed"
+	MsgRouteNotFound                    = "route_not_found"
+	MsgSearchTasksFailed                = "search_tasks_failed"
+	MsgSelectTaskFieldsFailed           = "select_task_fields_failed"
+	MsgTagsRetrieved                    = "tags_retrieved"
+	MsgTaskArchived                     = "task_archived"
+	MsgTaskCreated                      = "task_created"
+	MsgTaskDeleted                      = "task_deleted"
+	MsgTaskNotFound                     = "task_not_found"
+	MsgTaskRestored                     = "task_restored"
+	MsgTaskRetrieved                    = "task_retrieved"
+	MsgTaskRevisionsRetrieved           = "task_revisions_retrieved"
+	MsgTaskStatisticsRetrieved          = "task_statistics_retrieved"
+	MsgTaskStatusUpdated                = "task_status_updated"
+	MsgTaskStatusesUpdated              = "task_statuses_updated"
+	MsgTaskUnarchived                   = "task_unarchived"
+	MsgTaskUpdated                      = "task_updated"
+	MsgTasksArchived                    = "tasks_archived"
+	MsgTasksCounted                     = "tasks_counted"
+	MsgTasksCreated                     = "tasks_created"
+	MsgTasksDeleted                     = "tasks_deleted"
+	MsgTasksImported                    = "tasks_imported"
+	MsgTasksPartlyCreated               = "tasks_partly_created"
+	MsgTasksPartlyImported              = "tasks_partly_imported"
+	MsgTasksRetrieved                   = "tasks_retrieved"
+	MsgTasksSearched                    = "tasks_searched"
+	MsgTokenNotRevocable                = "token_not_revocable"
+	MsgTokenRefreshed                   = "token_refreshed"
+	MsgTooManyRequests                  = "too_many_requests"
+	MsgUnarchiveTaskFailed              = "unarchive_task_failed"
+	MsgUpdateProfileFailed              = "update_profile_failed"
+	MsgUpdateTaskFailed                 = "update_task_failed"
+	MsgUpdateTaskStatusFailed           = "update_task_status_failed"
+	MsgUpdateTaskStatusesFailed         = "update_task_statuses_failed"
+	MsgUpdateWebhookFailed              = "update_webhook_failed"
+	MsgUploadAttachmentFailed           = "upload_attachment_failed"
+	MsgUserActivated                    = "user_activated"
+	MsgUserAnonymized                   = "user_anonymized"
+	MsgUserDeactivated                  = "user_deactivated"
+	MsgUserDeleted                      = "user_deleted"
+	MsgUserDemoted                      = "user_demoted"
+	MsgUserPromoted                     = "user_promoted"
+	MsgUserRegistered                   = "user_registered"
+	MsgUserRoleNotFound                 = "user_role_not_found"
+	MsgUsersRetrieved                   = "users_retrieved"
+	MsgVerifyAPIKeyFailed               = "verify_api_key_failed"
+	MsgVerifyEmailFailed                = "verify_email_failed"
+	MsgVerifyTokenFailed                = "verify_token_failed"
+	MsgWebhookCreated                   = "webhook_created"
+	MsgWebhookDeleted                   = "webhook_deleted"
+	MsgWebhookDeliveriesRetrieved       = "webhook_deliveries_retrieved"
+	MsgWebhookRetrieved                 = "webhook_retrieved"
+	MsgWebhookUpdated                   = "webhook_updated"
+	MsgWebhooksRetrieved                = "webhooks_retrieved"
+	MsgWorkLogSummaryRetrieved          = "work_log_summary_retrieved"
+	MsgWorkLogged                       = "work_logged"
+	MsgWorkLogsRetrieved                = "work_logs_retrieved"
+)
+
+// DefaultLocale is the language of responses to requests that ask for no supported language
+const DefaultLocale = "en"
+
+// messageCatalogs holds the text of every message ID by locale. The English catalog is
+// complete; other locales may lag behind, and their missing messages are sent in English.
+var messageCatalogs = map[string]map[string]string{
+	DefaultLocale: englishMessages,
+	"am":          amharicMessages,
+}
+
+// SupportedLocales lists the locales that have a message catalog, the default first
+var SupportedLocales = []string{DefaultLocale, "am"}
+
+// IsSupportedLocale reports whether locale has a message catalog
+func IsSupportedLocale(locale string) bool {
+	_, ok := messageCatalogs[locale]
+	return ok
+}
+
+// Translate returns the text of a message in locale, falling back to English when the
+// locale or the message has no translation and to the ID itself when the ID is unknown.
+// Args fill the message's formatting verbs, such as the role in MsgUserPromoted.
+func Translate(locale, id string, args ...interface{}) string {
+	text, ok := messageCatalogs[locale][id]
+	if !ok {
+		if text, ok = englishMessages[id]; !ok {
+			return id
+		}
+	}
+	if len(args) > 0 {
+		return fmt.Sprintf(text, args...)
+	}
+	return text
+}
+
+// Message is a message resolved for one request: its ID and its text in the request's language
+type Message struct {
+	ID   string
+	Text string
+}
+
+// LocalizeMessage resolves a message in the language carried by ctx
+func LocalizeMessage(ctx context.Context, id string, args ...interface{}) Message {
+	return Message{ID: id, Text: Translate(LocaleFromContext(ctx), id, args...)}
+}
+
+type localeKey struct{}
+
+// WithLocale returns a copy of ctx carrying the locale responses should be written in
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey{}, strings.ToLower(locale))
+}
+
+// LocaleFromContext returns the locale carried by ctx, or DefaultLocale if there is none
+func LocaleFromContext(ctx context.Context) string {
+	if locale, ok := ctx.Value(localeKey{}).(string); ok && locale != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// englishMessages is the complete catalog every other locale falls back to
+var englishMessages = map[string]string{
+	MsgAccessDenied:                     "Access denied",
+	MsgAccountDeactivated:               "Account deactivated",
+	MsgActivateUserFailed:               "Failed to activate user",
+	MsgAddCommentFailed:                 "Failed to add comment",
+	MsgAPIKeyCreated:                    "API key created successfully; store the key now, it is not shown again",
+	MsgAPIKeyRevoked:                    "API key revoked successfully",
+	MsgAPIKeysRetrieved:                 "API keys retrieved successfully",
+	MsgArchiveTaskFailed:                "Failed to archive task",
+	MsgArchiveTasksFailed:               "Failed to archive tasks",
+	MsgAssignedTasksRetrieved:           "Assigned tasks retrieved successfully",
+	MsgAttachmentDeleted:                "Attachment deleted successfully",
+	MsgAttachmentUploaded:               "Attachment uploaded successfully",
+	MsgAttachmentsRetrieved:             "Attachments retrieved successfully",
+	MsgAuditLogRetrieved:                "Audit log retrieved successfully",
+	MsgAuthenticationFailed:             "Authentication failed",
+	MsgAuthorizationHeaderRequired:      "Authorization header required",
+	MsgBuildReportFailed:                "Failed to build report",
+	MsgCallerNotFound:                   "User ID not found in token",
+	MsgChangePasswordFailed:             "Failed to change password",
+	MsgCommentAdded:                     "Comment added successfully",
+	MsgCommentsRetrieved:                "Comments retrieved successfully",
+	MsgCountTasksFailed:                 "Failed to count tasks",
+	MsgCreateAPIKeyFailed:               "Failed to create API key",
+	MsgCreateTaskFailed:                 "Failed to create task",
+	MsgCreateTasksFailed:                "Failed to create tasks",
+	MsgCreateUserFailed:                 "Failed to create user",
+	MsgCreateWebhookFailed:              "Failed to create webhook",
+	MsgDeactivateUserFailed:             "Failed to deactivate user",
+	MsgDeleteAttachmentFailed:           "Failed to delete attachment",
+	MsgDeleteTaskFailed:                 "Failed to delete task",
+	MsgDeleteTasksFailed:                "Failed to delete tasks",
+	MsgDeleteUserFailed:                 "Failed to delete user",
+	MsgDeleteWebhookFailed:              "Failed to delete webhook",
+	MsgDeletedTasksPurged:               "Deleted tasks purged successfully",
+	MsgDeletedTasksRetrieved:            "Deleted tasks retrieved successfully",
+	MsgDemoteUserFailed:                 "Failed to demote user",
+	MsgDownloadAttachmentFailed:         "Failed to download attachment",
+	MsgEmailVerified:                    "Email verified successfully",
+	MsgExportTasksFailed:                "Failed to export tasks",
+	MsgExportUsersFailed:                "Failed to export users",
+	MsgImportTasksFailed:                "Failed to import tasks",
+	MsgInvalidAPIKey:                    "Invalid API key",
+	MsgInvalidAuthorizationHeaderFormat: "Invalid authorization header format",
+	MsgInvalidExportParameters:          "Invalid export parameters",
+	MsgInvalidFieldsParameter:           "Invalid fields parameter",
+	MsgInvalidFilterParameters:          "Invalid filter parameters",
+	MsgInvalidImportFile:                "Invalid import file",
+	MsgInvalidOrExpiredToken:            "Invalid or expired token",
+	MsgInvalidPaginationParameters:      "Invalid pagination parameters",
+	MsgInvalidPurgeParameters:           "Invalid purge parameters",
+	MsgInvalidQueryParameters:           "Invalid query parameters",
+	MsgInvalidRequestHeaders:            "Invalid request headers",
+	MsgInvalidRequestParameters:         "Invalid request parameters",
+	MsgInvalidRequestPayload:            "Invalid request payload",
+	MsgInvalidTokenClaims:               "Invalid token claims",
+	MsgLogWorkFailed:                    "Failed to log work",
+	MsgLoggedIn:                         "Login successful",
+	MsgLoggedOut:                        "Logged out successfully",
+	MsgLoginHistoryRetrieved:            "Login history retrieved successfully",
+	MsgLogoutFailed:                     "Failed to log out",
+	MsgMethodNotAllowed:                 "Method not allowed",
+	MsgNoPublicKeys:                     "No public keys available",
+	MsgNoTasksCreated:                   "No tasks were created",
+	MsgOverdueTasksRetrieved:            "Overdue tasks retrieved successfully",
+	MsgPasswordChanged:                  "Password changed successfully",
+	MsgPasswordReset:                    "Password reset successfully",
+	MsgPasswordResetRequested:           "If the account exists, password reset instructions have been sent",
+	MsgProfileRetrieved:                 "Profile retrieved successfully",
+	MsgProfileUpdated:                   "Profile updated successfully",
+	MsgPromoteUserFailed:                "Failed to promote user",
+	MsgPurgeDeletedTasksFailed:          "Failed to purge deleted tasks",
+	MsgRefreshTokenFailed:               "Failed to refresh token",
+	MsgReportBuilt:                      "Report built successfully",
+	MsgRequestBodyTooLarge:              "Request body too large",
+	MsgRequestPasswordResetFailed:       "Failed to request password reset",
+	MsgRequestTimedOut:                  "request timed out",
+	MsgResetPasswordFailed:              "Failed to reset password",
+	MsgRestoreTaskFailed:                "Failed to restore task",
+	MsgRetrieveAPIKeysFailed:            "Failed to retrieve API keys",
+	MsgRetrieveAttachmentsFailed:        "Failed to retrieve attachments",
+	MsgRetrieveAuditLogFailed:           "Failed to retrieve audit log",
+	MsgRetrieveCommentsFailed:           "Failed to retrieve comments",
+	MsgRetrieveDeletedTasksFailed:       "Failed to retrieve deleted tasks",
+	MsgRetrieveLoginHistoryFailed:       "Failed to retrieve login history",
+	MsgRetrieveOverdueTasksFailed:       "Failed to retrieve overdue tasks",
+	MsgRetrieveTagsFailed:               "Failed to retrieve tags",
+	MsgRetrieveTaskRevisionsFailed:      "Failed to retrieve task revisions",
+	MsgRetrieveTaskStatisticsFailed:     "Failed to retrieve task statistics",
+	MsgRetrieveTasksFailed:              "Failed to retrieve tasks",
+	MsgRetrieveUserProfileFailed:        "Failed to retrieve user profile",
+	MsgRetrieveUsersFailed:              "Failed to retrieve users",
+	MsgRetrieveWebhookDeliveriesFailed:  "Failed to retrieve webhook deliveries",
+	MsgRetrieveWebhookFailed:            "Failed to retrieve webhook",
+	MsgRetrieveWebhooksFailed:           "Failed to retrieve webhooks",
+	MsgRetrieveWorkLogSummaryFailed:     "Failed to retrieve work log summary",
+	MsgRetrieveWorkLogsFailed:           "Failed to retrieve work logs",
+	MsgRevokeAPIKeyFailed:               "Failed to revoke API key",
+	MsgRouteNotFound:                    "Route not found",
+	MsgSearchTasksFailed:                "Failed to search tasks",
+	MsgSelectTaskFieldsFailed:           "Failed to select task fields",
+	MsgTagsRetrieved:                    "Tags retrieved successfully",
+	MsgTaskArchived:                     "Task archived successfully",
+	MsgTaskCreated:                      "Task created successfully",
+	MsgTaskDeleted:                      "Task deleted successfully",
+	MsgTaskNotFound:                     "Task not found",
+	MsgTaskRestored:                     "Task restored successfully",
+	MsgTaskRetrieved:                    "Task retrieved successfully",
+	MsgTaskRevisionsRetrieved:           "Task revisions retrieved successfully",
+	MsgTaskStatisticsRetrieved:          "Task statistics retrieved successfully",
+	MsgTaskStatusUpdated:                "Task status updated successfully",
+	MsgTaskStatusesUpdated:              "Task statuses updated successfully",
+	MsgTaskUnarchived:                   "Task unarchived successfully",
+	MsgTaskUpdated:                      "Task updated successfully",
+	MsgTasksArchived:                    "Tasks archived successfully",
+	MsgTasksCounted:                     "Tasks counted successfully",
+	MsgTasksCreated:                     "Tasks created successfully",
+	MsgTasksDeleted:                     "Tasks deleted successfully",
+	MsgTasksImported:                    "Tasks imported successfully",
+	MsgTasksPartlyCreated:               "Some tasks could not be created",
+	MsgTasksPartlyImported:              "Imported %d tasks, skipped %d",
+	MsgTasksRetrieved:                   "Tasks retrieved successfully",
+	MsgTasksSearched:                    "Tasks searched successfully",
+	MsgTokenNotRevocable:                "Token cannot be revoked",
+	MsgTokenRefreshed:                   "Token refreshed successfully",
+	MsgTooManyRequests:                  "Too many requests",
+	MsgUnarchiveTaskFailed:              "Failed to unarchive task",
+	MsgUpdateProfileFailed:              "Failed to update profile",
+	MsgUpdateTaskFailed:                 "Failed to update task",
+	MsgUpdateTaskStatusFailed:           "Failed to update task status",
+	MsgUpdateTaskStatusesFailed:         "Failed to update task statuses",
+	MsgUpdateWebhookFailed:              "Failed to update webhook",
+	MsgUploadAttachmentFailed:           "Failed to upload attachment",
+	MsgUserActivated:                    "User activated successfully",
+	MsgUserAnonymized:                   "User anonymized successfully",
+	MsgUserDeactivated:                  "User deactivated successfully",
+	MsgUserDeleted:                      "User deleted successfully",
+	MsgUserDemoted:                      "User demoted successfully",
+	MsgUserPromoted:                     "User promoted to %s successfully",
+	MsgUserRegistered:                   "User registered successfully",
+	MsgUserRoleNotFound:                 "User role not found",
+	MsgUsersRetrieved:                   "Users retrieved successfully",
+	MsgVerifyAPIKeyFailed:               "Failed to verify API key",
+	MsgVerifyEmailFailed:                "Failed to verify email",
+	MsgVerifyTokenFailed:                "Failed to verify token",
+	MsgWebhookCreated:                   "Webhook created successfully; store the secret now, it is not shown again",
+	MsgWebhookDeleted:                   "Webhook deleted successfully",
+	MsgWebhookDeliveriesRetrieved:       "Webhook deliveries retrieved successfully",
+	MsgWebhookRetrieved:                 "Webhook retrieved successfully",
+	MsgWebhookUpdated:                   "Webhook updated successfully",
+	MsgWebhooksRetrieved:                "Webhooks retrieved successfully",
+	MsgWorkLogSummaryRetrieved:          "Work log summary retrieved successfully",
+	MsgWorkLogged:                       "Work logged successfully",
+	MsgWorkLogsRetrieved:                "Work logs retrieved successfully",
+}
@@ -0,0 +1,57 @@
+package Domain
+
+// amharicMessages translates the messages users see most often; the rest are sent in English
+var amharicMessages = map[string]string{
+	MsgAccessDenied:                "መዳረሻ ተከልክሏል",
+	MsgAccountDeactivated:          "መለያው ታግዷል",
+	MsgAssignedTasksRetrieved:      "የተመደቡልዎት ተግባራት በተሳካ ሁኔታ ተገኝተዋል",
+	MsgAttachmentDeleted:           "የተያያዘው ፋይል በተሳካ ሁኔታ ተሰርዟል",
+	MsgAttachmentUploaded:          "ፋይሉ በተሳካ ሁኔታ ተያይዟል",
+	MsgAttachmentsRetrieved:        "የተያያዙ ፋይሎች በተሳካ ሁኔታ ተገኝተዋል",
+	MsgAuthenticationFailed:        "ማረጋገጥ አልተሳካም",
+	MsgAuthorizationHeaderRequired: "የፈቃድ ራስጌ ያስፈልጋል",
+	MsgCallerNotFound:              "የተጠቃሚ መለያ በቶክኑ ውስጥ አልተገኘም",
+	MsgCommentAdded:                "አስተያየቱ በተሳካ ሁኔታ ተጨምሯል",
+	MsgCommentsRetrieved:           "አስተያየቶቹ በተሳካ ሁኔታ ተገኝተዋል",
+	MsgCreateTaskFailed:            "ተግባሩን መፍጠር አልተቻለም",
+	MsgDeleteTaskFailed:            "ተግባሩን መሰረዝ አልተቻለም",
+	MsgEmailVerified:               "ኢሜይሉ በተሳካ ሁኔታ ተረጋግጧል",
+	MsgInvalidAPIKey:               "ልክ ያልሆነ የኤፒአይ ቁልፍ",
+	MsgInvalidFilterParameters:     "ልክ ያልሆኑ የማጣሪያ መለኪያዎች",
+	MsgInvalidOrExpiredToken:       "ልክ ያልሆነ ወይም ጊዜው ያለፈበት ቶክን",
+	MsgInvalidPaginationParameters: "ልክ ያልሆኑ የገጽ መለኪያዎች",
+	MsgInvalidQueryParameters:      "ልክ ያልሆኑ የጥያቄ መለኪያዎች",
+	MsgInvalidRequestPayload:       "ልክ ያልሆነ የጥያቄ ይዘት",
+	MsgLoggedIn:                    "በተሳካ ሁኔታ ገብተዋል",
+	MsgLoggedOut:                   "በተሳካ ሁኔታ ወጥተዋል",
+	MsgMethodNotAllowed:            "የጥያቄው ዘዴ አይፈቀድም",
+	MsgOverdueTasksRetrieved:       "ጊዜያቸው ያለፈ ተግባራት በተሳካ ሁኔታ ተገኝተዋል",
+	MsgPasswordChanged:             "የይለፍ ቃሉ በተሳካ ሁኔታ ተቀይሯል",
+	MsgPasswordReset:               "የይለፍ ቃሉ በተሳካ ሁኔታ ዳግም ተቀናብሯል",
+	MsgPasswordResetRequested:      "መለያው ካለ የይለፍ ቃል ዳግም ማስጀመሪያ መመሪያዎች ተልከዋል",
+	MsgProfileRetrieved:            "መገለጫው በተሳካ ሁኔታ ተገኝቷል",
+	MsgProfileUpdated:              "መገለጫው በተሳካ ሁኔታ ተዘምኗል",
+	MsgRequestBodyTooLarge:         "የጥያቄው ይዘት በጣም ትልቅ ነው",
+	MsgRequestTimedOut:             "የጥያቄው ጊዜ አልፏል",
+	MsgRetrieveTasksFailed:         "ተግባራቱን ማግኘት አልተቻለም",
+	MsgRouteNotFound:               "የተጠየቀው መንገድ አልተገኘም",
+	MsgTagsRetrieved:               "መለያዎቹ በተሳካ ሁኔታ ተገኝተዋል",
+	MsgTaskCreated:                 "ተግባሩ በተሳካ ሁኔታ ተፈጥሯል",
+	MsgTaskDeleted:                 "ተግባሩ በተሳካ ሁኔታ ተሰርዟል",
+	MsgTaskNotFound:                "ተግባሩ አልተገኘም",
+	MsgTaskRestored:                "ተግባሩ በተሳካ ሁኔታ ተመልሷል",
+	MsgTaskRetrieved:               "ተግባሩ በተሳካ ሁኔታ ተገኝቷል",
+	MsgTaskStatusUpdated:           "የተግባሩ ሁኔታ በተሳካ ሁኔታ ተዘምኗል",
+	MsgTaskUpdated:                 "ተግባሩ በተሳካ ሁኔታ ተዘምኗል",
+	MsgTasksCreated:                "ተግባራቱ በተሳካ ሁኔታ ተፈጥረዋል",
+	MsgTasksDeleted:                "ተግባራቱ በተሳካ ሁኔታ ተሰርዘዋል",
+	MsgTasksRetrieved:              "ተግባራቱ በተሳካ ሁኔታ ተገኝተዋል",
+	MsgTokenRefreshed:              "ቶክኑ በተሳካ ሁኔታ ታድሷል",
+	MsgTooManyRequests:             "በጣም ብዙ ጥያቄዎች",
+	MsgUpdateTaskFailed:            "ተግባሩን ማዘመን አልተቻለም",
+	MsgUserDeleted:                 "ተጠቃሚው በተሳካ ሁኔታ ተሰርዟል",
+	MsgUserDemoted:                 "ተጠቃሚው በተሳካ ሁኔታ ዝቅ ብሏል",
+	MsgUserPromoted:                "ተጠቃሚው በተሳካ ሁኔታ ወደ %s ከፍ ብሏል",
+	MsgUserRegistered:              "ተጠቃሚው በተሳካ ሁኔታ ተመዝግቧል",
+	MsgUsersRetrieved:              "ተጠቃሚዎቹ በተሳካ ሁኔታ ተገኝተዋል",
+}
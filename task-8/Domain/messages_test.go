@@ -0,0 +1,70 @@
+package Domain
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name     string
+		locale   string
+		id       string
+		args     []interface{}
+		expected string
+	}{
+		{name: "English", locale: "en", id: MsgTaskCreated, expected: "Task created successfully"},
+		{name: "Amharic", locale: "am", id: MsgTaskCreated, expected: "ተግባሩ በተሳካ ሁኔታ ተፈጥሯል"},
+		{name: "Message missing from a locale falls back to English", locale: "am", id: MsgBuildReportFailed, expected: "Failed to build report"},
+		{name: "Unknown locale falls back to English", locale: "fr", id: MsgTaskCreated, expected: "Task created successfully"},
+		{name: "Unknown ID is returned as is", locale: "en", id: "no_such_message", expected: "no_such_message"},
+		{name: "Arguments fill the message", locale: "am", id: MsgUserPromoted, args: []interface{}{RoleManager}, expected: "ተጠቃሚው በተሳካ ሁኔታ ወደ manager ከፍ ብሏል"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Translate(tt.locale, tt.id, tt.args...))
+		})
+	}
+}
+
+func TestMessageCatalogs(t *testing.T) {
+	// Every translation must be of a message the English catalog has, with the same arguments
+	for locale, catalog := range messageCatalogs {
+		for id, text := range catalog {
+			english, ok := englishMessages[id]
+			if assert.True(t, ok, "%s translates unknown message %q", locale, id) {
+				assert.Equal(t, verbCount(english), verbCount(text), "%s message %q", locale, id)
+			}
+		}
+	}
+	for _, locale := range SupportedLocales {
+		assert.True(t, IsSupportedLocale(locale))
+	}
+}
+
+// verbCount counts the formatting verbs in a message
+func verbCount(text string) int {
+	count := 0
+	for i := 0; i < len(text)-1; i++ {
+		if text[i] == '%' {
+			count++
+			i++
+		}
+	}
+	return count
+}
+
+func TestLocalizeMessage(t *testing.T) {
+	// Arrange
+	ctx := WithLocale(context.Background(), "AM")
+
+	// Act
+	message := LocalizeMessage(ctx, MsgTaskNotFound)
+
+	// Assert
+	assert.Equal(t, Message{ID: MsgTaskNotFound, Text: "ተግባሩ አልተገኘም"}, message)
+	assert.Equal(t, DefaultLocale, LocaleFromContext(context.Background()))
+}
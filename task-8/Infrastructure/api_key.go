@@ -0,0 +1,28 @@
+package Infrastructure
+
+import (
+	"context"
+
+	"task_manager/Domain"
+)
+
+// APIKeyHeader carries an API key as an alternative to a Bearer token
+const APIKeyHeader = "X-API-Key"
+
+// apiKeyPrefix marks API keys so a leaked one is easy to recognize in logs and code
+const apiKeyPrefix = "tm_"
+
+// APIKeyValidator resolves the API key sent in APIKeyHeader. It returns an error starting with
+// "invalid API key" or "API key revoked" when the key must be rejected.
+type APIKeyValidator interface {
+	ValidateAPIKey(ctx context.Context, key string) (*Domain.APIKey, error)
+}
+
+// GenerateAPIKey returns a new random API key. Store only its HashOpaqueToken hash.
+func GenerateAPIKey() (string, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", err
+	}
+	return apiKeyPrefix + token, nil
+}
@@ -0,0 +1,183 @@
+package Infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Argon2id defaults, overridable with ARGON2_MEMORY, ARGON2_TIME, ARGON2_PARALLELISM and ARGON2_SALT_LENGTH.
+// They follow the second recommended option of RFC 9106 with fewer lanes.
+const (
+	DefaultArgon2Memory      uint32 = 64 * 1024 // KiB
+	DefaultArgon2Time        uint32 = 3
+	DefaultArgon2Parallelism uint8  = 2
+	DefaultArgon2SaltLength  uint32 = 16
+	argon2KeyLength          uint32 = 32
+)
+
+// argon2idPrefix starts every hash produced by Argon2PasswordService
+const argon2idPrefix = "$argon2id$"
+
+// ErrMismatchedArgon2Password is returned when a password does not match an Argon2id hash
+var ErrMismatchedArgon2Password = errors.New("argon2id: hashedPassword is not the hash of the given password")
+
+// Argon2PasswordService implements PasswordServiceInterface with Argon2id. Hashes use the
+// standard encoding $argon2id$v=19$m=<KiB>,t=<passes>,p=<lanes>$<salt>$<key>, so they carry their own parameters.
+type Argon2PasswordService struct {
+	memory      uint32
+	time        uint32
+	parallelism uint8
+	saltLength  uint32
+}
+
+// NewArgon2PasswordService creates a new instance of Argon2PasswordService from the environment.
+// Missing, invalid or zero values fall back to the defaults.
+func NewArgon2PasswordService() *Argon2PasswordService {
+	return &Argon2PasswordService{
+		memory:      uint32(uintFromEnv("ARGON2_MEMORY", uint64(DefaultArgon2Memory), 32)),
+		time:        uint32(uintFromEnv("ARGON2_TIME", uint64(DefaultArgon2Time), 32)),
+		parallelism: uint8(uintFromEnv("ARGON2_PARALLELISM", uint64(DefaultArgon2Parallelism), 8)),
+		saltLength:  uint32(uintFromEnv("ARGON2_SALT_LENGTH", uint64(DefaultArgon2SaltLength), 32)),
+	}
+}
+
+// uintFromEnv parses a positive integer that fits in bitSize bits, keeping the default otherwise
+func uintFromEnv(name string, defaultValue uint64, bitSize int) uint64 {
+	if value, err := strconv.ParseUint(os.Getenv(name), 10, bitSize); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// HashPassword hashes a plain text password with a random salt
+func (as *Argon2PasswordService) HashPassword(password string) (string, error) {
+	salt := make([]byte, as.saltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, as.time, as.memory, as.parallelism, argon2KeyLength)
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, as.memory, as.time, as.parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// ComparePassword compares an Argon2id hash with a plain text password, using the parameters stored in the hash
+func (as *Argon2PasswordService) ComparePassword(hashedPassword, password string) error {
+	params, salt, key, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return err
+	}
+
+	candidate := argon2.IDKey([]byte(password), salt, params.time, params.memory, params.parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(candidate, key) != 1 {
+		return ErrMismatchedArgon2Password
+	}
+	return nil
+}
+
+// NeedsRehash reports whether a hash was made with parameters other than the configured ones.
+// Malformed hashes are left alone, since comparing against them fails anyway.
+func (as *Argon2PasswordService) NeedsRehash(hashedPassword string) bool {
+	params, salt, key, err := decodeArgon2Hash(hashedPassword)
+	if err != nil {
+		return false
+	}
+	return params.memory != as.memory || params.time != as.time || params.parallelism != as.parallelism ||
+		uint32(len(salt)) != as.saltLength || uint32(len(key)) != argon2KeyLength
+}
+
+// decodeArgon2Hash splits an encoded Argon2id hash into its parameters, salt and key
+func decodeArgon2Hash(hashedPassword string) (*Argon2PasswordService, []byte, []byte, error) {
+	parts := strings.Split(hashedPassword, "$")
+	if len(parts) != 6 || parts[0] != "" || parts[1] != "argon2id" {
+		return nil, nil, nil, errors.New("argon2id: malformed hash")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, errors.New("argon2id: malformed hash version")
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("argon2id: unsupported version %d", version)
+	}
+
+	params := &Argon2PasswordService{}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.memory, &params.time, &params.parallelism); err != nil {
+		return nil, nil, nil, errors.New("argon2id: malformed hash parameters")
+	}
+	if params.memory == 0 || params.time == 0 || params.parallelism == 0 {
+		return nil, nil, nil, errors.New("argon2id: malformed hash parameters")
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return nil, nil, nil, errors.New("argon2id: malformed hash salt")
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return nil, nil, nil, errors.New("argon2id: malformed hash key")
+	}
+
+	params.saltLength = uint32(len(salt))
+	return params, salt, key, nil
+}
+
+// CompositePasswordService hashes new passwords with Argon2id and verifies both Argon2id
+// and bcrypt hashes, telling them apart by prefix. bcrypt hashes always need a rehash,
+// so existing accounts move to Argon2id as their users log in.
+type CompositePasswordService struct {
+	argon2 *Argon2PasswordService
+	bcrypt PasswordServiceInterface
+}
+
+// NewCompositePasswordService creates a new instance of CompositePasswordService
+func NewCompositePasswordService(argon2 *Argon2PasswordService, bcrypt PasswordServiceInterface) *CompositePasswordService {
+	return &CompositePasswordService{
+		argon2: argon2,
+		bcrypt: bcrypt,
+	}
+}
+
+// HashPassword hashes a plain text password with Argon2id
+func (cs *CompositePasswordService) HashPassword(password string) (string, error) {
+	return cs.argon2.HashPassword(password)
+}
+
+// ComparePassword compares a hash in either format with a plain text password
+func (cs *CompositePasswordService) ComparePassword(hashedPassword, password string) error {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return cs.argon2.ComparePassword(hashedPassword, password)
+	}
+	return cs.bcrypt.ComparePassword(hashedPassword, password)
+}
+
+// NeedsRehash reports whether a hash is a bcrypt hash or an Argon2id hash with outdated parameters
+func (cs *CompositePasswordService) NeedsRehash(hashedPassword string) bool {
+	if strings.HasPrefix(hashedPassword, argon2idPrefix) {
+		return cs.argon2.NeedsRehash(hashedPassword)
+	}
+	_, err := bcrypt.Cost([]byte(hashedPassword))
+	return err == nil
+}
+
+// NewPasswordServiceForAlgorithm returns the password service for PASSWORD_HASH_ALGORITHM:
+// bcrypt (the default) or argon2id, which still accepts existing bcrypt hashes
+func NewPasswordServiceForAlgorithm(algorithm string) (PasswordServiceInterface, error) {
+	switch strings.ToLower(algorithm) {
+	case "", "bcrypt":
+		return NewPasswordService(), nil
+	case "argon2id":
+		return NewCompositePasswordService(NewArgon2PasswordService(), NewPasswordService()), nil
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q, use bcrypt or argon2id", algorithm)
+	}
+}
@@ -0,0 +1,224 @@
+package Infrastructure
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// newTestArgon2Service keeps the parameters small so the tests stay fast
+func newTestArgon2Service() *Argon2PasswordService {
+	return &Argon2PasswordService{memory: 1024, time: 1, parallelism: 1, saltLength: 16}
+}
+
+func TestNewArgon2PasswordService(t *testing.T) {
+	t.Run("Unset uses the defaults", func(t *testing.T) {
+		t.Setenv("ARGON2_MEMORY", "")
+		t.Setenv("ARGON2_TIME", "")
+		t.Setenv("ARGON2_PARALLELISM", "")
+		t.Setenv("ARGON2_SALT_LENGTH", "")
+
+		service := NewArgon2PasswordService()
+
+		assert.Equal(t, DefaultArgon2Memory, service.memory)
+		assert.Equal(t, DefaultArgon2Time, service.time)
+		assert.Equal(t, DefaultArgon2Parallelism, service.parallelism)
+		assert.Equal(t, DefaultArgon2SaltLength, service.saltLength)
+	})
+
+	t.Run("Configured parameters", func(t *testing.T) {
+		t.Setenv("ARGON2_MEMORY", "32768")
+		t.Setenv("ARGON2_TIME", "2")
+		t.Setenv("ARGON2_PARALLELISM", "4")
+		t.Setenv("ARGON2_SALT_LENGTH", "24")
+
+		service := NewArgon2PasswordService()
+
+		assert.Equal(t, uint32(32768), service.memory)
+		assert.Equal(t, uint32(2), service.time)
+		assert.Equal(t, uint8(4), service.parallelism)
+		assert.Equal(t, uint32(24), service.saltLength)
+	})
+
+	t.Run("Invalid values use the defaults", func(t *testing.T) {
+		t.Setenv("ARGON2_MEMORY", "lots")
+		t.Setenv("ARGON2_TIME", "0")
+		t.Setenv("ARGON2_PARALLELISM", "300")
+		t.Setenv("ARGON2_SALT_LENGTH", "-1")
+
+		service := NewArgon2PasswordService()
+
+		assert.Equal(t, DefaultArgon2Memory, service.memory)
+		assert.Equal(t, DefaultArgon2Time, service.time)
+		assert.Equal(t, DefaultArgon2Parallelism, service.parallelism)
+		assert.Equal(t, DefaultArgon2SaltLength, service.saltLength)
+	})
+}
+
+func TestArgon2PasswordService_HashAndCompare(t *testing.T) {
+	service := newTestArgon2Service()
+
+	t.Run("Success - hash uses the standard encoding", func(t *testing.T) {
+		// Act
+		hashedPassword, err := service.HashPassword("password123")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(hashedPassword, "$argon2id$v=19$m=1024,t=1,p=1$"))
+		assert.Len(t, strings.Split(hashedPassword, "$"), 6)
+	})
+
+	t.Run("Success - salts differ between hashes", func(t *testing.T) {
+		// Act
+		first, _ := service.HashPassword("password123")
+		second, _ := service.HashPassword("password123")
+
+		// Assert
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("Success - correct password", func(t *testing.T) {
+		// Arrange
+		hashedPassword, _ := service.HashPassword("password123")
+
+		// Act
+		err := service.ComparePassword(hashedPassword, "password123")
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - hash made with other parameters", func(t *testing.T) {
+		// Arrange
+		other := &Argon2PasswordService{memory: 2048, time: 2, parallelism: 2, saltLength: 8}
+		hashedPassword, _ := other.HashPassword("password123")
+
+		// Act
+		err := service.ComparePassword(hashedPassword, "password123")
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - wrong password", func(t *testing.T) {
+		// Arrange
+		hashedPassword, _ := service.HashPassword("password123")
+
+		// Act
+		err := service.ComparePassword(hashedPassword, "wrongpassword")
+
+		// Assert
+		assert.Equal(t, ErrMismatchedArgon2Password, err)
+	})
+
+	t.Run("Error - malformed hashes", func(t *testing.T) {
+		hashedPassword, _ := service.HashPassword("password123")
+		parts := strings.Split(hashedPassword, "$")
+
+		tests := []struct {
+			name string
+			hash string
+			want string
+		}{
+			{"Empty", "", "argon2id: malformed hash"},
+			{"bcrypt hash", "$2a$10$abcdefghijklmnopqrstuuPvWvYwEY5EzMq6T7PSXT3C6pWnRv0ia", "argon2id: malformed hash"},
+			{"Missing key", strings.Join(parts[:5], "$"), "argon2id: malformed hash"},
+			{"argon2i variant", strings.Replace(hashedPassword, "argon2id", "argon2i", 1), "argon2id: malformed hash"},
+			{"Unsupported version", strings.Replace(hashedPassword, "v=19", "v=16", 1), "argon2id: unsupported version 16"},
+			{"Unreadable version", strings.Replace(hashedPassword, "v=19", "v=x", 1), "argon2id: malformed hash version"},
+			{"Unreadable parameters", strings.Replace(hashedPassword, parts[3], "m=a,t=1,p=1", 1), "argon2id: malformed hash parameters"},
+			{"Zero parameters", strings.Replace(hashedPassword, parts[3], "m=1024,t=0,p=1", 1), "argon2id: malformed hash parameters"},
+			{"Invalid salt", strings.Replace(hashedPassword, parts[4], "!!!", 1), "argon2id: malformed hash salt"},
+			{"Invalid key", strings.Replace(hashedPassword, parts[5], "!!!", 1), "argon2id: malformed hash key"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := service.ComparePassword(tt.hash, "password123")
+				assert.EqualError(t, err, tt.want)
+			})
+		}
+	})
+}
+
+func TestArgon2PasswordService_NeedsRehash(t *testing.T) {
+	service := newTestArgon2Service()
+	currentHash, _ := service.HashPassword("password123")
+	weakerHash, _ := (&Argon2PasswordService{memory: 512, time: 1, parallelism: 1, saltLength: 16}).HashPassword("password123")
+	shortSaltHash, _ := (&Argon2PasswordService{memory: 1024, time: 1, parallelism: 1, saltLength: 8}).HashPassword("password123")
+
+	assert.False(t, service.NeedsRehash(currentHash), "same parameters")
+	assert.True(t, service.NeedsRehash(weakerHash), "different memory")
+	assert.True(t, service.NeedsRehash(shortSaltHash), "different salt length")
+	assert.False(t, service.NeedsRehash("not-an-argon2-hash"), "malformed hash")
+}
+
+func TestCompositePasswordService(t *testing.T) {
+	bcryptService := &PasswordService{cost: bcrypt.MinCost}
+	service := NewCompositePasswordService(newTestArgon2Service(), bcryptService)
+	bcryptHash, _ := bcryptService.HashPassword("password123")
+
+	t.Run("Success - new hashes use argon2id", func(t *testing.T) {
+		// Act
+		hashedPassword, err := service.HashPassword("password123")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(hashedPassword, "$argon2id$"))
+		assert.NoError(t, service.ComparePassword(hashedPassword, "password123"))
+		assert.False(t, service.NeedsRehash(hashedPassword))
+	})
+
+	t.Run("Success - bcrypt hash is compared with bcrypt", func(t *testing.T) {
+		// Act
+		err := service.ComparePassword(bcryptHash, "password123")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, service.NeedsRehash(bcryptHash))
+	})
+
+	t.Run("Error - wrong password for either format", func(t *testing.T) {
+		// Arrange
+		argon2Hash, _ := service.HashPassword("password123")
+
+		// Act & Assert
+		assert.Equal(t, ErrMismatchedArgon2Password, service.ComparePassword(argon2Hash, "wrongpassword"))
+		assert.Equal(t, bcrypt.ErrMismatchedHashAndPassword, service.ComparePassword(bcryptHash, "wrongpassword"))
+	})
+
+	t.Run("Error - malformed hash", func(t *testing.T) {
+		// Act & Assert
+		assert.EqualError(t, service.ComparePassword("$argon2id$v=19$broken", "password123"), "argon2id: malformed hash")
+		assert.Error(t, service.ComparePassword("not-a-hash", "password123"))
+		assert.False(t, service.NeedsRehash("not-a-hash"))
+	})
+}
+
+func TestNewPasswordServiceForAlgorithm(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		want      interface{}
+	}{
+		{"", &PasswordService{}},
+		{"bcrypt", &PasswordService{}},
+		{"argon2id", &CompositePasswordService{}},
+		{"ARGON2ID", &CompositePasswordService{}},
+	}
+
+	for _, tt := range tests {
+		t.Run("Success - "+tt.algorithm, func(t *testing.T) {
+			service, err := NewPasswordServiceForAlgorithm(tt.algorithm)
+			assert.NoError(t, err)
+			assert.IsType(t, tt.want, service)
+		})
+	}
+
+	t.Run("Error - unsupported algorithm", func(t *testing.T) {
+		service, err := NewPasswordServiceForAlgorithm("md5")
+		assert.EqualError(t, err, `unsupported password hash algorithm "md5", use bcrypt or argon2id`)
+		assert.Nil(t, service)
+	})
+}
@@ -1,6 +1,7 @@
 package Infrastructure
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 
@@ -12,24 +13,35 @@ import (
 // AuthMiddleware provides authentication and authorization middleware
 type AuthMiddleware struct {
 	jwtService JWTServiceInterface
+	blacklist  TokenBlacklist
+	userStatus UserStatusChecker
+	apiKeys    APIKeyValidator
 }
 
 // NewAuthMiddleware creates a new instance of AuthMiddleware
-func NewAuthMiddleware(jwtService JWTServiceInterface) *AuthMiddleware {
+func NewAuthMiddleware(jwtService JWTServiceInterface, blacklist TokenBlacklist, userStatus UserStatusChecker, apiKeys APIKeyValidator) *AuthMiddleware {
 	return &AuthMiddleware{
 		jwtService: jwtService,
+		blacklist:  blacklist,
+		userStatus: userStatus,
+		apiKeys:    apiKeys,
 	}
 }
 
-// AuthenticateToken validates JWT tokens
+// AuthenticateToken validates JWT tokens, or the API key in the X-API-Key header when one is sent
 func (am *AuthMiddleware) AuthenticateToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := c.GetHeader(APIKeyHeader); apiKey != "" {
+			am.authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
-			c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
-				Success: false,
-				Message: "Authorization header required",
-				Error:   "Missing Authorization header",
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgAuthorizationHeaderRequired,
+				Error:     "Missing Authorization header",
 			})
 			c.Abort()
 			return
@@ -38,10 +50,10 @@ func (am *AuthMiddleware) AuthenticateToken() gin.HandlerFunc {
 		// Extract token from "Bearer <token>"
 		tokenParts := strings.Split(authHeader, " ")
 		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
-			c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
-				Success: false,
-				Message: "Invalid authorization header format",
-				Error:   "Authorization header must be in format: Bearer <token>",
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidAuthorizationHeaderFormat,
+				Error:     "Authorization header must be in format: Bearer <token>",
 			})
 			c.Abort()
 			return
@@ -56,10 +68,10 @@ func (am *AuthMiddleware) AuthenticateToken() gin.HandlerFunc {
 			if err != nil {
 				errorMsg = err.Error()
 			}
-			c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
-				Success: false,
-				Message: "Invalid or expired token",
-				Error:   errorMsg,
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidOrExpiredToken,
+				Error:     errorMsg,
 			})
 			c.Abort()
 			return
@@ -68,10 +80,69 @@ func (am *AuthMiddleware) AuthenticateToken() gin.HandlerFunc {
 		// Extract claims
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
-			c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
-				Success: false,
-				Message: "Invalid token claims",
-				Error:   "Could not parse token claims",
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidTokenClaims,
+				Error:     "Could not parse token claims",
+			})
+			c.Abort()
+			return
+		}
+
+		// Reject tokens revoked by logout. Tokens issued before JTIs were added cannot be revoked.
+		if jti, _ := claims["jti"].(string); jti != "" {
+			revoked, err := am.blacklist.Contains(c.Request.Context(), jti)
+			if err != nil {
+				respondError(c, http.StatusInternalServerError, Domain.ErrorResponse{
+					Success:   false,
+					MessageID: Domain.MsgVerifyTokenFailed,
+					Error:     err.Error(),
+				})
+				c.Abort()
+				return
+			}
+			if revoked {
+				respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+					Success:   false,
+					MessageID: Domain.MsgInvalidOrExpiredToken,
+					Error:     "Token has been revoked",
+				})
+				c.Abort()
+				return
+			}
+
+			// Kept for logout, which revokes the token until it expires
+			c.Set("token_id", jti)
+			if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+				c.Set("token_expires_at", exp.Time)
+			}
+		}
+
+		// Tokens stop working as soon as their user is deactivated or deleted
+		userID, _ := claims["user_id"].(string)
+		active, err := am.userStatus.IsActive(c.Request.Context(), userID)
+		if err != nil {
+			if errors.Is(err, Domain.ErrUserNotFound) || errors.Is(err, Domain.ErrInvalidUserID) {
+				respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+					Success:   false,
+					MessageID: Domain.MsgInvalidOrExpiredToken,
+					Error:     "User no longer exists",
+				})
+			} else {
+				respondError(c, http.StatusInternalServerError, Domain.ErrorResponse{
+					Success:   false,
+					MessageID: Domain.MsgVerifyTokenFailed,
+					Error:     err.Error(),
+				})
+			}
+			c.Abort()
+			return
+		}
+		if !active {
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgAccountDeactivated,
+				Error:     "This account has been deactivated",
 			})
 			c.Abort()
 			return
@@ -86,54 +157,58 @@ func (am *AuthMiddleware) AuthenticateToken() gin.HandlerFunc {
 	}
 }
 
-// RequireAdmin ensures only admin users can access the endpoint
-func (am *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role, exists := c.Get("role")
-		if !exists {
-			c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
-				Success: false,
-				Message: "User role not found",
-				Error:   "Authentication required",
+// authenticateAPIKey identifies the caller by an API key. The key's ID takes the place of the
+// user ID, so tasks and audit entries written through a key are attributed to the key.
+func (am *AuthMiddleware) authenticateAPIKey(c *gin.Context, apiKey string) {
+	key, err := am.apiKeys.ValidateAPIKey(c.Request.Context(), apiKey)
+	if err != nil {
+		if errors.Is(err, Domain.ErrInvalidAPIKey) || errors.Is(err, Domain.ErrAPIKeyRevoked) {
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgInvalidAPIKey,
+				Error:     err.Error(),
 			})
-			c.Abort()
-			return
-		}
-
-		if role != Domain.RoleAdmin {
-			c.JSON(http.StatusForbidden, Domain.ErrorResponse{
-				Success: false,
-				Message: "Access denied",
-				Error:   "Admin privileges required",
+		} else {
+			respondError(c, http.StatusInternalServerError, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgVerifyAPIKeyFailed,
+				Error:     err.Error(),
 			})
-			c.Abort()
-			return
 		}
-
-		c.Next()
+		c.Abort()
+		return
 	}
+
+	c.Set("user_id", key.ID.Hex())
+	c.Set("username", key.Name)
+	c.Set("role", key.Role)
+	c.Set("api_key_id", key.ID.Hex())
+
+	c.Next()
 }
 
-// RequireUser ensures authenticated users (both admin and regular users) can access the endpoint
-func (am *AuthMiddleware) RequireUser() gin.HandlerFunc {
+// RequirePermission ensures the caller's role grants the permission. Roles without an
+// entry in the permission map, such as one carried by a token issued before the role was
+// removed, are denied.
+func (am *AuthMiddleware) RequirePermission(permission string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role, exists := c.Get("role")
 		if !exists {
-			c.JSON(http.StatusUnauthorized, Domain.ErrorResponse{
-				Success: false,
-				Message: "User role not found",
-				Error:   "Authentication required",
+			respondError(c, http.StatusUnauthorized, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgUserRoleNotFound,
+				Error:     "Authentication required",
 			})
 			c.Abort()
 			return
 		}
 
-		// Both admin and user roles are allowed
-		if role != Domain.RoleAdmin && role != Domain.RoleUser {
-			c.JSON(http.StatusForbidden, Domain.ErrorResponse{
-				Success: false,
-				Message: "Access denied",
-				Error:   "Valid user role required",
+		roleName, _ := role.(string)
+		if !Domain.HasPermission(roleName, permission) {
+			respondError(c, http.StatusForbidden, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgAccessDenied,
+				Error:     "Permission " + permission + " required",
 			})
 			c.Abort()
 			return
@@ -141,4 +216,4 @@ func (am *AuthMiddleware) RequireUser() gin.HandlerFunc {
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
@@ -1,15 +1,19 @@
 package Infrastructure
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"task_manager/Domain"
 )
@@ -37,6 +41,37 @@ func (m *MockJWTServiceForAuth) GetJWTSecret() []byte {
 	return args.Get(0).([]byte)
 }
 
+func (m *MockJWTServiceForAuth) GenerateRefreshToken() (string, time.Time, error) {
+	args := m.Called()
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockJWTServiceForAuth) AccessTokenTTL() time.Duration {
+	args := m.Called()
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockJWTServiceForAuth) JWKS() *Domain.JSONWebKeySet {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*Domain.JSONWebKeySet)
+}
+
+func (m *MockJWTServiceForAuth) GenerateEmailVerificationToken(user *Domain.User) (string, time.Time, error) {
+	args := m.Called(user)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockJWTServiceForAuth) ValidateEmailVerificationToken(tokenString string) (*Domain.EmailVerificationClaims, error) {
+	args := m.Called(tokenString)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.EmailVerificationClaims), args.Error(1)
+}
+
 func setupAuthTestRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
 	return gin.New()
@@ -46,7 +81,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 	t.Run("Success - valid token", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		// Create a valid token with claims
@@ -66,7 +101,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 			userID, _ := c.Get("user_id")
 			username, _ := c.Get("username")
 			role, _ := c.Get("role")
-			
+
 			c.JSON(http.StatusOK, gin.H{
 				"user_id":  userID,
 				"username": username,
@@ -83,21 +118,21 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
+
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.Equal(t, "507f1f77bcf86cd799439011", response["user_id"])
 		assert.Equal(t, "testuser", response["username"])
 		assert.Equal(t, Domain.RoleUser, response["role"])
-		
+
 		mockJWTService.AssertExpectations(t)
 	})
 
 	t.Run("Error - missing authorization header", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
@@ -112,7 +147,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
@@ -124,7 +159,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 	t.Run("Error - invalid authorization header format", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
@@ -140,7 +175,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
@@ -152,7 +187,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 	t.Run("Error - wrong bearer format", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
@@ -168,7 +203,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
@@ -179,7 +214,7 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 	t.Run("Error - invalid token", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		mockJWTService.On("ValidateToken", "invalid.token").Return(nil, jwt.ErrSignatureInvalid)
@@ -197,20 +232,20 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.False(t, response.Success)
 		assert.Equal(t, "Invalid or expired token", response.Message)
-		
+
 		mockJWTService.AssertExpectations(t)
 	})
 
 	t.Run("Error - token not valid", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		// Create an invalid token
@@ -233,20 +268,20 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.False(t, response.Success)
 		assert.Equal(t, "Invalid or expired token", response.Message)
-		
+
 		mockJWTService.AssertExpectations(t)
 	})
 
 	t.Run("Error - invalid token claims", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		// Create a token with invalid claims type
@@ -270,59 +305,79 @@ func TestAuthMiddleware_AuthenticateToken(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.False(t, response.Success)
 		assert.Equal(t, "Invalid token claims", response.Message)
 		assert.Equal(t, "Could not parse token claims", response.Error)
-		
+
 		mockJWTService.AssertExpectations(t)
 	})
 }
 
-func TestAuthMiddleware_RequireAdmin(t *testing.T) {
-	t.Run("Success - admin user", func(t *testing.T) {
-		// Arrange
-		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
-		router := setupAuthTestRouter()
+func TestAuthMiddleware_RequirePermission(t *testing.T) {
+	testCases := []struct {
+		name         string
+		role         string
+		permission   string
+		expectedCode int
+	}{
+		{"Success - admin manages users", Domain.RoleAdmin, Domain.PermissionUsersManage, http.StatusOK},
+		{"Success - manager writes tasks", Domain.RoleManager, Domain.PermissionTasksWrite, http.StatusOK},
+		{"Success - user reads tasks", Domain.RoleUser, Domain.PermissionTasksRead, http.StatusOK},
+		{"Error - manager cannot manage users", Domain.RoleManager, Domain.PermissionUsersManage, http.StatusForbidden},
+		{"Error - user cannot write tasks", Domain.RoleUser, Domain.PermissionTasksWrite, http.StatusForbidden},
+		{"Error - unknown role fails closed", "superuser", Domain.PermissionTasksRead, http.StatusForbidden},
+	}
 
-		router.Use(func(c *gin.Context) {
-			c.Set("role", Domain.RoleAdmin)
-			c.Next()
-		})
-		router.GET("/admin", authMiddleware.RequireAdmin(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "admin access granted"})
-		})
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockJWTService := new(MockJWTServiceForAuth)
+			authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
+			router := setupAuthTestRouter()
 
-		req := httptest.NewRequest("GET", "/admin", nil)
-		w := httptest.NewRecorder()
+			router.Use(func(c *gin.Context) {
+				c.Set("role", tc.role)
+				c.Next()
+			})
+			router.GET("/protected", authMiddleware.RequirePermission(tc.permission), func(c *gin.Context) {
+				c.JSON(http.StatusOK, gin.H{"message": "access granted"})
+			})
 
-		// Act
-		router.ServeHTTP(w, req)
+			req := httptest.NewRequest("GET", "/protected", nil)
+			w := httptest.NewRecorder()
 
-		// Assert
-		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "admin access granted", response["message"])
-	})
+			// Act
+			router.ServeHTTP(w, req)
+
+			// Assert
+			assert.Equal(t, tc.expectedCode, w.Code)
+
+			if tc.expectedCode == http.StatusForbidden {
+				var response Domain.ErrorResponse
+				err := json.Unmarshal(w.Body.Bytes(), &response)
+				assert.NoError(t, err)
+				assert.False(t, response.Success)
+				assert.Equal(t, "Access denied", response.Message)
+				assert.Equal(t, "Permission "+tc.permission+" required", response.Error)
+			}
+		})
+	}
 
 	t.Run("Error - role not found in context", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
-		router.GET("/admin", authMiddleware.RequireAdmin(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "admin access granted"})
+		router.GET("/protected", authMiddleware.RequirePermission(Domain.PermissionTasksRead), func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"message": "access granted"})
 		})
 
-		req := httptest.NewRequest("GET", "/admin", nil)
+		req := httptest.NewRequest("GET", "/protected", nil)
 		w := httptest.NewRecorder()
 
 		// Act
@@ -330,7 +385,7 @@ func TestAuthMiddleware_RequireAdmin(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
@@ -338,164 +393,267 @@ func TestAuthMiddleware_RequireAdmin(t *testing.T) {
 		assert.Equal(t, "User role not found", response.Message)
 		assert.Equal(t, "Authentication required", response.Error)
 	})
+}
 
-	t.Run("Error - regular user trying to access admin endpoint", func(t *testing.T) {
-		// Arrange
-		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
-		router := setupAuthTestRouter()
+// Test constructor
+func TestNewAuthMiddleware(t *testing.T) {
+	mockJWTService := new(MockJWTServiceForAuth)
+	blacklist := NewMemoryTokenBlacklist()
+	userStatus := allUsersActive()
+	authMiddleware := NewAuthMiddleware(mockJWTService, blacklist, userStatus, noAPIKeys())
 
-		router.Use(func(c *gin.Context) {
-			c.Set("role", Domain.RoleUser)
-			c.Next()
-		})
-		router.GET("/admin", authMiddleware.RequireAdmin(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "admin access granted"})
+	assert.NotNil(t, authMiddleware)
+	assert.Equal(t, mockJWTService, authMiddleware.jwtService)
+	assert.Equal(t, blacklist, authMiddleware.blacklist)
+	assert.Equal(t, userStatus, authMiddleware.userStatus)
+}
+
+// stubUserStatusChecker answers every status lookup with the same result
+type stubUserStatusChecker struct {
+	active bool
+	err    error
+	calls  int
+}
+
+func (s *stubUserStatusChecker) IsActive(ctx context.Context, userID string) (bool, error) {
+	s.calls++
+	return s.active, s.err
+}
+
+func allUsersActive() *stubUserStatusChecker {
+	return &stubUserStatusChecker{active: true}
+}
+
+// stubAPIKeyValidator knows a single API key
+type stubAPIKeyValidator struct {
+	key    string
+	apiKey *Domain.APIKey
+	err    error
+}
+
+func (s *stubAPIKeyValidator) ValidateAPIKey(ctx context.Context, key string) (*Domain.APIKey, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	if key != s.key {
+		return nil, Domain.ErrInvalidAPIKey
+	}
+	return s.apiKey, nil
+}
+
+func noAPIKeys() *stubAPIKeyValidator {
+	return &stubAPIKeyValidator{}
+}
+
+func TestAuthMiddleware_APIKey(t *testing.T) {
+	apiKey := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "nightly-report", Role: Domain.RoleManager}
+
+	request := func(validator APIKeyValidator, key string) (*httptest.ResponseRecorder, gin.H) {
+		// JWT validation must not be reached when an API key is sent
+		authMiddleware := NewAuthMiddleware(new(MockJWTServiceForAuth), NewMemoryTokenBlacklist(), allUsersActive(), validator)
+		router := setupAuthTestRouter()
+		var seen gin.H
+		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
+			seen = gin.H{"user_id": c.GetString("user_id"), "username": c.GetString("username"), "role": c.GetString("role")}
+			c.Status(http.StatusOK)
 		})
 
-		req := httptest.NewRequest("GET", "/admin", nil)
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set(APIKeyHeader, key)
 		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w, seen
+	}
 
+	t.Run("Success - valid key sets the caller", func(t *testing.T) {
 		// Act
-		router.ServeHTTP(w, req)
+		w, seen := request(&stubAPIKeyValidator{key: "tm_valid", apiKey: apiKey}, "tm_valid")
 
 		// Assert
-		assert.Equal(t, http.StatusForbidden, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Access denied", response.Message)
-		assert.Equal(t, "Admin privileges required", response.Error)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, gin.H{"user_id": apiKey.ID.Hex(), "username": "nightly-report", "role": Domain.RoleManager}, seen)
+	})
+
+	t.Run("Error - unknown key", func(t *testing.T) {
+		// Act
+		w, seen := request(&stubAPIKeyValidator{key: "tm_valid", apiKey: apiKey}, "tm_other")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Nil(t, seen)
+	})
+
+	t.Run("Error - revoked key", func(t *testing.T) {
+		// Act
+		w, seen := request(&stubAPIKeyValidator{err: Domain.ErrAPIKeyRevoked}, "tm_valid")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.Nil(t, seen)
+	})
+
+	t.Run("Error - lookup failure", func(t *testing.T) {
+		// Act
+		w, seen := request(&stubAPIKeyValidator{err: errors.New("database unavailable")}, "tm_valid")
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Nil(t, seen)
 	})
 }
 
-func TestAuthMiddleware_RequireUser(t *testing.T) {
-	t.Run("Success - admin user", func(t *testing.T) {
-		// Arrange
-		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
-		router := setupAuthTestRouter()
+func TestAuthMiddleware_UserStatus(t *testing.T) {
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
 
-		router.Use(func(c *gin.Context) {
-			c.Set("role", Domain.RoleAdmin)
-			c.Next()
-		})
-		router.GET("/user", authMiddleware.RequireUser(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "user access granted"})
+	request := func(userStatus UserStatusChecker) (*httptest.ResponseRecorder, bool) {
+		jwtService := NewJWTService()
+		token, err := jwtService.GenerateToken(user)
+		assert.NoError(t, err)
+
+		authMiddleware := NewAuthMiddleware(jwtService, NewMemoryTokenBlacklist(), userStatus, noAPIKeys())
+		router := setupAuthTestRouter()
+		reached := false
+		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
+			reached = true
+			c.Status(http.StatusOK)
 		})
 
-		req := httptest.NewRequest("GET", "/user", nil)
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
 		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w, reached
+	}
 
+	t.Run("Success - active user passes", func(t *testing.T) {
 		// Act
-		router.ServeHTTP(w, req)
+		w, reached := request(allUsersActive())
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "user access granted", response["message"])
+		assert.True(t, reached)
 	})
 
-	t.Run("Success - regular user", func(t *testing.T) {
-		// Arrange
-		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
-		router := setupAuthTestRouter()
+	t.Run("Error - deactivated user is rejected", func(t *testing.T) {
+		// Act
+		w, reached := request(&stubUserStatusChecker{active: false})
 
-		router.Use(func(c *gin.Context) {
-			c.Set("role", Domain.RoleUser)
-			c.Next()
-		})
-		router.GET("/user", authMiddleware.RequireUser(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "user access granted"})
-		})
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.False(t, reached)
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Account deactivated", response.Message)
+	})
 
-		req := httptest.NewRequest("GET", "/user", nil)
-		w := httptest.NewRecorder()
+	t.Run("Error - deleted user is rejected", func(t *testing.T) {
+		// Act
+		w, reached := request(&stubUserStatusChecker{err: Domain.ErrUserNotFound})
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		assert.False(t, reached)
+	})
 
+	t.Run("Error - status lookup failure", func(t *testing.T) {
 		// Act
-		router.ServeHTTP(w, req)
+		w, reached := request(&stubUserStatusChecker{err: errors.New("database unavailable")})
 
 		// Assert
-		assert.Equal(t, http.StatusOK, w.Code)
-		
-		var response map[string]interface{}
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.Equal(t, "user access granted", response["message"])
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.False(t, reached)
 	})
+}
 
-	t.Run("Error - role not found in context", func(t *testing.T) {
-		// Arrange
-		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
-		router := setupAuthTestRouter()
+// failingTokenBlacklist is a TokenBlacklist whose lookups always fail
+type failingTokenBlacklist struct{}
+
+func (failingTokenBlacklist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	return errors.New("blacklist unavailable")
+}
 
-		router.GET("/user", authMiddleware.RequireUser(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "user access granted"})
+func (failingTokenBlacklist) Contains(ctx context.Context, jti string) (bool, error) {
+	return false, errors.New("blacklist unavailable")
+}
+
+func TestAuthMiddleware_RevokedTokens(t *testing.T) {
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+
+	setup := func(blacklist TokenBlacklist) (*gin.Engine, *int) {
+		authMiddleware := NewAuthMiddleware(NewJWTService(), blacklist, allUsersActive(), noAPIKeys())
+		router := setupAuthTestRouter()
+		reached := 0
+		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
+			reached++
+			c.JSON(http.StatusOK, gin.H{"token_id": c.GetString("token_id")})
 		})
+		return router, &reached
+	}
 
-		req := httptest.NewRequest("GET", "/user", nil)
+	request := func(router *gin.Engine, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
 		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Error - logged-out token no longer reaches the handler", func(t *testing.T) {
+		// Arrange
+		blacklist := NewMemoryTokenBlacklist()
+		router, reached := setup(blacklist)
+		token, err := NewJWTService().GenerateToken(user)
+		assert.NoError(t, err)
+
+		before := request(router, token)
+		var body map[string]string
+		assert.NoError(t, json.Unmarshal(before.Body.Bytes(), &body))
+		assert.NoError(t, blacklist.Add(context.Background(), body["token_id"], time.Now().Add(time.Hour)))
 
 		// Act
-		router.ServeHTTP(w, req)
+		w := request(router, token)
 
 		// Assert
+		assert.Equal(t, http.StatusOK, before.Code)
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
-		
+		assert.Equal(t, 1, *reached)
+
 		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "User role not found", response.Message)
-		assert.Equal(t, "Authentication required", response.Error)
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Token has been revoked", response.Error)
 	})
 
-	t.Run("Error - invalid role", func(t *testing.T) {
+	t.Run("Success - other tokens of the same user still work", func(t *testing.T) {
 		// Arrange
-		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
-		router := setupAuthTestRouter()
-
-		router.Use(func(c *gin.Context) {
-			c.Set("role", "invalid_role")
-			c.Next()
-		})
-		router.GET("/user", authMiddleware.RequireUser(), func(c *gin.Context) {
-			c.JSON(http.StatusOK, gin.H{"message": "user access granted"})
-		})
+		blacklist := NewMemoryTokenBlacklist()
+		router, reached := setup(blacklist)
+		first, _ := NewJWTService().GenerateToken(user)
+		second, _ := NewJWTService().GenerateToken(user)
 
-		req := httptest.NewRequest("GET", "/user", nil)
-		w := httptest.NewRecorder()
+		var body map[string]string
+		assert.NoError(t, json.Unmarshal(request(router, first).Body.Bytes(), &body))
+		assert.NoError(t, blacklist.Add(context.Background(), body["token_id"], time.Now().Add(time.Hour)))
 
 		// Act
-		router.ServeHTTP(w, req)
+		w := request(router, second)
 
 		// Assert
-		assert.Equal(t, http.StatusForbidden, w.Code)
-		
-		var response Domain.ErrorResponse
-		err := json.Unmarshal(w.Body.Bytes(), &response)
-		assert.NoError(t, err)
-		assert.False(t, response.Success)
-		assert.Equal(t, "Access denied", response.Message)
-		assert.Equal(t, "Valid user role required", response.Error)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, 2, *reached)
 	})
-}
 
-// Test constructor
-func TestNewAuthMiddleware(t *testing.T) {
-	mockJWTService := new(MockJWTServiceForAuth)
-	authMiddleware := NewAuthMiddleware(mockJWTService)
+	t.Run("Error - blacklist lookup fails", func(t *testing.T) {
+		// Arrange
+		router, reached := setup(failingTokenBlacklist{})
+		token, _ := NewJWTService().GenerateToken(user)
 
-	assert.NotNil(t, authMiddleware)
-	assert.Equal(t, mockJWTService, authMiddleware.jwtService)
+		// Act
+		w := request(router, token)
+
+		// Assert
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+		assert.Equal(t, 0, *reached)
+	})
 }
 
 // Integration test with multiple middleware layers
@@ -503,7 +661,7 @@ func TestAuthMiddleware_Integration(t *testing.T) {
 	t.Run("Success - full authentication and authorization flow", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		// Create a valid admin token
@@ -520,14 +678,14 @@ func TestAuthMiddleware_Integration(t *testing.T) {
 		mockJWTService.On("ValidateToken", "admin.jwt.token").Return(token, nil)
 
 		// Setup route with both authentication and admin authorization
-		router.GET("/admin/users", 
+		router.GET("/admin/users",
 			authMiddleware.AuthenticateToken(),
-			authMiddleware.RequireAdmin(),
+			authMiddleware.RequirePermission(Domain.PermissionUsersManage),
 			func(c *gin.Context) {
 				userID, _ := c.Get("user_id")
 				username, _ := c.Get("username")
 				role, _ := c.Get("role")
-				
+
 				c.JSON(http.StatusOK, gin.H{
 					"message":  "admin endpoint accessed",
 					"user_id":  userID,
@@ -545,7 +703,7 @@ func TestAuthMiddleware_Integration(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusOK, w.Code)
-		
+
 		var response map[string]interface{}
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
@@ -553,14 +711,14 @@ func TestAuthMiddleware_Integration(t *testing.T) {
 		assert.Equal(t, "507f1f77bcf86cd799439011", response["user_id"])
 		assert.Equal(t, "admin", response["username"])
 		assert.Equal(t, Domain.RoleAdmin, response["role"])
-		
+
 		mockJWTService.AssertExpectations(t)
 	})
 
 	t.Run("Error - regular user trying to access admin endpoint", func(t *testing.T) {
 		// Arrange
 		mockJWTService := new(MockJWTServiceForAuth)
-		authMiddleware := NewAuthMiddleware(mockJWTService)
+		authMiddleware := NewAuthMiddleware(mockJWTService, NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
 		router := setupAuthTestRouter()
 
 		// Create a valid user token (not admin)
@@ -577,9 +735,9 @@ func TestAuthMiddleware_Integration(t *testing.T) {
 		mockJWTService.On("ValidateToken", "user.jwt.token").Return(token, nil)
 
 		// Setup route with both authentication and admin authorization
-		router.GET("/admin/users", 
+		router.GET("/admin/users",
 			authMiddleware.AuthenticateToken(),
-			authMiddleware.RequireAdmin(),
+			authMiddleware.RequirePermission(Domain.PermissionUsersManage),
 			func(c *gin.Context) {
 				c.JSON(http.StatusOK, gin.H{"message": "admin endpoint accessed"})
 			})
@@ -593,14 +751,14 @@ func TestAuthMiddleware_Integration(t *testing.T) {
 
 		// Assert
 		assert.Equal(t, http.StatusForbidden, w.Code)
-		
+
 		var response Domain.ErrorResponse
 		err := json.Unmarshal(w.Body.Bytes(), &response)
 		assert.NoError(t, err)
 		assert.False(t, response.Success)
 		assert.Equal(t, "Access denied", response.Message)
-		assert.Equal(t, "Admin privileges required", response.Error)
-		
+		assert.Equal(t, "Permission users:manage required", response.Error)
+
 		mockJWTService.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
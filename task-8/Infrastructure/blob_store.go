@@ -0,0 +1,124 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultAttachmentsDir is where files are kept when ATTACHMENTS_DIR is unset
+const DefaultAttachmentsDir = "attachments"
+
+// ErrBlobNotFound is returned by BlobStore.Get when nothing is stored under the key
+var ErrBlobNotFound = errors.New("blob not found")
+
+// BlobStore keeps the bytes of uploaded files by key. Keys are slash-separated relative paths
+// such as "<task id>/<file id>", so an object store such as S3 can implement it as well as a disk can.
+type BlobStore interface {
+	// Put stores everything read from r under key and returns the number of bytes stored
+	Put(ctx context.Context, key string, r io.Reader) (int64, error)
+	// Get opens the blob stored under key; the caller closes it
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key; deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// NewBlobStoreFromEnv returns a LocalBlobStore rooted at ATTACHMENTS_DIR, "attachments" by default
+func NewBlobStoreFromEnv() BlobStore {
+	dir := os.Getenv("ATTACHMENTS_DIR")
+	if dir == "" {
+		dir = DefaultAttachmentsDir
+	}
+	return NewLocalBlobStore(dir)
+}
+
+// LocalBlobStore implements BlobStore with one file per blob under a directory, which is created
+// with the first blob. Every instance that serves the same files must share the directory.
+type LocalBlobStore struct {
+	dir string
+}
+
+// NewLocalBlobStore creates a new instance of LocalBlobStore
+func NewLocalBlobStore(dir string) *LocalBlobStore {
+	return &LocalBlobStore{dir: dir}
+}
+
+// Put writes the blob to a temporary file and renames it into place once it is complete, so a
+// failed or cancelled upload never leaves a partial file under key
+func (bs *LocalBlobStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	target, err := bs.path(key)
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+		return 0, err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return 0, err
+	}
+	written, err := io.Copy(file, r)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = ctx.Err()
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), target)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return 0, err
+	}
+
+	return written, nil
+}
+
+// Get opens the file stored under key
+func (bs *LocalBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := bs.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(target)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Delete removes the file stored under key, and its directory once that is empty
+func (bs *LocalBlobStore) Delete(ctx context.Context, key string) error {
+	target, err := bs.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	// Fails harmlessly while other blobs share the directory
+	if dir := filepath.Dir(target); dir != filepath.Clean(bs.dir) {
+		os.Remove(dir)
+	}
+	return nil
+}
+
+// path maps a key to its file, refusing keys that would point outside the directory
+func (bs *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || key == ".." || strings.HasPrefix(key, "../") {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(bs.dir, filepath.FromSlash(key)), nil
+}
@@ -0,0 +1,84 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader returns some bytes and then an error, like a client that disconnects mid-upload
+type failingReader struct {
+	sent bool
+}
+
+func (r *failingReader) Read(p []byte) (int, error) {
+	if r.sent {
+		return 0, errors.New("connection reset")
+	}
+	r.sent = true
+	return copy(p, "partial"), nil
+}
+
+func TestLocalBlobStore(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - a stored blob can be read back and deleted", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		store := NewLocalBlobStore(dir)
+
+		// Act
+		size, err := store.Put(ctx, "task/file", strings.NewReader("hello"))
+		require.NoError(t, err)
+		reader, err := store.Get(ctx, "task/file")
+		require.NoError(t, err)
+		content, _ := io.ReadAll(reader)
+		reader.Close()
+		deleteErr := store.Delete(ctx, "task/file")
+		_, getErr := store.Get(ctx, "task/file")
+
+		// Assert
+		assert.Equal(t, int64(5), size)
+		assert.Equal(t, "hello", string(content))
+		assert.NoError(t, deleteErr)
+		assert.ErrorIs(t, getErr, ErrBlobNotFound)
+		_, statErr := os.Stat(filepath.Join(dir, "task"))
+		assert.True(t, os.IsNotExist(statErr), "the emptied directory is removed")
+	})
+
+	t.Run("Success - deleting a missing blob is not an error", func(t *testing.T) {
+		store := NewLocalBlobStore(t.TempDir())
+
+		assert.NoError(t, store.Delete(ctx, "task/missing"))
+	})
+
+	t.Run("Error - a failed upload leaves no file behind", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		store := NewLocalBlobStore(dir)
+
+		// Act
+		_, err := store.Put(ctx, "task/file", &failingReader{})
+
+		// Assert
+		assert.Error(t, err)
+		entries, _ := os.ReadDir(filepath.Join(dir, "task"))
+		assert.Empty(t, entries)
+	})
+
+	t.Run("Error - keys cannot leave the directory", func(t *testing.T) {
+		store := NewLocalBlobStore(t.TempDir())
+
+		for _, key := range []string{"", "../outside", "/etc/passwd", "task/../../outside", "task//file"} {
+			_, err := store.Put(ctx, key, strings.NewReader("x"))
+			assert.Error(t, err, key)
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package Infrastructure
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// DefaultMaxRequestBodyBytes is used when MAX_REQUEST_BODY_BYTES is unset or invalid
+const DefaultMaxRequestBodyBytes int64 = 1 << 20
+
+// BodyLimitMiddleware rejects request bodies larger than a limit with a 413
+type BodyLimitMiddleware struct {
+	maxBytes int64
+}
+
+// NewBodyLimitMiddleware creates a new instance of BodyLimitMiddleware.
+// MAX_REQUEST_BODY_BYTES takes a positive number of bytes.
+func NewBodyLimitMiddleware() *BodyLimitMiddleware {
+	maxBytes := DefaultMaxRequestBodyBytes
+	if value := os.Getenv("MAX_REQUEST_BODY_BYTES"); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			maxBytes = parsed
+		}
+	}
+
+	return &BodyLimitMiddleware{
+		maxBytes: maxBytes,
+	}
+}
+
+// Limit answers 413 straight away when the declared Content-Length is over the limit. Otherwise
+// the body is read through a limited reader: once a handler reads past the limit its reads fail,
+// whatever it writes in response is discarded and the client gets the 413 instead.
+// Routes whose full path is listed in exemptPaths, such as file uploads that apply their own
+// limit with LimitTo, are passed through untouched.
+func (bl *BodyLimitMiddleware) Limit(exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+	limit := bl.LimitTo(bl.maxBytes)
+
+	return func(c *gin.Context) {
+		if exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+		limit(c)
+	}
+}
+
+// LimitTo works like Limit with a limit of maxBytes instead of MAX_REQUEST_BODY_BYTES
+func (bl *BodyLimitMiddleware) LimitTo(maxBytes int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.ContentLength > maxBytes {
+			tooLarge(c, maxBytes)
+			c.Abort()
+			return
+		}
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+
+		body := &limitedBody{ReadCloser: c.Request.Body, remaining: maxBytes, limit: maxBytes}
+		c.Request.Body = body
+		writer := &bodyLimitWriter{ResponseWriter: c.Writer, body: body}
+		c.Writer = writer
+
+		c.Next()
+
+		c.Writer = writer.ResponseWriter
+		if body.exceeded && !c.Writer.Written() {
+			tooLarge(c, maxBytes)
+		}
+	}
+}
+
+// tooLarge writes the 413 response
+func tooLarge(c *gin.Context, maxBytes int64) {
+	respondError(c, http.StatusRequestEntityTooLarge, Domain.ErrorResponse{
+		Success:   false,
+		MessageID: Domain.MsgRequestBodyTooLarge,
+		Error:     fmt.Sprintf("The request body must not exceed %d bytes", maxBytes),
+	})
+}
+
+// limitedBody fails reads once more than limit bytes have been read, like http.MaxBytesReader,
+// and remembers that it did
+type limitedBody struct {
+	io.ReadCloser
+
+	remaining int64
+	limit     int64
+	exceeded  bool
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.exceeded {
+		return 0, &http.MaxBytesError{Limit: b.limit}
+	}
+	// Read one byte more than allowed so a body of exactly the limit is not mistaken for a longer one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	if int64(n) <= b.remaining {
+		b.remaining -= int64(n)
+		return n, err
+	}
+	b.exceeded = true
+	n = int(b.remaining)
+	b.remaining = 0
+	return n, &http.MaxBytesError{Limit: b.limit}
+}
+
+// bodyLimitWriter discards the handler's response once the body limit has been exceeded,
+// leaving the middleware to send the 413
+type bodyLimitWriter struct {
+	gin.ResponseWriter
+
+	body    *limitedBody
+	discard http.Header
+}
+
+func (w *bodyLimitWriter) Header() http.Header {
+	if w.body.exceeded {
+		if w.discard == nil {
+			w.discard = http.Header{}
+		}
+		return w.discard
+	}
+	return w.ResponseWriter.Header()
+}
+
+func (w *bodyLimitWriter) WriteHeader(code int) {
+	if !w.body.exceeded {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *bodyLimitWriter) WriteHeaderNow() {
+	if !w.body.exceeded {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *bodyLimitWriter) Write(data []byte) (int, error) {
+	if w.body.exceeded {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bodyLimitWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
@@ -0,0 +1,184 @@
+package Infrastructure
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func setupBodyLimitTestRouter(maxBytes int64, handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use((&BodyLimitMiddleware{maxBytes: maxBytes}).Limit())
+	router.POST("/tasks", handler)
+	return router
+}
+
+// bindingHandler answers like the controllers do: 400 when the body cannot be bound
+func bindingHandler(c *gin.Context) {
+	var body map[string]interface{}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, Domain.ErrorResponse{Success: false, Message: "Invalid request payload", Error: err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, body)
+}
+
+// unsizedBody hides the length of a body, as with chunked transfer encoding
+type unsizedBody struct {
+	io.Reader
+}
+
+func TestNewBodyLimitMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected int64
+	}{
+		{name: "Unset uses default", value: "", expected: DefaultMaxRequestBodyBytes},
+		{name: "Bytes", value: "2048", expected: 2048},
+		{name: "Invalid uses default", value: "1MB", expected: DefaultMaxRequestBodyBytes},
+		{name: "Non-positive uses default", value: "0", expected: DefaultMaxRequestBodyBytes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("MAX_REQUEST_BODY_BYTES", tt.value)
+
+			// Act
+			middleware := NewBodyLimitMiddleware()
+
+			// Assert
+			assert.Equal(t, tt.expected, middleware.maxBytes)
+		})
+	}
+}
+
+func TestBodyLimitMiddleware_Limit(t *testing.T) {
+	t.Run("Success - body within the limit", func(t *testing.T) {
+		// Arrange
+		router := setupBodyLimitTestRouter(64, bindingHandler)
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(`{"title": "Write tests"}`))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.JSONEq(t, `{"title": "Write tests"}`, w.Body.String())
+	})
+
+	t.Run("Success - body of exactly the limit", func(t *testing.T) {
+		// Arrange
+		body := `{"title": "` + strings.Repeat("a", 20) + `"}`
+		router := setupBodyLimitTestRouter(int64(len(body)), bindingHandler)
+		req := httptest.NewRequest(http.MethodPost, "/tasks", unsizedBody{strings.NewReader(body)})
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+	})
+
+	t.Run("Error - declared length over the limit", func(t *testing.T) {
+		// Arrange
+		called := false
+		router := setupBodyLimitTestRouter(16, func(c *gin.Context) {
+			called = true
+		})
+		req := httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(strings.Repeat("a", 17)))
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+		assert.False(t, called)
+
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.False(t, response.Success)
+		assert.Equal(t, "Request body too large", response.Message)
+		assert.Equal(t, "The request body must not exceed 16 bytes", response.Error)
+	})
+
+	t.Run("Error - body exceeds the limit mid-read", func(t *testing.T) {
+		// Arrange: no Content-Length, so the limit is only hit while the handler reads
+		router := setupBodyLimitTestRouter(32, bindingHandler)
+		body := `{"title": "` + strings.Repeat("a", 100) + `"}`
+		req := httptest.NewRequest(http.MethodPost, "/tasks", unsizedBody{strings.NewReader(body)})
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert: the handler's 400 is replaced by the 413
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "Request body too large", response.Message)
+		assert.Equal(t, "The request body must not exceed 32 bytes", response.Error)
+	})
+
+	t.Run("Error - reads fail once the limit is passed", func(t *testing.T) {
+		// Arrange
+		var readErr error
+		router := setupBodyLimitTestRouter(8, func(c *gin.Context) {
+			_, readErr = io.ReadAll(c.Request.Body)
+		})
+		req := httptest.NewRequest(http.MethodPost, "/tasks", unsizedBody{strings.NewReader(strings.Repeat("a", 9))})
+		req.ContentLength = -1
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		var maxBytesErr *http.MaxBytesError
+		assert.ErrorAs(t, readErr, &maxBytesErr)
+		assert.Equal(t, int64(8), maxBytesErr.Limit)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	})
+}
+
+func TestBodyLimitMiddleware_LimitTo(t *testing.T) {
+	t.Run("Success - exempt routes get their own limit", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		middleware := &BodyLimitMiddleware{maxBytes: 8}
+		router := gin.New()
+		router.Use(middleware.Limit("/tasks/:id/attachments"))
+		router.POST("/tasks/:id/attachments", middleware.LimitTo(32), bindingHandler)
+		router.POST("/tasks", bindingHandler)
+		body := `{"title": "attachment"}`
+
+		// Act
+		uploadRecorder := httptest.NewRecorder()
+		router.ServeHTTP(uploadRecorder, httptest.NewRequest(http.MethodPost, "/tasks/1/attachments", strings.NewReader(body)))
+		otherRecorder := httptest.NewRecorder()
+		router.ServeHTTP(otherRecorder, httptest.NewRequest(http.MethodPost, "/tasks", strings.NewReader(body)))
+		tooLargeRecorder := httptest.NewRecorder()
+		router.ServeHTTP(tooLargeRecorder, httptest.NewRequest(http.MethodPost, "/tasks/1/attachments", strings.NewReader(strings.Repeat("a", 33))))
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, uploadRecorder.Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, otherRecorder.Code)
+		assert.Equal(t, http.StatusRequestEntityTooLarge, tooLargeRecorder.Code)
+		assert.Contains(t, tooLargeRecorder.Body.String(), "The request body must not exceed 32 bytes")
+	})
+}
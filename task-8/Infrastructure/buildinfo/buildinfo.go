@@ -0,0 +1,39 @@
+// Package buildinfo reports which build of the server is running. The values are set at build time:
+//
+//	go build -ldflags "-X task_manager/Infrastructure/buildinfo.Version=1.2.3 \
+//	  -X task_manager/Infrastructure/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	  -X task_manager/Infrastructure/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+package buildinfo
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"task_manager/Domain"
+)
+
+// Build values; each is "dev" unless set with -ldflags
+var (
+	Version   = "dev"
+	Commit    = "dev"
+	BuildTime = "dev"
+)
+
+// VersionHeader is the response header that carries the version
+const VersionHeader = "X-App-Version"
+
+// Get returns the build values
+func Get() Domain.VersionInfo {
+	return Domain.VersionInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+	}
+}
+
+// SetVersionHeader sets the X-App-Version header on every response, so a client can tell which build answered
+func SetVersionHeader() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Header(VersionHeader, Version)
+		c.Next()
+	}
+}
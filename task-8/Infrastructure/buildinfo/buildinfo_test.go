@@ -0,0 +1,53 @@
+package buildinfo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func TestGet(t *testing.T) {
+	t.Run("Success - dev values without ldflags", func(t *testing.T) {
+		// Act
+		info := Get()
+
+		// Assert
+		assert.Equal(t, Domain.VersionInfo{Version: "dev", Commit: "dev", BuildTime: "dev"}, info)
+	})
+
+	t.Run("Success - values set at build time", func(t *testing.T) {
+		// Arrange
+		defer func(version, commit, buildTime string) {
+			Version, Commit, BuildTime = version, commit, buildTime
+		}(Version, Commit, BuildTime)
+		Version, Commit, BuildTime = "1.2.3", "abc1234", "2024-05-01T10:00:00Z"
+
+		// Act
+		info := Get()
+
+		// Assert
+		assert.Equal(t, Domain.VersionInfo{Version: "1.2.3", Commit: "abc1234", BuildTime: "2024-05-01T10:00:00Z"}, info)
+	})
+}
+
+func TestSetVersionHeader(t *testing.T) {
+	// Arrange
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(SetVersionHeader())
+	router.GET("/tasks", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	w := httptest.NewRecorder()
+
+	// Act
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/tasks", nil))
+
+	// Assert
+	assert.Equal(t, "dev", w.Header().Get(VersionHeader))
+}
@@ -0,0 +1,185 @@
+package Infrastructure
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Business metric names. Dashboards query these, so they must not change when the code around them moves.
+const (
+	// MetricTasksCreated counts created tasks, labelled by MetricLabelRole of the creator
+	MetricTasksCreated = "tasks_created_total"
+	// MetricTaskStatusTransitions counts status changes of tasks, labelled by MetricLabelFrom and MetricLabelTo
+	MetricTaskStatusTransitions = "task_status_transitions_total"
+	// MetricTasksCompleted counts tasks moved to completed, labelled by MetricLabelUserID of whoever completed them
+	MetricTasksCompleted = "tasks_completed_total"
+	// MetricTaskCompletionSeconds observes how long completed tasks took from creation to completion
+	MetricTaskCompletionSeconds = "task_completion_seconds"
+	// MetricTasksDeleted counts tasks moved to the trash, labelled by MetricLabelRole of the caller
+	MetricTasksDeleted = "tasks_deleted_total"
+	// MetricUsersRegistered counts registered users, labelled by MetricLabelRole they were given
+	MetricUsersRegistered = "users_registered_total"
+	// MetricLoginFailures counts refused logins, labelled by MetricLabelReason
+	MetricLoginFailures = "login_failures_total"
+)
+
+// Business metric label names
+const (
+	MetricLabelRole   = "role"
+	MetricLabelFrom   = "from"
+	MetricLabelTo     = "to"
+	MetricLabelUserID = "user_id"
+	MetricLabelReason = "reason"
+)
+
+// Values of MetricLabelReason
+const (
+	LoginFailureInvalidCredentials = "invalid_credentials"
+	LoginFailureDeactivated        = "deactivated"
+)
+
+// businessMetricHelp describes every business metric; only metrics listed here are exported
+var businessMetricHelp = map[string]string{
+	MetricTasksCreated:          "Total number of tasks created.",
+	MetricTaskStatusTransitions: "Total number of task status changes.",
+	MetricTasksCompleted:        "Total number of tasks completed.",
+	MetricTaskCompletionSeconds: "Time from the creation of a task to its completion.",
+	MetricTasksDeleted:          "Total number of tasks moved to the trash.",
+	MetricUsersRegistered:       "Total number of users registered.",
+	MetricLoginFailures:         "Total number of refused logins.",
+}
+
+// businessSummaries are the business metrics recorded with Observe; the others are counters
+var businessSummaries = map[string]bool{
+	MetricTaskCompletionSeconds: true,
+}
+
+// BusinessMetrics counts what happens in the usecases, such as created tasks and failed logins,
+// for dashboards next to the request metrics
+type BusinessMetrics interface {
+	// Increment adds one to a counter
+	Increment(name string, labels map[string]string)
+	// Observe records one value of a summary
+	Observe(name string, value float64, labels map[string]string)
+}
+
+// NopBusinessMetrics discards every metric
+type NopBusinessMetrics struct{}
+
+// NewNopBusinessMetrics returns BusinessMetrics that discard everything, for tests and tools
+func NewNopBusinessMetrics() BusinessMetrics {
+	return NopBusinessMetrics{}
+}
+
+// Increment implements BusinessMetrics
+func (NopBusinessMetrics) Increment(name string, labels map[string]string) {}
+
+// Observe implements BusinessMetrics
+func (NopBusinessMetrics) Observe(name string, value float64, labels map[string]string) {}
+
+// businessSeries identifies one business metric series
+type businessSeries struct {
+	name   string
+	labels string
+}
+
+// summaryStats holds the sum and count of one summary series
+type summaryStats struct {
+	count uint64
+	sum   float64
+}
+
+// Increment implements BusinessMetrics
+func (m *Metrics) Increment(name string, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counters[businessSeries{name: name, labels: formatLabels(labels)}]++
+}
+
+// Observe implements BusinessMetrics
+func (m *Metrics) Observe(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series := businessSeries{name: name, labels: formatLabels(labels)}
+	stats, ok := m.summaries[series]
+	if !ok {
+		stats = &summaryStats{}
+		m.summaries[series] = stats
+	}
+	stats.count++
+	stats.sum += value
+}
+
+// renderBusiness writes the business counters and summaries. The caller holds m.mu.
+func (m *Metrics) renderBusiness(b *strings.Builder) {
+	names := make([]string, 0, len(businessMetricHelp))
+	for name := range businessMetricHelp {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	counters := sortedBusinessSeries(m.counters)
+	summaries := sortedBusinessSeries(m.summaries)
+
+	for _, name := range names {
+		fmt.Fprintf(b, "# HELP %s %s\n", name, businessMetricHelp[name])
+		if businessSummaries[name] {
+			fmt.Fprintf(b, "# TYPE %s summary\n", name)
+			for _, series := range summaries {
+				if series.name == name {
+					stats := m.summaries[series]
+					fmt.Fprintf(b, "%s_sum%s %s\n", name, series.labelSet(), formatFloat(stats.sum))
+					fmt.Fprintf(b, "%s_count%s %d\n", name, series.labelSet(), stats.count)
+				}
+			}
+			continue
+		}
+
+		fmt.Fprintf(b, "# TYPE %s counter\n", name)
+		for _, series := range counters {
+			if series.name == name {
+				fmt.Fprintf(b, "%s%s %d\n", name, series.labelSet(), m.counters[series])
+			}
+		}
+	}
+}
+
+// sortedBusinessSeries returns the series of a metric map ordered by name and labels
+func sortedBusinessSeries[V any](values map[businessSeries]V) []businessSeries {
+	series := make([]businessSeries, 0, len(values))
+	for s := range values {
+		series = append(series, s)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].name != series[j].name {
+			return series[i].name < series[j].name
+		}
+		return series[i].labels < series[j].labels
+	})
+	return series
+}
+
+// labelSet returns the labels of a series in braces, or nothing when it has none
+func (s businessSeries) labelSet() string {
+	if s.labels == "" {
+		return ""
+	}
+	return "{" + s.labels + "}"
+}
+
+// formatLabels writes labels in the Prometheus text format, ordered by name so equal label sets match
+func formatLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, len(keys))
+	for i, key := range keys {
+		pairs[i] = fmt.Sprintf("%s=\"%s\"", key, escapeLabelValue(labels[key]))
+	}
+	return strings.Join(pairs, ",")
+}
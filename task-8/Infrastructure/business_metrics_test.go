@@ -0,0 +1,57 @@
+package Infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics_BusinessMetrics(t *testing.T) {
+	t.Run("Success - counters are rendered by name and labels", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+
+		// Act
+		metrics.Increment(MetricTasksCreated, map[string]string{MetricLabelRole: "user"})
+		metrics.Increment(MetricTasksCreated, map[string]string{MetricLabelRole: "user"})
+		metrics.Increment(MetricTasksCreated, map[string]string{MetricLabelRole: "admin"})
+		metrics.Increment(MetricTaskStatusTransitions, map[string]string{MetricLabelTo: "completed", MetricLabelFrom: "in_progress"})
+		metrics.Increment(MetricLoginFailures, map[string]string{MetricLabelReason: `bad "quote"`})
+		body := metrics.render()
+
+		// Assert
+		assert.Contains(t, body, "# TYPE tasks_created_total counter\n")
+		assert.Contains(t, body, "tasks_created_total{role=\"admin\"} 1\ntasks_created_total{role=\"user\"} 2\n")
+		assert.Contains(t, body, `task_status_transitions_total{from="in_progress",to="completed"} 1`)
+		assert.Contains(t, body, `login_failures_total{reason="bad \"quote\""} 1`)
+	})
+
+	t.Run("Success - observations are rendered as a summary", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+
+		// Act
+		metrics.Observe(MetricTaskCompletionSeconds, 1.5, nil)
+		metrics.Observe(MetricTaskCompletionSeconds, 2, nil)
+		body := metrics.render()
+
+		// Assert
+		assert.Contains(t, body, "# TYPE task_completion_seconds summary\n")
+		assert.Contains(t, body, "task_completion_seconds_sum 3.5\n")
+		assert.Contains(t, body, "task_completion_seconds_count 2\n")
+	})
+
+	t.Run("Success - every metric is described before anything is counted", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+
+		// Act
+		body := metrics.render()
+
+		// Assert
+		for name := range businessMetricHelp {
+			assert.Contains(t, body, "# HELP "+name+" ")
+		}
+		assert.Contains(t, body, "# TYPE task_completion_seconds summary\n")
+	})
+}
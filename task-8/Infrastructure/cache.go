@@ -0,0 +1,159 @@
+package Infrastructure
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCacheSize is how many entries a cache holds when its _SIZE variable is unset
+const DefaultCacheSize = 1000
+
+// Cache stores encoded values by key for a limited time. Values are bytes so that an implementation
+// shared between instances, such as Redis, can hold them as well as process memory can.
+// A cache is an optimization: a failing implementation reports a miss rather than an error.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+	Set(ctx context.Context, key string, value []byte)
+	Clear(ctx context.Context)
+	Stats() CacheStats
+}
+
+// CacheStats counts the lookups a cache has answered since it was created
+type CacheStats struct {
+	Hits   uint64
+	Misses uint64
+}
+
+// NewCacheFromEnv returns a MemoryCache configured by <prefix>_TTL, a duration such as "30s", and
+// <prefix>_SIZE, the most entries kept (default 1000). Caching is off unless the TTL is set to a
+// positive duration, in which case it returns nil.
+func NewCacheFromEnv(prefix string) (Cache, error) {
+	ttlName, sizeName := prefix+"_TTL", prefix+"_SIZE"
+
+	ttl := time.Duration(0)
+	if value := os.Getenv(ttlName); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < 0 {
+			return nil, fmt.Errorf("invalid %s %q: must be a non-negative duration such as 30s", ttlName, value)
+		}
+		ttl = parsed
+	}
+
+	size := DefaultCacheSize
+	if value := os.Getenv(sizeName); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return nil, fmt.Errorf("invalid %s %q: must be a positive integer", sizeName, value)
+		}
+		size = parsed
+	}
+
+	if ttl == 0 {
+		return nil, nil
+	}
+	return NewMemoryCache(size, ttl), nil
+}
+
+// MemoryCache implements Cache in process memory. Entries expire after the TTL and, once the
+// cache is full, the least recently used entry makes room for a new one.
+type MemoryCache struct {
+	mu       sync.Mutex
+	capacity int
+	ttl      time.Duration
+	entries  map[string]*list.Element
+	order    *list.List // Most recently used at the front
+	now      func() time.Time
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+}
+
+// memoryCacheEntry is one value in a MemoryCache
+type memoryCacheEntry struct {
+	key       string
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCache creates a new instance of MemoryCache holding at most capacity entries for ttl each
+func NewMemoryCache(capacity int, ttl time.Duration) *MemoryCache {
+	return &MemoryCache{
+		capacity: capacity,
+		ttl:      ttl,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		now:      time.Now,
+	}
+}
+
+// Get implements Cache
+func (c *MemoryCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if ok && !element.Value.(*memoryCacheEntry).expiresAt.After(c.now()) {
+		c.remove(element)
+		ok = false
+	}
+	if !ok {
+		c.misses.Add(1)
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+	c.hits.Add(1)
+	return element.Value.(*memoryCacheEntry).value, true
+}
+
+// Set implements Cache
+func (c *MemoryCache) Set(ctx context.Context, key string, value []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryCacheEntry{key: key, value: value, expiresAt: c.now().Add(c.ttl)}
+	if element, ok := c.entries[key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(entry)
+	for c.order.Len() > c.capacity {
+		c.remove(c.order.Back())
+	}
+}
+
+// Clear implements Cache
+func (c *MemoryCache) Clear(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Stats implements Cache
+func (c *MemoryCache) Stats() CacheStats {
+	return CacheStats{Hits: c.hits.Load(), Misses: c.misses.Load()}
+}
+
+// Len returns the number of entries held, expired ones included until they are looked up or evicted
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.order.Len()
+}
+
+// remove drops an entry. The caller must hold the lock.
+func (c *MemoryCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(*memoryCacheEntry).key)
+}
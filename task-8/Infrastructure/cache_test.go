@@ -0,0 +1,124 @@
+package Infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - entries are found until they expire", func(t *testing.T) {
+		// Arrange
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		cache := NewMemoryCache(10, time.Minute)
+		cache.now = clock.Now
+		cache.Set(ctx, "key", []byte("value"))
+
+		// Act
+		value, found := cache.Get(ctx, "key")
+		_, other := cache.Get(ctx, "other")
+		clock.now = clock.now.Add(2 * time.Minute)
+		_, expired := cache.Get(ctx, "key")
+
+		// Assert
+		assert.True(t, found)
+		assert.Equal(t, []byte("value"), value)
+		assert.False(t, other)
+		assert.False(t, expired)
+		assert.Zero(t, cache.Len())
+		assert.Equal(t, CacheStats{Hits: 1, Misses: 2}, cache.Stats())
+	})
+
+	t.Run("Success - the least recently used entry is evicted when full", func(t *testing.T) {
+		// Arrange
+		cache := NewMemoryCache(2, time.Minute)
+		cache.Set(ctx, "a", []byte("1"))
+		cache.Set(ctx, "b", []byte("2"))
+		cache.Get(ctx, "a")
+
+		// Act
+		cache.Set(ctx, "c", []byte("3"))
+
+		// Assert
+		_, a := cache.Get(ctx, "a")
+		_, b := cache.Get(ctx, "b")
+		_, c := cache.Get(ctx, "c")
+		assert.True(t, a)
+		assert.False(t, b)
+		assert.True(t, c)
+		assert.Equal(t, 2, cache.Len())
+	})
+
+	t.Run("Success - setting a key again replaces its value", func(t *testing.T) {
+		cache := NewMemoryCache(2, time.Minute)
+		cache.Set(ctx, "a", []byte("1"))
+		cache.Set(ctx, "a", []byte("2"))
+
+		value, _ := cache.Get(ctx, "a")
+		assert.Equal(t, []byte("2"), value)
+		assert.Equal(t, 1, cache.Len())
+	})
+
+	t.Run("Success - clear drops every entry", func(t *testing.T) {
+		cache := NewMemoryCache(10, time.Minute)
+		cache.Set(ctx, "a", []byte("1"))
+		cache.Set(ctx, "b", []byte("2"))
+
+		cache.Clear(ctx)
+
+		_, found := cache.Get(ctx, "a")
+		assert.False(t, found)
+		assert.Zero(t, cache.Len())
+	})
+}
+
+func TestNewCacheFromEnv(t *testing.T) {
+	t.Run("Off by default", func(t *testing.T) {
+		cache, err := NewCacheFromEnv("TEST_CACHE")
+
+		assert.NoError(t, err)
+		assert.Nil(t, cache)
+	})
+
+	t.Run("Memory cache when a TTL is set", func(t *testing.T) {
+		t.Setenv("TEST_CACHE_TTL", "30s")
+		t.Setenv("TEST_CACHE_SIZE", "5")
+
+		cache, err := NewCacheFromEnv("TEST_CACHE")
+
+		require.NoError(t, err)
+		require.IsType(t, &MemoryCache{}, cache)
+		assert.Equal(t, 30*time.Second, cache.(*MemoryCache).ttl)
+		assert.Equal(t, 5, cache.(*MemoryCache).capacity)
+	})
+
+	testCases := []struct {
+		name     string
+		variable string
+		value    string
+		err      string
+	}{
+		{"unparsable TTL", "TEST_CACHE_TTL", "30", `invalid TEST_CACHE_TTL "30": must be a non-negative duration such as 30s`},
+		{"negative TTL", "TEST_CACHE_TTL", "-1s", `invalid TEST_CACHE_TTL "-1s": must be a non-negative duration such as 30s`},
+		{"zero size", "TEST_CACHE_SIZE", "0", `invalid TEST_CACHE_SIZE "0": must be a positive integer`},
+	}
+	for _, tc := range testCases {
+		t.Run("Error - "+tc.name, func(t *testing.T) {
+			t.Setenv(tc.variable, tc.value)
+
+			cache, err := NewCacheFromEnv("TEST_CACHE")
+
+			assert.EqualError(t, err, tc.err)
+			assert.Nil(t, cache)
+		})
+	}
+}
+
+func TestCacheImplementations(t *testing.T) {
+	var _ Cache = NewMemoryCache(1, time.Minute)
+}
@@ -0,0 +1,121 @@
+package Infrastructure
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientIPHeaders are the headers a trusted proxy names the client in, in the order they are consulted
+var clientIPHeaders = []string{"X-Forwarded-For", "X-Real-IP"}
+
+// ClientIPResolver works out the IP address of the client behind a request. Headers naming the client
+// are only believed when the connection comes from a trusted proxy, so anyone else gets their own
+// address however they fill those headers in.
+type ClientIPResolver struct {
+	trustedProxies []*net.IPNet
+}
+
+// NewClientIPResolver creates a ClientIPResolver trusting TRUSTED_PROXIES, a comma-separated list of
+// IPs or CIDRs. RATE_LIMIT_TRUSTED_PROXIES, which configured the rate limiter alone, is used when it is unset.
+func NewClientIPResolver() *ClientIPResolver {
+	value := os.Getenv("TRUSTED_PROXIES")
+	if value == "" {
+		value = os.Getenv("RATE_LIMIT_TRUSTED_PROXIES")
+	}
+	return &ClientIPResolver{trustedProxies: parseTrustedProxies(value)}
+}
+
+// ClientIP returns the address of the connection or, when that is a trusted proxy, the client it
+// forwards for. X-Forwarded-For is read from the right, skipping trusted proxies, so entries the client
+// added on the left are never used; X-Real-IP is consulted when there is no X-Forwarded-For.
+func (cr *ClientIPResolver) ClientIP(r *http.Request) string {
+	remoteIP, _, err := net.SplitHostPort(strings.TrimSpace(r.RemoteAddr))
+	if err != nil {
+		remoteIP = strings.TrimSpace(r.RemoteAddr)
+	}
+	if !cr.isTrustedProxy(remoteIP) {
+		return remoteIP
+	}
+
+	for _, header := range clientIPHeaders {
+		if ip, ok := cr.forwardedIP(r.Header.Get(header)); ok {
+			return ip
+		}
+	}
+	return remoteIP
+}
+
+// Configure makes gin's own Context.ClientIP trust the same proxies and headers as ClientIP.
+// Out of the box gin trusts every peer, which would let any client choose its address.
+func (cr *ClientIPResolver) Configure(engine *gin.Engine) error {
+	engine.ForwardedByClientIP = true
+	engine.RemoteIPHeaders = clientIPHeaders
+	return engine.SetTrustedProxies(cr.TrustedProxies())
+}
+
+// TrustedProxies returns the trusted proxy networks in CIDR notation
+func (cr *ClientIPResolver) TrustedProxies() []string {
+	proxies := make([]string, len(cr.trustedProxies))
+	for i, network := range cr.trustedProxies {
+		proxies[i] = network.String()
+	}
+	return proxies
+}
+
+// forwardedIP returns the right-most entry of a forwarding header that is not a trusted proxy, or the
+// left-most when they all are. A malformed entry ends the search, as nothing left of it can be trusted.
+func (cr *ClientIPResolver) forwardedIP(header string) (string, bool) {
+	if header == "" {
+		return "", false
+	}
+	entries := strings.Split(header, ",")
+	for i := len(entries) - 1; i >= 0; i-- {
+		ip := strings.TrimSpace(entries[i])
+		if net.ParseIP(ip) == nil {
+			return "", false
+		}
+		if i == 0 || !cr.isTrustedProxy(ip) {
+			return ip, true
+		}
+	}
+	return "", false
+}
+
+func (cr *ClientIPResolver) isTrustedProxy(ip string) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range cr.trustedProxies {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTrustedProxies parses a comma-separated list of IPs and CIDRs, skipping invalid entries
+func parseTrustedProxies(value string) []*net.IPNet {
+	var networks []*net.IPNet
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			if ip := net.ParseIP(entry); ip != nil && ip.To4() != nil {
+				entry += "/32"
+			} else {
+				entry += "/128"
+			}
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
@@ -0,0 +1,117 @@
+package Infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClientIPResolver_ClientIP(t *testing.T) {
+	tests := []struct {
+		name         string
+		trusted      string
+		remoteAddr   string
+		forwardedFor string
+		realIP       string
+		expected     string
+	}{
+		{name: "untrusted peer without headers", remoteAddr: "192.0.2.1:1234", expected: "192.0.2.1"},
+		{name: "untrusted peer cannot spoof X-Forwarded-For", remoteAddr: "192.0.2.1:1234", forwardedFor: "198.51.100.1", expected: "192.0.2.1"},
+		{name: "untrusted peer cannot spoof X-Real-IP", remoteAddr: "192.0.2.1:1234", realIP: "198.51.100.1", expected: "192.0.2.1"},
+		{name: "peer outside the trusted network cannot spoof either", trusted: "10.0.0.0/8", remoteAddr: "192.0.2.1:1234", forwardedFor: "198.51.100.1", realIP: "198.51.100.2", expected: "192.0.2.1"},
+		{name: "trusted proxy names the client in X-Forwarded-For", trusted: "10.0.0.0/8", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.1", expected: "198.51.100.1"},
+		{name: "trusted proxy chain is skipped from the right", trusted: "10.0.0.0/8", remoteAddr: "10.0.0.1:1234", forwardedFor: "203.0.113.9, 198.51.100.1, 10.0.0.2", expected: "198.51.100.1"},
+		{name: "malformed entry falls back to the peer", trusted: "10.0.0.0/8", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.1, not-an-ip", expected: "10.0.0.1"},
+		{name: "trusted proxy names the client in X-Real-IP", trusted: "10.0.0.1", remoteAddr: "10.0.0.1:1234", realIP: "198.51.100.1", expected: "198.51.100.1"},
+		{name: "X-Forwarded-For wins over X-Real-IP", trusted: "10.0.0.1", remoteAddr: "10.0.0.1:1234", forwardedFor: "198.51.100.1", realIP: "198.51.100.2", expected: "198.51.100.1"},
+		{name: "trusted proxy without headers is the client", trusted: "10.0.0.1", remoteAddr: "10.0.0.1:1234", expected: "10.0.0.1"},
+		{name: "IPv6 proxy", trusted: "2001:db8::/32", remoteAddr: "[2001:db8::1]:1234", forwardedFor: "198.51.100.1", expected: "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("TRUSTED_PROXIES", tt.trusted)
+			t.Setenv("RATE_LIMIT_TRUSTED_PROXIES", "")
+			resolver := NewClientIPResolver()
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwardedFor != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwardedFor)
+			}
+			if tt.realIP != "" {
+				req.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			// Act
+			ip := resolver.ClientIP(req)
+
+			// Assert
+			assert.Equal(t, tt.expected, ip)
+		})
+	}
+}
+
+func TestNewClientIPResolver(t *testing.T) {
+	t.Run("Success - falls back to the rate limiter's setting", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TRUSTED_PROXIES", "")
+		t.Setenv("RATE_LIMIT_TRUSTED_PROXIES", "10.0.0.1, invalid")
+
+		// Act
+		resolver := NewClientIPResolver()
+
+		// Assert
+		assert.Equal(t, []string{"10.0.0.1/32"}, resolver.TrustedProxies())
+	})
+
+	t.Run("Success - TRUSTED_PROXIES takes precedence", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8,2001:db8::1")
+		t.Setenv("RATE_LIMIT_TRUSTED_PROXIES", "192.0.2.1")
+
+		// Act
+		resolver := NewClientIPResolver()
+
+		// Assert
+		assert.Equal(t, []string{"10.0.0.0/8", "2001:db8::1/128"}, resolver.TrustedProxies())
+	})
+}
+
+func TestClientIPResolver_Configure(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    string
+		remoteAddr string
+		expected   string
+	}{
+		{name: "gin ignores headers from an untrusted peer", remoteAddr: "192.0.2.1:1234", expected: "192.0.2.1"},
+		{name: "gin reads headers from a trusted proxy", trusted: "10.0.0.0/8", remoteAddr: "10.0.0.1:1234", expected: "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			gin.SetMode(gin.TestMode)
+			resolver := &ClientIPResolver{trustedProxies: parseTrustedProxies(tt.trusted)}
+			router := gin.New()
+			assert.NoError(t, resolver.Configure(router))
+			var ginIP, resolvedIP string
+			router.GET("/", func(c *gin.Context) {
+				ginIP = c.ClientIP()
+				resolvedIP = resolver.ClientIP(c.Request)
+			})
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+
+			// Act
+			router.ServeHTTP(httptest.NewRecorder(), req)
+
+			// Assert
+			assert.Equal(t, tt.expected, ginIP)
+			assert.Equal(t, tt.expected, resolvedIP)
+		})
+	}
+}
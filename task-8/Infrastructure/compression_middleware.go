@@ -0,0 +1,192 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultCompressionMinSize is the smallest response body worth compressing
+const DefaultCompressionMinSize = 1024
+
+// incompressibleContentTypes are already compressed, so gzip would only cost CPU
+var incompressibleContentTypes = []string{
+	"image/",
+	"video/",
+	"audio/",
+	"font/woff",
+	"application/gzip",
+	"application/x-gzip",
+	"application/zip",
+	"application/zstd",
+	"application/x-7z-compressed",
+	"application/x-rar-compressed",
+	"application/pdf",
+}
+
+// CompressionMiddleware gzips responses for clients that accept it
+type CompressionMiddleware struct {
+	minSize int
+}
+
+// NewCompressionMiddleware creates a new instance of CompressionMiddleware
+func NewCompressionMiddleware() *CompressionMiddleware {
+	return &CompressionMiddleware{
+		minSize: DefaultCompressionMinSize,
+	}
+}
+
+// Compress gzips the response when the request's Accept-Encoding allows it. Bodies smaller than
+// the minimum size, already-compressed content types and responses that set their own
+// Content-Encoding are sent as they are. Streamed responses are compressed from the first flush.
+func (cm *CompressionMiddleware) Compress() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) {
+			c.Next()
+			return
+		}
+
+		writer := &gzipWriter{ResponseWriter: c.Writer, minSize: cm.minSize}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+		}()
+
+		c.Next()
+
+		writer.finish()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip, honouring q=0
+func acceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// gzipWriter buffers the start of a response until it knows whether the body is worth compressing
+type gzipWriter struct {
+	gin.ResponseWriter
+
+	minSize int
+	buffer  bytes.Buffer
+	decided bool
+	gz      *gzip.Writer
+}
+
+// WriteHeaderNow is held back until the decision, since compressing changes the headers
+func (w *gzipWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *gzipWriter) Write(data []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(data)
+		}
+		return w.ResponseWriter.Write(data)
+	}
+
+	w.buffer.Write(data)
+	if w.buffer.Len() >= w.minSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(data), nil
+}
+
+func (w *gzipWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *gzipWriter) Written() bool {
+	return w.buffer.Len() > 0 || w.ResponseWriter.Written()
+}
+
+// Flush starts a streamed response, compressed whatever its size so far
+func (w *gzipWriter) Flush() {
+	if !w.decided {
+		w.decide(true)
+	}
+	if w.gz != nil {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer, to lift the write deadline of a stream
+func (w *gzipWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide picks between compressing and sending as is, then sends what is buffered
+func (w *gzipWriter) decide(largeEnough bool) error {
+	w.decided = true
+
+	header := w.ResponseWriter.Header()
+	if largeEnough && w.compressible(header) {
+		header.Set("Content-Encoding", "gzip")
+		header.Add("Vary", "Accept-Encoding")
+		header.Del("Content-Length")
+		w.gz = gzip.NewWriter(w.ResponseWriter)
+	}
+
+	if w.buffer.Len() == 0 {
+		return nil
+	}
+	data := w.buffer.Bytes()
+	w.buffer.Reset()
+	if w.gz != nil {
+		_, err := w.gz.Write(data)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(data)
+	return err
+}
+
+// compressible reports whether the response may be compressed
+func (w *gzipWriter) compressible(header http.Header) bool {
+	switch w.ResponseWriter.Status() {
+	case http.StatusNoContent, http.StatusNotModified:
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	for _, incompressible := range incompressibleContentTypes {
+		if strings.HasPrefix(contentType, incompressible) {
+			return false
+		}
+	}
+	return true
+}
+
+// finish sends a response that stayed under the minimum size and ends the gzip stream
+func (w *gzipWriter) finish() {
+	if !w.decided {
+		w.decide(w.buffer.Len() >= w.minSize)
+	}
+	if w.gz != nil {
+		w.gz.Close()
+	}
+}
@@ -0,0 +1,187 @@
+package Infrastructure
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func setupCompressionTestRouter(handler gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewCompressionMiddleware().Compress())
+	router.GET("/tasks", handler)
+	return router
+}
+
+// gunzip decompresses a response body
+func gunzip(t *testing.T, body io.Reader) string {
+	t.Helper()
+	reader, err := gzip.NewReader(body)
+	assert.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	return string(data)
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{header: "", expected: false},
+		{header: "gzip", expected: true},
+		{header: "deflate, gzip;q=0.8", expected: true},
+		{header: "GZIP", expected: true},
+		{header: "*", expected: true},
+		{header: "br", expected: false},
+		{header: "gzip;q=0", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptsGzip(tt.header))
+		})
+	}
+}
+
+func TestCompressionMiddleware_Compress(t *testing.T) {
+	largeBody := strings.Repeat(`{"title": "Write tests"},`, 100)
+
+	t.Run("Success - large response is compressed", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(largeBody))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+		assert.Less(t, w.Body.Len(), len(largeBody))
+		assert.Equal(t, largeBody, gunzip(t, w.Body))
+	})
+
+	t.Run("Success - response built from small writes is compressed", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.Header("Content-Type", "text/csv")
+			c.Status(http.StatusOK)
+			for i := 0; i < 200; i++ {
+				c.Writer.WriteString("row,of,values\n")
+			}
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, strings.Repeat("row,of,values\n", 200), gunzip(t, w.Body))
+	})
+
+	t.Run("Success - streamed response is compressed from the first flush", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.Header("Content-Type", "text/csv")
+			c.Status(http.StatusOK)
+			c.Writer.WriteString("id,title\n")
+			c.Writer.Flush()
+			c.Writer.WriteString("1,Write tests\n")
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, "id,title\n1,Write tests\n", gunzip(t, w.Body))
+	})
+
+	t.Run("Skip - client does not accept gzip", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json", []byte(largeBody))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("Skip - small response", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"success": true})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.JSONEq(t, `{"success": true}`, w.Body.String())
+	})
+
+	t.Run("Skip - already compressed content type", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.Data(http.StatusOK, "image/png", []byte(largeBody))
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Equal(t, largeBody, w.Body.String())
+	})
+
+	t.Run("Skip - empty response keeps its status", func(t *testing.T) {
+		// Arrange
+		router := setupCompressionTestRouter(func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusNoContent, w.Code)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+		assert.Empty(t, w.Body.String())
+	})
+}
@@ -0,0 +1,100 @@
+package Infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"task_manager/Domain"
+)
+
+// eventSubscriberBuffer is how many events may wait for a slow subscriber before new ones are dropped
+const eventSubscriberBuffer = 64
+
+// EventBuses publishes every event to each bus in turn
+type EventBuses []EventBus
+
+// Publish implements EventBus
+func (buses EventBuses) Publish(ctx context.Context, event Domain.Event) {
+	for _, bus := range buses {
+		bus.Publish(ctx, event)
+	}
+}
+
+// EventBroker hands published events to the subscribers in this process, such as the task event
+// stream. A subscriber that falls behind misses events rather than holding up the publisher.
+type EventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan Domain.Event]struct{}
+	closed      bool
+	logger      *slog.Logger
+}
+
+// NewEventBroker creates a new instance of EventBroker
+func NewEventBroker(logger *slog.Logger) *EventBroker {
+	return &EventBroker{
+		subscribers: make(map[chan Domain.Event]struct{}),
+		logger:      logger,
+	}
+}
+
+// Publish implements EventBus
+func (b *EventBroker) Publish(ctx context.Context, event Domain.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			b.logger.WarnContext(ctx, "event subscriber is falling behind, dropping event", "event", event.Type, "event_id", event.ID)
+		}
+	}
+}
+
+// Subscribe returns the events published from now on. When ctx ends the subscription is removed
+// and the channel closed, so a subscriber only has to watch its own context.
+func (b *EventBroker) Subscribe(ctx context.Context) <-chan Domain.Event {
+	subscriber := make(chan Domain.Event, eventSubscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(subscriber)
+		return subscriber
+	}
+	b.subscribers[subscriber] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// Close may have ended the subscription already
+		if _, ok := b.subscribers[subscriber]; ok {
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+		}
+	}()
+
+	return subscriber
+}
+
+// Close ends every subscription, closing its channel, and makes later ones end straight away.
+// Events published afterwards reach no one.
+func (b *EventBroker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for subscriber := range b.subscribers {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// Subscribers returns how many subscriptions are open
+func (b *EventBroker) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
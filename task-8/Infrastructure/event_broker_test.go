@@ -0,0 +1,109 @@
+package Infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+// recordingEventBus keeps the events published to it
+type recordingEventBus struct {
+	events []Domain.Event
+}
+
+func (b *recordingEventBus) Publish(ctx context.Context, event Domain.Event) {
+	b.events = append(b.events, event)
+}
+
+func TestEventBuses(t *testing.T) {
+	// Arrange
+	first, second := &recordingEventBus{}, &recordingEventBus{}
+	event := Domain.NewEvent(Domain.EventTaskCreated, "", nil)
+
+	// Act
+	EventBuses{first, second}.Publish(context.Background(), event)
+
+	// Assert
+	assert.Equal(t, []Domain.Event{event}, first.events)
+	assert.Equal(t, []Domain.Event{event}, second.events)
+}
+
+func TestEventBroker(t *testing.T) {
+	t.Run("Success - every subscriber gets events published after it subscribed", func(t *testing.T) {
+		// Arrange
+		broker := NewEventBroker(NewNopLogger())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		broker.Publish(ctx, Domain.NewEvent(Domain.EventTaskCreated, "", nil))
+		first := broker.Subscribe(ctx)
+		second := broker.Subscribe(ctx)
+		event := Domain.NewEvent(Domain.EventTaskUpdated, "", nil)
+
+		// Act
+		broker.Publish(ctx, event)
+
+		// Assert
+		assert.Equal(t, event, <-first)
+		assert.Equal(t, event, <-second)
+		assert.Empty(t, first)
+		assert.Equal(t, 2, broker.Subscribers())
+	})
+
+	t.Run("Success - a slow subscriber misses events instead of blocking", func(t *testing.T) {
+		// Arrange
+		broker := NewEventBroker(NewNopLogger())
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := broker.Subscribe(ctx)
+
+		// Act
+		for i := 0; i < eventSubscriberBuffer+10; i++ {
+			broker.Publish(ctx, Domain.NewEvent(Domain.EventTaskUpdated, "", nil))
+		}
+
+		// Assert
+		assert.Len(t, events, eventSubscriberBuffer)
+	})
+
+	t.Run("Success - ending the context unsubscribes and closes the channel", func(t *testing.T) {
+		// Arrange
+		broker := NewEventBroker(NewNopLogger())
+		ctx, cancel := context.WithCancel(context.Background())
+		events := broker.Subscribe(ctx)
+
+		// Act
+		cancel()
+
+		// Assert
+		select {
+		case _, ok := <-events:
+			assert.False(t, ok)
+		case <-time.After(time.Second):
+			t.Fatal("the channel was not closed")
+		}
+		assert.Equal(t, 0, broker.Subscribers())
+		broker.Publish(context.Background(), Domain.NewEvent(Domain.EventTaskUpdated, "", nil))
+	})
+	t.Run("Success - closing ends every subscription", func(t *testing.T) {
+		// Arrange
+		broker := NewEventBroker(NewNopLogger())
+		ctx, cancel := context.WithCancel(context.Background())
+		events := broker.Subscribe(ctx)
+
+		// Act
+		broker.Close()
+		cancel()
+		late := broker.Subscribe(context.Background())
+
+		// Assert
+		_, ok := <-events
+		assert.False(t, ok)
+		_, ok = <-late
+		assert.False(t, ok)
+		assert.Equal(t, 0, broker.Subscribers())
+	})
+}
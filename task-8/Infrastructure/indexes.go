@@ -0,0 +1,48 @@
+package Infrastructure
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// IndexEnsurer creates the indexes a collection relies on. The MongoDB implementations are the
+// repositories, which create their indexes idempotently.
+type IndexEnsurer interface {
+	EnsureIndexes(ctx context.Context) error
+}
+
+// CollectionIndexes names the collection whose indexes an IndexEnsurer maintains, for logging
+type CollectionIndexes struct {
+	Collection string
+	Ensurer    IndexEnsurer
+}
+
+// StrictIndexes reports whether STRICT_INDEXES is true, making an index that cannot be created
+// stop startup instead of only being logged
+func StrictIndexes() bool {
+	strict, _ := strconv.ParseBool(os.Getenv("STRICT_INDEXES"))
+	return strict
+}
+
+// EnsureIndexes creates the indexes of every collection. Every collection is attempted even after a
+// failure, and each failure is logged. When strict is set, the first failure is returned so the
+// caller can refuse to start; otherwise the server runs on without the missing indexes.
+func EnsureIndexes(ctx context.Context, logger *slog.Logger, strict bool, collections []CollectionIndexes) error {
+	var firstErr error
+	for _, collection := range collections {
+		if err := collection.Ensurer.EnsureIndexes(ctx); err != nil {
+			logger.ErrorContext(ctx, "failed to create indexes", "collection", collection.Collection, "error", err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to create %s indexes: %w", collection.Collection, err)
+			}
+		}
+	}
+
+	if strict {
+		return firstErr
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stubIndexEnsurer counts its calls and fails with err when set
+type stubIndexEnsurer struct {
+	err   error
+	calls int
+}
+
+func (s *stubIndexEnsurer) EnsureIndexes(ctx context.Context) error {
+	s.calls++
+	return s.err
+}
+
+func TestEnsureIndexes(t *testing.T) {
+	setup := func() (*stubIndexEnsurer, *stubIndexEnsurer, []CollectionIndexes) {
+		failing := &stubIndexEnsurer{err: errors.New("index build failed")}
+		healthy := &stubIndexEnsurer{}
+		return failing, healthy, []CollectionIndexes{
+			{Collection: "tasks", Ensurer: failing},
+			{Collection: "users", Ensurer: healthy},
+		}
+	}
+
+	t.Run("Success - failures are only logged by default", func(t *testing.T) {
+		// Arrange
+		failing, healthy, collections := setup()
+
+		// Act
+		err := EnsureIndexes(context.Background(), NewNopLogger(), false, collections)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, failing.calls)
+		assert.Equal(t, 1, healthy.calls)
+	})
+
+	t.Run("Error - strict mode returns the failure after trying every collection", func(t *testing.T) {
+		// Arrange
+		failing, healthy, collections := setup()
+
+		// Act
+		err := EnsureIndexes(context.Background(), NewNopLogger(), true, collections)
+
+		// Assert
+		assert.EqualError(t, err, "failed to create tasks indexes: index build failed")
+		assert.Equal(t, 1, failing.calls)
+		assert.Equal(t, 1, healthy.calls)
+	})
+
+	t.Run("Success - strict mode with every index created", func(t *testing.T) {
+		// Act
+		err := EnsureIndexes(context.Background(), NewNopLogger(), true, []CollectionIndexes{{Collection: "users", Ensurer: &stubIndexEnsurer{}}})
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
+
+func TestStrictIndexes(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected bool
+	}{
+		{"", false},
+		{"false", false},
+		{"true", true},
+		{"1", true},
+		{"yes", false},
+	}
+	for _, tc := range testCases {
+		t.Run("STRICT_INDEXES="+tc.value, func(t *testing.T) {
+			// Arrange
+			t.Setenv("STRICT_INDEXES", tc.value)
+
+			// Act & Assert
+			assert.Equal(t, tc.expected, StrictIndexes())
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package Infrastructure
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+	"task_manager/Domain"
+)
+
+// loadRSAKeys reads the RS256 private key from JWT_PRIVATE_KEY (PEM) or JWT_PRIVATE_KEY_PATH.
+// A public key in JWT_PUBLIC_KEY or JWT_PUBLIC_KEY_PATH is optional, but must match when given.
+func loadRSAKeys() (*rsa.PrivateKey, error) {
+	privatePEM, err := pemFromEnv("JWT_PRIVATE_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if privatePEM == nil {
+		return nil, errors.New("JWT_PRIVATE_KEY or JWT_PRIVATE_KEY_PATH is required for RS256")
+	}
+	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM(privatePEM)
+	if err != nil {
+		return nil, fmt.Errorf("invalid RS256 private key: %w", err)
+	}
+
+	publicPEM, err := pemFromEnv("JWT_PUBLIC_KEY")
+	if err != nil {
+		return nil, err
+	}
+	if publicPEM != nil {
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(publicPEM)
+		if err != nil {
+			return nil, fmt.Errorf("invalid RS256 public key: %w", err)
+		}
+		if !publicKey.Equal(&privateKey.PublicKey) {
+			return nil, errors.New("RS256 public key does not match the private key")
+		}
+	}
+
+	return privateKey, nil
+}
+
+// pemFromEnv returns the PEM in the named variable, or the contents of the file named by <name>_PATH.
+// Escaped newlines are accepted so a key fits on one line of a .env file. It returns nil when neither is set.
+func pemFromEnv(name string) ([]byte, error) {
+	if value := os.Getenv(name); value != "" {
+		return []byte(strings.ReplaceAll(value, `\n`, "\n")), nil
+	}
+	path := os.Getenv(name + "_PATH")
+	if path == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s_PATH: %w", name, err)
+	}
+	return data, nil
+}
+
+// rsaJWK describes an RS256 public key as a JSON Web Key
+func rsaJWK(publicKey *rsa.PublicKey) Domain.JSONWebKey {
+	e := base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	n := base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+	return Domain.JSONWebKey{
+		Kty: "RSA",
+		Use: "sig",
+		Alg: jwt.SigningMethodRS256.Alg(),
+		Kid: rsaKeyID(n, e),
+		N:   n,
+		E:   e,
+	}
+}
+
+// rsaKeyID is the RFC 7638 thumbprint of an RSA key, so the kid is stable for a given key
+func rsaKeyID(n, e string) string {
+	sum := sha256.Sum256([]byte(`{"e":"` + e + `","kty":"RSA","n":"` + n + `"}`))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,183 @@
+package Infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// newTestRSAKeyPEMs generates a throwaway keypair and returns it PEM encoded
+func newTestRSAKeyPEMs(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate RSA key: %v", err)
+	}
+	privatePEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatalf("failed to marshal RSA public key: %v", err)
+	}
+	publicPEM := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER})
+	return string(privatePEM), string(publicPEM)
+}
+
+// setRS256Env configures RS256 with the keys in the environment, clearing any key paths
+func setRS256Env(t *testing.T, privatePEM, publicPEM string) {
+	t.Setenv("JWT_ALGORITHM", "RS256")
+	t.Setenv("JWT_PRIVATE_KEY", privatePEM)
+	t.Setenv("JWT_PUBLIC_KEY", publicPEM)
+	t.Setenv("JWT_PRIVATE_KEY_PATH", "")
+	t.Setenv("JWT_PUBLIC_KEY_PATH", "")
+}
+
+func TestJWTServiceRS256(t *testing.T) {
+	privatePEM, publicPEM := newTestRSAKeyPEMs(t)
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+
+	t.Run("Success - keys from environment", func(t *testing.T) {
+		// Arrange
+		setRS256Env(t, privatePEM, publicPEM)
+		service := NewJWTService()
+
+		// Act
+		token, err := service.GenerateToken(user)
+		assert.NoError(t, err)
+		parsedToken, err := service.ValidateToken(token)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, parsedToken.Valid)
+		assert.Equal(t, "RS256", parsedToken.Method.Alg())
+		assert.Equal(t, service.JWKS().Keys[0].Kid, parsedToken.Header["kid"])
+		assert.Nil(t, service.GetJWTSecret())
+	})
+
+	t.Run("Success - keys from files", func(t *testing.T) {
+		// Arrange
+		dir := t.TempDir()
+		privatePath := filepath.Join(dir, "private.pem")
+		publicPath := filepath.Join(dir, "public.pem")
+		assert.NoError(t, os.WriteFile(privatePath, []byte(privatePEM), 0o600))
+		assert.NoError(t, os.WriteFile(publicPath, []byte(publicPEM), 0o644))
+		setRS256Env(t, "", "")
+		t.Setenv("JWT_PRIVATE_KEY_PATH", privatePath)
+		t.Setenv("JWT_PUBLIC_KEY_PATH", publicPath)
+		service := NewJWTService()
+
+		// Act
+		token, err := service.GenerateToken(user)
+		assert.NoError(t, err)
+		_, err = service.ValidateToken(token)
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Success - single-line PEM with escaped newlines", func(t *testing.T) {
+		// Arrange
+		setRS256Env(t, strings.ReplaceAll(privatePEM, "\n", `\n`), "")
+
+		// Act
+		service := NewJWTService()
+		token, err := service.GenerateToken(user)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotEmpty(t, token)
+	})
+
+	t.Run("Success - public key set describes the signing key", func(t *testing.T) {
+		// Arrange
+		setRS256Env(t, privatePEM, "")
+		service := NewJWTService()
+		token, _ := service.GenerateToken(user)
+
+		// Act
+		keySet := service.JWKS()
+
+		// Assert
+		assert.Len(t, keySet.Keys, 1)
+		key := keySet.Keys[0]
+		assert.Equal(t, "RSA", key.Kty)
+		assert.Equal(t, "sig", key.Use)
+		assert.Equal(t, "RS256", key.Alg)
+		assert.Equal(t, "AQAB", key.E)
+
+		// A verifier holding only the public key accepts the token
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM([]byte(publicPEM))
+		assert.NoError(t, err)
+		assert.Equal(t, rsaJWK(publicKey), key)
+		_, err = jwt.Parse(token, func(*jwt.Token) (interface{}, error) { return publicKey, nil })
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - HS256 token signed with the public key", func(t *testing.T) {
+		// Arrange
+		setRS256Env(t, privatePEM, publicPEM)
+		service := NewJWTService()
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "attacker", "role": Domain.RoleAdmin})
+		forgedToken, err := forged.SignedString([]byte(publicPEM))
+		assert.NoError(t, err)
+
+		// Act
+		_, err = service.ValidateToken(forgedToken)
+
+		// Assert
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+
+	t.Run("Error - HS256 service rejects RS256 tokens", func(t *testing.T) {
+		// Arrange
+		setRS256Env(t, privatePEM, publicPEM)
+		token, _ := NewJWTService().GenerateToken(user)
+		t.Setenv("JWT_ALGORITHM", "HS256")
+		service := NewJWTService()
+
+		// Act
+		_, err := service.ValidateToken(token)
+
+		// Assert
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+		assert.Nil(t, service.JWKS())
+	})
+}
+
+func TestNewJWTServiceInvalidConfiguration(t *testing.T) {
+	privatePEM, _ := newTestRSAKeyPEMs(t)
+	_, otherPublicPEM := newTestRSAKeyPEMs(t)
+
+	tests := []struct {
+		name       string
+		algorithm  string
+		privatePEM string
+		publicPEM  string
+	}{
+		{name: "Unsupported algorithm", algorithm: "ES256"},
+		{name: "Missing private key", algorithm: "RS256"},
+		{name: "Malformed private key", algorithm: "RS256", privatePEM: "not a key"},
+		{name: "Mismatched public key", algorithm: "RS256", privatePEM: privatePEM, publicPEM: otherPublicPEM},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			setRS256Env(t, tt.privatePEM, tt.publicPEM)
+			t.Setenv("JWT_ALGORITHM", tt.algorithm)
+
+			// Act & Assert
+			assert.Panics(t, func() { NewJWTService() })
+		})
+	}
+}
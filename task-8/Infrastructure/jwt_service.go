@@ -1,62 +1,308 @@
 package Infrastructure
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"task_manager/Domain"
 )
 
+// Default token lifetimes, overridable with JWT_EXPIRY, REFRESH_TOKEN_TTL and EMAIL_VERIFICATION_TTL
+const (
+	DefaultAccessTokenTTL       = 24 * time.Hour
+	DefaultRefreshTokenTTL      = 7 * 24 * time.Hour
+	DefaultEmailVerificationTTL = 24 * time.Hour
+)
+
+// emailVerificationPurpose marks email verification tokens. Access tokens carry no purpose claim,
+// so one kind of token is never accepted as the other.
+const emailVerificationPurpose = "email_verification"
+
 // JWTServiceInterface defines the contract for JWT operations
 type JWTServiceInterface interface {
 	GenerateToken(user *Domain.User) (string, error)
+	GenerateRefreshToken() (string, time.Time, error)
 	ValidateToken(tokenString string) (*jwt.Token, error)
 	GetJWTSecret() []byte
+	AccessTokenTTL() time.Duration
+	JWKS() *Domain.JSONWebKeySet
+	GenerateEmailVerificationToken(user *Domain.User) (string, time.Time, error)
+	ValidateEmailVerificationToken(tokenString string) (*Domain.EmailVerificationClaims, error)
 }
 
 // JWTService implements JWT token operations
 type JWTService struct {
-	secret []byte
+	method       jwt.SigningMethod
+	secret       []byte      // HS256 only; the newest secret
+	signingKey   interface{} // secret for HS256, *rsa.PrivateKey for RS256
+	signingKeyID string      // kid header of new tokens; empty for a lone JWT_SECRET
+	verifyKeys   map[string]interface{}
+	jwks         *Domain.JSONWebKeySet
+	accessTTL    time.Duration
+	refreshTTL   time.Duration
+	verifyTTL    time.Duration
+	issuer       string
+	audience     string
 }
 
-// NewJWTService creates a new instance of JWTService
+// NewJWTService creates a new instance of JWTService.
+// Tokens are signed with HS256 and JWT_SECRET unless JWT_ALGORITHM=RS256, which signs with
+// the private key from JWT_PRIVATE_KEY(_PATH) so other services can verify with the public key alone.
+// For HS256, JWT_SECRETS="k2:newsecret,k1:oldsecret" enables rotation: new tokens are signed with
+// the first key and carry its kid, and tokens are verified with the key their kid names.
+// When JWT_ISSUER or JWT_AUDIENCE is set, tokens carry the iss/aud claim and tokens
+// without the same value are rejected, so environments sharing a secret cannot swap tokens.
+// It panics on an unknown algorithm or unusable keys, so a misconfigured server fails at startup.
 func NewJWTService() JWTServiceInterface {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		secret = "your-super-secret-jwt-key-change-this-in-production"
+	service := &JWTService{
+		verifyKeys: make(map[string]interface{}),
+		accessTTL:  durationFromEnv("JWT_EXPIRY", DefaultAccessTokenTTL),
+		refreshTTL: durationFromEnv("REFRESH_TOKEN_TTL", DefaultRefreshTokenTTL),
+		verifyTTL:  durationFromEnv("EMAIL_VERIFICATION_TTL", DefaultEmailVerificationTTL),
+		issuer:     os.Getenv("JWT_ISSUER"),
+		audience:   os.Getenv("JWT_AUDIENCE"),
 	}
-	
-	return &JWTService{
-		secret: []byte(secret),
+
+	switch algorithm := strings.ToUpper(os.Getenv("JWT_ALGORITHM")); algorithm {
+	case "", "HS256":
+		service.method = jwt.SigningMethodHS256
+		if err := service.loadSecrets(); err != nil {
+			panic(fmt.Sprintf("invalid JWT configuration: %v", err))
+		}
+	case "RS256":
+		privateKey, err := loadRSAKeys()
+		if err != nil {
+			panic(fmt.Sprintf("invalid JWT configuration: %v", err))
+		}
+		jwk := rsaJWK(&privateKey.PublicKey)
+		service.method = jwt.SigningMethodRS256
+		service.signingKey = privateKey
+		service.signingKeyID = jwk.Kid
+		service.verifyKeys[jwk.Kid] = &privateKey.PublicKey
+		service.jwks = &Domain.JSONWebKeySet{Keys: []Domain.JSONWebKey{jwk}}
+	default:
+		panic(fmt.Sprintf("invalid JWT configuration: unsupported JWT_ALGORITHM %q, use HS256 or RS256", algorithm))
 	}
+
+	return service
 }
 
-// GenerateToken generates a JWT token for a user
+// loadSecrets sets up the HS256 keys. With JWT_SECRETS the first entry signs; a JWT_SECRET set
+// alongside it still verifies tokens without a kid, issued before the keyring was introduced.
+func (js *JWTService) loadSecrets() error {
+	legacySecret := os.Getenv("JWT_SECRET")
+
+	keyring := os.Getenv("JWT_SECRETS")
+	if keyring == "" {
+		if legacySecret == "" {
+			legacySecret = "your-super-secret-jwt-key-change-this-in-production"
+		}
+		js.secret = []byte(legacySecret)
+		js.signingKey = js.secret
+		js.verifyKeys[""] = js.secret
+		return nil
+	}
+
+	for i, entry := range strings.Split(keyring, ",") {
+		kid, secret, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if !ok || kid == "" || secret == "" {
+			return fmt.Errorf("JWT_SECRETS entry %d must be in the form kid:secret", i+1)
+		}
+		if _, exists := js.verifyKeys[kid]; exists {
+			return fmt.Errorf("JWT_SECRETS has duplicate key ID %q", kid)
+		}
+		js.verifyKeys[kid] = []byte(secret)
+		if i == 0 {
+			js.secret = []byte(secret)
+			js.signingKey = js.secret
+			js.signingKeyID = kid
+		}
+	}
+	if legacySecret != "" {
+		js.verifyKeys[""] = []byte(legacySecret)
+	}
+	return nil
+}
+
+// durationFromEnv parses a positive duration such as "30m", keeping the default for unset or invalid values
+func durationFromEnv(name string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(name)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+// GenerateToken generates a JWT token for a user. The jti claim identifies the token so logout can revoke it.
 func (js *JWTService) GenerateToken(user *Domain.User) (string, error) {
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+
 	claims := jwt.MapClaims{
+		"jti":      hex.EncodeToString(jti),
 		"user_id":  user.ID.Hex(),
 		"username": user.Username,
 		"role":     user.Role,
-		"exp":      time.Now().Add(time.Hour * 24).Unix(), // Token expires in 24 hours
+		"exp":      time.Now().Add(js.accessTTL).Unix(),
 		"iat":      time.Now().Unix(),
 	}
+	return js.sign(claims)
+}
+
+// sign adds the configured issuer and audience to the claims and signs them with the current key
+func (js *JWTService) sign(claims jwt.MapClaims) (string, error) {
+	if js.issuer != "" {
+		claims["iss"] = js.issuer
+	}
+	if js.audience != "" {
+		claims["aud"] = js.audience
+	}
+
+	token := jwt.NewWithClaims(js.method, claims)
+	if js.signingKeyID != "" {
+		token.Header["kid"] = js.signingKeyID
+	}
+	return token.SignedString(js.signingKey)
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(js.secret)
+// GenerateEmailVerificationToken returns a signed token confirming the user's current email address
+// and when it expires. It names the address, so changing the email invalidates earlier tokens.
+func (js *JWTService) GenerateEmailVerificationToken(user *Domain.User) (string, time.Time, error) {
+	expiresAt := time.Now().Add(js.verifyTTL)
+	token, err := js.sign(jwt.MapClaims{
+		"sub":     user.ID.Hex(),
+		"email":   user.Email,
+		"purpose": emailVerificationPurpose,
+		"exp":     expiresAt.Unix(),
+		"iat":     time.Now().Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
 }
 
-// ValidateToken validates a JWT token and returns the parsed token
+// ValidateEmailVerificationToken checks an email verification token and returns its claims.
+// It fails with "verification token expired" for expired tokens and "invalid verification token" otherwise.
+func (js *JWTService) ValidateEmailVerificationToken(tokenString string) (*Domain.EmailVerificationClaims, error) {
+	token, err := js.parse(tokenString)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, Domain.ErrVerificationTokenExpired
+		}
+		return nil, Domain.ErrInvalidVerificationToken
+	}
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || claims["purpose"] != emailVerificationPurpose {
+		return nil, Domain.ErrInvalidVerificationToken
+	}
+	userID, _ := claims["sub"].(string)
+	email, _ := claims["email"].(string)
+	if userID == "" || email == "" {
+		return nil, Domain.ErrInvalidVerificationToken
+	}
+
+	return &Domain.EmailVerificationClaims{UserID: userID, Email: email}, nil
+}
+
+// GenerateRefreshToken returns a random, opaque refresh token and when it expires.
+// Only its HashRefreshToken hash should be stored.
+func (js *JWTService) GenerateRefreshToken() (string, time.Time, error) {
+	token, err := GenerateOpaqueToken()
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, time.Now().Add(js.refreshTTL), nil
+}
+
+// HashRefreshToken returns the SHA-256 hash under which a refresh token is stored
+func HashRefreshToken(token string) string {
+	return HashOpaqueToken(token)
+}
+
+// ValidateToken validates an access token and returns the parsed token.
+// Only the configured algorithm is accepted, so neither "none" nor an HS256 token signed
+// with the RS256 public key gets through. The key is chosen by the token's kid header, so
+// tokens signed with a key that was rotated out fail. The issuer and audience are checked when they are configured.
+// Tokens issued for another purpose, such as email verification, are rejected.
 func (js *JWTService) ValidateToken(tokenString string) (*jwt.Token, error) {
-	return jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, jwt.ErrSignatureInvalid
+	token, err := js.parse(tokenString)
+	if err != nil {
+		return token, err
+	}
+	if claims, ok := token.Claims.(jwt.MapClaims); ok && claims["purpose"] != nil {
+		return token, errors.New("token is not an access token")
+	}
+	return token, nil
+}
+
+// parse verifies the signature, algorithm, expiry, issuer and audience of any token this service signs
+func (js *JWTService) parse(tokenString string) (*jwt.Token, error) {
+	parserOptions := []jwt.ParserOption{jwt.WithValidMethods([]string{js.method.Alg()})}
+	if js.issuer != "" {
+		parserOptions = append(parserOptions, jwt.WithIssuer(js.issuer))
+	}
+	if js.audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(js.audience))
+	}
+
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, ok := js.verifyKeys[kid]
+		if !ok {
+			if kid == "" {
+				return nil, errors.New("token has no key ID")
+			}
+			return nil, fmt.Errorf("token signed with unknown key %q", kid)
 		}
-		return js.secret, nil
-	})
+		return key, nil
+	}, parserOptions...)
+
+	switch {
+	case errors.Is(err, jwt.ErrTokenInvalidIssuer):
+		return token, fmt.Errorf("token has invalid issuer: expected %q", js.issuer)
+	case errors.Is(err, jwt.ErrTokenInvalidAudience):
+		return token, fmt.Errorf("token has invalid audience: expected %q", js.audience)
+	case errors.Is(err, jwt.ErrTokenRequiredClaimMissing):
+		// Tokens issued before the issuer or audience was configured lack the claim altogether
+		if js.issuer != "" && !hasClaim(token, "iss") {
+			return token, fmt.Errorf("token has invalid issuer: expected %q", js.issuer)
+		}
+		if js.audience != "" && !hasClaim(token, "aud") {
+			return token, fmt.Errorf("token has invalid audience: expected %q", js.audience)
+		}
+	}
+	return token, err
 }
 
-// GetJWTSecret returns the JWT secret key
+// hasClaim reports whether the token's claims include the named claim
+func hasClaim(token *jwt.Token, name string) bool {
+	if token == nil {
+		return false
+	}
+	claims, ok := token.Claims.(jwt.MapClaims)
+	return ok && claims[name] != nil
+}
+
+// GetJWTSecret returns the secret new tokens are signed with, or nil when tokens are signed with RS256
 func (js *JWTService) GetJWTSecret() []byte {
 	return js.secret
-}
\ No newline at end of file
+}
+
+// JWKS returns the public keys that verify tokens, or nil when tokens are signed with a shared secret
+func (js *JWTService) JWKS() *Domain.JSONWebKeySet {
+	return js.jwks
+}
+
+// AccessTokenTTL returns how long access tokens are valid
+func (js *JWTService) AccessTokenTTL() time.Duration {
+	return js.accessTTL
+}
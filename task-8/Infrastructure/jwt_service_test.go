@@ -23,7 +23,7 @@ func (suite *JWTServiceTestSuite) SetupTest() {
 	// Set a test JWT secret
 	os.Setenv("JWT_SECRET", "test-secret-key-for-testing")
 	suite.jwtService = NewJWTService()
-	
+
 	// Create a test user
 	suite.testUser = &Domain.User{
 		ID:       primitive.NewObjectID(),
@@ -78,28 +78,28 @@ func (suite *JWTServiceTestSuite) TestGenerateToken() {
 			} else {
 				assert.NoError(suite.T(), err)
 				assert.NotEmpty(suite.T(), token)
-				
+
 				// Verify token structure (should have 3 parts separated by dots)
 				parts := len(token)
 				assert.True(suite.T(), parts > 0)
-				
+
 				// Verify token can be parsed
 				parsedToken, err := suite.jwtService.ValidateToken(token)
 				assert.NoError(suite.T(), err)
 				assert.True(suite.T(), parsedToken.Valid)
-				
+
 				// Verify claims
 				claims, ok := parsedToken.Claims.(jwt.MapClaims)
 				assert.True(suite.T(), ok)
 				assert.Equal(suite.T(), tt.user.ID.Hex(), claims["user_id"])
 				assert.Equal(suite.T(), tt.user.Username, claims["username"])
 				assert.Equal(suite.T(), tt.user.Role, claims["role"])
-				
-				// Verify expiration is set (should be 24 hours from now)
+
+				// Verify expiration is set (should be the access token lifetime from now)
 				exp, ok := claims["exp"].(float64)
 				assert.True(suite.T(), ok)
 				assert.True(suite.T(), exp > float64(time.Now().Unix()))
-				
+
 				// Verify issued at time
 				iat, ok := claims["iat"].(float64)
 				assert.True(suite.T(), ok)
@@ -209,7 +209,7 @@ func TestNewJWTService(t *testing.T) {
 
 		service := NewJWTService()
 		assert.NotNil(t, service)
-		
+
 		expectedDefault := "your-super-secret-jwt-key-change-this-in-production"
 		assert.Equal(t, []byte(expectedDefault), service.GetJWTSecret())
 	})
@@ -236,17 +236,404 @@ func TestJWTServiceTokenExpiration(t *testing.T) {
 	claims, ok := parsedToken.Claims.(jwt.MapClaims)
 	assert.True(t, ok)
 
-	// Check that expiration is approximately 24 hours from now
+	// Check that expiration is approximately the default access token lifetime from now
 	exp, ok := claims["exp"].(float64)
 	assert.True(t, ok)
-	
-	expectedExp := time.Now().Add(24 * time.Hour).Unix()
+
+	expectedExp := time.Now().Add(DefaultAccessTokenTTL).Unix()
 	actualExp := int64(exp)
-	
+
 	// Allow for a small time difference (within 1 minute)
 	assert.True(t, actualExp >= expectedExp-60 && actualExp <= expectedExp+60)
 }
 
+func TestJWTServiceTokenLifetimes(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("JWT_EXPIRY", "")
+		t.Setenv("REFRESH_TOKEN_TTL", "")
+
+		// Act
+		service := NewJWTService()
+		_, expiresAt, err := service.GenerateRefreshToken()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 24*time.Hour, service.AccessTokenTTL())
+		assert.WithinDuration(t, time.Now().Add(DefaultRefreshTokenTTL), expiresAt, time.Minute)
+	})
+
+	t.Run("Zero expiry uses the default", func(t *testing.T) {
+		// Arrange
+		t.Setenv("JWT_EXPIRY", "0s")
+
+		// Act
+		service := NewJWTService()
+
+		// Assert
+		assert.Equal(t, 24*time.Hour, service.AccessTokenTTL())
+	})
+
+	t.Run("From environment", func(t *testing.T) {
+		// Arrange
+		t.Setenv("JWT_EXPIRY", "5m")
+		t.Setenv("REFRESH_TOKEN_TTL", "48h")
+
+		// Act
+		service := NewJWTService()
+		_, expiresAt, err := service.GenerateRefreshToken()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 5*time.Minute, service.AccessTokenTTL())
+		assert.WithinDuration(t, time.Now().Add(48*time.Hour), expiresAt, time.Minute)
+	})
+
+	t.Run("Invalid values use defaults", func(t *testing.T) {
+		// Arrange
+		t.Setenv("JWT_EXPIRY", "soon")
+		t.Setenv("REFRESH_TOKEN_TTL", "-1h")
+
+		// Act
+		service := NewJWTService()
+		_, expiresAt, err := service.GenerateRefreshToken()
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, DefaultAccessTokenTTL, service.AccessTokenTTL())
+		assert.WithinDuration(t, time.Now().Add(DefaultRefreshTokenTTL), expiresAt, time.Minute)
+	})
+}
+
+func TestJWTServiceShortExpiry(t *testing.T) {
+	// Arrange
+	t.Setenv("JWT_EXPIRY", "1s")
+	service := NewJWTService()
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+
+	token, err := service.GenerateToken(user)
+	assert.NoError(t, err)
+	_, err = service.ValidateToken(token)
+	assert.NoError(t, err)
+
+	// Act
+	time.Sleep(1100 * time.Millisecond)
+	parsedToken, err := service.ValidateToken(token)
+
+	// Assert
+	assert.ErrorIs(t, err, jwt.ErrTokenExpired)
+	assert.False(t, parsedToken.Valid)
+}
+
+func TestJWTServiceIssuerAndAudience(t *testing.T) {
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+
+	newService := func(t *testing.T, issuer, audience string) JWTServiceInterface {
+		t.Setenv("JWT_SECRET", "shared-secret")
+		t.Setenv("JWT_ISSUER", issuer)
+		t.Setenv("JWT_AUDIENCE", audience)
+		return NewJWTService()
+	}
+
+	t.Run("Success - claims are set and accepted", func(t *testing.T) {
+		// Arrange
+		service := newService(t, "task-manager-prod", "task-manager-api")
+		token, err := service.GenerateToken(user)
+		assert.NoError(t, err)
+
+		// Act
+		parsedToken, err := service.ValidateToken(token)
+
+		// Assert
+		assert.NoError(t, err)
+		claims := parsedToken.Claims.(jwt.MapClaims)
+		assert.Equal(t, "task-manager-prod", claims["iss"])
+		assert.Equal(t, "task-manager-api", claims["aud"])
+	})
+
+	t.Run("Success - claims are omitted when unset", func(t *testing.T) {
+		// Arrange
+		service := newService(t, "", "")
+		token, _ := service.GenerateToken(user)
+
+		// Act
+		parsedToken, err := service.ValidateToken(token)
+
+		// Assert
+		assert.NoError(t, err)
+		claims := parsedToken.Claims.(jwt.MapClaims)
+		assert.NotContains(t, claims, "iss")
+		assert.NotContains(t, claims, "aud")
+	})
+
+	t.Run("Error - token from another issuer", func(t *testing.T) {
+		// Arrange
+		staging := newService(t, "task-manager-staging", "task-manager-api")
+		token, _ := staging.GenerateToken(user)
+		prod := newService(t, "task-manager-prod", "task-manager-api")
+
+		// Act
+		_, err := prod.ValidateToken(token)
+
+		// Assert
+		assert.EqualError(t, err, `token has invalid issuer: expected "task-manager-prod"`)
+	})
+
+	t.Run("Error - token for another audience", func(t *testing.T) {
+		// Arrange
+		other := newService(t, "task-manager-prod", "reporting-api")
+		token, _ := other.GenerateToken(user)
+		service := newService(t, "task-manager-prod", "task-manager-api")
+
+		// Act
+		_, err := service.ValidateToken(token)
+
+		// Assert
+		assert.EqualError(t, err, `token has invalid audience: expected "task-manager-api"`)
+	})
+
+	t.Run("Error - token without issuer when one is required", func(t *testing.T) {
+		// Arrange
+		legacy := newService(t, "", "")
+		token, _ := legacy.GenerateToken(user)
+		service := newService(t, "task-manager-prod", "")
+
+		// Act
+		_, err := service.ValidateToken(token)
+
+		// Assert
+		assert.EqualError(t, err, `token has invalid issuer: expected "task-manager-prod"`)
+	})
+
+	t.Run("Error - token without audience when one is required", func(t *testing.T) {
+		// Arrange
+		legacy := newService(t, "task-manager-prod", "")
+		token, _ := legacy.GenerateToken(user)
+		service := newService(t, "task-manager-prod", "task-manager-api")
+
+		// Act
+		_, err := service.ValidateToken(token)
+
+		// Assert
+		assert.EqualError(t, err, `token has invalid audience: expected "task-manager-api"`)
+	})
+}
+
+func TestJWTServiceKeyRotation(t *testing.T) {
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+
+	newService := func(t *testing.T, secret, secrets string) JWTServiceInterface {
+		t.Setenv("JWT_SECRET", secret)
+		t.Setenv("JWT_SECRETS", secrets)
+		return NewJWTService()
+	}
+
+	t.Run("Tokens stay valid across a rotation until their key is removed", func(t *testing.T) {
+		// Arrange: k1 is the only key
+		before := newService(t, "", "k1:old-secret")
+		oldToken, err := before.GenerateToken(user)
+		assert.NoError(t, err)
+
+		// Act: k2 is added as the signing key, then k1 is retired
+		during := newService(t, "", "k2:new-secret,k1:old-secret")
+		newToken, err := during.GenerateToken(user)
+		assert.NoError(t, err)
+		_, oldDuringErr := during.ValidateToken(oldToken)
+		_, newDuringErr := during.ValidateToken(newToken)
+
+		after := newService(t, "", "k2:new-secret")
+		_, oldAfterErr := after.ValidateToken(oldToken)
+		_, newAfterErr := after.ValidateToken(newToken)
+
+		// Assert
+		parsedOld, _ := before.ValidateToken(oldToken)
+		parsedNew, _ := during.ValidateToken(newToken)
+		assert.Equal(t, "k1", parsedOld.Header["kid"])
+		assert.Equal(t, "k2", parsedNew.Header["kid"])
+		assert.Equal(t, []byte("new-secret"), during.GetJWTSecret())
+
+		assert.NoError(t, oldDuringErr)
+		assert.NoError(t, newDuringErr)
+		assert.ErrorIs(t, oldAfterErr, jwt.ErrTokenUnverifiable)
+		assert.Contains(t, oldAfterErr.Error(), `token signed with unknown key "k1"`)
+		assert.NoError(t, newAfterErr)
+	})
+
+	t.Run("Tokens from a lone JWT_SECRET stay valid while it is kept", func(t *testing.T) {
+		// Arrange
+		legacy := newService(t, "legacy-secret", "")
+		legacyToken, err := legacy.GenerateToken(user)
+		assert.NoError(t, err)
+		parsed, _ := legacy.ValidateToken(legacyToken)
+		assert.NotContains(t, parsed.Header, "kid")
+
+		// Act
+		_, keptErr := newService(t, "legacy-secret", "k1:new-secret").ValidateToken(legacyToken)
+		_, droppedErr := newService(t, "", "k1:new-secret").ValidateToken(legacyToken)
+
+		// Assert
+		assert.NoError(t, keptErr)
+		assert.ErrorIs(t, droppedErr, jwt.ErrTokenUnverifiable)
+		assert.Contains(t, droppedErr.Error(), "token has no key ID")
+	})
+
+	t.Run("Token whose kid names another key is rejected", func(t *testing.T) {
+		// Arrange
+		service := newService(t, "", "k2:new-secret,k1:old-secret")
+		forged := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"user_id": "someone", "exp": time.Now().Add(time.Hour).Unix()})
+		forged.Header["kid"] = "k2"
+		forgedToken, err := forged.SignedString([]byte("old-secret"))
+		assert.NoError(t, err)
+
+		// Act
+		_, err = service.ValidateToken(forgedToken)
+
+		// Assert
+		assert.ErrorIs(t, err, jwt.ErrTokenSignatureInvalid)
+	})
+
+	t.Run("Invalid keyrings fail at startup", func(t *testing.T) {
+		for _, secrets := range []string{"k1", ":secret", "k1:", "k1:a,k1:b"} {
+			t.Setenv("JWT_SECRETS", secrets)
+			assert.Panics(t, func() { NewJWTService() }, secrets)
+		}
+	})
+}
+
+func TestJWTServiceTokenIDs(t *testing.T) {
+	service := NewJWTService()
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleUser}
+
+	first, err := service.GenerateToken(user)
+	assert.NoError(t, err)
+	second, err := service.GenerateToken(user)
+	assert.NoError(t, err)
+
+	firstToken, err := service.ValidateToken(first)
+	assert.NoError(t, err)
+	secondToken, err := service.ValidateToken(second)
+	assert.NoError(t, err)
+
+	// Every access token gets its own jti so logout can revoke exactly that token
+	firstID, ok := firstToken.Claims.(jwt.MapClaims)["jti"].(string)
+	assert.True(t, ok)
+	assert.Len(t, firstID, 32)
+	assert.NotEqual(t, firstID, secondToken.Claims.(jwt.MapClaims)["jti"])
+}
+
+func TestJWTServiceEmailVerificationTokens(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret-key-for-testing")
+	service := NewJWTService()
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Email: "user@example.com", Role: Domain.RoleUser}
+
+	t.Run("Success - token names the user and email", func(t *testing.T) {
+		// Arrange
+		token, expiresAt, err := service.GenerateEmailVerificationToken(user)
+		assert.NoError(t, err)
+
+		// Act
+		claims, err := service.ValidateEmailVerificationToken(token)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &Domain.EmailVerificationClaims{UserID: user.ID.Hex(), Email: "user@example.com"}, claims)
+		assert.WithinDuration(t, time.Now().Add(DefaultEmailVerificationTTL), expiresAt, 5*time.Second)
+	})
+
+	t.Run("Success - lifetime from environment", func(t *testing.T) {
+		// Arrange
+		t.Setenv("EMAIL_VERIFICATION_TTL", "2h")
+
+		// Act
+		_, expiresAt, err := NewJWTService().GenerateEmailVerificationToken(user)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(2*time.Hour), expiresAt, 5*time.Second)
+	})
+
+	t.Run("Error - verification token is not an access token", func(t *testing.T) {
+		// Arrange
+		token, _, err := service.GenerateEmailVerificationToken(user)
+		assert.NoError(t, err)
+
+		// Act
+		_, err = service.ValidateToken(token)
+
+		// Assert
+		assert.EqualError(t, err, "token is not an access token")
+	})
+
+	t.Run("Error - access token is not a verification token", func(t *testing.T) {
+		// Arrange
+		token, err := service.GenerateToken(user)
+		assert.NoError(t, err)
+
+		// Act
+		claims, err := service.ValidateEmailVerificationToken(token)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidVerificationToken)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("Error - expired token", func(t *testing.T) {
+		// Arrange
+		expired := *service.(*JWTService)
+		expired.verifyTTL = -time.Minute
+		token, _, err := expired.GenerateEmailVerificationToken(user)
+		assert.NoError(t, err)
+
+		// Act
+		claims, err := service.ValidateEmailVerificationToken(token)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrVerificationTokenExpired)
+		assert.Nil(t, claims)
+	})
+
+	t.Run("Error - token signed with another secret", func(t *testing.T) {
+		// Arrange
+		t.Setenv("JWT_SECRET", "another-secret")
+		token, _, err := NewJWTService().GenerateEmailVerificationToken(user)
+		assert.NoError(t, err)
+
+		// Act
+		_, err = service.ValidateEmailVerificationToken(token)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidVerificationToken)
+	})
+}
+
+func TestJWTServiceRefreshTokens(t *testing.T) {
+	t.Run("Tokens are random", func(t *testing.T) {
+		// Arrange
+		service := NewJWTService()
+
+		// Act
+		first, _, err1 := service.GenerateRefreshToken()
+		second, _, err2 := service.GenerateRefreshToken()
+
+		// Assert
+		assert.NoError(t, err1)
+		assert.NoError(t, err2)
+		assert.Len(t, first, 43)
+		assert.NotEqual(t, first, second)
+	})
+
+	t.Run("Hash is stable and hides the token", func(t *testing.T) {
+		// Act
+		hash := HashRefreshToken("refresh-token")
+
+		// Assert
+		assert.Equal(t, hash, HashRefreshToken("refresh-token"))
+		assert.NotEqual(t, hash, HashRefreshToken("other-token"))
+		assert.Len(t, hash, 64)
+		assert.NotContains(t, hash, "refresh-token")
+	})
+}
+
 func TestJWTServiceClaimsContent(t *testing.T) {
 	os.Setenv("JWT_SECRET", "test-secret")
 	defer os.Unsetenv("JWT_SECRET")
@@ -272,7 +659,7 @@ func TestJWTServiceClaimsContent(t *testing.T) {
 	assert.Equal(t, userID.Hex(), claims["user_id"])
 	assert.Equal(t, "testuser", claims["username"])
 	assert.Equal(t, Domain.RoleAdmin, claims["role"])
-	
+
 	// Verify exp and iat are present and valid
 	_, expExists := claims["exp"]
 	_, iatExists := claims["iat"]
@@ -490,4 +877,4 @@ func TestJWTServiceSecurityScenarios(t *testing.T) {
 		assert.True(t, ok)
 		assert.Equal(t, string(longUsername), claims["username"])
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,75 @@
+package Infrastructure
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// LocaleMiddleware picks the language responses are written in
+type LocaleMiddleware struct{}
+
+// NewLocaleMiddleware creates a new instance of LocaleMiddleware
+func NewLocaleMiddleware() *LocaleMiddleware {
+	return &LocaleMiddleware{}
+}
+
+// NegotiateLocale stores the supported locale the client prefers, going by Accept-Language,
+// in the request context. Requests without a supported language get Domain.DefaultLocale.
+// The chosen locale is named in the Content-Language header, and Vary tells caches that the
+// response depends on Accept-Language.
+func (lm *LocaleMiddleware) NegotiateLocale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := negotiateLocale(c.GetHeader("Accept-Language"))
+
+		c.Request = c.Request.WithContext(Domain.WithLocale(c.Request.Context(), locale))
+		c.Header("Content-Language", locale)
+		c.Writer.Header().Add("Vary", "Accept-Language")
+
+		c.Next()
+	}
+}
+
+// negotiateLocale returns the supported locale with the highest quality in an Accept-Language
+// header such as "am-ET,am;q=0.9,en;q=0.8". Ranges are matched on their primary subtag, so am-ET
+// selects am; ranges of equal quality keep their header order, and q=0 rules a language out.
+func negotiateLocale(header string) string {
+	type languageRange struct {
+		tag     string
+		quality float64
+	}
+
+	var ranges []languageRange
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		quality := 1.0
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				continue
+			}
+			quality = parsed
+		}
+		primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		if primary == "" || quality <= 0 {
+			continue
+		}
+		ranges = append(ranges, languageRange{tag: primary, quality: quality})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].quality > ranges[j].quality
+	})
+
+	for _, r := range ranges {
+		if r.tag == "*" {
+			return Domain.DefaultLocale
+		}
+		if Domain.IsSupportedLocale(r.tag) {
+			return r.tag
+		}
+	}
+	return Domain.DefaultLocale
+}
@@ -0,0 +1,61 @@
+package Infrastructure
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func TestNegotiateLocale(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{name: "No header", header: "", expected: "en"},
+		{name: "Supported language", header: "am", expected: "am"},
+		{name: "Regional variant matches its language", header: "am-ET,en;q=0.8", expected: "am"},
+		{name: "Unsupported language", header: "fr", expected: "en"},
+		{name: "First supported language is used", header: "fr,am;q=0.5", expected: "am"},
+		{name: "Higher quality wins over order", header: "en;q=0.5,am", expected: "am"},
+		{name: "Zero quality rules a language out", header: "am;q=0", expected: "en"},
+		{name: "Wildcard", header: "*", expected: "en"},
+		{name: "Malformed quality is ignored", header: "am;q=high,en;q=0.1", expected: "en"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, negotiateLocale(tt.header))
+		})
+	}
+}
+
+func TestLocaleMiddleware_NegotiateLocale(t *testing.T) {
+	t.Run("Success - locale is stored and named in the response", func(t *testing.T) {
+		// Arrange
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(NewLocaleMiddleware().NegotiateLocale())
+		var locale string
+		router.GET("/test", func(c *gin.Context) {
+			locale = Domain.LocaleFromContext(c.Request.Context())
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set("Accept-Language", "am-ET")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, "am", locale)
+		assert.Equal(t, "am", w.Header().Get("Content-Language"))
+		assert.Equal(t, "Accept-Language", w.Header().Get("Vary"))
+	})
+}
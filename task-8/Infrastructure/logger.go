@@ -0,0 +1,76 @@
+package Infrastructure
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"task_manager/Domain"
+)
+
+// NewLogger creates the application logger writing to stdout.
+// LOG_LEVEL is one of debug, info, warn or error (default info) and
+// LOG_FORMAT is text or json (default text).
+func NewLogger() *slog.Logger {
+	return newLogger(os.Stdout)
+}
+
+// NewLoggerTo creates the application logger writing to w, configured like NewLogger
+func NewLoggerTo(w io.Writer) *slog.Logger {
+	return newLogger(w)
+}
+
+// NewNopLogger returns a logger that discards everything, for tests
+func NewNopLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelError + 1}))
+}
+
+func newLogger(w io.Writer) *slog.Logger {
+	options := &slog.HandlerOptions{Level: parseLogLevel(os.Getenv("LOG_LEVEL"))}
+
+	var handler slog.Handler
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), "json") {
+		handler = slog.NewJSONHandler(w, options)
+	} else {
+		handler = slog.NewTextHandler(w, options)
+	}
+
+	return slog.New(requestIDHandler{Handler: handler})
+}
+
+// parseLogLevel maps a LOG_LEVEL value to a slog level, defaulting to info
+func parseLogLevel(value string) slog.Level {
+	switch strings.ToLower(value) {
+	case "debug":
+		return slog.LevelDebug
+	case "warn", "warning":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// requestIDHandler adds the request ID carried by the context to every record,
+// so anything logged with a *Context method can be correlated with its request
+type requestIDHandler struct {
+	slog.Handler
+}
+
+func (h requestIDHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := Domain.RequestIDFromContext(ctx); requestID != "" {
+		record.AddAttrs(slog.String(RequestIDKey, requestID))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h requestIDHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return requestIDHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h requestIDHandler) WithGroup(name string) slog.Handler {
+	return requestIDHandler{Handler: h.Handler.WithGroup(name)}
+}
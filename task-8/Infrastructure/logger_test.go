@@ -0,0 +1,97 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func TestParseLogLevel(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected slog.Level
+	}{
+		{value: "", expected: slog.LevelInfo},
+		{value: "debug", expected: slog.LevelDebug},
+		{value: "WARN", expected: slog.LevelWarn},
+		{value: "error", expected: slog.LevelError},
+		{value: "verbose", expected: slog.LevelInfo},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			// Act
+			level := parseLogLevel(tt.value)
+
+			// Assert
+			assert.Equal(t, tt.expected, level)
+		})
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	t.Run("Success - JSON format includes the request ID", func(t *testing.T) {
+		// Arrange
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_LEVEL", "")
+		var buf bytes.Buffer
+		logger := newLogger(&buf)
+		ctx := Domain.WithRequestID(context.Background(), "req-123")
+
+		// Act
+		logger.InfoContext(ctx, "hello", "answer", 42)
+
+		// Assert
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "hello", record["msg"])
+		assert.Equal(t, "req-123", record["request_id"])
+		assert.Equal(t, float64(42), record["answer"])
+	})
+
+	t.Run("Success - records below the level are dropped", func(t *testing.T) {
+		// Arrange
+		t.Setenv("LOG_FORMAT", "")
+		t.Setenv("LOG_LEVEL", "warn")
+		var buf bytes.Buffer
+		logger := newLogger(&buf)
+
+		// Act
+		logger.Info("quiet")
+		logger.Warn("loud")
+
+		// Assert
+		assert.NotContains(t, buf.String(), "quiet")
+		assert.Contains(t, buf.String(), "msg=loud")
+	})
+
+	t.Run("Success - derived loggers keep the request ID", func(t *testing.T) {
+		// Arrange
+		t.Setenv("LOG_FORMAT", "")
+		t.Setenv("LOG_LEVEL", "")
+		var buf bytes.Buffer
+		logger := newLogger(&buf).With("component", "test")
+		ctx := Domain.WithRequestID(context.Background(), "req-123")
+
+		// Act
+		logger.InfoContext(ctx, "hello")
+
+		// Assert
+		assert.Contains(t, buf.String(), "component=test")
+		assert.Contains(t, buf.String(), "request_id=req-123")
+	})
+}
+
+func TestNewNopLogger(t *testing.T) {
+	// Act
+	logger := NewNopLogger()
+
+	// Assert
+	assert.False(t, logger.Enabled(context.Background(), slog.LevelError))
+}
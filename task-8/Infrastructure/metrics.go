@@ -0,0 +1,258 @@
+package Infrastructure
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// latencyBuckets are the upper bounds, in seconds, of the request duration histogram
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// unmatchedRoute labels requests that did not match any route, so unknown paths
+// cannot blow up the number of series
+const unmatchedRoute = "unmatched"
+
+// otherMethod labels requests with a method outside knownMethods, so arbitrary methods
+// cannot blow up the number of series either
+const otherMethod = "OTHER"
+
+// knownMethods are the HTTP methods that get a method label of their own
+var knownMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// Metrics collects request, MongoDB, cache and business metrics and serves them in the Prometheus text
+// format. Each instance keeps its own series, so building several routers never registers anything twice.
+type Metrics struct {
+	mu          sync.Mutex
+	requests    map[requestSeries]*requestStats
+	mongoErrors map[string]uint64
+	caches      map[string]Cache
+	counters    map[businessSeries]uint64
+	summaries   map[businessSeries]*summaryStats
+	inFlight    int64
+
+	username string
+	password string
+}
+
+// requestSeries identifies one request counter and histogram series
+type requestSeries struct {
+	route  string
+	method string
+	status string
+}
+
+// requestStats holds the counter and histogram of one request series
+type requestStats struct {
+	count   uint64
+	sum     float64
+	buckets []uint64
+}
+
+// NewMetrics creates a new instance of Metrics.
+// Setting METRICS_USERNAME and METRICS_PASSWORD protects /metrics with basic auth.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests:    make(map[requestSeries]*requestStats),
+		mongoErrors: make(map[string]uint64),
+		caches:      make(map[string]Cache),
+		counters:    make(map[businessSeries]uint64),
+		summaries:   make(map[businessSeries]*summaryStats),
+		username:    os.Getenv("METRICS_USERNAME"),
+		password:    os.Getenv("METRICS_PASSWORD"),
+	}
+}
+
+// Instrument counts requests and records their latency, labelled by route template, method and status
+func (m *Metrics) Instrument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		atomic.AddInt64(&m.inFlight, 1)
+		defer atomic.AddInt64(&m.inFlight, -1)
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		m.observeRequest(requestSeries{
+			route:  route,
+			method: methodLabel(c.Request.Method),
+			status: strconv.Itoa(c.Writer.Status()),
+		}, time.Since(start))
+	}
+}
+
+// methodLabel returns the method label for a request method
+func methodLabel(method string) string {
+	if knownMethods[method] {
+		return method
+	}
+	return otherMethod
+}
+
+// MongoMonitor returns a command monitor that counts failed MongoDB commands by command name.
+// It must be set on the client options before connecting.
+func (m *Metrics) MongoMonitor() *event.CommandMonitor {
+	return &event.CommandMonitor{
+		Failed: func(_ context.Context, evt *event.CommandFailedEvent) {
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			m.mongoErrors[evt.CommandName]++
+		},
+	}
+}
+
+// ObserveCache reports the hits and misses of a cache under the given name
+func (m *Metrics) ObserveCache(name string, cache Cache) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.caches[name] = cache
+}
+
+// Handlers returns the handlers for GET /metrics, with basic auth in front when it is configured
+func (m *Metrics) Handlers() []gin.HandlerFunc {
+	if m.username == "" || m.password == "" {
+		return []gin.HandlerFunc{m.serve}
+	}
+	return []gin.HandlerFunc{gin.BasicAuth(gin.Accounts{m.username: m.password}), m.serve}
+}
+
+func (m *Metrics) serve(c *gin.Context) {
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(m.render()))
+}
+
+func (m *Metrics) observeRequest(series requestSeries, duration time.Duration) {
+	seconds := duration.Seconds()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats, ok := m.requests[series]
+	if !ok {
+		stats = &requestStats{buckets: make([]uint64, len(latencyBuckets))}
+		m.requests[series] = stats
+	}
+	stats.count++
+	stats.sum += seconds
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			stats.buckets[i]++
+		}
+	}
+}
+
+// render writes every metric in the Prometheus text exposition format, in a stable order
+func (m *Metrics) render() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	series := make([]requestSeries, 0, len(m.requests))
+	for s := range m.requests {
+		series = append(series, s)
+	}
+	sort.Slice(series, func(i, j int) bool {
+		if series[i].route != series[j].route {
+			return series[i].route < series[j].route
+		}
+		if series[i].method != series[j].method {
+			return series[i].method < series[j].method
+		}
+		return series[i].status < series[j].status
+	})
+
+	var b strings.Builder
+
+	b.WriteString("# HELP http_requests_total Total number of HTTP requests served.\n")
+	b.WriteString("# TYPE http_requests_total counter\n")
+	for _, s := range series {
+		fmt.Fprintf(&b, "http_requests_total{%s} %d\n", s.labels(), m.requests[s].count)
+	}
+
+	b.WriteString("# HELP http_request_duration_seconds Time taken to serve HTTP requests.\n")
+	b.WriteString("# TYPE http_request_duration_seconds histogram\n")
+	for _, s := range series {
+		stats := m.requests[s]
+		labels := s.labels()
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=\"%s\"} %d\n", labels, formatFloat(bound), stats.buckets[i])
+		}
+		fmt.Fprintf(&b, "http_request_duration_seconds_bucket{%s,le=\"+Inf\"} %d\n", labels, stats.count)
+		fmt.Fprintf(&b, "http_request_duration_seconds_sum{%s} %s\n", labels, formatFloat(stats.sum))
+		fmt.Fprintf(&b, "http_request_duration_seconds_count{%s} %d\n", labels, stats.count)
+	}
+
+	b.WriteString("# HELP http_requests_in_flight Number of HTTP requests currently being served.\n")
+	b.WriteString("# TYPE http_requests_in_flight gauge\n")
+	fmt.Fprintf(&b, "http_requests_in_flight %d\n", atomic.LoadInt64(&m.inFlight))
+
+	commands := make([]string, 0, len(m.mongoErrors))
+	for command := range m.mongoErrors {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+
+	b.WriteString("# HELP mongodb_command_errors_total Total number of failed MongoDB commands.\n")
+	b.WriteString("# TYPE mongodb_command_errors_total counter\n")
+	for _, command := range commands {
+		fmt.Fprintf(&b, "mongodb_command_errors_total{command=\"%s\"} %d\n", escapeLabelValue(command), m.mongoErrors[command])
+	}
+
+	names := make([]string, 0, len(m.caches))
+	for name := range m.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	stats := make([]CacheStats, len(names))
+	for i, name := range names {
+		stats[i] = m.caches[name].Stats()
+	}
+
+	b.WriteString("# HELP cache_hits_total Total number of cache lookups that found an entry.\n")
+	b.WriteString("# TYPE cache_hits_total counter\n")
+	for i, name := range names {
+		fmt.Fprintf(&b, "cache_hits_total{cache=\"%s\"} %d\n", escapeLabelValue(name), stats[i].Hits)
+	}
+
+	b.WriteString("# HELP cache_misses_total Total number of cache lookups that found no entry.\n")
+	b.WriteString("# TYPE cache_misses_total counter\n")
+	for i, name := range names {
+		fmt.Fprintf(&b, "cache_misses_total{cache=\"%s\"} %d\n", escapeLabelValue(name), stats[i].Misses)
+	}
+
+	m.renderBusiness(&b)
+
+	return b.String()
+}
+
+func (s requestSeries) labels() string {
+	return fmt.Sprintf("method=\"%s\",route=\"%s\",status=\"%s\"", escapeLabelValue(s.method), escapeLabelValue(s.route), escapeLabelValue(s.status))
+}
+
+// escapeLabelValue escapes a label value as the Prometheus text format requires
+func escapeLabelValue(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
@@ -0,0 +1,206 @@
+package Infrastructure
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+func setupMetricsTestRouter(metrics *Metrics) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(metrics.Instrument())
+	router.GET("/metrics", metrics.Handlers()...)
+	return router
+}
+
+func scrapeMetrics(router *gin.Engine, username, password string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	if username != "" {
+		req.SetBasicAuth(username, password)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMetrics_Instrument(t *testing.T) {
+	t.Run("Success - requests are counted by route template", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+		router := setupMetricsTestRouter(metrics)
+		router.GET("/tasks/:id", func(c *gin.Context) {
+			c.Status(http.StatusNotFound)
+		})
+
+		// Act
+		for _, id := range []string{"a", "b"} {
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/tasks/"+id, nil))
+		}
+		w := scrapeMetrics(router, "", "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		body := w.Body.String()
+		assert.Contains(t, body, `http_requests_total{method="GET",route="/tasks/:id",status="404"} 2`)
+		assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/tasks/:id",status="404",le="+Inf"} 2`)
+		assert.Contains(t, body, `http_request_duration_seconds_count{method="GET",route="/tasks/:id",status="404"} 2`)
+		assert.Contains(t, body, "# TYPE http_request_duration_seconds histogram")
+	})
+
+	t.Run("Success - unknown paths share one series", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+		router := setupMetricsTestRouter(metrics)
+
+		// Act
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/1", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/nope/2", nil))
+		w := scrapeMetrics(router, "", "")
+
+		// Assert
+		assert.Contains(t, w.Body.String(), `http_requests_total{method="GET",route="unmatched",status="404"} 2`)
+	})
+
+	t.Run("Success - unknown methods share one series", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+		router := setupMetricsTestRouter(metrics)
+
+		// Act
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("FOO", "/nope", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("BAR", "/nope", nil))
+		w := scrapeMetrics(router, "", "")
+
+		// Assert
+		body := w.Body.String()
+		assert.Contains(t, body, `http_requests_total{method="OTHER",route="unmatched",status="404"} 2`)
+		assert.NotContains(t, body, `method="FOO"`)
+	})
+
+	t.Run("Success - in-flight requests are reported", func(t *testing.T) {
+		// Arrange
+		metrics := NewMetrics()
+		router := setupMetricsTestRouter(metrics)
+		var inFlight string
+		router.GET("/busy", func(c *gin.Context) {
+			inFlight = scrapeMetrics(router, "", "").Body.String()
+			c.Status(http.StatusNoContent)
+		})
+
+		// Act
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/busy", nil))
+		w := scrapeMetrics(router, "", "")
+
+		// Assert
+		assert.Contains(t, inFlight, "http_requests_in_flight 2")
+		assert.Contains(t, w.Body.String(), "http_requests_in_flight 1")
+	})
+}
+
+func TestMetrics_ObserveRequest(t *testing.T) {
+	// Arrange
+	metrics := NewMetrics()
+	series := requestSeries{route: "/tasks", method: http.MethodGet, status: "200"}
+
+	// Act
+	metrics.observeRequest(series, 30*time.Millisecond)
+	body := metrics.render()
+
+	// Assert
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/tasks",status="200",le="0.025"} 0`)
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/tasks",status="200",le="0.05"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_bucket{method="GET",route="/tasks",status="200",le="10"} 1`)
+	assert.Contains(t, body, `http_request_duration_seconds_sum{method="GET",route="/tasks",status="200"} 0.03`)
+}
+
+func TestMetrics_MongoMonitor(t *testing.T) {
+	// Arrange
+	metrics := NewMetrics()
+	monitor := metrics.MongoMonitor()
+	failed := func(command string) *event.CommandFailedEvent {
+		return &event.CommandFailedEvent{CommandFinishedEvent: event.CommandFinishedEvent{CommandName: command}}
+	}
+
+	// Act
+	monitor.Failed(context.Background(), failed("find"))
+	monitor.Failed(context.Background(), failed("find"))
+	monitor.Failed(context.Background(), failed("update"))
+	body := metrics.render()
+
+	// Assert
+	assert.Contains(t, body, `mongodb_command_errors_total{command="find"} 2`)
+	assert.Contains(t, body, `mongodb_command_errors_total{command="update"} 1`)
+}
+
+func TestMetrics_ObserveCache(t *testing.T) {
+	// Arrange
+	metrics := NewMetrics()
+	cache := NewMemoryCache(10, time.Minute)
+	metrics.ObserveCache("tasks", cache)
+	cache.Set(context.Background(), "key", []byte("value"))
+
+	// Act
+	cache.Get(context.Background(), "key")
+	cache.Get(context.Background(), "key")
+	cache.Get(context.Background(), "other")
+	body := metrics.render()
+
+	// Assert
+	assert.Contains(t, body, `cache_hits_total{cache="tasks"} 2`)
+	assert.Contains(t, body, `cache_misses_total{cache="tasks"} 1`)
+}
+
+func TestMetrics_Handlers(t *testing.T) {
+	t.Run("Success - open when no credentials are configured", func(t *testing.T) {
+		// Arrange
+		t.Setenv("METRICS_USERNAME", "")
+		t.Setenv("METRICS_PASSWORD", "")
+		router := setupMetricsTestRouter(NewMetrics())
+
+		// Act
+		w := scrapeMetrics(router, "", "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+	})
+
+	t.Run("Success - correct credentials", func(t *testing.T) {
+		// Arrange
+		t.Setenv("METRICS_USERNAME", "prometheus")
+		t.Setenv("METRICS_PASSWORD", "secret")
+		router := setupMetricsTestRouter(NewMetrics())
+
+		// Act
+		w := scrapeMetrics(router, "prometheus", "secret")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Error - missing or wrong credentials", func(t *testing.T) {
+		// Arrange
+		t.Setenv("METRICS_USERNAME", "prometheus")
+		t.Setenv("METRICS_PASSWORD", "secret")
+		router := setupMetricsTestRouter(NewMetrics())
+
+		// Act
+		missing := scrapeMetrics(router, "", "")
+		wrong := scrapeMetrics(router, "prometheus", "guess")
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, missing.Code)
+		assert.Equal(t, http.StatusUnauthorized, wrong.Code)
+	})
+}
+
+func TestEscapeLabelValue(t *testing.T) {
+	assert.Equal(t, `a\"b\\c\nd`, escapeLabelValue("a\"b\\c\nd"))
+}
@@ -0,0 +1,188 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/smtp"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"task_manager/Domain"
+)
+
+// ErrNoEmailAddress is returned by notifiers that email users who have no email address
+var ErrNoEmailAddress = errors.New("user has no email address")
+
+// Notifier delivers password reset and email verification tokens and due-date reminders to users
+type Notifier interface {
+	SendPasswordReset(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error
+	SendEmailVerification(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error
+	SendTaskReminder(ctx context.Context, user *Domain.User, task *Domain.Task, overdue bool) error
+}
+
+// NewNotifier returns an SMTPNotifier when SMTP_HOST is set, and a LogNotifier for development otherwise
+func NewNotifier(logger *slog.Logger) Notifier {
+	if os.Getenv("SMTP_HOST") != "" {
+		return NewSMTPNotifier()
+	}
+	logger.Warn("SMTP_HOST is not set, password reset and email verification tokens and task reminders will be written to the log")
+	return NewLogNotifier(logger)
+}
+
+// LogNotifier implements Notifier by logging the tokens. It is meant for development only,
+// since anyone who can read the logs can reset any password.
+type LogNotifier struct {
+	logger *slog.Logger
+}
+
+// NewLogNotifier creates a new instance of LogNotifier
+func NewLogNotifier(logger *slog.Logger) *LogNotifier {
+	return &LogNotifier{logger: logger}
+}
+
+// SendPasswordReset implements Notifier
+func (n *LogNotifier) SendPasswordReset(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error {
+	n.logger.InfoContext(ctx, "password reset requested",
+		"username", user.Username, "reset_token", token, "expires_at", expiresAt)
+	return nil
+}
+
+// SendEmailVerification implements Notifier
+func (n *LogNotifier) SendEmailVerification(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error {
+	n.logger.InfoContext(ctx, "email verification requested",
+		"username", user.Username, "email", user.Email, "verification_token", token, "expires_at", expiresAt)
+	return nil
+}
+
+// SendTaskReminder implements Notifier
+func (n *LogNotifier) SendTaskReminder(ctx context.Context, user *Domain.User, task *Domain.Task, overdue bool) error {
+	n.logger.InfoContext(ctx, "task reminder",
+		"username", user.Username, "task_id", task.ID.Hex(), "title", task.Title, "due_date", task.DueDate, "overdue", overdue)
+	return nil
+}
+
+// SMTPNotifier implements Notifier by emailing the tokens to the user's email address
+type SMTPNotifier struct {
+	addr      string
+	auth      smtp.Auth
+	from      string
+	resetURL  string
+	verifyURL string
+	sendMail  func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier creates a new instance of SMTPNotifier from SMTP_HOST, SMTP_PORT (default 587),
+// SMTP_USERNAME, SMTP_PASSWORD and SMTP_FROM. When PASSWORD_RESET_URL or EMAIL_VERIFICATION_URL
+// is set the email contains that URL with the token in the token query parameter, otherwise the bare token.
+func NewSMTPNotifier() *SMTPNotifier {
+	host := os.Getenv("SMTP_HOST")
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	var auth smtp.Auth
+	if username := os.Getenv("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, os.Getenv("SMTP_PASSWORD"), host)
+	}
+
+	return &SMTPNotifier{
+		addr:      net.JoinHostPort(host, port),
+		auth:      auth,
+		from:      os.Getenv("SMTP_FROM"),
+		resetURL:  os.Getenv("PASSWORD_RESET_URL"),
+		verifyURL: os.Getenv("EMAIL_VERIFICATION_URL"),
+		sendMail:  smtp.SendMail,
+	}
+}
+
+// SendPasswordReset implements Notifier. It fails for users without an email address.
+func (n *SMTPNotifier) SendPasswordReset(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error {
+	if user.Email == "" {
+		return ErrNoEmailAddress
+	}
+
+	instructions := "Your password reset token is:\r\n\r\n" + token
+	if n.resetURL != "" {
+		instructions = "Reset your password here:\r\n\r\n" + n.resetURL + "?token=" + url.QueryEscape(token)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + user.Email,
+		"Subject: Reset your password",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Hello %s,", user.Username),
+		"",
+		instructions,
+		"",
+		fmt.Sprintf("This link expires at %s and can be used once. If you did not ask to reset your password, ignore this email.", expiresAt.UTC().Format(time.RFC1123)),
+		"",
+	}, "\r\n")
+
+	return n.sendMail(n.addr, n.auth, n.from, []string{user.Email}, []byte(msg))
+}
+
+// SendEmailVerification implements Notifier. It fails for users without an email address.
+func (n *SMTPNotifier) SendEmailVerification(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error {
+	if user.Email == "" {
+		return ErrNoEmailAddress
+	}
+
+	instructions := "Your email verification token is:\r\n\r\n" + token
+	if n.verifyURL != "" {
+		instructions = "Confirm your email address here:\r\n\r\n" + n.verifyURL + "?token=" + url.QueryEscape(token)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + user.Email,
+		"Subject: Confirm your email address",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Hello %s,", user.Username),
+		"",
+		instructions,
+		"",
+		fmt.Sprintf("This link expires at %s. If you did not create an account, ignore this email.", expiresAt.UTC().Format(time.RFC1123)),
+		"",
+	}, "\r\n")
+
+	return n.sendMail(n.addr, n.auth, n.from, []string{user.Email}, []byte(msg))
+}
+
+// SendTaskReminder implements Notifier. It fails for users without an email address.
+func (n *SMTPNotifier) SendTaskReminder(ctx context.Context, user *Domain.User, task *Domain.Task, overdue bool) error {
+	if user.Email == "" {
+		return ErrNoEmailAddress
+	}
+
+	subject := "Task due soon: " + task.Title
+	status := fmt.Sprintf("is due at %s.", task.DueDate.UTC().Format(time.RFC1123))
+	if overdue {
+		subject = "Task overdue: " + task.Title
+		status = fmt.Sprintf("was due at %s and is not completed yet.", task.DueDate.UTC().Format(time.RFC1123))
+	}
+
+	msg := strings.Join([]string{
+		"From: " + n.from,
+		"To: " + user.Email,
+		"Subject: " + subject,
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		fmt.Sprintf("Hello %s,", user.Username),
+		"",
+		fmt.Sprintf("The task %q %s", task.Title, status),
+		"",
+		"Task ID: " + task.ID.Hex(),
+		"",
+	}, "\r\n")
+
+	return n.sendMail(n.addr, n.auth, n.from, []string{user.Email}, []byte(msg))
+}
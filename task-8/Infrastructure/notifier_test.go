@@ -0,0 +1,170 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+func TestSMTPNotifier(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newNotifier := func(t *testing.T, resetURL string) (*SMTPNotifier, *[]byte, *[]string) {
+		t.Setenv("SMTP_HOST", "smtp.example.com")
+		t.Setenv("SMTP_PORT", "")
+		t.Setenv("SMTP_FROM", "noreply@example.com")
+		t.Setenv("PASSWORD_RESET_URL", resetURL)
+		notifier := NewSMTPNotifier()
+
+		var sent []byte
+		var recipients []string
+		notifier.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+			assert.Equal(t, "smtp.example.com:587", addr)
+			assert.Equal(t, "noreply@example.com", from)
+			recipients = to
+			sent = msg
+			return nil
+		}
+		return notifier, &sent, &recipients
+	}
+
+	t.Run("Success - reset link is emailed", func(t *testing.T) {
+		// Arrange
+		notifier, sent, recipients := newNotifier(t, "https://app.example.com/reset")
+		user := &Domain.User{Username: "testuser", Email: "user@example.com"}
+
+		// Act
+		err := notifier.SendPasswordReset(context.Background(), user, "abc+/=", expiresAt)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user@example.com"}, *recipients)
+		assert.Contains(t, string(*sent), "To: user@example.com\r\n")
+		assert.Contains(t, string(*sent), "https://app.example.com/reset?token=abc%2B%2F%3D")
+	})
+
+	t.Run("Success - bare token without a reset URL", func(t *testing.T) {
+		// Arrange
+		notifier, sent, _ := newNotifier(t, "")
+		user := &Domain.User{Username: "testuser", Email: "user@example.com"}
+
+		// Act
+		err := notifier.SendPasswordReset(context.Background(), user, "reset-token", expiresAt)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Contains(t, string(*sent), "reset-token")
+	})
+
+	t.Run("Error - user without email", func(t *testing.T) {
+		// Arrange
+		notifier, sent, _ := newNotifier(t, "")
+
+		// Act
+		err := notifier.SendPasswordReset(context.Background(), &Domain.User{Username: "testuser"}, "reset-token", expiresAt)
+
+		// Assert
+		assert.EqualError(t, err, "user has no email address")
+		assert.Nil(t, *sent)
+	})
+
+	t.Run("Error - SMTP failure", func(t *testing.T) {
+		// Arrange
+		notifier, _, _ := newNotifier(t, "")
+		notifier.sendMail = func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error {
+			return errors.New("connection refused")
+		}
+
+		// Act
+		err := notifier.SendPasswordReset(context.Background(), &Domain.User{Username: "testuser", Email: "user@example.com"}, "reset-token", expiresAt)
+
+		// Assert
+		assert.EqualError(t, err, "connection refused")
+	})
+
+	t.Run("Success - verification link is emailed", func(t *testing.T) {
+		// Arrange
+		t.Setenv("EMAIL_VERIFICATION_URL", "https://app.example.com/verify")
+		notifier, sent, recipients := newNotifier(t, "")
+		user := &Domain.User{Username: "testuser", Email: "user@example.com"}
+
+		// Act
+		err := notifier.SendEmailVerification(context.Background(), user, "a.b+c", expiresAt)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user@example.com"}, *recipients)
+		assert.Contains(t, string(*sent), "Subject: Confirm your email address\r\n")
+		assert.Contains(t, string(*sent), "https://app.example.com/verify?token=a.b%2Bc")
+	})
+
+	t.Run("Error - verification for user without email", func(t *testing.T) {
+		// Arrange
+		notifier, sent, _ := newNotifier(t, "")
+
+		// Act
+		err := notifier.SendEmailVerification(context.Background(), &Domain.User{Username: "testuser"}, "verification-token", expiresAt)
+
+		// Assert
+		assert.EqualError(t, err, "user has no email address")
+		assert.Nil(t, *sent)
+	})
+
+	t.Run("Success - due-soon and overdue reminders are emailed", func(t *testing.T) {
+		// Arrange
+		notifier, sent, recipients := newNotifier(t, "")
+		user := &Domain.User{Username: "testuser", Email: "user@example.com"}
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Write report", DueDate: expiresAt}
+
+		// Act
+		err := notifier.SendTaskReminder(context.Background(), user, task, false)
+		dueSoon := string(*sent)
+		require.NoError(t, err)
+		err = notifier.SendTaskReminder(context.Background(), user, task, true)
+		overdue := string(*sent)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"user@example.com"}, *recipients)
+		assert.Contains(t, dueSoon, "Subject: Task due soon: Write report\r\n")
+		assert.Contains(t, dueSoon, "is due at Tue, 01 Jan 2030 12:00:00 UTC.")
+		assert.Contains(t, dueSoon, "Task ID: "+task.ID.Hex())
+		assert.Contains(t, overdue, "Subject: Task overdue: Write report\r\n")
+	})
+
+	t.Run("Error - reminder for user without email", func(t *testing.T) {
+		// Arrange
+		notifier, sent, _ := newNotifier(t, "")
+
+		// Act
+		err := notifier.SendTaskReminder(context.Background(), &Domain.User{Username: "testuser"}, &Domain.Task{Title: "Write report"}, false)
+
+		// Assert
+		assert.ErrorIs(t, err, ErrNoEmailAddress)
+		assert.Nil(t, *sent)
+	})
+}
+
+func TestNewNotifier(t *testing.T) {
+	t.Run("SMTP when a host is configured", func(t *testing.T) {
+		t.Setenv("SMTP_HOST", "smtp.example.com")
+		assert.IsType(t, &SMTPNotifier{}, NewNotifier(NewNopLogger()))
+	})
+
+	t.Run("Log otherwise", func(t *testing.T) {
+		t.Setenv("SMTP_HOST", "")
+		notifier := NewNotifier(NewNopLogger())
+		assert.IsType(t, &LogNotifier{}, notifier)
+		assert.NoError(t, notifier.SendPasswordReset(context.Background(), &Domain.User{Username: "testuser"}, "reset-token", time.Now()))
+		assert.NoError(t, notifier.SendEmailVerification(context.Background(), &Domain.User{Username: "testuser"}, "verification-token", time.Now()))
+		assert.NoError(t, notifier.SendTaskReminder(context.Background(), &Domain.User{Username: "testuser"}, &Domain.Task{Title: "Write report"}, true))
+	})
+}
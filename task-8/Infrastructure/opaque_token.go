@@ -0,0 +1,25 @@
+package Infrastructure
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// GenerateOpaqueToken returns 32 random bytes, URL-safe base64 encoded, for tokens
+// that are looked up in the database rather than verified by signature
+func GenerateOpaqueToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// HashOpaqueToken returns the SHA-256 hash under which an opaque token is stored,
+// so a leaked database does not reveal usable tokens
+func HashOpaqueToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,145 @@
+package Infrastructure
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"task_manager/Domain"
+)
+
+// Password policy defaults, overridable with PASSWORD_MIN_LENGTH and PASSWORD_REQUIRED_CLASSES
+const (
+	DefaultPasswordMinLength       = 8
+	DefaultPasswordRequiredClasses = "letter,digit"
+)
+
+// PasswordPolicyInterface defines the contract for checking new passwords
+type PasswordPolicyInterface interface {
+	Validate(password string) error
+}
+
+// PasswordPolicy checks passwords for a minimum length, required character classes and
+// membership in a list of very common passwords. The zero value accepts any password.
+type PasswordPolicy struct {
+	MinLength     int
+	RequireLetter bool
+	RequireLower  bool
+	RequireUpper  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectCommon  bool
+}
+
+// NewPasswordPolicy creates the password policy from the environment.
+// PASSWORD_MIN_LENGTH is the minimum length in characters and PASSWORD_REQUIRED_CLASSES a
+// comma-separated list of letter, lowercase, uppercase, digit and symbol; set it to "none" to require none.
+// Common passwords are always rejected. It panics on an unknown class, so a typo cannot weaken the policy.
+func NewPasswordPolicy() *PasswordPolicy {
+	policy := &PasswordPolicy{
+		MinLength:    DefaultPasswordMinLength,
+		RejectCommon: true,
+	}
+	if value, err := strconv.Atoi(os.Getenv("PASSWORD_MIN_LENGTH")); err == nil && value > 0 {
+		policy.MinLength = value
+	}
+
+	classes := os.Getenv("PASSWORD_REQUIRED_CLASSES")
+	if classes == "" {
+		classes = DefaultPasswordRequiredClasses
+	}
+	if strings.EqualFold(classes, "none") {
+		return policy
+	}
+	for _, class := range strings.Split(classes, ",") {
+		switch strings.ToLower(strings.TrimSpace(class)) {
+		case Domain.PasswordRuleLetter:
+			policy.RequireLetter = true
+		case Domain.PasswordRuleLowercase:
+			policy.RequireLower = true
+		case Domain.PasswordRuleUppercase:
+			policy.RequireUpper = true
+		case Domain.PasswordRuleDigit:
+			policy.RequireDigit = true
+		case Domain.PasswordRuleSymbol:
+			policy.RequireSymbol = true
+		default:
+			panic(fmt.Sprintf("invalid password policy: unknown character class %q in PASSWORD_REQUIRED_CLASSES", class))
+		}
+	}
+	return policy
+}
+
+// NewPermissivePasswordPolicy returns a policy that accepts any password, for tests
+func NewPermissivePasswordPolicy() *PasswordPolicy {
+	return &PasswordPolicy{}
+}
+
+// Validate returns a *Domain.PasswordPolicyError listing every rule the password fails, or nil
+func (p *PasswordPolicy) Validate(password string) error {
+	var hasLetter, hasLower, hasUpper, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			hasLetter, hasLower = true, true
+		case unicode.IsUpper(r):
+			hasLetter, hasUpper = true, true
+		case unicode.IsLetter(r):
+			hasLetter = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+
+	var violations []Domain.PasswordRuleViolation
+	fail := func(rule, message string) {
+		violations = append(violations, Domain.PasswordRuleViolation{Rule: rule, Message: message})
+	}
+
+	if utf8.RuneCountInString(password) < p.MinLength {
+		fail(Domain.PasswordRuleMinLength, fmt.Sprintf("must be at least %d characters long", p.MinLength))
+	}
+	if p.RequireLetter && !hasLetter {
+		fail(Domain.PasswordRuleLetter, "must contain a letter")
+	}
+	if p.RequireLower && !hasLower {
+		fail(Domain.PasswordRuleLowercase, "must contain a lowercase letter")
+	}
+	if p.RequireUpper && !hasUpper {
+		fail(Domain.PasswordRuleUppercase, "must contain an uppercase letter")
+	}
+	if p.RequireDigit && !hasDigit {
+		fail(Domain.PasswordRuleDigit, "must contain a digit")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		fail(Domain.PasswordRuleSymbol, "must contain a symbol")
+	}
+	if p.RejectCommon && commonPasswords[strings.ToLower(password)] {
+		fail(Domain.PasswordRuleCommon, "must not be a commonly used password")
+	}
+
+	if len(violations) > 0 {
+		return &Domain.PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// commonPasswords holds some of the most used passwords from public breach lists, lowercased
+var commonPasswords = map[string]bool{
+	"123456": true, "123456789": true, "12345678": true, "1234567890": true, "12345": true,
+	"1234567": true, "123123": true, "111111": true, "000000": true, "654321": true,
+	"666666": true, "121212": true, "112233": true, "123321": true, "987654321": true,
+	"password": true, "password1": true, "password12": true, "password123": true, "passw0rd": true,
+	"qwerty": true, "qwerty123": true, "qwertyuiop": true, "1q2w3e4r": true, "1q2w3e4r5t": true,
+	"abc123": true, "abcd1234": true, "a1b2c3d4": true, "iloveyou": true, "admin": true,
+	"admin123": true, "welcome": true, "welcome1": true, "welcome123": true, "letmein": true,
+	"monkey": true, "dragon": true, "football": true, "baseball": true, "sunshine": true,
+	"princess": true, "shadow": true, "master": true, "superman": true, "trustno1": true,
+	"zaq12wsx": true, "asdfghjkl": true, "changeme": true, "secret": true, "login": true,
+	"aaaaaa": true, "aaaaaaaa": true, "qazwsx": true, "starwars": true, "michael": true,
+}
@@ -0,0 +1,94 @@
+package Infrastructure
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+// violatedRules returns the rules listed by a password policy error
+func violatedRules(t *testing.T, err error) []string {
+	var policyErr *Domain.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("expected a password policy error, got %v", err)
+	}
+	rules := make([]string, len(policyErr.Violations))
+	for i, violation := range policyErr.Violations {
+		rules[i] = violation.Rule
+	}
+	return rules
+}
+
+func TestPasswordPolicy_Validate(t *testing.T) {
+	t.Setenv("PASSWORD_MIN_LENGTH", "")
+	t.Setenv("PASSWORD_REQUIRED_CLASSES", "")
+	policy := NewPasswordPolicy()
+
+	t.Run("Success - strong password", func(t *testing.T) {
+		assert.NoError(t, policy.Validate("correct horse 42"))
+	})
+
+	t.Run("Error - every failed rule is listed", func(t *testing.T) {
+		// Act
+		err := policy.Validate("aaaaaa")
+
+		// Assert
+		assert.Equal(t, []string{Domain.PasswordRuleMinLength, Domain.PasswordRuleDigit, Domain.PasswordRuleCommon}, violatedRules(t, err))
+		assert.EqualError(t, err, "password must be at least 8 characters long, must contain a digit, must not be a commonly used password")
+	})
+
+	t.Run("Error - common passwords are rejected regardless of case", func(t *testing.T) {
+		assert.Equal(t, []string{Domain.PasswordRuleCommon}, violatedRules(t, policy.Validate("Password123")))
+	})
+
+	t.Run("Error - length counts characters, not bytes", func(t *testing.T) {
+		assert.Equal(t, []string{Domain.PasswordRuleMinLength}, violatedRules(t, policy.Validate("ñandú12")))
+	})
+}
+
+func TestNewPasswordPolicy(t *testing.T) {
+	t.Run("Configured length and classes", func(t *testing.T) {
+		// Arrange
+		t.Setenv("PASSWORD_MIN_LENGTH", "12")
+		t.Setenv("PASSWORD_REQUIRED_CLASSES", "lowercase, uppercase,digit,symbol")
+
+		// Act
+		policy := NewPasswordPolicy()
+		err := policy.Validate("lowercase only")
+
+		// Assert
+		assert.Equal(t, 12, policy.MinLength)
+		assert.Equal(t, []string{Domain.PasswordRuleUppercase, Domain.PasswordRuleDigit}, violatedRules(t, err))
+		assert.NoError(t, policy.Validate("Upper lower 42!"))
+	})
+
+	t.Run("No required classes", func(t *testing.T) {
+		t.Setenv("PASSWORD_MIN_LENGTH", "")
+		t.Setenv("PASSWORD_REQUIRED_CLASSES", "none")
+		assert.NoError(t, NewPasswordPolicy().Validate("onlyletters"))
+	})
+
+	t.Run("Invalid length falls back to the default", func(t *testing.T) {
+		t.Setenv("PASSWORD_MIN_LENGTH", "-1")
+		t.Setenv("PASSWORD_REQUIRED_CLASSES", "")
+		assert.Equal(t, DefaultPasswordMinLength, NewPasswordPolicy().MinLength)
+	})
+
+	t.Run("Unknown class fails at startup", func(t *testing.T) {
+		t.Setenv("PASSWORD_REQUIRED_CLASSES", "letter,emoji")
+		assert.Panics(t, func() { NewPasswordPolicy() })
+	})
+}
+
+func TestPermissivePasswordPolicy(t *testing.T) {
+	policy := NewPermissivePasswordPolicy()
+	assert.NoError(t, policy.Validate(""))
+	assert.NoError(t, policy.Validate("123456"))
+}
+
+func TestPasswordPolicyImplementations(t *testing.T) {
+	var _ PasswordPolicyInterface = NewPasswordPolicy()
+}
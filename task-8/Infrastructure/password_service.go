@@ -1,6 +1,9 @@
 package Infrastructure
 
 import (
+	"os"
+	"strconv"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -8,19 +11,28 @@ import (
 type PasswordServiceInterface interface {
 	HashPassword(password string) (string, error)
 	ComparePassword(hashedPassword, password string) error
+	NeedsRehash(hashedPassword string) bool
 }
 
 // PasswordService implements password hashing and comparison
-type PasswordService struct{}
+type PasswordService struct {
+	cost int
+}
 
-// NewPasswordService creates a new instance of PasswordService
+// NewPasswordService creates a new instance of PasswordService.
+// BCRYPT_COST sets the bcrypt cost of new hashes; values outside bcrypt's
+// allowed range are ignored in favour of bcrypt.DefaultCost.
 func NewPasswordService() PasswordServiceInterface {
-	return &PasswordService{}
+	cost := bcrypt.DefaultCost
+	if value, err := strconv.Atoi(os.Getenv("BCRYPT_COST")); err == nil && value >= bcrypt.MinCost && value <= bcrypt.MaxCost {
+		cost = value
+	}
+	return &PasswordService{cost: cost}
 }
 
 // HashPassword hashes a plain text password
 func (ps *PasswordService) HashPassword(password string) (string, error) {
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), ps.cost)
 	if err != nil {
 		return "", err
 	}
@@ -30,4 +42,12 @@ func (ps *PasswordService) HashPassword(password string) (string, error) {
 // ComparePassword compares a hashed password with a plain text password
 func (ps *PasswordService) ComparePassword(hashedPassword, password string) error {
 	return bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-}
\ No newline at end of file
+}
+
+// NeedsRehash reports whether a hash was made with a lower cost than the configured one,
+// so it should be replaced the next time the plain text password is known. Unreadable
+// hashes are left alone, since comparing against them fails anyway.
+func (ps *PasswordService) NeedsRehash(hashedPassword string) bool {
+	cost, err := bcrypt.Cost([]byte(hashedPassword))
+	return err == nil && cost < ps.cost
+}
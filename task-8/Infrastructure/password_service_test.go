@@ -1,10 +1,12 @@
 package Infrastructure
 
 import (
+	"strconv"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/suite"
+	"golang.org/x/crypto/bcrypt"
 )
 
 type PasswordServiceTestSuite struct {
@@ -132,7 +134,7 @@ func (suite *PasswordServiceTestSuite) TestHashPasswordConsistency() {
 	assert.NoError(suite.T(), err1)
 	assert.NoError(suite.T(), err2)
 	assert.NotEqual(suite.T(), hash1, hash2) // Hashes should be different due to salt
-	
+
 	// But both should validate correctly
 	assert.NoError(suite.T(), suite.passwordService.ComparePassword(hash1, password))
 	assert.NoError(suite.T(), suite.passwordService.ComparePassword(hash2, password))
@@ -155,6 +157,54 @@ func TestNewPasswordService(t *testing.T) {
 	assert.Implements(t, (*PasswordServiceInterface)(nil), service)
 }
 
+func TestNewPasswordService_Cost(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  int
+	}{
+		{"Unset uses the default", "", bcrypt.DefaultCost},
+		{"Configured cost", "5", 5},
+		{"Minimum cost", strconv.Itoa(bcrypt.MinCost), bcrypt.MinCost},
+		{"Below minimum uses the default", strconv.Itoa(bcrypt.MinCost - 1), bcrypt.DefaultCost},
+		{"Above maximum uses the default", strconv.Itoa(bcrypt.MaxCost + 1), bcrypt.DefaultCost},
+		{"Not a number uses the default", "high", bcrypt.DefaultCost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("BCRYPT_COST", tt.value)
+			service := NewPasswordService().(*PasswordService)
+			assert.Equal(t, tt.want, service.cost)
+		})
+	}
+
+	t.Run("New hashes use the configured cost", func(t *testing.T) {
+		t.Setenv("BCRYPT_COST", "5")
+		hashedPassword, err := NewPasswordService().HashPassword("password123")
+		assert.NoError(t, err)
+
+		cost, err := bcrypt.Cost([]byte(hashedPassword))
+		assert.NoError(t, err)
+		assert.Equal(t, 5, cost)
+	})
+}
+
+func TestPasswordService_NeedsRehash(t *testing.T) {
+	oldService := &PasswordService{cost: bcrypt.MinCost}
+	newService := &PasswordService{cost: bcrypt.MinCost + 1}
+	oldHash, _ := oldService.HashPassword("password123")
+	newHash, _ := newService.HashPassword("password123")
+
+	assert.True(t, newService.NeedsRehash(oldHash), "lower cost")
+	assert.False(t, newService.NeedsRehash(newHash), "same cost")
+	assert.False(t, oldService.NeedsRehash(newHash), "higher cost")
+	assert.False(t, newService.NeedsRehash("not-a-bcrypt-hash"), "unreadable hash")
+
+	// The old hash keeps working until it is replaced
+	assert.NoError(t, newService.ComparePassword(oldHash, "password123"))
+}
+
 func TestPasswordServiceEdgeCases(t *testing.T) {
 	service := NewPasswordService()
 
@@ -164,7 +214,7 @@ func TestPasswordServiceEdgeCases(t *testing.T) {
 		for i := range longPassword {
 			longPassword[i] = 'a'
 		}
-		
+
 		hashedPassword, err := service.HashPassword(string(longPassword))
 		// bcrypt will return an error for passwords > 72 bytes
 		assert.Error(t, err)
@@ -173,33 +223,33 @@ func TestPasswordServiceEdgeCases(t *testing.T) {
 
 	t.Run("Password with null bytes", func(t *testing.T) {
 		passwordWithNull := "password\x00with\x00null"
-		
+
 		hashedPassword, err := service.HashPassword(passwordWithNull)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, hashedPassword)
-		
+
 		err = service.ComparePassword(hashedPassword, passwordWithNull)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Password with only special characters", func(t *testing.T) {
 		specialPassword := "!@#$%^&*()_+-=[]{}|;':\",./<>?"
-		
+
 		hashedPassword, err := service.HashPassword(specialPassword)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, hashedPassword)
-		
+
 		err = service.ComparePassword(hashedPassword, specialPassword)
 		assert.NoError(t, err)
 	})
 
 	t.Run("Password with mixed case and numbers", func(t *testing.T) {
 		mixedPassword := "MyP@ssw0rd123"
-		
+
 		hashedPassword, err := service.HashPassword(mixedPassword)
 		assert.NoError(t, err)
 		assert.NotEmpty(t, hashedPassword)
-		
+
 		err = service.ComparePassword(hashedPassword, mixedPassword)
 		assert.NoError(t, err)
 	})
@@ -207,10 +257,10 @@ func TestPasswordServiceEdgeCases(t *testing.T) {
 	t.Run("Compare with completely different password", func(t *testing.T) {
 		originalPassword := "original123"
 		differentPassword := "different456"
-		
+
 		hashedPassword, err := service.HashPassword(originalPassword)
 		assert.NoError(t, err)
-		
+
 		err = service.ComparePassword(hashedPassword, differentPassword)
 		assert.Error(t, err)
 	})
@@ -219,10 +269,10 @@ func TestPasswordServiceEdgeCases(t *testing.T) {
 		password := "testpassword"
 		hashedPassword, err := service.HashPassword(password)
 		assert.NoError(t, err)
-		
+
 		// Truncate the hash to make it invalid
 		truncatedHash := hashedPassword[:10]
-		
+
 		err = service.ComparePassword(truncatedHash, password)
 		assert.Error(t, err)
 	})
@@ -230,8 +280,8 @@ func TestPasswordServiceEdgeCases(t *testing.T) {
 	t.Run("Compare with malformed hash", func(t *testing.T) {
 		password := "testpassword"
 		malformedHash := "not.a.valid.bcrypt.hash"
-		
+
 		err := service.ComparePassword(malformedHash, password)
 		assert.Error(t, err)
 	})
-}
\ No newline at end of file
+}
@@ -0,0 +1,167 @@
+package Infrastructure
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// RateLimit is a token bucket: RequestsPerMinute tokens are added each minute, up to Burst.
+// A RequestsPerMinute of zero or less disables the limit.
+type RateLimit struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// Default rate limits; the public auth endpoints are kept much tighter than the rest of the API
+var (
+	DefaultAuthRateLimit = RateLimit{RequestsPerMinute: 10, Burst: 5}
+	DefaultAPIRateLimit  = RateLimit{RequestsPerMinute: 600, Burst: 100}
+)
+
+// RateLimitFromEnv reads <prefix>_PER_MINUTE and <prefix>_BURST, keeping the default for unset or invalid values
+func RateLimitFromEnv(prefix string, defaults RateLimit) RateLimit {
+	limit := defaults
+	if value := os.Getenv(prefix + "_PER_MINUTE"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 0 {
+			limit.RequestsPerMinute = parsed
+		}
+	}
+	if value := os.Getenv(prefix + "_BURST"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			limit.Burst = parsed
+		}
+	}
+	return limit
+}
+
+// RateLimitStore holds the token buckets. The in-memory store suits a single instance;
+// a shared store (e.g. Redis) can be swapped in when the API runs on several.
+type RateLimitStore interface {
+	// Allow takes a token from key's bucket. When the bucket is empty it returns false
+	// and how long the caller has to wait for the next token.
+	Allow(key string, limit RateLimit) (bool, time.Duration)
+}
+
+// MemoryRateLimitStore implements RateLimitStore in process memory
+type MemoryRateLimitStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// tokenBucket is the state of one key's bucket. It keeps its own limit because buckets of
+// different limits share the store.
+type tokenBucket struct {
+	tokens    float64
+	updated   time.Time
+	capacity  float64
+	perSecond float64
+}
+
+// NewMemoryRateLimitStore creates a new instance of MemoryRateLimitStore
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow implements RateLimitStore
+func (s *MemoryRateLimitStore) Allow(key string, limit RateLimit) (bool, time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	capacity := float64(limit.Burst)
+	if capacity < 1 {
+		capacity = 1
+	}
+	perSecond := float64(limit.RequestsPerMinute) / 60
+
+	bucket, ok := s.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: capacity, updated: now}
+		s.buckets[key] = bucket
+	}
+	bucket.capacity = capacity
+	bucket.perSecond = perSecond
+	bucket.tokens = math.Min(capacity, bucket.tokens+now.Sub(bucket.updated).Seconds()*perSecond)
+	bucket.updated = now
+
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0
+	}
+
+	wait := time.Duration((1 - bucket.tokens) / perSecond * float64(time.Second))
+	return false, wait
+}
+
+// sweep drops buckets that have refilled completely, at most once a minute, so idle clients do not pile up
+func (s *MemoryRateLimitStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+
+	for key, bucket := range s.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*bucket.perSecond >= bucket.capacity {
+			delete(s.buckets, key)
+		}
+	}
+}
+
+// RateLimitMiddleware limits requests per client IP
+type RateLimitMiddleware struct {
+	store     RateLimitStore
+	clientIPs *ClientIPResolver
+}
+
+// NewRateLimitMiddleware creates a new instance of RateLimitMiddleware, which tells clients apart by
+// the IP clientIPs resolves, so clients behind a trusted proxy get buckets of their own
+func NewRateLimitMiddleware(store RateLimitStore, clientIPs *ClientIPResolver) *RateLimitMiddleware {
+	return &RateLimitMiddleware{
+		store:     store,
+		clientIPs: clientIPs,
+	}
+}
+
+// Limit rejects requests beyond the limit with 429 and a Retry-After header.
+// The name keeps buckets of different limits apart when they share a store.
+func (rl *RateLimitMiddleware) Limit(name string, limit RateLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limit.RequestsPerMinute <= 0 {
+			c.Next()
+			return
+		}
+
+		allowed, wait := rl.store.Allow(name+":"+rl.clientIPs.ClientIP(c.Request), limit)
+		if !allowed {
+			retryAfter := int(math.Ceil(wait.Seconds()))
+			if retryAfter < 1 {
+				retryAfter = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			respondError(c, http.StatusTooManyRequests, Domain.ErrorResponse{
+				Success:   false,
+				MessageID: Domain.MsgTooManyRequests,
+				Error:     fmt.Sprintf("Rate limit exceeded, retry in %d seconds", retryAfter),
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,272 @@
+package Infrastructure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+// fakeClock lets tests move the store's time forward by hand
+type fakeClock struct {
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time { return f.now }
+
+func newTestRateLimitStore() (*MemoryRateLimitStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+	store := NewMemoryRateLimitStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func setupRateLimitTestRouter(store RateLimitStore, limit RateLimit, trustedProxies string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	middleware := NewRateLimitMiddleware(store, &ClientIPResolver{trustedProxies: parseTrustedProxies(trustedProxies)})
+	router.Use(middleware.Limit("test", limit))
+	router.GET("/limited", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	return router
+}
+
+func performRateLimitedRequest(router *gin.Engine, remoteAddr, forwardedFor string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/limited", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitFromEnv(t *testing.T) {
+	defaults := RateLimit{RequestsPerMinute: 60, Burst: 10}
+	tests := []struct {
+		name      string
+		perMinute string
+		burst     string
+		expected  RateLimit
+	}{
+		{name: "Unset uses defaults", expected: defaults},
+		{name: "Values", perMinute: "120", burst: "20", expected: RateLimit{RequestsPerMinute: 120, Burst: 20}},
+		{name: "Zero disables", perMinute: "0", expected: RateLimit{RequestsPerMinute: 0, Burst: 10}},
+		{name: "Invalid uses defaults", perMinute: "lots", burst: "-1", expected: defaults},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("TEST_RATE_LIMIT_PER_MINUTE", tt.perMinute)
+			t.Setenv("TEST_RATE_LIMIT_BURST", tt.burst)
+
+			// Act
+			limit := RateLimitFromEnv("TEST_RATE_LIMIT", defaults)
+
+			// Assert
+			assert.Equal(t, tt.expected, limit)
+		})
+	}
+}
+
+func TestMemoryRateLimitStore_Allow(t *testing.T) {
+	limit := RateLimit{RequestsPerMinute: 60, Burst: 2}
+
+	t.Run("Success - burst is allowed then refused", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+
+		// Act
+		first, _ := store.Allow("client", limit)
+		second, _ := store.Allow("client", limit)
+		third, wait := store.Allow("client", limit)
+
+		// Assert
+		assert.True(t, first)
+		assert.True(t, second)
+		assert.False(t, third)
+		assert.Equal(t, time.Second, wait)
+	})
+
+	t.Run("Success - tokens refill over time", func(t *testing.T) {
+		// Arrange
+		store, clock := newTestRateLimitStore()
+		store.Allow("client", limit)
+		store.Allow("client", limit)
+
+		// Act
+		clock.now = clock.now.Add(500 * time.Millisecond)
+		early, wait := store.Allow("client", limit)
+		clock.now = clock.now.Add(500 * time.Millisecond)
+		later, _ := store.Allow("client", limit)
+
+		// Assert
+		assert.False(t, early)
+		assert.Equal(t, 500*time.Millisecond, wait)
+		assert.True(t, later)
+	})
+
+	t.Run("Success - keys have separate buckets", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		store.Allow("first", limit)
+		store.Allow("first", limit)
+
+		// Act
+		allowed, _ := store.Allow("second", limit)
+
+		// Assert
+		assert.True(t, allowed)
+	})
+
+	t.Run("Success - idle buckets are swept", func(t *testing.T) {
+		// Arrange
+		store, clock := newTestRateLimitStore()
+		store.Allow("idle", limit)
+
+		// Act
+		clock.now = clock.now.Add(2 * time.Minute)
+		store.Allow("active", limit)
+
+		// Assert
+		assert.NotContains(t, store.buckets, "idle")
+		assert.Contains(t, store.buckets, "active")
+	})
+
+	t.Run("Success - sweep judges each bucket by its own limit", func(t *testing.T) {
+		// Arrange: the auth and api limits share one store, as in the router
+		store, clock := newTestRateLimitStore()
+		store.Allow("api:10.0.0.2", DefaultAPIRateLimit)
+		clock.now = clock.now.Add(59 * time.Second)
+		for i := 0; i < DefaultAuthRateLimit.Burst; i++ {
+			store.Allow("auth:10.0.0.1", DefaultAuthRateLimit)
+		}
+
+		// Act: two seconds later an api request triggers the next sweep. At 600/min the drained
+		// auth bucket would look refilled; at its own 10/min it is not.
+		clock.now = clock.now.Add(2 * time.Second)
+		store.Allow("api:10.0.0.2", DefaultAPIRateLimit)
+
+		// Assert
+		assert.Contains(t, store.buckets, "auth:10.0.0.1")
+		allowed := 0
+		for i := 0; i < DefaultAuthRateLimit.Burst; i++ {
+			if ok, _ := store.Allow("auth:10.0.0.1", DefaultAuthRateLimit); ok {
+				allowed++
+			}
+		}
+		assert.Less(t, allowed, DefaultAuthRateLimit.Burst)
+	})
+}
+
+func TestRateLimitMiddleware_Limit(t *testing.T) {
+	limit := RateLimit{RequestsPerMinute: 30, Burst: 1}
+
+	t.Run("Success - request within the limit", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, limit, "")
+
+		// Act
+		w := performRateLimitedRequest(router, "192.0.2.1:1234", "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Retry-After"))
+	})
+
+	t.Run("Error - request over the limit", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, limit, "")
+		performRateLimitedRequest(router, "192.0.2.1:1234", "")
+
+		// Act
+		w := performRateLimitedRequest(router, "192.0.2.1:1234", "")
+
+		// Assert
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+
+		var response Domain.ErrorResponse
+		err := json.Unmarshal(w.Body.Bytes(), &response)
+		assert.NoError(t, err)
+		assert.False(t, response.Success)
+		assert.Equal(t, "Too many requests", response.Message)
+		assert.Equal(t, "Rate limit exceeded, retry in 2 seconds", response.Error)
+	})
+
+	t.Run("Success - other clients are not limited", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, limit, "")
+		performRateLimitedRequest(router, "192.0.2.1:1234", "")
+
+		// Act
+		w := performRateLimitedRequest(router, "192.0.2.2:1234", "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Success - disabled limit lets every request through", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, RateLimit{RequestsPerMinute: 0, Burst: 1}, "")
+		performRateLimitedRequest(router, "192.0.2.1:1234", "")
+
+		// Act
+		w := performRateLimitedRequest(router, "192.0.2.1:1234", "")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Error - X-Forwarded-For from an untrusted peer is ignored", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, limit, "")
+		performRateLimitedRequest(router, "192.0.2.1:1234", "198.51.100.1")
+
+		// Act
+		w := performRateLimitedRequest(router, "192.0.2.1:1234", "198.51.100.2")
+
+		// Assert
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+
+	t.Run("Success - X-Forwarded-For from a trusted proxy identifies the client", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, limit, "10.0.0.0/8")
+		performRateLimitedRequest(router, "10.0.0.1:1234", "198.51.100.1")
+
+		// Act
+		w := performRateLimitedRequest(router, "10.0.0.1:1234", "198.51.100.2")
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("Error - spoofed left-most X-Forwarded-For entry is not trusted", func(t *testing.T) {
+		// Arrange
+		store, _ := newTestRateLimitStore()
+		router := setupRateLimitTestRouter(store, limit, "10.0.0.1")
+		performRateLimitedRequest(router, "10.0.0.1:1234", "203.0.113.1, 198.51.100.1")
+
+		// Act
+		w := performRateLimitedRequest(router, "10.0.0.1:1234", "203.0.113.2, 198.51.100.1")
+
+		// Assert
+		assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	})
+}
@@ -0,0 +1,291 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"task_manager/Domain"
+)
+
+// DefaultAuditMaxBodyBytes is used when AUDIT_MAX_BODY_BYTES is unset or invalid
+const DefaultAuditMaxBodyBytes = 4096
+
+const (
+	// auditQueueSize is how many traced requests may wait to be written before new ones are dropped
+	auditQueueSize = 1000
+	// auditCaptureLimit caps how much of a request body is kept for redaction; larger bodies are not recorded
+	auditCaptureLimit = 1 << 20
+	// auditRedacted replaces the value of every sensitive body field
+	auditRedacted = "[REDACTED]"
+	// auditTruncated marks a body cut short at the size threshold
+	auditTruncated = "...[truncated]"
+)
+
+// auditSensitiveFields are the body field names whose values are never recorded. A field is
+// sensitive when its lowercased name contains one of them, so new_password and refresh_token are too.
+var auditSensitiveFields = []string{"password", "token"}
+
+// AuditRecorder stores audit entries
+type AuditRecorder interface {
+	Create(ctx context.Context, entry *Domain.AuditEntry) error
+}
+
+// RequestAuditMiddleware traces API requests in the audit log: method, path, actor, status,
+// latency and the JSON body with its sensitive fields redacted. Entries are queued and written in
+// the background, so a slow or failing audit store never delays or fails the request.
+type RequestAuditMiddleware struct {
+	recorder     AuditRecorder
+	logger       *slog.Logger
+	enabled      bool
+	maxBodyBytes int
+
+	queue  chan *Domain.AuditEntry
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewRequestAuditMiddleware creates a RequestAuditMiddleware. Requests are only traced with
+// AUDIT_REQUESTS=true; AUDIT_MAX_BODY_BYTES is the longest body recorded before it is truncated.
+func NewRequestAuditMiddleware(recorder AuditRecorder, logger *slog.Logger) *RequestAuditMiddleware {
+	enabled, _ := strconv.ParseBool(os.Getenv("AUDIT_REQUESTS"))
+
+	maxBodyBytes := DefaultAuditMaxBodyBytes
+	if value := os.Getenv("AUDIT_MAX_BODY_BYTES"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			maxBodyBytes = parsed
+		}
+	}
+
+	ra := &RequestAuditMiddleware{
+		recorder:     recorder,
+		logger:       logger,
+		enabled:      enabled,
+		maxBodyBytes: maxBodyBytes,
+	}
+	if enabled {
+		ra.queue = make(chan *Domain.AuditEntry, auditQueueSize)
+		ra.wg.Add(1)
+		go ra.work()
+	}
+	return ra
+}
+
+// Record traces every request of the routes it is applied to. It does nothing unless auditing is enabled.
+func (ra *RequestAuditMiddleware) Record() gin.HandlerFunc {
+	return ra.record(false)
+}
+
+// RecordWrites works like Record but leaves out GET, HEAD and OPTIONS requests
+func (ra *RequestAuditMiddleware) RecordWrites() gin.HandlerFunc {
+	return ra.record(true)
+}
+
+// record builds the middleware, which captures the body as the handler reads it and queues the
+// entry once the response has been written
+func (ra *RequestAuditMiddleware) record(writesOnly bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !ra.enabled || (writesOnly && isReadOnlyMethod(c.Request.Method)) {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		var captured *capturedBody
+		hasBody := c.Request.Body != nil && c.Request.Body != http.NoBody
+		if hasBody && isJSONContentType(c.ContentType()) {
+			captured = &capturedBody{ReadCloser: c.Request.Body}
+			c.Request.Body = captured
+		}
+
+		c.Next()
+
+		request := &Domain.AuditRequest{
+			Method:    c.Request.Method,
+			Path:      path,
+			Status:    c.Writer.Status(),
+			LatencyMs: time.Since(start).Milliseconds(),
+			RequestID: c.GetString(RequestIDKey),
+		}
+		switch {
+		case captured != nil:
+			request.Body = ra.redactBody(captured)
+		case hasBody:
+			request.Body = "[non-JSON body omitted]"
+		}
+
+		ra.enqueue(c.Request.Context(), &Domain.AuditEntry{
+			ActorID:  c.GetString("user_id"),
+			Action:   Domain.AuditActionRequest,
+			Entity:   Domain.AuditEntityAPI,
+			EntityID: path,
+			Request:  request,
+		})
+	}
+}
+
+// Close stops accepting entries and waits for the queued ones to be written, giving up when ctx is done
+func (ra *RequestAuditMiddleware) Close(ctx context.Context) error {
+	if !ra.enabled {
+		return nil
+	}
+
+	ra.mu.Lock()
+	if !ra.closed {
+		ra.closed = true
+		close(ra.queue)
+	}
+	ra.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		ra.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// enqueue hands an entry to the writer, dropping it with a warning when the queue is full or closed
+func (ra *RequestAuditMiddleware) enqueue(ctx context.Context, entry *Domain.AuditEntry) {
+	ra.mu.RLock()
+	defer ra.mu.RUnlock()
+
+	if ra.closed {
+		ra.logger.WarnContext(ctx, "request audit is closed, dropping entry", "method", entry.Request.Method, "path", entry.Request.Path)
+		return
+	}
+
+	select {
+	case ra.queue <- entry:
+	default:
+		ra.logger.WarnContext(ctx, "request audit queue is full, dropping entry", "method", entry.Request.Method, "path", entry.Request.Path)
+	}
+}
+
+// work writes queued entries until the queue is closed
+func (ra *RequestAuditMiddleware) work() {
+	defer ra.wg.Done()
+	for entry := range ra.queue {
+		if err := ra.recorder.Create(context.Background(), entry); err != nil {
+			ra.logger.Warn("failed to write request audit entry", "method", entry.Request.Method, "path", entry.Request.Path, "request_id", entry.Request.RequestID, "error", err)
+		}
+	}
+}
+
+// redactBody returns the part of a JSON body the handler read, with sensitive fields redacted and
+// truncated past the size threshold. Bodies that cannot be redacted are left out.
+func (ra *RequestAuditMiddleware) redactBody(body *capturedBody) string {
+	if body.overflow {
+		return "[body over " + strconv.Itoa(auditCaptureLimit) + " bytes omitted]"
+	}
+	if body.buf.Len() == 0 {
+		return ""
+	}
+
+	decoder := json.NewDecoder(&body.buf)
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return "[invalid JSON body omitted]"
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return "[invalid JSON body omitted]"
+	}
+	return truncateBody(string(redacted), ra.maxBodyBytes)
+}
+
+// redactValue replaces the values of sensitive fields at any depth of a decoded JSON value
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isSensitiveField(key) {
+				v[key] = auditRedacted
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
+
+// isSensitiveField checks if a body field holds a secret
+func isSensitiveField(name string) bool {
+	name = strings.ToLower(name)
+	for _, sensitive := range auditSensitiveFields {
+		if strings.Contains(name, sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// truncateBody cuts a body to at most maxBytes without splitting a character and marks that it was cut
+func truncateBody(body string, maxBytes int) string {
+	if len(body) <= maxBytes {
+		return body
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(body[cut]) {
+		cut--
+	}
+	return body[:cut] + auditTruncated
+}
+
+// isJSONContentType checks if a body of this media type is read as JSON. Handlers bind JSON whatever
+// the Content-Type says, so a body without one is treated as JSON too.
+func isJSONContentType(mediaType string) bool {
+	return mediaType == "" || mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// isReadOnlyMethod checks if a request method does not change anything
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// capturedBody keeps a copy of what is read from a request body, up to auditCaptureLimit bytes
+type capturedBody struct {
+	io.ReadCloser
+
+	buf      bytes.Buffer
+	overflow bool
+}
+
+func (b *capturedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if !b.overflow && n > 0 {
+		if b.buf.Len()+n > auditCaptureLimit {
+			b.overflow = true
+			b.buf.Reset()
+		} else {
+			b.buf.Write(p[:n])
+		}
+	}
+	return n, err
+}
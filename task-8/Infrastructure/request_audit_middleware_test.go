@@ -0,0 +1,204 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task_manager/Domain"
+)
+
+// fakeAuditStore keeps the audit entries written, or fails every write with err
+type fakeAuditStore struct {
+	mu      sync.Mutex
+	err     error
+	entries []*Domain.AuditEntry
+}
+
+func (s *fakeAuditStore) Create(ctx context.Context, entry *Domain.AuditEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.entries = append(s.entries, entry)
+	return nil
+}
+
+// setupAuditRouter serves POST and GET /users with the middleware built by record, acting as user-1
+func setupAuditRouter(record gin.HandlerFunc) (*gin.Engine, *string) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewRequestIDMiddleware().AssignRequestID(), record, func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+	})
+
+	var received string
+	router.POST("/users", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		received, _ = body["password"].(string)
+		c.Status(http.StatusCreated)
+	})
+	router.GET("/users", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router, &received
+}
+
+// closeRequestAudit waits for every queued entry to be written
+func closeRequestAudit(t *testing.T, audit *RequestAuditMiddleware) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, audit.Close(ctx))
+}
+
+func TestRequestAuditMiddleware(t *testing.T) {
+	t.Run("Success - request is traced with sensitive fields redacted", func(t *testing.T) {
+		// Arrange
+		t.Setenv("AUDIT_REQUESTS", "true")
+		store := &fakeAuditStore{}
+		audit := NewRequestAuditMiddleware(store, NewNopLogger())
+		router, received := setupAuditRouter(audit.Record())
+
+		body := `{"username":"alice","password":"s3cret","credentials":{"refresh_token":"abc","scopes":["read"]},"quota":12345678901234567890}`
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+		req.Header.Set(Domain.RequestIDHeader, "req-42")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+		closeRequestAudit(t, audit)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "s3cret", *received)
+
+		require.Len(t, store.entries, 1)
+		entry := store.entries[0]
+		assert.Equal(t, "user-1", entry.ActorID)
+		assert.Equal(t, Domain.AuditActionRequest, entry.Action)
+		assert.Equal(t, Domain.AuditEntityAPI, entry.Entity)
+		assert.Equal(t, "/users", entry.EntityID)
+		require.NotNil(t, entry.Request)
+		assert.Equal(t, http.MethodPost, entry.Request.Method)
+		assert.Equal(t, "/users", entry.Request.Path)
+		assert.Equal(t, http.StatusCreated, entry.Request.Status)
+		assert.GreaterOrEqual(t, entry.Request.LatencyMs, int64(0))
+		assert.Equal(t, "req-42", entry.Request.RequestID)
+		assert.JSONEq(t, `{"username":"alice","password":"[REDACTED]","credentials":{"refresh_token":"[REDACTED]","scopes":["read"]},"quota":12345678901234567890}`, entry.Request.Body)
+		assert.NotContains(t, entry.Request.Body, "s3cret")
+	})
+
+	t.Run("Success - bodies over the threshold are truncated with a marker", func(t *testing.T) {
+		// Arrange
+		t.Setenv("AUDIT_REQUESTS", "true")
+		t.Setenv("AUDIT_MAX_BODY_BYTES", "32")
+		store := &fakeAuditStore{}
+		audit := NewRequestAuditMiddleware(store, NewNopLogger())
+		router, _ := setupAuditRouter(audit.Record())
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"description":"`+strings.Repeat("a", 100)+`"}`))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+		closeRequestAudit(t, audit)
+
+		// Assert
+		require.Len(t, store.entries, 1)
+		assert.Equal(t, `{"description":"`+strings.Repeat("a", 16)+auditTruncated, store.entries[0].Request.Body)
+	})
+
+	t.Run("Success - non-JSON bodies are left out", func(t *testing.T) {
+		// Arrange
+		t.Setenv("AUDIT_REQUESTS", "true")
+		store := &fakeAuditStore{}
+		audit := NewRequestAuditMiddleware(store, NewNopLogger())
+		router, _ := setupAuditRouter(audit.Record())
+
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("password=s3cret"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+		closeRequestAudit(t, audit)
+
+		// Assert
+		require.Len(t, store.entries, 1)
+		assert.Equal(t, "[non-JSON body omitted]", store.entries[0].Request.Body)
+	})
+
+	t.Run("Success - RecordWrites leaves out reads", func(t *testing.T) {
+		// Arrange
+		t.Setenv("AUDIT_REQUESTS", "true")
+		store := &fakeAuditStore{}
+		audit := NewRequestAuditMiddleware(store, NewNopLogger())
+		router, _ := setupAuditRouter(audit.RecordWrites())
+
+		// Act
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("{}")))
+		closeRequestAudit(t, audit)
+
+		// Assert
+		require.Len(t, store.entries, 1)
+		assert.Equal(t, http.MethodPost, store.entries[0].Request.Method)
+	})
+
+	t.Run("Success - nothing is traced unless enabled", func(t *testing.T) {
+		// Arrange
+		t.Setenv("AUDIT_REQUESTS", "")
+		store := &fakeAuditStore{}
+		audit := NewRequestAuditMiddleware(store, NewNopLogger())
+		router, _ := setupAuditRouter(audit.Record())
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		closeRequestAudit(t, audit)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, store.entries)
+	})
+
+	t.Run("Error - a failing audit store does not fail the request", func(t *testing.T) {
+		// Arrange
+		t.Setenv("AUDIT_REQUESTS", "true")
+		store := &fakeAuditStore{err: errors.New("database unavailable")}
+		audit := NewRequestAuditMiddleware(store, NewNopLogger())
+		router, _ := setupAuditRouter(audit.Record())
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+		closeRequestAudit(t, audit)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, store.entries)
+	})
+}
+
+func TestTruncateBody(t *testing.T) {
+	assert.Equal(t, "short", truncateBody("short", 5))
+	assert.Equal(t, "sho"+auditTruncated, truncateBody("short", 3))
+	// A character is never split
+	assert.Equal(t, "a"+auditTruncated, truncateBody("añb", 2))
+}
@@ -0,0 +1,80 @@
+package Infrastructure
+
+import (
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// RequestIDKey is the gin context key holding the request ID
+const RequestIDKey = "request_id"
+
+// maxRequestIDLength bounds client-supplied request IDs so they stay safe to log
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware tags every request with an ID for correlating logs and responses
+type RequestIDMiddleware struct{}
+
+// NewRequestIDMiddleware creates a new instance of RequestIDMiddleware
+func NewRequestIDMiddleware() *RequestIDMiddleware {
+	return &RequestIDMiddleware{}
+}
+
+// AssignRequestID reuses the client's X-Request-ID when it is a plausible ID and generates a
+// UUID otherwise. The ID is stored in the gin context and the request context and echoed back
+// in the X-Request-ID response header.
+func (rm *RequestIDMiddleware) AssignRequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(Domain.RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+
+		c.Set(RequestIDKey, requestID)
+		c.Request = c.Request.WithContext(Domain.WithRequestID(c.Request.Context(), requestID))
+		c.Header(Domain.RequestIDHeader, requestID)
+
+		c.Next()
+	}
+}
+
+// respondError writes an error response tagged with the request's ID, resolving its MessageID
+// in the request's language
+func respondError(c *gin.Context, status int, response Domain.ErrorResponse) {
+	if response.MessageID != "" {
+		response.Message = Domain.LocalizeMessage(c.Request.Context(), response.MessageID).Text
+	}
+	response.RequestID = Domain.RequestIDFromContext(c.Request.Context())
+	c.JSON(status, response)
+}
+
+// isValidRequestID accepts short IDs made of letters, digits and - _ . : only
+func isValidRequestID(requestID string) bool {
+	if requestID == "" || len(requestID) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range requestID {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-' || r == '_' || r == '.' || r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random (version 4) UUID
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		// crypto/rand does not fail on supported platforms; fall back to something unique enough
+		return fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,118 @@
+package Infrastructure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func setupRequestIDTestRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(NewRequestIDMiddleware().AssignRequestID())
+	return router
+}
+
+func TestRequestIDMiddleware_AssignRequestID(t *testing.T) {
+	t.Run("Success - provided request ID is preserved", func(t *testing.T) {
+		// Arrange
+		router := setupRequestIDTestRouter()
+		var ginRequestID, contextRequestID string
+		router.GET("/test", func(c *gin.Context) {
+			ginRequestID = c.GetString(RequestIDKey)
+			contextRequestID = Domain.RequestIDFromContext(c.Request.Context())
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.Header.Set(Domain.RequestIDHeader, "client-id-42")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, "client-id-42", w.Header().Get(Domain.RequestIDHeader))
+		assert.Equal(t, "client-id-42", ginRequestID)
+		assert.Equal(t, "client-id-42", contextRequestID)
+	})
+
+	t.Run("Success - missing request ID is generated", func(t *testing.T) {
+		// Arrange
+		router := setupRequestIDTestRouter()
+		var contextRequestID string
+		router.GET("/test", func(c *gin.Context) {
+			contextRequestID = Domain.RequestIDFromContext(c.Request.Context())
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		requestID := w.Header().Get(Domain.RequestIDHeader)
+		assert.Regexp(t, uuidPattern, requestID)
+		assert.Equal(t, requestID, contextRequestID)
+	})
+
+	t.Run("Success - unsafe request ID is replaced", func(t *testing.T) {
+		tests := []struct {
+			name  string
+			value string
+		}{
+			{name: "Too long", value: strings.Repeat("a", maxRequestIDLength+1)},
+			{name: "Unsafe characters", value: "abc\" injected=1"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				router := setupRequestIDTestRouter()
+				router.GET("/test", func(c *gin.Context) {
+					c.Status(http.StatusNoContent)
+				})
+				req := httptest.NewRequest(http.MethodGet, "/test", nil)
+				req.Header.Set(Domain.RequestIDHeader, tt.value)
+				w := httptest.NewRecorder()
+
+				// Act
+				router.ServeHTTP(w, req)
+
+				// Assert
+				assert.Regexp(t, uuidPattern, w.Header().Get(Domain.RequestIDHeader))
+			})
+		}
+	})
+
+	t.Run("Error - middleware errors carry the request ID", func(t *testing.T) {
+		// Arrange
+		router := setupRequestIDTestRouter()
+		authMiddleware := NewAuthMiddleware(new(MockJWTServiceForAuth), NewMemoryTokenBlacklist(), allUsersActive(), noAPIKeys())
+		router.GET("/protected", authMiddleware.AuthenticateToken(), func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set(Domain.RequestIDHeader, "client-id-42")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "client-id-42", response.RequestID)
+	})
+}
@@ -0,0 +1,49 @@
+package Infrastructure
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLoggerMiddleware writes one log line per request
+type RequestLoggerMiddleware struct {
+	logger    *slog.Logger
+	clientIPs *ClientIPResolver
+}
+
+// NewRequestLoggerMiddleware creates a new instance of RequestLoggerMiddleware, which logs the client IP
+// clientIPs resolves
+func NewRequestLoggerMiddleware(logger *slog.Logger, clientIPs *ClientIPResolver) *RequestLoggerMiddleware {
+	return &RequestLoggerMiddleware{
+		logger:    logger,
+		clientIPs: clientIPs,
+	}
+}
+
+// LogRequest logs the method, path, status and latency of each request once it has been served.
+// It must run after AssignRequestID so the line carries the request ID.
+func (rl *RequestLoggerMiddleware) LogRequest() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		path := c.Request.URL.Path
+
+		c.Next()
+
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		if status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+
+		rl.logger.LogAttrs(c.Request.Context(), level, "request",
+			slog.String("method", c.Request.Method),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("client_ip", rl.clientIPs.ClientIP(c.Request)),
+		)
+	}
+}
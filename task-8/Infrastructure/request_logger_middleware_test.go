@@ -0,0 +1,70 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func TestRequestLoggerMiddleware_LogRequest(t *testing.T) {
+	t.Run("Success - request is logged with its request ID", func(t *testing.T) {
+		// Arrange
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_LEVEL", "")
+		var buf bytes.Buffer
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(NewRequestIDMiddleware().AssignRequestID(), NewRequestLoggerMiddleware(newLogger(&buf), &ClientIPResolver{}).LogRequest())
+		router.GET("/tasks", func(c *gin.Context) {
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/tasks", nil)
+		req.Header.Set(Domain.RequestIDHeader, "client-id-42")
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, "request", record["msg"])
+		assert.Equal(t, slog.LevelInfo.String(), record["level"])
+		assert.Equal(t, http.MethodGet, record["method"])
+		assert.Equal(t, "/tasks", record["path"])
+		assert.Equal(t, float64(http.StatusNoContent), record["status"])
+		assert.Contains(t, record, "latency")
+		assert.Equal(t, "client-id-42", record["request_id"])
+	})
+
+	t.Run("Success - server errors are logged at error level", func(t *testing.T) {
+		// Arrange
+		t.Setenv("LOG_FORMAT", "json")
+		t.Setenv("LOG_LEVEL", "")
+		var buf bytes.Buffer
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use(NewRequestLoggerMiddleware(newLogger(&buf), &ClientIPResolver{}).LogRequest())
+		router.GET("/broken", func(c *gin.Context) {
+			c.Status(http.StatusInternalServerError)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/broken", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		var record map[string]interface{}
+		assert.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+		assert.Equal(t, slog.LevelError.String(), record["level"])
+	})
+}
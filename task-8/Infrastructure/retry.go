@@ -0,0 +1,149 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math/rand"
+	"os"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// DefaultRetryMaxAttempts is used when DB_RETRY_MAX_ATTEMPTS is unset or invalid
+const DefaultRetryMaxAttempts = 3
+
+const (
+	// retryBaseDelay is the wait before the second attempt; it doubles with every attempt after that
+	retryBaseDelay = 50 * time.Millisecond
+	// retryMaxDelay caps the wait between two attempts
+	retryMaxDelay = time.Second
+)
+
+// retryableWriteLabel is the label the driver puts on write errors that are safe to send again
+const retryableWriteLabel = "RetryableWriteError"
+
+// Retrier runs database operations again when they fail with a transient error, waiting with
+// exponential backoff and jitter between attempts. The caller's context bounds the whole run, so
+// retries never outlast the operation's timeout. Operations inside a transaction are not retried;
+// the transaction is retried as a whole instead.
+type Retrier struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	logger      *slog.Logger
+	sleep       func(ctx context.Context, d time.Duration) error
+	jitter      func(d time.Duration) time.Duration
+}
+
+// NewRetrier creates a new instance of Retrier.
+// DB_RETRY_MAX_ATTEMPTS is the number of attempts per operation (default 3); 1 disables retries.
+func NewRetrier(logger *slog.Logger) *Retrier {
+	maxAttempts := DefaultRetryMaxAttempts
+	if value := os.Getenv("DB_RETRY_MAX_ATTEMPTS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 1 {
+			maxAttempts = parsed
+		}
+	}
+
+	return &Retrier{
+		maxAttempts: maxAttempts,
+		baseDelay:   retryBaseDelay,
+		maxDelay:    retryMaxDelay,
+		logger:      logger,
+		sleep:       sleepContext,
+		jitter:      halfJitter,
+	}
+}
+
+// Read runs a read operation, retrying it on network errors and timeouts
+func (r *Retrier) Read(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	return r.do(ctx, operation, fn, IsTransientError)
+}
+
+// Write runs a write operation, retrying it only when the driver labels the error as a retryable
+// write. Only writes that have the same effect when applied twice should use it.
+func (r *Retrier) Write(ctx context.Context, operation string, fn func(ctx context.Context) error) error {
+	return r.do(ctx, operation, fn, IsRetryableWriteError)
+}
+
+func (r *Retrier) do(ctx context.Context, operation string, fn func(ctx context.Context) error, retryable func(err error) bool) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn(ctx)
+		if err == nil {
+			if attempt > 1 {
+				r.logger.InfoContext(ctx, "database operation succeeded after retrying", "operation", operation, "attempts", attempt)
+			}
+			return nil
+		}
+
+		if !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+		if attempt >= r.maxAttempts {
+			if r.maxAttempts > 1 {
+				r.logger.ErrorContext(ctx, "database operation failed after retrying", "operation", operation, "attempts", attempt, "error", err)
+			}
+			return err
+		}
+
+		delay := r.jitter(r.backoff(attempt))
+		r.logger.WarnContext(ctx, "retrying database operation", "operation", operation, "attempt", attempt, "delay", delay, "error", err)
+		if r.sleep(ctx, delay) != nil {
+			return err
+		}
+	}
+}
+
+// backoff returns the delay after the given failed attempt before jitter is applied
+func (r *Retrier) backoff(attempt int) time.Duration {
+	delay := r.baseDelay
+	for i := 1; i < attempt && delay < r.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > r.maxDelay {
+		delay = r.maxDelay
+	}
+	return delay
+}
+
+// IsTransientError reports whether a failed read is worth sending again: network errors,
+// timeouts and errors the server labels as retryable
+func IsTransientError(err error) bool {
+	if mongo.IsNetworkError(err) || mongo.IsTimeout(err) {
+		return true
+	}
+	return IsRetryableWriteError(err)
+}
+
+// IsRetryableWriteError reports whether the driver labels an error as a retryable write
+func IsRetryableWriteError(err error) bool {
+	var serverErr mongo.ServerError
+	return errors.As(err, &serverErr) && serverErr.HasErrorLabel(retryableWriteLabel)
+}
+
+// halfJitter picks a random delay between half the given delay and all of it, so clients that
+// failed together do not retry together
+func halfJitter(d time.Duration) time.Duration {
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(d-half)+1))
+}
+
+// sleepContext waits for d or until ctx is done, whichever comes first
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,193 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"task_manager/Domain"
+)
+
+var (
+	errNetwork        = mongo.CommandError{Code: 6, Message: "connection reset", Labels: []string{"NetworkError"}}
+	errRetryableWrite = mongo.CommandError{Code: 91, Message: "shutdown in progress", Labels: []string{retryableWriteLabel}}
+	errDuplicateKey   = mongo.CommandError{Code: 11000, Message: "duplicate key"}
+)
+
+// sleepRecorder stands in for the clock, recording each wait instead of sleeping
+type sleepRecorder struct {
+	delays []time.Duration
+	err    error
+}
+
+func (s *sleepRecorder) sleep(ctx context.Context, d time.Duration) error {
+	s.delays = append(s.delays, d)
+	return s.err
+}
+
+// errorSequence returns the given errors one call at a time, then nil
+type errorSequence struct {
+	errs  []error
+	calls int
+}
+
+func (s *errorSequence) next(ctx context.Context) error {
+	s.calls++
+	if s.calls > len(s.errs) {
+		return nil
+	}
+	return s.errs[s.calls-1]
+}
+
+func newTestRetrier(maxAttempts int) (*Retrier, *sleepRecorder) {
+	clock := &sleepRecorder{}
+	retrier := &Retrier{
+		maxAttempts: maxAttempts,
+		baseDelay:   50 * time.Millisecond,
+		maxDelay:    120 * time.Millisecond,
+		logger:      NewNopLogger(),
+		sleep:       clock.sleep,
+		jitter:      func(d time.Duration) time.Duration { return d },
+	}
+	return retrier, clock
+}
+
+func TestRetrier_Read(t *testing.T) {
+	t.Run("Success - transient errors are retried with exponential backoff", func(t *testing.T) {
+		// Arrange
+		retrier, clock := newTestRetrier(4)
+		sequence := &errorSequence{errs: []error{errNetwork, errNetwork, errNetwork}}
+
+		// Act
+		err := retrier.Read(context.Background(), "tasks.get_by_id", sequence.next)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 4, sequence.calls)
+		assert.Equal(t, []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 120 * time.Millisecond}, clock.delays)
+	})
+
+	t.Run("Error - gives up after the last attempt", func(t *testing.T) {
+		// Arrange
+		retrier, clock := newTestRetrier(3)
+		sequence := &errorSequence{errs: []error{errNetwork, errNetwork, errNetwork, errNetwork}}
+
+		// Act
+		err := retrier.Read(context.Background(), "tasks.get_by_id", sequence.next)
+
+		// Assert
+		assert.Equal(t, errNetwork, err)
+		assert.Equal(t, 3, sequence.calls)
+		assert.Len(t, clock.delays, 2)
+	})
+
+	t.Run("Error - permanent errors are not retried", func(t *testing.T) {
+		// Arrange
+		retrier, clock := newTestRetrier(3)
+		sequence := &errorSequence{errs: []error{mongo.ErrNoDocuments}}
+
+		// Act
+		err := retrier.Read(context.Background(), "users.get_by_id", sequence.next)
+
+		// Assert
+		assert.Equal(t, mongo.ErrNoDocuments, err)
+		assert.Equal(t, 1, sequence.calls)
+		assert.Empty(t, clock.delays)
+	})
+
+	t.Run("Error - stops when the context is done during the wait", func(t *testing.T) {
+		// Arrange
+		retrier, clock := newTestRetrier(3)
+		clock.err = context.DeadlineExceeded
+		sequence := &errorSequence{errs: []error{errNetwork}}
+
+		// Act
+		err := retrier.Read(context.Background(), "users.get_by_id", sequence.next)
+
+		// Assert
+		assert.Equal(t, errNetwork, err)
+		assert.Equal(t, 1, sequence.calls)
+	})
+
+	t.Run("Error - stops when the context has expired", func(t *testing.T) {
+		// Arrange
+		retrier, clock := newTestRetrier(3)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		sequence := &errorSequence{errs: []error{context.Canceled}}
+
+		// Act
+		err := retrier.Read(ctx, "users.get_by_id", sequence.next)
+
+		// Assert
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, sequence.calls)
+		assert.Empty(t, clock.delays)
+	})
+}
+
+func TestRetrier_Write(t *testing.T) {
+	testCases := []struct {
+		name          string
+		err           error
+		expectedCalls int
+	}{
+		{"labelled retryable write is retried", errRetryableWrite, 2},
+		{"unlabelled network error is not retried", errNetwork, 1},
+		{"duplicate key is not retried", errDuplicateKey, 1},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			retrier, _ := newTestRetrier(3)
+			sequence := &errorSequence{errs: []error{tc.err}}
+
+			// Act
+			retrier.Write(context.Background(), "users.set_active", sequence.next)
+
+			// Assert
+			assert.Equal(t, tc.expectedCalls, sequence.calls)
+		})
+	}
+}
+
+func TestRetrier_LogsWithRequestID(t *testing.T) {
+	// Arrange
+	t.Setenv("LOG_LEVEL", "debug")
+	t.Setenv("LOG_FORMAT", "")
+	var output bytes.Buffer
+	retrier, _ := newTestRetrier(2)
+	retrier.logger = newLogger(&output)
+	ctx := Domain.WithRequestID(context.Background(), "req-42")
+	sequence := &errorSequence{errs: []error{errNetwork}}
+
+	// Act
+	err := retrier.Read(ctx, "tasks.get_all", sequence.next)
+
+	// Assert
+	assert.NoError(t, err)
+	logged := output.String()
+	assert.Contains(t, logged, `msg="retrying database operation" operation=tasks.get_all attempt=1`)
+	assert.Contains(t, logged, `msg="database operation succeeded after retrying" operation=tasks.get_all attempts=2`)
+	assert.Contains(t, logged, "request_id=req-42")
+}
+
+func TestHalfJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		delay := halfJitter(100 * time.Millisecond)
+		assert.GreaterOrEqual(t, delay, 50*time.Millisecond)
+		assert.LessOrEqual(t, delay, 100*time.Millisecond)
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	assert.True(t, IsTransientError(errNetwork))
+	assert.True(t, IsTransientError(errRetryableWrite))
+	assert.True(t, IsTransientError(context.DeadlineExceeded))
+	assert.False(t, IsTransientError(errDuplicateKey))
+	assert.False(t, IsTransientError(Domain.ErrTaskNotFound))
+}
@@ -0,0 +1,152 @@
+package Infrastructure
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+// Locker hands out named leases, so only one of several instances runs a background job at a time.
+// The MongoDB implementation lives in Repositories.
+type Locker interface {
+	// Acquire takes the lease for holder, or renews it when holder already has it, until ttl from now.
+	// It reports false when another holder has a lease that has not expired.
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	// Release gives up the lease if holder has it
+	Release(ctx context.Context, name, holder string) error
+}
+
+// MemoryLocker implements Locker in process memory. Leases are not shared between instances,
+// so it suits tests and single-instance setups.
+type MemoryLocker struct {
+	mu     sync.Mutex
+	leases map[string]memoryLease
+	now    func() time.Time
+}
+
+// memoryLease is who holds a lease and until when
+type memoryLease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// NewMemoryLocker creates a new instance of MemoryLocker
+func NewMemoryLocker() *MemoryLocker {
+	return &MemoryLocker{
+		leases: make(map[string]memoryLease),
+		now:    time.Now,
+	}
+}
+
+// Acquire implements Locker
+func (l *MemoryLocker) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if lease, ok := l.leases[name]; ok && lease.holder != holder && lease.expiresAt.After(now) {
+		return false, nil
+	}
+	l.leases[name] = memoryLease{holder: holder, expiresAt: now.Add(ttl)}
+	return true, nil
+}
+
+// Release implements Locker
+func (l *MemoryLocker) Release(ctx context.Context, name, holder string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if lease, ok := l.leases[name]; ok && lease.holder == holder {
+		delete(l.leases, name)
+	}
+	return nil
+}
+
+// Scheduler runs a job at a fixed interval on whichever instance holds the job's lease.
+// The lease outlives two intervals, so when the instance holding it stops without releasing it
+// another one takes over within two ticks.
+type Scheduler struct {
+	name     string
+	interval time.Duration
+	locker   Locker
+	holder   string
+	job      func(ctx context.Context) error
+	logger   *slog.Logger
+}
+
+// NewScheduler creates a new instance of Scheduler. name identifies the job's lease, so every
+// instance must use the same name for the same job.
+func NewScheduler(name string, interval time.Duration, locker Locker, job func(ctx context.Context) error, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		name:     name,
+		interval: interval,
+		locker:   locker,
+		holder:   newLockHolder(),
+		job:      job,
+		logger:   logger,
+	}
+}
+
+// newLockHolder names this process as a lease holder. The random suffix tells apart
+// processes that share a hostname, such as containers restarted in place.
+func newLockHolder() string {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	suffix := make([]byte, 8)
+	rand.Read(suffix)
+	return hostname + "-" + hex.EncodeToString(suffix)
+}
+
+// Run runs the job right away and then on every tick until ctx is done, and gives up the lease
+// before returning. A run is cut short once it takes longer than the interval.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	defer s.release()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick runs the job once if this instance holds, or can take, the lease
+func (s *Scheduler) tick(ctx context.Context) {
+	acquired, err := s.locker.Acquire(ctx, s.name, s.holder, 2*s.interval)
+	if err != nil {
+		s.logger.WarnContext(ctx, "failed to acquire scheduler lock", "job", s.name, "error", err)
+		return
+	}
+	if !acquired {
+		s.logger.DebugContext(ctx, "scheduler lock is held by another instance", "job", s.name)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+
+	if err := s.job(ctx); err != nil {
+		s.logger.WarnContext(ctx, "scheduled job failed", "job", s.name, "error", err)
+	}
+}
+
+// release gives up the lease so another instance can take over without waiting for it to expire
+func (s *Scheduler) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.locker.Release(ctx, s.name, s.holder); err != nil {
+		s.logger.WarnContext(ctx, "failed to release scheduler lock", "job", s.name, "error", err)
+	}
+}
@@ -0,0 +1,97 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryLocker(t *testing.T) {
+	t.Run("Success - a lease is held until it expires or is released", func(t *testing.T) {
+		// Arrange
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		locker := NewMemoryLocker()
+		locker.now = clock.Now
+		ctx := context.Background()
+
+		// Act
+		first, _ := locker.Acquire(ctx, "job", "a", time.Minute)
+		taken, _ := locker.Acquire(ctx, "job", "b", time.Minute)
+		clock.now = clock.now.Add(2 * time.Minute)
+		expired, _ := locker.Acquire(ctx, "job", "b", time.Minute)
+		locker.Release(ctx, "job", "a")
+		stillHeld, _ := locker.Acquire(ctx, "job", "a", time.Minute)
+		locker.Release(ctx, "job", "b")
+		released, _ := locker.Acquire(ctx, "job", "a", time.Minute)
+
+		// Assert
+		assert.True(t, first)
+		assert.False(t, taken)
+		assert.True(t, expired)
+		assert.False(t, stillHeld)
+		assert.True(t, released)
+	})
+}
+
+func TestScheduler(t *testing.T) {
+	t.Run("Success - runs on every tick and releases the lease when stopped", func(t *testing.T) {
+		// Arrange
+		locker := NewMemoryLocker()
+		var runs atomic.Int32
+		ctx, cancel := context.WithCancel(context.Background())
+		scheduler := NewScheduler("job", 10*time.Millisecond, locker, func(ctx context.Context) error {
+			if runs.Add(1) == 3 {
+				cancel()
+			}
+			return errors.New("failures are logged and retried next tick")
+		}, NewNopLogger())
+		done := make(chan struct{})
+
+		// Act
+		go func() {
+			scheduler.Run(ctx)
+			close(done)
+		}()
+
+		// Assert
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("scheduler did not stop")
+		}
+		assert.Equal(t, int32(3), runs.Load())
+		acquired, err := locker.Acquire(context.Background(), "job", "other", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("Success - skips ticks while another instance holds the lease", func(t *testing.T) {
+		// Arrange
+		locker := NewMemoryLocker()
+		locker.Acquire(context.Background(), "job", "other", time.Minute)
+		var runs atomic.Int32
+		scheduler := NewScheduler("job", 10*time.Millisecond, locker, func(ctx context.Context) error {
+			runs.Add(1)
+			return nil
+		}, NewNopLogger())
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		// Act
+		scheduler.Run(ctx)
+
+		// Assert
+		assert.Zero(t, runs.Load())
+		acquired, _ := locker.Acquire(context.Background(), "job", "other", time.Minute)
+		assert.True(t, acquired)
+	})
+}
+
+func TestLockerImplementations(t *testing.T) {
+	var _ Locker = NewMemoryLocker()
+}
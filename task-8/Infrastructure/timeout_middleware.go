@@ -0,0 +1,270 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"task_manager/Domain"
+)
+
+// DefaultRequestTimeout is used when REQUEST_TIMEOUT is unset or invalid
+const DefaultRequestTimeout = 15 * time.Second
+
+// TimeoutMiddleware bounds how long a request may run before the client gets a 503
+type TimeoutMiddleware struct {
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// NewTimeoutMiddleware creates a new instance of TimeoutMiddleware.
+// REQUEST_TIMEOUT takes a Go duration such as "15s" or "500ms".
+func NewTimeoutMiddleware(logger *slog.Logger) *TimeoutMiddleware {
+	timeout := DefaultRequestTimeout
+	if value := os.Getenv("REQUEST_TIMEOUT"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			timeout = parsed
+		}
+	}
+
+	return &TimeoutMiddleware{
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Timeout runs the rest of the chain with a request context that is cancelled at the deadline.
+// The handler's response is buffered so that exactly one response reaches the client: the
+// handler's if it finishes in time, otherwise a 503. Once a handler flushes (streaming), its
+// output goes straight to the client and the deadline only cancels the context.
+// Routes whose full path is listed in exemptPaths, such as streaming exports that may
+// legitimately run longer than the timeout, are passed through untouched.
+func (tm *TimeoutMiddleware) Timeout(exemptPaths ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptPaths))
+	for _, path := range exemptPaths {
+		exempt[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), tm.timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		method, path := c.Request.Method, c.Request.URL.Path
+
+		writer := newTimeoutWriter(c.Writer)
+		c.Writer = writer
+
+		done := make(chan struct{})
+		var panicValue interface{}
+		go func() {
+			defer close(done)
+			defer func() {
+				panicValue = recover()
+			}()
+			c.Next()
+		}()
+
+		select {
+		case <-done:
+		case <-ctx.Done():
+			select {
+			case <-done:
+			default:
+				if ctx.Err() == context.DeadlineExceeded {
+					tm.logger.WarnContext(ctx, "request timed out", "method", method, "path", path, "timeout", tm.timeout)
+					writer.timeOut(tm.timeout, Domain.LocalizeMessage(ctx, Domain.MsgRequestTimedOut), Domain.RequestIDFromContext(ctx))
+				}
+				// The gin context is reused once this middleware returns, so the
+				// handler must be finished with it first
+				<-done
+			}
+		}
+
+		c.Writer = writer.ResponseWriter
+		if panicValue != nil {
+			panic(panicValue)
+		}
+		writer.commit()
+	}
+}
+
+// timeoutWriter buffers a handler's response until the middleware decides whether to send it
+type timeoutWriter struct {
+	gin.ResponseWriter
+
+	mu        sync.Mutex
+	header    http.Header
+	body      bytes.Buffer
+	status    int
+	written   bool
+	committed bool
+	timedOut  bool
+}
+
+func newTimeoutWriter(w gin.ResponseWriter) *timeoutWriter {
+	return &timeoutWriter{
+		ResponseWriter: w,
+		header:         w.Header().Clone(),
+		status:         http.StatusOK,
+	}
+}
+
+func (w *timeoutWriter) Header() http.Header {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.committed {
+		return w.ResponseWriter.Header()
+	}
+	return w.header
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if code > 0 && !w.written {
+		w.status = code
+	}
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.written = true
+	if w.committed {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	w.written = true
+	if w.committed {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *timeoutWriter) Status() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.committed {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+func (w *timeoutWriter) Size() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.committed {
+		return w.ResponseWriter.Size()
+	}
+	if !w.written {
+		return -1
+	}
+	return w.body.Len()
+}
+
+func (w *timeoutWriter) Written() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.written
+}
+
+// Flush switches the writer to streaming: everything buffered so far is sent and later
+// writes go straight to the client
+func (w *timeoutWriter) Flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	w.written = true
+	w.commitLocked()
+	w.ResponseWriter.Flush()
+}
+
+// commit sends the buffered response unless the request already timed out. A status set
+// without a body, such as for an abandoned request, is still passed on for the logs.
+func (w *timeoutWriter) commit() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.timedOut {
+		return
+	}
+	if !w.written {
+		w.ResponseWriter.WriteHeader(w.status)
+		return
+	}
+	w.commitLocked()
+}
+
+func (w *timeoutWriter) commitLocked() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+
+	header := w.ResponseWriter.Header()
+	for key, values := range w.header {
+		header[key] = values
+	}
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
+
+// timeOut answers with a 503 unless the handler has already started streaming
+func (w *timeoutWriter) timeOut(timeout time.Duration, message Domain.Message, requestID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.committed {
+		return
+	}
+	w.timedOut = true
+
+	body, _ := json.Marshal(Domain.ErrorResponse{
+		Success:   false,
+		Message:   message.Text,
+		MessageID: message.ID,
+		Error:     fmt.Sprintf("The request did not complete within %s", timeout),
+		RequestID: requestID,
+	})
+	w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.ResponseWriter.WriteHeader(http.StatusServiceUnavailable)
+	w.ResponseWriter.Write(body)
+	w.ResponseWriter.Flush()
+}
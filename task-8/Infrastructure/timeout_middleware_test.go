@@ -0,0 +1,210 @@
+package Infrastructure
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"task_manager/Domain"
+)
+
+func setupTimeoutTestRouter(timeout time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use((&TimeoutMiddleware{timeout: timeout, logger: NewNopLogger()}).Timeout())
+	return router
+}
+
+func TestNewTimeoutMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected time.Duration
+	}{
+		{name: "Unset uses default", value: "", expected: DefaultRequestTimeout},
+		{name: "Duration", value: "2s", expected: 2 * time.Second},
+		{name: "Invalid uses default", value: "soon", expected: DefaultRequestTimeout},
+		{name: "Non-positive uses default", value: "0s", expected: DefaultRequestTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			t.Setenv("REQUEST_TIMEOUT", tt.value)
+
+			// Act
+			middleware := NewTimeoutMiddleware(NewNopLogger())
+
+			// Assert
+			assert.Equal(t, tt.expected, middleware.timeout)
+		})
+	}
+}
+
+func TestTimeoutMiddleware_Timeout(t *testing.T) {
+	t.Run("Success - exempt route streams past the deadline", func(t *testing.T) {
+		// Arrange: a slow export that writes and flushes rows for longer than the timeout
+		gin.SetMode(gin.TestMode)
+		router := gin.New()
+		router.Use((&TimeoutMiddleware{timeout: 20 * time.Millisecond, logger: NewNopLogger()}).Timeout("/export"))
+		var ctxErr error
+		router.GET("/export", func(c *gin.Context) {
+			c.Header("Content-Type", "text/csv")
+			c.Status(http.StatusOK)
+			for i := 0; i < 5; i++ {
+				c.Writer.WriteString("row\n")
+				c.Writer.Flush()
+				time.Sleep(10 * time.Millisecond)
+			}
+			ctxErr = c.Request.Context().Err()
+		})
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "row\nrow\nrow\nrow\nrow\n", w.Body.String())
+		assert.NoError(t, ctxErr)
+	})
+
+	t.Run("Success - handler finishes in time", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(time.Second)
+		router.GET("/fast", func(c *gin.Context) {
+			c.Header("X-Handler", "fast")
+			c.JSON(http.StatusCreated, gin.H{"ok": true})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/fast", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusCreated, w.Code)
+		assert.Equal(t, "fast", w.Header().Get("X-Handler"))
+		assert.JSONEq(t, `{"ok":true}`, w.Body.String())
+	})
+
+	t.Run("Success - status set without a body is passed on", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(time.Second)
+		router.GET("/abandoned", func(c *gin.Context) {
+			c.Status(499)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/abandoned", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, 499, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("Success - handler sees a context with a deadline", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(time.Second)
+		var hasDeadline bool
+		router.GET("/deadline", func(c *gin.Context) {
+			_, hasDeadline = c.Request.Context().Deadline()
+			c.Status(http.StatusNoContent)
+		})
+		req := httptest.NewRequest(http.MethodGet, "/deadline", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.True(t, hasDeadline)
+	})
+
+	t.Run("Error - slow handler gets 503", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(20 * time.Millisecond)
+		router.GET("/slow", func(c *gin.Context) {
+			<-c.Request.Context().Done()
+			c.JSON(http.StatusInternalServerError, gin.H{"error": c.Request.Context().Err().Error()})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/slow", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.False(t, response.Success)
+		assert.Equal(t, "request timed out", response.Message)
+	})
+
+	t.Run("Error - late handler response is discarded", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(20 * time.Millisecond)
+		router.GET("/late", func(c *gin.Context) {
+			time.Sleep(60 * time.Millisecond)
+			c.Header("X-Handler", "late")
+			c.JSON(http.StatusOK, gin.H{"ok": true})
+		})
+		req := httptest.NewRequest(http.MethodGet, "/late", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Empty(t, w.Header().Get("X-Handler"))
+		var response Domain.ErrorResponse
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &response))
+		assert.Equal(t, "request timed out", response.Message)
+	})
+
+	t.Run("Success - streamed response is not replaced", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(20 * time.Millisecond)
+		router.GET("/stream", func(c *gin.Context) {
+			c.Status(http.StatusOK)
+			c.Writer.WriteString("first\n")
+			c.Writer.Flush()
+			<-c.Request.Context().Done()
+			c.Writer.WriteString("last\n")
+		})
+		req := httptest.NewRequest(http.MethodGet, "/stream", nil)
+		w := httptest.NewRecorder()
+
+		// Act
+		router.ServeHTTP(w, req)
+
+		// Assert
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "first\nlast\n", w.Body.String())
+	})
+
+	t.Run("Success - handler panic reaches outer recovery", func(t *testing.T) {
+		// Arrange
+		router := setupTimeoutTestRouter(time.Second)
+		router.GET("/panic", func(c *gin.Context) {
+			panic("boom")
+		})
+		req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+		w := httptest.NewRecorder()
+
+		// Act & Assert
+		assert.Panics(t, func() {
+			router.ServeHTTP(w, req)
+		})
+	})
+}
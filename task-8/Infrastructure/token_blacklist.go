@@ -0,0 +1,56 @@
+package Infrastructure
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBlacklist records revoked access tokens by their JTI until they would have expired anyway.
+// The MongoDB implementation lives in Repositories.
+type TokenBlacklist interface {
+	Add(ctx context.Context, jti string, expiresAt time.Time) error
+	Contains(ctx context.Context, jti string) (bool, error)
+}
+
+// MemoryTokenBlacklist implements TokenBlacklist in process memory. Revocations are lost on
+// restart and not shared between instances, so it suits tests and single-instance setups.
+type MemoryTokenBlacklist struct {
+	mu     sync.Mutex
+	tokens map[string]time.Time
+	now    func() time.Time
+}
+
+// NewMemoryTokenBlacklist creates a new instance of MemoryTokenBlacklist
+func NewMemoryTokenBlacklist() *MemoryTokenBlacklist {
+	return &MemoryTokenBlacklist{
+		tokens: make(map[string]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Add implements TokenBlacklist, dropping entries that have expired
+func (b *MemoryTokenBlacklist) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for id, expiry := range b.tokens {
+		if !expiry.After(now) {
+			delete(b.tokens, id)
+		}
+	}
+	if expiresAt.After(now) {
+		b.tokens[jti] = expiresAt
+	}
+	return nil
+}
+
+// Contains implements TokenBlacklist
+func (b *MemoryTokenBlacklist) Contains(ctx context.Context, jti string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiresAt, ok := b.tokens[jti]
+	return ok && expiresAt.After(b.now()), nil
+}
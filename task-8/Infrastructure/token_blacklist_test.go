@@ -0,0 +1,54 @@
+package Infrastructure
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryTokenBlacklist(t *testing.T) {
+	t.Run("Success - revoked token is found until it expires", func(t *testing.T) {
+		// Arrange
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		blacklist := NewMemoryTokenBlacklist()
+		blacklist.now = clock.Now
+		ctx := context.Background()
+
+		// Act
+		err := blacklist.Add(ctx, "revoked", clock.now.Add(time.Minute))
+		revoked, _ := blacklist.Contains(ctx, "revoked")
+		other, _ := blacklist.Contains(ctx, "other")
+		clock.now = clock.now.Add(2 * time.Minute)
+		expired, _ := blacklist.Contains(ctx, "revoked")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, revoked)
+		assert.False(t, other)
+		assert.False(t, expired)
+	})
+
+	t.Run("Success - expired entries are dropped", func(t *testing.T) {
+		// Arrange
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		blacklist := NewMemoryTokenBlacklist()
+		blacklist.now = clock.Now
+		ctx := context.Background()
+		blacklist.Add(ctx, "old", clock.now.Add(time.Minute))
+
+		// Act
+		clock.now = clock.now.Add(2 * time.Minute)
+		blacklist.Add(ctx, "new", clock.now.Add(time.Minute))
+		blacklist.Add(ctx, "already-expired", clock.now.Add(-time.Second))
+
+		// Assert
+		assert.Len(t, blacklist.tokens, 1)
+		assert.Contains(t, blacklist.tokens, "new")
+	})
+}
+
+func TestTokenBlacklistImplementations(t *testing.T) {
+	var _ TokenBlacklist = NewMemoryTokenBlacklist()
+}
@@ -0,0 +1,101 @@
+package Infrastructure
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+)
+
+// TransactionManager runs a function in a database transaction. Repository calls made with the
+// context handed to fn take part in the transaction, so their writes apply together or not at all.
+// fn may run more than once when the transaction hits a transient error, so it must not have side
+// effects outside the database.
+type TransactionManager interface {
+	WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// DirectTransactionManager implements TransactionManager by running fn directly, for storage
+// without transactions such as the in-memory repositories
+type DirectTransactionManager struct{}
+
+// WithTransaction implements TransactionManager
+func (DirectTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// MongoTransactionManager implements TransactionManager with MongoDB sessions. Transactions need a
+// replica set or sharded cluster; against a standalone server, as used in local development, fn
+// runs without a transaction and a warning is logged once.
+type MongoTransactionManager struct {
+	client    *mongo.Client
+	logger    *slog.Logger
+	mu        sync.Mutex
+	checked   bool
+	supported bool
+}
+
+// NewMongoTransactionManager creates a new instance of MongoTransactionManager
+func NewMongoTransactionManager(client *mongo.Client, logger *slog.Logger) *MongoTransactionManager {
+	return &MongoTransactionManager{
+		client: client,
+		logger: logger,
+	}
+}
+
+// WithTransaction implements TransactionManager
+func (tm *MongoTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if !tm.transactionsSupported(ctx) {
+		return fn(ctx)
+	}
+
+	session, err := tm.client.StartSession()
+	if err != nil {
+		return err
+	}
+	defer session.EndSession(ctx)
+
+	// Reads in a transaction must go to the primary, whatever the client's read preference
+	transactionOptions := options.Transaction().SetReadPreference(readpref.Primary())
+	_, err = session.WithTransaction(ctx, func(sessionCtx mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sessionCtx)
+	}, transactionOptions)
+	return err
+}
+
+// transactionsSupported asks the server once whether it can run transactions. When the server
+// cannot be asked, this call runs without a transaction and the next one asks again.
+func (tm *MongoTransactionManager) transactionsSupported(ctx context.Context) bool {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	if tm.checked {
+		return tm.supported
+	}
+
+	var hello bson.M
+	if err := tm.client.Database("admin").RunCommand(ctx, bson.D{{Key: "hello", Value: 1}}).Decode(&hello); err != nil {
+		tm.logger.WarnContext(ctx, "could not check transaction support, running without a transaction", "error", err)
+		return false
+	}
+
+	tm.checked = true
+	tm.supported = supportsTransactions(hello)
+	if !tm.supported {
+		tm.logger.WarnContext(ctx, "MongoDB is a standalone server, multi-document writes run without transactions")
+	}
+	return tm.supported
+}
+
+// supportsTransactions reads a hello response: replica set members report their set name and
+// mongos routers report "isdbgrid"
+func supportsTransactions(hello bson.M) bool {
+	if _, ok := hello["setName"]; ok {
+		return true
+	}
+	return hello["msg"] == "isdbgrid"
+}
@@ -0,0 +1,30 @@
+package Infrastructure
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSupportsTransactions(t *testing.T) {
+	testCases := []struct {
+		name     string
+		hello    bson.M
+		expected bool
+	}{
+		{"replica set member", bson.M{"isWritablePrimary": true, "setName": "rs0"}, true},
+		{"mongos router", bson.M{"isWritablePrimary": true, "msg": "isdbgrid"}, true},
+		{"standalone server", bson.M{"isWritablePrimary": true}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Act & Assert
+			assert.Equal(t, tc.expected, supportsTransactions(tc.hello))
+		})
+	}
+}
+
+func TestTransactionManagerImplementations(t *testing.T) {
+	var _ TransactionManager = NewMongoTransactionManager(nil, NewNopLogger())
+}
@@ -0,0 +1,90 @@
+package Infrastructure
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultUserStatusCacheTTL is used when USER_STATUS_CACHE_TTL is unset or invalid
+const DefaultUserStatusCacheTTL = 10 * time.Second
+
+// maxUserStatusEntries bounds the cache; expired entries are dropped once it is reached
+const maxUserStatusEntries = 10000
+
+// UserStatusChecker reports whether the user an access token was issued to may still use it.
+// The MongoDB implementation is the user repository.
+type UserStatusChecker interface {
+	IsActive(ctx context.Context, userID string) (bool, error)
+}
+
+type userStatusEntry struct {
+	active    bool
+	expiresAt time.Time
+}
+
+// CachedUserStatusChecker remembers each user's status for a short time so that not every
+// authenticated request costs a database read. Deactivating a user takes effect on this
+// instance once their cached status expires.
+type CachedUserStatusChecker struct {
+	checker UserStatusChecker
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[string]userStatusEntry
+	now     func() time.Time
+}
+
+// NewCachedUserStatusChecker creates a new instance of CachedUserStatusChecker.
+// USER_STATUS_CACHE_TTL takes a Go duration such as "10s"; "0" disables the cache.
+func NewCachedUserStatusChecker(checker UserStatusChecker) *CachedUserStatusChecker {
+	ttl := DefaultUserStatusCacheTTL
+	if value := os.Getenv("USER_STATUS_CACHE_TTL"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed >= 0 {
+			ttl = parsed
+		}
+	}
+
+	return &CachedUserStatusChecker{
+		checker: checker,
+		ttl:     ttl,
+		entries: make(map[string]userStatusEntry),
+		now:     time.Now,
+	}
+}
+
+// IsActive implements UserStatusChecker. Errors are not cached.
+func (cc *CachedUserStatusChecker) IsActive(ctx context.Context, userID string) (bool, error) {
+	if cc.ttl == 0 {
+		return cc.checker.IsActive(ctx, userID)
+	}
+
+	now := cc.now()
+	cc.mu.Lock()
+	entry, ok := cc.entries[userID]
+	cc.mu.Unlock()
+	if ok && now.Before(entry.expiresAt) {
+		return entry.active, nil
+	}
+
+	active, err := cc.checker.IsActive(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+	if len(cc.entries) >= maxUserStatusEntries {
+		for id, cached := range cc.entries {
+			if !now.Before(cached.expiresAt) {
+				delete(cc.entries, id)
+			}
+		}
+		if len(cc.entries) >= maxUserStatusEntries {
+			cc.entries = make(map[string]userStatusEntry)
+		}
+	}
+	cc.entries[userID] = userStatusEntry{active: active, expiresAt: now.Add(cc.ttl)}
+
+	return active, nil
+}
@@ -0,0 +1,71 @@
+package Infrastructure
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCachedUserStatusChecker(t *testing.T) {
+	t.Run("Success - status is cached until the TTL passes", func(t *testing.T) {
+		// Arrange
+		t.Setenv("USER_STATUS_CACHE_TTL", "10s")
+		clock := &fakeClock{now: time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)}
+		inner := &stubUserStatusChecker{active: true}
+		checker := NewCachedUserStatusChecker(inner)
+		checker.now = clock.Now
+		ctx := context.Background()
+
+		// Act
+		first, _ := checker.IsActive(ctx, "user-1")
+		inner.active = false
+		cached, _ := checker.IsActive(ctx, "user-1")
+		clock.now = clock.now.Add(11 * time.Second)
+		refreshed, _ := checker.IsActive(ctx, "user-1")
+
+		// Assert
+		assert.True(t, first)
+		assert.True(t, cached)
+		assert.False(t, refreshed)
+		assert.Equal(t, 2, inner.calls)
+	})
+
+	t.Run("Success - zero TTL disables the cache", func(t *testing.T) {
+		// Arrange
+		t.Setenv("USER_STATUS_CACHE_TTL", "0")
+		inner := &stubUserStatusChecker{active: true}
+		checker := NewCachedUserStatusChecker(inner)
+		ctx := context.Background()
+
+		// Act
+		checker.IsActive(ctx, "user-1")
+		checker.IsActive(ctx, "user-1")
+
+		// Assert
+		assert.Equal(t, 2, inner.calls)
+		assert.Empty(t, checker.entries)
+	})
+
+	t.Run("Error - lookup errors are not cached", func(t *testing.T) {
+		// Arrange
+		t.Setenv("USER_STATUS_CACHE_TTL", "")
+		inner := &stubUserStatusChecker{err: errors.New("database unavailable")}
+		checker := NewCachedUserStatusChecker(inner)
+		ctx := context.Background()
+
+		// Act
+		_, err := checker.IsActive(ctx, "user-1")
+		inner.err = nil
+		inner.active = true
+		active, retryErr := checker.IsActive(ctx, "user-1")
+
+		// Assert
+		assert.Error(t, err)
+		assert.NoError(t, retryErr)
+		assert.True(t, active)
+		assert.Equal(t, DefaultUserStatusCacheTTL, checker.ttl)
+	})
+}
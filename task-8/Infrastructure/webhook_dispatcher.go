@@ -0,0 +1,311 @@
+package Infrastructure
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"task_manager/Domain"
+)
+
+// DefaultWebhookMaxAttempts is used when WEBHOOK_MAX_ATTEMPTS is unset or invalid
+const DefaultWebhookMaxAttempts = 5
+
+// DefaultWebhookTimeout is used when WEBHOOK_TIMEOUT is unset or invalid
+const DefaultWebhookTimeout = 10 * time.Second
+
+const (
+	// webhookQueueSize is how many events may wait for delivery before new ones are dropped
+	webhookQueueSize = 1000
+	// webhookWorkers is how many events are delivered at the same time
+	webhookWorkers = 4
+	// webhookBaseDelay is the wait before the second attempt; it doubles with every attempt after that
+	webhookBaseDelay = time.Second
+	// webhookMaxDelay caps the wait between two attempts
+	webhookMaxDelay = time.Minute
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// EventBus publishes events about tasks and users. Publishing never blocks on or fails because
+// of the subscribers, so usecases call it after their change has been saved.
+type EventBus interface {
+	Publish(ctx context.Context, event Domain.Event)
+}
+
+// NopEventBus discards every event
+type NopEventBus struct{}
+
+// NewNopEventBus returns an EventBus that discards every event, for tests and tools
+func NewNopEventBus() EventBus {
+	return NopEventBus{}
+}
+
+// Publish implements EventBus
+func (NopEventBus) Publish(ctx context.Context, event Domain.Event) {}
+
+// WebhookFinder looks up the webhooks an event is sent to
+type WebhookFinder interface {
+	GetActiveByEvent(ctx context.Context, eventType string) ([]*Domain.Webhook, error)
+}
+
+// WebhookDeliveryRecorder keeps the outcome of every delivery
+type WebhookDeliveryRecorder interface {
+	Create(ctx context.Context, delivery *Domain.WebhookDelivery) error
+}
+
+// WebhookDispatcher implements EventBus by POSTing every event to the webhooks subscribed to it.
+// Events are queued and delivered in the background, so a slow or failing endpoint never delays
+// the request that caused the event. Each delivery is signed with the webhook's secret and retried
+// with exponential backoff and jitter on network errors, 429 and 5xx responses; its outcome is
+// recorded once it succeeds or runs out of attempts.
+type WebhookDispatcher struct {
+	webhooks    WebhookFinder
+	deliveries  WebhookDeliveryRecorder
+	client      *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	logger      *slog.Logger
+	sleep       func(ctx context.Context, d time.Duration) error
+	jitter      func(d time.Duration) time.Duration
+
+	queue  chan Domain.Event
+	mu     sync.RWMutex
+	closed bool
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookDispatcher creates a WebhookDispatcher and starts its workers.
+// WEBHOOK_MAX_ATTEMPTS is the number of attempts per delivery (default 5) and WEBHOOK_TIMEOUT
+// bounds each attempt (default 10s).
+func NewWebhookDispatcher(webhooks WebhookFinder, deliveries WebhookDeliveryRecorder, logger *slog.Logger) *WebhookDispatcher {
+	maxAttempts := DefaultWebhookMaxAttempts
+	if value := os.Getenv("WEBHOOK_MAX_ATTEMPTS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed >= 1 {
+			maxAttempts = parsed
+		}
+	}
+
+	timeout := DefaultWebhookTimeout
+	if value := os.Getenv("WEBHOOK_TIMEOUT"); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil && parsed > 0 {
+			timeout = parsed
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &WebhookDispatcher{
+		webhooks:    webhooks,
+		deliveries:  deliveries,
+		client:      &http.Client{Timeout: timeout},
+		maxAttempts: maxAttempts,
+		baseDelay:   webhookBaseDelay,
+		maxDelay:    webhookMaxDelay,
+		logger:      logger,
+		sleep:       sleepContext,
+		jitter:      halfJitter,
+		queue:       make(chan Domain.Event, webhookQueueSize),
+		ctx:         ctx,
+		cancel:      cancel,
+	}
+
+	for i := 0; i < webhookWorkers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// Publish implements EventBus. The event is dropped with a warning when the queue is full or the
+// dispatcher has been closed.
+func (d *WebhookDispatcher) Publish(ctx context.Context, event Domain.Event) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	if d.closed {
+		d.logger.WarnContext(ctx, "webhook dispatcher is closed, dropping event", "event", event.Type, "event_id", event.ID)
+		return
+	}
+
+	select {
+	case d.queue <- event:
+	default:
+		d.logger.WarnContext(ctx, "webhook queue is full, dropping event", "event", event.Type, "event_id", event.ID)
+	}
+}
+
+// Close stops accepting events and waits for the queued ones to be delivered. When ctx ends
+// first, deliveries still waiting to retry are abandoned and recorded as failed.
+func (d *WebhookDispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+// work delivers queued events until the queue is closed
+func (d *WebhookDispatcher) work() {
+	defer d.wg.Done()
+	for event := range d.queue {
+		d.dispatch(event)
+	}
+}
+
+// dispatch sends an event to every webhook subscribed to it, in parallel
+func (d *WebhookDispatcher) dispatch(event Domain.Event) {
+	webhooks, err := d.webhooks.GetActiveByEvent(d.ctx, event.Type)
+	if err != nil {
+		d.logger.Error("failed to look up webhooks for event", "event", event.Type, "event_id", event.ID, "error", err)
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		d.logger.Error("failed to encode event", "event", event.Type, "event_id", event.ID, "error", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, webhook := range webhooks {
+		wg.Add(1)
+		go func(webhook *Domain.Webhook) {
+			defer wg.Done()
+			d.deliver(webhook, event, body)
+		}(webhook)
+	}
+	wg.Wait()
+}
+
+// deliver sends one event to one webhook, retrying until it succeeds or runs out of attempts,
+// and records the outcome
+func (d *WebhookDispatcher) deliver(webhook *Domain.Webhook, event Domain.Event, body []byte) {
+	delivery := &Domain.WebhookDelivery{
+		WebhookID: webhook.ID,
+		EventID:   event.ID,
+		Event:     event.Type,
+		CreatedAt: time.Now(),
+	}
+
+	for attempt := 1; ; attempt++ {
+		delivery.Attempts = attempt
+		statusCode, err := d.send(webhook, event, body)
+		delivery.StatusCode = statusCode
+		if err == nil {
+			delivery.Delivered = true
+			delivery.Error = ""
+			break
+		}
+		delivery.Error = err.Error()
+
+		if !retryableDelivery(statusCode) || attempt >= d.maxAttempts {
+			d.logger.Warn("webhook delivery failed", "webhook_id", webhook.ID.Hex(), "event", event.Type, "event_id", event.ID, "attempts", attempt, "error", err)
+			break
+		}
+
+		delay := d.jitter(d.backoff(attempt))
+		if d.sleep(d.ctx, delay) != nil {
+			delivery.Error += " (abandoned at shutdown)"
+			break
+		}
+	}
+
+	// The record is written even while shutting down, so it gets a context of its own
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := d.deliveries.Create(ctx, delivery); err != nil {
+		d.logger.Warn("failed to record webhook delivery", "webhook_id", webhook.ID.Hex(), "event_id", event.ID, "error", err)
+	}
+}
+
+// send makes one delivery attempt. It returns the response status code, or 0 when no response
+// arrived, and an error unless the endpoint answered with a 2xx status.
+func (d *WebhookDispatcher) send(webhook *Domain.Webhook, event Domain.Event, body []byte) (int, error) {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "task-manager-webhooks")
+	req.Header.Set(WebhookEventHeader, event.Type)
+	req.Header.Set(WebhookDeliveryHeader, event.ID)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(webhook.Secret, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a little of the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// backoff returns the delay after the given failed attempt before jitter is applied
+func (d *WebhookDispatcher) backoff(attempt int) time.Duration {
+	delay := d.baseDelay
+	for i := 1; i < attempt && delay < d.maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > d.maxDelay {
+		delay = d.maxDelay
+	}
+	return delay
+}
+
+// retryableDelivery reports whether a failed attempt is worth making again: no response at all,
+// too many requests or a server error. Other client errors will not change on their own.
+func retryableDelivery(statusCode int) bool {
+	return statusCode == 0 || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
+
+// SignWebhookPayload returns the X-Webhook-Signature value of a payload: the hex encoded
+// HMAC-SHA256 of the raw body under the webhook's secret, prefixed with "sha256="
+func SignWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
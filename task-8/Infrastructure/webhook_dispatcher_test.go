@@ -0,0 +1,282 @@
+package Infrastructure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// fakeWebhookStore serves fixed webhooks and keeps the deliveries recorded
+type fakeWebhookStore struct {
+	mu         sync.Mutex
+	webhooks   []*Domain.Webhook
+	findErr    error
+	deliveries []*Domain.WebhookDelivery
+}
+
+func (s *fakeWebhookStore) GetActiveByEvent(ctx context.Context, eventType string) ([]*Domain.Webhook, error) {
+	var subscribed []*Domain.Webhook
+	for _, webhook := range s.webhooks {
+		for _, event := range webhook.Events {
+			if event == eventType {
+				subscribed = append(subscribed, webhook)
+			}
+		}
+	}
+	return subscribed, s.findErr
+}
+
+func (s *fakeWebhookStore) Create(ctx context.Context, delivery *Domain.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deliveries = append(s.deliveries, delivery)
+	return nil
+}
+
+// newTestDispatcher returns a dispatcher for one webhook at url that does not wait between attempts
+func newTestDispatcher(t *testing.T, url string) (*WebhookDispatcher, *fakeWebhookStore, *[]time.Duration) {
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	store := &fakeWebhookStore{webhooks: []*Domain.Webhook{{
+		ID:     primitive.NewObjectID(),
+		URL:    url,
+		Secret: "s3cret",
+		Events: []string{Domain.EventTaskCreated},
+		Active: true,
+	}}}
+	dispatcher := NewWebhookDispatcher(store, store, NewNopLogger())
+
+	var delays []time.Duration
+	dispatcher.jitter = func(d time.Duration) time.Duration { return d }
+	dispatcher.sleep = func(ctx context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	return dispatcher, store, &delays
+}
+
+// closeDispatcher waits for every queued event to be delivered
+func closeDispatcher(t *testing.T, dispatcher *WebhookDispatcher) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, dispatcher.Close(ctx))
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	event := Domain.NewEvent(Domain.EventTaskCreated, "507f1f77bcf86cd799439011", map[string]string{"title": "Ship it"})
+
+	t.Run("Success - the event is posted, signed and recorded", func(t *testing.T) {
+		// Arrange
+		var received *http.Request
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+		dispatcher, store, _ := newTestDispatcher(t, server.URL)
+
+		// Act
+		dispatcher.Publish(context.Background(), event)
+		closeDispatcher(t, dispatcher)
+
+		// Assert
+		require.NotNil(t, received)
+		assert.Equal(t, http.MethodPost, received.Method)
+		assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+		assert.Equal(t, Domain.EventTaskCreated, received.Header.Get(WebhookEventHeader))
+		assert.Equal(t, event.ID, received.Header.Get(WebhookDeliveryHeader))
+		assert.Equal(t, SignWebhookPayload("s3cret", body), received.Header.Get(WebhookSignatureHeader))
+
+		var payload map[string]interface{}
+		require.NoError(t, json.Unmarshal(body, &payload))
+		assert.Equal(t, event.ID, payload["id"])
+		assert.Equal(t, Domain.EventTaskCreated, payload["type"])
+		assert.Equal(t, map[string]interface{}{"title": "Ship it"}, payload["data"])
+
+		require.Len(t, store.deliveries, 1)
+		assert.True(t, store.deliveries[0].Delivered)
+		assert.Equal(t, 1, store.deliveries[0].Attempts)
+		assert.Equal(t, http.StatusNoContent, store.deliveries[0].StatusCode)
+		assert.Equal(t, event.ID, store.deliveries[0].EventID)
+		assert.Equal(t, store.webhooks[0].ID, store.deliveries[0].WebhookID)
+	})
+
+	t.Run("Success - server errors are retried with backoff", func(t *testing.T) {
+		// Arrange
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if atomic.AddInt32(&calls, 1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+		dispatcher, store, delays := newTestDispatcher(t, server.URL)
+
+		// Act
+		dispatcher.Publish(context.Background(), event)
+		closeDispatcher(t, dispatcher)
+
+		// Assert
+		assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second}, *delays)
+		require.Len(t, store.deliveries, 1)
+		assert.True(t, store.deliveries[0].Delivered)
+		assert.Equal(t, 3, store.deliveries[0].Attempts)
+		assert.Empty(t, store.deliveries[0].Error)
+	})
+
+	t.Run("Error - gives up after the last attempt", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+		dispatcher, store, _ := newTestDispatcher(t, server.URL)
+
+		// Act
+		dispatcher.Publish(context.Background(), event)
+		closeDispatcher(t, dispatcher)
+
+		// Assert
+		require.Len(t, store.deliveries, 1)
+		assert.False(t, store.deliveries[0].Delivered)
+		assert.Equal(t, 3, store.deliveries[0].Attempts)
+		assert.Equal(t, http.StatusInternalServerError, store.deliveries[0].StatusCode)
+		assert.Equal(t, "endpoint responded with 500 Internal Server Error", store.deliveries[0].Error)
+	})
+
+	t.Run("Error - client errors are not retried", func(t *testing.T) {
+		// Arrange
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+			w.WriteHeader(http.StatusGone)
+		}))
+		defer server.Close()
+		dispatcher, store, _ := newTestDispatcher(t, server.URL)
+
+		// Act
+		dispatcher.Publish(context.Background(), event)
+		closeDispatcher(t, dispatcher)
+
+		// Assert
+		assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+		require.Len(t, store.deliveries, 1)
+		assert.False(t, store.deliveries[0].Delivered)
+		assert.Equal(t, 1, store.deliveries[0].Attempts)
+		assert.Equal(t, http.StatusGone, store.deliveries[0].StatusCode)
+	})
+
+	t.Run("Success - events nobody subscribed to are not sent", func(t *testing.T) {
+		// Arrange
+		var calls int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&calls, 1)
+		}))
+		defer server.Close()
+		dispatcher, store, _ := newTestDispatcher(t, server.URL)
+
+		// Act
+		dispatcher.Publish(context.Background(), Domain.NewEvent(Domain.EventTaskDeleted, "", Domain.DeletedTaskEvent{ID: "1"}))
+		closeDispatcher(t, dispatcher)
+
+		// Assert
+		assert.Zero(t, atomic.LoadInt32(&calls))
+		assert.Empty(t, store.deliveries)
+	})
+
+	t.Run("Error - webhook lookup fails", func(t *testing.T) {
+		// Arrange
+		dispatcher, store, _ := newTestDispatcher(t, "http://127.0.0.1:0")
+		store.findErr = errors.New("connection refused")
+
+		// Act
+		dispatcher.Publish(context.Background(), event)
+		closeDispatcher(t, dispatcher)
+
+		// Assert
+		assert.Empty(t, store.deliveries)
+	})
+
+	t.Run("Success - events published after closing are dropped", func(t *testing.T) {
+		// Arrange
+		dispatcher, store, _ := newTestDispatcher(t, "http://127.0.0.1:0")
+		closeDispatcher(t, dispatcher)
+
+		// Act
+		dispatcher.Publish(context.Background(), event)
+
+		// Assert
+		assert.Empty(t, store.deliveries)
+		assert.NoError(t, dispatcher.Close(context.Background()))
+	})
+
+	t.Run("Error - closing gives up on retries when the context ends", func(t *testing.T) {
+		// Arrange
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer server.Close()
+		dispatcher, store, _ := newTestDispatcher(t, server.URL)
+		dispatcher.sleep = sleepContext
+		dispatcher.baseDelay = time.Hour
+		dispatcher.maxDelay = time.Hour
+		dispatcher.Publish(context.Background(), event)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// Act
+		err := dispatcher.Close(ctx)
+
+		// Assert
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Len(t, store.deliveries, 1)
+		assert.False(t, store.deliveries[0].Delivered)
+		assert.Equal(t, 1, store.deliveries[0].Attempts)
+		assert.Contains(t, store.deliveries[0].Error, "abandoned at shutdown")
+	})
+}
+
+func TestSignWebhookPayload(t *testing.T) {
+	// HMAC-SHA256 of "hello" under "secret", as computed by e.g. `openssl dgst -sha256 -hmac secret`
+	assert.Equal(t, "sha256=88aab3ede8d3adf94d26ab90d3bafd4a2083070c3bcce9c014ee04a443847c0b", SignWebhookPayload("secret", []byte("hello")))
+	assert.NotEqual(t, SignWebhookPayload("secret", []byte("hello")), SignWebhookPayload("other", []byte("hello")))
+}
+
+func TestNewWebhookDispatcher(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		t.Setenv("WEBHOOK_MAX_ATTEMPTS", "")
+		t.Setenv("WEBHOOK_TIMEOUT", "")
+		dispatcher := NewWebhookDispatcher(&fakeWebhookStore{}, &fakeWebhookStore{}, NewNopLogger())
+		defer closeDispatcher(t, dispatcher)
+
+		assert.Equal(t, DefaultWebhookMaxAttempts, dispatcher.maxAttempts)
+		assert.Equal(t, DefaultWebhookTimeout, dispatcher.client.Timeout)
+	})
+
+	t.Run("From the environment, ignoring invalid values", func(t *testing.T) {
+		t.Setenv("WEBHOOK_MAX_ATTEMPTS", "0")
+		t.Setenv("WEBHOOK_TIMEOUT", "3s")
+		dispatcher := NewWebhookDispatcher(&fakeWebhookStore{}, &fakeWebhookStore{}, NewNopLogger())
+		defer closeDispatcher(t, dispatcher)
+
+		assert.Equal(t, DefaultWebhookMaxAttempts, dispatcher.maxAttempts)
+		assert.Equal(t, 3*time.Second, dispatcher.client.Timeout)
+	})
+}
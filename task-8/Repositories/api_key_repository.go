@@ -0,0 +1,138 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// APIKeyRepositoryInterface defines the contract for API key data access
+type APIKeyRepositoryInterface interface {
+	Create(ctx context.Context, key *Domain.APIKey) error
+	GetByHash(ctx context.Context, keyHash string) (*Domain.APIKey, error)
+	GetAll(ctx context.Context) ([]*Domain.APIKey, error)
+	Revoke(ctx context.Context, id string) (*Domain.APIKey, error)
+	UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// APIKeyRepository implements APIKeyRepositoryInterface with MongoDB
+type APIKeyRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository(client *mongo.Client, dbName string) APIKeyRepositoryInterface {
+	collection := client.Database(dbName).Collection("api_keys")
+	return &APIKeyRepository{
+		collection: collection,
+	}
+}
+
+// Create stores an API key
+func (ar *APIKeyRepository) Create(ctx context.Context, key *Domain.APIKey) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	key.ID = primitive.NewObjectID()
+	key.CreatedAt = time.Now()
+
+	_, err := ar.collection.InsertOne(ctx, key)
+	return err
+}
+
+// GetByHash returns the API key stored under a hash, revoked or not
+func (ar *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*Domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var key Domain.APIKey
+	err := ar.collection.FindOne(ctx, bson.M{"key_hash": keyHash}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// GetAll returns every API key, newest first
+func (ar *APIKeyRepository) GetAll(ctx context.Context) ([]*Domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := ar.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	keys := []*Domain.APIKey{}
+	if err = cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// Revoke marks an API key as revoked and returns it. Revoking a revoked key is not an error.
+func (ar *APIKeyRepository) Revoke(ctx context.Context, id string) (*Domain.APIKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidAPIKeyID
+	}
+
+	var key Domain.APIKey
+	err = ar.collection.FindOneAndUpdate(ctx,
+		bson.M{"_id": objectID},
+		bson.M{"$set": bson.M{"revoked": true}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After),
+	).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrAPIKeyNotFound
+		}
+		return nil, err
+	}
+
+	return &key, nil
+}
+
+// UpdateLastUsed records when an API key was last used
+func (ar *APIKeyRepository) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := ar.collection.UpdateOne(ctx, bson.M{"_id": id}, bson.M{"$set": bson.M{"last_used_at": at}})
+	return err
+}
+
+// EnsureIndexes creates the index API keys are looked up by
+func (ar *APIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := ar.collection.Indexes().CreateMany(ctx, apiKeyIndexes())
+	return err
+}
+
+// apiKeyIndexes lists the indexes maintained on the api_keys collection
+func apiKeyIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "key_hash", Value: 1}},
+			Options: options.Index().SetName("key_hash_1").SetUnique(true),
+		},
+	}
+}
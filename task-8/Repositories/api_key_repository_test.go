@@ -0,0 +1,106 @@
+package Repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockAPIKeyRepositoryImpl for testing purposes
+type MockAPIKeyRepositoryImpl struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepositoryImpl) Create(ctx context.Context, key *Domain.APIKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepositoryImpl) GetByHash(ctx context.Context, keyHash string) (*Domain.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepositoryImpl) GetAll(ctx context.Context) ([]*Domain.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepositoryImpl) Revoke(ctx context.Context, id string) (*Domain.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepositoryImpl) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestAPIKeyIndexes(t *testing.T) {
+	t.Run("Hashes are unique", func(t *testing.T) {
+		// Act
+		indexes := apiKeyIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 1)
+		assert.Equal(t, bson.D{{Key: "key_hash", Value: 1}}, indexes[0].Keys)
+		assert.True(t, *indexes[0].Options.Unique)
+	})
+}
+
+func TestAPIKeyRepository_Revoke(t *testing.T) {
+	t.Run("Success - returns the revoked key", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockAPIKeyRepositoryImpl)
+		key := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "nightly-report", Role: Domain.RoleUser, Revoked: true}
+		mockRepo.On("Revoke", key.ID.Hex()).Return(key, nil)
+
+		// Act
+		revoked, err := mockRepo.Revoke(context.Background(), key.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, revoked.Revoked)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - key not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockAPIKeyRepositoryImpl)
+		id := primitive.NewObjectID().Hex()
+		mockRepo.On("Revoke", id).Return(nil, Domain.ErrAPIKeyNotFound)
+
+		// Act
+		revoked, err := mockRepo.Revoke(context.Background(), id)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAPIKeyNotFound)
+		assert.Nil(t, revoked)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAPIKeyRepositoryInterface(t *testing.T) {
+	mockRepo := new(MockAPIKeyRepositoryImpl)
+	var _ APIKeyRepositoryInterface = mockRepo
+	var _ APIKeyRepositoryInterface = &APIKeyRepository{}
+	assert.NotNil(t, mockRepo)
+}
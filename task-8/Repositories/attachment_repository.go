@@ -0,0 +1,207 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// AttachmentRepositoryInterface defines the contract for attachment metadata access.
+// The purge methods return the records they removed so the caller can delete their files.
+type AttachmentRepositoryInterface interface {
+	GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Attachment, error)
+	GetByID(ctx context.Context, taskID, id string) (*Domain.Attachment, error)
+	Create(ctx context.Context, attachment *Domain.Attachment) error
+	Delete(ctx context.Context, taskID, id string) error
+	DeleteByTaskID(ctx context.Context, taskID string) error
+	DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error
+	RestoreByTaskID(ctx context.Context, taskID string) error
+	Purge(ctx context.Context, deletedBefore time.Time) ([]*Domain.Attachment, error)
+	PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) ([]*Domain.Attachment, error)
+}
+
+// AttachmentRepository implements AttachmentRepositoryInterface with MongoDB
+type AttachmentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAttachmentRepository creates a new instance of AttachmentRepository
+func NewAttachmentRepository(client *mongo.Client, dbName string) AttachmentRepositoryInterface {
+	collection := client.Database(dbName).Collection("attachments")
+	return &AttachmentRepository{
+		collection: collection,
+	}
+}
+
+// GetByTaskID returns the attachments on a task, oldest first
+func (ar *AttachmentRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := ar.collection.Find(ctx, bson.M{"task_id": objectID, "deleted_at": nil}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attachments := []*Domain.Attachment{}
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// GetByID returns an attachment on a task
+func (ar *AttachmentRepository) GetByID(ctx context.Context, taskID, id string) (*Domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	taskObjectID, objectID, err := attachmentObjectIDs(taskID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachment Domain.Attachment
+	err = ar.collection.FindOne(ctx, bson.M{"_id": objectID, "task_id": taskObjectID, "deleted_at": nil}).Decode(&attachment)
+	if err == mongo.ErrNoDocuments {
+		return nil, Domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+// Create creates a new attachment record in MongoDB
+func (ar *AttachmentRepository) Create(ctx context.Context, attachment *Domain.Attachment) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	attachment.ID = primitive.NewObjectID()
+	attachment.CreatedAt = time.Now()
+
+	_, err := ar.collection.InsertOne(ctx, attachment)
+	return err
+}
+
+// Delete permanently removes an attachment record from a task
+func (ar *AttachmentRepository) Delete(ctx context.Context, taskID, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	taskObjectID, objectID, err := attachmentObjectIDs(taskID, id)
+	if err != nil {
+		return err
+	}
+
+	result, err := ar.collection.DeleteOne(ctx, bson.M{"_id": objectID, "task_id": taskObjectID, "deleted_at": nil})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return Domain.ErrAttachmentNotFound
+	}
+
+	return nil
+}
+
+// DeleteByTaskID soft deletes every attachment on a task by stamping deleted_at
+func (ar *AttachmentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return ar.DeleteByTaskIDs(ctx, []primitive.ObjectID{objectID})
+}
+
+// DeleteByTaskIDs soft deletes every attachment on the given tasks in a single UpdateMany
+func (ar *AttachmentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := ar.collection.UpdateMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}, "deleted_at": nil}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	return err
+}
+
+// RestoreByTaskID clears deleted_at on every attachment on a task
+func (ar *AttachmentRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	_, err = ar.collection.UpdateMany(ctx, bson.M{"task_id": objectID, "deleted_at": bson.M{"$ne": nil}}, bson.M{"$unset": bson.M{"deleted_at": ""}})
+	return err
+}
+
+// Purge permanently removes attachment records that were soft deleted before the given time
+func (ar *AttachmentRepository) Purge(ctx context.Context, deletedBefore time.Time) ([]*Domain.Attachment, error) {
+	return ar.purge(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lte": deletedBefore}})
+}
+
+// PurgeByTaskIDs permanently removes every attachment record on the given tasks, in the trash or not
+func (ar *AttachmentRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) ([]*Domain.Attachment, error) {
+	return ar.purge(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+}
+
+// purge reads the records matching filter and then removes exactly those, so the records returned
+// are the ones deleted even when more match by the time DeleteMany runs
+func (ar *AttachmentRepository) purge(ctx context.Context, filter bson.M) ([]*Domain.Attachment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := ar.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	attachments := []*Domain.Attachment{}
+	if err = cursor.All(ctx, &attachments); err != nil {
+		return nil, err
+	}
+	if len(attachments) == 0 {
+		return attachments, nil
+	}
+
+	ids := make([]primitive.ObjectID, len(attachments))
+	for i, attachment := range attachments {
+		ids[i] = attachment.ID
+	}
+	if _, err = ar.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// attachmentObjectIDs parses the task and attachment IDs of an attachment
+func attachmentObjectIDs(taskID, id string) (primitive.ObjectID, primitive.ObjectID, error) {
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, Domain.ErrInvalidTaskID
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, Domain.ErrInvalidAttachmentID
+	}
+	return taskObjectID, objectID, nil
+}
@@ -0,0 +1,38 @@
+package Repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+func TestAttachmentObjectIDs(t *testing.T) {
+	t.Run("Success - both IDs are parsed", func(t *testing.T) {
+		taskID, id := primitive.NewObjectID(), primitive.NewObjectID()
+
+		taskObjectID, objectID, err := attachmentObjectIDs(taskID.Hex(), id.Hex())
+
+		assert.NoError(t, err)
+		assert.Equal(t, taskID, taskObjectID)
+		assert.Equal(t, id, objectID)
+	})
+
+	t.Run("Error - invalid task ID format", func(t *testing.T) {
+		_, _, err := attachmentObjectIDs("invalid-id", primitive.NewObjectID().Hex())
+
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+	})
+
+	t.Run("Error - invalid attachment ID format", func(t *testing.T) {
+		_, _, err := attachmentObjectIDs(primitive.NewObjectID().Hex(), "invalid-id")
+
+		assert.ErrorIs(t, err, Domain.ErrInvalidAttachmentID)
+	})
+}
+
+func TestAttachmentRepositoryInterface(t *testing.T) {
+	var _ AttachmentRepositoryInterface = &AttachmentRepository{}
+}
@@ -0,0 +1,114 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// AuditRepositoryInterface defines the contract for audit log data access
+type AuditRepositoryInterface interface {
+	Create(ctx context.Context, entry *Domain.AuditEntry) error
+	GetAll(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// AuditRepository implements AuditRepositoryInterface with MongoDB
+type AuditRepository struct {
+	collection *mongo.Collection
+}
+
+// NewAuditRepository creates a new instance of AuditRepository
+func NewAuditRepository(client *mongo.Client, dbName string) AuditRepositoryInterface {
+	collection := client.Database(dbName).Collection("audit_logs")
+	return &AuditRepository{
+		collection: collection,
+	}
+}
+
+// Create appends an entry to the audit log
+func (ar *AuditRepository) Create(ctx context.Context, entry *Domain.AuditEntry) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	entry.ID = primitive.NewObjectID()
+	entry.Timestamp = time.Now()
+
+	_, err := ar.collection.InsertOne(ctx, entry)
+	return err
+}
+
+// GetAll returns one page of audit entries matching the filter, newest first, with the total number of matches
+func (ar *AuditRepository) GetAll(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	query := buildAuditQuery(filter)
+
+	total, err := ar.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if pagination.Limit > 0 {
+		findOptions.SetLimit(pagination.Limit)
+	}
+	if pagination.Offset > 0 {
+		findOptions.SetSkip(pagination.Offset)
+	}
+
+	cursor, err := ar.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	entries := []*Domain.AuditEntry{}
+	if err = cursor.All(ctx, &entries); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// EnsureIndexes creates the indexes used by audit log listings
+func (ar *AuditRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := ar.collection.Indexes().CreateMany(ctx, auditIndexes())
+	return err
+}
+
+// auditIndexes lists the indexes maintained on the audit_logs collection
+func auditIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "actor_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("actor_id_1_timestamp_-1"),
+		},
+		{
+			Keys:    bson.D{{Key: "entity", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("entity_1_timestamp_-1"),
+		},
+	}
+}
+
+// buildAuditQuery translates an AuditFilter into a MongoDB query
+func buildAuditQuery(filter Domain.AuditFilter) bson.M {
+	query := bson.M{}
+	if filter.ActorID != "" {
+		query["actor_id"] = filter.ActorID
+	}
+	if filter.Entity != "" {
+		query["entity"] = filter.Entity
+	}
+	return query
+}
@@ -0,0 +1,99 @@
+package Repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+
+	"task_manager/Domain"
+)
+
+// MockAuditRepositoryImpl for testing purposes
+type MockAuditRepositoryImpl struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepositoryImpl) Create(ctx context.Context, entry *Domain.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepositoryImpl) GetAll(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error) {
+	args := m.Called(filter, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.AuditEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuditRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestBuildAuditQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   Domain.AuditFilter
+		expected bson.M
+	}{
+		{name: "No filter", filter: Domain.AuditFilter{}, expected: bson.M{}},
+		{name: "Actor", filter: Domain.AuditFilter{ActorID: "507f1f77bcf86cd799439011"}, expected: bson.M{"actor_id": "507f1f77bcf86cd799439011"}},
+		{
+			name:     "Actor and entity",
+			filter:   Domain.AuditFilter{ActorID: "507f1f77bcf86cd799439011", Entity: Domain.AuditEntityTask},
+			expected: bson.M{"actor_id": "507f1f77bcf86cd799439011", "entity": Domain.AuditEntityTask},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			query := buildAuditQuery(tt.filter)
+
+			// Assert
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestAuditIndexes(t *testing.T) {
+	// Act
+	indexes := auditIndexes()
+
+	// Assert
+	assert.Len(t, indexes, 2)
+	assert.Equal(t, bson.D{{Key: "actor_id", Value: 1}, {Key: "timestamp", Value: -1}}, indexes[0].Keys)
+	assert.Equal(t, bson.D{{Key: "entity", Value: 1}, {Key: "timestamp", Value: -1}}, indexes[1].Keys)
+}
+
+func TestAuditRepository_GetAll(t *testing.T) {
+	t.Run("Success - return filtered page", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockAuditRepositoryImpl)
+		filter := Domain.AuditFilter{Entity: Domain.AuditEntityUser}
+		pagination := Domain.Pagination{Limit: 10}
+		expectedEntries := []*Domain.AuditEntry{
+			{ActorID: "507f1f77bcf86cd799439011", Action: Domain.AuditActionPromote, Entity: Domain.AuditEntityUser},
+		}
+		mockRepo.On("GetAll", filter, pagination).Return(expectedEntries, int64(1), nil)
+
+		// Act
+		entries, total, err := mockRepo.GetAll(context.Background(), filter, pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedEntries, entries)
+		assert.Equal(t, int64(1), total)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestAuditRepositoryInterface(t *testing.T) {
+	mockRepo := new(MockAuditRepositoryImpl)
+	var _ AuditRepositoryInterface = mockRepo
+	assert.NotNil(t, mockRepo)
+}
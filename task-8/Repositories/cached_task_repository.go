@@ -0,0 +1,231 @@
+package Repositories
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// CachedTaskRepository decorates a task repository with a read-through cache for GetByID and
+// for GetAll without a filter, the reads a polling client repeats. Every write clears the cache
+// once the underlying write has finished, so a read that follows a write never sees what was
+// there before. Every other read goes straight to the underlying repository.
+type CachedTaskRepository struct {
+	TaskRepositoryInterface
+	cache Infrastructure.Cache
+
+	// generation counts writes. A read stores what it loaded only if no write finished while it
+	// was loading, since the value could predate that write.
+	mu         sync.Mutex
+	generation uint64
+}
+
+// NewCachedTaskRepository creates a new instance of CachedTaskRepository around repo
+func NewCachedTaskRepository(repo TaskRepositoryInterface, cache Infrastructure.Cache) TaskRepositoryInterface {
+	return &CachedTaskRepository{
+		TaskRepositoryInterface: repo,
+		cache:                   cache,
+	}
+}
+
+// cachedTaskPage is how a page of GetAll is stored in the cache
+type cachedTaskPage struct {
+	Tasks []*Domain.Task `json:"tasks"`
+	Total int64          `json:"total"`
+}
+
+// GetByID returns the cached task when there is one, and otherwise loads and caches it.
+// Missing tasks are not cached.
+func (cr *CachedTaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	key := "task:" + id
+	var task Domain.Task
+	if cr.lookup(ctx, key, &task) {
+		return &task, nil
+	}
+
+	generation := cr.currentGeneration()
+	loaded, err := cr.TaskRepositoryInterface.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	cr.store(ctx, generation, key, loaded)
+	return loaded, nil
+}
+
+// GetByIDWithFields caches each selection of fields separately from the whole task
+func (cr *CachedTaskRepository) GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error) {
+	if len(fields) == 0 {
+		return cr.GetByID(ctx, id)
+	}
+
+	key := "task:" + id + ":" + strings.Join(fields, ",")
+	var task Domain.Task
+	if cr.lookup(ctx, key, &task) {
+		return &task, nil
+	}
+
+	generation := cr.currentGeneration()
+	loaded, err := cr.TaskRepositoryInterface.GetByIDWithFields(ctx, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	cr.store(ctx, generation, key, loaded)
+	return loaded, nil
+}
+
+// GetAll serves pages of the unfiltered listing from the cache, and every filtered one from the
+// underlying repository
+func (cr *CachedTaskRepository) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	if filter != (Domain.TaskFilter{}) {
+		return cr.TaskRepositoryInterface.GetAll(ctx, filter, pagination)
+	}
+
+	key := fmt.Sprintf("tasks:%d:%d:%s:%s", pagination.Limit, pagination.Offset, pagination.Sort, strings.Join(pagination.Fields, ","))
+	var page cachedTaskPage
+	if cr.lookup(ctx, key, &page) {
+		return page.Tasks, page.Total, nil
+	}
+
+	generation := cr.currentGeneration()
+	tasks, total, err := cr.TaskRepositoryInterface.GetAll(ctx, filter, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+	cr.store(ctx, generation, key, cachedTaskPage{Tasks: tasks, Total: total})
+	return tasks, total, nil
+}
+
+// lookup decodes the cached value under key into value. Each hit decodes a fresh copy, so callers
+// may change what they get back.
+func (cr *CachedTaskRepository) lookup(ctx context.Context, key string, value interface{}) bool {
+	data, ok := cr.cache.Get(ctx, key)
+	return ok && json.Unmarshal(data, value) == nil
+}
+
+// currentGeneration returns the number of writes finished so far
+func (cr *CachedTaskRepository) currentGeneration() uint64 {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	return cr.generation
+}
+
+// store caches a value loaded at generation, unless a write has finished since
+func (cr *CachedTaskRepository) store(ctx context.Context, generation uint64, key string, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	if cr.generation == generation {
+		cr.cache.Set(ctx, key, data)
+	}
+}
+
+// invalidate forgets every cached read. It runs after each write, whether or not the write
+// succeeded, since a failed write may still have changed something.
+func (cr *CachedTaskRepository) invalidate(ctx context.Context) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.generation++
+	cr.cache.Clear(ctx)
+}
+
+// Create implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) Create(ctx context.Context, task *Domain.Task) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.Create(ctx, task)
+}
+
+// CreateMany implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) CreateMany(ctx context.Context, tasks []*Domain.Task) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.CreateMany(ctx, tasks)
+}
+
+// Update implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.Update(ctx, id, task)
+}
+
+// CompleteRecurring implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.CompleteRecurring(ctx, id, task, next)
+}
+
+// UpdateStatusMany implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.UpdateStatusMany(ctx, ids, status, fromStatuses)
+}
+
+// DeleteByStatus implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.DeleteByStatus(ctx, status)
+}
+
+// Delete implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) Delete(ctx context.Context, id string) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.Delete(ctx, id)
+}
+
+// Restore implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) Restore(ctx context.Context, id string) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.Restore(ctx, id)
+}
+
+// SetArchived implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.SetArchived(ctx, id, archived)
+}
+
+// ArchiveCompleted implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.ArchiveCompleted(ctx, completedBefore)
+}
+
+// Purge implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.Purge(ctx, deletedBefore)
+}
+
+// PurgeByStatus implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.PurgeByStatus(ctx, status, updatedBefore)
+}
+
+// ReassignCreator implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.ReassignCreator(ctx, fromUserID, toUserID)
+}
+
+// UnassignUser implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.UnassignUser(ctx, userID)
+}
+
+// MarkNotified implements TaskRepositoryInterface, clearing the cache
+func (cr *CachedTaskRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	defer cr.invalidate(ctx)
+	return cr.TaskRepositoryInterface.MarkNotified(ctx, id, at)
+}
@@ -0,0 +1,162 @@
+package Repositories_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+	"task_manager/Repositories/memory"
+	"task_manager/Repositories/repositorytest"
+)
+
+// pausingTaskRepository holds up the first GetByID after it has read the task,
+// until the test lets it return
+type pausingTaskRepository struct {
+	Repositories.TaskRepositoryInterface
+	once   sync.Once
+	loaded chan struct{}
+	resume chan struct{}
+}
+
+func (r *pausingTaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	task, err := r.TaskRepositoryInterface.GetByID(ctx, id)
+	r.once.Do(func() {
+		close(r.loaded)
+		<-r.resume
+	})
+	return task, err
+}
+
+func TestCachedTaskRepository_Conformance(t *testing.T) {
+	repositorytest.TaskRepository(t, func(t *testing.T) Repositories.TaskRepositoryInterface {
+		return Repositories.NewCachedTaskRepository(memory.NewTaskRepository(), Infrastructure.NewMemoryCache(100, time.Minute))
+	})
+}
+
+func TestCachedTaskRepository(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("Success - repeated reads are served from the cache as copies", func(t *testing.T) {
+		// Arrange
+		cache := Infrastructure.NewMemoryCache(100, time.Minute)
+		repo := Repositories.NewCachedTaskRepository(memory.NewTaskRepository(), cache)
+		task := &Domain.Task{Title: "Write report", Status: Domain.StatusPending, Tags: []string{"work"}}
+		require.NoError(t, repo.Create(ctx, task))
+
+		// Act
+		first, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		first.Tags[0] = "changed"
+		second, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		_, _, err = repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Limit: 10})
+		require.NoError(t, err)
+		page, total, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Limit: 10})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []string{"work"}, second.Tags)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, "Write report", page[0].Title)
+		assert.Equal(t, Infrastructure.CacheStats{Hits: 2, Misses: 2}, cache.Stats())
+	})
+
+	t.Run("Success - each selection of fields is cached apart from the whole task", func(t *testing.T) {
+		// Arrange
+		cache := Infrastructure.NewMemoryCache(100, time.Minute)
+		repo := Repositories.NewCachedTaskRepository(memory.NewTaskRepository(), cache)
+		task := &Domain.Task{Title: "Write report", Description: "Long", Status: Domain.StatusPending}
+		require.NoError(t, repo.Create(ctx, task))
+
+		// Act
+		_, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		selected, err := repo.GetByIDWithFields(ctx, task.ID.Hex(), []string{"title"})
+		require.NoError(t, err)
+		_, _, err = repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{})
+		require.NoError(t, err)
+		page, _, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Fields: []string{"title"}})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Empty(t, selected.Description)
+		assert.Empty(t, page[0].Description)
+		assert.Equal(t, Infrastructure.CacheStats{Misses: 4}, cache.Stats())
+	})
+
+	t.Run("Success - filtered listings bypass the cache", func(t *testing.T) {
+		// Arrange
+		cache := Infrastructure.NewMemoryCache(100, time.Minute)
+		repo := Repositories.NewCachedTaskRepository(memory.NewTaskRepository(), cache)
+
+		// Act
+		_, _, err := repo.GetAll(ctx, Domain.TaskFilter{Status: Domain.StatusPending}, Domain.Pagination{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, Infrastructure.CacheStats{}, cache.Stats())
+		assert.Zero(t, cache.Len())
+	})
+
+	t.Run("Success - a write clears what was cached before it", func(t *testing.T) {
+		// Arrange
+		cache := Infrastructure.NewMemoryCache(100, time.Minute)
+		repo := Repositories.NewCachedTaskRepository(memory.NewTaskRepository(), cache)
+		task := &Domain.Task{Title: "Old", Status: Domain.StatusPending}
+		require.NoError(t, repo.Create(ctx, task))
+		_, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		_, _, err = repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{})
+		require.NoError(t, err)
+
+		// Act
+		task.Title = "New"
+		require.NoError(t, repo.Update(ctx, task.ID.Hex(), task))
+		found, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		page, _, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{})
+		require.NoError(t, err)
+		require.NoError(t, repo.Delete(ctx, task.ID.Hex()))
+		_, deletedErr := repo.GetByID(ctx, task.ID.Hex())
+
+		// Assert
+		assert.Equal(t, "New", found.Title)
+		assert.Equal(t, "New", page[0].Title)
+		assert.ErrorIs(t, deletedErr, Domain.ErrTaskNotFound)
+	})
+
+	t.Run("Success - a read that loaded before a write does not cache what it loaded", func(t *testing.T) {
+		// Arrange
+		cache := Infrastructure.NewMemoryCache(100, time.Minute)
+		inner := &pausingTaskRepository{TaskRepositoryInterface: memory.NewTaskRepository(), loaded: make(chan struct{}), resume: make(chan struct{})}
+		repo := Repositories.NewCachedTaskRepository(inner, cache)
+		task := &Domain.Task{Title: "Old", Status: Domain.StatusPending}
+		require.NoError(t, repo.Create(ctx, task))
+
+		slowRead := make(chan *Domain.Task)
+		go func() {
+			found, _ := repo.GetByID(ctx, task.ID.Hex())
+			slowRead <- found
+		}()
+		<-inner.loaded
+
+		// Act
+		task.Title = "New"
+		require.NoError(t, repo.Update(ctx, task.ID.Hex(), task))
+		close(inner.resume)
+		stale := <-slowRead
+		found, err := repo.GetByID(ctx, task.ID.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, "Old", stale.Title)
+		assert.Equal(t, "New", found.Title)
+	})
+}
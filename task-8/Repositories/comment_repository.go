@@ -0,0 +1,137 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// CommentRepositoryInterface defines the contract for comment data access
+type CommentRepositoryInterface interface {
+	GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Comment, error)
+	Create(ctx context.Context, comment *Domain.Comment) error
+	DeleteByTaskID(ctx context.Context, taskID string) error
+	DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error
+	RestoreByTaskID(ctx context.Context, taskID string) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error)
+}
+
+// CommentRepository implements CommentRepositoryInterface with MongoDB
+type CommentRepository struct {
+	collection *mongo.Collection
+}
+
+// NewCommentRepository creates a new instance of CommentRepository
+func NewCommentRepository(client *mongo.Client, dbName string) CommentRepositoryInterface {
+	collection := client.Database(dbName).Collection("comments")
+	return &CommentRepository{
+		collection: collection,
+	}
+}
+
+// GetByTaskID returns the comments on a task, oldest first
+func (cr *CommentRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Comment, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := cr.collection.Find(ctx, bson.M{"task_id": objectID, "deleted_at": nil}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []*Domain.Comment{}
+	if err = cursor.All(ctx, &comments); err != nil {
+		return nil, err
+	}
+
+	return comments, nil
+}
+
+// Create creates a new comment in MongoDB
+func (cr *CommentRepository) Create(ctx context.Context, comment *Domain.Comment) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+
+	_, err := cr.collection.InsertOne(ctx, comment)
+	return err
+}
+
+// DeleteByTaskID soft deletes every comment on a task by stamping deleted_at
+func (cr *CommentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	_, err = cr.collection.UpdateMany(ctx, bson.M{"task_id": objectID, "deleted_at": nil}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	return err
+}
+
+// DeleteByTaskIDs soft deletes every comment on the given tasks in a single UpdateMany
+func (cr *CommentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := cr.collection.UpdateMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}, "deleted_at": nil}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	return err
+}
+
+// RestoreByTaskID clears deleted_at on every comment on a task
+func (cr *CommentRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	_, err = cr.collection.UpdateMany(ctx, bson.M{"task_id": objectID, "deleted_at": bson.M{"$ne": nil}}, bson.M{"$unset": bson.M{"deleted_at": ""}})
+	return err
+}
+
+// Purge permanently removes comments that were soft deleted before the given time
+func (cr *CommentRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := cr.collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lte": deletedBefore}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// PurgeByTaskIDs permanently removes every comment on the given tasks, in the trash or not
+func (cr *CommentRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := cr.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
@@ -0,0 +1,138 @@
+package Repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockCommentRepositoryImpl for testing purposes
+type MockCommentRepositoryImpl struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepositoryImpl) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Comment, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepositoryImpl) Create(ctx context.Context, comment *Domain.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepositoryImpl) DeleteByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepositoryImpl) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepositoryImpl) RestoreByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepositoryImpl) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentRepositoryImpl) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	args := m.Called(taskIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestCommentRepository_GetByTaskID(t *testing.T) {
+	t.Run("Success - return comments for task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCommentRepositoryImpl)
+		taskID := primitive.NewObjectID()
+		expectedComments := []*Domain.Comment{
+			{ID: primitive.NewObjectID(), TaskID: taskID, Body: "First"},
+			{ID: primitive.NewObjectID(), TaskID: taskID, Body: "Second"},
+		}
+		mockRepo.On("GetByTaskID", taskID.Hex()).Return(expectedComments, nil)
+
+		// Act
+		comments, err := mockRepo.GetByTaskID(context.Background(), taskID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedComments, comments)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid task ID format", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCommentRepositoryImpl)
+		expectedError := Domain.ErrInvalidTaskID
+		mockRepo.On("GetByTaskID", "invalid-id").Return(nil, expectedError)
+
+		// Act
+		comments, err := mockRepo.GetByTaskID(context.Background(), "invalid-id")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, comments)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCommentRepository_Create(t *testing.T) {
+	t.Run("Success - create comment", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCommentRepositoryImpl)
+		comment := &Domain.Comment{
+			TaskID:   primitive.NewObjectID(),
+			AuthorID: primitive.NewObjectID(),
+			Body:     "Looks good",
+		}
+		mockRepo.On("Create", comment).Return(nil)
+
+		// Act
+		err := mockRepo.Create(context.Background(), comment)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCommentRepository_DeleteAndRestoreByTaskID(t *testing.T) {
+	t.Run("Success - soft delete and restore comments", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockCommentRepositoryImpl)
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("DeleteByTaskID", taskID).Return(nil)
+		mockRepo.On("RestoreByTaskID", taskID).Return(nil)
+
+		// Act
+		deleteErr := mockRepo.DeleteByTaskID(context.Background(), taskID)
+		restoreErr := mockRepo.RestoreByTaskID(context.Background(), taskID)
+
+		// Assert
+		assert.NoError(t, deleteErr)
+		assert.NoError(t, restoreErr)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestCommentRepositoryInterface(t *testing.T) {
+	mockRepo := new(MockCommentRepositoryImpl)
+	var _ CommentRepositoryInterface = mockRepo
+	assert.NotNil(t, mockRepo)
+}
@@ -0,0 +1,93 @@
+package Repositories_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+	"task_manager/Repositories/repositorytest"
+)
+
+// connectTestMongo connects to the server named by MONGODB_TEST_URI, skipping the test when it is unset.
+// Every call gets a database of its own, which is dropped when the test ends.
+func connectTestMongo(t *testing.T) (*mongo.Client, string) {
+	uri := os.Getenv("MONGODB_TEST_URI")
+	if uri == "" {
+		t.Skip("MONGODB_TEST_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatalf("failed to connect to MongoDB: %v", err)
+	}
+	dbName := "taskmanager_test_" + primitive.NewObjectID().Hex()
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		client.Database(dbName).Drop(ctx)
+		client.Disconnect(ctx)
+	})
+	return client, dbName
+}
+
+func TestTaskRepository_Conformance(t *testing.T) {
+	repositorytest.TaskRepository(t, func(t *testing.T) Repositories.TaskRepositoryInterface {
+		client, dbName := connectTestMongo(t)
+		logger := Infrastructure.NewNopLogger()
+		repo := Repositories.NewTaskRepository(client, dbName, "tasks", Infrastructure.NewRetrier(logger), Infrastructure.NewMongoTransactionManager(client, logger))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create task indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	repositorytest.UserRepository(t, func(t *testing.T) Repositories.UserRepositoryInterface {
+		client, dbName := connectTestMongo(t)
+		repo := Repositories.NewUserRepository(client, dbName, Infrastructure.NewRetrier(Infrastructure.NewNopLogger()))
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create user indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestWorkLogRepository_Conformance(t *testing.T) {
+	repositorytest.WorkLogRepository(t, func(t *testing.T) Repositories.WorkLogRepositoryInterface {
+		client, dbName := connectTestMongo(t)
+		repo := Repositories.NewWorkLogRepository(client, dbName)
+		if err := repo.EnsureIndexes(context.Background()); err != nil {
+			t.Fatalf("failed to create work log indexes: %v", err)
+		}
+		return repo
+	})
+}
+
+func TestLockRepository_Conformance(t *testing.T) {
+	repositorytest.LockRepository(t, func(t *testing.T) Repositories.LockRepositoryInterface {
+		client, dbName := connectTestMongo(t)
+		return Repositories.NewLockRepository(client, dbName)
+	})
+}
+
+func TestReportRepository_Conformance(t *testing.T) {
+	repositorytest.ReportRepository(t, func(t *testing.T) (Repositories.ReportRepositoryInterface, Repositories.TaskRepositoryInterface, Repositories.UserRepositoryInterface) {
+		client, dbName := connectTestMongo(t)
+		retrier := Infrastructure.NewRetrier(Infrastructure.NewNopLogger())
+		return Repositories.NewReportRepository(client, dbName, "tasks", retrier),
+			Repositories.NewTaskRepository(client, dbName, "tasks", retrier, Infrastructure.DirectTransactionManager{}),
+			Repositories.NewUserRepository(client, dbName, retrier)
+	})
+}
@@ -0,0 +1,73 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// LockRepositoryInterface defines the contract for lease storage shared by every instance.
+// It satisfies Infrastructure.Locker.
+type LockRepositoryInterface interface {
+	Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error)
+	Release(ctx context.Context, name, holder string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// LockRepository implements LockRepositoryInterface with a MongoDB collection holding one
+// document per lease, keyed by the lease name
+type LockRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLockRepository creates a new instance of LockRepository
+func NewLockRepository(client *mongo.Client, dbName string) LockRepositoryInterface {
+	collection := client.Database(dbName).Collection("locks")
+	return &LockRepository{
+		collection: collection,
+	}
+}
+
+// Acquire takes the lease when it is free or expired, or renews it for its current holder.
+// The upsert only matches a lease holder may take; when another holder has it, the upsert tries
+// to insert a second document with the same _id and fails, which means the lease is taken.
+func (lr *LockRepository) Acquire(ctx context.Context, name, holder string, ttl time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{
+		"_id": name,
+		"$or": bson.A{
+			bson.M{"expires_at": bson.M{"$lte": now}},
+			bson.M{"holder": holder},
+		},
+	}
+	update := bson.M{"$set": bson.M{"holder": holder, "expires_at": now.Add(ttl)}}
+
+	_, err := lr.collection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Release removes the lease if holder still has it
+func (lr *LockRepository) Release(ctx context.Context, name, holder string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := lr.collection.DeleteOne(ctx, bson.M{"_id": name, "holder": holder})
+	return err
+}
+
+// EnsureIndexes has nothing to create, since leases are looked up by _id
+func (lr *LockRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,12 @@
+package Repositories
+
+import (
+	"testing"
+
+	"task_manager/Infrastructure"
+)
+
+func TestLockRepositoryInterface(t *testing.T) {
+	var _ LockRepositoryInterface = &LockRepository{}
+	var _ Infrastructure.Locker = &LockRepository{}
+}
@@ -0,0 +1,93 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// LoginEventRetention is how long login events are kept before MongoDB expires them
+const LoginEventRetention = 90 * 24 * time.Hour
+
+// LoginEventRepositoryInterface defines the contract for login history data access
+type LoginEventRepositoryInterface interface {
+	Create(ctx context.Context, event *Domain.LoginEvent) error
+	GetRecentByUserID(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*Domain.LoginEvent, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// LoginEventRepository implements LoginEventRepositoryInterface with MongoDB
+type LoginEventRepository struct {
+	collection *mongo.Collection
+}
+
+// NewLoginEventRepository creates a new instance of LoginEventRepository
+func NewLoginEventRepository(client *mongo.Client, dbName string) LoginEventRepositoryInterface {
+	collection := client.Database(dbName).Collection("login_events")
+	return &LoginEventRepository{
+		collection: collection,
+	}
+}
+
+// Create stores a successful login
+func (lr *LoginEventRepository) Create(ctx context.Context, event *Domain.LoginEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	event.ID = primitive.NewObjectID()
+
+	_, err := lr.collection.InsertOne(ctx, event)
+	return err
+}
+
+// GetRecentByUserID returns a user's most recent logins, newest first
+func (lr *LoginEventRepository) GetRecentByUserID(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*Domain.LoginEvent, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(limit)
+
+	cursor, err := lr.collection.Find(ctx, bson.M{"user_id": userID}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	events := []*Domain.LoginEvent{}
+	if err = cursor.All(ctx, &events); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// EnsureIndexes creates the per-user listing index and the TTL index that expires old logins
+func (lr *LoginEventRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := lr.collection.Indexes().CreateMany(ctx, loginEventIndexes())
+	return err
+}
+
+// loginEventIndexes lists the indexes maintained on the login_events collection
+func loginEventIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("user_id_1_timestamp_-1"),
+		},
+		{
+			Keys:    bson.D{{Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("timestamp_ttl").SetExpireAfterSeconds(int32(LoginEventRetention / time.Second)),
+		},
+	}
+}
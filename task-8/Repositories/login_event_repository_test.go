@@ -0,0 +1,26 @@
+package Repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLoginEventIndexes(t *testing.T) {
+	t.Run("Listing index and TTL index", func(t *testing.T) {
+		// Act
+		indexes := loginEventIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 2)
+		assert.Equal(t, bson.D{{Key: "user_id", Value: 1}, {Key: "timestamp", Value: -1}}, indexes[0].Keys)
+		assert.Equal(t, bson.D{{Key: "timestamp", Value: 1}}, indexes[1].Keys)
+		assert.Equal(t, int32(LoginEventRetention/time.Second), *indexes[1].Options.ExpireAfterSeconds)
+	})
+}
+
+func TestLoginEventRepositoryInterface(t *testing.T) {
+	var _ LoginEventRepositoryInterface = &LoginEventRepository{}
+}
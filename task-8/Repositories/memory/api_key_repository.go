@@ -0,0 +1,113 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// APIKeyRepository implements Repositories.APIKeyRepositoryInterface in process memory
+type APIKeyRepository struct {
+	mu   sync.RWMutex
+	keys map[primitive.ObjectID]*Domain.APIKey
+}
+
+// NewAPIKeyRepository creates a new instance of APIKeyRepository
+func NewAPIKeyRepository() Repositories.APIKeyRepositoryInterface {
+	return &APIKeyRepository{
+		keys: make(map[primitive.ObjectID]*Domain.APIKey),
+	}
+}
+
+// Create stores an API key
+func (ar *APIKeyRepository) Create(ctx context.Context, key *Domain.APIKey) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	key.ID = primitive.NewObjectID()
+	key.CreatedAt = time.Now()
+
+	ar.keys[key.ID] = copyAPIKey(key)
+	return nil
+}
+
+// GetByHash returns the API key stored under a hash, revoked or not
+func (ar *APIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*Domain.APIKey, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	for _, key := range ar.keys {
+		if key.KeyHash == keyHash {
+			return copyAPIKey(key), nil
+		}
+	}
+	return nil, Domain.ErrAPIKeyNotFound
+}
+
+// GetAll returns every API key, newest first
+func (ar *APIKeyRepository) GetAll(ctx context.Context) ([]*Domain.APIKey, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	keys := make([]*Domain.APIKey, 0, len(ar.keys))
+	for _, key := range ar.keys {
+		keys = append(keys, copyAPIKey(key))
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return idLess(b.ID, a.ID)
+	})
+	return keys, nil
+}
+
+// Revoke marks an API key as revoked and returns it. Revoking a revoked key is not an error.
+func (ar *APIKeyRepository) Revoke(ctx context.Context, id string) (*Domain.APIKey, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidAPIKeyID
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	key, ok := ar.keys[objectID]
+	if !ok {
+		return nil, Domain.ErrAPIKeyNotFound
+	}
+
+	key.Revoked = true
+	return copyAPIKey(key), nil
+}
+
+// UpdateLastUsed records when an API key was last used
+func (ar *APIKeyRepository) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	if key, ok := ar.keys[id]; ok {
+		key.LastUsedAt = copyTime(&at)
+	}
+	return nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (ar *APIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// copyAPIKey returns a copy of a key that shares no memory with the stored record
+func copyAPIKey(key *Domain.APIKey) *Domain.APIKey {
+	copied := *key
+	copied.LastUsedAt = copyTime(key.LastUsedAt)
+	return &copied
+}
@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// AttachmentRepository implements Repositories.AttachmentRepositoryInterface in process memory
+type AttachmentRepository struct {
+	mu          sync.RWMutex
+	attachments map[primitive.ObjectID]*Domain.Attachment
+}
+
+// NewAttachmentRepository creates a new instance of AttachmentRepository
+func NewAttachmentRepository() Repositories.AttachmentRepositoryInterface {
+	return &AttachmentRepository{
+		attachments: make(map[primitive.ObjectID]*Domain.Attachment),
+	}
+}
+
+// GetByTaskID returns the attachments on a task, oldest first
+func (ar *AttachmentRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Attachment, error) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	attachments := []*Domain.Attachment{}
+	for _, attachment := range ar.attachments {
+		if attachment.TaskID == objectID && attachment.DeletedAt == nil {
+			attachments = append(attachments, copyAttachment(attachment))
+		}
+	}
+
+	sort.Slice(attachments, func(i, j int) bool {
+		a, b := attachments[i], attachments[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return idLess(a.ID, b.ID)
+	})
+	return attachments, nil
+}
+
+// GetByID returns an attachment on a task
+func (ar *AttachmentRepository) GetByID(ctx context.Context, taskID, id string) (*Domain.Attachment, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	attachment, err := ar.find(taskID, id)
+	if err != nil {
+		return nil, err
+	}
+	return copyAttachment(attachment), nil
+}
+
+// Create stores a new attachment record
+func (ar *AttachmentRepository) Create(ctx context.Context, attachment *Domain.Attachment) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	attachment.ID = primitive.NewObjectID()
+	attachment.CreatedAt = time.Now()
+
+	ar.attachments[attachment.ID] = copyAttachment(attachment)
+	return nil
+}
+
+// Delete permanently removes an attachment record from a task
+func (ar *AttachmentRepository) Delete(ctx context.Context, taskID, id string) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	attachment, err := ar.find(taskID, id)
+	if err != nil {
+		return err
+	}
+	delete(ar.attachments, attachment.ID)
+	return nil
+}
+
+// DeleteByTaskID soft deletes every attachment on a task by stamping deleted_at
+func (ar *AttachmentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return ar.DeleteByTaskIDs(ctx, []primitive.ObjectID{objectID})
+}
+
+// DeleteByTaskIDs soft deletes every attachment on the given tasks
+func (ar *AttachmentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	tasks := taskIDSet(taskIDs)
+	now := time.Now()
+	for _, attachment := range ar.attachments {
+		if tasks[attachment.TaskID] && attachment.DeletedAt == nil {
+			attachment.DeletedAt = copyTime(&now)
+		}
+	}
+	return nil
+}
+
+// RestoreByTaskID clears deleted_at on every attachment on a task
+func (ar *AttachmentRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	for _, attachment := range ar.attachments {
+		if attachment.TaskID == objectID {
+			attachment.DeletedAt = nil
+		}
+	}
+	return nil
+}
+
+// Purge permanently removes attachment records that were soft deleted before the given time
+func (ar *AttachmentRepository) Purge(ctx context.Context, deletedBefore time.Time) ([]*Domain.Attachment, error) {
+	return ar.purge(func(attachment *Domain.Attachment) bool {
+		return attachment.DeletedAt != nil && !attachment.DeletedAt.After(deletedBefore)
+	}), nil
+}
+
+// PurgeByTaskIDs permanently removes every attachment record on the given tasks, in the trash or not
+func (ar *AttachmentRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) ([]*Domain.Attachment, error) {
+	tasks := taskIDSet(taskIDs)
+	return ar.purge(func(attachment *Domain.Attachment) bool {
+		return tasks[attachment.TaskID]
+	}), nil
+}
+
+// purge removes and returns the records that match
+func (ar *AttachmentRepository) purge(match func(attachment *Domain.Attachment) bool) []*Domain.Attachment {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	purged := []*Domain.Attachment{}
+	for id, attachment := range ar.attachments {
+		if match(attachment) {
+			delete(ar.attachments, id)
+			purged = append(purged, attachment)
+		}
+	}
+	return purged
+}
+
+// find returns the stored attachment on a task that is not in the trash; the caller holds the lock
+func (ar *AttachmentRepository) find(taskID, id string) (*Domain.Attachment, error) {
+	taskObjectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidAttachmentID
+	}
+
+	attachment, ok := ar.attachments[objectID]
+	if !ok || attachment.TaskID != taskObjectID || attachment.DeletedAt != nil {
+		return nil, Domain.ErrAttachmentNotFound
+	}
+	return attachment, nil
+}
+
+// taskIDSet indexes task IDs for lookups
+func taskIDSet(taskIDs []primitive.ObjectID) map[primitive.ObjectID]bool {
+	tasks := make(map[primitive.ObjectID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		tasks[id] = true
+	}
+	return tasks
+}
+
+// copyAttachment returns a copy that shares no memory with the stored record
+func copyAttachment(attachment *Domain.Attachment) *Domain.Attachment {
+	copied := *attachment
+	copied.DeletedAt = copyTime(attachment.DeletedAt)
+	return &copied
+}
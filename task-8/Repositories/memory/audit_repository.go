@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// AuditRepository implements Repositories.AuditRepositoryInterface in process memory
+type AuditRepository struct {
+	mu      sync.RWMutex
+	entries []*Domain.AuditEntry
+}
+
+// NewAuditRepository creates a new instance of AuditRepository
+func NewAuditRepository() Repositories.AuditRepositoryInterface {
+	return &AuditRepository{}
+}
+
+// Create appends an entry to the audit log
+func (ar *AuditRepository) Create(ctx context.Context, entry *Domain.AuditEntry) error {
+	ar.mu.Lock()
+	defer ar.mu.Unlock()
+
+	entry.ID = primitive.NewObjectID()
+	entry.Timestamp = time.Now()
+
+	copied := *entry
+	ar.entries = append(ar.entries, &copied)
+	return nil
+}
+
+// GetAll returns one page of audit entries matching the filter, newest first, with the total number of matches
+func (ar *AuditRepository) GetAll(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error) {
+	ar.mu.RLock()
+	defer ar.mu.RUnlock()
+
+	var matches []*Domain.AuditEntry
+	for _, entry := range ar.entries {
+		if (filter.ActorID == "" || entry.ActorID == filter.ActorID) && (filter.Entity == "" || entry.Entity == filter.Entity) {
+			matches = append(matches, entry)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return idLess(b.ID, a.ID)
+	})
+
+	start, end := pageBounds(len(matches), pagination)
+	entries := make([]*Domain.AuditEntry, 0, end-start)
+	for _, entry := range matches[start:end] {
+		copied := *entry
+		entries = append(entries, &copied)
+	}
+	return entries, int64(len(matches)), nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (ar *AuditRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,150 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// CommentRepository implements Repositories.CommentRepositoryInterface in process memory
+type CommentRepository struct {
+	mu       sync.RWMutex
+	comments map[primitive.ObjectID]*Domain.Comment
+}
+
+// NewCommentRepository creates a new instance of CommentRepository
+func NewCommentRepository() Repositories.CommentRepositoryInterface {
+	return &CommentRepository{
+		comments: make(map[primitive.ObjectID]*Domain.Comment),
+	}
+}
+
+// GetByTaskID returns the comments on a task, oldest first
+func (cr *CommentRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Comment, error) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+
+	comments := []*Domain.Comment{}
+	for _, comment := range cr.comments {
+		if comment.TaskID == objectID && comment.DeletedAt == nil {
+			copied := *comment
+			comments = append(comments, &copied)
+		}
+	}
+
+	sort.Slice(comments, func(i, j int) bool {
+		a, b := comments[i], comments[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return idLess(a.ID, b.ID)
+	})
+	return comments, nil
+}
+
+// Create stores a new comment
+func (cr *CommentRepository) Create(ctx context.Context, comment *Domain.Comment) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	comment.ID = primitive.NewObjectID()
+	comment.CreatedAt = time.Now()
+
+	copied := *comment
+	copied.DeletedAt = copyTime(comment.DeletedAt)
+	cr.comments[comment.ID] = &copied
+	return nil
+}
+
+// DeleteByTaskID soft deletes every comment on a task by stamping deleted_at
+func (cr *CommentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return cr.DeleteByTaskIDs(ctx, []primitive.ObjectID{objectID})
+}
+
+// DeleteByTaskIDs soft deletes every comment on the given tasks
+func (cr *CommentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	tasks := make(map[primitive.ObjectID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		tasks[id] = true
+	}
+
+	now := time.Now()
+	for _, comment := range cr.comments {
+		if tasks[comment.TaskID] && comment.DeletedAt == nil {
+			comment.DeletedAt = copyTime(&now)
+		}
+	}
+	return nil
+}
+
+// RestoreByTaskID clears deleted_at on every comment on a task
+func (cr *CommentRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	for _, comment := range cr.comments {
+		if comment.TaskID == objectID {
+			comment.DeletedAt = nil
+		}
+	}
+	return nil
+}
+
+// Purge permanently removes comments that were soft deleted before the given time
+func (cr *CommentRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	var purged int64
+	for id, comment := range cr.comments {
+		if comment.DeletedAt != nil && !comment.DeletedAt.After(deletedBefore) {
+			delete(cr.comments, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// PurgeByTaskIDs permanently removes every comment on the given tasks, in the trash or not
+func (cr *CommentRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	tasks := make(map[primitive.ObjectID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		tasks[id] = true
+	}
+
+	var purged int64
+	for id, comment := range cr.comments {
+		if tasks[comment.TaskID] {
+			delete(cr.comments, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,25 @@
+package memory
+
+import (
+	"context"
+
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// LockRepository implements Repositories.LockRepositoryInterface with the in-memory locker
+type LockRepository struct {
+	*Infrastructure.MemoryLocker
+}
+
+// NewLockRepository creates a new instance of LockRepository
+func NewLockRepository() Repositories.LockRepositoryInterface {
+	return &LockRepository{
+		MemoryLocker: Infrastructure.NewMemoryLocker(),
+	}
+}
+
+// EnsureIndexes has nothing to create in memory
+func (lr *LockRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
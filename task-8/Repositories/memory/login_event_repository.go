@@ -0,0 +1,66 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// LoginEventRepository implements Repositories.LoginEventRepositoryInterface in process memory.
+// Events are kept for the life of the process rather than for Repositories.LoginEventRetention.
+type LoginEventRepository struct {
+	mu     sync.RWMutex
+	events []*Domain.LoginEvent
+}
+
+// NewLoginEventRepository creates a new instance of LoginEventRepository
+func NewLoginEventRepository() Repositories.LoginEventRepositoryInterface {
+	return &LoginEventRepository{}
+}
+
+// Create stores a successful login
+func (lr *LoginEventRepository) Create(ctx context.Context, event *Domain.LoginEvent) error {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+
+	event.ID = primitive.NewObjectID()
+
+	copied := *event
+	lr.events = append(lr.events, &copied)
+	return nil
+}
+
+// GetRecentByUserID returns a user's most recent logins, newest first
+func (lr *LoginEventRepository) GetRecentByUserID(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*Domain.LoginEvent, error) {
+	lr.mu.RLock()
+	defer lr.mu.RUnlock()
+
+	events := []*Domain.LoginEvent{}
+	for _, event := range lr.events {
+		if event.UserID == userID {
+			copied := *event
+			events = append(events, &copied)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		a, b := events[i], events[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return idLess(b.ID, a.ID)
+	})
+
+	start, end := pageBounds(len(events), Domain.Pagination{Limit: limit})
+	return events[start:end], nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (lr *LoginEventRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
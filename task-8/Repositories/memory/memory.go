@@ -0,0 +1,61 @@
+// Package memory implements the repository interfaces with maps guarded by mutexes, so the API
+// can run without MongoDB for demos and tests can exercise real repository behavior. The
+// implementations follow the MongoDB repositories: they generate the same IDs, return the same
+// errors and enforce the same unique fields. Data lives only as long as the process, nothing
+// expires the way TTL indexes expire MongoDB documents, and there are no transactions.
+package memory
+
+import (
+	"bytes"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// idLess orders ObjectIDs the way MongoDB sorts them, which is creation order
+func idLess(a, b primitive.ObjectID) bool {
+	return bytes.Compare(a[:], b[:]) < 0
+}
+
+// pageBounds returns the slice bounds of a pagination window over n sorted items.
+// A zero limit means no limit, as in MongoDB.
+func pageBounds(n int, pagination Domain.Pagination) (int, int) {
+	start := int(pagination.Offset)
+	if start > n || pagination.Offset < 0 {
+		start = n
+	}
+	end := n
+	if pagination.Limit > 0 && int64(n-start) > pagination.Limit {
+		end = start + int(pagination.Limit)
+	}
+	return start, end
+}
+
+// copyTime returns a copy of an optional timestamp, so stored records never share memory with callers
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+// copyInt returns a copy of an optional integer
+func copyInt(n *int) *int {
+	if n == nil {
+		return nil
+	}
+	copied := *n
+	return &copied
+}
+
+// copyObjectID returns a copy of an optional ObjectID
+func copyObjectID(id *primitive.ObjectID) *primitive.ObjectID {
+	if id == nil {
+		return nil
+	}
+	copied := *id
+	return &copied
+}
@@ -0,0 +1,266 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+	"task_manager/Repositories/repositorytest"
+)
+
+func TestTaskRepository_Conformance(t *testing.T) {
+	repositorytest.TaskRepository(t, func(t *testing.T) Repositories.TaskRepositoryInterface {
+		return NewTaskRepository()
+	})
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	repositorytest.UserRepository(t, func(t *testing.T) Repositories.UserRepositoryInterface {
+		return NewUserRepository()
+	})
+}
+
+func TestLockRepository_Conformance(t *testing.T) {
+	repositorytest.LockRepository(t, func(t *testing.T) Repositories.LockRepositoryInterface {
+		return NewLockRepository()
+	})
+}
+
+func TestReportRepository_Conformance(t *testing.T) {
+	repositorytest.ReportRepository(t, func(t *testing.T) (Repositories.ReportRepositoryInterface, Repositories.TaskRepositoryInterface, Repositories.UserRepositoryInterface) {
+		tasks, users := NewTaskRepository(), NewUserRepository()
+		return NewReportRepository(tasks, users), tasks, users
+	})
+}
+
+func TestWorkLogRepository_Conformance(t *testing.T) {
+	repositorytest.WorkLogRepository(t, func(t *testing.T) Repositories.WorkLogRepositoryInterface {
+		return NewWorkLogRepository()
+	})
+}
+
+func TestTaskRepository_ReturnsCopies(t *testing.T) {
+	// Arrange
+	ctx := context.Background()
+	repo := NewTaskRepository()
+	task := &Domain.Task{Title: "Draft", Status: Domain.StatusPending, Tags: []string{"work"}}
+	assert.NoError(t, repo.Create(ctx, task))
+
+	// Act
+	task.Tags[0] = "changed"
+	found, _ := repo.GetByID(ctx, task.ID.Hex())
+	found.Title = "changed"
+
+	// Assert
+	stored, _ := repo.GetByID(ctx, task.ID.Hex())
+	assert.Equal(t, "Draft", stored.Title)
+	assert.Equal(t, []string{"work"}, stored.Tags)
+}
+
+func TestCommentRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewCommentRepository()
+	taskID := primitive.NewObjectID()
+	assert.NoError(t, repo.Create(ctx, &Domain.Comment{TaskID: taskID, Body: "first"}))
+	assert.NoError(t, repo.Create(ctx, &Domain.Comment{TaskID: taskID, Body: "second"}))
+
+	comments, err := repo.GetByTaskID(ctx, taskID.Hex())
+	assert.NoError(t, err)
+	assert.Len(t, comments, 2)
+	assert.Equal(t, "first", comments[0].Body)
+
+	assert.NoError(t, repo.DeleteByTaskID(ctx, taskID.Hex()))
+	comments, _ = repo.GetByTaskID(ctx, taskID.Hex())
+	assert.Empty(t, comments)
+
+	assert.NoError(t, repo.RestoreByTaskID(ctx, taskID.Hex()))
+	comments, _ = repo.GetByTaskID(ctx, taskID.Hex())
+	assert.Len(t, comments, 2)
+
+	_, err = repo.GetByTaskID(ctx, "invalid-id")
+	assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+}
+
+func TestAttachmentRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAttachmentRepository()
+	taskID, otherTaskID := primitive.NewObjectID(), primitive.NewObjectID()
+	first := &Domain.Attachment{TaskID: taskID, Filename: "spec.pdf", StorageKey: "a"}
+	assert.NoError(t, repo.Create(ctx, first))
+	assert.NoError(t, repo.Create(ctx, &Domain.Attachment{TaskID: taskID, Filename: "screenshot.png", StorageKey: "b"}))
+	assert.NoError(t, repo.Create(ctx, &Domain.Attachment{TaskID: otherTaskID, Filename: "notes.txt", StorageKey: "c"}))
+
+	attachments, err := repo.GetByTaskID(ctx, taskID.Hex())
+	assert.NoError(t, err)
+	assert.Len(t, attachments, 2)
+	assert.Equal(t, "spec.pdf", attachments[0].Filename)
+
+	stored, err := repo.GetByID(ctx, taskID.Hex(), first.ID.Hex())
+	assert.NoError(t, err)
+	assert.Equal(t, "a", stored.StorageKey)
+	_, err = repo.GetByID(ctx, otherTaskID.Hex(), first.ID.Hex())
+	assert.ErrorIs(t, err, Domain.ErrAttachmentNotFound)
+
+	assert.NoError(t, repo.DeleteByTaskID(ctx, taskID.Hex()))
+	attachments, _ = repo.GetByTaskID(ctx, taskID.Hex())
+	assert.Empty(t, attachments)
+	assert.NoError(t, repo.RestoreByTaskID(ctx, taskID.Hex()))
+	attachments, _ = repo.GetByTaskID(ctx, taskID.Hex())
+	assert.Len(t, attachments, 2)
+
+	assert.NoError(t, repo.Delete(ctx, taskID.Hex(), first.ID.Hex()))
+	assert.ErrorIs(t, repo.Delete(ctx, taskID.Hex(), first.ID.Hex()), Domain.ErrAttachmentNotFound)
+
+	assert.NoError(t, repo.DeleteByTaskID(ctx, taskID.Hex()))
+	purged, err := repo.Purge(ctx, time.Now())
+	assert.NoError(t, err)
+	assert.Len(t, purged, 1)
+	assert.Equal(t, "b", purged[0].StorageKey)
+
+	purged, err = repo.PurgeByTaskIDs(ctx, []primitive.ObjectID{otherTaskID})
+	assert.NoError(t, err)
+	assert.Len(t, purged, 1)
+	attachments, _ = repo.GetByTaskID(ctx, otherTaskID.Hex())
+	assert.Empty(t, attachments)
+
+	_, err = repo.GetByID(ctx, taskID.Hex(), "invalid-id")
+	assert.ErrorIs(t, err, Domain.ErrInvalidAttachmentID)
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	ctx := context.Background()
+	repo := NewRefreshTokenRepository()
+	token := &Domain.RefreshToken{UserID: primitive.NewObjectID(), FamilyID: "family", TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, repo.Create(ctx, token))
+
+	assert.NoError(t, repo.Revoke(ctx, token.ID))
+	assert.EqualError(t, repo.Revoke(ctx, token.ID), "refresh token already revoked")
+
+	stored, err := repo.GetByHash(ctx, "hash")
+	assert.NoError(t, err)
+	assert.NotNil(t, stored.RevokedAt)
+	_, err = repo.GetByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, Domain.ErrRefreshTokenNotFound)
+}
+
+func TestPasswordResetRepository_MarkUsed(t *testing.T) {
+	ctx := context.Background()
+	repo := NewPasswordResetRepository()
+	token := &Domain.PasswordResetToken{UserID: primitive.NewObjectID(), TokenHash: "hash", ExpiresAt: time.Now().Add(time.Hour)}
+	assert.NoError(t, repo.Create(ctx, token))
+
+	assert.NoError(t, repo.MarkUsed(ctx, token.ID))
+	assert.EqualError(t, repo.MarkUsed(ctx, token.ID), "reset token already used")
+	_, err := repo.GetByHash(ctx, "unknown")
+	assert.ErrorIs(t, err, Domain.ErrResetTokenNotFound)
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAPIKeyRepository()
+	first := &Domain.APIKey{Name: "first", KeyHash: "first-hash", Role: Domain.RoleUser}
+	second := &Domain.APIKey{Name: "second", KeyHash: "second-hash", Role: Domain.RoleUser}
+	assert.NoError(t, repo.Create(ctx, first))
+	assert.NoError(t, repo.Create(ctx, second))
+
+	keys, err := repo.GetAll(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "second", keys[0].Name)
+
+	revoked, err := repo.Revoke(ctx, first.ID.Hex())
+	assert.NoError(t, err)
+	assert.True(t, revoked.Revoked)
+	_, err = repo.Revoke(ctx, primitive.NewObjectID().Hex())
+	assert.ErrorIs(t, err, Domain.ErrAPIKeyNotFound)
+	_, err = repo.Revoke(ctx, "invalid-id")
+	assert.ErrorIs(t, err, Domain.ErrInvalidAPIKeyID)
+}
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookRepository()
+	created := &Domain.Webhook{URL: "https://ci.example.com/hook", Events: []string{Domain.EventTaskCreated}, Active: true}
+	updated := &Domain.Webhook{URL: "https://chat.example.com/hook", Events: []string{Domain.EventTaskUpdated, Domain.EventTaskCreated}, Active: true}
+	assert.NoError(t, repo.Create(ctx, created))
+	assert.NoError(t, repo.Create(ctx, updated))
+
+	subscribers, err := repo.GetActiveByEvent(ctx, Domain.EventTaskCreated)
+	assert.NoError(t, err)
+	assert.Len(t, subscribers, 2)
+	assert.Equal(t, updated.ID, subscribers[0].ID)
+
+	// Inactive webhooks are not sent events
+	created.Active = false
+	assert.NoError(t, repo.Update(ctx, created))
+	subscribers, _ = repo.GetActiveByEvent(ctx, Domain.EventTaskCreated)
+	assert.Len(t, subscribers, 1)
+	subscribers, _ = repo.GetActiveByEvent(ctx, Domain.EventTaskDeleted)
+	assert.Empty(t, subscribers)
+
+	assert.NoError(t, repo.Delete(ctx, created.ID.Hex()))
+	_, err = repo.GetByID(ctx, created.ID.Hex())
+	assert.ErrorIs(t, err, Domain.ErrWebhookNotFound)
+	assert.ErrorIs(t, repo.Delete(ctx, created.ID.Hex()), Domain.ErrWebhookNotFound)
+	assert.ErrorIs(t, repo.Update(ctx, created), Domain.ErrWebhookNotFound)
+	_, err = repo.GetByID(ctx, "invalid-id")
+	assert.ErrorIs(t, err, Domain.ErrInvalidWebhookID)
+}
+
+func TestWebhookDeliveryRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookDeliveryRepository()
+	webhookID, otherID := primitive.NewObjectID(), primitive.NewObjectID()
+	assert.NoError(t, repo.Create(ctx, &Domain.WebhookDelivery{WebhookID: webhookID, EventID: "first"}))
+	assert.NoError(t, repo.Create(ctx, &Domain.WebhookDelivery{WebhookID: webhookID, EventID: "second"}))
+	assert.NoError(t, repo.Create(ctx, &Domain.WebhookDelivery{WebhookID: otherID, EventID: "other"}))
+
+	deliveries, total, err := repo.GetByWebhookID(ctx, webhookID.Hex(), Domain.Pagination{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "second", deliveries[0].EventID)
+
+	assert.NoError(t, repo.DeleteByWebhookID(ctx, webhookID.Hex()))
+	_, total, _ = repo.GetByWebhookID(ctx, webhookID.Hex(), Domain.Pagination{})
+	assert.Zero(t, total)
+	_, total, _ = repo.GetByWebhookID(ctx, otherID.Hex(), Domain.Pagination{})
+	assert.Equal(t, int64(1), total)
+}
+
+func TestAuditRepository_GetAll(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAuditRepository()
+	assert.NoError(t, repo.Create(ctx, &Domain.AuditEntry{ActorID: "alice", Entity: "task", Action: "first"}))
+	assert.NoError(t, repo.Create(ctx, &Domain.AuditEntry{ActorID: "bob", Entity: "task", Action: "second"}))
+
+	entries, total, err := repo.GetAll(ctx, Domain.AuditFilter{Entity: "task"}, Domain.Pagination{Limit: 1})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, "second", entries[0].Action)
+
+	entries, total, _ = repo.GetAll(ctx, Domain.AuditFilter{ActorID: "alice"}, Domain.Pagination{})
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, "first", entries[0].Action)
+}
+
+func TestRepositoryInterfaces(t *testing.T) {
+	var _ Repositories.TaskRepositoryInterface = &TaskRepository{}
+	var _ Repositories.UserRepositoryInterface = &UserRepository{}
+	var _ Repositories.AuditRepositoryInterface = &AuditRepository{}
+	var _ Repositories.CommentRepositoryInterface = &CommentRepository{}
+	var _ Repositories.AttachmentRepositoryInterface = &AttachmentRepository{}
+	var _ Repositories.LoginEventRepositoryInterface = &LoginEventRepository{}
+	var _ Repositories.PasswordResetRepositoryInterface = &PasswordResetRepository{}
+	var _ Repositories.RefreshTokenRepositoryInterface = &RefreshTokenRepository{}
+	var _ Repositories.RevisionRepositoryInterface = &RevisionRepository{}
+	var _ Repositories.APIKeyRepositoryInterface = &APIKeyRepository{}
+	var _ Repositories.TokenBlacklistRepositoryInterface = &TokenBlacklistRepository{}
+	var _ Repositories.WebhookRepositoryInterface = &WebhookRepository{}
+	var _ Repositories.WebhookDeliveryRepositoryInterface = &WebhookDeliveryRepository{}
+	var _ Repositories.LockRepositoryInterface = &LockRepository{}
+	var _ Repositories.ReportRepositoryInterface = &ReportRepository{}
+}
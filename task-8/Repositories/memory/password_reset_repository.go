@@ -0,0 +1,75 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// PasswordResetRepository implements Repositories.PasswordResetRepositoryInterface in process memory
+type PasswordResetRepository struct {
+	mu     sync.RWMutex
+	tokens map[primitive.ObjectID]*Domain.PasswordResetToken
+}
+
+// NewPasswordResetRepository creates a new instance of PasswordResetRepository
+func NewPasswordResetRepository() Repositories.PasswordResetRepositoryInterface {
+	return &PasswordResetRepository{
+		tokens: make(map[primitive.ObjectID]*Domain.PasswordResetToken),
+	}
+}
+
+// Create stores a password reset token
+func (pr *PasswordResetRepository) Create(ctx context.Context, token *Domain.PasswordResetToken) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	copied := *token
+	copied.UsedAt = copyTime(token.UsedAt)
+	pr.tokens[token.ID] = &copied
+	return nil
+}
+
+// GetByHash returns the reset token stored under a hash, used or not
+func (pr *PasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*Domain.PasswordResetToken, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	for _, token := range pr.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			copied.UsedAt = copyTime(token.UsedAt)
+			return &copied, nil
+		}
+	}
+	return nil, Domain.ErrResetTokenNotFound
+}
+
+// MarkUsed marks a reset token as used. It fails if the token was already used,
+// so two requests racing with the same token cannot both reset the password.
+func (pr *PasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	token, ok := pr.tokens[id]
+	if !ok || token.UsedAt != nil {
+		return Domain.ErrResetTokenUsed
+	}
+
+	now := time.Now()
+	token.UsedAt = &now
+	return nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (pr *PasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
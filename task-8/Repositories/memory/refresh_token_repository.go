@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// RefreshTokenRepository implements Repositories.RefreshTokenRepositoryInterface in process memory
+type RefreshTokenRepository struct {
+	mu     sync.RWMutex
+	tokens map[primitive.ObjectID]*Domain.RefreshToken
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository
+func NewRefreshTokenRepository() Repositories.RefreshTokenRepositoryInterface {
+	return &RefreshTokenRepository{
+		tokens: make(map[primitive.ObjectID]*Domain.RefreshToken),
+	}
+}
+
+// Create stores a refresh token
+func (rr *RefreshTokenRepository) Create(ctx context.Context, token *Domain.RefreshToken) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	copied := *token
+	copied.RevokedAt = copyTime(token.RevokedAt)
+	rr.tokens[token.ID] = &copied
+	return nil
+}
+
+// GetByHash returns the refresh token stored under a hash, revoked or not
+func (rr *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*Domain.RefreshToken, error) {
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	for _, token := range rr.tokens {
+		if token.TokenHash == tokenHash {
+			copied := *token
+			copied.RevokedAt = copyTime(token.RevokedAt)
+			return &copied, nil
+		}
+	}
+	return nil, Domain.ErrRefreshTokenNotFound
+}
+
+// Revoke marks a refresh token as revoked. It fails if the token was already revoked,
+// so two requests racing to rotate the same token cannot both succeed.
+func (rr *RefreshTokenRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	token, ok := rr.tokens[id]
+	if !ok || token.RevokedAt != nil {
+		return Domain.ErrRefreshTokenRevoked
+	}
+
+	now := time.Now()
+	token.RevokedAt = &now
+	return nil
+}
+
+// RevokeFamily revokes every outstanding refresh token of a family
+func (rr *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	rr.revokeWhere(func(token *Domain.RefreshToken) bool { return token.FamilyID == familyID })
+	return nil
+}
+
+// RevokeAllForUser revokes every outstanding refresh token of a user, ending all their logins
+func (rr *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	rr.revokeWhere(func(token *Domain.RefreshToken) bool { return token.UserID == userID })
+	return nil
+}
+
+// revokeWhere revokes every outstanding refresh token the predicate selects
+func (rr *RefreshTokenRepository) revokeWhere(selected func(token *Domain.RefreshToken) bool) {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	now := time.Now()
+	for _, token := range rr.tokens {
+		if token.RevokedAt == nil && selected(token) {
+			token.RevokedAt = copyTime(&now)
+		}
+	}
+}
+
+// EnsureIndexes has nothing to create in memory
+func (rr *RefreshTokenRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
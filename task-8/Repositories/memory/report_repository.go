@@ -0,0 +1,83 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// ReportRepository implements Repositories.ReportRepositoryInterface over the repositories
+// that hold the data being reported on
+type ReportRepository struct {
+	tasks Repositories.TaskRepositoryInterface
+	users Repositories.UserRepositoryInterface
+}
+
+// NewReportRepository creates a new instance of ReportRepository reading tasks and users
+func NewReportRepository(tasks Repositories.TaskRepositoryInterface, users Repositories.UserRepositoryInterface) Repositories.ReportRepositoryInterface {
+	return &ReportRepository{
+		tasks: tasks,
+		users: users,
+	}
+}
+
+// GetTasksByUser counts every user's active tasks by status, users without tasks included.
+// A non-zero since only counts tasks created at or after it, and tasks due before now are overdue.
+// Rows are ordered by open tasks, most first, then by username.
+func (rr *ReportRepository) GetTasksByUser(ctx context.Context, since, now time.Time) ([]*Domain.UserTaskReport, error) {
+	users, _, err := rr.users.GetAll(ctx, Domain.UserFilter{}, Domain.Pagination{})
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]*Domain.UserTaskReport, 0, len(users))
+	byUser := make(map[primitive.ObjectID]*Domain.UserTaskReport, len(users))
+	for _, user := range users {
+		report := &Domain.UserTaskReport{UserID: user.ID, Username: user.Username}
+		reports = append(reports, report)
+		byUser[user.ID] = report
+	}
+
+	// Archived tasks still count, as they do in the MongoDB report
+	err = rr.tasks.Stream(ctx, Domain.TaskFilter{IncludeArchived: true}, func(task *Domain.Task) error {
+		report, ok := byUser[task.OwnerID()]
+		if !ok || task.CreatedAt.Before(since) {
+			return nil
+		}
+
+		switch task.Status {
+		case Domain.StatusPending:
+			report.Pending++
+		case Domain.StatusInProgress:
+			report.InProgress++
+		case Domain.StatusCompleted:
+			report.Completed++
+		}
+		if task.Status != Domain.StatusCompleted && !task.DueDate.IsZero() && task.DueDate.Before(now) {
+			report.Overdue++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, report := range reports {
+		report.Open = report.Pending + report.InProgress
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		if reports[i].Open != reports[j].Open {
+			return reports[i].Open > reports[j].Open
+		}
+		if reports[i].Username != reports[j].Username {
+			return reports[i].Username < reports[j].Username
+		}
+		return idLess(reports[i].UserID, reports[j].UserID)
+	})
+	return reports, nil
+}
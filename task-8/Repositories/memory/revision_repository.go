@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// RevisionRepository implements Repositories.RevisionRepositoryInterface in process memory.
+// Revisions are kept for the life of the process rather than for Repositories.RevisionRetention.
+type RevisionRepository struct {
+	mu        sync.RWMutex
+	revisions []*Domain.TaskRevision
+}
+
+// NewRevisionRepository creates a new instance of RevisionRepository
+func NewRevisionRepository() Repositories.RevisionRepositoryInterface {
+	return &RevisionRepository{}
+}
+
+// Create stores a revision of a task
+func (rr *RevisionRepository) Create(ctx context.Context, revision *Domain.TaskRevision) error {
+	rr.mu.Lock()
+	defer rr.mu.Unlock()
+
+	revision.ID = primitive.NewObjectID()
+	revision.Timestamp = time.Now()
+
+	copied := *revision
+	rr.revisions = append(rr.revisions, &copied)
+	return nil
+}
+
+// GetByTaskID returns one page of a task's revisions, newest first, with the total number of revisions
+func (rr *RevisionRepository) GetByTaskID(ctx context.Context, taskID string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, 0, Domain.ErrInvalidTaskID
+	}
+
+	rr.mu.RLock()
+	defer rr.mu.RUnlock()
+
+	var matches []*Domain.TaskRevision
+	for _, revision := range rr.revisions {
+		if revision.TaskID == objectID {
+			matches = append(matches, revision)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.After(b.Timestamp)
+		}
+		return idLess(b.ID, a.ID)
+	})
+
+	start, end := pageBounds(len(matches), pagination)
+	revisions := make([]*Domain.TaskRevision, 0, end-start)
+	for _, revision := range matches[start:end] {
+		copied := *revision
+		revisions = append(revisions, &copied)
+	}
+	return revisions, int64(len(matches)), nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (rr *RevisionRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
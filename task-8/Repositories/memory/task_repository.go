@@ -0,0 +1,840 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// priorityRanks orders priorities from urgent to low; a missing priority ranks as medium
+var priorityRanks = map[string]int{
+	Domain.PriorityUrgent: 4,
+	Domain.PriorityHigh:   3,
+	Domain.PriorityMedium: 2,
+	Domain.PriorityLow:    1,
+}
+
+// TaskRepository implements Repositories.TaskRepositoryInterface in process memory
+type TaskRepository struct {
+	mu    sync.RWMutex
+	tasks map[primitive.ObjectID]*Domain.Task
+}
+
+// NewTaskRepository creates a new instance of TaskRepository
+func NewTaskRepository() Repositories.TaskRepositoryInterface {
+	return &TaskRepository{
+		tasks: make(map[primitive.ObjectID]*Domain.Task),
+	}
+}
+
+// GetAll returns a page of tasks matching the filter along with the total number of matching tasks
+func (tr *TaskRepository) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	matches := tr.find(filter, pagination.Sort)
+	start, end := pageBounds(len(matches), pagination)
+
+	tasks := make([]*Domain.Task, 0, end-start)
+	for _, task := range matches[start:end] {
+		projected, err := projectTask(task, pagination.Fields)
+		if err != nil {
+			return nil, 0, err
+		}
+		tasks = append(tasks, projected)
+	}
+	return tasks, int64(len(matches)), nil
+}
+
+// CountTasks returns the number of tasks matching the filter
+func (tr *TaskRepository) CountTasks(ctx context.Context, filter Domain.TaskFilter) (int64, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	return int64(len(tr.find(filter, ""))), nil
+}
+
+// Search returns a page of the tasks matching the filter whose title or description contains a word
+// of the query, most relevant first, along with the total number of matching tasks. It approximates
+// MongoDB's text search: words match whole and ignore case, but are not stemmed, and phrases and
+// negations are read as plain words. A task's score is the number of times query words occur in it.
+func (tr *TaskRepository) Search(ctx context.Context, query string, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	terms := map[string]bool{}
+	for _, word := range searchWords(query) {
+		terms[word] = true
+	}
+
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	var matches []*Domain.Task
+	for _, task := range tr.find(filter, "") {
+		var score float64
+		for _, word := range searchWords(task.Title + " " + task.Description) {
+			if terms[word] {
+				score++
+			}
+		}
+		if score > 0 {
+			found := readTask(task)
+			found.Score = &score
+			matches = append(matches, found)
+		}
+	}
+
+	// Ties keep ID order, as in the MongoDB repository
+	sort.SliceStable(matches, func(i, j int) bool {
+		return *matches[i].Score > *matches[j].Score
+	})
+
+	start, end := pageBounds(len(matches), pagination)
+	return matches[start:end], int64(len(matches)), nil
+}
+
+// searchWords splits text into lowercase words of letters and digits
+func searchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Stream calls fn for every task matching the filter in ID order. It works on a snapshot taken
+// up front, so fn may call back into the repository. It stops at the first error fn returns.
+func (tr *TaskRepository) Stream(ctx context.Context, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	tr.mu.RLock()
+	matches := tr.find(filter, "")
+	snapshot := make([]*Domain.Task, 0, len(matches))
+	for _, task := range matches {
+		snapshot = append(snapshot, readTask(task))
+	}
+	tr.mu.RUnlock()
+
+	for _, task := range snapshot {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetByID returns a task by its ObjectID, ignoring soft-deleted tasks
+func (tr *TaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	return tr.GetByIDWithFields(ctx, id, nil)
+}
+
+// GetByIDWithFields returns an active task with only the selected fields loaded
+func (tr *TaskRepository) GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	task, ok := tr.tasks[objectID]
+	if !ok || task.DeletedAt != nil {
+		return nil, Domain.ErrTaskNotFound
+	}
+	return projectTask(task, fields)
+}
+
+// GetByParentID returns the active subtasks of a task, oldest first
+func (tr *TaskRepository) GetByParentID(ctx context.Context, parentID string) ([]*Domain.Task, error) {
+	objectID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	tasks := []*Domain.Task{}
+	for _, task := range tr.sorted("") {
+		if task.DeletedAt == nil && task.ParentTaskID != nil && *task.ParentTaskID == objectID {
+			tasks = append(tasks, readTask(task))
+		}
+	}
+	return tasks, nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (tr *TaskRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// Create stores a new task
+func (tr *TaskRepository) Create(ctx context.Context, task *Domain.Task) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task.ID = primitive.NewObjectID()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+	task.TitleKey = Domain.NormalizeTitle(task.Title)
+
+	tr.tasks[task.ID] = copyTask(task)
+	return nil
+}
+
+// CreateMany stores several tasks at once.
+// A CreatedAt that is already set is kept so imported tasks retain their original creation time.
+func (tr *TaskRepository) CreateMany(ctx context.Context, tasks []*Domain.Task) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	now := time.Now()
+	for _, task := range tasks {
+		task.ID = primitive.NewObjectID()
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		task.UpdatedAt = now
+		task.TitleKey = Domain.NormalizeTitle(task.Title)
+		tr.tasks[task.ID] = copyTask(task)
+	}
+	return nil
+}
+
+// ExistsByTitleAndDueDate reports whether an active task has exactly this title and due date
+func (tr *TaskRepository) ExistsByTitleAndDueDate(ctx context.Context, title string, dueDate time.Time) (bool, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	for _, task := range tr.tasks {
+		if task.DeletedAt == nil && task.Title == title && task.DueDate.Equal(dueDate) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// FindOpenByTitle returns an active task created by createdBy that is not completed and whose
+// title matches title once both are normalized with Domain.NormalizeTitle
+func (tr *TaskRepository) FindOpenByTitle(ctx context.Context, createdBy primitive.ObjectID, title string) (*Domain.Task, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	titleKey := Domain.NormalizeTitle(title)
+	for _, task := range tr.sorted("") {
+		if task.DeletedAt == nil && task.CreatedBy == createdBy && task.Status != Domain.StatusCompleted && task.TitleKey == titleKey {
+			return readTask(task), nil
+		}
+	}
+	return nil, Domain.ErrTaskNotFound
+}
+
+// Update saves the mutable fields of a task, provided it is still at the version it was read at
+func (tr *TaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	stored, ok := tr.tasks[objectID]
+	if !ok || stored.DeletedAt != nil {
+		return Domain.ErrTaskNotFound
+	}
+	if stored.Version != task.Version {
+		return Domain.ErrVersionConflict
+	}
+
+	task.UpdatedAt = time.Now()
+	applyTaskUpdate(stored, task)
+	task.Version++
+	return nil
+}
+
+// CompleteRecurring saves a completed recurring task and stores its next occurrence in one step,
+// linking the two through NextOccurrenceID. A task spawns at most once.
+func (tr *TaskRepository) CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	stored, ok := tr.tasks[objectID]
+	if !ok || stored.DeletedAt != nil {
+		return Domain.ErrTaskNotFound
+	}
+	if stored.NextOccurrenceID != nil || stored.Version != task.Version {
+		return Domain.ErrVersionConflict
+	}
+
+	now := time.Now()
+	next.ID = primitive.NewObjectID()
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	next.TitleKey = Domain.NormalizeTitle(next.Title)
+	task.UpdatedAt = now
+	task.NextOccurrenceID = &next.ID
+
+	applyTaskUpdate(stored, task)
+	tr.tasks[next.ID] = copyTask(next)
+	task.Version++
+	return nil
+}
+
+// UpdateStatusMany sets the status of every active task in ids.
+// When fromStatuses is not empty only tasks currently in one of those statuses are changed.
+// It returns the matched and modified counts and the changed tasks with their previous statuses.
+func (tr *TaskRepository) UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	now := time.Now()
+	var matched int64
+	var changes []Domain.TaskStatusChange
+	seen := map[primitive.ObjectID]bool{}
+	for _, id := range ids {
+		task, ok := tr.tasks[id]
+		if !ok || task.DeletedAt != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if len(fromStatuses) > 0 && !containsString(fromStatuses, task.Status) {
+			continue
+		}
+		fromStatus := task.Status
+		task.Status = status
+		task.UpdatedAt = now
+		task.Version++
+		matched++
+		changes = append(changes, Domain.TaskStatusChange{Task: copyTask(task), FromStatus: fromStatus})
+	}
+
+	// Every matched task gets a new version, so each one counts as modified
+	return matched, matched, changes, nil
+}
+
+// DeleteByStatus soft deletes every active task with the given status.
+// Parents that still have active subtasks in another status are left in place.
+// It returns the matched and modified counts and the IDs of the tasks it deleted.
+func (tr *TaskRepository) DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	busyParents := map[primitive.ObjectID]bool{}
+	for _, task := range tr.tasks {
+		if task.DeletedAt == nil && task.ParentTaskID != nil && task.Status != status {
+			busyParents[*task.ParentTaskID] = true
+		}
+	}
+
+	now := time.Now()
+	var ids []primitive.ObjectID
+	for _, task := range tr.sorted("") {
+		if task.DeletedAt != nil || task.Status != status || busyParents[task.ID] {
+			continue
+		}
+		task.DeletedAt = copyTime(&now)
+		task.UpdatedAt = now
+		ids = append(ids, task.ID)
+	}
+
+	return int64(len(ids)), int64(len(ids)), ids, nil
+}
+
+// Delete soft deletes a task by stamping deleted_at
+func (tr *TaskRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[objectID]
+	if !ok || task.DeletedAt != nil {
+		return Domain.ErrTaskNotFound
+	}
+
+	now := time.Now()
+	task.DeletedAt = &now
+	task.UpdatedAt = now
+	return nil
+}
+
+// Restore clears deleted_at on a soft-deleted task
+func (tr *TaskRepository) Restore(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[objectID]
+	if !ok || task.DeletedAt == nil {
+		return Domain.ErrTaskNotFound
+	}
+
+	task.DeletedAt = nil
+	task.UpdatedAt = time.Now()
+	return nil
+}
+
+// SetArchived archives or unarchives an active task and bumps its version
+func (tr *TaskRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[objectID]
+	if !ok || task.DeletedAt != nil {
+		return Domain.ErrTaskNotFound
+	}
+
+	task.Archived = archived
+	task.UpdatedAt = time.Now()
+	task.Version++
+	return nil
+}
+
+// ArchiveCompleted archives every active completed task last updated before the given time and
+// returns how many it archived
+func (tr *TaskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	now := time.Now()
+	var archived int64
+	for _, task := range tr.tasks {
+		if task.DeletedAt != nil || task.Archived || task.Status != Domain.StatusCompleted || !task.UpdatedAt.Before(completedBefore) {
+			continue
+		}
+		task.Archived = true
+		task.UpdatedAt = now
+		task.Version++
+		archived++
+	}
+	return archived, nil
+}
+
+// Purge permanently removes tasks that were soft deleted before the given time
+func (tr *TaskRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	var purged int64
+	for id, task := range tr.tasks {
+		if task.DeletedAt != nil && !task.DeletedAt.After(deletedBefore) {
+			delete(tr.tasks, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// PurgeByStatus permanently removes tasks with the given status, in the trash or not, last updated
+// before the given time. Parents that keep a subtask which is not removed are left in place.
+// It returns the IDs of the tasks it removed.
+func (tr *TaskRepository) PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	stale := func(task *Domain.Task) bool {
+		return task.Status == status && !task.UpdatedAt.After(updatedBefore)
+	}
+	busyParents := map[primitive.ObjectID]bool{}
+	for _, task := range tr.tasks {
+		if task.ParentTaskID != nil && !stale(task) {
+			busyParents[*task.ParentTaskID] = true
+		}
+	}
+
+	var ids []primitive.ObjectID
+	for _, task := range tr.sorted("") {
+		if !stale(task) || busyParents[task.ID] {
+			continue
+		}
+		delete(tr.tasks, task.ID)
+		ids = append(ids, task.ID)
+	}
+	return ids, nil
+}
+
+// ReassignCreator hands every task created by one user, soft-deleted ones included, to another user.
+// It returns the number of tasks changed.
+func (tr *TaskRepository) ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	now := time.Now()
+	var changed int64
+	for _, task := range tr.tasks {
+		// Tasks without a creator are stored without the field, so they never match
+		if !task.CreatedBy.IsZero() && task.CreatedBy == fromUserID {
+			task.CreatedBy = toUserID
+			task.UpdatedAt = now
+			task.Version++
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// UnassignUser clears the assignee of every task assigned to the user, soft-deleted ones included.
+// It returns the number of tasks changed.
+func (tr *TaskRepository) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	now := time.Now()
+	var changed int64
+	for _, task := range tr.tasks {
+		if task.AssigneeID != nil && *task.AssigneeID == userID {
+			task.AssigneeID = nil
+			task.UpdatedAt = now
+			task.Version++
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// GetDueForReminder returns up to limit open tasks due before dueBefore that still need a reminder,
+// soonest due first. A task needs one when it was never reminded, or when it is overdue at now and
+// its last reminder was sent before it fell due.
+func (tr *TaskRepository) GetDueForReminder(ctx context.Context, dueBefore, now time.Time, limit int64) ([]*Domain.Task, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	tasks := []*Domain.Task{}
+	for _, task := range tr.sorted(Domain.SortDueDate) {
+		if limit > 0 && int64(len(tasks)) == limit {
+			break
+		}
+		if task.DeletedAt != nil || task.Status == Domain.StatusCompleted {
+			continue
+		}
+		if task.DueDate.IsZero() || !task.DueDate.Before(dueBefore) {
+			continue
+		}
+		overdueSinceReminder := task.DueDate.Before(now) && task.LastNotifiedAt != nil && task.LastNotifiedAt.Before(task.DueDate)
+		if task.LastNotifiedAt != nil && !overdueSinceReminder {
+			continue
+		}
+		tasks = append(tasks, readTask(task))
+	}
+	return tasks, nil
+}
+
+// MarkNotified records when a reminder was sent for a task without changing its version or update time
+func (tr *TaskRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+
+	task, ok := tr.tasks[id]
+	if !ok || task.DeletedAt != nil {
+		return Domain.ErrTaskNotFound
+	}
+	task.LastNotifiedAt = &at
+	return nil
+}
+
+// GetTags returns the distinct tags used by tasks matching the filter, sorted alphabetically
+func (tr *TaskRepository) GetTags(ctx context.Context, filter Domain.TaskFilter) ([]string, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	seen := map[string]bool{}
+	tags := []string{}
+	for _, task := range tr.find(filter, "") {
+		for _, tag := range task.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				tags = append(tags, tag)
+			}
+		}
+	}
+	sort.Strings(tags)
+	return tags, nil
+}
+
+// GetStats counts the tasks matching the filter by status, how many are overdue or due in the
+// next seven days, and how many were created on each of the last days
+func (tr *TaskRepository) GetStats(ctx context.Context, filter Domain.TaskFilter, now time.Time) (*Domain.TaskStats, error) {
+	tr.mu.RLock()
+	defer tr.mu.RUnlock()
+
+	now = now.UTC()
+	since := statsWindowStart(now)
+	dueSoonEnd := now.AddDate(0, 0, 7)
+
+	stats := &Domain.TaskStats{
+		ByStatus: map[string]int64{
+			Domain.StatusPending:    0,
+			Domain.StatusInProgress: 0,
+			Domain.StatusCompleted:  0,
+		},
+	}
+	createdPerDay := map[string]int64{}
+	for _, task := range tr.find(filter, "") {
+		stats.ByStatus[task.Status]++
+
+		// Tasks without a due date are stored with the zero time and never count as overdue or due soon
+		if task.Status != Domain.StatusCompleted && !task.DueDate.IsZero() {
+			if task.DueDate.Before(now) {
+				stats.Overdue++
+			} else if task.DueDate.Before(dueSoonEnd) {
+				stats.DueNext7Days++
+			}
+		}
+
+		if !task.CreatedAt.Before(since) {
+			createdPerDay[task.CreatedAt.UTC().Format("2006-01-02")]++
+		}
+	}
+
+	stats.CreatedPerDay = make([]Domain.DailyCount, Domain.StatsCreatedDays)
+	for i := range stats.CreatedPerDay {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		stats.CreatedPerDay[i] = Domain.DailyCount{Date: date, Count: createdPerDay[date]}
+	}
+
+	return stats, nil
+}
+
+// statsWindowStart returns midnight UTC of the first day in the created-per-day window ending today
+func statsWindowStart(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day-(Domain.StatsCreatedDays-1), 0, 0, 0, 0, time.UTC)
+}
+
+// find returns the stored tasks matching the filter in the requested order. The caller must hold the lock.
+func (tr *TaskRepository) find(filter Domain.TaskFilter, sortBy string) []*Domain.Task {
+	var matches []*Domain.Task
+	for _, task := range tr.sorted(sortBy) {
+		if matchesTaskFilter(task, filter) {
+			matches = append(matches, task)
+		}
+	}
+	return matches
+}
+
+// sorted returns every stored task in ID order, by due date, or from urgent to low priority.
+// Ties are broken by ID. The caller must hold the lock.
+func (tr *TaskRepository) sorted(sortBy string) []*Domain.Task {
+	tasks := make([]*Domain.Task, 0, len(tr.tasks))
+	for _, task := range tr.tasks {
+		tasks = append(tasks, task)
+	}
+
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		switch sortBy {
+		case Domain.SortDueDate:
+			if !a.DueDate.Equal(b.DueDate) {
+				return a.DueDate.Before(b.DueDate)
+			}
+		case Domain.SortPriority:
+			if rankA, rankB := priorityRank(a.Priority), priorityRank(b.Priority); rankA != rankB {
+				return rankA > rankB
+			}
+		}
+		return idLess(a.ID, b.ID)
+	})
+	return tasks
+}
+
+// priorityRank ranks a priority for sorting, treating a missing priority as medium
+func priorityRank(priority string) int {
+	if rank, ok := priorityRanks[priority]; ok {
+		return rank
+	}
+	return priorityRanks[Domain.PriorityMedium]
+}
+
+// matchesTaskFilter applies a task filter the way the MongoDB repository's query does.
+// An empty filter matches every task that has not been soft deleted.
+func matchesTaskFilter(task *Domain.Task, filter Domain.TaskFilter) bool {
+	switch {
+	case filter.OnlyDeleted:
+		if task.DeletedAt == nil {
+			return false
+		}
+	case !filter.IncludeDeleted:
+		if task.DeletedAt != nil {
+			return false
+		}
+	}
+
+	switch {
+	case filter.OnlyArchived:
+		if !task.Archived {
+			return false
+		}
+	case !filter.IncludeArchived:
+		if task.Archived {
+			return false
+		}
+	}
+
+	if filter.Status != "" && task.Status != filter.Status {
+		return false
+	}
+
+	if filter.Priority != "" {
+		// Tasks stored before priorities existed count as medium
+		if task.Priority != filter.Priority && !(filter.Priority == Domain.PriorityMedium && task.Priority == "") {
+			return false
+		}
+	}
+
+	if filter.Tag != "" && !containsString(task.Tags, filter.Tag) {
+		return false
+	}
+
+	if !filter.CreatedBy.IsZero() && task.CreatedBy != filter.CreatedBy {
+		return false
+	}
+
+	if !filter.AssigneeID.IsZero() && (task.AssigneeID == nil || *task.AssigneeID != filter.AssigneeID) {
+		return false
+	}
+
+	if !filter.DueAfter.IsZero() && task.DueDate.Before(filter.DueAfter) {
+		return false
+	}
+	if !filter.DueBefore.IsZero() && task.DueDate.After(filter.DueBefore) {
+		return false
+	}
+
+	// Tasks without a due date are stored with the zero time and are never overdue
+	if !filter.OverdueAt.IsZero() {
+		if task.DueDate.IsZero() || !task.DueDate.Before(filter.OverdueAt) {
+			return false
+		}
+		if filter.Status == "" && task.Status == Domain.StatusCompleted {
+			return false
+		}
+	}
+
+	if filter.OpenOnly && filter.Status == "" && task.Status == Domain.StatusCompleted {
+		return false
+	}
+
+	return true
+}
+
+// applyTaskUpdate copies the mutable fields of task onto the stored task and bumps its version.
+// Optional fields that are empty are cleared; the next occurrence link is only ever added.
+func applyTaskUpdate(stored, task *Domain.Task) {
+	stored.Title = task.Title
+	stored.TitleKey = Domain.NormalizeTitle(task.Title)
+	stored.Description = task.Description
+	stored.DueDate = task.DueDate
+	stored.Status = task.Status
+	stored.Priority = task.Priority
+	stored.Recurrence = task.Recurrence
+	stored.UpdatedAt = task.UpdatedAt
+	stored.AssigneeID = copyObjectID(task.AssigneeID)
+	stored.EstimateMinutes = copyInt(task.EstimateMinutes)
+	stored.Tags = nil
+	if len(task.Tags) > 0 {
+		stored.Tags = append([]string(nil), task.Tags...)
+	}
+	if task.NextOccurrenceID != nil {
+		stored.NextOccurrenceID = copyObjectID(task.NextOccurrenceID)
+	}
+	if task.LastNotifiedAt == nil {
+		stored.LastNotifiedAt = nil
+	}
+	stored.Version++
+}
+
+// readTask returns a copy of a stored task as the MongoDB repository would decode it
+func readTask(task *Domain.Task) *Domain.Task {
+	copied := copyTask(task)
+	copied.ApplyDefaults()
+	return copied
+}
+
+// projectTask returns a copy of a stored task as the MongoDB repository would decode it with
+// Repositories.TaskProjection applied, by passing it through BSON and keeping the projected keys
+func projectTask(task *Domain.Task, fields []string) (*Domain.Task, error) {
+	projection := Repositories.TaskProjection(fields)
+	if projection == nil {
+		return readTask(task), nil
+	}
+
+	data, err := bson.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := bson.Raw(data).Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := bson.D{}
+	for _, element := range elements {
+		if key := element.Key(); key == "_id" || projection[key] != nil {
+			kept = append(kept, bson.E{Key: key, Value: element.Value()})
+		}
+	}
+	if data, err = bson.Marshal(kept); err != nil {
+		return nil, err
+	}
+
+	var projected Domain.Task
+	if err := bson.Unmarshal(data, &projected); err != nil {
+		return nil, err
+	}
+	projected.ApplyDefaults()
+	return &projected, nil
+}
+
+// copyTask returns a deep copy of a task without the fields that are never stored
+func copyTask(task *Domain.Task) *Domain.Task {
+	copied := *task
+	if task.Tags != nil {
+		copied.Tags = append([]string(nil), task.Tags...)
+	}
+	copied.EstimateMinutes = copyInt(task.EstimateMinutes)
+	copied.AssigneeID = copyObjectID(task.AssigneeID)
+	copied.ParentTaskID = copyObjectID(task.ParentTaskID)
+	copied.NextOccurrenceID = copyObjectID(task.NextOccurrenceID)
+	copied.DeletedAt = copyTime(task.DeletedAt)
+	copied.LastNotifiedAt = copyTime(task.LastNotifiedAt)
+	copied.Subtasks = nil
+	copied.DaysOverdue = nil
+	copied.Score = nil
+	copied.TotalLoggedMinutes = nil
+	return &copied
+}
+
+// containsString reports whether values holds value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
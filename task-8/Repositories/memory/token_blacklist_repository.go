@@ -0,0 +1,26 @@
+package memory
+
+import (
+	"context"
+
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// TokenBlacklistRepository implements Repositories.TokenBlacklistRepositoryInterface with the
+// in-memory blacklist, which drops each entry once the token it revokes has expired
+type TokenBlacklistRepository struct {
+	*Infrastructure.MemoryTokenBlacklist
+}
+
+// NewTokenBlacklistRepository creates a new instance of TokenBlacklistRepository
+func NewTokenBlacklistRepository() Repositories.TokenBlacklistRepositoryInterface {
+	return &TokenBlacklistRepository{
+		MemoryTokenBlacklist: Infrastructure.NewMemoryTokenBlacklist(),
+	}
+}
+
+// EnsureIndexes has nothing to create in memory
+func (br *TokenBlacklistRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
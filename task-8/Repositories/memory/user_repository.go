@@ -0,0 +1,469 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// UserRepository implements Repositories.UserRepositoryInterface in process memory.
+// Usernames are unique ignoring case and email addresses are unique when set, as with the
+// unique indexes on the MongoDB users collection.
+type UserRepository struct {
+	mu    sync.RWMutex
+	users map[primitive.ObjectID]*Domain.User
+}
+
+// NewUserRepository creates a new instance of UserRepository
+func NewUserRepository() Repositories.UserRepositoryInterface {
+	return &UserRepository{
+		users: make(map[primitive.ObjectID]*Domain.User),
+	}
+}
+
+// GetAll returns one page of users matching the filter with the total number of matches.
+// Users are ordered by creation unless pagination.Sort is SortUsername; the ID breaks ties
+// so pages never overlap.
+func (ur *UserRepository) GetAll(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var matches []*Domain.User
+	for _, user := range ur.users {
+		if matchesUserFilter(user, filter) {
+			matches = append(matches, user)
+		}
+	}
+
+	sortUsers(matches, pagination.Sort)
+
+	start, end := pageBounds(len(matches), pagination)
+	users := make([]*Domain.User, 0, end-start)
+	for _, user := range matches[start:end] {
+		users = append(users, copyUser(user))
+	}
+	return users, int64(len(matches)), nil
+}
+
+// Stream calls fn for a snapshot of the users matching the filter in creation order, without
+// their password hashes. It stops at the first error fn returns.
+func (ur *UserRepository) Stream(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	users, _, err := ur.GetAll(ctx, filter, Domain.Pagination{})
+	if err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		user.Password = ""
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetDirectory returns one page of the IDs and usernames of users whose username contains query,
+// ignoring case, with the total number of matches. Users are ordered as by GetAll.
+func (ur *UserRepository) GetDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	query = strings.ToLower(query)
+	var matches []*Domain.User
+	for _, user := range ur.users {
+		if strings.Contains(strings.ToLower(user.Username), query) {
+			matches = append(matches, user)
+		}
+	}
+
+	sortUsers(matches, pagination.Sort)
+
+	start, end := pageBounds(len(matches), pagination)
+	entries := make([]*Domain.UserDirectoryEntry, 0, end-start)
+	for _, user := range matches[start:end] {
+		entries = append(entries, &Domain.UserDirectoryEntry{ID: user.ID, Username: user.Username})
+	}
+	return entries, int64(len(matches)), nil
+}
+
+// sortUsers orders users by creation, or by username when sort is SortUsername. The ID breaks ties.
+func sortUsers(users []*Domain.User, sortBy string) {
+	sort.Slice(users, func(i, j int) bool {
+		a, b := users[i], users[j]
+		if sortBy == Domain.SortUsername {
+			if a.UsernameLower != b.UsernameLower {
+				return a.UsernameLower < b.UsernameLower
+			}
+		} else if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.Before(b.CreatedAt)
+		}
+		return idLess(a.ID, b.ID)
+	})
+}
+
+// matchesUserFilter reports whether the search text appears in the username or email, ignoring
+// case, and the role matches
+func matchesUserFilter(user *Domain.User, filter Domain.UserFilter) bool {
+	if filter.Query != "" {
+		query := strings.ToLower(filter.Query)
+		if !strings.Contains(strings.ToLower(user.Username), query) && !strings.Contains(strings.ToLower(user.Email), query) {
+			return false
+		}
+	}
+	return filter.Role == "" || user.Role == filter.Role
+}
+
+// GetByID retrieves a user by ID
+func (ur *UserRepository) GetByID(ctx context.Context, id string) (*Domain.User, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	user, ok := ur.users[objectID]
+	if !ok {
+		return nil, Domain.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+// GetByUsername retrieves a user by username, ignoring case and surrounding whitespace
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	user := ur.findByUsername(username)
+	if user == nil {
+		return nil, Domain.ErrUserNotFound
+	}
+	return copyUser(user), nil
+}
+
+// GetByEmail retrieves a user by email address
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*Domain.User, error) {
+	email = Domain.NormalizeEmail(email)
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	for _, user := range ur.users {
+		if user.Email != "" && user.Email == email {
+			return copyUser(user), nil
+		}
+	}
+	return nil, Domain.ErrUserNotFound
+}
+
+// Create stores a new user, failing when the username or email address is taken
+func (ur *UserRepository) Create(ctx context.Context, user *Domain.User) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if err := ur.checkUnique(primitive.NilObjectID, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	user.ID = primitive.NewObjectID()
+	user.UsernameLower = Domain.NormalizeUsername(user.Username)
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+
+	ur.users[user.ID] = copyUser(user)
+	return nil
+}
+
+// Update stores a user's username, password and role
+func (ur *UserRepository) Update(ctx context.Context, id string, user *Domain.User) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return Domain.ErrUserNotFound
+	}
+	if err := ur.checkUnique(objectID, user.Username, ""); err != nil {
+		return err
+	}
+
+	user.UpdatedAt = time.Now()
+	stored.Username = user.Username
+	stored.UsernameLower = Domain.NormalizeUsername(user.Username)
+	stored.Password = user.Password
+	stored.Role = user.Role
+	stored.UpdatedAt = user.UpdatedAt
+	return nil
+}
+
+// UpdateByUsername stores the role of the user with the given username, matching it like GetByUsername
+func (ur *UserRepository) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	user.UpdatedAt = time.Now()
+
+	stored := ur.findByUsername(username)
+	if stored == nil {
+		return Domain.ErrUserNotFound
+	}
+
+	stored.Role = user.Role
+	stored.UpdatedAt = user.UpdatedAt
+	return nil
+}
+
+// UpdateProfile stores a user's username and email address with its verification status
+func (ur *UserRepository) UpdateProfile(ctx context.Context, id string, user *Domain.User) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return Domain.ErrUserNotFound
+	}
+	if err := ur.checkUnique(objectID, user.Username, user.Email); err != nil {
+		return err
+	}
+
+	user.UpdatedAt = time.Now()
+	stored.Username = user.Username
+	stored.UsernameLower = Domain.NormalizeUsername(user.Username)
+	stored.Email = user.Email
+	stored.EmailVerified = user.EmailVerified
+	stored.UpdatedAt = user.UpdatedAt
+	return nil
+}
+
+// MarkEmailVerified marks a user's email address as verified. It only matches while the user
+// still has that address, so a token for a replaced address cannot verify the new one.
+func (ur *UserRepository) MarkEmailVerified(ctx context.Context, id string, email string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok || stored.Email == "" || stored.Email != email {
+		return Domain.ErrUserNotFound
+	}
+
+	stored.EmailVerified = true
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+// UpdateLastLogin records when the user last logged in
+func (ur *UserRepository) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return Domain.ErrUserNotFound
+	}
+
+	stored.LastLoginAt = copyTime(&at)
+	return nil
+}
+
+// SetActive activates or deactivates a user
+func (ur *UserRepository) SetActive(ctx context.Context, id string, active bool) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return Domain.ErrUserNotFound
+	}
+
+	stored.IsActive = active
+	stored.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsActive reports whether a user may use the tokens issued to them
+func (ur *UserRepository) IsActive(ctx context.Context, id string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, Domain.ErrInvalidUserID
+	}
+
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return false, Domain.ErrUserNotFound
+	}
+	return stored.IsActive, nil
+}
+
+// Anonymize scrubs a user's personal data but keeps the record, so tasks, comments and audit
+// entries that reference the user stay valid
+func (ur *UserRepository) Anonymize(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return Domain.ErrUserNotFound
+	}
+
+	now := time.Now()
+	stored.Username = Domain.AnonymizedUsername(id)
+	stored.UsernameLower = Domain.NormalizeUsername(stored.Username)
+	stored.Password = ""
+	stored.Email = ""
+	stored.Role = Domain.RoleUser
+	stored.EmailVerified = false
+	stored.AnonymizedAt = &now
+	stored.UpdatedAt = now
+	return nil
+}
+
+// Delete removes a user
+func (ur *UserRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	if _, ok := ur.users[objectID]; !ok {
+		return Domain.ErrUserNotFound
+	}
+	delete(ur.users, objectID)
+	return nil
+}
+
+// CountUsers returns the total number of users
+func (ur *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	return int64(len(ur.users)), nil
+}
+
+// CountByRole returns the number of users with the given role
+func (ur *UserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	var count int64
+	for _, user := range ur.users {
+		if user.Role == role {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// ClaimFirstAdmin makes a user the admin unless another user has already claimed that as the first user
+func (ur *UserRepository) ClaimFirstAdmin(ctx context.Context, id string) (bool, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, Domain.ErrInvalidUserID
+	}
+
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	stored, ok := ur.users[objectID]
+	if !ok {
+		return false, Domain.ErrUserNotFound
+	}
+	for _, user := range ur.users {
+		if user.FirstAdmin && user.ID != objectID {
+			return false, nil
+		}
+	}
+
+	stored.Role = Domain.RoleAdmin
+	stored.FirstAdmin = true
+	stored.UpdatedAt = time.Now()
+	return true, nil
+}
+
+// EnsureIndexes has nothing to create in memory; uniqueness is checked on every write
+func (ur *UserRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// findByUsername returns the stored user with the username in any case, or nil. The caller must hold the lock.
+func (ur *UserRepository) findByUsername(username string) *Domain.User {
+	usernameLower := Domain.NormalizeUsername(username)
+	for _, user := range ur.users {
+		if user.UsernameLower == usernameLower {
+			return user
+		}
+	}
+	return nil
+}
+
+// checkUnique fails when another user than id already has the username in any case, or the
+// non-empty email address. Usernames are checked first, as MongoDB checks the username index
+// before the email index. The caller must hold the lock.
+func (ur *UserRepository) checkUnique(id primitive.ObjectID, username, email string) error {
+	if other := ur.findByUsername(username); other != nil && other.ID != id {
+		return Domain.ErrUsernameTaken
+	}
+	if email == "" {
+		return nil
+	}
+	for _, user := range ur.users {
+		if user.ID != id && user.Email == email {
+			return Domain.ErrEmailTaken
+		}
+	}
+	return nil
+}
+
+// copyUser returns a copy of a user that shares no memory with the stored record
+func copyUser(user *Domain.User) *Domain.User {
+	copied := *user
+	copied.AnonymizedAt = copyTime(user.AnonymizedAt)
+	copied.LastLoginAt = copyTime(user.LastLoginAt)
+	return &copied
+}
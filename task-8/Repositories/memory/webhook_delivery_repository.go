@@ -0,0 +1,98 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// WebhookDeliveryRepository implements Repositories.WebhookDeliveryRepositoryInterface in process memory
+type WebhookDeliveryRepository struct {
+	mu         sync.RWMutex
+	deliveries []*Domain.WebhookDelivery
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository
+func NewWebhookDeliveryRepository() Repositories.WebhookDeliveryRepositoryInterface {
+	return &WebhookDeliveryRepository{}
+}
+
+// Create records a delivery. CreatedAt is kept when set, since it marks the first attempt.
+func (dr *WebhookDeliveryRepository) Create(ctx context.Context, delivery *Domain.WebhookDelivery) error {
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	delivery.ID = primitive.NewObjectID()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	copied := *delivery
+	dr.deliveries = append(dr.deliveries, &copied)
+	return nil
+}
+
+// GetByWebhookID returns one page of a webhook's deliveries, newest first, with the total number of them
+func (dr *WebhookDeliveryRepository) GetByWebhookID(ctx context.Context, webhookID string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error) {
+	objectID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, 0, Domain.ErrInvalidWebhookID
+	}
+
+	dr.mu.RLock()
+	defer dr.mu.RUnlock()
+
+	var matches []*Domain.WebhookDelivery
+	for _, delivery := range dr.deliveries {
+		if delivery.WebhookID == objectID {
+			matches = append(matches, delivery)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return idLess(b.ID, a.ID)
+	})
+
+	start, end := pageBounds(len(matches), pagination)
+	deliveries := make([]*Domain.WebhookDelivery, 0, end-start)
+	for _, delivery := range matches[start:end] {
+		copied := *delivery
+		deliveries = append(deliveries, &copied)
+	}
+	return deliveries, int64(len(matches)), nil
+}
+
+// DeleteByWebhookID removes every delivery record of a webhook
+func (dr *WebhookDeliveryRepository) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	objectID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return Domain.ErrInvalidWebhookID
+	}
+
+	dr.mu.Lock()
+	defer dr.mu.Unlock()
+
+	kept := dr.deliveries[:0]
+	for _, delivery := range dr.deliveries {
+		if delivery.WebhookID != objectID {
+			kept = append(kept, delivery)
+		}
+	}
+	dr.deliveries = kept
+	return nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (dr *WebhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,146 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// WebhookRepository implements Repositories.WebhookRepositoryInterface in process memory
+type WebhookRepository struct {
+	mu       sync.RWMutex
+	webhooks map[primitive.ObjectID]*Domain.Webhook
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository() Repositories.WebhookRepositoryInterface {
+	return &WebhookRepository{
+		webhooks: make(map[primitive.ObjectID]*Domain.Webhook),
+	}
+}
+
+// Create stores a webhook
+func (wr *WebhookRepository) Create(ctx context.Context, webhook *Domain.Webhook) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	webhook.ID = primitive.NewObjectID()
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	wr.webhooks[webhook.ID] = copyWebhook(webhook)
+	return nil
+}
+
+// GetByID returns a webhook by its ID
+func (wr *WebhookRepository) GetByID(ctx context.Context, id string) (*Domain.Webhook, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidWebhookID
+	}
+
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+
+	webhook, ok := wr.webhooks[objectID]
+	if !ok {
+		return nil, Domain.ErrWebhookNotFound
+	}
+	return copyWebhook(webhook), nil
+}
+
+// GetAll returns every webhook, newest first
+func (wr *WebhookRepository) GetAll(ctx context.Context) ([]*Domain.Webhook, error) {
+	return wr.find(func(webhook *Domain.Webhook) bool { return true }), nil
+}
+
+// GetActiveByEvent returns the active webhooks subscribed to an event type
+func (wr *WebhookRepository) GetActiveByEvent(ctx context.Context, eventType string) ([]*Domain.Webhook, error) {
+	return wr.find(func(webhook *Domain.Webhook) bool {
+		if !webhook.Active {
+			return false
+		}
+		for _, subscribed := range webhook.Events {
+			if subscribed == eventType {
+				return true
+			}
+		}
+		return false
+	}), nil
+}
+
+// find returns copies of the webhooks matching a predicate, newest first
+func (wr *WebhookRepository) find(match func(webhook *Domain.Webhook) bool) []*Domain.Webhook {
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+
+	webhooks := []*Domain.Webhook{}
+	for _, webhook := range wr.webhooks {
+		if match(webhook) {
+			webhooks = append(webhooks, copyWebhook(webhook))
+		}
+	}
+
+	sort.Slice(webhooks, func(i, j int) bool {
+		a, b := webhooks[i], webhooks[j]
+		if !a.CreatedAt.Equal(b.CreatedAt) {
+			return a.CreatedAt.After(b.CreatedAt)
+		}
+		return idLess(b.ID, a.ID)
+	})
+	return webhooks
+}
+
+// Update replaces the URL, secret, events and active flag of a webhook
+func (wr *WebhookRepository) Update(ctx context.Context, webhook *Domain.Webhook) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	stored, ok := wr.webhooks[webhook.ID]
+	if !ok {
+		return Domain.ErrWebhookNotFound
+	}
+
+	webhook.UpdatedAt = time.Now()
+	stored.URL = webhook.URL
+	stored.Secret = webhook.Secret
+	stored.Events = append([]string(nil), webhook.Events...)
+	stored.Active = webhook.Active
+	stored.UpdatedAt = webhook.UpdatedAt
+	return nil
+}
+
+// Delete removes a webhook
+func (wr *WebhookRepository) Delete(ctx context.Context, id string) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidWebhookID
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	if _, ok := wr.webhooks[objectID]; !ok {
+		return Domain.ErrWebhookNotFound
+	}
+	delete(wr.webhooks, objectID)
+	return nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (wr *WebhookRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// copyWebhook returns a copy of a webhook that shares no memory with the stored record
+func copyWebhook(webhook *Domain.Webhook) *Domain.Webhook {
+	copied := *webhook
+	copied.Events = append([]string(nil), webhook.Events...)
+	return &copied
+}
@@ -0,0 +1,231 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// WorkLogRepository implements Repositories.WorkLogRepositoryInterface in process memory
+type WorkLogRepository struct {
+	mu       sync.RWMutex
+	workLogs map[primitive.ObjectID]*Domain.WorkLog
+}
+
+// NewWorkLogRepository creates a new instance of WorkLogRepository
+func NewWorkLogRepository() Repositories.WorkLogRepositoryInterface {
+	return &WorkLogRepository{
+		workLogs: make(map[primitive.ObjectID]*Domain.WorkLog),
+	}
+}
+
+// Create stores a new work log
+func (wr *WorkLogRepository) Create(ctx context.Context, workLog *Domain.WorkLog) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	workLog.ID = primitive.NewObjectID()
+	workLog.CreatedAt = time.Now()
+
+	copied := *workLog
+	copied.DeletedAt = copyTime(workLog.DeletedAt)
+	wr.workLogs[workLog.ID] = &copied
+	return nil
+}
+
+// GetByTaskID returns the work logged on a task, earliest day first
+func (wr *WorkLogRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.WorkLog, error) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+
+	workLogs := []*Domain.WorkLog{}
+	for _, workLog := range wr.workLogs {
+		if workLog.TaskID == objectID && workLog.DeletedAt == nil {
+			copied := *workLog
+			workLogs = append(workLogs, &copied)
+		}
+	}
+
+	sort.Slice(workLogs, func(i, j int) bool {
+		a, b := workLogs[i], workLogs[j]
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return idLess(a.ID, b.ID)
+	})
+	return workLogs, nil
+}
+
+// TotalMinutesByTaskID adds up the minutes logged on a task
+func (wr *WorkLogRepository) TotalMinutesByTaskID(ctx context.Context, taskID string) (int, error) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return 0, Domain.ErrInvalidTaskID
+	}
+
+	return wr.totalMinutes(func(workLog *Domain.WorkLog) bool {
+		return workLog.TaskID == objectID
+	}), nil
+}
+
+// TotalMinutesByUserOnDate adds up the minutes a user logged on one day, on every task
+func (wr *WorkLogRepository) TotalMinutesByUserOnDate(ctx context.Context, userID primitive.ObjectID, date time.Time) (int, error) {
+	return wr.totalMinutes(func(workLog *Domain.WorkLog) bool {
+		return workLog.UserID == userID && workLog.Date.Equal(date)
+	}), nil
+}
+
+// totalMinutes adds up the minutes of the active work logs that match
+func (wr *WorkLogRepository) totalMinutes(matches func(workLog *Domain.WorkLog) bool) int {
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+
+	total := 0
+	for _, workLog := range wr.workLogs {
+		if workLog.DeletedAt == nil && matches(workLog) {
+			total += workLog.Minutes
+		}
+	}
+	return total
+}
+
+// Summarize totals the minutes and entries each user logged, most minutes first
+func (wr *WorkLogRepository) Summarize(ctx context.Context, filter Domain.WorkLogFilter) ([]*Domain.WorkLogSummary, error) {
+	wr.mu.RLock()
+	defer wr.mu.RUnlock()
+
+	byUser := make(map[primitive.ObjectID]*Domain.WorkLogSummary)
+	for _, workLog := range wr.workLogs {
+		if workLog.DeletedAt != nil {
+			continue
+		}
+		if !filter.UserID.IsZero() && workLog.UserID != filter.UserID {
+			continue
+		}
+		if !filter.From.IsZero() && workLog.Date.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && workLog.Date.After(filter.To) {
+			continue
+		}
+
+		summary, ok := byUser[workLog.UserID]
+		if !ok {
+			summary = &Domain.WorkLogSummary{UserID: workLog.UserID}
+			byUser[workLog.UserID] = summary
+		}
+		summary.TotalMinutes += int64(workLog.Minutes)
+		summary.Entries++
+	}
+
+	summaries := make([]*Domain.WorkLogSummary, 0, len(byUser))
+	for _, summary := range byUser {
+		summaries = append(summaries, summary)
+	}
+	sort.Slice(summaries, func(i, j int) bool {
+		a, b := summaries[i], summaries[j]
+		if a.TotalMinutes != b.TotalMinutes {
+			return a.TotalMinutes > b.TotalMinutes
+		}
+		return idLess(a.UserID, b.UserID)
+	})
+	return summaries, nil
+}
+
+// DeleteByTaskID soft deletes every work log on a task by stamping deleted_at
+func (wr *WorkLogRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return wr.DeleteByTaskIDs(ctx, []primitive.ObjectID{objectID})
+}
+
+// DeleteByTaskIDs soft deletes every work log on the given tasks
+func (wr *WorkLogRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	tasks := make(map[primitive.ObjectID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		tasks[id] = true
+	}
+
+	now := time.Now()
+	for _, workLog := range wr.workLogs {
+		if tasks[workLog.TaskID] && workLog.DeletedAt == nil {
+			workLog.DeletedAt = copyTime(&now)
+		}
+	}
+	return nil
+}
+
+// RestoreByTaskID clears deleted_at on every work log on a task
+func (wr *WorkLogRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	for _, workLog := range wr.workLogs {
+		if workLog.TaskID == objectID {
+			workLog.DeletedAt = nil
+		}
+	}
+	return nil
+}
+
+// Purge permanently removes work logs that were soft deleted before the given time
+func (wr *WorkLogRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	var purged int64
+	for id, workLog := range wr.workLogs {
+		if workLog.DeletedAt != nil && !workLog.DeletedAt.After(deletedBefore) {
+			delete(wr.workLogs, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// PurgeByTaskIDs permanently removes every work log on the given tasks, in the trash or not
+func (wr *WorkLogRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	wr.mu.Lock()
+	defer wr.mu.Unlock()
+
+	tasks := make(map[primitive.ObjectID]bool, len(taskIDs))
+	for _, id := range taskIDs {
+		tasks[id] = true
+	}
+
+	var purged int64
+	for id, workLog := range wr.workLogs {
+		if tasks[workLog.TaskID] {
+			delete(wr.workLogs, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// EnsureIndexes has nothing to create in memory
+func (wr *WorkLogRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,108 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// PasswordResetRepositoryInterface defines the contract for password reset token data access
+type PasswordResetRepositoryInterface interface {
+	Create(ctx context.Context, token *Domain.PasswordResetToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*Domain.PasswordResetToken, error)
+	MarkUsed(ctx context.Context, id primitive.ObjectID) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// PasswordResetRepository implements PasswordResetRepositoryInterface with MongoDB
+type PasswordResetRepository struct {
+	collection *mongo.Collection
+}
+
+// NewPasswordResetRepository creates a new instance of PasswordResetRepository
+func NewPasswordResetRepository(client *mongo.Client, dbName string) PasswordResetRepositoryInterface {
+	collection := client.Database(dbName).Collection("password_reset_tokens")
+	return &PasswordResetRepository{
+		collection: collection,
+	}
+}
+
+// Create stores a password reset token
+func (pr *PasswordResetRepository) Create(ctx context.Context, token *Domain.PasswordResetToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := pr.collection.InsertOne(ctx, token)
+	return err
+}
+
+// GetByHash returns the reset token stored under a hash, used or not
+func (pr *PasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*Domain.PasswordResetToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var token Domain.PasswordResetToken
+	err := pr.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrResetTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// MarkUsed marks a reset token as used. It fails if the token was already used,
+// so two requests racing with the same token cannot both reset the password.
+func (pr *PasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := pr.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "used_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrResetTokenUsed
+	}
+
+	return nil
+}
+
+// EnsureIndexes creates the lookup index and the TTL index that removes expired tokens
+func (pr *PasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := pr.collection.Indexes().CreateMany(ctx, passwordResetIndexes())
+	return err
+}
+
+// passwordResetIndexes lists the indexes maintained on the password_reset_tokens collection.
+// Expired tokens are kept for a day so a late attempt is told the token expired rather than that it is unknown.
+func passwordResetIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetName("token_hash_1").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(int32((24 * time.Hour) / time.Second)),
+		},
+	}
+}
@@ -0,0 +1,78 @@
+package Repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockPasswordResetRepositoryImpl for testing purposes
+type MockPasswordResetRepositoryImpl struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepositoryImpl) Create(ctx context.Context, token *Domain.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*Domain.PasswordResetToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepositoryImpl) MarkUsed(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestPasswordResetIndexes(t *testing.T) {
+	t.Run("Hashes are unique and expired tokens are removed a day later", func(t *testing.T) {
+		// Act
+		indexes := passwordResetIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 2)
+		assert.Equal(t, bson.D{{Key: "token_hash", Value: 1}}, indexes[0].Keys)
+		assert.True(t, *indexes[0].Options.Unique)
+		assert.Equal(t, bson.D{{Key: "expires_at", Value: 1}}, indexes[1].Keys)
+		assert.Equal(t, int32(86400), *indexes[1].Options.ExpireAfterSeconds)
+	})
+}
+
+func TestPasswordResetRepository_MarkUsed(t *testing.T) {
+	t.Run("Error - token already used", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockPasswordResetRepositoryImpl)
+		id := primitive.NewObjectID()
+		mockRepo.On("MarkUsed", id).Return(Domain.ErrResetTokenUsed)
+
+		// Act
+		err := mockRepo.MarkUsed(context.Background(), id)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrResetTokenUsed)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestPasswordResetRepositoryInterface(t *testing.T) {
+	mockRepo := new(MockPasswordResetRepositoryImpl)
+	var _ PasswordResetRepositoryInterface = mockRepo
+	var _ PasswordResetRepositoryInterface = &PasswordResetRepository{}
+	assert.NotNil(t, mockRepo)
+}
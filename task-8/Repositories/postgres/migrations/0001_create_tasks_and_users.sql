@@ -0,0 +1,54 @@
+-- IDs are ObjectID hex strings, so they sort by creation time under the "C" collation like MongoDB
+-- _id values do. A zero due date means the task has none, as in MongoDB.
+CREATE TABLE tasks (
+    id                 TEXT COLLATE "C" PRIMARY KEY,
+    title              TEXT NOT NULL,
+    title_key          TEXT NOT NULL DEFAULT '',
+    description        TEXT NOT NULL DEFAULT '',
+    due_date           TIMESTAMPTZ NOT NULL,
+    status             TEXT NOT NULL,
+    priority           TEXT NOT NULL DEFAULT '',
+    tags               TEXT[],
+    estimate_minutes   INTEGER,
+    created_at         TIMESTAMPTZ NOT NULL,
+    updated_at         TIMESTAMPTZ NOT NULL,
+    version            INTEGER NOT NULL DEFAULT 0,
+    created_by         TEXT COLLATE "C",
+    assignee_id        TEXT COLLATE "C",
+    parent_task_id     TEXT COLLATE "C",
+    recurrence         TEXT NOT NULL DEFAULT '',
+    next_occurrence_id TEXT COLLATE "C",
+    archived           BOOLEAN NOT NULL DEFAULT FALSE,
+    deleted_at         TIMESTAMPTZ,
+    last_notified_at   TIMESTAMPTZ,
+    search             TSVECTOR GENERATED ALWAYS AS (to_tsvector('english', title || ' ' || description)) STORED
+);
+
+CREATE INDEX tasks_parent_task_id_idx ON tasks (parent_task_id);
+CREATE INDEX tasks_status_idx ON tasks (status);
+CREATE INDEX tasks_due_date_idx ON tasks (due_date);
+CREATE INDEX tasks_created_by_idx ON tasks (created_by);
+CREATE INDEX tasks_created_by_title_key_idx ON tasks (created_by, title_key);
+CREATE INDEX tasks_tags_idx ON tasks USING GIN (tags);
+CREATE INDEX tasks_search_idx ON tasks USING GIN (search);
+
+CREATE TABLE users (
+    id             TEXT COLLATE "C" PRIMARY KEY,
+    username       TEXT NOT NULL,
+    username_lower TEXT COLLATE "C" NOT NULL,
+    email          TEXT,
+    email_verified BOOLEAN NOT NULL DEFAULT FALSE,
+    password       TEXT NOT NULL DEFAULT '',
+    role           TEXT NOT NULL,
+    created_at     TIMESTAMPTZ NOT NULL,
+    updated_at     TIMESTAMPTZ NOT NULL,
+    anonymized_at  TIMESTAMPTZ,
+    last_login_at  TIMESTAMPTZ,
+    is_active      BOOLEAN NOT NULL DEFAULT TRUE,
+    first_admin    BOOLEAN NOT NULL DEFAULT FALSE,
+    CONSTRAINT users_username_lower_key UNIQUE (username_lower),
+    CONSTRAINT users_email_key UNIQUE (email)
+);
+
+CREATE UNIQUE INDEX users_first_admin_key ON users (first_admin) WHERE first_admin;
+CREATE INDEX users_role_created_at_idx ON users (role, created_at);
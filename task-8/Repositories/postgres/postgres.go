@@ -0,0 +1,245 @@
+// Package postgres implements the task, user and report repositories on PostgreSQL through
+// database/sql and the pgx driver. The implementations follow the MongoDB repositories: IDs are
+// still ObjectIDs, stored as their hex form, and the same errors and unique fields apply. The
+// schema is created and upgraded by the SQL migrations embedded in this package, which Open runs.
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationLockID serializes migrations when several servers start at once
+const migrationLockID = 7218553309
+
+// Open connects to the PostgreSQL database named by dsn, a URL or key=value connection string,
+// and brings its schema up to date. It fails when the database cannot be reached.
+func Open(ctx context.Context, dsn string) (*sql.DB, error) {
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid PostgreSQL connection string: %v", err)
+	}
+	return OpenConfig(ctx, config)
+}
+
+// OpenConfig connects like Open with a parsed connection configuration
+func OpenConfig(ctx context.Context, config *pgx.ConnConfig) (*sql.DB, error) {
+	db := stdlib.OpenDB(*config)
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %v", err)
+	}
+	if err := Migrate(ctx, db); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+// Migrate applies the embedded migrations that have not run yet, in file name order, and records
+// each one in schema_migrations. Everything runs in one transaction under an advisory lock, so a
+// failed migration leaves the schema as it was and concurrent starts apply each migration once.
+func Migrate(ctx context.Context, db *sql.DB) error {
+	names, err := fs.Glob(migrations, "migrations/*.sql")
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	return withTx(ctx, db, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+			version    TEXT PRIMARY KEY,
+			applied_at TIMESTAMPTZ NOT NULL
+		)`)
+		if err != nil {
+			return err
+		}
+
+		for _, name := range names {
+			version := strings.TrimSuffix(strings.TrimPrefix(name, "migrations/"), ".sql")
+
+			var applied bool
+			err := tx.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = $1)", version).Scan(&applied)
+			if err != nil {
+				return err
+			}
+			if applied {
+				continue
+			}
+
+			script, err := migrations.ReadFile(name)
+			if err != nil {
+				return err
+			}
+			if _, err := tx.ExecContext(ctx, string(script)); err != nil {
+				return fmt.Errorf("migration %s failed: %v", version, err)
+			}
+			if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version, applied_at) VALUES ($1, $2)", version, time.Now()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// withTx runs fn in a transaction, committing when it returns nil and rolling back otherwise
+func withTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// queryArgs collects the arguments of a query as its placeholders are written
+type queryArgs []interface{}
+
+// add appends an argument and returns its placeholder
+func (a *queryArgs) add(value interface{}) string {
+	*a = append(*a, value)
+	return "$" + strconv.Itoa(len(*a))
+}
+
+// where joins conditions into a WHERE clause, or returns "" when there are none
+func where(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+// pageClause returns the LIMIT and OFFSET of a page. A zero limit means no limit, as in MongoDB.
+func pageClause(args *queryArgs, limit, offset int64) string {
+	clause := ""
+	if limit > 0 {
+		clause += " LIMIT " + args.add(limit)
+	}
+	if offset > 0 {
+		clause += " OFFSET " + args.add(offset)
+	}
+	return clause
+}
+
+// containsPattern returns an ILIKE pattern matching text anywhere in a value. Wildcards in
+// text are escaped, so it always matches literally.
+func containsPattern(text string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+	return "%" + escaped + "%"
+}
+
+// isUniqueViolation reports whether err is a unique constraint violation and returns the name
+// of the constraint
+func isUniqueViolation(err error) (string, bool) {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return pgErr.ConstraintName, true
+	}
+	return "", false
+}
+
+// nullableID stores an optional ObjectID as its hex form, or NULL
+func nullableID(id *primitive.ObjectID) interface{} {
+	if id == nil {
+		return nil
+	}
+	return id.Hex()
+}
+
+// nullableObjectID stores an ObjectID as its hex form, or NULL for the zero ID, which MongoDB omits
+func nullableObjectID(id primitive.ObjectID) interface{} {
+	if id.IsZero() {
+		return nil
+	}
+	return id.Hex()
+}
+
+// nullableTime stores an optional timestamp, or NULL
+func nullableTime(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return *t
+}
+
+// parseID reads a stored ObjectID, returning the zero ID for NULL
+func parseID(value sql.NullString) (primitive.ObjectID, error) {
+	if !value.Valid {
+		return primitive.NilObjectID, nil
+	}
+	return primitive.ObjectIDFromHex(value.String)
+}
+
+// parseOptionalID reads a stored ObjectID, returning nil for NULL
+func parseOptionalID(value sql.NullString) (*primitive.ObjectID, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+	id, err := primitive.ObjectIDFromHex(value.String)
+	if err != nil {
+		return nil, err
+	}
+	return &id, nil
+}
+
+// optionalTime reads a stored timestamp, returning nil for NULL
+func optionalTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	t := value.Time
+	return &t
+}
+
+// scanIDs reads a result of single ObjectID column rows, in ID order
+func scanIDs(rows *sql.Rows) ([]primitive.ObjectID, error) {
+	defer rows.Close()
+
+	var ids []primitive.ObjectID
+	for rows.Next() {
+		var hex string
+		if err := rows.Scan(&hex); err != nil {
+			return nil, err
+		}
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		return ids[i].Hex() < ids[j].Hex()
+	})
+	return ids, nil
+}
@@ -0,0 +1,134 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Repositories"
+	"task_manager/Repositories/repositorytest"
+)
+
+// openTestDB connects to the server named by POSTGRES_TEST_DSN, skipping the test when it is unset.
+// Every call migrates a schema of its own, which is dropped when the test ends.
+func openTestDB(t *testing.T) *sql.DB {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	config, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("invalid POSTGRES_TEST_DSN: %v", err)
+	}
+	admin := stdlib.OpenDB(*config.Copy())
+	schema := "taskmanager_test_" + primitive.NewObjectID().Hex()
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		admin.Close()
+		t.Fatalf("failed to create schema: %v", err)
+	}
+
+	config.RuntimeParams["search_path"] = schema
+	db, err := OpenConfig(ctx, config)
+	if err != nil {
+		t.Fatalf("failed to migrate schema: %v", err)
+	}
+
+	t.Cleanup(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		db.Close()
+		admin.ExecContext(ctx, "DROP SCHEMA "+schema+" CASCADE")
+		admin.Close()
+	})
+	return db
+}
+
+func TestTaskRepository_Conformance(t *testing.T) {
+	repositorytest.TaskRepository(t, func(t *testing.T) Repositories.TaskRepositoryInterface {
+		return NewTaskRepository(openTestDB(t))
+	})
+}
+
+func TestUserRepository_Conformance(t *testing.T) {
+	repositorytest.UserRepository(t, func(t *testing.T) Repositories.UserRepositoryInterface {
+		return NewUserRepository(openTestDB(t))
+	})
+}
+
+func TestReportRepository_Conformance(t *testing.T) {
+	repositorytest.ReportRepository(t, func(t *testing.T) (Repositories.ReportRepositoryInterface, Repositories.TaskRepositoryInterface, Repositories.UserRepositoryInterface) {
+		db := openTestDB(t)
+		return NewReportRepository(db), NewTaskRepository(db), NewUserRepository(db)
+	})
+}
+
+func TestSearchQuery(t *testing.T) {
+	t.Run("Success - Matches any word", func(t *testing.T) {
+		// Act
+		query := searchQuery("Invoice the  client!")
+
+		// Assert
+		assert.Equal(t, "invoice | the | client", query)
+	})
+
+	t.Run("Success - Drops tsquery syntax", func(t *testing.T) {
+		// Act
+		query := searchQuery("a & !b | c:*")
+
+		// Assert
+		assert.Equal(t, "a | b | c", query)
+	})
+
+	t.Run("Success - Empty without words", func(t *testing.T) {
+		// Act
+		query := searchQuery(" &! ")
+
+		// Assert
+		assert.Empty(t, query)
+	})
+}
+
+func TestContainsPattern(t *testing.T) {
+	// Act
+	pattern := containsPattern(`50%_off\`)
+
+	// Assert
+	assert.Equal(t, `%50\%\_off\\%`, pattern)
+}
+
+func TestPageClause(t *testing.T) {
+	t.Run("Success - No limit when zero", func(t *testing.T) {
+		// Arrange
+		args := queryArgs{"pending"}
+
+		// Act
+		clause := pageClause(&args, 0, 20)
+
+		// Assert
+		assert.Equal(t, " OFFSET $2", clause)
+		assert.Equal(t, queryArgs{"pending", int64(20)}, args)
+	})
+
+	t.Run("Success - Limit and offset", func(t *testing.T) {
+		// Arrange
+		var args queryArgs
+
+		// Act
+		clause := pageClause(&args, 10, 5)
+
+		// Assert
+		assert.Equal(t, " LIMIT $1 OFFSET $2", clause)
+		assert.Equal(t, queryArgs{int64(10), int64(5)}, args)
+	})
+}
@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// ReportRepository implements Repositories.ReportRepositoryInterface with PostgreSQL
+type ReportRepository struct {
+	db *sql.DB
+}
+
+// NewReportRepository creates a new instance of ReportRepository reading the users and tasks
+// tables of a database migrated by Open
+func NewReportRepository(db *sql.DB) Repositories.ReportRepositoryInterface {
+	return &ReportRepository{db: db}
+}
+
+// GetTasksByUser counts every user's active tasks by status in one query, users without tasks
+// included. A task belongs to its assignee, or else its creator. A non-zero since only counts
+// tasks created at or after it, and tasks due before now are overdue; tasks without a due date
+// are stored with the zero time and are never overdue. Rows are ordered by open tasks, most
+// first, then by username.
+func (rr *ReportRepository) GetTasksByUser(ctx context.Context, since, now time.Time) ([]*Domain.UserTaskReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	args := queryArgs{Domain.StatusPending, Domain.StatusInProgress, Domain.StatusCompleted, time.Time{}, now}
+	join := "t.deleted_at IS NULL AND COALESCE(t.assignee_id, t.created_by) = u.id"
+	if !since.IsZero() {
+		join += " AND t.created_at >= " + args.add(since)
+	}
+
+	rows, err := rr.db.QueryContext(ctx, `SELECT u.id, u.username, r.pending, r.in_progress, r.completed, r.overdue
+		FROM users u
+		CROSS JOIN LATERAL (
+			SELECT
+				COUNT(*) FILTER (WHERE t.status = $1) AS pending,
+				COUNT(*) FILTER (WHERE t.status = $2) AS in_progress,
+				COUNT(*) FILTER (WHERE t.status = $3) AS completed,
+				COUNT(*) FILTER (WHERE t.status <> $3 AND t.due_date > $4 AND t.due_date < $5) AS overdue
+			FROM tasks t WHERE `+join+`
+		) r
+		ORDER BY r.pending + r.in_progress DESC, u.username COLLATE "C", u.id`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*Domain.UserTaskReport{}
+	for rows.Next() {
+		var id string
+		var report Domain.UserTaskReport
+		if err := rows.Scan(&id, &report.Username, &report.Pending, &report.InProgress, &report.Completed, &report.Overdue); err != nil {
+			return nil, err
+		}
+		if report.UserID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, err
+		}
+		report.Open = report.Pending + report.InProgress
+		reports = append(reports, &report)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
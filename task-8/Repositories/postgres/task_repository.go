@@ -0,0 +1,993 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// taskColumns lists the stored task columns in the order scanTask reads them. Tags are read as
+// JSON, which database/sql can scan without driver-specific array types.
+var taskColumns = []string{
+	"id", "title", "title_key", "description", "due_date", "status", "priority", "to_jsonb(tags)",
+	"estimate_minutes", "created_at", "updated_at", "version", "created_by", "assignee_id",
+	"parent_task_id", "recurrence", "next_occurrence_id", "archived", "deleted_at", "last_notified_at",
+}
+
+// priorityRank orders priorities from urgent to low; any other priority ranks as medium
+const priorityRank = `CASE priority WHEN 'urgent' THEN 4 WHEN 'high' THEN 3 WHEN 'low' THEN 1 ELSE 2 END`
+
+// TaskRepository implements Repositories.TaskRepositoryInterface with PostgreSQL
+type TaskRepository struct {
+	db *sql.DB
+}
+
+// NewTaskRepository creates a new instance of TaskRepository on a database migrated by Open
+func NewTaskRepository(db *sql.DB) Repositories.TaskRepositoryInterface {
+	return &TaskRepository{db: db}
+}
+
+// selectTasks returns the SELECT list of the task columns, qualified with table when it is not empty
+func selectTasks(table string) string {
+	if table == "" {
+		return strings.Join(taskColumns, ", ")
+	}
+	columns := make([]string, len(taskColumns))
+	for i, column := range taskColumns {
+		if strings.HasPrefix(column, "to_jsonb(") {
+			columns[i] = "to_jsonb(" + table + "." + strings.TrimPrefix(column, "to_jsonb(")
+		} else {
+			columns[i] = table + "." + column
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// rowScanner is implemented by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanTask reads a task selected with selectTasks, after any extra leading columns in extra
+func scanTask(row rowScanner, extra ...interface{}) (*Domain.Task, error) {
+	var task Domain.Task
+	var id string
+	var tags []byte
+	var estimate sql.NullInt64
+	var createdBy, assigneeID, parentTaskID, nextOccurrenceID sql.NullString
+	var deletedAt, lastNotifiedAt sql.NullTime
+
+	dest := append(extra,
+		&id, &task.Title, &task.TitleKey, &task.Description, &task.DueDate, &task.Status, &task.Priority, &tags,
+		&estimate, &task.CreatedAt, &task.UpdatedAt, &task.Version, &createdBy, &assigneeID,
+		&parentTaskID, &task.Recurrence, &nextOccurrenceID, &task.Archived, &deletedAt, &lastNotifiedAt,
+	)
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if task.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	if task.CreatedBy, err = parseID(createdBy); err != nil {
+		return nil, err
+	}
+	if task.AssigneeID, err = parseOptionalID(assigneeID); err != nil {
+		return nil, err
+	}
+	if task.ParentTaskID, err = parseOptionalID(parentTaskID); err != nil {
+		return nil, err
+	}
+	if task.NextOccurrenceID, err = parseOptionalID(nextOccurrenceID); err != nil {
+		return nil, err
+	}
+	if tags != nil {
+		if err := json.Unmarshal(tags, &task.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if estimate.Valid {
+		minutes := int(estimate.Int64)
+		task.EstimateMinutes = &minutes
+	}
+
+	// Times come back in the local zone; MongoDB returns them in UTC
+	task.CreatedAt = task.CreatedAt.UTC()
+	task.UpdatedAt = task.UpdatedAt.UTC()
+	if task.DeletedAt = optionalTime(deletedAt); task.DeletedAt != nil {
+		*task.DeletedAt = task.DeletedAt.UTC()
+	}
+	if task.LastNotifiedAt = optionalTime(lastNotifiedAt); task.LastNotifiedAt != nil {
+		*task.LastNotifiedAt = task.LastNotifiedAt.UTC()
+	}
+
+	task.ApplyDefaults()
+	return &task, nil
+}
+
+// queryTasks runs a query selecting tasks with selectTasks and reads every row
+func (tr *TaskRepository) queryTasks(ctx context.Context, query string, args queryArgs) ([]*Domain.Task, error) {
+	rows, err := tr.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*Domain.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// taskConditions translates a task filter into SQL conditions, following buildTaskQuery in the
+// MongoDB repository. An empty filter matches every task that has not been soft deleted or archived.
+func taskConditions(filter Domain.TaskFilter, args *queryArgs) []string {
+	var conditions []string
+
+	switch {
+	case filter.OnlyDeleted:
+		conditions = append(conditions, "deleted_at IS NOT NULL")
+	case !filter.IncludeDeleted:
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+
+	switch {
+	case filter.OnlyArchived:
+		conditions = append(conditions, "archived")
+	case !filter.IncludeArchived:
+		conditions = append(conditions, "NOT archived")
+	}
+
+	if filter.Status != "" {
+		conditions = append(conditions, "status = "+args.add(filter.Status))
+	}
+
+	if filter.Priority != "" {
+		if filter.Priority == Domain.PriorityMedium {
+			// Tasks stored without a priority count as medium
+			conditions = append(conditions, "priority IN ("+args.add(Domain.PriorityMedium)+", '')")
+		} else {
+			conditions = append(conditions, "priority = "+args.add(filter.Priority))
+		}
+	}
+
+	if filter.Tag != "" {
+		conditions = append(conditions, "tags @> ARRAY["+args.add(filter.Tag)+"]::text[]")
+	}
+
+	if !filter.CreatedBy.IsZero() {
+		conditions = append(conditions, "created_by = "+args.add(filter.CreatedBy.Hex()))
+	}
+
+	if !filter.AssigneeID.IsZero() {
+		conditions = append(conditions, "assignee_id = "+args.add(filter.AssigneeID.Hex()))
+	}
+
+	if !filter.DueAfter.IsZero() {
+		conditions = append(conditions, "due_date >= "+args.add(filter.DueAfter))
+	}
+	if !filter.DueBefore.IsZero() {
+		conditions = append(conditions, "due_date <= "+args.add(filter.DueBefore))
+	}
+	// Tasks without a due date are stored with the zero time and are never overdue
+	if !filter.OverdueAt.IsZero() {
+		conditions = append(conditions, "due_date > "+args.add(time.Time{}), "due_date < "+args.add(filter.OverdueAt))
+	}
+
+	if (filter.OpenOnly || !filter.OverdueAt.IsZero()) && filter.Status == "" {
+		conditions = append(conditions, "status <> "+args.add(Domain.StatusCompleted))
+	}
+
+	return conditions
+}
+
+// taskOrder returns the ORDER BY clause of a sort. Ties are broken by id, so that consecutive
+// pages are stable.
+func taskOrder(sortBy string) string {
+	switch sortBy {
+	case Domain.SortDueDate:
+		return " ORDER BY due_date, id"
+	case Domain.SortPriority:
+		return " ORDER BY " + priorityRank + " DESC, id"
+	default:
+		return " ORDER BY id"
+	}
+}
+
+// GetAll returns a page of tasks matching the filter along with the total number of matching tasks
+func (tr *TaskRepository) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	total, err := tr.CountTasks(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var args queryArgs
+	query := "SELECT " + selectTasks("") + " FROM tasks" + where(taskConditions(filter, &args)) +
+		taskOrder(pagination.Sort) + pageClause(&args, pagination.Limit, pagination.Offset)
+
+	tasks, err := tr.queryTasks(ctx, query, args)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i, task := range tasks {
+		if tasks[i], err = projectTask(task, pagination.Fields); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	return tasks, total, nil
+}
+
+// projectTask keeps only the fields selected by Repositories.TaskProjection, so selected reads
+// return the same tasks as they do from MongoDB. Rows are narrow, so whole rows are loaded.
+func projectTask(task *Domain.Task, fields []string) (*Domain.Task, error) {
+	projection := Repositories.TaskProjection(fields)
+	if projection == nil {
+		return task, nil
+	}
+
+	data, err := bson.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	elements, err := bson.Raw(data).Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	kept := bson.D{}
+	for _, element := range elements {
+		if key := element.Key(); key == "_id" || projection[key] != nil {
+			kept = append(kept, bson.E{Key: key, Value: element.Value()})
+		}
+	}
+	if data, err = bson.Marshal(kept); err != nil {
+		return nil, err
+	}
+
+	var projected Domain.Task
+	if err := bson.Unmarshal(data, &projected); err != nil {
+		return nil, err
+	}
+	projected.ApplyDefaults()
+	return &projected, nil
+}
+
+// Search returns a page of the tasks matching the filter whose title or description contains any
+// word of the query, most relevant first, along with the total number of matching tasks. Words are
+// stemmed in English as in the MongoDB text index. Each task carries its relevance in Score.
+func (tr *TaskRepository) Search(ctx context.Context, query string, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	words := searchQuery(query)
+	if words == "" {
+		return []*Domain.Task{}, 0, nil
+	}
+
+	var args queryArgs
+	match := "to_tsquery('english', " + args.add(words) + ")"
+	conditions := append(taskConditions(filter, &args), "search @@ "+match)
+
+	var total int64
+	if err := tr.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+where(conditions), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := tr.db.QueryContext(ctx,
+		"SELECT ts_rank(search, "+match+") AS score, "+selectTasks("")+" FROM tasks"+where(conditions)+
+			" ORDER BY score DESC, id"+pageClause(&args, pagination.Limit, pagination.Offset),
+		args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	tasks := []*Domain.Task{}
+	for rows.Next() {
+		var score float64
+		task, err := scanTask(rows, &score)
+		if err != nil {
+			return nil, 0, err
+		}
+		task.Score = &score
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return tasks, total, nil
+}
+
+// searchQuery turns a search into a tsquery matching any of its words. Words keep only letters
+// and digits, so nothing in them is tsquery syntax.
+func searchQuery(query string) string {
+	words := strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, " | ")
+}
+
+// CountTasks returns the number of tasks matching the filter without loading them
+func (tr *TaskRepository) CountTasks(ctx context.Context, filter Domain.TaskFilter) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var args queryArgs
+	var count int64
+	err := tr.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks"+where(taskConditions(filter, &args)), args...).Scan(&count)
+	return count, err
+}
+
+// Stream calls fn for every task matching the filter in ID order, reading one row at a time so
+// large result sets are never held in memory. It stops at the first error fn returns.
+func (tr *TaskRepository) Stream(ctx context.Context, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	// Exports can be large, so they get more time than a single query
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	var args queryArgs
+	rows, err := tr.db.QueryContext(ctx, "SELECT "+selectTasks("")+" FROM tasks"+where(taskConditions(filter, &args))+taskOrder(""), args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(task); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetByID returns a task by its ObjectID, ignoring soft-deleted tasks
+func (tr *TaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	return tr.GetByIDWithFields(ctx, id, nil)
+}
+
+// GetByIDWithFields returns a task like GetByID, keeping only the fields named, by JSON name,
+// along with those Repositories.TaskProjection always includes. No fields loads the whole task.
+func (tr *TaskRepository) GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	row := tr.db.QueryRowContext(ctx, "SELECT "+selectTasks("")+" FROM tasks WHERE id = $1 AND deleted_at IS NULL", objectID.Hex())
+	task, err := scanTask(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, Domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	return projectTask(task, fields)
+}
+
+// GetByParentID returns the active subtasks of a task, oldest first
+func (tr *TaskRepository) GetByParentID(ctx context.Context, parentID string) ([]*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	return tr.queryTasks(ctx, "SELECT "+selectTasks("")+" FROM tasks WHERE parent_task_id = $1 AND deleted_at IS NULL ORDER BY id", queryArgs{objectID.Hex()})
+}
+
+// EnsureIndexes does nothing: the migrations Open runs create the indexes task queries rely on
+func (tr *TaskRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// taskExecer is implemented by *sql.DB and *sql.Tx
+type taskExecer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// insertTask inserts a task with every stored field
+func insertTask(ctx context.Context, db taskExecer, task *Domain.Task) error {
+	var estimate interface{}
+	if task.EstimateMinutes != nil {
+		estimate = *task.EstimateMinutes
+	}
+
+	_, err := db.ExecContext(ctx, `INSERT INTO tasks (
+		id, title, title_key, description, due_date, status, priority, tags, estimate_minutes,
+		created_at, updated_at, version, created_by, assignee_id, parent_task_id, recurrence,
+		next_occurrence_id, archived, deleted_at, last_notified_at
+	) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)`,
+		task.ID.Hex(), task.Title, task.TitleKey, task.Description, task.DueDate, task.Status, task.Priority,
+		tagsValue(task.Tags), estimate, task.CreatedAt, task.UpdatedAt, task.Version,
+		nullableObjectID(task.CreatedBy), nullableID(task.AssigneeID), nullableID(task.ParentTaskID), task.Recurrence,
+		nullableID(task.NextOccurrenceID), task.Archived, nullableTime(task.DeletedAt), nullableTime(task.LastNotifiedAt),
+	)
+	return err
+}
+
+// tagsValue stores tags as a text array, or NULL when there are none as MongoDB omits them
+func tagsValue(tags []string) interface{} {
+	if len(tags) == 0 {
+		return nil
+	}
+	return tags
+}
+
+// Create creates a new task
+func (tr *TaskRepository) Create(ctx context.Context, task *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	task.ID = primitive.NewObjectID()
+	task.CreatedAt = time.Now()
+	task.UpdatedAt = time.Now()
+	task.TitleKey = Domain.NormalizeTitle(task.Title)
+
+	return insertTask(ctx, tr.db, task)
+}
+
+// CreateMany inserts several tasks in a single transaction.
+// A CreatedAt that is already set is kept so imported tasks retain their original creation time.
+func (tr *TaskRepository) CreateMany(ctx context.Context, tasks []*Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	for _, task := range tasks {
+		task.ID = primitive.NewObjectID()
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		task.UpdatedAt = now
+		task.TitleKey = Domain.NormalizeTitle(task.Title)
+	}
+
+	return withTx(ctx, tr.db, func(tx *sql.Tx) error {
+		for _, task := range tasks {
+			if err := insertTask(ctx, tx, task); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ExistsByTitleAndDueDate reports whether an active task has exactly this title and due date
+func (tr *TaskRepository) ExistsByTitleAndDueDate(ctx context.Context, title string, dueDate time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var exists bool
+	err := tr.db.QueryRowContext(ctx,
+		"SELECT EXISTS (SELECT 1 FROM tasks WHERE title = $1 AND due_date = $2 AND deleted_at IS NULL)",
+		title, dueDate,
+	).Scan(&exists)
+	return exists, err
+}
+
+// FindOpenByTitle returns an active task created by createdBy that is not completed and whose title
+// matches title once both are normalized with Domain.NormalizeTitle
+func (tr *TaskRepository) FindOpenByTitle(ctx context.Context, createdBy primitive.ObjectID, title string) (*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	row := tr.db.QueryRowContext(ctx,
+		"SELECT "+selectTasks("")+" FROM tasks WHERE created_by = $1 AND title_key = $2 AND status <> $3 AND deleted_at IS NULL ORDER BY id LIMIT 1",
+		createdBy.Hex(), Domain.NormalizeTitle(title), Domain.StatusCompleted,
+	)
+	task, err := scanTask(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, Domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+	return task, nil
+}
+
+// Update updates an existing task when its version still matches
+func (tr *TaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	task.UpdatedAt = time.Now()
+	if err := updateTask(ctx, tr.db, objectID, task, false); err != nil {
+		return err
+	}
+
+	task.Version++
+	return nil
+}
+
+// taskQueryer is implemented by *sql.DB and *sql.Tx
+type taskQueryer interface {
+	taskExecer
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// updateTask writes the mutable fields of a task, following buildTaskUpdate in the MongoDB
+// repository, when the stored version matches. With spawning set it also requires that no next
+// occurrence was linked yet. A missed update is explained by missedUpdateError.
+func updateTask(ctx context.Context, db taskQueryer, id primitive.ObjectID, task *Domain.Task, spawning bool) error {
+	var estimate interface{}
+	if task.EstimateMinutes != nil {
+		estimate = *task.EstimateMinutes
+	}
+
+	var args queryArgs
+	set := []string{
+		"title = " + args.add(task.Title),
+		"title_key = " + args.add(Domain.NormalizeTitle(task.Title)),
+		"description = " + args.add(task.Description),
+		"due_date = " + args.add(task.DueDate),
+		"status = " + args.add(task.Status),
+		"priority = " + args.add(task.Priority),
+		"recurrence = " + args.add(task.Recurrence),
+		"updated_at = " + args.add(task.UpdatedAt),
+		// A nil assignee means the task was unassigned
+		"assignee_id = " + args.add(nullableID(task.AssigneeID)),
+		"tags = " + args.add(tagsValue(task.Tags)),
+		"estimate_minutes = " + args.add(estimate),
+		"version = version + 1",
+	}
+
+	// The next occurrence link is only ever added, never removed
+	if task.NextOccurrenceID != nil {
+		set = append(set, "next_occurrence_id = "+args.add(task.NextOccurrenceID.Hex()))
+	}
+
+	// Reminders are recorded through MarkNotified; an update can only clear them
+	if task.LastNotifiedAt == nil {
+		set = append(set, "last_notified_at = NULL")
+	}
+
+	conditions := []string{
+		"id = " + args.add(id.Hex()),
+		"deleted_at IS NULL",
+		"version = " + args.add(task.Version),
+	}
+	if spawning {
+		conditions = append(conditions, "next_occurrence_id IS NULL")
+	}
+
+	result, err := db.ExecContext(ctx, "UPDATE tasks SET "+strings.Join(set, ", ")+where(conditions), args...)
+	if err != nil {
+		return err
+	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if updated == 0 {
+		return missedUpdateError(ctx, db, id)
+	}
+	return nil
+}
+
+// missedUpdateError explains why a versioned update matched nothing: the task is
+// either gone or was changed by someone else since it was read
+func missedUpdateError(ctx context.Context, db taskQueryer, id primitive.ObjectID) error {
+	var exists bool
+	err := db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM tasks WHERE id = $1 AND deleted_at IS NULL)", id.Hex()).Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return Domain.ErrVersionConflict
+	}
+	return Domain.ErrTaskNotFound
+}
+
+// CompleteRecurring saves a completed recurring task and inserts its next occurrence
+// in a single transaction, linking the two through NextOccurrenceID.
+// The update only matches while no occurrence has been spawned, so a task spawns at most once.
+func (tr *TaskRepository) CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	now := time.Now()
+	next.ID = primitive.NewObjectID()
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	next.TitleKey = Domain.NormalizeTitle(next.Title)
+	task.UpdatedAt = now
+	task.NextOccurrenceID = &next.ID
+
+	err = withTx(ctx, tr.db, func(tx *sql.Tx) error {
+		if err := updateTask(ctx, tx, objectID, task, true); err != nil {
+			return err
+		}
+		return insertTask(ctx, tx, next)
+	})
+	if err != nil {
+		task.NextOccurrenceID = nil
+		return err
+	}
+
+	task.Version++
+	return nil
+}
+
+// UpdateStatusMany sets the status of every active task in ids with a single UPDATE.
+// When fromStatuses is not empty only tasks currently in one of those statuses are changed.
+// It returns the matched and modified counts and the tasks it changed, as saved, with the
+// status each had before, so the change can be announced.
+func (tr *TaskRepository) UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	hexIDs := make([]string, len(ids))
+	for i, id := range ids {
+		hexIDs[i] = id.Hex()
+	}
+
+	var args queryArgs
+	conditions := []string{
+		"t.id = old.id",
+		"t.id = ANY(" + args.add(hexIDs) + "::text[])",
+		"t.deleted_at IS NULL",
+	}
+	if len(fromStatuses) > 0 {
+		conditions = append(conditions, "t.status = ANY("+args.add(fromStatuses)+"::text[])")
+	}
+
+	// The self join exposes each row as it was before the update, along with its old status
+	rows, err := tr.db.QueryContext(ctx,
+		"UPDATE tasks t SET status = "+args.add(status)+", updated_at = "+args.add(time.Now())+", version = t.version + 1"+
+			" FROM tasks old"+where(conditions)+" RETURNING old.status, "+selectTasks("t"),
+		args...)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	defer rows.Close()
+
+	var changes []Domain.TaskStatusChange
+	for rows.Next() {
+		var fromStatus string
+		task, err := scanTask(rows, &fromStatus)
+		if err != nil {
+			return 0, 0, nil, err
+		}
+		changes = append(changes, Domain.TaskStatusChange{Task: task, FromStatus: fromStatus})
+	}
+	if err := rows.Err(); err != nil {
+		return 0, 0, nil, err
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Task.ID.Hex() < changes[j].Task.ID.Hex()
+	})
+
+	// Every matched task gets a new version, so each one counts as modified
+	matched := int64(len(changes))
+	return matched, matched, changes, nil
+}
+
+// DeleteByStatus soft deletes every active task with the given status.
+// Parents that still have active subtasks in another status are left in place.
+// It returns the matched and modified counts and the IDs of the tasks it deleted, so
+// their comments can follow them into the trash.
+func (tr *TaskRepository) DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	rows, err := tr.db.QueryContext(ctx, `UPDATE tasks SET deleted_at = $1, updated_at = $1
+		WHERE status = $2 AND deleted_at IS NULL AND id NOT IN (
+			SELECT parent_task_id FROM tasks
+			WHERE deleted_at IS NULL AND parent_task_id IS NOT NULL AND status <> $2
+		)
+		RETURNING id`, now, status)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	ids, err := scanIDs(rows)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	return int64(len(ids)), int64(len(ids)), ids, nil
+}
+
+// Delete soft deletes a task by its ObjectID by stamping deleted_at
+func (tr *TaskRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	now := time.Now()
+	return tr.execOne(ctx, "UPDATE tasks SET deleted_at = $1, updated_at = $1 WHERE id = $2 AND deleted_at IS NULL", now, objectID.Hex())
+}
+
+// execOne runs a statement that targets a single task, returning ErrTaskNotFound when it matches none
+func (tr *TaskRepository) execOne(ctx context.Context, query string, args ...interface{}) error {
+	result, err := tr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return Domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// Restore clears deleted_at on a soft-deleted task
+func (tr *TaskRepository) Restore(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return tr.execOne(ctx, "UPDATE tasks SET deleted_at = NULL, updated_at = $1 WHERE id = $2 AND deleted_at IS NOT NULL", time.Now(), objectID.Hex())
+}
+
+// SetArchived archives or unarchives an active task and bumps its version
+func (tr *TaskRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return tr.execOne(ctx,
+		"UPDATE tasks SET archived = $1, updated_at = $2, version = version + 1 WHERE id = $3 AND deleted_at IS NULL",
+		archived, time.Now(), objectID.Hex())
+}
+
+// execCount runs a statement and returns the number of rows it changed
+func (tr *TaskRepository) execCount(ctx context.Context, query string, args ...interface{}) (int64, error) {
+	result, err := tr.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// ArchiveCompleted archives every active completed task last updated before the given time and
+// returns how many it archived. Tasks keep no completion time, so the last update stands in for it.
+func (tr *TaskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return tr.execCount(ctx, `UPDATE tasks SET archived = TRUE, updated_at = $1, version = version + 1
+		WHERE status = $2 AND deleted_at IS NULL AND NOT archived AND updated_at < $3`,
+		time.Now(), Domain.StatusCompleted, completedBefore)
+}
+
+// Purge permanently removes tasks that were soft deleted before the given time
+func (tr *TaskRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return tr.execCount(ctx, "DELETE FROM tasks WHERE deleted_at IS NOT NULL AND deleted_at <= $1", deletedBefore)
+}
+
+// PurgeByStatus permanently removes tasks with the given status, in the trash or not, last updated
+// before the given time. Parents that keep a subtask which is not removed are left in place.
+// It returns the IDs of the tasks it removed.
+func (tr *TaskRepository) PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rows, err := tr.db.QueryContext(ctx, `DELETE FROM tasks
+		WHERE status = $1 AND updated_at <= $2 AND id NOT IN (
+			SELECT parent_task_id FROM tasks
+			WHERE parent_task_id IS NOT NULL AND NOT (status = $1 AND updated_at <= $2)
+		)
+		RETURNING id`, status, updatedBefore)
+	if err != nil {
+		return nil, err
+	}
+	return scanIDs(rows)
+}
+
+// ReassignCreator hands every task created by one user, soft-deleted ones included, to another user.
+// It returns the number of tasks changed.
+func (tr *TaskRepository) ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return tr.execCount(ctx,
+		"UPDATE tasks SET created_by = $1, updated_at = $2, version = version + 1 WHERE created_by = $3",
+		toUserID.Hex(), time.Now(), fromUserID.Hex())
+}
+
+// UnassignUser clears the assignee of every task assigned to the user, soft-deleted ones included.
+// It returns the number of tasks changed.
+func (tr *TaskRepository) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return tr.execCount(ctx,
+		"UPDATE tasks SET assignee_id = NULL, updated_at = $1, version = version + 1 WHERE assignee_id = $2",
+		time.Now(), userID.Hex())
+}
+
+// GetDueForReminder returns up to limit open tasks due before dueBefore that still need a reminder,
+// soonest due first. A task needs one when it was never reminded, or when it is overdue at now and
+// its last reminder was sent before it fell due.
+func (tr *TaskRepository) GetDueForReminder(ctx context.Context, dueBefore, now time.Time, limit int64) ([]*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	args := queryArgs{Domain.StatusCompleted, time.Time{}, dueBefore, now}
+	// Tasks without a due date are stored with the zero time
+	query := "SELECT " + selectTasks("") + ` FROM tasks
+		WHERE deleted_at IS NULL AND status <> $1 AND due_date > $2 AND due_date < $3
+		AND (last_notified_at IS NULL OR (due_date < $4 AND last_notified_at < due_date))
+		ORDER BY due_date, id` + pageClause(&args, limit, 0)
+
+	return tr.queryTasks(ctx, query, args)
+}
+
+// MarkNotified records when a reminder was sent for a task. It is bookkeeping rather than an edit,
+// so neither the version nor the update time changes.
+func (tr *TaskRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return tr.execOne(ctx, "UPDATE tasks SET last_notified_at = $1 WHERE id = $2 AND deleted_at IS NULL", at, id.Hex())
+}
+
+// GetTags returns the distinct tags used by tasks matching the filter, sorted alphabetically
+func (tr *TaskRepository) GetTags(ctx context.Context, filter Domain.TaskFilter) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var args queryArgs
+	rows, err := tr.db.QueryContext(ctx,
+		`SELECT DISTINCT tag COLLATE "C" AS tag FROM tasks, unnest(tags) AS tag`+where(taskConditions(filter, &args))+" ORDER BY tag",
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []string{}
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// GetStats counts the tasks matching the filter by status, overdue, due soon and created per day
+// in a single query
+func (tr *TaskRepository) GetStats(ctx context.Context, filter Domain.TaskFilter, now time.Time) (*Domain.TaskStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now = now.UTC()
+	since := statsWindowStart(now)
+
+	var args queryArgs
+	matching := "SELECT status, due_date, created_at FROM tasks" + where(taskConditions(filter, &args))
+	completed := args.add(Domain.StatusCompleted)
+	noDueDate := args.add(time.Time{})
+	nowArg := args.add(now)
+	weekArg := args.add(now.AddDate(0, 0, 7))
+	sinceArg := args.add(since)
+
+	// Tasks without a due date are stored with the zero time and never count as overdue or due soon.
+	// Each row is one status, the overdue count, the due-soon count, or one day of creations.
+	rows, err := tr.db.QueryContext(ctx, `WITH matching AS (`+matching+`)
+		SELECT 'status', status, COUNT(*) FROM matching GROUP BY status
+		UNION ALL
+		SELECT 'overdue', '', COUNT(*) FROM matching WHERE status <> `+completed+` AND due_date > `+noDueDate+` AND due_date < `+nowArg+`
+		UNION ALL
+		SELECT 'due_soon', '', COUNT(*) FROM matching WHERE status <> `+completed+` AND due_date >= `+nowArg+` AND due_date < `+weekArg+`
+		UNION ALL
+		SELECT 'created', to_char(created_at AT TIME ZONE 'UTC', 'YYYY-MM-DD'), COUNT(*) FROM matching
+		WHERE created_at >= `+sinceArg+` GROUP BY 2`,
+		args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := &Domain.TaskStats{
+		ByStatus: map[string]int64{
+			Domain.StatusPending:    0,
+			Domain.StatusInProgress: 0,
+			Domain.StatusCompleted:  0,
+		},
+	}
+	createdPerDay := map[string]int64{}
+	for rows.Next() {
+		var kind, key string
+		var count int64
+		if err := rows.Scan(&kind, &key, &count); err != nil {
+			return nil, err
+		}
+		switch kind {
+		case "status":
+			stats.ByStatus[key] = count
+		case "overdue":
+			stats.Overdue = count
+		case "due_soon":
+			stats.DueNext7Days = count
+		case "created":
+			createdPerDay[key] = count
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	stats.CreatedPerDay = fillDailyCounts(createdPerDay, since, Domain.StatsCreatedDays)
+
+	return stats, nil
+}
+
+// statsWindowStart returns midnight UTC of the first day in the created-per-day window ending today
+func statsWindowStart(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day-(Domain.StatsCreatedDays-1), 0, 0, 0, 0, time.UTC)
+}
+
+// fillDailyCounts lists one entry per day starting at since, using zero for days without tasks
+func fillDailyCounts(counts map[string]int64, since time.Time, days int) []Domain.DailyCount {
+	daily := make([]Domain.DailyCount, days)
+	for i := range daily {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		daily[i] = Domain.DailyCount{Date: date, Count: counts[date]}
+	}
+	return daily
+}
@@ -0,0 +1,494 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// userColumns lists the stored user columns in the order scanUser reads them
+const userColumns = "id, username, username_lower, email, email_verified, password, role, created_at, updated_at, anonymized_at, last_login_at, is_active, first_admin"
+
+// userColumnsWithoutPassword selects the same columns as userColumns with an empty password hash
+const userColumnsWithoutPassword = "id, username, username_lower, email, email_verified, '', role, created_at, updated_at, anonymized_at, last_login_at, is_active, first_admin"
+
+// UserRepository implements Repositories.UserRepositoryInterface with PostgreSQL
+type UserRepository struct {
+	db *sql.DB
+}
+
+// NewUserRepository creates a new instance of UserRepository on a database migrated by Open
+func NewUserRepository(db *sql.DB) Repositories.UserRepositoryInterface {
+	return &UserRepository{db: db}
+}
+
+// scanUser reads a user selected with userColumns
+func scanUser(row rowScanner) (*Domain.User, error) {
+	var user Domain.User
+	var id string
+	var email sql.NullString
+	var anonymizedAt, lastLoginAt sql.NullTime
+
+	err := row.Scan(&id, &user.Username, &user.UsernameLower, &email, &user.EmailVerified, &user.Password, &user.Role,
+		&user.CreatedAt, &user.UpdatedAt, &anonymizedAt, &lastLoginAt, &user.IsActive, &user.FirstAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+		return nil, err
+	}
+	user.Email = email.String
+
+	// Times come back in the local zone; MongoDB returns them in UTC
+	user.CreatedAt = user.CreatedAt.UTC()
+	user.UpdatedAt = user.UpdatedAt.UTC()
+	if user.AnonymizedAt = optionalTime(anonymizedAt); user.AnonymizedAt != nil {
+		*user.AnonymizedAt = user.AnonymizedAt.UTC()
+	}
+	if user.LastLoginAt = optionalTime(lastLoginAt); user.LastLoginAt != nil {
+		*user.LastLoginAt = user.LastLoginAt.UTC()
+	}
+
+	return &user, nil
+}
+
+// queryUser returns the single user a query selects, or ErrUserNotFound
+func (ur *UserRepository) queryUser(ctx context.Context, query string, args ...interface{}) (*Domain.User, error) {
+	user, err := scanUser(ur.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, Domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// userConditions translates a UserFilter into SQL conditions. The search text is escaped,
+// so it always matches literally.
+func userConditions(filter Domain.UserFilter, args *queryArgs) []string {
+	var conditions []string
+	if filter.Query != "" {
+		pattern := args.add(containsPattern(filter.Query))
+		conditions = append(conditions, "(username ILIKE "+pattern+" OR email ILIKE "+pattern+")")
+	}
+	if filter.Role != "" {
+		conditions = append(conditions, "role = "+args.add(filter.Role))
+	}
+	return conditions
+}
+
+// userOrder returns the ORDER BY clause of a user listing: by creation unless sortBy is
+// SortUsername. The ID breaks ties so pages never overlap.
+func userOrder(sortBy string) string {
+	if sortBy == Domain.SortUsername {
+		return " ORDER BY username_lower, id"
+	}
+	return " ORDER BY created_at, id"
+}
+
+// GetAll returns one page of users matching the filter with the total number of matches.
+// Users are ordered by creation unless pagination.Sort is SortUsername; the ID breaks ties
+// so pages never overlap.
+func (ur *UserRepository) GetAll(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var args queryArgs
+	conditions := where(userConditions(filter, &args))
+
+	var total int64
+	if err := ur.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+conditions, args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := ur.db.QueryContext(ctx,
+		"SELECT "+userColumns+" FROM users"+conditions+userOrder(pagination.Sort)+pageClause(&args, pagination.Limit, pagination.Offset),
+		args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	users := []*Domain.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, 0, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// GetDirectory returns one page of the IDs and usernames of users whose username contains query,
+// ignoring case, with the total number of matches. Only those two columns are read. Users are
+// ordered as by GetAll.
+func (ur *UserRepository) GetDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var args queryArgs
+	var conditions []string
+	if query != "" {
+		conditions = append(conditions, "username ILIKE "+args.add(containsPattern(query)))
+	}
+
+	var total int64
+	if err := ur.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users"+where(conditions), args...).Scan(&total); err != nil {
+		return nil, 0, err
+	}
+
+	rows, err := ur.db.QueryContext(ctx,
+		"SELECT id, username FROM users"+where(conditions)+userOrder(pagination.Sort)+pageClause(&args, pagination.Limit, pagination.Offset),
+		args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []*Domain.UserDirectoryEntry{}
+	for rows.Next() {
+		var id string
+		var entry Domain.UserDirectoryEntry
+		if err := rows.Scan(&id, &entry.Username); err != nil {
+			return nil, 0, err
+		}
+		if entry.ID, err = primitive.ObjectIDFromHex(id); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// Stream calls fn for every user matching the filter in creation order, reading one row at
+// a time. Password hashes are never read. It stops at the first error fn returns.
+func (ur *UserRepository) Stream(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	// Exports can be large, so they get more time than a single query
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	var args queryArgs
+	rows, err := ur.db.QueryContext(ctx,
+		"SELECT "+userColumnsWithoutPassword+" FROM users"+where(userConditions(filter, &args))+userOrder(""),
+		args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// GetByID retrieves a user by ID
+func (ur *UserRepository) GetByID(ctx context.Context, id string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	return ur.queryUser(ctx, "SELECT "+userColumns+" FROM users WHERE id = $1", objectID.Hex())
+}
+
+// GetByUsername retrieves a user by username, ignoring case and surrounding whitespace
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return ur.queryUser(ctx, "SELECT "+userColumns+" FROM users WHERE username_lower = $1", Domain.NormalizeUsername(username))
+}
+
+// GetByEmail retrieves a user by email address
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return ur.queryUser(ctx, "SELECT "+userColumns+" FROM users WHERE email = $1", Domain.NormalizeEmail(email))
+}
+
+// nullableEmail stores an email address, or NULL when there is none so users without one
+// never collide on the unique constraint
+func nullableEmail(email string) interface{} {
+	if email == "" {
+		return nil
+	}
+	return email
+}
+
+// Create creates a new user
+func (ur *UserRepository) Create(ctx context.Context, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	user.ID = primitive.NewObjectID()
+	user.UsernameLower = Domain.NormalizeUsername(user.Username)
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = time.Now()
+
+	_, err := ur.db.ExecContext(ctx, "INSERT INTO users ("+userColumns+") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)",
+		user.ID.Hex(), user.Username, user.UsernameLower, nullableEmail(user.Email), user.EmailVerified, user.Password, user.Role,
+		user.CreatedAt, user.UpdatedAt, nullableTime(user.AnonymizedAt), nullableTime(user.LastLoginAt), user.IsActive, user.FirstAdmin,
+	)
+	return translateUserWriteError(err)
+}
+
+// execUser runs a statement that targets a single user, returning ErrUserNotFound when it matches none
+func (ur *UserRepository) execUser(ctx context.Context, query string, args ...interface{}) error {
+	result, err := ur.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return translateUserWriteError(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return Domain.ErrUserNotFound
+	}
+	return nil
+}
+
+// Update updates an existing user's username, password and role
+func (ur *UserRepository) Update(ctx context.Context, id string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	user.UpdatedAt = time.Now()
+
+	return ur.execUser(ctx,
+		"UPDATE users SET username = $1, username_lower = $2, password = $3, role = $4, updated_at = $5 WHERE id = $6",
+		user.Username, Domain.NormalizeUsername(user.Username), user.Password, user.Role, user.UpdatedAt, objectID.Hex())
+}
+
+// UpdateByUsername updates the role of an existing user found by username, matching it like GetByUsername
+func (ur *UserRepository) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	user.UpdatedAt = time.Now()
+
+	return ur.execUser(ctx,
+		"UPDATE users SET role = $1, updated_at = $2 WHERE username_lower = $3",
+		user.Role, user.UpdatedAt, Domain.NormalizeUsername(username))
+}
+
+// UpdateProfile stores a user's username and email address with its verification status
+func (ur *UserRepository) UpdateProfile(ctx context.Context, id string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	user.UpdatedAt = time.Now()
+
+	return ur.execUser(ctx,
+		"UPDATE users SET username = $1, username_lower = $2, email = $3, email_verified = $4, updated_at = $5 WHERE id = $6",
+		user.Username, Domain.NormalizeUsername(user.Username), nullableEmail(user.Email), user.EmailVerified, user.UpdatedAt, objectID.Hex())
+}
+
+// MarkEmailVerified marks a user's email address as verified. It only matches while the user
+// still has that address, so a token for a replaced address cannot verify the new one.
+func (ur *UserRepository) MarkEmailVerified(ctx context.Context, id string, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	return ur.execUser(ctx,
+		"UPDATE users SET email_verified = TRUE, updated_at = $1 WHERE id = $2 AND email = $3",
+		time.Now(), objectID.Hex(), email)
+}
+
+// UpdateLastLogin records when the user last logged in. Only last_login_at is written, so a
+// login never overwrites a concurrent change to the rest of the record.
+func (ur *UserRepository) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	return ur.execUser(ctx, "UPDATE users SET last_login_at = $1 WHERE id = $2", at, objectID.Hex())
+}
+
+// SetActive activates or deactivates a user
+func (ur *UserRepository) SetActive(ctx context.Context, id string, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	return ur.execUser(ctx, "UPDATE users SET is_active = $1, updated_at = $2 WHERE id = $3", active, time.Now(), objectID.Hex())
+}
+
+// IsActive reports whether a user may use the tokens issued to them. Only the flag is read.
+func (ur *UserRepository) IsActive(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, Domain.ErrInvalidUserID
+	}
+
+	var active bool
+	err = ur.db.QueryRowContext(ctx, "SELECT is_active FROM users WHERE id = $1", objectID.Hex()).Scan(&active)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return false, Domain.ErrUserNotFound
+		}
+		return false, err
+	}
+
+	return active, nil
+}
+
+// Anonymize scrubs a user's personal data but keeps the record, so tasks, comments and audit
+// entries that reference the user stay valid. The username becomes a placeholder, the email
+// address is removed and the password is cleared so the account can no longer log in.
+func (ur *UserRepository) Anonymize(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	now := time.Now()
+	username := Domain.AnonymizedUsername(id)
+
+	return ur.execUser(ctx, `UPDATE users SET username = $1, username_lower = $2, email = NULL, password = '',
+		role = $3, email_verified = FALSE, anonymized_at = $4, updated_at = $4 WHERE id = $5`,
+		username, Domain.NormalizeUsername(username), Domain.RoleUser, now, objectID.Hex())
+}
+
+// Delete removes a user
+func (ur *UserRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	return ur.execUser(ctx, "DELETE FROM users WHERE id = $1", objectID.Hex())
+}
+
+// CountUsers returns the total number of users in the database
+func (ur *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	err := ur.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&count)
+	return count, err
+}
+
+// CountByRole returns the number of users with the given role
+func (ur *UserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	err := ur.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM users WHERE role = $1", role).Scan(&count)
+	return count, err
+}
+
+// ClaimFirstAdmin makes a user the admin unless another user has already claimed that as the first
+// user. Concurrent first registrations all see an empty database, so the partial unique index on
+// first_admin is what lets exactly one of them win; the others get false and stay regular users.
+func (ur *UserRepository) ClaimFirstAdmin(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, Domain.ErrInvalidUserID
+	}
+
+	result, err := ur.db.ExecContext(ctx,
+		"UPDATE users SET role = $1, first_admin = TRUE, updated_at = $2 WHERE id = $3",
+		Domain.RoleAdmin, time.Now(), objectID.Hex())
+	if _, unique := isUniqueViolation(err); unique {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if affected == 0 {
+		return false, Domain.ErrUserNotFound
+	}
+
+	return true, nil
+}
+
+// EnsureIndexes does nothing: the migrations Open runs create the unique username, email and
+// first admin indexes
+func (ur *UserRepository) EnsureIndexes(ctx context.Context) error {
+	return nil
+}
+
+// translateUserWriteError maps a unique violation to the "email already exists" or
+// "username already exists" error, depending on the constraint named in the error
+func translateUserWriteError(err error) error {
+	constraint, unique := isUniqueViolation(err)
+	if !unique {
+		return err
+	}
+	if strings.Contains(constraint, "email") {
+		return Domain.ErrEmailTaken
+	}
+	return Domain.ErrUsernameTaken
+}
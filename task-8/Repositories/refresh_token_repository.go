@@ -0,0 +1,140 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// RefreshTokenRepositoryInterface defines the contract for refresh token data access
+type RefreshTokenRepositoryInterface interface {
+	Create(ctx context.Context, token *Domain.RefreshToken) error
+	GetByHash(ctx context.Context, tokenHash string) (*Domain.RefreshToken, error)
+	Revoke(ctx context.Context, id primitive.ObjectID) error
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeAllForUser(ctx context.Context, userID primitive.ObjectID) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// RefreshTokenRepository implements RefreshTokenRepositoryInterface with MongoDB
+type RefreshTokenRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRefreshTokenRepository creates a new instance of RefreshTokenRepository
+func NewRefreshTokenRepository(client *mongo.Client, dbName string) RefreshTokenRepositoryInterface {
+	collection := client.Database(dbName).Collection("refresh_tokens")
+	return &RefreshTokenRepository{
+		collection: collection,
+	}
+}
+
+// Create stores a refresh token
+func (rr *RefreshTokenRepository) Create(ctx context.Context, token *Domain.RefreshToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	token.ID = primitive.NewObjectID()
+	token.CreatedAt = time.Now()
+
+	_, err := rr.collection.InsertOne(ctx, token)
+	return err
+}
+
+// GetByHash returns the refresh token stored under a hash, revoked or not
+func (rr *RefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*Domain.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var token Domain.RefreshToken
+	err := rr.collection.FindOne(ctx, bson.M{"token_hash": tokenHash}).Decode(&token)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrRefreshTokenNotFound
+		}
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Revoke marks a refresh token as revoked. It fails if the token was already revoked,
+// so two requests racing to rotate the same token cannot both succeed.
+func (rr *RefreshTokenRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := rr.collection.UpdateOne(ctx,
+		bson.M{"_id": id, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return Domain.ErrRefreshTokenRevoked
+	}
+
+	return nil
+}
+
+// RevokeFamily revokes every outstanding refresh token of a family
+func (rr *RefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := rr.collection.UpdateMany(ctx,
+		bson.M{"family_id": familyID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+// RevokeAllForUser revokes every outstanding refresh token of a user, ending all their logins
+func (rr *RefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := rr.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	return err
+}
+
+// EnsureIndexes creates the lookup indexes and the TTL index that removes expired tokens
+func (rr *RefreshTokenRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := rr.collection.Indexes().CreateMany(ctx, refreshTokenIndexes())
+	return err
+}
+
+// refreshTokenIndexes lists the indexes maintained on the refresh_tokens collection
+func refreshTokenIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "token_hash", Value: 1}},
+			Options: options.Index().SetName("token_hash_1").SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "family_id", Value: 1}},
+			Options: options.Index().SetName("family_id_1"),
+		},
+		{
+			Keys:    bson.D{{Key: "expires_at", Value: 1}},
+			Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}},
+			Options: options.Index().SetName("user_id_1"),
+		},
+	}
+}
@@ -0,0 +1,119 @@
+package Repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockRefreshTokenRepositoryImpl for testing purposes
+type MockRefreshTokenRepositoryImpl struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepositoryImpl) Create(ctx context.Context, token *Domain.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepositoryImpl) GetByHash(ctx context.Context, tokenHash string) (*Domain.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepositoryImpl) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepositoryImpl) RevokeAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestRefreshTokenIndexes(t *testing.T) {
+	t.Run("Hashes are unique and tokens expire at expires_at", func(t *testing.T) {
+		// Act
+		indexes := refreshTokenIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 4)
+		assert.Equal(t, bson.D{{Key: "token_hash", Value: 1}}, indexes[0].Keys)
+		assert.True(t, *indexes[0].Options.Unique)
+		assert.Equal(t, bson.D{{Key: "family_id", Value: 1}}, indexes[1].Keys)
+		assert.Equal(t, bson.D{{Key: "expires_at", Value: 1}}, indexes[2].Keys)
+		assert.Equal(t, int32(0), *indexes[2].Options.ExpireAfterSeconds)
+		assert.Equal(t, bson.D{{Key: "user_id", Value: 1}}, indexes[3].Keys)
+	})
+}
+
+func TestRefreshTokenRepository_GetByHash(t *testing.T) {
+	t.Run("Success - token found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRefreshTokenRepositoryImpl)
+		expected := &Domain.RefreshToken{ID: primitive.NewObjectID(), FamilyID: "family", TokenHash: "hash"}
+		mockRepo.On("GetByHash", "hash").Return(expected, nil)
+
+		// Act
+		token, err := mockRepo.GetByHash(context.Background(), "hash")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expected, token)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - token not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRefreshTokenRepositoryImpl)
+		mockRepo.On("GetByHash", "unknown").Return(nil, Domain.ErrRefreshTokenNotFound)
+
+		// Act
+		token, err := mockRepo.GetByHash(context.Background(), "unknown")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrRefreshTokenNotFound)
+		assert.Nil(t, token)
+	})
+}
+
+func TestRefreshTokenRepository_Revoke(t *testing.T) {
+	t.Run("Error - token already revoked", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRefreshTokenRepositoryImpl)
+		id := primitive.NewObjectID()
+		mockRepo.On("Revoke", id).Return(Domain.ErrRefreshTokenRevoked)
+
+		// Act
+		err := mockRepo.Revoke(context.Background(), id)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrRefreshTokenRevoked)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestRefreshTokenRepositoryInterface(t *testing.T) {
+	mockRepo := new(MockRefreshTokenRepositoryImpl)
+	var _ RefreshTokenRepositoryInterface = mockRepo
+	assert.NotNil(t, mockRepo)
+}
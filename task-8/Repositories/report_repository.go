@@ -0,0 +1,116 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// ReportRepositoryInterface defines the contract for reports that span several collections
+type ReportRepositoryInterface interface {
+	GetTasksByUser(ctx context.Context, since, now time.Time) ([]*Domain.UserTaskReport, error)
+}
+
+// ReportRepository implements ReportRepositoryInterface with MongoDB
+type ReportRepository struct {
+	users          *mongo.Collection
+	taskCollection string
+	retrier        *Infrastructure.Retrier
+}
+
+// NewReportRepository creates a new instance of ReportRepository reading the users collection
+// and the tasks stored in taskCollection. Reads are retried on transient errors.
+func NewReportRepository(client *mongo.Client, dbName, taskCollection string, retrier *Infrastructure.Retrier) ReportRepositoryInterface {
+	return &ReportRepository{
+		users:          client.Database(dbName).Collection("users"),
+		taskCollection: taskCollection,
+		retrier:        retrier,
+	}
+}
+
+// GetTasksByUser counts every user's active tasks by status in one aggregation, users without
+// tasks included. A non-zero since only counts tasks created at or after it, and tasks due
+// before now are overdue. Rows are ordered by open tasks, most first, then by username.
+func (rr *ReportRepository) GetTasksByUser(ctx context.Context, since, now time.Time) ([]*Domain.UserTaskReport, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	reports := []*Domain.UserTaskReport{}
+	err := rr.retrier.Read(ctx, "reports.tasks_by_user", func(ctx context.Context) error {
+		cursor, err := rr.users.Aggregate(ctx, buildTasksByUserPipeline(rr.taskCollection, since, now))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &reports)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return reports, nil
+}
+
+// buildTasksByUserPipeline builds an aggregation over users that looks up the tasks each one
+// owns, the assignee or else the creator, and counts them by status in the lookup itself so
+// no task documents leave the server. Tasks without a due date are stored with the zero time
+// and are never overdue.
+func buildTasksByUserPipeline(taskCollection string, since, now time.Time) mongo.Pipeline {
+	match := bson.M{
+		"deleted_at": nil,
+		"$expr":      bson.M{"$eq": bson.A{bson.M{"$ifNull": bson.A{"$assignee_id", "$created_by"}}, "$$user_id"}},
+	}
+	if !since.IsZero() {
+		match["created_at"] = bson.M{"$gte": since}
+	}
+
+	countWhere := func(condition interface{}) bson.M {
+		return bson.M{"$sum": bson.M{"$cond": bson.A{condition, 1, 0}}}
+	}
+	withStatus := func(status string) bson.M {
+		return countWhere(bson.M{"$eq": bson.A{"$status", status}})
+	}
+	countOf := func(field string) bson.M {
+		return bson.M{"$ifNull": bson.A{"$counts." + field, 0}}
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$lookup", Value: bson.M{
+			"from": taskCollection,
+			"let":  bson.M{"user_id": "$_id"},
+			"pipeline": bson.A{
+				bson.M{"$match": match},
+				bson.M{"$group": bson.M{
+					"_id":         nil,
+					"pending":     withStatus(Domain.StatusPending),
+					"in_progress": withStatus(Domain.StatusInProgress),
+					"completed":   withStatus(Domain.StatusCompleted),
+					"overdue": countWhere(bson.M{"$and": bson.A{
+						bson.M{"$ne": bson.A{"$status", Domain.StatusCompleted}},
+						bson.M{"$gt": bson.A{"$due_date", time.Time{}}},
+						bson.M{"$lt": bson.A{"$due_date", now}},
+					}}),
+				}},
+			},
+			"as": "counts",
+		}}},
+		{{Key: "$unwind", Value: bson.M{"path": "$counts", "preserveNullAndEmptyArrays": true}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":         0,
+			"user_id":     "$_id",
+			"username":    1,
+			"pending":     countOf("pending"),
+			"in_progress": countOf("in_progress"),
+			"completed":   countOf("completed"),
+			"overdue":     countOf("overdue"),
+			"open":        bson.M{"$add": bson.A{countOf("pending"), countOf("in_progress")}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "open", Value: -1}, {Key: "username", Value: 1}, {Key: "user_id", Value: 1}}}},
+	}
+}
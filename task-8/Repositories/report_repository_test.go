@@ -0,0 +1,38 @@
+package Repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestReportRepositoryInterface(t *testing.T) {
+	var _ ReportRepositoryInterface = &ReportRepository{}
+}
+
+func TestBuildTasksByUserPipeline(t *testing.T) {
+	t.Run("Success - looks up the configured task collection", func(t *testing.T) {
+		// Act
+		pipeline := buildTasksByUserPipeline("work_items", time.Time{}, time.Now())
+
+		// Assert
+		lookup := pipeline[0][0].Value.(bson.M)
+		assert.Equal(t, "work_items", lookup["from"])
+		match := lookup["pipeline"].(bson.A)[0].(bson.M)["$match"].(bson.M)
+		assert.NotContains(t, match, "created_at")
+	})
+
+	t.Run("Success - since limits tasks by creation time", func(t *testing.T) {
+		// Arrange
+		since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		pipeline := buildTasksByUserPipeline("tasks", since, time.Now())
+
+		// Assert
+		match := pipeline[0][0].Value.(bson.M)["pipeline"].(bson.A)[0].(bson.M)["$match"].(bson.M)
+		assert.Equal(t, bson.M{"$gte": since}, match["created_at"])
+	})
+}
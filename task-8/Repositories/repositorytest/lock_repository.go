@@ -0,0 +1,77 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task_manager/Repositories"
+)
+
+// NewLockRepository returns an empty lock repository for one test
+type NewLockRepository func(t *testing.T) Repositories.LockRepositoryInterface
+
+// LockRepository runs the lock repository conformance suite
+func LockRepository(t *testing.T, newRepo NewLockRepository) {
+	ctx := context.Background()
+
+	t.Run("Acquire grants a free lease to one holder and lets it renew", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+
+		// Act
+		first, err := repo.Acquire(ctx, "reminders", "a", time.Minute)
+		require.NoError(t, err)
+		other, err := repo.Acquire(ctx, "reminders", "b", time.Minute)
+		require.NoError(t, err)
+		renewed, err := repo.Acquire(ctx, "reminders", "a", time.Minute)
+		require.NoError(t, err)
+		otherName, err := repo.Acquire(ctx, "cleanup", "b", time.Minute)
+		require.NoError(t, err)
+
+		// Assert
+		assert.True(t, first)
+		assert.False(t, other)
+		assert.True(t, renewed)
+		assert.True(t, otherName)
+	})
+
+	t.Run("Acquire takes over an expired lease", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		acquired, err := repo.Acquire(ctx, "reminders", "a", time.Millisecond)
+		require.NoError(t, err)
+		require.True(t, acquired)
+		time.Sleep(10 * time.Millisecond)
+
+		// Act
+		acquired, err = repo.Acquire(ctx, "reminders", "b", time.Minute)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, acquired)
+	})
+
+	t.Run("Release frees the lease only for its holder", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		acquired, err := repo.Acquire(ctx, "reminders", "a", time.Minute)
+		require.NoError(t, err)
+		require.True(t, acquired)
+
+		// Act
+		require.NoError(t, repo.Release(ctx, "reminders", "b"))
+		stillHeld, err := repo.Acquire(ctx, "reminders", "b", time.Minute)
+		require.NoError(t, err)
+		require.NoError(t, repo.Release(ctx, "reminders", "a"))
+		freed, err := repo.Acquire(ctx, "reminders", "b", time.Minute)
+		require.NoError(t, err)
+
+		// Assert
+		assert.False(t, stillHeld)
+		assert.True(t, freed)
+	})
+}
@@ -0,0 +1,65 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// NewReportRepository returns a report repository for one test along with the empty task and
+// user repositories it reports on
+type NewReportRepository func(t *testing.T) (Repositories.ReportRepositoryInterface, Repositories.TaskRepositoryInterface, Repositories.UserRepositoryInterface)
+
+// ReportRepository runs the report repository conformance suite
+func ReportRepository(t *testing.T, newRepo NewReportRepository) {
+	ctx := context.Background()
+
+	t.Run("GetTasksByUser counts each owner's active tasks, busiest first", func(t *testing.T) {
+		// Arrange
+		repo, tasks, users := newRepo(t)
+		alice := createUser(t, users, &Domain.User{Username: "alice", Role: Domain.RoleUser})
+		bob := createUser(t, users, &Domain.User{Username: "bob", Role: Domain.RoleUser})
+		carol := createUser(t, users, &Domain.User{Username: "carol", Role: Domain.RoleUser})
+
+		createTask(t, tasks, &Domain.Task{Title: "late", DueDate: dueDate(-1), Status: Domain.StatusPending, CreatedBy: alice.ID})
+		createTask(t, tasks, &Domain.Task{Title: "soon", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: alice.ID})
+		createTask(t, tasks, &Domain.Task{Title: "done late", DueDate: dueDate(-1), Status: Domain.StatusCompleted, CreatedBy: alice.ID})
+		createTask(t, tasks, &Domain.Task{Title: "undated", Status: Domain.StatusInProgress, CreatedBy: alice.ID, AssigneeID: &bob.ID})
+		deleted := createTask(t, tasks, &Domain.Task{Title: "deleted", Status: Domain.StatusPending, CreatedBy: bob.ID})
+		require.NoError(t, tasks.Delete(ctx, deleted.ID.Hex()))
+
+		// Act
+		reports, err := repo.GetTasksByUser(ctx, time.Time{}, dueDate(0))
+		require.NoError(t, err)
+		future, err := repo.GetTasksByUser(ctx, time.Now().Add(time.Hour), dueDate(0))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []*Domain.UserTaskReport{
+			{UserID: alice.ID, Username: "alice", Pending: 2, Completed: 1, Overdue: 1, Open: 2},
+			{UserID: bob.ID, Username: "bob", InProgress: 1, Open: 1},
+			{UserID: carol.ID, Username: "carol"},
+		}, reports)
+		assert.Equal(t, []*Domain.UserTaskReport{
+			{UserID: alice.ID, Username: "alice"},
+			{UserID: bob.ID, Username: "bob"},
+			{UserID: carol.ID, Username: "carol"},
+		}, future)
+	})
+
+	t.Run("GetTasksByUser returns an empty list when there are no users", func(t *testing.T) {
+		repo, _, _ := newRepo(t)
+
+		reports, err := repo.GetTasksByUser(ctx, time.Time{}, time.Now())
+
+		assert.NoError(t, err)
+		assert.Empty(t, reports)
+		assert.NotNil(t, reports)
+	})
+}
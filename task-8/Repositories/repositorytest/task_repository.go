@@ -0,0 +1,805 @@
+// Package repositorytest holds conformance suites for the repository interfaces. Every
+// implementation runs the same suite, so the in-memory repositories behave like the MongoDB ones.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// NewTaskRepository returns an empty task repository for one test
+type NewTaskRepository func(t *testing.T) Repositories.TaskRepositoryInterface
+
+// dueDate is a due date MongoDB stores without losing precision
+func dueDate(days int) time.Time {
+	return time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, days)
+}
+
+// createTask stores a task and fails the test if that is not possible
+func createTask(t *testing.T, repo Repositories.TaskRepositoryInterface, task *Domain.Task) *Domain.Task {
+	t.Helper()
+	require.NoError(t, repo.Create(context.Background(), task))
+	return task
+}
+
+// taskTitles lists the titles of tasks in order
+func taskTitles(tasks []*Domain.Task) []string {
+	titles := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		titles = append(titles, task.Title)
+	}
+	return titles
+}
+
+// TaskRepository runs the task repository conformance suite
+func TaskRepository(t *testing.T, newRepo NewTaskRepository) {
+	ctx := context.Background()
+
+	t.Run("Create assigns an ID and timestamps", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task := &Domain.Task{Title: "Write report", DueDate: dueDate(1), Status: Domain.StatusPending}
+
+		// Act
+		err := repo.Create(ctx, task)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, task.ID.IsZero())
+		assert.WithinDuration(t, time.Now(), task.CreatedAt, time.Second)
+		assert.WithinDuration(t, time.Now(), task.UpdatedAt, time.Second)
+	})
+
+	t.Run("GetByID returns the stored task with defaults applied", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		assignee := primitive.NewObjectID()
+		task := createTask(t, repo, &Domain.Task{
+			Title:       "Write report",
+			Description: "Quarterly numbers",
+			DueDate:     dueDate(1),
+			Status:      Domain.StatusPending,
+			Tags:        []string{"work"},
+			CreatedBy:   primitive.NewObjectID(),
+			AssigneeID:  &assignee,
+		})
+
+		// Act
+		found, err := repo.GetByID(ctx, task.ID.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, task.ID, found.ID)
+		assert.Equal(t, "Write report", found.Title)
+		assert.Equal(t, "Quarterly numbers", found.Description)
+		assert.True(t, dueDate(1).Equal(found.DueDate))
+		assert.Equal(t, Domain.PriorityMedium, found.Priority)
+		assert.Equal(t, Domain.RecurrenceNone, found.Recurrence)
+		assert.Equal(t, []string{"work"}, found.Tags)
+		assert.Equal(t, task.CreatedBy, found.CreatedBy)
+		require.NotNil(t, found.AssigneeID)
+		assert.Equal(t, assignee, *found.AssigneeID)
+	})
+
+	t.Run("GetByID reports unknown and malformed IDs", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(ctx, primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+
+		_, err = repo.GetByID(ctx, "invalid-id")
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+	})
+
+	t.Run("GetAll pages through tasks in creation order", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		for _, title := range []string{"one", "two", "three"} {
+			createTask(t, repo, &Domain.Task{Title: title, DueDate: dueDate(1), Status: Domain.StatusPending})
+		}
+
+		// Act
+		all, total, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{})
+		require.NoError(t, err)
+		page, pageTotal, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []string{"one", "two", "three"}, taskTitles(all))
+		assert.Equal(t, int64(3), pageTotal)
+		assert.Equal(t, []string{"two"}, taskTitles(page))
+	})
+
+	t.Run("GetAll returns an empty list when there are no tasks", func(t *testing.T) {
+		repo := newRepo(t)
+
+		tasks, total, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, tasks)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("GetAll applies every filter", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		owner := primitive.NewObjectID()
+		assignee := primitive.NewObjectID()
+		createTask(t, repo, &Domain.Task{Title: "legacy", DueDate: dueDate(1), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "urgent", DueDate: dueDate(2), Status: Domain.StatusInProgress, Priority: Domain.PriorityUrgent, Tags: []string{"work", "ops"}, CreatedBy: owner})
+		createTask(t, repo, &Domain.Task{Title: "done", DueDate: dueDate(3), Status: Domain.StatusCompleted, Priority: Domain.PriorityLow, CreatedBy: owner, AssigneeID: &assignee})
+		createTask(t, repo, &Domain.Task{Title: "undated", Status: Domain.StatusPending, Priority: Domain.PriorityHigh})
+
+		testCases := []struct {
+			name     string
+			filter   Domain.TaskFilter
+			expected []string
+		}{
+			{"status", Domain.TaskFilter{Status: Domain.StatusPending}, []string{"legacy", "undated"}},
+			{"medium priority includes tasks without one", Domain.TaskFilter{Priority: Domain.PriorityMedium}, []string{"legacy"}},
+			{"priority", Domain.TaskFilter{Priority: Domain.PriorityUrgent}, []string{"urgent"}},
+			{"tag", Domain.TaskFilter{Tag: "ops"}, []string{"urgent"}},
+			{"creator", Domain.TaskFilter{CreatedBy: owner}, []string{"urgent", "done"}},
+			{"assignee", Domain.TaskFilter{AssigneeID: assignee}, []string{"done"}},
+			{"inclusive due date range", Domain.TaskFilter{DueAfter: dueDate(2), DueBefore: dueDate(3)}, []string{"urgent", "done"}},
+			{"overdue skips completed and undated tasks", Domain.TaskFilter{OverdueAt: dueDate(5)}, []string{"legacy", "urgent"}},
+			{"open only", Domain.TaskFilter{OpenOnly: true}, []string{"legacy", "urgent", "undated"}},
+			{"open only yields to an explicit status", Domain.TaskFilter{OpenOnly: true, Status: Domain.StatusCompleted}, []string{"done"}},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Act
+				tasks, total, err := repo.GetAll(ctx, tc.filter, Domain.Pagination{})
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, taskTitles(tasks))
+				assert.Equal(t, int64(len(tc.expected)), total)
+
+				count, err := repo.CountTasks(ctx, tc.filter)
+				require.NoError(t, err)
+				assert.Equal(t, int64(len(tc.expected)), count)
+			})
+		}
+	})
+
+	t.Run("GetAll sorts by due date or priority and breaks ties by ID", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		createTask(t, repo, &Domain.Task{Title: "late low", DueDate: dueDate(3), Status: Domain.StatusPending, Priority: Domain.PriorityLow})
+		createTask(t, repo, &Domain.Task{Title: "early", DueDate: dueDate(1), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "urgent", DueDate: dueDate(2), Status: Domain.StatusPending, Priority: Domain.PriorityUrgent})
+		createTask(t, repo, &Domain.Task{Title: "medium", DueDate: dueDate(1), Status: Domain.StatusPending, Priority: Domain.PriorityMedium})
+
+		// Act
+		byDueDate, _, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Sort: Domain.SortDueDate})
+		require.NoError(t, err)
+		byPriority, _, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Sort: Domain.SortPriority})
+		require.NoError(t, err)
+		priorityPage, _, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Sort: Domain.SortPriority, Limit: 2, Offset: 1})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []string{"early", "medium", "urgent", "late low"}, taskTitles(byDueDate))
+		assert.Equal(t, []string{"urgent", "early", "medium", "late low"}, taskTitles(byPriority))
+		assert.Equal(t, []string{"early", "medium"}, taskTitles(priorityPage))
+	})
+
+	t.Run("GetAll and GetByIDWithFields load only the selected fields", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		assignee := primitive.NewObjectID()
+		createTask(t, repo, &Domain.Task{Title: "low", Description: "Long", DueDate: dueDate(1), Status: Domain.StatusPending, Priority: Domain.PriorityLow})
+		task := createTask(t, repo, &Domain.Task{
+			Title:       "urgent",
+			Description: "Long",
+			DueDate:     dueDate(2),
+			Status:      Domain.StatusPending,
+			Priority:    Domain.PriorityUrgent,
+			Tags:        []string{"work"},
+			CreatedBy:   primitive.NewObjectID(),
+			AssigneeID:  &assignee,
+		})
+		fields := []string{"title", "due_date"}
+
+		// Act
+		found, err := repo.GetByIDWithFields(ctx, task.ID.Hex(), fields)
+		require.NoError(t, err)
+		listed, total, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Fields: fields})
+		require.NoError(t, err)
+		byPriority, _, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{Sort: Domain.SortPriority, Fields: fields})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, task.ID, found.ID)
+		assert.Equal(t, "urgent", found.Title)
+		assert.True(t, dueDate(2).Equal(found.DueDate))
+		assert.Empty(t, found.Description)
+		assert.Empty(t, found.Tags)
+		assert.Empty(t, found.Status)
+		// Visibility and ETags depend on these, so they are always loaded
+		assert.Equal(t, task.CreatedBy, found.CreatedBy)
+		require.NotNil(t, found.AssigneeID)
+		assert.Equal(t, assignee, *found.AssigneeID)
+		assert.Equal(t, task.Version, found.Version)
+		assert.WithinDuration(t, task.UpdatedAt, found.UpdatedAt, time.Millisecond)
+
+		assert.Equal(t, int64(2), total)
+		assert.Equal(t, []string{"low", "urgent"}, taskTitles(listed))
+		assert.Empty(t, listed[0].Description)
+		assert.Equal(t, []string{"urgent", "low"}, taskTitles(byPriority))
+		assert.Empty(t, byPriority[0].Description)
+	})
+
+	t.Run("Stream visits matching tasks in order and stops on error", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		for _, title := range []string{"one", "two", "three"} {
+			createTask(t, repo, &Domain.Task{Title: title, DueDate: dueDate(1), Status: Domain.StatusPending})
+		}
+		var visited []string
+		stop := assert.AnError
+
+		// Act
+		err := repo.Stream(ctx, Domain.TaskFilter{}, func(task *Domain.Task) error {
+			visited = append(visited, task.Title)
+			if len(visited) == 2 {
+				return stop
+			}
+			return nil
+		})
+
+		// Assert
+		assert.ErrorIs(t, err, stop)
+		assert.Equal(t, []string{"one", "two"}, visited)
+	})
+
+	t.Run("Search ranks matching tasks by relevance within the filter", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		owner := primitive.NewObjectID()
+		best := createTask(t, repo, &Domain.Task{Title: "Invoice", Description: "Invoice the client", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: owner})
+		createTask(t, repo, &Domain.Task{Title: "Invoice", Description: "Call the bank", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: owner})
+		createTask(t, repo, &Domain.Task{Title: "Water plants", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: owner})
+		createTask(t, repo, &Domain.Task{Title: "Invoice", DueDate: dueDate(1), Status: Domain.StatusPending})
+		deleted := createTask(t, repo, &Domain.Task{Title: "Invoice", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: owner})
+		require.NoError(t, repo.Delete(ctx, deleted.ID.Hex()))
+		archived := createTask(t, repo, &Domain.Task{Title: "Invoice", DueDate: dueDate(1), Status: Domain.StatusCompleted, CreatedBy: owner})
+		require.NoError(t, repo.SetArchived(ctx, archived.ID.Hex(), true))
+		filter := Domain.TaskFilter{CreatedBy: owner}
+
+		// Act
+		found, total, err := repo.Search(ctx, "invoice", filter, Domain.Pagination{})
+		page, pageTotal, pageErr := repo.Search(ctx, "invoice", filter, Domain.Pagination{Limit: 1, Offset: 1})
+		none, noneTotal, noneErr := repo.Search(ctx, "invoice", Domain.TaskFilter{AssigneeID: owner}, Domain.Pagination{})
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), total)
+		require.Len(t, found, 2)
+		assert.Equal(t, best.ID, found[0].ID)
+		require.NotNil(t, found[0].Score)
+		require.NotNil(t, found[1].Score)
+		assert.Greater(t, *found[0].Score, *found[1].Score)
+		assert.Equal(t, "Call the bank", found[1].Description)
+
+		require.NoError(t, pageErr)
+		assert.Equal(t, int64(2), pageTotal)
+		require.Len(t, page, 1)
+		assert.Equal(t, found[1].ID, page[0].ID)
+
+		require.NoError(t, noneErr)
+		assert.Equal(t, int64(0), noneTotal)
+		assert.Empty(t, none)
+
+		stored, err := repo.GetByID(ctx, best.ID.Hex())
+		require.NoError(t, err)
+		assert.Nil(t, stored.Score, "only search results carry a score")
+	})
+
+	t.Run("CreateMany keeps imported creation times", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		imported := time.Date(2020, time.March, 1, 9, 0, 0, 0, time.UTC)
+		tasks := []*Domain.Task{
+			{Title: "imported", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedAt: imported},
+			{Title: "new", DueDate: dueDate(1), Status: Domain.StatusPending},
+		}
+
+		// Act
+		err := repo.CreateMany(ctx, tasks)
+
+		// Assert
+		require.NoError(t, err)
+		assert.NotEqual(t, tasks[0].ID, tasks[1].ID)
+		stored, err := repo.GetByID(ctx, tasks[0].ID.Hex())
+		require.NoError(t, err)
+		assert.True(t, imported.Equal(stored.CreatedAt))
+		assert.WithinDuration(t, time.Now(), tasks[1].CreatedAt, time.Second)
+	})
+
+	t.Run("ExistsByTitleAndDueDate matches active tasks exactly", func(t *testing.T) {
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Pay rent", DueDate: dueDate(1), Status: Domain.StatusPending})
+
+		exists, err := repo.ExistsByTitleAndDueDate(ctx, "Pay rent", dueDate(1))
+		require.NoError(t, err)
+		assert.True(t, exists)
+
+		exists, err = repo.ExistsByTitleAndDueDate(ctx, "Pay rent", dueDate(2))
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		require.NoError(t, repo.Delete(ctx, task.ID.Hex()))
+		exists, err = repo.ExistsByTitleAndDueDate(ctx, "Pay rent", dueDate(1))
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("FindOpenByTitle matches normalized titles of the creator's open tasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		owner, other := primitive.NewObjectID(), primitive.NewObjectID()
+		open := createTask(t, repo, &Domain.Task{Title: "Deploy  release", DueDate: dueDate(1), Status: Domain.StatusInProgress, CreatedBy: owner})
+		createTask(t, repo, &Domain.Task{Title: "Write notes", DueDate: dueDate(1), Status: Domain.StatusCompleted, CreatedBy: owner})
+		createTask(t, repo, &Domain.Task{Title: "Review", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: other})
+
+		// Act
+		found, err := repo.FindOpenByTitle(ctx, owner, " deploy RELEASE ")
+		_, completedErr := repo.FindOpenByTitle(ctx, owner, "Write notes")
+		_, otherErr := repo.FindOpenByTitle(ctx, owner, "Review")
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, open.ID, found.ID)
+		assert.ErrorIs(t, completedErr, Domain.ErrTaskNotFound)
+		assert.ErrorIs(t, otherErr, Domain.ErrTaskNotFound)
+	})
+
+	t.Run("FindOpenByTitle follows renames and ignores trashed tasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		owner := primitive.NewObjectID()
+		task := createTask(t, repo, &Domain.Task{Title: "Draft", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: owner})
+		task.Title = "Final"
+		require.NoError(t, repo.Update(ctx, task.ID.Hex(), task))
+
+		// Act
+		_, oldErr := repo.FindOpenByTitle(ctx, owner, "Draft")
+		renamed, renamedErr := repo.FindOpenByTitle(ctx, owner, "final")
+		require.NoError(t, repo.Delete(ctx, task.ID.Hex()))
+		_, trashedErr := repo.FindOpenByTitle(ctx, owner, "final")
+
+		// Assert
+		assert.ErrorIs(t, oldErr, Domain.ErrTaskNotFound)
+		require.NoError(t, renamedErr)
+		assert.Equal(t, task.ID, renamed.ID)
+		assert.ErrorIs(t, trashedErr, Domain.ErrTaskNotFound)
+	})
+
+	t.Run("Update saves fields, clears empty optional ones and bumps the version", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		assignee := primitive.NewObjectID()
+		task := createTask(t, repo, &Domain.Task{Title: "Draft", DueDate: dueDate(1), Status: Domain.StatusPending, Tags: []string{"work"}, AssigneeID: &assignee})
+		task.Title = "Final"
+		task.Status = Domain.StatusInProgress
+		task.Priority = Domain.PriorityHigh
+		task.Tags = nil
+		task.AssigneeID = nil
+
+		// Act
+		err := repo.Update(ctx, task.ID.Hex(), task)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, task.Version)
+		stored, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "Final", stored.Title)
+		assert.Equal(t, Domain.StatusInProgress, stored.Status)
+		assert.Equal(t, Domain.PriorityHigh, stored.Priority)
+		assert.Empty(t, stored.Tags)
+		assert.Nil(t, stored.AssigneeID)
+		assert.Equal(t, 1, stored.Version)
+	})
+
+	t.Run("Update rejects a stale version", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Draft", DueDate: dueDate(1), Status: Domain.StatusPending})
+		stale := *task
+		require.NoError(t, repo.Update(ctx, task.ID.Hex(), task))
+
+		// Act
+		err := repo.Update(ctx, task.ID.Hex(), &stale)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrVersionConflict)
+	})
+
+	t.Run("CompleteRecurring saves the task and spawns its next occurrence once", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Standup", DueDate: dueDate(1), Status: Domain.StatusPending, Recurrence: Domain.RecurrenceDaily})
+		task.Status = Domain.StatusCompleted
+		next := task.NextOccurrence()
+
+		// Act
+		err := repo.CompleteRecurring(ctx, task.ID.Hex(), task, next)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, 1, task.Version)
+		stored, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.StatusCompleted, stored.Status)
+		assert.Equal(t, &next.ID, stored.NextOccurrenceID)
+		spawned, err := repo.GetByID(ctx, next.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.StatusPending, spawned.Status)
+
+		// A task spawns its next occurrence only once
+		err = repo.CompleteRecurring(ctx, task.ID.Hex(), task, task.NextOccurrence())
+		assert.ErrorIs(t, err, Domain.ErrVersionConflict)
+	})
+
+	t.Run("Update reports unknown, deleted and malformed tasks", func(t *testing.T) {
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Draft", DueDate: dueDate(1), Status: Domain.StatusPending})
+		require.NoError(t, repo.Delete(ctx, task.ID.Hex()))
+
+		assert.EqualError(t, repo.Update(ctx, primitive.NewObjectID().Hex(), &Domain.Task{}), "task not found")
+		assert.EqualError(t, repo.Update(ctx, task.ID.Hex(), task), "task not found")
+		assert.EqualError(t, repo.Update(ctx, "invalid-id", &Domain.Task{}), "invalid task ID format")
+	})
+
+	t.Run("UpdateStatusMany changes only tasks in the given statuses", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		pending := createTask(t, repo, &Domain.Task{Title: "pending", DueDate: dueDate(1), Status: Domain.StatusPending})
+		done := createTask(t, repo, &Domain.Task{Title: "done", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		deleted := createTask(t, repo, &Domain.Task{Title: "deleted", DueDate: dueDate(1), Status: Domain.StatusPending})
+		require.NoError(t, repo.Delete(ctx, deleted.ID.Hex()))
+
+		// Act
+		matched, modified, changes, err := repo.UpdateStatusMany(ctx,
+			[]primitive.ObjectID{pending.ID, done.ID, deleted.ID},
+			Domain.StatusInProgress,
+			[]string{Domain.StatusPending},
+		)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), matched)
+		assert.Equal(t, int64(1), modified)
+		stored, err := repo.GetByID(ctx, pending.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.StatusInProgress, stored.Status)
+		assert.Equal(t, 1, stored.Version)
+		require.Len(t, changes, 1)
+		assert.Equal(t, Domain.StatusPending, changes[0].FromStatus)
+		assert.Equal(t, pending.ID, changes[0].Task.ID)
+		assert.Equal(t, Domain.StatusInProgress, changes[0].Task.Status)
+		assert.Equal(t, 1, changes[0].Task.Version)
+		assert.WithinDuration(t, stored.UpdatedAt, changes[0].Task.UpdatedAt, time.Millisecond)
+		stored, err = repo.GetByID(ctx, done.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.StatusCompleted, stored.Status)
+	})
+
+	t.Run("DeleteByStatus keeps parents with unfinished subtasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		parent := createTask(t, repo, &Domain.Task{Title: "parent", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "child", DueDate: dueDate(1), Status: Domain.StatusPending, ParentTaskID: &parent.ID})
+		done := createTask(t, repo, &Domain.Task{Title: "done", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+
+		// Act
+		matched, modified, ids, err := repo.DeleteByStatus(ctx, Domain.StatusCompleted)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), matched)
+		assert.Equal(t, int64(1), modified)
+		assert.Equal(t, []primitive.ObjectID{done.ID}, ids)
+		_, err = repo.GetByID(ctx, parent.ID.Hex())
+		assert.NoError(t, err)
+		_, err = repo.GetByID(ctx, done.ID.Hex())
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+	})
+
+	t.Run("Delete and Restore move a task in and out of the trash", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Draft", DueDate: dueDate(1), Status: Domain.StatusPending})
+
+		// Act and assert
+		require.NoError(t, repo.Delete(ctx, task.ID.Hex()))
+		_, err := repo.GetByID(ctx, task.ID.Hex())
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.EqualError(t, repo.Delete(ctx, task.ID.Hex()), "task not found")
+
+		trash, total, err := repo.GetAll(ctx, Domain.TaskFilter{OnlyDeleted: true}, Domain.Pagination{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		require.Len(t, trash, 1)
+		assert.NotNil(t, trash[0].DeletedAt)
+
+		require.NoError(t, repo.Restore(ctx, task.ID.Hex()))
+		restored, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		assert.Nil(t, restored.DeletedAt)
+		assert.EqualError(t, repo.Restore(ctx, task.ID.Hex()), "task not found")
+	})
+
+	t.Run("Delete and Restore report malformed IDs", func(t *testing.T) {
+		repo := newRepo(t)
+
+		assert.EqualError(t, repo.Delete(ctx, "invalid-id"), "invalid task ID format")
+		assert.EqualError(t, repo.Restore(ctx, "invalid-id"), "invalid task ID format")
+	})
+
+	t.Run("SetArchived hides a task from listings until it is unarchived", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Shipped", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "Open", DueDate: dueDate(1), Status: Domain.StatusPending})
+
+		// Act and assert
+		require.NoError(t, repo.SetArchived(ctx, task.ID.Hex(), true))
+		archived, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		assert.True(t, archived.Archived)
+		assert.Equal(t, task.Version+1, archived.Version)
+
+		listed, total, err := repo.GetAll(ctx, Domain.TaskFilter{}, Domain.Pagination{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []string{"Open"}, taskTitles(listed))
+		onlyArchived, _, err := repo.GetAll(ctx, Domain.TaskFilter{OnlyArchived: true}, Domain.Pagination{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Shipped"}, taskTitles(onlyArchived))
+		count, err := repo.CountTasks(ctx, Domain.TaskFilter{IncludeArchived: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		require.NoError(t, repo.SetArchived(ctx, task.ID.Hex(), false))
+		count, err = repo.CountTasks(ctx, Domain.TaskFilter{})
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
+
+	t.Run("SetArchived reports missing, deleted and malformed tasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		deleted := createTask(t, repo, &Domain.Task{Title: "Deleted", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		require.NoError(t, repo.Delete(ctx, deleted.ID.Hex()))
+
+		// Act and assert
+		assert.ErrorIs(t, repo.SetArchived(ctx, deleted.ID.Hex(), true), Domain.ErrTaskNotFound)
+		assert.ErrorIs(t, repo.SetArchived(ctx, primitive.NewObjectID().Hex(), true), Domain.ErrTaskNotFound)
+		assert.EqualError(t, repo.SetArchived(ctx, "invalid-id", true), "invalid task ID format")
+	})
+
+	t.Run("ArchiveCompleted archives only active completed tasks updated before the cutoff", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		createTask(t, repo, &Domain.Task{Title: "done", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "pending", DueDate: dueDate(1), Status: Domain.StatusPending})
+		trashed := createTask(t, repo, &Domain.Task{Title: "trashed", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		require.NoError(t, repo.Delete(ctx, trashed.ID.Hex()))
+
+		// Act
+		none, err := repo.ArchiveCompleted(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		archived, err := repo.ArchiveCompleted(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+		again, err := repo.ArchiveCompleted(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(0), none)
+		assert.Equal(t, int64(1), archived)
+		assert.Equal(t, int64(0), again, "archived tasks are not archived twice")
+		listed, _, err := repo.GetAll(ctx, Domain.TaskFilter{OnlyArchived: true, IncludeDeleted: true}, Domain.Pagination{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"done"}, taskTitles(listed))
+	})
+
+	t.Run("Purge removes only tasks deleted before the cutoff", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		deleted := createTask(t, repo, &Domain.Task{Title: "deleted", DueDate: dueDate(1), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "active", DueDate: dueDate(1), Status: Domain.StatusPending})
+		require.NoError(t, repo.Delete(ctx, deleted.ID.Hex()))
+
+		// Act
+		none, err := repo.Purge(ctx, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		purged, err := repo.Purge(ctx, time.Now().Add(time.Second))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(0), none)
+		assert.Equal(t, int64(1), purged)
+		count, err := repo.CountTasks(ctx, Domain.TaskFilter{IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+	})
+
+	t.Run("PurgeByStatus removes stale tasks in the status and keeps busy parents", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		parent := createTask(t, repo, &Domain.Task{Title: "parent", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "child", DueDate: dueDate(1), Status: Domain.StatusPending, ParentTaskID: &parent.ID})
+		done := createTask(t, repo, &Domain.Task{Title: "done", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		trashed := createTask(t, repo, &Domain.Task{Title: "trashed", DueDate: dueDate(1), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "pending", DueDate: dueDate(1), Status: Domain.StatusPending})
+		require.NoError(t, repo.Delete(ctx, trashed.ID.Hex()))
+
+		// Act
+		none, err := repo.PurgeByStatus(ctx, Domain.StatusCompleted, time.Now().Add(-time.Hour))
+		require.NoError(t, err)
+		purged, err := repo.PurgeByStatus(ctx, Domain.StatusCompleted, time.Now().Add(time.Second))
+		require.NoError(t, err)
+
+		// Assert
+		assert.Empty(t, none)
+		assert.ElementsMatch(t, []primitive.ObjectID{done.ID, trashed.ID}, purged)
+		remaining, _, err := repo.GetAll(ctx, Domain.TaskFilter{IncludeDeleted: true}, Domain.Pagination{})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"parent", "child", "pending"}, taskTitles(remaining))
+	})
+
+	t.Run("ReassignCreator and UnassignUser move a leaving user's tasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		leaving := primitive.NewObjectID()
+		admin := primitive.NewObjectID()
+		created := createTask(t, repo, &Domain.Task{Title: "created", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: leaving})
+		assigned := createTask(t, repo, &Domain.Task{Title: "assigned", DueDate: dueDate(1), Status: Domain.StatusPending, CreatedBy: admin, AssigneeID: &leaving})
+		require.NoError(t, repo.Delete(ctx, assigned.ID.Hex()))
+
+		// Act
+		reassigned, err := repo.ReassignCreator(ctx, leaving, admin)
+		require.NoError(t, err)
+		unassigned, err := repo.UnassignUser(ctx, leaving)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(1), reassigned)
+		assert.Equal(t, int64(1), unassigned)
+		stored, err := repo.GetByID(ctx, created.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, admin, stored.CreatedBy)
+		assert.Equal(t, 1, stored.Version)
+		count, err := repo.CountTasks(ctx, Domain.TaskFilter{AssigneeID: leaving, IncludeDeleted: true})
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("GetByParentID returns active subtasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		parent := createTask(t, repo, &Domain.Task{Title: "parent", DueDate: dueDate(1), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "first", DueDate: dueDate(1), Status: Domain.StatusPending, ParentTaskID: &parent.ID})
+		second := createTask(t, repo, &Domain.Task{Title: "second", DueDate: dueDate(1), Status: Domain.StatusPending, ParentTaskID: &parent.ID})
+		createTask(t, repo, &Domain.Task{Title: "other", DueDate: dueDate(1), Status: Domain.StatusPending})
+		require.NoError(t, repo.Delete(ctx, second.ID.Hex()))
+
+		// Act
+		subtasks, err := repo.GetByParentID(ctx, parent.ID.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, []string{"first"}, taskTitles(subtasks))
+		_, err = repo.GetByParentID(ctx, "invalid-id")
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+	})
+
+	t.Run("GetDueForReminder returns open tasks due in the window that still need a reminder", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		now := dueDate(0)
+		earlier := now.Add(-48 * time.Hour)
+		createTask(t, repo, &Domain.Task{Title: "soon", DueDate: now.Add(2 * time.Hour), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "overdue", DueDate: now.Add(-time.Hour), Status: Domain.StatusInProgress})
+		createTask(t, repo, &Domain.Task{Title: "later", DueDate: now.Add(72 * time.Hour), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "done", DueDate: now.Add(time.Hour), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "undated", Status: Domain.StatusPending})
+		reminded := createTask(t, repo, &Domain.Task{Title: "reminded", DueDate: now.Add(3 * time.Hour), Status: Domain.StatusPending})
+		require.NoError(t, repo.MarkNotified(ctx, reminded.ID, now))
+		overdueSinceReminder := createTask(t, repo, &Domain.Task{Title: "overdue since reminder", DueDate: now.Add(-2 * time.Hour), Status: Domain.StatusPending})
+		require.NoError(t, repo.MarkNotified(ctx, overdueSinceReminder.ID, earlier))
+		remindedOverdue := createTask(t, repo, &Domain.Task{Title: "reminded overdue", DueDate: now.Add(-3 * time.Hour), Status: Domain.StatusPending})
+		require.NoError(t, repo.MarkNotified(ctx, remindedOverdue.ID, now))
+		deleted := createTask(t, repo, &Domain.Task{Title: "deleted", DueDate: now.Add(time.Hour), Status: Domain.StatusPending})
+		require.NoError(t, repo.Delete(ctx, deleted.ID.Hex()))
+
+		// Act
+		tasks, err := repo.GetDueForReminder(ctx, now.Add(24*time.Hour), now, 10)
+		require.NoError(t, err)
+		limited, err := repo.GetDueForReminder(ctx, now.Add(24*time.Hour), now, 1)
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []string{"overdue since reminder", "overdue", "soon"}, taskTitles(tasks))
+		assert.Equal(t, []string{"overdue since reminder"}, taskTitles(limited))
+	})
+
+	t.Run("MarkNotified leaves the version alone and a new due date clears it", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task := createTask(t, repo, &Domain.Task{Title: "Write report", DueDate: dueDate(1), Status: Domain.StatusPending})
+
+		// Act
+		require.NoError(t, repo.MarkNotified(ctx, task.ID, dueDate(0)))
+		notified, err := repo.GetByID(ctx, task.ID.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, notified.LastNotifiedAt)
+		assert.True(t, dueDate(0).Equal(*notified.LastNotifiedAt))
+		assert.Equal(t, 0, notified.Version)
+
+		notified.DueDate = dueDate(2)
+		notified.LastNotifiedAt = nil
+		require.NoError(t, repo.Update(ctx, task.ID.Hex(), notified))
+		updated, err := repo.GetByID(ctx, task.ID.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		assert.Nil(t, updated.LastNotifiedAt)
+		assert.ErrorIs(t, repo.MarkNotified(ctx, primitive.NewObjectID(), dueDate(0)), Domain.ErrTaskNotFound)
+	})
+
+	t.Run("GetTags lists distinct tags of matching tasks alphabetically", func(t *testing.T) {
+		repo := newRepo(t)
+		createTask(t, repo, &Domain.Task{Title: "a", DueDate: dueDate(1), Status: Domain.StatusPending, Tags: []string{"work", "home"}})
+		createTask(t, repo, &Domain.Task{Title: "b", DueDate: dueDate(1), Status: Domain.StatusCompleted, Tags: []string{"work", "errand"}})
+
+		all, err := repo.GetTags(ctx, Domain.TaskFilter{})
+		require.NoError(t, err)
+		pending, err := repo.GetTags(ctx, Domain.TaskFilter{Status: Domain.StatusPending})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"errand", "home", "work"}, all)
+		assert.Equal(t, []string{"home", "work"}, pending)
+	})
+
+	t.Run("GetStats counts statuses, deadlines and daily creations", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		now := time.Now().UTC()
+		createTask(t, repo, &Domain.Task{Title: "overdue", DueDate: now.Add(-time.Hour), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "soon", DueDate: now.Add(48 * time.Hour), Status: Domain.StatusInProgress})
+		createTask(t, repo, &Domain.Task{Title: "later", DueDate: now.AddDate(0, 1, 0), Status: Domain.StatusPending})
+		createTask(t, repo, &Domain.Task{Title: "done late", DueDate: now.Add(-time.Hour), Status: Domain.StatusCompleted})
+		createTask(t, repo, &Domain.Task{Title: "undated", Status: Domain.StatusPending})
+
+		// Act
+		stats, err := repo.GetStats(ctx, Domain.TaskFilter{}, now)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, map[string]int64{
+			Domain.StatusPending:    3,
+			Domain.StatusInProgress: 1,
+			Domain.StatusCompleted:  1,
+		}, stats.ByStatus)
+		assert.Equal(t, int64(1), stats.Overdue)
+		assert.Equal(t, int64(1), stats.DueNext7Days)
+		require.Len(t, stats.CreatedPerDay, Domain.StatsCreatedDays)
+		today := stats.CreatedPerDay[Domain.StatsCreatedDays-1]
+		assert.Equal(t, now.Format("2006-01-02"), today.Date)
+		assert.Equal(t, int64(5), today.Count)
+	})
+}
@@ -0,0 +1,400 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// NewUserRepository returns an empty user repository for one test
+type NewUserRepository func(t *testing.T) Repositories.UserRepositoryInterface
+
+// createUser stores a user and fails the test if that is not possible
+func createUser(t *testing.T, repo Repositories.UserRepositoryInterface, user *Domain.User) *Domain.User {
+	t.Helper()
+	require.NoError(t, repo.Create(context.Background(), user))
+	return user
+}
+
+// usernames lists the usernames of users in order
+func usernames(users []*Domain.User) []string {
+	names := make([]string, 0, len(users))
+	for _, user := range users {
+		names = append(names, user.Username)
+	}
+	return names
+}
+
+// UserRepository runs the user repository conformance suite
+func UserRepository(t *testing.T, newRepo NewUserRepository) {
+	ctx := context.Background()
+
+	t.Run("Create assigns an ID, canonical username and timestamps", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		user := &Domain.User{Username: "Alice", Password: "hashed", Role: Domain.RoleUser, IsActive: true}
+
+		// Act
+		err := repo.Create(ctx, user)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, user.ID.IsZero())
+		assert.Equal(t, "alice", user.UsernameLower)
+		assert.WithinDuration(t, time.Now(), user.CreatedAt, time.Second)
+		assert.WithinDuration(t, time.Now(), user.UpdatedAt, time.Second)
+	})
+
+	t.Run("Create rejects taken usernames in any case and taken emails", func(t *testing.T) {
+		repo := newRepo(t)
+		createUser(t, repo, &Domain.User{Username: "alice", Email: "alice@example.com", Role: Domain.RoleUser})
+
+		err := repo.Create(ctx, &Domain.User{Username: "ALICE", Role: Domain.RoleUser})
+		assert.ErrorIs(t, err, Domain.ErrUsernameTaken)
+
+		err = repo.Create(ctx, &Domain.User{Username: "bob", Email: "alice@example.com", Role: Domain.RoleUser})
+		assert.ErrorIs(t, err, Domain.ErrEmailTaken)
+	})
+
+	t.Run("Users without an email do not collide", func(t *testing.T) {
+		repo := newRepo(t)
+		createUser(t, repo, &Domain.User{Username: "alice", Role: Domain.RoleUser})
+
+		err := repo.Create(ctx, &Domain.User{Username: "bob", Role: Domain.RoleUser})
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("GetByID returns the stored user", func(t *testing.T) {
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Password: "hashed", Role: Domain.RoleAdmin, IsActive: true})
+
+		found, err := repo.GetByID(ctx, user.ID.Hex())
+
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+		assert.Equal(t, "alice", found.Username)
+		assert.Equal(t, "hashed", found.Password)
+		assert.Equal(t, Domain.RoleAdmin, found.Role)
+		assert.True(t, found.IsActive)
+	})
+
+	t.Run("GetByID reports unknown and malformed IDs", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetByID(ctx, primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+
+		_, err = repo.GetByID(ctx, "invalid-id")
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+	})
+
+	t.Run("GetByUsername ignores case and surrounding whitespace", func(t *testing.T) {
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "Alice.Smith-01", Role: Domain.RoleUser})
+
+		found, err := repo.GetByUsername(ctx, "  alice.smith-01 ")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+
+		_, err = repo.GetByUsername(ctx, "alice")
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		_, err = repo.GetByUsername(ctx, "")
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+	})
+
+	t.Run("GetByEmail normalizes the address", func(t *testing.T) {
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Email: "alice@example.com", Role: Domain.RoleUser})
+
+		found, err := repo.GetByEmail(ctx, " Alice@Example.com ")
+		require.NoError(t, err)
+		assert.Equal(t, user.ID, found.ID)
+
+		_, err = repo.GetByEmail(ctx, "bob@example.com")
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+	})
+
+	t.Run("GetAll searches, filters by role and pages in creation order", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		createUser(t, repo, &Domain.User{Username: "carol", Email: "carol@corp.example", Role: Domain.RoleAdmin})
+		createUser(t, repo, &Domain.User{Username: "Bob", Role: Domain.RoleUser})
+		createUser(t, repo, &Domain.User{Username: "alice", Email: "alice@corp.example", Role: Domain.RoleUser})
+
+		// Act
+		all, total, err := repo.GetAll(ctx, Domain.UserFilter{}, Domain.Pagination{})
+		require.NoError(t, err)
+		byName, _, err := repo.GetAll(ctx, Domain.UserFilter{}, Domain.Pagination{Sort: Domain.SortUsername})
+		require.NoError(t, err)
+		page, pageTotal, err := repo.GetAll(ctx, Domain.UserFilter{}, Domain.Pagination{Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		corp, corpTotal, err := repo.GetAll(ctx, Domain.UserFilter{Query: "CORP.example"}, Domain.Pagination{})
+		require.NoError(t, err)
+		corpUsers, _, err := repo.GetAll(ctx, Domain.UserFilter{Query: "corp", Role: Domain.RoleUser}, Domain.Pagination{})
+		require.NoError(t, err)
+		literal, _, err := repo.GetAll(ctx, Domain.UserFilter{Query: "a.*"}, Domain.Pagination{})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []string{"carol", "Bob", "alice"}, usernames(all))
+		assert.Equal(t, []string{"alice", "Bob", "carol"}, usernames(byName))
+		assert.Equal(t, int64(3), pageTotal)
+		assert.Equal(t, []string{"Bob"}, usernames(page))
+		assert.Equal(t, int64(2), corpTotal)
+		assert.Equal(t, []string{"carol", "alice"}, usernames(corp))
+		assert.Equal(t, []string{"alice"}, usernames(corpUsers))
+		assert.Empty(t, literal)
+	})
+
+	t.Run("GetDirectory searches usernames only and pages like GetAll", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		carol := createUser(t, repo, &Domain.User{Username: "carol", Email: "carol@corp.example", Role: Domain.RoleAdmin})
+		createUser(t, repo, &Domain.User{Username: "Bob", Role: Domain.RoleUser})
+		createUser(t, repo, &Domain.User{Username: "alice", Email: "alice@corp.example", Role: Domain.RoleUser})
+
+		// Act
+		all, total, err := repo.GetDirectory(ctx, "", Domain.Pagination{})
+		require.NoError(t, err)
+		page, pageTotal, err := repo.GetDirectory(ctx, "", Domain.Pagination{Sort: Domain.SortUsername, Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		byName, _, err := repo.GetDirectory(ctx, "AR", Domain.Pagination{})
+		require.NoError(t, err)
+		byEmail, emailTotal, err := repo.GetDirectory(ctx, "corp", Domain.Pagination{})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(3), total)
+		assert.Equal(t, []*Domain.UserDirectoryEntry{{ID: carol.ID, Username: "carol"}}, all[:1])
+		assert.Len(t, all, 3)
+		assert.Equal(t, int64(3), pageTotal)
+		if assert.Len(t, page, 1) {
+			assert.Equal(t, "Bob", page[0].Username)
+		}
+		if assert.Len(t, byName, 1) {
+			assert.Equal(t, "carol", byName[0].Username)
+		}
+		assert.Empty(t, byEmail)
+		assert.Zero(t, emailTotal)
+	})
+
+	t.Run("Stream visits matching users in creation order without password hashes", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		createUser(t, repo, &Domain.User{Username: "carol", Password: "hash", Role: Domain.RoleAdmin})
+		createUser(t, repo, &Domain.User{Username: "bob", Password: "hash", Role: Domain.RoleUser})
+		createUser(t, repo, &Domain.User{Username: "alice", Password: "hash", Role: Domain.RoleUser})
+
+		// Act
+		var streamed []*Domain.User
+		err := repo.Stream(ctx, Domain.UserFilter{Role: Domain.RoleUser}, func(user *Domain.User) error {
+			streamed = append(streamed, user)
+			return nil
+		})
+		require.NoError(t, err)
+		stop := errors.New("stop")
+		visited := 0
+		stopErr := repo.Stream(ctx, Domain.UserFilter{}, func(user *Domain.User) error {
+			visited++
+			return stop
+		})
+
+		// Assert
+		assert.Equal(t, []string{"bob", "alice"}, usernames(streamed))
+		for _, user := range streamed {
+			assert.Empty(t, user.Password)
+		}
+		assert.ErrorIs(t, stopErr, stop)
+		assert.Equal(t, 1, visited)
+	})
+
+	t.Run("GetAll returns an empty list when there are no users", func(t *testing.T) {
+		repo := newRepo(t)
+
+		users, total, err := repo.GetAll(ctx, Domain.UserFilter{}, Domain.Pagination{})
+
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+		assert.Equal(t, int64(0), total)
+	})
+
+	t.Run("Update saves the username, password and role", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Password: "old", Role: Domain.RoleUser})
+		createUser(t, repo, &Domain.User{Username: "bob", Role: Domain.RoleUser})
+		user.Username = "Alicia"
+		user.Password = "new"
+		user.Role = Domain.RoleAdmin
+
+		// Act
+		err := repo.Update(ctx, user.ID.Hex(), user)
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repo.GetByUsername(ctx, "alicia")
+		require.NoError(t, err)
+		assert.Equal(t, "new", found.Password)
+		assert.Equal(t, Domain.RoleAdmin, found.Role)
+
+		user.Username = "BOB"
+		assert.EqualError(t, repo.Update(ctx, user.ID.Hex(), user), "username already exists")
+	})
+
+	t.Run("Update reports unknown and malformed IDs", func(t *testing.T) {
+		repo := newRepo(t)
+
+		assert.EqualError(t, repo.Update(ctx, primitive.NewObjectID().Hex(), &Domain.User{Username: "alice"}), "user not found")
+		assert.EqualError(t, repo.Update(ctx, "invalid-id", &Domain.User{Username: "alice"}), "invalid user ID format")
+	})
+
+	t.Run("UpdateByUsername promotes a user found in any case", func(t *testing.T) {
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Role: Domain.RoleUser})
+
+		err := repo.UpdateByUsername(ctx, "ALICE", &Domain.User{Role: Domain.RoleAdmin})
+
+		require.NoError(t, err)
+		found, err := repo.GetByID(ctx, user.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.RoleAdmin, found.Role)
+		assert.EqualError(t, repo.UpdateByUsername(ctx, "nobody", &Domain.User{Role: Domain.RoleAdmin}), "user not found")
+	})
+
+	t.Run("UpdateProfile and MarkEmailVerified manage the email address", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Email: "old@example.com", Role: Domain.RoleUser})
+		createUser(t, repo, &Domain.User{Username: "bob", Email: "bob@example.com", Role: Domain.RoleUser})
+
+		// Act and assert
+		user.Email = "bob@example.com"
+		assert.EqualError(t, repo.UpdateProfile(ctx, user.ID.Hex(), user), "email already exists")
+
+		user.Email = "new@example.com"
+		require.NoError(t, repo.UpdateProfile(ctx, user.ID.Hex(), user))
+		assert.EqualError(t, repo.MarkEmailVerified(ctx, user.ID.Hex(), "old@example.com"), "user not found")
+		require.NoError(t, repo.MarkEmailVerified(ctx, user.ID.Hex(), "new@example.com"))
+		found, err := repo.GetByEmail(ctx, "new@example.com")
+		require.NoError(t, err)
+		assert.True(t, found.EmailVerified)
+
+		user.Email = ""
+		user.EmailVerified = false
+		require.NoError(t, repo.UpdateProfile(ctx, user.ID.Hex(), user))
+		found, err = repo.GetByID(ctx, user.ID.Hex())
+		require.NoError(t, err)
+		assert.Empty(t, found.Email)
+	})
+
+	t.Run("UpdateLastLogin, SetActive and IsActive track account state", func(t *testing.T) {
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Role: Domain.RoleUser, IsActive: true})
+		loggedIn := time.Date(2030, time.January, 1, 12, 0, 0, 0, time.UTC)
+
+		require.NoError(t, repo.UpdateLastLogin(ctx, user.ID.Hex(), loggedIn))
+		require.NoError(t, repo.SetActive(ctx, user.ID.Hex(), false))
+
+		found, err := repo.GetByID(ctx, user.ID.Hex())
+		require.NoError(t, err)
+		require.NotNil(t, found.LastLoginAt)
+		assert.True(t, loggedIn.Equal(*found.LastLoginAt))
+		active, err := repo.IsActive(ctx, user.ID.Hex())
+		require.NoError(t, err)
+		assert.False(t, active)
+
+		missing := primitive.NewObjectID().Hex()
+		assert.EqualError(t, repo.UpdateLastLogin(ctx, missing, loggedIn), "user not found")
+		assert.EqualError(t, repo.SetActive(ctx, missing, true), "user not found")
+		_, err = repo.IsActive(ctx, missing)
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+	})
+
+	t.Run("Anonymize scrubs personal data but keeps the record", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Email: "alice@example.com", EmailVerified: true, Password: "hashed", Role: Domain.RoleAdmin})
+
+		// Act
+		err := repo.Anonymize(ctx, user.ID.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		found, err := repo.GetByID(ctx, user.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.AnonymizedUsername(user.ID.Hex()), found.Username)
+		assert.Empty(t, found.Email)
+		assert.Empty(t, found.Password)
+		assert.False(t, found.EmailVerified)
+		assert.Equal(t, Domain.RoleUser, found.Role)
+		assert.NotNil(t, found.AnonymizedAt)
+		_, err = repo.GetByUsername(ctx, "alice")
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+	})
+
+	t.Run("Delete removes the user", func(t *testing.T) {
+		repo := newRepo(t)
+		user := createUser(t, repo, &Domain.User{Username: "alice", Role: Domain.RoleUser})
+
+		require.NoError(t, repo.Delete(ctx, user.ID.Hex()))
+
+		_, err := repo.GetByID(ctx, user.ID.Hex())
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		assert.EqualError(t, repo.Delete(ctx, user.ID.Hex()), "user not found")
+		assert.EqualError(t, repo.Delete(ctx, "invalid-id"), "invalid user ID format")
+	})
+
+	t.Run("CountUsers and CountByRole count stored users", func(t *testing.T) {
+		repo := newRepo(t)
+
+		count, err := repo.CountUsers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+
+		createUser(t, repo, &Domain.User{Username: "alice", Role: Domain.RoleAdmin})
+		createUser(t, repo, &Domain.User{Username: "bob", Role: Domain.RoleUser})
+		createUser(t, repo, &Domain.User{Username: "carol", Role: Domain.RoleUser})
+
+		count, err = repo.CountUsers(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+		admins, err := repo.CountByRole(ctx, Domain.RoleAdmin)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), admins)
+	})
+
+	t.Run("ClaimFirstAdmin promotes only the first claimant", func(t *testing.T) {
+		repo := newRepo(t)
+		first := createUser(t, repo, &Domain.User{Username: "first", Role: Domain.RoleUser})
+		second := createUser(t, repo, &Domain.User{Username: "second", Role: Domain.RoleUser})
+
+		claimed, err := repo.ClaimFirstAdmin(ctx, first.ID.Hex())
+		require.NoError(t, err)
+		assert.True(t, claimed)
+		claimed, err = repo.ClaimFirstAdmin(ctx, second.ID.Hex())
+		require.NoError(t, err)
+		assert.False(t, claimed)
+
+		stored, err := repo.GetByID(ctx, first.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.RoleAdmin, stored.Role)
+		stored, err = repo.GetByID(ctx, second.ID.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, Domain.RoleUser, stored.Role)
+
+		_, err = repo.ClaimFirstAdmin(ctx, primitive.NewObjectID().Hex())
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		_, err = repo.ClaimFirstAdmin(ctx, "invalid-id")
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+	})
+}
@@ -0,0 +1,152 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// NewWorkLogRepository returns an empty work log repository for one test
+type NewWorkLogRepository func(t *testing.T) Repositories.WorkLogRepositoryInterface
+
+// day returns midnight UTC of the given day of March 2024, the way work log dates are stored
+func day(n int) time.Time {
+	return time.Date(2024, time.March, n, 0, 0, 0, 0, time.UTC)
+}
+
+// logWork stores a work log and fails the test on error
+func logWork(t *testing.T, repo Repositories.WorkLogRepositoryInterface, taskID, userID primitive.ObjectID, minutes int, date time.Time) *Domain.WorkLog {
+	workLog := &Domain.WorkLog{TaskID: taskID, UserID: userID, Minutes: minutes, Date: date}
+	require.NoError(t, repo.Create(context.Background(), workLog))
+	return workLog
+}
+
+// WorkLogRepository runs the work log repository conformance suite
+func WorkLogRepository(t *testing.T, newRepo NewWorkLogRepository) {
+	ctx := context.Background()
+
+	t.Run("GetByTaskID lists a task's work earliest day first", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task, other, user := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		later := logWork(t, repo, task, user, 30, day(2))
+		earlier := logWork(t, repo, task, user, 45, day(1))
+		logWork(t, repo, other, user, 60, day(1))
+
+		// Act
+		workLogs, err := repo.GetByTaskID(ctx, task.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		require.Len(t, workLogs, 2)
+		assert.Equal(t, earlier.ID, workLogs[0].ID)
+		assert.Equal(t, later.ID, workLogs[1].ID)
+		assert.True(t, day(1).Equal(workLogs[0].Date))
+		assert.False(t, workLogs[0].CreatedAt.IsZero())
+
+		_, err = repo.GetByTaskID(ctx, "invalid")
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+	})
+
+	t.Run("Totals add up a task's minutes and a user's minutes on one day", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task, other, user, colleague := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		logWork(t, repo, task, user, 30, day(1))
+		logWork(t, repo, task, colleague, 45, day(1))
+		logWork(t, repo, other, user, 60, day(1))
+		logWork(t, repo, task, user, 15, day(2))
+
+		// Act
+		taskTotal, err := repo.TotalMinutesByTaskID(ctx, task.Hex())
+		require.NoError(t, err)
+		dayTotal, err := repo.TotalMinutesByUserOnDate(ctx, user, day(1))
+		require.NoError(t, err)
+		emptyTotal, err := repo.TotalMinutesByTaskID(ctx, primitive.NewObjectID().Hex())
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, 90, taskTotal)
+		assert.Equal(t, 90, dayTotal)
+		assert.Equal(t, 0, emptyTotal)
+	})
+
+	t.Run("Summarize totals each user's work in the date range, most minutes first", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task, user, colleague := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		logWork(t, repo, task, user, 30, day(1))
+		logWork(t, repo, task, user, 20, day(3))
+		logWork(t, repo, task, colleague, 90, day(2))
+		logWork(t, repo, task, colleague, 600, day(10))
+
+		// Act
+		all, err := repo.Summarize(ctx, Domain.WorkLogFilter{})
+		require.NoError(t, err)
+		ranged, err := repo.Summarize(ctx, Domain.WorkLogFilter{From: day(1), To: day(3)})
+		require.NoError(t, err)
+		own, err := repo.Summarize(ctx, Domain.WorkLogFilter{UserID: user})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, []*Domain.WorkLogSummary{
+			{UserID: colleague, TotalMinutes: 690, Entries: 2},
+			{UserID: user, TotalMinutes: 50, Entries: 2},
+		}, all)
+		assert.Equal(t, []*Domain.WorkLogSummary{
+			{UserID: colleague, TotalMinutes: 90, Entries: 1},
+			{UserID: user, TotalMinutes: 50, Entries: 2},
+		}, ranged)
+		assert.Equal(t, []*Domain.WorkLogSummary{{UserID: user, TotalMinutes: 50, Entries: 2}}, own)
+	})
+
+	t.Run("Work logs follow their task into the trash and back", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		task, user := primitive.NewObjectID(), primitive.NewObjectID()
+		logWork(t, repo, task, user, 30, day(1))
+
+		// Act and assert
+		require.NoError(t, repo.DeleteByTaskID(ctx, task.Hex()))
+		total, err := repo.TotalMinutesByTaskID(ctx, task.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, 0, total)
+		summaries, err := repo.Summarize(ctx, Domain.WorkLogFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+
+		require.NoError(t, repo.RestoreByTaskID(ctx, task.Hex()))
+		total, err = repo.TotalMinutesByTaskID(ctx, task.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, 30, total)
+	})
+
+	t.Run("Purge removes trashed work logs and PurgeByTaskIDs every log on the tasks", func(t *testing.T) {
+		// Arrange
+		repo := newRepo(t)
+		trashed, kept, user := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+		logWork(t, repo, trashed, user, 30, day(1))
+		logWork(t, repo, kept, user, 45, day(1))
+		require.NoError(t, repo.DeleteByTaskIDs(ctx, []primitive.ObjectID{trashed}))
+
+		// Act
+		purged, err := repo.Purge(ctx, time.Now().Add(time.Minute))
+		require.NoError(t, err)
+		purgedByTask, err := repo.PurgeByTaskIDs(ctx, []primitive.ObjectID{kept})
+		require.NoError(t, err)
+
+		// Assert
+		assert.Equal(t, int64(1), purged)
+		assert.Equal(t, int64(1), purgedByTask)
+		summaries, err := repo.Summarize(ctx, Domain.WorkLogFilter{})
+		require.NoError(t, err)
+		assert.Empty(t, summaries)
+	})
+}
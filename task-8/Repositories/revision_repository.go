@@ -0,0 +1,110 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// RevisionRetention is how long task revisions are kept before MongoDB expires them
+const RevisionRetention = 365 * 24 * time.Hour
+
+// RevisionRepositoryInterface defines the contract for task revision data access
+type RevisionRepositoryInterface interface {
+	Create(ctx context.Context, revision *Domain.TaskRevision) error
+	GetByTaskID(ctx context.Context, taskID string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// RevisionRepository implements RevisionRepositoryInterface with MongoDB
+type RevisionRepository struct {
+	collection *mongo.Collection
+}
+
+// NewRevisionRepository creates a new instance of RevisionRepository
+func NewRevisionRepository(client *mongo.Client, dbName string) RevisionRepositoryInterface {
+	collection := client.Database(dbName).Collection("task_revisions")
+	return &RevisionRepository{
+		collection: collection,
+	}
+}
+
+// Create stores a revision of a task
+func (rr *RevisionRepository) Create(ctx context.Context, revision *Domain.TaskRevision) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	revision.ID = primitive.NewObjectID()
+	revision.Timestamp = time.Now()
+
+	_, err := rr.collection.InsertOne(ctx, revision)
+	return err
+}
+
+// GetByTaskID returns one page of a task's revisions, newest first, with the total number of revisions
+func (rr *RevisionRepository) GetByTaskID(ctx context.Context, taskID string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, 0, Domain.ErrInvalidTaskID
+	}
+
+	query := bson.M{"task_id": objectID}
+
+	total, err := rr.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}, {Key: "_id", Value: -1}})
+	if pagination.Limit > 0 {
+		findOptions.SetLimit(pagination.Limit)
+	}
+	if pagination.Offset > 0 {
+		findOptions.SetSkip(pagination.Offset)
+	}
+
+	cursor, err := rr.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	revisions := []*Domain.TaskRevision{}
+	if err = cursor.All(ctx, &revisions); err != nil {
+		return nil, 0, err
+	}
+
+	return revisions, total, nil
+}
+
+// EnsureIndexes creates the revision listing index and the TTL index that expires old revisions
+func (rr *RevisionRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := rr.collection.Indexes().CreateMany(ctx, revisionIndexes())
+	return err
+}
+
+// revisionIndexes lists the indexes maintained on the task_revisions collection
+func revisionIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "timestamp", Value: -1}},
+			Options: options.Index().SetName("task_id_1_timestamp_-1"),
+		},
+		{
+			Keys:    bson.D{{Key: "timestamp", Value: 1}},
+			Options: options.Index().SetName("timestamp_ttl").SetExpireAfterSeconds(int32(RevisionRetention / time.Second)),
+		},
+	}
+}
@@ -0,0 +1,90 @@
+package Repositories
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockRevisionRepositoryImpl for testing purposes
+type MockRevisionRepositoryImpl struct {
+	mock.Mock
+}
+
+func (m *MockRevisionRepositoryImpl) Create(ctx context.Context, revision *Domain.TaskRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockRevisionRepositoryImpl) GetByTaskID(ctx context.Context, taskID string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error) {
+	args := m.Called(taskID, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.TaskRevision), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRevisionRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestRevisionIndexes(t *testing.T) {
+	t.Run("Revisions expire after the retention period", func(t *testing.T) {
+		// Act
+		indexes := revisionIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 2)
+		assert.Equal(t, bson.D{{Key: "task_id", Value: 1}, {Key: "timestamp", Value: -1}}, indexes[0].Keys)
+		assert.Equal(t, bson.D{{Key: "timestamp", Value: 1}}, indexes[1].Keys)
+		assert.Equal(t, int32(365*24*60*60), *indexes[1].Options.ExpireAfterSeconds)
+	})
+}
+
+func TestRevisionRepository_GetByTaskID(t *testing.T) {
+	t.Run("Success - return page of revisions", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRevisionRepositoryImpl)
+		taskID := primitive.NewObjectID()
+		pagination := Domain.Pagination{Limit: 5}
+		expectedRevisions := []*Domain.TaskRevision{
+			{ID: primitive.NewObjectID(), TaskID: taskID, Previous: map[string]interface{}{"description": "Old text"}},
+		}
+		mockRepo.On("GetByTaskID", taskID.Hex(), pagination).Return(expectedRevisions, int64(1), nil)
+
+		// Act
+		revisions, total, err := mockRepo.GetByTaskID(context.Background(), taskID.Hex(), pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRevisions, revisions)
+		assert.Equal(t, int64(1), total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid task ID format", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockRevisionRepositoryImpl)
+		mockRepo.On("GetByTaskID", "invalid-id", Domain.Pagination{}).Return(nil, int64(0), Domain.ErrInvalidTaskID)
+
+		// Act
+		revisions, _, err := mockRepo.GetByTaskID(context.Background(), "invalid-id", Domain.Pagination{})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+		assert.Nil(t, revisions)
+	})
+}
+
+func TestRevisionRepositoryInterface(t *testing.T) {
+	mockRepo := new(MockRevisionRepositoryImpl)
+	var _ RevisionRepositoryInterface = mockRepo
+	assert.NotNil(t, mockRepo)
+}
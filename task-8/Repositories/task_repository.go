@@ -2,144 +2,1199 @@ package Repositories
 
 import (
 	"context"
-	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
 )
 
 // TaskRepositoryInterface defines the contract for task data access
 type TaskRepositoryInterface interface {
-	GetAll() ([]*Domain.Task, error)
-	GetByID(id string) (*Domain.Task, error)
-	Create(task *Domain.Task) error
-	Update(id string, task *Domain.Task) error
-	Delete(id string) error
+	GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	GetByID(ctx context.Context, id string) (*Domain.Task, error)
+	GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error)
+	CountTasks(ctx context.Context, filter Domain.TaskFilter) (int64, error)
+	Search(ctx context.Context, query string, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	Stream(ctx context.Context, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error
+	Create(ctx context.Context, task *Domain.Task) error
+	CreateMany(ctx context.Context, tasks []*Domain.Task) error
+	ExistsByTitleAndDueDate(ctx context.Context, title string, dueDate time.Time) (bool, error)
+	FindOpenByTitle(ctx context.Context, createdBy primitive.ObjectID, title string) (*Domain.Task, error)
+	Update(ctx context.Context, id string, task *Domain.Task) error
+	UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error)
+	DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error)
+	Delete(ctx context.Context, id string) error
+	Restore(ctx context.Context, id string) error
+	SetArchived(ctx context.Context, id string, archived bool) error
+	ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error)
+	ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error)
+	UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error)
+	GetTags(ctx context.Context, filter Domain.TaskFilter) ([]string, error)
+	GetStats(ctx context.Context, filter Domain.TaskFilter, now time.Time) (*Domain.TaskStats, error)
+	GetByParentID(ctx context.Context, parentID string) ([]*Domain.Task, error)
+	GetDueForReminder(ctx context.Context, dueBefore, now time.Time, limit int64) ([]*Domain.Task, error)
+	MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error
+	EnsureIndexes(ctx context.Context) error
+	CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error
 }
 
 // TaskRepository implements TaskRepositoryInterface with MongoDB
 type TaskRepository struct {
 	collection *mongo.Collection
+	retrier    *Infrastructure.Retrier
+	txManager  Infrastructure.TransactionManager
 }
 
-// NewTaskRepository creates a new instance of TaskRepository
-func NewTaskRepository(client *mongo.Client, dbName, collectionName string) TaskRepositoryInterface {
+// NewTaskRepository creates a new instance of TaskRepository. Reads are retried on transient errors,
+// and writes spanning several documents run in transactions of txManager.
+func NewTaskRepository(client *mongo.Client, dbName, collectionName string, retrier *Infrastructure.Retrier, txManager Infrastructure.TransactionManager) TaskRepositoryInterface {
 	collection := client.Database(dbName).Collection(collectionName)
 	return &TaskRepository{
 		collection: collection,
+		retrier:    retrier,
+		txManager:  txManager,
 	}
 }
 
-// GetAll returns all tasks from MongoDB
-func (tr *TaskRepository) GetAll() ([]*Domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetAll returns a page of tasks matching the filter from MongoDB along with
+// the total number of matching tasks
+func (tr *TaskRepository) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	cursor, err := tr.collection.Find(ctx, bson.M{})
+	query := buildTaskQuery(filter)
+
+	var total int64
+	var tasks []*Domain.Task
+	err := tr.retrier.Read(ctx, "tasks.get_all", func(ctx context.Context) error {
+		var err error
+		total, err = tr.collection.CountDocuments(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		var cursor *mongo.Cursor
+		if pagination.Sort == Domain.SortPriority {
+			// Priorities do not sort alphabetically, so rank them in an aggregation
+			cursor, err = tr.collection.Aggregate(ctx, buildPrioritySortPipeline(query, pagination))
+		} else {
+			findOptions := buildFindOptions(pagination)
+			if projection := TaskProjection(pagination.Fields); projection != nil {
+				findOptions.SetProjection(projection)
+			}
+			cursor, err = tr.collection.Find(ctx, query, findOptions)
+		}
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &tasks)
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	for _, task := range tasks {
+		task.ApplyDefaults()
+	}
+
+	return tasks, total, nil
+}
+
+// Search returns a page of the tasks matching the filter whose title or description matches the
+// query in the text index, most relevant first, along with the total number of matching tasks.
+// Each task carries its relevance in Score.
+func (tr *TaskRepository) Search(ctx context.Context, query string, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	match := buildTaskQuery(filter)
+	match["$text"] = bson.M{"$search": query}
+
+	var total int64
+	var results []struct {
+		Domain.Task `bson:",inline"`
+		Relevance   float64 `bson:"score"`
+	}
+	err := tr.retrier.Read(ctx, "tasks.search", func(ctx context.Context) error {
+		var err error
+		total, err = tr.collection.CountDocuments(ctx, match)
+		if err != nil {
+			return err
+		}
+
+		cursor, err := tr.collection.Find(ctx, match, buildSearchFindOptions(pagination))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &results)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	tasks := make([]*Domain.Task, 0, len(results))
+	for i := range results {
+		task := &results[i].Task
+		task.ApplyDefaults()
+		task.Score = &results[i].Relevance
+		tasks = append(tasks, task)
+	}
+
+	return tasks, total, nil
+}
+
+// buildSearchFindOptions loads the text score of each match as score and sorts by it, best first,
+// breaking ties by _id so that consecutive pages are stable
+func buildSearchFindOptions(pagination Domain.Pagination) *options.FindOptions {
+	textScore := bson.M{"$meta": "textScore"}
+	return buildFindOptions(pagination).
+		SetProjection(bson.M{"score": textScore}).
+		SetSort(bson.D{{Key: "score", Value: textScore}, {Key: "_id", Value: 1}})
+}
+
+// buildTaskQuery translates a task filter into a BSON query.
+// An empty filter matches every task that has not been soft deleted or archived.
+func buildTaskQuery(filter Domain.TaskFilter) bson.M {
+	query := bson.M{}
+
+	switch {
+	case filter.OnlyDeleted:
+		query["deleted_at"] = bson.M{"$ne": nil}
+	case !filter.IncludeDeleted:
+		query["deleted_at"] = nil
+	}
+
+	// Tasks stored before archiving existed have no archived field and are not archived
+	switch {
+	case filter.OnlyArchived:
+		query["archived"] = true
+	case !filter.IncludeArchived:
+		query["archived"] = bson.M{"$ne": true}
+	}
+
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+
+	if filter.Priority != "" {
+		if filter.Priority == Domain.PriorityMedium {
+			// Tasks stored before priorities existed count as medium
+			query["priority"] = bson.M{"$in": bson.A{Domain.PriorityMedium, nil}}
+		} else {
+			query["priority"] = filter.Priority
+		}
+	}
+
+	if filter.Tag != "" {
+		query["tags"] = filter.Tag
+	}
+
+	if !filter.CreatedBy.IsZero() {
+		query["created_by"] = filter.CreatedBy
+	}
+
+	if !filter.AssigneeID.IsZero() {
+		query["assignee_id"] = filter.AssigneeID
+	}
+
+	dueDate := bson.M{}
+	if !filter.DueAfter.IsZero() {
+		dueDate["$gte"] = filter.DueAfter
+	}
+	if !filter.DueBefore.IsZero() {
+		dueDate["$lte"] = filter.DueBefore
+	}
+	// Tasks without a due date are stored with the zero time and are never overdue
+	if !filter.OverdueAt.IsZero() {
+		dueDate["$gt"] = time.Time{}
+		dueDate["$lt"] = filter.OverdueAt
+		if filter.Status == "" {
+			query["status"] = bson.M{"$ne": Domain.StatusCompleted}
+		}
+	}
+	if len(dueDate) > 0 {
+		query["due_date"] = dueDate
+	}
+
+	if filter.OpenOnly && filter.Status == "" {
+		query["status"] = bson.M{"$ne": Domain.StatusCompleted}
+	}
+
+	return query
+}
+
+// buildFindOptions translates a pagination window into Mongo find options.
+// Results are sorted by _id, or by due date then _id, so that consecutive pages are stable.
+func buildFindOptions(pagination Domain.Pagination) *options.FindOptions {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}})
+	if pagination.Sort == Domain.SortDueDate {
+		findOptions.SetSort(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}})
+	}
+	if pagination.Offset > 0 {
+		findOptions.SetSkip(pagination.Offset)
+	}
+	if pagination.Limit > 0 {
+		findOptions.SetLimit(pagination.Limit)
+	}
+	return findOptions
+}
+
+// buildPrioritySortPipeline builds an aggregation that returns the tasks matching
+// query ordered from urgent to low, with missing priorities ranked as medium.
+// Ties are broken by _id so that consecutive pages are stable.
+func buildPrioritySortPipeline(query bson.M, pagination Domain.Pagination) mongo.Pipeline {
+	rank := bson.M{
+		"$switch": bson.M{
+			"branches": bson.A{
+				bson.M{"case": bson.M{"$eq": bson.A{"$priority", Domain.PriorityUrgent}}, "then": 4},
+				bson.M{"case": bson.M{"$eq": bson.A{"$priority", Domain.PriorityHigh}}, "then": 3},
+				bson.M{"case": bson.M{"$eq": bson.A{"$priority", Domain.PriorityLow}}, "then": 1},
+			},
+			"default": 2,
+		},
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$addFields", Value: bson.M{"priority_rank": rank}}},
+		{{Key: "$sort", Value: bson.D{{Key: "priority_rank", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	if pagination.Offset > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$skip", Value: pagination.Offset}})
+	}
+	if pagination.Limit > 0 {
+		pipeline = append(pipeline, bson.D{{Key: "$limit", Value: pagination.Limit}})
+	}
+	// An inclusion projection drops the rank along with every field not selected
+	projection := TaskProjection(pagination.Fields)
+	if projection == nil {
+		projection = bson.M{"priority_rank": 0}
+	}
+	return append(pipeline, bson.D{{Key: "$project", Value: projection}})
+}
+
+// taskProjectionRequired lists the fields every projection loads whatever was selected:
+// visibility checks need the creator and assignee, and ETags the version and update time.
+var taskProjectionRequired = []string{"created_by", "assignee_id", "updated_at", "version"}
+
+// TaskProjection translates a selection of task fields, by JSON name, into a Mongo inclusion
+// projection. It returns nil, loading whole documents, when no fields are selected.
+func TaskProjection(fields []string) bson.M {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	// _id is included unless excluded, and every other field shares its JSON name
+	projection := bson.M{}
+	for _, field := range fields {
+		if field != "id" {
+			projection[field] = 1
+		}
+	}
+	for _, field := range taskProjectionRequired {
+		projection[field] = 1
+	}
+	return projection
+}
+
+// CountTasks returns the number of tasks matching the filter without loading them
+func (tr *TaskRepository) CountTasks(ctx context.Context, filter Domain.TaskFilter) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	err := tr.retrier.Read(ctx, "tasks.count", func(ctx context.Context) error {
+		var err error
+		count, err = tr.collection.CountDocuments(ctx, buildTaskQuery(filter))
+		return err
+	})
+	return count, err
+}
+
+// Stream calls fn for every task matching the filter in _id order, decoding one document
+// at a time so large result sets are never held in memory. It stops at the first error fn returns.
+// Only opening the cursor is retried, since tasks already passed to fn cannot be taken back.
+func (tr *TaskRepository) Stream(ctx context.Context, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	// Exports can be large, so they get more time than a single query
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	var cursor *mongo.Cursor
+	err := tr.retrier.Read(ctx, "tasks.stream", func(ctx context.Context) error {
+		var err error
+		cursor, err = tr.collection.Find(ctx, buildTaskQuery(filter), buildFindOptions(Domain.Pagination{}))
+		return err
+	})
+	if err != nil {
+		return err
 	}
 	defer cursor.Close(ctx)
 
-	var tasks []*Domain.Task
-	if err = cursor.All(ctx, &tasks); err != nil {
-		return nil, err
+	for cursor.Next(ctx) {
+		var task Domain.Task
+		if err := cursor.Decode(&task); err != nil {
+			return err
+		}
+		task.ApplyDefaults()
+
+		if err := fn(&task); err != nil {
+			return err
+		}
 	}
 
-	return tasks, nil
+	return cursor.Err()
+}
+
+// GetByID returns a task by its ObjectID from MongoDB, ignoring soft-deleted tasks
+func (tr *TaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
+	return tr.GetByIDWithFields(ctx, id, nil)
 }
 
-// GetByID returns a task by its ObjectID from MongoDB
-func (tr *TaskRepository) GetByID(id string) (*Domain.Task, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetByIDWithFields returns a task like GetByID, loading only the fields named, by JSON name,
+// along with those TaskProjection always includes. No fields loads the whole task.
+func (tr *TaskRepository) GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid task ID format")
+		return nil, Domain.ErrInvalidTaskID
 	}
 
 	var task Domain.Task
-	err = tr.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&task)
+	err = tr.retrier.Read(ctx, "tasks.get_by_id", func(ctx context.Context) error {
+		findOptions := options.FindOne()
+		if projection := TaskProjection(fields); projection != nil {
+			findOptions.SetProjection(projection)
+		}
+		return tr.collection.FindOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}, findOptions).Decode(&task)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("task not found")
+			return nil, Domain.ErrTaskNotFound
 		}
 		return nil, err
 	}
 
+	task.ApplyDefaults()
 	return &task, nil
 }
 
+// GetByParentID returns the active subtasks of a task, oldest first
+func (tr *TaskRepository) GetByParentID(ctx context.Context, parentID string) ([]*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(parentID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	tasks := []*Domain.Task{}
+	err = tr.retrier.Read(ctx, "tasks.get_by_parent_id", func(ctx context.Context) error {
+		cursor, err := tr.collection.Find(ctx, bson.M{"parent_task_id": objectID, "deleted_at": nil}, buildFindOptions(Domain.Pagination{}))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		task.ApplyDefaults()
+	}
+
+	return tasks, nil
+}
+
+// EnsureIndexes creates the indexes task queries rely on. It is safe to call on every start.
+func (tr *TaskRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := tr.collection.Indexes().CreateMany(ctx, taskIndexes())
+	return err
+}
+
+// taskIndexes lists the indexes maintained on the tasks collection: one per field tasks are
+// filtered by, and the collection's single text index for searching titles and descriptions
+func taskIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "parent_task_id", Value: 1}},
+			Options: options.Index().SetName("parent_task_id_1").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "status", Value: 1}},
+			Options: options.Index().SetName("status_1"),
+		},
+		{
+			Keys:    bson.D{{Key: "due_date", Value: 1}},
+			Options: options.Index().SetName("due_date_1"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_by", Value: 1}},
+			Options: options.Index().SetName("created_by_1").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_by", Value: 1}, {Key: "title_key", Value: 1}},
+			Options: options.Index().SetName("created_by_1_title_key_1").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "tags", Value: 1}},
+			Options: options.Index().SetName("tags_1").SetSparse(true),
+		},
+		{
+			Keys:    bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}},
+			Options: options.Index().SetName("title_text_description_text"),
+		},
+	}
+}
+
 // Create creates a new task in MongoDB
-func (tr *TaskRepository) Create(task *Domain.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) Create(ctx context.Context, task *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	task.ID = primitive.NewObjectID()
 	task.CreatedAt = time.Now()
 	task.UpdatedAt = time.Now()
+	task.TitleKey = Domain.NormalizeTitle(task.Title)
 
 	_, err := tr.collection.InsertOne(ctx, task)
 	return err
 }
 
+// CreateMany inserts several tasks in a single InsertMany.
+// A CreatedAt that is already set is kept so imported tasks retain their original creation time.
+func (tr *TaskRepository) CreateMany(ctx context.Context, tasks []*Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	documents := make([]interface{}, len(tasks))
+	for i, task := range tasks {
+		task.ID = primitive.NewObjectID()
+		if task.CreatedAt.IsZero() {
+			task.CreatedAt = now
+		}
+		task.UpdatedAt = now
+		task.TitleKey = Domain.NormalizeTitle(task.Title)
+		documents[i] = task
+	}
+
+	_, err := tr.collection.InsertMany(ctx, documents)
+	return err
+}
+
+// ExistsByTitleAndDueDate reports whether an active task has exactly this title and due date
+func (tr *TaskRepository) ExistsByTitleAndDueDate(ctx context.Context, title string, dueDate time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	err := tr.retrier.Read(ctx, "tasks.exists_by_title_and_due_date", func(ctx context.Context) error {
+		var err error
+		count, err = tr.collection.CountDocuments(ctx, bson.M{"title": title, "due_date": dueDate, "deleted_at": nil}, options.Count().SetLimit(1))
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// FindOpenByTitle returns an active task created by createdBy that is not completed and whose title
+// matches title once both are normalized with Domain.NormalizeTitle. The lookup uses the stored
+// title_key, so tasks written before it existed are found only once they are next saved.
+func (tr *TaskRepository) FindOpenByTitle(ctx context.Context, createdBy primitive.ObjectID, title string) (*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"created_by": createdBy,
+		"title_key":  Domain.NormalizeTitle(title),
+		"status":     bson.M{"$ne": Domain.StatusCompleted},
+		"deleted_at": nil,
+	}
+
+	var task Domain.Task
+	err := tr.retrier.Read(ctx, "tasks.find_open_by_title", func(ctx context.Context) error {
+		return tr.collection.FindOne(ctx, filter).Decode(&task)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrTaskNotFound
+		}
+		return nil, err
+	}
+
+	task.ApplyDefaults()
+	return &task, nil
+}
+
 // Update updates an existing task in MongoDB
-func (tr *TaskRepository) Update(id string, task *Domain.Task) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (tr *TaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid task ID format")
+		return Domain.ErrInvalidTaskID
 	}
 
 	task.UpdatedAt = time.Now()
+	update := buildTaskUpdate(task)
+
+	filter := bson.M{"_id": objectID, "deleted_at": nil, "version": versionFilter(task.Version)}
+	result, err := tr.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return tr.missedUpdateError(ctx, objectID)
+	}
+
+	task.Version++
+	return nil
+}
+
+// versionFilter matches the expected task version. Documents written before
+// versioning have no version field and count as version 0.
+func versionFilter(version int) interface{} {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+// missedUpdateError explains why a versioned update matched nothing: the task is
+// either gone or was changed by someone else since it was read
+func (tr *TaskRepository) missedUpdateError(ctx context.Context, objectID primitive.ObjectID) error {
+	count, err := tr.collection.CountDocuments(ctx, bson.M{"_id": objectID, "deleted_at": nil})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return Domain.ErrVersionConflict
+	}
+	return Domain.ErrTaskNotFound
+}
+
+// buildTaskUpdate translates the mutable fields of a task into a Mongo update.
+// Optional fields that are empty are unset so they disappear from the document.
+func buildTaskUpdate(task *Domain.Task) bson.M {
+	fields := bson.M{
+		"title":       task.Title,
+		"title_key":   Domain.NormalizeTitle(task.Title),
+		"description": task.Description,
+		"due_date":    task.DueDate,
+		"status":      task.Status,
+		"priority":    task.Priority,
+		"recurrence":  task.Recurrence,
+		"updated_at":  task.UpdatedAt,
+	}
+	unset := bson.M{}
+
+	// A nil assignee means the task was unassigned
+	if task.AssigneeID != nil {
+		fields["assignee_id"] = *task.AssigneeID
+	} else {
+		unset["assignee_id"] = ""
+	}
+
+	if len(task.Tags) > 0 {
+		fields["tags"] = task.Tags
+	} else {
+		unset["tags"] = ""
+	}
+
+	if task.EstimateMinutes != nil {
+		fields["estimate_minutes"] = *task.EstimateMinutes
+	} else {
+		unset["estimate_minutes"] = ""
+	}
+
+	// The next occurrence link is only ever added, never removed
+	if task.NextOccurrenceID != nil {
+		fields["next_occurrence_id"] = *task.NextOccurrenceID
+	}
+
+	// Reminders are recorded through MarkNotified; an update can only clear them
+	if task.LastNotifiedAt == nil {
+		unset["last_notified_at"] = ""
+	}
+
+	update := bson.M{"$set": fields, "$inc": bson.M{"version": 1}}
+	if len(unset) > 0 {
+		update["$unset"] = unset
+	}
+	return update
+}
+
+// CompleteRecurring saves a completed recurring task and inserts its next occurrence
+// in a single transaction, linking the two through NextOccurrenceID.
+// The update only matches while no occurrence has been spawned, so a task spawns at most once.
+// On a standalone server, where the transaction manager runs without a transaction, the
+// occurrence is inserted only once the update has matched.
+func (tr *TaskRepository) CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	now := time.Now()
+	next.ID = primitive.NewObjectID()
+	next.CreatedAt = now
+	next.UpdatedAt = now
+	next.TitleKey = Domain.NormalizeTitle(next.Title)
+	task.UpdatedAt = now
+	task.NextOccurrenceID = &next.ID
+
+	err = tr.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		filter := bson.M{"_id": objectID, "deleted_at": nil, "next_occurrence_id": nil, "version": versionFilter(task.Version)}
+		result, err := tr.collection.UpdateOne(ctx, filter, buildTaskUpdate(task))
+		if err != nil {
+			return err
+		}
+		if result.MatchedCount == 0 {
+			return tr.missedUpdateError(ctx, objectID)
+		}
+
+		_, err = tr.collection.InsertOne(ctx, next)
+		return err
+	})
+	if err != nil {
+		task.NextOccurrenceID = nil
+		return err
+	}
+
+	task.Version++
+	return nil
+}
+
+// UpdateStatusMany sets the status of every active task in ids with a single UpdateMany.
+// When fromStatuses is not empty only tasks currently in one of those statuses are changed.
+// It returns the matched and modified counts and the tasks it selected, as saved, with the
+// status each had before, so the change can be announced.
+func (tr *TaskRepository) UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil}
+	if len(fromStatuses) > 0 {
+		filter["status"] = bson.M{"$in": fromStatuses}
+	}
+
+	var tasks []*Domain.Task
+	cursor, err := tr.collection.Find(ctx, filter)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if err := cursor.All(ctx, &tasks); err != nil {
+		return 0, 0, nil, err
+	}
+	if len(tasks) == 0 {
+		return 0, 0, nil, nil
+	}
+
+	selected := make([]primitive.ObjectID, len(tasks))
+	for i, task := range tasks {
+		selected[i] = task.ID
+	}
+	filter["_id"] = bson.M{"$in": selected}
+
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"status": status, "updated_at": now}, "$inc": bson.M{"version": 1}}
+
+	result, err := tr.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	changes := make([]Domain.TaskStatusChange, len(tasks))
+	for i, task := range tasks {
+		task.ApplyDefaults()
+		changes[i] = Domain.TaskStatusChange{Task: task, FromStatus: task.Status}
+		task.Status = status
+		task.UpdatedAt = now
+		task.Version++
+	}
+
+	return result.MatchedCount, result.ModifiedCount, changes, nil
+}
+
+// DeleteByStatus soft deletes every active task with the given status.
+// Parents that still have active subtasks in another status are left in place.
+// It returns the matched and modified counts and the IDs of the tasks it selected, so
+// their comments can follow them into the trash.
+func (tr *TaskRepository) DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	busyParents, err := tr.collection.Distinct(ctx, "parent_task_id", bson.M{
+		"deleted_at":     nil,
+		"parent_task_id": bson.M{"$ne": nil},
+		"status":         bson.M{"$ne": status},
+	})
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	filter := bson.M{"status": status, "deleted_at": nil}
+	if len(busyParents) > 0 {
+		filter["_id"] = bson.M{"$nin": busyParents}
+	}
+
+	selected, err := tr.collection.Distinct(ctx, "_id", filter)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+	if len(selected) == 0 {
+		return 0, 0, nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(selected))
+	for _, value := range selected {
+		if id, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	now := time.Now()
+	update := bson.M{"$set": bson.M{"deleted_at": now, "updated_at": now}}
+
+	result, err := tr.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "deleted_at": nil}, update)
+	if err != nil {
+		return 0, 0, nil, err
+	}
+
+	return result.MatchedCount, result.ModifiedCount, ids, nil
+}
+
+// Delete soft deletes a task by its ObjectID by stamping deleted_at
+func (tr *TaskRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
 
+	now := time.Now()
 	update := bson.M{
 		"$set": bson.M{
-			"title":       task.Title,
-			"description": task.Description,
-			"due_date":    task.DueDate,
-			"status":      task.Status,
-			"updated_at":  task.UpdatedAt,
+			"deleted_at": now,
+			"updated_at": now,
 		},
 	}
 
-	result, err := tr.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	result, err := tr.collection.UpdateOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}, update)
 	if err != nil {
 		return err
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("task not found")
+		return Domain.ErrTaskNotFound
 	}
 
 	return nil
 }
 
-// Delete deletes a task by its ObjectID from MongoDB
-func (tr *TaskRepository) Delete(id string) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// Restore clears deleted_at on a soft-deleted task
+func (tr *TaskRepository) Restore(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid task ID format")
+		return Domain.ErrInvalidTaskID
 	}
 
-	result, err := tr.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	update := bson.M{
+		"$unset": bson.M{"deleted_at": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+	}
+
+	result, err := tr.collection.UpdateOne(ctx, bson.M{"_id": objectID, "deleted_at": bson.M{"$ne": nil}}, update)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrTaskNotFound
+	}
+
+	return nil
+}
+
+// SetArchived archives or unarchives an active task and bumps its version
+func (tr *TaskRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	update := bson.M{
+		"$set": bson.M{"archived": archived, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := tr.collection.UpdateOne(ctx, bson.M{"_id": objectID, "deleted_at": nil}, update)
 	if err != nil {
 		return err
 	}
 
-	if result.DeletedCount == 0 {
-		return errors.New("task not found")
+	if result.MatchedCount == 0 {
+		return Domain.ErrTaskNotFound
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// ArchiveCompleted archives every active completed task last updated before the given time and
+// returns how many it archived. Tasks keep no completion time, so the last update stands in for it.
+func (tr *TaskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"status":     Domain.StatusCompleted,
+		"deleted_at": nil,
+		"archived":   bson.M{"$ne": true},
+		"updated_at": bson.M{"$lt": completedBefore},
+	}
+	update := bson.M{
+		"$set": bson.M{"archived": true, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	}
+
+	result, err := tr.collection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// Purge permanently removes tasks that were soft deleted before the given time
+func (tr *TaskRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := tr.collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lte": deletedBefore}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// PurgeByStatus permanently removes tasks with the given status, in the trash or not, last updated
+// before the given time. Parents that keep a subtask which is not removed are left in place.
+// It returns the IDs of the tasks it removed.
+func (tr *TaskRepository) PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	stale := bson.M{"status": status, "updated_at": bson.M{"$lte": updatedBefore}}
+	busyParents, err := tr.collection.Distinct(ctx, "parent_task_id", bson.M{
+		"parent_task_id": bson.M{"$ne": nil},
+		"$nor":           bson.A{stale},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	filter := bson.M{"status": status, "updated_at": bson.M{"$lte": updatedBefore}}
+	if len(busyParents) > 0 {
+		filter["_id"] = bson.M{"$nin": busyParents}
+	}
+
+	selected, err := tr.collection.Distinct(ctx, "_id", filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(selected))
+	for _, value := range selected {
+		if id, ok := value.(primitive.ObjectID); ok {
+			ids = append(ids, id)
+		}
+	}
+
+	if _, err := tr.collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}}); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ReassignCreator hands every task created by one user, soft-deleted ones included, to another user.
+// It returns the number of tasks changed.
+func (tr *TaskRepository) ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"created_by": toUserID, "updated_at": time.Now()}, "$inc": bson.M{"version": 1}}
+
+	result, err := tr.collection.UpdateMany(ctx, bson.M{"created_by": fromUserID}, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// UnassignUser clears the assignee of every task assigned to the user, soft-deleted ones included.
+// It returns the number of tasks changed.
+func (tr *TaskRepository) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	update := bson.M{
+		"$unset": bson.M{"assignee_id": ""},
+		"$set":   bson.M{"updated_at": time.Now()},
+		"$inc":   bson.M{"version": 1},
+	}
+
+	result, err := tr.collection.UpdateMany(ctx, bson.M{"assignee_id": userID}, update)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// GetDueForReminder returns up to limit open tasks due before dueBefore that still need a reminder,
+// soonest due first. A task needs one when it was never reminded, or when it is overdue at now and
+// its last reminder was sent before it fell due.
+func (tr *TaskRepository) GetDueForReminder(ctx context.Context, dueBefore, now time.Time, limit int64) ([]*Domain.Task, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	query := bson.M{
+		"deleted_at": nil,
+		"status":     bson.M{"$ne": Domain.StatusCompleted},
+		// Tasks without a due date are stored with the zero time
+		"due_date": bson.M{"$gt": time.Time{}, "$lt": dueBefore},
+		"$or": bson.A{
+			bson.M{"last_notified_at": nil},
+			bson.M{"due_date": bson.M{"$lt": now}, "$expr": bson.M{"$lt": bson.A{"$last_notified_at", "$due_date"}}},
+		},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}).SetLimit(limit)
+
+	tasks := []*Domain.Task{}
+	err := tr.retrier.Read(ctx, "tasks.get_due_for_reminder", func(ctx context.Context) error {
+		cursor, err := tr.collection.Find(ctx, query, opts)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &tasks)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		task.ApplyDefaults()
+	}
+
+	return tasks, nil
+}
+
+// MarkNotified records when a reminder was sent for a task. It is bookkeeping rather than an edit,
+// so neither the version nor the update time changes.
+func (tr *TaskRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := tr.collection.UpdateOne(ctx, bson.M{"_id": id, "deleted_at": nil}, bson.M{"$set": bson.M{"last_notified_at": at}})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrTaskNotFound
+	}
+	return nil
+}
+
+// GetTags returns the distinct tags used by tasks matching the filter, sorted alphabetically
+func (tr *TaskRepository) GetTags(ctx context.Context, filter Domain.TaskFilter) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var results []struct {
+		Tag string `bson:"_id"`
+	}
+	err := tr.retrier.Read(ctx, "tasks.get_tags", func(ctx context.Context) error {
+		cursor, err := tr.collection.Aggregate(ctx, buildTagsPipeline(buildTaskQuery(filter)))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &results)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]string, 0, len(results))
+	for _, result := range results {
+		tags = append(tags, result.Tag)
+	}
+
+	return tags, nil
+}
+
+// GetStats aggregates counts over the tasks matching the filter in a single pipeline
+func (tr *TaskRepository) GetStats(ctx context.Context, filter Domain.TaskFilter, now time.Time) (*Domain.TaskStats, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	now = now.UTC()
+	since := statsWindowStart(now)
+
+	type groupCount struct {
+		Key   string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	var results []struct {
+		ByStatus      []groupCount `bson:"by_status"`
+		Overdue       []groupCount `bson:"overdue"`
+		DueSoon       []groupCount `bson:"due_soon"`
+		CreatedPerDay []groupCount `bson:"created_per_day"`
+	}
+	err := tr.retrier.Read(ctx, "tasks.get_stats", func(ctx context.Context) error {
+		cursor, err := tr.collection.Aggregate(ctx, buildStatsPipeline(buildTaskQuery(filter), now, since))
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &results)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats := &Domain.TaskStats{
+		ByStatus: map[string]int64{
+			Domain.StatusPending:    0,
+			Domain.StatusInProgress: 0,
+			Domain.StatusCompleted:  0,
+		},
+	}
+	createdPerDay := map[string]int64{}
+	if len(results) > 0 {
+		result := results[0]
+		for _, group := range result.ByStatus {
+			stats.ByStatus[group.Key] = group.Count
+		}
+		for _, group := range result.Overdue {
+			stats.Overdue = group.Count
+		}
+		for _, group := range result.DueSoon {
+			stats.DueNext7Days = group.Count
+		}
+		for _, group := range result.CreatedPerDay {
+			createdPerDay[group.Key] = group.Count
+		}
+	}
+	stats.CreatedPerDay = fillDailyCounts(createdPerDay, since, Domain.StatsCreatedDays)
+
+	return stats, nil
+}
+
+// statsWindowStart returns midnight UTC of the first day in the created-per-day window ending today
+func statsWindowStart(now time.Time) time.Time {
+	year, month, day := now.UTC().Date()
+	return time.Date(year, month, day-(Domain.StatsCreatedDays-1), 0, 0, 0, 0, time.UTC)
+}
+
+// buildStatsPipeline builds an aggregation that computes every task statistic in one $facet.
+// Tasks without a due date are stored with the zero time and never count as overdue or due soon.
+func buildStatsPipeline(query bson.M, now time.Time, since time.Time) mongo.Pipeline {
+	countStage := bson.M{"$group": bson.M{"_id": nil, "count": bson.M{"$sum": 1}}}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$facet", Value: bson.M{
+			"by_status": bson.A{
+				bson.M{"$group": bson.M{"_id": "$status", "count": bson.M{"$sum": 1}}},
+			},
+			"overdue": bson.A{
+				bson.M{"$match": bson.M{
+					"status":   bson.M{"$ne": Domain.StatusCompleted},
+					"due_date": bson.M{"$gt": time.Time{}, "$lt": now},
+				}},
+				countStage,
+			},
+			"due_soon": bson.A{
+				bson.M{"$match": bson.M{
+					"status":   bson.M{"$ne": Domain.StatusCompleted},
+					"due_date": bson.M{"$gte": now, "$lt": now.AddDate(0, 0, 7)},
+				}},
+				countStage,
+			},
+			"created_per_day": bson.A{
+				bson.M{"$match": bson.M{"created_at": bson.M{"$gte": since}}},
+				bson.M{"$group": bson.M{
+					"_id":   bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$created_at"}},
+					"count": bson.M{"$sum": 1},
+				}},
+			},
+		}}},
+	}
+}
+
+// fillDailyCounts lists one entry per day starting at since, using zero for days without tasks
+func fillDailyCounts(counts map[string]int64, since time.Time, days int) []Domain.DailyCount {
+	daily := make([]Domain.DailyCount, days)
+	for i := range daily {
+		date := since.AddDate(0, 0, i).Format("2006-01-02")
+		daily[i] = Domain.DailyCount{Date: date, Count: counts[date]}
+	}
+	return daily
+}
+
+// buildTagsPipeline builds an aggregation that groups the tags of the tasks matching query
+func buildTagsPipeline(query bson.M) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.M{"_id": "$tags"}}},
+		{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+	}
+}
@@ -1,13 +1,16 @@
 package Repositories
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"task_manager/Domain"
 )
@@ -17,12 +20,12 @@ type MockTaskRepositoryImpl struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepositoryImpl) GetAll() ([]*Domain.Task, error) {
-	args := m.Called()
-	return args.Get(0).([]*Domain.Task), args.Error(1)
+func (m *MockTaskRepositoryImpl) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTaskRepositoryImpl) GetByID(id string) (*Domain.Task, error) {
+func (m *MockTaskRepositoryImpl) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,21 +33,156 @@ func (m *MockTaskRepositoryImpl) GetByID(id string) (*Domain.Task, error) {
 	return args.Get(0).(*Domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepositoryImpl) Create(task *Domain.Task) error {
+func (m *MockTaskRepositoryImpl) GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error) {
+	args := m.Called(id, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) CountTasks(ctx context.Context, filter Domain.TaskFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) Search(ctx context.Context, query string, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(query, filter, pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskRepositoryImpl) Stream(ctx context.Context, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepositoryImpl) ExistsByTitleAndDueDate(ctx context.Context, title string, dueDate time.Time) (bool, error) {
+	args := m.Called(title, dueDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) FindOpenByTitle(ctx context.Context, createdBy primitive.ObjectID, title string) (*Domain.Task, error) {
+	args := m.Called(createdBy, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) Create(ctx context.Context, task *Domain.Task) error {
 	args := m.Called(task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepositoryImpl) Update(id string, task *Domain.Task) error {
+func (m *MockTaskRepositoryImpl) CreateMany(ctx context.Context, tasks []*Domain.Task) error {
+	args := m.Called(tasks)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepositoryImpl) UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error) {
+	args := m.Called(ids, status, fromStatuses)
+	changes, _ := args.Get(2).([]Domain.TaskStatusChange)
+	return args.Get(0).(int64), args.Get(1).(int64), changes, args.Error(3)
+}
+
+func (m *MockTaskRepositoryImpl) DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error) {
+	args := m.Called(status)
+	ids, _ := args.Get(2).([]primitive.ObjectID)
+	return args.Get(0).(int64), args.Get(1).(int64), ids, args.Error(3)
+}
+
+func (m *MockTaskRepositoryImpl) Update(ctx context.Context, id string, task *Domain.Task) error {
 	args := m.Called(id, task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepositoryImpl) Delete(id string) error {
+func (m *MockTaskRepositoryImpl) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepositoryImpl) Restore(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockTaskRepositoryImpl) SetArchived(ctx context.Context, id string, archived bool) error {
+	args := m.Called(id, archived)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepositoryImpl) ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	args := m.Called(completedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error) {
+	args := m.Called(status, updatedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	args := m.Called(fromUserID, toUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) GetTags(ctx context.Context, filter Domain.TaskFilter) ([]string, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]string), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) GetByParentID(ctx context.Context, parentID string) ([]*Domain.Task, error) {
+	args := m.Called(parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) GetDueForReminder(ctx context.Context, dueBefore, now time.Time, limit int64) ([]*Domain.Task, error) {
+	args := m.Called(dueBefore, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockTaskRepositoryImpl) GetStats(ctx context.Context, filter Domain.TaskFilter, now time.Time) (*Domain.TaskStats, error) {
+	args := m.Called(filter, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.TaskStats), args.Error(1)
+}
+
+func (m *MockTaskRepositoryImpl) CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error {
+	args := m.Called(id, task, next)
+	return args.Error(0)
+}
+
 func TestTaskRepository_GetAll(t *testing.T) {
 	t.Run("Success - return all tasks", func(t *testing.T) {
 		// Arrange
@@ -67,15 +205,16 @@ func TestTaskRepository_GetAll(t *testing.T) {
 				UpdatedAt:   time.Now(),
 			},
 		}
-		mockRepo.On("GetAll").Return(expectedTasks, nil)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, Domain.Pagination{}).Return(expectedTasks, int64(2), nil)
 
 		// Act
-		tasks, err := mockRepo.GetAll()
+		tasks, total, err := mockRepo.GetAll(context.Background(), Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTasks, tasks)
 		assert.Len(t, tasks, 2)
+		assert.Equal(t, int64(2), total)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -83,15 +222,16 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		expectedTasks := []*Domain.Task{}
-		mockRepo.On("GetAll").Return(expectedTasks, nil)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, Domain.Pagination{}).Return(expectedTasks, int64(0), nil)
 
 		// Act
-		tasks, err := mockRepo.GetAll()
+		tasks, total, err := mockRepo.GetAll(context.Background(), Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTasks, tasks)
 		assert.Len(t, tasks, 0)
+		assert.Equal(t, int64(0), total)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -99,14 +239,460 @@ func TestTaskRepository_GetAll(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		expectedError := errors.New("database connection failed")
-		mockRepo.On("GetAll").Return([]*Domain.Task(nil), expectedError)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, Domain.Pagination{}).Return([]*Domain.Task(nil), int64(0), expectedError)
 
 		// Act
-		tasks, err := mockRepo.GetAll()
+		tasks, _, err := mockRepo.GetAll(context.Background(), Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, tasks)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - return requested page", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		pagination := Domain.Pagination{Limit: 1, Offset: 1}
+		expectedTasks := []*Domain.Task{
+			{
+				ID:     primitive.NewObjectID(),
+				Title:  "Task 2",
+				Status: Domain.StatusPending,
+			},
+		}
+		mockRepo.On("GetAll", Domain.TaskFilter{}, pagination).Return(expectedTasks, int64(3), nil)
+
+		// Act
+		tasks, total, err := mockRepo.GetAll(context.Background(), Domain.TaskFilter{}, pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, tasks, 1)
+		assert.Equal(t, int64(3), total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - return filtered tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		filter := Domain.TaskFilter{Status: Domain.StatusCompleted}
+		expectedTasks := []*Domain.Task{
+			{
+				ID:     primitive.NewObjectID(),
+				Title:  "Done task",
+				Status: Domain.StatusCompleted,
+			},
+		}
+		mockRepo.On("GetAll", filter, Domain.Pagination{}).Return(expectedTasks, int64(1), nil)
+
+		// Act
+		tasks, total, err := mockRepo.GetAll(context.Background(), filter, Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(1), total)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestBuildTaskQuery(t *testing.T) {
+	dueAfter := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	dueBefore := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+	ownerID := primitive.NewObjectID()
+	notArchived := bson.M{"$ne": true}
+
+	tests := []struct {
+		name     string
+		filter   Domain.TaskFilter
+		expected bson.M
+	}{
+		{
+			name:     "Empty filter matches every active task that is not archived",
+			filter:   Domain.TaskFilter{},
+			expected: bson.M{"deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Status filter",
+			filter:   Domain.TaskFilter{Status: Domain.StatusPending},
+			expected: bson.M{"status": Domain.StatusPending, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Due before filter",
+			filter:   Domain.TaskFilter{DueBefore: dueBefore},
+			expected: bson.M{"due_date": bson.M{"$lte": dueBefore}, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Due after filter",
+			filter:   Domain.TaskFilter{DueAfter: dueAfter},
+			expected: bson.M{"due_date": bson.M{"$gte": dueAfter}, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:   "Overdue filter excludes completed tasks and tasks without a due date",
+			filter: Domain.TaskFilter{OverdueAt: dueBefore},
+			expected: bson.M{
+				"due_date":   bson.M{"$gt": time.Time{}, "$lt": dueBefore},
+				"status":     bson.M{"$ne": Domain.StatusCompleted},
+				"deleted_at": nil,
+				"archived":   notArchived,
+			},
+		},
+		{
+			name: "Combined filters are AND-ed",
+			filter: Domain.TaskFilter{
+				Status:    Domain.StatusCompleted,
+				DueBefore: dueBefore,
+				DueAfter:  dueAfter,
+			},
+			expected: bson.M{
+				"status":     Domain.StatusCompleted,
+				"due_date":   bson.M{"$gte": dueAfter, "$lte": dueBefore},
+				"deleted_at": nil,
+				"archived":   notArchived,
+			},
+		},
+		{
+			name:     "Priority filter",
+			filter:   Domain.TaskFilter{Priority: Domain.PriorityHigh},
+			expected: bson.M{"priority": Domain.PriorityHigh, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Medium priority filter also matches tasks without a priority",
+			filter:   Domain.TaskFilter{Priority: Domain.PriorityMedium},
+			expected: bson.M{"priority": bson.M{"$in": bson.A{Domain.PriorityMedium, nil}}, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Tag filter matches array members",
+			filter:   Domain.TaskFilter{Tag: "backend"},
+			expected: bson.M{"tags": "backend", "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Created by filter",
+			filter:   Domain.TaskFilter{CreatedBy: ownerID},
+			expected: bson.M{"created_by": ownerID, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Open only filter excludes completed tasks",
+			filter:   Domain.TaskFilter{CreatedBy: ownerID, OpenOnly: true},
+			expected: bson.M{"created_by": ownerID, "status": bson.M{"$ne": Domain.StatusCompleted}, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Assignee filter",
+			filter:   Domain.TaskFilter{AssigneeID: ownerID},
+			expected: bson.M{"assignee_id": ownerID, "deleted_at": nil, "archived": notArchived},
+		},
+		{
+			name:     "Include deleted drops the deleted_at condition",
+			filter:   Domain.TaskFilter{IncludeDeleted: true},
+			expected: bson.M{"archived": notArchived},
+		},
+		{
+			name:     "Only deleted matches soft-deleted tasks",
+			filter:   Domain.TaskFilter{OnlyDeleted: true},
+			expected: bson.M{"deleted_at": bson.M{"$ne": nil}, "archived": notArchived},
+		},
+		{
+			name:     "Include archived drops the archived condition",
+			filter:   Domain.TaskFilter{IncludeArchived: true},
+			expected: bson.M{"deleted_at": nil},
+		},
+		{
+			name:     "Only archived matches archived tasks",
+			filter:   Domain.TaskFilter{OnlyArchived: true},
+			expected: bson.M{"deleted_at": nil, "archived": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			query := buildTaskQuery(tt.filter)
+
+			// Assert
+			assert.Equal(t, tt.expected, query)
+		})
+	}
+}
+
+func TestBuildFindOptions(t *testing.T) {
+	t.Run("Zero pagination sets no limit or skip", func(t *testing.T) {
+		// Act
+		findOptions := buildFindOptions(Domain.Pagination{})
+
+		// Assert
+		assert.Nil(t, findOptions.Limit)
+		assert.Nil(t, findOptions.Skip)
+		assert.Equal(t, bson.D{{Key: "_id", Value: 1}}, findOptions.Sort)
+	})
+
+	t.Run("Limit and offset are applied", func(t *testing.T) {
+		// Act
+		findOptions := buildFindOptions(Domain.Pagination{Limit: 10, Offset: 30})
+
+		// Assert
+		assert.Equal(t, int64(10), *findOptions.Limit)
+		assert.Equal(t, int64(30), *findOptions.Skip)
+	})
+
+	t.Run("Due date sort orders earliest first", func(t *testing.T) {
+		// Act
+		findOptions := buildFindOptions(Domain.Pagination{Sort: Domain.SortDueDate})
+
+		// Assert
+		assert.Equal(t, bson.D{{Key: "due_date", Value: 1}, {Key: "_id", Value: 1}}, findOptions.Sort)
+	})
+}
+
+func TestBuildSearchFindOptions(t *testing.T) {
+	textScore := bson.M{"$meta": "textScore"}
+
+	// Act
+	findOptions := buildSearchFindOptions(Domain.Pagination{Limit: 10, Offset: 30})
+
+	// Assert
+	assert.Equal(t, bson.M{"score": textScore}, findOptions.Projection)
+	assert.Equal(t, bson.D{{Key: "score", Value: textScore}, {Key: "_id", Value: 1}}, findOptions.Sort)
+	assert.Equal(t, int64(10), *findOptions.Limit)
+	assert.Equal(t, int64(30), *findOptions.Skip)
+}
+
+func TestBuildPrioritySortPipeline(t *testing.T) {
+	query := bson.M{"deleted_at": nil}
+
+	t.Run("Ranks priorities and breaks ties by _id", func(t *testing.T) {
+		// Act
+		pipeline := buildPrioritySortPipeline(query, Domain.Pagination{Sort: Domain.SortPriority})
+
+		// Assert
+		assert.Len(t, pipeline, 4)
+		assert.Equal(t, bson.D{{Key: "$match", Value: query}}, pipeline[0])
+		assert.Equal(t, "$addFields", pipeline[1][0].Key)
+		assert.Equal(t, bson.D{{Key: "$sort", Value: bson.D{{Key: "priority_rank", Value: -1}, {Key: "_id", Value: 1}}}}, pipeline[2])
+		assert.Equal(t, bson.D{{Key: "$project", Value: bson.M{"priority_rank": 0}}}, pipeline[3])
+	})
+
+	t.Run("Limit and offset are applied after sorting", func(t *testing.T) {
+		// Act
+		pipeline := buildPrioritySortPipeline(query, Domain.Pagination{Limit: 10, Offset: 30, Sort: Domain.SortPriority})
+
+		// Assert
+		assert.Len(t, pipeline, 6)
+		assert.Equal(t, bson.D{{Key: "$skip", Value: int64(30)}}, pipeline[3])
+		assert.Equal(t, bson.D{{Key: "$limit", Value: int64(10)}}, pipeline[4])
+	})
+}
+
+func TestBuildTagsPipeline(t *testing.T) {
+	t.Run("Unwinds and groups tags of matching tasks", func(t *testing.T) {
+		query := bson.M{"deleted_at": nil}
+
+		// Act
+		pipeline := buildTagsPipeline(query)
+
+		// Assert
+		assert.Equal(t, mongo.Pipeline{
+			{{Key: "$match", Value: query}},
+			{{Key: "$unwind", Value: "$tags"}},
+			{{Key: "$group", Value: bson.M{"_id": "$tags"}}},
+			{{Key: "$sort", Value: bson.D{{Key: "_id", Value: 1}}}},
+		}, pipeline)
+	})
+}
+
+func TestVersionFilter(t *testing.T) {
+	t.Run("Version 0 also matches documents without a version", func(t *testing.T) {
+		assert.Equal(t, bson.M{"$in": bson.A{0, nil}}, versionFilter(0))
+	})
+
+	t.Run("Later versions match exactly", func(t *testing.T) {
+		assert.Equal(t, 3, versionFilter(3))
+	})
+}
+
+func TestBuildTaskUpdate(t *testing.T) {
+	t.Run("Unsets empty optional fields", func(t *testing.T) {
+		// Arrange
+		task := &Domain.Task{Title: "Task", Status: Domain.StatusPending, Priority: Domain.PriorityLow, Recurrence: Domain.RecurrenceNone}
+
+		// Act
+		update := buildTaskUpdate(task)
+
+		// Assert
+		fields := update["$set"].(bson.M)
+		assert.Equal(t, "Task", fields["title"])
+		assert.Equal(t, "task", fields["title_key"])
+		assert.Equal(t, Domain.RecurrenceNone, fields["recurrence"])
+		assert.NotContains(t, fields, "next_occurrence_id")
+		assert.Equal(t, bson.M{"assignee_id": "", "tags": "", "estimate_minutes": "", "last_notified_at": ""}, update["$unset"])
+		assert.Equal(t, bson.M{"version": 1}, update["$inc"])
+	})
+
+	t.Run("Sets assignee, tags, estimate and next occurrence", func(t *testing.T) {
+		// Arrange
+		assigneeID := primitive.NewObjectID()
+		estimate := 90
+		nextID := primitive.NewObjectID()
+		notifiedAt := time.Now()
+		task := &Domain.Task{
+			Title:            "Task",
+			Tags:             []string{"ops"},
+			EstimateMinutes:  &estimate,
+			AssigneeID:       &assigneeID,
+			Recurrence:       Domain.RecurrenceDaily,
+			NextOccurrenceID: &nextID,
+			LastNotifiedAt:   &notifiedAt,
+		}
+
+		// Act
+		update := buildTaskUpdate(task)
+
+		// Assert
+		fields := update["$set"].(bson.M)
+		assert.Equal(t, assigneeID, fields["assignee_id"])
+		assert.Equal(t, []string{"ops"}, fields["tags"])
+		assert.Equal(t, 90, fields["estimate_minutes"])
+		assert.Equal(t, nextID, fields["next_occurrence_id"])
+		assert.NotContains(t, fields, "last_notified_at")
+		assert.NotContains(t, update, "$unset")
+	})
+}
+
+func TestBuildStatsPipeline(t *testing.T) {
+	t.Run("Matches the filter then facets every statistic", func(t *testing.T) {
+		// Arrange
+		query := bson.M{"deleted_at": nil}
+		now := time.Date(2024, 12, 31, 12, 0, 0, 0, time.UTC)
+		since := statsWindowStart(now)
+
+		// Act
+		pipeline := buildStatsPipeline(query, now, since)
+
+		// Assert
+		assert.Len(t, pipeline, 2)
+		assert.Equal(t, bson.D{{Key: "$match", Value: query}}, pipeline[0])
+		facet := pipeline[1][0].Value.(bson.M)
+		assert.ElementsMatch(t, []string{"by_status", "overdue", "due_soon", "created_per_day"}, keysOf(facet))
+
+		overdue := facet["overdue"].(bson.A)[0].(bson.M)["$match"].(bson.M)
+		assert.Equal(t, bson.M{"$gt": time.Time{}, "$lt": now}, overdue["due_date"])
+		assert.Equal(t, bson.M{"$ne": Domain.StatusCompleted}, overdue["status"])
+
+		dueSoon := facet["due_soon"].(bson.A)[0].(bson.M)["$match"].(bson.M)
+		assert.Equal(t, bson.M{"$gte": now, "$lt": now.AddDate(0, 0, 7)}, dueSoon["due_date"])
+	})
+}
+
+func TestStatsWindowStart(t *testing.T) {
+	now := time.Date(2024, 12, 31, 18, 30, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, 12, 2, 0, 0, 0, 0, time.UTC), statsWindowStart(now))
+}
+
+func TestFillDailyCounts(t *testing.T) {
+	since := time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC)
+
+	daily := fillDailyCounts(map[string]int64{"2024-12-31": 3}, since, 3)
+
+	assert.Equal(t, []Domain.DailyCount{
+		{Date: "2024-12-30", Count: 0},
+		{Date: "2024-12-31", Count: 3},
+		{Date: "2025-01-01", Count: 0},
+	}, daily)
+}
+
+// keysOf lists the keys of a bson.M
+func keysOf(m bson.M) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func TestTaskIndexes(t *testing.T) {
+	t.Run("Parent task index is sparse", func(t *testing.T) {
+		// Act
+		indexes := taskIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 7)
+		assert.Equal(t, bson.D{{Key: "parent_task_id", Value: 1}}, indexes[0].Keys)
+		assert.Equal(t, "parent_task_id_1", *indexes[0].Options.Name)
+		assert.True(t, *indexes[0].Options.Sparse)
+	})
+
+	t.Run("Filtered fields are indexed", func(t *testing.T) {
+		// Act
+		indexes := taskIndexes()
+
+		// Assert
+		expected := map[string]bson.D{
+			"status_1":                 {{Key: "status", Value: 1}},
+			"due_date_1":               {{Key: "due_date", Value: 1}},
+			"created_by_1":             {{Key: "created_by", Value: 1}},
+			"tags_1":                   {{Key: "tags", Value: 1}},
+			"created_by_1_title_key_1": {{Key: "created_by", Value: 1}, {Key: "title_key", Value: 1}},
+		}
+		for _, index := range indexes {
+			if keys, ok := expected[*index.Options.Name]; ok {
+				assert.Equal(t, keys, index.Keys)
+				delete(expected, *index.Options.Name)
+			}
+		}
+		assert.Empty(t, expected)
+	})
+
+	t.Run("Titles and descriptions share one text index", func(t *testing.T) {
+		// Act
+		indexes := taskIndexes()
+
+		// Assert
+		var textIndexes []mongo.IndexModel
+		for _, index := range indexes {
+			for _, key := range index.Keys.(bson.D) {
+				if key.Value == "text" {
+					textIndexes = append(textIndexes, index)
+					break
+				}
+			}
+		}
+		assert.Len(t, textIndexes, 1)
+		assert.Equal(t, bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}}, textIndexes[0].Keys)
+	})
+}
+
+func TestTaskRepository_GetByParentID(t *testing.T) {
+	t.Run("Success - return subtasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		parentID := primitive.NewObjectID()
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Subtask", ParentTaskID: &parentID},
+		}
+		mockRepo.On("GetByParentID", parentID.Hex()).Return(expectedTasks, nil)
+
+		// Act
+		tasks, err := mockRepo.GetByParentID(context.Background(), parentID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid parent ID format", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		expectedError := Domain.ErrInvalidTaskID
+		mockRepo.On("GetByParentID", "invalid-id").Return(nil, expectedError)
+
+		// Act
+		tasks, err := mockRepo.GetByParentID(context.Background(), "invalid-id")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, tasks)
 		mockRepo.AssertExpectations(t)
 	})
@@ -128,7 +714,7 @@ func TestTaskRepository_GetByID(t *testing.T) {
 		mockRepo.On("GetByID", taskID).Return(expectedTask, nil)
 
 		// Act
-		task, err := mockRepo.GetByID(taskID)
+		task, err := mockRepo.GetByID(context.Background(), taskID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -140,15 +726,15 @@ func TestTaskRepository_GetByID(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		taskID := primitive.NewObjectID().Hex()
-		expectedError := errors.New("task not found")
+		expectedError := Domain.ErrTaskNotFound
 		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
 
 		// Act
-		task, err := mockRepo.GetByID(taskID)
+		task, err := mockRepo.GetByID(context.Background(), taskID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, task)
 		mockRepo.AssertExpectations(t)
 	})
@@ -157,15 +743,15 @@ func TestTaskRepository_GetByID(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		invalidID := "invalid-id-format"
-		expectedError := errors.New("invalid task ID format")
+		expectedError := Domain.ErrInvalidTaskID
 		mockRepo.On("GetByID", invalidID).Return(nil, expectedError)
 
 		// Act
-		task, err := mockRepo.GetByID(invalidID)
+		task, err := mockRepo.GetByID(context.Background(), invalidID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, task)
 		mockRepo.AssertExpectations(t)
 	})
@@ -183,7 +769,7 @@ func TestTaskRepository_Create(t *testing.T) {
 		mockRepo.On("Create", task).Return(nil)
 
 		// Act
-		err := mockRepo.Create(task)
+		err := mockRepo.Create(context.Background(), task)
 
 		// Assert
 		assert.NoError(t, err)
@@ -201,11 +787,11 @@ func TestTaskRepository_Create(t *testing.T) {
 		mockRepo.On("Create", task).Return(expectedError)
 
 		// Act
-		err := mockRepo.Create(task)
+		err := mockRepo.Create(context.Background(), task)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -222,7 +808,7 @@ func TestTaskRepository_Create(t *testing.T) {
 		mockRepo.On("Create", task).Return(nil)
 
 		// Act
-		err := mockRepo.Create(task)
+		err := mockRepo.Create(context.Background(), task)
 
 		// Assert
 		assert.NoError(t, err)
@@ -244,7 +830,7 @@ func TestTaskRepository_Update(t *testing.T) {
 		mockRepo.On("Update", taskID, task).Return(nil)
 
 		// Act
-		err := mockRepo.Update(taskID, task)
+		err := mockRepo.Update(context.Background(), taskID, task)
 
 		// Assert
 		assert.NoError(t, err)
@@ -259,15 +845,15 @@ func TestTaskRepository_Update(t *testing.T) {
 			Title:  "Updated Task",
 			Status: Domain.StatusCompleted,
 		}
-		expectedError := errors.New("task not found")
+		expectedError := Domain.ErrTaskNotFound
 		mockRepo.On("Update", taskID, task).Return(expectedError)
 
 		// Act
-		err := mockRepo.Update(taskID, task)
+		err := mockRepo.Update(context.Background(), taskID, task)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -279,15 +865,15 @@ func TestTaskRepository_Update(t *testing.T) {
 			Title:  "Updated Task",
 			Status: Domain.StatusCompleted,
 		}
-		expectedError := errors.New("invalid task ID format")
+		expectedError := Domain.ErrInvalidTaskID
 		mockRepo.On("Update", invalidID, task).Return(expectedError)
 
 		// Act
-		err := mockRepo.Update(invalidID, task)
+		err := mockRepo.Update(context.Background(), invalidID, task)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -303,11 +889,11 @@ func TestTaskRepository_Update(t *testing.T) {
 		mockRepo.On("Update", taskID, task).Return(expectedError)
 
 		// Act
-		err := mockRepo.Update(taskID, task)
+		err := mockRepo.Update(context.Background(), taskID, task)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -320,7 +906,7 @@ func TestTaskRepository_Delete(t *testing.T) {
 		mockRepo.On("Delete", taskID).Return(nil)
 
 		// Act
-		err := mockRepo.Delete(taskID)
+		err := mockRepo.Delete(context.Background(), taskID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -331,15 +917,15 @@ func TestTaskRepository_Delete(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		taskID := primitive.NewObjectID().Hex()
-		expectedError := errors.New("task not found")
+		expectedError := Domain.ErrTaskNotFound
 		mockRepo.On("Delete", taskID).Return(expectedError)
 
 		// Act
-		err := mockRepo.Delete(taskID)
+		err := mockRepo.Delete(context.Background(), taskID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -347,15 +933,15 @@ func TestTaskRepository_Delete(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		invalidID := "invalid-id"
-		expectedError := errors.New("invalid task ID format")
+		expectedError := Domain.ErrInvalidTaskID
 		mockRepo.On("Delete", invalidID).Return(expectedError)
 
 		// Act
-		err := mockRepo.Delete(invalidID)
+		err := mockRepo.Delete(context.Background(), invalidID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -367,11 +953,11 @@ func TestTaskRepository_Delete(t *testing.T) {
 		mockRepo.On("Delete", taskID).Return(expectedError)
 
 		// Act
-		err := mockRepo.Delete(taskID)
+		err := mockRepo.Delete(context.Background(), taskID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -395,7 +981,7 @@ func TestTaskRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("Create", task).Return(nil)
 
 		// Act
-		err := mockRepo.Create(task)
+		err := mockRepo.Create(context.Background(), task)
 
 		// Assert
 		assert.NoError(t, err)
@@ -415,7 +1001,7 @@ func TestTaskRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("Update", taskID, task).Return(nil)
 
 		// Act
-		err := mockRepo.Update(taskID, task)
+		err := mockRepo.Update(context.Background(), taskID, task)
 
 		// Assert
 		assert.NoError(t, err)
@@ -426,15 +1012,102 @@ func TestTaskRepository_EdgeCases(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepositoryImpl)
 		longID := "very-long-id-that-might-cause-issues-in-some-systems-but-should-be-handled-gracefully"
-		expectedError := errors.New("invalid task ID format")
+		expectedError := Domain.ErrInvalidTaskID
 		mockRepo.On("GetByID", longID).Return(nil, expectedError)
 
 		// Act
-		task, err := mockRepo.GetByID(longID)
+		task, err := mockRepo.GetByID(context.Background(), longID)
 
 		// Assert
 		assert.Error(t, err)
 		assert.Nil(t, task)
 		mockRepo.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestTaskRepository_Restore(t *testing.T) {
+	t.Run("Success - restore soft-deleted task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("Restore", taskID).Return(nil)
+
+		// Act
+		err := mockRepo.Restore(context.Background(), taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - no soft-deleted task with ID", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		taskID := primitive.NewObjectID().Hex()
+		expectedError := Domain.ErrTaskNotFound
+		mockRepo.On("Restore", taskID).Return(expectedError)
+
+		// Act
+		err := mockRepo.Restore(context.Background(), taskID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskRepository_Purge(t *testing.T) {
+	t.Run("Success - purge old soft-deleted tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		cutoff := time.Now().AddDate(0, 0, -30)
+		mockRepo.On("Purge", cutoff).Return(int64(4), nil)
+
+		// Act
+		purged, err := mockRepo.Purge(context.Background(), cutoff)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), purged)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - database error", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		cutoff := time.Now()
+		expectedError := errors.New("database connection failed")
+		mockRepo.On("Purge", cutoff).Return(int64(0), expectedError)
+
+		// Act
+		purged, err := mockRepo.Purge(context.Background(), cutoff)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, int64(0), purged)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskRepository_ReassignCreatorAndUnassignUser(t *testing.T) {
+	t.Run("Success - move a leaving user's tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepositoryImpl)
+		userID := primitive.NewObjectID()
+		adminID := primitive.NewObjectID()
+		mockRepo.On("ReassignCreator", userID, adminID).Return(int64(3), nil)
+		mockRepo.On("UnassignUser", userID).Return(int64(2), nil)
+
+		// Act
+		reassigned, err := mockRepo.ReassignCreator(context.Background(), userID, adminID)
+		assert.NoError(t, err)
+		unassigned, err := mockRepo.UnassignUser(context.Background(), userID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), reassigned)
+		assert.Equal(t, int64(2), unassigned)
+		mockRepo.AssertExpectations(t)
+	})
+}
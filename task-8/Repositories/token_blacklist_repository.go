@@ -0,0 +1,78 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TokenBlacklistRepositoryInterface defines the contract for revoked access token storage.
+// It satisfies Infrastructure.TokenBlacklist.
+type TokenBlacklistRepositoryInterface interface {
+	Add(ctx context.Context, jti string, expiresAt time.Time) error
+	Contains(ctx context.Context, jti string) (bool, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// TokenBlacklistRepository implements TokenBlacklistRepositoryInterface with a MongoDB
+// collection whose TTL index removes each entry once the token it revokes has expired
+type TokenBlacklistRepository struct {
+	collection *mongo.Collection
+}
+
+// NewTokenBlacklistRepository creates a new instance of TokenBlacklistRepository
+func NewTokenBlacklistRepository(client *mongo.Client, dbName string) TokenBlacklistRepositoryInterface {
+	collection := client.Database(dbName).Collection("revoked_tokens")
+	return &TokenBlacklistRepository{
+		collection: collection,
+	}
+}
+
+// Add records a revoked token until it expires. Revoking the same token twice is not an error.
+func (br *TokenBlacklistRepository) Add(ctx context.Context, jti string, expiresAt time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := br.collection.UpdateOne(ctx,
+		bson.M{"_id": jti},
+		bson.M{"$set": bson.M{"expires_at": expiresAt}},
+		options.Update().SetUpsert(true),
+	)
+	return err
+}
+
+// Contains reports whether a token has been revoked. Entries past their expiry are ignored,
+// since MongoDB only removes expired documents about once a minute.
+func (br *TokenBlacklistRepository) Contains(ctx context.Context, jti string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	count, err := br.collection.CountDocuments(ctx,
+		bson.M{"_id": jti, "expires_at": bson.M{"$gt": time.Now()}},
+		options.Count().SetLimit(1),
+	)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// EnsureIndexes creates the TTL index that removes entries once their token has expired
+func (br *TokenBlacklistRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := br.collection.Indexes().CreateOne(ctx, tokenBlacklistIndex())
+	return err
+}
+
+// tokenBlacklistIndex is the TTL index maintained on the revoked_tokens collection
+func tokenBlacklistIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: "expires_at", Value: 1}},
+		Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(0),
+	}
+}
@@ -0,0 +1,23 @@
+package Repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestTokenBlacklistIndex(t *testing.T) {
+	t.Run("Entries expire with their token", func(t *testing.T) {
+		// Act
+		index := tokenBlacklistIndex()
+
+		// Assert
+		assert.Equal(t, bson.D{{Key: "expires_at", Value: 1}}, index.Keys)
+		assert.Equal(t, int32(0), *index.Options.ExpireAfterSeconds)
+	})
+}
+
+func TestTokenBlacklistRepositoryInterface(t *testing.T) {
+	var _ TokenBlacklistRepositoryInterface = &TokenBlacklistRepository{}
+}
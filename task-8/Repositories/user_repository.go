@@ -2,74 +2,225 @@ package Repositories
 
 import (
 	"context"
-	"errors"
+	"regexp"
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
 )
 
 // UserRepositoryInterface defines the contract for user data access
 type UserRepositoryInterface interface {
-	GetAll() ([]*Domain.User, error)
-	GetByID(id string) (*Domain.User, error)
-	GetByUsername(username string) (*Domain.User, error)
-	Create(user *Domain.User) error
-	Update(id string, user *Domain.User) error
-	UpdateByUsername(username string, user *Domain.User) error
-	CountUsers() (int64, error)
+	GetAll(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error)
+	Stream(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error
+	GetDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error)
+	GetByID(ctx context.Context, id string) (*Domain.User, error)
+	GetByUsername(ctx context.Context, username string) (*Domain.User, error)
+	GetByEmail(ctx context.Context, email string) (*Domain.User, error)
+	Create(ctx context.Context, user *Domain.User) error
+	Update(ctx context.Context, id string, user *Domain.User) error
+	UpdateByUsername(ctx context.Context, username string, user *Domain.User) error
+	UpdateProfile(ctx context.Context, id string, user *Domain.User) error
+	MarkEmailVerified(ctx context.Context, id string, email string) error
+	UpdateLastLogin(ctx context.Context, id string, at time.Time) error
+	SetActive(ctx context.Context, id string, active bool) error
+	IsActive(ctx context.Context, id string) (bool, error)
+	Anonymize(ctx context.Context, id string) error
+	Delete(ctx context.Context, id string) error
+	CountUsers(ctx context.Context) (int64, error)
+	CountByRole(ctx context.Context, role string) (int64, error)
+	ClaimFirstAdmin(ctx context.Context, id string) (bool, error)
+	EnsureIndexes(ctx context.Context) error
 }
 
 // UserRepository implements UserRepositoryInterface with MongoDB
 type UserRepository struct {
 	collection *mongo.Collection
+	retrier    *Infrastructure.Retrier
 }
 
-// NewUserRepository creates a new instance of UserRepository
-func NewUserRepository(client *mongo.Client, dbName string) UserRepositoryInterface {
+// NewUserRepository creates a new instance of UserRepository. Reads are retried on transient
+// errors, and so are the updates that can safely be applied twice.
+func NewUserRepository(client *mongo.Client, dbName string, retrier *Infrastructure.Retrier) UserRepositoryInterface {
 	collection := client.Database(dbName).Collection("users")
 	return &UserRepository{
 		collection: collection,
+		retrier:    retrier,
 	}
 }
 
-// GetAll returns all users from MongoDB
-func (ur *UserRepository) GetAll() ([]*Domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetAll returns one page of users matching the filter with the total number of matches.
+// Users are ordered by creation unless pagination.Sort is SortUsername; the ID breaks ties
+// so pages never overlap.
+func (ur *UserRepository) GetAll(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	cursor, err := ur.collection.Find(ctx, bson.M{})
+	query := buildUserQuery(filter)
+
+	sortField := "created_at"
+	if pagination.Sort == Domain.SortUsername {
+		sortField = "username_lower"
+	}
+	findOptions := options.Find().SetSort(bson.D{{Key: sortField, Value: 1}, {Key: "_id", Value: 1}})
+	if pagination.Limit > 0 {
+		findOptions.SetLimit(pagination.Limit)
+	}
+	if pagination.Offset > 0 {
+		findOptions.SetSkip(pagination.Offset)
+	}
+
+	var total int64
+	users := []*Domain.User{}
+	err := ur.retrier.Read(ctx, "users.get_all", func(ctx context.Context) error {
+		var err error
+		total, err = ur.collection.CountDocuments(ctx, query)
+		if err != nil {
+			return err
+		}
+
+		cursor, err := ur.collection.Find(ctx, query, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &users)
+	})
 	if err != nil {
-		return nil, err
+		return nil, 0, err
+	}
+
+	return users, total, nil
+}
+
+// GetDirectory returns one page of the IDs and usernames of users whose username contains query,
+// ignoring case, with the total number of matches. Only those two fields are read. Users are ordered
+// as by GetAll.
+func (ur *UserRepository) GetDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if query != "" {
+		filter["username"] = bson.M{"$regex": regexp.QuoteMeta(query), "$options": "i"}
+	}
+
+	sortField := "created_at"
+	if pagination.Sort == Domain.SortUsername {
+		sortField = "username_lower"
+	}
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: sortField, Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"_id": 1, "username": 1})
+	if pagination.Limit > 0 {
+		findOptions.SetLimit(pagination.Limit)
+	}
+	if pagination.Offset > 0 {
+		findOptions.SetSkip(pagination.Offset)
+	}
+
+	var total int64
+	entries := []*Domain.UserDirectoryEntry{}
+	err := ur.retrier.Read(ctx, "users.get_directory", func(ctx context.Context) error {
+		var err error
+		total, err = ur.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return err
+		}
+
+		cursor, err := ur.collection.Find(ctx, filter, findOptions)
+		if err != nil {
+			return err
+		}
+		defer cursor.Close(ctx)
+
+		return cursor.All(ctx, &entries)
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
+
+// Stream calls fn for every user matching the filter in creation order, decoding one document at
+// a time. Password hashes are never read. It stops at the first error fn returns, and only opening
+// the cursor is retried, since users already passed to fn cannot be taken back.
+func (ur *UserRepository) Stream(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	// Exports can be large, so they get more time than a single query
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	findOptions := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: 1}, {Key: "_id", Value: 1}}).
+		SetProjection(bson.M{"password": 0})
+
+	var cursor *mongo.Cursor
+	err := ur.retrier.Read(ctx, "users.stream", func(ctx context.Context) error {
+		var err error
+		cursor, err = ur.collection.Find(ctx, buildUserQuery(filter), findOptions)
+		return err
+	})
+	if err != nil {
+		return err
 	}
 	defer cursor.Close(ctx)
 
-	var users []*Domain.User
-	if err = cursor.All(ctx, &users); err != nil {
-		return nil, err
+	for cursor.Next(ctx) {
+		var user Domain.User
+		if err := cursor.Decode(&user); err != nil {
+			return err
+		}
+
+		if err := fn(&user); err != nil {
+			return err
+		}
 	}
 
-	return users, nil
+	return cursor.Err()
+}
+
+// buildUserQuery translates a UserFilter into a MongoDB query. The search text is escaped,
+// so it always matches literally and cannot inject regular expression syntax.
+func buildUserQuery(filter Domain.UserFilter) bson.M {
+	query := bson.M{}
+	if filter.Query != "" {
+		pattern := bson.M{"$regex": regexp.QuoteMeta(filter.Query), "$options": "i"}
+		query["$or"] = bson.A{
+			bson.M{"username": pattern},
+			bson.M{"email": pattern},
+		}
+	}
+	if filter.Role != "" {
+		query["role"] = filter.Role
+	}
+	return query
 }
 
 // GetByID retrieves a user by ID from MongoDB
-func (ur *UserRepository) GetByID(id string) (*Domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) GetByID(ctx context.Context, id string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, errors.New("invalid user ID format")
+		return nil, Domain.ErrInvalidUserID
 	}
 
 	var user Domain.User
-	err = ur.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+	err = ur.retrier.Read(ctx, "users.get_by_id", func(ctx context.Context) error {
+		return ur.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&user)
+	})
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+			return nil, Domain.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -77,16 +228,30 @@ func (ur *UserRepository) GetByID(id string) (*Domain.User, error) {
 	return &user, nil
 }
 
-// GetByUsername retrieves a user by username from MongoDB
-func (ur *UserRepository) GetByUsername(username string) (*Domain.User, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// GetByUsername retrieves a user by username from MongoDB, ignoring case and surrounding whitespace.
+// Records created before usernames were normalized have no username_lower; they are found by a
+// case-insensitive match on username instead and backfilled, so the next lookup uses the index.
+func (ur *UserRepository) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	usernameLower := Domain.NormalizeUsername(username)
+
 	var user Domain.User
-	err := ur.collection.FindOne(ctx, bson.M{"username": username}).Decode(&user)
+	err := ur.retrier.Read(ctx, "users.get_by_username", func(ctx context.Context) error {
+		return ur.collection.FindOne(ctx, bson.M{"username_lower": usernameLower}).Decode(&user)
+	})
+	if err == mongo.ErrNoDocuments {
+		err = ur.retrier.Read(ctx, "users.get_by_legacy_username", func(ctx context.Context) error {
+			return ur.collection.FindOne(ctx, legacyUsernameFilter(usernameLower)).Decode(&user)
+		})
+		if err == nil {
+			ur.backfillUsernameLower(ctx, &user, usernameLower)
+		}
+	}
 	if err != nil {
 		if err == mongo.ErrNoDocuments {
-			return nil, errors.New("user not found")
+			return nil, Domain.ErrUserNotFound
 		}
 		return nil, err
 	}
@@ -94,83 +259,464 @@ func (ur *UserRepository) GetByUsername(username string) (*Domain.User, error) {
 	return &user, nil
 }
 
+// GetByEmail retrieves a user by email address from MongoDB
+func (ur *UserRepository) GetByEmail(ctx context.Context, email string) (*Domain.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var user Domain.User
+	err := ur.retrier.Read(ctx, "users.get_by_email", func(ctx context.Context) error {
+		return ur.collection.FindOne(ctx, bson.M{"email": Domain.NormalizeEmail(email)}).Decode(&user)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrUserNotFound
+		}
+		return nil, err
+	}
+
+	return &user, nil
+}
+
+// backfillUsernameLower stores the canonical username of a legacy record. A failure leaves the
+// record on the fallback path and does not fail the lookup; a duplicate key means another
+// record already owns the canonical name.
+func (ur *UserRepository) backfillUsernameLower(ctx context.Context, user *Domain.User, usernameLower string) {
+	_, err := ur.collection.UpdateOne(ctx,
+		bson.M{"_id": user.ID, "username_lower": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"username_lower": usernameLower}},
+	)
+	if err == nil {
+		user.UsernameLower = usernameLower
+	}
+}
+
+// legacyUsernameFilter matches a record without username_lower whose username equals the
+// canonical name in any case
+func legacyUsernameFilter(usernameLower string) bson.M {
+	return bson.M{
+		"username_lower": bson.M{"$exists": false},
+		"username":       bson.M{"$regex": "^" + regexp.QuoteMeta(usernameLower) + "$", "$options": "i"},
+	}
+}
+
 // Create creates a new user in MongoDB
-func (ur *UserRepository) Create(user *Domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) Create(ctx context.Context, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	user.ID = primitive.NewObjectID()
+	user.UsernameLower = Domain.NormalizeUsername(user.Username)
 	user.CreatedAt = time.Now()
 	user.UpdatedAt = time.Now()
 
 	_, err := ur.collection.InsertOne(ctx, user)
-	return err
+	return translateUserWriteError(err)
 }
 
 // Update updates an existing user in MongoDB
-func (ur *UserRepository) Update(id string, user *Domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) Update(ctx context.Context, id string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	objectID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return errors.New("invalid user ID format")
+		return Domain.ErrInvalidUserID
 	}
 
 	user.UpdatedAt = time.Now()
 
 	update := bson.M{
 		"$set": bson.M{
-			"username":   user.Username,
-			"password":   user.Password,
-			"role":       user.Role,
-			"updated_at": user.UpdatedAt,
+			"username":       user.Username,
+			"username_lower": Domain.NormalizeUsername(user.Username),
+			"password":       user.Password,
+			"role":           user.Role,
+			"updated_at":     user.UpdatedAt,
 		},
 	}
 
-	result, err := ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.update", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+		return err
+	})
+	if err != nil {
+		return translateUserWriteError(err)
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateByUsername updates an existing user by username in MongoDB, matching it like GetByUsername.
+// A legacy record has its username_lower backfilled by the same write.
+func (ur *UserRepository) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	user.UpdatedAt = time.Now()
+	usernameLower := Domain.NormalizeUsername(username)
+
+	set := bson.M{
+		"role":       user.Role,
+		"updated_at": user.UpdatedAt,
+	}
+
+	result, err := ur.collection.UpdateOne(ctx, bson.M{"username_lower": usernameLower}, bson.M{"$set": set})
 	if err != nil {
 		return err
 	}
+	if result.MatchedCount == 0 {
+		set["username_lower"] = usernameLower
+		result, err = ur.collection.UpdateOne(ctx, legacyUsernameFilter(usernameLower), bson.M{"$set": set})
+		if err != nil {
+			return translateUserWriteError(err)
+		}
+	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return Domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
-// UpdateByUsername updates an existing user by username in MongoDB
-func (ur *UserRepository) UpdateByUsername(username string, user *Domain.User) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// UpdateProfile stores a user's username and email address with its verification status
+func (ur *UserRepository) UpdateProfile(ctx context.Context, id string, user *Domain.User) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
 	user.UpdatedAt = time.Now()
 
+	set := bson.M{
+		"username":       user.Username,
+		"username_lower": Domain.NormalizeUsername(user.Username),
+		"email_verified": user.EmailVerified,
+		"updated_at":     user.UpdatedAt,
+	}
+	update := bson.M{"$set": set}
+	if user.Email == "" {
+		// An empty address would take the single empty slot of the unique email index
+		update["$unset"] = bson.M{"email": ""}
+	} else {
+		set["email"] = user.Email
+	}
+
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.update_profile", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+		return err
+	})
+	if err != nil {
+		return translateUserWriteError(err)
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// MarkEmailVerified marks a user's email address as verified. It only matches while the user
+// still has that address, so a token for a replaced address cannot verify the new one.
+func (ur *UserRepository) MarkEmailVerified(ctx context.Context, id string, email string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.mark_email_verified", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx,
+			bson.M{"_id": objectID, "email": email},
+			bson.M{"$set": bson.M{"email_verified": true, "updated_at": time.Now()}},
+		)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// UpdateLastLogin records when the user last logged in. Only last_login_at is written, so a
+// login never overwrites a concurrent change to the rest of the record.
+func (ur *UserRepository) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.update_last_login", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"last_login_at": at}})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// SetActive activates or deactivates a user
+func (ur *UserRepository) SetActive(ctx context.Context, id string, active bool) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.set_active", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, bson.M{"$set": bson.M{"is_active": active, "updated_at": time.Now()}})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return Domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// IsActive reports whether a user may use the tokens issued to them. Only the flag is read,
+// and users stored before the flag existed are active.
+func (ur *UserRepository) IsActive(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, Domain.ErrInvalidUserID
+	}
+
+	var user Domain.User
+	err = ur.retrier.Read(ctx, "users.is_active", func(ctx context.Context) error {
+		return ur.collection.FindOne(ctx, bson.M{"_id": objectID}, options.FindOne().SetProjection(bson.M{"is_active": 1})).Decode(&user)
+	})
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, Domain.ErrUserNotFound
+		}
+		return false, err
+	}
+
+	return user.IsActive, nil
+}
+
+// Anonymize scrubs a user's personal data but keeps the record, so tasks, comments and audit
+// entries that reference the user stay valid. The username becomes a placeholder, the email
+// address is removed and the password is cleared so the account can no longer log in.
+func (ur *UserRepository) Anonymize(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	now := time.Now()
+	username := Domain.AnonymizedUsername(id)
 	update := bson.M{
 		"$set": bson.M{
-			"role":       user.Role,
-			"updated_at": user.UpdatedAt,
+			"username":       username,
+			"username_lower": Domain.NormalizeUsername(username),
+			"password":       "",
+			"role":           Domain.RoleUser,
+			"email_verified": false,
+			"anonymized_at":  now,
+			"updated_at":     now,
 		},
+		"$unset": bson.M{"email": ""},
 	}
 
-	result, err := ur.collection.UpdateOne(ctx, bson.M{"username": username}, update)
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.anonymize", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+		return err
+	})
 	if err != nil {
 		return err
 	}
 
 	if result.MatchedCount == 0 {
-		return errors.New("user not found")
+		return Domain.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a user from MongoDB
+func (ur *UserRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidUserID
+	}
+
+	result, err := ur.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return Domain.ErrUserNotFound
 	}
 
 	return nil
 }
 
 // CountUsers returns the total number of users in the database
-func (ur *UserRepository) CountUsers() (int64, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+func (ur *UserRepository) CountUsers(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	count, err := ur.collection.CountDocuments(ctx, bson.M{})
+	var count int64
+	err := ur.retrier.Read(ctx, "users.count", func(ctx context.Context) error {
+		var err error
+		count, err = ur.collection.CountDocuments(ctx, bson.M{})
+		return err
+	})
+	return count, err
+}
+
+// CountByRole returns the number of users with the given role
+func (ur *UserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	err := ur.retrier.Read(ctx, "users.count_by_role", func(ctx context.Context) error {
+		var err error
+		count, err = ur.collection.CountDocuments(ctx, bson.M{"role": role})
+		return err
+	})
 	return count, err
-}
\ No newline at end of file
+}
+
+// ClaimFirstAdmin makes a user the admin unless another user has already claimed that as the first
+// user. Concurrent first registrations all see an empty database, so the unique first_admin index is
+// what lets exactly one of them win; the others get false and stay regular users.
+func (ur *UserRepository) ClaimFirstAdmin(ctx context.Context, id string) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return false, Domain.ErrInvalidUserID
+	}
+
+	update := bson.M{"$set": bson.M{"role": Domain.RoleAdmin, "first_admin": true, "updated_at": time.Now()}}
+
+	var result *mongo.UpdateResult
+	err = ur.retrier.Write(ctx, "users.claim_first_admin", func(ctx context.Context) error {
+		var err error
+		result, err = ur.collection.UpdateOne(ctx, bson.M{"_id": objectID}, update)
+		return err
+	})
+	if mongo.IsDuplicateKeyError(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if result.MatchedCount == 0 {
+		return false, Domain.ErrUserNotFound
+	}
+
+	return true, nil
+}
+
+// EnsureIndexes creates the unique username and email indexes. The pre-reads in RegisterUser cannot
+// stop two concurrent registrations, so these indexes are what keep usernames and emails unique.
+func (ur *UserRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := ur.collection.Indexes().CreateMany(ctx, userIndexes())
+	return err
+}
+
+// userIndexes lists the indexes maintained on the users collection
+func userIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "username", Value: 1}},
+			Options: options.Index().SetName("username_1").SetUnique(true),
+		},
+		{
+			// Partial, so records not yet migrated do not collide on a missing value
+			Keys: bson.D{{Key: "username_lower", Value: 1}},
+			Options: options.Index().SetName("username_lower_1").SetUnique(true).
+				SetPartialFilterExpression(bson.M{"username_lower": bson.M{"$exists": true}}),
+		},
+		{
+			// Partial, so any number of users can register without an email
+			Keys:    bson.D{{Key: "email", Value: 1}},
+			Options: options.Index().SetName("email_1").SetUnique(true).SetPartialFilterExpression(bson.M{"email": bson.M{"$exists": true}}),
+		},
+		{
+			// Partial and unique, so at most one user is ever made admin for registering first
+			Keys:    bson.D{{Key: "first_admin", Value: 1}},
+			Options: options.Index().SetName("first_admin_1").SetUnique(true).SetPartialFilterExpression(bson.M{"first_admin": true}),
+		},
+		{
+			// Serves the admin user list filtered by role in its default order
+			Keys:    bson.D{{Key: "role", Value: 1}, {Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("role_1_created_at_1"),
+		},
+	}
+}
+
+// translateUserWriteError maps a duplicate key error (code 11000) to the "email already exists" or
+// "username already exists" error, depending on the index named in the error
+func translateUserWriteError(err error) error {
+	if !mongo.IsDuplicateKeyError(err) {
+		return err
+	}
+	if strings.Contains(err.Error(), "email_1") {
+		return Domain.ErrEmailTaken
+	}
+	return Domain.ErrUsernameTaken
+}
@@ -1,13 +1,16 @@
 package Repositories
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 
 	"task_manager/Domain"
 )
@@ -17,12 +20,22 @@ type MockUserRepositoryImpl struct {
 	mock.Mock
 }
 
-func (m *MockUserRepositoryImpl) GetAll() ([]*Domain.User, error) {
-	args := m.Called()
-	return args.Get(0).([]*Domain.User), args.Error(1)
+func (m *MockUserRepositoryImpl) GetAll(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).([]*Domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepositoryImpl) Stream(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
 }
 
-func (m *MockUserRepositoryImpl) GetByID(id string) (*Domain.User, error) {
+func (m *MockUserRepositoryImpl) GetDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	args := m.Called(query, pagination)
+	return args.Get(0).([]*Domain.UserDirectoryEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepositoryImpl) GetByID(ctx context.Context, id string) (*Domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,7 +43,7 @@ func (m *MockUserRepositoryImpl) GetByID(id string) (*Domain.User, error) {
 	return args.Get(0).(*Domain.User), args.Error(1)
 }
 
-func (m *MockUserRepositoryImpl) GetByUsername(username string) (*Domain.User, error) {
+func (m *MockUserRepositoryImpl) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
 	args := m.Called(username)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -38,26 +51,84 @@ func (m *MockUserRepositoryImpl) GetByUsername(username string) (*Domain.User, e
 	return args.Get(0).(*Domain.User), args.Error(1)
 }
 
-func (m *MockUserRepositoryImpl) Create(user *Domain.User) error {
+func (m *MockUserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*Domain.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserRepositoryImpl) Create(ctx context.Context, user *Domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepositoryImpl) Update(id string, user *Domain.User) error {
+func (m *MockUserRepositoryImpl) Update(ctx context.Context, id string, user *Domain.User) error {
 	args := m.Called(id, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepositoryImpl) UpdateByUsername(username string, user *Domain.User) error {
+func (m *MockUserRepositoryImpl) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
 	args := m.Called(username, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepositoryImpl) CountUsers() (int64, error) {
+func (m *MockUserRepositoryImpl) UpdateProfile(ctx context.Context, id string, user *Domain.User) error {
+	args := m.Called(id, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryImpl) MarkEmailVerified(ctx context.Context, id string, email string) error {
+	args := m.Called(id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryImpl) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryImpl) Anonymize(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryImpl) SetActive(ctx context.Context, id string, active bool) error {
+	args := m.Called(id, active)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryImpl) IsActive(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryImpl) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepositoryImpl) CountByRole(ctx context.Context, role string) (int64, error) {
+	args := m.Called(role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepositoryImpl) ClaimFirstAdmin(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepositoryImpl) CountUsers(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserRepositoryImpl) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 func TestUserRepository_GetAll(t *testing.T) {
 	t.Run("Success - return all users", func(t *testing.T) {
 		// Arrange
@@ -80,15 +151,16 @@ func TestUserRepository_GetAll(t *testing.T) {
 				UpdatedAt: time.Now(),
 			},
 		}
-		mockRepo.On("GetAll").Return(expectedUsers, nil)
+		mockRepo.On("GetAll", Domain.UserFilter{}, Domain.Pagination{}).Return(expectedUsers, int64(len(expectedUsers)), nil)
 
 		// Act
-		users, err := mockRepo.GetAll()
+		users, total, err := mockRepo.GetAll(context.Background(), Domain.UserFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUsers, users)
 		assert.Len(t, users, 2)
+		assert.Equal(t, int64(2), total)
 		assert.Equal(t, Domain.RoleUser, users[0].Role)
 		assert.Equal(t, Domain.RoleAdmin, users[1].Role)
 		mockRepo.AssertExpectations(t)
@@ -98,15 +170,16 @@ func TestUserRepository_GetAll(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockUserRepositoryImpl)
 		expectedUsers := []*Domain.User{}
-		mockRepo.On("GetAll").Return(expectedUsers, nil)
+		mockRepo.On("GetAll", Domain.UserFilter{}, Domain.Pagination{}).Return(expectedUsers, int64(len(expectedUsers)), nil)
 
 		// Act
-		users, err := mockRepo.GetAll()
+		users, total, err := mockRepo.GetAll(context.Background(), Domain.UserFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedUsers, users)
 		assert.Len(t, users, 0)
+		assert.Equal(t, int64(0), total)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -114,15 +187,16 @@ func TestUserRepository_GetAll(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockUserRepositoryImpl)
 		expectedError := errors.New("database connection failed")
-		mockRepo.On("GetAll").Return([]*Domain.User(nil), expectedError)
+		mockRepo.On("GetAll", Domain.UserFilter{}, Domain.Pagination{}).Return([]*Domain.User(nil), int64(0), expectedError)
 
 		// Act
-		users, err := mockRepo.GetAll()
+		users, total, err := mockRepo.GetAll(context.Background(), Domain.UserFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, users)
+		assert.Equal(t, int64(0), total)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -143,7 +217,7 @@ func TestUserRepository_GetByID(t *testing.T) {
 		mockRepo.On("GetByID", userID).Return(expectedUser, nil)
 
 		// Act
-		user, err := mockRepo.GetByID(userID)
+		user, err := mockRepo.GetByID(context.Background(), userID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -157,15 +231,15 @@ func TestUserRepository_GetByID(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockUserRepositoryImpl)
 		userID := primitive.NewObjectID().Hex()
-		expectedError := errors.New("user not found")
+		expectedError := Domain.ErrUserNotFound
 		mockRepo.On("GetByID", userID).Return(nil, expectedError)
 
 		// Act
-		user, err := mockRepo.GetByID(userID)
+		user, err := mockRepo.GetByID(context.Background(), userID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, user)
 		mockRepo.AssertExpectations(t)
 	})
@@ -174,15 +248,15 @@ func TestUserRepository_GetByID(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockUserRepositoryImpl)
 		invalidID := "invalid-id-format"
-		expectedError := errors.New("invalid user ID format")
+		expectedError := Domain.ErrInvalidUserID
 		mockRepo.On("GetByID", invalidID).Return(nil, expectedError)
 
 		// Act
-		user, err := mockRepo.GetByID(invalidID)
+		user, err := mockRepo.GetByID(context.Background(), invalidID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, user)
 		mockRepo.AssertExpectations(t)
 	})
@@ -204,7 +278,7 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 		mockRepo.On("GetByUsername", username).Return(expectedUser, nil)
 
 		// Act
-		user, err := mockRepo.GetByUsername(username)
+		user, err := mockRepo.GetByUsername(context.Background(), username)
 
 		// Assert
 		assert.NoError(t, err)
@@ -218,15 +292,15 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockUserRepositoryImpl)
 		username := "nonexistentuser"
-		expectedError := errors.New("user not found")
+		expectedError := Domain.ErrUserNotFound
 		mockRepo.On("GetByUsername", username).Return(nil, expectedError)
 
 		// Act
-		user, err := mockRepo.GetByUsername(username)
+		user, err := mockRepo.GetByUsername(context.Background(), username)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, user)
 		mockRepo.AssertExpectations(t)
 	})
@@ -239,11 +313,11 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 		mockRepo.On("GetByUsername", username).Return(nil, expectedError)
 
 		// Act
-		user, err := mockRepo.GetByUsername(username)
+		user, err := mockRepo.GetByUsername(context.Background(), username)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, user)
 		mockRepo.AssertExpectations(t)
 	})
@@ -260,7 +334,7 @@ func TestUserRepository_GetByUsername(t *testing.T) {
 		mockRepo.On("GetByUsername", username).Return(expectedUser, nil)
 
 		// Act
-		user, err := mockRepo.GetByUsername(username)
+		user, err := mockRepo.GetByUsername(context.Background(), username)
 
 		// Assert
 		assert.NoError(t, err)
@@ -282,7 +356,7 @@ func TestUserRepository_Create(t *testing.T) {
 		mockRepo.On("Create", user).Return(nil)
 
 		// Act
-		err := mockRepo.Create(user)
+		err := mockRepo.Create(context.Background(), user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -300,7 +374,7 @@ func TestUserRepository_Create(t *testing.T) {
 		mockRepo.On("Create", user).Return(nil)
 
 		// Act
-		err := mockRepo.Create(user)
+		err := mockRepo.Create(context.Background(), user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -319,11 +393,11 @@ func TestUserRepository_Create(t *testing.T) {
 		mockRepo.On("Create", user).Return(expectedError)
 
 		// Act
-		err := mockRepo.Create(user)
+		err := mockRepo.Create(context.Background(), user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -339,11 +413,11 @@ func TestUserRepository_Create(t *testing.T) {
 		mockRepo.On("Create", user).Return(expectedError)
 
 		// Act
-		err := mockRepo.Create(user)
+		err := mockRepo.Create(context.Background(), user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -362,7 +436,7 @@ func TestUserRepository_Update(t *testing.T) {
 		mockRepo.On("Update", userID, user).Return(nil)
 
 		// Act
-		err := mockRepo.Update(userID, user)
+		err := mockRepo.Update(context.Background(), userID, user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -377,15 +451,15 @@ func TestUserRepository_Update(t *testing.T) {
 			Username: "updateduser",
 			Role:     Domain.RoleUser,
 		}
-		expectedError := errors.New("user not found")
+		expectedError := Domain.ErrUserNotFound
 		mockRepo.On("Update", userID, user).Return(expectedError)
 
 		// Act
-		err := mockRepo.Update(userID, user)
+		err := mockRepo.Update(context.Background(), userID, user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -397,15 +471,15 @@ func TestUserRepository_Update(t *testing.T) {
 			Username: "updateduser",
 			Role:     Domain.RoleUser,
 		}
-		expectedError := errors.New("invalid user ID format")
+		expectedError := Domain.ErrInvalidUserID
 		mockRepo.On("Update", invalidID, user).Return(expectedError)
 
 		// Act
-		err := mockRepo.Update(invalidID, user)
+		err := mockRepo.Update(context.Background(), invalidID, user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -421,11 +495,11 @@ func TestUserRepository_Update(t *testing.T) {
 		mockRepo.On("Update", userID, user).Return(expectedError)
 
 		// Act
-		err := mockRepo.Update(userID, user)
+		err := mockRepo.Update(context.Background(), userID, user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 }
@@ -443,7 +517,7 @@ func TestUserRepository_UpdateByUsername(t *testing.T) {
 		mockRepo.On("UpdateByUsername", username, user).Return(nil)
 
 		// Act
-		err := mockRepo.UpdateByUsername(username, user)
+		err := mockRepo.UpdateByUsername(context.Background(), username, user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -458,15 +532,15 @@ func TestUserRepository_UpdateByUsername(t *testing.T) {
 			Username: username,
 			Role:     Domain.RoleAdmin,
 		}
-		expectedError := errors.New("user not found")
+		expectedError := Domain.ErrUserNotFound
 		mockRepo.On("UpdateByUsername", username, user).Return(expectedError)
 
 		// Act
-		err := mockRepo.UpdateByUsername(username, user)
+		err := mockRepo.UpdateByUsername(context.Background(), username, user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -482,11 +556,11 @@ func TestUserRepository_UpdateByUsername(t *testing.T) {
 		mockRepo.On("UpdateByUsername", username, user).Return(expectedError)
 
 		// Act
-		err := mockRepo.UpdateByUsername(username, user)
+		err := mockRepo.UpdateByUsername(context.Background(), username, user)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		mockRepo.AssertExpectations(t)
 	})
 
@@ -501,7 +575,7 @@ func TestUserRepository_UpdateByUsername(t *testing.T) {
 		mockRepo.On("UpdateByUsername", username, user).Return(nil)
 
 		// Act
-		err := mockRepo.UpdateByUsername(username, user)
+		err := mockRepo.UpdateByUsername(context.Background(), username, user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -517,7 +591,7 @@ func TestUserRepository_CountUsers(t *testing.T) {
 		mockRepo.On("CountUsers").Return(expectedCount, nil)
 
 		// Act
-		count, err := mockRepo.CountUsers()
+		count, err := mockRepo.CountUsers(context.Background())
 
 		// Assert
 		assert.NoError(t, err)
@@ -532,7 +606,7 @@ func TestUserRepository_CountUsers(t *testing.T) {
 		mockRepo.On("CountUsers").Return(expectedCount, nil)
 
 		// Act
-		count, err := mockRepo.CountUsers()
+		count, err := mockRepo.CountUsers(context.Background())
 
 		// Assert
 		assert.NoError(t, err)
@@ -547,7 +621,7 @@ func TestUserRepository_CountUsers(t *testing.T) {
 		mockRepo.On("CountUsers").Return(expectedCount, nil)
 
 		// Act
-		count, err := mockRepo.CountUsers()
+		count, err := mockRepo.CountUsers(context.Background())
 
 		// Assert
 		assert.NoError(t, err)
@@ -562,16 +636,127 @@ func TestUserRepository_CountUsers(t *testing.T) {
 		mockRepo.On("CountUsers").Return(int64(0), expectedError)
 
 		// Act
-		count, err := mockRepo.CountUsers()
+		count, err := mockRepo.CountUsers(context.Background())
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Equal(t, int64(0), count)
 		mockRepo.AssertExpectations(t)
 	})
 }
 
+func TestUserIndexes(t *testing.T) {
+	t.Run("Usernames are unique in any case", func(t *testing.T) {
+		// Act
+		indexes := userIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 5)
+		assert.Equal(t, bson.D{{Key: "username", Value: 1}}, indexes[0].Keys)
+		assert.True(t, *indexes[0].Options.Unique)
+		assert.Equal(t, bson.D{{Key: "username_lower", Value: 1}}, indexes[1].Keys)
+		assert.True(t, *indexes[1].Options.Unique)
+		assert.Equal(t, bson.M{"username_lower": bson.M{"$exists": true}}, indexes[1].Options.PartialFilterExpression)
+	})
+
+	t.Run("Emails are unique when present", func(t *testing.T) {
+		// Act
+		indexes := userIndexes()
+
+		// Assert
+		assert.Equal(t, bson.D{{Key: "email", Value: 1}}, indexes[2].Keys)
+		assert.True(t, *indexes[2].Options.Unique)
+		assert.Equal(t, bson.M{"email": bson.M{"$exists": true}}, indexes[2].Options.PartialFilterExpression)
+	})
+
+	t.Run("Only one user is ever the first admin", func(t *testing.T) {
+		// Act
+		indexes := userIndexes()
+
+		// Assert
+		assert.Equal(t, bson.D{{Key: "first_admin", Value: 1}}, indexes[3].Keys)
+		assert.True(t, *indexes[3].Options.Unique)
+		assert.Equal(t, bson.M{"first_admin": true}, indexes[3].Options.PartialFilterExpression)
+	})
+}
+
+func TestBuildUserQuery(t *testing.T) {
+	t.Run("Empty filter matches every user", func(t *testing.T) {
+		// Act & Assert
+		assert.Equal(t, bson.M{}, buildUserQuery(Domain.UserFilter{}))
+	})
+
+	t.Run("Search and role are combined", func(t *testing.T) {
+		// Act
+		query := buildUserQuery(Domain.UserFilter{Query: "Ali", Role: Domain.RoleAdmin})
+
+		// Assert
+		pattern := bson.M{"$regex": "Ali", "$options": "i"}
+		assert.Equal(t, bson.A{bson.M{"username": pattern}, bson.M{"email": pattern}}, query["$or"])
+		assert.Equal(t, Domain.RoleAdmin, query["role"])
+	})
+
+	t.Run("Escapes regex metacharacters", func(t *testing.T) {
+		// Act
+		query := buildUserQuery(Domain.UserFilter{Query: "a.b(c"})
+
+		// Assert
+		pattern := bson.M{"$regex": `a\.b\(c`, "$options": "i"}
+		assert.Equal(t, bson.A{bson.M{"username": pattern}, bson.M{"email": pattern}}, query["$or"])
+	})
+}
+
+func TestLegacyUsernameFilter(t *testing.T) {
+	t.Run("Matches unmigrated records in any case", func(t *testing.T) {
+		// Act
+		filter := legacyUsernameFilter("alice")
+
+		// Assert
+		assert.Equal(t, bson.M{"$exists": false}, filter["username_lower"])
+		assert.Equal(t, bson.M{"$regex": "^alice$", "$options": "i"}, filter["username"])
+	})
+
+	t.Run("Escapes regex metacharacters", func(t *testing.T) {
+		// Act
+		filter := legacyUsernameFilter("a.b+c")
+
+		// Assert
+		assert.Equal(t, bson.M{"$regex": `^a\.b\+c$`, "$options": "i"}, filter["username"])
+	})
+}
+
+func TestTranslateUserWriteError(t *testing.T) {
+	t.Run("Duplicate key becomes username already exists", func(t *testing.T) {
+		// Arrange
+		err := mongo.WriteException{
+			WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error collection: taskmanager.users index: username_1"}},
+		}
+
+		// Act & Assert
+		assert.EqualError(t, translateUserWriteError(err), "username already exists")
+	})
+
+	t.Run("Duplicate email becomes email already exists", func(t *testing.T) {
+		// Arrange
+		err := mongo.WriteException{
+			WriteErrors: []mongo.WriteError{{Code: 11000, Message: "E11000 duplicate key error collection: taskmanager.users index: email_1 dup key: { email: \"a@example.com\" }"}},
+		}
+
+		// Act & Assert
+		assert.EqualError(t, translateUserWriteError(err), "email already exists")
+	})
+
+	t.Run("Other errors pass through", func(t *testing.T) {
+		// Arrange
+		err := mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121, Message: "Document failed validation"}}}
+
+		// Act & Assert
+		assert.Equal(t, err, translateUserWriteError(err))
+		assert.Nil(t, translateUserWriteError(nil))
+	})
+}
+
 // Test interface compliance
 func TestUserRepositoryInterface(t *testing.T) {
 	mockRepo := new(MockUserRepositoryImpl)
@@ -592,7 +777,7 @@ func TestUserRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("Create", user).Return(nil)
 
 		// Act
-		err := mockRepo.Create(user)
+		err := mockRepo.Create(context.Background(), user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -610,7 +795,7 @@ func TestUserRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("GetByUsername", longUsername).Return(expectedUser, nil)
 
 		// Act
-		user, err := mockRepo.GetByUsername(longUsername)
+		user, err := mockRepo.GetByUsername(context.Background(), longUsername)
 
 		// Assert
 		assert.NoError(t, err)
@@ -631,7 +816,7 @@ func TestUserRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("Update", userID, user).Return(nil)
 
 		// Act
-		err := mockRepo.Update(userID, user)
+		err := mockRepo.Update(context.Background(), userID, user)
 
 		// Assert
 		assert.NoError(t, err)
@@ -646,7 +831,7 @@ func TestUserRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("GetByUsername", emptyUsername).Return(nil, expectedError)
 
 		// Act
-		user, err := mockRepo.GetByUsername(emptyUsername)
+		user, err := mockRepo.GetByUsername(context.Background(), emptyUsername)
 
 		// Assert
 		assert.Error(t, err)
@@ -661,11 +846,11 @@ func TestUserRepository_EdgeCases(t *testing.T) {
 		mockRepo.On("CountUsers").Return(expectedCount, nil)
 
 		// Act
-		count, err := mockRepo.CountUsers()
+		count, err := mockRepo.CountUsers(context.Background())
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedCount, count)
 		mockRepo.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
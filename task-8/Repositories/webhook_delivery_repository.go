@@ -0,0 +1,126 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// WebhookDeliveryRetention is how long delivery records are kept before MongoDB expires them
+const WebhookDeliveryRetention = 30 * 24 * time.Hour
+
+// WebhookDeliveryRepositoryInterface defines the contract for webhook delivery log data access
+type WebhookDeliveryRepositoryInterface interface {
+	Create(ctx context.Context, delivery *Domain.WebhookDelivery) error
+	GetByWebhookID(ctx context.Context, webhookID string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error)
+	DeleteByWebhookID(ctx context.Context, webhookID string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// WebhookDeliveryRepository implements WebhookDeliveryRepositoryInterface with MongoDB
+type WebhookDeliveryRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookDeliveryRepository creates a new instance of WebhookDeliveryRepository
+func NewWebhookDeliveryRepository(client *mongo.Client, dbName string) WebhookDeliveryRepositoryInterface {
+	collection := client.Database(dbName).Collection("webhook_deliveries")
+	return &WebhookDeliveryRepository{
+		collection: collection,
+	}
+}
+
+// Create records a delivery. CreatedAt is kept when set, since it marks the first attempt.
+func (dr *WebhookDeliveryRepository) Create(ctx context.Context, delivery *Domain.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	delivery.ID = primitive.NewObjectID()
+	if delivery.CreatedAt.IsZero() {
+		delivery.CreatedAt = time.Now()
+	}
+
+	_, err := dr.collection.InsertOne(ctx, delivery)
+	return err
+}
+
+// GetByWebhookID returns one page of a webhook's deliveries, newest first, with the total number of them
+func (dr *WebhookDeliveryRepository) GetByWebhookID(ctx context.Context, webhookID string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return nil, 0, Domain.ErrInvalidWebhookID
+	}
+	query := bson.M{"webhook_id": objectID}
+
+	total, err := dr.collection.CountDocuments(ctx, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}})
+	if pagination.Limit > 0 {
+		findOptions.SetLimit(pagination.Limit)
+	}
+	if pagination.Offset > 0 {
+		findOptions.SetSkip(pagination.Offset)
+	}
+
+	cursor, err := dr.collection.Find(ctx, query, findOptions)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []*Domain.WebhookDelivery{}
+	if err = cursor.All(ctx, &deliveries); err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+// DeleteByWebhookID removes every delivery record of a webhook
+func (dr *WebhookDeliveryRepository) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(webhookID)
+	if err != nil {
+		return Domain.ErrInvalidWebhookID
+	}
+
+	_, err = dr.collection.DeleteMany(ctx, bson.M{"webhook_id": objectID})
+	return err
+}
+
+// EnsureIndexes creates the per-webhook listing index and the TTL index that expires old deliveries
+func (dr *WebhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := dr.collection.Indexes().CreateMany(ctx, webhookDeliveryIndexes())
+	return err
+}
+
+// webhookDeliveryIndexes lists the indexes maintained on the webhook_deliveries collection
+func webhookDeliveryIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}},
+			Options: options.Index().SetName("webhook_id_1_created_at_-1"),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetName("created_at_ttl").SetExpireAfterSeconds(int32(WebhookDeliveryRetention / time.Second)),
+		},
+	}
+}
@@ -0,0 +1,165 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// WebhookRepositoryInterface defines the contract for webhook data access
+type WebhookRepositoryInterface interface {
+	Create(ctx context.Context, webhook *Domain.Webhook) error
+	GetByID(ctx context.Context, id string) (*Domain.Webhook, error)
+	GetAll(ctx context.Context) ([]*Domain.Webhook, error)
+	GetActiveByEvent(ctx context.Context, eventType string) ([]*Domain.Webhook, error)
+	Update(ctx context.Context, webhook *Domain.Webhook) error
+	Delete(ctx context.Context, id string) error
+	EnsureIndexes(ctx context.Context) error
+}
+
+// WebhookRepository implements WebhookRepositoryInterface with MongoDB
+type WebhookRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository
+func NewWebhookRepository(client *mongo.Client, dbName string) WebhookRepositoryInterface {
+	collection := client.Database(dbName).Collection("webhooks")
+	return &WebhookRepository{
+		collection: collection,
+	}
+}
+
+// Create stores a webhook
+func (wr *WebhookRepository) Create(ctx context.Context, webhook *Domain.Webhook) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhook.ID = primitive.NewObjectID()
+	webhook.CreatedAt = time.Now()
+	webhook.UpdatedAt = webhook.CreatedAt
+
+	_, err := wr.collection.InsertOne(ctx, webhook)
+	return err
+}
+
+// GetByID returns a webhook by its ID
+func (wr *WebhookRepository) GetByID(ctx context.Context, id string) (*Domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, Domain.ErrInvalidWebhookID
+	}
+
+	var webhook Domain.Webhook
+	err = wr.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&webhook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, Domain.ErrWebhookNotFound
+		}
+		return nil, err
+	}
+
+	return &webhook, nil
+}
+
+// GetAll returns every webhook, newest first
+func (wr *WebhookRepository) GetAll(ctx context.Context) ([]*Domain.Webhook, error) {
+	return wr.find(ctx, bson.M{})
+}
+
+// GetActiveByEvent returns the active webhooks subscribed to an event type
+func (wr *WebhookRepository) GetActiveByEvent(ctx context.Context, eventType string) ([]*Domain.Webhook, error) {
+	return wr.find(ctx, bson.M{"active": true, "events": eventType})
+}
+
+// find returns the webhooks matching a query, newest first
+func (wr *WebhookRepository) find(ctx context.Context, query bson.M) ([]*Domain.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := wr.collection.Find(ctx, query, options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	webhooks := []*Domain.Webhook{}
+	if err = cursor.All(ctx, &webhooks); err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+// Update replaces the URL, secret, events and active flag of a webhook
+func (wr *WebhookRepository) Update(ctx context.Context, webhook *Domain.Webhook) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhook.UpdatedAt = time.Now()
+
+	result, err := wr.collection.UpdateOne(ctx, bson.M{"_id": webhook.ID}, bson.M{"$set": bson.M{
+		"url":        webhook.URL,
+		"secret":     webhook.Secret,
+		"events":     webhook.Events,
+		"active":     webhook.Active,
+		"updated_at": webhook.UpdatedAt,
+	}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return Domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// Delete removes a webhook
+func (wr *WebhookRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return Domain.ErrInvalidWebhookID
+	}
+
+	result, err := wr.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return Domain.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+// EnsureIndexes creates the index events are matched to their subscribers with
+func (wr *WebhookRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := wr.collection.Indexes().CreateMany(ctx, webhookIndexes())
+	return err
+}
+
+// webhookIndexes lists the indexes maintained on the webhooks collection
+func webhookIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}},
+			Options: options.Index().SetName("events_1_active_1"),
+		},
+	}
+}
@@ -0,0 +1,38 @@
+package Repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestWebhookIndexes(t *testing.T) {
+	t.Run("Subscribers are found by event", func(t *testing.T) {
+		// Act
+		indexes := webhookIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 1)
+		assert.Equal(t, bson.D{{Key: "events", Value: 1}, {Key: "active", Value: 1}}, indexes[0].Keys)
+	})
+}
+
+func TestWebhookDeliveryIndexes(t *testing.T) {
+	t.Run("Listing index and TTL index", func(t *testing.T) {
+		// Act
+		indexes := webhookDeliveryIndexes()
+
+		// Assert
+		assert.Len(t, indexes, 2)
+		assert.Equal(t, bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}, indexes[0].Keys)
+		assert.Equal(t, bson.D{{Key: "created_at", Value: 1}}, indexes[1].Keys)
+		assert.Equal(t, int32(WebhookDeliveryRetention/time.Second), *indexes[1].Options.ExpireAfterSeconds)
+	})
+}
+
+func TestWebhookRepositoryInterface(t *testing.T) {
+	var _ WebhookRepositoryInterface = &WebhookRepository{}
+	var _ WebhookDeliveryRepositoryInterface = &WebhookDeliveryRepository{}
+}
@@ -0,0 +1,258 @@
+package Repositories
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+
+	"task_manager/Domain"
+)
+
+// WorkLogRepositoryInterface defines the contract for work log data access.
+// Totals are computed by the database, so no work log documents are read to add them up.
+type WorkLogRepositoryInterface interface {
+	Create(ctx context.Context, workLog *Domain.WorkLog) error
+	GetByTaskID(ctx context.Context, taskID string) ([]*Domain.WorkLog, error)
+	TotalMinutesByTaskID(ctx context.Context, taskID string) (int, error)
+	TotalMinutesByUserOnDate(ctx context.Context, userID primitive.ObjectID, date time.Time) (int, error)
+	Summarize(ctx context.Context, filter Domain.WorkLogFilter) ([]*Domain.WorkLogSummary, error)
+	DeleteByTaskID(ctx context.Context, taskID string) error
+	DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error
+	RestoreByTaskID(ctx context.Context, taskID string) error
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
+	PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error)
+	EnsureIndexes(ctx context.Context) error
+}
+
+// WorkLogRepository implements WorkLogRepositoryInterface with MongoDB
+type WorkLogRepository struct {
+	collection *mongo.Collection
+}
+
+// NewWorkLogRepository creates a new instance of WorkLogRepository
+func NewWorkLogRepository(client *mongo.Client, dbName string) WorkLogRepositoryInterface {
+	collection := client.Database(dbName).Collection("work_logs")
+	return &WorkLogRepository{
+		collection: collection,
+	}
+}
+
+// Create stores a new work log
+func (wr *WorkLogRepository) Create(ctx context.Context, workLog *Domain.WorkLog) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	workLog.ID = primitive.NewObjectID()
+	workLog.CreatedAt = time.Now()
+
+	_, err := wr.collection.InsertOne(ctx, workLog)
+	return err
+}
+
+// GetByTaskID returns the work logged on a task, earliest day first
+func (wr *WorkLogRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.WorkLog, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return nil, Domain.ErrInvalidTaskID
+	}
+
+	findOptions := options.Find().SetSort(bson.D{{Key: "date", Value: 1}, {Key: "_id", Value: 1}})
+	cursor, err := wr.collection.Find(ctx, bson.M{"task_id": objectID, "deleted_at": nil}, findOptions)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	workLogs := []*Domain.WorkLog{}
+	if err = cursor.All(ctx, &workLogs); err != nil {
+		return nil, err
+	}
+
+	return workLogs, nil
+}
+
+// TotalMinutesByTaskID adds up the minutes logged on a task
+func (wr *WorkLogRepository) TotalMinutesByTaskID(ctx context.Context, taskID string) (int, error) {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return 0, Domain.ErrInvalidTaskID
+	}
+
+	return wr.totalMinutes(ctx, bson.M{"task_id": objectID, "deleted_at": nil})
+}
+
+// TotalMinutesByUserOnDate adds up the minutes a user logged on one day, on every task
+func (wr *WorkLogRepository) TotalMinutesByUserOnDate(ctx context.Context, userID primitive.ObjectID, date time.Time) (int, error) {
+	return wr.totalMinutes(ctx, bson.M{"user_id": userID, "date": date, "deleted_at": nil})
+}
+
+// totalMinutes adds up the minutes of the work logs matching query
+func (wr *WorkLogRepository) totalMinutes(ctx context.Context, query bson.M) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := wr.collection.Aggregate(ctx, buildTotalMinutesPipeline(query))
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	var totals []struct {
+		Total int `bson:"total"`
+	}
+	if err = cursor.All(ctx, &totals); err != nil {
+		return 0, err
+	}
+	if len(totals) == 0 {
+		return 0, nil
+	}
+
+	return totals[0].Total, nil
+}
+
+// Summarize totals the minutes and entries each user logged, most minutes first
+func (wr *WorkLogRepository) Summarize(ctx context.Context, filter Domain.WorkLogFilter) ([]*Domain.WorkLogSummary, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	cursor, err := wr.collection.Aggregate(ctx, buildWorkLogSummaryPipeline(filter))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	summaries := []*Domain.WorkLogSummary{}
+	if err = cursor.All(ctx, &summaries); err != nil {
+		return nil, err
+	}
+
+	return summaries, nil
+}
+
+// buildTotalMinutesPipeline sums the minutes of the work logs matching query into a single
+// document, or none when nothing matches
+func buildTotalMinutesPipeline(query bson.M) mongo.Pipeline {
+	return mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$minutes"}}}},
+	}
+}
+
+// buildWorkLogSummaryPipeline groups the active work logs matching filter by user. Ties are
+// broken by user ID so the order is stable.
+func buildWorkLogSummaryPipeline(filter Domain.WorkLogFilter) mongo.Pipeline {
+	match := bson.M{"deleted_at": nil}
+	if !filter.UserID.IsZero() {
+		match["user_id"] = filter.UserID
+	}
+
+	dateRange := bson.M{}
+	if !filter.From.IsZero() {
+		dateRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		dateRange["$lte"] = filter.To
+	}
+	if len(dateRange) > 0 {
+		match["date"] = dateRange
+	}
+
+	return mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$user_id",
+			"total_minutes": bson.M{"$sum": "$minutes"},
+			"entries":       bson.M{"$sum": 1},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "total_minutes", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+}
+
+// DeleteByTaskID soft deletes every work log on a task by stamping deleted_at
+func (wr *WorkLogRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	return wr.DeleteByTaskIDs(ctx, []primitive.ObjectID{objectID})
+}
+
+// DeleteByTaskIDs soft deletes every work log on the given tasks in a single UpdateMany
+func (wr *WorkLogRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := wr.collection.UpdateMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}, "deleted_at": nil}, bson.M{"$set": bson.M{"deleted_at": time.Now()}})
+	return err
+}
+
+// RestoreByTaskID clears deleted_at on every work log on a task
+func (wr *WorkLogRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	objectID, err := primitive.ObjectIDFromHex(taskID)
+	if err != nil {
+		return Domain.ErrInvalidTaskID
+	}
+
+	_, err = wr.collection.UpdateMany(ctx, bson.M{"task_id": objectID, "deleted_at": bson.M{"$ne": nil}}, bson.M{"$unset": bson.M{"deleted_at": ""}})
+	return err
+}
+
+// Purge permanently removes work logs that were soft deleted before the given time
+func (wr *WorkLogRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := wr.collection.DeleteMany(ctx, bson.M{"deleted_at": bson.M{"$ne": nil, "$lte": deletedBefore}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// PurgeByTaskIDs permanently removes every work log on the given tasks, in the trash or not
+func (wr *WorkLogRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	result, err := wr.collection.DeleteMany(ctx, bson.M{"task_id": bson.M{"$in": taskIDs}})
+	if err != nil {
+		return 0, err
+	}
+
+	return result.DeletedCount, nil
+}
+
+// EnsureIndexes creates the indexes behind the task listing, the daily limit and the summary
+func (wr *WorkLogRepository) EnsureIndexes(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	_, err := wr.collection.Indexes().CreateMany(ctx, workLogIndexes())
+	return err
+}
+
+// workLogIndexes lists the indexes maintained on the work_logs collection
+func workLogIndexes() []mongo.IndexModel {
+	return []mongo.IndexModel{
+		{
+			Keys:    bson.D{{Key: "task_id", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetName("task_id_1_date_1"),
+		},
+		{
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: 1}},
+			Options: options.Index().SetName("user_id_1_date_1"),
+		},
+	}
+}
@@ -0,0 +1,66 @@
+package Repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+
+	"task_manager/Domain"
+)
+
+func TestWorkLogIndexes(t *testing.T) {
+	// Act
+	indexes := workLogIndexes()
+
+	// Assert
+	assert.Len(t, indexes, 2)
+	assert.Equal(t, bson.D{{Key: "task_id", Value: 1}, {Key: "date", Value: 1}}, indexes[0].Keys)
+	assert.Equal(t, bson.D{{Key: "user_id", Value: 1}, {Key: "date", Value: 1}}, indexes[1].Keys)
+}
+
+func TestBuildTotalMinutesPipeline(t *testing.T) {
+	// Arrange
+	query := bson.M{"task_id": primitive.NewObjectID(), "deleted_at": nil}
+
+	// Act
+	pipeline := buildTotalMinutesPipeline(query)
+
+	// Assert
+	assert.Equal(t, mongo.Pipeline{
+		{{Key: "$match", Value: query}},
+		{{Key: "$group", Value: bson.M{"_id": nil, "total": bson.M{"$sum": "$minutes"}}}},
+	}, pipeline)
+}
+
+func TestBuildWorkLogSummaryPipeline(t *testing.T) {
+	t.Run("An empty filter matches every active work log", func(t *testing.T) {
+		// Act
+		pipeline := buildWorkLogSummaryPipeline(Domain.WorkLogFilter{})
+
+		// Assert
+		assert.Len(t, pipeline, 3)
+		assert.Equal(t, bson.D{{Key: "$match", Value: bson.M{"deleted_at": nil}}}, pipeline[0])
+		assert.Equal(t, bson.D{{Key: "$sort", Value: bson.D{{Key: "total_minutes", Value: -1}, {Key: "_id", Value: 1}}}}, pipeline[2])
+	})
+
+	t.Run("User and inclusive date range are matched", func(t *testing.T) {
+		// Arrange
+		userID := primitive.NewObjectID()
+		from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		to := time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC)
+
+		// Act
+		pipeline := buildWorkLogSummaryPipeline(Domain.WorkLogFilter{UserID: userID, From: from, To: to})
+
+		// Assert
+		assert.Equal(t, bson.D{{Key: "$match", Value: bson.M{
+			"deleted_at": nil,
+			"user_id":    userID,
+			"date":       bson.M{"$gte": from, "$lte": to},
+		}}}, pipeline[0])
+	})
+}
@@ -0,0 +1,136 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// MaxAPIKeyNameLength bounds API key names, counted in characters after trimming
+const MaxAPIKeyNameLength = 100
+
+// apiKeyLastUsedInterval is how stale last_used_at may get before a request through the key
+// updates it, so a busy key does not cost a write on every request
+const apiKeyLastUsedInterval = time.Minute
+
+// APIKeyUsecaseInterface defines the contract for API key management and validation
+type APIKeyUsecaseInterface interface {
+	CreateAPIKey(ctx context.Context, caller Domain.Caller, req Domain.APIKeyRequest) (*Domain.CreatedAPIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*Domain.APIKey, error)
+	RevokeAPIKey(ctx context.Context, caller Domain.Caller, id string) (*Domain.APIKey, error)
+	ValidateAPIKey(ctx context.Context, key string) (*Domain.APIKey, error)
+}
+
+// APIKeyUsecase implements API key business logic
+type APIKeyUsecase struct {
+	apiKeyRepo Repositories.APIKeyRepositoryInterface
+	auditRepo  Repositories.AuditRepositoryInterface
+	logger     *slog.Logger
+	now        func() time.Time
+}
+
+// NewAPIKeyUsecase creates a new instance of APIKeyUsecase
+func NewAPIKeyUsecase(apiKeyRepo Repositories.APIKeyRepositoryInterface, auditRepo Repositories.AuditRepositoryInterface, logger *slog.Logger) APIKeyUsecaseInterface {
+	return &APIKeyUsecase{
+		apiKeyRepo: apiKeyRepo,
+		auditRepo:  auditRepo,
+		logger:     logger,
+		now:        time.Now,
+	}
+}
+
+// CreateAPIKey issues a new API key on behalf of the calling admin. The key defaults to the user
+// role. Only its hash is stored, so the returned plaintext cannot be retrieved again.
+func (ku *APIKeyUsecase) CreateAPIKey(ctx context.Context, caller Domain.Caller, req Domain.APIKeyRequest) (*Domain.CreatedAPIKey, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > MaxAPIKeyNameLength {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "invalid name, must be between 1 and %d characters", MaxAPIKeyNameLength)
+	}
+
+	role := req.Role
+	if role == "" {
+		role = Domain.RoleUser
+	}
+	if !Domain.IsValidRole(role) {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "invalid role, must be one of: user, manager, admin")
+	}
+
+	createdBy, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	plaintext, err := Infrastructure.GenerateAPIKey()
+	if err != nil {
+		return nil, errors.New("failed to generate API key")
+	}
+
+	key := &Domain.APIKey{
+		Name:      name,
+		KeyHash:   Infrastructure.HashOpaqueToken(plaintext),
+		Role:      role,
+		CreatedBy: createdBy,
+	}
+	if err := ku.apiKeyRepo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, ku.logger, ku.auditRepo, caller, Domain.AuditActionCreate, Domain.AuditEntityAPIKey, key.ID.Hex(), fmt.Sprintf("name: %s, role: %s", key.Name, key.Role))
+
+	return &Domain.CreatedAPIKey{APIKey: key, Key: plaintext}, nil
+}
+
+// ListAPIKeys returns every API key, revoked ones included, without their hashes
+func (ku *APIKeyUsecase) ListAPIKeys(ctx context.Context) ([]*Domain.APIKey, error) {
+	return ku.apiKeyRepo.GetAll(ctx)
+}
+
+// RevokeAPIKey revokes an API key on behalf of the calling admin. Requests with the key are
+// rejected from then on.
+func (ku *APIKeyUsecase) RevokeAPIKey(ctx context.Context, caller Domain.Caller, id string) (*Domain.APIKey, error) {
+	key, err := ku.apiKeyRepo.Revoke(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, ku.logger, ku.auditRepo, caller, Domain.AuditActionRevoke, Domain.AuditEntityAPIKey, key.ID.Hex(), "name: "+key.Name)
+
+	return key, nil
+}
+
+// ValidateAPIKey implements Infrastructure.APIKeyValidator. A failure to record the key's use
+// is logged rather than failing the request.
+func (ku *APIKeyUsecase) ValidateAPIKey(ctx context.Context, key string) (*Domain.APIKey, error) {
+	apiKey, err := ku.apiKeyRepo.GetByHash(ctx, Infrastructure.HashOpaqueToken(key))
+	if err != nil {
+		if errors.Is(err, Domain.ErrAPIKeyNotFound) {
+			return nil, Domain.ErrInvalidAPIKey
+		}
+		return nil, err
+	}
+
+	if apiKey.Revoked {
+		return nil, Domain.ErrAPIKeyRevoked
+	}
+
+	now := ku.now()
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedInterval {
+		if err := ku.apiKeyRepo.UpdateLastUsed(ctx, apiKey.ID, now); err != nil {
+			ku.logger.WarnContext(ctx, "failed to record API key use", "api_key_id", apiKey.ID.Hex(), "error", err)
+		} else {
+			apiKey.LastUsedAt = &now
+		}
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,226 @@
+package Usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// MockAPIKeyRepository is a mock implementation of APIKeyRepositoryInterface
+type MockAPIKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockAPIKeyRepository) Create(ctx context.Context, key *Domain.APIKey) error {
+	args := m.Called(key)
+	if args.Error(0) == nil {
+		key.ID = primitive.NewObjectID()
+	}
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*Domain.APIKey, error) {
+	args := m.Called(keyHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) GetAll(ctx context.Context) ([]*Domain.APIKey, error) {
+	args := m.Called()
+	return args.Get(0).([]*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) Revoke(ctx context.Context, id string) (*Domain.APIKey, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.APIKey), args.Error(1)
+}
+
+func (m *MockAPIKeyRepository) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockAPIKeyRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func TestAPIKeyUsecase_CreateAPIKey(t *testing.T) {
+	t.Run("Success - only the hash is stored and the key is returned once", func(t *testing.T) {
+		// Arrange
+		mockAPIKeyRepo := new(MockAPIKeyRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		apiKeyUsecase := NewAPIKeyUsecase(mockAPIKeyRepo, mockAuditRepo, Infrastructure.NewNopLogger())
+
+		var stored *Domain.APIKey
+		mockAPIKeyRepo.On("Create", mock.AnythingOfType("*Domain.APIKey")).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*Domain.APIKey)
+		}).Return(nil)
+		mockAuditRepo.On("Create", mock.MatchedBy(func(entry *Domain.AuditEntry) bool {
+			return entry.Action == Domain.AuditActionCreate && entry.Entity == Domain.AuditEntityAPIKey && entry.Diff == "name: nightly-report, role: user"
+		})).Return(nil)
+
+		// Act
+		created, err := apiKeyUsecase.CreateAPIKey(context.Background(), adminCaller, Domain.APIKeyRequest{Name: "  nightly-report  "})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(created.Key, "tm_"))
+		assert.Equal(t, "nightly-report", created.Name)
+		assert.Equal(t, Domain.RoleUser, created.Role)
+		assert.Equal(t, adminCaller.UserID, created.CreatedBy.Hex())
+		assert.Equal(t, Infrastructure.HashOpaqueToken(created.Key), stored.KeyHash)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	invalidRequests := []struct {
+		name          string
+		req           Domain.APIKeyRequest
+		expectedError string
+	}{
+		{"blank name", Domain.APIKeyRequest{Name: "   "}, "invalid name, must be between 1 and 100 characters"},
+		{"name too long", Domain.APIKeyRequest{Name: strings.Repeat("k", MaxAPIKeyNameLength+1)}, "invalid name, must be between 1 and 100 characters"},
+		{"unknown role", Domain.APIKeyRequest{Name: "cron", Role: "owner"}, "invalid role, must be one of: user, manager, admin"},
+	}
+	for _, tc := range invalidRequests {
+		t.Run("Error - "+tc.name, func(t *testing.T) {
+			// Arrange
+			mockAPIKeyRepo := new(MockAPIKeyRepository)
+			apiKeyUsecase := NewAPIKeyUsecase(mockAPIKeyRepo, newMockAuditRepository(), Infrastructure.NewNopLogger())
+
+			// Act
+			created, err := apiKeyUsecase.CreateAPIKey(context.Background(), adminCaller, tc.req)
+
+			// Assert
+			assert.EqualError(t, err, tc.expectedError)
+			assert.Nil(t, created)
+			mockAPIKeyRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestAPIKeyUsecase_RevokeAPIKey(t *testing.T) {
+	t.Run("Success - revokes and audits", func(t *testing.T) {
+		// Arrange
+		mockAPIKeyRepo := new(MockAPIKeyRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		apiKeyUsecase := NewAPIKeyUsecase(mockAPIKeyRepo, mockAuditRepo, Infrastructure.NewNopLogger())
+
+		key := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "cron", Revoked: true}
+		mockAPIKeyRepo.On("Revoke", key.ID.Hex()).Return(key, nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionRevoke, Domain.AuditEntityAPIKey, key.ID.Hex(), "name: cron")).Return(nil)
+
+		// Act
+		revoked, err := apiKeyUsecase.RevokeAPIKey(context.Background(), adminCaller, key.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, key, revoked)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - key not found", func(t *testing.T) {
+		// Arrange
+		mockAPIKeyRepo := new(MockAPIKeyRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		apiKeyUsecase := NewAPIKeyUsecase(mockAPIKeyRepo, mockAuditRepo, Infrastructure.NewNopLogger())
+
+		id := primitive.NewObjectID().Hex()
+		mockAPIKeyRepo.On("Revoke", id).Return(nil, Domain.ErrAPIKeyNotFound)
+
+		// Act
+		revoked, err := apiKeyUsecase.RevokeAPIKey(context.Background(), adminCaller, id)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAPIKeyNotFound)
+		assert.Nil(t, revoked)
+		mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestAPIKeyUsecase_ValidateAPIKey(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	setup := func() (*APIKeyUsecase, *MockAPIKeyRepository) {
+		mockAPIKeyRepo := new(MockAPIKeyRepository)
+		apiKeyUsecase := NewAPIKeyUsecase(mockAPIKeyRepo, newMockAuditRepository(), Infrastructure.NewNopLogger()).(*APIKeyUsecase)
+		apiKeyUsecase.now = func() time.Time { return now }
+		return apiKeyUsecase, mockAPIKeyRepo
+	}
+
+	t.Run("Success - valid key records its use", func(t *testing.T) {
+		// Arrange
+		apiKeyUsecase, mockAPIKeyRepo := setup()
+		key := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "cron", Role: Domain.RoleUser}
+		mockAPIKeyRepo.On("GetByHash", Infrastructure.HashOpaqueToken("tm_secret")).Return(key, nil)
+		mockAPIKeyRepo.On("UpdateLastUsed", key.ID, now).Return(nil)
+
+		// Act
+		validated, err := apiKeyUsecase.ValidateAPIKey(context.Background(), "tm_secret")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &now, validated.LastUsedAt)
+		mockAPIKeyRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - recent use is not written again", func(t *testing.T) {
+		// Arrange
+		apiKeyUsecase, mockAPIKeyRepo := setup()
+		lastUsed := now.Add(-10 * time.Second)
+		key := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "cron", Role: Domain.RoleUser, LastUsedAt: &lastUsed}
+		mockAPIKeyRepo.On("GetByHash", Infrastructure.HashOpaqueToken("tm_secret")).Return(key, nil)
+
+		// Act
+		_, err := apiKeyUsecase.ValidateAPIKey(context.Background(), "tm_secret")
+
+		// Assert
+		assert.NoError(t, err)
+		mockAPIKeyRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - unknown key", func(t *testing.T) {
+		// Arrange
+		apiKeyUsecase, mockAPIKeyRepo := setup()
+		mockAPIKeyRepo.On("GetByHash", mock.Anything).Return(nil, Domain.ErrAPIKeyNotFound)
+
+		// Act
+		validated, err := apiKeyUsecase.ValidateAPIKey(context.Background(), "tm_unknown")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidAPIKey)
+		assert.Nil(t, validated)
+	})
+
+	t.Run("Error - revoked key", func(t *testing.T) {
+		// Arrange
+		apiKeyUsecase, mockAPIKeyRepo := setup()
+		key := &Domain.APIKey{ID: primitive.NewObjectID(), Name: "cron", Role: Domain.RoleUser, Revoked: true}
+		mockAPIKeyRepo.On("GetByHash", mock.Anything).Return(key, nil)
+
+		// Act
+		validated, err := apiKeyUsecase.ValidateAPIKey(context.Background(), "tm_secret")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAPIKeyRevoked)
+		assert.Nil(t, validated)
+		mockAPIKeyRepo.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+	})
+}
+
+func TestAPIKeyUsecaseInterface(t *testing.T) {
+	var _ Infrastructure.APIKeyValidator = NewAPIKeyUsecase(new(MockAPIKeyRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+}
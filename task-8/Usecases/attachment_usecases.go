@@ -0,0 +1,267 @@
+package Usecases
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// DefaultMaxAttachmentBytes is the largest file accepted when ATTACHMENT_MAX_BYTES is unset or invalid
+const DefaultMaxAttachmentBytes int64 = 10 << 20
+
+// MaxAttachmentFilenameLength is the longest filename kept, in characters; longer names are cut
+const MaxAttachmentFilenameLength = 255
+
+// DefaultAttachmentContentTypes are the file types accepted when ATTACHMENT_CONTENT_TYPES is unset
+var DefaultAttachmentContentTypes = []string{"application/pdf", "application/zip", "image/gif", "image/jpeg", "image/png", "image/webp", "text/plain"}
+
+// AttachmentLimits bound the files that may be attached to tasks
+type AttachmentLimits struct {
+	MaxBytes     int64
+	ContentTypes []string // Media types without parameters, such as "image/png"
+}
+
+// AttachmentLimitsFromEnv reads ATTACHMENT_MAX_BYTES, a positive number of bytes, and
+// ATTACHMENT_CONTENT_TYPES, a comma-separated list of media types
+func AttachmentLimitsFromEnv() AttachmentLimits {
+	limits := AttachmentLimits{MaxBytes: DefaultMaxAttachmentBytes, ContentTypes: DefaultAttachmentContentTypes}
+	if value := os.Getenv("ATTACHMENT_MAX_BYTES"); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			limits.MaxBytes = parsed
+		}
+	}
+	if value := os.Getenv("ATTACHMENT_CONTENT_TYPES"); value != "" {
+		var contentTypes []string
+		for _, contentType := range strings.Split(value, ",") {
+			if contentType = strings.ToLower(strings.TrimSpace(contentType)); contentType != "" {
+				contentTypes = append(contentTypes, contentType)
+			}
+		}
+		if len(contentTypes) > 0 {
+			limits.ContentTypes = contentTypes
+		}
+	}
+	return limits
+}
+
+// AttachmentUsecaseInterface defines the contract for attachment business logic
+type AttachmentUsecaseInterface interface {
+	AddAttachment(ctx context.Context, caller Domain.Caller, taskID, filename string, content io.Reader) (*Domain.Attachment, error)
+	GetAttachments(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.Attachment, error)
+	OpenAttachment(ctx context.Context, caller Domain.Caller, taskID, attachmentID string) (*Domain.Attachment, io.ReadCloser, error)
+	DeleteAttachment(ctx context.Context, caller Domain.Caller, taskID, attachmentID string) error
+}
+
+// AttachmentUsecase implements attachment business logic
+type AttachmentUsecase struct {
+	attachmentRepo Repositories.AttachmentRepositoryInterface
+	taskRepo       Repositories.TaskRepositoryInterface
+	blobs          Infrastructure.BlobStore
+	limits         AttachmentLimits
+	logger         *slog.Logger
+}
+
+// NewAttachmentUsecase creates a new instance of AttachmentUsecase
+func NewAttachmentUsecase(attachmentRepo Repositories.AttachmentRepositoryInterface, taskRepo Repositories.TaskRepositoryInterface, blobs Infrastructure.BlobStore, limits AttachmentLimits, logger *slog.Logger) AttachmentUsecaseInterface {
+	return &AttachmentUsecase{
+		attachmentRepo: attachmentRepo,
+		taskRepo:       taskRepo,
+		blobs:          blobs,
+		limits:         limits,
+		logger:         logger,
+	}
+}
+
+// AddAttachment stores a file uploaded by the caller to a task the caller can see. The content type
+// is detected from the first bytes of the file, so a client cannot label an executable as an image.
+func (au *AttachmentUsecase) AddAttachment(ctx context.Context, caller Domain.Caller, taskID, filename string, content io.Reader) (*Domain.Attachment, error) {
+	uploaderID, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	task, err := au.visibleTask(ctx, caller, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	// http.DetectContentType looks at no more than the first 512 bytes
+	head := make([]byte, 512)
+	n, err := io.ReadFull(content, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "attachment is empty")
+	}
+	contentType := http.DetectContentType(head[:n])
+	if !au.isAllowedContentType(contentType) {
+		return nil, Domain.NewError(Domain.ErrUnsupportedAttachmentType, "attachment content type %s is not allowed", contentType)
+	}
+
+	// One byte more than the limit is read so an oversized file can be told apart from one at the limit
+	storageKey := task.ID.Hex() + "/" + primitive.NewObjectID().Hex()
+	body := io.LimitReader(io.MultiReader(bytes.NewReader(head[:n]), content), au.limits.MaxBytes+1)
+	size, err := au.blobs.Put(ctx, storageKey, body)
+	if err != nil {
+		return nil, err
+	}
+	if size > au.limits.MaxBytes {
+		au.deleteBlob(ctx, storageKey)
+		return nil, Domain.NewError(Domain.ErrAttachmentTooLarge, "attachment must not exceed %d bytes", au.limits.MaxBytes)
+	}
+
+	attachment := &Domain.Attachment{
+		TaskID:      task.ID,
+		Filename:    attachmentFilename(filename),
+		ContentType: contentType,
+		Size:        size,
+		StorageKey:  storageKey,
+		UploadedBy:  uploaderID,
+	}
+
+	err = au.attachmentRepo.Create(ctx, attachment)
+	if err != nil {
+		au.deleteBlob(ctx, storageKey)
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// GetAttachments returns the attachments on a task the caller can see, oldest first
+func (au *AttachmentUsecase) GetAttachments(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.Attachment, error) {
+	if _, err := au.visibleTask(ctx, caller, taskID); err != nil {
+		return nil, err
+	}
+
+	return au.attachmentRepo.GetByTaskID(ctx, taskID)
+}
+
+// OpenAttachment returns an attachment on a task the caller can see together with its content,
+// which the caller closes
+func (au *AttachmentUsecase) OpenAttachment(ctx context.Context, caller Domain.Caller, taskID, attachmentID string) (*Domain.Attachment, io.ReadCloser, error) {
+	if _, err := au.visibleTask(ctx, caller, taskID); err != nil {
+		return nil, nil, err
+	}
+
+	attachment, err := au.attachmentRepo.GetByID(ctx, taskID, attachmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	content, err := au.blobs.Get(ctx, attachment.StorageKey)
+	if errors.Is(err, Infrastructure.ErrBlobNotFound) {
+		au.logger.ErrorContext(ctx, "attachment file is missing", "attachment_id", attachmentID, "storage_key", attachment.StorageKey)
+		return nil, nil, Domain.ErrAttachmentNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return attachment, content, nil
+}
+
+// DeleteAttachment permanently removes an attachment and its file. Only the uploader or a caller
+// who can write tasks may delete it.
+func (au *AttachmentUsecase) DeleteAttachment(ctx context.Context, caller Domain.Caller, taskID, attachmentID string) error {
+	if _, err := au.visibleTask(ctx, caller, taskID); err != nil {
+		return err
+	}
+
+	attachment, err := au.attachmentRepo.GetByID(ctx, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+	if attachment.UploadedBy.Hex() != caller.UserID && !caller.Can(Domain.PermissionTasksWrite) {
+		return Domain.ErrNotUploader
+	}
+
+	err = au.attachmentRepo.Delete(ctx, taskID, attachmentID)
+	if err != nil {
+		return err
+	}
+
+	au.deleteBlob(ctx, attachment.StorageKey)
+	return nil
+}
+
+// isAllowedContentType reports whether the media type of a detected content type is accepted
+func (au *AttachmentUsecase) isAllowedContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range au.limits.ContentTypes {
+		if mediaType == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// deleteBlob removes a file that no record points at
+func (au *AttachmentUsecase) deleteBlob(ctx context.Context, storageKey string) {
+	deleteAttachmentBlobs(ctx, au.logger, au.blobs, []*Domain.Attachment{{StorageKey: storageKey}})
+}
+
+// visibleTask loads a task, reporting "task not found" when the caller may not see it
+func (au *AttachmentUsecase) visibleTask(ctx context.Context, caller Domain.Caller, taskID string) (*Domain.Task, error) {
+	task, err := au.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.IsVisibleTo(caller) {
+		return nil, Domain.ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+// deleteAttachmentBlobs removes the files of attachments whose records are gone. A file that cannot
+// be removed is only logged: no record points at it any more, so it is never served again.
+func deleteAttachmentBlobs(ctx context.Context, logger *slog.Logger, blobs Infrastructure.BlobStore, attachments []*Domain.Attachment) {
+	for _, attachment := range attachments {
+		if err := blobs.Delete(ctx, attachment.StorageKey); err != nil {
+			logger.WarnContext(ctx, "failed to delete attachment file", "storage_key", attachment.StorageKey, "error", err)
+		}
+	}
+}
+
+// attachmentFilename keeps the last element of a client supplied filename, without control
+// characters and cut to MaxAttachmentFilenameLength characters
+func attachmentFilename(filename string) string {
+	// Browsers on Windows may send the full path
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, filename))
+
+	if filename == "" || filename == "." || filename == ".." || filename == "/" {
+		return "attachment"
+	}
+	if utf8.RuneCountInString(filename) > MaxAttachmentFilenameLength {
+		filename = string([]rune(filename)[:MaxAttachmentFilenameLength])
+	}
+	return filename
+}
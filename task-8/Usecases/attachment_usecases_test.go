@@ -0,0 +1,413 @@
+package Usecases
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// MockAttachmentRepository is a mock implementation of AttachmentRepositoryInterface
+type MockAttachmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAttachmentRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Attachment, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) GetByID(ctx context.Context, taskID, id string) (*Domain.Attachment, error) {
+	args := m.Called(taskID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) Create(ctx context.Context, attachment *Domain.Attachment) error {
+	args := m.Called(attachment)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) Delete(ctx context.Context, taskID, id string) error {
+	args := m.Called(taskID, id)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockAttachmentRepository) Purge(ctx context.Context, deletedBefore time.Time) ([]*Domain.Attachment, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).([]*Domain.Attachment), args.Error(1)
+}
+
+func (m *MockAttachmentRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) ([]*Domain.Attachment, error) {
+	args := m.Called(taskIDs)
+	return args.Get(0).([]*Domain.Attachment), args.Error(1)
+}
+
+// newMockAttachmentRepository returns a mock for tasks without attachments, for tests that are not about them
+func newMockAttachmentRepository() *MockAttachmentRepository {
+	mockAttachmentRepo := new(MockAttachmentRepository)
+	mockAttachmentRepo.On("DeleteByTaskID", mock.Anything).Return(nil).Maybe()
+	mockAttachmentRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	mockAttachmentRepo.On("RestoreByTaskID", mock.Anything).Return(nil).Maybe()
+	mockAttachmentRepo.On("Purge", mock.Anything).Return([]*Domain.Attachment{}, nil).Maybe()
+	mockAttachmentRepo.On("PurgeByTaskIDs", mock.Anything).Return([]*Domain.Attachment{}, nil).Maybe()
+	return mockAttachmentRepo
+}
+
+// MockBlobStore is a mock implementation of Infrastructure.BlobStore
+type MockBlobStore struct {
+	mock.Mock
+}
+
+func (m *MockBlobStore) Put(ctx context.Context, key string, r io.Reader) (int64, error) {
+	args := m.Called(key, r)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockBlobStore) Delete(ctx context.Context, key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+// newMockBlobStore returns a mock that accepts every delete
+func newMockBlobStore() *MockBlobStore {
+	mockBlobs := new(MockBlobStore)
+	mockBlobs.On("Delete", mock.Anything).Return(nil).Maybe()
+	return mockBlobs
+}
+
+// pngHeader is enough of a PNG file for its content type to be detected
+const pngHeader = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+
+func setupAttachmentUsecase(t *testing.T, limits AttachmentLimits) (AttachmentUsecaseInterface, *MockAttachmentRepository, *MockTaskRepository, string) {
+	dir := t.TempDir()
+	blobs := Infrastructure.NewLocalBlobStore(dir)
+
+	mockAttachmentRepo := new(MockAttachmentRepository)
+	mockTaskRepo := new(MockTaskRepository)
+	return NewAttachmentUsecase(mockAttachmentRepo, mockTaskRepo, blobs, limits, Infrastructure.NewNopLogger()), mockAttachmentRepo, mockTaskRepo, dir
+}
+
+// storedFiles lists the files kept under a blob store directory
+func storedFiles(t *testing.T, dir string) []string {
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, entry os.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	return files
+}
+
+func TestAttachmentUsecase_AddAttachment(t *testing.T) {
+	limits := AttachmentLimits{MaxBytes: 64, ContentTypes: []string{"image/png", "text/plain"}}
+
+	t.Run("Success - file is stored with its detected content type", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, mockAttachmentRepo, mockTaskRepo, dir := setupAttachmentUsecase(t, limits)
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: ownerID}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("Create", mock.MatchedBy(func(attachment *Domain.Attachment) bool {
+			return attachment.TaskID == task.ID && attachment.UploadedBy == ownerID && strings.HasPrefix(attachment.StorageKey, task.ID.Hex()+"/")
+		})).Return(nil)
+
+		// Act
+		attachment, err := attachmentUsecase.AddAttachment(context.Background(), userCaller, task.ID.Hex(), `C:\Users\me\screenshot.png`, strings.NewReader(pngHeader))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "screenshot.png", attachment.Filename)
+		assert.Equal(t, "image/png", attachment.ContentType)
+		assert.Equal(t, int64(len(pngHeader)), attachment.Size)
+		files := storedFiles(t, dir)
+		require.Len(t, files, 1)
+		content, _ := os.ReadFile(files[0])
+		assert.Equal(t, pngHeader, string(content))
+		mockAttachmentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - a file of exactly the limit is accepted", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, mockAttachmentRepo, mockTaskRepo, _ := setupAttachmentUsecase(t, limits)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("Create", mock.Anything).Return(nil)
+
+		// Act
+		attachment, err := attachmentUsecase.AddAttachment(context.Background(), adminCaller, task.ID.Hex(), "notes.txt", strings.NewReader(strings.Repeat("a", 64)))
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(64), attachment.Size)
+		assert.Equal(t, "text/plain; charset=utf-8", attachment.ContentType)
+	})
+
+	t.Run("Error - file over the limit is discarded", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, mockAttachmentRepo, mockTaskRepo, dir := setupAttachmentUsecase(t, limits)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		attachment, err := attachmentUsecase.AddAttachment(context.Background(), adminCaller, task.ID.Hex(), "notes.txt", strings.NewReader(strings.Repeat("a", 65)))
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAttachmentTooLarge)
+		assert.EqualError(t, err, "attachment must not exceed 64 bytes")
+		assert.Nil(t, attachment)
+		assert.Empty(t, storedFiles(t, dir))
+		mockAttachmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - content type not allowed", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, mockAttachmentRepo, mockTaskRepo, dir := setupAttachmentUsecase(t, limits)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		_, err := attachmentUsecase.AddAttachment(context.Background(), adminCaller, task.ID.Hex(), "image.png", strings.NewReader("<html><script>alert(1)</script></html>"))
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrUnsupportedAttachmentType)
+		assert.Empty(t, storedFiles(t, dir))
+		mockAttachmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - empty file", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, _, mockTaskRepo, _ := setupAttachmentUsecase(t, limits)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		_, err := attachmentUsecase.AddAttachment(context.Background(), adminCaller, task.ID.Hex(), "empty.txt", strings.NewReader(""))
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidInput)
+	})
+
+	t.Run("Error - task not visible to caller", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, mockAttachmentRepo, mockTaskRepo, _ := setupAttachmentUsecase(t, limits)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		_, err := attachmentUsecase.AddAttachment(context.Background(), userCaller, task.ID.Hex(), "notes.txt", strings.NewReader("hello"))
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		mockAttachmentRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - file is removed when its record cannot be saved", func(t *testing.T) {
+		// Arrange
+		attachmentUsecase, mockAttachmentRepo, mockTaskRepo, dir := setupAttachmentUsecase(t, limits)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("Create", mock.Anything).Return(assert.AnError)
+
+		// Act
+		_, err := attachmentUsecase.AddAttachment(context.Background(), adminCaller, task.ID.Hex(), "notes.txt", strings.NewReader("hello"))
+
+		// Assert
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Empty(t, storedFiles(t, dir))
+	})
+}
+
+func TestAttachmentUsecase_OpenAttachment(t *testing.T) {
+	t.Run("Success - content is read from the blob store", func(t *testing.T) {
+		// Arrange
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockBlobs := new(MockBlobStore)
+		attachmentUsecase := NewAttachmentUsecase(mockAttachmentRepo, mockTaskRepo, mockBlobs, AttachmentLimitsFromEnv(), Infrastructure.NewNopLogger())
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		stored := &Domain.Attachment{ID: primitive.NewObjectID(), TaskID: task.ID, Filename: "notes.txt", StorageKey: "key"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("GetByID", task.ID.Hex(), stored.ID.Hex()).Return(stored, nil)
+		mockBlobs.On("Get", "key").Return(io.NopCloser(strings.NewReader("hello")), nil)
+
+		// Act
+		attachment, content, err := attachmentUsecase.OpenAttachment(context.Background(), adminCaller, task.ID.Hex(), stored.ID.Hex())
+
+		// Assert
+		require.NoError(t, err)
+		body, _ := io.ReadAll(content)
+		assert.Equal(t, "hello", string(body))
+		assert.Equal(t, stored, attachment)
+	})
+
+	t.Run("Error - missing file is reported as a missing attachment", func(t *testing.T) {
+		// Arrange
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockBlobs := new(MockBlobStore)
+		attachmentUsecase := NewAttachmentUsecase(mockAttachmentRepo, mockTaskRepo, mockBlobs, AttachmentLimitsFromEnv(), Infrastructure.NewNopLogger())
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		stored := &Domain.Attachment{ID: primitive.NewObjectID(), TaskID: task.ID, StorageKey: "key"}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("GetByID", task.ID.Hex(), stored.ID.Hex()).Return(stored, nil)
+		mockBlobs.On("Get", "key").Return(nil, Infrastructure.ErrBlobNotFound)
+
+		// Act
+		_, _, err := attachmentUsecase.OpenAttachment(context.Background(), adminCaller, task.ID.Hex(), stored.ID.Hex())
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAttachmentNotFound)
+	})
+}
+
+func TestAttachmentUsecase_DeleteAttachment(t *testing.T) {
+	ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+
+	t.Run("Success - uploader deletes the record and the file", func(t *testing.T) {
+		// Arrange
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockBlobs := new(MockBlobStore)
+		attachmentUsecase := NewAttachmentUsecase(mockAttachmentRepo, mockTaskRepo, mockBlobs, AttachmentLimitsFromEnv(), Infrastructure.NewNopLogger())
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: ownerID}
+		stored := &Domain.Attachment{ID: primitive.NewObjectID(), TaskID: task.ID, StorageKey: "key", UploadedBy: ownerID}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("GetByID", task.ID.Hex(), stored.ID.Hex()).Return(stored, nil)
+		mockAttachmentRepo.On("Delete", task.ID.Hex(), stored.ID.Hex()).Return(nil)
+		mockBlobs.On("Delete", "key").Return(nil)
+
+		// Act
+		err := attachmentUsecase.DeleteAttachment(context.Background(), userCaller, task.ID.Hex(), stored.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		mockAttachmentRepo.AssertExpectations(t)
+		mockBlobs.AssertExpectations(t)
+	})
+
+	t.Run("Success - a file that cannot be removed does not fail the delete", func(t *testing.T) {
+		// Arrange
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockBlobs := new(MockBlobStore)
+		attachmentUsecase := NewAttachmentUsecase(mockAttachmentRepo, mockTaskRepo, mockBlobs, AttachmentLimitsFromEnv(), Infrastructure.NewNopLogger())
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		stored := &Domain.Attachment{ID: primitive.NewObjectID(), TaskID: task.ID, StorageKey: "key", UploadedBy: ownerID}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("GetByID", task.ID.Hex(), stored.ID.Hex()).Return(stored, nil)
+		mockAttachmentRepo.On("Delete", task.ID.Hex(), stored.ID.Hex()).Return(nil)
+		mockBlobs.On("Delete", "key").Return(assert.AnError)
+
+		// Act
+		err := attachmentUsecase.DeleteAttachment(context.Background(), adminCaller, task.ID.Hex(), stored.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - other users cannot delete the attachment", func(t *testing.T) {
+		// Arrange
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockBlobs := new(MockBlobStore)
+		attachmentUsecase := NewAttachmentUsecase(mockAttachmentRepo, mockTaskRepo, mockBlobs, AttachmentLimitsFromEnv(), Infrastructure.NewNopLogger())
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: ownerID}
+		stored := &Domain.Attachment{ID: primitive.NewObjectID(), TaskID: task.ID, StorageKey: "key", UploadedBy: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockAttachmentRepo.On("GetByID", task.ID.Hex(), stored.ID.Hex()).Return(stored, nil)
+
+		// Act
+		err := attachmentUsecase.DeleteAttachment(context.Background(), userCaller, task.ID.Hex(), stored.ID.Hex())
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrNotUploader)
+		mockAttachmentRepo.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+		mockBlobs.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestAttachmentLimitsFromEnv(t *testing.T) {
+	t.Run("Success - defaults", func(t *testing.T) {
+		t.Setenv("ATTACHMENT_MAX_BYTES", "")
+		t.Setenv("ATTACHMENT_CONTENT_TYPES", "")
+
+		limits := AttachmentLimitsFromEnv()
+
+		assert.Equal(t, DefaultMaxAttachmentBytes, limits.MaxBytes)
+		assert.Equal(t, DefaultAttachmentContentTypes, limits.ContentTypes)
+	})
+
+	t.Run("Success - configured", func(t *testing.T) {
+		t.Setenv("ATTACHMENT_MAX_BYTES", "1024")
+		t.Setenv("ATTACHMENT_CONTENT_TYPES", " Image/PNG, application/pdf ,")
+
+		limits := AttachmentLimitsFromEnv()
+
+		assert.Equal(t, int64(1024), limits.MaxBytes)
+		assert.Equal(t, []string{"image/png", "application/pdf"}, limits.ContentTypes)
+	})
+}
+
+func TestAttachmentFilename(t *testing.T) {
+	assert.Equal(t, "report.pdf", attachmentFilename("report.pdf"))
+	assert.Equal(t, "passwd", attachmentFilename("../../etc/passwd"))
+	assert.Equal(t, "photo.jpg", attachmentFilename(`C:\Users\me\photo.jpg`))
+	assert.Equal(t, "evil.txt", attachmentFilename("evil\r\n.txt"))
+	assert.Equal(t, "attachment", attachmentFilename(""))
+	assert.Equal(t, "attachment", attachmentFilename(".."))
+	assert.Equal(t, MaxAttachmentFilenameLength, len([]rune(attachmentFilename(strings.Repeat("é", 300)))))
+}
@@ -0,0 +1,56 @@
+package Usecases
+
+import (
+	"context"
+	"log/slog"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// AuditUsecaseInterface defines the contract for reading the audit log
+type AuditUsecaseInterface interface {
+	GetAuditLog(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error)
+}
+
+// AuditUsecase implements audit log business logic
+type AuditUsecase struct {
+	auditRepo Repositories.AuditRepositoryInterface
+}
+
+// NewAuditUsecase creates a new instance of AuditUsecase
+func NewAuditUsecase(auditRepo Repositories.AuditRepositoryInterface) AuditUsecaseInterface {
+	return &AuditUsecase{
+		auditRepo: auditRepo,
+	}
+}
+
+// GetAuditLog returns one page of audit entries, newest first, with the total number of matches
+func (au *AuditUsecase) GetAuditLog(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error) {
+	if filter.Entity != "" && !Domain.IsValidAuditEntity(filter.Entity) {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "invalid entity, must be one of: task, user, api_key, webhook, api")
+	}
+	if pagination.Limit < 0 || pagination.Offset < 0 {
+		return nil, 0, Domain.ErrInvalidPagination
+	}
+	if pagination.Sort != "" {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "the audit log is always sorted newest first")
+	}
+
+	return au.auditRepo.GetAll(ctx, filter, pagination)
+}
+
+// recordAudit writes an audit entry on behalf of the caller. Auditing is best-effort:
+// a failed write is logged and never fails the operation being audited.
+func recordAudit(ctx context.Context, logger *slog.Logger, auditRepo Repositories.AuditRepositoryInterface, caller Domain.Caller, action, entity, entityID, diff string) {
+	entry := &Domain.AuditEntry{
+		ActorID:  caller.UserID,
+		Action:   action,
+		Entity:   entity,
+		EntityID: entityID,
+		Diff:     diff,
+	}
+	if err := auditRepo.Create(ctx, entry); err != nil {
+		logger.WarnContext(ctx, "failed to write audit entry", "action", action, "entity", entity, "entity_id", entityID, "error", err)
+	}
+}
@@ -0,0 +1,229 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// MockAuditRepository is a mock implementation of AuditRepositoryInterface
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) Create(ctx context.Context, entry *Domain.AuditEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockAuditRepository) GetAll(ctx context.Context, filter Domain.AuditFilter, pagination Domain.Pagination) ([]*Domain.AuditEntry, int64, error) {
+	args := m.Called(filter, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.AuditEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAuditRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// newMockAuditRepository returns an audit repository that accepts any entry,
+// for tests that are not about auditing
+func newMockAuditRepository() *MockAuditRepository {
+	mockAuditRepo := new(MockAuditRepository)
+	mockAuditRepo.On("Create", mock.Anything).Return(nil).Maybe()
+	return mockAuditRepo
+}
+
+// auditEntryMatching matches an entry by its caller-provided fields
+func auditEntryMatching(actorID, action, entity, entityID, diff string) interface{} {
+	return mock.MatchedBy(func(entry *Domain.AuditEntry) bool {
+		return entry.ActorID == actorID && entry.Action == action && entry.Entity == entity && entry.EntityID == entityID && entry.Diff == diff
+	})
+}
+
+func TestAuditUsecase_GetAuditLog(t *testing.T) {
+	t.Run("Success - filtered page from repository", func(t *testing.T) {
+		// Arrange
+		mockAuditRepo := new(MockAuditRepository)
+		auditUsecase := NewAuditUsecase(mockAuditRepo)
+
+		filter := Domain.AuditFilter{ActorID: adminCaller.UserID, Entity: Domain.AuditEntityTask}
+		pagination := Domain.Pagination{Limit: 20}
+		expectedEntries := []*Domain.AuditEntry{
+			{ActorID: adminCaller.UserID, Action: Domain.AuditActionDelete, Entity: Domain.AuditEntityTask},
+		}
+		mockAuditRepo.On("GetAll", filter, pagination).Return(expectedEntries, int64(1), nil)
+
+		// Act
+		entries, total, err := auditUsecase.GetAuditLog(context.Background(), filter, pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedEntries, entries)
+		assert.Equal(t, int64(1), total)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid requests", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			filter        Domain.AuditFilter
+			pagination    Domain.Pagination
+			expectedError string
+		}{
+			{name: "unknown entity", filter: Domain.AuditFilter{Entity: "comment"}, expectedError: "invalid entity, must be one of: task, user, api_key, webhook, api"},
+			{name: "negative offset", pagination: Domain.Pagination{Offset: -1}, expectedError: "invalid pagination, limit and offset must not be negative"},
+			{name: "sort requested", pagination: Domain.Pagination{Sort: Domain.SortPriority}, expectedError: "the audit log is always sorted newest first"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockAuditRepo := new(MockAuditRepository)
+				auditUsecase := NewAuditUsecase(mockAuditRepo)
+
+				// Act
+				entries, _, err := auditUsecase.GetAuditLog(context.Background(), tt.filter, tt.pagination)
+
+				// Assert
+				assert.Nil(t, entries)
+				assert.EqualError(t, err, tt.expectedError)
+				mockAuditRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestTaskUsecase_Audit(t *testing.T) {
+	t.Run("Success - create records the caller as actor", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID()
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Run(func(args mock.Arguments) {
+			args.Get(0).(*Domain.Task).ID = taskID
+		}).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionCreate, Domain.AuditEntityTask, taskID.Hex(), `title "Audit me"`)).Return(nil).Once()
+
+		// Act
+		_, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Audit me", Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - update records only changed fields", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID()
+		existingTask := &Domain.Task{ID: taskID, Title: "Old", Status: Domain.StatusPending, Priority: Domain.PriorityMedium}
+		title := "New"
+		status := Domain.StatusInProgress
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(existingTask, nil)
+		mockRepo.On("Update", taskID.Hex(), mock.AnythingOfType("*Domain.Task")).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionUpdate, Domain.AuditEntityTask, taskID.Hex(), "title, status: pending -> in_progress")).Return(nil).Once()
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{Title: &title, Status: &status})
+
+		// Assert
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - no-op update is not audited", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID()
+		existingTask := &Domain.Task{ID: taskID, Title: "Same", Status: Domain.StatusPending, Priority: Domain.PriorityMedium}
+		title := "Same"
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(existingTask, nil)
+		mockRepo.On("Update", taskID.Hex(), mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{Title: &title})
+
+		// Assert
+		assert.NoError(t, err)
+		mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - audit failure does not fail the delete", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Test Task"}, nil)
+		mockRepo.On("GetByParentID", taskID).Return([]*Domain.Task{}, nil)
+		mockRepo.On("Delete", taskID).Return(nil)
+		mockCommentRepo.On("DeleteByTaskID", taskID).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionDelete, Domain.AuditEntityTask, taskID, "")).Return(errors.New("database error")).Once()
+
+		// Act
+		err := taskUsecase.DeleteTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - bulk delete records one entry", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("DeleteByStatus", Domain.StatusCompleted).Return(int64(3), int64(3), nil, nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionBulkDelete, Domain.AuditEntityTask, "", "moved 3 completed tasks to the trash")).Return(nil).Once()
+
+		// Act
+		_, err := taskUsecase.DeleteTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		mockAuditRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_PromoteUser_Audit(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(MockUserRepository)
+	mockAuditRepo := new(MockAuditRepository)
+	userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), mockAuditRepo, newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "promoted", Role: Domain.RoleUser}
+	mockUserRepo.On("GetByUsername", "promoted").Return(user, nil)
+	mockUserRepo.On("UpdateByUsername", "promoted", user).Return(nil)
+	mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionPromote, Domain.AuditEntityUser, user.ID.Hex(), "role: user -> admin")).Return(nil).Once()
+
+	// Act
+	_, err := userUsecase.PromoteUser(context.Background(), adminCaller, "promoted", "")
+
+	// Assert
+	assert.NoError(t, err)
+	mockAuditRepo.AssertExpectations(t)
+}
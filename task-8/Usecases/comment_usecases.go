@@ -0,0 +1,92 @@
+package Usecases
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// MaxCommentLength is the largest comment body accepted, in characters
+const MaxCommentLength = 2000
+
+// CommentUsecaseInterface defines the contract for comment business logic
+type CommentUsecaseInterface interface {
+	AddComment(ctx context.Context, caller Domain.Caller, taskID string, commentReq Domain.CommentRequest) (*Domain.Comment, error)
+	GetComments(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.Comment, error)
+}
+
+// CommentUsecase implements comment business logic
+type CommentUsecase struct {
+	commentRepo Repositories.CommentRepositoryInterface
+	taskRepo    Repositories.TaskRepositoryInterface
+}
+
+// NewCommentUsecase creates a new instance of CommentUsecase
+func NewCommentUsecase(commentRepo Repositories.CommentRepositoryInterface, taskRepo Repositories.TaskRepositoryInterface) CommentUsecaseInterface {
+	return &CommentUsecase{
+		commentRepo: commentRepo,
+		taskRepo:    taskRepo,
+	}
+}
+
+// AddComment adds a comment authored by the caller to a task the caller can see
+func (cu *CommentUsecase) AddComment(ctx context.Context, caller Domain.Caller, taskID string, commentReq Domain.CommentRequest) (*Domain.Comment, error) {
+	authorID, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	body := strings.TrimSpace(commentReq.Body)
+	if body == "" {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "comment body cannot be empty")
+	}
+	if utf8.RuneCountInString(body) > MaxCommentLength {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "comment body must not exceed %d characters", MaxCommentLength)
+	}
+
+	task, err := cu.visibleTask(ctx, caller, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &Domain.Comment{
+		TaskID:   task.ID,
+		AuthorID: authorID,
+		Body:     body,
+	}
+
+	err = cu.commentRepo.Create(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+// GetComments returns the comments on a task the caller can see, oldest first
+func (cu *CommentUsecase) GetComments(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.Comment, error) {
+	if _, err := cu.visibleTask(ctx, caller, taskID); err != nil {
+		return nil, err
+	}
+
+	return cu.commentRepo.GetByTaskID(ctx, taskID)
+}
+
+// visibleTask loads a task, reporting "task not found" when the caller may not see it
+func (cu *CommentUsecase) visibleTask(ctx context.Context, caller Domain.Caller, taskID string) (*Domain.Task, error) {
+	task, err := cu.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.IsVisibleTo(caller) {
+		return nil, Domain.ErrTaskNotFound
+	}
+
+	return task, nil
+}
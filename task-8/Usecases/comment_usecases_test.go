@@ -0,0 +1,207 @@
+package Usecases
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockCommentRepository is a mock implementation of CommentRepositoryInterface
+type MockCommentRepository struct {
+	mock.Mock
+}
+
+func (m *MockCommentRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.Comment, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Comment), args.Error(1)
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *Domain.Comment) error {
+	args := m.Called(comment)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockCommentRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockCommentRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	args := m.Called(taskIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func TestCommentUsecase_AddComment(t *testing.T) {
+	t.Run("Success - author taken from caller", func(t *testing.T) {
+		// Arrange
+		mockCommentRepo := new(MockCommentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: ownerID}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockCommentRepo.On("Create", mock.MatchedBy(func(comment *Domain.Comment) bool {
+			return comment.TaskID == task.ID && comment.AuthorID == ownerID && comment.Body == "Looks good"
+		})).Return(nil)
+
+		// Act
+		comment, err := commentUsecase.AddComment(context.Background(), userCaller, task.ID.Hex(), Domain.CommentRequest{Body: "  Looks good  "})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Looks good", comment.Body)
+		assert.Equal(t, ownerID, comment.AuthorID)
+		mockTaskRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		mockCommentRepo := new(MockCommentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskRepo.On("GetByID", taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		// Act
+		comment, err := commentUsecase.AddComment(context.Background(), adminCaller, taskID, Domain.CommentRequest{Body: "Hello"})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, comment)
+		mockCommentRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - task not visible to caller", func(t *testing.T) {
+		// Arrange
+		mockCommentRepo := new(MockCommentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		comment, err := commentUsecase.AddComment(context.Background(), userCaller, task.ID.Hex(), Domain.CommentRequest{Body: "Hello"})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, comment)
+		mockCommentRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - invalid bodies rejected before repository", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			body          string
+			expectedError string
+		}{
+			{name: "blank body", body: "   ", expectedError: "comment body cannot be empty"},
+			{name: "body too long", body: strings.Repeat("a", MaxCommentLength+1), expectedError: "comment body must not exceed 2000 characters"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockCommentRepo := new(MockCommentRepository)
+				mockTaskRepo := new(MockTaskRepository)
+				commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+				// Act
+				comment, err := commentUsecase.AddComment(context.Background(), adminCaller, primitive.NewObjectID().Hex(), Domain.CommentRequest{Body: tt.body})
+
+				// Assert
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, comment)
+				mockTaskRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+				mockCommentRepo.AssertNotCalled(t, "Create", mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Error - caller without valid user ID", func(t *testing.T) {
+		// Arrange
+		mockCommentRepo := new(MockCommentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+		// Act
+		comment, err := commentUsecase.AddComment(context.Background(), Domain.Caller{Role: Domain.RoleUser}, primitive.NewObjectID().Hex(), Domain.CommentRequest{Body: "Hello"})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+		assert.Nil(t, comment)
+	})
+}
+
+func TestCommentUsecase_GetComments(t *testing.T) {
+	t.Run("Success - return comments on visible task", func(t *testing.T) {
+		// Arrange
+		mockCommentRepo := new(MockCommentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task"}
+		expectedComments := []*Domain.Comment{{ID: primitive.NewObjectID(), TaskID: task.ID, Body: "First"}}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockCommentRepo.On("GetByTaskID", task.ID.Hex()).Return(expectedComments, nil)
+
+		// Act
+		comments, err := commentUsecase.GetComments(context.Background(), adminCaller, task.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedComments, comments)
+		mockTaskRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		mockCommentRepo := new(MockCommentRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		commentUsecase := NewCommentUsecase(mockCommentRepo, mockTaskRepo)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockTaskRepo.On("GetByID", taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		// Act
+		comments, err := commentUsecase.GetComments(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, comments)
+		mockCommentRepo.AssertNotCalled(t, "GetByTaskID", mock.Anything)
+	})
+}
@@ -0,0 +1,149 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// DefaultPasswordResetTTL is how long a reset token is valid, overridable with PASSWORD_RESET_TTL
+const DefaultPasswordResetTTL = time.Hour
+
+// PasswordResetUsecaseInterface defines the contract for self-service password recovery
+type PasswordResetUsecaseInterface interface {
+	RequestReset(ctx context.Context, username string) error
+	ResetPassword(ctx context.Context, token, newPassword string) error
+}
+
+// PasswordResetUsecase implements password recovery with single-use, time-limited reset tokens
+type PasswordResetUsecase struct {
+	userRepo          Repositories.UserRepositoryInterface
+	passwordResetRepo Repositories.PasswordResetRepositoryInterface
+	refreshTokenRepo  Repositories.RefreshTokenRepositoryInterface
+	passwordService   Infrastructure.PasswordServiceInterface
+	passwordPolicy    Infrastructure.PasswordPolicyInterface
+	notifier          Infrastructure.Notifier
+	auditRepo         Repositories.AuditRepositoryInterface
+	tokenTTL          time.Duration
+	logger            *slog.Logger
+}
+
+// NewPasswordResetUsecase creates a new instance of PasswordResetUsecase.
+// Reset tokens are valid for PASSWORD_RESET_TTL (a duration such as "30m"), falling back to one hour.
+func NewPasswordResetUsecase(
+	userRepo Repositories.UserRepositoryInterface,
+	passwordResetRepo Repositories.PasswordResetRepositoryInterface,
+	refreshTokenRepo Repositories.RefreshTokenRepositoryInterface,
+	passwordService Infrastructure.PasswordServiceInterface,
+	passwordPolicy Infrastructure.PasswordPolicyInterface,
+	notifier Infrastructure.Notifier,
+	auditRepo Repositories.AuditRepositoryInterface,
+	logger *slog.Logger,
+) PasswordResetUsecaseInterface {
+	tokenTTL := DefaultPasswordResetTTL
+	if value, err := time.ParseDuration(os.Getenv("PASSWORD_RESET_TTL")); err == nil && value > 0 {
+		tokenTTL = value
+	}
+
+	return &PasswordResetUsecase{
+		userRepo:          userRepo,
+		passwordResetRepo: passwordResetRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		passwordService:   passwordService,
+		passwordPolicy:    passwordPolicy,
+		notifier:          notifier,
+		auditRepo:         auditRepo,
+		tokenTTL:          tokenTTL,
+		logger:            logger,
+	}
+}
+
+// RequestReset issues a reset token for the user and hands it to the notifier.
+// Unknown usernames and delivery failures are not reported, so the outcome
+// looks the same to the caller whether or not the user exists.
+func (pu *PasswordResetUsecase) RequestReset(ctx context.Context, username string) error {
+	user, err := pu.userRepo.GetByUsername(ctx, Domain.NormalizeUsername(username))
+	if err != nil {
+		if errors.Is(err, Domain.ErrUserNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	token, err := Infrastructure.GenerateOpaqueToken()
+	if err != nil {
+		return errors.New("failed to generate reset token")
+	}
+
+	resetToken := &Domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: Infrastructure.HashOpaqueToken(token),
+		ExpiresAt: time.Now().Add(pu.tokenTTL),
+	}
+	if err := pu.passwordResetRepo.Create(ctx, resetToken); err != nil {
+		return err
+	}
+
+	if err := pu.notifier.SendPasswordReset(ctx, user, token, resetToken.ExpiresAt); err != nil {
+		pu.logger.ErrorContext(ctx, "failed to send password reset", "user_id", user.ID.Hex(), "error", err)
+	}
+	return nil
+}
+
+// ResetPassword consumes a reset token and sets the new password. The password policy is checked
+// first so a rejected password leaves the token usable. The token is marked used before the
+// password changes so it works only once, and all refresh tokens of the user are revoked.
+func (pu *PasswordResetUsecase) ResetPassword(ctx context.Context, token, newPassword string) error {
+	if err := pu.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	resetToken, err := pu.passwordResetRepo.GetByHash(ctx, Infrastructure.HashOpaqueToken(token))
+	if err != nil {
+		if errors.Is(err, Domain.ErrResetTokenNotFound) {
+			return Domain.ErrInvalidResetToken
+		}
+		return err
+	}
+
+	if resetToken.UsedAt != nil {
+		return Domain.ErrResetTokenUsed
+	}
+	if !time.Now().Before(resetToken.ExpiresAt) {
+		return Domain.ErrResetTokenExpired
+	}
+
+	user, err := pu.userRepo.GetByID(ctx, resetToken.UserID.Hex())
+	if err != nil {
+		return err
+	}
+
+	hashedPassword, err := pu.passwordService.HashPassword(newPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	// Claim the token; of two requests racing with the same token only one gets past here
+	if err := pu.passwordResetRepo.MarkUsed(ctx, resetToken.ID); err != nil {
+		return err
+	}
+
+	user.Password = hashedPassword
+	if err := pu.userRepo.Update(ctx, user.ID.Hex(), user); err != nil {
+		return err
+	}
+
+	caller := Domain.Caller{UserID: user.ID.Hex(), Role: user.Role}
+	recordAudit(ctx, pu.logger, pu.auditRepo, caller, Domain.AuditActionResetPassword, Domain.AuditEntityUser, user.ID.Hex(), "")
+
+	if err := pu.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		pu.logger.ErrorContext(ctx, "failed to revoke refresh tokens after password reset", "user_id", user.ID.Hex(), "error", err)
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// MockPasswordResetRepository is a mock implementation of PasswordResetRepositoryInterface
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(ctx context.Context, token *Domain.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) GetByHash(ctx context.Context, tokenHash string) (*Domain.PasswordResetToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) MarkUsed(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockNotifier is a mock implementation of Infrastructure.Notifier
+type MockNotifier struct {
+	mock.Mock
+}
+
+func (m *MockNotifier) SendPasswordReset(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error {
+	args := m.Called(user, token, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockNotifier) SendEmailVerification(ctx context.Context, user *Domain.User, token string, expiresAt time.Time) error {
+	args := m.Called(user, token, expiresAt)
+	return args.Error(0)
+}
+
+func (m *MockNotifier) SendTaskReminder(ctx context.Context, user *Domain.User, task *Domain.Task, overdue bool) error {
+	args := m.Called(user, task, overdue)
+	return args.Error(0)
+}
+
+type passwordResetMocks struct {
+	userRepo          *MockUserRepository
+	passwordResetRepo *MockPasswordResetRepository
+	refreshTokenRepo  *MockRefreshTokenRepository
+	passwordService   *MockPasswordService
+	notifier          *MockNotifier
+}
+
+func setupPasswordResetUsecase() (PasswordResetUsecaseInterface, passwordResetMocks) {
+	mocks := passwordResetMocks{
+		userRepo:          new(MockUserRepository),
+		passwordResetRepo: new(MockPasswordResetRepository),
+		refreshTokenRepo:  new(MockRefreshTokenRepository),
+		passwordService:   new(MockPasswordService),
+		notifier:          new(MockNotifier),
+	}
+	passwordResetUsecase := NewPasswordResetUsecase(mocks.userRepo, mocks.passwordResetRepo, mocks.refreshTokenRepo, mocks.passwordService, Infrastructure.NewPermissivePasswordPolicy(), mocks.notifier, newMockAuditRepository(), Infrastructure.NewNopLogger())
+	return passwordResetUsecase, mocks
+}
+
+func TestPasswordResetUsecase_RequestReset(t *testing.T) {
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Email: "user@example.com", Role: Domain.RoleUser}
+
+	t.Run("Success - hashed token stored and plain token sent", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		var stored *Domain.PasswordResetToken
+		mocks.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+		mocks.passwordResetRepo.On("Create", mock.AnythingOfType("*Domain.PasswordResetToken")).
+			Run(func(args mock.Arguments) { stored = args.Get(0).(*Domain.PasswordResetToken) }).
+			Return(nil)
+		mocks.notifier.On("SendPasswordReset", user, mock.AnythingOfType("string"), mock.AnythingOfType("time.Time")).Return(nil)
+
+		// Act
+		err := passwordResetUsecase.RequestReset(context.Background(), "testuser")
+
+		// Assert
+		assert.NoError(t, err)
+		mocks.notifier.AssertExpectations(t)
+		sentToken := mocks.notifier.Calls[0].Arguments.String(1)
+		assert.Equal(t, user.ID, stored.UserID)
+		assert.Equal(t, Infrastructure.HashOpaqueToken(sentToken), stored.TokenHash)
+		assert.NotEqual(t, sentToken, stored.TokenHash)
+		assert.WithinDuration(t, time.Now().Add(DefaultPasswordResetTTL), stored.ExpiresAt, time.Minute)
+	})
+
+	t.Run("Success - unknown user is not revealed", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		mocks.userRepo.On("GetByUsername", "nobody").Return(nil, Domain.ErrUserNotFound)
+
+		// Act
+		err := passwordResetUsecase.RequestReset(context.Background(), "nobody")
+
+		// Assert
+		assert.NoError(t, err)
+		mocks.passwordResetRepo.AssertNotCalled(t, "Create", mock.Anything)
+		mocks.notifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - delivery failure is not revealed", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		mocks.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+		mocks.passwordResetRepo.On("Create", mock.Anything).Return(nil)
+		mocks.notifier.On("SendPasswordReset", user, mock.Anything, mock.Anything).Return(errors.New("user has no email address"))
+
+		// Act
+		err := passwordResetUsecase.RequestReset(context.Background(), "testuser")
+
+		// Assert
+		assert.NoError(t, err)
+	})
+
+	t.Run("Error - database failure looking up the user", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		mocks.userRepo.On("GetByUsername", "testuser").Return(nil, errors.New("database error"))
+
+		// Act
+		err := passwordResetUsecase.RequestReset(context.Background(), "testuser")
+
+		// Assert
+		assert.EqualError(t, err, "database error")
+	})
+
+	t.Run("Error - token cannot be stored", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		mocks.userRepo.On("GetByUsername", "testuser").Return(user, nil)
+		mocks.passwordResetRepo.On("Create", mock.Anything).Return(errors.New("database error"))
+
+		// Act
+		err := passwordResetUsecase.RequestReset(context.Background(), "testuser")
+
+		// Assert
+		assert.EqualError(t, err, "database error")
+		mocks.notifier.AssertNotCalled(t, "SendPasswordReset", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestPasswordResetUsecase_ResetPassword(t *testing.T) {
+	userID := primitive.NewObjectID()
+	tokenHash := Infrastructure.HashOpaqueToken("reset-token")
+	storedToken := func() *Domain.PasswordResetToken {
+		return &Domain.PasswordResetToken{ID: primitive.NewObjectID(), UserID: userID, TokenHash: tokenHash, ExpiresAt: time.Now().Add(time.Hour)}
+	}
+	storedUser := func() *Domain.User {
+		return &Domain.User{ID: userID, Username: "testuser", Password: "old-hash", Role: Domain.RoleUser}
+	}
+
+	t.Run("Success - password replaced and refresh tokens revoked", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		token := storedToken()
+		mocks.passwordResetRepo.On("GetByHash", tokenHash).Return(token, nil)
+		mocks.userRepo.On("GetByID", userID.Hex()).Return(storedUser(), nil)
+		mocks.passwordService.On("HashPassword", "newpassword").Return("new-hash", nil)
+		mocks.passwordResetRepo.On("MarkUsed", token.ID).Return(nil)
+		mocks.userRepo.On("Update", userID.Hex(), mock.MatchedBy(func(user *Domain.User) bool {
+			return user.Password == "new-hash"
+		})).Return(nil)
+		mocks.refreshTokenRepo.On("RevokeAllForUser", userID).Return(nil)
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "reset-token", "newpassword")
+
+		// Assert
+		assert.NoError(t, err)
+		mocks.passwordResetRepo.AssertExpectations(t)
+		mocks.userRepo.AssertExpectations(t)
+		mocks.refreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - unknown token", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		mocks.passwordResetRepo.On("GetByHash", Infrastructure.HashOpaqueToken("unknown")).Return(nil, Domain.ErrResetTokenNotFound)
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "unknown", "newpassword")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidResetToken)
+	})
+
+	t.Run("Error - token already used", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		token := storedToken()
+		usedAt := time.Now().Add(-time.Minute)
+		token.UsedAt = &usedAt
+		mocks.passwordResetRepo.On("GetByHash", tokenHash).Return(token, nil)
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "reset-token", "newpassword")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrResetTokenUsed)
+		mocks.userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - token expired", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		token := storedToken()
+		token.ExpiresAt = time.Now().Add(-time.Minute)
+		mocks.passwordResetRepo.On("GetByHash", tokenHash).Return(token, nil)
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "reset-token", "newpassword")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrResetTokenExpired)
+		mocks.passwordResetRepo.AssertNotCalled(t, "MarkUsed", mock.Anything)
+	})
+
+	t.Run("Error - token used by a concurrent request", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		token := storedToken()
+		mocks.passwordResetRepo.On("GetByHash", tokenHash).Return(token, nil)
+		mocks.userRepo.On("GetByID", userID.Hex()).Return(storedUser(), nil)
+		mocks.passwordService.On("HashPassword", "newpassword").Return("new-hash", nil)
+		mocks.passwordResetRepo.On("MarkUsed", token.ID).Return(Domain.ErrResetTokenUsed)
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "reset-token", "newpassword")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrResetTokenUsed)
+		mocks.userRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - hashing fails", func(t *testing.T) {
+		// Arrange
+		passwordResetUsecase, mocks := setupPasswordResetUsecase()
+		mocks.passwordResetRepo.On("GetByHash", tokenHash).Return(storedToken(), nil)
+		mocks.userRepo.On("GetByID", userID.Hex()).Return(storedUser(), nil)
+		mocks.passwordService.On("HashPassword", "newpassword").Return("", errors.New("bcrypt error"))
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "reset-token", "newpassword")
+
+		// Assert
+		assert.EqualError(t, err, "failed to hash password")
+		mocks.passwordResetRepo.AssertNotCalled(t, "MarkUsed", mock.Anything)
+	})
+
+	t.Run("Error - new password fails the policy and the token stays usable", func(t *testing.T) {
+		// Arrange
+		mockPasswordResetRepo := new(MockPasswordResetRepository)
+		policy := &Infrastructure.PasswordPolicy{MinLength: 8}
+		passwordResetUsecase := NewPasswordResetUsecase(new(MockUserRepository), mockPasswordResetRepo, new(MockRefreshTokenRepository), new(MockPasswordService), policy, new(MockNotifier), newMockAuditRepository(), Infrastructure.NewNopLogger())
+
+		// Act
+		err := passwordResetUsecase.ResetPassword(context.Background(), "reset-token", "short")
+
+		// Assert
+		var policyErr *Domain.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		mockPasswordResetRepo.AssertNotCalled(t, "GetByHash", mock.Anything)
+		mockPasswordResetRepo.AssertNotCalled(t, "MarkUsed", mock.Anything)
+	})
+}
+
+func TestNewPasswordResetUsecase_TokenTTL(t *testing.T) {
+	t.Run("Configured TTL", func(t *testing.T) {
+		t.Setenv("PASSWORD_RESET_TTL", "30m")
+		passwordResetUsecase, _ := setupPasswordResetUsecase()
+		assert.Equal(t, 30*time.Minute, passwordResetUsecase.(*PasswordResetUsecase).tokenTTL)
+	})
+
+	t.Run("Invalid TTL falls back to the default", func(t *testing.T) {
+		t.Setenv("PASSWORD_RESET_TTL", "soon")
+		passwordResetUsecase, _ := setupPasswordResetUsecase()
+		assert.Equal(t, DefaultPasswordResetTTL, passwordResetUsecase.(*PasswordResetUsecase).tokenTTL)
+	})
+}
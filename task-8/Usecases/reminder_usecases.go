@@ -0,0 +1,117 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"time"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// DefaultReminderWindow is how long before its due date a task is reminded of, overridable with REMINDER_WINDOW
+const DefaultReminderWindow = 24 * time.Hour
+
+// reminderBatchSize is how many tasks are loaded at a time while sending reminders
+const reminderBatchSize = 100
+
+// ReminderUsecaseInterface defines the contract for due-date reminders
+type ReminderUsecaseInterface interface {
+	SendReminders(ctx context.Context, now time.Time) (int, error)
+}
+
+// ReminderUsecase reminds users of open tasks that are coming due or overdue
+type ReminderUsecase struct {
+	taskRepo Repositories.TaskRepositoryInterface
+	userRepo Repositories.UserRepositoryInterface
+	notifier Infrastructure.Notifier
+	window   time.Duration
+	logger   *slog.Logger
+}
+
+// NewReminderUsecase creates a new instance of ReminderUsecase.
+// Tasks are reminded of REMINDER_WINDOW (a duration such as "2h") before they are due, falling back to a day.
+func NewReminderUsecase(
+	taskRepo Repositories.TaskRepositoryInterface,
+	userRepo Repositories.UserRepositoryInterface,
+	notifier Infrastructure.Notifier,
+	logger *slog.Logger,
+) ReminderUsecaseInterface {
+	window := DefaultReminderWindow
+	if value, err := time.ParseDuration(os.Getenv("REMINDER_WINDOW")); err == nil && value > 0 {
+		window = value
+	}
+
+	return &ReminderUsecase{
+		taskRepo: taskRepo,
+		userRepo: userRepo,
+		notifier: notifier,
+		window:   window,
+		logger:   logger,
+	}
+}
+
+// SendReminders notifies the assignee, or the creator of unassigned tasks, of every open task due
+// within the window that has not been reminded yet, and once more when such a task becomes overdue.
+// Tasks are marked as they are handled so the next run skips them. A task whose reminder could not
+// be delivered stays unmarked and is retried next run; one whose recipient is gone, deactivated or
+// has no email address is marked without a reminder. It returns the number of reminders sent.
+func (ru *ReminderUsecase) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	sent := 0
+	for {
+		tasks, err := ru.taskRepo.GetDueForReminder(ctx, now.Add(ru.window), now, reminderBatchSize)
+		if err != nil {
+			return sent, err
+		}
+
+		marked := 0
+		for _, task := range tasks {
+			delivered, err := ru.remind(ctx, task, now)
+			if err != nil {
+				ru.logger.WarnContext(ctx, "failed to send task reminder", "task_id", task.ID.Hex(), "error", err)
+				continue
+			}
+			if err := ru.taskRepo.MarkNotified(ctx, task.ID, now); err != nil && !errors.Is(err, Domain.ErrTaskNotFound) {
+				return sent, err
+			}
+			marked++
+			if delivered {
+				sent++
+			}
+		}
+
+		// A short batch is the last one, and a batch that marked nothing would come back unchanged
+		if len(tasks) < reminderBatchSize || marked == 0 {
+			return sent, nil
+		}
+	}
+}
+
+// remind sends the reminder for one task. It reports false without an error when there is nobody
+// who can receive it, so the task is marked rather than retried forever.
+func (ru *ReminderUsecase) remind(ctx context.Context, task *Domain.Task, now time.Time) (bool, error) {
+	recipientID := task.OwnerID()
+	if recipientID.IsZero() {
+		return false, nil
+	}
+
+	user, err := ru.userRepo.GetByID(ctx, recipientID.Hex())
+	if err != nil {
+		if errors.Is(err, Domain.ErrUserNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !user.IsActive {
+		return false, nil
+	}
+
+	err = ru.notifier.SendTaskReminder(ctx, user, task, task.DueDate.Before(now))
+	if errors.Is(err, Infrastructure.ErrNoEmailAddress) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,160 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+func TestReminderUsecase_SendReminders(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newReminderUsecase := func() (ReminderUsecaseInterface, *MockTaskRepository, *MockUserRepository, *MockNotifier) {
+		mockTaskRepo := new(MockTaskRepository)
+		mockUserRepo := new(MockUserRepository)
+		mockNotifier := new(MockNotifier)
+		return NewReminderUsecase(mockTaskRepo, mockUserRepo, mockNotifier, Infrastructure.NewNopLogger()), mockTaskRepo, mockUserRepo, mockNotifier
+	}
+
+	t.Run("Success - assignee or creator is reminded and the task marked", func(t *testing.T) {
+		// Arrange
+		reminderUsecase, mockTaskRepo, mockUserRepo, mockNotifier := newReminderUsecase()
+		assignee := &Domain.User{ID: primitive.NewObjectID(), Username: "assignee", IsActive: true}
+		creator := &Domain.User{ID: primitive.NewObjectID(), Username: "creator", IsActive: true}
+		dueSoon := &Domain.Task{ID: primitive.NewObjectID(), DueDate: now.Add(time.Hour), CreatedBy: creator.ID, AssigneeID: &assignee.ID}
+		overdue := &Domain.Task{ID: primitive.NewObjectID(), DueDate: now.Add(-time.Hour), CreatedBy: creator.ID}
+
+		mockTaskRepo.On("GetDueForReminder", now.Add(DefaultReminderWindow), now, int64(reminderBatchSize)).Return([]*Domain.Task{overdue, dueSoon}, nil).Once()
+		mockUserRepo.On("GetByID", assignee.ID.Hex()).Return(assignee, nil)
+		mockUserRepo.On("GetByID", creator.ID.Hex()).Return(creator, nil)
+		mockNotifier.On("SendTaskReminder", assignee, dueSoon, false).Return(nil).Once()
+		mockNotifier.On("SendTaskReminder", creator, overdue, true).Return(nil).Once()
+		mockTaskRepo.On("MarkNotified", dueSoon.ID, now).Return(nil).Once()
+		mockTaskRepo.On("MarkNotified", overdue.ID, now).Return(nil).Once()
+
+		// Act
+		sent, err := reminderUsecase.SendReminders(context.Background(), now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, sent)
+		mockTaskRepo.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Success - window comes from REMINDER_WINDOW", func(t *testing.T) {
+		// Arrange
+		t.Setenv("REMINDER_WINDOW", "2h")
+		reminderUsecase, mockTaskRepo, _, _ := newReminderUsecase()
+		mockTaskRepo.On("GetDueForReminder", now.Add(2*time.Hour), now, int64(reminderBatchSize)).Return([]*Domain.Task{}, nil).Once()
+
+		// Act
+		sent, err := reminderUsecase.SendReminders(context.Background(), now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+		mockTaskRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - tasks nobody can receive are marked without a reminder", func(t *testing.T) {
+		// Arrange
+		reminderUsecase, mockTaskRepo, mockUserRepo, mockNotifier := newReminderUsecase()
+		deactivated := &Domain.User{ID: primitive.NewObjectID(), Username: "deactivated"}
+		noEmail := &Domain.User{ID: primitive.NewObjectID(), Username: "no-email", IsActive: true}
+		goneID := primitive.NewObjectID()
+		tasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), DueDate: now.Add(time.Hour), CreatedBy: deactivated.ID},
+			{ID: primitive.NewObjectID(), DueDate: now.Add(time.Hour), CreatedBy: noEmail.ID},
+			{ID: primitive.NewObjectID(), DueDate: now.Add(time.Hour), CreatedBy: goneID},
+			{ID: primitive.NewObjectID(), DueDate: now.Add(time.Hour)},
+		}
+
+		mockTaskRepo.On("GetDueForReminder", mock.Anything, now, int64(reminderBatchSize)).Return(tasks, nil).Once()
+		mockUserRepo.On("GetByID", deactivated.ID.Hex()).Return(deactivated, nil)
+		mockUserRepo.On("GetByID", noEmail.ID.Hex()).Return(noEmail, nil)
+		mockUserRepo.On("GetByID", goneID.Hex()).Return(nil, Domain.ErrUserNotFound)
+		mockNotifier.On("SendTaskReminder", noEmail, tasks[1], false).Return(Infrastructure.ErrNoEmailAddress).Once()
+		for _, task := range tasks {
+			mockTaskRepo.On("MarkNotified", task.ID, now).Return(nil).Once()
+		}
+
+		// Act
+		sent, err := reminderUsecase.SendReminders(context.Background(), now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+		mockTaskRepo.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
+	})
+
+	t.Run("Success - undelivered reminders are left for the next run", func(t *testing.T) {
+		// Arrange
+		reminderUsecase, mockTaskRepo, mockUserRepo, mockNotifier := newReminderUsecase()
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "user", IsActive: true}
+		tasks := make([]*Domain.Task, reminderBatchSize)
+		for i := range tasks {
+			tasks[i] = &Domain.Task{ID: primitive.NewObjectID(), DueDate: now.Add(time.Hour), CreatedBy: user.ID}
+		}
+
+		// A full batch that marks nothing would come back unchanged, so it is not fetched again
+		mockTaskRepo.On("GetDueForReminder", mock.Anything, now, int64(reminderBatchSize)).Return(tasks, nil).Once()
+		mockUserRepo.On("GetByID", user.ID.Hex()).Return(user, nil)
+		mockNotifier.On("SendTaskReminder", user, mock.Anything, false).Return(errors.New("connection refused"))
+
+		// Act
+		sent, err := reminderUsecase.SendReminders(context.Background(), now)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Zero(t, sent)
+		mockTaskRepo.AssertExpectations(t)
+		mockTaskRepo.AssertNotCalled(t, "MarkNotified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - tasks cannot be loaded", func(t *testing.T) {
+		// Arrange
+		reminderUsecase, mockTaskRepo, _, mockNotifier := newReminderUsecase()
+		mockTaskRepo.On("GetDueForReminder", mock.Anything, now, int64(reminderBatchSize)).Return(nil, errors.New("database error"))
+
+		// Act
+		sent, err := reminderUsecase.SendReminders(context.Background(), now)
+
+		// Assert
+		assert.EqualError(t, err, "database error")
+		assert.Zero(t, sent)
+		mockNotifier.AssertNotCalled(t, "SendTaskReminder", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_PatchTask_ResetsReminder(t *testing.T) {
+	// Arrange
+	mockRepo := new(MockTaskRepository)
+	taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+	taskID := primitive.NewObjectID()
+	notifiedAt := time.Now()
+	existingTask := &Domain.Task{ID: taskID, Title: "Report", Status: Domain.StatusPending, Priority: Domain.PriorityMedium, DueDate: time.Now().Add(time.Hour), LastNotifiedAt: &notifiedAt}
+	dueDate := "2030-01-02T12:00:00Z"
+
+	mockRepo.On("GetByID", taskID.Hex()).Return(existingTask, nil)
+	mockRepo.On("Update", taskID.Hex(), mock.MatchedBy(func(task *Domain.Task) bool {
+		return task.LastNotifiedAt == nil
+	})).Return(nil).Once()
+
+	// Act
+	_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{DueDate: &dueDate})
+
+	// Assert
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
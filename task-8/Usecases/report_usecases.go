@@ -0,0 +1,39 @@
+package Usecases
+
+import (
+	"context"
+	"time"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// ReportUsecaseInterface defines the contract for admin reports
+type ReportUsecaseInterface interface {
+	GetTasksByUser(ctx context.Context, since time.Time) ([]*Domain.UserTaskReport, error)
+}
+
+// ReportUsecase implements report business logic
+type ReportUsecase struct {
+	reportRepo Repositories.ReportRepositoryInterface
+	now        func() time.Time
+}
+
+// NewReportUsecase creates a new instance of ReportUsecase
+func NewReportUsecase(reportRepo Repositories.ReportRepositoryInterface) ReportUsecaseInterface {
+	return &ReportUsecase{
+		reportRepo: reportRepo,
+		now:        time.Now,
+	}
+}
+
+// GetTasksByUser returns every user's task counts, busiest first. A non-zero since only counts
+// tasks created at or after it.
+func (ru *ReportUsecase) GetTasksByUser(ctx context.Context, since time.Time) ([]*Domain.UserTaskReport, error) {
+	now := ru.now()
+	if since.After(now) {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "since must not be in the future")
+	}
+
+	return ru.reportRepo.GetTasksByUser(ctx, since, now)
+}
@@ -0,0 +1,83 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockReportRepository is a mock implementation of ReportRepositoryInterface
+type MockReportRepository struct {
+	mock.Mock
+}
+
+func (m *MockReportRepository) GetTasksByUser(ctx context.Context, since, now time.Time) ([]*Domain.UserTaskReport, error) {
+	args := m.Called(since, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.UserTaskReport), args.Error(1)
+}
+
+func TestReportUsecase_GetTasksByUser(t *testing.T) {
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	newReportUsecase := func() (*ReportUsecase, *MockReportRepository) {
+		mockReportRepo := new(MockReportRepository)
+		reportUsecase := NewReportUsecase(mockReportRepo).(*ReportUsecase)
+		reportUsecase.now = func() time.Time { return now }
+		return reportUsecase, mockReportRepo
+	}
+
+	t.Run("Success - report is counted as of now", func(t *testing.T) {
+		// Arrange
+		reportUsecase, mockReportRepo := newReportUsecase()
+		since := now.AddDate(0, -1, 0)
+		expected := []*Domain.UserTaskReport{
+			{UserID: primitive.NewObjectID(), Username: "alice", Pending: 2, Overdue: 1, Open: 2},
+			{UserID: primitive.NewObjectID(), Username: "bob"},
+		}
+		mockReportRepo.On("GetTasksByUser", since, now).Return(expected, nil)
+
+		// Act
+		reports, err := reportUsecase.GetTasksByUser(context.Background(), since)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expected, reports)
+		mockReportRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - since in the future", func(t *testing.T) {
+		// Arrange
+		reportUsecase, mockReportRepo := newReportUsecase()
+
+		// Act
+		reports, err := reportUsecase.GetTasksByUser(context.Background(), now.Add(time.Hour))
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidInput)
+		assert.Nil(t, reports)
+		mockReportRepo.AssertNotCalled(t, "GetTasksByUser", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - repository failure", func(t *testing.T) {
+		// Arrange
+		reportUsecase, mockReportRepo := newReportUsecase()
+		mockReportRepo.On("GetTasksByUser", time.Time{}, now).Return(nil, errors.New("database error"))
+
+		// Act
+		reports, err := reportUsecase.GetTasksByUser(context.Background(), time.Time{})
+
+		// Assert
+		assert.EqualError(t, err, "database error")
+		assert.Nil(t, reports)
+	})
+}
@@ -1,114 +1,1486 @@
 package Usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
 	"task_manager/Repositories"
 )
 
 // TaskUsecaseInterface defines the contract for task business logic
 type TaskUsecaseInterface interface {
-	GetAllTasks() ([]*Domain.Task, error)
-	GetTaskByID(id string) (*Domain.Task, error)
-	CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, error)
-	UpdateTask(id string, taskReq Domain.TaskRequest) (*Domain.Task, error)
-	DeleteTask(id string) error
+	GetAllTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	CountTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter) (int64, error)
+	SearchTasks(ctx context.Context, caller Domain.Caller, query string, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	ExportTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error
+	GetTaskByID(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error)
+	GetTaskByIDWithFields(ctx context.Context, caller Domain.Caller, id string, fields []string) (*Domain.Task, error)
+	CreateTask(ctx context.Context, caller Domain.Caller, taskReq Domain.TaskRequest, allowDuplicate *bool) (*Domain.Task, error)
+	CreateTasks(ctx context.Context, caller Domain.Caller, taskReqs []Domain.TaskRequest, atomic bool) (*Domain.BulkCreateResult, error)
+	ImportTasks(ctx context.Context, caller Domain.Caller, imports []Domain.TaskImport, skipDuplicates bool) (*Domain.ImportResult, error)
+	UpdateTask(ctx context.Context, caller Domain.Caller, id string, taskReq Domain.TaskRequest) (*Domain.Task, error)
+	PatchTask(ctx context.Context, caller Domain.Caller, id string, patch Domain.TaskPatchRequest) (*Domain.Task, error)
+	DeleteTask(ctx context.Context, caller Domain.Caller, id string) error
+	GetDeletedTasks(ctx context.Context, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	RestoreTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error)
+	ArchiveTask(ctx context.Context, caller Domain.Caller, id string, force bool) (*Domain.Task, error)
+	UnarchiveTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error)
+	ArchiveCompletedTasks(ctx context.Context, caller Domain.Caller, completedBefore time.Time) (*Domain.BulkResult, error)
+	PurgeDeletedTasks(ctx context.Context, olderThanDays int) (int64, error)
+	PurgeTasksByStatus(ctx context.Context, caller Domain.Caller, status string, olderThan time.Duration) (int64, error)
+	GetAssignedTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	GetOverdueTasks(ctx context.Context, caller Domain.Caller, pagination Domain.Pagination) ([]*Domain.Task, int64, error)
+	UpdateTaskStatus(ctx context.Context, caller Domain.Caller, id string, status string) (*Domain.Task, error)
+	UpdateTasksStatus(ctx context.Context, caller Domain.Caller, ids []string, status string) (*Domain.BulkResult, error)
+	DeleteTasksByStatus(ctx context.Context, caller Domain.Caller, status string) (*Domain.BulkResult, error)
+	GetTags(ctx context.Context, caller Domain.Caller) ([]string, error)
+	GetStats(ctx context.Context, caller Domain.Caller) (*Domain.TaskStats, error)
+	GetTaskWithSubtasks(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error)
+	GetTaskRevisions(ctx context.Context, caller Domain.Caller, id string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error)
+}
+
+// MaxTaskTags is the largest number of tags a single task may carry
+const MaxTaskTags = 20
+
+// MaxBulkTasks is the largest number of tasks accepted by a single bulk create
+const MaxBulkTasks = 100
+
+// MaxImportTasks is the largest number of tasks accepted by a single import
+const MaxImportTasks = 1000
+
+// MaxEstimateMinutes is the longest estimate accepted, a year of minutes
+const MaxEstimateMinutes = 365 * 24 * 60
+
+// Accepted due date layouts; date-only values are due at the end of that day
+const (
+	DueDateLayout     = "2006-01-02"
+	DueDateTimeLayout = time.RFC3339
+)
+
+// DueDateRules restrict the due dates given to tasks. The zero value accepts any date.
+type DueDateRules struct {
+	RejectPast    bool // Refuse due dates that have passed, on create and when an update sets a new one
+	MaxYearsAhead int  // Refuse due dates further than this many years ahead; 0 means no limit
+}
+
+// DueDateRulesFromEnv reads TASKS_REJECT_PAST_DUE_DATES, a boolean, and
+// TASKS_MAX_DUE_DATE_YEARS, a positive number of years
+func DueDateRulesFromEnv() DueDateRules {
+	var rules DueDateRules
+	if value := os.Getenv("TASKS_REJECT_PAST_DUE_DATES"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			rules.RejectPast = parsed
+		}
+	}
+	if value := os.Getenv("TASKS_MAX_DUE_DATE_YEARS"); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil && parsed > 0 {
+			rules.MaxYearsAhead = parsed
+		}
+	}
+	return rules
+}
+
+// check refuses a due date the rules do not allow. A task without a due date is always accepted.
+func (r DueDateRules) check(dueDate, now time.Time) error {
+	if dueDate.IsZero() {
+		return nil
+	}
+	if r.RejectPast && dueDate.Before(now) {
+		return Domain.ErrDueDateInPast
+	}
+	if r.MaxYearsAhead > 0 && dueDate.After(now.AddDate(r.MaxYearsAhead, 0, 0)) {
+		return Domain.NewError(Domain.ErrDueDateTooFar, "due date must be at most %d years from now", r.MaxYearsAhead)
+	}
+	return nil
 }
 
 // TaskUsecase implements task business logic
 type TaskUsecase struct {
-	taskRepo Repositories.TaskRepositoryInterface
+	taskRepo           Repositories.TaskRepositoryInterface
+	userRepo           Repositories.UserRepositoryInterface
+	commentRepo        Repositories.CommentRepositoryInterface
+	attachmentRepo     Repositories.AttachmentRepositoryInterface
+	blobs              Infrastructure.BlobStore
+	workLogRepo        Repositories.WorkLogRepositoryInterface
+	auditRepo          Repositories.AuditRepositoryInterface
+	revisionRepo       Repositories.RevisionRepositoryInterface
+	events             Infrastructure.EventBus
+	metrics            Infrastructure.BusinessMetrics
+	defaultLocation    *time.Location
+	enforceTransitions bool
+	maxOpenTasks       int64
+	allowDuplicates    bool
+	dueDateRules       DueDateRules
+	logger             *slog.Logger
 }
 
-// NewTaskUsecase creates a new instance of TaskUsecase
-func NewTaskUsecase(taskRepo Repositories.TaskRepositoryInterface) TaskUsecaseInterface {
+// NewTaskUsecase creates a new instance of TaskUsecase.
+// Date-only due dates are interpreted in TASKS_DEFAULT_TIMEZONE (an IANA name), falling back to UTC.
+// Status transitions are enforced unless TASKS_ENFORCE_STATUS_TRANSITIONS is set to false.
+// MAX_OPEN_TASKS_PER_USER caps the tasks a non-admin may own that are not completed; 0 means no limit.
+// Setting TASKS_ALLOW_DUPLICATES to false makes CreateTask refuse duplicates unless told otherwise.
+// Created, updated and deleted tasks are published to events and counted in metrics.
+// New due dates, on create and update, must pass dueDateRules.
+// Purging a task also deletes the files of its attachments from blobs.
+func NewTaskUsecase(taskRepo Repositories.TaskRepositoryInterface, userRepo Repositories.UserRepositoryInterface, commentRepo Repositories.CommentRepositoryInterface, attachmentRepo Repositories.AttachmentRepositoryInterface, blobs Infrastructure.BlobStore, workLogRepo Repositories.WorkLogRepositoryInterface, auditRepo Repositories.AuditRepositoryInterface, revisionRepo Repositories.RevisionRepositoryInterface, events Infrastructure.EventBus, metrics Infrastructure.BusinessMetrics, dueDateRules DueDateRules, logger *slog.Logger) TaskUsecaseInterface {
+	enforceTransitions := true
+	if value := os.Getenv("TASKS_ENFORCE_STATUS_TRANSITIONS"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			enforceTransitions = parsed
+		}
+	}
+
+	var maxOpenTasks int64
+	if value := os.Getenv("MAX_OPEN_TASKS_PER_USER"); value != "" {
+		if parsed, err := strconv.ParseInt(value, 10, 64); err == nil && parsed > 0 {
+			maxOpenTasks = parsed
+		}
+	}
+
+	allowDuplicates := true
+	if value := os.Getenv("TASKS_ALLOW_DUPLICATES"); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			allowDuplicates = parsed
+		}
+	}
+
 	return &TaskUsecase{
-		taskRepo: taskRepo,
+		taskRepo:           taskRepo,
+		userRepo:           userRepo,
+		commentRepo:        commentRepo,
+		attachmentRepo:     attachmentRepo,
+		blobs:              blobs,
+		workLogRepo:        workLogRepo,
+		auditRepo:          auditRepo,
+		revisionRepo:       revisionRepo,
+		events:             events,
+		metrics:            metrics,
+		defaultLocation:    DefaultLocation(),
+		enforceTransitions: enforceTransitions,
+		maxOpenTasks:       maxOpenTasks,
+		allowDuplicates:    allowDuplicates,
+		dueDateRules:       dueDateRules,
+		logger:             logger,
+	}
+}
+
+// GetAllTasks returns a page of tasks matching the filter along with the total number of matching tasks.
+// Admins see every task; regular users only see the tasks they created.
+func (tu *TaskUsecase) GetAllTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	if err := validatePagination(pagination); err != nil {
+		return nil, 0, err
 	}
+
+	filter, err := scopeTaskFilter(caller, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tu.taskRepo.GetAll(ctx, filter, pagination)
 }
 
-// GetAllTasks returns all tasks
-func (tu *TaskUsecase) GetAllTasks() ([]*Domain.Task, error) {
-	return tu.taskRepo.GetAll()
+// CountTasks returns the number of tasks matching the filter.
+// Regular users only count tasks they created, exactly as GetAllTasks lists them.
+func (tu *TaskUsecase) CountTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter) (int64, error) {
+	filter, err := scopeTaskFilter(caller, filter)
+	if err != nil {
+		return 0, err
+	}
+
+	return tu.taskRepo.CountTasks(ctx, filter)
 }
 
-// GetTaskByID returns a task by its ID
-func (tu *TaskUsecase) GetTaskByID(id string) (*Domain.Task, error) {
-	return tu.taskRepo.GetByID(id)
+// SearchTasks returns a page of the tasks whose title or description matches the query, most
+// relevant first. Regular users only find tasks they created, exactly as GetAllTasks lists them.
+func (tu *TaskUsecase) SearchTasks(ctx context.Context, caller Domain.Caller, query string, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "search query is required")
+	}
+	if utf8.RuneCountInString(query) > Domain.MaxSearchQueryLength {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, fmt.Sprintf("search query must be at most %d characters", Domain.MaxSearchQueryLength))
+	}
+	if pagination.Sort != "" {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "invalid sort, search results are always sorted by relevance")
+	}
+	if err := validatePagination(pagination); err != nil {
+		return nil, 0, err
+	}
+
+	filter, err := scopeTaskFilter(caller, Domain.TaskFilter{})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return tu.taskRepo.Search(ctx, query, filter, pagination)
 }
 
-// CreateTask creates a new task
-func (tu *TaskUsecase) CreateTask(taskReq Domain.TaskRequest) (*Domain.Task, error) {
-	// Validate status
-	if !Domain.IsValidStatus(taskReq.Status) {
-		return nil, errors.New("invalid status, must be one of: pending, in_progress, completed")
+// ExportTasks calls fn for every task matching the filter, one at a time.
+// Regular users only export tasks they created, exactly as GetAllTasks lists them.
+func (tu *TaskUsecase) ExportTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	filter, err := scopeTaskFilter(caller, filter)
+	if err != nil {
+		return err
 	}
 
-	// Parse due date if provided
-	var dueDate time.Time
-	var err error
-	if taskReq.DueDate != "" {
-		dueDate, err = time.Parse("2006-01-02", taskReq.DueDate)
+	return tu.taskRepo.Stream(ctx, filter, fn)
+}
+
+// scopeTaskFilter validates a listing filter and restricts regular users to the tasks they created
+func scopeTaskFilter(caller Domain.Caller, filter Domain.TaskFilter) (Domain.TaskFilter, error) {
+	if filter.Status != "" && !Domain.IsValidStatus(filter.Status) {
+		return Domain.TaskFilter{}, Domain.ErrInvalidStatus
+	}
+
+	if filter.Priority != "" && !Domain.IsValidPriority(filter.Priority) {
+		return Domain.TaskFilter{}, Domain.ErrInvalidPriority
+	}
+
+	if !caller.Can(Domain.PermissionTasksReadAll) {
+		ownerID, err := primitive.ObjectIDFromHex(caller.UserID)
 		if err != nil {
-			return nil, errors.New("invalid due date format, use YYYY-MM-DD")
+			return Domain.TaskFilter{}, Domain.ErrInvalidUserID
 		}
+		filter.CreatedBy = ownerID
 	}
 
-	task := &Domain.Task{
-		Title:       taskReq.Title,
-		Description: taskReq.Description,
-		DueDate:     dueDate,
-		Status:      taskReq.Status,
+	return filter, nil
+}
+
+// GetTaskByID returns a task by its ID with the total time logged on it.
+// Regular users get "task not found" for tasks they neither created nor are assigned to,
+// so existence is not leaked.
+func (tu *TaskUsecase) GetTaskByID(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	task, err := tu.visibleTask(ctx, caller, id)
+	if err != nil {
+		return nil, err
 	}
 
-	err = tu.taskRepo.Create(task)
+	loggedMinutes, err := tu.workLogRepo.TotalMinutesByTaskID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	task.TotalLoggedMinutes = &loggedMinutes
 
 	return task, nil
 }
 
-// UpdateTask updates an existing task
-func (tu *TaskUsecase) UpdateTask(id string, taskReq Domain.TaskRequest) (*Domain.Task, error) {
-	// Check if task exists
-	existingTask, err := tu.taskRepo.GetByID(id)
+// visibleTask loads a task, reporting "task not found" when the caller may not see it
+func (tu *TaskUsecase) visibleTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	task, err := tu.taskRepo.GetByID(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 
-	// Validate status
-	if !Domain.IsValidStatus(taskReq.Status) {
-		return nil, errors.New("invalid status, must be one of: pending, in_progress, completed")
+	if !task.IsVisibleTo(caller) {
+		return nil, Domain.ErrTaskNotFound
 	}
 
-	// Parse due date if provided
-	var dueDate time.Time
-	if taskReq.DueDate != "" {
-		dueDate, err = time.Parse("2006-01-02", taskReq.DueDate)
+	return task, nil
+}
+
+// GetTaskByIDWithFields returns a task like GetTaskByID, loading only the selected fields.
+// The fields visibility is decided on are always loaded.
+func (tu *TaskUsecase) GetTaskByIDWithFields(ctx context.Context, caller Domain.Caller, id string, fields []string) (*Domain.Task, error) {
+	for _, field := range fields {
+		if !Domain.IsValidTaskField(field) {
+			return nil, Domain.ErrInvalidTaskFields
+		}
+	}
+
+	task, err := tu.taskRepo.GetByIDWithFields(ctx, id, fields)
+	if err != nil {
+		return nil, err
+	}
+
+	if !task.IsVisibleTo(caller) {
+		return nil, Domain.ErrTaskNotFound
+	}
+
+	return task, nil
+}
+
+// GetTaskWithSubtasks returns a task with its subtasks embedded.
+// Subtasks the caller cannot see are left out.
+func (tu *TaskUsecase) GetTaskWithSubtasks(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	task, err := tu.GetTaskByID(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+
+	subtasks, err := tu.taskRepo.GetByParentID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task.Subtasks = []*Domain.Task{}
+	for _, subtask := range subtasks {
+		if subtask.IsVisibleTo(caller) {
+			task.Subtasks = append(task.Subtasks, subtask)
+		}
+	}
+
+	return task, nil
+}
+
+// GetAssignedTasks returns a page of tasks assigned to the caller
+func (tu *TaskUsecase) GetAssignedTasks(ctx context.Context, caller Domain.Caller, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	if filter.Status != "" && !Domain.IsValidStatus(filter.Status) {
+		return nil, 0, Domain.ErrInvalidStatus
+	}
+
+	if filter.Priority != "" && !Domain.IsValidPriority(filter.Priority) {
+		return nil, 0, Domain.ErrInvalidPriority
+	}
+
+	if err := validatePagination(pagination); err != nil {
+		return nil, 0, err
+	}
+
+	assigneeID, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, 0, Domain.ErrInvalidUserID
+	}
+	filter.AssigneeID = assigneeID
+
+	return tu.taskRepo.GetAll(ctx, filter, pagination)
+}
+
+// GetOverdueTasks lists tasks past their due date that are not completed, most overdue first.
+// Regular users only see overdue tasks they created.
+func (tu *TaskUsecase) GetOverdueTasks(ctx context.Context, caller Domain.Caller, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	if pagination.Sort != "" {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "invalid sort, overdue tasks are always sorted by due date")
+	}
+	if err := validatePagination(pagination); err != nil {
+		return nil, 0, err
+	}
+
+	now := time.Now()
+	filter := Domain.TaskFilter{OverdueAt: now}
+	if !caller.Can(Domain.PermissionTasksReadAll) {
+		ownerID, err := primitive.ObjectIDFromHex(caller.UserID)
 		if err != nil {
-			return nil, errors.New("invalid due date format, use YYYY-MM-DD")
+			return nil, 0, Domain.ErrInvalidUserID
 		}
+		filter.CreatedBy = ownerID
 	}
+	pagination.Sort = Domain.SortDueDate
 
-	// Update task fields
-	existingTask.Title = taskReq.Title
-	existingTask.Description = taskReq.Description
-	existingTask.DueDate = dueDate
-	existingTask.Status = taskReq.Status
+	tasks, total, err := tu.taskRepo.GetAll(ctx, filter, pagination)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	for _, task := range tasks {
+		task.SetDaysOverdue(now)
+	}
+
+	return tasks, total, nil
+}
+
+// UpdateTaskStatus changes only the status of a task.
+// Admins and managers may change any task; regular users only tasks assigned to them.
+func (tu *TaskUsecase) UpdateTaskStatus(ctx context.Context, caller Domain.Caller, id string, status string) (*Domain.Task, error) {
+	if !Domain.IsValidStatus(status) {
+		return nil, Domain.ErrInvalidStatus
+	}
+
+	task, err := tu.visibleTask(ctx, caller, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !caller.Can(Domain.PermissionTasksWrite) && !task.IsAssignedTo(caller.UserID) {
+		return nil, Domain.ErrNotAssignee
+	}
+
+	if err := tu.checkStatusTransition(task.Status, status); err != nil {
+		return nil, err
+	}
+
+	before := *task
+	task.Status = status
+
+	return tu.saveTask(ctx, caller, id, before, task)
+}
+
+// UpdateTasksStatus sets the status of several tasks at once.
+// Every id is validated before the repository is touched. With transition enforcement on,
+// tasks whose current status cannot move to the new one are left unchanged and not counted as matched.
+// Every task whose status changed is announced and counted like a single status update.
+// Recurring tasks completed this way do not spawn their next occurrence.
+func (tu *TaskUsecase) UpdateTasksStatus(ctx context.Context, caller Domain.Caller, ids []string, status string) (*Domain.BulkResult, error) {
+	if len(ids) == 0 {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "no task IDs provided")
+	}
+	if !Domain.IsValidStatus(status) {
+		return nil, Domain.ErrInvalidStatus
+	}
+
+	objectIDs := make([]primitive.ObjectID, len(ids))
+	for i, id := range ids {
+		objectID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", Domain.ErrInvalidTaskID, id)
+		}
+		objectIDs[i] = objectID
+	}
+
+	var fromStatuses []string
+	if tu.enforceTransitions {
+		fromStatuses = Domain.StatusesTransitioningTo(status)
+	}
+
+	matched, modified, changes, err := tu.taskRepo.UpdateStatusMany(ctx, objectIDs, status, fromStatuses)
+	if err != nil {
+		return nil, err
+	}
+
+	if modified > 0 {
+		recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionBulkUpdate, Domain.AuditEntityTask, "", fmt.Sprintf("status set to %s on %d of %d tasks", status, modified, len(ids)))
+	}
+	for _, change := range changes {
+		// Tasks already in the status only got a new version, so nothing changed for subscribers
+		if change.FromStatus == status {
+			continue
+		}
+		before := *change.Task
+		before.Status = change.FromStatus
+		tu.countStatusChange(caller, &before, change.Task)
+		tu.publish(ctx, caller, Domain.EventTaskUpdated, change.Task)
+	}
+
+	return &Domain.BulkResult{MatchedCount: matched, ModifiedCount: modified}, nil
+}
+
+// DeleteTasksByStatus moves every task with the given status, and the comments and attachments on them, to the trash
+func (tu *TaskUsecase) DeleteTasksByStatus(ctx context.Context, caller Domain.Caller, status string) (*Domain.BulkResult, error) {
+	if !Domain.IsValidStatus(status) {
+		return nil, Domain.ErrInvalidStatus
+	}
+
+	matched, modified, ids, err := tu.taskRepo.DeleteByStatus(ctx, status)
+	if err != nil {
+		return nil, err
+	}
+
+	// Comments, attachments and work logs follow their tasks into the trash, so purging the tasks purges them too
+	if len(ids) > 0 {
+		if err := tu.commentRepo.DeleteByTaskIDs(ctx, ids); err != nil {
+			return nil, err
+		}
+		if err := tu.attachmentRepo.DeleteByTaskIDs(ctx, ids); err != nil {
+			return nil, err
+		}
+		if err := tu.workLogRepo.DeleteByTaskIDs(ctx, ids); err != nil {
+			return nil, err
+		}
+	}
+
+	if modified > 0 {
+		recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionBulkDelete, Domain.AuditEntityTask, "", fmt.Sprintf("moved %d %s tasks to the trash", modified, status))
+	}
+	for _, id := range ids {
+		tu.publish(ctx, caller, Domain.EventTaskDeleted, Domain.DeletedTaskEvent{ID: id.Hex()})
+	}
+
+	return &Domain.BulkResult{MatchedCount: matched, ModifiedCount: modified}, nil
+}
+
+// CreateTask creates a new task owned by the caller. Unless duplicates are allowed, by allowDuplicate
+// or when it is nil by TASKS_ALLOW_DUPLICATES, a title repeating one of the caller's open tasks is refused.
+func (tu *TaskUsecase) CreateTask(ctx context.Context, caller Domain.Caller, taskReq Domain.TaskRequest, allowDuplicate *bool) (*Domain.Task, error) {
+	task, err := tu.buildTask(ctx, caller, taskReq)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.dueDateRules.check(task.DueDate, time.Now()); err != nil {
+		return nil, err
+	}
+
+	allowed := tu.allowDuplicates
+	if allowDuplicate != nil {
+		allowed = *allowDuplicate
+	}
+	if !allowed {
+		if err := tu.checkDuplicate(ctx, task); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tu.checkOpenTaskQuota(ctx, caller, task); err != nil {
+		return nil, err
+	}
+
+	err = tu.taskRepo.Create(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	tu.recordCreated(ctx, caller, task)
+
+	return task, nil
+}
+
+// checkDuplicate refuses a task whose creator already has a task with the same title, ignoring case
+// and whitespace, that is not completed. The error carries the ID of that task.
+func (tu *TaskUsecase) checkDuplicate(ctx context.Context, task *Domain.Task) error {
+	existing, err := tu.taskRepo.FindOpenByTitle(ctx, task.CreatedBy, task.Title)
+	if errors.Is(err, Domain.ErrTaskNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return &Domain.DuplicateTaskError{TaskID: existing.ID}
+}
+
+// checkOpenTaskQuota refuses a new open task once its owner already has the maximum number of
+// tasks that are not completed. Admins are exempt, as are tasks created already completed.
+func (tu *TaskUsecase) checkOpenTaskQuota(ctx context.Context, caller Domain.Caller, task *Domain.Task) error {
+	if tu.maxOpenTasks == 0 || caller.Role == Domain.RoleAdmin || task.Status == Domain.StatusCompleted {
+		return nil
+	}
+
+	open, err := tu.taskRepo.CountTasks(ctx, Domain.TaskFilter{CreatedBy: task.CreatedBy, OpenOnly: true})
+	if err != nil {
+		return err
+	}
+	if open >= tu.maxOpenTasks {
+		return fmt.Errorf("%w: %d of %d open tasks", Domain.ErrOpenTaskLimit, open, tu.maxOpenTasks)
+	}
+	return nil
+}
+
+// CreateTasks validates every request and inserts the valid ones in a single write.
+// Failures are reported by their index in the request list. In atomic mode nothing
+// is inserted unless every item is valid.
+func (tu *TaskUsecase) CreateTasks(ctx context.Context, caller Domain.Caller, taskReqs []Domain.TaskRequest, atomic bool) (*Domain.BulkCreateResult, error) {
+	if len(taskReqs) == 0 {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "no tasks provided")
+	}
+	if len(taskReqs) > MaxBulkTasks {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "a bulk request can have at most %d tasks", MaxBulkTasks)
+	}
 
-	err = tu.taskRepo.Update(id, existingTask)
+	result := &Domain.BulkCreateResult{
+		Created: []string{},
+		Errors:  []Domain.BulkItemError{},
+	}
+
+	now := time.Now()
+	tasks := make([]*Domain.Task, 0, len(taskReqs))
+	for i, taskReq := range taskReqs {
+		task, err := tu.buildTask(ctx, caller, taskReq)
+		if err == nil {
+			err = tu.dueDateRules.check(task.DueDate, now)
+		}
+		if err != nil {
+			result.Errors = append(result.Errors, Domain.BulkItemError{Index: i, Error: err.Error()})
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+
+	if len(tasks) == 0 || (atomic && len(result.Errors) > 0) {
+		return result, nil
+	}
+
+	err := tu.taskRepo.CreateMany(ctx, tasks)
 	if err != nil {
 		return nil, err
 	}
 
-	// Return updated task
-	return tu.taskRepo.GetByID(id)
+	for _, task := range tasks {
+		result.Created = append(result.Created, task.ID.Hex())
+		tu.recordCreated(ctx, caller, task)
+	}
+
+	return result, nil
+}
+
+// ImportTasks validates every entry with the CreateTask rules and inserts the valid ones in a single write.
+// Entries keep their created_at when one is given. With skipDuplicates, entries whose title and due date
+// exactly match an existing task or an earlier entry are skipped instead of imported.
+func (tu *TaskUsecase) ImportTasks(ctx context.Context, caller Domain.Caller, imports []Domain.TaskImport, skipDuplicates bool) (*Domain.ImportResult, error) {
+	if len(imports) == 0 {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "no tasks provided")
+	}
+	if len(imports) > MaxImportTasks {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "an import can have at most %d tasks", MaxImportTasks)
+	}
+
+	result := &Domain.ImportResult{
+		Created: []string{},
+		Errors:  []Domain.BulkItemError{},
+	}
+	skip := func(index int, reason string) {
+		result.Skipped++
+		result.Errors = append(result.Errors, Domain.BulkItemError{Index: index, Error: reason})
+	}
+
+	seen := map[string]bool{}
+	tasks := make([]*Domain.Task, 0, len(imports))
+	for i, entry := range imports {
+		task, err := tu.buildTask(ctx, caller, entry.TaskRequest)
+		if err != nil {
+			skip(i, err.Error())
+			continue
+		}
+
+		if entry.CreatedAt != "" {
+			createdAt, err := time.Parse(time.RFC3339, entry.CreatedAt)
+			if err != nil {
+				skip(i, "invalid created_at format, use RFC3339")
+				continue
+			}
+			task.CreatedAt = createdAt.UTC()
+		}
+
+		if skipDuplicates {
+			key := task.Title + "\x00" + task.DueDate.Format(time.RFC3339)
+			if seen[key] {
+				skip(i, "duplicate of an earlier task in the import")
+				continue
+			}
+			exists, err := tu.taskRepo.ExistsByTitleAndDueDate(ctx, task.Title, task.DueDate)
+			if err != nil {
+				return nil, err
+			}
+			if exists {
+				skip(i, "duplicate of an existing task")
+				continue
+			}
+			seen[key] = true
+		}
+
+		tasks = append(tasks, task)
+	}
+
+	if len(tasks) == 0 {
+		return result, nil
+	}
+
+	if err := tu.taskRepo.CreateMany(ctx, tasks); err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		result.Created = append(result.Created, task.ID.Hex())
+		tu.recordCreated(ctx, caller, task)
+	}
+	result.Imported = len(tasks)
+
+	return result, nil
 }
 
-// DeleteTask deletes a task by its ID
-func (tu *TaskUsecase) DeleteTask(id string) error {
-	return tu.taskRepo.Delete(id)
-}
\ No newline at end of file
+// buildTask validates a create request and returns the task it describes, owned by the caller
+func (tu *TaskUsecase) buildTask(ctx context.Context, caller Domain.Caller, taskReq Domain.TaskRequest) (*Domain.Task, error) {
+	ownerID, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	// Bulk requests bypass request binding, so the title is checked here too
+	if taskReq.Title == "" {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "title cannot be empty")
+	}
+
+	// Validate status
+	if !Domain.IsValidStatus(taskReq.Status) {
+		return nil, Domain.ErrInvalidStatus
+	}
+	if tu.enforceTransitions && !Domain.IsValidInitialStatus(taskReq.Status) {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "invalid initial status, tasks must be created as pending or in_progress")
+	}
+
+	// Validate priority, defaulting to medium
+	priority := taskReq.Priority
+	if priority == "" {
+		priority = Domain.PriorityMedium
+	}
+	if !Domain.IsValidPriority(priority) {
+		return nil, Domain.ErrInvalidPriority
+	}
+
+	// Parse due date if provided
+	dueDate, err := tu.parseDueDate(taskReq.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	recurrence, err := validateRecurrence(taskReq.Recurrence, dueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeID, err := tu.resolveAssignee(ctx, taskReq.AssigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := normalizeTags(taskReq.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	parentTaskID, err := tu.resolveParentTask(ctx, taskReq.ParentTaskID)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := validateEstimate(taskReq.EstimateMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Domain.Task{
+		Title:           taskReq.Title,
+		Description:     taskReq.Description,
+		DueDate:         dueDate,
+		Status:          taskReq.Status,
+		Priority:        priority,
+		Tags:            tags,
+		EstimateMinutes: estimate,
+		CreatedBy:       ownerID,
+		AssigneeID:      assigneeID,
+		ParentTaskID:    parentTaskID,
+		Recurrence:      recurrence,
+	}, nil
+}
+
+// UpdateTask updates an existing task
+func (tu *TaskUsecase) UpdateTask(ctx context.Context, caller Domain.Caller, id string, taskReq Domain.TaskRequest) (*Domain.Task, error) {
+	// Check if task exists
+	existingTask, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(existingTask, taskReq.Version); err != nil {
+		return nil, err
+	}
+
+	// Validate status
+	if !Domain.IsValidStatus(taskReq.Status) {
+		return nil, Domain.ErrInvalidStatus
+	}
+	if err := tu.checkStatusTransition(existingTask.Status, taskReq.Status); err != nil {
+		return nil, err
+	}
+
+	// Validate priority, defaulting to medium
+	priority := taskReq.Priority
+	if priority == "" {
+		priority = Domain.PriorityMedium
+	}
+	if !Domain.IsValidPriority(priority) {
+		return nil, Domain.ErrInvalidPriority
+	}
+
+	// Parse due date if provided
+	dueDate, err := tu.parseDueDate(taskReq.DueDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := tu.checkNewDueDate(existingTask, dueDate); err != nil {
+		return nil, err
+	}
+
+	recurrence, err := validateRecurrence(taskReq.Recurrence, dueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	assigneeID, err := tu.resolveAssignee(ctx, taskReq.AssigneeID)
+	if err != nil {
+		return nil, err
+	}
+
+	tags, err := normalizeTags(taskReq.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := validateEstimate(taskReq.EstimateMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update task fields
+	before := *existingTask
+	existingTask.Title = taskReq.Title
+	existingTask.Description = taskReq.Description
+	existingTask.DueDate = dueDate
+	existingTask.Status = taskReq.Status
+	existingTask.Priority = priority
+	existingTask.AssigneeID = assigneeID
+	existingTask.Recurrence = recurrence
+	if taskReq.Tags != nil {
+		existingTask.Tags = tags
+	}
+	if taskReq.EstimateMinutes != nil {
+		existingTask.EstimateMinutes = estimate
+	}
+
+	return tu.saveTask(ctx, caller, id, before, existingTask)
+}
+
+// PatchTask applies only the fields present in the patch to an existing task
+func (tu *TaskUsecase) PatchTask(ctx context.Context, caller Domain.Caller, id string, patch Domain.TaskPatchRequest) (*Domain.Task, error) {
+	if patch.Title == nil && patch.Description == nil && patch.DueDate == nil && patch.Status == nil && patch.Priority == nil && patch.AssigneeID == nil && patch.Tags == nil && patch.Recurrence == nil && patch.EstimateMinutes == nil {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "no fields provided for update")
+	}
+
+	// Validate every provided field before touching the repository
+	if patch.Title != nil && *patch.Title == "" {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "title cannot be empty")
+	}
+
+	if patch.Status != nil && !Domain.IsValidStatus(*patch.Status) {
+		return nil, Domain.ErrInvalidStatus
+	}
+
+	if patch.Priority != nil && !Domain.IsValidPriority(*patch.Priority) {
+		return nil, Domain.ErrInvalidPriority
+	}
+
+	if patch.Recurrence != nil && *patch.Recurrence != "" && !Domain.IsValidRecurrence(*patch.Recurrence) {
+		return nil, Domain.ErrInvalidRecurrence
+	}
+
+	var dueDate time.Time
+	if patch.DueDate != nil {
+		var err error
+		dueDate, err = tu.parseDueDate(*patch.DueDate)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var assigneeID *primitive.ObjectID
+	if patch.AssigneeID != nil {
+		var err error
+		assigneeID, err = tu.resolveAssignee(ctx, *patch.AssigneeID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tags, err := normalizeTags(patch.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	estimate, err := validateEstimate(patch.EstimateMinutes)
+	if err != nil {
+		return nil, err
+	}
+
+	existingTask, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkExpectedVersion(existingTask, patch.Version); err != nil {
+		return nil, err
+	}
+
+	if patch.Status != nil {
+		if err := tu.checkStatusTransition(existingTask.Status, *patch.Status); err != nil {
+			return nil, err
+		}
+	}
+	if patch.DueDate != nil {
+		if err := tu.checkNewDueDate(existingTask, dueDate); err != nil {
+			return nil, err
+		}
+	}
+
+	before := *existingTask
+	if patch.Title != nil {
+		existingTask.Title = *patch.Title
+	}
+	if patch.Description != nil {
+		existingTask.Description = *patch.Description
+	}
+	if patch.DueDate != nil {
+		existingTask.DueDate = dueDate
+	}
+	if patch.Status != nil {
+		existingTask.Status = *patch.Status
+	}
+	if patch.Priority != nil {
+		existingTask.Priority = *patch.Priority
+	}
+	if patch.AssigneeID != nil {
+		existingTask.AssigneeID = assigneeID
+	}
+	if patch.Tags != nil {
+		existingTask.Tags = tags
+	}
+	if patch.EstimateMinutes != nil {
+		existingTask.EstimateMinutes = estimate
+	}
+	if patch.Recurrence != nil {
+		existingTask.Recurrence = *patch.Recurrence
+		existingTask.ApplyDefaults()
+	}
+
+	// The merged task must still satisfy the recurrence rules
+	if existingTask.IsRecurring() && existingTask.DueDate.IsZero() {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "recurring tasks require a due date")
+	}
+
+	return tu.saveTask(ctx, caller, id, before, existingTask)
+}
+
+// validateEstimate checks an estimate in minutes. Zero means no estimate and is returned as nil.
+func validateEstimate(estimate *int) (*int, error) {
+	if estimate == nil || *estimate == 0 {
+		return nil, nil
+	}
+	if *estimate < 0 {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "estimate minutes must not be negative")
+	}
+	if *estimate > MaxEstimateMinutes {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "estimate minutes must be at most %d", MaxEstimateMinutes)
+	}
+	return estimate, nil
+}
+
+// checkStatusTransition rejects status changes missing from the transition table
+// unless enforcement has been disabled
+func (tu *TaskUsecase) checkStatusTransition(from, to string) error {
+	if tu.enforceTransitions && !Domain.CanTransitionStatus(from, to) {
+		return fmt.Errorf("%w from %s to %s", Domain.ErrInvalidStatusTransition, from, to)
+	}
+	return nil
+}
+
+// checkExpectedVersion rejects an update based on a version of the task the client no longer has.
+// Without an expected version the repository still guards against changes made since the task was read.
+func checkExpectedVersion(task *Domain.Task, expected *int) error {
+	if expected != nil && *expected != task.Version {
+		return Domain.ErrVersionConflict
+	}
+	return nil
+}
+
+// saveTask persists an updated task, audits and publishes what changed since before and returns the
+// stored version. Completing a recurring task spawns its next occurrence in the same write.
+func (tu *TaskUsecase) saveTask(ctx context.Context, caller Domain.Caller, id string, before Domain.Task, task *Domain.Task) (*Domain.Task, error) {
+	// A new due date earns the task a fresh reminder
+	if !task.DueDate.Equal(before.DueDate) {
+		task.LastNotifiedAt = nil
+	}
+
+	var err error
+	if task.IsRecurring() && before.Status != Domain.StatusCompleted && task.Status == Domain.StatusCompleted && task.NextOccurrenceID == nil {
+		err = tu.taskRepo.CompleteRecurring(ctx, id, task, task.NextOccurrence())
+	} else {
+		err = tu.taskRepo.Update(ctx, id, task)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	diff := taskAuditDiff(&before, task)
+	if diff != "" {
+		recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionUpdate, Domain.AuditEntityTask, id, diff)
+		tu.recordRevision(ctx, caller, &before, task)
+	}
+	if before.Status != task.Status {
+		tu.countStatusChange(caller, &before, task)
+	}
+
+	stored, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if diff != "" {
+		tu.publish(ctx, caller, Domain.EventTaskUpdated, stored)
+	}
+	return stored, nil
+}
+
+// recordRevision keeps the previous values of the fields an update changed.
+// Like auditing it is best-effort, since the update itself has already been saved.
+func (tu *TaskUsecase) recordRevision(ctx context.Context, caller Domain.Caller, before, after *Domain.Task) {
+	revision := &Domain.TaskRevision{
+		TaskID:   before.ID,
+		EditorID: caller.UserID,
+		Previous: after.PreviousValues(before),
+	}
+	if err := tu.revisionRepo.Create(ctx, revision); err != nil {
+		tu.logger.WarnContext(ctx, "failed to write task revision", "task_id", before.ID.Hex(), "error", err)
+	}
+}
+
+// GetTaskRevisions returns one page of the revisions of a task the caller can see, newest first
+func (tu *TaskUsecase) GetTaskRevisions(ctx context.Context, caller Domain.Caller, id string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error) {
+	if pagination.Limit < 0 || pagination.Offset < 0 {
+		return nil, 0, Domain.ErrInvalidPagination
+	}
+	if pagination.Sort != "" {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "revisions are always sorted newest first")
+	}
+
+	if _, err := tu.visibleTask(ctx, caller, id); err != nil {
+		return nil, 0, err
+	}
+
+	return tu.revisionRepo.GetByTaskID(ctx, id, pagination)
+}
+
+// taskAuditDiff summarizes the fields an update changed, spelling out status moves
+func taskAuditDiff(before, after *Domain.Task) string {
+	fields := after.ChangedFields(before)
+	for i, field := range fields {
+		if field == "status" {
+			fields[i] = fmt.Sprintf("status: %s -> %s", before.Status, after.Status)
+		}
+	}
+	return strings.Join(fields, ", ")
+}
+
+// recordCreated audits and publishes the creation of a task
+func (tu *TaskUsecase) recordCreated(ctx context.Context, caller Domain.Caller, task *Domain.Task) {
+	recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionCreate, Domain.AuditEntityTask, task.ID.Hex(), fmt.Sprintf("title %q", task.Title))
+	tu.publish(ctx, caller, Domain.EventTaskCreated, task)
+	tu.metrics.Increment(Infrastructure.MetricTasksCreated, map[string]string{Infrastructure.MetricLabelRole: caller.Role})
+}
+
+// countStatusChange counts a saved status change and, when it completes the task, who completed it
+// and how long the task took since it was created
+func (tu *TaskUsecase) countStatusChange(caller Domain.Caller, before, task *Domain.Task) {
+	tu.metrics.Increment(Infrastructure.MetricTaskStatusTransitions, map[string]string{
+		Infrastructure.MetricLabelFrom: before.Status,
+		Infrastructure.MetricLabelTo:   task.Status,
+	})
+	if task.Status != Domain.StatusCompleted {
+		return
+	}
+
+	tu.metrics.Increment(Infrastructure.MetricTasksCompleted, map[string]string{Infrastructure.MetricLabelUserID: caller.UserID})
+	if !task.CreatedAt.IsZero() {
+		tu.metrics.Observe(Infrastructure.MetricTaskCompletionSeconds, time.Since(task.CreatedAt).Seconds(), nil)
+	}
+}
+
+// publish announces a change to tasks that has already been saved
+func (tu *TaskUsecase) publish(ctx context.Context, caller Domain.Caller, eventType string, data interface{}) {
+	tu.events.Publish(ctx, Domain.NewEvent(eventType, caller.UserID, data))
+}
+
+// DeleteTask soft deletes a task and its comments and attachments by the task ID.
+// Tasks that still have active subtasks are rejected rather than cascaded.
+func (tu *TaskUsecase) DeleteTask(ctx context.Context, caller Domain.Caller, id string) error {
+	// The task is read first so the deletion event can reach the users who could see it
+	task, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// Parents are never deleted out from under their subtasks
+	subtasks, err := tu.taskRepo.GetByParentID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(subtasks) > 0 {
+		return Domain.ErrTaskHasSubtasks
+	}
+
+	err = tu.taskRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionDelete, Domain.AuditEntityTask, id, "")
+	tu.publish(ctx, caller, Domain.EventTaskDeleted, Domain.DeletedTaskEvent{ID: id, Task: task})
+	tu.metrics.Increment(Infrastructure.MetricTasksDeleted, map[string]string{Infrastructure.MetricLabelRole: caller.Role})
+
+	// Comments, attachments and work logs follow their task into the trash; attachment files stay until it is purged
+	if err := tu.commentRepo.DeleteByTaskID(ctx, id); err != nil {
+		return err
+	}
+	if err := tu.attachmentRepo.DeleteByTaskID(ctx, id); err != nil {
+		return err
+	}
+	return tu.workLogRepo.DeleteByTaskID(ctx, id)
+}
+
+// GetDeletedTasks returns a page of soft-deleted tasks
+func (tu *TaskUsecase) GetDeletedTasks(ctx context.Context, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	if err := validatePagination(pagination); err != nil {
+		return nil, 0, err
+	}
+
+	// Archived tasks are listed too, or they could not be found in the trash at all
+	return tu.taskRepo.GetAll(ctx, Domain.TaskFilter{OnlyDeleted: true, IncludeArchived: true}, pagination)
+}
+
+// RestoreTask brings a soft-deleted task and its comments, attachments and work logs back,
+// then records and announces the task as updated
+func (tu *TaskUsecase) RestoreTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	// A task that can still be fetched has not been deleted
+	_, err := tu.taskRepo.GetByID(ctx, id)
+	if err == nil {
+		return nil, Domain.ErrTaskNotDeleted
+	}
+	if !errors.Is(err, Domain.ErrTaskNotFound) {
+		return nil, err
+	}
+
+	err = tu.taskRepo.Restore(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.commentRepo.RestoreByTaskID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.attachmentRepo.RestoreByTaskID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tu.workLogRepo.RestoreByTaskID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionRestore, Domain.AuditEntityTask, id, "")
+	tu.publish(ctx, caller, Domain.EventTaskUpdated, task)
+
+	return task, nil
+}
+
+// ArchiveTask hides a task from listings without deleting it. Only completed tasks are archived
+// unless the caller is an admin or force is set.
+func (tu *TaskUsecase) ArchiveTask(ctx context.Context, caller Domain.Caller, id string, force bool) (*Domain.Task, error) {
+	task, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task.Archived {
+		return nil, Domain.ErrTaskArchived
+	}
+	if task.Status != Domain.StatusCompleted && caller.Role != Domain.RoleAdmin && !force {
+		return nil, Domain.ErrTaskNotCompleted
+	}
+
+	return tu.setArchived(ctx, caller, id, true)
+}
+
+// UnarchiveTask brings an archived task back into listings
+func (tu *TaskUsecase) UnarchiveTask(ctx context.Context, caller Domain.Caller, id string) (*Domain.Task, error) {
+	task, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !task.Archived {
+		return nil, Domain.ErrTaskNotArchived
+	}
+
+	return tu.setArchived(ctx, caller, id, false)
+}
+
+// setArchived saves the archived flag, then records and announces the change
+func (tu *TaskUsecase) setArchived(ctx context.Context, caller Domain.Caller, id string, archived bool) (*Domain.Task, error) {
+	if err := tu.taskRepo.SetArchived(ctx, id, archived); err != nil {
+		return nil, err
+	}
+
+	task, err := tu.taskRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	action := Domain.AuditActionArchive
+	if !archived {
+		action = Domain.AuditActionUnarchive
+	}
+	recordAudit(ctx, tu.logger, tu.auditRepo, caller, action, Domain.AuditEntityTask, id, "")
+	tu.publish(ctx, caller, Domain.EventTaskUpdated, task)
+
+	return task, nil
+}
+
+// ArchiveCompletedTasks archives every completed task last updated before completedBefore.
+// Tasks keep no completion time, so their last update stands in for it.
+func (tu *TaskUsecase) ArchiveCompletedTasks(ctx context.Context, caller Domain.Caller, completedBefore time.Time) (*Domain.BulkResult, error) {
+	if completedBefore.IsZero() {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "completed before is required")
+	}
+
+	archived, err := tu.taskRepo.ArchiveCompleted(ctx, completedBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	if archived > 0 {
+		recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionBulkArchive, Domain.AuditEntityTask, "", fmt.Sprintf("archived %d tasks completed before %s", archived, completedBefore.Format(time.RFC3339)))
+	}
+
+	return &Domain.BulkResult{MatchedCount: archived, ModifiedCount: archived}, nil
+}
+
+// PurgeDeletedTasks permanently removes tasks, and their comments and attachments, soft deleted more than olderThanDays days ago
+func (tu *TaskUsecase) PurgeDeletedTasks(ctx context.Context, olderThanDays int) (int64, error) {
+	if olderThanDays < 0 {
+		return 0, Domain.NewError(Domain.ErrInvalidInput, "older than days must not be negative")
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -olderThanDays)
+
+	purged, err := tu.taskRepo.Purge(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+
+	// Comments, attachments and work logs were trashed together with their task, so the same cutoff applies
+	if _, err = tu.commentRepo.Purge(ctx, cutoff); err != nil {
+		return 0, err
+	}
+	if _, err = tu.workLogRepo.Purge(ctx, cutoff); err != nil {
+		return 0, err
+	}
+	attachments, err := tu.attachmentRepo.Purge(ctx, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	deleteAttachmentBlobs(ctx, tu.logger, tu.blobs, attachments)
+
+	return purged, nil
+}
+
+// PurgeTasksByStatus permanently removes tasks with the given status, in the trash or not, and their
+// comments, attachments and work logs, when they were last updated more than olderThan ago. Parents of subtasks that stay are kept.
+func (tu *TaskUsecase) PurgeTasksByStatus(ctx context.Context, caller Domain.Caller, status string, olderThan time.Duration) (int64, error) {
+	if !Domain.IsValidStatus(status) {
+		return 0, Domain.ErrInvalidStatus
+	}
+	if olderThan <= 0 {
+		return 0, Domain.NewError(Domain.ErrInvalidInput, "older than must be positive")
+	}
+
+	ids, err := tu.taskRepo.PurgeByStatus(ctx, status, time.Now().Add(-olderThan))
+	if err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	if _, err := tu.commentRepo.PurgeByTaskIDs(ctx, ids); err != nil {
+		return 0, err
+	}
+	if _, err := tu.workLogRepo.PurgeByTaskIDs(ctx, ids); err != nil {
+		return 0, err
+	}
+	attachments, err := tu.attachmentRepo.PurgeByTaskIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+	deleteAttachmentBlobs(ctx, tu.logger, tu.blobs, attachments)
+
+	recordAudit(ctx, tu.logger, tu.auditRepo, caller, Domain.AuditActionBulkDelete, Domain.AuditEntityTask, "", fmt.Sprintf("purged %d %s tasks not updated for %s", len(ids), status, olderThan))
+	return int64(len(ids)), nil
+}
+
+// GetTags returns the distinct tags in use.
+// Regular users only see tags on the tasks they created.
+func (tu *TaskUsecase) GetTags(ctx context.Context, caller Domain.Caller) ([]string, error) {
+	var filter Domain.TaskFilter
+
+	if !caller.Can(Domain.PermissionTasksReadAll) {
+		ownerID, err := primitive.ObjectIDFromHex(caller.UserID)
+		if err != nil {
+			return nil, Domain.ErrInvalidUserID
+		}
+		filter.CreatedBy = ownerID
+	}
+
+	return tu.taskRepo.GetTags(ctx, filter)
+}
+
+// GetStats returns task statistics.
+// Admins and managers get statistics over every task; regular users only over tasks they created.
+func (tu *TaskUsecase) GetStats(ctx context.Context, caller Domain.Caller) (*Domain.TaskStats, error) {
+	var filter Domain.TaskFilter
+
+	if !caller.Can(Domain.PermissionTasksReadAll) {
+		ownerID, err := primitive.ObjectIDFromHex(caller.UserID)
+		if err != nil {
+			return nil, Domain.ErrInvalidUserID
+		}
+		filter.CreatedBy = ownerID
+	}
+
+	return tu.taskRepo.GetStats(ctx, filter, time.Now())
+}
+
+// resolveAssignee checks that the referenced user exists.
+// An empty ID means the task is unassigned.
+func (tu *TaskUsecase) resolveAssignee(ctx context.Context, assigneeID string) (*primitive.ObjectID, error) {
+	if assigneeID == "" {
+		return nil, nil
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(assigneeID)
+	if err != nil {
+		return nil, Domain.ErrInvalidAssigneeID
+	}
+
+	_, err = tu.userRepo.GetByID(ctx, assigneeID)
+	if err != nil {
+		if errors.Is(err, Domain.ErrUserNotFound) {
+			return nil, Domain.ErrAssigneeNotFound
+		}
+		return nil, err
+	}
+
+	return &objectID, nil
+}
+
+// resolveParentTask checks that the referenced parent exists and is not itself a subtask,
+// so nesting stays one level deep. An empty ID means the task has no parent.
+func (tu *TaskUsecase) resolveParentTask(ctx context.Context, parentTaskID string) (*primitive.ObjectID, error) {
+	if parentTaskID == "" {
+		return nil, nil
+	}
+
+	parent, err := tu.taskRepo.GetByID(ctx, parentTaskID)
+	if err != nil {
+		switch {
+		case errors.Is(err, Domain.ErrTaskNotFound):
+			return nil, Domain.ErrParentTaskNotFound
+		case errors.Is(err, Domain.ErrInvalidTaskID):
+			return nil, Domain.ErrInvalidParentTaskID
+		}
+		return nil, err
+	}
+
+	if parent.IsSubtask() {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "parent task is itself a subtask, only one level of nesting is supported")
+	}
+
+	return &parent.ID, nil
+}
+
+// normalizeTags trims, lowercases and de-duplicates tags, keeping their first-seen order.
+// A nil slice stays nil so callers can tell an omitted field from an empty one.
+func normalizeTags(tags []string) ([]string, error) {
+	if tags == nil {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, Domain.NewError(Domain.ErrInvalidInput, "tags cannot be empty")
+		}
+		if seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+
+	if len(normalized) > MaxTaskTags {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "a task can have at most %d tags", MaxTaskTags)
+	}
+
+	return normalized, nil
+}
+
+// DefaultLocation returns the timezone date-only due dates are interpreted in:
+// TASKS_DEFAULT_TIMEZONE (an IANA name), falling back to UTC
+func DefaultLocation() *time.Location {
+	if value := os.Getenv("TASKS_DEFAULT_TIMEZONE"); value != "" {
+		if location, err := time.LoadLocation(value); err == nil {
+			return location
+		}
+	}
+	return time.UTC
+}
+
+// parseDueDate accepts either a date or an RFC3339 timestamp and returns it in UTC.
+// A date-only value is due at the last second of that day in the default timezone.
+// An empty value means no due date.
+func (tu *TaskUsecase) parseDueDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if dueDate, err := time.Parse(DueDateTimeLayout, value); err == nil {
+		return dueDate.UTC(), nil
+	}
+
+	day, err := time.ParseInLocation(DueDateLayout, value, tu.defaultLocation)
+	if err != nil {
+		return time.Time{}, Domain.ErrInvalidDueDate
+	}
+	endOfDay := day.AddDate(0, 0, 1).Add(-time.Second)
+	return endOfDay.UTC(), nil
+}
+
+// checkNewDueDate applies the due date rules to a due date an update gives a task. Keeping the
+// current due date is always allowed, so a task that is already overdue can still be edited.
+func (tu *TaskUsecase) checkNewDueDate(task *Domain.Task, dueDate time.Time) error {
+	if dueDate.Equal(task.DueDate) {
+		return nil
+	}
+	return tu.dueDateRules.check(dueDate, time.Now())
+}
+
+// validateRecurrence defaults an empty recurrence to none and
+// rejects recurring tasks that have no due date to advance from
+func validateRecurrence(recurrence string, dueDate time.Time) (string, error) {
+	if recurrence == "" {
+		recurrence = Domain.RecurrenceNone
+	}
+	if !Domain.IsValidRecurrence(recurrence) {
+		return "", Domain.ErrInvalidRecurrence
+	}
+	if recurrence != Domain.RecurrenceNone && dueDate.IsZero() {
+		return "", Domain.NewError(Domain.ErrInvalidInput, "recurring tasks require a due date")
+	}
+	return recurrence, nil
+}
+
+// validatePagination rejects negative limit or offset values, unknown sort orders and unknown fields
+func validatePagination(pagination Domain.Pagination) error {
+	if pagination.Limit < 0 || pagination.Offset < 0 {
+		return Domain.ErrInvalidPagination
+	}
+	if pagination.Sort != "" && pagination.Sort != Domain.SortPriority {
+		return Domain.NewError(Domain.ErrInvalidInput, "invalid sort, must be one of: priority")
+	}
+	for _, field := range pagination.Fields {
+		if !Domain.IsValidTaskField(field) {
+			return Domain.ErrInvalidTaskFields
+		}
+	}
+	return nil
+}
@@ -1,15 +1,20 @@
 package Usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
 )
 
 // MockTaskRepository is a mock implementation of TaskRepositoryInterface
@@ -17,12 +22,12 @@ type MockTaskRepository struct {
 	mock.Mock
 }
 
-func (m *MockTaskRepository) GetAll() ([]*Domain.Task, error) {
-	args := m.Called()
-	return args.Get(0).([]*Domain.Task), args.Error(1)
+func (m *MockTaskRepository) GetAll(ctx context.Context, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
 }
 
-func (m *MockTaskRepository) GetByID(id string) (*Domain.Task, error) {
+func (m *MockTaskRepository) GetByID(ctx context.Context, id string) (*Domain.Task, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,27 +35,217 @@ func (m *MockTaskRepository) GetByID(id string) (*Domain.Task, error) {
 	return args.Get(0).(*Domain.Task), args.Error(1)
 }
 
-func (m *MockTaskRepository) Create(task *Domain.Task) error {
+func (m *MockTaskRepository) GetByIDWithFields(ctx context.Context, id string, fields []string) (*Domain.Task, error) {
+	args := m.Called(id, fields)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountTasks(ctx context.Context, filter Domain.TaskFilter) (int64, error) {
+	args := m.Called(filter)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) Search(ctx context.Context, query string, filter Domain.TaskFilter, pagination Domain.Pagination) ([]*Domain.Task, int64, error) {
+	args := m.Called(query, filter, pagination)
+	return args.Get(0).([]*Domain.Task), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTaskRepository) Stream(ctx context.Context, filter Domain.TaskFilter, fn func(task *Domain.Task) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) ExistsByTitleAndDueDate(ctx context.Context, title string, dueDate time.Time) (bool, error) {
+	args := m.Called(title, dueDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindOpenByTitle(ctx context.Context, createdBy primitive.ObjectID, title string) (*Domain.Task, error) {
+	args := m.Called(createdBy, title)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) Create(ctx context.Context, task *Domain.Task) error {
 	args := m.Called(task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) Update(id string, task *Domain.Task) error {
+func (m *MockTaskRepository) CreateMany(ctx context.Context, tasks []*Domain.Task) error {
+	args := m.Called(tasks)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) UpdateStatusMany(ctx context.Context, ids []primitive.ObjectID, status string, fromStatuses []string) (int64, int64, []Domain.TaskStatusChange, error) {
+	args := m.Called(ids, status, fromStatuses)
+	changes, _ := args.Get(2).([]Domain.TaskStatusChange)
+	return args.Get(0).(int64), args.Get(1).(int64), changes, args.Error(3)
+}
+
+func (m *MockTaskRepository) DeleteByStatus(ctx context.Context, status string) (int64, int64, []primitive.ObjectID, error) {
+	args := m.Called(status)
+	ids, _ := args.Get(2).([]primitive.ObjectID)
+	return args.Get(0).(int64), args.Get(1).(int64), ids, args.Error(3)
+}
+
+func (m *MockTaskRepository) Update(ctx context.Context, id string, task *Domain.Task) error {
 	args := m.Called(id, task)
 	return args.Error(0)
 }
 
-func (m *MockTaskRepository) Delete(id string) error {
+func (m *MockTaskRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) Restore(ctx context.Context, id string) error {
 	args := m.Called(id)
 	return args.Error(0)
 }
 
+func (m *MockTaskRepository) SetArchived(ctx context.Context, id string, archived bool) error {
+	args := m.Called(id, archived)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) ArchiveCompleted(ctx context.Context, completedBefore time.Time) (int64, error) {
+	args := m.Called(completedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) PurgeByStatus(ctx context.Context, status string, updatedBefore time.Time) ([]primitive.ObjectID, error) {
+	args := m.Called(status, updatedBefore)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]primitive.ObjectID), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetByParentID(ctx context.Context, parentID string) ([]*Domain.Task, error) {
+	args := m.Called(parentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetDueForReminder(ctx context.Context, dueBefore, now time.Time, limit int64) ([]*Domain.Task, error) {
+	args := m.Called(dueBefore, now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) MarkNotified(ctx context.Context, id primitive.ObjectID, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) GetStats(ctx context.Context, filter Domain.TaskFilter, now time.Time) (*Domain.TaskStats, error) {
+	args := m.Called(filter, now)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.TaskStats), args.Error(1)
+}
+
+func (m *MockTaskRepository) CompleteRecurring(ctx context.Context, id string, task *Domain.Task, next *Domain.Task) error {
+	args := m.Called(id, task, next)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) ReassignCreator(ctx context.Context, fromUserID, toUserID primitive.ObjectID) (int64, error) {
+	args := m.Called(fromUserID, toUserID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) UnassignUser(ctx context.Context, userID primitive.ObjectID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockTaskRepository) GetTags(ctx context.Context, filter Domain.TaskFilter) ([]string, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]string), args.Error(1)
+}
+
+// Callers used by task usecase tests
+// MockRevisionRepository is a mock implementation of RevisionRepositoryInterface
+type MockRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevisionRepository) Create(ctx context.Context, revision *Domain.TaskRevision) error {
+	args := m.Called(revision)
+	return args.Error(0)
+}
+
+func (m *MockRevisionRepository) GetByTaskID(ctx context.Context, taskID string, pagination Domain.Pagination) ([]*Domain.TaskRevision, int64, error) {
+	args := m.Called(taskID, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.TaskRevision), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRevisionRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// newMockRevisionRepository returns a revision repository that accepts any revision,
+// for tests that are not about revision history
+func newMockRevisionRepository() *MockRevisionRepository {
+	mockRevisionRepo := new(MockRevisionRepository)
+	mockRevisionRepo.On("Create", mock.Anything).Return(nil).Maybe()
+	return mockRevisionRepo
+}
+
+// MockBusinessMetrics is a mock implementation of BusinessMetrics
+type MockBusinessMetrics struct {
+	mock.Mock
+}
+
+func (m *MockBusinessMetrics) Increment(name string, labels map[string]string) {
+	m.Called(name, labels)
+}
+
+func (m *MockBusinessMetrics) Observe(name string, value float64, labels map[string]string) {
+	m.Called(name, value, labels)
+}
+
+var (
+	adminCaller = Domain.Caller{UserID: "507f1f77bcf86cd799439011", Role: Domain.RoleAdmin}
+	userCaller  = Domain.Caller{UserID: "507f1f77bcf86cd799439022", Role: Domain.RoleUser}
+
+	managerCaller = Domain.Caller{UserID: "507f1f77bcf86cd799439033", Role: Domain.RoleManager}
+)
+
 func TestTaskUsecase_GetAllTasks(t *testing.T) {
 	t.Run("Success - return all tasks", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		expectedTasks := []*Domain.Task{
 			{
 				ID:          primitive.NewObjectID(),
@@ -65,375 +260,3434 @@ func TestTaskUsecase_GetAllTasks(t *testing.T) {
 				Status:      Domain.StatusCompleted,
 			},
 		}
-		mockRepo.On("GetAll").Return(expectedTasks, nil)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, Domain.Pagination{}).Return(expectedTasks, int64(2), nil)
 
 		// Act
-		tasks, err := taskUsecase.GetAllTasks()
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTasks, tasks)
 		assert.Len(t, tasks, 2)
+		assert.Equal(t, int64(2), total)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Success - return empty list", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		expectedTasks := []*Domain.Task{}
-		mockRepo.On("GetAll").Return(expectedTasks, nil)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, Domain.Pagination{}).Return(expectedTasks, int64(0), nil)
 
 		// Act
-		tasks, err := taskUsecase.GetAllTasks()
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, expectedTasks, tasks)
 		assert.Len(t, tasks, 0)
+		assert.Equal(t, int64(0), total)
 		mockRepo.AssertExpectations(t)
 	})
 
 	t.Run("Error - repository error", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		expectedError := errors.New("database connection error")
-		mockRepo.On("GetAll").Return([]*Domain.Task(nil), expectedError)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, Domain.Pagination{}).Return([]*Domain.Task(nil), int64(0), expectedError)
 
 		// Act
-		tasks, err := taskUsecase.GetAllTasks()
+		tasks, _, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, tasks)
 		mockRepo.AssertExpectations(t)
 	})
-}
 
-func TestTaskUsecase_GetTaskByID(t *testing.T) {
-	t.Run("Success - task found", func(t *testing.T) {
+	t.Run("Success - pagination passed to repository", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskID := primitive.NewObjectID().Hex()
-		expectedTask := &Domain.Task{
-			ID:          primitive.NewObjectID(),
-			Title:       "Test Task",
-			Description: "Test Description",
-			Status:      Domain.StatusInProgress,
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		pagination := Domain.Pagination{Limit: 10, Offset: 20}
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Task 21", Status: Domain.StatusPending},
 		}
-		mockRepo.On("GetByID", taskID).Return(expectedTask, nil)
+		mockRepo.On("GetAll", Domain.TaskFilter{}, pagination).Return(expectedTasks, int64(21), nil)
 
 		// Act
-		task, err := taskUsecase.GetTaskByID(taskID)
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{}, pagination)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, expectedTask, task)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(21), total)
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error - task not found", func(t *testing.T) {
+	t.Run("Error - negative pagination", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskID := primitive.NewObjectID().Hex()
-		expectedError := errors.New("task not found")
-		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
 
 		// Act
-		task, err := taskUsecase.GetTaskByID(taskID)
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{}, Domain.Pagination{Limit: -1})
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, task)
-		mockRepo.AssertExpectations(t)
+		assert.ErrorIs(t, err, Domain.ErrInvalidPagination)
+		assert.Nil(t, tasks)
+		assert.Equal(t, int64(0), total)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - invalid ID format", func(t *testing.T) {
+	t.Run("Success - filter passed to repository", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		invalidID := "invalid-id"
-		expectedError := errors.New("invalid task ID format")
-		mockRepo.On("GetByID", invalidID).Return(nil, expectedError)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		filter := Domain.TaskFilter{
+			Status:    Domain.StatusPending,
+			DueAfter:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			DueBefore: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC),
+		}
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Due this month", Status: Domain.StatusPending},
+		}
+		mockRepo.On("GetAll", filter, Domain.Pagination{}).Return(expectedTasks, int64(1), nil)
 
 		// Act
-		task, err := taskUsecase.GetTaskByID(invalidID)
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, filter, Domain.Pagination{})
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, task)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(1), total)
 		mockRepo.AssertExpectations(t)
 	})
-}
 
-func TestTaskUsecase_CreateTask(t *testing.T) {
-	t.Run("Success - create task with all fields", func(t *testing.T) {
+	t.Run("Error - invalid status filter", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskReq := Domain.TaskRequest{
-			Title:       "New Task",
-			Description: "New Description",
-			DueDate:     "2024-12-31",
-			Status:      Domain.StatusPending,
-		}
-		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
 
 		// Act
-		task, err := taskUsecase.CreateTask(taskReq)
+		tasks, _, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{Status: "done"}, Domain.Pagination{})
 
 		// Assert
-		assert.NoError(t, err)
-		assert.NotNil(t, task)
-		assert.Equal(t, taskReq.Title, task.Title)
-		assert.Equal(t, taskReq.Description, task.Description)
-		assert.Equal(t, taskReq.Status, task.Status)
-		
-		expectedDate, _ := time.Parse("2006-01-02", taskReq.DueDate)
-		assert.Equal(t, expectedDate, task.DueDate)
-		mockRepo.AssertExpectations(t)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidStatus)
+		assert.Nil(t, tasks)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Success - create task without due date", func(t *testing.T) {
+	t.Run("Error - invalid priority filter", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskReq := Domain.TaskRequest{
-			Title:       "Task without due date",
-			Description: "Description",
-			Status:      Domain.StatusInProgress,
-		}
-		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
 
 		// Act
-		task, err := taskUsecase.CreateTask(taskReq)
+		tasks, _, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{Priority: "critical"}, Domain.Pagination{})
 
 		// Assert
-		assert.NoError(t, err)
-		assert.NotNil(t, task)
-		assert.Equal(t, taskReq.Title, task.Title)
-		assert.True(t, task.DueDate.IsZero())
-		mockRepo.AssertExpectations(t)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidPriority)
+		assert.Nil(t, tasks)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - invalid status", func(t *testing.T) {
+	t.Run("Error - unknown sort", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskReq := Domain.TaskRequest{
-			Title:  "Task with invalid status",
-			Status: "invalid_status",
-		}
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
 
 		// Act
-		task, err := taskUsecase.CreateTask(taskReq)
+		tasks, _, err := taskUsecase.GetAllTasks(context.Background(), adminCaller, Domain.TaskFilter{}, Domain.Pagination{Sort: "title"})
 
 		// Assert
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid status")
-		assert.Nil(t, task)
-		// No repository call expected for validation errors
+		assert.Equal(t, "invalid sort, must be one of: priority", err.Error())
+		assert.Nil(t, tasks)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - invalid due date format", func(t *testing.T) {
+	t.Run("Success - regular user scoped to own tasks", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskReq := Domain.TaskRequest{
-			Title:   "Task with invalid date",
-			DueDate: "invalid-date",
-			Status:  Domain.StatusPending,
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		expectedFilter := Domain.TaskFilter{Status: Domain.StatusPending, CreatedBy: ownerID}
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Mine", Status: Domain.StatusPending, CreatedBy: ownerID},
 		}
+		mockRepo.On("GetAll", expectedFilter, Domain.Pagination{}).Return(expectedTasks, int64(1), nil)
 
 		// Act
-		task, err := taskUsecase.CreateTask(taskReq)
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), userCaller, Domain.TaskFilter{Status: Domain.StatusPending}, Domain.Pagination{})
 
 		// Assert
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid due date format")
-		assert.Nil(t, task)
-		// No repository call expected for validation errors
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(1), total)
+		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error - repository error", func(t *testing.T) {
+	t.Run("Success - manager sees every task", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskReq := Domain.TaskRequest{
-			Title:  "Task",
-			Status: Domain.StatusPending,
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		expectedFilter := Domain.TaskFilter{Status: Domain.StatusPending}
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Someone else's", Status: Domain.StatusPending, CreatedBy: primitive.NewObjectID()},
 		}
-		expectedError := errors.New("database error")
-		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(expectedError)
+		mockRepo.On("GetAll", expectedFilter, Domain.Pagination{}).Return(expectedTasks, int64(1), nil)
 
 		// Act
-		task, err := taskUsecase.CreateTask(taskReq)
+		tasks, total, err := taskUsecase.GetAllTasks(context.Background(), managerCaller, Domain.TaskFilter{Status: Domain.StatusPending}, Domain.Pagination{})
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, task)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(1), total)
 		mockRepo.AssertExpectations(t)
 	})
-}
 
-func TestTaskUsecase_UpdateTask(t *testing.T) {
-	t.Run("Success - update existing task", func(t *testing.T) {
+	t.Run("Error - regular user with invalid ID", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
-		taskID := primitive.NewObjectID().Hex()
-		existingTask := &Domain.Task{
-			ID:          primitive.NewObjectID(),
-			Title:       "Old Title",
-			Description: "Old Description",
-			Status:      Domain.StatusPending,
-		}
-		updatedTask := &Domain.Task{
-			ID:          existingTask.ID,
-			Title:       "Updated Title",
-			Description: "Updated Description",
-			Status:      Domain.StatusCompleted,
-			DueDate:     time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
-		}
-		taskReq := Domain.TaskRequest{
-			Title:       "Updated Title",
-			Description: "Updated Description",
-			DueDate:     "2024-12-31",
-			Status:      Domain.StatusCompleted,
-		}
-
-		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
-		mockRepo.On("Update", taskID, mock.AnythingOfType("*Domain.Task")).Return(nil).Once()
-		mockRepo.On("GetByID", taskID).Return(updatedTask, nil).Once()
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
 
 		// Act
-		task, err := taskUsecase.UpdateTask(taskID, taskReq)
+		tasks, _, err := taskUsecase.GetAllTasks(context.Background(), Domain.Caller{UserID: "not-an-id", Role: Domain.RoleUser}, Domain.TaskFilter{}, Domain.Pagination{})
 
 		// Assert
-		assert.NoError(t, err)
-		assert.NotNil(t, task)
-		assert.Equal(t, updatedTask.Title, task.Title)
-		assert.Equal(t, updatedTask.Status, task.Status)
-		mockRepo.AssertExpectations(t)
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+		assert.Nil(t, tasks)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
 	})
+}
 
-	t.Run("Error - task not found", func(t *testing.T) {
+func TestTaskUsecase_GetTaskByIDWithFields(t *testing.T) {
+	t.Run("Success - selected fields are loaded", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		taskID := primitive.NewObjectID().Hex()
-		taskReq := Domain.TaskRequest{
-			Title:  "Updated Title",
-			Status: Domain.StatusCompleted,
-		}
-		expectedError := errors.New("task not found")
-		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
+		fields := []string{"id", "title"}
+		expectedTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Test Task"}
+		mockRepo.On("GetByIDWithFields", taskID, fields).Return(expectedTask, nil)
 
 		// Act
-		task, err := taskUsecase.UpdateTask(taskID, taskReq)
+		task, err := taskUsecase.GetTaskByIDWithFields(context.Background(), adminCaller, taskID, fields)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, task)
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTask, task)
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid status", func(t *testing.T) {
+	t.Run("Error - task of another user is hidden", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		taskID := primitive.NewObjectID().Hex()
-		existingTask := &Domain.Task{
-			ID:     primitive.NewObjectID(),
-			Title:  "Existing Task",
-			Status: Domain.StatusPending,
-		}
-		taskReq := Domain.TaskRequest{
-			Title:  "Updated Title",
-			Status: "invalid_status",
+		otherTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Theirs", CreatedBy: primitive.NewObjectID()}
+		mockRepo.On("GetByIDWithFields", taskID, []string{"title"}).Return(otherTask, nil)
+
+		// Act
+		task, err := taskUsecase.GetTaskByIDWithFields(context.Background(), userCaller, taskID, []string{"title"})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, task)
+	})
+
+	t.Run("Error - unknown field", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		task, err := taskUsecase.GetTaskByIDWithFields(context.Background(), adminCaller, primitive.NewObjectID().Hex(), []string{"secret"})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskFields)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "GetByIDWithFields", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_GetTaskByID(t *testing.T) {
+	t.Run("Success - task found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedTask := &Domain.Task{
+			ID:          primitive.NewObjectID(),
+			Title:       "Test Task",
+			Description: "Test Description",
+			Status:      Domain.StatusInProgress,
 		}
-		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("GetByID", taskID).Return(expectedTask, nil)
+
+		// Act
+		task, err := taskUsecase.GetTaskByID(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTask, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - total logged minutes attached", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), mockWorkLogRepo, newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Test Task"}, nil)
+		mockWorkLogRepo.On("TotalMinutesByTaskID", taskID).Return(135, nil)
+
+		// Act
+		task, err := taskUsecase.GetTaskByID(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 135, *task.TotalLoggedMinutes)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedError := Domain.ErrTaskNotFound
+		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
 
 		// Act
-		task, err := taskUsecase.UpdateTask(taskID, taskReq)
+		task, err := taskUsecase.GetTaskByID(context.Background(), adminCaller, taskID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid status")
+		assert.ErrorIs(t, err, expectedError)
 		assert.Nil(t, task)
 		mockRepo.AssertExpectations(t)
 	})
-}
 
-func TestTaskUsecase_DeleteTask(t *testing.T) {
-	t.Run("Success - delete existing task", func(t *testing.T) {
+	t.Run("Error - invalid ID format", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		invalidID := "invalid-id"
+		expectedError := Domain.ErrInvalidTaskID
+		mockRepo.On("GetByID", invalidID).Return(nil, expectedError)
+
+		// Act
+		task, err := taskUsecase.GetTaskByID(context.Background(), adminCaller, invalidID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - regular user reads own task", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		taskID := primitive.NewObjectID().Hex()
-		mockRepo.On("Delete", taskID).Return(nil)
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		expectedTask := &Domain.Task{
+			ID:        primitive.NewObjectID(),
+			Title:     "Mine",
+			Status:    Domain.StatusPending,
+			CreatedBy: ownerID,
+		}
+		mockRepo.On("GetByID", taskID).Return(expectedTask, nil)
 
 		// Act
-		err := taskUsecase.DeleteTask(taskID)
+		task, err := taskUsecase.GetTaskByID(context.Background(), userCaller, taskID)
 
 		// Assert
 		assert.NoError(t, err)
+		assert.Equal(t, expectedTask, task)
 		mockRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error - task not found", func(t *testing.T) {
+	t.Run("Error - regular user reads someone else's task", func(t *testing.T) {
 		// Arrange
 		mockRepo := new(MockTaskRepository)
-		taskUsecase := NewTaskUsecase(mockRepo)
-		
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
 		taskID := primitive.NewObjectID().Hex()
-		expectedError := errors.New("task not found")
-		mockRepo.On("Delete", taskID).Return(expectedError)
+		otherTask := &Domain.Task{
+			ID:        primitive.NewObjectID(),
+			Title:     "Not mine",
+			Status:    Domain.StatusPending,
+			CreatedBy: primitive.NewObjectID(),
+		}
+		mockRepo.On("GetByID", taskID).Return(otherTask, nil)
 
 		// Act
-		err := taskUsecase.DeleteTask(taskID)
+		task, err := taskUsecase.GetTaskByID(context.Background(), userCaller, taskID)
 
 		// Assert
 		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, task)
 		mockRepo.AssertExpectations(t)
 	})
-}
 
-// Additional standalone tests
-func TestNewTaskUsecase(t *testing.T) {
-	mockRepo := new(MockTaskRepository)
-	usecase := NewTaskUsecase(mockRepo)
-	
-	assert.NotNil(t, usecase)
-	assert.Implements(t, (*TaskUsecaseInterface)(nil), usecase)
+	t.Run("Success - admin reads any task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		otherTask := &Domain.Task{
+			ID:        primitive.NewObjectID(),
+			Title:     "Someone else's",
+			Status:    Domain.StatusPending,
+			CreatedBy: primitive.NewObjectID(),
+		}
+		mockRepo.On("GetByID", taskID).Return(otherTask, nil)
+
+		// Act
+		task, err := taskUsecase.GetTaskByID(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, otherTask, task)
+		mockRepo.AssertExpectations(t)
+	})
 }
 
-func TestTaskUsecaseInterface(t *testing.T) {
-	// Test that our implementation satisfies the interface
-	mockRepo := new(MockTaskRepository)
-	var _ TaskUsecaseInterface = &TaskUsecase{taskRepo: mockRepo}
-	var _ TaskUsecaseInterface = NewTaskUsecase(mockRepo)
-}
\ No newline at end of file
+func TestTaskUsecase_CreateTask(t *testing.T) {
+	t.Run("Success - create task with all fields", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:       "New Task",
+			Description: "New Description",
+			DueDate:     "2024-12-31",
+			Status:      Domain.StatusPending,
+		}
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		assert.Equal(t, taskReq.Title, task.Title)
+		assert.Equal(t, taskReq.Description, task.Description)
+		assert.Equal(t, taskReq.Status, task.Status)
+		assert.Equal(t, adminCaller.UserID, task.CreatedBy.Hex())
+
+		// Date-only values are due at the end of the day
+		assert.Equal(t, time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC), task.DueDate)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Open task quota", func(t *testing.T) {
+		managerID, _ := primitive.ObjectIDFromHex(managerCaller.UserID)
+		openFilter := Domain.TaskFilter{CreatedBy: managerID, OpenOnly: true}
+		taskReq := Domain.TaskRequest{Title: "New Task", Status: Domain.StatusPending}
+
+		t.Run("Success - under the limit", func(t *testing.T) {
+			// Arrange
+			t.Setenv("MAX_OPEN_TASKS_PER_USER", "3")
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("CountTasks", openFilter).Return(int64(2), nil)
+			mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+			// Act
+			task, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, nil)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NotNil(t, task)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("Error - limit reached", func(t *testing.T) {
+			// Arrange
+			t.Setenv("MAX_OPEN_TASKS_PER_USER", "3")
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("CountTasks", openFilter).Return(int64(3), nil)
+
+			// Act
+			task, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, nil)
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrOpenTaskLimit)
+			assert.Nil(t, task)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+
+		t.Run("Success - admins are exempt", func(t *testing.T) {
+			// Arrange
+			t.Setenv("MAX_OPEN_TASKS_PER_USER", "1")
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+			// Act
+			_, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+			// Assert
+			assert.NoError(t, err)
+			mockRepo.AssertNotCalled(t, "CountTasks", mock.Anything)
+		})
+
+		t.Run("Success - no limit by default", func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+			// Act
+			_, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, nil)
+
+			// Assert
+			assert.NoError(t, err)
+			mockRepo.AssertNotCalled(t, "CountTasks", mock.Anything)
+		})
+	})
+
+	t.Run("Duplicate titles", func(t *testing.T) {
+		managerID, _ := primitive.ObjectIDFromHex(managerCaller.UserID)
+		taskReq := Domain.TaskRequest{Title: "Deploy release", Status: Domain.StatusPending}
+		allow, refuse := true, false
+
+		t.Run("Error - an open task with the same title is refused", func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			existing := &Domain.Task{ID: primitive.NewObjectID(), Title: "deploy  Release", Status: Domain.StatusInProgress, CreatedBy: managerID}
+			mockRepo.On("FindOpenByTitle", managerID, "Deploy release").Return(existing, nil)
+
+			// Act
+			task, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, &refuse)
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrDuplicateTask)
+			var duplicateErr *Domain.DuplicateTaskError
+			require.ErrorAs(t, err, &duplicateErr)
+			assert.Equal(t, existing.ID, duplicateErr.TaskID)
+			assert.Nil(t, task)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+
+		t.Run("Error - TASKS_ALLOW_DUPLICATES=false refuses when the request does not say", func(t *testing.T) {
+			// Arrange
+			t.Setenv("TASKS_ALLOW_DUPLICATES", "false")
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("FindOpenByTitle", managerID, "Deploy release").Return(&Domain.Task{ID: primitive.NewObjectID()}, nil)
+
+			// Act
+			_, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, nil)
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrDuplicateTask)
+		})
+
+		t.Run("Success - allow_duplicate overrides the configured default", func(t *testing.T) {
+			// Arrange
+			t.Setenv("TASKS_ALLOW_DUPLICATES", "false")
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+			// Act
+			task, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, &allow)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NotNil(t, task)
+			mockRepo.AssertNotCalled(t, "FindOpenByTitle", mock.Anything, mock.Anything)
+		})
+
+		t.Run("Success - a title matching only completed tasks is created", func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			// The repository only returns tasks that are not completed
+			mockRepo.On("FindOpenByTitle", managerID, "Deploy release").Return(nil, Domain.ErrTaskNotFound)
+			mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+			// Act
+			task, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, &refuse)
+
+			// Assert
+			assert.NoError(t, err)
+			assert.NotNil(t, task)
+			mockRepo.AssertExpectations(t)
+		})
+
+		t.Run("Error - a failed lookup is returned", func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+			mockRepo.On("FindOpenByTitle", managerID, "Deploy release").Return(nil, errors.New("database error"))
+
+			// Act
+			_, err := taskUsecase.CreateTask(context.Background(), managerCaller, taskReq, &refuse)
+
+			// Assert
+			assert.EqualError(t, err, "database error")
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	})
+
+	t.Run("Error - caller without valid user ID", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:  "New Task",
+			Status: Domain.StatusPending,
+		}
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), Domain.Caller{Role: Domain.RoleAdmin}, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - create task without due date", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:       "Task without due date",
+			Description: "Description",
+			Status:      Domain.StatusInProgress,
+		}
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		assert.Equal(t, taskReq.Title, task.Title)
+		assert.True(t, task.DueDate.IsZero())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:  "Task with invalid status",
+			Status: "invalid_status",
+		}
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+		assert.Nil(t, task)
+		// No repository call expected for validation errors
+	})
+
+	t.Run("Error - invalid priority", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:    "Task with invalid priority",
+			Status:   Domain.StatusPending,
+			Priority: "critical",
+		}
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidPriority)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - missing priority defaults to medium", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:  "Task without priority",
+			Status: Domain.StatusPending,
+		}
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.PriorityMedium, task.Priority)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid due date format", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:   "Task with invalid date",
+			DueDate: "invalid-date",
+			Status:  Domain.StatusPending,
+		}
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid due date format")
+		assert.Nil(t, task)
+		// No repository call expected for validation errors
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:  "Task",
+			Status: Domain.StatusPending,
+		}
+		expectedError := errors.New("database error")
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(expectedError)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_UpdateTask(t *testing.T) {
+	t.Run("Success - update existing task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{
+			ID:          primitive.NewObjectID(),
+			Title:       "Old Title",
+			Description: "Old Description",
+			Status:      Domain.StatusInProgress,
+		}
+		updatedTask := &Domain.Task{
+			ID:          existingTask.ID,
+			Title:       "Updated Title",
+			Description: "Updated Description",
+			Status:      Domain.StatusCompleted,
+			DueDate:     time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+		}
+		taskReq := Domain.TaskRequest{
+			Title:       "Updated Title",
+			Description: "Updated Description",
+			DueDate:     "2024-12-31",
+			Status:      Domain.StatusCompleted,
+		}
+
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
+		mockRepo.On("Update", taskID, mock.AnythingOfType("*Domain.Task")).Return(nil).Once()
+		mockRepo.On("GetByID", taskID).Return(updatedTask, nil).Once()
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, taskReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		assert.Equal(t, updatedTask.Title, task.Title)
+		assert.Equal(t, updatedTask.Status, task.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		taskReq := Domain.TaskRequest{
+			Title:  "Updated Title",
+			Status: Domain.StatusCompleted,
+		}
+		expectedError := Domain.ErrTaskNotFound
+		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, taskReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Existing Task",
+			Status: Domain.StatusPending,
+		}
+		taskReq := Domain.TaskRequest{
+			Title:  "Updated Title",
+			Status: "invalid_status",
+		}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, taskReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid status")
+		assert.Nil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid priority", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Existing Task",
+			Status: Domain.StatusPending,
+		}
+		taskReq := Domain.TaskRequest{
+			Title:    "Updated Title",
+			Status:   Domain.StatusPending,
+			Priority: "critical",
+		}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, taskReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidPriority)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_PatchTask(t *testing.T) {
+	t.Run("Success - patch status only", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		dueDate := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
+		existingTask := &Domain.Task{
+			ID:          primitive.NewObjectID(),
+			Title:       "Existing Title",
+			Description: "Existing Description",
+			DueDate:     dueDate,
+			Status:      Domain.StatusInProgress,
+		}
+		status := Domain.StatusCompleted
+		patch := Domain.TaskPatchRequest{Status: &status}
+
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.Title == "Existing Title" &&
+				task.Description == "Existing Description" &&
+				task.DueDate.Equal(dueDate) &&
+				task.Status == Domain.StatusCompleted
+		})).Return(nil).Once()
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, patch)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		assert.Equal(t, Domain.StatusCompleted, task.Status)
+		assert.Equal(t, "Existing Title", task.Title)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - empty due date clears it", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{
+			ID:      primitive.NewObjectID(),
+			Title:   "Existing Title",
+			DueDate: time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC),
+			Status:  Domain.StatusPending,
+		}
+		dueDate := ""
+		patch := Domain.TaskPatchRequest{DueDate: &dueDate}
+
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.DueDate.IsZero()
+		})).Return(nil)
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, patch)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, task.DueDate.IsZero())
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid fields rejected before repository", func(t *testing.T) {
+		emptyTitle := ""
+		invalidStatus := "invalid_status"
+		invalidPriority := "critical"
+		invalidDueDate := "31-12-2024"
+
+		tests := []struct {
+			name          string
+			patch         Domain.TaskPatchRequest
+			expectedError string
+		}{
+			{
+				name:          "empty patch",
+				patch:         Domain.TaskPatchRequest{},
+				expectedError: "no fields provided for update",
+			},
+			{
+				name:          "empty title",
+				patch:         Domain.TaskPatchRequest{Title: &emptyTitle},
+				expectedError: "title cannot be empty",
+			},
+			{
+				name:          "invalid status",
+				patch:         Domain.TaskPatchRequest{Status: &invalidStatus},
+				expectedError: "invalid status, must be one of: pending, in_progress, completed",
+			},
+			{
+				name:          "invalid priority",
+				patch:         Domain.TaskPatchRequest{Priority: &invalidPriority},
+				expectedError: "invalid priority, must be one of: low, medium, high, urgent",
+			},
+			{
+				name:          "malformed due date",
+				patch:         Domain.TaskPatchRequest{DueDate: &invalidDueDate},
+				expectedError: "invalid due date format, use YYYY-MM-DD or RFC3339 (e.g. 2024-12-31T17:00:00+03:00)",
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockRepo := new(MockTaskRepository)
+				taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+				// Act
+				task, err := taskUsecase.PatchTask(context.Background(), adminCaller, primitive.NewObjectID().Hex(), tt.patch)
+
+				// Assert
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, task)
+				mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+				mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		title := "New Title"
+		expectedError := Domain.ErrTaskNotFound
+		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{Title: &title})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_DeleteTask(t *testing.T) {
+	t.Run("Success - delete existing task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockBlobs := new(MockBlobStore)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, mockAttachmentRepo, mockBlobs, newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Test Task"}, nil)
+		mockRepo.On("GetByParentID", taskID).Return([]*Domain.Task{}, nil)
+		mockRepo.On("Delete", taskID).Return(nil)
+		mockCommentRepo.On("DeleteByTaskID", taskID).Return(nil)
+		mockAttachmentRepo.On("DeleteByTaskID", taskID).Return(nil)
+
+		// Act
+		err := taskUsecase.DeleteTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+		mockAttachmentRepo.AssertExpectations(t)
+		mockBlobs.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+
+	t.Run("Error - task not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		expectedError := Domain.ErrTaskNotFound
+		mockRepo.On("GetByID", taskID).Return(nil, expectedError)
+
+		// Act
+		err := taskUsecase.DeleteTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+		mockCommentRepo.AssertNotCalled(t, "DeleteByTaskID", mock.Anything)
+	})
+	t.Run("Error - parent with subtasks is rejected", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		parentID := primitive.NewObjectID()
+		subtasks := []*Domain.Task{{ID: primitive.NewObjectID(), Title: "Subtask", ParentTaskID: &parentID}}
+		mockRepo.On("GetByID", parentID.Hex()).Return(&Domain.Task{ID: parentID, Title: "Parent"}, nil)
+		mockRepo.On("GetByParentID", parentID.Hex()).Return(subtasks, nil)
+
+		// Act
+		err := taskUsecase.DeleteTask(context.Background(), adminCaller, parentID.Hex())
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskHasSubtasks)
+		mockRepo.AssertNotCalled(t, "Delete", mock.Anything)
+		mockCommentRepo.AssertNotCalled(t, "DeleteByTaskID", mock.Anything)
+	})
+}
+
+// Additional standalone tests
+func TestNewTaskUsecase(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	usecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+	assert.NotNil(t, usecase)
+	assert.Implements(t, (*TaskUsecaseInterface)(nil), usecase)
+}
+
+func TestTaskUsecaseInterface(t *testing.T) {
+	// Test that our implementation satisfies the interface
+	mockRepo := new(MockTaskRepository)
+	var _ TaskUsecaseInterface = &TaskUsecase{taskRepo: mockRepo}
+	var _ TaskUsecaseInterface = NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+}
+
+func TestTaskUsecase_GetDeletedTasks(t *testing.T) {
+	t.Run("Success - only deleted tasks requested", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		deletedAt := time.Now()
+		expectedTasks := []*Domain.Task{
+			{ID: primitive.NewObjectID(), Title: "Deleted", Status: Domain.StatusPending, DeletedAt: &deletedAt},
+		}
+		mockRepo.On("GetAll", Domain.TaskFilter{OnlyDeleted: true, IncludeArchived: true}, Domain.Pagination{}).Return(expectedTasks, int64(1), nil)
+
+		// Act
+		tasks, total, err := taskUsecase.GetDeletedTasks(context.Background(), Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(1), total)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_RestoreTask(t *testing.T) {
+	t.Run("Success - restore deleted task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		restoredTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Restored",
+			Status: Domain.StatusPending,
+		}
+		mockRepo.On("GetByID", taskID).Return(nil, Domain.ErrTaskNotFound).Once()
+		mockRepo.On("Restore", taskID).Return(nil).Once()
+		mockCommentRepo.On("RestoreByTaskID", taskID).Return(nil)
+		mockRepo.On("GetByID", taskID).Return(restoredTask, nil).Once()
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionRestore, Domain.AuditEntityTask, taskID, "")).Return(nil).Once()
+
+		// Act
+		task, err := taskUsecase.RestoreTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, restoredTask, task)
+		assert.False(t, task.IsDeleted())
+		mockRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - task is not deleted", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		activeTask := &Domain.Task{
+			ID:     primitive.NewObjectID(),
+			Title:  "Active",
+			Status: Domain.StatusPending,
+		}
+		mockRepo.On("GetByID", taskID).Return(activeTask, nil)
+
+		// Act
+		task, err := taskUsecase.RestoreTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotDeleted)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Restore", mock.Anything)
+	})
+
+	t.Run("Error - task does not exist", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(nil, Domain.ErrTaskNotFound)
+		mockRepo.On("Restore", taskID).Return(Domain.ErrTaskNotFound)
+
+		// Act
+		task, err := taskUsecase.RestoreTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid task ID", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("GetByID", "invalid-id").Return(nil, Domain.ErrInvalidTaskID)
+
+		// Act
+		task, err := taskUsecase.RestoreTask(context.Background(), adminCaller, "invalid-id")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidTaskID)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Restore", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_ArchiveTask(t *testing.T) {
+	newUsecase := func(mockRepo *MockTaskRepository) TaskUsecaseInterface {
+		return NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+	}
+
+	t.Run("Success - a completed task is archived", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Done", Status: Domain.StatusCompleted}, nil).Once()
+		mockRepo.On("SetArchived", taskID, true).Return(nil)
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Done", Status: Domain.StatusCompleted, Archived: true}, nil).Once()
+
+		// Act
+		task, err := taskUsecase.ArchiveTask(context.Background(), managerCaller, taskID, false)
+
+		// Assert
+		require.NoError(t, err)
+		assert.True(t, task.Archived)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - an open task is archived by an admin or with force", func(t *testing.T) {
+		for _, tt := range []struct {
+			name   string
+			caller Domain.Caller
+			force  bool
+		}{
+			{name: "admin", caller: adminCaller},
+			{name: "force", caller: managerCaller, force: true},
+		} {
+			// Arrange
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := newUsecase(mockRepo)
+
+			taskID := primitive.NewObjectID().Hex()
+			mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Open", Status: Domain.StatusPending}, nil)
+			mockRepo.On("SetArchived", taskID, true).Return(nil)
+
+			// Act
+			_, err := taskUsecase.ArchiveTask(context.Background(), tt.caller, taskID, tt.force)
+
+			// Assert
+			assert.NoError(t, err, tt.name)
+			mockRepo.AssertExpectations(t)
+		}
+	})
+
+	t.Run("Error - an open task needs force", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Open", Status: Domain.StatusInProgress}, nil)
+
+		// Act
+		task, err := taskUsecase.ArchiveTask(context.Background(), managerCaller, taskID, false)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotCompleted)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "SetArchived", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - task is already archived", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Done", Status: Domain.StatusCompleted, Archived: true}, nil)
+
+		// Act
+		_, err := taskUsecase.ArchiveTask(context.Background(), adminCaller, taskID, true)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskArchived)
+		mockRepo.AssertNotCalled(t, "SetArchived", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - task does not exist", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo)
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(nil, Domain.ErrTaskNotFound)
+
+		// Act
+		_, err := taskUsecase.ArchiveTask(context.Background(), adminCaller, taskID, false)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+	})
+}
+
+func TestTaskUsecase_UnarchiveTask(t *testing.T) {
+	t.Run("Success - an archived task is unarchived", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Done", Archived: true}, nil).Once()
+		mockRepo.On("SetArchived", taskID, false).Return(nil)
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Done"}, nil).Once()
+
+		// Act
+		task, err := taskUsecase.UnarchiveTask(context.Background(), managerCaller, taskID)
+
+		// Assert
+		require.NoError(t, err)
+		assert.False(t, task.Archived)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - task is not archived", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Done"}, nil)
+
+		// Act
+		_, err := taskUsecase.UnarchiveTask(context.Background(), managerCaller, taskID)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotArchived)
+		mockRepo.AssertNotCalled(t, "SetArchived", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_ArchiveCompletedTasks(t *testing.T) {
+	t.Run("Success - archives completed tasks before the cutoff", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		cutoff := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		mockRepo.On("ArchiveCompleted", cutoff).Return(int64(3), nil)
+
+		// Act
+		result, err := taskUsecase.ArchiveCompletedTasks(context.Background(), adminCaller, cutoff)
+
+		// Assert
+		require.NoError(t, err)
+		assert.Equal(t, &Domain.BulkResult{MatchedCount: 3, ModifiedCount: 3}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - the cutoff is required", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.ArchiveCompletedTasks(context.Background(), adminCaller, time.Time{})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidInput)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "ArchiveCompleted", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_PurgeDeletedTasks(t *testing.T) {
+	t.Run("Success - purge with cutoff", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockBlobs := new(MockBlobStore)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, mockAttachmentRepo, mockBlobs, newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		expectedCutoff := time.Now().AddDate(0, 0, -30)
+		nearCutoff := mock.MatchedBy(func(cutoff time.Time) bool {
+			return cutoff.Sub(expectedCutoff).Abs() < time.Minute
+		})
+		mockRepo.On("Purge", nearCutoff).Return(int64(3), nil)
+		mockCommentRepo.On("Purge", nearCutoff).Return(int64(7), nil)
+		mockAttachmentRepo.On("Purge", nearCutoff).Return([]*Domain.Attachment{{StorageKey: "task/a"}, {StorageKey: "task/b"}}, nil)
+		mockBlobs.On("Delete", "task/a").Return(nil)
+		mockBlobs.On("Delete", "task/b").Return(nil)
+
+		// Act
+		purged, err := taskUsecase.PurgeDeletedTasks(context.Background(), 30)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+		mockRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+		mockAttachmentRepo.AssertExpectations(t)
+		mockBlobs.AssertExpectations(t)
+	})
+
+	t.Run("Error - negative days", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		purged, err := taskUsecase.PurgeDeletedTasks(context.Background(), -1)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, int64(0), purged)
+		mockRepo.AssertNotCalled(t, "Purge", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_PurgeTasksByStatus(t *testing.T) {
+	t.Run("Success - purge stale tasks and their comments and attachments", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockAttachmentRepo := new(MockAttachmentRepository)
+		mockBlobs := new(MockBlobStore)
+		mockAuditRepo := newMockAuditRepository()
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, mockAttachmentRepo, mockBlobs, newMockWorkLogRepository(), mockAuditRepo, newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+		expectedCutoff := time.Now().Add(-90 * 24 * time.Hour)
+		nearCutoff := mock.MatchedBy(func(cutoff time.Time) bool {
+			return cutoff.Sub(expectedCutoff).Abs() < time.Minute
+		})
+		mockRepo.On("PurgeByStatus", Domain.StatusCompleted, nearCutoff).Return(ids, nil)
+		mockCommentRepo.On("PurgeByTaskIDs", ids).Return(int64(5), nil)
+		mockAttachmentRepo.On("PurgeByTaskIDs", ids).Return([]*Domain.Attachment{{StorageKey: "task/a"}}, nil)
+		mockBlobs.On("Delete", "task/a").Return(nil)
+
+		// Act
+		purged, err := taskUsecase.PurgeTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted, 90*24*time.Hour)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+		mockRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+		mockAttachmentRepo.AssertExpectations(t)
+		mockBlobs.AssertExpectations(t)
+		mockAuditRepo.AssertCalled(t, "Create", mock.MatchedBy(func(entry *Domain.AuditEntry) bool {
+			return entry.Action == Domain.AuditActionBulkDelete && entry.Diff == "purged 2 completed tasks not updated for 2160h0m0s"
+		}))
+	})
+
+	t.Run("Success - nothing to purge", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		mockRepo.On("PurgeByStatus", Domain.StatusCompleted, mock.Anything).Return(nil, nil)
+
+		// Act
+		purged, err := taskUsecase.PurgeTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted, time.Hour)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), purged)
+		mockCommentRepo.AssertNotCalled(t, "PurgeByTaskIDs", mock.Anything)
+	})
+
+	t.Run("Error - invalid arguments", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		_, statusErr := taskUsecase.PurgeTasksByStatus(context.Background(), adminCaller, "done", time.Hour)
+		_, ageErr := taskUsecase.PurgeTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted, 0)
+
+		// Assert
+		assert.ErrorIs(t, statusErr, Domain.ErrInvalidStatus)
+		assert.ErrorIs(t, ageErr, Domain.ErrInvalidInput)
+		mockRepo.AssertNotCalled(t, "PurgeByStatus", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_TaskAssignment(t *testing.T) {
+	assigneeID := userCaller.UserID
+
+	t.Run("Success - create task with existing assignee", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockUserRepo := new(MockUserRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, mockUserRepo, new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:      "Assigned Task",
+			Status:     Domain.StatusPending,
+			AssigneeID: assigneeID,
+		}
+		mockUserRepo.On("GetByID", assigneeID).Return(&Domain.User{Username: "worker"}, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, task.IsAssignedTo(assigneeID))
+		mockRepo.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - create task with unknown assignee", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockUserRepo := new(MockUserRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, mockUserRepo, new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:      "Assigned Task",
+			Status:     Domain.StatusPending,
+			AssigneeID: assigneeID,
+		}
+		mockUserRepo.On("GetByID", assigneeID).Return(nil, Domain.ErrUserNotFound)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrAssigneeNotFound)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - create task with malformed assignee ID", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockUserRepo := new(MockUserRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, mockUserRepo, new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:      "Assigned Task",
+			Status:     Domain.StatusPending,
+			AssigneeID: "not-an-id",
+		}
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidAssigneeID)
+		assert.Nil(t, task)
+		mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - patch with empty assignee unassigns", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockUserRepo := new(MockUserRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, mockUserRepo, new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		currentAssignee, _ := primitive.ObjectIDFromHex(assigneeID)
+		existingTask := &Domain.Task{Title: "Task", Status: Domain.StatusPending, AssigneeID: &currentAssignee}
+		empty := ""
+
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.AssigneeID == nil
+		})).Return(nil)
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{AssigneeID: &empty})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+		mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+
+	t.Run("Success - assignee can read the task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		assignee, _ := primitive.ObjectIDFromHex(assigneeID)
+		task := &Domain.Task{Title: "Task", CreatedBy: primitive.NewObjectID(), AssigneeID: &assignee}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+
+		// Act
+		result, err := taskUsecase.GetTaskByID(context.Background(), userCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, task, result)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_GetAssignedTasks(t *testing.T) {
+	t.Run("Success - scopes listing to the caller as assignee", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		callerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		expectedFilter := Domain.TaskFilter{AssigneeID: callerID}
+		expectedTasks := []*Domain.Task{{Title: "Assigned Task"}}
+		mockRepo.On("GetAll", expectedFilter, Domain.Pagination{}).Return(expectedTasks, int64(1), nil)
+
+		// Act
+		tasks, total, err := taskUsecase.GetAssignedTasks(context.Background(), userCaller, Domain.TaskFilter{}, Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedTasks, tasks)
+		assert.Equal(t, int64(1), total)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - caller without valid user ID", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		tasks, _, err := taskUsecase.GetAssignedTasks(context.Background(), Domain.Caller{Role: Domain.RoleUser}, Domain.TaskFilter{}, Domain.Pagination{})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+		assert.Nil(t, tasks)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_UpdateTaskStatus(t *testing.T) {
+	assignee, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+
+	t.Run("Success - assignee changes status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		task := &Domain.Task{Title: "Task", Status: Domain.StatusInProgress, CreatedBy: primitive.NewObjectID(), AssigneeID: &assignee}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.Status == Domain.StatusCompleted
+		})).Return(nil)
+
+		// Act
+		result, err := taskUsecase.UpdateTaskStatus(context.Background(), userCaller, taskID, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.StatusCompleted, result.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - manager changes any task's status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		task := &Domain.Task{Title: "Task", Status: Domain.StatusInProgress, CreatedBy: primitive.NewObjectID(), AssigneeID: &assignee}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+		mockRepo.On("Update", taskID, mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		result, err := taskUsecase.UpdateTaskStatus(context.Background(), managerCaller, taskID, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.StatusCompleted, result.Status)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - creator who is not the assignee", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		other := primitive.NewObjectID()
+		task := &Domain.Task{Title: "Task", Status: Domain.StatusPending, CreatedBy: assignee, AssigneeID: &other}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+
+		// Act
+		result, err := taskUsecase.UpdateTaskStatus(context.Background(), userCaller, taskID, Domain.StatusCompleted)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrNotAssignee)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - unrelated user gets not found", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		task := &Domain.Task{Title: "Task", Status: Domain.StatusPending, CreatedBy: primitive.NewObjectID()}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+
+		// Act
+		result, err := taskUsecase.UpdateTaskStatus(context.Background(), userCaller, taskID, Domain.StatusCompleted)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.UpdateTaskStatus(context.Background(), userCaller, primitive.NewObjectID().Hex(), "done")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidStatus)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_ParseDueDate(t *testing.T) {
+	eat, err := time.LoadLocation("Africa/Addis_Ababa")
+	if err != nil {
+		t.Skip("timezone data not available")
+	}
+
+	tests := []struct {
+		name          string
+		location      *time.Location
+		value         string
+		expected      time.Time
+		expectedError string
+	}{
+		{name: "Empty value means no due date", location: time.UTC, value: "", expected: time.Time{}},
+		{name: "Date only is end of day in UTC", location: time.UTC, value: "2024-12-31", expected: time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{name: "Date only is end of day in default timezone", location: eat, value: "2024-12-31", expected: time.Date(2024, 12, 31, 20, 59, 59, 0, time.UTC)},
+		{name: "RFC3339 with offset is stored in UTC", location: time.UTC, value: "2024-12-31T17:00:00+03:00", expected: time.Date(2024, 12, 31, 14, 0, 0, 0, time.UTC)},
+		{name: "RFC3339 ignores default timezone", location: eat, value: "2024-12-31T17:00:00Z", expected: time.Date(2024, 12, 31, 17, 0, 0, 0, time.UTC)},
+		{name: "Invalid format", location: time.UTC, value: "31/12/2024", expectedError: "invalid due date format, use YYYY-MM-DD or RFC3339 (e.g. 2024-12-31T17:00:00+03:00)"},
+		{name: "Timestamp without offset", location: time.UTC, value: "2024-12-31T17:00:00", expectedError: "invalid due date format, use YYYY-MM-DD or RFC3339 (e.g. 2024-12-31T17:00:00+03:00)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Arrange
+			taskUsecase := &TaskUsecase{defaultLocation: tt.location}
+
+			// Act
+			dueDate, err := taskUsecase.parseDueDate(tt.value)
+
+			// Assert
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, dueDate)
+		})
+	}
+
+	t.Run("Default timezone read from environment", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TASKS_DEFAULT_TIMEZONE", "Africa/Addis_Ababa")
+
+		// Act
+		taskUsecase := NewTaskUsecase(new(MockTaskRepository), new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger()).(*TaskUsecase)
+
+		// Assert
+		assert.Equal(t, "Africa/Addis_Ababa", taskUsecase.defaultLocation.String())
+	})
+
+	t.Run("Unknown timezone falls back to UTC", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TASKS_DEFAULT_TIMEZONE", "Mars/Olympus_Mons")
+
+		// Act
+		taskUsecase := NewTaskUsecase(new(MockTaskRepository), new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger()).(*TaskUsecase)
+
+		// Assert
+		assert.Equal(t, time.UTC, taskUsecase.defaultLocation)
+	})
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tooMany := make([]string, MaxTaskTags+1)
+	for i := range tooMany {
+		tooMany[i] = fmt.Sprintf("tag-%d", i)
+	}
+
+	tests := []struct {
+		name          string
+		tags          []string
+		expected      []string
+		expectedError string
+	}{
+		{name: "Omitted tags stay nil", tags: nil, expected: nil},
+		{name: "Empty array stays empty", tags: []string{}, expected: []string{}},
+		{name: "Trims, lowercases and dedupes", tags: []string{" Backend", "backend ", "Sprint-12"}, expected: []string{"backend", "sprint-12"}},
+		{name: "Rejects blank tags", tags: []string{"backend", "  "}, expectedError: "tags cannot be empty"},
+		{name: "Rejects too many tags", tags: tooMany, expectedError: fmt.Sprintf("a task can have at most %d tags", MaxTaskTags)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Act
+			tags, err := normalizeTags(tt.tags)
+
+			// Assert
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, tags)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, tags)
+		})
+	}
+}
+
+func TestTaskUsecase_TaskTags(t *testing.T) {
+	t.Run("Success - create task stores normalized tags", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskReq := Domain.TaskRequest{
+			Title:  "Tagged Task",
+			Status: Domain.StatusPending,
+			Tags:   []string{"Backend", "backend", "urgent"},
+		}
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, taskReq, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"backend", "urgent"}, task.Tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - update without tags leaves them unchanged", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{Title: "Task", Status: Domain.StatusPending, Tags: []string{"backend"}}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return len(task.Tags) == 1 && task.Tags[0] == "backend"
+		})).Return(nil)
+
+		// Act
+		_, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, Domain.TaskRequest{Title: "Task", Status: Domain.StatusPending})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - update with empty tags clears them", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{Title: "Task", Status: Domain.StatusPending, Tags: []string{"backend"}}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return len(task.Tags) == 0
+		})).Return(nil)
+
+		// Act
+		_, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, Domain.TaskRequest{Title: "Task", Status: Domain.StatusPending, Tags: []string{}})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - blank tag rejected before repository", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, primitive.NewObjectID().Hex(), Domain.TaskPatchRequest{Tags: []string{""}})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "tags cannot be empty", err.Error())
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_TaskEstimates(t *testing.T) {
+	t.Run("Success - create task with an estimate", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		estimate := 240
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Estimated", Status: Domain.StatusPending, EstimateMinutes: &estimate}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 240, *task.EstimateMinutes)
+	})
+
+	t.Run("Success - patch with zero clears the estimate", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		estimate, cleared := 240, 0
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Task", Status: Domain.StatusPending, EstimateMinutes: &estimate}, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.EstimateMinutes == nil
+		})).Return(nil)
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{EstimateMinutes: &cleared})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - negative or absurd estimate rejected before repository", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		for _, estimate := range []int{-30, MaxEstimateMinutes + 1} {
+			// Act
+			_, err := taskUsecase.PatchTask(context.Background(), adminCaller, primitive.NewObjectID().Hex(), Domain.TaskPatchRequest{EstimateMinutes: &estimate})
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrInvalidInput, "estimate %d", estimate)
+		}
+		mockRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_GetTags(t *testing.T) {
+	t.Run("Success - admin sees every tag", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("GetTags", Domain.TaskFilter{}).Return([]string{"backend", "urgent"}, nil)
+
+		// Act
+		tags, err := taskUsecase.GetTags(context.Background(), adminCaller)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"backend", "urgent"}, tags)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - regular user scoped to own tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		mockRepo.On("GetTags", Domain.TaskFilter{CreatedBy: ownerID}).Return([]string{"backend"}, nil)
+
+		// Act
+		tags, err := taskUsecase.GetTags(context.Background(), userCaller)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"backend"}, tags)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_Subtasks(t *testing.T) {
+	t.Run("Success - create subtask under top-level parent", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		parent := &Domain.Task{ID: primitive.NewObjectID(), Title: "Parent"}
+		mockRepo.On("GetByID", parent.ID.Hex()).Return(parent, nil)
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{
+			Title:        "Subtask",
+			Status:       Domain.StatusPending,
+			ParentTaskID: parent.ID.Hex(),
+		}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, task.IsSubtask())
+		assert.Equal(t, parent.ID, *task.ParentTaskID)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid parents rejected before create", func(t *testing.T) {
+		grandparentID := primitive.NewObjectID()
+		nestedParent := &Domain.Task{ID: primitive.NewObjectID(), Title: "Subtask", ParentTaskID: &grandparentID}
+
+		tests := []struct {
+			name          string
+			parentID      string
+			parent        *Domain.Task
+			repoErr       error
+			expectedError string
+		}{
+			{name: "missing parent", parentID: primitive.NewObjectID().Hex(), repoErr: Domain.ErrTaskNotFound, expectedError: "parent task not found"},
+			{name: "malformed parent ID", parentID: "bad-id", repoErr: Domain.ErrInvalidTaskID, expectedError: "invalid parent task ID format"},
+			{name: "parent is a subtask", parentID: nestedParent.ID.Hex(), parent: nestedParent, expectedError: "parent task is itself a subtask, only one level of nesting is supported"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockRepo := new(MockTaskRepository)
+				taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+				if tt.parent != nil {
+					mockRepo.On("GetByID", tt.parentID).Return(tt.parent, nil)
+				} else {
+					mockRepo.On("GetByID", tt.parentID).Return(nil, tt.repoErr)
+				}
+
+				// Act
+				task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{
+					Title:        "Subtask",
+					Status:       Domain.StatusPending,
+					ParentTaskID: tt.parentID,
+				}, nil)
+
+				// Assert
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, task)
+				mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Success - embed visible subtasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		callerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		parent := &Domain.Task{ID: primitive.NewObjectID(), Title: "Parent", AssigneeID: &callerID}
+		mine := &Domain.Task{ID: primitive.NewObjectID(), Title: "Mine", ParentTaskID: &parent.ID, AssigneeID: &callerID}
+		other := &Domain.Task{ID: primitive.NewObjectID(), Title: "Other", ParentTaskID: &parent.ID}
+		mockRepo.On("GetByID", parent.ID.Hex()).Return(parent, nil)
+		mockRepo.On("GetByParentID", parent.ID.Hex()).Return([]*Domain.Task{mine, other}, nil)
+
+		// Act
+		task, err := taskUsecase.GetTaskWithSubtasks(context.Background(), userCaller, parent.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []*Domain.Task{mine}, task.Subtasks)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - parent not visible", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		parent := &Domain.Task{ID: primitive.NewObjectID(), Title: "Parent", CreatedBy: primitive.NewObjectID()}
+		mockRepo.On("GetByID", parent.ID.Hex()).Return(parent, nil)
+
+		// Act
+		task, err := taskUsecase.GetTaskWithSubtasks(context.Background(), userCaller, parent.ID.Hex())
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "GetByParentID", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_RecurringTasks(t *testing.T) {
+	dueDate := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Success - completing a recurring task spawns the next occurrence", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Rent", DueDate: dueDate, Status: Domain.StatusInProgress, Recurrence: Domain.RecurrenceMonthly}
+		taskID := existingTask.ID.Hex()
+		nextID := primitive.NewObjectID()
+		completedTask := &Domain.Task{ID: existingTask.ID, Title: "Rent", Status: Domain.StatusCompleted, Recurrence: Domain.RecurrenceMonthly, NextOccurrenceID: &nextID}
+
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
+		mockRepo.On("CompleteRecurring", taskID, mock.AnythingOfType("*Domain.Task"), mock.MatchedBy(func(next *Domain.Task) bool {
+			return next.Status == Domain.StatusPending && next.DueDate.Equal(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC))
+		})).Return(nil).Once()
+		mockRepo.On("GetByID", taskID).Return(completedTask, nil).Once()
+
+		// Act
+		task, err := taskUsecase.UpdateTaskStatus(context.Background(), adminCaller, taskID, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &nextID, task.NextOccurrenceID)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - already spawned task does not spawn again", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		nextID := primitive.NewObjectID()
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Rent", DueDate: dueDate, Status: Domain.StatusInProgress, Recurrence: Domain.RecurrenceMonthly, NextOccurrenceID: &nextID}
+		taskID := existingTask.ID.Hex()
+
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, existingTask).Return(nil).Once()
+
+		status := Domain.StatusCompleted
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{Status: &status})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertNotCalled(t, "CompleteRecurring", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - recurring task without due date", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Rent", Status: Domain.StatusPending, Recurrence: Domain.RecurrenceMonthly}, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "recurring tasks require a due date", err.Error())
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - patch clears due date of recurring task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Rent", DueDate: dueDate, Status: Domain.StatusPending, Recurrence: Domain.RecurrenceWeekly}
+		taskID := existingTask.ID.Hex()
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		dueDate := ""
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{DueDate: &dueDate})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "recurring tasks require a due date", err.Error())
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid recurrence", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Rent", Status: Domain.StatusPending, DueDate: "2025-01-31", Recurrence: "yearly"}, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidRecurrence)
+		assert.Nil(t, task)
+	})
+}
+
+func TestTaskUsecase_StatusTransitions(t *testing.T) {
+	t.Run("Error - illegal transitions are rejected", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", Status: Domain.StatusCompleted}
+		taskID := existingTask.ID.Hex()
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		status := Domain.StatusPending
+
+		// Act
+		updated, updateErr := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, Domain.TaskRequest{Title: "Task", Status: Domain.StatusPending})
+		patched, patchErr := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{Status: &status})
+		changed, statusErr := taskUsecase.UpdateTaskStatus(context.Background(), adminCaller, taskID, Domain.StatusPending)
+
+		// Assert
+		for _, err := range []error{updateErr, patchErr, statusErr} {
+			assert.Error(t, err)
+			assert.ErrorIs(t, err, Domain.ErrInvalidStatusTransition)
+		}
+		assert.Nil(t, updated)
+		assert.Nil(t, patched)
+		assert.Nil(t, changed)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - tasks cannot be created as completed", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Task", Status: Domain.StatusCompleted}, nil)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "invalid initial status, tasks must be created as pending or in_progress", err.Error())
+		assert.Nil(t, task)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - enforcement can be disabled", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TASKS_ENFORCE_STATUS_TRANSITIONS", "false")
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", Status: Domain.StatusCompleted}
+		taskID := existingTask.ID.Hex()
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, existingTask).Return(nil)
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		_, updateErr := taskUsecase.UpdateTaskStatus(context.Background(), adminCaller, taskID, Domain.StatusPending)
+		_, createErr := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Task", Status: Domain.StatusCompleted}, nil)
+
+		// Assert
+		assert.NoError(t, updateErr)
+		assert.NoError(t, createErr)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_CreateTasks(t *testing.T) {
+	taskReqs := []Domain.TaskRequest{
+		{Title: "First", Status: Domain.StatusPending},
+		{Title: "", Status: Domain.StatusPending},
+		{Title: "Third", Status: Domain.StatusInProgress},
+		{Title: "Fourth", Status: "done"},
+	}
+
+	// assignIDs mimics the repository stamping ids on insert
+	assignIDs := func(args mock.Arguments) {
+		for _, task := range args.Get(0).([]*Domain.Task) {
+			task.ID = primitive.NewObjectID()
+		}
+	}
+
+	t.Run("Success - valid items are inserted and failures reported by index", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("CreateMany", mock.MatchedBy(func(tasks []*Domain.Task) bool {
+			return len(tasks) == 2 && tasks[0].Title == "First" && tasks[1].Title == "Third"
+		})).Run(assignIDs).Return(nil).Once()
+
+		// Act
+		result, err := taskUsecase.CreateTasks(context.Background(), adminCaller, taskReqs, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, result.Created, 2)
+		assert.Equal(t, []Domain.BulkItemError{
+			{Index: 1, Error: "title cannot be empty"},
+			{Index: 3, Error: "invalid status, must be one of: pending, in_progress, completed"},
+		}, result.Errors)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - atomic batch with invalid items inserts nothing", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.CreateTasks(context.Background(), adminCaller, taskReqs, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, result.Created)
+		assert.Len(t, result.Errors, 2)
+		mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything)
+	})
+
+	t.Run("Success - atomic batch of valid items", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("CreateMany", mock.AnythingOfType("[]*Domain.Task")).Run(assignIDs).Return(nil).Once()
+
+		// Act
+		result, err := taskUsecase.CreateTasks(context.Background(), adminCaller, []Domain.TaskRequest{taskReqs[0], taskReqs[2]}, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Len(t, result.Created, 2)
+		assert.Empty(t, result.Errors)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - batch size limits", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			taskReqs      []Domain.TaskRequest
+			expectedError string
+		}{
+			{name: "empty batch", taskReqs: []Domain.TaskRequest{}, expectedError: "no tasks provided"},
+			{name: "oversized batch", taskReqs: make([]Domain.TaskRequest, MaxBulkTasks+1), expectedError: "a bulk request can have at most 100 tasks"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockRepo := new(MockTaskRepository)
+				taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+				// Act
+				result, err := taskUsecase.CreateTasks(context.Background(), adminCaller, tt.taskReqs, false)
+
+				// Assert
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, result)
+			})
+		}
+	})
+
+	t.Run("Error - insert failure", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("CreateMany", mock.AnythingOfType("[]*Domain.Task")).Return(errors.New("database error"))
+
+		// Act
+		result, err := taskUsecase.CreateTasks(context.Background(), adminCaller, taskReqs[:1], false)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestTaskUsecase_ImportTasks(t *testing.T) {
+	dueDate := "2030-01-15T10:00:00Z"
+	parsedDue, _ := time.Parse(time.RFC3339, dueDate)
+
+	imports := []Domain.TaskImport{
+		{TaskRequest: Domain.TaskRequest{Title: "Old", Status: Domain.StatusPending, DueDate: dueDate}, CreatedAt: "2023-03-01T08:00:00+02:00"},
+		{TaskRequest: Domain.TaskRequest{Title: "", Status: Domain.StatusPending}},
+		{TaskRequest: Domain.TaskRequest{Title: "Bad date", Status: Domain.StatusPending}, CreatedAt: "yesterday"},
+		{TaskRequest: Domain.TaskRequest{Title: "Old", Status: Domain.StatusPending, DueDate: dueDate}},
+	}
+
+	assignIDs := func(args mock.Arguments) {
+		for _, task := range args.Get(0).([]*Domain.Task) {
+			task.ID = primitive.NewObjectID()
+		}
+	}
+
+	t.Run("Success - keeps created_at and reports invalid rows", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("CreateMany", mock.MatchedBy(func(tasks []*Domain.Task) bool {
+			return len(tasks) == 2 &&
+				tasks[0].CreatedAt.Equal(time.Date(2023, 3, 1, 6, 0, 0, 0, time.UTC)) &&
+				tasks[1].CreatedAt.IsZero()
+		})).Run(assignIDs).Return(nil).Once()
+
+		// Act
+		result, err := taskUsecase.ImportTasks(context.Background(), adminCaller, imports, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Imported)
+		assert.Equal(t, 2, result.Skipped)
+		assert.Len(t, result.Created, 2)
+		assert.Equal(t, []Domain.BulkItemError{
+			{Index: 1, Error: "title cannot be empty"},
+			{Index: 2, Error: "invalid created_at format, use RFC3339"},
+		}, result.Errors)
+		mockRepo.AssertNotCalled(t, "ExistsByTitleAndDueDate", mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - skip_duplicates skips existing and repeated rows", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		rows := []Domain.TaskImport{
+			imports[0],
+			imports[3],
+			{TaskRequest: Domain.TaskRequest{Title: "Existing", Status: Domain.StatusPending}},
+		}
+		mockRepo.On("ExistsByTitleAndDueDate", "Old", parsedDue).Return(false, nil).Once()
+		mockRepo.On("ExistsByTitleAndDueDate", "Existing", time.Time{}).Return(true, nil).Once()
+		mockRepo.On("CreateMany", mock.MatchedBy(func(tasks []*Domain.Task) bool {
+			return len(tasks) == 1 && tasks[0].Title == "Old"
+		})).Run(assignIDs).Return(nil).Once()
+
+		// Act
+		result, err := taskUsecase.ImportTasks(context.Background(), adminCaller, rows, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Imported)
+		assert.Equal(t, 2, result.Skipped)
+		assert.Equal(t, []Domain.BulkItemError{
+			{Index: 1, Error: "duplicate of an earlier task in the import"},
+			{Index: 2, Error: "duplicate of an existing task"},
+		}, result.Errors)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - nothing valid inserts nothing", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.ImportTasks(context.Background(), adminCaller, imports[1:3], false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Imported)
+		assert.Equal(t, 2, result.Skipped)
+		mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything)
+	})
+
+	t.Run("Error - empty import", func(t *testing.T) {
+		// Arrange
+		taskUsecase := NewTaskUsecase(new(MockTaskRepository), new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.ImportTasks(context.Background(), adminCaller, nil, false)
+
+		// Assert
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "no tasks provided")
+	})
+
+	t.Run("Error - duplicate lookup fails", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("ExistsByTitleAndDueDate", "Old", parsedDue).Return(false, errors.New("database error")).Once()
+
+		// Act
+		result, err := taskUsecase.ImportTasks(context.Background(), adminCaller, imports[:1], true)
+
+		// Assert
+		assert.Nil(t, result)
+		assert.EqualError(t, err, "database error")
+		mockRepo.AssertNotCalled(t, "CreateMany", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_UpdateTasksStatus(t *testing.T) {
+	t.Run("Success - only tasks that may move to the status are changed", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+		mockRepo.On("UpdateStatusMany", ids, Domain.StatusCompleted, []string{Domain.StatusInProgress, Domain.StatusCompleted}).Return(int64(2), int64(1), nil, nil)
+
+		// Act
+		result, err := taskUsecase.UpdateTasksStatus(context.Background(), adminCaller, []string{ids[0].Hex(), ids[1].Hex()}, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &Domain.BulkResult{MatchedCount: 2, ModifiedCount: 1}, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - any status may change when enforcement is disabled", func(t *testing.T) {
+		// Arrange
+		t.Setenv("TASKS_ENFORCE_STATUS_TRANSITIONS", "false")
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		id := primitive.NewObjectID()
+		mockRepo.On("UpdateStatusMany", []primitive.ObjectID{id}, Domain.StatusCompleted, []string(nil)).Return(int64(1), int64(1), nil, nil)
+
+		// Act
+		_, err := taskUsecase.UpdateTasksStatus(context.Background(), adminCaller, []string{id.Hex()}, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid requests fail before the repository", func(t *testing.T) {
+		tests := []struct {
+			name          string
+			ids           []string
+			status        string
+			expectedError string
+		}{
+			{name: "empty id list", ids: []string{}, status: Domain.StatusCompleted, expectedError: "no task IDs provided"},
+			{name: "invalid status", ids: []string{primitive.NewObjectID().Hex()}, status: "done", expectedError: "invalid status, must be one of: pending, in_progress, completed"},
+			{name: "one malformed id", ids: []string{primitive.NewObjectID().Hex(), "bad-id"}, status: Domain.StatusCompleted, expectedError: "invalid task ID format: bad-id"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockRepo := new(MockTaskRepository)
+				taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+				// Act
+				result, err := taskUsecase.UpdateTasksStatus(context.Background(), adminCaller, tt.ids, tt.status)
+
+				// Assert
+				assert.Error(t, err)
+				assert.Equal(t, tt.expectedError, err.Error())
+				assert.Nil(t, result)
+				mockRepo.AssertNotCalled(t, "UpdateStatusMany", mock.Anything, mock.Anything, mock.Anything)
+			})
+		}
+	})
+}
+
+func TestTaskUsecase_DeleteTasksByStatus(t *testing.T) {
+	t.Run("Success - moves tasks with the status to the trash", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()}
+		mockRepo.On("DeleteByStatus", Domain.StatusCompleted).Return(int64(4), int64(4), ids, nil)
+		mockCommentRepo.On("DeleteByTaskIDs", ids).Return(nil)
+
+		// Act
+		result, err := taskUsecase.DeleteTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &Domain.BulkResult{MatchedCount: 4, ModifiedCount: 4}, result)
+		mockRepo.AssertExpectations(t)
+		mockCommentRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - no matching tasks leaves comments alone", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("DeleteByStatus", Domain.StatusPending).Return(int64(0), int64(0), nil, nil)
+
+		// Act
+		result, err := taskUsecase.DeleteTasksByStatus(context.Background(), adminCaller, Domain.StatusPending)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &Domain.BulkResult{}, result)
+		mockCommentRepo.AssertNotCalled(t, "DeleteByTaskIDs", mock.Anything)
+	})
+
+	t.Run("Error - trashing the comments fails", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ids := []primitive.ObjectID{primitive.NewObjectID()}
+		mockRepo.On("DeleteByStatus", Domain.StatusCompleted).Return(int64(1), int64(1), ids, nil)
+		mockCommentRepo.On("DeleteByTaskIDs", ids).Return(errors.New("database connection failed"))
+
+		// Act
+		result, err := taskUsecase.DeleteTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted)
+
+		// Assert
+		assert.EqualError(t, err, "database connection failed")
+		assert.Nil(t, result)
+	})
+
+	t.Run("Error - invalid status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.DeleteTasksByStatus(context.Background(), adminCaller, "done")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "DeleteByStatus", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_GetStats(t *testing.T) {
+	stats := &Domain.TaskStats{ByStatus: map[string]int64{Domain.StatusPending: 1}, Overdue: 1}
+
+	t.Run("Success - admin stats cover every task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("GetStats", Domain.TaskFilter{}, mock.AnythingOfType("time.Time")).Return(stats, nil)
+
+		// Act
+		result, err := taskUsecase.GetStats(context.Background(), adminCaller)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, stats, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - user stats scoped to own tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		mockRepo.On("GetStats", Domain.TaskFilter{CreatedBy: ownerID}, mock.AnythingOfType("time.Time")).Return(stats, nil)
+
+		// Act
+		result, err := taskUsecase.GetStats(context.Background(), userCaller)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, stats, result)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - caller without valid user ID", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		result, err := taskUsecase.GetStats(context.Background(), Domain.Caller{UserID: "bad-id", Role: Domain.RoleUser})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+		assert.Nil(t, result)
+		mockRepo.AssertNotCalled(t, "GetStats", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - repository failure", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("GetStats", Domain.TaskFilter{}, mock.AnythingOfType("time.Time")).Return(nil, errors.New("database error"))
+
+		// Act
+		result, err := taskUsecase.GetStats(context.Background(), adminCaller)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+}
+
+func TestTaskUsecase_GetOverdueTasks(t *testing.T) {
+	t.Run("Success - overdue tasks sorted by due date with days overdue", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Late", DueDate: time.Now().Add(-50 * time.Hour)}
+		mockRepo.On("GetAll", mock.MatchedBy(func(filter Domain.TaskFilter) bool {
+			return !filter.OverdueAt.IsZero() && filter.CreatedBy.IsZero()
+		}), Domain.Pagination{Limit: 10, Sort: Domain.SortDueDate}).Return([]*Domain.Task{task}, int64(1), nil)
+
+		// Act
+		tasks, total, err := taskUsecase.GetOverdueTasks(context.Background(), adminCaller, Domain.Pagination{Limit: 10})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, 2, *tasks[0].DaysOverdue)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - user sees only own overdue tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		mockRepo.On("GetAll", mock.MatchedBy(func(filter Domain.TaskFilter) bool {
+			return filter.CreatedBy == ownerID
+		}), Domain.Pagination{Sort: Domain.SortDueDate}).Return([]*Domain.Task{}, int64(0), nil)
+
+		// Act
+		_, _, err := taskUsecase.GetOverdueTasks(context.Background(), userCaller, Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - custom sort rejected", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		tasks, _, err := taskUsecase.GetOverdueTasks(context.Background(), adminCaller, Domain.Pagination{Sort: Domain.SortPriority})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, "invalid sort, overdue tasks are always sorted by due date", err.Error())
+		assert.Nil(t, tasks)
+		mockRepo.AssertNotCalled(t, "GetAll", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_SearchTasks(t *testing.T) {
+	t.Run("Success - admin searches every task with a trimmed query", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		score := 1.5
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Invoice", Score: &score}
+		mockRepo.On("Search", "invoice", Domain.TaskFilter{}, Domain.Pagination{Limit: 10}).Return([]*Domain.Task{task}, int64(1), nil)
+
+		// Act
+		tasks, total, err := taskUsecase.SearchTasks(context.Background(), adminCaller, "  invoice ", Domain.Pagination{Limit: 10})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), total)
+		assert.Equal(t, []*Domain.Task{task}, tasks)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - user searches only own tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		mockRepo.On("Search", "invoice", Domain.TaskFilter{CreatedBy: ownerID}, Domain.Pagination{}).Return([]*Domain.Task{}, int64(0), nil)
+
+		// Act
+		_, _, err := taskUsecase.SearchTasks(context.Background(), userCaller, "invoice", Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	testCases := []struct {
+		name       string
+		query      string
+		pagination Domain.Pagination
+		message    string
+	}{
+		{"Error - blank query", "   ", Domain.Pagination{}, "search query is required"},
+		{"Error - query too long", strings.Repeat("a", Domain.MaxSearchQueryLength+1), Domain.Pagination{}, "search query must be at most 200 characters"},
+		{"Error - custom sort rejected", "invoice", Domain.Pagination{Sort: Domain.SortDueDate}, "invalid sort, search results are always sorted by relevance"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Arrange
+			mockRepo := new(MockTaskRepository)
+			taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+			// Act
+			tasks, _, err := taskUsecase.SearchTasks(context.Background(), adminCaller, tc.query, tc.pagination)
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrInvalidInput)
+			assert.Equal(t, tc.message, err.Error())
+			assert.Nil(t, tasks)
+			mockRepo.AssertNotCalled(t, "Search", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestTaskUsecase_CountTasks(t *testing.T) {
+	t.Run("Success - admin counts every matching task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		filter := Domain.TaskFilter{Status: Domain.StatusPending}
+		mockRepo.On("CountTasks", filter).Return(int64(7), nil)
+
+		// Act
+		count, err := taskUsecase.CountTasks(context.Background(), adminCaller, filter)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), count)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - user counts only own tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		mockRepo.On("CountTasks", Domain.TaskFilter{Status: Domain.StatusPending, CreatedBy: ownerID}).Return(int64(2), nil)
+
+		// Act
+		count, err := taskUsecase.CountTasks(context.Background(), userCaller, Domain.TaskFilter{Status: Domain.StatusPending})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		count, err := taskUsecase.CountTasks(context.Background(), adminCaller, Domain.TaskFilter{Status: "done"})
+
+		// Assert
+		assert.Error(t, err)
+		assert.Equal(t, int64(0), count)
+		mockRepo.AssertNotCalled(t, "CountTasks", mock.Anything)
+	})
+}
+
+func TestTaskUsecase_ExportTasks(t *testing.T) {
+	t.Run("Success - user export scoped to own tasks", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Mine"}
+		mockRepo.On("Stream", Domain.TaskFilter{Status: Domain.StatusPending, CreatedBy: ownerID}, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(task *Domain.Task) error)
+			_ = fn(task)
+		}).Return(nil)
+
+		var exported []*Domain.Task
+
+		// Act
+		err := taskUsecase.ExportTasks(context.Background(), userCaller, Domain.TaskFilter{Status: Domain.StatusPending}, func(task *Domain.Task) error {
+			exported = append(exported, task)
+			return nil
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []*Domain.Task{task}, exported)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid filter", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		err := taskUsecase.ExportTasks(context.Background(), adminCaller, Domain.TaskFilter{Priority: "critical"}, func(task *Domain.Task) error { return nil })
+
+		// Assert
+		assert.Error(t, err)
+		mockRepo.AssertNotCalled(t, "Stream", mock.Anything, mock.Anything)
+	})
+}
+
+func TestTaskUsecase_Revisions(t *testing.T) {
+	t.Run("Success - update stores the previous values of changed fields", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockRevisionRepo := new(MockRevisionRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), mockRevisionRepo, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID()
+		existingTask := &Domain.Task{ID: taskID, Title: "Task", Description: "Old text", Status: Domain.StatusPending, Priority: Domain.PriorityMedium}
+		description := "New text"
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(existingTask, nil)
+		mockRepo.On("Update", taskID.Hex(), mock.AnythingOfType("*Domain.Task")).Return(nil)
+		mockRevisionRepo.On("Create", mock.MatchedBy(func(revision *Domain.TaskRevision) bool {
+			return revision.TaskID == taskID &&
+				revision.EditorID == adminCaller.UserID &&
+				assert.ObjectsAreEqual(map[string]interface{}{"description": "Old text"}, revision.Previous)
+		})).Return(nil).Once()
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{Description: &description})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - no-op update stores no revision", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockRevisionRepo := new(MockRevisionRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), mockRevisionRepo, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID()
+		existingTask := &Domain.Task{ID: taskID, Title: "Task", Status: Domain.StatusPending, Priority: Domain.PriorityMedium, Recurrence: Domain.RecurrenceNone}
+		taskReq := Domain.TaskRequest{Title: "Task", Status: Domain.StatusPending, Priority: Domain.PriorityMedium}
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(existingTask, nil)
+		mockRepo.On("Update", taskID.Hex(), mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		_, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID.Hex(), taskReq)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRevisionRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - revision failure does not fail the update", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockRevisionRepo := new(MockRevisionRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), mockRevisionRepo, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID()
+		existingTask := &Domain.Task{ID: taskID, Title: "Task", Status: Domain.StatusPending, Priority: Domain.PriorityMedium}
+		title := "Renamed"
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(existingTask, nil)
+		mockRepo.On("Update", taskID.Hex(), mock.AnythingOfType("*Domain.Task")).Return(nil)
+		mockRevisionRepo.On("Create", mock.Anything).Return(errors.New("database error")).Once()
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{Title: &title})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_GetTaskRevisions(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	otherUserID, _ := primitive.ObjectIDFromHex("507f1f77bcf86cd799439033")
+
+	t.Run("Success - page of revisions for a visible task", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockRevisionRepo := new(MockRevisionRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), mockRevisionRepo, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		pagination := Domain.Pagination{Limit: 10}
+		expectedRevisions := []*Domain.TaskRevision{{TaskID: taskID, EditorID: adminCaller.UserID}}
+		mockRepo.On("GetByID", taskID.Hex()).Return(&Domain.Task{ID: taskID, Title: "Task"}, nil)
+		mockRevisionRepo.On("GetByTaskID", taskID.Hex(), pagination).Return(expectedRevisions, int64(1), nil)
+
+		// Act
+		revisions, total, err := taskUsecase.GetTaskRevisions(context.Background(), adminCaller, taskID.Hex(), pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedRevisions, revisions)
+		assert.Equal(t, int64(1), total)
+		mockRevisionRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - task hidden from caller", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockRevisionRepo := new(MockRevisionRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), mockRevisionRepo, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(&Domain.Task{ID: taskID, Title: "Task", CreatedBy: otherUserID}, nil)
+
+		// Act
+		revisions, _, err := taskUsecase.GetTaskRevisions(context.Background(), userCaller, taskID.Hex(), Domain.Pagination{})
+
+		// Assert
+		assert.Nil(t, revisions)
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		mockRevisionRepo.AssertNotCalled(t, "GetByTaskID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - sort is not supported", func(t *testing.T) {
+		// Arrange
+		taskUsecase := NewTaskUsecase(new(MockTaskRepository), new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		// Act
+		revisions, _, err := taskUsecase.GetTaskRevisions(context.Background(), adminCaller, taskID.Hex(), Domain.Pagination{Sort: Domain.SortPriority})
+
+		// Assert
+		assert.Nil(t, revisions)
+		assert.EqualError(t, err, "revisions are always sorted newest first")
+	})
+}
+
+func TestTaskUsecase_ExpectedVersion(t *testing.T) {
+	taskID := primitive.NewObjectID()
+	stale := 1
+
+	t.Run("Error - stale version on update is rejected before saving", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("GetByID", taskID.Hex()).Return(&Domain.Task{ID: taskID, Title: "Task", Status: Domain.StatusPending, Version: 2}, nil)
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskRequest{Title: "Task", Status: Domain.StatusPending, Version: &stale})
+
+		// Assert
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, Domain.ErrVersionConflict)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - stale version on patch is rejected before saving", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		title := "Renamed"
+		mockRepo.On("GetByID", taskID.Hex()).Return(&Domain.Task{ID: taskID, Title: "Task", Status: Domain.StatusPending, Version: 2}, nil)
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{Title: &title, Version: &stale})
+
+		// Assert
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, Domain.ErrVersionConflict)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - matching version is saved", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+
+		title := "Renamed"
+		current := 2
+		mockRepo.On("GetByID", taskID.Hex()).Return(&Domain.Task{ID: taskID, Title: "Task", Status: Domain.StatusPending, Version: 2}, nil)
+		mockRepo.On("Update", taskID.Hex(), mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.Version == 2 && task.Title == "Renamed"
+		})).Return(nil).Once()
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID.Hex(), Domain.TaskPatchRequest{Title: &title, Version: &current})
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_BusinessMetrics(t *testing.T) {
+	t.Run("Success - created tasks are counted by the creator's role", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockMetrics := new(MockBusinessMetrics)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), mockMetrics, DueDateRules{}, Infrastructure.NewNopLogger())
+
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+		mockMetrics.On("Increment", Infrastructure.MetricTasksCreated, map[string]string{Infrastructure.MetricLabelRole: Domain.RoleManager}).Once()
+
+		// Act
+		_, err := taskUsecase.CreateTask(context.Background(), managerCaller, Domain.TaskRequest{Title: "Counted", Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		mockMetrics.AssertExpectations(t)
+	})
+
+	t.Run("Success - completing a task counts the transition, the completer and the time taken", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockMetrics := new(MockBusinessMetrics)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), mockMetrics, DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		task := &Domain.Task{Title: "Task", Status: Domain.StatusInProgress, CreatedBy: primitive.NewObjectID(), CreatedAt: time.Now().Add(-time.Hour)}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+		mockRepo.On("Update", taskID, mock.AnythingOfType("*Domain.Task")).Return(nil)
+		mockMetrics.On("Increment", Infrastructure.MetricTaskStatusTransitions, map[string]string{
+			Infrastructure.MetricLabelFrom: Domain.StatusInProgress,
+			Infrastructure.MetricLabelTo:   Domain.StatusCompleted,
+		}).Once()
+		mockMetrics.On("Increment", Infrastructure.MetricTasksCompleted, map[string]string{Infrastructure.MetricLabelUserID: managerCaller.UserID}).Once()
+		mockMetrics.On("Observe", Infrastructure.MetricTaskCompletionSeconds, mock.MatchedBy(func(seconds float64) bool {
+			return seconds >= time.Hour.Seconds()
+		}), map[string]string(nil)).Once()
+
+		// Act
+		_, err := taskUsecase.UpdateTaskStatus(context.Background(), managerCaller, taskID, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		mockMetrics.AssertExpectations(t)
+	})
+
+	t.Run("Success - bulk status updates count every transition", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockMetrics := new(MockBusinessMetrics)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), mockMetrics, DueDateRules{}, Infrastructure.NewNopLogger())
+
+		moved := &Domain.Task{ID: primitive.NewObjectID(), Status: Domain.StatusCompleted, CreatedAt: time.Now().Add(-time.Hour)}
+		unchanged := &Domain.Task{ID: primitive.NewObjectID(), Status: Domain.StatusCompleted}
+		changes := []Domain.TaskStatusChange{{Task: moved, FromStatus: Domain.StatusInProgress}, {Task: unchanged, FromStatus: Domain.StatusCompleted}}
+		mockRepo.On("UpdateStatusMany", []primitive.ObjectID{moved.ID, unchanged.ID}, Domain.StatusCompleted, mock.Anything).Return(int64(2), int64(2), changes, nil)
+		mockMetrics.On("Increment", Infrastructure.MetricTaskStatusTransitions, map[string]string{
+			Infrastructure.MetricLabelFrom: Domain.StatusInProgress,
+			Infrastructure.MetricLabelTo:   Domain.StatusCompleted,
+		}).Once()
+		mockMetrics.On("Increment", Infrastructure.MetricTasksCompleted, map[string]string{Infrastructure.MetricLabelUserID: managerCaller.UserID}).Once()
+		mockMetrics.On("Observe", Infrastructure.MetricTaskCompletionSeconds, mock.Anything, map[string]string(nil)).Once()
+
+		// Act
+		_, err := taskUsecase.UpdateTasksStatus(context.Background(), managerCaller, []string{moved.ID.Hex(), unchanged.ID.Hex()}, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		mockMetrics.AssertExpectations(t)
+	})
+
+	t.Run("Success - updates that keep the status count nothing", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockMetrics := new(MockBusinessMetrics)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), mockMetrics, DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		title := "Renamed"
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Task", Status: Domain.StatusPending}, nil)
+		mockRepo.On("Update", taskID, mock.AnythingOfType("*Domain.Task")).Return(nil)
+
+		// Act
+		_, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{Title: &title})
+
+		// Assert
+		assert.NoError(t, err)
+		mockMetrics.AssertNotCalled(t, "Increment", mock.Anything, mock.Anything)
+		mockMetrics.AssertNotCalled(t, "Observe", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - deleted tasks are counted by the caller's role", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockMetrics := new(MockBusinessMetrics)
+		mockCommentRepo := new(MockCommentRepository)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), mockMetrics, DueDateRules{}, Infrastructure.NewNopLogger())
+
+		taskID := primitive.NewObjectID().Hex()
+		mockRepo.On("GetByID", taskID).Return(&Domain.Task{Title: "Task"}, nil)
+		mockCommentRepo.On("DeleteByTaskID", taskID).Return(nil)
+		mockRepo.On("GetByParentID", taskID).Return([]*Domain.Task{}, nil)
+		mockRepo.On("Delete", taskID).Return(nil)
+		mockMetrics.On("Increment", Infrastructure.MetricTasksDeleted, map[string]string{Infrastructure.MetricLabelRole: Domain.RoleAdmin}).Once()
+
+		// Act
+		err := taskUsecase.DeleteTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockMetrics.AssertExpectations(t)
+	})
+}
+
+func TestTaskUsecase_DueDateRules(t *testing.T) {
+	newUsecase := func(mockRepo *MockTaskRepository, rules DueDateRules) TaskUsecaseInterface {
+		return NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), rules, Infrastructure.NewNopLogger())
+	}
+	pastDay := time.Now().AddDate(0, 0, -7).Format(DueDateLayout)
+	farDay := time.Now().AddDate(5, 0, 0).Format(DueDateLayout)
+	nextWeek := time.Now().AddDate(0, 0, 7).Format(DueDateLayout)
+
+	t.Run("Success - default rules accept past and distant due dates", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{})
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil).Twice()
+
+		// Act
+		_, pastErr := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Past", DueDate: "1970-01-01", Status: Domain.StatusPending}, nil)
+		_, farErr := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Far", DueDate: "2999-01-01", Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.NoError(t, pastErr)
+		assert.NoError(t, farErr)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - past due date refused on create", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{RejectPast: true})
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Past", DueDate: pastDay, Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, Domain.ErrDueDateInPast)
+		assert.ErrorIs(t, err, Domain.ErrDueDateOutOfRange)
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - due date today is not in the past", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{RejectPast: true})
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil).Once()
+
+		// Act
+		_, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Today", DueDate: time.Now().Format(DueDateLayout), Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - distant due date refused on create", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{MaxYearsAhead: 2})
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Far", DueDate: farDay, Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, Domain.ErrDueDateTooFar)
+		assert.EqualError(t, err, "due date must be at most 2 years from now")
+		mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - bulk create reports refused due dates by index", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{RejectPast: true, MaxYearsAhead: 2})
+		mockRepo.On("CreateMany", mock.MatchedBy(func(tasks []*Domain.Task) bool {
+			return len(tasks) == 1 && tasks[0].Title == "Soon"
+		})).Return(nil).Once()
+
+		// Act
+		result, err := taskUsecase.CreateTasks(context.Background(), adminCaller, []Domain.TaskRequest{
+			{Title: "Past", DueDate: pastDay, Status: Domain.StatusPending},
+			{Title: "Soon", DueDate: nextWeek, Status: Domain.StatusPending},
+			{Title: "Far", DueDate: farDay, Status: Domain.StatusPending},
+		}, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []Domain.BulkItemError{
+			{Index: 0, Error: "due date must not be in the past"},
+			{Index: 2, Error: "due date must be at most 2 years from now"},
+		}, result.Errors)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - update setting a new past due date is refused", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{RejectPast: true})
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", Status: Domain.StatusPending}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, Domain.TaskRequest{Title: "Task", DueDate: pastDay, Status: Domain.StatusPending})
+
+		// Assert
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, Domain.ErrDueDateInPast)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - update keeping an overdue due date is accepted", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{RejectPast: true})
+
+		taskID := primitive.NewObjectID().Hex()
+		overdue := time.Date(2020, 3, 1, 23, 59, 59, 0, time.UTC)
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", DueDate: overdue, Status: Domain.StatusPending}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, mock.MatchedBy(func(task *Domain.Task) bool {
+			return task.Title == "Renamed" && task.DueDate.Equal(overdue)
+		})).Return(nil).Once()
+
+		// Act
+		task, err := taskUsecase.UpdateTask(context.Background(), adminCaller, taskID, Domain.TaskRequest{Title: "Renamed", DueDate: "2020-03-01", Status: Domain.StatusPending})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - patch leaving an overdue due date alone is accepted", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{RejectPast: true, MaxYearsAhead: 2})
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", DueDate: time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC), Status: Domain.StatusPending}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil)
+		mockRepo.On("Update", taskID, mock.AnythingOfType("*Domain.Task")).Return(nil).Once()
+
+		title := "Renamed"
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{Title: &title})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, task)
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - patch moving the due date too far ahead is refused", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		taskUsecase := newUsecase(mockRepo, DueDateRules{MaxYearsAhead: 2})
+
+		taskID := primitive.NewObjectID().Hex()
+		existingTask := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", Status: Domain.StatusPending}
+		mockRepo.On("GetByID", taskID).Return(existingTask, nil).Once()
+
+		// Act
+		task, err := taskUsecase.PatchTask(context.Background(), adminCaller, taskID, Domain.TaskPatchRequest{DueDate: &farDay})
+
+		// Assert
+		assert.Nil(t, task)
+		assert.ErrorIs(t, err, Domain.ErrDueDateTooFar)
+		mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+}
+
+func TestDueDateRulesFromEnv(t *testing.T) {
+	t.Run("Success - defaults accept any due date", func(t *testing.T) {
+		t.Setenv("TASKS_REJECT_PAST_DUE_DATES", "")
+		t.Setenv("TASKS_MAX_DUE_DATE_YEARS", "")
+
+		assert.Equal(t, DueDateRules{}, DueDateRulesFromEnv())
+	})
+
+	t.Run("Success - configured", func(t *testing.T) {
+		t.Setenv("TASKS_REJECT_PAST_DUE_DATES", "true")
+		t.Setenv("TASKS_MAX_DUE_DATE_YEARS", "10")
+
+		assert.Equal(t, DueDateRules{RejectPast: true, MaxYearsAhead: 10}, DueDateRulesFromEnv())
+	})
+
+	t.Run("Success - invalid values are ignored", func(t *testing.T) {
+		t.Setenv("TASKS_REJECT_PAST_DUE_DATES", "sometimes")
+		t.Setenv("TASKS_MAX_DUE_DATE_YEARS", "-1")
+
+		assert.Equal(t, DueDateRules{}, DueDateRulesFromEnv())
+	})
+}
@@ -1,7 +1,18 @@
 package Usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"task_manager/Domain"
 	"task_manager/Infrastructure"
@@ -10,39 +21,161 @@ import (
 
 // UserUsecaseInterface defines the contract for user business logic
 type UserUsecaseInterface interface {
-	RegisterUser(userReq Domain.UserRequest) (*Domain.User, error)
-	LoginUser(loginReq Domain.LoginRequest) (*Domain.User, string, error)
-	GetUserProfile(userID string) (*Domain.User, error)
-	GetAllUsers() ([]*Domain.User, error)
-	PromoteUserToAdmin(username string) (*Domain.User, error)
+	RegisterUser(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error)
+	LoginUser(ctx context.Context, loginReq Domain.LoginRequest) (*Domain.User, *Domain.AuthTokens, error)
+	RefreshTokens(ctx context.Context, refreshToken string) (*Domain.User, *Domain.AuthTokens, error)
+	Logout(ctx context.Context, caller Domain.Caller, tokenID string, expiresAt time.Time, refreshToken string) error
+	ChangePassword(ctx context.Context, caller Domain.Caller, currentPassword, newPassword string) error
+	GetUserProfile(ctx context.Context, userID string) (*Domain.User, error)
+	UpdateProfile(ctx context.Context, userID string, req Domain.ProfileUpdateRequest) (*Domain.User, error)
+	GetAllUsers(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error)
+	GetUserDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error)
+	ExportUsers(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error
+	GetLoginHistory(ctx context.Context, caller Domain.Caller) ([]*Domain.LoginEvent, error)
+	PromoteUser(ctx context.Context, caller Domain.Caller, username, role string) (*Domain.User, error)
+	DemoteAdminToUser(ctx context.Context, caller Domain.Caller, username string) (*Domain.User, error)
+	DeleteUser(ctx context.Context, caller Domain.Caller, userID string, anonymize bool) (*Domain.UserDeletionResult, error)
+	DeactivateUser(ctx context.Context, caller Domain.Caller, userID string) (*Domain.User, error)
+	ActivateUser(ctx context.Context, caller Domain.Caller, userID string) (*Domain.User, error)
+	VerifyEmail(ctx context.Context, token string) (*Domain.User, error)
+	SeedAdmin(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, bool, error)
+	CreateAdmin(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error)
 }
 
+// MaxLoginHistory is the number of recent logins returned by GetLoginHistory
+const MaxLoginHistory = 20
+
 // UserUsecase implements user business logic
 type UserUsecase struct {
-	userRepo        Repositories.UserRepositoryInterface
-	passwordService Infrastructure.PasswordServiceInterface
-	jwtService      Infrastructure.JWTServiceInterface
+	userRepo         Repositories.UserRepositoryInterface
+	taskRepo         Repositories.TaskRepositoryInterface
+	refreshTokenRepo Repositories.RefreshTokenRepositoryInterface
+	tokenBlacklist   Infrastructure.TokenBlacklist
+	passwordService  Infrastructure.PasswordServiceInterface
+	passwordPolicy   Infrastructure.PasswordPolicyInterface
+	jwtService       Infrastructure.JWTServiceInterface
+	notifier         Infrastructure.Notifier
+	auditRepo        Repositories.AuditRepositoryInterface
+	loginEventRepo   Repositories.LoginEventRepositoryInterface
+	txManager        Infrastructure.TransactionManager
+	events           Infrastructure.EventBus
+	metrics          Infrastructure.BusinessMetrics
+	requireEmail     bool
+	firstUserAdmin   bool
+	logger           *slog.Logger
 }
 
-// NewUserUsecase creates a new instance of UserUsecase
+// NewUserUsecase creates a new instance of UserUsecase.
+// Registration requires an email address when REGISTRATION_REQUIRE_EMAIL is true; by default it is optional.
+// The first user to register becomes an admin unless ADMIN_USERNAME or ADMIN_PASSWORD is set, in which
+// case the admin is seeded at startup instead (see SeedAdmin). Registrations and promotions are published to events;
+// registrations and refused logins are counted in metrics.
 func NewUserUsecase(
 	userRepo Repositories.UserRepositoryInterface,
+	taskRepo Repositories.TaskRepositoryInterface,
+	refreshTokenRepo Repositories.RefreshTokenRepositoryInterface,
+	tokenBlacklist Infrastructure.TokenBlacklist,
 	passwordService Infrastructure.PasswordServiceInterface,
+	passwordPolicy Infrastructure.PasswordPolicyInterface,
 	jwtService Infrastructure.JWTServiceInterface,
+	notifier Infrastructure.Notifier,
+	auditRepo Repositories.AuditRepositoryInterface,
+	loginEventRepo Repositories.LoginEventRepositoryInterface,
+	txManager Infrastructure.TransactionManager,
+	events Infrastructure.EventBus,
+	metrics Infrastructure.BusinessMetrics,
+	logger *slog.Logger,
 ) UserUsecaseInterface {
+	requireEmail, _ := strconv.ParseBool(os.Getenv("REGISTRATION_REQUIRE_EMAIL"))
+	firstUserAdmin := os.Getenv("ADMIN_USERNAME") == "" && os.Getenv("ADMIN_PASSWORD") == ""
+
 	return &UserUsecase{
-		userRepo:        userRepo,
-		passwordService: passwordService,
-		jwtService:      jwtService,
+		userRepo:         userRepo,
+		taskRepo:         taskRepo,
+		refreshTokenRepo: refreshTokenRepo,
+		tokenBlacklist:   tokenBlacklist,
+		passwordService:  passwordService,
+		passwordPolicy:   passwordPolicy,
+		jwtService:       jwtService,
+		notifier:         notifier,
+		auditRepo:        auditRepo,
+		loginEventRepo:   loginEventRepo,
+		txManager:        txManager,
+		events:           events,
+		metrics:          metrics,
+		requireEmail:     requireEmail,
+		firstUserAdmin:   firstUserAdmin,
+		logger:           logger,
+	}
+}
+
+// RegisterUser creates a new user. The username is trimmed and must be unique regardless of case;
+// the password must satisfy the password policy. An email address must be unique too; it starts out
+// unverified and a verification token is sent to it.
+func (uu *UserUsecase) RegisterUser(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error) {
+	return uu.registerUser(ctx, userReq, "")
+}
+
+// SeedAdmin creates an admin from userReq, validated and hashed like any registration, unless an admin
+// already exists. It reports whether it created one, so calling it on every start is safe.
+func (uu *UserUsecase) SeedAdmin(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, bool, error) {
+	adminCount, err := uu.userRepo.CountByRole(ctx, Domain.RoleAdmin)
+	if err != nil {
+		return nil, false, err
+	}
+	if adminCount > 0 {
+		return nil, false, nil
+	}
+
+	user, err := uu.CreateAdmin(ctx, userReq)
+	if errors.Is(err, Domain.ErrUsernameTaken) {
+		// Another instance starting at the same time may have just seeded the same admin
+		existing, lookupErr := uu.userRepo.GetByUsername(ctx, Domain.NormalizeUsername(strings.TrimSpace(userReq.Username)))
+		if lookupErr == nil && existing.Role == Domain.RoleAdmin {
+			return nil, false, nil
+		}
+	}
+	if err != nil {
+		return nil, false, err
 	}
+	return user, true, nil
 }
 
-// RegisterUser creates a new user
-func (uu *UserUsecase) RegisterUser(userReq Domain.UserRequest) (*Domain.User, error) {
+// CreateAdmin creates an admin from userReq, validated and hashed like any registration,
+// whether or not other admins exist
+func (uu *UserUsecase) CreateAdmin(ctx context.Context, userReq Domain.UserRequest) (*Domain.User, error) {
+	return uu.registerUser(ctx, userReq, Domain.RoleAdmin)
+}
+
+// registerUser validates and creates a user with the given role. An empty role makes the first
+// user an admin, when that rule is enabled, and everyone else a user.
+func (uu *UserUsecase) registerUser(ctx context.Context, userReq Domain.UserRequest, role string) (*Domain.User, error) {
+	selfRegistered := role == ""
+
+	username := strings.TrimSpace(userReq.Username)
+	if err := validateUsername(username); err != nil {
+		return nil, err
+	}
+
+	email := Domain.NormalizeEmail(userReq.Email)
+	if email == "" && uu.requireEmail {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "email is required")
+	}
+
+	if err := uu.passwordPolicy.Validate(userReq.Password); err != nil {
+		return nil, err
+	}
+
 	// Check if username already exists
-	existingUser, _ := uu.userRepo.GetByUsername(userReq.Username)
+	existingUser, _ := uu.userRepo.GetByUsername(ctx, Domain.NormalizeUsername(username))
 	if existingUser != nil {
-		return nil, errors.New("username already exists")
+		return nil, Domain.ErrUsernameTaken
+	}
+
+	if email != "" {
+		if existingUser, _ := uu.userRepo.GetByEmail(ctx, email); existingUser != nil {
+			return nil, Domain.ErrEmailTaken
+		}
 	}
 
 	// Hash the password
@@ -51,80 +184,631 @@ func (uu *UserUsecase) RegisterUser(userReq Domain.UserRequest) (*Domain.User, e
 		return nil, errors.New("failed to hash password")
 	}
 
-	// Check if this is the first user (make them admin)
-	userCount, err := uu.userRepo.CountUsers()
+	// The first user becomes the admin. Concurrent first registrations all count zero users, so each
+	// is created as a regular user and then claims the admin role, which only one of them gets.
+	claimAdmin := false
+	if role == "" {
+		role = Domain.RoleUser
+
+		if uu.firstUserAdmin {
+			userCount, err := uu.userRepo.CountUsers(ctx)
+			if err != nil {
+				return nil, err
+			}
+			claimAdmin = userCount == 0
+		}
+	}
+
+	user := &Domain.User{
+		Username:      username,
+		UsernameLower: Domain.NormalizeUsername(username),
+		Email:         email,
+		Password:      hashedPassword,
+		Role:          role,
+		IsActive:      true,
+	}
+
+	err = uu.userRepo.Create(ctx, user)
 	if err != nil {
 		return nil, err
 	}
 
-	role := Domain.RoleUser
-	if userCount == 0 {
-		role = Domain.RoleAdmin
+	if claimAdmin {
+		// The user exists by now, so a failed claim leaves them a regular user rather than failing the registration
+		claimed, err := uu.userRepo.ClaimFirstAdmin(ctx, user.ID.Hex())
+		if err != nil {
+			uu.logger.ErrorContext(ctx, "failed to make the first user an admin", "user_id", user.ID.Hex(), "error", err)
+		}
+		if claimed {
+			user.Role = Domain.RoleAdmin
+			user.FirstAdmin = true
+		}
 	}
 
-	user := &Domain.User{
-		Username: userReq.Username,
-		Password: hashedPassword,
-		Role:     role,
+	uu.metrics.Increment(Infrastructure.MetricUsersRegistered, map[string]string{Infrastructure.MetricLabelRole: user.Role})
+
+	// Users registering themselves are the actor; admins created by the admin commands have none
+	actorID := ""
+	if selfRegistered {
+		actorID = user.ID.Hex()
+	}
+	uu.events.Publish(ctx, Domain.NewEvent(Domain.EventUserRegistered, actorID, user))
+
+	if user.Email != "" {
+		uu.sendEmailVerification(ctx, user)
+	}
+
+	return user, nil
+}
+
+// sendEmailVerification sends a verification token to the user's email address. Failures are
+// logged rather than returned, so they never undo a registration.
+func (uu *UserUsecase) sendEmailVerification(ctx context.Context, user *Domain.User) {
+	token, expiresAt, err := uu.jwtService.GenerateEmailVerificationToken(user)
+	if err != nil {
+		uu.logger.ErrorContext(ctx, "failed to generate email verification token", "user_id", user.ID.Hex(), "error", err)
+		return
+	}
+	if err := uu.notifier.SendEmailVerification(ctx, user, token, expiresAt); err != nil {
+		uu.logger.ErrorContext(ctx, "failed to send email verification", "user_id", user.ID.Hex(), "error", err)
+	}
+}
+
+// VerifyEmail consumes an email verification token and marks the address it names as verified.
+// A token for an address the user has since replaced is invalid. Verifying twice succeeds.
+func (uu *UserUsecase) VerifyEmail(ctx context.Context, token string) (*Domain.User, error) {
+	claims, err := uu.jwtService.ValidateEmailVerificationToken(token)
+	if err != nil {
+		return nil, err
 	}
 
-	err = uu.userRepo.Create(user)
+	user, err := uu.userRepo.GetByID(ctx, claims.UserID)
 	if err != nil {
+		if errors.Is(err, Domain.ErrUserNotFound) {
+			return nil, Domain.ErrInvalidVerificationToken
+		}
 		return nil, err
 	}
+	if user.Email != claims.Email {
+		return nil, Domain.ErrInvalidVerificationToken
+	}
+	if user.EmailVerified {
+		return user, nil
+	}
 
+	if err := uu.userRepo.MarkEmailVerified(ctx, claims.UserID, claims.Email); err != nil {
+		if errors.Is(err, Domain.ErrUserNotFound) {
+			return nil, Domain.ErrInvalidVerificationToken
+		}
+		return nil, err
+	}
+
+	user.EmailVerified = true
 	return user, nil
 }
 
-// LoginUser authenticates a user and returns user info with JWT token
-func (uu *UserUsecase) LoginUser(loginReq Domain.LoginRequest) (*Domain.User, string, error) {
-	user, err := uu.userRepo.GetByUsername(loginReq.Username)
+// LoginUser authenticates a user and returns user info with an access and refresh token.
+// Each login starts a new refresh token family. A password hashed with a lower bcrypt cost
+// than configured is re-hashed, so accounts move to the new cost as their users log in.
+func (uu *UserUsecase) LoginUser(ctx context.Context, loginReq Domain.LoginRequest) (*Domain.User, *Domain.AuthTokens, error) {
+	user, err := uu.userRepo.GetByUsername(ctx, Domain.NormalizeUsername(loginReq.Username))
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		uu.countLoginFailure(Infrastructure.LoginFailureInvalidCredentials)
+		return nil, nil, Domain.ErrInvalidCredentials
 	}
 
 	// Compare password with hash
 	err = uu.passwordService.ComparePassword(user.Password, loginReq.Password)
 	if err != nil {
-		return nil, "", errors.New("invalid credentials")
+		uu.countLoginFailure(Infrastructure.LoginFailureInvalidCredentials)
+		return nil, nil, Domain.ErrInvalidCredentials
 	}
 
-	// Generate JWT token
-	token, err := uu.jwtService.GenerateToken(user)
+	// Checked after the password so the response does not reveal which accounts exist
+	if !user.IsActive {
+		uu.countLoginFailure(Infrastructure.LoginFailureDeactivated)
+		return nil, nil, Domain.ErrAccountDeactivated
+	}
+
+	if uu.passwordService.NeedsRehash(user.Password) {
+		uu.rehashPassword(ctx, user, loginReq.Password)
+	}
+
+	tokens, err := uu.issueTokens(ctx, user, primitive.NewObjectID().Hex())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	uu.recordLogin(ctx, user, loginReq)
+
+	return user, tokens, nil
+}
+
+// countLoginFailure counts a refused login for the given reason
+func (uu *UserUsecase) countLoginFailure(reason string) {
+	uu.metrics.Increment(Infrastructure.MetricLoginFailures, map[string]string{Infrastructure.MetricLabelReason: reason})
+}
+
+// recordLogin stores the login time on the user and appends the login to their history.
+// Neither write may fail the login, so errors are only logged.
+func (uu *UserUsecase) recordLogin(ctx context.Context, user *Domain.User, loginReq Domain.LoginRequest) {
+	now := time.Now()
+	if err := uu.userRepo.UpdateLastLogin(ctx, user.ID.Hex(), now); err != nil {
+		uu.logger.WarnContext(ctx, "failed to record last login", "user_id", user.ID.Hex(), "error", err)
+	} else {
+		user.LastLoginAt = &now
+	}
+
+	event := &Domain.LoginEvent{
+		UserID:    user.ID,
+		IP:        loginReq.IP,
+		UserAgent: loginReq.UserAgent,
+		Timestamp: now,
+	}
+	if err := uu.loginEventRepo.Create(ctx, event); err != nil {
+		uu.logger.WarnContext(ctx, "failed to record login event", "user_id", user.ID.Hex(), "error", err)
+	}
+}
+
+// RefreshTokens exchanges a refresh token for a new token pair, revoking the one presented.
+// Presenting a token that was already rotated or revoked means it leaked, so the whole
+// family is revoked and the caller has to log in again.
+func (uu *UserUsecase) RefreshTokens(ctx context.Context, refreshToken string) (*Domain.User, *Domain.AuthTokens, error) {
+	stored, err := uu.refreshTokenRepo.GetByHash(ctx, Infrastructure.HashRefreshToken(refreshToken))
+	if err != nil {
+		return nil, nil, Domain.ErrInvalidRefreshToken
+	}
+
+	if stored.RevokedAt != nil {
+		uu.revokeFamily(ctx, stored)
+		return nil, nil, Domain.ErrRefreshTokenReused
+	}
+
+	if time.Now().After(stored.ExpiresAt) {
+		return nil, nil, Domain.ErrRefreshTokenExpired
+	}
+
+	// Revoke fails when another request rotated the token first, which is reuse as well
+	if err := uu.refreshTokenRepo.Revoke(ctx, stored.ID); err != nil {
+		uu.revokeFamily(ctx, stored)
+		return nil, nil, Domain.ErrRefreshTokenReused
+	}
+
+	// Reload the user so the new access token carries their current role
+	user, err := uu.userRepo.GetByID(ctx, stored.UserID.Hex())
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, nil, Domain.ErrInvalidRefreshToken
+	}
+
+	if !user.IsActive {
+		return nil, nil, Domain.ErrAccountDeactivated
+	}
+
+	tokens, err := uu.issueTokens(ctx, user, stored.FamilyID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, tokens, nil
+}
+
+// Logout revokes the caller's access token until it expires. When the caller also sends their
+// refresh token, every refresh token from that login is revoked too; unknown or foreign
+// refresh tokens are ignored so logging out twice is harmless.
+func (uu *UserUsecase) Logout(ctx context.Context, caller Domain.Caller, tokenID string, expiresAt time.Time, refreshToken string) error {
+	if err := uu.tokenBlacklist.Add(ctx, tokenID, expiresAt); err != nil {
+		return err
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+	stored, err := uu.refreshTokenRepo.GetByHash(ctx, Infrastructure.HashRefreshToken(refreshToken))
+	if err != nil || stored.UserID.Hex() != caller.UserID {
+		return nil
+	}
+	return uu.refreshTokenRepo.RevokeFamily(ctx, stored.FamilyID)
+}
+
+// rehashPassword stores the password hashed with the configured cost. Failures are only
+// logged: the old hash still works, so the upgrade is retried on the next login.
+func (uu *UserUsecase) rehashPassword(ctx context.Context, user *Domain.User, password string) {
+	hashedPassword, err := uu.passwordService.HashPassword(password)
+	if err != nil {
+		uu.logger.WarnContext(ctx, "failed to rehash password", "user_id", user.ID.Hex(), "error", err)
+		return
+	}
+
+	oldHash := user.Password
+	user.Password = hashedPassword
+	if err := uu.userRepo.Update(ctx, user.ID.Hex(), user); err != nil {
+		user.Password = oldHash
+		uu.logger.WarnContext(ctx, "failed to store rehashed password", "user_id", user.ID.Hex(), "error", err)
+	}
+}
+
+// issueTokens mints an access token and a refresh token in the given family, storing the refresh token's hash
+func (uu *UserUsecase) issueTokens(ctx context.Context, user *Domain.User, familyID string) (*Domain.AuthTokens, error) {
+	accessToken, err := uu.jwtService.GenerateToken(user)
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	refreshToken, expiresAt, err := uu.jwtService.GenerateRefreshToken()
+	if err != nil {
+		return nil, errors.New("failed to generate token")
+	}
+
+	err = uu.refreshTokenRepo.Create(ctx, &Domain.RefreshToken{
+		UserID:    user.ID,
+		FamilyID:  familyID,
+		TokenHash: Infrastructure.HashRefreshToken(refreshToken),
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return user, token, nil
+	return &Domain.AuthTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresIn:    int64(uu.jwtService.AccessTokenTTL() / time.Second),
+	}, nil
+}
+
+// revokeFamily revokes every token descended from the same login after a reused refresh token
+func (uu *UserUsecase) revokeFamily(ctx context.Context, token *Domain.RefreshToken) {
+	uu.logger.WarnContext(ctx, "refresh token reuse detected, revoking token family",
+		"user_id", token.UserID.Hex(), "family_id", token.FamilyID)
+	if err := uu.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID); err != nil {
+		uu.logger.ErrorContext(ctx, "failed to revoke refresh token family", "family_id", token.FamilyID, "error", err)
+	}
+}
+
+// ChangePassword replaces the caller's password after checking their current one, then
+// revokes all their refresh tokens so every other login has to sign in again
+func (uu *UserUsecase) ChangePassword(ctx context.Context, caller Domain.Caller, currentPassword, newPassword string) error {
+	if err := uu.passwordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	user, err := uu.userRepo.GetByID(ctx, caller.UserID)
+	if err != nil {
+		return err
+	}
+
+	if err := uu.passwordService.ComparePassword(user.Password, currentPassword); err != nil {
+		return Domain.ErrIncorrectPassword
+	}
+
+	hashedPassword, err := uu.passwordService.HashPassword(newPassword)
+	if err != nil {
+		return errors.New("failed to hash password")
+	}
+
+	user.Password = hashedPassword
+	if err := uu.userRepo.Update(ctx, user.ID.Hex(), user); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, uu.logger, uu.auditRepo, caller, Domain.AuditActionChangePassword, Domain.AuditEntityUser, user.ID.Hex(), "")
+
+	// The password is already changed, so a failure here is logged rather than reported
+	if err := uu.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uu.logger.ErrorContext(ctx, "failed to revoke refresh tokens after password change", "user_id", user.ID.Hex(), "error", err)
+	}
+	return nil
 }
 
 // GetUserProfile returns user profile by ID
-func (uu *UserUsecase) GetUserProfile(userID string) (*Domain.User, error) {
-	return uu.userRepo.GetByID(userID)
+func (uu *UserUsecase) GetUserProfile(ctx context.Context, userID string) (*Domain.User, error) {
+	return uu.userRepo.GetByID(ctx, userID)
+}
+
+// UpdateProfile changes the user's own username and email address. Omitted fields keep their value.
+// The username follows the registration rules; a new email address must be unique, starts out
+// unverified and is sent a verification token. Tokens issued afterwards carry the new username.
+func (uu *UserUsecase) UpdateProfile(ctx context.Context, userID string, req Domain.ProfileUpdateRequest) (*Domain.User, error) {
+	user, err := uu.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []string
+
+	if username := strings.TrimSpace(req.Username); username != "" && username != user.Username {
+		if err := validateUsername(username); err != nil {
+			return nil, err
+		}
+		// Changing only the case keeps the same canonical name, which the user already owns
+		if Domain.NormalizeUsername(username) != Domain.NormalizeUsername(user.Username) {
+			if existingUser, _ := uu.userRepo.GetByUsername(ctx, Domain.NormalizeUsername(username)); existingUser != nil && existingUser.ID != user.ID {
+				return nil, Domain.ErrUsernameTaken
+			}
+		}
+		changes = append(changes, fmt.Sprintf("username: %s -> %s", user.Username, username))
+		user.Username = username
+		user.UsernameLower = Domain.NormalizeUsername(username)
+	}
+
+	emailChanged := false
+	if email := Domain.NormalizeEmail(req.Email); email != "" && email != user.Email {
+		if existingUser, _ := uu.userRepo.GetByEmail(ctx, email); existingUser != nil && existingUser.ID != user.ID {
+			return nil, Domain.ErrEmailTaken
+		}
+		changes = append(changes, "email changed")
+		user.Email = email
+		user.EmailVerified = false
+		emailChanged = true
+	}
+
+	if len(changes) == 0 {
+		return user, nil
+	}
+
+	if err := uu.userRepo.UpdateProfile(ctx, userID, user); err != nil {
+		return nil, err
+	}
+
+	caller := Domain.Caller{UserID: userID, Role: user.Role}
+	recordAudit(ctx, uu.logger, uu.auditRepo, caller, Domain.AuditActionUpdateProfile, Domain.AuditEntityUser, userID, strings.Join(changes, ", "))
+
+	if emailChanged {
+		uu.sendEmailVerification(ctx, user)
+	}
+
+	return user, nil
+}
+
+// GetAllUsers returns one page of the users matching the filter (admin only) and the total number of matches
+func (uu *UserUsecase) GetAllUsers(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	return uu.userRepo.GetAll(ctx, filter, pagination)
+}
+
+// GetUserDirectory returns one page of the IDs and usernames of the users whose username contains
+// query, and the total number of matches. Any signed-in user may read it, so nothing else is loaded.
+func (uu *UserUsecase) GetUserDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	return uu.userRepo.GetDirectory(ctx, query, pagination)
+}
+
+// ExportUsers calls fn for every user matching the filter (admin only), oldest account first.
+// Password hashes are never loaded.
+func (uu *UserUsecase) ExportUsers(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	return uu.userRepo.Stream(ctx, filter, fn)
+}
+
+// GetLoginHistory returns the caller's most recent logins, newest first
+func (uu *UserUsecase) GetLoginHistory(ctx context.Context, caller Domain.Caller) ([]*Domain.LoginEvent, error) {
+	userID, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	return uu.loginEventRepo.GetRecentByUserID(ctx, userID, MaxLoginHistory)
+}
+
+// PromoteUser grants a user the manager or admin role on behalf of the calling admin.
+// An empty role promotes to admin. Admins are taken down a role with DemoteAdminToUser.
+// The role change and its audit entry are written in one transaction.
+func (uu *UserUsecase) PromoteUser(ctx context.Context, caller Domain.Caller, username, role string) (*Domain.User, error) {
+	if role == "" {
+		role = Domain.RoleAdmin
+	}
+	if role != Domain.RoleManager && role != Domain.RoleAdmin {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "invalid role, must be one of: manager, admin")
+	}
+
+	var promoted *Domain.User
+	var previousRole string
+	err := uu.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := uu.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		if user.Role == Domain.RoleAdmin {
+			return Domain.ErrAlreadyAdmin
+		}
+		if user.Role == role {
+			return Domain.NewError(Domain.ErrAlreadyHasRole, "user is already a %s", role)
+		}
+
+		previousRole = user.Role
+		user.Role = role
+		err = uu.userRepo.UpdateByUsername(ctx, username, user)
+		if err != nil {
+			return err
+		}
+
+		recordAudit(ctx, uu.logger, uu.auditRepo, caller, Domain.AuditActionPromote, Domain.AuditEntityUser, user.ID.Hex(), fmt.Sprintf("role: %s -> %s", previousRole, role))
+
+		// Return updated user
+		promoted, err = uu.userRepo.GetByUsername(ctx, username)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Published only once the transaction has committed, so subscribers never hear of a rolled back promotion
+	uu.events.Publish(ctx, Domain.NewEvent(Domain.EventUserPromoted, caller.UserID, Domain.PromotedUserEvent{User: promoted, PreviousRole: previousRole}))
+	return promoted, nil
 }
 
-// GetAllUsers returns all users (admin only)
-func (uu *UserUsecase) GetAllUsers() ([]*Domain.User, error) {
-	return uu.userRepo.GetAll()
+// DemoteAdminToUser demotes an admin or manager to user role on behalf of the calling admin.
+// Admins cannot demote themselves, and the last remaining admin cannot be demoted. The admin count,
+// the role change and its audit entry run in one transaction.
+func (uu *UserUsecase) DemoteAdminToUser(ctx context.Context, caller Domain.Caller, username string) (*Domain.User, error) {
+	var demoted *Domain.User
+	err := uu.txManager.WithTransaction(ctx, func(ctx context.Context) error {
+		user, err := uu.userRepo.GetByUsername(ctx, username)
+		if err != nil {
+			return err
+		}
+
+		if user.Role == Domain.RoleUser {
+			return Domain.ErrNotPrivileged
+		}
+
+		if user.ID.Hex() == caller.UserID {
+			return Domain.ErrSelfDemotion
+		}
+
+		if user.Role == Domain.RoleAdmin {
+			admins, err := uu.userRepo.CountByRole(ctx, Domain.RoleAdmin)
+			if err != nil {
+				return err
+			}
+			if admins <= 1 {
+				return Domain.ErrLastAdminDemotion
+			}
+		}
+
+		previousRole := user.Role
+		user.Role = Domain.RoleUser
+		err = uu.userRepo.UpdateByUsername(ctx, username, user)
+		if err != nil {
+			return err
+		}
+
+		recordAudit(ctx, uu.logger, uu.auditRepo, caller, Domain.AuditActionDemote, Domain.AuditEntityUser, user.ID.Hex(), fmt.Sprintf("role: %s -> %s", previousRole, Domain.RoleUser))
+
+		// Return updated user
+		demoted, err = uu.userRepo.GetByUsername(ctx, username)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return demoted, nil
 }
 
-// PromoteUserToAdmin promotes a user to admin role
-func (uu *UserUsecase) PromoteUserToAdmin(username string) (*Domain.User, error) {
-	user, err := uu.userRepo.GetByUsername(username)
+// DeleteUser removes a user on behalf of the calling admin. A hard delete hands the tasks the
+// user created to the caller and unassigns the tasks assigned to them, so no task points at a
+// missing user. Anonymizing keeps the record, and with it every task reference, but scrubs the
+// user's personal data. Either way the user's refresh tokens are revoked. Admins cannot delete
+// themselves, and the last remaining admin cannot be deleted. An API key has no user to hand the
+// tasks to, so it can only anonymize.
+func (uu *UserUsecase) DeleteUser(ctx context.Context, caller Domain.Caller, userID string, anonymize bool) (*Domain.UserDeletionResult, error) {
+	if userID == caller.UserID {
+		return nil, Domain.ErrSelfDeletion
+	}
+	if caller.APIKey && !anonymize {
+		return nil, Domain.ErrReassignToAPIKey
+	}
+
+	user, err := uu.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
 	if user.Role == Domain.RoleAdmin {
-		return nil, errors.New("user is already an admin")
+		admins, err := uu.userRepo.CountByRole(ctx, Domain.RoleAdmin)
+		if err != nil {
+			return nil, err
+		}
+		if admins <= 1 {
+			return nil, Domain.ErrLastAdminDeletion
+		}
 	}
 
-	user.Role = Domain.RoleAdmin
-	err = uu.userRepo.UpdateByUsername(username, user)
+	result := &Domain.UserDeletionResult{Anonymized: anonymize}
+	if anonymize {
+		if err := uu.userRepo.Anonymize(ctx, userID); err != nil {
+			return nil, err
+		}
+		recordAudit(ctx, uu.logger, uu.auditRepo, caller, Domain.AuditActionAnonymize, Domain.AuditEntityUser, userID, "")
+	} else {
+		callerID, err := primitive.ObjectIDFromHex(caller.UserID)
+		if err != nil {
+			return nil, Domain.ErrInvalidUserID
+		}
+
+		// Move the tasks first: if that fails the user still exists and the delete can be retried
+		if result.TasksReassigned, err = uu.taskRepo.ReassignCreator(ctx, user.ID, callerID); err != nil {
+			return nil, err
+		}
+		if result.TasksUnassigned, err = uu.taskRepo.UnassignUser(ctx, user.ID); err != nil {
+			return nil, err
+		}
+		if err := uu.userRepo.Delete(ctx, userID); err != nil {
+			return nil, err
+		}
+
+		diff := fmt.Sprintf("username: %s, tasks reassigned: %d, tasks unassigned: %d", user.Username, result.TasksReassigned, result.TasksUnassigned)
+		recordAudit(ctx, uu.logger, uu.auditRepo, caller, Domain.AuditActionDelete, Domain.AuditEntityUser, userID, diff)
+	}
+
+	// The user is already gone, so a failure here is logged rather than reported
+	if err := uu.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uu.logger.ErrorContext(ctx, "failed to revoke refresh tokens after deleting user", "user_id", userID, "error", err)
+	}
+
+	return result, nil
+}
+
+// DeactivateUser stops a user from logging in or using the tokens already issued to them, on
+// behalf of the calling admin. The user's refresh tokens are revoked; access tokens are
+// rejected by the auth middleware. Admins cannot deactivate themselves.
+func (uu *UserUsecase) DeactivateUser(ctx context.Context, caller Domain.Caller, userID string) (*Domain.User, error) {
+	if userID == caller.UserID {
+		return nil, Domain.ErrSelfDeactivation
+	}
+
+	user, err := uu.setActive(ctx, caller, userID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// The user is already deactivated, so a failure here is logged rather than reported
+	if err := uu.refreshTokenRepo.RevokeAllForUser(ctx, user.ID); err != nil {
+		uu.logger.ErrorContext(ctx, "failed to revoke refresh tokens after deactivating user", "user_id", userID, "error", err)
+	}
+
+	return user, nil
+}
+
+// ActivateUser lets a deactivated user log in again, on behalf of the calling admin
+func (uu *UserUsecase) ActivateUser(ctx context.Context, caller Domain.Caller, userID string) (*Domain.User, error) {
+	return uu.setActive(ctx, caller, userID, true)
+}
+
+// setActive stores the user's new status and audits the change. Setting the status the user
+// already has is not an error and is not audited.
+func (uu *UserUsecase) setActive(ctx context.Context, caller Domain.Caller, userID string, active bool) (*Domain.User, error) {
+	user, err := uu.userRepo.GetByID(ctx, userID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Return updated user
-	return uu.userRepo.GetByUsername(username)
-}
\ No newline at end of file
+	if user.IsActive == active {
+		return user, nil
+	}
+
+	if err := uu.userRepo.SetActive(ctx, userID, active); err != nil {
+		return nil, err
+	}
+	user.IsActive = active
+
+	action := Domain.AuditActionDeactivate
+	if active {
+		action = Domain.AuditActionActivate
+	}
+	recordAudit(ctx, uu.logger, uu.auditRepo, caller, action, Domain.AuditEntityUser, userID, fmt.Sprintf("is_active: %t -> %t", !active, active))
+
+	return user, nil
+}
+
+// validateUsername checks the length and characters of a trimmed username
+func validateUsername(username string) error {
+	length := utf8.RuneCountInString(username)
+	if length < Domain.MinUsernameLength || length > Domain.MaxUsernameLength {
+		return Domain.NewError(Domain.ErrInvalidInput, "username must be between %d and %d characters", Domain.MinUsernameLength, Domain.MaxUsernameLength)
+	}
+	for _, r := range username {
+		if unicode.IsControl(r) {
+			return Domain.NewError(Domain.ErrInvalidInput, "username must not contain control characters")
+		}
+	}
+	return nil
+}
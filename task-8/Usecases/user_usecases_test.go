@@ -1,8 +1,13 @@
 package Usecases
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/stretchr/testify/assert"
@@ -10,6 +15,9 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 
 	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+	"task_manager/Repositories/memory"
 )
 
 // MockUserRepository is a mock implementation of UserRepositoryInterface
@@ -17,12 +25,22 @@ type MockUserRepository struct {
 	mock.Mock
 }
 
-func (m *MockUserRepository) GetAll() ([]*Domain.User, error) {
-	args := m.Called()
-	return args.Get(0).([]*Domain.User), args.Error(1)
+func (m *MockUserRepository) GetAll(ctx context.Context, filter Domain.UserFilter, pagination Domain.Pagination) ([]*Domain.User, int64, error) {
+	args := m.Called(filter, pagination)
+	return args.Get(0).([]*Domain.User), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) Stream(ctx context.Context, filter Domain.UserFilter, fn func(user *Domain.User) error) error {
+	args := m.Called(filter, fn)
+	return args.Error(0)
 }
 
-func (m *MockUserRepository) GetByID(id string) (*Domain.User, error) {
+func (m *MockUserRepository) GetDirectory(ctx context.Context, query string, pagination Domain.Pagination) ([]*Domain.UserDirectoryEntry, int64, error) {
+	args := m.Called(query, pagination)
+	return args.Get(0).([]*Domain.UserDirectoryEntry), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockUserRepository) GetByID(ctx context.Context, id string) (*Domain.User, error) {
 	args := m.Called(id)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -30,7 +48,7 @@ func (m *MockUserRepository) GetByID(id string) (*Domain.User, error) {
 	return args.Get(0).(*Domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) GetByUsername(username string) (*Domain.User, error) {
+func (m *MockUserRepository) GetByUsername(ctx context.Context, username string) (*Domain.User, error) {
 	args := m.Called(username)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -38,26 +56,84 @@ func (m *MockUserRepository) GetByUsername(username string) (*Domain.User, error
 	return args.Get(0).(*Domain.User), args.Error(1)
 }
 
-func (m *MockUserRepository) Create(user *Domain.User) error {
+func (m *MockUserRepository) Create(ctx context.Context, user *Domain.User) error {
 	args := m.Called(user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) Update(id string, user *Domain.User) error {
+func (m *MockUserRepository) Update(ctx context.Context, id string, user *Domain.User) error {
 	args := m.Called(id, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) UpdateByUsername(username string, user *Domain.User) error {
+func (m *MockUserRepository) UpdateByUsername(ctx context.Context, username string, user *Domain.User) error {
 	args := m.Called(username, user)
 	return args.Error(0)
 }
 
-func (m *MockUserRepository) CountUsers() (int64, error) {
+func (m *MockUserRepository) GetByEmail(ctx context.Context, email string) (*Domain.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.User), args.Error(1)
+}
+
+func (m *MockUserRepository) UpdateProfile(ctx context.Context, id string, user *Domain.User) error {
+	args := m.Called(id, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) MarkEmailVerified(ctx context.Context, id string, email string) error {
+	args := m.Called(id, email)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateLastLogin(ctx context.Context, id string, at time.Time) error {
+	args := m.Called(id, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Anonymize(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetActive(ctx context.Context, id string, active bool) error {
+	args := m.Called(id, active)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) IsActive(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) CountByRole(ctx context.Context, role string) (int64, error) {
+	args := m.Called(role)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockUserRepository) ClaimFirstAdmin(ctx context.Context, id string) (bool, error) {
+	args := m.Called(id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockUserRepository) CountUsers(ctx context.Context) (int64, error) {
 	args := m.Called()
 	return args.Get(0).(int64), args.Error(1)
 }
 
+func (m *MockUserRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
 // MockPasswordService is a mock implementation of PasswordServiceInterface
 type MockPasswordService struct {
 	mock.Mock
@@ -73,6 +149,11 @@ func (m *MockPasswordService) ComparePassword(hashedPassword, password string) e
 	return args.Error(0)
 }
 
+func (m *MockPasswordService) NeedsRehash(hashedPassword string) bool {
+	args := m.Called(hashedPassword)
+	return args.Bool(0)
+}
+
 // MockJWTService is a mock implementation of JWTServiceInterface
 type MockJWTService struct {
 	mock.Mock
@@ -96,13 +177,144 @@ func (m *MockJWTService) GetJWTSecret() []byte {
 	return args.Get(0).([]byte)
 }
 
+func (m *MockJWTService) GenerateRefreshToken() (string, time.Time, error) {
+	args := m.Called()
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockJWTService) AccessTokenTTL() time.Duration {
+	args := m.Called()
+	return args.Get(0).(time.Duration)
+}
+
+func (m *MockJWTService) JWKS() *Domain.JSONWebKeySet {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil
+	}
+	return args.Get(0).(*Domain.JSONWebKeySet)
+}
+
+func (m *MockJWTService) GenerateEmailVerificationToken(user *Domain.User) (string, time.Time, error) {
+	args := m.Called(user)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockJWTService) ValidateEmailVerificationToken(tokenString string) (*Domain.EmailVerificationClaims, error) {
+	args := m.Called(tokenString)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.EmailVerificationClaims), args.Error(1)
+}
+
+// MockRefreshTokenRepository is a mock implementation of RefreshTokenRepositoryInterface
+type MockRefreshTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockRefreshTokenRepository) Create(ctx context.Context, token *Domain.RefreshToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) GetByHash(ctx context.Context, tokenHash string) (*Domain.RefreshToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.RefreshToken), args.Error(1)
+}
+
+func (m *MockRefreshTokenRepository) Revoke(ctx context.Context, id primitive.ObjectID) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) RevokeAllForUser(ctx context.Context, userID primitive.ObjectID) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockRefreshTokenRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockLoginEventRepository is a mock implementation of LoginEventRepositoryInterface
+type MockLoginEventRepository struct {
+	mock.Mock
+}
+
+func (m *MockLoginEventRepository) Create(ctx context.Context, event *Domain.LoginEvent) error {
+	args := m.Called(event)
+	return args.Error(0)
+}
+
+func (m *MockLoginEventRepository) GetRecentByUserID(ctx context.Context, userID primitive.ObjectID, limit int64) ([]*Domain.LoginEvent, error) {
+	args := m.Called(userID, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.LoginEvent), args.Error(1)
+}
+
+func (m *MockLoginEventRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// newMockLoginEventRepository returns a login event repository that accepts any event,
+// for tests that are not about login history
+func newMockLoginEventRepository() *MockLoginEventRepository {
+	mockLoginEventRepo := new(MockLoginEventRepository)
+	mockLoginEventRepo.On("Create", mock.Anything).Return(nil).Maybe()
+	return mockLoginEventRepo
+}
+
+// MockTransactionManager is a mock implementation of Infrastructure.TransactionManager. It runs fn
+// directly unless the expectation returns an error.
+type MockTransactionManager struct {
+	mock.Mock
+}
+
+func (m *MockTransactionManager) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	args := m.Called()
+	if err := args.Error(0); err != nil {
+		return err
+	}
+	return fn(ctx)
+}
+
+// newMockTransactionManager returns a transaction manager that runs every function it is given,
+// for tests that are not about transactions
+func newMockTransactionManager() *MockTransactionManager {
+	mockTxManager := new(MockTransactionManager)
+	mockTxManager.On("WithTransaction").Return(nil).Maybe()
+	return mockTxManager
+}
+
+// refreshTokenMatching matches a stored refresh token by its user, family and hashed value
+func refreshTokenMatching(userID primitive.ObjectID, familyID, token string) interface{} {
+	return mock.MatchedBy(func(stored *Domain.RefreshToken) bool {
+		return stored.UserID == userID &&
+			(familyID == "" || stored.FamilyID == familyID) &&
+			stored.TokenHash == Infrastructure.HashRefreshToken(token)
+	})
+}
+
 func TestUserUsecase_RegisterUser(t *testing.T) {
 	t.Run("Success - register first user as admin", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		userReq := Domain.UserRequest{
 			Username: "firstuser",
@@ -110,13 +322,14 @@ func TestUserUsecase_RegisterUser(t *testing.T) {
 		}
 		hashedPassword := "hashed_password_123"
 
-		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, errors.New("user not found"))
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
 		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
 		mockUserRepo.On("CountUsers").Return(int64(0), nil)
-		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockUserRepo.On("Create", mock.MatchedBy(func(user *Domain.User) bool { return user.Role == Domain.RoleUser })).Return(nil)
+		mockUserRepo.On("ClaimFirstAdmin", mock.Anything).Return(true, nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
 		assert.NoError(t, err)
@@ -134,7 +347,7 @@ func TestUserUsecase_RegisterUser(t *testing.T) {
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		userReq := Domain.UserRequest{
 			Username: "regularuser",
@@ -142,13 +355,13 @@ func TestUserUsecase_RegisterUser(t *testing.T) {
 		}
 		hashedPassword := "hashed_password_123"
 
-		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, errors.New("user not found"))
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
 		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
 		mockUserRepo.On("CountUsers").Return(int64(1), nil) // Already has users
 		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
 		assert.NoError(t, err)
@@ -166,7 +379,7 @@ func TestUserUsecase_RegisterUser(t *testing.T) {
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		userReq := Domain.UserRequest{
 			Username: "existinguser",
@@ -180,7 +393,7 @@ func TestUserUsecase_RegisterUser(t *testing.T) {
 		mockUserRepo.On("GetByUsername", userReq.Username).Return(existingUser, nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
 		assert.Error(t, err)
@@ -190,515 +403,2493 @@ func TestUserUsecase_RegisterUser(t *testing.T) {
 		mockUserRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error - password hashing fails", func(t *testing.T) {
+	t.Run("Success - username is trimmed and looked up in lowercase", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		userReq := Domain.UserRequest{
-			Username: "newuser",
+			Username: "  Alice ",
 			Password: "password123",
 		}
-		expectedError := errors.New("hashing error")
 
-		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, errors.New("user not found"))
-		mockPasswordService.On("HashPassword", userReq.Password).Return("", expectedError)
+		mockUserRepo.On("GetByUsername", "alice").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to hash password")
-		assert.Nil(t, user)
-
+		assert.NoError(t, err)
+		assert.Equal(t, "Alice", user.Username)
+		assert.Equal(t, "alice", user.UsernameLower)
 		mockUserRepo.AssertExpectations(t)
-		mockPasswordService.AssertExpectations(t)
 	})
 
-	t.Run("Error - count users fails", func(t *testing.T) {
+	t.Run("Error - username differs only in case", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
-
-		userReq := Domain.UserRequest{
-			Username: "newuser",
-			Password: "password123",
-		}
-		hashedPassword := "hashed_password_123"
-		expectedError := errors.New("database error")
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
-		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, errors.New("user not found"))
-		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
-		mockUserRepo.On("CountUsers").Return(int64(0), expectedError)
+		mockUserRepo.On("GetByUsername", "alice").Return(&Domain.User{Username: "alice", Role: Domain.RoleUser}, nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: "ALICE", Password: "password123"})
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, Domain.ErrUsernameTaken)
 		assert.Nil(t, user)
-
 		mockUserRepo.AssertExpectations(t)
-		mockPasswordService.AssertExpectations(t)
 	})
 
-	t.Run("Error - create user fails", func(t *testing.T) {
+	t.Run("Error - invalid usernames", func(t *testing.T) {
+		tests := []struct {
+			name     string
+			username string
+			want     string
+		}{
+			{"Too short", "ab", "username must be between 3 and 32 characters"},
+			{"Too short after trimming", "  ab  ", "username must be between 3 and 32 characters"},
+			{"Too long", strings.Repeat("a", 33), "username must be between 3 and 32 characters"},
+			{"Control character", "ali\x00ce", "username must not contain control characters"},
+			{"Newline", "ali\nce", "username must not contain control characters"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				// Arrange
+				mockUserRepo := new(MockUserRepository)
+				userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+				// Act
+				user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: tt.username, Password: "password123"})
+
+				// Assert
+				assert.EqualError(t, err, tt.want)
+				assert.Nil(t, user)
+				mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+			})
+		}
+	})
+
+	t.Run("Success - multibyte username counts characters", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
-		userReq := Domain.UserRequest{
-			Username: "newuser",
-			Password: "password123",
-		}
-		hashedPassword := "hashed_password_123"
-		expectedError := errors.New("database create error")
-
-		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, errors.New("user not found"))
-		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
-		mockUserRepo.On("CountUsers").Return(int64(0), nil)
-		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(expectedError)
+		username := strings.Repeat("é", Domain.MaxUsernameLength) // 64 bytes, 32 characters
+		mockUserRepo.On("GetByUsername", username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", "password123").Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: username, Password: "password123"})
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, user)
-
-		mockUserRepo.AssertExpectations(t)
-		mockPasswordService.AssertExpectations(t)
+		assert.NoError(t, err)
+		assert.Equal(t, username, user.Username)
 	})
-}
 
-func TestUserUsecase_LoginUser(t *testing.T) {
-	t.Run("Success - valid credentials", func(t *testing.T) {
+	t.Run("Success - register with email sends a verification token", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		mockNotifier := new(MockNotifier)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, mockNotifier, newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
-		loginReq := Domain.LoginRequest{
-			Username: "testuser",
+		userReq := Domain.UserRequest{
+			Username: "mailuser",
 			Password: "password123",
+			Email:    " Mail.User@Example.com ",
 		}
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: loginReq.Username,
-			Password: "hashed_password",
-			Role:     Domain.RoleUser,
-		}
-		expectedToken := "jwt.token.here"
+		expiresAt := time.Now().Add(24 * time.Hour)
 
-		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
-		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(nil)
-		mockJWTService.On("GenerateToken", user).Return(expectedToken, nil)
+		mockUserRepo.On("GetByUsername", "mailuser").Return(nil, Domain.ErrUserNotFound)
+		mockUserRepo.On("GetByEmail", "mail.user@example.com").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockJWTService.On("GenerateEmailVerificationToken", mock.AnythingOfType("*Domain.User")).Return("verification-token", expiresAt, nil)
+		mockNotifier.On("SendEmailVerification", mock.AnythingOfType("*Domain.User"), "verification-token", expiresAt).Return(nil)
 
 		// Act
-		resultUser, token, err := userUsecase.LoginUser(loginReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, user, resultUser)
-		assert.Equal(t, expectedToken, token)
-
+		assert.Equal(t, "mail.user@example.com", user.Email)
+		assert.False(t, user.EmailVerified)
 		mockUserRepo.AssertExpectations(t)
-		mockPasswordService.AssertExpectations(t)
 		mockJWTService.AssertExpectations(t)
+		mockNotifier.AssertExpectations(t)
 	})
 
-	t.Run("Error - user not found", func(t *testing.T) {
+	t.Run("Success - failed verification email does not fail registration", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		mockNotifier := new(MockNotifier)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, mockNotifier, newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
-		loginReq := Domain.LoginRequest{
-			Username: "nonexistentuser",
-			Password: "password123",
-		}
-		expectedError := errors.New("user not found")
+		userReq := Domain.UserRequest{Username: "mailuser", Password: "password123", Email: "user@example.com"}
+		expiresAt := time.Now().Add(24 * time.Hour)
 
-		mockUserRepo.On("GetByUsername", loginReq.Username).Return(nil, expectedError)
+		mockUserRepo.On("GetByUsername", "mailuser").Return(nil, Domain.ErrUserNotFound)
+		mockUserRepo.On("GetByEmail", "user@example.com").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockJWTService.On("GenerateEmailVerificationToken", mock.AnythingOfType("*Domain.User")).Return("verification-token", expiresAt, nil)
+		mockNotifier.On("SendEmailVerification", mock.AnythingOfType("*Domain.User"), "verification-token", expiresAt).Return(errors.New("connection refused"))
 
 		// Act
-		user, token, err := userUsecase.LoginUser(loginReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid credentials")
-		assert.Nil(t, user)
-		assert.Empty(t, token)
-
-		mockUserRepo.AssertExpectations(t)
+		assert.NoError(t, err)
+		assert.NotNil(t, user)
+		mockNotifier.AssertExpectations(t)
 	})
 
-	t.Run("Error - invalid password", func(t *testing.T) {
+	t.Run("Error - email already exists", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		mockNotifier := new(MockNotifier)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), mockNotifier, newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
-		loginReq := Domain.LoginRequest{
-			Username: "testuser",
-			Password: "wrongpassword",
-		}
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: loginReq.Username,
-			Password: "hashed_password",
-			Role:     Domain.RoleUser,
-		}
-		expectedError := errors.New("password mismatch")
+		userReq := Domain.UserRequest{Username: "newuser", Password: "password123", Email: "Taken@example.com"}
 
-		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
-		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(expectedError)
+		mockUserRepo.On("GetByUsername", "newuser").Return(nil, Domain.ErrUserNotFound)
+		mockUserRepo.On("GetByEmail", "taken@example.com").Return(&Domain.User{Username: "olduser", Email: "taken@example.com"}, nil)
 
 		// Act
-		resultUser, token, err := userUsecase.LoginUser(loginReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "invalid credentials")
-		assert.Nil(t, resultUser)
-		assert.Empty(t, token)
-
-		mockUserRepo.AssertExpectations(t)
-		mockPasswordService.AssertExpectations(t)
+		assert.ErrorIs(t, err, Domain.ErrEmailTaken)
+		assert.Nil(t, user)
+		mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+		mockNotifier.AssertNotCalled(t, "SendEmailVerification", mock.Anything, mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - token generation fails", func(t *testing.T) {
+	t.Run("Error - concurrent registration hits the unique email index", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		mockNotifier := new(MockNotifier)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), mockNotifier, newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
-		loginReq := Domain.LoginRequest{
-			Username: "testuser",
-			Password: "password123",
-		}
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: loginReq.Username,
-			Password: "hashed_password",
-			Role:     Domain.RoleUser,
-		}
-		expectedError := errors.New("token generation error")
+		userReq := Domain.UserRequest{Username: "newuser", Password: "password123", Email: "user@example.com"}
 
-		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
-		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(nil)
-		mockJWTService.On("GenerateToken", user).Return("", expectedError)
+		mockUserRepo.On("GetByUsername", "newuser").Return(nil, Domain.ErrUserNotFound)
+		mockUserRepo.On("GetByEmail", "user@example.com").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(Domain.ErrEmailTaken)
 
 		// Act
-		resultUser, token, err := userUsecase.LoginUser(loginReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "failed to generate token")
+		assert.ErrorIs(t, err, Domain.ErrEmailTaken)
+		assert.Nil(t, user)
+		mockNotifier.AssertNotCalled(t, "SendEmailVerification", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - email required by configuration", func(t *testing.T) {
+		// Arrange
+		t.Setenv("REGISTRATION_REQUIRE_EMAIL", "true")
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: "newuser", Password: "password123"})
+
+		// Assert
+		assert.EqualError(t, err, "email is required")
+		assert.Nil(t, user)
+		mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Error - concurrent registration hits the unique username index", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		userReq := Domain.UserRequest{
+			Username: "raceduser",
+			Password: "password123",
+		}
+
+		// The pre-read finds nothing, but another registration inserts the username first
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(Domain.ErrUsernameTaken)
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrUsernameTaken)
+		assert.Nil(t, user)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - password hashing fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		userReq := Domain.UserRequest{
+			Username: "newuser",
+			Password: "password123",
+		}
+		expectedError := errors.New("hashing error")
+
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("", expectedError)
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to hash password")
+		assert.Nil(t, user)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+	})
+
+	t.Run("Error - count users fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		userReq := Domain.UserRequest{
+			Username: "newuser",
+			Password: "password123",
+		}
+		hashedPassword := "hashed_password_123"
+		expectedError := errors.New("database error")
+
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
+		mockUserRepo.On("CountUsers").Return(int64(0), expectedError)
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, user)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+	})
+
+	t.Run("Error - create user fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		userReq := Domain.UserRequest{
+			Username: "newuser",
+			Password: "password123",
+		}
+		hashedPassword := "hashed_password_123"
+		expectedError := errors.New("database create error")
+
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
+		mockUserRepo.On("CountUsers").Return(int64(0), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(expectedError)
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, user)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+	})
+
+	t.Run("Error - password fails the policy", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		policy := &Infrastructure.PasswordPolicy{MinLength: 8, RejectCommon: true}
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, policy, new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: "newuser", Password: "aaaaaa"})
+
+		// Assert
+		var policyErr *Domain.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		assert.Len(t, policyErr.Violations, 2)
+		assert.Nil(t, user)
+		mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+		mockPasswordService.AssertNotCalled(t, "HashPassword", mock.Anything)
+	})
+
+	t.Run("Success - first user who loses the admin claim stays a regular user", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		userReq := Domain.UserRequest{Username: "seconduser", Password: "password123"}
+
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(0), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockUserRepo.On("ClaimFirstAdmin", mock.Anything).Return(false, nil)
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleUser, user.Role)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - a failed admin claim still registers the user", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		userReq := Domain.UserRequest{Username: "firstuser", Password: "password123"}
+
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("CountUsers").Return(int64(0), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockUserRepo.On("ClaimFirstAdmin", mock.Anything).Return(false, errors.New("database error"))
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleUser, user.Role)
+	})
+
+	t.Run("Success - first user is a regular user when an admin is seeded", func(t *testing.T) {
+		// Arrange
+		t.Setenv("ADMIN_USERNAME", "root")
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		userReq := Domain.UserRequest{Username: "firstuser", Password: "password123"}
+
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleUser, user.Role)
+		mockUserRepo.AssertNotCalled(t, "CountUsers")
+	})
+}
+
+// racingUserRepository is a user repository as seen by registrations that all counted the users
+// before any of them was created
+type racingUserRepository struct {
+	Repositories.UserRepositoryInterface
+}
+
+func (racingUserRepository) CountUsers(ctx context.Context) (int64, error) {
+	return 0, nil
+}
+
+func TestUserUsecase_RegisterUser_FirstUserRace(t *testing.T) {
+	t.Run("Success - exactly one of the concurrent first registrations becomes admin", func(t *testing.T) {
+		// Arrange
+		t.Setenv("BCRYPT_COST", "4")
+		userRepo := racingUserRepository{memory.NewUserRepository()}
+		userUsecase := NewUserUsecase(userRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), Infrastructure.NewPasswordService(), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		const registrations = 8
+
+		// Act
+		var wg sync.WaitGroup
+		roles := make([]string, registrations)
+		errs := make([]error, registrations)
+		for i := 0; i < registrations; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: fmt.Sprintf("racer%d", i), Password: "password123"})
+				errs[i] = err
+				if user != nil {
+					roles[i] = user.Role
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		// Assert
+		admins := 0
+		for i := range roles {
+			assert.NoError(t, errs[i])
+			if roles[i] == Domain.RoleAdmin {
+				admins++
+			}
+		}
+		assert.Equal(t, 1, admins)
+		stored, err := userRepo.CountByRole(context.Background(), Domain.RoleAdmin)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), stored)
+	})
+}
+
+func TestUserUsecase_SeedAdmin(t *testing.T) {
+	newUsecase := func(mockUserRepo *MockUserRepository, mockPasswordService *MockPasswordService) UserUsecaseInterface {
+		return NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	}
+	adminReq := Domain.UserRequest{Username: "root", Password: "password123"}
+
+	t.Run("Success - create the admin on a database without one", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := newUsecase(mockUserRepo, mockPasswordService)
+
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(0), nil)
+		mockUserRepo.On("GetByUsername", "root").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", adminReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+
+		// Act
+		user, created, err := userUsecase.SeedAdmin(context.Background(), adminReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, "root", user.Username)
+		assert.Equal(t, Domain.RoleAdmin, user.Role)
+		assert.Equal(t, "hashed_password_123", user.Password)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - skip when an admin exists", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := newUsecase(mockUserRepo, mockPasswordService)
+
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(1), nil)
+
+		// Act
+		user, created, err := userUsecase.SeedAdmin(context.Background(), adminReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Nil(t, user)
+		mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+
+	t.Run("Success - skip when another instance seeded the same admin first", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := newUsecase(mockUserRepo, mockPasswordService)
+
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(0), nil)
+		mockUserRepo.On("GetByUsername", "root").Return(&Domain.User{Username: "root", Role: Domain.RoleAdmin}, nil)
+
+		// Act
+		user, created, err := userUsecase.SeedAdmin(context.Background(), adminReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.False(t, created)
+		assert.Nil(t, user)
+	})
+
+	t.Run("Error - username belongs to a regular user", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := newUsecase(mockUserRepo, mockPasswordService)
+
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(0), nil)
+		mockUserRepo.On("GetByUsername", "root").Return(&Domain.User{Username: "root", Role: Domain.RoleUser}, nil)
+
+		// Act
+		user, created, err := userUsecase.SeedAdmin(context.Background(), adminReq)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrUsernameTaken)
+		assert.False(t, created)
+		assert.Nil(t, user)
+	})
+
+	t.Run("Error - password fails the policy", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		policy := &Infrastructure.PasswordPolicy{MinLength: 12}
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), policy, new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(0), nil)
+
+		// Act
+		user, created, err := userUsecase.SeedAdmin(context.Background(), adminReq)
+
+		// Assert
+		var policyErr *Domain.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		assert.False(t, created)
+		assert.Nil(t, user)
+		mockUserRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestUserUsecase_CreateAdmin(t *testing.T) {
+	t.Run("Success - create an admin alongside existing ones", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		userReq := Domain.UserRequest{Username: "operator", Password: "password123"}
+
+		mockUserRepo.On("GetByUsername", "operator").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password_123", nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+
+		// Act
+		user, err := userUsecase.CreateAdmin(context.Background(), userReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleAdmin, user.Role)
+		mockUserRepo.AssertNotCalled(t, "CountByRole", mock.Anything)
+	})
+}
+
+func TestUserUsecase_LoginUser(t *testing.T) {
+	t.Run("Success - valid credentials", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{
+			Username: "testuser",
+			Password: "password123",
+		}
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: loginReq.Username,
+			Password: "hashed_password",
+			Role:     Domain.RoleUser,
+			IsActive: true,
+		}
+		expectedToken := "jwt.token.here"
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(nil)
+		mockPasswordService.On("NeedsRehash", user.Password).Return(false)
+		mockJWTService.On("GenerateToken", user).Return(expectedToken, nil)
+		mockJWTService.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", refreshTokenMatching(user.ID, "", "refresh-token")).Return(nil)
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, user, resultUser)
+		assert.Equal(t, expectedToken, tokens.AccessToken)
+		assert.Equal(t, "refresh-token", tokens.RefreshToken)
+		assert.Equal(t, int64(900), tokens.ExpiresIn)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+		mockJWTService.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{
+			Username: "nonexistentuser",
+			Password: "password123",
+		}
+		expectedError := Domain.ErrUserNotFound
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(nil, expectedError)
+
+		// Act
+		user, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid credentials")
+		assert.Nil(t, user)
+		assert.Nil(t, tokens)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - deactivated account", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{Username: "testuser", Password: "password123"}
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: loginReq.Username, Password: "hashed_password", Role: Domain.RoleUser, IsActive: false}
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(nil)
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAccountDeactivated)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+		mockJWTService.AssertNotCalled(t, "GenerateToken", mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "UpdateLastLogin", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid password", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{
+			Username: "testuser",
+			Password: "wrongpassword",
+		}
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: loginReq.Username,
+			Password: "hashed_password",
+			Role:     Domain.RoleUser,
+			IsActive: true,
+		}
+		expectedError := errors.New("password mismatch")
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(expectedError)
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "invalid credentials")
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+	})
+
+	t.Run("Error - token generation fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{
+			Username: "testuser",
+			Password: "password123",
+		}
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: loginReq.Username,
+			Password: "hashed_password",
+			Role:     Domain.RoleUser,
+			IsActive: true,
+		}
+		expectedError := errors.New("token generation error")
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", user.Password, loginReq.Password).Return(nil)
+		mockPasswordService.On("NeedsRehash", user.Password).Return(false)
+		mockJWTService.On("GenerateToken", user).Return("", expectedError)
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to generate token")
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+		mockJWTService.AssertExpectations(t)
+	})
+
+	t.Run("Success - password hashed with an old cost is upgraded", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{Username: "testuser", Password: "password123"}
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: loginReq.Username, Password: "old_cost_hash", Role: Domain.RoleUser, IsActive: true}
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", "old_cost_hash", loginReq.Password).Return(nil)
+		mockPasswordService.On("NeedsRehash", "old_cost_hash").Return(true)
+		mockPasswordService.On("HashPassword", loginReq.Password).Return("new_cost_hash", nil)
+		mockUserRepo.On("Update", user.ID.Hex(), mock.MatchedBy(func(updated *Domain.User) bool {
+			return updated.Password == "new_cost_hash" && updated.Role == Domain.RoleUser
+		})).Return(nil)
+		mockJWTService.On("GenerateToken", user).Return("jwt.token.here", nil)
+		mockJWTService.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "jwt.token.here", tokens.AccessToken)
+		assert.Equal(t, "new_cost_hash", resultUser.Password)
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+	})
+
+	t.Run("Success - login works when the upgrade cannot be stored", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{Username: "testuser", Password: "password123"}
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: loginReq.Username, Password: "old_cost_hash", Role: Domain.RoleUser, IsActive: true}
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", "old_cost_hash", loginReq.Password).Return(nil)
+		mockPasswordService.On("NeedsRehash", "old_cost_hash").Return(true)
+		mockPasswordService.On("HashPassword", loginReq.Password).Return("new_cost_hash", nil)
+		mockUserRepo.On("Update", user.ID.Hex(), mock.AnythingOfType("*Domain.User")).Return(errors.New("database error"))
+		mockJWTService.On("GenerateToken", user).Return("jwt.token.here", nil)
+		mockJWTService.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, tokens)
+		assert.Equal(t, "old_cost_hash", resultUser.Password)
+	})
+
+	t.Run("Success - login works when rehashing fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		loginReq := Domain.LoginRequest{Username: "testuser", Password: "password123"}
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: loginReq.Username, Password: "old_cost_hash", Role: Domain.RoleUser, IsActive: true}
+
+		mockUserRepo.On("GetByUsername", loginReq.Username).Return(user, nil)
+		mockPasswordService.On("ComparePassword", "old_cost_hash", loginReq.Password).Return(nil)
+		mockPasswordService.On("NeedsRehash", "old_cost_hash").Return(true)
+		mockPasswordService.On("HashPassword", loginReq.Password).Return("", errors.New("bcrypt error"))
+		mockJWTService.On("GenerateToken", user).Return("jwt.token.here", nil)
+		mockJWTService.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", mock.Anything).Return(nil)
+
+		// Act
+		_, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, tokens)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Success - username is matched case-insensitively", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		user := &Domain.User{
+			ID:            primitive.NewObjectID(),
+			Username:      "Alice",
+			UsernameLower: "alice",
+			Password:      "hashed_password",
+			Role:          Domain.RoleUser,
+			IsActive:      true,
+		}
+
+		mockUserRepo.On("GetByUsername", "alice").Return(user, nil)
+		mockPasswordService.On("ComparePassword", user.Password, "password123").Return(nil)
+		mockPasswordService.On("NeedsRehash", user.Password).Return(false)
+		mockJWTService.On("GenerateToken", user).Return("jwt.token.here", nil)
+		mockJWTService.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", refreshTokenMatching(user.ID, "", "refresh-token")).Return(nil)
+
+		// Act
+		resultUser, _, err := userUsecase.LoginUser(context.Background(), Domain.LoginRequest{Username: " ALICE ", Password: "password123"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, user, resultUser)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_LoginUser_RecordsLogin(t *testing.T) {
+	setup := func() (*MockUserRepository, *MockLoginEventRepository, *Domain.User, UserUsecaseInterface) {
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		mockLoginEventRepo := new(MockLoginEventRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), mockLoginEventRepo, newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Password: "hashed_password", Role: Domain.RoleUser, IsActive: true}
+		mockUserRepo.On("GetByUsername", "testuser").Return(user, nil)
+		mockPasswordService.On("ComparePassword", user.Password, "password123").Return(nil)
+		mockPasswordService.On("NeedsRehash", user.Password).Return(false)
+		mockJWTService.On("GenerateToken", user).Return("jwt.token.here", nil)
+		mockJWTService.On("GenerateRefreshToken").Return("refresh-token", time.Now().Add(time.Hour), nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", mock.AnythingOfType("*Domain.RefreshToken")).Return(nil)
+
+		return mockUserRepo, mockLoginEventRepo, user, userUsecase
+	}
+	loginReq := Domain.LoginRequest{Username: "testuser", Password: "password123", IP: "203.0.113.7", UserAgent: "task-cli/1.0"}
+
+	t.Run("Success - last login and login event are stored", func(t *testing.T) {
+		// Arrange
+		mockUserRepo, mockLoginEventRepo, user, userUsecase := setup()
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockLoginEventRepo.On("Create", mock.MatchedBy(func(event *Domain.LoginEvent) bool {
+			return event.UserID == user.ID && event.IP == "203.0.113.7" && event.UserAgent == "task-cli/1.0" && !event.Timestamp.IsZero()
+		})).Return(nil)
+
+		// Act
+		resultUser, _, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NotNil(t, resultUser.LastLoginAt)
+		mockUserRepo.AssertExpectations(t)
+		mockLoginEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - login works when neither write succeeds", func(t *testing.T) {
+		// Arrange
+		mockUserRepo, mockLoginEventRepo, user, userUsecase := setup()
+		mockUserRepo.On("UpdateLastLogin", user.ID.Hex(), mock.AnythingOfType("time.Time")).Return(errors.New("database error"))
+		mockLoginEventRepo.On("Create", mock.AnythingOfType("*Domain.LoginEvent")).Return(errors.New("database error"))
+
+		// Act
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "jwt.token.here", tokens.AccessToken)
+		assert.Nil(t, resultUser.LastLoginAt)
+		mockLoginEventRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_GetLoginHistory(t *testing.T) {
+	t.Run("Success - recent logins of the caller", func(t *testing.T) {
+		// Arrange
+		mockLoginEventRepo := new(MockLoginEventRepository)
+		userUsecase := NewUserUsecase(new(MockUserRepository), new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), mockLoginEventRepo, newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		callerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		expectedEvents := []*Domain.LoginEvent{{UserID: callerID, IP: "203.0.113.7", Timestamp: time.Now()}}
+		mockLoginEventRepo.On("GetRecentByUserID", callerID, int64(MaxLoginHistory)).Return(expectedEvents, nil)
+
+		// Act
+		events, err := userUsecase.GetLoginHistory(context.Background(), userCaller)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedEvents, events)
+		mockLoginEventRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - caller without valid user ID", func(t *testing.T) {
+		// Arrange
+		mockLoginEventRepo := new(MockLoginEventRepository)
+		userUsecase := NewUserUsecase(new(MockUserRepository), new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), mockLoginEventRepo, newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		// Act
+		events, err := userUsecase.GetLoginHistory(context.Background(), Domain.Caller{Role: Domain.RoleUser})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidUserID)
+		assert.Nil(t, events)
+		mockLoginEventRepo.AssertNotCalled(t, "GetRecentByUserID", mock.Anything, mock.Anything)
+	})
+}
+
+func TestUserUsecase_RefreshTokens(t *testing.T) {
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "testuser", Role: Domain.RoleAdmin, IsActive: true}
+
+	setup := func() (UserUsecaseInterface, *MockUserRepository, *MockRefreshTokenRepository, *MockJWTService) {
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		return userUsecase, mockUserRepo, mockRefreshTokenRepo, mockJWTService
+	}
+
+	storedToken := func() *Domain.RefreshToken {
+		return &Domain.RefreshToken{
+			ID:        primitive.NewObjectID(),
+			UserID:    user.ID,
+			FamilyID:  "family-1",
+			TokenHash: Infrastructure.HashRefreshToken("old-refresh-token"),
+			ExpiresAt: time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("Success - rotate refresh token within its family", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, mockJWTService := setup()
+		stored := storedToken()
+		mockRefreshTokenRepo.On("GetByHash", stored.TokenHash).Return(stored, nil)
+		mockRefreshTokenRepo.On("Revoke", stored.ID).Return(nil)
+		mockUserRepo.On("GetByID", user.ID.Hex()).Return(user, nil)
+		mockJWTService.On("GenerateToken", user).Return("new-access-token", nil)
+		mockJWTService.On("GenerateRefreshToken").Return("new-refresh-token", time.Now().Add(time.Hour), nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", refreshTokenMatching(user.ID, "family-1", "new-refresh-token")).Return(nil)
+
+		// Act
+		resultUser, tokens, err := userUsecase.RefreshTokens(context.Background(), "old-refresh-token")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, user, resultUser)
+		assert.Equal(t, "new-access-token", tokens.AccessToken)
+		assert.Equal(t, "new-refresh-token", tokens.RefreshToken)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockJWTService.AssertExpectations(t)
+	})
+
+	t.Run("Error - deactivated user", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, mockJWTService := setup()
+		stored := storedToken()
+		deactivated := &Domain.User{ID: user.ID, Username: user.Username, Role: user.Role, IsActive: false}
+		mockRefreshTokenRepo.On("GetByHash", stored.TokenHash).Return(stored, nil)
+		mockRefreshTokenRepo.On("Revoke", stored.ID).Return(nil)
+		mockUserRepo.On("GetByID", user.ID.Hex()).Return(deactivated, nil)
+
+		// Act
+		resultUser, tokens, err := userUsecase.RefreshTokens(context.Background(), "old-refresh-token")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAccountDeactivated)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+		mockJWTService.AssertNotCalled(t, "GenerateToken", mock.Anything)
+	})
+
+	t.Run("Error - unknown refresh token", func(t *testing.T) {
+		// Arrange
+		userUsecase, _, mockRefreshTokenRepo, _ := setup()
+		mockRefreshTokenRepo.On("GetByHash", Infrastructure.HashRefreshToken("unknown")).Return(nil, Domain.ErrRefreshTokenNotFound)
+
+		// Act
+		resultUser, tokens, err := userUsecase.RefreshTokens(context.Background(), "unknown")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidRefreshToken)
+		assert.Nil(t, resultUser)
+		assert.Nil(t, tokens)
+	})
+
+	t.Run("Error - expired refresh token", func(t *testing.T) {
+		// Arrange
+		userUsecase, _, mockRefreshTokenRepo, _ := setup()
+		stored := storedToken()
+		stored.ExpiresAt = time.Now().Add(-time.Minute)
+		mockRefreshTokenRepo.On("GetByHash", stored.TokenHash).Return(stored, nil)
+
+		// Act
+		_, tokens, err := userUsecase.RefreshTokens(context.Background(), "old-refresh-token")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrRefreshTokenExpired)
+		assert.Nil(t, tokens)
+		mockRefreshTokenRepo.AssertNotCalled(t, "Revoke", mock.Anything)
+	})
+
+	t.Run("Error - reused refresh token revokes the family", func(t *testing.T) {
+		// Arrange
+		userUsecase, _, mockRefreshTokenRepo, _ := setup()
+		stored := storedToken()
+		revokedAt := time.Now().Add(-time.Minute)
+		stored.RevokedAt = &revokedAt
+		mockRefreshTokenRepo.On("GetByHash", stored.TokenHash).Return(stored, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return(nil)
+
+		// Act
+		_, tokens, err := userUsecase.RefreshTokens(context.Background(), "old-refresh-token")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrRefreshTokenReused)
+		assert.Nil(t, tokens)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - concurrent rotation revokes the family", func(t *testing.T) {
+		// Arrange
+		userUsecase, _, mockRefreshTokenRepo, mockJWTService := setup()
+		stored := storedToken()
+		mockRefreshTokenRepo.On("GetByHash", stored.TokenHash).Return(stored, nil)
+		mockRefreshTokenRepo.On("Revoke", stored.ID).Return(Domain.ErrRefreshTokenRevoked)
+		mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return(nil)
+
+		// Act
+		_, tokens, err := userUsecase.RefreshTokens(context.Background(), "old-refresh-token")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrRefreshTokenReused)
+		assert.Nil(t, tokens)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockJWTService.AssertNotCalled(t, "GenerateToken", mock.Anything)
+	})
+
+	t.Run("Error - user no longer exists", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, _ := setup()
+		stored := storedToken()
+		mockRefreshTokenRepo.On("GetByHash", stored.TokenHash).Return(stored, nil)
+		mockRefreshTokenRepo.On("Revoke", stored.ID).Return(nil)
+		mockUserRepo.On("GetByID", user.ID.Hex()).Return(nil, Domain.ErrUserNotFound)
+
+		// Act
+		_, tokens, err := userUsecase.RefreshTokens(context.Background(), "old-refresh-token")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidRefreshToken)
+		assert.Nil(t, tokens)
+	})
+}
+
+func TestUserUsecase_Logout(t *testing.T) {
+	caller := userCaller
+	callerID, _ := primitive.ObjectIDFromHex(caller.UserID)
+	expiresAt := time.Now().Add(10 * time.Minute)
+
+	setup := func() (UserUsecaseInterface, *MockRefreshTokenRepository, *Infrastructure.MemoryTokenBlacklist) {
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		blacklist := Infrastructure.NewMemoryTokenBlacklist()
+		userUsecase := NewUserUsecase(new(MockUserRepository), new(MockTaskRepository), mockRefreshTokenRepo, blacklist, new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		return userUsecase, mockRefreshTokenRepo, blacklist
+	}
+
+	t.Run("Success - access token is blacklisted", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockRefreshTokenRepo, blacklist := setup()
+
+		// Act
+		err := userUsecase.Logout(context.Background(), caller, "token-id", expiresAt, "")
+
+		// Assert
+		assert.NoError(t, err)
+		revoked, _ := blacklist.Contains(context.Background(), "token-id")
+		assert.True(t, revoked)
+		mockRefreshTokenRepo.AssertNotCalled(t, "GetByHash", mock.Anything)
+	})
+
+	t.Run("Success - caller's refresh token family is revoked", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockRefreshTokenRepo, _ := setup()
+		stored := &Domain.RefreshToken{ID: primitive.NewObjectID(), UserID: callerID, FamilyID: "family-1"}
+		mockRefreshTokenRepo.On("GetByHash", Infrastructure.HashRefreshToken("refresh-token")).Return(stored, nil)
+		mockRefreshTokenRepo.On("RevokeFamily", "family-1").Return(nil)
+
+		// Act
+		err := userUsecase.Logout(context.Background(), caller, "token-id", expiresAt, "refresh-token")
+
+		// Assert
+		assert.NoError(t, err)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - another user's refresh token is left alone", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockRefreshTokenRepo, _ := setup()
+		stored := &Domain.RefreshToken{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID(), FamilyID: "family-2"}
+		mockRefreshTokenRepo.On("GetByHash", Infrastructure.HashRefreshToken("refresh-token")).Return(stored, nil)
+
+		// Act
+		err := userUsecase.Logout(context.Background(), caller, "token-id", expiresAt, "refresh-token")
+
+		// Assert
+		assert.NoError(t, err)
+		mockRefreshTokenRepo.AssertNotCalled(t, "RevokeFamily", mock.Anything)
+	})
+
+	t.Run("Success - unknown refresh token is ignored", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockRefreshTokenRepo, _ := setup()
+		mockRefreshTokenRepo.On("GetByHash", Infrastructure.HashRefreshToken("unknown")).Return(nil, Domain.ErrRefreshTokenNotFound)
+
+		// Act
+		err := userUsecase.Logout(context.Background(), caller, "token-id", expiresAt, "unknown")
+
+		// Assert
+		assert.NoError(t, err)
+	})
+}
+
+func TestUserUsecase_ChangePassword(t *testing.T) {
+	caller := userCaller
+	callerID, _ := primitive.ObjectIDFromHex(caller.UserID)
+
+	setup := func() (UserUsecaseInterface, *MockUserRepository, *MockRefreshTokenRepository, *MockPasswordService, *MockAuditRepository) {
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockAuditRepo := new(MockAuditRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), mockAuditRepo, newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		return userUsecase, mockUserRepo, mockRefreshTokenRepo, mockPasswordService, mockAuditRepo
+	}
+	storedUser := func() *Domain.User {
+		return &Domain.User{ID: callerID, Username: "testuser", Password: "old-hash", Role: Domain.RoleUser}
+	}
+
+	t.Run("Success - password replaced and refresh tokens revoked", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, mockPasswordService, mockAuditRepo := setup()
+		mockUserRepo.On("GetByID", caller.UserID).Return(storedUser(), nil)
+		mockPasswordService.On("ComparePassword", "old-hash", "oldpassword").Return(nil)
+		mockPasswordService.On("HashPassword", "newpassword").Return("new-hash", nil)
+		mockUserRepo.On("Update", caller.UserID, mock.MatchedBy(func(user *Domain.User) bool {
+			return user.Password == "new-hash" && user.Role == Domain.RoleUser
+		})).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(caller.UserID, Domain.AuditActionChangePassword, Domain.AuditEntityUser, caller.UserID, "")).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", callerID).Return(nil)
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "oldpassword", "newpassword")
+
+		// Assert
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+		mockPasswordService.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - revocation failure does not undo the change", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, mockPasswordService, mockAuditRepo := setup()
+		mockUserRepo.On("GetByID", caller.UserID).Return(storedUser(), nil)
+		mockPasswordService.On("ComparePassword", "old-hash", "oldpassword").Return(nil)
+		mockPasswordService.On("HashPassword", "newpassword").Return("new-hash", nil)
+		mockUserRepo.On("Update", caller.UserID, mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockAuditRepo.On("Create", mock.Anything).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", callerID).Return(errors.New("database error"))
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "oldpassword", "newpassword")
+
+		// Assert
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - wrong current password", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, mockPasswordService, _ := setup()
+		mockUserRepo.On("GetByID", caller.UserID).Return(storedUser(), nil)
+		mockPasswordService.On("ComparePassword", "old-hash", "wrongpassword").Return(errors.New("mismatch"))
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "wrongpassword", "newpassword")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrIncorrectPassword)
+		mockPasswordService.AssertNotCalled(t, "HashPassword", mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+		mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, _, mockPasswordService, _ := setup()
+		mockUserRepo.On("GetByID", caller.UserID).Return(nil, Domain.ErrUserNotFound)
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "oldpassword", "newpassword")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		mockPasswordService.AssertNotCalled(t, "ComparePassword", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - hashing fails", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, _, mockPasswordService, _ := setup()
+		mockUserRepo.On("GetByID", caller.UserID).Return(storedUser(), nil)
+		mockPasswordService.On("ComparePassword", "old-hash", "oldpassword").Return(nil)
+		mockPasswordService.On("HashPassword", "newpassword").Return("", errors.New("bcrypt error"))
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "oldpassword", "newpassword")
+
+		// Assert
+		assert.EqualError(t, err, "failed to hash password")
+		mockUserRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - update fails", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockRefreshTokenRepo, mockPasswordService, _ := setup()
+		mockUserRepo.On("GetByID", caller.UserID).Return(storedUser(), nil)
+		mockPasswordService.On("ComparePassword", "old-hash", "oldpassword").Return(nil)
+		mockPasswordService.On("HashPassword", "newpassword").Return("new-hash", nil)
+		mockUserRepo.On("Update", caller.UserID, mock.AnythingOfType("*Domain.User")).Return(errors.New("database error"))
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "oldpassword", "newpassword")
+
+		// Assert
+		assert.EqualError(t, err, "database error")
+		mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+	})
+
+	t.Run("Error - new password fails the policy", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		policy := &Infrastructure.PasswordPolicy{MinLength: 8}
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), policy, new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		// Act
+		err := userUsecase.ChangePassword(context.Background(), caller, "oldpassword", "short")
+
+		// Assert
+		var policyErr *Domain.PasswordPolicyError
+		assert.ErrorAs(t, err, &policyErr)
+		mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+}
+
+func TestUserUsecase_GetUserProfile(t *testing.T) {
+	t.Run("Success - user found", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		userID := primitive.NewObjectID().Hex()
+		expectedUser := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: "testuser",
+			Role:     Domain.RoleUser,
+		}
+
+		mockUserRepo.On("GetByID", userID).Return(expectedUser, nil)
+
+		// Act
+		user, err := userUsecase.GetUserProfile(context.Background(), userID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUser, user)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		userID := primitive.NewObjectID().Hex()
+		expectedError := Domain.ErrUserNotFound
+
+		mockUserRepo.On("GetByID", userID).Return(nil, expectedError)
+
+		// Act
+		user, err := userUsecase.GetUserProfile(context.Background(), userID)
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, user)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_GetAllUsers(t *testing.T) {
+	t.Run("Success - return all users", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		expectedUsers := []*Domain.User{
+			{
+				ID:       primitive.NewObjectID(),
+				Username: "user1",
+				Role:     Domain.RoleUser,
+			},
+			{
+				ID:       primitive.NewObjectID(),
+				Username: "admin1",
+				Role:     Domain.RoleAdmin,
+			},
+		}
+
+		mockUserRepo.On("GetAll", Domain.UserFilter{}, Domain.Pagination{}).Return(expectedUsers, int64(len(expectedUsers)), nil)
+
+		// Act
+		users, total, err := userUsecase.GetAllUsers(context.Background(), Domain.UserFilter{}, Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsers, users)
+		assert.Len(t, users, 2)
+		assert.Equal(t, int64(2), total)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - return empty list", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		expectedUsers := []*Domain.User{}
+
+		mockUserRepo.On("GetAll", Domain.UserFilter{}, Domain.Pagination{}).Return(expectedUsers, int64(len(expectedUsers)), nil)
+
+		// Act
+		users, total, err := userUsecase.GetAllUsers(context.Background(), Domain.UserFilter{}, Domain.Pagination{})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsers, users)
+		assert.Len(t, users, 0)
+		assert.Equal(t, int64(0), total)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - repository error", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		expectedError := errors.New("database error")
+
+		mockUserRepo.On("GetAll", Domain.UserFilter{}, Domain.Pagination{}).Return([]*Domain.User(nil), int64(0), expectedError)
+
+		// Act
+		users, total, err := userUsecase.GetAllUsers(context.Background(), Domain.UserFilter{}, Domain.Pagination{})
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, users)
+		assert.Equal(t, int64(0), total)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - combined filters are passed to the repository", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		filter := Domain.UserFilter{Query: "ali", Role: Domain.RoleAdmin}
+		pagination := Domain.Pagination{Limit: 10, Offset: 10, Sort: Domain.SortUsername}
+		expectedUsers := []*Domain.User{{ID: primitive.NewObjectID(), Username: "alice", Role: Domain.RoleAdmin}}
+		mockUserRepo.On("GetAll", filter, pagination).Return(expectedUsers, int64(11), nil)
+
+		// Act
+		users, total, err := userUsecase.GetAllUsers(context.Background(), filter, pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, expectedUsers, users)
+		assert.Equal(t, int64(11), total)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - page past the end is empty", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		pagination := Domain.Pagination{Limit: 20, Offset: 1000}
+		mockUserRepo.On("GetAll", Domain.UserFilter{}, pagination).Return([]*Domain.User{}, int64(3), nil)
+
+		// Act
+		users, total, err := userUsecase.GetAllUsers(context.Background(), Domain.UserFilter{}, pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Empty(t, users)
+		assert.Equal(t, int64(3), total)
+	})
+}
+
+func TestUserUsecase_ExportUsers(t *testing.T) {
+	t.Run("Success - filter passed to the stream", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		filter := Domain.UserFilter{Query: "corp", Role: Domain.RoleManager}
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "manager1", Role: Domain.RoleManager}
+		mockUserRepo.On("Stream", filter, mock.Anything).Run(func(args mock.Arguments) {
+			fn := args.Get(1).(func(user *Domain.User) error)
+			_ = fn(user)
+		}).Return(nil)
+
+		// Act
+		var exported []*Domain.User
+		err := userUsecase.ExportUsers(context.Background(), filter, func(user *Domain.User) error {
+			exported = append(exported, user)
+			return nil
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, []*Domain.User{user}, exported)
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+func TestUserUsecase_PromoteUser(t *testing.T) {
+	t.Run("Success - promote user to admin", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "usertoPromote"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleUser,
+		}
+		promotedUser := &Domain.User{
+			ID:       user.ID,
+			Username: username,
+			Role:     Domain.RoleAdmin,
+		}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
+		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(promotedUser, nil).Once()
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, "")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, promotedUser, resultUser)
+		assert.Equal(t, Domain.RoleAdmin, resultUser.Role)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - promote user to manager", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "usertoPromote"
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: username, Role: Domain.RoleUser}
+		promotedUser := &Domain.User{ID: user.ID, Username: username, Role: Domain.RoleManager}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
+		mockUserRepo.On("UpdateByUsername", username, mock.MatchedBy(func(u *Domain.User) bool {
+			return u.Role == Domain.RoleManager
+		})).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(promotedUser, nil).Once()
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, Domain.RoleManager)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleManager, resultUser.Role)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - runs in a transaction", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTxManager := new(MockTransactionManager)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), mockTxManager, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "usertoPromote"
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: username, Role: Domain.RoleUser}
+		promotedUser := &Domain.User{ID: user.ID, Username: username, Role: Domain.RoleAdmin}
+
+		mockTxManager.On("WithTransaction").Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
+		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(promotedUser, nil).Once()
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, "")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, promotedUser, resultUser)
+		mockTxManager.AssertExpectations(t)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - transaction fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTxManager := new(MockTransactionManager)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), mockTxManager, Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		mockTxManager.On("WithTransaction").Return(errors.New("transaction aborted")).Once()
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, "usertoPromote", "")
+
+		// Assert
+		assert.EqualError(t, err, "transaction aborted")
+		assert.Nil(t, resultUser)
+		mockUserRepo.AssertNotCalled(t, "UpdateByUsername", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid role", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		for _, role := range []string{Domain.RoleUser, "superuser"} {
+			// Act
+			resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, "usertoPromote", role)
+
+			// Assert
+			assert.EqualError(t, err, "invalid role, must be one of: manager, admin")
+			assert.Nil(t, resultUser)
+		}
+
+		mockUserRepo.AssertNotCalled(t, "GetByUsername", mock.Anything)
+	})
+
+	t.Run("Error - admin cannot be promoted to manager", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "adminuser"
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: username, Role: Domain.RoleAdmin}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, Domain.RoleManager)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrAlreadyAdmin)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertNotCalled(t, "UpdateByUsername", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "nonexistentuser"
+		expectedError := Domain.ErrUserNotFound
+
+		mockUserRepo.On("GetByUsername", username).Return(nil, expectedError)
+
+		// Act
+		user, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, "")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, user)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - user is already admin", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "adminuser"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleAdmin,
+		}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, "")
+
+		// Assert
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "user is already an admin")
 		assert.Nil(t, resultUser)
-		assert.Empty(t, token)
 
 		mockUserRepo.AssertExpectations(t)
-		mockPasswordService.AssertExpectations(t)
-		mockJWTService.AssertExpectations(t)
 	})
-}
 
-func TestUserUsecase_GetUserProfile(t *testing.T) {
-	t.Run("Success - user found", func(t *testing.T) {
+	t.Run("Error - update fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "usertoPromote"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleUser,
+		}
+		expectedError := errors.New("database update error")
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(expectedError)
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, "")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - get updated user fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockJWTService := new(MockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		username := "usertoPromote"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleUser,
+		}
+		expectedError := errors.New("user not found after update")
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
+		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(nil, expectedError).Once()
+
+		// Act
+		resultUser, err := userUsecase.PromoteUser(context.Background(), adminCaller, username, "")
+
+		// Assert
+		assert.Error(t, err)
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+func TestUserUsecase_DemoteAdminToUser(t *testing.T) {
+	newUsecase := func(mockUserRepo *MockUserRepository) UserUsecaseInterface {
+		return NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	}
+
+	t.Run("Success - demote admin to user", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		username := "admintoDemote"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleAdmin,
+		}
+		demotedUser := &Domain.User{
+			ID:       user.ID,
+			Username: username,
+			Role:     Domain.RoleUser,
+		}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(2), nil)
+		mockUserRepo.On("UpdateByUsername", username, mock.MatchedBy(func(u *Domain.User) bool {
+			return u.Role == Domain.RoleUser
+		})).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(demotedUser, nil).Once()
+
+		// Act
+		resultUser, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, demotedUser, resultUser)
+		assert.Equal(t, Domain.RoleUser, resultUser.Role)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - demote manager without counting admins", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		username := "managertoDemote"
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: username, Role: Domain.RoleManager}
+		demotedUser := &Domain.User{ID: user.ID, Username: username, Role: Domain.RoleUser}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
+		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(nil).Once()
+		mockUserRepo.On("GetByUsername", username).Return(demotedUser, nil).Once()
+
+		// Act
+		resultUser, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, Domain.RoleUser, resultUser.Role)
+
+		mockUserRepo.AssertExpectations(t)
+		mockUserRepo.AssertNotCalled(t, "CountByRole", mock.Anything)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		username := "nonexistentuser"
+		expectedError := Domain.ErrUserNotFound
+
+		mockUserRepo.On("GetByUsername", username).Return(nil, expectedError)
+
+		// Act
+		user, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, user)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - user is not an admin or manager", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		username := "regularuser"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleUser,
+		}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+
+		// Act
+		resultUser, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrNotPrivileged)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertNotCalled(t, "UpdateByUsername", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - cannot demote yourself", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		callerID, _ := primitive.ObjectIDFromHex(adminCaller.UserID)
+		username := "currentadmin"
+		user := &Domain.User{
+			ID:       callerID,
+			Username: username,
+			Role:     Domain.RoleAdmin,
+		}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+
+		// Act
+		resultUser, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrSelfDemotion)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertNotCalled(t, "CountByRole", mock.Anything)
+		mockUserRepo.AssertNotCalled(t, "UpdateByUsername", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - cannot demote the last admin", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		username := "lastadmin"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleAdmin,
+		}
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(1), nil)
+
+		// Act
+		resultUser, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrLastAdminDemotion)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertNotCalled(t, "UpdateByUsername", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - update fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo)
+
+		username := "admintoDemote"
+		user := &Domain.User{
+			ID:       primitive.NewObjectID(),
+			Username: username,
+			Role:     Domain.RoleAdmin,
+		}
+		expectedError := errors.New("database update error")
+
+		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(3), nil)
+		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(expectedError)
+
+		// Act
+		resultUser, err := userUsecase.DemoteAdminToUser(context.Background(), adminCaller, username)
+
+		// Assert
+		assert.ErrorIs(t, err, expectedError)
+		assert.Nil(t, resultUser)
+
+		mockUserRepo.AssertExpectations(t)
+	})
+}
+
+// Additional standalone tests
+func TestUserUsecase_DeleteUser(t *testing.T) {
+	adminID, _ := primitive.ObjectIDFromHex(adminCaller.UserID)
+
+	newUsecase := func(mockUserRepo *MockUserRepository, mockTaskRepo *MockTaskRepository, mockRefreshTokenRepo *MockRefreshTokenRepository, mockAuditRepo *MockAuditRepository) UserUsecaseInterface {
+		return NewUserUsecase(mockUserRepo, mockTaskRepo, mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), mockAuditRepo, newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	}
+
+	t.Run("Success - hard delete reassigns created tasks to the caller and unassigns the rest", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		userUsecase := newUsecase(mockUserRepo, mockTaskRepo, mockRefreshTokenRepo, mockAuditRepo)
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "leaver", Role: Domain.RoleUser}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockTaskRepo.On("ReassignCreator", user.ID, adminID).Return(int64(3), nil)
+		mockTaskRepo.On("UnassignUser", user.ID).Return(int64(2), nil)
+		mockUserRepo.On("Delete", userID).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", user.ID).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionDelete, Domain.AuditEntityUser, userID,
+			"username: leaver, tasks reassigned: 3, tasks unassigned: 2")).Return(nil)
+
+		// Act
+		result, err := userUsecase.DeleteUser(context.Background(), adminCaller, userID, false)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &Domain.UserDeletionResult{TasksReassigned: 3, TasksUnassigned: 2}, result)
+		mockUserRepo.AssertExpectations(t)
+		mockTaskRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - anonymize keeps task references", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		userUsecase := newUsecase(mockUserRepo, mockTaskRepo, mockRefreshTokenRepo, mockAuditRepo)
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "leaver", Role: Domain.RoleUser}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockUserRepo.On("Anonymize", userID).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", user.ID).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionAnonymize, Domain.AuditEntityUser, userID, "")).Return(nil)
+
+		// Act
+		result, err := userUsecase.DeleteUser(context.Background(), adminCaller, userID, true)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, &Domain.UserDeletionResult{Anonymized: true}, result)
+		mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything)
+		mockTaskRepo.AssertNotCalled(t, "ReassignCreator", mock.Anything, mock.Anything)
+		mockTaskRepo.AssertNotCalled(t, "UnassignUser", mock.Anything)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - another admin can be deleted while one remains", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		userUsecase := newUsecase(mockUserRepo, mockTaskRepo, mockRefreshTokenRepo, newMockAuditRepository())
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "otheradmin", Role: Domain.RoleAdmin}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(2), nil)
+		mockTaskRepo.On("ReassignCreator", user.ID, adminID).Return(int64(0), nil)
+		mockTaskRepo.On("UnassignUser", user.ID).Return(int64(0), nil)
+		mockUserRepo.On("Delete", userID).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", user.ID).Return(nil)
+
+		// Act
+		_, err := userUsecase.DeleteUser(context.Background(), adminCaller, userID, false)
+
+		// Assert
+		assert.NoError(t, err)
+		mockUserRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - cannot delete yourself", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		userUsecase := newUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), newMockAuditRepository())
+
+		// Act
+		result, err := userUsecase.DeleteUser(context.Background(), adminCaller, adminCaller.UserID, false)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrSelfDeletion)
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
+	})
+
+	t.Run("Error - API key cannot take over the user's tasks", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		mockTaskRepo := new(MockTaskRepository)
+		userUsecase := newUsecase(mockUserRepo, mockTaskRepo, new(MockRefreshTokenRepository), newMockAuditRepository())
+		keyCaller := Domain.Caller{UserID: primitive.NewObjectID().Hex(), Role: Domain.RoleAdmin, APIKey: true}
 
-		userID := primitive.NewObjectID().Hex()
-		expectedUser := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: "testuser",
-			Role:     Domain.RoleUser,
-		}
+		// Act
+		result, err := userUsecase.DeleteUser(context.Background(), keyCaller, primitive.NewObjectID().Hex(), false)
 
-		mockUserRepo.On("GetByID", userID).Return(expectedUser, nil)
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrReassignToAPIKey)
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything)
+		mockTaskRepo.AssertNotCalled(t, "ReassignCreator", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - cannot delete the last admin", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		userUsecase := newUsecase(mockUserRepo, mockTaskRepo, new(MockRefreshTokenRepository), newMockAuditRepository())
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "onlyadmin", Role: Domain.RoleAdmin}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockUserRepo.On("CountByRole", Domain.RoleAdmin).Return(int64(1), nil)
 
 		// Act
-		user, err := userUsecase.GetUserProfile(userID)
+		result, err := userUsecase.DeleteUser(context.Background(), adminCaller, userID, true)
 
 		// Assert
-		assert.NoError(t, err)
-		assert.Equal(t, expectedUser, user)
-
-		mockUserRepo.AssertExpectations(t)
+		assert.ErrorIs(t, err, Domain.ErrLastAdminDeletion)
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "Anonymize", mock.Anything)
+		mockTaskRepo.AssertNotCalled(t, "ReassignCreator", mock.Anything, mock.Anything)
 	})
 
 	t.Run("Error - user not found", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := newUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), newMockAuditRepository())
 
 		userID := primitive.NewObjectID().Hex()
-		expectedError := errors.New("user not found")
-
-		mockUserRepo.On("GetByID", userID).Return(nil, expectedError)
+		mockUserRepo.On("GetByID", userID).Return(nil, Domain.ErrUserNotFound)
 
 		// Act
-		user, err := userUsecase.GetUserProfile(userID)
+		result, err := userUsecase.DeleteUser(context.Background(), adminCaller, userID, false)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, user)
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		assert.Nil(t, result)
+	})
 
-		mockUserRepo.AssertExpectations(t)
+	t.Run("Error - reassigning tasks fails leaves the user in place", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		userUsecase := newUsecase(mockUserRepo, mockTaskRepo, new(MockRefreshTokenRepository), newMockAuditRepository())
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "leaver", Role: Domain.RoleUser}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockTaskRepo.On("ReassignCreator", user.ID, adminID).Return(int64(0), errors.New("database connection failed"))
+
+		// Act
+		result, err := userUsecase.DeleteUser(context.Background(), adminCaller, userID, false)
+
+		// Assert
+		assert.EqualError(t, err, "database connection failed")
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "Delete", mock.Anything)
 	})
 }
 
-func TestUserUsecase_GetAllUsers(t *testing.T) {
-	t.Run("Success - return all users", func(t *testing.T) {
+func TestUserUsecase_DeactivateUser(t *testing.T) {
+	newUsecase := func(mockUserRepo *MockUserRepository, mockRefreshTokenRepo *MockRefreshTokenRepository, mockAuditRepo *MockAuditRepository) UserUsecaseInterface {
+		return NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), mockAuditRepo, newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	}
+
+	t.Run("Success - deactivates the user and revokes their refresh tokens", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
-
-		expectedUsers := []*Domain.User{
-			{
-				ID:       primitive.NewObjectID(),
-				Username: "user1",
-				Role:     Domain.RoleUser,
-			},
-			{
-				ID:       primitive.NewObjectID(),
-				Username: "admin1",
-				Role:     Domain.RoleAdmin,
-			},
-		}
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		userUsecase := newUsecase(mockUserRepo, mockRefreshTokenRepo, mockAuditRepo)
 
-		mockUserRepo.On("GetAll").Return(expectedUsers, nil)
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "leaver", Role: Domain.RoleUser, IsActive: true}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockUserRepo.On("SetActive", userID, false).Return(nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", user.ID).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionDeactivate, Domain.AuditEntityUser, userID, "is_active: true -> false")).Return(nil)
 
 		// Act
-		users, err := userUsecase.GetAllUsers()
+		result, err := userUsecase.DeactivateUser(context.Background(), adminCaller, userID)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, expectedUsers, users)
-		assert.Len(t, users, 2)
-
+		assert.False(t, result.IsActive)
 		mockUserRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
 	})
 
-	t.Run("Success - return empty list", func(t *testing.T) {
+	t.Run("Success - already deactivated user is left alone", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
-
-		expectedUsers := []*Domain.User{}
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		userUsecase := newUsecase(mockUserRepo, mockRefreshTokenRepo, mockAuditRepo)
 
-		mockUserRepo.On("GetAll").Return(expectedUsers, nil)
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "leaver", Role: Domain.RoleUser, IsActive: false}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockRefreshTokenRepo.On("RevokeAllForUser", user.ID).Return(nil)
 
 		// Act
-		users, err := userUsecase.GetAllUsers()
+		result, err := userUsecase.DeactivateUser(context.Background(), adminCaller, userID)
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, expectedUsers, users)
-		assert.Len(t, users, 0)
-
-		mockUserRepo.AssertExpectations(t)
+		assert.False(t, result.IsActive)
+		mockUserRepo.AssertNotCalled(t, "SetActive", mock.Anything, mock.Anything)
+		mockAuditRepo.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
-	t.Run("Error - repository error", func(t *testing.T) {
+	t.Run("Error - cannot deactivate yourself", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := newUsecase(mockUserRepo, new(MockRefreshTokenRepository), new(MockAuditRepository))
 
-		expectedError := errors.New("database error")
+		// Act
+		result, err := userUsecase.DeactivateUser(context.Background(), adminCaller, adminCaller.UserID)
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrSelfDeactivation)
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "SetActive", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - user not found", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		userUsecase := newUsecase(mockUserRepo, mockRefreshTokenRepo, new(MockAuditRepository))
 
-		mockUserRepo.On("GetAll").Return([]*Domain.User(nil), expectedError)
+		userID := primitive.NewObjectID().Hex()
+		mockUserRepo.On("GetByID", userID).Return(nil, Domain.ErrUserNotFound)
 
 		// Act
-		users, err := userUsecase.GetAllUsers()
+		result, err := userUsecase.DeactivateUser(context.Background(), adminCaller, userID)
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, users)
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		assert.Nil(t, result)
+		mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
+	})
+}
 
+func TestUserUsecase_ActivateUser(t *testing.T) {
+	t.Run("Success - reactivates a deactivated user", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), mockAuditRepo, newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+
+		user := &Domain.User{ID: primitive.NewObjectID(), Username: "returner", Role: Domain.RoleUser, IsActive: false}
+		userID := user.ID.Hex()
+		mockUserRepo.On("GetByID", userID).Return(user, nil)
+		mockUserRepo.On("SetActive", userID, true).Return(nil)
+		mockAuditRepo.On("Create", auditEntryMatching(adminCaller.UserID, Domain.AuditActionActivate, Domain.AuditEntityUser, userID, "is_active: false -> true")).Return(nil)
+
+		// Act
+		result, err := userUsecase.ActivateUser(context.Background(), adminCaller, userID)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, result.IsActive)
 		mockUserRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+		mockRefreshTokenRepo.AssertNotCalled(t, "RevokeAllForUser", mock.Anything)
 	})
 }
 
-func TestUserUsecase_PromoteUserToAdmin(t *testing.T) {
-	t.Run("Success - promote user to admin", func(t *testing.T) {
+func TestUserUsecase_UpdateProfile(t *testing.T) {
+	userID := primitive.NewObjectID()
+	newUser := func() *Domain.User {
+		return &Domain.User{ID: userID, Username: "alice", UsernameLower: "alice", Email: "alice@example.com", EmailVerified: true, Password: "hashed_password", Role: Domain.RoleUser}
+	}
+
+	type mocks struct {
+		userRepo   *MockUserRepository
+		jwtService *MockJWTService
+		notifier   *MockNotifier
+		auditRepo  *MockAuditRepository
+	}
+	setup := func() (UserUsecaseInterface, mocks) {
+		m := mocks{new(MockUserRepository), new(MockJWTService), new(MockNotifier), new(MockAuditRepository)}
+		userUsecase := NewUserUsecase(m.userRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), m.jwtService, m.notifier, m.auditRepo, newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		return userUsecase, m
+	}
+
+	t.Run("Success - username and email changed", func(t *testing.T) {
 		// Arrange
-		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase, m := setup()
+		expiresAt := time.Now().Add(24 * time.Hour)
+		m.userRepo.On("GetByID", userID.Hex()).Return(newUser(), nil)
+		m.userRepo.On("GetByUsername", "bob").Return(nil, Domain.ErrUserNotFound)
+		m.userRepo.On("GetByEmail", "bob@example.com").Return(nil, Domain.ErrUserNotFound)
+		m.userRepo.On("UpdateProfile", userID.Hex(), mock.MatchedBy(func(user *Domain.User) bool {
+			return user.Username == "Bob" && user.UsernameLower == "bob" && user.Email == "bob@example.com" && !user.EmailVerified
+		})).Return(nil)
+		m.auditRepo.On("Create", auditEntryMatching(userID.Hex(), Domain.AuditActionUpdateProfile, Domain.AuditEntityUser, userID.Hex(), "username: alice -> Bob, email changed")).Return(nil)
+		m.jwtService.On("GenerateEmailVerificationToken", mock.AnythingOfType("*Domain.User")).Return("verification-token", expiresAt, nil)
+		m.notifier.On("SendEmailVerification", mock.AnythingOfType("*Domain.User"), "verification-token", expiresAt).Return(nil)
 
-		username := "usertoPromote"
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: username,
-			Role:     Domain.RoleUser,
-		}
-		promotedUser := &Domain.User{
-			ID:       user.ID,
-			Username: username,
-			Role:     Domain.RoleAdmin,
-		}
+		// Act
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Username: " Bob ", Email: "Bob@Example.com"})
 
-		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
-		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(nil).Once()
-		mockUserRepo.On("GetByUsername", username).Return(promotedUser, nil).Once()
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "Bob", user.Username)
+		assert.Equal(t, "bob@example.com", user.Email)
+		assert.False(t, user.EmailVerified)
+		assert.Equal(t, Domain.RoleUser, user.Role)
+		assert.Equal(t, "hashed_password", user.Password)
+		m.userRepo.AssertExpectations(t)
+		m.auditRepo.AssertExpectations(t)
+		m.notifier.AssertExpectations(t)
+	})
+
+	t.Run("Success - changing only the case skips the uniqueness check", func(t *testing.T) {
+		// Arrange
+		userUsecase, m := setup()
+		m.userRepo.On("GetByID", userID.Hex()).Return(newUser(), nil)
+		m.userRepo.On("UpdateProfile", userID.Hex(), mock.AnythingOfType("*Domain.User")).Return(nil)
+		m.auditRepo.On("Create", mock.Anything).Return(nil)
 
 		// Act
-		resultUser, err := userUsecase.PromoteUserToAdmin(username)
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Username: "Alice"})
 
 		// Assert
 		assert.NoError(t, err)
-		assert.Equal(t, promotedUser, resultUser)
-		assert.Equal(t, Domain.RoleAdmin, resultUser.Role)
+		assert.Equal(t, "Alice", user.Username)
+		assert.True(t, user.EmailVerified)
+		m.userRepo.AssertNotCalled(t, "GetByUsername", mock.Anything)
+		m.notifier.AssertNotCalled(t, "SendEmailVerification", mock.Anything, mock.Anything, mock.Anything)
+	})
 
-		mockUserRepo.AssertExpectations(t)
+	t.Run("Success - nothing to change", func(t *testing.T) {
+		// Arrange
+		userUsecase, m := setup()
+		m.userRepo.On("GetByID", userID.Hex()).Return(newUser(), nil)
+
+		// Act
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Username: "alice", Email: "ALICE@example.com"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "alice", user.Username)
+		m.userRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+		m.auditRepo.AssertNotCalled(t, "Create", mock.Anything)
 	})
 
-	t.Run("Error - user not found", func(t *testing.T) {
+	t.Run("Error - username taken by another user", func(t *testing.T) {
 		// Arrange
-		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase, m := setup()
+		m.userRepo.On("GetByID", userID.Hex()).Return(newUser(), nil)
+		m.userRepo.On("GetByUsername", "bob").Return(&Domain.User{ID: primitive.NewObjectID(), Username: "bob"}, nil)
 
-		username := "nonexistentuser"
-		expectedError := errors.New("user not found")
+		// Act
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Username: "BOB"})
 
-		mockUserRepo.On("GetByUsername", username).Return(nil, expectedError)
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrUsernameTaken)
+		assert.Nil(t, user)
+		m.userRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - email taken by another user", func(t *testing.T) {
+		// Arrange
+		userUsecase, m := setup()
+		m.userRepo.On("GetByID", userID.Hex()).Return(newUser(), nil)
+		m.userRepo.On("GetByEmail", "bob@example.com").Return(&Domain.User{ID: primitive.NewObjectID(), Username: "bob"}, nil)
 
 		// Act
-		user, err := userUsecase.PromoteUserToAdmin(username)
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Email: "bob@example.com"})
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
+		assert.ErrorIs(t, err, Domain.ErrEmailTaken)
 		assert.Nil(t, user)
+		m.userRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
+	})
 
-		mockUserRepo.AssertExpectations(t)
+	t.Run("Error - invalid username", func(t *testing.T) {
+		// Arrange
+		userUsecase, m := setup()
+		m.userRepo.On("GetByID", userID.Hex()).Return(newUser(), nil)
+
+		// Act
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Username: "al"})
+
+		// Assert
+		assert.EqualError(t, err, "username must be between 3 and 32 characters")
+		assert.Nil(t, user)
+		m.userRepo.AssertNotCalled(t, "UpdateProfile", mock.Anything, mock.Anything)
 	})
 
-	t.Run("Error - user is already admin", func(t *testing.T) {
+	t.Run("Error - user not found", func(t *testing.T) {
 		// Arrange
+		userUsecase, m := setup()
+		m.userRepo.On("GetByID", userID.Hex()).Return(nil, Domain.ErrUserNotFound)
+
+		// Act
+		user, err := userUsecase.UpdateProfile(context.Background(), userID.Hex(), Domain.ProfileUpdateRequest{Username: "bob"})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrUserNotFound)
+		assert.Nil(t, user)
+	})
+}
+
+func TestUserUsecase_VerifyEmail(t *testing.T) {
+	userID := primitive.NewObjectID()
+	claims := &Domain.EmailVerificationClaims{UserID: userID.Hex(), Email: "user@example.com"}
+
+	setup := func() (UserUsecaseInterface, *MockUserRepository, *MockJWTService) {
 		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
-
-		username := "adminuser"
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: username,
-			Role:     Domain.RoleAdmin,
-		}
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		return userUsecase, mockUserRepo, mockJWTService
+	}
 
-		mockUserRepo.On("GetByUsername", username).Return(user, nil)
+	t.Run("Success - email marked verified", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockJWTService := setup()
+		user := &Domain.User{ID: userID, Username: "testuser", Email: "user@example.com", Role: Domain.RoleUser}
+		mockJWTService.On("ValidateEmailVerificationToken", "verification-token").Return(claims, nil)
+		mockUserRepo.On("GetByID", userID.Hex()).Return(user, nil)
+		mockUserRepo.On("MarkEmailVerified", userID.Hex(), "user@example.com").Return(nil)
 
 		// Act
-		resultUser, err := userUsecase.PromoteUserToAdmin(username)
+		result, err := userUsecase.VerifyEmail(context.Background(), "verification-token")
 
 		// Assert
-		assert.Error(t, err)
-		assert.Contains(t, err.Error(), "user is already an admin")
-		assert.Nil(t, resultUser)
-
+		assert.NoError(t, err)
+		assert.True(t, result.EmailVerified)
 		mockUserRepo.AssertExpectations(t)
 	})
 
-	t.Run("Error - update fails", func(t *testing.T) {
+	t.Run("Success - already verified", func(t *testing.T) {
 		// Arrange
-		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase, mockUserRepo, mockJWTService := setup()
+		user := &Domain.User{ID: userID, Username: "testuser", Email: "user@example.com", EmailVerified: true, Role: Domain.RoleUser}
+		mockJWTService.On("ValidateEmailVerificationToken", "verification-token").Return(claims, nil)
+		mockUserRepo.On("GetByID", userID.Hex()).Return(user, nil)
 
-		username := "usertoPromote"
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: username,
-			Role:     Domain.RoleUser,
-		}
-		expectedError := errors.New("database update error")
+		// Act
+		result, err := userUsecase.VerifyEmail(context.Background(), "verification-token")
 
-		mockUserRepo.On("GetByUsername", username).Return(user, nil)
-		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(expectedError)
+		// Assert
+		assert.NoError(t, err)
+		assert.True(t, result.EmailVerified)
+		mockUserRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - expired token", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockJWTService := setup()
+		mockJWTService.On("ValidateEmailVerificationToken", "verification-token").Return(nil, Domain.ErrVerificationTokenExpired)
 
 		// Act
-		resultUser, err := userUsecase.PromoteUserToAdmin(username)
+		result, err := userUsecase.VerifyEmail(context.Background(), "verification-token")
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, resultUser)
-
-		mockUserRepo.AssertExpectations(t)
+		assert.ErrorIs(t, err, Domain.ErrVerificationTokenExpired)
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "GetByID", mock.Anything)
 	})
 
-	t.Run("Error - get updated user fails", func(t *testing.T) {
+	t.Run("Error - email changed since the token was issued", func(t *testing.T) {
 		// Arrange
-		mockUserRepo := new(MockUserRepository)
-		mockPasswordService := new(MockPasswordService)
-		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase, mockUserRepo, mockJWTService := setup()
+		user := &Domain.User{ID: userID, Username: "testuser", Email: "new@example.com", Role: Domain.RoleUser}
+		mockJWTService.On("ValidateEmailVerificationToken", "verification-token").Return(claims, nil)
+		mockUserRepo.On("GetByID", userID.Hex()).Return(user, nil)
 
-		username := "usertoPromote"
-		user := &Domain.User{
-			ID:       primitive.NewObjectID(),
-			Username: username,
-			Role:     Domain.RoleUser,
-		}
-		expectedError := errors.New("user not found after update")
+		// Act
+		result, err := userUsecase.VerifyEmail(context.Background(), "verification-token")
 
-		mockUserRepo.On("GetByUsername", username).Return(user, nil).Once()
-		mockUserRepo.On("UpdateByUsername", username, mock.AnythingOfType("*Domain.User")).Return(nil).Once()
-		mockUserRepo.On("GetByUsername", username).Return(nil, expectedError).Once()
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidVerificationToken)
+		assert.Nil(t, result)
+		mockUserRepo.AssertNotCalled(t, "MarkEmailVerified", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - user no longer exists", func(t *testing.T) {
+		// Arrange
+		userUsecase, mockUserRepo, mockJWTService := setup()
+		mockJWTService.On("ValidateEmailVerificationToken", "verification-token").Return(claims, nil)
+		mockUserRepo.On("GetByID", userID.Hex()).Return(nil, Domain.ErrUserNotFound)
 
 		// Act
-		resultUser, err := userUsecase.PromoteUserToAdmin(username)
+		result, err := userUsecase.VerifyEmail(context.Background(), "verification-token")
 
 		// Assert
-		assert.Error(t, err)
-		assert.Equal(t, expectedError, err)
-		assert.Nil(t, resultUser)
-
-		mockUserRepo.AssertExpectations(t)
+		assert.ErrorIs(t, err, Domain.ErrInvalidVerificationToken)
+		assert.Nil(t, result)
 	})
 }
 
-// Additional standalone tests
 func TestNewUserUsecase(t *testing.T) {
 	mockUserRepo := new(MockUserRepository)
 	mockPasswordService := new(MockPasswordService)
 	mockJWTService := new(MockJWTService)
 
-	usecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+	usecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 	assert.NotNil(t, usecase)
 	assert.Implements(t, (*UserUsecaseInterface)(nil), usecase)
@@ -715,7 +2906,7 @@ func TestUserUsecaseInterface(t *testing.T) {
 		passwordService: mockPasswordService,
 		jwtService:      mockJWTService,
 	}
-	var _ UserUsecaseInterface = NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+	var _ UserUsecaseInterface = NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 }
 
 // Edge case tests
@@ -725,7 +2916,7 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		userReq := Domain.UserRequest{
 			Username: "testuser",
@@ -733,13 +2924,13 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 		}
 		hashedPassword := "hashed_123456"
 
-		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, errors.New("user not found"))
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
 		mockPasswordService.On("HashPassword", userReq.Password).Return(hashedPassword, nil)
 		mockUserRepo.On("CountUsers").Return(int64(1), nil)
 		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
 
 		// Act
-		user, err := userUsecase.RegisterUser(userReq)
+		user, err := userUsecase.RegisterUser(context.Background(), userReq)
 
 		// Assert
 		assert.NoError(t, err)
@@ -753,9 +2944,10 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 	t.Run("Login with admin user", func(t *testing.T) {
 		// Arrange
 		mockUserRepo := new(MockUserRepository)
+		mockRefreshTokenRepo := new(MockRefreshTokenRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), mockRefreshTokenRepo, Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		loginReq := Domain.LoginRequest{
 			Username: "admin",
@@ -766,20 +2958,26 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 			Username: loginReq.Username,
 			Password: "hashed_adminpass",
 			Role:     Domain.RoleAdmin,
+			IsActive: true,
 		}
 		expectedToken := "admin.jwt.token"
 
 		mockUserRepo.On("GetByUsername", loginReq.Username).Return(adminUser, nil)
 		mockPasswordService.On("ComparePassword", adminUser.Password, loginReq.Password).Return(nil)
+		mockPasswordService.On("NeedsRehash", adminUser.Password).Return(false)
 		mockJWTService.On("GenerateToken", adminUser).Return(expectedToken, nil)
+		mockJWTService.On("GenerateRefreshToken").Return("admin-refresh-token", time.Now().Add(time.Hour), nil)
+		mockUserRepo.On("UpdateLastLogin", adminUser.ID.Hex(), mock.AnythingOfType("time.Time")).Return(nil)
+		mockJWTService.On("AccessTokenTTL").Return(15 * time.Minute)
+		mockRefreshTokenRepo.On("Create", refreshTokenMatching(adminUser.ID, "", "admin-refresh-token")).Return(nil)
 
 		// Act
-		resultUser, token, err := userUsecase.LoginUser(loginReq)
+		resultUser, tokens, err := userUsecase.LoginUser(context.Background(), loginReq)
 
 		// Assert
 		assert.NoError(t, err)
 		assert.Equal(t, adminUser, resultUser)
-		assert.Equal(t, expectedToken, token)
+		assert.Equal(t, expectedToken, tokens.AccessToken)
 		assert.Equal(t, Domain.RoleAdmin, resultUser.Role)
 
 		mockUserRepo.AssertExpectations(t)
@@ -792,7 +2990,7 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 		mockUserRepo := new(MockUserRepository)
 		mockPasswordService := new(MockPasswordService)
 		mockJWTService := new(MockJWTService)
-		userUsecase := NewUserUsecase(mockUserRepo, mockPasswordService, mockJWTService)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), mockJWTService, new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
 
 		userID := primitive.NewObjectID().Hex()
 		adminUser := &Domain.User{
@@ -804,7 +3002,7 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 		mockUserRepo.On("GetByID", userID).Return(adminUser, nil)
 
 		// Act
-		user, err := userUsecase.GetUserProfile(userID)
+		user, err := userUsecase.GetUserProfile(context.Background(), userID)
 
 		// Assert
 		assert.NoError(t, err)
@@ -813,4 +3011,55 @@ func TestUserUsecase_EdgeCases(t *testing.T) {
 
 		mockUserRepo.AssertExpectations(t)
 	})
-}
\ No newline at end of file
+}
+
+func TestUserUsecase_BusinessMetrics(t *testing.T) {
+	t.Run("Success - registrations are counted by the role given", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockMetrics := new(MockBusinessMetrics)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), mockMetrics, Infrastructure.NewNopLogger())
+
+		userReq := Domain.UserRequest{Username: "counted", Password: "password123"}
+		mockUserRepo.On("GetByUsername", userReq.Username).Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", userReq.Password).Return("hashed_password", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(nil)
+		mockMetrics.On("Increment", Infrastructure.MetricUsersRegistered, map[string]string{Infrastructure.MetricLabelRole: Domain.RoleUser}).Once()
+
+		// Act
+		_, err := userUsecase.RegisterUser(context.Background(), userReq)
+
+		// Assert
+		assert.NoError(t, err)
+		mockMetrics.AssertExpectations(t)
+	})
+
+	t.Run("Error - refused logins are counted by reason", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockMetrics := new(MockBusinessMetrics)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), Infrastructure.NewNopEventBus(), mockMetrics, Infrastructure.NewNopLogger())
+
+		inactive := &Domain.User{ID: primitive.NewObjectID(), Username: "inactive", Password: "hashed_password", Role: Domain.RoleUser, IsActive: false}
+		mockUserRepo.On("GetByUsername", "missing").Return(nil, Domain.ErrUserNotFound)
+		mockUserRepo.On("GetByUsername", "inactive").Return(inactive, nil)
+		mockPasswordService.On("ComparePassword", inactive.Password, "wrong").Return(errors.New("mismatch"))
+		mockPasswordService.On("ComparePassword", inactive.Password, "right").Return(nil)
+		mockMetrics.On("Increment", Infrastructure.MetricLoginFailures, map[string]string{Infrastructure.MetricLabelReason: Infrastructure.LoginFailureInvalidCredentials}).Twice()
+		mockMetrics.On("Increment", Infrastructure.MetricLoginFailures, map[string]string{Infrastructure.MetricLabelReason: Infrastructure.LoginFailureDeactivated}).Once()
+
+		// Act
+		_, _, missingErr := userUsecase.LoginUser(context.Background(), Domain.LoginRequest{Username: "missing", Password: "right"})
+		_, _, wrongErr := userUsecase.LoginUser(context.Background(), Domain.LoginRequest{Username: "inactive", Password: "wrong"})
+		_, _, inactiveErr := userUsecase.LoginUser(context.Background(), Domain.LoginRequest{Username: "inactive", Password: "right"})
+
+		// Assert
+		assert.ErrorIs(t, missingErr, Domain.ErrInvalidCredentials)
+		assert.ErrorIs(t, wrongErr, Domain.ErrInvalidCredentials)
+		assert.ErrorIs(t, inactiveErr, Domain.ErrAccountDeactivated)
+		mockMetrics.AssertExpectations(t)
+	})
+}
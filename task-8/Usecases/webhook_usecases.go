@@ -0,0 +1,189 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+	"task_manager/Repositories"
+)
+
+// Webhook secret length limits, in bytes
+const (
+	MinWebhookSecretLength = 16
+	MaxWebhookSecretLength = 256
+)
+
+// MaxWebhookURLLength bounds webhook URLs
+const MaxWebhookURLLength = 2048
+
+// WebhookUsecaseInterface defines the contract for webhook management
+type WebhookUsecaseInterface interface {
+	CreateWebhook(ctx context.Context, caller Domain.Caller, req Domain.WebhookRequest) (*Domain.CreatedWebhook, error)
+	ListWebhooks(ctx context.Context) ([]*Domain.Webhook, error)
+	GetWebhook(ctx context.Context, id string) (*Domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, caller Domain.Caller, id string, req Domain.WebhookRequest) (*Domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, caller Domain.Caller, id string) error
+	GetDeliveries(ctx context.Context, id string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error)
+}
+
+// WebhookUsecase implements webhook business logic
+type WebhookUsecase struct {
+	webhookRepo  Repositories.WebhookRepositoryInterface
+	deliveryRepo Repositories.WebhookDeliveryRepositoryInterface
+	auditRepo    Repositories.AuditRepositoryInterface
+	logger       *slog.Logger
+}
+
+// NewWebhookUsecase creates a new instance of WebhookUsecase
+func NewWebhookUsecase(webhookRepo Repositories.WebhookRepositoryInterface, deliveryRepo Repositories.WebhookDeliveryRepositoryInterface, auditRepo Repositories.AuditRepositoryInterface, logger *slog.Logger) WebhookUsecaseInterface {
+	return &WebhookUsecase{
+		webhookRepo:  webhookRepo,
+		deliveryRepo: deliveryRepo,
+		auditRepo:    auditRepo,
+		logger:       logger,
+	}
+}
+
+// CreateWebhook registers a webhook on behalf of the calling admin. Without a secret one is
+// generated; either way the secret is returned only here.
+func (wu *WebhookUsecase) CreateWebhook(ctx context.Context, caller Domain.Caller, req Domain.WebhookRequest) (*Domain.CreatedWebhook, error) {
+	createdBy, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	webhook := &Domain.Webhook{CreatedBy: createdBy}
+	if err := applyWebhookRequest(webhook, req); err != nil {
+		return nil, err
+	}
+	if webhook.Secret == "" {
+		webhook.Secret, err = Infrastructure.GenerateOpaqueToken()
+		if err != nil {
+			return nil, errors.New("failed to generate webhook secret")
+		}
+	}
+
+	if err := wu.webhookRepo.Create(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	recordAudit(ctx, wu.logger, wu.auditRepo, caller, Domain.AuditActionCreate, Domain.AuditEntityWebhook, webhook.ID.Hex(), fmt.Sprintf("url: %s, events: %s", webhook.URL, strings.Join(webhook.Events, ", ")))
+
+	return &Domain.CreatedWebhook{Webhook: webhook, Secret: webhook.Secret}, nil
+}
+
+// ListWebhooks returns every webhook, inactive ones included, without their secrets
+func (wu *WebhookUsecase) ListWebhooks(ctx context.Context) ([]*Domain.Webhook, error) {
+	return wu.webhookRepo.GetAll(ctx)
+}
+
+// GetWebhook returns a webhook by its ID
+func (wu *WebhookUsecase) GetWebhook(ctx context.Context, id string) (*Domain.Webhook, error) {
+	return wu.webhookRepo.GetByID(ctx, id)
+}
+
+// UpdateWebhook replaces a webhook's URL, events and active flag on behalf of the calling admin.
+// The secret changes only when the request has one.
+func (wu *WebhookUsecase) UpdateWebhook(ctx context.Context, caller Domain.Caller, id string, req Domain.WebhookRequest) (*Domain.Webhook, error) {
+	webhook, err := wu.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := applyWebhookRequest(webhook, req); err != nil {
+		return nil, err
+	}
+
+	if err := wu.webhookRepo.Update(ctx, webhook); err != nil {
+		return nil, err
+	}
+
+	diff := fmt.Sprintf("url: %s, events: %s, active: %t", webhook.URL, strings.Join(webhook.Events, ", "), webhook.Active)
+	if req.Secret != "" {
+		diff += ", secret rotated"
+	}
+	recordAudit(ctx, wu.logger, wu.auditRepo, caller, Domain.AuditActionUpdate, Domain.AuditEntityWebhook, id, diff)
+
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook and its delivery log on behalf of the calling admin
+func (wu *WebhookUsecase) DeleteWebhook(ctx context.Context, caller Domain.Caller, id string) error {
+	webhook, err := wu.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := wu.webhookRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	recordAudit(ctx, wu.logger, wu.auditRepo, caller, Domain.AuditActionDelete, Domain.AuditEntityWebhook, id, "url: "+webhook.URL)
+
+	// The log is of no use without its webhook; a failure leaves records that expire on their own
+	if err := wu.deliveryRepo.DeleteByWebhookID(ctx, id); err != nil {
+		wu.logger.WarnContext(ctx, "failed to delete webhook deliveries", "webhook_id", id, "error", err)
+	}
+	return nil
+}
+
+// GetDeliveries returns one page of a webhook's deliveries, newest first, with the total number of them
+func (wu *WebhookUsecase) GetDeliveries(ctx context.Context, id string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error) {
+	if pagination.Limit < 0 || pagination.Offset < 0 {
+		return nil, 0, Domain.ErrInvalidPagination
+	}
+	if pagination.Sort != "" {
+		return nil, 0, Domain.NewError(Domain.ErrInvalidInput, "deliveries are always sorted newest first")
+	}
+
+	if _, err := wu.webhookRepo.GetByID(ctx, id); err != nil {
+		return nil, 0, err
+	}
+
+	return wu.deliveryRepo.GetByWebhookID(ctx, id, pagination)
+}
+
+// applyWebhookRequest validates a create or update request and copies it onto the webhook.
+// An empty secret leaves the webhook's secret as it is.
+func applyWebhookRequest(webhook *Domain.Webhook, req Domain.WebhookRequest) error {
+	webhookURL := strings.TrimSpace(req.URL)
+	parsed, err := url.Parse(webhookURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" || len(webhookURL) > MaxWebhookURLLength {
+		return Domain.NewError(Domain.ErrInvalidInput, "invalid url, must be an absolute http or https URL of at most %d characters", MaxWebhookURLLength)
+	}
+
+	if len(req.Events) == 0 {
+		return Domain.NewError(Domain.ErrInvalidInput, "at least one event is required")
+	}
+	events := make([]string, 0, len(req.Events))
+	seen := map[string]bool{}
+	for _, event := range req.Events {
+		if !Domain.IsValidEventType(event) {
+			return Domain.NewError(Domain.ErrInvalidInput, "invalid event %q, must be one of: %s", event, strings.Join(Domain.EventTypes, ", "))
+		}
+		if !seen[event] {
+			seen[event] = true
+			events = append(events, event)
+		}
+	}
+
+	if req.Secret != "" && (len(req.Secret) < MinWebhookSecretLength || len(req.Secret) > MaxWebhookSecretLength) {
+		return Domain.NewError(Domain.ErrInvalidInput, "invalid secret, must be between %d and %d characters", MinWebhookSecretLength, MaxWebhookSecretLength)
+	}
+
+	webhook.URL = webhookURL
+	webhook.Events = events
+	webhook.Active = req.Active == nil || *req.Active
+	if req.Secret != "" {
+		webhook.Secret = req.Secret
+	}
+	return nil
+}
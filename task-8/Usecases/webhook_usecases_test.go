@@ -0,0 +1,573 @@
+package Usecases
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Infrastructure"
+)
+
+// MockWebhookRepository is a mock implementation of WebhookRepositoryInterface
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) Create(ctx context.Context, webhook *Domain.Webhook) error {
+	args := m.Called(webhook)
+	if args.Error(0) == nil {
+		webhook.ID = primitive.NewObjectID()
+	}
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) GetByID(ctx context.Context, id string) (*Domain.Webhook, error) {
+	args := m.Called(id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*Domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetAll(ctx context.Context) ([]*Domain.Webhook, error) {
+	args := m.Called()
+	return args.Get(0).([]*Domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) GetActiveByEvent(ctx context.Context, eventType string) ([]*Domain.Webhook, error) {
+	args := m.Called(eventType)
+	return args.Get(0).([]*Domain.Webhook), args.Error(1)
+}
+
+func (m *MockWebhookRepository) Update(ctx context.Context, webhook *Domain.Webhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) Delete(ctx context.Context, id string) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockWebhookDeliveryRepository is a mock implementation of WebhookDeliveryRepositoryInterface
+type MockWebhookDeliveryRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookDeliveryRepository) Create(ctx context.Context, delivery *Domain.WebhookDelivery) error {
+	args := m.Called(delivery)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) GetByWebhookID(ctx context.Context, webhookID string, pagination Domain.Pagination) ([]*Domain.WebhookDelivery, int64, error) {
+	args := m.Called(webhookID, pagination)
+	if args.Get(0) == nil {
+		return nil, 0, args.Error(2)
+	}
+	return args.Get(0).([]*Domain.WebhookDelivery), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockWebhookDeliveryRepository) DeleteByWebhookID(ctx context.Context, webhookID string) error {
+	args := m.Called(webhookID)
+	return args.Error(0)
+}
+
+func (m *MockWebhookDeliveryRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// MockEventBus is a mock implementation of Infrastructure.EventBus
+type MockEventBus struct {
+	mock.Mock
+}
+
+func (m *MockEventBus) Publish(ctx context.Context, event Domain.Event) {
+	m.Called(event)
+}
+
+// eventOf matches a published event by its type, actor and data
+func eventOf(eventType, actorID string, data interface{}) interface{} {
+	return mock.MatchedBy(func(event Domain.Event) bool {
+		return event.Type == eventType && event.ActorID == actorID && assert.ObjectsAreEqual(data, event.Data) && event.ID != "" && !event.OccurredAt.IsZero()
+	})
+}
+
+func TestWebhookUsecase_CreateWebhook(t *testing.T) {
+	t.Run("Success - a secret is generated and returned once", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, new(MockWebhookDeliveryRepository), mockAuditRepo, Infrastructure.NewNopLogger())
+
+		var stored *Domain.Webhook
+		mockWebhookRepo.On("Create", mock.AnythingOfType("*Domain.Webhook")).Run(func(args mock.Arguments) {
+			stored = args.Get(0).(*Domain.Webhook)
+		}).Return(nil)
+		mockAuditRepo.On("Create", mock.MatchedBy(func(entry *Domain.AuditEntry) bool {
+			return entry.Action == Domain.AuditActionCreate && entry.Entity == Domain.AuditEntityWebhook && entry.Diff == "url: https://ci.example.com/hook, events: task.created, task.deleted"
+		})).Return(nil)
+
+		// Act
+		created, err := webhookUsecase.CreateWebhook(context.Background(), adminCaller, Domain.WebhookRequest{
+			URL:    " https://ci.example.com/hook ",
+			Events: []string{Domain.EventTaskCreated, Domain.EventTaskDeleted, Domain.EventTaskCreated},
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "https://ci.example.com/hook", created.URL)
+		assert.Equal(t, []string{Domain.EventTaskCreated, Domain.EventTaskDeleted}, created.Events)
+		assert.True(t, created.Active)
+		assert.Equal(t, adminCaller.UserID, created.CreatedBy.Hex())
+		assert.Len(t, created.Secret, 43)
+		assert.Equal(t, stored.Secret, created.Secret)
+		mockWebhookRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - a given secret is kept and the webhook may start inactive", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, new(MockWebhookDeliveryRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+		mockWebhookRepo.On("Create", mock.AnythingOfType("*Domain.Webhook")).Return(nil)
+		active := false
+
+		// Act
+		created, err := webhookUsecase.CreateWebhook(context.Background(), adminCaller, Domain.WebhookRequest{
+			URL:    "http://localhost:9000/events",
+			Secret: "0123456789abcdef",
+			Events: []string{Domain.EventUserPromoted},
+			Active: &active,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "0123456789abcdef", created.Secret)
+		assert.False(t, created.Active)
+	})
+
+	t.Run("Error - invalid requests", func(t *testing.T) {
+		testCases := []struct {
+			name          string
+			req           Domain.WebhookRequest
+			expectedError string
+		}{
+			{"relative URL", Domain.WebhookRequest{URL: "/hook", Events: []string{Domain.EventTaskCreated}}, "invalid url, must be an absolute http or https URL of at most 2048 characters"},
+			{"other scheme", Domain.WebhookRequest{URL: "ftp://example.com/hook", Events: []string{Domain.EventTaskCreated}}, "invalid url, must be an absolute http or https URL of at most 2048 characters"},
+			{"URL too long", Domain.WebhookRequest{URL: "https://example.com/" + strings.Repeat("a", MaxWebhookURLLength), Events: []string{Domain.EventTaskCreated}}, "invalid url, must be an absolute http or https URL of at most 2048 characters"},
+			{"no events", Domain.WebhookRequest{URL: "https://example.com/hook", Events: []string{}}, "at least one event is required"},
+			{"unknown event", Domain.WebhookRequest{URL: "https://example.com/hook", Events: []string{"task.archived"}}, `invalid event "task.archived", must be one of: task.created, task.updated, task.deleted, user.registered, user.promoted`},
+			{"short secret", Domain.WebhookRequest{URL: "https://example.com/hook", Secret: "hunter2", Events: []string{Domain.EventTaskCreated}}, "invalid secret, must be between 16 and 256 characters"},
+		}
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				// Arrange
+				mockWebhookRepo := new(MockWebhookRepository)
+				webhookUsecase := NewWebhookUsecase(mockWebhookRepo, new(MockWebhookDeliveryRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+
+				// Act
+				created, err := webhookUsecase.CreateWebhook(context.Background(), adminCaller, tc.req)
+
+				// Assert
+				assert.Nil(t, created)
+				assert.ErrorIs(t, err, Domain.ErrInvalidInput)
+				assert.EqualError(t, err, tc.expectedError)
+				mockWebhookRepo.AssertNotCalled(t, "Create", mock.Anything)
+			})
+		}
+	})
+}
+
+func TestWebhookUsecase_UpdateWebhook(t *testing.T) {
+	t.Run("Success - the secret is kept unless a new one is given", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		mockAuditRepo := new(MockAuditRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, new(MockWebhookDeliveryRepository), mockAuditRepo, Infrastructure.NewNopLogger())
+		existing := &Domain.Webhook{ID: primitive.NewObjectID(), URL: "https://old.example.com", Secret: "original-secret-1", Events: []string{Domain.EventTaskCreated}, Active: true}
+		id := existing.ID.Hex()
+		active := false
+		mockWebhookRepo.On("GetByID", id).Return(existing, nil)
+		mockWebhookRepo.On("Update", existing).Return(nil)
+		mockAuditRepo.On("Create", mock.MatchedBy(func(entry *Domain.AuditEntry) bool {
+			return entry.Action == Domain.AuditActionUpdate && entry.EntityID == id && entry.Diff == "url: https://new.example.com, events: task.updated, active: false"
+		})).Return(nil)
+
+		// Act
+		updated, err := webhookUsecase.UpdateWebhook(context.Background(), adminCaller, id, Domain.WebhookRequest{
+			URL:    "https://new.example.com",
+			Events: []string{Domain.EventTaskUpdated},
+			Active: &active,
+		})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, "https://new.example.com", updated.URL)
+		assert.Equal(t, "original-secret-1", updated.Secret)
+		assert.False(t, updated.Active)
+		mockWebhookRepo.AssertExpectations(t)
+		mockAuditRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - webhook not found", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, new(MockWebhookDeliveryRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+		id := primitive.NewObjectID().Hex()
+		mockWebhookRepo.On("GetByID", id).Return(nil, Domain.ErrWebhookNotFound)
+
+		// Act
+		updated, err := webhookUsecase.UpdateWebhook(context.Background(), adminCaller, id, Domain.WebhookRequest{URL: "https://example.com", Events: []string{Domain.EventTaskCreated}})
+
+		// Assert
+		assert.Nil(t, updated)
+		assert.ErrorIs(t, err, Domain.ErrWebhookNotFound)
+	})
+}
+
+func TestWebhookUsecase_DeleteWebhook(t *testing.T) {
+	t.Run("Success - the delivery log goes too", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, newMockAuditRepository(), Infrastructure.NewNopLogger())
+		existing := &Domain.Webhook{ID: primitive.NewObjectID(), URL: "https://example.com"}
+		id := existing.ID.Hex()
+		mockWebhookRepo.On("GetByID", id).Return(existing, nil)
+		mockWebhookRepo.On("Delete", id).Return(nil)
+		mockDeliveryRepo.On("DeleteByWebhookID", id).Return(errors.New("connection reset"))
+
+		// Act
+		err := webhookUsecase.DeleteWebhook(context.Background(), adminCaller, id)
+
+		// Assert: the webhook is gone, so a failure to clean up its log is not an error
+		assert.NoError(t, err)
+		mockWebhookRepo.AssertExpectations(t)
+		mockDeliveryRepo.AssertExpectations(t)
+	})
+
+	t.Run("Error - invalid ID", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, new(MockWebhookDeliveryRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+		mockWebhookRepo.On("GetByID", "invalid-id").Return(nil, Domain.ErrInvalidWebhookID)
+
+		// Act
+		err := webhookUsecase.DeleteWebhook(context.Background(), adminCaller, "invalid-id")
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrInvalidWebhookID)
+		mockWebhookRepo.AssertNotCalled(t, "Delete", mock.Anything)
+	})
+}
+
+func TestWebhookUsecase_GetDeliveries(t *testing.T) {
+	t.Run("Success - one page of deliveries", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, newMockAuditRepository(), Infrastructure.NewNopLogger())
+		id := primitive.NewObjectID().Hex()
+		pagination := Domain.Pagination{Limit: 10}
+		deliveries := []*Domain.WebhookDelivery{{Event: Domain.EventTaskCreated, Attempts: 1, Delivered: true}}
+		mockWebhookRepo.On("GetByID", id).Return(&Domain.Webhook{}, nil)
+		mockDeliveryRepo.On("GetByWebhookID", id, pagination).Return(deliveries, int64(12), nil)
+
+		// Act
+		result, total, err := webhookUsecase.GetDeliveries(context.Background(), id, pagination)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, deliveries, result)
+		assert.Equal(t, int64(12), total)
+	})
+
+	t.Run("Error - webhook not found", func(t *testing.T) {
+		// Arrange
+		mockWebhookRepo := new(MockWebhookRepository)
+		mockDeliveryRepo := new(MockWebhookDeliveryRepository)
+		webhookUsecase := NewWebhookUsecase(mockWebhookRepo, mockDeliveryRepo, newMockAuditRepository(), Infrastructure.NewNopLogger())
+		id := primitive.NewObjectID().Hex()
+		mockWebhookRepo.On("GetByID", id).Return(nil, Domain.ErrWebhookNotFound)
+
+		// Act
+		_, _, err := webhookUsecase.GetDeliveries(context.Background(), id, Domain.Pagination{})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrWebhookNotFound)
+		mockDeliveryRepo.AssertNotCalled(t, "GetByWebhookID", mock.Anything, mock.Anything)
+	})
+
+	t.Run("Error - invalid pagination", func(t *testing.T) {
+		// Arrange
+		webhookUsecase := NewWebhookUsecase(new(MockWebhookRepository), new(MockWebhookDeliveryRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+
+		// Act
+		_, _, negativeErr := webhookUsecase.GetDeliveries(context.Background(), primitive.NewObjectID().Hex(), Domain.Pagination{Limit: -1})
+		_, _, sortErr := webhookUsecase.GetDeliveries(context.Background(), primitive.NewObjectID().Hex(), Domain.Pagination{Sort: Domain.SortDueDate})
+
+		// Assert
+		assert.ErrorIs(t, negativeErr, Domain.ErrInvalidPagination)
+		assert.EqualError(t, sortErr, "deliveries are always sorted newest first")
+	})
+}
+
+func TestTaskUsecase_PublishesEvents(t *testing.T) {
+	t.Run("task.created", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(nil)
+		var published *Domain.Task
+		mockEvents.On("Publish", mock.MatchedBy(func(event Domain.Event) bool {
+			published, _ = event.Data.(*Domain.Task)
+			return event.Type == Domain.EventTaskCreated && event.ActorID == adminCaller.UserID
+		})).Return().Once()
+
+		// Act
+		task, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Ship it", Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Same(t, task, published)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("task.updated carries the stored task, and only when something changed", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		taskID := primitive.NewObjectID()
+		existing := &Domain.Task{ID: taskID, Title: "Ship it", Status: Domain.StatusPending}
+		stored := &Domain.Task{ID: taskID, Title: "Ship it", Status: Domain.StatusInProgress, Version: 1}
+		mockRepo.On("GetByID", taskID.Hex()).Return(existing, nil).Once()
+		mockRepo.On("Update", taskID.Hex(), mock.AnythingOfType("*Domain.Task")).Return(nil)
+		mockRepo.On("GetByID", taskID.Hex()).Return(stored, nil).Once()
+		mockEvents.On("Publish", eventOf(Domain.EventTaskUpdated, adminCaller.UserID, stored)).Return().Once()
+
+		unchanged := &Domain.Task{ID: taskID, Title: "Ship it", Status: Domain.StatusInProgress, Version: 1}
+		mockRepo.On("GetByID", taskID.Hex()).Return(unchanged, nil)
+
+		// Act
+		_, err := taskUsecase.UpdateTaskStatus(context.Background(), adminCaller, taskID.Hex(), Domain.StatusInProgress)
+		_, unchangedErr := taskUsecase.UpdateTaskStatus(context.Background(), adminCaller, taskID.Hex(), Domain.StatusInProgress)
+
+		// Assert
+		assert.NoError(t, err)
+		assert.NoError(t, unchangedErr)
+		mockEvents.AssertExpectations(t)
+		mockEvents.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("task.deleted", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		taskID := primitive.NewObjectID().Hex()
+		task := &Domain.Task{Title: "Ship it", CreatedBy: primitive.NewObjectID()}
+		mockRepo.On("GetByID", taskID).Return(task, nil)
+		mockRepo.On("GetByParentID", taskID).Return([]*Domain.Task{}, nil)
+		mockRepo.On("Delete", taskID).Return(nil)
+		mockCommentRepo.On("DeleteByTaskID", taskID).Return(nil)
+		mockEvents.On("Publish", eventOf(Domain.EventTaskDeleted, adminCaller.UserID, Domain.DeletedTaskEvent{ID: taskID, Task: task})).Return().Once()
+
+		// Act
+		err := taskUsecase.DeleteTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("task.deleted for every task deleted by status", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		ids := []primitive.ObjectID{primitive.NewObjectID(), primitive.NewObjectID()}
+		mockRepo.On("DeleteByStatus", Domain.StatusCompleted).Return(int64(2), int64(2), ids, nil)
+		mockCommentRepo.On("DeleteByTaskIDs", ids).Return(nil)
+		for _, id := range ids {
+			mockEvents.On("Publish", eventOf(Domain.EventTaskDeleted, adminCaller.UserID, Domain.DeletedTaskEvent{ID: id.Hex()})).Return().Once()
+		}
+
+		// Act
+		_, err := taskUsecase.DeleteTasksByStatus(context.Background(), adminCaller, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("task.updated for every task a bulk status update moved", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		moved := &Domain.Task{ID: primitive.NewObjectID(), Status: Domain.StatusCompleted, Version: 1}
+		unchanged := &Domain.Task{ID: primitive.NewObjectID(), Status: Domain.StatusCompleted, Version: 3}
+		changes := []Domain.TaskStatusChange{{Task: moved, FromStatus: Domain.StatusInProgress}, {Task: unchanged, FromStatus: Domain.StatusCompleted}}
+		mockRepo.On("UpdateStatusMany", []primitive.ObjectID{moved.ID, unchanged.ID}, Domain.StatusCompleted, mock.Anything).Return(int64(2), int64(2), changes, nil)
+		mockEvents.On("Publish", eventOf(Domain.EventTaskUpdated, adminCaller.UserID, moved)).Return().Once()
+
+		// Act
+		_, err := taskUsecase.UpdateTasksStatus(context.Background(), adminCaller, []string{moved.ID.Hex(), unchanged.ID.Hex()}, Domain.StatusCompleted)
+
+		// Assert
+		assert.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+		mockEvents.AssertNumberOfCalls(t, "Publish", 1)
+	})
+
+	t.Run("task.updated when a task is restored from the trash", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockCommentRepo := new(MockCommentRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), mockCommentRepo, newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		taskID := primitive.NewObjectID().Hex()
+		restored := &Domain.Task{Title: "Ship it", Status: Domain.StatusPending}
+		mockRepo.On("GetByID", taskID).Return(nil, Domain.ErrTaskNotFound).Once()
+		mockRepo.On("Restore", taskID).Return(nil)
+		mockCommentRepo.On("RestoreByTaskID", taskID).Return(nil)
+		mockRepo.On("GetByID", taskID).Return(restored, nil).Once()
+		mockEvents.On("Publish", eventOf(Domain.EventTaskUpdated, adminCaller.UserID, restored)).Return().Once()
+
+		// Act
+		_, err := taskUsecase.RestoreTask(context.Background(), adminCaller, taskID)
+
+		// Assert
+		assert.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("Nothing is published when the change fails", func(t *testing.T) {
+		// Arrange
+		mockRepo := new(MockTaskRepository)
+		mockEvents := new(MockEventBus)
+		taskUsecase := NewTaskUsecase(mockRepo, new(MockUserRepository), new(MockCommentRepository), newMockAttachmentRepository(), newMockBlobStore(), newMockWorkLogRepository(), newMockAuditRepository(), newMockRevisionRepository(), mockEvents, Infrastructure.NewNopBusinessMetrics(), DueDateRules{}, Infrastructure.NewNopLogger())
+		mockRepo.On("Create", mock.AnythingOfType("*Domain.Task")).Return(errors.New("write failed"))
+
+		// Act
+		_, err := taskUsecase.CreateTask(context.Background(), adminCaller, Domain.TaskRequest{Title: "Ship it", Status: Domain.StatusPending}, nil)
+
+		// Assert
+		assert.Error(t, err)
+		mockEvents.AssertNotCalled(t, "Publish", mock.Anything)
+	})
+}
+
+func TestUserUsecase_PromoteUser_PublishesEvent(t *testing.T) {
+	// Arrange
+	mockUserRepo := new(MockUserRepository)
+	mockEvents := new(MockEventBus)
+	userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), new(MockPasswordService), Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), mockEvents, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+	user := &Domain.User{ID: primitive.NewObjectID(), Username: "alice", Role: Domain.RoleUser}
+	promoted := &Domain.User{ID: user.ID, Username: "alice", Role: Domain.RoleManager}
+	mockUserRepo.On("GetByUsername", "alice").Return(user, nil).Once()
+	mockUserRepo.On("UpdateByUsername", "alice", mock.AnythingOfType("*Domain.User")).Return(nil)
+	mockUserRepo.On("GetByUsername", "alice").Return(promoted, nil).Once()
+	mockEvents.On("Publish", eventOf(Domain.EventUserPromoted, adminCaller.UserID, Domain.PromotedUserEvent{User: promoted, PreviousRole: Domain.RoleUser})).Return().Once()
+
+	// Act
+	_, err := userUsecase.PromoteUser(context.Background(), adminCaller, "alice", Domain.RoleManager)
+
+	// Assert
+	assert.NoError(t, err)
+	mockEvents.AssertExpectations(t)
+}
+
+func TestUserUsecase_RegisterUser_PublishesEvent(t *testing.T) {
+	// assignID mimics the repository stamping an id on insert
+	assignID := func(args mock.Arguments) {
+		args.Get(0).(*Domain.User).ID = primitive.NewObjectID()
+	}
+
+	t.Run("user.registered names the new user as the actor", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockEvents := new(MockEventBus)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), mockEvents, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		mockUserRepo.On("GetByUsername", "alice").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", "password123").Return("hashed", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil).Maybe()
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Run(assignID).Return(nil)
+		var published Domain.Event
+		mockEvents.On("Publish", mock.MatchedBy(func(event Domain.Event) bool {
+			published = event
+			return event.Type == Domain.EventUserRegistered
+		})).Return().Once()
+
+		// Act
+		user, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: "alice", Password: "password123"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Same(t, user, published.Data)
+		assert.Equal(t, user.ID.Hex(), published.ActorID)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("user.registered has no actor for admins created by the admin commands", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockEvents := new(MockEventBus)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), mockEvents, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		mockUserRepo.On("GetByUsername", "root").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", "password123").Return("hashed", nil)
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Run(assignID).Return(nil)
+		mockEvents.On("Publish", mock.MatchedBy(func(event Domain.Event) bool {
+			return event.Type == Domain.EventUserRegistered && event.ActorID == ""
+		})).Return().Once()
+
+		// Act
+		_, err := userUsecase.CreateAdmin(context.Background(), Domain.UserRequest{Username: "root", Password: "password123"})
+
+		// Assert
+		assert.NoError(t, err)
+		mockEvents.AssertExpectations(t)
+	})
+
+	t.Run("Nothing is published when the registration fails", func(t *testing.T) {
+		// Arrange
+		mockUserRepo := new(MockUserRepository)
+		mockPasswordService := new(MockPasswordService)
+		mockEvents := new(MockEventBus)
+		userUsecase := NewUserUsecase(mockUserRepo, new(MockTaskRepository), new(MockRefreshTokenRepository), Infrastructure.NewMemoryTokenBlacklist(), mockPasswordService, Infrastructure.NewPermissivePasswordPolicy(), new(MockJWTService), new(MockNotifier), newMockAuditRepository(), newMockLoginEventRepository(), newMockTransactionManager(), mockEvents, Infrastructure.NewNopBusinessMetrics(), Infrastructure.NewNopLogger())
+		mockUserRepo.On("GetByUsername", "alice").Return(nil, Domain.ErrUserNotFound)
+		mockPasswordService.On("HashPassword", "password123").Return("hashed", nil)
+		mockUserRepo.On("CountUsers").Return(int64(1), nil).Maybe()
+		mockUserRepo.On("Create", mock.AnythingOfType("*Domain.User")).Return(errors.New("write failed"))
+
+		// Act
+		_, err := userUsecase.RegisterUser(context.Background(), Domain.UserRequest{Username: "alice", Password: "password123"})
+
+		// Assert
+		assert.Error(t, err)
+		mockEvents.AssertNotCalled(t, "Publish", mock.Anything)
+	})
+}
+
+func TestWebhookUsecaseInterface(t *testing.T) {
+	var _ WebhookUsecaseInterface = NewWebhookUsecase(new(MockWebhookRepository), new(MockWebhookDeliveryRepository), newMockAuditRepository(), Infrastructure.NewNopLogger())
+	var _ Infrastructure.EventBus = new(MockEventBus)
+}
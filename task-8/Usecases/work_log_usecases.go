@@ -0,0 +1,176 @@
+package Usecases
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+	"task_manager/Repositories"
+)
+
+// MaxLoggedMinutesPerDay is the most time one user can log on a single day, across all tasks
+const MaxLoggedMinutesPerDay = 24 * 60
+
+// MaxWorkLogNoteLength is the longest work log note accepted, in characters
+const MaxWorkLogNoteLength = 500
+
+// WorkLogUsecaseInterface defines the contract for time tracking business logic
+type WorkLogUsecaseInterface interface {
+	LogWork(ctx context.Context, caller Domain.Caller, taskID string, workLogReq Domain.WorkLogRequest) (*Domain.WorkLog, error)
+	GetWorkLogs(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.WorkLog, error)
+	GetSummary(ctx context.Context, caller Domain.Caller, userID, from, to string) ([]*Domain.WorkLogSummary, error)
+}
+
+// WorkLogUsecase implements time tracking business logic
+type WorkLogUsecase struct {
+	workLogRepo     Repositories.WorkLogRepositoryInterface
+	taskRepo        Repositories.TaskRepositoryInterface
+	defaultLocation *time.Location
+}
+
+// NewWorkLogUsecase creates a new instance of WorkLogUsecase
+func NewWorkLogUsecase(workLogRepo Repositories.WorkLogRepositoryInterface, taskRepo Repositories.TaskRepositoryInterface) WorkLogUsecaseInterface {
+	return &WorkLogUsecase{
+		workLogRepo:     workLogRepo,
+		taskRepo:        taskRepo,
+		defaultLocation: DefaultLocation(),
+	}
+}
+
+// LogWork records time the caller spent on a task. Only callers who can see the task, its
+// creator, its assignee, managers and admins, can log time, and no user can log more than a
+// day's worth of minutes on one date.
+func (wu *WorkLogUsecase) LogWork(ctx context.Context, caller Domain.Caller, taskID string, workLogReq Domain.WorkLogRequest) (*Domain.WorkLog, error) {
+	userID, err := primitive.ObjectIDFromHex(caller.UserID)
+	if err != nil {
+		return nil, Domain.ErrInvalidUserID
+	}
+
+	if workLogReq.Minutes <= 0 {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "minutes must be positive")
+	}
+	if workLogReq.Minutes > MaxLoggedMinutesPerDay {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "minutes must be at most %d", MaxLoggedMinutesPerDay)
+	}
+
+	note := strings.TrimSpace(workLogReq.Note)
+	if utf8.RuneCountInString(note) > MaxWorkLogNoteLength {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "note must not exceed %d characters", MaxWorkLogNoteLength)
+	}
+
+	date, err := wu.workDate(workLogReq.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := wu.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsVisibleTo(caller) {
+		return nil, Domain.ErrTaskNotFound
+	}
+
+	logged, err := wu.workLogRepo.TotalMinutesByUserOnDate(ctx, userID, date)
+	if err != nil {
+		return nil, err
+	}
+	if logged+workLogReq.Minutes > MaxLoggedMinutesPerDay {
+		return nil, Domain.ErrDailyWorkLimit
+	}
+
+	workLog := &Domain.WorkLog{
+		TaskID:  task.ID,
+		UserID:  userID,
+		Minutes: workLogReq.Minutes,
+		Note:    note,
+		Date:    date,
+	}
+
+	err = wu.workLogRepo.Create(ctx, workLog)
+	if err != nil {
+		return nil, err
+	}
+
+	return workLog, nil
+}
+
+// GetWorkLogs returns the time logged on a task the caller can see, earliest day first
+func (wu *WorkLogUsecase) GetWorkLogs(ctx context.Context, caller Domain.Caller, taskID string) ([]*Domain.WorkLog, error) {
+	task, err := wu.taskRepo.GetByID(ctx, taskID)
+	if err != nil {
+		return nil, err
+	}
+	if !task.IsVisibleTo(caller) {
+		return nil, Domain.ErrTaskNotFound
+	}
+
+	return wu.workLogRepo.GetByTaskID(ctx, taskID)
+}
+
+// GetSummary totals the time each user logged between from and to, both optional and inclusive.
+// Regular users only see their own total; managers and admins can narrow it to one user.
+func (wu *WorkLogUsecase) GetSummary(ctx context.Context, caller Domain.Caller, userID, from, to string) ([]*Domain.WorkLogSummary, error) {
+	filter := Domain.WorkLogFilter{}
+
+	if !caller.Can(Domain.PermissionTasksReadAll) {
+		userID = caller.UserID
+	}
+	if userID != "" {
+		objectID, err := primitive.ObjectIDFromHex(userID)
+		if err != nil {
+			return nil, Domain.ErrInvalidUserID
+		}
+		filter.UserID = objectID
+	}
+
+	var err error
+	if filter.From, err = parseWorkDate(from); err != nil {
+		return nil, err
+	}
+	if filter.To, err = parseWorkDate(to); err != nil {
+		return nil, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.To.Before(filter.From) {
+		return nil, Domain.NewError(Domain.ErrInvalidInput, "to must not be before from")
+	}
+
+	return wu.workLogRepo.Summarize(ctx, filter)
+}
+
+// workDate parses the day work was done on, defaulting to today in the default timezone.
+// Work cannot be logged ahead of time.
+func (wu *WorkLogUsecase) workDate(value string) (time.Time, error) {
+	now := time.Now().In(wu.defaultLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if value == "" {
+		return today, nil
+	}
+
+	date, err := parseWorkDate(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if date.After(today) {
+		return time.Time{}, Domain.NewError(Domain.ErrInvalidInput, "work cannot be logged on a future date")
+	}
+	return date, nil
+}
+
+// parseWorkDate parses a YYYY-MM-DD day as midnight UTC, the way work log dates are stored.
+// An empty value means no date.
+func parseWorkDate(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	date, err := time.Parse(DueDateLayout, value)
+	if err != nil {
+		return time.Time{}, Domain.NewError(Domain.ErrInvalidInput, "dates must use the YYYY-MM-DD format")
+	}
+	return date, nil
+}
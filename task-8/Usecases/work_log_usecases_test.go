@@ -0,0 +1,295 @@
+package Usecases
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+
+	"task_manager/Domain"
+)
+
+// MockWorkLogRepository is a mock implementation of WorkLogRepositoryInterface
+type MockWorkLogRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkLogRepository) Create(ctx context.Context, workLog *Domain.WorkLog) error {
+	args := m.Called(workLog)
+	return args.Error(0)
+}
+
+func (m *MockWorkLogRepository) GetByTaskID(ctx context.Context, taskID string) ([]*Domain.WorkLog, error) {
+	args := m.Called(taskID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.WorkLog), args.Error(1)
+}
+
+func (m *MockWorkLogRepository) TotalMinutesByTaskID(ctx context.Context, taskID string) (int, error) {
+	args := m.Called(taskID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWorkLogRepository) TotalMinutesByUserOnDate(ctx context.Context, userID primitive.ObjectID, date time.Time) (int, error) {
+	args := m.Called(userID, date)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockWorkLogRepository) Summarize(ctx context.Context, filter Domain.WorkLogFilter) ([]*Domain.WorkLogSummary, error) {
+	args := m.Called(filter)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]*Domain.WorkLogSummary), args.Error(1)
+}
+
+func (m *MockWorkLogRepository) DeleteByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockWorkLogRepository) DeleteByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) error {
+	args := m.Called(taskIDs)
+	return args.Error(0)
+}
+
+func (m *MockWorkLogRepository) RestoreByTaskID(ctx context.Context, taskID string) error {
+	args := m.Called(taskID)
+	return args.Error(0)
+}
+
+func (m *MockWorkLogRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	args := m.Called(deletedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWorkLogRepository) PurgeByTaskIDs(ctx context.Context, taskIDs []primitive.ObjectID) (int64, error) {
+	args := m.Called(taskIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockWorkLogRepository) EnsureIndexes(ctx context.Context) error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+// newMockWorkLogRepository returns a mock for tasks without logged work, for tests that are not about it
+func newMockWorkLogRepository() *MockWorkLogRepository {
+	mockWorkLogRepo := new(MockWorkLogRepository)
+	mockWorkLogRepo.On("TotalMinutesByTaskID", mock.Anything).Return(0, nil).Maybe()
+	mockWorkLogRepo.On("DeleteByTaskID", mock.Anything).Return(nil).Maybe()
+	mockWorkLogRepo.On("DeleteByTaskIDs", mock.Anything).Return(nil).Maybe()
+	mockWorkLogRepo.On("RestoreByTaskID", mock.Anything).Return(nil).Maybe()
+	mockWorkLogRepo.On("Purge", mock.Anything).Return(int64(0), nil).Maybe()
+	mockWorkLogRepo.On("PurgeByTaskIDs", mock.Anything).Return(int64(0), nil).Maybe()
+	return mockWorkLogRepo
+}
+
+func TestWorkLogUsecase_LogWork(t *testing.T) {
+	t.Run("Success - assignee logs time on a past day", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, mockTaskRepo)
+
+		userID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: primitive.NewObjectID(), AssigneeID: &userID}
+		date := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockWorkLogRepo.On("TotalMinutesByUserOnDate", userID, date).Return(600, nil)
+		mockWorkLogRepo.On("Create", mock.MatchedBy(func(workLog *Domain.WorkLog) bool {
+			return workLog.TaskID == task.ID && workLog.UserID == userID && workLog.Minutes == 90 && workLog.Note == "Review" && workLog.Date.Equal(date)
+		})).Return(nil)
+
+		// Act
+		workLog, err := workLogUsecase.LogWork(context.Background(), userCaller, task.ID.Hex(), Domain.WorkLogRequest{Minutes: 90, Note: " Review ", Date: "2024-03-01"})
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, 90, workLog.Minutes)
+		mockWorkLogRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - date defaults to today", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, mockTaskRepo)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockWorkLogRepo.On("TotalMinutesByUserOnDate", mock.Anything, mock.Anything).Return(0, nil)
+		mockWorkLogRepo.On("Create", mock.Anything).Return(nil)
+
+		// Act
+		workLog, err := workLogUsecase.LogWork(context.Background(), managerCaller, task.ID.Hex(), Domain.WorkLogRequest{Minutes: 30})
+
+		// Assert
+		assert.NoError(t, err)
+		now := time.Now().In(DefaultLocation())
+		assert.Equal(t, time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC), workLog.Date)
+	})
+
+	t.Run("Error - minutes out of range", func(t *testing.T) {
+		// Arrange
+		workLogUsecase := NewWorkLogUsecase(new(MockWorkLogRepository), new(MockTaskRepository))
+		taskID := primitive.NewObjectID().Hex()
+
+		for _, minutes := range []int{-15, 0, MaxLoggedMinutesPerDay + 1} {
+			// Act
+			workLog, err := workLogUsecase.LogWork(context.Background(), userCaller, taskID, Domain.WorkLogRequest{Minutes: minutes})
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrInvalidInput, "minutes %d", minutes)
+			assert.Nil(t, workLog)
+		}
+	})
+
+	t.Run("Error - malformed or future date", func(t *testing.T) {
+		// Arrange
+		workLogUsecase := NewWorkLogUsecase(new(MockWorkLogRepository), new(MockTaskRepository))
+		taskID := primitive.NewObjectID().Hex()
+		tomorrow := time.Now().AddDate(0, 0, 2).Format(DueDateLayout)
+
+		for _, date := range []string{"01/03/2024", tomorrow} {
+			// Act
+			_, err := workLogUsecase.LogWork(context.Background(), userCaller, taskID, Domain.WorkLogRequest{Minutes: 30, Date: date})
+
+			// Assert
+			assert.ErrorIs(t, err, Domain.ErrInvalidInput, "date %s", date)
+		}
+	})
+
+	t.Run("Error - caller neither owns nor is assigned the task", func(t *testing.T) {
+		// Arrange
+		mockTaskRepo := new(MockTaskRepository)
+		workLogUsecase := NewWorkLogUsecase(new(MockWorkLogRepository), mockTaskRepo)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		_, err := workLogUsecase.LogWork(context.Background(), userCaller, task.ID.Hex(), Domain.WorkLogRequest{Minutes: 30})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+	})
+
+	t.Run("Error - day would exceed 24 hours", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, mockTaskRepo)
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: ownerID}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockWorkLogRepo.On("TotalMinutesByUserOnDate", ownerID, mock.Anything).Return(MaxLoggedMinutesPerDay-20, nil)
+
+		// Act
+		_, err := workLogUsecase.LogWork(context.Background(), userCaller, task.ID.Hex(), Domain.WorkLogRequest{Minutes: 30})
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrDailyWorkLimit)
+		mockWorkLogRepo.AssertNotCalled(t, "Create", mock.Anything)
+	})
+}
+
+func TestWorkLogUsecase_GetWorkLogs(t *testing.T) {
+	t.Run("Success - work on a visible task", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, mockTaskRepo)
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: ownerID}
+		workLogs := []*Domain.WorkLog{{ID: primitive.NewObjectID(), TaskID: task.ID, UserID: ownerID, Minutes: 30}}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+		mockWorkLogRepo.On("GetByTaskID", task.ID.Hex()).Return(workLogs, nil)
+
+		// Act
+		result, err := workLogUsecase.GetWorkLogs(context.Background(), userCaller, task.ID.Hex())
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, workLogs, result)
+	})
+
+	t.Run("Error - task hidden from caller", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		mockTaskRepo := new(MockTaskRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, mockTaskRepo)
+
+		task := &Domain.Task{ID: primitive.NewObjectID(), Title: "Task", CreatedBy: primitive.NewObjectID()}
+		mockTaskRepo.On("GetByID", task.ID.Hex()).Return(task, nil)
+
+		// Act
+		_, err := workLogUsecase.GetWorkLogs(context.Background(), userCaller, task.ID.Hex())
+
+		// Assert
+		assert.ErrorIs(t, err, Domain.ErrTaskNotFound)
+		mockWorkLogRepo.AssertNotCalled(t, "GetByTaskID", mock.Anything)
+	})
+}
+
+func TestWorkLogUsecase_GetSummary(t *testing.T) {
+	t.Run("Success - regular users only see their own total", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, new(MockTaskRepository))
+
+		ownerID, _ := primitive.ObjectIDFromHex(userCaller.UserID)
+		mockWorkLogRepo.On("Summarize", Domain.WorkLogFilter{UserID: ownerID}).Return([]*Domain.WorkLogSummary{}, nil)
+
+		// Act
+		_, err := workLogUsecase.GetSummary(context.Background(), userCaller, primitive.NewObjectID().Hex(), "", "")
+
+		// Assert
+		assert.NoError(t, err)
+		mockWorkLogRepo.AssertExpectations(t)
+	})
+
+	t.Run("Success - managers filter by user and date range", func(t *testing.T) {
+		// Arrange
+		mockWorkLogRepo := new(MockWorkLogRepository)
+		workLogUsecase := NewWorkLogUsecase(mockWorkLogRepo, new(MockTaskRepository))
+
+		userID := primitive.NewObjectID()
+		filter := Domain.WorkLogFilter{
+			UserID: userID,
+			From:   time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC),
+			To:     time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC),
+		}
+		summaries := []*Domain.WorkLogSummary{{UserID: userID, TotalMinutes: 120, Entries: 3}}
+		mockWorkLogRepo.On("Summarize", filter).Return(summaries, nil)
+
+		// Act
+		result, err := workLogUsecase.GetSummary(context.Background(), managerCaller, userID.Hex(), "2024-03-01", "2024-03-31")
+
+		// Assert
+		assert.NoError(t, err)
+		assert.Equal(t, summaries, result)
+	})
+
+	t.Run("Error - invalid filters", func(t *testing.T) {
+		// Arrange
+		workLogUsecase := NewWorkLogUsecase(new(MockWorkLogRepository), new(MockTaskRepository))
+
+		// Act
+		_, userErr := workLogUsecase.GetSummary(context.Background(), managerCaller, "invalid", "", "")
+		_, dateErr := workLogUsecase.GetSummary(context.Background(), managerCaller, "", "March", "")
+		_, rangeErr := workLogUsecase.GetSummary(context.Background(), managerCaller, "", "2024-03-31", "2024-03-01")
+
+		// Assert
+		assert.ErrorIs(t, userErr, Domain.ErrInvalidUserID)
+		assert.ErrorIs(t, dateErr, Domain.ErrInvalidInput)
+		assert.ErrorIs(t, rangeErr, Domain.ErrInvalidInput)
+	})
+}